@@ -21,25 +21,15 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-/*
-	Package kvpb is a generated protocol buffer package.
-
-	It is generated from these files:
-		github.com/m3db/m3/src/cluster/generated/proto/kvpb/kv.proto
-
-	It has these top-level messages:
-		KeyValueUpdate
-		KeyValueUpdateResult
-		QueryLimits
-		QueryLimit
-*/
 package kvpb
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
-import io "io"
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -50,7 +40,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type KeyValueUpdate struct {
 	Key    string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -58,10 +48,38 @@ type KeyValueUpdate struct {
 	Commit bool   `protobuf:"varint,3,opt,name=commit,proto3" json:"commit,omitempty"`
 }
 
-func (m *KeyValueUpdate) Reset()                    { *m = KeyValueUpdate{} }
-func (m *KeyValueUpdate) String() string            { return proto.CompactTextString(m) }
-func (*KeyValueUpdate) ProtoMessage()               {}
-func (*KeyValueUpdate) Descriptor() ([]byte, []int) { return fileDescriptorKv, []int{0} }
+func (m *KeyValueUpdate) Reset()         { *m = KeyValueUpdate{} }
+func (m *KeyValueUpdate) String() string { return proto.CompactTextString(m) }
+func (*KeyValueUpdate) ProtoMessage()    {}
+func (*KeyValueUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af26b26e1fde094, []int{0}
+}
+func (m *KeyValueUpdate) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeyValueUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeyValueUpdate.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *KeyValueUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyValueUpdate.Merge(m, src)
+}
+func (m *KeyValueUpdate) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeyValueUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyValueUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyValueUpdate proto.InternalMessageInfo
 
 func (m *KeyValueUpdate) GetKey() string {
 	if m != nil {
@@ -90,10 +108,38 @@ type KeyValueUpdateResult struct {
 	New string `protobuf:"bytes,3,opt,name=new,proto3" json:"new,omitempty"`
 }
 
-func (m *KeyValueUpdateResult) Reset()                    { *m = KeyValueUpdateResult{} }
-func (m *KeyValueUpdateResult) String() string            { return proto.CompactTextString(m) }
-func (*KeyValueUpdateResult) ProtoMessage()               {}
-func (*KeyValueUpdateResult) Descriptor() ([]byte, []int) { return fileDescriptorKv, []int{1} }
+func (m *KeyValueUpdateResult) Reset()         { *m = KeyValueUpdateResult{} }
+func (m *KeyValueUpdateResult) String() string { return proto.CompactTextString(m) }
+func (*KeyValueUpdateResult) ProtoMessage()    {}
+func (*KeyValueUpdateResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af26b26e1fde094, []int{1}
+}
+func (m *KeyValueUpdateResult) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeyValueUpdateResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeyValueUpdateResult.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *KeyValueUpdateResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyValueUpdateResult.Merge(m, src)
+}
+func (m *KeyValueUpdateResult) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeyValueUpdateResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyValueUpdateResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyValueUpdateResult proto.InternalMessageInfo
 
 func (m *KeyValueUpdateResult) GetKey() string {
 	if m != nil {
@@ -117,16 +163,45 @@ func (m *KeyValueUpdateResult) GetNew() string {
 }
 
 type QueryLimits struct {
-	MaxRecentlyQueriedSeriesBlocks        *QueryLimit `protobuf:"bytes,1,opt,name=maxRecentlyQueriedSeriesBlocks" json:"maxRecentlyQueriedSeriesBlocks,omitempty"`
-	MaxRecentlyQueriedSeriesDiskBytesRead *QueryLimit `protobuf:"bytes,2,opt,name=maxRecentlyQueriedSeriesDiskBytesRead" json:"maxRecentlyQueriedSeriesDiskBytesRead,omitempty"`
-	MaxRecentlyQueriedSeriesDiskRead      *QueryLimit `protobuf:"bytes,3,opt,name=maxRecentlyQueriedSeriesDiskRead" json:"maxRecentlyQueriedSeriesDiskRead,omitempty"`
-	MaxRecentlyQueriedMetadataRead        *QueryLimit `protobuf:"bytes,4,opt,name=maxRecentlyQueriedMetadataRead" json:"maxRecentlyQueriedMetadataRead,omitempty"`
+	MaxRecentlyQueriedSeriesBlocks        *QueryLimit              `protobuf:"bytes,1,opt,name=maxRecentlyQueriedSeriesBlocks,proto3" json:"maxRecentlyQueriedSeriesBlocks,omitempty"`
+	MaxRecentlyQueriedSeriesDiskBytesRead *QueryLimit              `protobuf:"bytes,2,opt,name=maxRecentlyQueriedSeriesDiskBytesRead,proto3" json:"maxRecentlyQueriedSeriesDiskBytesRead,omitempty"`
+	MaxRecentlyQueriedSeriesDiskRead      *QueryLimit              `protobuf:"bytes,3,opt,name=maxRecentlyQueriedSeriesDiskRead,proto3" json:"maxRecentlyQueriedSeriesDiskRead,omitempty"`
+	MaxRecentlyQueriedMetadataRead        *QueryLimit              `protobuf:"bytes,4,opt,name=maxRecentlyQueriedMetadataRead,proto3" json:"maxRecentlyQueriedMetadataRead,omitempty"`
+	TenantLimits                          map[string]*TenantLimits `protobuf:"bytes,5,rep,name=tenantLimits,proto3" json:"tenantLimits,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (m *QueryLimits) Reset()                    { *m = QueryLimits{} }
-func (m *QueryLimits) String() string            { return proto.CompactTextString(m) }
-func (*QueryLimits) ProtoMessage()               {}
-func (*QueryLimits) Descriptor() ([]byte, []int) { return fileDescriptorKv, []int{2} }
+func (m *QueryLimits) Reset()         { *m = QueryLimits{} }
+func (m *QueryLimits) String() string { return proto.CompactTextString(m) }
+func (*QueryLimits) ProtoMessage()    {}
+func (*QueryLimits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af26b26e1fde094, []int{2}
+}
+func (m *QueryLimits) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryLimits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryLimits.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryLimits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryLimits.Merge(m, src)
+}
+func (m *QueryLimits) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryLimits) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryLimits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryLimits proto.InternalMessageInfo
 
 func (m *QueryLimits) GetMaxRecentlyQueriedSeriesBlocks() *QueryLimit {
 	if m != nil {
@@ -156,6 +231,73 @@ func (m *QueryLimits) GetMaxRecentlyQueriedMetadataRead() *QueryLimit {
 	return nil
 }
 
+func (m *QueryLimits) GetTenantLimits() map[string]*TenantLimits {
+	if m != nil {
+		return m.TenantLimits
+	}
+	return nil
+}
+
+type TenantLimits struct {
+	MaxRecentlyQueriedSeriesBlocks        *QueryLimit `protobuf:"bytes,1,opt,name=maxRecentlyQueriedSeriesBlocks,proto3" json:"maxRecentlyQueriedSeriesBlocks,omitempty"`
+	MaxRecentlyQueriedSeriesDiskBytesRead *QueryLimit `protobuf:"bytes,2,opt,name=maxRecentlyQueriedSeriesDiskBytesRead,proto3" json:"maxRecentlyQueriedSeriesDiskBytesRead,omitempty"`
+	MaxRecentlyWrittenSeries              *QueryLimit `protobuf:"bytes,3,opt,name=maxRecentlyWrittenSeries,proto3" json:"maxRecentlyWrittenSeries,omitempty"`
+}
+
+func (m *TenantLimits) Reset()         { *m = TenantLimits{} }
+func (m *TenantLimits) String() string { return proto.CompactTextString(m) }
+func (*TenantLimits) ProtoMessage()    {}
+func (*TenantLimits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af26b26e1fde094, []int{3}
+}
+func (m *TenantLimits) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantLimits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TenantLimits.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TenantLimits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantLimits.Merge(m, src)
+}
+func (m *TenantLimits) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantLimits) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantLimits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantLimits proto.InternalMessageInfo
+
+func (m *TenantLimits) GetMaxRecentlyQueriedSeriesBlocks() *QueryLimit {
+	if m != nil {
+		return m.MaxRecentlyQueriedSeriesBlocks
+	}
+	return nil
+}
+
+func (m *TenantLimits) GetMaxRecentlyQueriedSeriesDiskBytesRead() *QueryLimit {
+	if m != nil {
+		return m.MaxRecentlyQueriedSeriesDiskBytesRead
+	}
+	return nil
+}
+
+func (m *TenantLimits) GetMaxRecentlyWrittenSeries() *QueryLimit {
+	if m != nil {
+		return m.MaxRecentlyWrittenSeries
+	}
+	return nil
+}
+
 type QueryLimit struct {
 	Limit           int64 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
 	LookbackSeconds int64 `protobuf:"varint,2,opt,name=lookbackSeconds,proto3" json:"lookbackSeconds,omitempty"`
@@ -163,10 +305,38 @@ type QueryLimit struct {
 	ForceWaited     bool  `protobuf:"varint,4,opt,name=forceWaited,proto3" json:"forceWaited,omitempty"`
 }
 
-func (m *QueryLimit) Reset()                    { *m = QueryLimit{} }
-func (m *QueryLimit) String() string            { return proto.CompactTextString(m) }
-func (*QueryLimit) ProtoMessage()               {}
-func (*QueryLimit) Descriptor() ([]byte, []int) { return fileDescriptorKv, []int{3} }
+func (m *QueryLimit) Reset()         { *m = QueryLimit{} }
+func (m *QueryLimit) String() string { return proto.CompactTextString(m) }
+func (*QueryLimit) ProtoMessage()    {}
+func (*QueryLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af26b26e1fde094, []int{4}
+}
+func (m *QueryLimit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryLimit.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryLimit.Merge(m, src)
+}
+func (m *QueryLimit) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryLimit proto.InternalMessageInfo
 
 func (m *QueryLimit) GetLimit() int64 {
 	if m != nil {
@@ -200,12 +370,54 @@ func init() {
 	proto.RegisterType((*KeyValueUpdate)(nil), "kvpb.KeyValueUpdate")
 	proto.RegisterType((*KeyValueUpdateResult)(nil), "kvpb.KeyValueUpdateResult")
 	proto.RegisterType((*QueryLimits)(nil), "kvpb.QueryLimits")
+	proto.RegisterMapType((map[string]*TenantLimits)(nil), "kvpb.QueryLimits.TenantLimitsEntry")
+	proto.RegisterType((*TenantLimits)(nil), "kvpb.TenantLimits")
 	proto.RegisterType((*QueryLimit)(nil), "kvpb.QueryLimit")
 }
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/cluster/generated/proto/kvpb/kv.proto", fileDescriptor_2af26b26e1fde094)
+}
+
+var fileDescriptor_2af26b26e1fde094 = []byte{
+	// 484 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x54, 0xbf, 0x6f, 0xd3, 0x40,
+	0x14, 0x8e, 0xe3, 0xb4, 0x6a, 0x5f, 0x0a, 0x84, 0x53, 0x85, 0x2c, 0x06, 0x2b, 0x32, 0x20, 0x79,
+	0xb2, 0xa5, 0x64, 0x41, 0x88, 0x29, 0xa2, 0x62, 0x20, 0x48, 0x70, 0x01, 0xca, 0xc0, 0x72, 0x3e,
+	0xbf, 0x16, 0xcb, 0x3f, 0x2e, 0xf2, 0x9d, 0x43, 0xfd, 0x5f, 0x30, 0x30, 0xb3, 0xf1, 0xbf, 0x30,
+	0x76, 0x64, 0x44, 0xc9, 0x3f, 0x82, 0xee, 0x1c, 0xa9, 0x4e, 0x49, 0x68, 0x67, 0x16, 0xeb, 0xbd,
+	0xef, 0xbe, 0xef, 0x7b, 0xbe, 0xf3, 0xe7, 0x83, 0xe7, 0xe7, 0x89, 0xfa, 0x5c, 0x45, 0x01, 0x17,
+	0x79, 0x98, 0x8f, 0xe3, 0x28, 0xcc, 0xc7, 0xa1, 0x2c, 0x79, 0xc8, 0xb3, 0x4a, 0x2a, 0x2c, 0xc3,
+	0x73, 0x2c, 0xb0, 0x64, 0x0a, 0xe3, 0x70, 0x5e, 0x0a, 0x25, 0xc2, 0x74, 0x31, 0x8f, 0xc2, 0x74,
+	0x11, 0x98, 0x8e, 0xf4, 0x74, 0xeb, 0xbd, 0x81, 0xbb, 0xaf, 0xb0, 0xfe, 0xc0, 0xb2, 0x0a, 0xdf,
+	0xcf, 0x63, 0xa6, 0x90, 0x0c, 0xc0, 0x4e, 0xb1, 0x76, 0xac, 0xa1, 0xe5, 0x1f, 0x52, 0x5d, 0x92,
+	0x63, 0xd8, 0x5b, 0x68, 0x82, 0xd3, 0x35, 0x58, 0xd3, 0x90, 0x07, 0xb0, 0xcf, 0x45, 0x9e, 0x27,
+	0xca, 0xb1, 0x87, 0x96, 0x7f, 0x40, 0xd7, 0x9d, 0x37, 0x85, 0xe3, 0x4d, 0x47, 0x8a, 0xb2, 0xca,
+	0xd4, 0x16, 0xdf, 0x01, 0xd8, 0x22, 0x8b, 0xd7, 0xae, 0xba, 0xd4, 0x48, 0x81, 0x5f, 0x8c, 0xe1,
+	0x21, 0xd5, 0xa5, 0xf7, 0xa3, 0x07, 0xfd, 0xb7, 0x15, 0x96, 0xf5, 0x34, 0xc9, 0x13, 0x25, 0xc9,
+	0x47, 0x70, 0x73, 0x76, 0x41, 0x91, 0x63, 0xa1, 0xb2, 0x5a, 0xaf, 0x24, 0x18, 0xcf, 0xf4, 0x53,
+	0x4e, 0x32, 0xc1, 0x53, 0x69, 0x06, 0xf4, 0x47, 0x83, 0x40, 0x6f, 0x2f, 0xb8, 0x92, 0xd2, 0x1b,
+	0x74, 0xe4, 0x0c, 0x9e, 0xec, 0x62, 0xbc, 0x48, 0x64, 0x3a, 0xa9, 0x15, 0x4a, 0x8a, 0xac, 0x79,
+	0xdf, 0x6d, 0x03, 0x6e, 0x27, 0x27, 0x9f, 0x60, 0xf8, 0x2f, 0xa2, 0x19, 0x61, 0xef, 0x18, 0x71,
+	0xa3, 0x72, 0xfb, 0xf9, 0xbc, 0x46, 0xc5, 0x62, 0xa6, 0x98, 0xf1, 0xee, 0xdd, 0xfe, 0x7c, 0xda,
+	0x3a, 0xf2, 0x12, 0x8e, 0x14, 0x16, 0xac, 0x50, 0xcd, 0x97, 0x70, 0xf6, 0x86, 0xb6, 0xdf, 0x1f,
+	0x3d, 0xba, 0xee, 0x23, 0x83, 0x77, 0x2d, 0xd6, 0x49, 0xa1, 0xca, 0x9a, 0x6e, 0x08, 0x1f, 0xce,
+	0xe0, 0xfe, 0x5f, 0x94, 0x2d, 0xe9, 0xf0, 0xdb, 0xa9, 0xeb, 0x8f, 0x48, 0x33, 0xa8, 0xad, 0x5c,
+	0x27, 0xf1, 0x59, 0xf7, 0xa9, 0xe5, 0x7d, 0xef, 0xc2, 0x51, 0x7b, 0xed, 0x3f, 0x08, 0xca, 0x14,
+	0x9c, 0x16, 0xf1, 0xb4, 0x4c, 0x94, 0xc2, 0xa2, 0x21, 0xee, 0x0c, 0xc8, 0x4e, 0x85, 0xf7, 0xcd,
+	0x02, 0xb8, 0x22, 0xea, 0x7f, 0x3a, 0xd3, 0x85, 0x39, 0x05, 0x9b, 0x36, 0x0d, 0xf1, 0xe1, 0x5e,
+	0x26, 0x44, 0x1a, 0x31, 0x9e, 0xce, 0x90, 0x8b, 0x22, 0x96, 0x66, 0x13, 0x36, 0xbd, 0x0e, 0x93,
+	0xc7, 0x70, 0xe7, 0x4c, 0x94, 0x1c, 0x4f, 0x2e, 0x38, 0x62, 0x8c, 0xf1, 0xfa, 0x12, 0xd8, 0x04,
+	0xc9, 0x10, 0xfa, 0x06, 0x38, 0x65, 0x89, 0xc2, 0x26, 0x7a, 0x07, 0xb4, 0x0d, 0x4d, 0x9c, 0x9f,
+	0x4b, 0xd7, 0xba, 0x5c, 0xba, 0xd6, 0xef, 0xa5, 0x6b, 0x7d, 0x5d, 0xb9, 0x9d, 0xcb, 0x95, 0xdb,
+	0xf9, 0xb5, 0x72, 0x3b, 0xd1, 0xbe, 0xb9, 0xa6, 0xc6, 0x7f, 0x06, 0x00, 0x2f, 0xf3, 0xab, 0x9e,
+	0xe6, 0x04, 0x00, 0x00,
+}
+
 func (m *KeyValueUpdate) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -213,39 +425,46 @@ func (m *KeyValueUpdate) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *KeyValueUpdate) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KeyValueUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Key) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(len(m.Key)))
-		i += copy(dAtA[i:], m.Key)
-	}
-	if len(m.Value) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(len(m.Value)))
-		i += copy(dAtA[i:], m.Value)
-	}
 	if m.Commit {
-		dAtA[i] = 0x18
-		i++
+		i--
 		if m.Commit {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintKv(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return i, nil
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintKv(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
 func (m *KeyValueUpdateResult) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -253,35 +472,43 @@ func (m *KeyValueUpdateResult) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *KeyValueUpdateResult) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KeyValueUpdateResult) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Key) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(len(m.Key)))
-		i += copy(dAtA[i:], m.Key)
+	if len(m.New) > 0 {
+		i -= len(m.New)
+		copy(dAtA[i:], m.New)
+		i = encodeVarintKv(dAtA, i, uint64(len(m.New)))
+		i--
+		dAtA[i] = 0x1a
 	}
 	if len(m.Old) > 0 {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.Old)
+		copy(dAtA[i:], m.Old)
 		i = encodeVarintKv(dAtA, i, uint64(len(m.Old)))
-		i += copy(dAtA[i:], m.Old)
+		i--
+		dAtA[i] = 0x12
 	}
-	if len(m.New) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(len(m.New)))
-		i += copy(dAtA[i:], m.New)
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintKv(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *QueryLimits) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -289,57 +516,155 @@ func (m *QueryLimits) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *QueryLimits) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryLimits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.MaxRecentlyQueriedSeriesBlocks != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(m.MaxRecentlyQueriedSeriesBlocks.Size()))
-		n1, err := m.MaxRecentlyQueriedSeriesBlocks.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if len(m.TenantLimits) > 0 {
+		for k := range m.TenantLimits {
+			v := m.TenantLimits[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintKv(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintKv(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintKv(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.MaxRecentlyQueriedMetadataRead != nil {
+		{
+			size, err := m.MaxRecentlyQueriedMetadataRead.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
 		}
-		i += n1
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.MaxRecentlyQueriedSeriesDiskRead != nil {
+		{
+			size, err := m.MaxRecentlyQueriedSeriesDiskRead.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
 	}
 	if m.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		{
+			size, err := m.MaxRecentlyQueriedSeriesDiskBytesRead.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
+		}
+		i--
 		dAtA[i] = 0x12
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(m.MaxRecentlyQueriedSeriesDiskBytesRead.Size()))
-		n2, err := m.MaxRecentlyQueriedSeriesDiskBytesRead.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	}
+	if m.MaxRecentlyQueriedSeriesBlocks != nil {
+		{
+			size, err := m.MaxRecentlyQueriedSeriesBlocks.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
 		}
-		i += n2
+		i--
+		dAtA[i] = 0xa
 	}
-	if m.MaxRecentlyQueriedSeriesDiskRead != nil {
+	return len(dAtA) - i, nil
+}
+
+func (m *TenantLimits) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TenantLimits) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantLimits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxRecentlyWrittenSeries != nil {
+		{
+			size, err := m.MaxRecentlyWrittenSeries.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
+		}
+		i--
 		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(m.MaxRecentlyQueriedSeriesDiskRead.Size()))
-		n3, err := m.MaxRecentlyQueriedSeriesDiskRead.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	}
+	if m.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		{
+			size, err := m.MaxRecentlyQueriedSeriesDiskBytesRead.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
 		}
-		i += n3
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.MaxRecentlyQueriedMetadataRead != nil {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(m.MaxRecentlyQueriedMetadataRead.Size()))
-		n4, err := m.MaxRecentlyQueriedMetadataRead.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.MaxRecentlyQueriedSeriesBlocks != nil {
+		{
+			size, err := m.MaxRecentlyQueriedSeriesBlocks.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKv(dAtA, i, uint64(size))
 		}
-		i += n4
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *QueryLimit) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -347,53 +672,63 @@ func (m *QueryLimit) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *QueryLimit) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryLimit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Limit != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(m.Limit))
-	}
-	if m.LookbackSeconds != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintKv(dAtA, i, uint64(m.LookbackSeconds))
-	}
-	if m.ForceExceeded {
-		dAtA[i] = 0x18
-		i++
-		if m.ForceExceeded {
+	if m.ForceWaited {
+		i--
+		if m.ForceWaited {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
-	}
-	if m.ForceWaited {
+		i--
 		dAtA[i] = 0x20
-		i++
-		if m.ForceWaited {
+	}
+	if m.ForceExceeded {
+		i--
+		if m.ForceExceeded {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.LookbackSeconds != 0 {
+		i = encodeVarintKv(dAtA, i, uint64(m.LookbackSeconds))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Limit != 0 {
+		i = encodeVarintKv(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func encodeVarintKv(dAtA []byte, offset int, v uint64) int {
+	offset -= sovKv(v)
+	base := offset
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
 		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
-	return offset + 1
+	return base
 }
 func (m *KeyValueUpdate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Key)
@@ -411,6 +746,9 @@ func (m *KeyValueUpdate) Size() (n int) {
 }
 
 func (m *KeyValueUpdateResult) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Key)
@@ -429,6 +767,9 @@ func (m *KeyValueUpdateResult) Size() (n int) {
 }
 
 func (m *QueryLimits) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.MaxRecentlyQueriedSeriesBlocks != nil {
@@ -447,10 +788,47 @@ func (m *QueryLimits) Size() (n int) {
 		l = m.MaxRecentlyQueriedMetadataRead.Size()
 		n += 1 + l + sovKv(uint64(l))
 	}
+	if len(m.TenantLimits) > 0 {
+		for k, v := range m.TenantLimits {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovKv(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovKv(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovKv(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *TenantLimits) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MaxRecentlyQueriedSeriesBlocks != nil {
+		l = m.MaxRecentlyQueriedSeriesBlocks.Size()
+		n += 1 + l + sovKv(uint64(l))
+	}
+	if m.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		l = m.MaxRecentlyQueriedSeriesDiskBytesRead.Size()
+		n += 1 + l + sovKv(uint64(l))
+	}
+	if m.MaxRecentlyWrittenSeries != nil {
+		l = m.MaxRecentlyWrittenSeries.Size()
+		n += 1 + l + sovKv(uint64(l))
+	}
 	return n
 }
 
 func (m *QueryLimit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Limit != 0 {
@@ -469,14 +847,7 @@ func (m *QueryLimit) Size() (n int) {
 }
 
 func sovKv(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
-	}
-	return n
+	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozKv(x uint64) (n int) {
 	return sovKv(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -496,7 +867,7 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -524,7 +895,7 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -534,6 +905,9 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -553,7 +927,7 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -563,6 +937,9 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -582,7 +959,7 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -594,7 +971,7 @@ func (m *KeyValueUpdate) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthKv
 			}
 			if (iNdEx + skippy) > l {
@@ -624,7 +1001,7 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -652,7 +1029,7 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -662,6 +1039,9 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -681,7 +1061,7 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -691,6 +1071,9 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -710,7 +1093,7 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -720,6 +1103,9 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -731,7 +1117,7 @@ func (m *KeyValueUpdateResult) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthKv
 			}
 			if (iNdEx + skippy) > l {
@@ -761,7 +1147,7 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -789,7 +1175,7 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -798,6 +1184,9 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -822,7 +1211,7 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -831,6 +1220,9 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -855,7 +1247,7 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -864,6 +1256,9 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -888,7 +1283,7 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -897,6 +1292,9 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthKv
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -907,13 +1305,300 @@ func (m *QueryLimits) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantLimits", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TenantLimits == nil {
+				m.TenantLimits = make(map[string]*TenantLimits)
+			}
+			var mapkey string
+			var mapvalue *TenantLimits
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowKv
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowKv
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthKv
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthKv
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowKv
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthKv
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthKv
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &TenantLimits{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipKv(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthKv
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.TenantLimits[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipKv(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthKv
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantLimits) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowKv
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantLimits: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantLimits: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRecentlyQueriedSeriesBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRecentlyQueriedSeriesBlocks == nil {
+				m.MaxRecentlyQueriedSeriesBlocks = &QueryLimit{}
+			}
+			if err := m.MaxRecentlyQueriedSeriesBlocks.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRecentlyQueriedSeriesDiskBytesRead", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRecentlyQueriedSeriesDiskBytesRead == nil {
+				m.MaxRecentlyQueriedSeriesDiskBytesRead = &QueryLimit{}
+			}
+			if err := m.MaxRecentlyQueriedSeriesDiskBytesRead.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRecentlyWrittenSeries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKv
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKv
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRecentlyWrittenSeries == nil {
+				m.MaxRecentlyWrittenSeries = &QueryLimit{}
+			}
+			if err := m.MaxRecentlyWrittenSeries.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipKv(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthKv
 			}
 			if (iNdEx + skippy) > l {
@@ -943,7 +1628,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -971,7 +1656,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= (int64(b) & 0x7F) << shift
+				m.Limit |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -990,7 +1675,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LookbackSeconds |= (int64(b) & 0x7F) << shift
+				m.LookbackSeconds |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1009,7 +1694,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1029,7 +1714,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1041,7 +1726,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthKv
 			}
 			if (iNdEx + skippy) > l {
@@ -1059,6 +1744,7 @@ func (m *QueryLimit) Unmarshal(dAtA []byte) error {
 func skipKv(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -1090,10 +1776,8 @@ func skipKv(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -1110,86 +1794,34 @@ func skipKv(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthKv
 			}
-			return iNdEx, nil
+			iNdEx += length
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowKv
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipKv(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupKv
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthKv
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthKv = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowKv   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthKv        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowKv          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupKv = fmt.Errorf("proto: unexpected end of group")
 )
-
-func init() {
-	proto.RegisterFile("github.com/m3db/m3/src/cluster/generated/proto/kvpb/kv.proto", fileDescriptorKv)
-}
-
-var fileDescriptorKv = []byte{
-	// 391 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0xab, 0xd3, 0x40,
-	0x14, 0x35, 0xe6, 0xf9, 0x78, 0xef, 0x16, 0x35, 0x0c, 0x0f, 0xe9, 0x2a, 0x84, 0xa0, 0xd0, 0x55,
-	0x06, 0xec, 0xd6, 0x55, 0xd1, 0x95, 0x15, 0x74, 0x8a, 0x1f, 0x0b, 0x37, 0x93, 0x99, 0xdb, 0x1a,
-	0x32, 0xc9, 0x94, 0xcc, 0xa4, 0x36, 0xbf, 0xc0, 0xad, 0x0b, 0x7f, 0x94, 0x4b, 0x7f, 0x82, 0xd4,
-	0x3f, 0x22, 0x33, 0x29, 0xb4, 0x95, 0xd6, 0x76, 0x13, 0xee, 0x39, 0xb9, 0xe7, 0xdc, 0x3b, 0x87,
-	0x0b, 0x2f, 0x16, 0x85, 0xfd, 0xd2, 0xe6, 0x99, 0xd0, 0x15, 0xad, 0xc6, 0x32, 0xa7, 0xd5, 0x98,
-	0x9a, 0x46, 0x50, 0xa1, 0x5a, 0x63, 0xb1, 0xa1, 0x0b, 0xac, 0xb1, 0xe1, 0x16, 0x25, 0x5d, 0x36,
-	0xda, 0x6a, 0x5a, 0xae, 0x96, 0x39, 0x2d, 0x57, 0x99, 0x47, 0xe4, 0xca, 0xc1, 0xf4, 0x2d, 0x3c,
-	0x7a, 0x8d, 0xdd, 0x07, 0xae, 0x5a, 0x7c, 0xbf, 0x94, 0xdc, 0x22, 0x89, 0x20, 0x2c, 0xb1, 0x1b,
-	0x06, 0x49, 0x30, 0xba, 0x65, 0xae, 0x24, 0x77, 0xf0, 0x60, 0xe5, 0x1a, 0x86, 0xf7, 0x3d, 0xd7,
-	0x03, 0xf2, 0x04, 0xae, 0x85, 0xae, 0xaa, 0xc2, 0x0e, 0xc3, 0x24, 0x18, 0xdd, 0xb0, 0x2d, 0x4a,
-	0xa7, 0x70, 0x77, 0xe8, 0xc8, 0xd0, 0xb4, 0xca, 0x1e, 0xf1, 0x8d, 0x20, 0xd4, 0x4a, 0x6e, 0x5d,
-	0x5d, 0xe9, 0x98, 0x1a, 0xbf, 0x7a, 0xc3, 0x5b, 0xe6, 0xca, 0xf4, 0x5b, 0x08, 0x83, 0x77, 0x2d,
-	0x36, 0xdd, 0xb4, 0xa8, 0x0a, 0x6b, 0xc8, 0x27, 0x88, 0x2b, 0xbe, 0x66, 0x28, 0xb0, 0xb6, 0xaa,
-	0x73, 0x7f, 0x0a, 0x94, 0x33, 0xf7, 0x35, 0x13, 0xa5, 0x45, 0x69, 0xfc, 0x80, 0xc1, 0xf3, 0x28,
-	0x73, 0xcf, 0xcb, 0x76, 0x52, 0x76, 0x46, 0x47, 0xe6, 0xf0, 0xec, 0x54, 0xc7, 0xcb, 0xc2, 0x94,
-	0x93, 0xce, 0xa2, 0x61, 0xc8, 0xfb, 0x7d, 0x8f, 0x0d, 0xb8, 0x4c, 0x4e, 0x3e, 0x43, 0xf2, 0xbf,
-	0x46, 0x3f, 0x22, 0x3c, 0x31, 0xe2, 0xac, 0xf2, 0x78, 0x3e, 0x6f, 0xd0, 0x72, 0xc9, 0x2d, 0xf7,
-	0xde, 0x57, 0x97, 0xe7, 0xb3, 0xaf, 0x4b, 0x7f, 0x04, 0x00, 0xbb, 0x76, 0x77, 0x14, 0xca, 0x15,
-	0x3e, 0xef, 0x90, 0xf5, 0x80, 0x8c, 0xe0, 0xb1, 0xd2, 0xba, 0xcc, 0xb9, 0x28, 0x67, 0x28, 0x74,
-	0x2d, 0x8d, 0x8f, 0x2b, 0x64, 0xff, 0xd2, 0xe4, 0x29, 0x3c, 0x9c, 0xeb, 0x46, 0xe0, 0xab, 0xb5,
-	0x40, 0x94, 0x28, 0xb7, 0x57, 0x74, 0x48, 0x92, 0x04, 0x06, 0x9e, 0xf8, 0xc8, 0x0b, 0x8b, 0xfd,
-	0xee, 0x37, 0x6c, 0x9f, 0x9a, 0x44, 0x3f, 0x37, 0x71, 0xf0, 0x6b, 0x13, 0x07, 0xbf, 0x37, 0x71,
-	0xf0, 0xfd, 0x4f, 0x7c, 0x2f, 0xbf, 0xf6, 0xf7, 0x3d, 0xfe, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x1c,
-	0x39, 0x9e, 0xf7, 0x1f, 0x03, 0x00, 0x00,
-}
@@ -476,6 +476,13 @@ func newCarbonIngesterMetrics(scope tally.Scope) (carbonIngesterMetrics, error)
 //      __g0__:foo
 //      __g1__:bar
 //      __g2__:baz
+// Graphite tagged paths also have their tags added as is, such that an input
+// like:
+//      foo.bar;host=a
+// becomes
+//      __g0__:foo
+//      __g1__:bar
+//      host:a
 func GenerateTagsFromName(
 	name []byte,
 	opts models.TagOptions,
@@ -502,6 +509,10 @@ func generateTagsFromName(
 		return models.EmptyTags(), errCannotGenerateTagsFromEmptyName
 	}
 
+	if graphite.IsTaggedPath(name) {
+		return generateTagsFromTaggedName(name, opts, tags)
+	}
+
 	numTags := bytes.Count(name, carbonSeparatorBytes) + 1
 
 	if cap(tags) >= numTags {
@@ -547,6 +558,38 @@ func generateTagsFromName(
 	return models.Tags{Opts: opts, Tags: tags}, nil
 }
 
+func generateTagsFromTaggedName(
+	name []byte,
+	opts models.TagOptions,
+	tags []models.Tag,
+) (models.Tags, error) {
+	path, taggedPathTags, err := graphite.ParseTaggedPath(name, nil)
+	if err != nil {
+		return models.EmptyTags(), fmt.Errorf("carbon metric: %v", err)
+	}
+
+	pathTags, err := generateTagsFromName(path, opts, tags)
+	if err != nil {
+		return models.EmptyTags(), err
+	}
+
+	for _, tag := range taggedPathTags {
+		pathTags = pathTags.AddTagWithoutNormalizing(models.Tag{
+			Name:  tag.Name,
+			Value: tag.Value,
+		})
+	}
+
+	// NB: tags are added out of order, so must be sorted to generate the ID.
+	pathTags = pathTags.Normalize()
+	if err := pathTags.Validate(); err != nil {
+		return models.EmptyTags(), fmt.Errorf("carbon metric: %s has invalid tags: %v",
+			string(name), err)
+	}
+
+	return pathTags, nil
+}
+
 // Compile all the carbon ingestion rules into matcher so that we can
 // perform matching. Also, generate all the mapping rules and storage
 // policies that we will need to pass to the DownsamplerAndWriter upfront
@@ -604,6 +604,38 @@ func TestGenerateTagsFromName(t *testing.T) {
 			expectedErr:  fmt.Errorf("carbon metric: foo.bar.baz.. has duplicate separator"),
 			expectedTags: []models.Tag{},
 		},
+		{
+			name: "foo.bar;host=a;dc=b",
+			id:   "foo.bar;dc=b;host=a",
+			expectedTags: []models.Tag{
+				{Name: []byte("dc"), Value: []byte("b")},
+				{Name: []byte("host"), Value: []byte("a")},
+				{Name: graphite.TagName(0), Value: []byte("foo")},
+				{Name: graphite.TagName(1), Value: []byte("bar")},
+			},
+		},
+		{
+			name: "foo.bar.;host=a",
+			id:   "foo.bar;host=a",
+			expectedTags: []models.Tag{
+				{Name: []byte("host"), Value: []byte("a")},
+				{Name: graphite.TagName(0), Value: []byte("foo")},
+				{Name: graphite.TagName(1), Value: []byte("bar")},
+			},
+		},
+		{
+			name: "foo;host",
+			expectedErr: fmt.Errorf("carbon metric: " +
+				"invalid tag in tagged path foo;host: host"),
+			expectedTags: []models.Tag{},
+		},
+		{
+			name: "foo;host=a;host=b",
+			expectedErr: fmt.Errorf("carbon metric: foo;host=a;host=b has " +
+				"invalid tags: graphite tags out of order: 'host' appears after " +
+				"'host', tags: [host: a host: b __g0__: foo]"),
+			expectedTags: []models.Tag{},
+		},
 	}
 
 	opts := models.NewTagOptions().SetIDSchemeType(models.TypeGraphite)
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
@@ -53,6 +54,7 @@ type IterValue struct {
 	Unit       xtime.Unit
 	Metadata   ts.Metadata
 	Annotation []byte
+	Exemplars  []exemplar.Exemplar
 }
 
 // DownsampleAndWriteIter is an interface that can be implemented to use
@@ -420,6 +422,7 @@ func (d *downsamplerAndWriter) WriteBatch(
 						Unit:       value.Unit,
 						Annotation: value.Annotation,
 						Attributes: storageAttributesFromPolicy(p),
+						Exemplars:  value.Exemplars,
 					})
 					if err == nil {
 						err = d.store.Write(ctx, writeQuery)
@@ -423,7 +423,7 @@ func TestDownsampleAndWriteWithWriteOverridesAndStoragePolicies(t *testing.T) {
 	// All the datapoints will get written for each of the namespaces.
 	for range aggregatedNamespaces {
 		for _, dp := range testDatapoints1 {
-			session.EXPECT().WriteTagged(
+			session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), gomock.Any())
 		}
 	}
@@ -482,7 +482,7 @@ func TestDownsampleAndWriteBatch(t *testing.T) {
 
 	for _, entry := range testEntries {
 		for _, dp := range entry.datapoints {
-			session.EXPECT().WriteTagged(
+			session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), entry.annotation,
 			)
 		}
@@ -529,7 +529,7 @@ func TestDownsampleAndWriteBatchBadTags(t *testing.T) {
 	// Only expect to write non-bad tags.
 	for _, entry := range testEntries[1:] {
 		for _, dp := range entry.datapoints {
-			session.EXPECT().WriteTagged(
+			session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), entry.annotation,
 			)
 		}
@@ -593,7 +593,7 @@ func TestDownsampleAndWriteBatchDifferentTypes(t *testing.T) {
 
 	for _, entry := range testEntries2 {
 		for _, dp := range entry.datapoints {
-			session.EXPECT().WriteTagged(
+			session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), entry.annotation,
 			)
 		}
@@ -642,7 +642,7 @@ func TestDownsampleAndWriteBatchSingleDrop(t *testing.T) {
 	mockMetricsAppender.EXPECT().Finalize()
 
 	for _, dp := range testEntries[1].datapoints {
-		session.EXPECT().WriteTagged(
+		session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), testEntries[1].annotation,
 		)
 	}
@@ -693,13 +693,13 @@ func TestDownsampleAndWriteBatchDropTimestamp(t *testing.T) {
 	mockMetricsAppender.EXPECT().Finalize()
 
 	for _, dp := range testEntries[0].datapoints {
-		session.EXPECT().WriteTagged(
+		session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), testEntries[0].annotation,
 		)
 	}
 
 	for _, dp := range testEntries[1].datapoints {
-		session.EXPECT().WriteTagged(
+		session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), testEntries[1].annotation,
 		)
 	}
@@ -718,7 +718,7 @@ func TestDownsampleAndWriteBatchNoDownsampler(t *testing.T) {
 
 	for _, entry := range testEntries {
 		for _, dp := range entry.datapoints {
-			session.EXPECT().WriteTagged(
+			session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), entry.annotation,
 			)
 		}
@@ -811,7 +811,7 @@ func TestDownsampleAndWriteBatchOverrideStoragePolicies(t *testing.T) {
 		for _, entry := range entries {
 			for _, dp := range entry.datapoints {
 				namespaceMatcher := ident.NewIDMatcher(namespace.NamespaceID.String())
-				session.EXPECT().WriteTagged(
+				session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 					namespaceMatcher, gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), entry.annotation,
 				)
 			}
@@ -857,7 +857,7 @@ func expectDefaultDownsampling(
 
 func expectDefaultStorageWrites(session *client.MockSession, datapoints []ts.Datapoint, annotation []byte) {
 	for _, dp := range datapoints {
-		session.EXPECT().WriteTagged(
+		session.EXPECT().WriteTaggedWithContext(gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), dp.Value, gomock.Any(), annotation)
 	}
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/commitlog"
 	bfs "github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/fs"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/peers"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/restore"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper/uninitialized"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
@@ -146,6 +147,10 @@ type BootstrapConfiguration struct {
 	// Peers bootstrapper configuration.
 	Peers *BootstrapPeersConfiguration `yaml:"peers"`
 
+	// Restore configures restoring from a backup, if set the restore
+	// bootstrapper runs before every other bootstrapper.
+	Restore *BootstrapRestoreConfiguration `yaml:"restore"`
+
 	// CacheSeriesMetadata determines whether individual bootstrappers cache
 	// series metadata across all calls (namespaces / shards / blocks).
 	CacheSeriesMetadata *bool `yaml:"cacheSeriesMetadata"`
@@ -260,6 +265,23 @@ type BootstrapPeersConfiguration struct {
 	// for historical data being streamed between peers (historical blocks).
 	// Defaults to: 1.
 	StreamPersistShardFlushConcurrency *int `yaml:"streamPersistShardFlushConcurrency"`
+	// StreamPersistToDisk controls whether historical blocks streamed between
+	// peers are written straight to disk fileset-by-fileset, validated against
+	// the block checksums of the peers and checkpointed so that a restarted
+	// bootstrap resumes rather than starting over.
+	// Defaults to: false.
+	StreamPersistToDisk *bool `yaml:"streamPersistToDisk"`
+}
+
+// BootstrapRestoreConfiguration specifies config for the restore bootstrapper.
+type BootstrapRestoreConfiguration struct {
+	// Directory is the directory of the backup to restore from, i.e. the
+	// backup directory of the nodes followed by the ID of the backup.
+	Directory string `yaml:"directory" validate:"nonzero"`
+	// Namespaces maps the namespaces to restore to the namespaces of the
+	// backup to restore them from, namespaces that are not mapped are
+	// restored from the namespace of the same name.
+	Namespaces map[string]string `yaml:"namespaces"`
 }
 
 // New creates a bootstrap process based on the bootstrap configuration.
@@ -364,6 +386,9 @@ func (bsc BootstrapConfiguration) New(
 			if pCfg.StreamPersistShardFlushConcurrency != nil {
 				pOpts = pOpts.SetShardPersistenceFlushConcurrency(*pCfg.StreamPersistShardFlushConcurrency)
 			}
+			if pCfg.StreamPersistToDisk != nil {
+				pOpts = pOpts.SetStreamingPersistEnabled(*pCfg.StreamPersistToDisk)
+			}
 			if v := bsc.IndexSegmentConcurrency; v != nil {
 				pOpts = pOpts.SetIndexSegmentConcurrency(*v)
 			}
@@ -374,6 +399,21 @@ func (bsc BootstrapConfiguration) New(
 			if err != nil {
 				return nil, err
 			}
+		case restore.RestoreBootstrapperName:
+			rCfg := bsc.Restore
+			if rCfg == nil {
+				return nil, errors.New("restore bootstrapper requires restore configuration")
+			}
+			rOpts := restore.NewOptions().
+				SetResultOptions(rsOpts).
+				SetFilesystemOptions(fsOpts).
+				SetInstrumentOptions(opts.InstrumentOptions()).
+				SetBackupDirectory(rCfg.Directory).
+				SetNamespaceMappings(rCfg.Namespaces)
+			bs, err = restore.NewRestoreBootstrapperProvider(rOpts, bs)
+			if err != nil {
+				return nil, err
+			}
 		case uninitialized.UninitializedTopologyBootstrapperName:
 			uOpts := uninitialized.NewOptions().
 				SetResultOptions(rsOpts).
@@ -418,6 +458,16 @@ func (bsc BootstrapConfiguration) peersConfig() BootstrapPeersConfiguration {
 }
 
 func (bsc BootstrapConfiguration) orderedBootstrappers() []string {
+	bootstrappers := bsc.modeOrderedBootstrappers()
+	if bsc.Restore != nil {
+		// Restoring must come first so that the filesystem bootstrapper
+		// loads the restored filesets.
+		return append([]string{restore.RestoreBootstrapperName}, bootstrappers...)
+	}
+	return bootstrappers
+}
+
+func (bsc BootstrapConfiguration) modeOrderedBootstrappers() []string {
 	if bsc.BootstrapMode != nil {
 		switch *bsc.BootstrapMode {
 		case DefaultBootstrapMode:
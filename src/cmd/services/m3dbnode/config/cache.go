@@ -20,13 +20,21 @@
 
 package config
 
-import "github.com/m3db/m3/src/dbnode/storage/series"
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/series"
+)
 
 var (
 	defaultPostingsListCacheSize   = 2 << 11 // 4096
 	defaultPostingsListCacheRegexp = true
 	defaultPostingsListCacheTerms  = true
 	defaultRegexpCacheSize         = 256
+
+	defaultPostingsListCacheWarmupSize            = 1024
+	defaultPostingsListCacheWarmupMaxDuration     = 5 * time.Minute
+	defaultPostingsListCacheWarmupPersistInterval = time.Minute
 )
 
 // CacheConfigurations is the cache configurations.
@@ -84,9 +92,10 @@ type LRUSeriesCachePolicyConfiguration struct {
 
 // PostingsListCacheConfiguration is the postings list cache configuration.
 type PostingsListCacheConfiguration struct {
-	Size        *int  `yaml:"size"`
-	CacheRegexp *bool `yaml:"cacheRegexp"`
-	CacheTerms  *bool `yaml:"cacheTerms"`
+	Size        *int                                  `yaml:"size"`
+	CacheRegexp *bool                                 `yaml:"cacheRegexp"`
+	CacheTerms  *bool                                 `yaml:"cacheTerms"`
+	Warmup      *PostingsListCacheWarmupConfiguration `yaml:"warmup"`
 }
 
 // SizeOrDefault returns the provided size or the default value is none is
@@ -119,6 +128,60 @@ func (p PostingsListCacheConfiguration) CacheTermsOrDefault() bool {
 	return *p.CacheTerms
 }
 
+// WarmupConfiguration returns the provided warmup configuration or the
+// default value if none is provided.
+func (p PostingsListCacheConfiguration) WarmupConfiguration() PostingsListCacheWarmupConfiguration {
+	if p.Warmup == nil {
+		return PostingsListCacheWarmupConfiguration{}
+	}
+
+	return *p.Warmup
+}
+
+// PostingsListCacheWarmupConfiguration is the postings list cache warmup
+// configuration, when enabled the hottest cached queries are persisted and
+// executed again after bootstrapping to warm the cache after a restart.
+type PostingsListCacheWarmupConfiguration struct {
+	// Enabled enables the warmup.
+	Enabled bool `yaml:"enabled"`
+	// Size is the maximum number of queries persisted and executed.
+	Size *int `yaml:"size"`
+	// MaxDuration is the maximum duration of the warmup.
+	MaxDuration *time.Duration `yaml:"maxDuration"`
+	// PersistInterval is the interval at which the queries are persisted.
+	PersistInterval *time.Duration `yaml:"persistInterval"`
+}
+
+// SizeOrDefault returns the provided size or the default value is none is
+// provided.
+func (p PostingsListCacheWarmupConfiguration) SizeOrDefault() int {
+	if p.Size == nil {
+		return defaultPostingsListCacheWarmupSize
+	}
+
+	return *p.Size
+}
+
+// MaxDurationOrDefault returns the provided max duration or the default value
+// is none is provided.
+func (p PostingsListCacheWarmupConfiguration) MaxDurationOrDefault() time.Duration {
+	if p.MaxDuration == nil {
+		return defaultPostingsListCacheWarmupMaxDuration
+	}
+
+	return *p.MaxDuration
+}
+
+// PersistIntervalOrDefault returns the provided persist interval or the
+// default value is none is provided.
+func (p PostingsListCacheWarmupConfiguration) PersistIntervalOrDefault() time.Duration {
+	if p.PersistInterval == nil {
+		return defaultPostingsListCacheWarmupPersistInterval
+	}
+
+	return *p.PersistInterval
+}
+
 // RegexpCacheConfiguration is a compiled regexp cache for query regexps.
 type RegexpCacheConfiguration struct {
 	Size *int `yaml:"size"`
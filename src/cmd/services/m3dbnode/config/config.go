@@ -38,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/discovery"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/x/config/hostid"
@@ -52,6 +53,8 @@ const (
 	defaultEtcdListenHost = "http://0.0.0.0"
 	defaultEtcdClientPort = 2379
 	defaultEtcdServerPort = 2380
+
+	defaultMaxRestoredVolumes = 64
 )
 
 var (
@@ -212,6 +215,17 @@ type DBConfiguration struct {
 	// ForceColdWritesEnabled will force enable cold writes for all namespaces
 	// if set.
 	ForceColdWritesEnabled *bool `yaml:"forceColdWritesEnabled"`
+
+	// Exemplars configures the in-memory exemplar store.
+	Exemplars *ExemplarsConfiguration `yaml:"exemplars"`
+
+	// TieredStorage configures offloading old data filesets to a blob store,
+	// if not set data filesets are only kept on local disk.
+	TieredStorage *TieredStorageConfiguration `yaml:"tieredStorage"`
+
+	// Backup configures taking part in cluster-wide backups, if not set the
+	// node ignores backup requests.
+	Backup *BackupConfiguration `yaml:"backup"`
 }
 
 // LoggingOrDefault returns the logging configuration or defaults.
@@ -631,6 +645,49 @@ type WideConfiguration struct {
 	BatchSize int `yaml:"batchSize"`
 }
 
+// ExemplarsConfiguration is the configuration for the in-memory exemplar store.
+type ExemplarsConfiguration struct {
+	// MaxExemplarsPerNamespace is the maximum number of exemplars held per
+	// namespace, once reached the oldest exemplars are evicted first. Zero
+	// disables storing exemplars.
+	MaxExemplarsPerNamespace int `yaml:"maxExemplarsPerNamespace" validate:"min=0"`
+}
+
+// TieredStorageConfiguration is the configuration for offloading data
+// fileset volumes from local disk to a blob store.
+type TieredStorageConfiguration struct {
+	// LocalDirectory is the directory of the blob store that volumes are
+	// offloaded to, typically a mounted network or object store file system.
+	LocalDirectory string `yaml:"localDirectory" validate:"nonzero"`
+
+	// OffloadAfter is how long after the end of a block its data fileset is
+	// offloaded.
+	OffloadAfter time.Duration `yaml:"offloadAfter" validate:"nonzero"`
+
+	// MaxRestoredVolumes is the number of offloaded volumes kept on local
+	// disk after being read, defaults to 64.
+	MaxRestoredVolumes int `yaml:"maxRestoredVolumes" validate:"min=0"`
+}
+
+// OffloadOptions returns the offload options of the configuration.
+func (c TieredStorageConfiguration) OffloadOptions() fs.OffloadOptions {
+	maxRestoredVolumes := c.MaxRestoredVolumes
+	if maxRestoredVolumes == 0 {
+		maxRestoredVolumes = defaultMaxRestoredVolumes
+	}
+	return fs.OffloadOptions{
+		OffloadAfter:       c.OffloadAfter,
+		MaxRestoredVolumes: maxRestoredVolumes,
+	}
+}
+
+// BackupConfiguration is the configuration for cluster-wide backups.
+type BackupConfiguration struct {
+	// Directory is the directory backups are written to, each backup is
+	// written to a sub directory named after the ID of the backup.
+	Directory string `yaml:"directory" validate:"nonzero"`
+}
+
 // NamespaceProtoSchema is the namespace protobuf schema.
 type NamespaceProtoSchema struct {
 	// For application m3db client integration test convenience (where a local dbnode is started as a docker container),
@@ -419,6 +419,7 @@ func TestConfiguration(t *testing.T) {
     commitlog:
       returnUnfulfilledForCorruptCommitLogFiles: false
     peers: null
+    restore: null
     cacheSeriesMetadata: null
     indexSegmentConcurrency: null
     verify: null
@@ -429,6 +430,7 @@ func TestConfiguration(t *testing.T) {
       size: 100
       cacheRegexp: false
       cacheTerms: false
+      warmup: null
     regexp: null
   filesystem:
     filePathPrefix: /var/lib/m3db
@@ -755,6 +757,9 @@ func TestConfiguration(t *testing.T) {
     mutexProfileFraction: 0
     blockProfileRate: 0
   forceColdWritesEnabled: null
+  exemplars: null
+  tieredStorage: null
+  backup: null
 coordinator: null
 `
 
@@ -167,6 +167,9 @@ type Configuration struct {
 	// Carbon is the carbon configuration.
 	Carbon *CarbonConfiguration `yaml:"carbon"`
 
+	// OTLP is the OpenTelemetry protocol (OTLP) metrics receiver configuration.
+	OTLP OTLPConfiguration `yaml:"otlp"`
+
 	// Middleware is middleware-specific configuration.
 	Middleware MiddlewareConfiguration `yaml:"middleware"`
 
@@ -283,6 +286,11 @@ type QueryConfiguration struct {
 	// RequireSeriesEndpointStartEndTime requires requests to /series endpoint
 	// to specify a start and end time to prevent unbounded queries.
 	RequireSeriesEndpointStartEndTime bool `yaml:"requireSeriesEndpointStartEndTime"`
+	// CountSeriesFromIndex enables counting the series of instant ungrouped
+	// count and absent queries of a selector from the index, without fetching
+	// their data. Series which are indexed but have no datapoints within the
+	// lookback window, e.g. stale series, are counted as present.
+	CountSeriesFromIndex bool `yaml:"countSeriesFromIndex"`
 }
 
 // TimeoutOrDefault returns the configured timeout or default value.
@@ -430,6 +438,28 @@ type IngestConfiguration struct {
 	M3Msg m3msg.Configuration `yaml:"m3msg"`
 }
 
+// OTLPConfiguration is the configuration for the OpenTelemetry protocol
+// (OTLP) metrics receiver, OTLP/HTTP is always served by the HTTP server.
+type OTLPConfiguration struct {
+	// GRPC if set defines an OTLP/gRPC server to run.
+	GRPC *OTLPGRPCConfiguration `yaml:"grpc"`
+
+	// PromoteResourceAttributes are the resource attributes added as labels
+	// to each series, in addition to the job and instance labels derived from
+	// the service attributes.
+	PromoteResourceAttributes []string `yaml:"promoteResourceAttributes"`
+
+	// DeltaExpiry is how long the running total of a series with delta
+	// temporality is kept after it was last written.
+	DeltaExpiry *time.Duration `yaml:"deltaExpiry"`
+}
+
+// OTLPGRPCConfiguration is the configuration for the OTLP/gRPC server.
+type OTLPGRPCConfiguration struct {
+	// ListenAddress is the OTLP/gRPC server listen address.
+	ListenAddress string `yaml:"listenAddress"`
+}
+
 // CarbonConfiguration is the configuration for the carbon server.
 type CarbonConfiguration struct {
 	// Ingester if set defines an ingester to run for carbon.
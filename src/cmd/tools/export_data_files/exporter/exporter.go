@@ -0,0 +1,339 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package exporter exports the data filesets of a namespace as Prometheus
+// TSDB blocks or Parquet files.
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"go.uber.org/zap"
+)
+
+const defaultParquetRowGroupSize = 1 << 16
+
+var (
+	errNamespaceIDNotSet = errors.New("namespace id not set")
+	errOutputDirNotSet   = errors.New("output dir not set")
+	errInvalidTimeRange  = errors.New("end must be after start")
+)
+
+// Options are the options for an export.
+type Options struct {
+	// FilePathPrefix is the M3DB data directory to read filesets from.
+	FilePathPrefix string
+	// NamespaceID is the ID of the namespace to export.
+	NamespaceID ident.ID
+	// EncodingScheme is the encoding scheme of the namespace.
+	EncodingScheme namespace.EncodingScheme
+	// Start is the inclusive start of the time range to export.
+	Start xtime.UnixNano
+	// End is the exclusive end of the time range to export.
+	End xtime.UnixNano
+	// Matchers select the series to export using the index volumes of the
+	// namespace, if empty all series are exported.
+	Matchers []*labels.Matcher
+	// Format is the format to export to.
+	Format Format
+	// OutputDir is the directory the blocks or files are written to.
+	OutputDir string
+	// ParquetRowGroupSize is the number of rows per Parquet row group, if
+	// zero a default is used.
+	ParquetRowGroupSize int
+	// Logger is the logger to use, if nil no progress is logged.
+	Logger *zap.Logger
+}
+
+// Validate validates the options.
+func (o Options) Validate() error {
+	if o.NamespaceID == nil {
+		return errNamespaceIDNotSet
+	}
+	if o.OutputDir == "" {
+		return errOutputDirNotSet
+	}
+	if !o.End.After(o.Start) {
+		return errInvalidTimeRange
+	}
+	if err := o.EncodingScheme.Validate(); err != nil {
+		return err
+	}
+	return o.Format.Validate()
+}
+
+// Result describes the outcome of an export.
+type Result struct {
+	// NumBlocks is the number of Prometheus blocks or Parquet files written.
+	NumBlocks int
+	// NumSeriesBlocks is the number of series blocks exported.
+	NumSeriesBlocks int
+	// NumDatapoints is the number of datapoints exported.
+	NumDatapoints int
+}
+
+// Export walks the latest volumes of the data filesets of the namespace that
+// overlap the time range and writes the matching series to one Prometheus
+// block or Parquet file per namespace block.
+func Export(opts Options) (Result, error) {
+	if err := opts.Validate(); err != nil {
+		return Result{}, err
+	}
+	if opts.ParquetRowGroupSize <= 0 {
+		opts.ParquetRowGroupSize = defaultParquetRowGroupSize
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	fsOpts := fs.NewOptions().SetFilePathPrefix(opts.FilePathPrefix)
+	var filter *seriesFilter
+	if len(opts.Matchers) > 0 {
+		var err error
+		filter, err = newSeriesFilter(opts.NamespaceID, opts.Matchers, fsOpts)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	reader, err := fs.NewReader(nil, fsOpts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	filesets, err := dataFileSets(opts)
+	if err != nil {
+		return Result{}, err
+	}
+	blockStarts := make([]xtime.UnixNano, 0, len(filesets))
+	for blockStart := range filesets {
+		blockStarts = append(blockStarts, blockStart)
+	}
+	sort.Slice(blockStarts, func(i, j int) bool {
+		return blockStarts[i].Before(blockStarts[j])
+	})
+
+	e := &exporter{
+		opts:   opts,
+		reader: reader,
+		filter: filter,
+		iter:   newReaderIterator(opts.EncodingScheme),
+	}
+	for _, blockStart := range blockStarts {
+		if err := e.exportBlock(blockStart, filesets[blockStart]); err != nil {
+			return e.result, fmt.Errorf("unable to export block %s: %v",
+				blockStart.ToTime().UTC(), err)
+		}
+	}
+	return e.result, nil
+}
+
+// dataFileSets returns the latest complete data fileset volumes of every
+// shard by block start, skipping blocks that start after the time range.
+func dataFileSets(opts Options) (map[xtime.UnixNano][]fs.FileSetFile, error) {
+	dirs, err := ioutil.ReadDir(fs.NamespaceDataDirPath(opts.FilePathPrefix, opts.NamespaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[xtime.UnixNano][]fs.FileSetFile)
+	for _, dir := range dirs {
+		shard, err := strconv.ParseUint(dir.Name(), 10, 32)
+		if !dir.IsDir() || err != nil {
+			continue
+		}
+		files, err := fs.DataFiles(opts.FilePathPrefix, opts.NamespaceID, uint32(shard))
+		if err != nil {
+			return nil, err
+		}
+
+		var blockStarts []xtime.UnixNano
+		for _, f := range files {
+			blockStart := f.ID.BlockStart
+			if !blockStart.Before(opts.End) ||
+				len(blockStarts) > 0 && blockStarts[len(blockStarts)-1].Equal(blockStart) {
+				continue
+			}
+			blockStarts = append(blockStarts, blockStart)
+		}
+		for _, blockStart := range blockStarts {
+			if latest, ok := files.LatestVolumeForBlock(blockStart); ok {
+				result[blockStart] = append(result[blockStart], latest)
+			}
+		}
+	}
+	return result, nil
+}
+
+func newReaderIterator(scheme namespace.EncodingScheme) encoding.ReaderIterator {
+	encodingOpts := encoding.NewOptions()
+	if scheme == namespace.XOREncodingScheme {
+		return xor.NewReaderIterator(nil, encodingOpts)
+	}
+	return m3tsz.NewReaderIterator(nil, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+}
+
+type exporter struct {
+	opts       Options
+	reader     fs.DataFileSetReader
+	filter     *seriesFilter
+	iter       encoding.ReaderIterator
+	bytes      xio.BytesReader64
+	datapoints []ts.Datapoint
+	result     Result
+}
+
+func (e *exporter) exportBlock(blockStart xtime.UnixNano, filesets []fs.FileSetFile) error {
+	var writer blockWriter
+	for _, f := range filesets {
+		w, err := e.exportFileSet(blockStart, f, writer)
+		if err != nil {
+			if w != nil {
+				w.Close()
+			}
+			return err
+		}
+		writer = w
+	}
+	if writer == nil {
+		return nil
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	e.result.NumBlocks++
+	e.opts.Logger.Info("exported block",
+		zap.Time("blockStart", blockStart.ToTime()),
+		zap.Int("numFileSets", len(filesets)))
+	return nil
+}
+
+// exportFileSet exports the matching series of the fileset, creating the
+// block writer on the first series to write if it is nil.
+func (e *exporter) exportFileSet(
+	blockStart xtime.UnixNano,
+	f fs.FileSetFile,
+	writer blockWriter,
+) (blockWriter, error) {
+	err := e.reader.Open(fs.DataReaderOpenOptions{
+		Identifier:       f.ID,
+		FileSetType:      persist.FileSetFlushType,
+		StreamingEnabled: true,
+	})
+	if err != nil {
+		return writer, err
+	}
+	defer e.reader.Close()
+
+	blockSize := e.reader.Status().BlockSize
+	if !blockStart.Add(blockSize).After(e.opts.Start) {
+		return writer, nil
+	}
+
+	for {
+		entry, err := e.reader.StreamingRead()
+		if err == io.EOF {
+			return writer, nil
+		}
+		if err != nil {
+			return writer, err
+		}
+
+		if e.filter != nil {
+			matches, err := e.filter.Matches(blockStart, entry.ID)
+			if err != nil {
+				return writer, err
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		if err := e.decode(entry.Data); err != nil {
+			return writer, err
+		}
+		if len(e.datapoints) == 0 {
+			continue
+		}
+
+		metadata, err := convert.FromSeriesIDAndEncodedTags(entry.ID, entry.EncodedTags)
+		if err != nil {
+			return writer, err
+		}
+		if writer == nil {
+			writer, err = e.newBlockWriter(blockStart, blockSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := writer.WriteSeries(metadata, e.datapoints); err != nil {
+			return writer, err
+		}
+		e.result.NumSeriesBlocks++
+		e.result.NumDatapoints += len(e.datapoints)
+	}
+}
+
+func (e *exporter) decode(data []byte) error {
+	e.datapoints = e.datapoints[:0]
+	e.bytes.Reset(data)
+	e.iter.Reset(&e.bytes, nil)
+	for e.iter.Next() {
+		dp, _, _ := e.iter.Current()
+		if dp.TimestampNanos.Before(e.opts.Start) || !dp.TimestampNanos.Before(e.opts.End) {
+			continue
+		}
+		e.datapoints = append(e.datapoints, dp)
+	}
+	return e.iter.Err()
+}
+
+func (e *exporter) newBlockWriter(
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+) (blockWriter, error) {
+	switch e.opts.Format {
+	case ParquetFormat:
+		return newParquetBlockWriter(e.opts.OutputDir, e.opts.NamespaceID,
+			blockStart, e.opts.ParquetRowGroupSize)
+	default:
+		return newPrometheusBlockWriter(e.opts.OutputDir, blockSize)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/tools/import_prometheus_blocks/importer"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testBlockSize = time.Hour
+
+var testStart = xtime.UnixNano(0).Add(100 * 24 * time.Hour)
+
+type testSample struct {
+	t int64
+	v float64
+}
+
+func (s testSample) T() int64   { return s.t }
+func (s testSample) V() float64 { return s.v }
+
+func testLabels(name string) labels.Labels {
+	return labels.FromStrings(labels.MetricName, name, "job", "test")
+}
+
+func testSamples(start, end xtime.UnixNano) []tsdbutil.Sample {
+	var samples []tsdbutil.Sample
+	for t := start; t.Before(end); t = t.Add(time.Minute) {
+		samples = append(samples, testSample{t: toMillis(t), v: float64(len(samples))})
+	}
+	return samples
+}
+
+func toMillis(t xtime.UnixNano) int64 {
+	return int64(t) / int64(time.Millisecond)
+}
+
+// setupTestNamespace writes two hours of foo and bar series to the filesets
+// and index volumes of a namespace with one hour blocks.
+func setupTestNamespace(
+	t *testing.T,
+	dir string,
+	encodingScheme namespace.EncodingScheme,
+) Options {
+	var (
+		promDir = filepath.Join(dir, "prometheus")
+		end     = testStart.Add(2 * testBlockSize)
+	)
+	series := []storage.Series{
+		storage.NewListSeries(testLabels("foo"), testSamples(testStart, end)),
+		storage.NewListSeries(testLabels("bar"), testSamples(testStart, end)),
+	}
+	_, err := tsdb.CreateBlock(series, promDir, int64(2*time.Hour/time.Millisecond),
+		kitlog.NewNopLogger())
+	require.NoError(t, err)
+
+	blockDirs, err := importer.BlockDirs(promDir)
+	require.NoError(t, err)
+	shardSet, err := sharding.NewShardSet(sharding.NewShards([]uint32{0, 1}, shard.Available),
+		sharding.DefaultHashFn(2))
+	require.NoError(t, err)
+	_, err = importer.Import(importer.Options{
+		FilePathPrefix: filepath.Join(dir, "m3db"),
+		NamespaceID:    ident.StringID("metrics"),
+		BlockSize:      testBlockSize,
+		IndexBlockSize: 2 * testBlockSize,
+		EncodingScheme: encodingScheme,
+		ShardSet:       shardSet,
+	}, blockDirs)
+	require.NoError(t, err)
+
+	outputDir := filepath.Join(dir, "output")
+	require.NoError(t, os.Mkdir(outputDir, 0755))
+	return Options{
+		FilePathPrefix: filepath.Join(dir, "m3db"),
+		NamespaceID:    ident.StringID("metrics"),
+		EncodingScheme: encodingScheme,
+		Start:          testStart,
+		End:            end,
+		OutputDir:      outputDir,
+	}
+}
+
+func readPrometheusBlocks(t *testing.T, dir string) map[string][]testSample {
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	result := make(map[string][]testSample)
+	for _, entry := range entries {
+		block, err := tsdb.OpenBlock(nil, filepath.Join(dir, entry.Name()), nil)
+		require.NoError(t, err)
+		querier, err := tsdb.NewBlockQuerier(block, block.MinTime(), block.MaxTime())
+		require.NoError(t, err)
+
+		set := querier.Select(false, nil,
+			labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".*"))
+		for set.Next() {
+			series := set.At()
+			name := series.Labels().Get(labels.MetricName)
+			iter := series.Iterator()
+			for iter.Next() {
+				ts, v := iter.At()
+				result[name] = append(result[name], testSample{t: ts, v: v})
+			}
+			require.NoError(t, iter.Err())
+		}
+		require.NoError(t, set.Err())
+		require.NoError(t, querier.Close())
+		require.NoError(t, block.Close())
+	}
+	for _, samples := range result {
+		sort.Slice(samples, func(i, j int) bool { return samples[i].t < samples[j].t })
+	}
+	return result
+}
+
+func testExportPrometheus(t *testing.T, encodingScheme namespace.EncodingScheme) {
+	dir, err := ioutil.TempDir("", "export_data_files")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := setupTestNamespace(t, dir, encodingScheme)
+	opts.Format = PrometheusFormat
+	opts.Matchers = []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "foo"),
+	}
+
+	result, err := Export(opts)
+	require.NoError(t, err)
+	require.Equal(t, Result{
+		NumBlocks:       2,
+		NumSeriesBlocks: 2,
+		NumDatapoints:   120,
+	}, result)
+
+	var expected []testSample
+	for _, s := range testSamples(testStart, opts.End) {
+		expected = append(expected, testSample{t: s.T(), v: s.V()})
+	}
+	require.Equal(t, map[string][]testSample{"foo": expected},
+		readPrometheusBlocks(t, opts.OutputDir))
+}
+
+func TestExportPrometheusM3TSZ(t *testing.T) {
+	testExportPrometheus(t, namespace.M3TSZEncodingScheme)
+}
+
+func TestExportPrometheusXOR(t *testing.T) {
+	testExportPrometheus(t, namespace.XOREncodingScheme)
+}
+
+func TestExportParquetTimeRange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export_data_files")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := setupTestNamespace(t, dir, namespace.M3TSZEncodingScheme)
+	opts.Format = ParquetFormat
+	opts.ParquetRowGroupSize = 7
+	opts.Start = testStart.Add(90 * time.Minute)
+
+	result, err := Export(opts)
+	require.NoError(t, err)
+	require.Equal(t, Result{
+		NumBlocks:       1,
+		NumSeriesBlocks: 2,
+		NumDatapoints:   60,
+	}, result)
+
+	blockStart := testStart.Add(testBlockSize)
+	data, err := ioutil.ReadFile(filepath.Join(opts.OutputDir,
+		fmt.Sprintf("metrics-%d.parquet", int64(blockStart))))
+	require.NoError(t, err)
+	rows := readParquetFile(t, data)
+	require.Len(t, rows, 60)
+
+	names := make(map[string]int)
+	for _, row := range rows {
+		require.False(t, xtime.UnixNano(row.timestamp).Before(opts.Start))
+		require.True(t, xtime.UnixNano(row.timestamp).Before(opts.End))
+		require.Contains(t, row.id, `job="test"`)
+		require.Contains(t, row.tags, `"job":"test"`)
+		names[row.tags]++
+	}
+	require.Equal(t, map[string]int{
+		`{"__name__":"bar","job":"test"}`: 30,
+		`{"__name__":"foo","job":"test"}`: 30,
+	}, names)
+}
+
+func TestExportNoIndexVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export_data_files")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := setupTestNamespace(t, dir, namespace.M3TSZEncodingScheme)
+	opts.Matchers = []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "foo"),
+	}
+	opts.End = testStart.Add(4 * testBlockSize)
+	require.NoError(t, os.RemoveAll(filepath.Join(opts.FilePathPrefix, "index")))
+
+	_, err = Export(opts)
+	require.Error(t, err)
+}
+
+func TestFormatParse(t *testing.T) {
+	for _, f := range ValidFormats() {
+		parsed, err := ParseFormat(f.String())
+		require.NoError(t, err)
+		require.Equal(t, f, parsed)
+	}
+	_, err := ParseFormat("csv")
+	require.Error(t, err)
+}
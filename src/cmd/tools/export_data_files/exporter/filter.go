@@ -0,0 +1,196 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst/encoding/docs"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/executor"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// seriesFilter matches series IDs against the results of querying the
+// index volumes of the index block a data block belongs to, only holding
+// the results of a single index block at a time.
+type seriesFilter struct {
+	namespace ident.ID
+	query     search.Query
+	fsOpts    fs.Options
+	volumes   []fs.ReadIndexInfoFileResult
+
+	loaded     bool
+	blockStart xtime.UnixNano
+	blockEnd   xtime.UnixNano
+	matched    map[string]struct{}
+}
+
+func newSeriesFilter(
+	namespace ident.ID,
+	matchers []*labels.Matcher,
+	fsOpts fs.Options,
+) (*seriesFilter, error) {
+	modelMatchers, err := promql.LabelMatchersToModelMatcher(matchers, models.NewTagOptions())
+	if err != nil {
+		return nil, err
+	}
+	query, err := storage.FetchQueryToM3Query(&storage.FetchQuery{
+		TagMatchers: modelMatchers,
+	}, storage.NewFetchOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := fs.ReadIndexInfoFiles(fs.ReadIndexInfoFilesOptions{
+		FilePathPrefix:   fsOpts.FilePathPrefix(),
+		Namespace:        namespace,
+		ReaderBufferSize: fsOpts.InfoReaderBufferSize(),
+	})
+	for _, volume := range volumes {
+		if err := volume.Err.Error(); err != nil {
+			return nil, fmt.Errorf("unable to read index info file %s: %v",
+				volume.Err.Filepath(), err)
+		}
+	}
+
+	return &seriesFilter{
+		namespace: namespace,
+		query:     query.Query.SearchQuery(),
+		fsOpts:    fsOpts,
+		volumes:   volumes,
+	}, nil
+}
+
+// Matches returns whether the series with the given ID in the data block
+// starting at blockStart matches the query.
+func (f *seriesFilter) Matches(blockStart xtime.UnixNano, id []byte) (bool, error) {
+	if !f.loaded || blockStart.Before(f.blockStart) || !blockStart.Before(f.blockEnd) {
+		if err := f.load(blockStart); err != nil {
+			return false, err
+		}
+	}
+	_, ok := f.matched[string(id)]
+	return ok, nil
+}
+
+func (f *seriesFilter) load(blockStart xtime.UnixNano) error {
+	f.loaded = false
+	f.matched = make(map[string]struct{})
+
+	found := false
+	for _, volume := range f.volumes {
+		var (
+			start = xtime.UnixNano(volume.Info.BlockStart)
+			end   = start.Add(time.Duration(volume.Info.BlockSize))
+		)
+		if blockStart.Before(start) || !blockStart.Before(end) {
+			continue
+		}
+		if err := f.queryVolume(volume); err != nil {
+			return err
+		}
+		found = true
+		f.blockStart, f.blockEnd = start, end
+	}
+	if !found {
+		return fmt.Errorf("no index volume covers block start %s",
+			blockStart.ToTime().UTC())
+	}
+
+	f.loaded = true
+	return nil
+}
+
+func (f *seriesFilter) queryVolume(volume fs.ReadIndexInfoFileResult) error {
+	result, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+		ReaderOptions: fs.IndexReaderOpenOptions{
+			Identifier:  volume.ID,
+			FileSetType: persist.FileSetFlushType,
+		},
+		FilesystemOptions: f.fsOpts,
+	})
+	if err != nil {
+		return err
+	}
+
+	var multiErr xerrors.MultiError
+	readers := make([]index.Reader, 0, len(result.Segments))
+	for _, seg := range result.Segments {
+		reader, err := seg.Reader()
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+		readers = append(readers, reader)
+	}
+	if multiErr.Empty() {
+		multiErr = multiErr.Add(f.executeQuery(readers))
+	} else {
+		for _, reader := range readers {
+			multiErr = multiErr.Add(reader.Close())
+		}
+	}
+	for _, seg := range result.Segments {
+		multiErr = multiErr.Add(seg.Close())
+	}
+	return multiErr.FinalError()
+}
+
+func (f *seriesFilter) executeQuery(readers []index.Reader) error {
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	exec := executor.NewExecutor(readers)
+	defer exec.Close()
+
+	iter, err := exec.Execute(ctx, f.query)
+	if err != nil {
+		return err
+	}
+
+	reader := docs.NewEncodedDocumentReader()
+	for iter.Next() {
+		metadata, err := docs.MetadataFromDocument(iter.Current(), reader)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		f.matched[string(metadata.ID)] = struct{}{}
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return err
+	}
+	return iter.Close()
+}
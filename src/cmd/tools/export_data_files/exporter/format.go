@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"fmt"
+)
+
+// Format is the format namespace data is exported to.
+type Format uint8
+
+const (
+	// PrometheusFormat exports one Prometheus TSDB block per namespace block.
+	PrometheusFormat Format = iota
+	// ParquetFormat exports one Parquet file per namespace block, with a row
+	// holding the series ID, tags, timestamp and value of each datapoint.
+	ParquetFormat
+)
+
+// ValidFormats returns the valid export formats.
+func ValidFormats() []Format {
+	return []Format{PrometheusFormat, ParquetFormat}
+}
+
+// Validate validates the Format.
+func (f Format) Validate() error {
+	for _, valid := range ValidFormats() {
+		if f == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("format %d is invalid", f)
+}
+
+func (f Format) String() string {
+	switch f {
+	case PrometheusFormat:
+		return "prometheus"
+	case ParquetFormat:
+		return "parquet"
+	}
+	return "unknown"
+}
+
+// ParseFormat parses a Format from a string.
+func ParseFormat(str string) (Format, error) {
+	for _, valid := range ValidFormats() {
+		if str == valid.String() {
+			return valid, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid format '%s' valid formats are: %v",
+		str, ValidFormats())
+}
@@ -0,0 +1,398 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/golang/snappy"
+)
+
+// The subset of the Parquet format used to write flat tables of required
+// columns, each column chunk holding a single PLAIN encoded and snappy
+// compressed data page. See https://github.com/apache/parquet-format for
+// the Thrift definitions the field IDs and enum values below refer to.
+const (
+	parquetMagic   = "PAR1"
+	parquetVersion = 1
+	parquetCreator = "m3 export_data_files"
+
+	parquetTypeInt64     = 2
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetRepetitionRequired = 0
+
+	parquetConvertedTypeUTF8 = 0
+	parquetConvertedTypeJSON = 19
+
+	parquetLogicalTypeString    = 1
+	parquetLogicalTypeTimestamp = 8
+	parquetLogicalTypeJSON      = 12
+	parquetTimeUnitNanos        = 3
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecSnappy = 1
+
+	parquetPageTypeData = 0
+)
+
+var errParquetWriterClosed = errors.New("parquet writer is closed")
+
+type parquetColumn struct {
+	name          string
+	physicalType  int32
+	convertedType int32
+	logicalType   int16
+}
+
+// parquetColumns are the columns of the exported tables.
+var parquetColumns = []parquetColumn{
+	{
+		name:          "series_id",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeUTF8,
+		logicalType:   parquetLogicalTypeString,
+	},
+	{
+		name:          "tags",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeJSON,
+		logicalType:   parquetLogicalTypeJSON,
+	},
+	{
+		name:          "timestamp",
+		physicalType:  parquetTypeInt64,
+		convertedType: -1,
+		logicalType:   parquetLogicalTypeTimestamp,
+	},
+	{
+		name:          "value",
+		physicalType:  parquetTypeDouble,
+		convertedType: -1,
+	},
+}
+
+type parquetColumnChunk struct {
+	numValues             int64
+	uncompressedSize      int64
+	compressedSize        int64
+	dataPageOffset        int64
+	uncompressedPageBytes int
+}
+
+type parquetRowGroup struct {
+	numRows int64
+	columns []parquetColumnChunk
+}
+
+// parquetWriter writes rows of series ID, tags, timestamp and value to a
+// Parquet file, buffering up to rowGroupSize rows in memory.
+type parquetWriter struct {
+	w            io.Writer
+	offset       int64
+	rowGroupSize int
+	numRows      int
+	buffers      []bytes.Buffer
+	scratch      [8]byte
+	rowGroups    []parquetRowGroup
+	closed       bool
+}
+
+func newParquetWriter(w io.Writer, rowGroupSize int) (*parquetWriter, error) {
+	pw := &parquetWriter{
+		w:            w,
+		rowGroupSize: rowGroupSize,
+		buffers:      make([]bytes.Buffer, len(parquetColumns)),
+	}
+	if err := pw.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (w *parquetWriter) Write(id, tags []byte, timestamp int64, value float64) error {
+	if w.closed {
+		return errParquetWriterClosed
+	}
+	w.writeByteArray(&w.buffers[0], id)
+	w.writeByteArray(&w.buffers[1], tags)
+	binary.LittleEndian.PutUint64(w.scratch[:], uint64(timestamp))
+	w.buffers[2].Write(w.scratch[:])
+	binary.LittleEndian.PutUint64(w.scratch[:], math.Float64bits(value))
+	w.buffers[3].Write(w.scratch[:])
+
+	w.numRows++
+	if w.numRows < w.rowGroupSize {
+		return nil
+	}
+	return w.flushRowGroup()
+}
+
+func (w *parquetWriter) writeByteArray(buf *bytes.Buffer, value []byte) {
+	binary.LittleEndian.PutUint32(w.scratch[:4], uint32(len(value)))
+	buf.Write(w.scratch[:4])
+	buf.Write(value)
+}
+
+func (w *parquetWriter) flushRowGroup() error {
+	if w.numRows == 0 {
+		return nil
+	}
+
+	rowGroup := parquetRowGroup{
+		numRows: int64(w.numRows),
+		columns: make([]parquetColumnChunk, 0, len(parquetColumns)),
+	}
+	for i := range w.buffers {
+		data := w.buffers[i].Bytes()
+		compressed := snappy.Encode(nil, data)
+
+		header, err := encodeThrift(func(p *thriftWriter) {
+			p.fieldI32(1, parquetPageTypeData)
+			p.fieldI32(2, int32(len(data)))
+			p.fieldI32(3, int32(len(compressed)))
+			p.fieldStruct(5, func() {
+				p.fieldI32(1, int32(w.numRows))
+				p.fieldI32(2, parquetEncodingPlain)
+				p.fieldI32(3, parquetEncodingRLE)
+				p.fieldI32(4, parquetEncodingRLE)
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		chunk := parquetColumnChunk{
+			numValues:        int64(w.numRows),
+			uncompressedSize: int64(len(header) + len(data)),
+			compressedSize:   int64(len(header) + len(compressed)),
+			dataPageOffset:   w.offset,
+		}
+		if err := w.write(header); err != nil {
+			return err
+		}
+		if err := w.write(compressed); err != nil {
+			return err
+		}
+		rowGroup.columns = append(rowGroup.columns, chunk)
+		w.buffers[i].Reset()
+	}
+
+	w.rowGroups = append(w.rowGroups, rowGroup)
+	w.numRows = 0
+	return nil
+}
+
+// Close flushes the buffered rows and writes the file footer, it does not
+// close the underlying writer.
+func (w *parquetWriter) Close() error {
+	if w.closed {
+		return errParquetWriterClosed
+	}
+	if err := w.flushRowGroup(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	footer, err := encodeThrift(w.writeFileMetaData)
+	if err != nil {
+		return err
+	}
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint32(w.scratch[:4], uint32(len(footer)))
+	if err := w.write(w.scratch[:4]); err != nil {
+		return err
+	}
+	return w.write([]byte(parquetMagic))
+}
+
+func (w *parquetWriter) writeFileMetaData(p *thriftWriter) {
+	var numRows int64
+	for _, rg := range w.rowGroups {
+		numRows += rg.numRows
+	}
+
+	p.fieldI32(1, parquetVersion)
+	p.fieldList(2, thrift.STRUCT, len(parquetColumns)+1, func(i int) {
+		p.structValue(func() {
+			if i == 0 {
+				// The root of the schema.
+				p.fieldString(4, "schema")
+				p.fieldI32(5, int32(len(parquetColumns)))
+				return
+			}
+			col := parquetColumns[i-1]
+			p.fieldI32(1, col.physicalType)
+			p.fieldI32(3, parquetRepetitionRequired)
+			p.fieldString(4, col.name)
+			if col.convertedType >= 0 {
+				p.fieldI32(6, col.convertedType)
+			}
+			if col.logicalType == 0 {
+				return
+			}
+			p.fieldStruct(10, func() {
+				p.fieldStruct(col.logicalType, func() {
+					if col.logicalType != parquetLogicalTypeTimestamp {
+						return
+					}
+					p.fieldBool(1, true)
+					p.fieldStruct(2, func() {
+						p.fieldStruct(parquetTimeUnitNanos, func() {})
+					})
+				})
+			})
+		})
+	})
+	p.fieldI64(3, numRows)
+	p.fieldList(4, thrift.STRUCT, len(w.rowGroups), func(i int) {
+		rg := w.rowGroups[i]
+		p.structValue(func() {
+			var totalSize int64
+			p.fieldList(1, thrift.STRUCT, len(rg.columns), func(j int) {
+				chunk := rg.columns[j]
+				col := parquetColumns[j]
+				totalSize += chunk.uncompressedSize
+				p.structValue(func() {
+					p.fieldI64(2, chunk.dataPageOffset)
+					p.fieldStruct(3, func() {
+						p.fieldI32(1, col.physicalType)
+						p.fieldList(2, thrift.I32, 2, func(k int) {
+							if k == 0 {
+								p.i32Value(parquetEncodingPlain)
+							} else {
+								p.i32Value(parquetEncodingRLE)
+							}
+						})
+						p.fieldList(3, thrift.STRING, 1, func(int) {
+							p.stringValue(col.name)
+						})
+						p.fieldI32(4, parquetCodecSnappy)
+						p.fieldI64(5, chunk.numValues)
+						p.fieldI64(6, chunk.uncompressedSize)
+						p.fieldI64(7, chunk.compressedSize)
+						p.fieldI64(9, chunk.dataPageOffset)
+					})
+				})
+			})
+			p.fieldI64(2, totalSize)
+			p.fieldI64(3, rg.numRows)
+		})
+	})
+	p.fieldString(6, parquetCreator)
+}
+
+func (w *parquetWriter) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// thriftWriter writes Thrift structs with the compact protocol, keeping the
+// first error encountered.
+type thriftWriter struct {
+	p   *thrift.TCompactProtocol
+	err error
+}
+
+func encodeThrift(fn func(p *thriftWriter)) ([]byte, error) {
+	buf := thrift.NewTMemoryBuffer()
+	w := &thriftWriter{p: thrift.NewTCompactProtocol(buf)}
+	w.structValue(func() { fn(w) })
+	if w.err != nil {
+		return nil, w.err
+	}
+	if err := w.p.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *thriftWriter) setErr(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *thriftWriter) structValue(fn func()) {
+	w.setErr(w.p.WriteStructBegin(""))
+	fn()
+	w.setErr(w.p.WriteFieldStop())
+	w.setErr(w.p.WriteStructEnd())
+}
+
+func (w *thriftWriter) i32Value(v int32) {
+	w.setErr(w.p.WriteI32(v))
+}
+
+func (w *thriftWriter) stringValue(v string) {
+	w.setErr(w.p.WriteString(v))
+}
+
+func (w *thriftWriter) fieldI32(id int16, v int32) {
+	w.setErr(w.p.WriteFieldBegin("", thrift.I32, id))
+	w.i32Value(v)
+	w.setErr(w.p.WriteFieldEnd())
+}
+
+func (w *thriftWriter) fieldI64(id int16, v int64) {
+	w.setErr(w.p.WriteFieldBegin("", thrift.I64, id))
+	w.setErr(w.p.WriteI64(v))
+	w.setErr(w.p.WriteFieldEnd())
+}
+
+func (w *thriftWriter) fieldBool(id int16, v bool) {
+	w.setErr(w.p.WriteFieldBegin("", thrift.BOOL, id))
+	w.setErr(w.p.WriteBool(v))
+	w.setErr(w.p.WriteFieldEnd())
+}
+
+func (w *thriftWriter) fieldString(id int16, v string) {
+	w.setErr(w.p.WriteFieldBegin("", thrift.STRING, id))
+	w.stringValue(v)
+	w.setErr(w.p.WriteFieldEnd())
+}
+
+func (w *thriftWriter) fieldStruct(id int16, fn func()) {
+	w.setErr(w.p.WriteFieldBegin("", thrift.STRUCT, id))
+	w.structValue(fn)
+	w.setErr(w.p.WriteFieldEnd())
+}
+
+func (w *thriftWriter) fieldList(id int16, elemType thrift.TType, n int, fn func(i int)) {
+	w.setErr(w.p.WriteFieldBegin("", thrift.LIST, id))
+	w.setErr(w.p.WriteListBegin(elemType, n))
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+	w.setErr(w.p.WriteListEnd())
+	w.setErr(w.p.WriteFieldEnd())
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// blockWriter writes the series of a single namespace block.
+type blockWriter interface {
+	// WriteSeries writes the datapoints of a series.
+	WriteSeries(metadata doc.Metadata, datapoints []ts.Datapoint) error
+
+	// Close finishes writing the block.
+	Close() error
+}
+
+type prometheusBlockWriter struct {
+	writer     *tsdb.BlockWriter
+	numSamples int
+}
+
+func newPrometheusBlockWriter(dir string, blockSize time.Duration) (blockWriter, error) {
+	// The block writer rejects samples that are older than half its block
+	// size before the latest sample written, so use twice the namespace
+	// block size to accept samples regardless of the order series are
+	// written in.
+	writer, err := tsdb.NewBlockWriter(kitlog.NewNopLogger(), dir,
+		2*int64(blockSize/time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusBlockWriter{writer: writer}, nil
+}
+
+func (w *prometheusBlockWriter) WriteSeries(
+	metadata doc.Metadata,
+	datapoints []ts.Datapoint,
+) error {
+	lset := make(labels.Labels, 0, len(metadata.Fields))
+	for _, f := range metadata.Fields {
+		lset = append(lset, labels.Label{Name: string(f.Name), Value: string(f.Value)})
+	}
+	lset = labels.New(lset...)
+
+	var (
+		app    = w.writer.Appender(context.Background())
+		ref    uint64
+		prevTs = int64(math.MinInt64)
+		err    error
+	)
+	for _, dp := range datapoints {
+		// Prometheus samples have millisecond precision, keep the first
+		// datapoint of each millisecond.
+		t := int64(dp.TimestampNanos) / int64(time.Millisecond)
+		if t <= prevTs {
+			continue
+		}
+		ref, err = app.Append(ref, lset, t, dp.Value)
+		if err != nil {
+			return xerrors.FirstError(err, app.Rollback())
+		}
+		prevTs = t
+		w.numSamples++
+	}
+	return app.Commit()
+}
+
+func (w *prometheusBlockWriter) Close() error {
+	var multiErr xerrors.MultiError
+	if w.numSamples > 0 {
+		_, err := w.writer.Flush(context.Background())
+		multiErr = multiErr.Add(err)
+	}
+	multiErr = multiErr.Add(w.writer.Close())
+	return multiErr.FinalError()
+}
+
+type parquetBlockWriter struct {
+	file     *os.File
+	buffered *bufio.Writer
+	writer   *parquetWriter
+	tags     map[string]string
+}
+
+func newParquetBlockWriter(
+	dir string,
+	namespace ident.ID,
+	blockStart xtime.UnixNano,
+	rowGroupSize int,
+) (blockWriter, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.parquet", namespace.String(), int64(blockStart)))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	buffered := bufio.NewWriter(file)
+	writer, err := newParquetWriter(buffered, rowGroupSize)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &parquetBlockWriter{
+		file:     file,
+		buffered: buffered,
+		writer:   writer,
+		tags:     make(map[string]string),
+	}, nil
+}
+
+func (w *parquetBlockWriter) WriteSeries(
+	metadata doc.Metadata,
+	datapoints []ts.Datapoint,
+) error {
+	for k := range w.tags {
+		delete(w.tags, k)
+	}
+	for _, f := range metadata.Fields {
+		w.tags[string(f.Name)] = string(f.Value)
+	}
+	tags, err := json.Marshal(w.tags)
+	if err != nil {
+		return err
+	}
+
+	for _, dp := range datapoints {
+		err := w.writer.Write(metadata.ID, tags, int64(dp.TimestampNanos), dp.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *parquetBlockWriter) Close() error {
+	var multiErr xerrors.MultiError
+	multiErr = multiErr.Add(w.writer.Close())
+	multiErr = multiErr.Add(w.buffered.Flush())
+	multiErr = multiErr.Add(w.file.Close())
+	return multiErr.FinalError()
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/tools/export_data_files/exporter"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/getopt"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		optPathPrefix     = getopt.StringLong("path-prefix", 'p', "/var/lib/m3db", "Path prefix [e.g. /var/lib/m3db]")
+		optNamespace      = getopt.StringLong("namespace", 'n', "default", "Namespace [e.g. metrics]")
+		optEncodingScheme = getopt.StringLong("encoding-scheme", 'e', namespace.M3TSZEncodingScheme.String(), "Namespace encoding scheme [m3tsz|xor]")
+		optQuery          = getopt.StringLong("query", 'q', "", "Series to export (PromQL selector, optional) [e.g. {job=\"api\"}]")
+		optStart          = getopt.StringLong("start", 's', "", "Start of the time range to export (RFC3339, optional)")
+		optEnd            = getopt.StringLong("end", 'E', "", "End of the time range to export (RFC3339, defaults to now)")
+		optFormat         = getopt.StringLong("format", 'f', exporter.PrometheusFormat.String(), "Output format [prometheus|parquet]")
+		optOutputDir      = getopt.StringLong("output-dir", 'o', "", "Output directory")
+		optRowGroupSize   = getopt.IntLong("parquet-row-group-size", 'r', 0, "Rows per Parquet row group (optional)")
+	)
+	getopt.Parse()
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	log := rawLogger.Sugar()
+
+	if *optPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optOutputDir == "" {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	encodingScheme, err := namespace.ParseEncodingScheme(*optEncodingScheme)
+	if err != nil {
+		log.Fatalf("unable to parse encoding scheme: %v", err)
+	}
+	format, err := exporter.ParseFormat(*optFormat)
+	if err != nil {
+		log.Fatalf("unable to parse format: %v", err)
+	}
+
+	var matchers []*labels.Matcher
+	if *optQuery != "" {
+		matchers, err = parser.ParseMetricSelector(*optQuery)
+		if err != nil {
+			log.Fatalf("unable to parse query: %v", err)
+		}
+	}
+
+	start, end := xtime.UnixNano(0), xtime.Now()
+	if *optStart != "" {
+		t, err := time.Parse(time.RFC3339, *optStart)
+		if err != nil {
+			log.Fatalf("unable to parse start: %v", err)
+		}
+		start = xtime.ToUnixNano(t)
+	}
+	if *optEnd != "" {
+		t, err := time.Parse(time.RFC3339, *optEnd)
+		if err != nil {
+			log.Fatalf("unable to parse end: %v", err)
+		}
+		end = xtime.ToUnixNano(t)
+	}
+
+	if err := os.MkdirAll(*optOutputDir, 0755); err != nil {
+		log.Fatalf("unable to create output dir: %v", err)
+	}
+
+	result, err := exporter.Export(exporter.Options{
+		FilePathPrefix:      *optPathPrefix,
+		NamespaceID:         ident.StringID(*optNamespace),
+		EncodingScheme:      encodingScheme,
+		Start:               start,
+		End:                 end,
+		Matchers:            matchers,
+		Format:              format,
+		OutputDir:           *optOutputDir,
+		ParquetRowGroupSize: *optRowGroupSize,
+		Logger:              rawLogger,
+	})
+	if err != nil {
+		log.Fatalf("unable to export: %v", err)
+	}
+
+	log.Infof("exported %d datapoints in %d series blocks to %d %s blocks",
+		result.NumDatapoints, result.NumSeriesBlocks, result.NumBlocks, format)
+}
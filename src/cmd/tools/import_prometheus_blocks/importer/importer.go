@@ -0,0 +1,550 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package importer writes the series of Prometheus TSDB blocks directly to
+// M3DB data filesets and index volumes.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
+	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"go.uber.org/zap"
+)
+
+const blockMetaFileName = "meta.json"
+
+var (
+	errNoBlocks            = errors.New("no prometheus blocks to import")
+	errNamespaceIDNotSet   = errors.New("namespace id not set")
+	errBlockSizeNotSet     = errors.New("block size must be positive")
+	errIndexBlockSizeValue = errors.New("index block size must be a multiple of block size")
+	errShardSetNotSet      = errors.New("shard set not set")
+)
+
+// Options are the options for an import.
+type Options struct {
+	// FilePathPrefix is the M3DB data directory to write filesets to.
+	FilePathPrefix string
+	// NamespaceID is the ID of the namespace to import into.
+	NamespaceID ident.ID
+	// BlockSize is the block size of the namespace.
+	BlockSize time.Duration
+	// IndexBlockSize is the index block size of the namespace, if zero
+	// no index volumes are written.
+	IndexBlockSize time.Duration
+	// EncodingScheme is the encoding scheme of the namespace.
+	EncodingScheme namespace.EncodingScheme
+	// ShardSet is the set of shards to write filesets for, series that
+	// belong to other shards are skipped.
+	ShardSet sharding.ShardSet
+	// TagOptions determine how series IDs are generated from labels and
+	// must match the tag options of the coordinators querying the namespace.
+	TagOptions models.TagOptions
+	// Logger is the logger to use, if nil no progress is logged.
+	Logger *zap.Logger
+}
+
+// Validate validates the options.
+func (o Options) Validate() error {
+	if o.NamespaceID == nil {
+		return errNamespaceIDNotSet
+	}
+	if o.BlockSize <= 0 {
+		return errBlockSizeNotSet
+	}
+	if o.IndexBlockSize < 0 || o.IndexBlockSize%o.BlockSize != 0 {
+		return errIndexBlockSizeValue
+	}
+	if o.ShardSet == nil {
+		return errShardSetNotSet
+	}
+	if err := o.EncodingScheme.Validate(); err != nil {
+		return err
+	}
+	if o.TagOptions == nil {
+		return nil
+	}
+	return o.TagOptions.Validate()
+}
+
+// Result describes the outcome of an import.
+type Result struct {
+	// NumSeriesBlocks is the number of series blocks written.
+	NumSeriesBlocks int
+	// NumDatapoints is the number of datapoints written.
+	NumDatapoints int
+	// NumDataFileSets is the number of data filesets written.
+	NumDataFileSets int
+	// NumIndexVolumes is the number of index volumes written.
+	NumIndexVolumes int
+}
+
+// BlockDirs returns the Prometheus block directories at the given path,
+// which is either a block directory or a Prometheus data directory.
+func BlockDirs(path string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(path, blockMetaFileName)); err == nil {
+		return []string{path}, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(path, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, blockMetaFileName)); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// Import reads the series of the given Prometheus block directories and
+// writes them to data filesets aligned to the namespace block size, and to
+// index volumes aligned to the namespace index block size. Samples of
+// overlapping blocks are merged. Filesets are only written for blocks that
+// have no data filesets on disk yet, so that the filesystem bootstrapper of
+// the nodes owning the shards picks them up on their next bootstrap.
+func Import(opts Options, blockDirs []string) (Result, error) {
+	if err := opts.Validate(); err != nil {
+		return Result{}, err
+	}
+	if len(blockDirs) == 0 {
+		return Result{}, errNoBlocks
+	}
+	if opts.TagOptions == nil {
+		opts.TagOptions = models.NewTagOptions()
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	blocks := make([]*tsdb.Block, 0, len(blockDirs))
+	defer func() {
+		for _, b := range blocks {
+			b.Close()
+		}
+	}()
+	chunkPool := chunkenc.NewPool()
+	for _, dir := range blockDirs {
+		b, err := tsdb.OpenBlock(nil, dir, chunkPool)
+		if err != nil {
+			return Result{}, fmt.Errorf("unable to open prometheus block %s: %v", dir, err)
+		}
+		blocks = append(blocks, b)
+	}
+
+	im, err := newImporter(opts)
+	if err != nil {
+		return Result{}, err
+	}
+	return im.importBlocks(blocks)
+}
+
+type importer struct {
+	opts        Options
+	fsOpts      fs.Options
+	encoder     encoding.Encoder
+	dataWriters map[uint32]fs.DataFileSetWriter
+	indexShards map[uint32]struct{}
+	result      Result
+
+	indexBlockStart xtime.UnixNano
+	indexBuilder    segment.CloseableDocumentsBuilder
+}
+
+func newImporter(opts Options) (*importer, error) {
+	fsOpts := fs.NewOptions().SetFilePathPrefix(opts.FilePathPrefix)
+	shards := opts.ShardSet.AllIDs()
+	dataWriters := make(map[uint32]fs.DataFileSetWriter, len(shards))
+	indexShards := make(map[uint32]struct{}, len(shards))
+	for _, shard := range shards {
+		writer, err := fs.NewWriter(fsOpts)
+		if err != nil {
+			return nil, err
+		}
+		dataWriters[shard] = writer
+		indexShards[shard] = struct{}{}
+	}
+
+	encodingOpts := encoding.NewOptions()
+	var encoder encoding.Encoder
+	switch opts.EncodingScheme {
+	case namespace.XOREncodingScheme:
+		encoder = xor.NewEncoder(0, nil, encodingOpts)
+	default:
+		encoder = m3tsz.NewEncoder(0, nil, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+	}
+
+	return &importer{
+		opts:        opts,
+		fsOpts:      fsOpts,
+		encoder:     encoder,
+		dataWriters: dataWriters,
+		indexShards: indexShards,
+	}, nil
+}
+
+func (im *importer) importBlocks(blocks []*tsdb.Block) (Result, error) {
+	minTime, maxTime := blocks[0].Meta().MinTime, blocks[0].Meta().MaxTime
+	for _, b := range blocks[1:] {
+		if meta := b.Meta(); meta.MinTime < minTime {
+			minTime = meta.MinTime
+		}
+		if meta := b.Meta(); meta.MaxTime > maxTime {
+			maxTime = meta.MaxTime
+		}
+	}
+
+	var (
+		blockSize = im.opts.BlockSize
+		start     = fromMillis(minTime).Truncate(blockSize)
+		end       = fromMillis(maxTime)
+	)
+	var blockStarts []xtime.UnixNano
+	for blockStart := start; blockStart.Before(end); blockStart = blockStart.Add(blockSize) {
+		if len(overlappingBlocks(blocks, blockStart, blockSize)) > 0 {
+			blockStarts = append(blockStarts, blockStart)
+		}
+	}
+	if err := im.checkNoDataFileSets(blockStarts); err != nil {
+		return im.result, err
+	}
+
+	for _, blockStart := range blockStarts {
+		overlapping := overlappingBlocks(blocks, blockStart, blockSize)
+		if err := im.importBlock(blockStart, overlapping); err != nil {
+			return im.result, fmt.Errorf("unable to import block %s: %v",
+				blockStart.ToTime().UTC(), err)
+		}
+	}
+
+	if err := im.flushIndex(); err != nil {
+		return im.result, err
+	}
+	return im.result, nil
+}
+
+func (im *importer) importBlock(blockStart xtime.UnixNano, blocks []*tsdb.Block) error {
+	if err := im.maybeFlushIndex(blockStart); err != nil {
+		return err
+	}
+
+	var (
+		blockEnd = blockStart.Add(im.opts.BlockSize)
+		mint     = toMillis(blockStart)
+		// Prometheus query ranges are inclusive of their end.
+		maxt = toMillis(blockEnd) - 1
+	)
+	queriers := make([]storage.Querier, 0, len(blocks))
+	for _, b := range blocks {
+		q, err := tsdb.NewBlockQuerier(b, mint, maxt)
+		if err != nil {
+			return err
+		}
+		queriers = append(queriers, q)
+	}
+	querier := storage.NewMergeQuerier(queriers, nil, storage.ChainedSeriesMerge)
+	defer querier.Close()
+
+	if err := im.openDataWriters(blockStart); err != nil {
+		return err
+	}
+
+	matcher := labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".*")
+	seriesSet := querier.Select(false, nil, matcher)
+	for seriesSet.Next() {
+		series := seriesSet.At()
+		if err := im.writeSeries(blockStart, blockEnd, series); err != nil {
+			return err
+		}
+	}
+	if err := seriesSet.Err(); err != nil {
+		return err
+	}
+
+	for _, writer := range im.dataWriters {
+		if err := writer.Close(); err != nil {
+			return err
+		}
+	}
+	im.result.NumDataFileSets += len(im.dataWriters)
+	im.opts.Logger.Info("imported block",
+		zap.Time("blockStart", blockStart.ToTime()),
+		zap.Int("numBlocks", len(blocks)))
+	return nil
+}
+
+func overlappingBlocks(
+	blocks []*tsdb.Block,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+) []*tsdb.Block {
+	var (
+		mint        = toMillis(blockStart)
+		maxt        = toMillis(blockStart.Add(blockSize))
+		overlapping []*tsdb.Block
+	)
+	for _, b := range blocks {
+		if meta := b.Meta(); meta.MinTime < maxt && meta.MaxTime > mint {
+			overlapping = append(overlapping, b)
+		}
+	}
+	return overlapping
+}
+
+// checkNoDataFileSets ensures that none of the block starts to import
+// already have data filesets on disk since the volume written by the import
+// would otherwise shadow the data flushed by the nodes.
+func (im *importer) checkNoDataFileSets(blockStarts []xtime.UnixNano) error {
+	importing := make(map[xtime.UnixNano]struct{}, len(blockStarts))
+	for _, blockStart := range blockStarts {
+		importing[blockStart] = struct{}{}
+	}
+	for shard := range im.dataWriters {
+		files, err := fs.DataFiles(im.opts.FilePathPrefix, im.opts.NamespaceID, shard)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if _, ok := importing[f.ID.BlockStart]; ok {
+				return fmt.Errorf("data fileset already exists: shard=%d, blockStart=%s, volume=%d",
+					shard, f.ID.BlockStart.ToTime().UTC(), f.ID.VolumeIndex)
+			}
+		}
+	}
+	return nil
+}
+
+func (im *importer) openDataWriters(blockStart xtime.UnixNano) error {
+	for shard, writer := range im.dataWriters {
+		err := writer.Open(fs.DataWriterOpenOptions{
+			BlockSize: im.opts.BlockSize,
+			Identifier: fs.FileSetFileIdentifier{
+				Namespace:  im.opts.NamespaceID,
+				Shard:      shard,
+				BlockStart: blockStart,
+			},
+			FileSetType: persist.FileSetFlushType,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *importer) writeSeries(
+	blockStart, blockEnd xtime.UnixNano,
+	series storage.Series,
+) error {
+	tags := promLabelsToM3Tags(series.Labels(), im.opts.TagOptions)
+	id := tags.ID()
+	shard := im.opts.ShardSet.Lookup(ident.BytesID(id))
+	writer, ok := im.dataWriters[shard]
+	if !ok {
+		return nil
+	}
+
+	im.encoder.Reset(blockStart, 0, nil)
+	var (
+		iter          = series.Iterator()
+		numDatapoints int
+	)
+	for iter.Next() {
+		t, v := iter.At()
+		timestamp := fromMillis(t)
+		if timestamp.Before(blockStart) || !timestamp.Before(blockEnd) {
+			continue
+		}
+		dp := ts.Datapoint{TimestampNanos: timestamp, Value: v}
+		if err := im.encoder.Encode(dp, xtime.Millisecond, nil); err != nil {
+			return err
+		}
+		numDatapoints++
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if numDatapoints == 0 {
+		return nil
+	}
+
+	ctx := context.NewBackground()
+	defer ctx.BlockingClose()
+	stream, ok := im.encoder.Stream(ctx)
+	if !ok {
+		return nil
+	}
+	seg, err := stream.Segment()
+	if err != nil {
+		return err
+	}
+
+	metadata := doc.Metadata{ID: id, Fields: make([]doc.Field, 0, len(tags.Tags))}
+	for _, tag := range tags.Tags {
+		metadata.Fields = append(metadata.Fields, doc.Field{Name: tag.Name, Value: tag.Value})
+	}
+	data := []checked.Bytes{seg.Head, seg.Tail}
+	if err := writer.WriteAll(persist.NewMetadata(metadata), data, seg.CalculateChecksum()); err != nil {
+		return err
+	}
+	im.result.NumSeriesBlocks++
+	im.result.NumDatapoints += numDatapoints
+
+	if im.indexBuilder == nil {
+		return nil
+	}
+	if _, err := im.indexBuilder.Insert(metadata); err != nil && err != m3ninxindex.ErrDuplicateID {
+		return err
+	}
+	return nil
+}
+
+// maybeFlushIndex writes the index volume of the current index block if the
+// given block start belongs to a later index block, then starts building
+// the index block of the given block start.
+func (im *importer) maybeFlushIndex(blockStart xtime.UnixNano) error {
+	if im.opts.IndexBlockSize == 0 {
+		return nil
+	}
+	indexBlockStart := blockStart.Truncate(im.opts.IndexBlockSize)
+	if im.indexBuilder != nil && im.indexBlockStart.Equal(indexBlockStart) {
+		return nil
+	}
+	if err := im.flushIndex(); err != nil {
+		return err
+	}
+
+	b, err := builder.NewBuilderFromDocuments(builder.NewOptions())
+	if err != nil {
+		return err
+	}
+	im.indexBlockStart = indexBlockStart
+	im.indexBuilder = b
+	return nil
+}
+
+func (im *importer) flushIndex() error {
+	if im.indexBuilder == nil {
+		return nil
+	}
+	b := im.indexBuilder
+	im.indexBuilder = nil
+	defer b.Close()
+
+	volumeIndex, err := fs.NextIndexFileSetVolumeIndex(im.opts.FilePathPrefix,
+		im.opts.NamespaceID, im.indexBlockStart)
+	if err != nil {
+		return err
+	}
+	writer, err := fs.NewIndexWriter(im.fsOpts)
+	if err != nil {
+		return err
+	}
+	err = writer.Open(fs.IndexWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			FileSetContentType: persist.FileSetIndexContentType,
+			Namespace:          im.opts.NamespaceID,
+			BlockStart:         im.indexBlockStart,
+			VolumeIndex:        volumeIndex,
+		},
+		BlockSize:       im.opts.IndexBlockSize,
+		FileSetType:     persist.FileSetFlushType,
+		Shards:          im.indexShards,
+		IndexVolumeType: idxpersist.DefaultIndexVolumeType,
+	})
+	if err != nil {
+		return err
+	}
+
+	segmentWriter, err := idxpersist.NewMutableSegmentFileSetWriter(fst.WriterOptions{})
+	if err != nil {
+		return err
+	}
+	if err := segmentWriter.Reset(b); err != nil {
+		return err
+	}
+	if err := writer.WriteSegmentFileSet(segmentWriter); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	im.result.NumIndexVolumes++
+	return nil
+}
+
+// promLabelsToM3Tags converts Prometheus labels to tags the same way the
+// coordinator converts the labels of remote writes.
+func promLabelsToM3Tags(lset labels.Labels, opts models.TagOptions) models.Tags {
+	tags := models.NewTags(len(lset), opts)
+	for _, l := range lset {
+		switch {
+		case l.Name == labels.MetricName:
+			tags = tags.SetName([]byte(l.Value))
+		case l.Name == labels.BucketLabel:
+			tags = tags.SetBucket([]byte(l.Value))
+		default:
+			tags = tags.AddTag(models.Tag{Name: []byte(l.Name), Value: []byte(l.Value)})
+		}
+	}
+	return tags
+}
+
+func toMillis(t xtime.UnixNano) int64 {
+	return int64(t) / int64(time.Millisecond)
+}
+
+func fromMillis(t int64) xtime.UnixNano {
+	return xtime.UnixNano(t * int64(time.Millisecond))
+}
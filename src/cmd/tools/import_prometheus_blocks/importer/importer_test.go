@@ -0,0 +1,351 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package importer
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNumShards = 4
+	testBlockSize = time.Hour
+)
+
+type testSample struct {
+	t int64
+	v float64
+}
+
+func (s testSample) T() int64   { return s.t }
+func (s testSample) V() float64 { return s.v }
+
+type testSeries struct {
+	labels  labels.Labels
+	samples []tsdbutil.Sample
+}
+
+func newTestSeries(
+	name string,
+	start xtime.UnixNano,
+	end xtime.UnixNano,
+	step time.Duration,
+) testSeries {
+	var samples []tsdbutil.Sample
+	for t := start; t.Before(end); t = t.Add(step) {
+		samples = append(samples, testSample{t: toMillis(t), v: float64(len(samples))})
+	}
+	return testSeries{
+		labels:  labels.FromStrings(labels.MetricName, name, "job", "test"),
+		samples: samples,
+	}
+}
+
+func createTestBlock(t *testing.T, dir string, series []testSeries) string {
+	list := make([]storage.Series, 0, len(series))
+	for _, s := range series {
+		list = append(list, storage.NewListSeries(s.labels, s.samples))
+	}
+	blockDir, err := tsdb.CreateBlock(list, dir, int64(2*time.Hour/time.Millisecond),
+		kitlog.NewNopLogger())
+	require.NoError(t, err)
+	return blockDir
+}
+
+func newTestOptions(t *testing.T, filePathPrefix string) Options {
+	ids := make([]uint32, 0, testNumShards)
+	for i := 0; i < testNumShards; i++ {
+		ids = append(ids, uint32(i))
+	}
+	shardSet, err := sharding.NewShardSet(sharding.NewShards(ids, shard.Available),
+		sharding.DefaultHashFn(testNumShards))
+	require.NoError(t, err)
+	return Options{
+		FilePathPrefix: filePathPrefix,
+		NamespaceID:    ident.StringID("metrics"),
+		BlockSize:      testBlockSize,
+		IndexBlockSize: 2 * testBlockSize,
+		ShardSet:       shardSet,
+	}
+}
+
+type readSeries struct {
+	id         string
+	datapoints []testSample
+}
+
+func readDataFileSet(
+	t *testing.T,
+	opts Options,
+	shard uint32,
+	blockStart xtime.UnixNano,
+) []readSeries {
+	fsOpts := fs.NewOptions().SetFilePathPrefix(opts.FilePathPrefix)
+	reader, err := fs.NewReader(nil, fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  opts.NamespaceID,
+			Shard:      shard,
+			BlockStart: blockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+
+	encodingOpts := encoding.NewOptions()
+	var result []readSeries
+	for {
+		id, tags, data, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		tags.Close()
+
+		data.IncRef()
+		var iter encoding.ReaderIterator
+		r := xio.NewBytesReader64(data.Bytes())
+		if opts.EncodingScheme == namespace.XOREncodingScheme {
+			iter = xor.NewReaderIterator(r, encodingOpts)
+		} else {
+			iter = m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encodingOpts)
+		}
+		series := readSeries{id: id.String()}
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			series.datapoints = append(series.datapoints,
+				testSample{t: toMillis(dp.TimestampNanos), v: dp.Value})
+		}
+		require.NoError(t, iter.Err())
+		iter.Close()
+		data.DecRef()
+		data.Finalize()
+		result = append(result, series)
+	}
+	return result
+}
+
+func testImport(t *testing.T, encodingScheme namespace.EncodingScheme) {
+	dir, err := ioutil.TempDir("", "import_prometheus_blocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		promDir = filepath.Join(dir, "prometheus")
+		opts    = newTestOptions(t, filepath.Join(dir, "m3db"))
+		start   = xtime.UnixNano(0).Add(100 * 24 * time.Hour)
+		step    = 30 * time.Second
+	)
+	opts.EncodingScheme = encodingScheme
+
+	// Two adjacent two hour blocks, the first one with a series that does
+	// not exist in the second one.
+	first := []testSeries{
+		newTestSeries("foo", start, start.Add(2*time.Hour), step),
+		newTestSeries("bar", start, start.Add(90*time.Minute), step),
+	}
+	second := []testSeries{
+		newTestSeries("foo", start.Add(2*time.Hour), start.Add(4*time.Hour), step),
+	}
+	createTestBlock(t, promDir, first)
+	createTestBlock(t, promDir, second)
+
+	blockDirs, err := BlockDirs(promDir)
+	require.NoError(t, err)
+	require.Len(t, blockDirs, 2)
+
+	result, err := Import(opts, blockDirs)
+	require.NoError(t, err)
+	require.Equal(t, Result{
+		NumSeriesBlocks: 6,
+		NumDatapoints:   2*240 + 180,
+		NumDataFileSets: 4 * testNumShards,
+		NumIndexVolumes: 2,
+	}, result)
+
+	tagOpts := models.NewTagOptions()
+	fooID := string(promLabelsToM3Tags(first[0].labels, tagOpts).ID())
+	barID := string(promLabelsToM3Tags(first[1].labels, tagOpts).ID())
+	fooShard := opts.ShardSet.Lookup(ident.StringID(fooID))
+	barShard := opts.ShardSet.Lookup(ident.StringID(barID))
+
+	for i := 0; i < 4; i++ {
+		blockStart := start.Add(time.Duration(i) * testBlockSize)
+		for _, shard := range opts.ShardSet.AllIDs() {
+			var expected []readSeries
+			if shard == fooShard {
+				expected = append(expected, readSeries{
+					id:         fooID,
+					datapoints: testSamples(t, first, second, 0, blockStart),
+				})
+			}
+			if shard == barShard && i < 2 {
+				bar := readSeries{
+					id:         barID,
+					datapoints: testSamples(t, first, nil, 1, blockStart),
+				}
+				if barID < fooID || shard != fooShard {
+					expected = append([]readSeries{bar}, expected...)
+				} else {
+					expected = append(expected, bar)
+				}
+			}
+			require.Equal(t, expected, readDataFileSet(t, opts, shard, blockStart),
+				"block=%d, shard=%d", i, shard)
+		}
+	}
+
+	for i, numSeries := range []int{2, 1} {
+		indexBlockStart := start.Add(time.Duration(i) * opts.IndexBlockSize)
+		readResult, err := fs.ReadIndexSegments(fs.ReadIndexSegmentsOptions{
+			ReaderOptions: fs.IndexReaderOpenOptions{
+				Identifier: fs.FileSetFileIdentifier{
+					FileSetContentType: persist.FileSetIndexContentType,
+					Namespace:          opts.NamespaceID,
+					BlockStart:         indexBlockStart,
+				},
+				FileSetType: persist.FileSetFlushType,
+			},
+			FilesystemOptions: fs.NewOptions().SetFilePathPrefix(opts.FilePathPrefix),
+		})
+		require.NoError(t, err)
+		require.Len(t, readResult.Segments, 1)
+		seg := readResult.Segments[0]
+		require.Equal(t, int64(numSeries), seg.Size())
+		contains, err := seg.ContainsID([]byte(fooID))
+		require.NoError(t, err)
+		require.True(t, contains)
+		require.NoError(t, seg.Close())
+	}
+
+	// Importing the same blocks again is refused since it would shadow the
+	// filesets that were written by the first import.
+	_, err = Import(opts, blockDirs)
+	require.Error(t, err)
+}
+
+// testSamples returns the samples of the series at the given index within
+// the blocks that fall into the data block starting at blockStart.
+func testSamples(
+	t *testing.T,
+	first, second []testSeries,
+	idx int,
+	blockStart xtime.UnixNano,
+) []testSample {
+	var samples []testSample
+	for _, block := range [][]testSeries{first, second} {
+		if idx >= len(block) {
+			continue
+		}
+		for _, s := range block[idx].samples {
+			ts := fromMillis(s.T())
+			if !ts.Before(blockStart) && ts.Before(blockStart.Add(testBlockSize)) {
+				samples = append(samples, testSample{t: s.T(), v: s.V()})
+			}
+		}
+	}
+	return samples
+}
+
+func TestImportM3TSZ(t *testing.T) {
+	testImport(t, namespace.M3TSZEncodingScheme)
+}
+
+func TestImportXOR(t *testing.T) {
+	testImport(t, namespace.XOREncodingScheme)
+}
+
+func TestImportMergesOverlappingBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "import_prometheus_blocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		promDir = filepath.Join(dir, "prometheus")
+		opts    = newTestOptions(t, filepath.Join(dir, "m3db"))
+		start   = xtime.UnixNano(0).Add(100 * 24 * time.Hour)
+	)
+	opts.IndexBlockSize = 0
+
+	// Overlapping blocks holding the even and odd minutes of the same series.
+	even := newTestSeries("foo", start, start.Add(time.Hour), 2*time.Minute)
+	odd := newTestSeries("foo", start.Add(time.Minute), start.Add(time.Hour), 2*time.Minute)
+	createTestBlock(t, promDir, []testSeries{even})
+	createTestBlock(t, promDir, []testSeries{odd})
+
+	blockDirs, err := BlockDirs(promDir)
+	require.NoError(t, err)
+	result, err := Import(opts, blockDirs)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.NumSeriesBlocks)
+	require.Equal(t, 60, result.NumDatapoints)
+	require.Equal(t, 0, result.NumIndexVolumes)
+
+	id := string(promLabelsToM3Tags(even.labels, models.NewTagOptions()).ID())
+	series := readDataFileSet(t, opts, opts.ShardSet.Lookup(ident.StringID(id)), start)
+	require.Len(t, series, 1)
+	require.Len(t, series[0].datapoints, 60)
+	for i, dp := range series[0].datapoints {
+		require.Equal(t, toMillis(start.Add(time.Duration(i)*time.Minute)), dp.t)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := newTestOptions(t, "/tmp")
+	require.NoError(t, opts.Validate())
+
+	invalid := opts
+	invalid.IndexBlockSize = 90 * time.Minute
+	require.Error(t, invalid.Validate())
+
+	invalid = opts
+	invalid.BlockSize = 0
+	require.Error(t, invalid.Validate())
+
+	invalid = opts
+	invalid.ShardSet = nil
+	require.Error(t, invalid.Validate())
+}
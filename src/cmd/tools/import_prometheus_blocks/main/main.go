@@ -0,0 +1,151 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/tools/import_prometheus_blocks/importer"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/x/ident"
+
+	"go.uber.org/zap"
+)
+
+var (
+	optPathPrefix     = flag.String("path-prefix", "/var/lib/m3db", "Path prefix [e.g. /var/lib/m3db]")
+	optNamespace      = flag.String("namespace", "default", "Namespace [e.g. metrics]")
+	optBlockSize      = flag.Duration("block-size", 0, "Namespace block size [e.g. 2h]")
+	optIndexBlockSize = flag.Duration("index-block-size", 0, "Namespace index block size, defaults to the block size [e.g. 2h]")
+	optEncodingScheme = flag.String("encoding-scheme", namespace.M3TSZEncodingScheme.String(), "Namespace encoding scheme [m3tsz or xor]")
+	optNumShards      = flag.Int("num-shards", 0, "Number of shards of the placement [e.g. 64]")
+	optShards         = flag.String("shards", "", "Comma separated shards to write, defaults to all shards [e.g. 0,1,2]")
+	optIDScheme       = flag.String("id-scheme", models.TypeQuoted.String(), "Coordinator tag options ID scheme [quoted or prepend_meta]")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <prometheus block or data dir>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *optPathPrefix == "" ||
+		*optNamespace == "" ||
+		*optBlockSize <= 0 ||
+		*optNumShards <= 0 ||
+		flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	rawLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("unable to create logger: %+v", err)
+	}
+	logger := rawLogger.Sugar()
+
+	encodingScheme, err := namespace.ParseEncodingScheme(*optEncodingScheme)
+	if err != nil {
+		logger.Fatalf("unable to parse encoding scheme: %v", err)
+	}
+
+	var idScheme models.IDSchemeType
+	if err := idScheme.UnmarshalYAML(func(v interface{}) error {
+		*(v.(*string)) = *optIDScheme
+		return nil
+	}); err != nil {
+		logger.Fatalf("unable to parse id scheme: %v", err)
+	}
+
+	shardIDs, err := parseShards(*optShards, *optNumShards)
+	if err != nil {
+		logger.Fatalf("unable to parse shards: %v", err)
+	}
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards(shardIDs, shard.Available),
+		sharding.DefaultHashFn(*optNumShards),
+	)
+	if err != nil {
+		logger.Fatalf("unable to create shard set: %v", err)
+	}
+
+	var blockDirs []string
+	for _, path := range flag.Args() {
+		dirs, err := importer.BlockDirs(path)
+		if err != nil {
+			logger.Fatalf("unable to list prometheus blocks in %s: %v", path, err)
+		}
+		blockDirs = append(blockDirs, dirs...)
+	}
+	logger.Infof("importing %d prometheus blocks", len(blockDirs))
+
+	indexBlockSize := *optIndexBlockSize
+	if indexBlockSize == 0 {
+		indexBlockSize = *optBlockSize
+	}
+	result, err := importer.Import(importer.Options{
+		FilePathPrefix: *optPathPrefix,
+		NamespaceID:    ident.StringID(*optNamespace),
+		BlockSize:      *optBlockSize,
+		IndexBlockSize: indexBlockSize,
+		EncodingScheme: encodingScheme,
+		ShardSet:       shardSet,
+		TagOptions:     models.NewTagOptions().SetIDSchemeType(idScheme),
+		Logger:         rawLogger,
+	}, blockDirs)
+	if err != nil {
+		logger.Fatalf("unable to import prometheus blocks: %v", err)
+	}
+
+	logger.Infof("imported %d datapoints in %d series blocks to %d data filesets and %d index volumes",
+		result.NumDatapoints, result.NumSeriesBlocks, result.NumDataFileSets, result.NumIndexVolumes)
+}
+
+func parseShards(str string, numShards int) ([]uint32, error) {
+	if str == "" {
+		shards := make([]uint32, 0, numShards)
+		for i := 0; i < numShards; i++ {
+			shards = append(shards, uint32(i))
+		}
+		return shards, nil
+	}
+
+	var shards []uint32
+	for _, s := range strings.Split(str, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		if int(v) >= numShards {
+			return nil, fmt.Errorf("shard %d is not less than the number of shards %d", v, numShards)
+		}
+		shards = append(shards, uint32(v))
+	}
+	return shards, nil
+}
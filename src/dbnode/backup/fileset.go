@@ -0,0 +1,321 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	checkpointFileSuffix = "-checkpoint.db"
+	dataFileSuffix       = "-data.db"
+	copyTmpSuffix        = ".tmp"
+)
+
+// CopySnapshotMetadata copies the metadata of the latest snapshot of the host
+// to the backup directory of the host, returning nil if the host has not
+// taken a snapshot yet.
+func CopySnapshotMetadata(fsOpts fs.Options, hostDir string) (*SnapshotManifest, error) {
+	metadatas, _, err := fs.SortedSnapshotMetadataFiles(fsOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadatas) == 0 {
+		return nil, nil
+	}
+
+	latest := metadatas[len(metadatas)-1]
+	if err := LinkOrCopyFiles(fsOpts.FilePathPrefix(), hostDir,
+		latest.AbsoluteFilePaths(), fsOpts); err != nil {
+		return nil, err
+	}
+	return &SnapshotManifest{
+		Index: latest.ID.Index,
+		ID:    latest.ID.UUID.String(),
+		CommitLog: CommitLogPosition{
+			FilePath: latest.CommitlogIdentifier.FilePath,
+			Index:    latest.CommitlogIdentifier.Index,
+		},
+	}, nil
+}
+
+// CopyNamespace copies the latest complete data fileset of every block of the
+// shards of a namespace, the snapshot filesets of the snapshot the backup is
+// cut at and the index volumes of the namespace to the backup directory of the
+// host. The caller must ensure no filesets of the namespace are written or
+// cleaned up while the namespace is copied.
+func CopyNamespace(
+	fsOpts fs.Options,
+	offloader fs.FileSetOffloader,
+	hostDir string,
+	md namespace.Metadata,
+	shards []uint32,
+	snapshot *SnapshotManifest,
+) (NamespaceManifest, error) {
+	var (
+		prefix = fsOpts.FilePathPrefix()
+		opts   = md.Options()
+		result = NamespaceManifest{
+			ID:             md.ID().String(),
+			BlockSize:      opts.RetentionOptions().BlockSize(),
+			IndexBlockSize: opts.IndexOptions().BlockSize(),
+		}
+	)
+	for _, shard := range shards {
+		shardResult, err := copyShard(fsOpts, offloader, hostDir, md, shard, snapshot)
+		if err != nil {
+			return NamespaceManifest{}, fmt.Errorf(
+				"failed to back up shard %d: %v", shard, err)
+		}
+		result.Shards = append(result.Shards, shardResult)
+	}
+
+	if !opts.IndexOptions().Enabled() {
+		return result, nil
+	}
+
+	infoFiles := fs.ReadIndexInfoFiles(fs.ReadIndexInfoFilesOptions{
+		FilePathPrefix:   prefix,
+		Namespace:        md.ID(),
+		ReaderBufferSize: fsOpts.InfoReaderBufferSize(),
+	})
+	for _, f := range infoFiles {
+		if f.Err.Error() != nil || f.Corrupted {
+			continue
+		}
+		if err := LinkOrCopyFiles(prefix, hostDir, f.AbsoluteFilePaths, fsOpts); err != nil {
+			return NamespaceManifest{}, fmt.Errorf(
+				"failed to back up index volume: %v", err)
+		}
+		result.IndexVolumes = append(result.IndexVolumes, VolumeManifest{
+			BlockStart:  f.ID.BlockStart,
+			VolumeIndex: f.ID.VolumeIndex,
+			Shards:      f.Info.Shards,
+		})
+	}
+	return result, nil
+}
+
+func copyShard(
+	fsOpts fs.Options,
+	offloader fs.FileSetOffloader,
+	hostDir string,
+	md namespace.Metadata,
+	shard uint32,
+	snapshot *SnapshotManifest,
+) (ShardManifest, error) {
+	var (
+		prefix = fsOpts.FilePathPrefix()
+		result = ShardManifest{ID: shard}
+	)
+	dataFiles, err := fs.DataFiles(prefix, md.ID(), shard)
+	if err != nil {
+		return ShardManifest{}, err
+	}
+	for _, blockStart := range blockStarts(dataFiles) {
+		f, ok := dataFiles.LatestVolumeForBlock(blockStart)
+		if !ok {
+			continue
+		}
+		if !hasDataFile(f.AbsoluteFilePaths) {
+			// Volumes offloaded to tiered storage only keep their info,
+			// digest and checkpoint files on local disk.
+			f, err = restoreVolume(fsOpts, offloader, md, shard, f)
+			if err != nil {
+				return ShardManifest{}, err
+			}
+		}
+		if err := LinkOrCopyFiles(prefix, hostDir, f.AbsoluteFilePaths, fsOpts); err != nil {
+			return ShardManifest{}, err
+		}
+		result.DataVolumes = append(result.DataVolumes, VolumeManifest{
+			BlockStart:  f.ID.BlockStart,
+			VolumeIndex: f.ID.VolumeIndex,
+		})
+	}
+
+	if snapshot == nil {
+		return result, nil
+	}
+
+	// Only the snapshot filesets of the snapshot the backup is cut at are
+	// consistent with the commit log position of the backup.
+	result.CommitLog = &snapshot.CommitLog
+	snapshotFiles, err := fs.SnapshotFiles(prefix, md.ID(), shard)
+	if err != nil {
+		return ShardManifest{}, err
+	}
+	for _, f := range snapshotFiles {
+		if !f.HasCompleteCheckpointFile() {
+			continue
+		}
+		_, id, err := f.SnapshotTimeAndID()
+		if err != nil {
+			return ShardManifest{}, err
+		}
+		if id.String() != snapshot.ID {
+			continue
+		}
+		if err := LinkOrCopyFiles(prefix, hostDir, f.AbsoluteFilePaths, fsOpts); err != nil {
+			return ShardManifest{}, err
+		}
+		result.SnapshotVolumes = append(result.SnapshotVolumes, VolumeManifest{
+			BlockStart:  f.ID.BlockStart,
+			VolumeIndex: f.ID.VolumeIndex,
+		})
+	}
+	return result, nil
+}
+
+// restoreVolume restores the files of a volume that was offloaded to tiered
+// storage to local disk so that it can be backed up, it fails rather than
+// skipping the volume so that a backup never silently misses blocks.
+func restoreVolume(
+	fsOpts fs.Options,
+	offloader fs.FileSetOffloader,
+	md namespace.Metadata,
+	shard uint32,
+	f fs.FileSetFile,
+) (fs.FileSetFile, error) {
+	if offloader == nil {
+		return fs.FileSetFile{}, fmt.Errorf(
+			"volume %d of block %s is missing its data file",
+			f.ID.VolumeIndex, f.ID.BlockStart.ToTime())
+	}
+	if err := offloader.Restore(md.ID(), shard, f.ID.BlockStart); err != nil {
+		return fs.FileSetFile{}, err
+	}
+
+	dataFiles, err := fs.DataFiles(fsOpts.FilePathPrefix(), md.ID(), shard)
+	if err != nil {
+		return fs.FileSetFile{}, err
+	}
+	restored, ok := dataFiles.LatestVolumeForBlock(f.ID.BlockStart)
+	if !ok || restored.ID.VolumeIndex != f.ID.VolumeIndex ||
+		!hasDataFile(restored.AbsoluteFilePaths) {
+		return fs.FileSetFile{}, fmt.Errorf(
+			"volume %d of block %s was not restored from tiered storage",
+			f.ID.VolumeIndex, f.ID.BlockStart.ToTime())
+	}
+	return restored, nil
+}
+
+func blockStarts(files fs.FileSetFilesSlice) []xtime.UnixNano {
+	var (
+		seen   = make(map[xtime.UnixNano]struct{}, len(files))
+		result = make([]xtime.UnixNano, 0, len(files))
+	)
+	for _, f := range files {
+		if _, ok := seen[f.ID.BlockStart]; ok {
+			continue
+		}
+		seen[f.ID.BlockStart] = struct{}{}
+		result = append(result, f.ID.BlockStart)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// LinkOrCopyFiles hard links, or copies when they cannot be linked, files
+// from a source directory to the same relative path under a destination
+// directory. Checkpoint files are copied last so that an interrupted copy
+// leaves an incomplete fileset behind rather than a corrupt complete one.
+func LinkOrCopyFiles(srcDir string, dstDir string, paths []string, fsOpts fs.Options) error {
+	sorted := append([]string(nil), paths...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !isCheckpointFile(sorted[i]) && isCheckpointFile(sorted[j])
+	})
+	for _, src := range sorted {
+		rel, err := filepath.Rel(srcDir, src)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("file %s is not in directory %s", src, srcDir)
+		}
+		if err := linkOrCopyFile(src, filepath.Join(dstDir, rel), fsOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasDataFile(paths []string) bool {
+	for _, p := range paths {
+		if strings.HasSuffix(p, dataFileSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCheckpointFile(path string) bool {
+	return strings.HasSuffix(path, checkpointFileSuffix)
+}
+
+func linkOrCopyFile(src string, dst string, fsOpts fs.Options) error {
+	if err := os.MkdirAll(filepath.Dir(dst), fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	// Hard links are not possible across devices, fall back to a copy.
+	in, err := os.Open(src) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+
+	tmpPath := dst + copyTmpSuffix
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		fsOpts.NewFileMode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close() //nolint:errcheck
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close() //nolint:errcheck
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dst)
+}
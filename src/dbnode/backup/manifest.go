@@ -0,0 +1,205 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	xos "github.com/m3db/m3/src/x/os"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const (
+	manifestFileName  = "manifest.json"
+	manifestTmpSuffix = ".tmp"
+)
+
+// Manifest describes the point-in-time backup of the data of a single host.
+// The directory of a host backup mirrors the layout of the file path prefix
+// of the host so that the backed up filesets can be read with the same
+// readers as the live ones.
+type Manifest struct {
+	// ID is the ID of the backup request.
+	ID string `json:"id"`
+	// HostID is the ID of the host that was backed up.
+	HostID string `json:"hostID"`
+	// CreatedAt is the time the backup completed at.
+	CreatedAt time.Time `json:"createdAt"`
+	// Snapshot is the snapshot the backup was cut at, if any.
+	Snapshot *SnapshotManifest `json:"snapshot,omitempty"`
+	// Namespaces are the namespaces that were backed up.
+	Namespaces []NamespaceManifest `json:"namespaces"`
+}
+
+// Namespace returns the manifest of a namespace, if it was backed up.
+func (m Manifest) Namespace(id string) (NamespaceManifest, bool) {
+	for _, ns := range m.Namespaces {
+		if ns.ID == id {
+			return ns, true
+		}
+	}
+	return NamespaceManifest{}, false
+}
+
+// SnapshotManifest describes the snapshot a host backup was cut at.
+type SnapshotManifest struct {
+	// Index is the index of the snapshot metadata.
+	Index int64 `json:"index"`
+	// ID is the UUID of the snapshot.
+	ID string `json:"id"`
+	// CommitLog is the position in the commit log covered by the snapshot.
+	CommitLog CommitLogPosition `json:"commitLog"`
+}
+
+// CommitLogPosition is the commit log a snapshot was taken at. Every write
+// of the commit logs preceding it has been either flushed or snapshotted.
+type CommitLogPosition struct {
+	// FilePath is the path of the commit log on the host.
+	FilePath string `json:"filePath"`
+	// Index is the index of the commit log.
+	Index int64 `json:"index"`
+}
+
+// NamespaceManifest describes the backup of a namespace.
+type NamespaceManifest struct {
+	// ID is the ID of the namespace.
+	ID string `json:"id"`
+	// BlockSize is the data block size of the namespace.
+	BlockSize time.Duration `json:"blockSize"`
+	// IndexBlockSize is the index block size of the namespace.
+	IndexBlockSize time.Duration `json:"indexBlockSize"`
+	// Shards are the shards of the namespace that were backed up.
+	Shards []ShardManifest `json:"shards"`
+	// IndexVolumes are the index volumes of the namespace that were backed up.
+	IndexVolumes []VolumeManifest `json:"indexVolumes,omitempty"`
+}
+
+// Shard returns the manifest of a shard, if it was backed up.
+func (m NamespaceManifest) Shard(id uint32) (ShardManifest, bool) {
+	for _, shard := range m.Shards {
+		if shard.ID == id {
+			return shard, true
+		}
+	}
+	return ShardManifest{}, false
+}
+
+// ShardManifest describes the backup of a shard of a namespace.
+type ShardManifest struct {
+	// ID is the ID of the shard.
+	ID uint32 `json:"id"`
+	// DataVolumes are the latest complete data filesets of each block.
+	DataVolumes []VolumeManifest `json:"dataVolumes,omitempty"`
+	// SnapshotVolumes are the snapshot filesets of the snapshot the backup
+	// was cut at.
+	SnapshotVolumes []VolumeManifest `json:"snapshotVolumes,omitempty"`
+	// CommitLog is the position in the commit log the shard was cut at.
+	CommitLog *CommitLogPosition `json:"commitLog,omitempty"`
+}
+
+// VolumeManifest describes a backed up fileset volume.
+type VolumeManifest struct {
+	// BlockStart is the block start of the fileset.
+	BlockStart xtime.UnixNano `json:"blockStart"`
+	// VolumeIndex is the volume index of the fileset.
+	VolumeIndex int `json:"volumeIndex"`
+	// Shards are the shards covered by the fileset, set for index volumes.
+	Shards []uint32 `json:"shards,omitempty"`
+}
+
+// HostDirPath returns the directory of the backup of a host.
+func HostDirPath(root string, id string, hostID string) string {
+	return filepath.Join(root, id, hostID)
+}
+
+// ManifestFilePath returns the path of the manifest of the backup of a host.
+func ManifestFilePath(root string, id string, hostID string) string {
+	return filepath.Join(HostDirPath(root, id, hostID), manifestFileName)
+}
+
+// ManifestExists returns whether the backup of a host has completed.
+func ManifestExists(root string, id string, hostID string) (bool, error) {
+	_, err := os.Stat(ManifestFilePath(root, id, hostID))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// WriteManifest atomically writes the manifest of the backup of a host, which
+// marks the backup of the host as complete.
+func WriteManifest(root string, m Manifest, fsOpts fs.Options) error {
+	if err := os.MkdirAll(HostDirPath(root, m.ID, m.HostID), fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	filePath := ManifestFilePath(root, m.ID, m.HostID)
+	tmpPath := filePath + manifestTmpSuffix
+	if err := xos.WriteFileSync(tmpPath, data, fsOpts.NewFileMode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// ReadManifest reads a manifest from a file.
+func ReadManifest(filePath string) (Manifest, error) {
+	var m Manifest
+	data, err := ioutil.ReadFile(filePath) //nolint:gosec
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// ReadManifests reads the manifests of every host that completed a backup,
+// given the directory of the backup, sorted by host ID.
+func ReadManifests(backupDir string) ([]Manifest, error) {
+	paths, err := filepath.Glob(filepath.Join(backupDir, "*", manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]Manifest, 0, len(paths))
+	for _, p := range paths {
+		m, err := ReadManifest(p)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].HostID < manifests[j].HostID
+	})
+	return manifests, nil
+}
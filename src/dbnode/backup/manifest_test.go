@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fsOpts := fs.NewOptions()
+	exists, err := ManifestExists(dir, "b1", "host1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	manifests := []Manifest{
+		{
+			ID:        "b1",
+			HostID:    "host0",
+			CreatedAt: time.Unix(1, 0).UTC(),
+			Namespaces: []NamespaceManifest{
+				{
+					ID:        "metrics",
+					BlockSize: 2 * time.Hour,
+					Shards: []ShardManifest{
+						{ID: 1, DataVolumes: []VolumeManifest{{BlockStart: 7200, VolumeIndex: 1}}},
+					},
+				},
+			},
+		},
+		{
+			ID:        "b1",
+			HostID:    "host1",
+			CreatedAt: time.Unix(2, 0).UTC(),
+		},
+	}
+	for i := len(manifests) - 1; i >= 0; i-- {
+		require.NoError(t, WriteManifest(dir, manifests[i], fsOpts))
+	}
+
+	exists, err = ManifestExists(dir, "b1", "host1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	read, err := ReadManifests(filepath.Join(dir, "b1"))
+	require.NoError(t, err)
+	require.Equal(t, manifests, read)
+
+	ns, ok := read[0].Namespace("metrics")
+	require.True(t, ok)
+	_, ok = ns.Shard(1)
+	require.True(t, ok)
+	_, ok = ns.Shard(2)
+	require.False(t, ok)
+	_, ok = read[1].Namespace("metrics")
+	require.False(t, ok)
+}
+
+func TestLinkOrCopyFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		src    = filepath.Join(dir, "src")
+		dst    = filepath.Join(dir, "dst")
+		fsOpts = fs.NewOptions()
+		paths  = []string{
+			filepath.Join(src, "data", "ns", "0", "fileset-0-0-checkpoint.db"),
+			filepath.Join(src, "data", "ns", "0", "fileset-0-0-data.db"),
+		}
+	)
+	for _, p := range paths {
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, ioutil.WriteFile(p, []byte(filepath.Base(p)), 0644))
+	}
+
+	// Copying again replaces files copied before.
+	require.NoError(t, LinkOrCopyFiles(src, dst, paths, fsOpts))
+	require.NoError(t, LinkOrCopyFiles(src, dst, paths, fsOpts))
+	for _, p := range paths {
+		data, err := ioutil.ReadFile(filepath.Join(dst, "data", "ns", "0", filepath.Base(p)))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Base(p), string(data))
+	}
+
+	require.Error(t, LinkOrCopyFiles(dst, src, paths, fsOpts))
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package backup implements point-in-time backups of the filesets of a node
+// and the coordination of cluster-wide backups through the KV store.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	backuppb "github.com/m3db/m3/src/dbnode/generated/proto/backup"
+)
+
+const (
+	// RequestKey is the KV key under which the pending cluster-wide backup
+	// request is stored.
+	RequestKey = "m3db.node.backup"
+
+	statusKeyPrefix = "m3db.node.backup-status"
+)
+
+var (
+	errRequestIDEmpty     = errors.New("backup request id must be set")
+	errRequestIDMalformed = errors.New("backup request id must be a valid directory name")
+)
+
+// Request is a request for every node of the cluster to back up its data.
+type Request struct {
+	// ID identifies the backup, it is used as the directory name of the
+	// backup under the backup directory of each node.
+	ID string
+	// Namespaces are the namespaces to back up, all namespaces are backed
+	// up if empty.
+	Namespaces []string
+	// RequestedAt is the time the backup was requested at.
+	RequestedAt time.Time
+}
+
+// Validate validates the request.
+func (r Request) Validate() error {
+	if r.ID == "" {
+		return errRequestIDEmpty
+	}
+	if r.ID == "." || r.ID == ".." || strings.ContainsAny(r.ID, `/\`) {
+		return errRequestIDMalformed
+	}
+	return nil
+}
+
+// IncludesNamespace returns whether the request includes a namespace.
+func (r Request) IncludesNamespace(id string) bool {
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range r.Namespaces {
+		if ns == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ToProto converts the request to its protobuf representation.
+func (r Request) ToProto() *backuppb.Request {
+	return &backuppb.Request{
+		Id:               r.ID,
+		Namespaces:       r.Namespaces,
+		RequestedAtNanos: r.RequestedAt.UnixNano(),
+	}
+}
+
+// NewRequestFromProto converts a protobuf request to a request.
+func NewRequestFromProto(pb *backuppb.Request) Request {
+	return Request{
+		ID:          pb.Id,
+		Namespaces:  pb.Namespaces,
+		RequestedAt: time.Unix(0, pb.RequestedAtNanos),
+	}
+}
+
+// StatusKey returns the KV key under which a host reports that it has
+// completed a backup.
+func StatusKey(id string, hostID string) string {
+	return fmt.Sprintf("%s/%s/%s", statusKeyPrefix, id, hostID)
+}
+
+// SetRequest sets the pending cluster-wide backup request.
+func SetRequest(store kv.Store, req Request) (int, error) {
+	if err := req.Validate(); err != nil {
+		return 0, err
+	}
+	return store.Set(RequestKey, req.ToProto())
+}
+
+// PendingRequest returns the pending cluster-wide backup request, if any.
+func PendingRequest(store kv.Store) (Request, bool, error) {
+	value, err := store.Get(RequestKey)
+	if err == kv.ErrNotFound {
+		return Request{}, false, nil
+	}
+	if err != nil {
+		return Request{}, false, err
+	}
+
+	var pb backuppb.Request
+	if err := value.Unmarshal(&pb); err != nil {
+		return Request{}, false, err
+	}
+	req := NewRequestFromProto(&pb)
+	if err := req.Validate(); err != nil {
+		return Request{}, false, err
+	}
+	return req, true, nil
+}
+
+// Completed returns whether a host has reported that it completed a backup.
+func Completed(store kv.Store, id string, hostID string) (bool, error) {
+	value, err := store.Get(StatusKey(id, hostID))
+	if err == kv.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var completed commonpb.BoolProto
+	if err := value.Unmarshal(&completed); err != nil {
+		return false, err
+	}
+	return completed.Value, nil
+}
+
+// Coordinator coordinates the backups of a host with the rest of the cluster.
+type Coordinator interface {
+	// PendingRequest returns the pending cluster-wide backup request, if any.
+	PendingRequest() (Request, bool, error)
+
+	// ReportCompleted reports that the host has completed a backup.
+	ReportCompleted(id string) error
+
+	// HostID returns the ID of the host.
+	HostID() string
+}
+
+type kvCoordinator struct {
+	store  kv.Store
+	hostID string
+}
+
+// NewKVCoordinator returns a coordinator that reads backup requests from and
+// reports the status of the host to a KV store.
+func NewKVCoordinator(store kv.Store, hostID string) Coordinator {
+	return &kvCoordinator{
+		store:  store,
+		hostID: hostID,
+	}
+}
+
+func (c *kvCoordinator) PendingRequest() (Request, bool, error) {
+	return PendingRequest(c.store)
+}
+
+func (c *kvCoordinator) ReportCompleted(id string) error {
+	_, err := c.store.Set(StatusKey(id, c.hostID), &commonpb.BoolProto{Value: true})
+	return err
+}
+
+func (c *kvCoordinator) HostID() string {
+	return c.hostID
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestValidate(t *testing.T) {
+	require.NoError(t, Request{ID: "2021-01-01"}.Validate())
+	require.Equal(t, errRequestIDEmpty, Request{}.Validate())
+	for _, id := range []string{".", "..", "a/b", `a\b`} {
+		require.Equal(t, errRequestIDMalformed, Request{ID: id}.Validate(), id)
+	}
+}
+
+func TestRequestIncludesNamespace(t *testing.T) {
+	require.True(t, Request{ID: "b1"}.IncludesNamespace("metrics"))
+
+	req := Request{ID: "b1", Namespaces: []string{"metrics"}}
+	require.True(t, req.IncludesNamespace("metrics"))
+	require.False(t, req.IncludesNamespace("other"))
+}
+
+func TestKVCoordinator(t *testing.T) {
+	store := mem.NewStore()
+	coordinator := NewKVCoordinator(store, "host0")
+	require.Equal(t, "host0", coordinator.HostID())
+
+	_, ok, err := coordinator.PendingRequest()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = SetRequest(store, Request{ID: "a/b"})
+	require.Error(t, err)
+
+	req := Request{
+		ID:          "b1",
+		Namespaces:  []string{"metrics"},
+		RequestedAt: time.Unix(0, 1000),
+	}
+	_, err = SetRequest(store, req)
+	require.NoError(t, err)
+
+	pending, ok, err := coordinator.PendingRequest()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, req, pending)
+
+	completed, err := Completed(store, "b1", "host0")
+	require.NoError(t, err)
+	require.False(t, completed)
+
+	require.NoError(t, coordinator.ReportCompleted("b1"))
+	completed, err = Completed(store, "b1", "host0")
+	require.NoError(t, err)
+	require.True(t, completed)
+
+	completed, err = Completed(store, "b1", "host1")
+	require.NoError(t, err)
+	require.False(t, completed)
+}
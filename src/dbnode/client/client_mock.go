@@ -172,6 +172,21 @@ func (mr *MockSessionMockRecorder) Aggregate(ctx, namespace, q, opts interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockSession)(nil).Aggregate), ctx, namespace, q, opts)
 }
 
+// Cardinality mocks base method.
+func (m *MockSession) Cardinality(namespace ident.ID, opts index.CardinalityQueryOptions) (index.CardinalityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockSessionMockRecorder) Cardinality(namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockSession)(nil).Cardinality), namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockSession) Close() error {
 	m.ctrl.T.Helper()
@@ -186,6 +201,36 @@ func (mr *MockSessionMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSession)(nil).Close))
 }
 
+// Count mocks base method.
+func (m *MockSession) Count(namespace ident.ID, q index.Query, opts index.CountQueryOptions) (index.CountResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", namespace, q, opts)
+	ret0, _ := ret[0].(index.CountResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockSessionMockRecorder) Count(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockSession)(nil).Count), namespace, q, opts)
+}
+
+// DeleteTagged mocks base method.
+func (m *MockSession) DeleteTagged(namespace ident.ID, q index.Query, start, end time0.UnixNano) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", namespace, q, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockSessionMockRecorder) DeleteTagged(namespace, q, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockSession)(nil).DeleteTagged), namespace, q, start, end)
+}
+
 // Fetch mocks base method.
 func (m *MockSession) Fetch(namespace, id ident.ID, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterator, error) {
 	m.ctrl.T.Helper()
@@ -201,6 +246,21 @@ func (mr *MockSessionMockRecorder) Fetch(namespace, id, startInclusive, endExclu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockSession)(nil).Fetch), namespace, id, startInclusive, endExclusive)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockSession) FetchExemplars(namespace ident.ID, ids []ident.ID, start, end time0.UnixNano) ([]SeriesExemplars, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", namespace, ids, start, end)
+	ret0, _ := ret[0].([]SeriesExemplars)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockSessionMockRecorder) FetchExemplars(namespace, ids, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockSession)(nil).FetchExemplars), namespace, ids, start, end)
+}
+
 // FetchIDs mocks base method.
 func (m *MockSession) FetchIDs(namespace ident.ID, ids ident.Iterator, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterators, error) {
 	m.ctrl.T.Helper()
@@ -322,6 +382,20 @@ func (mr *MockSessionMockRecorder) WriteClusterAvailability() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClusterAvailability", reflect.TypeOf((*MockSession)(nil).WriteClusterAvailability))
 }
 
+// WriteExemplars mocks base method.
+func (m *MockSession) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockSessionMockRecorder) WriteExemplars(namespace, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockSession)(nil).WriteExemplars), namespace, series)
+}
+
 // WriteTagged mocks base method.
 func (m *MockSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -336,6 +410,20 @@ func (mr *MockSessionMockRecorder) WriteTagged(namespace, id, tags, t, value, un
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTagged", reflect.TypeOf((*MockSession)(nil).WriteTagged), namespace, id, tags, t, value, unit, annotation)
 }
 
+// WriteTaggedWithContext mocks base method.
+func (m *MockSession) WriteTaggedWithContext(ctx context.Context, namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteTaggedWithContext", ctx, namespace, id, tags, t, value, unit, annotation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteTaggedWithContext indicates an expected call of WriteTaggedWithContext.
+func (mr *MockSessionMockRecorder) WriteTaggedWithContext(ctx, namespace, id, tags, t, value, unit, annotation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTaggedWithContext", reflect.TypeOf((*MockSession)(nil).WriteTaggedWithContext), ctx, namespace, id, tags, t, value, unit, annotation)
+}
+
 // MockAggregatedTagsIterator is a mock of AggregatedTagsIterator interface.
 type MockAggregatedTagsIterator struct {
 	ctrl     *gomock.Controller
@@ -820,6 +908,21 @@ func (mr *MockAdminSessionMockRecorder) BorrowConnections(shardID, fn, opts inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowConnections", reflect.TypeOf((*MockAdminSession)(nil).BorrowConnections), shardID, fn, opts)
 }
 
+// Cardinality mocks base method.
+func (m *MockAdminSession) Cardinality(namespace ident.ID, opts index.CardinalityQueryOptions) (index.CardinalityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockAdminSessionMockRecorder) Cardinality(namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockAdminSession)(nil).Cardinality), namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockAdminSession) Close() error {
 	m.ctrl.T.Helper()
@@ -834,6 +937,21 @@ func (mr *MockAdminSessionMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAdminSession)(nil).Close))
 }
 
+// Count mocks base method.
+func (m *MockAdminSession) Count(namespace ident.ID, q index.Query, opts index.CountQueryOptions) (index.CountResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", namespace, q, opts)
+	ret0, _ := ret[0].(index.CountResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockAdminSessionMockRecorder) Count(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockAdminSession)(nil).Count), namespace, q, opts)
+}
+
 // DedicatedConnection mocks base method.
 func (m *MockAdminSession) DedicatedConnection(shardID uint32, opts DedicatedConnectionOptions) (rpc.TChanNode, Channel, error) {
 	m.ctrl.T.Helper()
@@ -850,6 +968,21 @@ func (mr *MockAdminSessionMockRecorder) DedicatedConnection(shardID, opts interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DedicatedConnection", reflect.TypeOf((*MockAdminSession)(nil).DedicatedConnection), shardID, opts)
 }
 
+// DeleteTagged mocks base method.
+func (m *MockAdminSession) DeleteTagged(namespace ident.ID, q index.Query, start, end time0.UnixNano) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", namespace, q, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockAdminSessionMockRecorder) DeleteTagged(namespace, q, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockAdminSession)(nil).DeleteTagged), namespace, q, start, end)
+}
+
 // Fetch mocks base method.
 func (m *MockAdminSession) Fetch(namespace, id ident.ID, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterator, error) {
 	m.ctrl.T.Helper()
@@ -925,6 +1058,21 @@ func (mr *MockAdminSessionMockRecorder) FetchBootstrapBlocksMetadataFromPeers(na
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBootstrapBlocksMetadataFromPeers", reflect.TypeOf((*MockAdminSession)(nil).FetchBootstrapBlocksMetadataFromPeers), namespace, shard, start, end, result)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockAdminSession) FetchExemplars(namespace ident.ID, ids []ident.ID, start, end time0.UnixNano) ([]SeriesExemplars, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", namespace, ids, start, end)
+	ret0, _ := ret[0].([]SeriesExemplars)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockAdminSessionMockRecorder) FetchExemplars(namespace, ids, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockAdminSession)(nil).FetchExemplars), namespace, ids, start, end)
+}
+
 // FetchIDs mocks base method.
 func (m *MockAdminSession) FetchIDs(namespace ident.ID, ids ident.Iterator, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterators, error) {
 	m.ctrl.T.Helper()
@@ -1104,6 +1252,20 @@ func (mr *MockAdminSessionMockRecorder) WriteClusterAvailability() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClusterAvailability", reflect.TypeOf((*MockAdminSession)(nil).WriteClusterAvailability))
 }
 
+// WriteExemplars mocks base method.
+func (m *MockAdminSession) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockAdminSessionMockRecorder) WriteExemplars(namespace, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockAdminSession)(nil).WriteExemplars), namespace, series)
+}
+
 // WriteTagged mocks base method.
 func (m *MockAdminSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -1118,6 +1280,20 @@ func (mr *MockAdminSessionMockRecorder) WriteTagged(namespace, id, tags, t, valu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTagged", reflect.TypeOf((*MockAdminSession)(nil).WriteTagged), namespace, id, tags, t, value, unit, annotation)
 }
 
+// WriteTaggedWithContext mocks base method.
+func (m *MockAdminSession) WriteTaggedWithContext(ctx context.Context, namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteTaggedWithContext", ctx, namespace, id, tags, t, value, unit, annotation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteTaggedWithContext indicates an expected call of WriteTaggedWithContext.
+func (mr *MockAdminSessionMockRecorder) WriteTaggedWithContext(ctx, namespace, id, tags, t, value, unit, annotation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTaggedWithContext", reflect.TypeOf((*MockAdminSession)(nil).WriteTaggedWithContext), ctx, namespace, id, tags, t, value, unit, annotation)
+}
+
 // MockOptions is a mock of Options interface.
 type MockOptions struct {
 	ctrl     *gomock.Controller
@@ -4746,6 +4922,21 @@ func (mr *MockclientSessionMockRecorder) BorrowConnections(shardID, fn, opts int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BorrowConnections", reflect.TypeOf((*MockclientSession)(nil).BorrowConnections), shardID, fn, opts)
 }
 
+// Cardinality mocks base method.
+func (m *MockclientSession) Cardinality(namespace ident.ID, opts index.CardinalityQueryOptions) (index.CardinalityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", namespace, opts)
+	ret0, _ := ret[0].(index.CardinalityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockclientSessionMockRecorder) Cardinality(namespace, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockclientSession)(nil).Cardinality), namespace, opts)
+}
+
 // Close mocks base method.
 func (m *MockclientSession) Close() error {
 	m.ctrl.T.Helper()
@@ -4760,6 +4951,21 @@ func (mr *MockclientSessionMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockclientSession)(nil).Close))
 }
 
+// Count mocks base method.
+func (m *MockclientSession) Count(namespace ident.ID, q index.Query, opts index.CountQueryOptions) (index.CountResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", namespace, q, opts)
+	ret0, _ := ret[0].(index.CountResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockclientSessionMockRecorder) Count(namespace, q, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockclientSession)(nil).Count), namespace, q, opts)
+}
+
 // DedicatedConnection mocks base method.
 func (m *MockclientSession) DedicatedConnection(shardID uint32, opts DedicatedConnectionOptions) (rpc.TChanNode, Channel, error) {
 	m.ctrl.T.Helper()
@@ -4776,6 +4982,21 @@ func (mr *MockclientSessionMockRecorder) DedicatedConnection(shardID, opts inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DedicatedConnection", reflect.TypeOf((*MockclientSession)(nil).DedicatedConnection), shardID, opts)
 }
 
+// DeleteTagged mocks base method.
+func (m *MockclientSession) DeleteTagged(namespace ident.ID, q index.Query, start, end time0.UnixNano) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", namespace, q, start, end)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockclientSessionMockRecorder) DeleteTagged(namespace, q, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockclientSession)(nil).DeleteTagged), namespace, q, start, end)
+}
+
 // Fetch mocks base method.
 func (m *MockclientSession) Fetch(namespace, id ident.ID, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterator, error) {
 	m.ctrl.T.Helper()
@@ -4851,6 +5072,21 @@ func (mr *MockclientSessionMockRecorder) FetchBootstrapBlocksMetadataFromPeers(n
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBootstrapBlocksMetadataFromPeers", reflect.TypeOf((*MockclientSession)(nil).FetchBootstrapBlocksMetadataFromPeers), namespace, shard, start, end, result)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockclientSession) FetchExemplars(namespace ident.ID, ids []ident.ID, start, end time0.UnixNano) ([]SeriesExemplars, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", namespace, ids, start, end)
+	ret0, _ := ret[0].([]SeriesExemplars)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockclientSessionMockRecorder) FetchExemplars(namespace, ids, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockclientSession)(nil).FetchExemplars), namespace, ids, start, end)
+}
+
 // FetchIDs mocks base method.
 func (m *MockclientSession) FetchIDs(namespace ident.ID, ids ident.Iterator, startInclusive, endExclusive time0.UnixNano) (encoding.SeriesIterators, error) {
 	m.ctrl.T.Helper()
@@ -5044,6 +5280,20 @@ func (mr *MockclientSessionMockRecorder) WriteClusterAvailability() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteClusterAvailability", reflect.TypeOf((*MockclientSession)(nil).WriteClusterAvailability))
 }
 
+// WriteExemplars mocks base method.
+func (m *MockclientSession) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", namespace, series)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockclientSessionMockRecorder) WriteExemplars(namespace, series interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockclientSession)(nil).WriteExemplars), namespace, series)
+}
+
 // WriteTagged mocks base method.
 func (m *MockclientSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
 	m.ctrl.T.Helper()
@@ -5058,6 +5308,20 @@ func (mr *MockclientSessionMockRecorder) WriteTagged(namespace, id, tags, t, val
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTagged", reflect.TypeOf((*MockclientSession)(nil).WriteTagged), namespace, id, tags, t, value, unit, annotation)
 }
 
+// WriteTaggedWithContext mocks base method.
+func (m *MockclientSession) WriteTaggedWithContext(ctx context.Context, namespace, id ident.ID, tags ident.TagIterator, t time0.UnixNano, value float64, unit time0.Unit, annotation []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteTaggedWithContext", ctx, namespace, id, tags, t, value, unit, annotation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteTaggedWithContext indicates an expected call of WriteTaggedWithContext.
+func (mr *MockclientSessionMockRecorder) WriteTaggedWithContext(ctx, namespace, id, tags, t, value, unit, annotation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTaggedWithContext", reflect.TypeOf((*MockclientSession)(nil).WriteTaggedWithContext), ctx, namespace, id, tags, t, value, unit, annotation)
+}
+
 // MockhostQueue is a mock of hostQueue interface.
 type MockhostQueue struct {
 	ctrl     *gomock.Controller
@@ -274,6 +274,7 @@ func (f *fetchState) asAggregatedTagsIterator(pools fetchTaggedPools, limit int)
 type fetchTaggedPools interface {
 	MultiReaderIteratorArray() encoding.MultiReaderIteratorArrayPool
 	MultiReaderIterator() encoding.MultiReaderIteratorPool
+	XORMultiReaderIterator() encoding.MultiReaderIteratorPool
 	MutableSeriesIterators() encoding.MutableSeriesIteratorsPool
 	SeriesIterator() encoding.SeriesIteratorPool
 	CheckedBytesWrapper() xpool.CheckedBytesWrapperPool
@@ -305,7 +305,7 @@ func (accum *fetchTaggedResultAccumulator) sliceResponsesAsSeriesIter(
 	for idx, elem := range elems {
 		slicesIter := pools.ReaderSliceOfSlicesIterator().Get()
 		slicesIter.Reset(elem.Segments)
-		multiIter := pools.MultiReaderIterator().Get()
+		multiIter := multiReaderIteratorPool(pools, elem.EncodingScheme).Get()
 		multiIter.ResetSliceOfSlices(slicesIter, descr)
 		iters[idx] = multiIter
 	}
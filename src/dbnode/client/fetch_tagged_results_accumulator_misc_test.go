@@ -30,12 +30,14 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	"github.com/m3db/m3/src/x/serialize"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -61,6 +63,52 @@ func TestFetchTaggedResultsAccumulatorClearResetsState(t *testing.T) {
 	require.NoError(t, resultsIter.Err())
 }
 
+func TestFetchTaggedResultsAccumulatorDecodesEncodingScheme(t *testing.T) {
+	var (
+		th    = newTestFetchTaggedHelper(t)
+		pools = newTestFetchTaggedPools()
+		ns    = ident.StringID("ns")
+		start = xtime.Now().Truncate(time.Hour)
+		end   = start.Add(time.Hour)
+		dps   = newTestDatapoints(10, start, end)
+		accum = newFetchTaggedResultAccumulator()
+	)
+
+	// Series of namespaces with different encoding schemes are decoded with
+	// the encoding scheme returned with their segments.
+	m3tszSeries := testSeries{ns: ns, id: ident.StringID("m3tsz"), datapoints: dps}
+	m3tszResult := m3tszSeries.toRPCResult(th, start)
+
+	xorEncoder := xor.NewEncoder(start, nil, encoding.NewOptions())
+	for _, dp := range dps {
+		require.NoError(t, xorEncoder.Encode(dp, testFetchTaggedTimeUnit, nil))
+	}
+	xorSegment := xorEncoder.Discard()
+	xorSeries := testSeries{ns: ns, id: ident.StringID("xor"), datapoints: dps}
+	xorResult := &rpc.FetchTaggedIDResult_{
+		NameSpace:   ns.Bytes(),
+		ID:          xorSeries.id.Bytes(),
+		EncodedTags: th.encodeTags(xorSeries.tags),
+		Segments: []*rpc.Segments{{
+			Merged: &rpc.Segment{Head: xorSegment.Head.Bytes()},
+		}},
+		EncodingScheme: rpc.EncodingScheme_XOR,
+	}
+
+	accum.startTime, accum.endTime = start, end
+	accum.fetchResponses = fetchTaggedIDResults{xorResult, m3tszResult}
+	iters, _, err := accum.AsEncodingSeriesIterators(10, pools, nil,
+		index.IterationOptions{})
+	require.NoError(t, err)
+	defer iters.Close()
+
+	require.Equal(t, 2, iters.Len())
+	for _, iter := range iters.Iters() {
+		dps.assertMatchesEncodingIter(t, iter)
+		require.NoError(t, iter.Err())
+	}
+}
+
 func TestFetchTaggedShardConsistencyResultsInitializeLength(t *testing.T) {
 	var results fetchTaggedShardConsistencyResults
 	require.Len(t, results, 0)
@@ -272,6 +320,9 @@ func initTestFetchTaggedPools() *testFetchTaggedPools {
 	pools.multiReader = encoding.NewMultiReaderIteratorPool(opts)
 	pools.multiReader.Init(m3tsz.DefaultReaderIteratorAllocFn(encoding.NewOptions()))
 
+	pools.xorMultiReader = encoding.NewMultiReaderIteratorPool(opts)
+	pools.xorMultiReader.Init(xor.DefaultReaderIteratorAllocFn(encoding.NewOptions()))
+
 	pools.seriesIter = encoding.NewSeriesIteratorPool(opts)
 	pools.seriesIter.Init()
 
@@ -304,6 +355,7 @@ var _ fetchTaggedPools = testFetchTaggedPools{}
 type testFetchTaggedPools struct {
 	readerSlices             *readerSliceOfSlicesIteratorPool
 	multiReader              encoding.MultiReaderIteratorPool
+	xorMultiReader           encoding.MultiReaderIteratorPool
 	seriesIter               encoding.SeriesIteratorPool
 	mutableSeriesIter        encoding.MutableSeriesIteratorsPool
 	multiReaderIteratorArray encoding.MultiReaderIteratorArrayPool
@@ -320,6 +372,10 @@ func (p testFetchTaggedPools) MultiReaderIterator() encoding.MultiReaderIterator
 	return p.multiReader
 }
 
+func (p testFetchTaggedPools) XORMultiReaderIterator() encoding.MultiReaderIteratorPool {
+	return p.xorMultiReader
+}
+
 func (p testFetchTaggedPools) SeriesIterator() encoding.SeriesIteratorPool {
 	return p.seriesIter
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/x/clock"
+	"github.com/m3db/m3/src/x/headers"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/pool"
 	xsync "github.com/m3db/m3/src/x/sync"
@@ -235,8 +236,7 @@ func (q *queue) drain() {
 		currV2WriteReq *rpc.WriteBatchRawV2Request
 		currV2WriteOps []op
 
-		currV2WriteTaggedReq *rpc.WriteTaggedBatchRawV2Request
-		currV2WriteTaggedOps []op
+		currV2WriteTaggedByTenant tenantWriteTaggedBatchV2Slice
 
 		currWriteOpsByNamespace       namespaceWriteBatchOpsSlice
 		currTaggedWriteOpsByNamespace namespaceWriteTaggedBatchOpsSlice
@@ -257,7 +257,7 @@ func (q *queue) drain() {
 				}
 			case *writeTaggedOperation:
 				if q.serverSupportsV2APIs {
-					currV2WriteTaggedReq, currV2WriteTaggedOps = q.drainTaggedWriteOpV2(v, currV2WriteTaggedReq, currV2WriteTaggedOps, ops[i])
+					currV2WriteTaggedByTenant = q.drainTaggedWriteOpV2(v, currV2WriteTaggedByTenant, ops[i])
 				} else {
 					currTaggedWriteOpsByNamespace = q.drainTaggedWriteOpV1(v, currTaggedWriteOpsByNamespace, ops[i])
 				}
@@ -295,8 +295,8 @@ func (q *queue) drain() {
 		// If any outstanding tagged write ops, async write
 		for i, writeOps := range currTaggedWriteOpsByNamespace {
 			if len(writeOps.ops) > 0 {
-				q.asyncTaggedWrite(writeOps.namespace, writeOps.ops,
-					writeOps.elems)
+				q.asyncTaggedWrite(writeOps.namespace, writeOps.tenant,
+					writeOps.ops, writeOps.elems)
 			}
 			// Zero the element
 			currTaggedWriteOpsByNamespace[i] = namespaceWriteTaggedBatchOps{}
@@ -310,11 +310,15 @@ func (q *queue) drain() {
 
 		// Reset the slice
 		currTaggedWriteOpsByNamespace = currTaggedWriteOpsByNamespace[:0]
-		if currV2WriteTaggedReq != nil {
-			q.asyncTaggedWriteV2(currV2WriteTaggedOps, currV2WriteTaggedReq)
-			currV2WriteTaggedReq = nil
-			currV2WriteTaggedOps = nil
+		for i, batch := range currV2WriteTaggedByTenant {
+			if batch.req != nil {
+				q.asyncTaggedWriteV2(batch.tenant, batch.ops, batch.req)
+			}
+			// Zero the element
+			currV2WriteTaggedByTenant[i] = tenantWriteTaggedBatchV2{}
 		}
+		// Reset the slice
+		currV2WriteTaggedByTenant = currV2WriteTaggedByTenant[:0]
 
 		if ops != nil {
 			q.opsArrayPool.Put(ops)
@@ -361,10 +365,11 @@ func (q *queue) drainTaggedWriteOpV1(
 	op op,
 ) namespaceWriteTaggedBatchOpsSlice {
 	namespace := v.namespace
-	idx := currTaggedWriteOpsByNamespace.indexOf(namespace)
+	idx := currTaggedWriteOpsByNamespace.indexOf(namespace, v.tenant)
 	if idx == -1 {
 		value := namespaceWriteTaggedBatchOps{
 			namespace:    namespace,
+			tenant:       v.tenant,
 			opsArrayPool: q.opsArrayPool,
 			writeTaggedBatchRawRequestElementArrayPool: q.writeTaggedBatchRawRequestElementArrayPool,
 		}
@@ -376,7 +381,7 @@ func (q *queue) drainTaggedWriteOpV1(
 
 	if currTaggedWriteOpsByNamespace.lenAt(idx) == q.opts.WriteBatchSize() {
 		// Reached write batch limit, write async and reset
-		q.asyncTaggedWrite(namespace, currTaggedWriteOpsByNamespace[idx].ops,
+		q.asyncTaggedWrite(namespace, v.tenant, currTaggedWriteOpsByNamespace[idx].ops,
 			currTaggedWriteOpsByNamespace[idx].elems)
 		currTaggedWriteOpsByNamespace.resetAt(idx)
 	}
@@ -427,11 +432,23 @@ func (q *queue) drainWriteOpV2(
 
 func (q *queue) drainTaggedWriteOpV2(
 	v *writeTaggedOperation,
-	currV2WriteTaggedReq *rpc.WriteTaggedBatchRawV2Request,
-	currV2WriteTaggedOps []op,
+	currV2WriteTaggedByTenant tenantWriteTaggedBatchV2Slice,
 	op op,
-) (*rpc.WriteTaggedBatchRawV2Request, []op) {
-	namespace := v.namespace
+) tenantWriteTaggedBatchV2Slice {
+	// NB: writes are batched by tenant since the tenant is sent as a header
+	// of the whole batch.
+	tenantIdx := currV2WriteTaggedByTenant.indexOf(v.tenant)
+	if tenantIdx == -1 {
+		tenantIdx = len(currV2WriteTaggedByTenant)
+		currV2WriteTaggedByTenant = append(currV2WriteTaggedByTenant,
+			tenantWriteTaggedBatchV2{tenant: v.tenant})
+	}
+
+	var (
+		namespace            = v.namespace
+		currV2WriteTaggedReq = currV2WriteTaggedByTenant[tenantIdx].req
+		currV2WriteTaggedOps = currV2WriteTaggedByTenant[tenantIdx].ops
+	)
 	if currV2WriteTaggedReq == nil {
 		currV2WriteTaggedReq = q.writeTaggedBatchRawV2RequestPool.Get()
 		currV2WriteTaggedReq.Elements = q.writeTaggedBatchRawV2RequestElementArrayPool.Get()
@@ -458,12 +475,14 @@ func (q *queue) drainTaggedWriteOpV2(
 	currV2WriteTaggedOps = append(currV2WriteTaggedOps, op)
 	if len(currV2WriteTaggedReq.Elements) == q.opts.WriteBatchSize() {
 		// Reached write batch limit, write async and reset.
-		q.asyncTaggedWriteV2(currV2WriteTaggedOps, currV2WriteTaggedReq)
+		q.asyncTaggedWriteV2(v.tenant, currV2WriteTaggedOps, currV2WriteTaggedReq)
 		currV2WriteTaggedReq = nil
 		currV2WriteTaggedOps = nil
 	}
 
-	return currV2WriteTaggedReq, currV2WriteTaggedOps
+	currV2WriteTaggedByTenant[tenantIdx].req = currV2WriteTaggedReq
+	currV2WriteTaggedByTenant[tenantIdx].ops = currV2WriteTaggedOps
+	return currV2WriteTaggedByTenant
 }
 
 func (q *queue) drainFetchBatchRawV2Op(
@@ -508,6 +527,7 @@ func (q *queue) drainFetchBatchRawV2Op(
 
 func (q *queue) asyncTaggedWrite(
 	namespace ident.ID,
+	tenant string,
 	ops []op,
 	elems []*rpc.WriteTaggedBatchRawRequestElement,
 ) {
@@ -538,7 +558,7 @@ func (q *queue) asyncTaggedWrite(
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
+		ctx := q.newWriteContext(tenant)
 		err = client.WriteTaggedBatchRaw(ctx, req)
 		if err == nil {
 			// All succeeded
@@ -573,6 +593,7 @@ func (q *queue) asyncTaggedWrite(
 }
 
 func (q *queue) asyncTaggedWriteV2(
+	tenant string,
 	ops []op,
 	req *rpc.WriteTaggedBatchRawV2Request,
 ) {
@@ -598,7 +619,7 @@ func (q *queue) asyncTaggedWriteV2(
 			return
 		}
 
-		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
+		ctx := q.newWriteContext(tenant)
 		err = client.WriteTaggedBatchRawV2(ctx, req)
 		if err == nil {
 			// All succeeded
@@ -756,6 +777,16 @@ func (q *queue) asyncWriteV2(
 	})
 }
 
+// newWriteContext returns the context for a write request, which attributes
+// the writes of the request to their tenant, if any.
+func (q *queue) newWriteContext(tenant string) thrift.Context {
+	ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
+	if tenant == "" {
+		return ctx
+	}
+	return thrift.WithHeaders(ctx, map[string]string{headers.SourceHeader: tenant})
+}
+
 func (q *queue) asyncFetch(op *fetchBatchOp) {
 	q.fetchOpBatchSize.RecordValue(float64(len(op.request.Ids)))
 	q.Add(1)
@@ -795,7 +826,7 @@ func (q *queue) asyncFetch(op *fetchBatchOp) {
 				op.complete(i, nil, result.Elements[i].Err)
 				continue
 			}
-			op.complete(i, result.Elements[i].Segments, nil)
+			op.complete(i, result.Elements[i], nil)
 		}
 		cleanup()
 	})
@@ -851,7 +882,7 @@ func (q *queue) asyncFetchV2(
 					fetchOp.complete(j, nil, result.Elements[resultIdx].Err)
 					continue
 				}
-				fetchOp.complete(j, result.Elements[resultIdx].Segments, nil)
+				fetchOp.complete(j, result.Elements[resultIdx], nil)
 			}
 		}
 		cleanup()
@@ -1183,6 +1214,7 @@ func (s namespaceWriteBatchOpsSlice) resetAt(
 // share code (https://github.com/m3db/m3/src/dbnode/issues/531)
 type namespaceWriteTaggedBatchOps struct {
 	namespace                                  ident.ID
+	tenant                                     string
 	opsArrayPool                               *opArrayPool
 	writeTaggedBatchRawRequestElementArrayPool writeTaggedBatchRawRequestElementArrayPool
 	ops                                        []op
@@ -1193,10 +1225,11 @@ type namespaceWriteTaggedBatchOpsSlice []namespaceWriteTaggedBatchOps
 
 func (s namespaceWriteTaggedBatchOpsSlice) indexOf(
 	namespace ident.ID,
+	tenant string,
 ) int {
 	idx := -1
 	for i := range s {
-		if s[i].namespace.Equal(namespace) {
+		if s[i].namespace.Equal(namespace) && s[i].tenant == tenant {
 			return i
 		}
 	}
@@ -1230,3 +1263,24 @@ func (s namespaceWriteTaggedBatchOpsSlice) resetAt(
 	s[index].ops = nil
 	s[index].elems = nil
 }
+
+// tenantWriteTaggedBatchV2 is the current batch of tagged writes of a tenant.
+type tenantWriteTaggedBatchV2 struct {
+	tenant string
+	req    *rpc.WriteTaggedBatchRawV2Request
+	ops    []op
+}
+
+type tenantWriteTaggedBatchV2Slice []tenantWriteTaggedBatchV2
+
+func (s tenantWriteTaggedBatchV2Slice) indexOf(
+	tenant string,
+) int {
+	idx := -1
+	for i := range s {
+		if s[i].tenant == tenant {
+			return i
+		}
+	}
+	return idx
+}
@@ -41,7 +41,7 @@ func TestHostQueueFetchBatches(t *testing.T) {
 	}
 	var expected []hostQueueResult
 	for i := range ids {
-		expected = append(expected, hostQueueResult{result.Elements[i].Segments, nil})
+		expected = append(expected, hostQueueResult{result.Elements[i], nil})
 	}
 	testHostQueueFetchBatches(t, namespace, ids, result, expected, nil, func(results []hostQueueResult) {
 		assert.Equal(t, expected, results)
@@ -56,7 +56,7 @@ func TestHostQueueFetchBatchesV2MultiNS(t *testing.T) {
 	}
 	var expected []hostQueueResult
 	for i := range ids {
-		expected = append(expected, hostQueueResult{result.Elements[i].Segments, nil})
+		expected = append(expected, hostQueueResult{result.Elements[i], nil})
 	}
 	opts := newHostQueueTestOptions().SetUseV2BatchAPIs(true)
 	ctrl := gomock.NewController(t)
@@ -185,7 +185,7 @@ func TestHostQueueFetchBatchesErrorOnFetchNoResponse(t *testing.T) {
 	}
 	var expected []hostQueueResult
 	for i := range ids[:len(ids)-1] {
-		expected = append(expected, hostQueueResult{result.Elements[i].Segments, nil})
+		expected = append(expected, hostQueueResult{result.Elements[i], nil})
 	}
 
 	testHostQueueFetchBatches(t, namespace, ids, result, expected, nil, func(results []hostQueueResult) {
@@ -207,7 +207,7 @@ func TestHostQueueFetchBatchesErrorOnResultError(t *testing.T) {
 	result.Elements = append(result.Elements, &rpc.FetchRawResult_{Err: anError})
 	var expected []hostQueueResult
 	for i := range ids[:len(ids)-1] {
-		expected = append(expected, hostQueueResult{result.Elements[i].Segments, nil})
+		expected = append(expected, hostQueueResult{result.Elements[i], nil})
 	}
 	testHostQueueFetchBatches(t, namespace, ids, result, expected, nil, func(results []hostQueueResult) {
 		assert.Equal(t, expected, results[:len(results)-1])
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/x/headers"
 	"github.com/m3db/m3/src/x/ident"
 
 	"github.com/golang/mock/gomock"
@@ -156,6 +157,91 @@ func TestHostQueueWriteTaggedBatches(t *testing.T) {
 	}
 }
 
+func TestHostQueueWriteTaggedBatchesByTenant(t *testing.T) {
+	for _, opts := range []Options{
+		newHostQueueTestOptions().SetUseV2BatchAPIs(false),
+		newHostQueueTestOptions().SetUseV2BatchAPIs(true),
+	} {
+		t.Run(fmt.Sprintf("useV2: %v", opts.UseV2BatchAPIs()), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConnPool := NewMockconnectionPool(ctrl)
+
+			queue := newTestHostQueue(opts)
+			queue.connPool = mockConnPool
+
+			// Open
+			mockConnPool.EXPECT().Open()
+			queue.Open()
+
+			// Prepare writes of several tenants, including writes without one.
+			var (
+				wg       sync.WaitGroup
+				tenants  = []string{"foo", "bar", ""}
+				expected = make(map[string]int)
+				callback = func(r interface{}, err error) {
+					assert.NoError(t, err)
+					wg.Done()
+				}
+				writes []*writeTaggedOperation
+			)
+			for i := 0; i < opts.HostQueueOpsFlushSize(); i++ {
+				write := testWriteTaggedOp("testNs", fmt.Sprintf("foo%d", i),
+					map[string]string{"tag": "value"}, float64(i), 1000, rpc.TimeType_UNIX_SECONDS, callback)
+				write.tenant = tenants[i%len(tenants)]
+				expected[write.tenant]++
+				writes = append(writes, write)
+			}
+			wg.Add(len(writes))
+
+			// Prepare mocks for flush, recording the writes of each tenant.
+			var (
+				lock       sync.Mutex
+				written    = make(map[string]int)
+				mockClient = rpc.NewMockTChanNode(ctrl)
+			)
+			recordTenant := func(ctx thrift.Context, numWrites int) {
+				lock.Lock()
+				defer lock.Unlock()
+				written[ctx.Headers()[headers.SourceHeader]] += numWrites
+			}
+			if opts.UseV2BatchAPIs() {
+				mockClient.EXPECT().WriteTaggedBatchRawV2(gomock.Any(), gomock.Any()).
+					Do(func(ctx thrift.Context, req *rpc.WriteTaggedBatchRawV2Request) {
+						recordTenant(ctx, len(req.Elements))
+					}).Return(nil).Times(len(tenants))
+			} else {
+				mockClient.EXPECT().WriteTaggedBatchRaw(gomock.Any(), gomock.Any()).
+					Do(func(ctx thrift.Context, req *rpc.WriteTaggedBatchRawRequest) {
+						recordTenant(ctx, len(req.Elements))
+					}).Return(nil).Times(len(tenants))
+			}
+			mockConnPool.EXPECT().NextClient().Return(mockClient, &noopPooledChannel{}, nil).
+				Times(len(tenants))
+
+			for _, write := range writes {
+				assert.NoError(t, queue.Enqueue(write))
+			}
+
+			// Wait for all writes
+			wg.Wait()
+
+			// Each batch holds the writes of a single tenant.
+			assert.Equal(t, expected, written)
+
+			// Close
+			var closeWg sync.WaitGroup
+			closeWg.Add(1)
+			mockConnPool.EXPECT().Close().Do(func() {
+				closeWg.Done()
+			})
+			queue.Close()
+			closeWg.Wait()
+		})
+	}
+}
+
 func TestHostQueueWriteTaggedBatchesDifferentNamespaces(t *testing.T) {
 	for _, opts := range []Options{
 		newHostQueueTestOptions().SetUseV2BatchAPIs(false),
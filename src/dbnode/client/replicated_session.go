@@ -146,6 +146,7 @@ func (s *replicatedSession) setAsyncSessions(opts []Options) error {
 }
 
 type replicatedParams struct {
+	ctx        context.Context
 	namespace  ident.ID
 	id         ident.ID
 	t          xtime.UnixNano
@@ -164,18 +165,7 @@ func (s replicatedSession) replicate(params replicatedParams) error {
 		select {
 		case s.replicationSemaphore <- struct{}{}:
 			s.workerPool.Go(func() {
-				var err error
-				if params.useTags {
-					err = asyncSession.WriteTagged(
-						params.namespace, params.id, params.tags, params.t,
-						params.value, params.unit, params.annotation,
-					)
-				} else {
-					err = asyncSession.Write(
-						params.namespace, params.id, params.t,
-						params.value, params.unit, params.annotation,
-					)
-				}
+				err := s.write(asyncSession, params)
 				if err != nil {
 					s.metrics.replicateError.Inc(1)
 					s.log.Error("could not replicate write", zap.Error(err))
@@ -193,14 +183,24 @@ func (s replicatedSession) replicate(params replicatedParams) error {
 		}
 	}
 
+	return s.write(s.session, params)
+}
+
+func (s replicatedSession) write(session clientSession, params replicatedParams) error {
 	if params.useTags {
-		return s.session.WriteTagged(
+		if params.ctx != nil {
+			return session.WriteTaggedWithContext(params.ctx,
+				params.namespace, params.id, params.tags, params.t,
+				params.value, params.unit, params.annotation,
+			)
+		}
+		return session.WriteTagged(
 			params.namespace, params.id, params.tags, params.t,
 			params.value, params.unit, params.annotation,
 		)
 	}
 
-	return s.session.Write(
+	return session.Write(
 		params.namespace, params.id, params.t,
 		params.value, params.unit, params.annotation,
 	)
@@ -246,6 +246,25 @@ func (s replicatedSession) WriteTagged(
 	})
 }
 
+// WriteTaggedWithContext value to the database for an ID and given tags,
+// attributing the write to the source of the context.
+func (s replicatedSession) WriteTaggedWithContext(
+	ctx context.Context, namespace, id ident.ID, tags ident.TagIterator,
+	t xtime.UnixNano, value float64, unit xtime.Unit, annotation []byte,
+) error {
+	return s.replicate(replicatedParams{
+		ctx:        ctx,
+		namespace:  namespace,
+		id:         id,
+		t:          t.Add(-s.writeTimestampOffset),
+		value:      value,
+		unit:       unit,
+		annotation: annotation,
+		tags:       tags,
+		useTags:    true,
+	})
+}
+
 // Fetch values from the database for an ID.
 func (s replicatedSession) Fetch(
 	namespace, id ident.ID, startInclusive, endExclusive xtime.UnixNano,
@@ -290,6 +309,44 @@ func (s replicatedSession) FetchTaggedIDs(
 	return s.session.FetchTaggedIDs(ctx, namespace, q, opts)
 }
 
+// WriteExemplars writes exemplars for a set of series.
+// NB: exemplars are only held in memory and so are not replicated to the
+// async clusters.
+func (s replicatedSession) WriteExemplars(
+	namespace ident.ID, series []SeriesExemplars,
+) error {
+	return s.session.WriteExemplars(namespace, series)
+}
+
+// FetchExemplars fetches exemplars for a set of series IDs.
+func (s replicatedSession) FetchExemplars(
+	namespace ident.ID, ids []ident.ID, start, end xtime.UnixNano,
+) ([]SeriesExemplars, error) {
+	return s.session.FetchExemplars(namespace, ids, start, end)
+}
+
+// DeleteTagged deletes the data of the series matching the query.
+// NB: deletions are not replicated to the async clusters.
+func (s replicatedSession) DeleteTagged(
+	namespace ident.ID, q index.Query, start, end xtime.UnixNano,
+) (int64, error) {
+	return s.session.DeleteTagged(namespace, q, start, end)
+}
+
+// Cardinality returns the top cardinality statistics of the series of a namespace.
+func (s replicatedSession) Cardinality(
+	namespace ident.ID, opts index.CardinalityQueryOptions,
+) (index.CardinalityStats, error) {
+	return s.session.Cardinality(namespace, opts)
+}
+
+// Count returns the number of series of a namespace matching a query.
+func (s replicatedSession) Count(
+	namespace ident.ID, q index.Query, opts index.CountQueryOptions,
+) (index.CountResult, error) {
+	return s.session.Count(namespace, q, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
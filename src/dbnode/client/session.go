@@ -35,18 +35,21 @@ import (
 	"github.com/m3db/m3/src/cluster/shard"
 	"github.com/m3db/m3/src/dbnode/digest"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	idxconvert "github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
+	"github.com/m3db/m3/src/query/source"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/context"
@@ -74,6 +77,11 @@ const (
 	blockMetadataChBufSize           = 65536
 	hostNotAvailableMinSleepInterval = 1 * time.Millisecond
 	hostNotAvailableMaxSleepInterval = 100 * time.Millisecond
+
+	// cardinalityHostLimitFactor is the factor of the limit of a cardinality
+	// query that each host returns, since the top statistics of each host are
+	// merged the extra entries make the merged counts more accurate.
+	cardinalityHostLimitFactor = 10
 )
 
 type resultTypeEnum string
@@ -93,6 +101,9 @@ var (
 	// ErrClusterConnectTimeout is raised when connecting to the cluster and
 	// ensuring at least each partition has an up node with a connection to it
 	ErrClusterConnectTimeout = errors.New("timed out establishing min connections to cluster")
+	// ErrNoAvailableReplicaForShard is raised when no replica of a shard is
+	// available for an operation which requires one.
+	ErrNoAvailableReplicaForShard = errors.New("no available replica for shard")
 	// errSessionStatusNotInitial is raised when trying to open a session and
 	// its not in the initial clean state
 	errSessionStatusNotInitial = errors.New("session not in initial state")
@@ -1033,6 +1044,16 @@ func (s *session) setTopologyWithLock(topoMap topology.Map, queues []hostQueue,
 		s.pools.multiReaderIterator = encoding.NewMultiReaderIteratorPool(poolOpts)
 		s.pools.multiReaderIterator.Init(s.opts.ReaderIteratorAllocate())
 	}
+	if s.pools.xorMultiReaderIterator == nil {
+		size := replicas * s.opts.SeriesIteratorPoolSize()
+		poolOpts := pool.NewObjectPoolOptions().
+			SetSize(size).
+			SetInstrumentOptions(s.opts.InstrumentOptions().SetMetricsScope(
+				s.scope.SubScope("xor-multi-reader-iterator-pool"),
+			))
+		s.pools.xorMultiReaderIterator = encoding.NewMultiReaderIteratorPool(poolOpts)
+		s.pools.xorMultiReaderIterator.Init(xor.DefaultReaderIteratorAllocFn(encoding.NewOptions()))
+	}
 	if replicas > len(s.metrics.writeNodesRespondingErrors) {
 		curr := len(s.metrics.writeNodesRespondingErrors)
 		for i := curr; i < replicas; i++ {
@@ -1206,12 +1227,41 @@ func (s *session) WriteTagged(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
+) error {
+	return s.writeTagged(nsID, id, tags, t, value, unit, annotation, "")
+}
+
+func (s *session) WriteTaggedWithContext(
+	ctx gocontext.Context,
+	nsID, id ident.ID,
+	tags ident.TagIterator,
+	t xtime.UnixNano,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+) error {
+	var tenant string
+	if src, ok := source.RawFromContext(ctx); ok {
+		tenant = string(src)
+	}
+	return s.writeTagged(nsID, id, tags, t, value, unit, annotation, tenant)
+}
+
+func (s *session) writeTagged(
+	nsID, id ident.ID,
+	tags ident.TagIterator,
+	t xtime.UnixNano,
+	value float64,
+	unit xtime.Unit,
+	annotation []byte,
+	tenant string,
 ) error {
 	w := s.pools.writeAttempt.Get()
 	w.args.attemptType = taggedWriteAttemptType
 	w.args.namespace, w.args.id, w.args.tags = nsID, id, tags
 	w.args.t = t
 	w.args.value, w.args.unit, w.args.annotation = value, unit, annotation
+	w.args.tenant = tenant
 	err := s.writeRetrier.Attempt(w.attemptFn)
 	s.pools.writeAttempt.Put(w)
 	return err
@@ -1225,6 +1275,7 @@ func (s *session) writeAttempt(
 	value float64,
 	unit xtime.Unit,
 	annotation []byte,
+	tenant string,
 ) error {
 	startWriteAttempt := s.nowFn()
 
@@ -1245,7 +1296,7 @@ func (s *session) writeAttempt(
 	}
 
 	state, majority, enqueued, err := s.writeAttemptWithRLock(
-		wType, nsID, id, inputTags, timestamp, value, timeType, annotation)
+		wType, nsID, id, inputTags, timestamp, value, timeType, annotation, tenant)
 	s.state.RUnlock()
 
 	if err != nil {
@@ -1280,6 +1331,7 @@ func (s *session) writeAttemptWithRLock(
 	value float64,
 	timeType rpc.TimeType,
 	annotation []byte,
+	tenant string,
 ) (*writeState, int32, int32, error) {
 	var (
 		majority = int32(s.state.majority)
@@ -1330,6 +1382,7 @@ func (s *session) writeAttemptWithRLock(
 	case taggedWriteAttemptType:
 		wop := s.pools.writeTaggedOperation.Get()
 		wop.namespace = nsID
+		wop.tenant = tenant
 		wop.shardID = s.state.topoMap.ShardSet().Lookup(tsID)
 		wop.request.ID = tsID.Bytes()
 		encodedTagBytes, ok := tagEncoder.Data()
@@ -1952,9 +2005,10 @@ func (s *session) fetchIDsAttempt(
 				errors = append(errors, err)
 				resultErrLock.Unlock()
 			} else {
+				rawResult := result.(*rpc.FetchRawResult_)
 				slicesIter := s.pools.readerSliceOfSlicesIterator.Get()
-				slicesIter.Reset(result.([]*rpc.Segments))
-				multiIter := s.pools.multiReaderIterator.Get()
+				slicesIter.Reset(rawResult.Segments)
+				multiIter := multiReaderIteratorPool(s.pools, rawResult.EncodingScheme).Get()
 				multiIter.ResetSliceOfSlices(slicesIter, nsCtx.Schema)
 				// Results is pre-allocated after creating fetch ops for this ID below
 				resultsLock.Lock()
@@ -2209,6 +2263,427 @@ func (s *session) Truncate(namespace ident.ID) (int64, error) {
 	return truncated, resultErr.FinalError()
 }
 
+func (s *session) WriteExemplars(namespace ident.ID, series []SeriesExemplars) error {
+	ids := make([]ident.ID, 0, len(series))
+	for _, elem := range series {
+		ids = append(ids, elem.ID)
+	}
+
+	idxsByHost, err := s.routeIDsByHost(ids)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg          sync.WaitGroup
+		resultLock  sync.Mutex
+		resultErr   xerrors.MultiError
+		nsID        = namespace.Bytes()
+		reqTimeout  = s.opts.WriteRequestTimeout()
+		exemplarsFn = func(hostID string, req *rpc.WriteExemplarsRequest) {
+			defer wg.Done()
+
+			var writeErr error
+			borrowErr := s.BorrowConnection(hostID, func(client rpc.TChanNode, _ Channel) {
+				tctx, _ := thrift.NewContext(reqTimeout)
+				writeErr = client.WriteExemplars(tctx, req)
+			})
+			if err := xerrors.FirstError(borrowErr, writeErr); err != nil {
+				resultLock.Lock()
+				resultErr = resultErr.Add(err)
+				resultLock.Unlock()
+			}
+		}
+	)
+	for hostID, idxs := range idxsByHost {
+		req := rpc.NewWriteExemplarsRequest()
+		req.NameSpace = nsID
+		req.Elements = make([]*rpc.WriteExemplarsRequestElement, 0, len(idxs))
+		for _, i := range idxs {
+			req.Elements = append(req.Elements, &rpc.WriteExemplarsRequestElement{
+				ID:        series[i].ID.Bytes(),
+				Exemplars: convert.ToRPCExemplars(series[i].Exemplars),
+			})
+		}
+
+		wg.Add(1)
+		go exemplarsFn(hostID, req)
+	}
+
+	wg.Wait()
+
+	return resultErr.FinalError()
+}
+
+func (s *session) FetchExemplars(
+	namespace ident.ID,
+	ids []ident.ID,
+	start,
+	end xtime.UnixNano,
+) ([]SeriesExemplars, error) {
+	idxsByHost, err := s.routeIDsByHost(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg         sync.WaitGroup
+		resultLock sync.Mutex
+		resultErr  xerrors.MultiError
+		successes  int
+		results    = make([][]exemplar.Exemplar, len(ids))
+		nsID       = namespace.Bytes()
+		reqTimeout = s.opts.FetchRequestTimeout()
+		fetchFn    = func(hostID string, idxs []int) {
+			defer wg.Done()
+
+			req := rpc.NewFetchExemplarsRequest()
+			req.NameSpace = nsID
+			req.RangeStart = int64(start)
+			req.RangeEnd = int64(end)
+			req.Ids = make([][]byte, 0, len(idxs))
+			idxsByID := make(map[string]int, len(idxs))
+			for _, i := range idxs {
+				id := ids[i].Bytes()
+				req.Ids = append(req.Ids, id)
+				idxsByID[string(id)] = i
+			}
+
+			var (
+				fetchResult *rpc.FetchExemplarsResult_
+				fetchErr    error
+			)
+			borrowErr := s.BorrowConnection(hostID, func(client rpc.TChanNode, _ Channel) {
+				tctx, _ := thrift.NewContext(reqTimeout)
+				fetchResult, fetchErr = client.FetchExemplars(tctx, req)
+			})
+
+			resultLock.Lock()
+			defer resultLock.Unlock()
+
+			if err := xerrors.FirstError(borrowErr, fetchErr); err != nil {
+				resultErr = resultErr.Add(err)
+				return
+			}
+
+			successes++
+			for _, elem := range fetchResult.Elements {
+				i, ok := idxsByID[string(elem.ID)]
+				if !ok {
+					continue
+				}
+				results[i] = mergeExemplars(results[i],
+					convert.FromRPCExemplars(elem.Exemplars))
+			}
+		}
+	)
+	for hostID, idxs := range idxsByHost {
+		wg.Add(1)
+		go fetchFn(hostID, idxs)
+	}
+
+	wg.Wait()
+
+	// NB: exemplars are replicated to every replica owning a series so only
+	// fail the fetch if no replica could be reached.
+	if successes == 0 && resultErr.NumErrors() > 0 {
+		return nil, resultErr.FinalError()
+	}
+
+	series := make([]SeriesExemplars, 0, len(ids))
+	for i, exemplars := range results {
+		if len(exemplars) == 0 {
+			continue
+		}
+		series = append(series, SeriesExemplars{
+			ID:        ids[i],
+			Exemplars: exemplars,
+		})
+	}
+	return series, nil
+}
+
+func (s *session) DeleteTagged(
+	namespace ident.ID,
+	q index.Query,
+	start,
+	end xtime.UnixNano,
+) (int64, error) {
+	req, err := convert.ToRPCDeleteTaggedRequest(namespace, q, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	s.state.RLock()
+	topoMap, err := s.topologyMapWithStateRLock()
+	s.state.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg         sync.WaitGroup
+		resultLock sync.Mutex
+		resultErr  xerrors.MultiError
+		deleted    int64
+		reqTimeout = s.opts.WriteRequestTimeout()
+		deleteFn   = func(hostID string) {
+			defer wg.Done()
+
+			var (
+				deleteResult *rpc.DeleteTaggedResult_
+				deleteErr    error
+			)
+			borrowErr := s.BorrowConnection(hostID, func(client rpc.TChanNode, _ Channel) {
+				tctx, _ := thrift.NewContext(reqTimeout)
+				deleteResult, deleteErr = client.DeleteTagged(tctx, &req)
+			})
+
+			resultLock.Lock()
+			defer resultLock.Unlock()
+
+			if err := xerrors.FirstError(borrowErr, deleteErr); err != nil {
+				resultErr = resultErr.Add(err)
+				return
+			}
+			deleted += deleteResult.NumSeries
+		}
+	)
+	for _, host := range topoMap.Hosts() {
+		wg.Add(1)
+		go deleteFn(host.ID())
+	}
+
+	wg.Wait()
+
+	// NB: tombstones are recorded by each replica independently so any
+	// failed host fails the deletion, it is safe to retry since deleting
+	// the same range again is a no-op.
+	if err := resultErr.FinalError(); err != nil {
+		return 0, err
+	}
+
+	// Every series is deleted once on each of its replicas.
+	return deleted / int64(topoMap.Replicas()), nil
+}
+
+func (s *session) Cardinality(
+	namespace ident.ID,
+	opts index.CardinalityQueryOptions,
+) (index.CardinalityStats, error) {
+	// Count each shard on a single available replica so that the series
+	// counts of the hosts can be summed.
+	shardsByHost, err := s.routeShardsByAvailableHost()
+	if err != nil {
+		return index.CardinalityStats{}, err
+	}
+
+	var (
+		wg            sync.WaitGroup
+		resultLock    sync.Mutex
+		resultErr     xerrors.MultiError
+		stats         = make([]index.CardinalityStats, 0, len(shardsByHost))
+		reqTimeout    = s.opts.FetchRequestTimeout()
+		cardinalityFn = func(hostID string, shards []uint32) {
+			defer wg.Done()
+
+			hostOpts := opts
+			hostOpts.Shards = shards
+			hostOpts.Limit = opts.Limit * cardinalityHostLimitFactor
+			req := convert.ToRPCCardinalityRequest(namespace, hostOpts)
+
+			var (
+				result *rpc.CardinalityResult_
+				reqErr error
+			)
+			borrowErr := s.BorrowConnection(hostID, func(client rpc.TChanNode, _ Channel) {
+				tctx, _ := thrift.NewContext(reqTimeout)
+				result, reqErr = client.Cardinality(tctx, req)
+			})
+
+			resultLock.Lock()
+			defer resultLock.Unlock()
+
+			if err := xerrors.FirstError(borrowErr, reqErr); err != nil {
+				resultErr = resultErr.Add(err)
+				return
+			}
+			stats = append(stats, convert.FromRPCCardinalityResult(result))
+		}
+	)
+	for hostID, shards := range shardsByHost {
+		wg.Add(1)
+		go cardinalityFn(hostID, shards)
+	}
+
+	wg.Wait()
+
+	if err := resultErr.FinalError(); err != nil {
+		return index.CardinalityStats{}, err
+	}
+	return index.MergeCardinalityStats(stats, opts.Limit), nil
+}
+
+func (s *session) Count(
+	namespace ident.ID,
+	q index.Query,
+	opts index.CountQueryOptions,
+) (index.CountResult, error) {
+	// Count each shard on a single available replica so that the series
+	// counts of the hosts can be summed.
+	shardsByHost, err := s.routeShardsByAvailableHost()
+	if err != nil {
+		return index.CountResult{}, err
+	}
+
+	result, err := index.NewCountResult(opts.IncludeSketch)
+	if err != nil {
+		return index.CountResult{}, err
+	}
+
+	var (
+		wg         sync.WaitGroup
+		resultLock sync.Mutex
+		resultErr  xerrors.MultiError
+		reqTimeout = s.opts.FetchRequestTimeout()
+		countFn    = func(hostID string, shards []uint32) {
+			defer wg.Done()
+
+			hostOpts := opts
+			hostOpts.Shards = shards
+			hostResult, err := s.countHost(hostID, namespace, q, hostOpts, reqTimeout)
+
+			resultLock.Lock()
+			defer resultLock.Unlock()
+
+			if err == nil {
+				err = result.Merge(hostResult)
+			}
+			if err != nil {
+				resultErr = resultErr.Add(err)
+			}
+		}
+	)
+	for hostID, shards := range shardsByHost {
+		wg.Add(1)
+		go countFn(hostID, shards)
+	}
+
+	wg.Wait()
+
+	if err := resultErr.FinalError(); err != nil {
+		return index.CountResult{}, err
+	}
+	return result, nil
+}
+
+func (s *session) countHost(
+	hostID string,
+	namespace ident.ID,
+	q index.Query,
+	opts index.CountQueryOptions,
+	timeout time.Duration,
+) (index.CountResult, error) {
+	req, err := convert.ToRPCCountRequest(namespace, q, opts)
+	if err != nil {
+		return index.CountResult{}, err
+	}
+
+	var (
+		result *rpc.CountResult_
+		reqErr error
+	)
+	borrowErr := s.BorrowConnection(hostID, func(client rpc.TChanNode, _ Channel) {
+		tctx, _ := thrift.NewContext(timeout)
+		result, reqErr = client.Count(tctx, req)
+	})
+	if err := xerrors.FirstError(borrowErr, reqErr); err != nil {
+		return index.CountResult{}, err
+	}
+	return convert.FromRPCCountResult(result)
+}
+
+// routeShardsByAvailableHost routes each shard to the first of its replicas
+// which is available.
+func (s *session) routeShardsByAvailableHost() (map[string][]uint32, error) {
+	s.state.RLock()
+	topoMap, err := s.topologyMapWithStateRLock()
+	s.state.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	shardsByHost := make(map[string][]uint32)
+	for _, shardID := range topoMap.ShardSet().AllIDs() {
+		var hostID string
+		if err := topoMap.RouteShardForEach(shardID, func(
+			_ int,
+			s shard.Shard,
+			host topology.Host,
+		) {
+			if hostID == "" && s.State() == shard.Available {
+				hostID = host.ID()
+			}
+		}); err != nil {
+			return nil, err
+		}
+		if hostID == "" {
+			return nil, fmt.Errorf("%w: shard=%d",
+				ErrNoAvailableReplicaForShard, shardID)
+		}
+		shardsByHost[hostID] = append(shardsByHost[hostID], shardID)
+	}
+	return shardsByHost, nil
+}
+
+func (s *session) routeIDsByHost(ids []ident.ID) (map[string][]int, error) {
+	s.state.RLock()
+	topoMap, err := s.topologyMapWithStateRLock()
+	s.state.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	idxsByHost := make(map[string][]int)
+	for i, id := range ids {
+		if err := topoMap.RouteForEach(id, func(
+			_ int,
+			_ shard.Shard,
+			host topology.Host,
+		) {
+			idxsByHost[host.ID()] = append(idxsByHost[host.ID()], i)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return idxsByHost, nil
+}
+
+// mergeExemplars merges two sets of exemplars sorted by timestamp, dropping
+// exemplars of the second set with a timestamp already present in the first.
+func mergeExemplars(a, b []exemplar.Exemplar) []exemplar.Exemplar {
+	if len(a) == 0 {
+		return b
+	}
+
+	result := make([]exemplar.Exemplar, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0].Timestamp.Before(b[0].Timestamp):
+			result = append(result, a[0])
+			a = a[1:]
+		case b[0].Timestamp.Before(a[0].Timestamp):
+			result = append(result, b[0])
+			b = b[1:]
+		default:
+			result = append(result, a[0])
+			a, b = a[1:], b[1:]
+		}
+	}
+	result = append(result, a...)
+	return append(result, b...)
+}
+
 // NB(r): Excluding maligned struct check here as we can
 // live with a few extra bytes since this struct is only
 // ever passed by stack, its much more readable not optimized
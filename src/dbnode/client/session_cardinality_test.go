@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+func TestSessionCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	var (
+		start = xtime.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+		end   = start.Add(time.Hour)
+
+		lock   sync.Mutex
+		shards []int
+	)
+	session.newHostQueueFn = func(
+		host topology.Host,
+		opts hostQueueOpts,
+	) (hostQueue, error) {
+		client := rpc.NewMockTChanNode(ctrl)
+		client.EXPECT().
+			Cardinality(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ thrift.Context,
+				req *rpc.CardinalityRequest,
+			) (*rpc.CardinalityResult_, error) {
+				require.Equal(t, []byte("metrics"), req.NameSpace)
+				require.Equal(t, int64(start), req.RangeStart)
+				require.Equal(t, int64(end), req.RangeEnd)
+				require.Equal(t, int64(2*cardinalityHostLimitFactor), req.Limit)
+				require.Equal(t, []byte("__name__"), req.MetricNameTag)
+
+				lock.Lock()
+				for _, shard := range req.Shards {
+					shards = append(shards, int(shard))
+				}
+				lock.Unlock()
+
+				// Each shard holds a single foo series.
+				n := int64(len(req.Shards))
+				return &rpc.CardinalityResult_{
+					NumSeries: n,
+					SeriesCountByMetricName: []*rpc.CardinalityStat{
+						{Name: []byte("foo"), Value: n},
+					},
+					LabelValueCountByLabelName: []*rpc.CardinalityStat{
+						{Name: []byte("__name__"), Value: 1},
+					},
+					SeriesCountByLabelValuePair: []*rpc.CardinalityStat{
+						{Name: []byte("__name__=foo"), Value: n},
+					},
+				}, nil
+			}).
+			AnyTimes()
+
+		hostQueue := NewMockhostQueue(ctrl)
+		hostQueue.EXPECT().Open()
+		hostQueue.EXPECT().Host().Return(host).AnyTimes()
+		hostQueue.EXPECT().ConnectionCount().
+			Return(opts.opts.MinConnectionCount()).Times(sessionTestShards)
+		hostQueue.EXPECT().BorrowConnection(gomock.Any()).
+			Do(func(fn WithConnectionFn) {
+				fn(client, &noopPooledChannel{})
+			}).Return(nil).AnyTimes()
+		hostQueue.EXPECT().Close()
+		return hostQueue, nil
+	}
+
+	require.NoError(t, session.Open())
+
+	stats, err := s.Cardinality(ident.StringID("metrics"), index.CardinalityQueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		MetricNameTag:  []byte("__name__"),
+		Limit:          2,
+	})
+	require.NoError(t, err)
+
+	// Each shard is counted on a single replica.
+	sort.Ints(shards)
+	require.Equal(t, []int{0, 1, 2}, shards)
+	require.Equal(t, index.CardinalityStats{
+		NumSeries: sessionTestShards,
+		SeriesCountByMetricName: []index.CardinalityStat{
+			{Name: "foo", Value: sessionTestShards},
+		},
+		LabelValueCountByLabelName: []index.CardinalityStat{
+			{Name: "__name__", Value: 1},
+		},
+		SeriesCountByLabelValuePair: []index.CardinalityStat{
+			{Name: "__name__=foo", Value: sessionTestShards},
+		},
+	}, stats)
+
+	require.NoError(t, session.Close())
+}
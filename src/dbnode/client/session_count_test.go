@@ -0,0 +1,166 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/hyperloglog"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+func TestSessionCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	var (
+		start = xtime.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+		end   = start.Add(time.Hour)
+		query = idx.NewTermQuery([]byte("__name__"), []byte("foo"))
+
+		lock   sync.Mutex
+		shards []int
+	)
+	session.newHostQueueFn = func(
+		host topology.Host,
+		opts hostQueueOpts,
+	) (hostQueue, error) {
+		client := rpc.NewMockTChanNode(ctrl)
+		client.EXPECT().
+			Count(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ thrift.Context,
+				req *rpc.CountRequest,
+			) (*rpc.CountResult_, error) {
+				require.Equal(t, []byte("metrics"), req.NameSpace)
+				require.Equal(t, int64(start), req.RangeStart)
+				require.Equal(t, int64(end), req.RangeEnd)
+				require.True(t, req.IncludeSketch)
+
+				q, err := idx.Unmarshal(req.Query)
+				require.NoError(t, err)
+				require.True(t, query.Equal(q))
+
+				lock.Lock()
+				for _, shard := range req.Shards {
+					shards = append(shards, int(shard))
+				}
+				lock.Unlock()
+
+				// Each shard holds two foo series.
+				sketch, err := hyperloglog.New(hyperloglog.DefaultPrecision)
+				require.NoError(t, err)
+				for _, shard := range req.Shards {
+					sketch.Add([]byte(fmt.Sprintf("foo{shard=\"%d\",a=\"b\"}", shard)))
+					sketch.Add([]byte(fmt.Sprintf("foo{shard=\"%d\",a=\"c\"}", shard)))
+				}
+				encoded, err := sketch.MarshalBinary()
+				require.NoError(t, err)
+				return &rpc.CountResult_{
+					Count:  int64(2 * len(req.Shards)),
+					Sketch: encoded,
+				}, nil
+			}).
+			AnyTimes()
+
+		hostQueue := NewMockhostQueue(ctrl)
+		hostQueue.EXPECT().Open()
+		hostQueue.EXPECT().Host().Return(host).AnyTimes()
+		hostQueue.EXPECT().ConnectionCount().
+			Return(opts.opts.MinConnectionCount()).Times(sessionTestShards)
+		hostQueue.EXPECT().BorrowConnection(gomock.Any()).
+			Do(func(fn WithConnectionFn) {
+				fn(client, &noopPooledChannel{})
+			}).Return(nil).AnyTimes()
+		hostQueue.EXPECT().Close()
+		return hostQueue, nil
+	}
+
+	require.NoError(t, session.Open())
+
+	result, err := s.Count(ident.StringID("metrics"), index.Query{Query: query},
+		index.CountQueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+			IncludeSketch:  true,
+		})
+	require.NoError(t, err)
+
+	// Each shard is counted on a single replica.
+	sort.Ints(shards)
+	require.Equal(t, []int{0, 1, 2}, shards)
+	require.Equal(t, int64(2*sessionTestShards), result.Count)
+	require.NotNil(t, result.Sketch)
+	require.Equal(t, uint64(2*sessionTestShards), result.Sketch.Estimate())
+
+	require.NoError(t, session.Close())
+}
+
+func TestSessionCountNoAvailableReplica(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		shardID = uint32(0)
+		topoMap = topology.NewMockMap(ctrl)
+		host    = topology.NewHost("h0", "local")
+	)
+	shardSet, err := sharding.NewShardSet(sharding.NewShards([]uint32{shardID},
+		shard.Initializing), sharding.DefaultHashFn(1))
+	require.NoError(t, err)
+
+	topoMap.EXPECT().ShardSet().Return(shardSet)
+	topoMap.EXPECT().RouteShardForEach(shardID, gomock.Any()).DoAndReturn(
+		func(shardID uint32, callback func(int, shard.Shard, topology.Host)) error {
+			callback(0, shard.NewShard(shardID).SetState(shard.Initializing), host)
+			return nil
+		})
+
+	s := session{}
+	s.state.status = statusOpen
+	s.state.topoMap = topoMap
+
+	_, err = s.Count(ident.StringID("metrics"),
+		index.Query{Query: idx.NewAllQuery()}, index.CountQueryOptions{})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrNoAvailableReplicaForShard))
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go/thrift"
+)
+
+func TestSessionDeleteTagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions()
+	s, err := newSession(opts)
+	require.NoError(t, err)
+	session := s.(*session)
+
+	var (
+		start = xtime.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+		end   = start.Add(time.Hour)
+		hosts int
+	)
+	session.newHostQueueFn = func(
+		host topology.Host,
+		opts hostQueueOpts,
+	) (hostQueue, error) {
+		hosts++
+		client := rpc.NewMockTChanNode(ctrl)
+		client.EXPECT().
+			DeleteTagged(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ thrift.Context,
+				req *rpc.DeleteTaggedRequest,
+			) (*rpc.DeleteTaggedResult_, error) {
+				require.Equal(t, []byte("metrics"), req.NameSpace)
+				require.Equal(t, int64(start), req.RangeStart)
+				require.Equal(t, int64(end), req.RangeEnd)
+				return &rpc.DeleteTaggedResult_{NumSeries: 5}, nil
+			})
+
+		hostQueue := NewMockhostQueue(ctrl)
+		hostQueue.EXPECT().Open()
+		hostQueue.EXPECT().Host().Return(host).AnyTimes()
+		hostQueue.EXPECT().ConnectionCount().
+			Return(opts.opts.MinConnectionCount()).Times(sessionTestShards)
+		hostQueue.EXPECT().BorrowConnection(gomock.Any()).
+			Do(func(fn WithConnectionFn) {
+				fn(client, &noopPooledChannel{})
+			}).Return(nil)
+		hostQueue.EXPECT().Close()
+		return hostQueue, nil
+	}
+
+	require.NoError(t, session.Open())
+
+	q := index.Query{Query: idx.NewTermQuery([]byte("foo"), []byte("bar"))}
+	n, err := s.DeleteTagged(ident.StringID("metrics"), q, start, end)
+	require.NoError(t, err)
+	// Each replica reports every series it deleted.
+	require.Equal(t, int64(5*hosts/sessionTestReplicas), n)
+
+	require.NoError(t, session.Close())
+}
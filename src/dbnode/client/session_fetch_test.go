@@ -554,9 +554,9 @@ func fulfillFetchBatchOps(
 					encoder.Encode(dp, value.unit, value.annotation)
 				}
 				seg := encoder.Discard()
-				op.completionFns[i]([]*rpc.Segments{{
+				op.completionFns[i](&rpc.FetchRawResult_{Segments: []*rpc.Segments{{
 					Merged: &rpc.Segment{Head: bytesIfNotNil(seg.Head), Tail: bytesIfNotNil(seg.Tail)},
-				}}, nil)
+				}}}, nil)
 				calledCompletionFn = true
 				break
 			}
@@ -22,6 +22,7 @@ package client
 
 import (
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
@@ -44,6 +45,7 @@ type sessionPools struct {
 	tagDecoder                  serialize.TagDecoderPool
 	readerSliceOfSlicesIterator *readerSliceOfSlicesIteratorPool
 	multiReaderIterator         encoding.MultiReaderIteratorPool
+	xorMultiReaderIterator      encoding.MultiReaderIteratorPool
 	seriesIterator              encoding.SeriesIteratorPool
 	seriesIterators             encoding.MutableSeriesIteratorsPool
 	writeAttempt                *writeAttemptPool
@@ -86,6 +88,10 @@ func (s sessionPools) MultiReaderIterator() encoding.MultiReaderIteratorPool {
 	return s.multiReaderIterator
 }
 
+func (s sessionPools) XORMultiReaderIterator() encoding.MultiReaderIteratorPool {
+	return s.xorMultiReaderIterator
+}
+
 func (s sessionPools) CheckedBytesWrapper() xpool.CheckedBytesWrapperPool {
 	return s.checkedBytesWrapper
 }
@@ -93,3 +99,15 @@ func (s sessionPools) CheckedBytesWrapper() xpool.CheckedBytesWrapperPool {
 func (s sessionPools) MutableSeriesIterators() encoding.MutableSeriesIteratorsPool {
 	return s.seriesIterators
 }
+
+// multiReaderIteratorPool returns the pool of iterators that decode segments
+// encoded with the encoding scheme.
+func multiReaderIteratorPool(
+	pools fetchTaggedPools,
+	scheme rpc.EncodingScheme,
+) encoding.MultiReaderIteratorPool {
+	if scheme == rpc.EncodingScheme_XOR {
+		return pools.XORMultiReaderIterator()
+	}
+	return pools.MultiReaderIterator()
+}
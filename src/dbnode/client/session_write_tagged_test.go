@@ -21,6 +21,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -32,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/topology"
 	xmetrics "github.com/m3db/m3/src/dbnode/x/metrics"
+	"github.com/m3db/m3/src/query/source"
 	"github.com/m3db/m3/src/x/checked"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/ident"
@@ -100,6 +102,36 @@ func TestSessionWriteTaggedEmptyTagValue(t *testing.T) {
 	require.NoError(t, s.Close())
 }
 
+func TestSessionWriteTaggedWithContextTenant(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	s := newDefaultTestSession(t).(*session)
+	var hosts []topology.Host
+
+	mockHostQueues(ctrl, s, sessionTestReplicas, []testEnqueueFn{func(idx int, op op) {
+		write, ok := op.(*writeTaggedOperation)
+		assert.True(t, ok)
+		assert.Equal(t, "foo", write.tenant)
+		go func() {
+			op.CompletionFn()(hosts[idx], nil)
+		}()
+	}})
+	assert.NoError(t, s.Open())
+
+	s.state.RLock()
+	hosts = s.state.topoMap.Hosts()
+	s.state.RUnlock()
+
+	ctx, err := source.NewContext(context.Background(), []byte("foo"), nil)
+	require.NoError(t, err)
+	err = s.WriteTaggedWithContext(ctx, ident.StringID("namespace"),
+		ident.StringID("foo"), ident.MustNewTagStringsIterator("a", "b"),
+		xtime.Now(), 1.337, xtime.Second, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+}
+
 func TestSessionWriteTagged(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
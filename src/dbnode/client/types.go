@@ -34,6 +34,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/x/clock"
@@ -92,6 +93,20 @@ type Session interface {
 		annotation []byte,
 	) error
 
+	// WriteTaggedWithContext is the same as WriteTagged, however the write is
+	// attributed to the source of the context, if any, which nodes use as the
+	// tenant of the write to enforce per-tenant write limits.
+	WriteTaggedWithContext(
+		ctx gocontext.Context,
+		namespace,
+		id ident.ID,
+		tags ident.TagIterator,
+		t xtime.UnixNano,
+		value float64,
+		unit xtime.Unit,
+		annotation []byte,
+	) error
+
 	// Fetch values from the database for an ID.
 	Fetch(
 		namespace,
@@ -132,6 +147,47 @@ type Session interface {
 		opts index.AggregationOptions,
 	) (AggregatedTagsIterator, FetchResponseMetadata, error)
 
+	// WriteExemplars writes exemplars for a set of series to the in-memory
+	// exemplar store of each replica owning the series.
+	WriteExemplars(namespace ident.ID, series []SeriesExemplars) error
+
+	// FetchExemplars fetches exemplars for a set of series IDs with timestamps
+	// within the inclusive range [start, end], series without exemplars are
+	// omitted from the result.
+	FetchExemplars(
+		namespace ident.ID,
+		ids []ident.ID,
+		start,
+		end xtime.UnixNano,
+	) ([]SeriesExemplars, error)
+
+	// DeleteTagged deletes the data of the series matching the query within
+	// the range [start, end) on every replica, returning the number of
+	// series deleted.
+	DeleteTagged(
+		namespace ident.ID,
+		q index.Query,
+		start,
+		end xtime.UnixNano,
+	) (int64, error)
+
+	// Cardinality returns the top cardinality statistics of the series of a
+	// namespace within a time range, counting each shard on a single
+	// available replica.
+	Cardinality(
+		namespace ident.ID,
+		opts index.CardinalityQueryOptions,
+	) (index.CardinalityStats, error)
+
+	// Count returns the number of series of a namespace within a time range
+	// matching a query from the index only, counting each shard on a single
+	// available replica.
+	Count(
+		namespace ident.ID,
+		q index.Query,
+		opts index.CountQueryOptions,
+	) (index.CountResult, error)
+
 	// ShardID returns the given shard for an ID for callers
 	// to easily discern what shard is failing when operations
 	// for given IDs begin failing.
@@ -144,6 +200,12 @@ type Session interface {
 	Close() error
 }
 
+// SeriesExemplars are the exemplars of a series.
+type SeriesExemplars struct {
+	ID        ident.ID
+	Exemplars []exemplar.Exemplar
+}
+
 // FetchResponseMetadata is metadata about a fetch response.
 type FetchResponseMetadata struct {
 	// Exhaustive indicates whether the underlying data set presents a full
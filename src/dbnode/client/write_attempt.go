@@ -55,6 +55,7 @@ type writeAttemptArgs struct {
 	value       float64
 	annotation  []byte
 	unit        xtime.Unit
+	tenant      string
 	attemptType writeAttemptType
 }
 
@@ -65,7 +66,7 @@ func (w *writeAttempt) reset() {
 func (w *writeAttempt) perform() error {
 	err := w.session.writeAttempt(w.args.attemptType,
 		w.args.namespace, w.args.id, w.args.tags, w.args.t,
-		w.args.value, w.args.unit, w.args.annotation)
+		w.args.value, w.args.unit, w.args.annotation, w.args.tenant)
 
 	if IsBadRequestError(err) {
 		// Do not retry bad request errors
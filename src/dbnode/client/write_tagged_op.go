@@ -35,6 +35,7 @@ var (
 
 type writeTaggedOperation struct {
 	namespace    ident.ID
+	tenant       string
 	shardID      uint32
 	request      rpc.WriteTaggedBatchRawRequestElement
 	requestV2    rpc.WriteTaggedBatchRawV2RequestElement
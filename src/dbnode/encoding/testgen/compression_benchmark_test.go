@@ -0,0 +1,151 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testgen
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The benchmarks of this file compare the compression ratio and the speed of
+// the m3tsz and the Prometheus compatible xor encodings, run them with:
+//   go test -run none -bench . ./src/dbnode/encoding/testgen
+
+const benchmarkNumPoints = 720 // 2h of 10s resolution datapoints.
+
+var benchmarkStart = xtime.FromSeconds(1427162400)
+
+type benchmarkScheme struct {
+	name       string
+	newEncoder func() encoding.Encoder
+	newReader  func() encoding.ReaderIterator
+}
+
+func benchmarkSchemes() []benchmarkScheme {
+	opts := encoding.NewOptions()
+	return []benchmarkScheme{
+		{
+			name: "m3tsz",
+			newEncoder: func() encoding.Encoder {
+				return m3tsz.NewEncoder(benchmarkStart, nil, m3tsz.DefaultIntOptimizationEnabled, opts)
+			},
+			newReader: func() encoding.ReaderIterator {
+				return m3tsz.NewReaderIterator(nil, m3tsz.DefaultIntOptimizationEnabled, opts)
+			},
+		},
+		{
+			name: "xor",
+			newEncoder: func() encoding.Encoder {
+				return xor.NewEncoder(benchmarkStart, nil, opts)
+			},
+			newReader: func() encoding.ReaderIterator {
+				return xor.NewReaderIterator(nil, opts)
+			},
+		},
+	}
+}
+
+func benchmarkDatapoints(seriesType SeriesType) []ts.Datapoint {
+	r := rand.New(rand.NewSource(int64(benchmarkStart)))
+	// NB: millisecond jitter is representable by both encodings.
+	return GenerateDatapoints(r, seriesType, benchmarkStart,
+		10*time.Second, time.Second, benchmarkNumPoints)
+}
+
+func encodeDatapoints(b *testing.B, enc encoding.Encoder, dps []ts.Datapoint) []byte {
+	for _, dp := range dps {
+		require.NoError(b, enc.Encode(dp, xtime.Millisecond, nil))
+	}
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	stream, ok := enc.Stream(ctx)
+	require.True(b, ok)
+	segment, err := stream.Segment()
+	require.NoError(b, err)
+
+	var data []byte
+	if segment.Head != nil {
+		data = append(data, segment.Head.Bytes()...)
+	}
+	if segment.Tail != nil {
+		data = append(data, segment.Tail.Bytes()...)
+	}
+	return data
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, scheme := range benchmarkSchemes() {
+		for _, seriesType := range SeriesTypes {
+			scheme, dps := scheme, benchmarkDatapoints(seriesType)
+			b.Run(fmt.Sprintf("%s/%s", scheme.name, seriesType), func(b *testing.B) {
+				b.ReportAllocs()
+				var size int
+				for i := 0; i < b.N; i++ {
+					size = len(encodeDatapoints(b, scheme.newEncoder(), dps))
+				}
+				b.ReportMetric(float64(size)/float64(len(dps)), "bytes/dp")
+			})
+		}
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, scheme := range benchmarkSchemes() {
+		for _, seriesType := range SeriesTypes {
+			var (
+				scheme = scheme
+				dps    = benchmarkDatapoints(seriesType)
+				data   = encodeDatapoints(b, scheme.newEncoder(), dps)
+				reader = xio.NewBytesReader64(nil)
+				iter   = scheme.newReader()
+			)
+			b.Run(fmt.Sprintf("%s/%s", scheme.name, seriesType), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+				for i := 0; i < b.N; i++ {
+					reader.Reset(data)
+					iter.Reset(reader, nil)
+					var n int
+					for iter.Next() {
+						n++
+					}
+					require.NoError(b, iter.Err())
+					require.Equal(b, len(dps), n)
+				}
+				b.ReportMetric(float64(len(data))/float64(len(dps)), "bytes/dp")
+			})
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testgen
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// SeriesType is a type of series to generate datapoints for.
+type SeriesType int
+
+const (
+	// CounterSeries is a monotonically increasing series.
+	CounterSeries SeriesType = iota
+	// GaugeSeries is a series of small floats with a few decimal places.
+	GaugeSeries
+	// PreciseGaugeSeries is a series of floats with many decimal places.
+	PreciseGaugeSeries
+	// ConstantSeries is a series with a single repeated value.
+	ConstantSeries
+)
+
+// SeriesTypes are all the types of series that can be generated.
+var SeriesTypes = []SeriesType{
+	CounterSeries,
+	GaugeSeries,
+	PreciseGaugeSeries,
+	ConstantSeries,
+}
+
+func (t SeriesType) String() string {
+	switch t {
+	case CounterSeries:
+		return "counter"
+	case GaugeSeries:
+		return "gauge"
+	case PreciseGaugeSeries:
+		return "precise_gauge"
+	case ConstantSeries:
+		return "constant"
+	}
+	return "unknown"
+}
+
+// GenerateDatapoints generates numPoints datapoints of the given series type
+// starting at start, spaced by step with up to jitter added to each timestamp,
+// the jitter must not exceed the step so that timestamps remain ordered.
+func GenerateDatapoints(
+	r *rand.Rand,
+	seriesType SeriesType,
+	start xtime.UnixNano,
+	step time.Duration,
+	jitter time.Duration,
+	numPoints int,
+) []ts.Datapoint {
+	dps := make([]ts.Datapoint, 0, numPoints)
+	value := GenerateFloatVal(r, 3, 0)
+	for i := 0; i < numPoints; i++ {
+		t := start.Add(time.Duration(i) * step)
+		if jitter > 0 {
+			t = t.Add(time.Duration(r.Int63n(int64(jitter))))
+		}
+
+		switch seriesType {
+		case CounterSeries:
+			value += float64(r.Intn(100))
+		case GaugeSeries:
+			value = GenerateFloatVal(r, 3, 2)
+		case PreciseGaugeSeries:
+			value = GenerateFloatVal(r, 5, 16)
+		}
+
+		dps = append(dps, ts.Datapoint{TimestampNanos: t, Value: value})
+	}
+	return dps
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// encoder is an encoder that can encode a stream of data in the Prometheus
+// XOR chunk format.
+type encoder struct {
+	os   encoding.OStream
+	opts encoding.Options
+
+	// internal bookkeeping
+	prevTime      int64  // previous timestamp in milliseconds
+	prevTimeDelta uint64 // previous timestamp delta in milliseconds
+	prevValue     float64
+	leading       uint8
+	trailing      uint8
+
+	// lastByteWrite is whether the last write was a whole byte, in which case
+	// Prometheus leaves an empty trailing byte if the stream is byte aligned.
+	lastByteWrite bool
+
+	numEncoded uint32
+	closed     bool
+}
+
+// NewEncoder creates a new encoder, the start time is not encoded as chunks
+// of the Prometheus XOR format are self-describing.
+func NewEncoder(
+	start xtime.UnixNano,
+	bytes checked.Bytes,
+	opts encoding.Options,
+) encoding.Encoder {
+	if opts == nil {
+		opts = encoding.NewOptions()
+	}
+	// NB: only perform an initial allocation if there is no pool that
+	// will be used for this encoder. If a pool is being used alloc when the
+	// `Reset` method is called.
+	initAllocIfEmpty := opts.EncoderPool() == nil
+	return &encoder{
+		os:      encoding.NewOStream(bytes, initAllocIfEmpty, opts.BytesPool()),
+		opts:    opts,
+		leading: math.MaxUint8,
+	}
+}
+
+func (enc *encoder) SetSchema(descr namespace.SchemaDescr) {}
+
+// Encode encodes the timestamp and the value of a datapoint, the timestamp is
+// truncated to milliseconds and the annotation is dropped.
+func (enc *encoder) Encode(dp ts.Datapoint, _ xtime.Unit, _ ts.Annotation) error {
+	if enc.closed {
+		return errEncoderClosed
+	}
+	if enc.numEncoded >= MaxSamples {
+		return errTooManySamples
+	}
+
+	t := toMillis(int64(dp.TimestampNanos))
+	if enc.numEncoded > 0 && t < enc.prevTime {
+		return fmt.Errorf(
+			"xor encoder received out of order datapoint: prev=%d, curr=%d",
+			enc.prevTime, t)
+	}
+
+	var timeDelta uint64
+	switch enc.numEncoded {
+	case 0:
+		// Reserve the header which is updated in place on every encode.
+		enc.writeBits(0, 8*HeaderSize)
+		enc.writeVarint(t)
+		enc.writeBits(math.Float64bits(dp.Value), 64)
+	case 1:
+		timeDelta = uint64(t - enc.prevTime)
+		enc.writeUvarint(timeDelta)
+		enc.writeValueDelta(dp.Value)
+	default:
+		timeDelta = uint64(t - enc.prevTime)
+		enc.writeDeltaOfDelta(int64(timeDelta - enc.prevTimeDelta))
+		enc.writeValueDelta(dp.Value)
+	}
+
+	enc.prevTime = t
+	enc.prevTimeDelta = timeDelta
+	enc.prevValue = dp.Value
+	enc.numEncoded++
+
+	raw, _ := enc.os.RawBytes()
+	binary.BigEndian.PutUint16(raw, uint16(enc.numEncoded))
+	return nil
+}
+
+func (enc *encoder) writeBit(v encoding.Bit) {
+	enc.os.WriteBit(v)
+	enc.lastByteWrite = false
+}
+
+func (enc *encoder) writeByte(v byte) {
+	enc.os.WriteByte(v)
+	enc.lastByteWrite = true
+}
+
+func (enc *encoder) writeBits(v uint64, numBits int) {
+	enc.os.WriteBits(v, numBits)
+	enc.lastByteWrite = numBits%8 == 0
+}
+
+func (enc *encoder) writeVarint(v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	for _, b := range buf[:binary.PutVarint(buf[:], v)] {
+		enc.writeByte(b)
+	}
+}
+
+func (enc *encoder) writeUvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	for _, b := range buf[:binary.PutUvarint(buf[:], v)] {
+		enc.writeByte(b)
+	}
+}
+
+// writeDeltaOfDelta writes the delta-of-delta of timestamps using value
+// ranges suited to millisecond resolution, as Prometheus does.
+func (enc *encoder) writeDeltaOfDelta(dod int64) {
+	switch {
+	case dod == 0:
+		enc.writeBit(0)
+	case bitRange(dod, 14):
+		enc.writeBits(0x02, 2)
+		enc.writeBits(uint64(dod), 14)
+	case bitRange(dod, 17):
+		enc.writeBits(0x06, 3)
+		enc.writeBits(uint64(dod), 17)
+	case bitRange(dod, 20):
+		enc.writeBits(0x0e, 4)
+		enc.writeBits(uint64(dod), 20)
+	default:
+		enc.writeBits(0x0f, 4)
+		enc.writeBits(uint64(dod), 64)
+	}
+}
+
+// writeValueDelta writes the XOR of the value with the previous value.
+func (enc *encoder) writeValueDelta(v float64) {
+	delta := math.Float64bits(v) ^ math.Float64bits(enc.prevValue)
+	if delta == 0 {
+		enc.writeBit(0)
+		return
+	}
+	enc.writeBit(1)
+
+	leading := uint8(bits.LeadingZeros64(delta))
+	trailing := uint8(bits.TrailingZeros64(delta))
+	// Clamp the number of leading zeros so that it fits in 5 bits.
+	if leading >= 32 {
+		leading = 31
+	}
+
+	if enc.leading != math.MaxUint8 && leading >= enc.leading && trailing >= enc.trailing {
+		enc.writeBit(0)
+		enc.writeBits(delta>>enc.trailing, 64-int(enc.leading)-int(enc.trailing))
+		return
+	}
+
+	enc.leading, enc.trailing = leading, trailing
+	enc.writeBit(1)
+	enc.writeBits(uint64(leading), 5)
+	// NB: 64 significant bits do not fit in 6 bits and are written as 0, which
+	// is never ambiguous since a zero delta is encoded with a single bit.
+	sigBits := 64 - leading - trailing
+	enc.writeBits(uint64(sigBits), 6)
+	enc.writeBits(delta>>trailing, int(sigBits))
+}
+
+func (enc *encoder) newBuffer(capacity int) checked.Bytes {
+	if bytesPool := enc.opts.BytesPool(); bytesPool != nil {
+		return bytesPool.Get(capacity)
+	}
+	return checked.NewBytes(make([]byte, 0, capacity), nil)
+}
+
+// Reset resets the encoder for reuse.
+func (enc *encoder) Reset(
+	start xtime.UnixNano,
+	capacity int,
+	schema namespace.SchemaDescr,
+) {
+	enc.reset(enc.newBuffer(capacity))
+}
+
+func (enc *encoder) reset(bytes checked.Bytes) {
+	enc.os.Reset(bytes)
+	enc.prevTime = 0
+	enc.prevTimeDelta = 0
+	enc.prevValue = 0
+	enc.leading = math.MaxUint8
+	enc.trailing = 0
+	enc.lastByteWrite = false
+	enc.numEncoded = 0
+	enc.closed = false
+}
+
+// Stream returns a copy of the underlying data stream, the data is always
+// copied since the header of the chunk is updated in place.
+func (enc *encoder) Stream(_ context.Context) (xio.SegmentReader, bool) {
+	segment := enc.segmentCopy()
+	if segment.Len() == 0 {
+		return nil, false
+	}
+	if readerPool := enc.opts.SegmentReaderPool(); readerPool != nil {
+		reader := readerPool.Get()
+		reader.Reset(segment)
+		return reader, true
+	}
+	return xio.NewSegmentReader(segment), true
+}
+
+// NumEncoded returns the number of encoded datapoints.
+func (enc *encoder) NumEncoded() int {
+	return int(enc.numEncoded)
+}
+
+// LastEncoded returns the last encoded datapoint.
+func (enc *encoder) LastEncoded() (ts.Datapoint, error) {
+	if enc.numEncoded == 0 {
+		return ts.Datapoint{}, errNoEncodedDatapoints
+	}
+	return ts.Datapoint{
+		TimestampNanos: xtime.UnixNano(fromMillis(enc.prevTime)),
+		Value:          enc.prevValue,
+	}, nil
+}
+
+// LastAnnotationChecksum returns zero since annotations are not encoded.
+func (enc *encoder) LastAnnotationChecksum() (uint64, error) {
+	if enc.numEncoded == 0 {
+		return 0, errNoEncodedDatapoints
+	}
+	return 0, nil
+}
+
+// Len returns the length of the final data stream that would be generated
+// by a call to Stream().
+func (enc *encoder) Len() int {
+	if enc.padded() {
+		return enc.os.Len() + 1
+	}
+	return enc.os.Len()
+}
+
+// padded returns whether the stream requires an empty trailing byte to be
+// identical to the chunk Prometheus would produce for the same samples.
+func (enc *encoder) padded() bool {
+	_, pos := enc.os.RawBytes()
+	return enc.lastByteWrite && pos == 8
+}
+
+// Close closes the encoder.
+func (enc *encoder) Close() {
+	if enc.closed {
+		return
+	}
+
+	enc.closed = true
+
+	// Ensure to free ref to ostream bytes
+	enc.os.Reset(nil)
+
+	if pool := enc.opts.EncoderPool(); pool != nil {
+		pool.Put(enc)
+	}
+}
+
+// Discard closes the encoder and transfers ownership of the data stream to
+// the caller.
+func (enc *encoder) Discard() ts.Segment {
+	segment := enc.segmentTakeOwnership()
+
+	// Close the encoder no longer needed
+	enc.Close()
+
+	return segment
+}
+
+// DiscardReset does the same thing as Discard except it does not close the encoder but resets it for reuse.
+func (enc *encoder) DiscardReset(
+	start xtime.UnixNano,
+	capacity int,
+	descr namespace.SchemaDescr,
+) ts.Segment {
+	segment := enc.segmentTakeOwnership()
+	enc.Reset(start, capacity, descr)
+	return segment
+}
+
+func (enc *encoder) segmentCopy() ts.Segment {
+	rawBuffer, _ := enc.os.RawBytes()
+	if len(rawBuffer) == 0 {
+		return ts.Segment{}
+	}
+
+	head := enc.newBuffer(enc.Len())
+	head.IncRef()
+	head.AppendAll(rawBuffer)
+	if enc.padded() {
+		head.Append(0)
+	}
+	head.DecRef()
+
+	return ts.NewSegment(head, nil, 0, ts.FinalizeHead)
+}
+
+func (enc *encoder) segmentTakeOwnership() ts.Segment {
+	if enc.os.Len() == 0 {
+		return ts.Segment{}
+	}
+	if enc.padded() {
+		enc.os.WriteByte(0)
+	}
+
+	// NB: the chunk is complete as is since the header holds the number of
+	// samples, so no tail is required.
+	return ts.NewSegment(enc.os.Discard(), nil, 0, ts.FinalizeHead)
+}
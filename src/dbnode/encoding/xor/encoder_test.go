@@ -0,0 +1,230 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xor
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/stretchr/testify/require"
+)
+
+var testStartTime = xtime.FromSeconds(1427162400)
+
+func getTestEncoder() *encoder {
+	return NewEncoder(testStartTime, nil, nil).(*encoder)
+}
+
+func testDatapoints(seed int64, n int) []ts.Datapoint {
+	var (
+		r   = rand.New(rand.NewSource(seed))
+		t   = testStartTime
+		v   = 100.0
+		dps = make([]ts.Datapoint, 0, n)
+	)
+	for i := 0; i < n; i++ {
+		switch r.Intn(4) {
+		case 0:
+			// Regular interval.
+			t = t.Add(10 * time.Second)
+		case 1:
+			// Jitter.
+			t = t.Add(10*time.Second + time.Duration(r.Intn(2000)-1000)*time.Millisecond)
+		case 2:
+			// Large gaps exercising the widest delta-of-delta ranges.
+			t = t.Add(time.Duration(r.Intn(1<<22)) * time.Millisecond)
+		default:
+			t = t.Add(time.Millisecond)
+		}
+		switch r.Intn(3) {
+		case 0:
+			// Repeated value.
+		case 1:
+			v += float64(r.Intn(10))
+		default:
+			v = r.NormFloat64() * 1e6
+		}
+		dps = append(dps, ts.Datapoint{TimestampNanos: t, Value: v})
+	}
+	return dps
+}
+
+func encodeWithPrometheus(t *testing.T, dps []ts.Datapoint) []byte {
+	chunk := chunkenc.NewXORChunk()
+	app, err := chunk.Appender()
+	require.NoError(t, err)
+	for _, dp := range dps {
+		app.Append(toMillis(int64(dp.TimestampNanos)), dp.Value)
+	}
+	return chunk.Bytes()
+}
+
+func encodedBytes(t *testing.T, enc encoding.Encoder) []byte {
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	stream, ok := enc.Stream(ctx)
+	require.True(t, ok)
+	segment, err := stream.Segment()
+	require.NoError(t, err)
+	return append([]byte(nil), segment.Head.Bytes()...)
+}
+
+func TestEncoderMatchesPrometheus(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		dps := testDatapoints(seed, 1000)
+		enc := getTestEncoder()
+		for _, dp := range dps {
+			require.NoError(t, enc.Encode(dp, xtime.Nanosecond, nil))
+		}
+		require.Equal(t, len(dps), enc.NumEncoded())
+		require.Equal(t, encodeWithPrometheus(t, dps), encodedBytes(t, enc))
+		require.Equal(t, enc.Len(), len(encodedBytes(t, enc)))
+	}
+}
+
+func TestEncoderSpecialValuesMatchPrometheus(t *testing.T) {
+	values := []float64{
+		0, math.NaN(), math.Inf(1), math.Inf(-1), -0.5, math.MaxFloat64,
+		math.SmallestNonzeroFloat64, 1, 1, 0x1p-1074, -math.MaxFloat64,
+	}
+	dps := make([]ts.Datapoint, 0, len(values))
+	for i, v := range values {
+		dps = append(dps, ts.Datapoint{
+			TimestampNanos: testStartTime.Add(time.Duration(i) * time.Minute),
+			Value:          v,
+		})
+	}
+
+	enc := getTestEncoder()
+	for _, dp := range dps {
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+	require.Equal(t, encodeWithPrometheus(t, dps), encodedBytes(t, enc))
+}
+
+func TestEncoderLastEncoded(t *testing.T) {
+	enc := getTestEncoder()
+	_, err := enc.LastEncoded()
+	require.Equal(t, errNoEncodedDatapoints, err)
+	_, err = enc.LastAnnotationChecksum()
+	require.Equal(t, errNoEncodedDatapoints, err)
+
+	// Timestamps are truncated to milliseconds and annotations are dropped.
+	dp := ts.Datapoint{TimestampNanos: testStartTime.Add(time.Millisecond + 1), Value: 42}
+	require.NoError(t, enc.Encode(dp, xtime.Nanosecond, ts.Annotation("foo")))
+
+	last, err := enc.LastEncoded()
+	require.NoError(t, err)
+	require.Equal(t, ts.Datapoint{TimestampNanos: testStartTime.Add(time.Millisecond), Value: 42}, last)
+	checksum, err := enc.LastAnnotationChecksum()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), checksum)
+}
+
+func TestEncoderOutOfOrder(t *testing.T) {
+	enc := getTestEncoder()
+	require.NoError(t, enc.Encode(ts.Datapoint{TimestampNanos: testStartTime}, xtime.Second, nil))
+	require.Error(t, enc.Encode(ts.Datapoint{TimestampNanos: testStartTime.Add(-time.Second)}, xtime.Second, nil))
+	require.Equal(t, 1, enc.NumEncoded())
+}
+
+func TestEncoderTooManySamples(t *testing.T) {
+	enc := getTestEncoder()
+	for i := 0; i < MaxSamples; i++ {
+		dp := ts.Datapoint{TimestampNanos: testStartTime.Add(time.Duration(i) * time.Second)}
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+	dp := ts.Datapoint{TimestampNanos: testStartTime.Add(time.Duration(MaxSamples) * time.Second)}
+	require.Equal(t, errTooManySamples, enc.Encode(dp, xtime.Second, nil))
+}
+
+func TestEncoderStreamEmpty(t *testing.T) {
+	enc := getTestEncoder()
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	_, ok := enc.Stream(ctx)
+	require.False(t, ok)
+	require.Equal(t, 0, enc.Len())
+}
+
+func TestEncoderStreamIsSnapshot(t *testing.T) {
+	enc := getTestEncoder()
+	require.NoError(t, enc.Encode(ts.Datapoint{TimestampNanos: testStartTime, Value: 1}, xtime.Second, nil))
+	snapshot := encodedBytes(t, enc)
+
+	require.NoError(t, enc.Encode(ts.Datapoint{TimestampNanos: testStartTime.Add(time.Second), Value: 2}, xtime.Second, nil))
+	require.Equal(t, uint8(1), snapshot[1])
+	require.Equal(t, uint8(2), encodedBytes(t, enc)[1])
+}
+
+func TestEncoderDiscardReset(t *testing.T) {
+	enc := getTestEncoder()
+	dps := testDatapoints(1, 100)
+	for _, dp := range dps {
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+
+	segment := enc.DiscardReset(testStartTime, 0, nil)
+	require.Equal(t, encodeWithPrometheus(t, dps), segment.Head.Bytes())
+	require.Nil(t, segment.Tail)
+	require.Equal(t, 0, enc.NumEncoded())
+
+	// The reset encoder encodes from scratch.
+	for _, dp := range dps[:10] {
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+	require.Equal(t, encodeWithPrometheus(t, dps[:10]), enc.Discard().Head.Bytes())
+
+	require.Equal(t, errEncoderClosed, enc.Encode(dps[0], xtime.Second, nil))
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	dps := testDatapoints(2, 5000)
+	enc := getTestEncoder()
+	for _, dp := range dps {
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+
+	it := NewReaderIterator(xio.NewBytesReader64(encodedBytes(t, enc)), encoding.NewOptions())
+	defer it.Close()
+
+	var i int
+	for ; it.Next(); i++ {
+		dp, unit, annotation := it.Current()
+		require.Equal(t, xtime.Millisecond, unit)
+		require.Nil(t, annotation)
+		require.Equal(t, dps[i].TimestampNanos, dp.TimestampNanos)
+		require.Equal(t, math.Float64bits(dps[i].Value), math.Float64bits(dp.Value))
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, len(dps), i)
+}
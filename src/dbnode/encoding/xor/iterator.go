@@ -0,0 +1,237 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xor
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// readerIterator provides an interface for clients to incrementally
+// read datapoints off of a stream encoded in the Prometheus XOR chunk format.
+type readerIterator struct {
+	is   *encoding.IStream
+	opts encoding.Options
+
+	err error // current error
+
+	// internal bookkeeping
+	time      int64  // current timestamp in milliseconds
+	timeDelta uint64 // current timestamp delta in milliseconds
+	value     float64
+	leading   uint8
+	trailing  uint8
+
+	numRead  uint16
+	numTotal uint16
+
+	headerRead bool
+	closed     bool
+}
+
+// NewReaderIterator returns a new iterator for a given reader.
+func NewReaderIterator(
+	reader xio.Reader64,
+	opts encoding.Options,
+) encoding.ReaderIterator {
+	return &readerIterator{
+		is:   encoding.NewIStream(reader),
+		opts: opts,
+	}
+}
+
+// Next moves to the next item.
+func (it *readerIterator) Next() bool {
+	if !it.hasNext() {
+		return false
+	}
+
+	switch it.numRead {
+	case 0:
+		t, err := binary.ReadVarint(it.is)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.time = t
+		it.value = math.Float64frombits(it.readBits(64))
+	case 1:
+		timeDelta, err := binary.ReadUvarint(it.is)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.timeDelta = timeDelta
+		it.time += int64(timeDelta)
+		it.readValueDelta()
+	default:
+		it.timeDelta = uint64(int64(it.timeDelta) + it.readDeltaOfDelta())
+		it.time += int64(it.timeDelta)
+		it.readValueDelta()
+	}
+
+	if it.hasError() {
+		return false
+	}
+	it.numRead++
+	return true
+}
+
+func (it *readerIterator) readHeader() bool {
+	it.headerRead = true
+	numTotal, err := it.is.ReadBits(8 * HeaderSize)
+	if err == io.EOF {
+		// An empty stream holds no samples.
+		return false
+	}
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.numTotal = uint16(numTotal)
+	return true
+}
+
+func (it *readerIterator) readDeltaOfDelta() int64 {
+	// Read up to four bits of the '0', '10', '110', '1110' or '1111' prefix.
+	var prefix uint8
+	for i := 0; i < 4; i++ {
+		prefix <<= 1
+		if it.readBits(1) == 0 {
+			break
+		}
+		prefix |= 1
+	}
+
+	var size uint8
+	switch prefix {
+	case 0x00:
+		return 0
+	case 0x02:
+		size = 14
+	case 0x06:
+		size = 17
+	case 0x0e:
+		size = 20
+	default:
+		return int64(it.readBits(64))
+	}
+
+	bits := it.readBits(size)
+	if bits > 1<<(size-1) {
+		bits -= 1 << size
+	}
+	return int64(bits)
+}
+
+func (it *readerIterator) readValueDelta() {
+	if it.readBits(1) == 0 {
+		// Value is repeated.
+		return
+	}
+
+	if it.readBits(1) == 1 {
+		it.leading = uint8(it.readBits(5))
+		sigBits := uint8(it.readBits(6))
+		// NB: 64 significant bits are written as 0, see the encoder.
+		if sigBits == 0 {
+			sigBits = 64
+		}
+		it.trailing = 64 - it.leading - sigBits
+	}
+
+	sigBits := 64 - it.leading - it.trailing
+	delta := it.readBits(sigBits) << it.trailing
+	it.value = math.Float64frombits(math.Float64bits(it.value) ^ delta)
+}
+
+func (it *readerIterator) readBits(numBits uint8) uint64 {
+	if it.hasError() {
+		return 0
+	}
+	var res uint64
+	res, it.err = it.is.ReadBits(numBits)
+	return res
+}
+
+// Current returns the value of the current datapoint, timestamps are
+// in milliseconds and annotations are never set.
+func (it *readerIterator) Current() (ts.Datapoint, xtime.Unit, ts.Annotation) {
+	return ts.Datapoint{
+		TimestampNanos: xtime.UnixNano(fromMillis(it.time)),
+		Value:          it.value,
+	}, xtime.Millisecond, nil
+}
+
+// Err returns the error encountered.
+func (it *readerIterator) Err() error {
+	return it.err
+}
+
+func (it *readerIterator) hasError() bool {
+	return it.err != nil
+}
+
+func (it *readerIterator) hasNext() bool {
+	if it.hasError() {
+		return false
+	}
+	if !it.headerRead && !it.readHeader() {
+		return false
+	}
+	return it.numRead < it.numTotal
+}
+
+// Reset resets the ReadIterator for reuse.
+func (it *readerIterator) Reset(reader xio.Reader64, schema namespace.SchemaDescr) {
+	it.is.Reset(reader)
+	it.err = nil
+	it.time = 0
+	it.timeDelta = 0
+	it.value = 0
+	it.leading = 0
+	it.trailing = 0
+	it.numRead = 0
+	it.numTotal = 0
+	it.headerRead = false
+	it.closed = false
+}
+
+// Close closes the ReaderIterator.
+func (it *readerIterator) Close() {
+	if it.closed {
+		return
+	}
+
+	it.closed = true
+	it.err = errIteratorClosed
+	pool := it.opts.ReaderIteratorPool()
+	if pool != nil {
+		pool.Put(it)
+	}
+}
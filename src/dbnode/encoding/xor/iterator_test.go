@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xor
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, it encoding.ReaderIterator) []ts.Datapoint {
+	var dps []ts.Datapoint
+	for it.Next() {
+		dp, unit, annotation := it.Current()
+		require.Equal(t, xtime.Millisecond, unit)
+		require.Nil(t, annotation)
+		dps = append(dps, dp)
+	}
+	require.NoError(t, it.Err())
+	return dps
+}
+
+func requireDatapointsEqual(t *testing.T, expected, actual []ts.Datapoint) {
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		require.Equal(t, expected[i].TimestampNanos, actual[i].TimestampNanos)
+		require.Equal(t, math.Float64bits(expected[i].Value), math.Float64bits(actual[i].Value))
+	}
+}
+
+func TestReaderIteratorReadsPrometheusChunks(t *testing.T) {
+	it := NewReaderIterator(nil, encoding.NewOptions())
+	for seed := int64(0); seed < 20; seed++ {
+		dps := testDatapoints(seed, 1000)
+		it.Reset(xio.NewBytesReader64(encodeWithPrometheus(t, dps)), nil)
+		requireDatapointsEqual(t, dps, readAll(t, it))
+	}
+}
+
+func TestReaderIteratorEmptyStream(t *testing.T) {
+	it := NewReaderIterator(xio.NewBytesReader64(nil), encoding.NewOptions())
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+
+	it.Reset(xio.NewBytesReader64(encodeWithPrometheus(t, nil)), nil)
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
+
+func TestReaderIteratorTruncatedStream(t *testing.T) {
+	data := encodeWithPrometheus(t, testDatapoints(0, 100))
+	it := NewReaderIterator(xio.NewBytesReader64(data[:len(data)/2]), encoding.NewOptions())
+
+	var n int
+	for it.Next() {
+		n++
+	}
+	require.Equal(t, io.EOF, it.Err())
+	require.True(t, n > 0 && n < 100)
+}
+
+func TestReaderIteratorClose(t *testing.T) {
+	var (
+		pool = encoding.NewReaderIteratorPool(nil)
+		opts = encoding.NewOptions().SetReaderIteratorPool(pool)
+	)
+	pool.Init(DefaultReaderIteratorAllocFn(opts))
+
+	dps := testDatapoints(0, 10)
+	it := pool.Get()
+	it.Reset(xio.NewBytesReader64(encodeWithPrometheus(t, dps)), nil)
+	require.True(t, it.Next())
+
+	it.Close()
+	require.False(t, it.Next())
+	require.Equal(t, errIteratorClosed, it.Err())
+
+	// The iterator is returned to the pool and can be reused.
+	it = pool.Get()
+	it.Reset(xio.NewBytesReader64(encodeWithPrometheus(t, dps)), nil)
+	requireDatapointsEqual(t, dps, readAll(t, it))
+}
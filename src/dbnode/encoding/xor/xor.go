@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package xor implements an encoding scheme that is bit-compatible with the
+// XOR chunk format of Prometheus TSDB blocks, i.e. a big-endian uint16 count
+// of the samples followed by the Gorilla style delta-of-delta encoded
+// timestamps and XOR encoded values of the samples, so that the data of
+// filesets can be exchanged with Prometheus TSDB blocks without re-encoding.
+//
+// Since Prometheus samples are made of millisecond timestamps and float64
+// values only, timestamps are truncated to millisecond precision and
+// annotations are not persisted.
+package xor
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+)
+
+const (
+	// HeaderSize is the size of the header of a chunk which holds the
+	// number of samples of the chunk.
+	HeaderSize = 2
+
+	// MaxSamples is the maximum number of samples of a chunk.
+	MaxSamples = math.MaxUint16
+)
+
+var (
+	errEncoderClosed       = errors.New("encoder is closed")
+	errNoEncodedDatapoints = errors.New("encoder has no encoded datapoints")
+	errTooManySamples      = errors.New("xor chunk cannot hold more samples")
+	errIteratorClosed      = errors.New("iterator is closed")
+)
+
+// DefaultReaderIteratorAllocFn returns a function for allocating NewReaderIterator.
+func DefaultReaderIteratorAllocFn(
+	opts encoding.Options,
+) func(r xio.Reader64, _ namespace.SchemaDescr) encoding.ReaderIterator {
+	return func(r xio.Reader64, _ namespace.SchemaDescr) encoding.ReaderIterator {
+		return NewReaderIterator(r, opts)
+	}
+}
+
+func toMillis(nanos int64) int64 {
+	return nanos / int64(time.Millisecond)
+}
+
+func fromMillis(millis int64) int64 {
+	return millis * int64(time.Millisecond)
+}
+
+// bitRange returns whether the delta-of-delta fits in the given number of
+// bits, note the range is not symmetric to match the Prometheus encoding.
+func bitRange(x int64, nbits uint8) bool {
+	return -((1<<(nbits-1))-1) <= x && x <= 1<<(nbits-1)
+}
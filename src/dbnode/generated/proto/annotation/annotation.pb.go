@@ -91,6 +91,7 @@ func (MetricType) EnumDescriptor() ([]byte, []int) { return fileDescriptorAnnota
 type Payload struct {
 	MetricType        MetricType `protobuf:"varint,1,opt,name=metric_type,json=metricType,proto3,enum=annotation.MetricType" json:"metric_type,omitempty"`
 	HandleValueResets bool       `protobuf:"varint,2,opt,name=handle_value_resets,json=handleValueResets,proto3" json:"handle_value_resets,omitempty"`
+	NativeHistogram   []byte     `protobuf:"bytes,3,opt,name=native_histogram,json=nativeHistogram,proto3" json:"native_histogram,omitempty"`
 }
 
 func (m *Payload) Reset()                    { *m = Payload{} }
@@ -112,6 +113,13 @@ func (m *Payload) GetHandleValueResets() bool {
 	return false
 }
 
+func (m *Payload) GetNativeHistogram() []byte {
+	if m != nil {
+		return m.NativeHistogram
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Payload)(nil), "annotation.Payload")
 	proto.RegisterEnum("annotation.MetricType", MetricType_name, MetricType_value)
@@ -146,6 +154,12 @@ func (m *Payload) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i++
 	}
+	if len(m.NativeHistogram) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintAnnotation(dAtA, i, uint64(len(m.NativeHistogram)))
+		i += copy(dAtA[i:], m.NativeHistogram)
+	}
 	return i, nil
 }
 
@@ -167,6 +181,10 @@ func (m *Payload) Size() (n int) {
 	if m.HandleValueResets {
 		n += 2
 	}
+	l = len(m.NativeHistogram)
+	if l > 0 {
+		n += 1 + l + sovAnnotation(uint64(l))
+	}
 	return n
 }
 
@@ -251,6 +269,37 @@ func (m *Payload) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.HandleValueResets = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NativeHistogram", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAnnotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAnnotation
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NativeHistogram = append(m.NativeHistogram[:0], dAtA[iNdEx:postIndex]...)
+			if m.NativeHistogram == nil {
+				m.NativeHistogram = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAnnotation(dAtA[iNdEx:])
@@ -382,24 +431,26 @@ func init() {
 }
 
 var fileDescriptorAnnotation = []byte{
-	// 297 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0xd0, 0x4d, 0x4b, 0xc3, 0x30,
-	0x1c, 0x06, 0xf0, 0x65, 0xee, 0xf5, 0x3f, 0x5f, 0x62, 0x06, 0xb2, 0x53, 0x19, 0x9e, 0x86, 0x87,
-	0x06, 0xdc, 0xc1, 0x73, 0x95, 0x3a, 0x87, 0xb4, 0x95, 0x36, 0x55, 0x3c, 0x95, 0x74, 0x09, 0xdb,
-	0x60, 0x4d, 0x46, 0x96, 0x09, 0xf3, 0x53, 0xf8, 0xb1, 0x3c, 0xfa, 0x11, 0x64, 0x7e, 0x11, 0x59,
-	0x05, 0xb7, 0xdb, 0x93, 0xe7, 0xc7, 0x93, 0xc3, 0x1f, 0xc6, 0xd3, 0xb9, 0x9d, 0xad, 0x73, 0x77,
-	0xa2, 0x0b, 0x5a, 0x0c, 0x45, 0x4e, 0x8b, 0x21, 0x5d, 0x99, 0x09, 0x15, 0xb9, 0xd2, 0x42, 0xd2,
-	0xa9, 0x54, 0xd2, 0x70, 0x2b, 0x05, 0x5d, 0x1a, 0x6d, 0x35, 0xe5, 0x4a, 0x69, 0xcb, 0xed, 0x5c,
-	0xab, 0x83, 0xe8, 0x96, 0x46, 0x60, 0xdf, 0x5c, 0x1a, 0x68, 0x3e, 0xf1, 0xcd, 0x42, 0x73, 0x41,
-	0x6e, 0xa0, 0x53, 0x48, 0x6b, 0xe6, 0x93, 0xcc, 0x6e, 0x96, 0xb2, 0x87, 0xfa, 0x68, 0x70, 0x7a,
-	0x7d, 0xe1, 0x1e, 0xcc, 0x83, 0x92, 0xd9, 0x66, 0x29, 0x63, 0x28, 0xfe, 0x33, 0x71, 0xa1, 0x3b,
-	0xe3, 0x4a, 0x2c, 0x64, 0xf6, 0xc6, 0x17, 0x6b, 0x99, 0x19, 0xb9, 0x92, 0x76, 0xd5, 0xab, 0xf6,
-	0xd1, 0xa0, 0x15, 0x9f, 0xff, 0xd1, 0xf3, 0x4e, 0xe2, 0x12, 0xae, 0xde, 0x01, 0xf6, 0x3f, 0x91,
-	0x0e, 0x34, 0xd3, 0xf0, 0x31, 0x8c, 0x5e, 0x42, 0x5c, 0xd9, 0x3d, 0xee, 0xa2, 0x34, 0x64, 0x7e,
-	0x8c, 0x11, 0x69, 0x43, 0x7d, 0xe4, 0xa5, 0x23, 0x1f, 0x57, 0xc9, 0x09, 0xb4, 0x1f, 0xc6, 0x09,
-	0x8b, 0x46, 0xb1, 0x17, 0xe0, 0x23, 0xd2, 0x85, 0xb3, 0x52, 0xb2, 0x7d, 0x59, 0xdb, 0x6d, 0x93,
-	0x34, 0x08, 0xbc, 0xf8, 0x15, 0xd7, 0x49, 0x0b, 0x6a, 0xe3, 0xf0, 0x3e, 0xc2, 0x0d, 0x72, 0x0c,
-	0xad, 0x84, 0x79, 0xcc, 0x4f, 0x7c, 0x86, 0x9b, 0xb7, 0xf8, 0x73, 0xeb, 0xa0, 0xaf, 0xad, 0x83,
-	0xbe, 0xb7, 0x0e, 0xfa, 0xf8, 0x71, 0x2a, 0x79, 0xa3, 0x3c, 0xca, 0xf0, 0x37, 0x00, 0x00, 0xff,
-	0xff, 0x25, 0xe6, 0x7a, 0x90, 0x61, 0x01, 0x00, 0x00,
+	// 324 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xcf, 0x6a, 0xf2, 0x40,
+	0x14, 0xc5, 0x1d, 0xff, 0x7b, 0xf5, 0xfb, 0x9c, 0x8e, 0x50, 0x5c, 0x05, 0xe9, 0xca, 0x76, 0x91,
+	0x40, 0x5d, 0x74, 0x9d, 0x96, 0x54, 0xa5, 0x24, 0x29, 0x93, 0xa4, 0xa5, 0xab, 0x30, 0x31, 0x83,
+	0x06, 0xcc, 0x8c, 0x24, 0xa3, 0x60, 0x9f, 0xa2, 0x2f, 0xd0, 0xf7, 0xe9, 0xb2, 0x8f, 0x50, 0xec,
+	0x8b, 0x14, 0x23, 0xad, 0xee, 0xce, 0xfd, 0xfd, 0x38, 0x5c, 0x38, 0x30, 0x9d, 0x27, 0x6a, 0xb1,
+	0x8e, 0xf4, 0x99, 0x4c, 0x8d, 0x74, 0x14, 0x47, 0x46, 0x3a, 0x32, 0xf2, 0x6c, 0x66, 0xc4, 0x91,
+	0x90, 0x31, 0x37, 0xe6, 0x5c, 0xf0, 0x8c, 0x29, 0x1e, 0x1b, 0xab, 0x4c, 0x2a, 0x69, 0x30, 0x21,
+	0xa4, 0x62, 0x2a, 0x91, 0xe2, 0x24, 0xea, 0x85, 0x23, 0x70, 0x24, 0x17, 0xef, 0x08, 0x1a, 0x8f,
+	0x6c, 0xbb, 0x94, 0x2c, 0x26, 0x37, 0xd0, 0x4e, 0xb9, 0xca, 0x92, 0x59, 0xa8, 0xb6, 0x2b, 0xde,
+	0x47, 0x03, 0x34, 0xfc, 0x7f, 0x7d, 0xae, 0x9f, 0xf4, 0xed, 0x42, 0xfb, 0xdb, 0x15, 0xa7, 0x90,
+	0xfe, 0x65, 0xa2, 0x43, 0x6f, 0xc1, 0x44, 0xbc, 0xe4, 0xe1, 0x86, 0x2d, 0xd7, 0x3c, 0xcc, 0x78,
+	0xce, 0x55, 0xde, 0x2f, 0x0f, 0xd0, 0xb0, 0x49, 0xcf, 0x0e, 0xea, 0x69, 0x6f, 0x68, 0x21, 0xc8,
+	0x25, 0x60, 0xc1, 0x54, 0xb2, 0xe1, 0xe1, 0x22, 0xc9, 0x95, 0x9c, 0x67, 0x2c, 0xed, 0x57, 0x06,
+	0x68, 0xd8, 0xa1, 0xdd, 0x03, 0x9f, 0xfc, 0xe2, 0xab, 0x57, 0x80, 0xe3, 0x53, 0xd2, 0x86, 0x46,
+	0xe0, 0x3c, 0x38, 0xee, 0xb3, 0x83, 0x4b, 0xfb, 0xe3, 0xce, 0x0d, 0x1c, 0xdf, 0xa2, 0x18, 0x91,
+	0x16, 0xd4, 0xc6, 0x66, 0x30, 0xb6, 0x70, 0x99, 0xfc, 0x83, 0xd6, 0x64, 0xea, 0xf9, 0xee, 0x98,
+	0x9a, 0x36, 0xae, 0x90, 0x1e, 0x74, 0x0b, 0x13, 0x1e, 0x61, 0x75, 0xdf, 0xf5, 0x02, 0xdb, 0x36,
+	0xe9, 0x0b, 0xae, 0x91, 0x26, 0x54, 0xa7, 0xce, 0xbd, 0x8b, 0xeb, 0xa4, 0x03, 0x4d, 0xcf, 0x37,
+	0x7d, 0xcb, 0xb3, 0x7c, 0xdc, 0xb8, 0xc5, 0x1f, 0x3b, 0x0d, 0x7d, 0xee, 0x34, 0xf4, 0xb5, 0xd3,
+	0xd0, 0xdb, 0xb7, 0x56, 0x8a, 0xea, 0xc5, 0x80, 0xa3, 0x9f, 0x01, 0x00, 0x2d, 0x03, 0xc7, 0x6a,
+	0x8d, 0x01, 0x00, 0x00,
 }
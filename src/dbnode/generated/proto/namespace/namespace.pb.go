@@ -21,38 +21,19 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-/*
-	Package namespace is a generated protocol buffer package.
-
-	It is generated from these files:
-		github.com/m3db/m3/src/dbnode/generated/proto/namespace/namespace.proto
-		github.com/m3db/m3/src/dbnode/generated/proto/namespace/schema.proto
-
-	It has these top-level messages:
-		RetentionOptions
-		IndexOptions
-		NamespaceOptions
-		AggregationOptions
-		Aggregation
-		AggregatedAttributes
-		DownsampleOptions
-		StagingState
-		Registry
-		NamespaceRuntimeOptions
-		ExtendedOptions
-		SchemaOptions
-		SchemaHistory
-		FileDescriptorSet
-*/
-package namespace
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: github.com/m3db/m3/src/dbnode/generated/proto/namespace/namespace.proto
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import google_protobuf "github.com/gogo/protobuf/types"
-import google_protobuf1 "github.com/gogo/protobuf/types"
+package namespace
 
-import io "io"
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	types "github.com/gogo/protobuf/types"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -63,7 +44,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 // StagingStatus represents the current status of the namespace.
 type StagingStatus int32
@@ -82,6 +63,7 @@ var StagingStatus_name = map[int32]string{
 	1: "INITIALIZING",
 	2: "READY",
 }
+
 var StagingStatus_value = map[string]int32{
 	"UNKNOWN":      0,
 	"INITIALIZING": 1,
@@ -91,7 +73,38 @@ var StagingStatus_value = map[string]int32{
 func (x StagingStatus) String() string {
 	return proto.EnumName(StagingStatus_name, int32(x))
 }
-func (StagingStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{0} }
+
+func (StagingStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{0}
+}
+
+// EncodingScheme is the scheme used to encode the datapoints of the namespace.
+type EncodingScheme int32
+
+const (
+	// Datapoints are encoded with M3TSZ.
+	EncodingScheme_M3TSZ EncodingScheme = 0
+	// Datapoints are encoded in a format bit-compatible with Prometheus XOR chunks.
+	EncodingScheme_XOR EncodingScheme = 1
+)
+
+var EncodingScheme_name = map[int32]string{
+	0: "M3TSZ",
+	1: "XOR",
+}
+
+var EncodingScheme_value = map[string]int32{
+	"M3TSZ": 0,
+	"XOR":   1,
+}
+
+func (x EncodingScheme) String() string {
+	return proto.EnumName(EncodingScheme_name, int32(x))
+}
+
+func (EncodingScheme) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{1}
+}
 
 type RetentionOptions struct {
 	RetentionPeriodNanos                     int64 `protobuf:"varint,1,opt,name=retentionPeriodNanos,proto3" json:"retentionPeriodNanos,omitempty"`
@@ -103,10 +116,38 @@ type RetentionOptions struct {
 	FutureRetentionPeriodNanos               int64 `protobuf:"varint,7,opt,name=futureRetentionPeriodNanos,proto3" json:"futureRetentionPeriodNanos,omitempty"`
 }
 
-func (m *RetentionOptions) Reset()                    { *m = RetentionOptions{} }
-func (m *RetentionOptions) String() string            { return proto.CompactTextString(m) }
-func (*RetentionOptions) ProtoMessage()               {}
-func (*RetentionOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{0} }
+func (m *RetentionOptions) Reset()         { *m = RetentionOptions{} }
+func (m *RetentionOptions) String() string { return proto.CompactTextString(m) }
+func (*RetentionOptions) ProtoMessage()    {}
+func (*RetentionOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{0}
+}
+func (m *RetentionOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RetentionOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RetentionOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RetentionOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionOptions.Merge(m, src)
+}
+func (m *RetentionOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *RetentionOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionOptions proto.InternalMessageInfo
 
 func (m *RetentionOptions) GetRetentionPeriodNanos() int64 {
 	if m != nil {
@@ -162,10 +203,38 @@ type IndexOptions struct {
 	BlockSizeNanos int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
 }
 
-func (m *IndexOptions) Reset()                    { *m = IndexOptions{} }
-func (m *IndexOptions) String() string            { return proto.CompactTextString(m) }
-func (*IndexOptions) ProtoMessage()               {}
-func (*IndexOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{1} }
+func (m *IndexOptions) Reset()         { *m = IndexOptions{} }
+func (m *IndexOptions) String() string { return proto.CompactTextString(m) }
+func (*IndexOptions) ProtoMessage()    {}
+func (*IndexOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{1}
+}
+func (m *IndexOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *IndexOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_IndexOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *IndexOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IndexOptions.Merge(m, src)
+}
+func (m *IndexOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *IndexOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_IndexOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IndexOptions proto.InternalMessageInfo
 
 func (m *IndexOptions) GetEnabled() bool {
 	if m != nil {
@@ -182,28 +251,59 @@ func (m *IndexOptions) GetBlockSizeNanos() int64 {
 }
 
 type NamespaceOptions struct {
-	BootstrapEnabled      bool                        `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
-	FlushEnabled          bool                        `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
-	WritesToCommitLog     bool                        `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
-	CleanupEnabled        bool                        `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
-	RepairEnabled         bool                        `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
-	RetentionOptions      *RetentionOptions           `protobuf:"bytes,6,opt,name=retentionOptions" json:"retentionOptions,omitempty"`
-	SnapshotEnabled       bool                        `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
-	IndexOptions          *IndexOptions               `protobuf:"bytes,8,opt,name=indexOptions" json:"indexOptions,omitempty"`
-	SchemaOptions         *SchemaOptions              `protobuf:"bytes,9,opt,name=schemaOptions" json:"schemaOptions,omitempty"`
-	ColdWritesEnabled     bool                        `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
-	RuntimeOptions        *NamespaceRuntimeOptions    `protobuf:"bytes,11,opt,name=runtimeOptions" json:"runtimeOptions,omitempty"`
-	CacheBlocksOnRetrieve *google_protobuf1.BoolValue `protobuf:"bytes,12,opt,name=cacheBlocksOnRetrieve" json:"cacheBlocksOnRetrieve,omitempty"`
-	AggregationOptions    *AggregationOptions         `protobuf:"bytes,13,opt,name=aggregationOptions" json:"aggregationOptions,omitempty"`
-	StagingState          *StagingState               `protobuf:"bytes,14,opt,name=stagingState" json:"stagingState,omitempty"`
+	BootstrapEnabled      bool                     `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
+	FlushEnabled          bool                     `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
+	WritesToCommitLog     bool                     `protobuf:"varint,3,opt,name=writesToCommitLog,proto3" json:"writesToCommitLog,omitempty"`
+	CleanupEnabled        bool                     `protobuf:"varint,4,opt,name=cleanupEnabled,proto3" json:"cleanupEnabled,omitempty"`
+	RepairEnabled         bool                     `protobuf:"varint,5,opt,name=repairEnabled,proto3" json:"repairEnabled,omitempty"`
+	RetentionOptions      *RetentionOptions        `protobuf:"bytes,6,opt,name=retentionOptions,proto3" json:"retentionOptions,omitempty"`
+	SnapshotEnabled       bool                     `protobuf:"varint,7,opt,name=snapshotEnabled,proto3" json:"snapshotEnabled,omitempty"`
+	IndexOptions          *IndexOptions            `protobuf:"bytes,8,opt,name=indexOptions,proto3" json:"indexOptions,omitempty"`
+	SchemaOptions         *SchemaOptions           `protobuf:"bytes,9,opt,name=schemaOptions,proto3" json:"schemaOptions,omitempty"`
+	ColdWritesEnabled     bool                     `protobuf:"varint,10,opt,name=coldWritesEnabled,proto3" json:"coldWritesEnabled,omitempty"`
+	RuntimeOptions        *NamespaceRuntimeOptions `protobuf:"bytes,11,opt,name=runtimeOptions,proto3" json:"runtimeOptions,omitempty"`
+	CacheBlocksOnRetrieve *types.BoolValue         `protobuf:"bytes,12,opt,name=cacheBlocksOnRetrieve,proto3" json:"cacheBlocksOnRetrieve,omitempty"`
+	AggregationOptions    *AggregationOptions      `protobuf:"bytes,13,opt,name=aggregationOptions,proto3" json:"aggregationOptions,omitempty"`
+	StagingState          *StagingState            `protobuf:"bytes,14,opt,name=stagingState,proto3" json:"stagingState,omitempty"`
+	ResizeState           *ResizeState             `protobuf:"bytes,15,opt,name=resizeState,proto3" json:"resizeState,omitempty"`
+	LateWritesEnabled     bool                     `protobuf:"varint,16,opt,name=lateWritesEnabled,proto3" json:"lateWritesEnabled,omitempty"`
+	EncodingScheme        EncodingScheme           `protobuf:"varint,17,opt,name=encodingScheme,proto3,enum=namespace.EncodingScheme" json:"encodingScheme,omitempty"`
 	// Use larger field ID to ensure new fields are always added before extended options.
-	ExtendedOptions *ExtendedOptions `protobuf:"bytes,1000,opt,name=extendedOptions" json:"extendedOptions,omitempty"`
+	ExtendedOptions *ExtendedOptions `protobuf:"bytes,1000,opt,name=extendedOptions,proto3" json:"extendedOptions,omitempty"`
+}
+
+func (m *NamespaceOptions) Reset()         { *m = NamespaceOptions{} }
+func (m *NamespaceOptions) String() string { return proto.CompactTextString(m) }
+func (*NamespaceOptions) ProtoMessage()    {}
+func (*NamespaceOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{2}
+}
+func (m *NamespaceOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NamespaceOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NamespaceOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceOptions.Merge(m, src)
+}
+func (m *NamespaceOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceOptions.DiscardUnknown(m)
 }
 
-func (m *NamespaceOptions) Reset()                    { *m = NamespaceOptions{} }
-func (m *NamespaceOptions) String() string            { return proto.CompactTextString(m) }
-func (*NamespaceOptions) ProtoMessage()               {}
-func (*NamespaceOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{2} }
+var xxx_messageInfo_NamespaceOptions proto.InternalMessageInfo
 
 func (m *NamespaceOptions) GetBootstrapEnabled() bool {
 	if m != nil {
@@ -282,7 +382,7 @@ func (m *NamespaceOptions) GetRuntimeOptions() *NamespaceRuntimeOptions {
 	return nil
 }
 
-func (m *NamespaceOptions) GetCacheBlocksOnRetrieve() *google_protobuf1.BoolValue {
+func (m *NamespaceOptions) GetCacheBlocksOnRetrieve() *types.BoolValue {
 	if m != nil {
 		return m.CacheBlocksOnRetrieve
 	}
@@ -303,6 +403,27 @@ func (m *NamespaceOptions) GetStagingState() *StagingState {
 	return nil
 }
 
+func (m *NamespaceOptions) GetResizeState() *ResizeState {
+	if m != nil {
+		return m.ResizeState
+	}
+	return nil
+}
+
+func (m *NamespaceOptions) GetLateWritesEnabled() bool {
+	if m != nil {
+		return m.LateWritesEnabled
+	}
+	return false
+}
+
+func (m *NamespaceOptions) GetEncodingScheme() EncodingScheme {
+	if m != nil {
+		return m.EncodingScheme
+	}
+	return EncodingScheme_M3TSZ
+}
+
 func (m *NamespaceOptions) GetExtendedOptions() *ExtendedOptions {
 	if m != nil {
 		return m.ExtendedOptions
@@ -316,13 +437,44 @@ type AggregationOptions struct {
 	// aggregations is a repeated field to support the ability to send aggregated data
 	// to a namespace also receiving unaggregated data. In this case, the namespace will
 	// have one Aggregation with aggregated set to false and another with aggregated set to true.
-	Aggregations []*Aggregation `protobuf:"bytes,1,rep,name=aggregations" json:"aggregations,omitempty"`
+	Aggregations []*Aggregation `protobuf:"bytes,1,rep,name=aggregations,proto3" json:"aggregations,omitempty"`
+	// rollupPolicy, if set, automatically rolls up sealed blocks of this
+	// namespace into a coarser namespace once they reach a given age.
+	RollupPolicy *RollupPolicy `protobuf:"bytes,2,opt,name=rollupPolicy,proto3" json:"rollupPolicy,omitempty"`
 }
 
-func (m *AggregationOptions) Reset()                    { *m = AggregationOptions{} }
-func (m *AggregationOptions) String() string            { return proto.CompactTextString(m) }
-func (*AggregationOptions) ProtoMessage()               {}
-func (*AggregationOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{3} }
+func (m *AggregationOptions) Reset()         { *m = AggregationOptions{} }
+func (m *AggregationOptions) String() string { return proto.CompactTextString(m) }
+func (*AggregationOptions) ProtoMessage()    {}
+func (*AggregationOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{3}
+}
+func (m *AggregationOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AggregationOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AggregationOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AggregationOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregationOptions.Merge(m, src)
+}
+func (m *AggregationOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *AggregationOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregationOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregationOptions proto.InternalMessageInfo
 
 func (m *AggregationOptions) GetAggregations() []*Aggregation {
 	if m != nil {
@@ -331,6 +483,87 @@ func (m *AggregationOptions) GetAggregations() []*Aggregation {
 	return nil
 }
 
+func (m *AggregationOptions) GetRollupPolicy() *RollupPolicy {
+	if m != nil {
+		return m.RollupPolicy
+	}
+	return nil
+}
+
+// RollupPolicy describes how sealed blocks are rolled up into a coarser namespace.
+type RollupPolicy struct {
+	// targetNamespace is the namespace the rolled up tiles are written to.
+	TargetNamespace string `protobuf:"bytes,1,opt,name=targetNamespace,proto3" json:"targetNamespace,omitempty"`
+	// rollupAfterNanos is how long after the end of a block it becomes eligible for rollup.
+	RollupAfterNanos int64 `protobuf:"varint,2,opt,name=rollupAfterNanos,proto3" json:"rollupAfterNanos,omitempty"`
+	// stepNanos is the resolution of the rolled up tiles.
+	StepNanos int64 `protobuf:"varint,3,opt,name=stepNanos,proto3" json:"stepNanos,omitempty"`
+	// rawRetentionNanos, if set, shortens the retention of raw data files
+	// that have already been rolled up.
+	RawRetentionNanos int64 `protobuf:"varint,4,opt,name=rawRetentionNanos,proto3" json:"rawRetentionNanos,omitempty"`
+}
+
+func (m *RollupPolicy) Reset()         { *m = RollupPolicy{} }
+func (m *RollupPolicy) String() string { return proto.CompactTextString(m) }
+func (*RollupPolicy) ProtoMessage()    {}
+func (*RollupPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{4}
+}
+func (m *RollupPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RollupPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RollupPolicy.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RollupPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollupPolicy.Merge(m, src)
+}
+func (m *RollupPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *RollupPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollupPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollupPolicy proto.InternalMessageInfo
+
+func (m *RollupPolicy) GetTargetNamespace() string {
+	if m != nil {
+		return m.TargetNamespace
+	}
+	return ""
+}
+
+func (m *RollupPolicy) GetRollupAfterNanos() int64 {
+	if m != nil {
+		return m.RollupAfterNanos
+	}
+	return 0
+}
+
+func (m *RollupPolicy) GetStepNanos() int64 {
+	if m != nil {
+		return m.StepNanos
+	}
+	return 0
+}
+
+func (m *RollupPolicy) GetRawRetentionNanos() int64 {
+	if m != nil {
+		return m.RawRetentionNanos
+	}
+	return 0
+}
+
 // Aggregation describes data points within the namespace.
 type Aggregation struct {
 	// aggregated is true if data points are aggregated, false otherwise.
@@ -338,13 +571,41 @@ type Aggregation struct {
 	// attributes specifies how to aggregate data when aggregated is set to true.
 	// This field is ignored when aggregated is false and required when aggregated
 	// is true.
-	Attributes *AggregatedAttributes `protobuf:"bytes,2,opt,name=attributes" json:"attributes,omitempty"`
+	Attributes *AggregatedAttributes `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
 }
 
-func (m *Aggregation) Reset()                    { *m = Aggregation{} }
-func (m *Aggregation) String() string            { return proto.CompactTextString(m) }
-func (*Aggregation) ProtoMessage()               {}
-func (*Aggregation) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{4} }
+func (m *Aggregation) Reset()         { *m = Aggregation{} }
+func (m *Aggregation) String() string { return proto.CompactTextString(m) }
+func (*Aggregation) ProtoMessage()    {}
+func (*Aggregation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{5}
+}
+func (m *Aggregation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Aggregation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Aggregation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Aggregation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Aggregation.Merge(m, src)
+}
+func (m *Aggregation) XXX_Size() int {
+	return m.Size()
+}
+func (m *Aggregation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Aggregation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Aggregation proto.InternalMessageInfo
 
 func (m *Aggregation) GetAggregated() bool {
 	if m != nil {
@@ -364,13 +625,41 @@ func (m *Aggregation) GetAttributes() *AggregatedAttributes {
 type AggregatedAttributes struct {
 	// resolutionNanos is the time range to aggregate data across.
 	ResolutionNanos   int64              `protobuf:"varint,1,opt,name=resolutionNanos,proto3" json:"resolutionNanos,omitempty"`
-	DownsampleOptions *DownsampleOptions `protobuf:"bytes,2,opt,name=downsampleOptions" json:"downsampleOptions,omitempty"`
+	DownsampleOptions *DownsampleOptions `protobuf:"bytes,2,opt,name=downsampleOptions,proto3" json:"downsampleOptions,omitempty"`
+}
+
+func (m *AggregatedAttributes) Reset()         { *m = AggregatedAttributes{} }
+func (m *AggregatedAttributes) String() string { return proto.CompactTextString(m) }
+func (*AggregatedAttributes) ProtoMessage()    {}
+func (*AggregatedAttributes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{6}
+}
+func (m *AggregatedAttributes) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AggregatedAttributes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AggregatedAttributes.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AggregatedAttributes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregatedAttributes.Merge(m, src)
+}
+func (m *AggregatedAttributes) XXX_Size() int {
+	return m.Size()
+}
+func (m *AggregatedAttributes) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregatedAttributes.DiscardUnknown(m)
 }
 
-func (m *AggregatedAttributes) Reset()                    { *m = AggregatedAttributes{} }
-func (m *AggregatedAttributes) String() string            { return proto.CompactTextString(m) }
-func (*AggregatedAttributes) ProtoMessage()               {}
-func (*AggregatedAttributes) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{5} }
+var xxx_messageInfo_AggregatedAttributes proto.InternalMessageInfo
 
 func (m *AggregatedAttributes) GetResolutionNanos() int64 {
 	if m != nil {
@@ -393,10 +682,38 @@ type DownsampleOptions struct {
 	All bool `protobuf:"varint,1,opt,name=all,proto3" json:"all,omitempty"`
 }
 
-func (m *DownsampleOptions) Reset()                    { *m = DownsampleOptions{} }
-func (m *DownsampleOptions) String() string            { return proto.CompactTextString(m) }
-func (*DownsampleOptions) ProtoMessage()               {}
-func (*DownsampleOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{6} }
+func (m *DownsampleOptions) Reset()         { *m = DownsampleOptions{} }
+func (m *DownsampleOptions) String() string { return proto.CompactTextString(m) }
+func (*DownsampleOptions) ProtoMessage()    {}
+func (*DownsampleOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{7}
+}
+func (m *DownsampleOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DownsampleOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DownsampleOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DownsampleOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownsampleOptions.Merge(m, src)
+}
+func (m *DownsampleOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *DownsampleOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownsampleOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownsampleOptions proto.InternalMessageInfo
 
 func (m *DownsampleOptions) GetAll() bool {
 	if m != nil {
@@ -411,10 +728,38 @@ type StagingState struct {
 	Status StagingStatus `protobuf:"varint,1,opt,name=status,proto3,enum=namespace.StagingStatus" json:"status,omitempty"`
 }
 
-func (m *StagingState) Reset()                    { *m = StagingState{} }
-func (m *StagingState) String() string            { return proto.CompactTextString(m) }
-func (*StagingState) ProtoMessage()               {}
-func (*StagingState) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{7} }
+func (m *StagingState) Reset()         { *m = StagingState{} }
+func (m *StagingState) String() string { return proto.CompactTextString(m) }
+func (*StagingState) ProtoMessage()    {}
+func (*StagingState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{8}
+}
+func (m *StagingState) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StagingState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StagingState.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StagingState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StagingState.Merge(m, src)
+}
+func (m *StagingState) XXX_Size() int {
+	return m.Size()
+}
+func (m *StagingState) XXX_DiscardUnknown() {
+	xxx_messageInfo_StagingState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StagingState proto.InternalMessageInfo
 
 func (m *StagingState) GetStatus() StagingStatus {
 	if m != nil {
@@ -423,14 +768,98 @@ func (m *StagingState) GetStatus() StagingStatus {
 	return StagingStatus_UNKNOWN
 }
 
+// ResizeState is state related to an in-progress online resize of the
+// namespace's block sizes.
+type ResizeState struct {
+	// blockSizeNanos is the data block size being converted to.
+	BlockSizeNanos int64 `protobuf:"varint,1,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	// indexBlockSizeNanos is the index block size being converted to.
+	IndexBlockSizeNanos int64 `protobuf:"varint,2,opt,name=indexBlockSizeNanos,proto3" json:"indexBlockSizeNanos,omitempty"`
+}
+
+func (m *ResizeState) Reset()         { *m = ResizeState{} }
+func (m *ResizeState) String() string { return proto.CompactTextString(m) }
+func (*ResizeState) ProtoMessage()    {}
+func (*ResizeState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{9}
+}
+func (m *ResizeState) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResizeState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResizeState.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResizeState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResizeState.Merge(m, src)
+}
+func (m *ResizeState) XXX_Size() int {
+	return m.Size()
+}
+func (m *ResizeState) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResizeState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResizeState proto.InternalMessageInfo
+
+func (m *ResizeState) GetBlockSizeNanos() int64 {
+	if m != nil {
+		return m.BlockSizeNanos
+	}
+	return 0
+}
+
+func (m *ResizeState) GetIndexBlockSizeNanos() int64 {
+	if m != nil {
+		return m.IndexBlockSizeNanos
+	}
+	return 0
+}
+
 type Registry struct {
-	Namespaces map[string]*NamespaceOptions `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	Namespaces map[string]*NamespaceOptions `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (m *Registry) Reset()                    { *m = Registry{} }
-func (m *Registry) String() string            { return proto.CompactTextString(m) }
-func (*Registry) ProtoMessage()               {}
-func (*Registry) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{8} }
+func (m *Registry) Reset()         { *m = Registry{} }
+func (m *Registry) String() string { return proto.CompactTextString(m) }
+func (*Registry) ProtoMessage()    {}
+func (*Registry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{10}
+}
+func (m *Registry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Registry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Registry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Registry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Registry.Merge(m, src)
+}
+func (m *Registry) XXX_Size() int {
+	return m.Size()
+}
+func (m *Registry) XXX_DiscardUnknown() {
+	xxx_messageInfo_Registry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Registry proto.InternalMessageInfo
 
 func (m *Registry) GetNamespaces() map[string]*NamespaceOptions {
 	if m != nil {
@@ -440,23 +869,51 @@ func (m *Registry) GetNamespaces() map[string]*NamespaceOptions {
 }
 
 type NamespaceRuntimeOptions struct {
-	WriteIndexingPerCPUConcurrency *google_protobuf1.DoubleValue `protobuf:"bytes,1,opt,name=writeIndexingPerCPUConcurrency" json:"writeIndexingPerCPUConcurrency,omitempty"`
-	FlushIndexingPerCPUConcurrency *google_protobuf1.DoubleValue `protobuf:"bytes,2,opt,name=flushIndexingPerCPUConcurrency" json:"flushIndexingPerCPUConcurrency,omitempty"`
+	WriteIndexingPerCPUConcurrency *types.DoubleValue `protobuf:"bytes,1,opt,name=writeIndexingPerCPUConcurrency,proto3" json:"writeIndexingPerCPUConcurrency,omitempty"`
+	FlushIndexingPerCPUConcurrency *types.DoubleValue `protobuf:"bytes,2,opt,name=flushIndexingPerCPUConcurrency,proto3" json:"flushIndexingPerCPUConcurrency,omitempty"`
+}
+
+func (m *NamespaceRuntimeOptions) Reset()         { *m = NamespaceRuntimeOptions{} }
+func (m *NamespaceRuntimeOptions) String() string { return proto.CompactTextString(m) }
+func (*NamespaceRuntimeOptions) ProtoMessage()    {}
+func (*NamespaceRuntimeOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{11}
+}
+func (m *NamespaceRuntimeOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceRuntimeOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NamespaceRuntimeOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NamespaceRuntimeOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceRuntimeOptions.Merge(m, src)
+}
+func (m *NamespaceRuntimeOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceRuntimeOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceRuntimeOptions.DiscardUnknown(m)
 }
 
-func (m *NamespaceRuntimeOptions) Reset()                    { *m = NamespaceRuntimeOptions{} }
-func (m *NamespaceRuntimeOptions) String() string            { return proto.CompactTextString(m) }
-func (*NamespaceRuntimeOptions) ProtoMessage()               {}
-func (*NamespaceRuntimeOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{9} }
+var xxx_messageInfo_NamespaceRuntimeOptions proto.InternalMessageInfo
 
-func (m *NamespaceRuntimeOptions) GetWriteIndexingPerCPUConcurrency() *google_protobuf1.DoubleValue {
+func (m *NamespaceRuntimeOptions) GetWriteIndexingPerCPUConcurrency() *types.DoubleValue {
 	if m != nil {
 		return m.WriteIndexingPerCPUConcurrency
 	}
 	return nil
 }
 
-func (m *NamespaceRuntimeOptions) GetFlushIndexingPerCPUConcurrency() *google_protobuf1.DoubleValue {
+func (m *NamespaceRuntimeOptions) GetFlushIndexingPerCPUConcurrency() *types.DoubleValue {
 	if m != nil {
 		return m.FlushIndexingPerCPUConcurrency
 	}
@@ -464,14 +921,42 @@ func (m *NamespaceRuntimeOptions) GetFlushIndexingPerCPUConcurrency() *google_pr
 }
 
 type ExtendedOptions struct {
-	Type    string                  `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Options *google_protobuf.Struct `protobuf:"bytes,2,opt,name=options" json:"options,omitempty"`
+	Type    string        `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Options *types.Struct `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *ExtendedOptions) Reset()         { *m = ExtendedOptions{} }
+func (m *ExtendedOptions) String() string { return proto.CompactTextString(m) }
+func (*ExtendedOptions) ProtoMessage()    {}
+func (*ExtendedOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7614f6b10dee3d7, []int{12}
+}
+func (m *ExtendedOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExtendedOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExtendedOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExtendedOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtendedOptions.Merge(m, src)
+}
+func (m *ExtendedOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExtendedOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtendedOptions.DiscardUnknown(m)
 }
 
-func (m *ExtendedOptions) Reset()                    { *m = ExtendedOptions{} }
-func (m *ExtendedOptions) String() string            { return proto.CompactTextString(m) }
-func (*ExtendedOptions) ProtoMessage()               {}
-func (*ExtendedOptions) Descriptor() ([]byte, []int) { return fileDescriptorNamespace, []int{10} }
+var xxx_messageInfo_ExtendedOptions proto.InternalMessageInfo
 
 func (m *ExtendedOptions) GetType() string {
 	if m != nil {
@@ -480,7 +965,7 @@ func (m *ExtendedOptions) GetType() string {
 	return ""
 }
 
-func (m *ExtendedOptions) GetOptions() *google_protobuf.Struct {
+func (m *ExtendedOptions) GetOptions() *types.Struct {
 	if m != nil {
 		return m.Options
 	}
@@ -488,23 +973,110 @@ func (m *ExtendedOptions) GetOptions() *google_protobuf.Struct {
 }
 
 func init() {
+	proto.RegisterEnum("namespace.StagingStatus", StagingStatus_name, StagingStatus_value)
+	proto.RegisterEnum("namespace.EncodingScheme", EncodingScheme_name, EncodingScheme_value)
 	proto.RegisterType((*RetentionOptions)(nil), "namespace.RetentionOptions")
 	proto.RegisterType((*IndexOptions)(nil), "namespace.IndexOptions")
 	proto.RegisterType((*NamespaceOptions)(nil), "namespace.NamespaceOptions")
 	proto.RegisterType((*AggregationOptions)(nil), "namespace.AggregationOptions")
+	proto.RegisterType((*RollupPolicy)(nil), "namespace.RollupPolicy")
 	proto.RegisterType((*Aggregation)(nil), "namespace.Aggregation")
 	proto.RegisterType((*AggregatedAttributes)(nil), "namespace.AggregatedAttributes")
 	proto.RegisterType((*DownsampleOptions)(nil), "namespace.DownsampleOptions")
 	proto.RegisterType((*StagingState)(nil), "namespace.StagingState")
+	proto.RegisterType((*ResizeState)(nil), "namespace.ResizeState")
 	proto.RegisterType((*Registry)(nil), "namespace.Registry")
+	proto.RegisterMapType((map[string]*NamespaceOptions)(nil), "namespace.Registry.NamespacesEntry")
 	proto.RegisterType((*NamespaceRuntimeOptions)(nil), "namespace.NamespaceRuntimeOptions")
 	proto.RegisterType((*ExtendedOptions)(nil), "namespace.ExtendedOptions")
-	proto.RegisterEnum("namespace.StagingStatus", StagingStatus_name, StagingStatus_value)
 }
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/dbnode/generated/proto/namespace/namespace.proto", fileDescriptor_f7614f6b10dee3d7)
+}
+
+var fileDescriptor_f7614f6b10dee3d7 = []byte{
+	// 1181 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x4f, 0x6f, 0x13, 0x47,
+	0x14, 0xcf, 0x3a, 0x80, 0x93, 0x17, 0xc7, 0xd9, 0x4c, 0x69, 0xd9, 0xa6, 0xd4, 0x45, 0x5b, 0x5a,
+	0x45, 0xa8, 0x8a, 0x81, 0x5c, 0x28, 0x95, 0x68, 0x9d, 0xc4, 0x45, 0xa6, 0xe0, 0x58, 0x63, 0x28,
+	0x34, 0xb7, 0xf1, 0xee, 0x78, 0xb3, 0x62, 0xbd, 0xb3, 0x9a, 0x99, 0x25, 0x98, 0xcf, 0x40, 0xa5,
+	0x1e, 0xfb, 0x1d, 0x7a, 0xe9, 0xc7, 0xe8, 0x91, 0x63, 0x8f, 0x15, 0xa8, 0x52, 0x3f, 0x46, 0x35,
+	0xb3, 0x5e, 0x7b, 0x76, 0xd7, 0x50, 0xd4, 0x4b, 0x34, 0x79, 0xef, 0xf7, 0xfe, 0xf8, 0xfd, 0xde,
+	0xfc, 0x66, 0xe1, 0x6e, 0x10, 0xca, 0xd3, 0x74, 0xb4, 0xe7, 0xb1, 0x49, 0x7b, 0xb2, 0xef, 0x8f,
+	0xda, 0x93, 0xfd, 0xb6, 0xe0, 0x5e, 0xdb, 0x1f, 0xc5, 0xcc, 0xa7, 0xed, 0x80, 0xc6, 0x94, 0x13,
+	0x49, 0xfd, 0x76, 0xc2, 0x99, 0x64, 0xed, 0x98, 0x4c, 0xa8, 0x48, 0x88, 0x47, 0x17, 0xa7, 0x3d,
+	0xed, 0x41, 0xeb, 0x73, 0xc3, 0xce, 0xe5, 0x80, 0xb1, 0x20, 0xa2, 0x59, 0xc8, 0x28, 0x1d, 0xb7,
+	0x85, 0xe4, 0xa9, 0x27, 0x33, 0xe0, 0x4e, 0xab, 0xec, 0x3d, 0xe3, 0x24, 0x49, 0x28, 0x17, 0x33,
+	0xff, 0xd1, 0xff, 0xed, 0x48, 0x78, 0xa7, 0x74, 0x42, 0xb2, 0x2c, 0xee, 0xcb, 0x55, 0xb0, 0x31,
+	0x95, 0x34, 0x96, 0x21, 0x8b, 0x8f, 0x13, 0xf5, 0x57, 0xa0, 0x9b, 0x70, 0x91, 0xe7, 0xb6, 0x01,
+	0xe5, 0x21, 0xf3, 0xfb, 0x24, 0x66, 0xc2, 0xb1, 0xae, 0x58, 0xbb, 0xab, 0x78, 0xa9, 0x0f, 0x7d,
+	0x09, 0xcd, 0x51, 0xc4, 0xbc, 0xa7, 0xc3, 0xf0, 0x05, 0xcd, 0xd0, 0x35, 0x8d, 0x2e, 0x59, 0xd1,
+	0x57, 0xb0, 0x3d, 0x4a, 0xc7, 0x63, 0xca, 0xbf, 0x4f, 0x65, 0xca, 0x67, 0xd0, 0x55, 0x0d, 0xad,
+	0x3a, 0xd0, 0x2e, 0x6c, 0x65, 0xc6, 0x01, 0x11, 0x32, 0xc3, 0x9e, 0xd3, 0xd8, 0xb2, 0x59, 0x23,
+	0x55, 0xa5, 0x23, 0x22, 0x49, 0xf7, 0x79, 0x12, 0xf2, 0xa9, 0x73, 0xfe, 0x8a, 0xb5, 0xbb, 0x86,
+	0xcb, 0x66, 0x74, 0x02, 0xbb, 0x25, 0x53, 0x67, 0x2c, 0x29, 0xef, 0x33, 0xd9, 0xf1, 0x3c, 0x2a,
+	0x84, 0xf9, 0x8b, 0x2f, 0xe8, 0x62, 0xef, 0x8d, 0x47, 0x77, 0x60, 0x67, 0xac, 0xdb, 0xc7, 0xcb,
+	0xe6, 0x57, 0xd7, 0xd9, 0xde, 0x81, 0x70, 0x07, 0xd0, 0xe8, 0xc5, 0x3e, 0x7d, 0x9e, 0x33, 0xe1,
+	0x40, 0x9d, 0xc6, 0x64, 0x14, 0x51, 0x5f, 0x0f, 0x7f, 0x0d, 0xe7, 0xff, 0xbe, 0xef, 0xbc, 0xdd,
+	0x5f, 0xd7, 0xc0, 0xee, 0xe7, 0xdc, 0xe7, 0x69, 0xaf, 0x81, 0x3d, 0x62, 0x4c, 0x0a, 0xc9, 0x49,
+	0xd2, 0x2d, 0xe4, 0xaf, 0xd8, 0x91, 0x0b, 0x8d, 0x71, 0x94, 0x8a, 0xd3, 0x1c, 0x57, 0xd3, 0xb8,
+	0x82, 0x4d, 0x91, 0x7a, 0xc6, 0x43, 0x49, 0xc5, 0x43, 0x76, 0xc8, 0x26, 0x93, 0x50, 0xde, 0x67,
+	0x81, 0x26, 0x75, 0x0d, 0x57, 0x1d, 0xaa, 0x75, 0x2f, 0xa2, 0x24, 0x4e, 0xe7, 0xb5, 0xcf, 0x69,
+	0x68, 0xc9, 0x8a, 0xae, 0xc2, 0x26, 0xa7, 0x09, 0x09, 0x79, 0x0e, 0xcb, 0x08, 0x2d, 0x1a, 0xd1,
+	0x5d, 0xb0, 0x79, 0x69, 0x81, 0x35, 0x6d, 0x1b, 0x37, 0x3f, 0xd9, 0x5b, 0x5c, 0xbe, 0xf2, 0x8e,
+	0xe3, 0x4a, 0x90, 0xda, 0x20, 0x11, 0x93, 0x44, 0x9c, 0x32, 0x99, 0x17, 0xac, 0x67, 0x1b, 0x54,
+	0x32, 0xa3, 0x6f, 0xa0, 0x11, 0x1a, 0x2c, 0x39, 0x6b, 0xba, 0xdc, 0x25, 0xa3, 0x9c, 0x49, 0x22,
+	0x2e, 0x80, 0xd1, 0x1d, 0xd8, 0xcc, 0x6e, 0x60, 0x1e, 0xbd, 0xae, 0xa3, 0x1d, 0x23, 0x7a, 0x68,
+	0xfa, 0x71, 0x11, 0xae, 0x66, 0xed, 0xb1, 0xc8, 0x7f, 0xac, 0xc7, 0x9a, 0x37, 0x0a, 0xd9, 0xac,
+	0x2b, 0x0e, 0x74, 0x0f, 0x9a, 0x3c, 0x8d, 0x65, 0x38, 0xc9, 0xb9, 0x77, 0x36, 0x74, 0x39, 0xd7,
+	0x28, 0x37, 0x5f, 0x0f, 0x5c, 0x40, 0xe2, 0x52, 0x24, 0x1a, 0xc0, 0x87, 0x1e, 0xf1, 0x4e, 0xe9,
+	0x81, 0xda, 0x30, 0x71, 0x1c, 0x63, 0x2a, 0x79, 0x48, 0x9f, 0x51, 0xa7, 0xa1, 0x53, 0xee, 0xec,
+	0x65, 0x8a, 0xb5, 0x97, 0x2b, 0xd6, 0xde, 0x01, 0x63, 0xd1, 0x8f, 0x24, 0x4a, 0x29, 0x5e, 0x1e,
+	0x88, 0x1e, 0x00, 0x22, 0x41, 0xc0, 0x69, 0x40, 0x4c, 0xf6, 0x36, 0x75, 0xba, 0x4f, 0x8d, 0x0e,
+	0x3b, 0x15, 0x10, 0x5e, 0x12, 0xa8, 0x78, 0x11, 0x92, 0x04, 0x61, 0x1c, 0x0c, 0x25, 0x91, 0xd4,
+	0x69, 0x56, 0x78, 0x19, 0x1a, 0x6e, 0x5c, 0x00, 0xa3, 0x5b, 0xb0, 0xc1, 0xa9, 0x08, 0x5f, 0xd0,
+	0x2c, 0x76, 0x4b, 0xc7, 0x7e, 0x54, 0x58, 0xa1, 0xb9, 0x17, 0x9b, 0x50, 0xc5, 0x48, 0x44, 0x24,
+	0x2d, 0x32, 0x62, 0x67, 0x8c, 0x54, 0x1c, 0xa8, 0x03, 0x4d, 0x1a, 0x7b, 0xcc, 0x57, 0x85, 0x15,
+	0xb1, 0xd4, 0xd9, 0xbe, 0x62, 0xed, 0x36, 0x6f, 0x7e, 0x6c, 0x94, 0xea, 0x16, 0x00, 0xb8, 0x14,
+	0x80, 0xba, 0xb0, 0x45, 0x9f, 0x4b, 0x1a, 0xfb, 0xd4, 0xcf, 0x67, 0xf6, 0x4f, 0x7d, 0xc6, 0x81,
+	0x91, 0xa4, 0x08, 0xc1, 0xe5, 0x18, 0xf7, 0x67, 0x0b, 0x50, 0x75, 0xb2, 0xe8, 0x36, 0x34, 0x8c,
+	0xd9, 0x2a, 0xd5, 0x5f, 0x2d, 0x4d, 0xc2, 0x08, 0xc2, 0x05, 0xac, 0x62, 0x80, 0xb3, 0x28, 0x4a,
+	0x93, 0x01, 0x8b, 0x42, 0x6f, 0xea, 0xd4, 0x2a, 0x0c, 0x60, 0xc3, 0x8d, 0x0b, 0x60, 0xf7, 0x77,
+	0x0b, 0x1a, 0xa6, 0x5b, 0xdd, 0x48, 0x49, 0x78, 0x40, 0xe5, 0x7c, 0x43, 0xb5, 0x4a, 0xad, 0xe3,
+	0xb2, 0x59, 0x09, 0x5a, 0x96, 0x2a, 0x93, 0x66, 0x43, 0x0f, 0x2b, 0x76, 0x74, 0x19, 0xd6, 0x85,
+	0xa4, 0x89, 0xf9, 0xf2, 0x2c, 0x0c, 0x8a, 0x4c, 0x4e, 0xce, 0xe6, 0x72, 0x61, 0xbe, 0x39, 0x55,
+	0x87, 0x1b, 0xc3, 0x86, 0x31, 0x0c, 0xd4, 0x02, 0xc8, 0xc7, 0x31, 0x57, 0x54, 0xc3, 0x82, 0xbe,
+	0x05, 0x20, 0x52, 0xf2, 0x70, 0x94, 0x4a, 0x2a, 0x66, 0xc3, 0xf9, 0x6c, 0xc9, 0x60, 0xa9, 0xdf,
+	0x99, 0xc3, 0xb0, 0x11, 0xe2, 0xbe, 0xb4, 0xe0, 0xe2, 0x32, 0x90, 0x1a, 0x15, 0xa7, 0x82, 0x45,
+	0xe9, 0xa2, 0xe9, 0xec, 0xb5, 0x2e, 0x9b, 0xd1, 0x3d, 0xd8, 0xf6, 0xd9, 0x59, 0x2c, 0xc8, 0x24,
+	0x89, 0xe6, 0xa2, 0x90, 0xb5, 0x72, 0xd9, 0x68, 0xe5, 0xa8, 0x8c, 0xc1, 0xd5, 0x30, 0xf7, 0x0b,
+	0xd8, 0xae, 0xe0, 0x90, 0x0d, 0xab, 0x24, 0x8a, 0x66, 0xbf, 0x5e, 0x1d, 0xdd, 0xef, 0xa0, 0x61,
+	0x5e, 0x3c, 0x74, 0x1d, 0x2e, 0x08, 0x49, 0x64, 0x9a, 0xf5, 0xd8, 0x2c, 0x6a, 0xdf, 0x02, 0x98,
+	0x0a, 0x3c, 0xc3, 0xb9, 0x01, 0x6c, 0x18, 0xd7, 0x6f, 0xc9, 0xe3, 0x67, 0x2d, 0xfd, 0xd8, 0xb8,
+	0x0e, 0x1f, 0x68, 0xed, 0x3d, 0x58, 0xf6, 0x52, 0x2e, 0x73, 0xb9, 0xbf, 0x59, 0xb0, 0x86, 0x69,
+	0x10, 0x0a, 0xc9, 0xa7, 0xe8, 0x10, 0x60, 0xde, 0x58, 0x7e, 0x0f, 0x3e, 0x2f, 0x28, 0x42, 0x06,
+	0x5c, 0x28, 0xa8, 0xe8, 0xc6, 0x92, 0x4f, 0xb1, 0x11, 0xb6, 0x73, 0x02, 0x5b, 0x25, 0xb7, 0x9a,
+	0xd0, 0x53, 0x3a, 0x9d, 0xed, 0xb2, 0x3a, 0xa2, 0x1b, 0x70, 0xfe, 0x99, 0x12, 0x4a, 0xa7, 0x56,
+	0x79, 0xb9, 0xca, 0x8f, 0x37, 0xce, 0x90, 0xb7, 0x6b, 0xb7, 0x2c, 0xf7, 0x6f, 0x0b, 0x2e, 0xbd,
+	0x45, 0xbd, 0x91, 0x0f, 0x2d, 0xfd, 0xf4, 0xea, 0xa7, 0x28, 0x8c, 0x83, 0x01, 0xe5, 0x87, 0x83,
+	0x47, 0x87, 0x2c, 0xf6, 0x52, 0xce, 0x69, 0xec, 0x65, 0xf5, 0x15, 0xe9, 0x65, 0xd9, 0x3e, 0x62,
+	0xe9, 0x28, 0xa2, 0x99, 0x70, 0xff, 0x47, 0x0e, 0x55, 0x45, 0x7f, 0x09, 0xbc, 0xbd, 0x4a, 0xed,
+	0x7d, 0xaa, 0xbc, 0x3b, 0x87, 0xfb, 0x04, 0xb6, 0x4a, 0x6a, 0x86, 0x10, 0x9c, 0x93, 0xd3, 0x24,
+	0x17, 0x04, 0x7d, 0x46, 0x37, 0xa0, 0xce, 0x0a, 0x0b, 0x7d, 0xa9, 0x52, 0x75, 0xa8, 0x3f, 0xb1,
+	0x71, 0x8e, 0xbb, 0xf6, 0x35, 0x6c, 0x16, 0x36, 0x0e, 0x6d, 0x40, 0xfd, 0x51, 0xff, 0x87, 0xfe,
+	0xf1, 0xe3, 0xbe, 0xbd, 0x82, 0x6c, 0x68, 0xf4, 0xfa, 0xbd, 0x87, 0xbd, 0xce, 0xfd, 0xde, 0x49,
+	0xaf, 0x7f, 0xd7, 0xb6, 0xd0, 0x3a, 0x9c, 0xc7, 0xdd, 0xce, 0xd1, 0x4f, 0x76, 0xed, 0xda, 0x55,
+	0x68, 0x16, 0x75, 0x5a, 0x39, 0x1f, 0xec, 0x3f, 0x1c, 0x9e, 0xd8, 0x2b, 0xa8, 0x0e, 0xab, 0x4f,
+	0x8e, 0xb1, 0x6d, 0x1d, 0x38, 0x7f, 0xbc, 0x6e, 0x59, 0xaf, 0x5e, 0xb7, 0xac, 0xbf, 0x5e, 0xb7,
+	0xac, 0x5f, 0xde, 0xb4, 0x56, 0x5e, 0xbd, 0x69, 0xad, 0xfc, 0xf9, 0xa6, 0xb5, 0x32, 0xba, 0xa0,
+	0x9b, 0xda, 0xff, 0x77, 0x00, 0xbc, 0xe5, 0x66, 0xd6, 0x5b, 0x0c, 0x00, 0x00,
+}
+
 func (m *RetentionOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -512,57 +1084,62 @@ func (m *RetentionOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *RetentionOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RetentionOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.RetentionPeriodNanos != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.RetentionPeriodNanos))
-	}
-	if m.BlockSizeNanos != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
-	}
-	if m.BufferFutureNanos != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.BufferFutureNanos))
+	if m.FutureRetentionPeriodNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.FutureRetentionPeriodNanos))
+		i--
+		dAtA[i] = 0x38
 	}
-	if m.BufferPastNanos != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.BufferPastNanos))
+	if m.BlockDataExpiryAfterNotAccessPeriodNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockDataExpiryAfterNotAccessPeriodNanos))
+		i--
+		dAtA[i] = 0x30
 	}
 	if m.BlockDataExpiry {
-		dAtA[i] = 0x28
-		i++
+		i--
 		if m.BlockDataExpiry {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x28
 	}
-	if m.BlockDataExpiryAfterNotAccessPeriodNanos != 0 {
-		dAtA[i] = 0x30
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockDataExpiryAfterNotAccessPeriodNanos))
+	if m.BufferPastNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BufferPastNanos))
+		i--
+		dAtA[i] = 0x20
 	}
-	if m.FutureRetentionPeriodNanos != 0 {
-		dAtA[i] = 0x38
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.FutureRetentionPeriodNanos))
+	if m.BufferFutureNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BufferFutureNanos))
+		i--
+		dAtA[i] = 0x18
 	}
-	return i, nil
-}
-
-func (m *IndexOptions) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	if m.BlockSizeNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.RetentionPeriodNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.RetentionPeriodNanos))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *IndexOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -570,32 +1147,37 @@ func (m *IndexOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *IndexOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if m.BlockSizeNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
+		i--
+		dAtA[i] = 0x10
+	}
 	if m.Enabled {
-		dAtA[i] = 0x8
-		i++
+		i--
 		if m.Enabled {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
-	}
-	if m.BlockSizeNanos != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *NamespaceOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -603,169 +1185,221 @@ func (m *NamespaceOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *NamespaceOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NamespaceOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.BootstrapEnabled {
-		dAtA[i] = 0x8
-		i++
-		if m.BootstrapEnabled {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if m.ExtendedOptions != nil {
+		{
+			size, err := m.ExtendedOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i++
+		i--
+		dAtA[i] = 0x3e
+		i--
+		dAtA[i] = 0xc2
 	}
-	if m.FlushEnabled {
-		dAtA[i] = 0x10
-		i++
-		if m.FlushEnabled {
+	if m.EncodingScheme != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.EncodingScheme))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if m.LateWritesEnabled {
+		i--
+		if m.LateWritesEnabled {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.ResizeState != nil {
+		{
+			size, err := m.ResizeState.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
 	}
-	if m.WritesToCommitLog {
-		dAtA[i] = 0x18
-		i++
-		if m.WritesToCommitLog {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if m.StagingState != nil {
+		{
+			size, err := m.StagingState.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i++
+		i--
+		dAtA[i] = 0x72
 	}
-	if m.CleanupEnabled {
-		dAtA[i] = 0x20
-		i++
-		if m.CleanupEnabled {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if m.AggregationOptions != nil {
+		{
+			size, err := m.AggregationOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	if m.CacheBlocksOnRetrieve != nil {
+		{
+			size, err := m.CacheBlocksOnRetrieve.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i++
+		i--
+		dAtA[i] = 0x62
 	}
-	if m.RepairEnabled {
-		dAtA[i] = 0x28
-		i++
-		if m.RepairEnabled {
+	if m.RuntimeOptions != nil {
+		{
+			size, err := m.RuntimeOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.ColdWritesEnabled {
+		i--
+		if m.ColdWritesEnabled {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x50
 	}
-	if m.RetentionOptions != nil {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.RetentionOptions.Size()))
-		n1, err := m.RetentionOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.SchemaOptions != nil {
+		{
+			size, err := m.SchemaOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i += n1
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.IndexOptions != nil {
+		{
+			size, err := m.IndexOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
 	}
 	if m.SnapshotEnabled {
-		dAtA[i] = 0x38
-		i++
+		i--
 		if m.SnapshotEnabled {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
-	}
-	if m.IndexOptions != nil {
-		dAtA[i] = 0x42
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.IndexOptions.Size()))
-		n2, err := m.IndexOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n2
+		i--
+		dAtA[i] = 0x38
 	}
-	if m.SchemaOptions != nil {
-		dAtA[i] = 0x4a
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.SchemaOptions.Size()))
-		n3, err := m.SchemaOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.RetentionOptions != nil {
+		{
+			size, err := m.RetentionOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i += n3
+		i--
+		dAtA[i] = 0x32
 	}
-	if m.ColdWritesEnabled {
-		dAtA[i] = 0x50
-		i++
-		if m.ColdWritesEnabled {
+	if m.RepairEnabled {
+		i--
+		if m.RepairEnabled {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
-	}
-	if m.RuntimeOptions != nil {
-		dAtA[i] = 0x5a
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.RuntimeOptions.Size()))
-		n4, err := m.RuntimeOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n4
+		i--
+		dAtA[i] = 0x28
 	}
-	if m.CacheBlocksOnRetrieve != nil {
-		dAtA[i] = 0x62
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.CacheBlocksOnRetrieve.Size()))
-		n5, err := m.CacheBlocksOnRetrieve.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.CleanupEnabled {
+		i--
+		if m.CleanupEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
-		i += n5
+		i--
+		dAtA[i] = 0x20
 	}
-	if m.AggregationOptions != nil {
-		dAtA[i] = 0x6a
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.AggregationOptions.Size()))
-		n6, err := m.AggregationOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.WritesToCommitLog {
+		i--
+		if m.WritesToCommitLog {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
-		i += n6
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.StagingState != nil {
-		dAtA[i] = 0x72
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.StagingState.Size()))
-		n7, err := m.StagingState.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.FlushEnabled {
+		i--
+		if m.FlushEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
-		i += n7
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.ExtendedOptions != nil {
-		dAtA[i] = 0xc2
-		i++
-		dAtA[i] = 0x3e
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.ExtendedOptions.Size()))
-		n8, err := m.ExtendedOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if m.BootstrapEnabled {
+		i--
+		if m.BootstrapEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
-		i += n8
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *AggregationOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -773,29 +1407,93 @@ func (m *AggregationOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *AggregationOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AggregationOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Aggregations) > 0 {
-		for _, msg := range m.Aggregations {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintNamespace(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
+	if m.RollupPolicy != nil {
+		{
+			size, err := m.RollupPolicy.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
-			i += n
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x12
 	}
-	return i, nil
+	if len(m.Aggregations) > 0 {
+		for iNdEx := len(m.Aggregations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Aggregations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintNamespace(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RollupPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RollupPolicy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RollupPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.RawRetentionNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.RawRetentionNanos))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.StepNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.StepNanos))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.RollupAfterNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.RollupAfterNanos))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TargetNamespace) > 0 {
+		i -= len(m.TargetNamespace)
+		copy(dAtA[i:], m.TargetNamespace)
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.TargetNamespace)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
 func (m *Aggregation) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -803,37 +1501,44 @@ func (m *Aggregation) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *Aggregation) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Aggregation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if m.Attributes != nil {
+		{
+			size, err := m.Attributes.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.Aggregated {
-		dAtA[i] = 0x8
-		i++
+		i--
 		if m.Aggregated {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
-	}
-	if m.Attributes != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.Attributes.Size()))
-		n9, err := m.Attributes.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n9
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *AggregatedAttributes) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -841,32 +1546,39 @@ func (m *AggregatedAttributes) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *AggregatedAttributes) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AggregatedAttributes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.ResolutionNanos != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.ResolutionNanos))
-	}
 	if m.DownsampleOptions != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.DownsampleOptions.Size()))
-		n10, err := m.DownsampleOptions.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.DownsampleOptions.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i += n10
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ResolutionNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.ResolutionNanos))
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *DownsampleOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -874,27 +1586,32 @@ func (m *DownsampleOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *DownsampleOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DownsampleOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.All {
-		dAtA[i] = 0x8
-		i++
+		i--
 		if m.All {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
-		i++
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *StagingState) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -902,22 +1619,60 @@ func (m *StagingState) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *StagingState) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StagingState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.Status != 0 {
-		dAtA[i] = 0x8
-		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ResizeState) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return i, nil
+	return dAtA[:n], nil
+}
+
+func (m *ResizeState) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ResizeState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.IndexBlockSizeNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.IndexBlockSizeNanos))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.BlockSizeNanos != 0 {
+		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
 func (m *Registry) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -925,45 +1680,48 @@ func (m *Registry) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *Registry) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Registry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Namespaces) > 0 {
-		for k, _ := range m.Namespaces {
-			dAtA[i] = 0xa
-			i++
+		for k := range m.Namespaces {
 			v := m.Namespaces[k]
-			msgSize := 0
-			if v != nil {
-				msgSize = v.Size()
-				msgSize += 1 + sovNamespace(uint64(msgSize))
-			}
-			mapSize := 1 + len(k) + sovNamespace(uint64(len(k))) + msgSize
-			i = encodeVarintNamespace(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintNamespace(dAtA, i, uint64(len(k)))
-			i += copy(dAtA[i:], k)
+			baseI := i
 			if v != nil {
-				dAtA[i] = 0x12
-				i++
-				i = encodeVarintNamespace(dAtA, i, uint64(v.Size()))
-				n11, err := v.MarshalTo(dAtA[i:])
-				if err != nil {
-					return 0, err
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintNamespace(dAtA, i, uint64(size))
 				}
-				i += n11
+				i--
+				dAtA[i] = 0x12
 			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintNamespace(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintNamespace(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *NamespaceRuntimeOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -971,37 +1729,46 @@ func (m *NamespaceRuntimeOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *NamespaceRuntimeOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NamespaceRuntimeOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.WriteIndexingPerCPUConcurrency != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.WriteIndexingPerCPUConcurrency.Size()))
-		n12, err := m.WriteIndexingPerCPUConcurrency.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n12
-	}
 	if m.FlushIndexingPerCPUConcurrency != nil {
+		{
+			size, err := m.FlushIndexingPerCPUConcurrency.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
+		}
+		i--
 		dAtA[i] = 0x12
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.FlushIndexingPerCPUConcurrency.Size()))
-		n13, err := m.FlushIndexingPerCPUConcurrency.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	}
+	if m.WriteIndexingPerCPUConcurrency != nil {
+		{
+			size, err := m.WriteIndexingPerCPUConcurrency.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i += n13
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *ExtendedOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -1009,39 +1776,52 @@ func (m *ExtendedOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *ExtendedOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExtendedOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Type) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Type)))
-		i += copy(dAtA[i:], m.Type)
-	}
 	if m.Options != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintNamespace(dAtA, i, uint64(m.Options.Size()))
-		n14, err := m.Options.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Options.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintNamespace(dAtA, i, uint64(size))
 		}
-		i += n14
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintNamespace(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func encodeVarintNamespace(dAtA []byte, offset int, v uint64) int {
+	offset -= sovNamespace(v)
+	base := offset
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
 		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
-	return offset + 1
+	return base
 }
 func (m *RetentionOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.RetentionPeriodNanos != 0 {
@@ -1069,6 +1849,9 @@ func (m *RetentionOptions) Size() (n int) {
 }
 
 func (m *IndexOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Enabled {
@@ -1081,6 +1864,9 @@ func (m *IndexOptions) Size() (n int) {
 }
 
 func (m *NamespaceOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.BootstrapEnabled {
@@ -1132,6 +1918,16 @@ func (m *NamespaceOptions) Size() (n int) {
 		l = m.StagingState.Size()
 		n += 1 + l + sovNamespace(uint64(l))
 	}
+	if m.ResizeState != nil {
+		l = m.ResizeState.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.LateWritesEnabled {
+		n += 3
+	}
+	if m.EncodingScheme != 0 {
+		n += 2 + sovNamespace(uint64(m.EncodingScheme))
+	}
 	if m.ExtendedOptions != nil {
 		l = m.ExtendedOptions.Size()
 		n += 2 + l + sovNamespace(uint64(l))
@@ -1140,6 +1936,9 @@ func (m *NamespaceOptions) Size() (n int) {
 }
 
 func (m *AggregationOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Aggregations) > 0 {
@@ -1148,10 +1947,39 @@ func (m *AggregationOptions) Size() (n int) {
 			n += 1 + l + sovNamespace(uint64(l))
 		}
 	}
+	if m.RollupPolicy != nil {
+		l = m.RollupPolicy.Size()
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	return n
+}
+
+func (m *RollupPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TargetNamespace)
+	if l > 0 {
+		n += 1 + l + sovNamespace(uint64(l))
+	}
+	if m.RollupAfterNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.RollupAfterNanos))
+	}
+	if m.StepNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.StepNanos))
+	}
+	if m.RawRetentionNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.RawRetentionNanos))
+	}
 	return n
 }
 
 func (m *Aggregation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Aggregated {
@@ -1165,6 +1993,9 @@ func (m *Aggregation) Size() (n int) {
 }
 
 func (m *AggregatedAttributes) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.ResolutionNanos != 0 {
@@ -1178,6 +2009,9 @@ func (m *AggregatedAttributes) Size() (n int) {
 }
 
 func (m *DownsampleOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.All {
@@ -1187,6 +2021,9 @@ func (m *DownsampleOptions) Size() (n int) {
 }
 
 func (m *StagingState) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Status != 0 {
@@ -1195,7 +2032,25 @@ func (m *StagingState) Size() (n int) {
 	return n
 }
 
+func (m *ResizeState) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlockSizeNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.BlockSizeNanos))
+	}
+	if m.IndexBlockSizeNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.IndexBlockSizeNanos))
+	}
+	return n
+}
+
 func (m *Registry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Namespaces) > 0 {
@@ -1215,6 +2070,9 @@ func (m *Registry) Size() (n int) {
 }
 
 func (m *NamespaceRuntimeOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.WriteIndexingPerCPUConcurrency != nil {
@@ -1229,6 +2087,9 @@ func (m *NamespaceRuntimeOptions) Size() (n int) {
 }
 
 func (m *ExtendedOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Type)
@@ -1243,14 +2104,7 @@ func (m *ExtendedOptions) Size() (n int) {
 }
 
 func sovNamespace(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
-	}
-	return n
+	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozNamespace(x uint64) (n int) {
 	return sovNamespace(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -1270,7 +2124,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1298,7 +2152,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.RetentionPeriodNanos |= (int64(b) & 0x7F) << shift
+				m.RetentionPeriodNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1317,7 +2171,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BlockSizeNanos |= (int64(b) & 0x7F) << shift
+				m.BlockSizeNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1336,7 +2190,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BufferFutureNanos |= (int64(b) & 0x7F) << shift
+				m.BufferFutureNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1355,7 +2209,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BufferPastNanos |= (int64(b) & 0x7F) << shift
+				m.BufferPastNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1374,7 +2228,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1394,7 +2248,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BlockDataExpiryAfterNotAccessPeriodNanos |= (int64(b) & 0x7F) << shift
+				m.BlockDataExpiryAfterNotAccessPeriodNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1413,7 +2267,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.FutureRetentionPeriodNanos |= (int64(b) & 0x7F) << shift
+				m.FutureRetentionPeriodNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1424,7 +2278,7 @@ func (m *RetentionOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -1454,7 +2308,7 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1482,7 +2336,7 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1502,7 +2356,7 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BlockSizeNanos |= (int64(b) & 0x7F) << shift
+				m.BlockSizeNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1513,7 +2367,7 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -1543,7 +2397,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1571,7 +2425,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1591,7 +2445,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1611,7 +2465,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1631,7 +2485,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1651,7 +2505,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1671,7 +2525,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1680,6 +2534,9 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1704,7 +2561,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1724,7 +2581,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1733,6 +2590,9 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1757,7 +2617,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1766,6 +2626,9 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1790,7 +2653,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1810,7 +2673,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1819,6 +2682,9 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1843,7 +2709,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1852,11 +2718,14 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
 			if m.CacheBlocksOnRetrieve == nil {
-				m.CacheBlocksOnRetrieve = &google_protobuf1.BoolValue{}
+				m.CacheBlocksOnRetrieve = &types.BoolValue{}
 			}
 			if err := m.CacheBlocksOnRetrieve.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -1876,7 +2745,154 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AggregationOptions == nil {
+				m.AggregationOptions = &AggregationOptions{}
+			}
+			if err := m.AggregationOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StagingState", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StagingState == nil {
+				m.StagingState = &StagingState{}
+			}
+			if err := m.StagingState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResizeState", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ResizeState == nil {
+				m.ResizeState = &ResizeState{}
+			}
+			if err := m.ResizeState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LateWritesEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.LateWritesEnabled = bool(v != 0)
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncodingScheme", wireType)
+			}
+			m.EncodingScheme = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncodingScheme |= EncodingScheme(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 1000:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExtendedOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1884,20 +2900,73 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 			if msglen < 0 {
 				return ErrInvalidLengthNamespace
 			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ExtendedOptions == nil {
+				m.ExtendedOptions = &ExtendedOptions{}
+			}
+			if err := m.ExtendedOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregationOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.AggregationOptions == nil {
-				m.AggregationOptions = &AggregationOptions{}
-			}
-			if err := m.AggregationOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 14:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregationOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregationOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StagingState", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregations", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1909,7 +2978,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1918,19 +2987,20 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.StagingState == nil {
-				m.StagingState = &StagingState{}
-			}
-			if err := m.StagingState.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Aggregations = append(m.Aggregations, &Aggregation{})
+			if err := m.Aggregations[len(m.Aggregations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 1000:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExtendedOptions", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RollupPolicy", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1942,7 +3012,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1951,13 +3021,16 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.ExtendedOptions == nil {
-				m.ExtendedOptions = &ExtendedOptions{}
+			if m.RollupPolicy == nil {
+				m.RollupPolicy = &RollupPolicy{}
 			}
-			if err := m.ExtendedOptions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.RollupPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1967,7 +3040,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -1982,7 +3055,7 @@ func (m *NamespaceOptions) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AggregationOptions) Unmarshal(dAtA []byte) error {
+func (m *RollupPolicy) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1997,7 +3070,7 @@ func (m *AggregationOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2005,17 +3078,17 @@ func (m *AggregationOptions) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AggregationOptions: wiretype end group for non-group")
+			return fmt.Errorf("proto: RollupPolicy: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AggregationOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RollupPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Aggregations", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetNamespace", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowNamespace
@@ -2025,30 +3098,88 @@ func (m *AggregationOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthNamespace
 			}
-			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Aggregations = append(m.Aggregations, &Aggregation{})
-			if err := m.Aggregations[len(m.Aggregations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.TargetNamespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RollupAfterNanos", wireType)
+			}
+			m.RollupAfterNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RollupAfterNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StepNanos", wireType)
+			}
+			m.StepNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StepNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawRetentionNanos", wireType)
+			}
+			m.RawRetentionNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RawRetentionNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2078,7 +3209,7 @@ func (m *Aggregation) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2106,7 +3237,7 @@ func (m *Aggregation) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2126,7 +3257,7 @@ func (m *Aggregation) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2135,6 +3266,9 @@ func (m *Aggregation) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2151,7 +3285,7 @@ func (m *Aggregation) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2181,7 +3315,7 @@ func (m *AggregatedAttributes) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2209,7 +3343,7 @@ func (m *AggregatedAttributes) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ResolutionNanos |= (int64(b) & 0x7F) << shift
+				m.ResolutionNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2228,7 +3362,7 @@ func (m *AggregatedAttributes) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2237,6 +3371,9 @@ func (m *AggregatedAttributes) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2253,7 +3390,7 @@ func (m *AggregatedAttributes) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2283,7 +3420,7 @@ func (m *DownsampleOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2311,7 +3448,7 @@ func (m *DownsampleOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2323,7 +3460,7 @@ func (m *DownsampleOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2353,7 +3490,7 @@ func (m *StagingState) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2381,7 +3518,95 @@ func (m *StagingState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= (StagingStatus(b) & 0x7F) << shift
+				m.Status |= StagingStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNamespace(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthNamespace
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResizeState) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNamespace
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResizeState: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResizeState: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockSizeNanos", wireType)
+			}
+			m.BlockSizeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockSizeNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexBlockSizeNanos", wireType)
+			}
+			m.IndexBlockSizeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IndexBlockSizeNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2392,7 +3617,7 @@ func (m *StagingState) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2422,7 +3647,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2450,7 +3675,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2459,6 +3684,9 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2479,7 +3707,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 					}
 					b := dAtA[iNdEx]
 					iNdEx++
-					wire |= (uint64(b) & 0x7F) << shift
+					wire |= uint64(b&0x7F) << shift
 					if b < 0x80 {
 						break
 					}
@@ -2496,7 +3724,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						stringLenmapkey |= uint64(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
@@ -2506,6 +3734,9 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 						return ErrInvalidLengthNamespace
 					}
 					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthNamespace
+					}
 					if postStringIndexmapkey > l {
 						return io.ErrUnexpectedEOF
 					}
@@ -2522,7 +3753,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						mapmsglen |= (int(b) & 0x7F) << shift
+						mapmsglen |= int(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
@@ -2531,7 +3762,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 						return ErrInvalidLengthNamespace
 					}
 					postmsgIndex := iNdEx + mapmsglen
-					if mapmsglen < 0 {
+					if postmsgIndex < 0 {
 						return ErrInvalidLengthNamespace
 					}
 					if postmsgIndex > l {
@@ -2548,7 +3779,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 					if err != nil {
 						return err
 					}
-					if skippy < 0 {
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
 						return ErrInvalidLengthNamespace
 					}
 					if (iNdEx + skippy) > postIndex {
@@ -2565,7 +3796,7 @@ func (m *Registry) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2595,7 +3826,7 @@ func (m *NamespaceRuntimeOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2623,7 +3854,7 @@ func (m *NamespaceRuntimeOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2632,11 +3863,14 @@ func (m *NamespaceRuntimeOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
 			if m.WriteIndexingPerCPUConcurrency == nil {
-				m.WriteIndexingPerCPUConcurrency = &google_protobuf1.DoubleValue{}
+				m.WriteIndexingPerCPUConcurrency = &types.DoubleValue{}
 			}
 			if err := m.WriteIndexingPerCPUConcurrency.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -2656,7 +3890,7 @@ func (m *NamespaceRuntimeOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2665,11 +3899,14 @@ func (m *NamespaceRuntimeOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
 			if m.FlushIndexingPerCPUConcurrency == nil {
-				m.FlushIndexingPerCPUConcurrency = &google_protobuf1.DoubleValue{}
+				m.FlushIndexingPerCPUConcurrency = &types.DoubleValue{}
 			}
 			if err := m.FlushIndexingPerCPUConcurrency.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -2681,7 +3918,7 @@ func (m *NamespaceRuntimeOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2711,7 +3948,7 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2739,7 +3976,7 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2749,6 +3986,9 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -2768,7 +4008,7 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2777,11 +4017,14 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthNamespace
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthNamespace
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Options == nil {
-				m.Options = &google_protobuf.Struct{}
+				m.Options = &types.Struct{}
 			}
 			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -2793,7 +4036,7 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthNamespace
 			}
 			if (iNdEx + skippy) > l {
@@ -2811,6 +4054,7 @@ func (m *ExtendedOptions) Unmarshal(dAtA []byte) error {
 func skipNamespace(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -2842,10 +4086,8 @@ func skipNamespace(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -2862,124 +4104,34 @@ func skipNamespace(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthNamespace
 			}
-			return iNdEx, nil
+			iNdEx += length
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowNamespace
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipNamespace(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupNamespace
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthNamespace
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthNamespace = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowNamespace   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthNamespace        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowNamespace          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupNamespace = fmt.Errorf("proto: unexpected end of group")
 )
-
-func init() {
-	proto.RegisterFile("github.com/m3db/m3/src/dbnode/generated/proto/namespace/namespace.proto", fileDescriptorNamespace)
-}
-
-var fileDescriptorNamespace = []byte{
-	// 1001 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x96, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0x80, 0xbb, 0xf9, 0x73, 0x72, 0xec, 0x24, 0xce, 0xa8, 0x10, 0x2b, 0x2d, 0xa6, 0x5a, 0x7e,
-	0x14, 0x55, 0xc8, 0xa6, 0xc9, 0x0d, 0x14, 0xa9, 0xe0, 0x24, 0x26, 0x72, 0x29, 0x8e, 0x35, 0x69,
-	0x29, 0xe4, 0x6e, 0x76, 0xf7, 0x78, 0xb3, 0xea, 0x7a, 0x66, 0x35, 0x33, 0xdb, 0x24, 0x3c, 0x43,
-	0x2f, 0x78, 0x0f, 0x5e, 0x84, 0x4b, 0x1e, 0x01, 0x05, 0x21, 0x71, 0xc3, 0x3b, 0xa0, 0x9d, 0xf5,
-	0x3a, 0xfb, 0xe3, 0x96, 0x88, 0x9b, 0x68, 0x72, 0xce, 0x77, 0x7e, 0xe6, 0xfc, 0xcc, 0x1a, 0x8e,
-	0xfd, 0x40, 0x9f, 0xc7, 0x4e, 0xc7, 0x15, 0x93, 0xee, 0x64, 0xdf, 0x73, 0xba, 0x93, 0xfd, 0xae,
-	0x92, 0x6e, 0xd7, 0x73, 0xb8, 0xf0, 0xb0, 0xeb, 0x23, 0x47, 0xc9, 0x34, 0x7a, 0xdd, 0x48, 0x0a,
-	0x2d, 0xba, 0x9c, 0x4d, 0x50, 0x45, 0xcc, 0xc5, 0x9b, 0x53, 0xc7, 0x68, 0xc8, 0xda, 0x4c, 0xb0,
-	0x73, 0xdf, 0x17, 0xc2, 0x0f, 0x31, 0x35, 0x71, 0xe2, 0x71, 0x57, 0x69, 0x19, 0xbb, 0x3a, 0x05,
-	0x77, 0xda, 0x65, 0xed, 0x85, 0x64, 0x51, 0x84, 0x52, 0x4d, 0xf5, 0x47, 0xff, 0x37, 0x23, 0xe5,
-	0x9e, 0xe3, 0x84, 0xa5, 0x5e, 0xec, 0x37, 0x8b, 0xd0, 0xa4, 0xa8, 0x91, 0xeb, 0x40, 0xf0, 0x93,
-	0x28, 0xf9, 0xab, 0xc8, 0x1e, 0xdc, 0x95, 0x99, 0x6c, 0x84, 0x32, 0x10, 0xde, 0x90, 0x71, 0xa1,
-	0x5a, 0xd6, 0x03, 0x6b, 0x77, 0x91, 0xce, 0xd5, 0x91, 0x4f, 0x61, 0xc3, 0x09, 0x85, 0xfb, 0xea,
-	0x34, 0xf8, 0x19, 0x53, 0x7a, 0xc1, 0xd0, 0x25, 0x29, 0xf9, 0x0c, 0xb6, 0x9c, 0x78, 0x3c, 0x46,
-	0xf9, 0x6d, 0xac, 0x63, 0x39, 0x45, 0x17, 0x0d, 0x5a, 0x55, 0x90, 0x5d, 0xd8, 0x4c, 0x85, 0x23,
-	0xa6, 0x74, 0xca, 0x2e, 0x19, 0xb6, 0x2c, 0x36, 0x64, 0x12, 0xe9, 0x88, 0x69, 0xd6, 0xbf, 0x8c,
-	0x02, 0x79, 0xd5, 0x5a, 0x7e, 0x60, 0xed, 0xae, 0xd2, 0xb2, 0x98, 0x9c, 0xc1, 0x6e, 0x49, 0xd4,
-	0x1b, 0x6b, 0x94, 0x43, 0xa1, 0x7b, 0xae, 0x8b, 0x4a, 0xe5, 0x6f, 0xbc, 0x62, 0x82, 0xdd, 0x9a,
-	0x27, 0x4f, 0x60, 0x67, 0x6c, 0xd2, 0xa7, 0xf3, 0xea, 0x57, 0x33, 0xde, 0xde, 0x41, 0xd8, 0x23,
-	0x68, 0x0c, 0xb8, 0x87, 0x97, 0x59, 0x27, 0x5a, 0x50, 0x43, 0xce, 0x9c, 0x10, 0x3d, 0x53, 0xfc,
-	0x55, 0x9a, 0xfd, 0x7b, 0xdb, 0x7a, 0xdb, 0xff, 0xac, 0x40, 0x73, 0x98, 0xf5, 0x3e, 0x73, 0xfb,
-	0x10, 0x9a, 0x8e, 0x10, 0x5a, 0x69, 0xc9, 0xa2, 0x7e, 0xc1, 0x7f, 0x45, 0x4e, 0x6c, 0x68, 0x8c,
-	0xc3, 0x58, 0x9d, 0x67, 0xdc, 0x82, 0xe1, 0x0a, 0xb2, 0xa4, 0xa9, 0x17, 0x32, 0xd0, 0xa8, 0x9e,
-	0x8b, 0x43, 0x31, 0x99, 0x04, 0xfa, 0x99, 0xf0, 0x4d, 0x53, 0x57, 0x69, 0x55, 0x91, 0xa4, 0xee,
-	0x86, 0xc8, 0x78, 0x3c, 0x8b, 0xbd, 0x64, 0xd0, 0x92, 0x94, 0x7c, 0x0c, 0xeb, 0x12, 0x23, 0x16,
-	0xc8, 0x0c, 0x4b, 0x1b, 0x5a, 0x14, 0x92, 0x63, 0x68, 0xca, 0xd2, 0x00, 0x9b, 0xb6, 0xd5, 0xf7,
-	0xee, 0x75, 0x6e, 0x96, 0xaf, 0x3c, 0xe3, 0xb4, 0x62, 0x94, 0x4c, 0x90, 0xe2, 0x2c, 0x52, 0xe7,
-	0x42, 0x67, 0x01, 0x6b, 0xe9, 0x04, 0x95, 0xc4, 0xe4, 0x2b, 0x68, 0x04, 0xb9, 0x2e, 0xb5, 0x56,
-	0x4d, 0xb8, 0xed, 0x5c, 0xb8, 0x7c, 0x13, 0x69, 0x01, 0x26, 0x4f, 0x60, 0x3d, 0xdd, 0xc0, 0xcc,
-	0x7a, 0xcd, 0x58, 0xb7, 0x72, 0xd6, 0xa7, 0x79, 0x3d, 0x2d, 0xe2, 0x49, 0xad, 0x5d, 0x11, 0x7a,
-	0x2f, 0x4d, 0x59, 0xb3, 0x44, 0x21, 0xad, 0x75, 0x45, 0x41, 0x9e, 0xc2, 0x86, 0x8c, 0xb9, 0x0e,
-	0x26, 0x59, 0xef, 0x5b, 0x75, 0x13, 0xce, 0xce, 0x85, 0x9b, 0x8d, 0x07, 0x2d, 0x90, 0xb4, 0x64,
-	0x49, 0x46, 0xf0, 0x9e, 0xcb, 0xdc, 0x73, 0x3c, 0x48, 0x26, 0x4c, 0x9d, 0x70, 0x8a, 0x5a, 0x06,
-	0xf8, 0x1a, 0x5b, 0x0d, 0xe3, 0x72, 0xa7, 0x93, 0xbe, 0x58, 0x9d, 0xec, 0xc5, 0xea, 0x1c, 0x08,
-	0x11, 0xfe, 0xc0, 0xc2, 0x18, 0xe9, 0x7c, 0x43, 0xf2, 0x3d, 0x10, 0xe6, 0xfb, 0x12, 0x7d, 0x96,
-	0xef, 0xde, 0xba, 0x71, 0xf7, 0x41, 0x2e, 0xc3, 0x5e, 0x05, 0xa2, 0x73, 0x0c, 0x93, 0xbe, 0x28,
-	0xcd, 0xfc, 0x80, 0xfb, 0xa7, 0x9a, 0x69, 0x6c, 0x6d, 0x54, 0xfa, 0x72, 0x9a, 0x53, 0xd3, 0x02,
-	0x4c, 0xfa, 0xb0, 0x89, 0x97, 0x1a, 0xb9, 0x87, 0x5e, 0x96, 0xc8, 0xdf, 0xb5, 0xe9, 0xc5, 0x6e,
-	0x1c, 0xf4, 0x8b, 0x08, 0x2d, 0xdb, 0xd8, 0x23, 0x20, 0xd5, 0x6c, 0xc9, 0x63, 0x68, 0xe4, 0xf2,
-	0x4d, 0x5e, 0xd2, 0xc5, 0xdd, 0xfa, 0xde, 0xfb, 0xf3, 0xaf, 0x48, 0x0b, 0xac, 0xcd, 0xa1, 0x9e,
-	0x53, 0x92, 0x36, 0x40, 0xa6, 0x9e, 0x6d, 0x6d, 0x4e, 0x42, 0xbe, 0x06, 0x60, 0x5a, 0xcb, 0xc0,
-	0x89, 0x35, 0xa6, 0x8f, 0x42, 0x7d, 0xef, 0xc3, 0x39, 0x81, 0xd0, 0xeb, 0xcd, 0x30, 0x9a, 0x33,
-	0xb1, 0xdf, 0x58, 0x70, 0x77, 0x1e, 0x94, 0x2c, 0x88, 0x44, 0x25, 0xc2, 0x38, 0xc9, 0x23, 0xff,
-	0x45, 0x28, 0x8b, 0xc9, 0x53, 0xd8, 0xf2, 0xc4, 0x05, 0x57, 0x6c, 0x12, 0x85, 0xb3, 0xc1, 0x4b,
-	0x53, 0xb9, 0x9f, 0x4b, 0xe5, 0xa8, 0xcc, 0xd0, 0xaa, 0x99, 0xfd, 0x09, 0x6c, 0x55, 0x38, 0xd2,
-	0x84, 0x45, 0x16, 0x86, 0xd3, 0xdb, 0x27, 0x47, 0xfb, 0x1b, 0x68, 0xe4, 0x9b, 0x4b, 0x3e, 0x87,
-	0x15, 0xa5, 0x99, 0x8e, 0xd3, 0x1c, 0x37, 0x8a, 0xfb, 0x75, 0x03, 0xc6, 0x8a, 0x4e, 0x39, 0xfb,
-	0x57, 0x0b, 0x56, 0x29, 0xfa, 0x81, 0xd2, 0xf2, 0x8a, 0x1c, 0x02, 0xcc, 0xf8, 0xac, 0x5d, 0x1f,
-	0x15, 0xde, 0x93, 0x14, 0xbc, 0x59, 0x1e, 0xd5, 0xe7, 0x5a, 0x5e, 0xd1, 0x9c, 0xd9, 0xce, 0x19,
-	0x6c, 0x96, 0xd4, 0x49, 0xe2, 0xaf, 0xf0, 0xca, 0xe4, 0xb4, 0x46, 0x93, 0x23, 0x79, 0x04, 0xcb,
-	0xaf, 0x93, 0x1d, 0x99, 0xd6, 0xe7, 0xde, 0xbc, 0xc5, 0xcc, 0xca, 0x93, 0x92, 0x8f, 0x17, 0xbe,
-	0xb0, 0xec, 0xbf, 0x2c, 0xd8, 0x7e, 0xcb, 0xe2, 0x12, 0x0f, 0xda, 0xe6, 0xd5, 0x35, 0xaf, 0x50,
-	0xc0, 0xfd, 0x11, 0xca, 0xc3, 0xd1, 0x8b, 0x43, 0xc1, 0xdd, 0x58, 0x4a, 0xe4, 0x6e, 0x1a, 0x3f,
-	0xe9, 0x45, 0x79, 0x63, 0x8f, 0x44, 0xec, 0x84, 0x98, 0xee, 0xec, 0x7f, 0xf8, 0x48, 0xa2, 0x98,
-	0x8f, 0xc0, 0xdb, 0xa3, 0x2c, 0xdc, 0x26, 0xca, 0xbb, 0x7d, 0xd8, 0x3f, 0xc2, 0x66, 0x69, 0xe7,
-	0x08, 0x81, 0x25, 0x7d, 0x15, 0xe1, 0xb4, 0x88, 0xe6, 0x4c, 0x1e, 0x41, 0x4d, 0x14, 0xe6, 0x6c,
-	0xbb, 0x12, 0xf5, 0xd4, 0xfc, 0xba, 0xa2, 0x19, 0xf7, 0xf0, 0x4b, 0x58, 0x2f, 0x0c, 0x02, 0xa9,
-	0x43, 0xed, 0xc5, 0xf0, 0xbb, 0xe1, 0xc9, 0xcb, 0x61, 0xf3, 0x0e, 0x69, 0x42, 0x63, 0x30, 0x1c,
-	0x3c, 0x1f, 0xf4, 0x9e, 0x0d, 0xce, 0x06, 0xc3, 0xe3, 0xa6, 0x45, 0xd6, 0x60, 0x99, 0xf6, 0x7b,
-	0x47, 0x3f, 0x35, 0x17, 0x0e, 0x9a, 0xbf, 0x5d, 0xb7, 0xad, 0xdf, 0xaf, 0xdb, 0xd6, 0x1f, 0xd7,
-	0x6d, 0xeb, 0x97, 0x3f, 0xdb, 0x77, 0x9c, 0x15, 0x13, 0x66, 0xff, 0xdf, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x48, 0x8b, 0xb8, 0x2d, 0x28, 0x0a, 0x00, 0x00,
-}
@@ -23,29 +23,65 @@
 
 package namespace
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
-import io "io"
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
 // SchemaOptions contains schema information for a namespace.
 type SchemaOptions struct {
 	// history contains a history of deployed schema definitions.
-	History *SchemaHistory `protobuf:"bytes,1,opt,name=history" json:"history,omitempty"`
+	History *SchemaHistory `protobuf:"bytes,1,opt,name=history,proto3" json:"history,omitempty"`
 	// defaultMessageName identifies the proto message that contains the default schema for the namespace.
 	DefaultMessageName string `protobuf:"bytes,2,opt,name=defaultMessageName,proto3" json:"defaultMessageName,omitempty"`
 }
 
-func (m *SchemaOptions) Reset()                    { *m = SchemaOptions{} }
-func (m *SchemaOptions) String() string            { return proto.CompactTextString(m) }
-func (*SchemaOptions) ProtoMessage()               {}
-func (*SchemaOptions) Descriptor() ([]byte, []int) { return fileDescriptorSchema, []int{0} }
+func (m *SchemaOptions) Reset()         { *m = SchemaOptions{} }
+func (m *SchemaOptions) String() string { return proto.CompactTextString(m) }
+func (*SchemaOptions) ProtoMessage()    {}
+func (*SchemaOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_745828f361790dec, []int{0}
+}
+func (m *SchemaOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SchemaOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SchemaOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SchemaOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaOptions.Merge(m, src)
+}
+func (m *SchemaOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *SchemaOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SchemaOptions proto.InternalMessageInfo
 
 func (m *SchemaOptions) GetHistory() *SchemaHistory {
 	if m != nil {
@@ -65,13 +101,41 @@ func (m *SchemaOptions) GetDefaultMessageName() string {
 type SchemaHistory struct {
 	// versions is a list of FileDescriptorSet sorted by version in ascending order.
 	// the list is a linked list and we use FileDescriptorSet.prevId to ensure the order is ascending.
-	Versions []*FileDescriptorSet `protobuf:"bytes,1,rep,name=versions" json:"versions,omitempty"`
+	Versions []*FileDescriptorSet `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+}
+
+func (m *SchemaHistory) Reset()         { *m = SchemaHistory{} }
+func (m *SchemaHistory) String() string { return proto.CompactTextString(m) }
+func (*SchemaHistory) ProtoMessage()    {}
+func (*SchemaHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_745828f361790dec, []int{1}
+}
+func (m *SchemaHistory) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SchemaHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SchemaHistory.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SchemaHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaHistory.Merge(m, src)
+}
+func (m *SchemaHistory) XXX_Size() int {
+	return m.Size()
+}
+func (m *SchemaHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaHistory.DiscardUnknown(m)
 }
 
-func (m *SchemaHistory) Reset()                    { *m = SchemaHistory{} }
-func (m *SchemaHistory) String() string            { return proto.CompactTextString(m) }
-func (*SchemaHistory) ProtoMessage()               {}
-func (*SchemaHistory) Descriptor() ([]byte, []int) { return fileDescriptorSchema, []int{1} }
+var xxx_messageInfo_SchemaHistory proto.InternalMessageInfo
 
 func (m *SchemaHistory) GetVersions() []*FileDescriptorSet {
 	if m != nil {
@@ -87,13 +151,41 @@ type FileDescriptorSet struct {
 	// prevId identifies the previous deploy id of FileDescriptorSet.
 	PrevId string `protobuf:"bytes,2,opt,name=prevId,proto3" json:"prevId,omitempty"`
 	// descriptors is a list of proto file descriptors sorted by dependency in topological order.
-	Descriptors [][]byte `protobuf:"bytes,3,rep,name=descriptors" json:"descriptors,omitempty"`
+	Descriptors [][]byte `protobuf:"bytes,3,rep,name=descriptors,proto3" json:"descriptors,omitempty"`
+}
+
+func (m *FileDescriptorSet) Reset()         { *m = FileDescriptorSet{} }
+func (m *FileDescriptorSet) String() string { return proto.CompactTextString(m) }
+func (*FileDescriptorSet) ProtoMessage()    {}
+func (*FileDescriptorSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_745828f361790dec, []int{2}
+}
+func (m *FileDescriptorSet) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileDescriptorSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FileDescriptorSet.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FileDescriptorSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileDescriptorSet.Merge(m, src)
+}
+func (m *FileDescriptorSet) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileDescriptorSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileDescriptorSet.DiscardUnknown(m)
 }
 
-func (m *FileDescriptorSet) Reset()                    { *m = FileDescriptorSet{} }
-func (m *FileDescriptorSet) String() string            { return proto.CompactTextString(m) }
-func (*FileDescriptorSet) ProtoMessage()               {}
-func (*FileDescriptorSet) Descriptor() ([]byte, []int) { return fileDescriptorSchema, []int{2} }
+var xxx_messageInfo_FileDescriptorSet proto.InternalMessageInfo
 
 func (m *FileDescriptorSet) GetDeployId() string {
 	if m != nil {
@@ -121,10 +213,38 @@ func init() {
 	proto.RegisterType((*SchemaHistory)(nil), "namespace.SchemaHistory")
 	proto.RegisterType((*FileDescriptorSet)(nil), "namespace.FileDescriptorSet")
 }
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/dbnode/generated/proto/namespace/schema.proto", fileDescriptor_745828f361790dec)
+}
+
+var fileDescriptor_745828f361790dec = []byte{
+	// 290 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xb1, 0x6a, 0xeb, 0x30,
+	0x14, 0x86, 0xa3, 0x1b, 0xc8, 0x4d, 0x94, 0x66, 0xa8, 0x86, 0x22, 0x4a, 0x11, 0x26, 0x93, 0x27,
+	0x0b, 0x92, 0xa5, 0x73, 0x09, 0xa5, 0x19, 0xda, 0x82, 0xf3, 0x04, 0xb2, 0x75, 0x6a, 0x0b, 0x2c,
+	0x4b, 0x48, 0x4a, 0x20, 0x6f, 0xd1, 0xc7, 0xea, 0x98, 0xb1, 0x63, 0xb1, 0x5f, 0xa4, 0xd4, 0x49,
+	0x4d, 0x4a, 0x3b, 0x9e, 0xff, 0xfb, 0x38, 0xfa, 0x75, 0xf0, 0xaa, 0x50, 0xa1, 0xdc, 0x66, 0x49,
+	0x6e, 0x34, 0xd7, 0x4b, 0x99, 0x71, 0xbd, 0xe4, 0xde, 0xe5, 0x5c, 0x66, 0xb5, 0x91, 0xc0, 0x0b,
+	0xa8, 0xc1, 0x89, 0x00, 0x92, 0x5b, 0x67, 0x82, 0xe1, 0xb5, 0xd0, 0xe0, 0xad, 0xc8, 0x81, 0xfb,
+	0xbc, 0x04, 0x2d, 0x92, 0x2e, 0x26, 0x93, 0x3e, 0x9f, 0x7b, 0x3c, 0xdb, 0x74, 0xe8, 0xd9, 0x06,
+	0x65, 0x6a, 0x4f, 0x16, 0xf8, 0x7f, 0xa9, 0x7c, 0x30, 0x6e, 0x4f, 0x51, 0x84, 0xe2, 0xe9, 0x82,
+	0x26, 0xbd, 0x9d, 0x1c, 0xd5, 0x87, 0x23, 0x4f, 0xbf, 0x45, 0x92, 0x60, 0x22, 0xe1, 0x45, 0x6c,
+	0xab, 0xf0, 0x08, 0xde, 0x8b, 0x02, 0x9e, 0x84, 0x06, 0xfa, 0x2f, 0x42, 0xf1, 0x24, 0xfd, 0x83,
+	0xcc, 0xd7, 0x78, 0xf6, 0x63, 0x13, 0xb9, 0xc5, 0xe3, 0x1d, 0x38, 0xff, 0x55, 0x80, 0xa2, 0x68,
+	0x18, 0x4f, 0x17, 0x37, 0x67, 0xaf, 0xde, 0xab, 0x0a, 0x56, 0xe0, 0x73, 0xa7, 0x6c, 0x30, 0x6e,
+	0x03, 0x21, 0xed, 0xed, 0xb9, 0xc2, 0x97, 0xbf, 0x30, 0xb9, 0xc6, 0x63, 0x09, 0xb6, 0x32, 0xfb,
+	0xb5, 0xec, 0x3e, 0x31, 0x49, 0xfb, 0x99, 0x5c, 0xe1, 0x91, 0x75, 0xb0, 0x5b, 0xcb, 0x53, 0xbf,
+	0xd3, 0x44, 0x22, 0x3c, 0x95, 0xfd, 0x12, 0x4f, 0x87, 0xd1, 0x30, 0xbe, 0x48, 0xcf, 0xa3, 0x3b,
+	0xfa, 0xd6, 0x30, 0x74, 0x68, 0x18, 0xfa, 0x68, 0x18, 0x7a, 0x6d, 0xd9, 0xe0, 0xd0, 0xb2, 0xc1,
+	0x7b, 0xcb, 0x06, 0xd9, 0xa8, 0x3b, 0xeb, 0xf2, 0x73, 0x00, 0x16, 0xb3, 0x97, 0x19, 0x9e, 0x01,
+	0x00, 0x00,
+}
+
 func (m *SchemaOptions) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -132,33 +252,41 @@ func (m *SchemaOptions) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *SchemaOptions) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SchemaOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.History != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintSchema(dAtA, i, uint64(m.History.Size()))
-		n1, err := m.History.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
-	}
 	if len(m.DefaultMessageName) > 0 {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.DefaultMessageName)
+		copy(dAtA[i:], m.DefaultMessageName)
 		i = encodeVarintSchema(dAtA, i, uint64(len(m.DefaultMessageName)))
-		i += copy(dAtA[i:], m.DefaultMessageName)
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.History != nil {
+		{
+			size, err := m.History.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSchema(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *SchemaHistory) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -166,29 +294,36 @@ func (m *SchemaHistory) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *SchemaHistory) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SchemaHistory) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Versions) > 0 {
-		for _, msg := range m.Versions {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintSchema(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+		for iNdEx := len(m.Versions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Versions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSchema(dAtA, i, uint64(size))
 			}
-			i += n
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *FileDescriptorSet) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -196,43 +331,56 @@ func (m *FileDescriptorSet) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *FileDescriptorSet) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileDescriptorSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.DeployId) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintSchema(dAtA, i, uint64(len(m.DeployId)))
-		i += copy(dAtA[i:], m.DeployId)
+	if len(m.Descriptors) > 0 {
+		for iNdEx := len(m.Descriptors) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Descriptors[iNdEx])
+			copy(dAtA[i:], m.Descriptors[iNdEx])
+			i = encodeVarintSchema(dAtA, i, uint64(len(m.Descriptors[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
 	if len(m.PrevId) > 0 {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.PrevId)
+		copy(dAtA[i:], m.PrevId)
 		i = encodeVarintSchema(dAtA, i, uint64(len(m.PrevId)))
-		i += copy(dAtA[i:], m.PrevId)
+		i--
+		dAtA[i] = 0x12
 	}
-	if len(m.Descriptors) > 0 {
-		for _, b := range m.Descriptors {
-			dAtA[i] = 0x1a
-			i++
-			i = encodeVarintSchema(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
-		}
+	if len(m.DeployId) > 0 {
+		i -= len(m.DeployId)
+		copy(dAtA[i:], m.DeployId)
+		i = encodeVarintSchema(dAtA, i, uint64(len(m.DeployId)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func encodeVarintSchema(dAtA []byte, offset int, v uint64) int {
+	offset -= sovSchema(v)
+	base := offset
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
 		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
-	return offset + 1
+	return base
 }
 func (m *SchemaOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.History != nil {
@@ -247,6 +395,9 @@ func (m *SchemaOptions) Size() (n int) {
 }
 
 func (m *SchemaHistory) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Versions) > 0 {
@@ -259,6 +410,9 @@ func (m *SchemaHistory) Size() (n int) {
 }
 
 func (m *FileDescriptorSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.DeployId)
@@ -279,14 +433,7 @@ func (m *FileDescriptorSet) Size() (n int) {
 }
 
 func sovSchema(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
-	}
-	return n
+	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozSchema(x uint64) (n int) {
 	return sovSchema(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -306,7 +453,7 @@ func (m *SchemaOptions) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -334,7 +481,7 @@ func (m *SchemaOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -343,6 +490,9 @@ func (m *SchemaOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthSchema
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchema
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -367,7 +517,7 @@ func (m *SchemaOptions) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -377,6 +527,9 @@ func (m *SchemaOptions) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthSchema
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchema
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -388,7 +541,7 @@ func (m *SchemaOptions) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthSchema
 			}
 			if (iNdEx + skippy) > l {
@@ -418,7 +571,7 @@ func (m *SchemaHistory) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -446,7 +599,7 @@ func (m *SchemaHistory) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -455,6 +608,9 @@ func (m *SchemaHistory) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthSchema
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchema
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -469,7 +625,7 @@ func (m *SchemaHistory) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthSchema
 			}
 			if (iNdEx + skippy) > l {
@@ -499,7 +655,7 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -527,7 +683,7 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -537,6 +693,9 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthSchema
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchema
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -556,7 +715,7 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -566,6 +725,9 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthSchema
 			}
 			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchema
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -585,7 +747,7 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -594,6 +756,9 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthSchema
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchema
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -606,7 +771,7 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthSchema
 			}
 			if (iNdEx + skippy) > l {
@@ -624,6 +789,7 @@ func (m *FileDescriptorSet) Unmarshal(dAtA []byte) error {
 func skipSchema(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -655,10 +821,8 @@ func skipSchema(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -675,79 +839,34 @@ func skipSchema(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthSchema
 			}
-			return iNdEx, nil
+			iNdEx += length
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowSchema
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipSchema(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupSchema
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthSchema
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthSchema = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowSchema   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthSchema        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowSchema          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupSchema = fmt.Errorf("proto: unexpected end of group")
 )
-
-func init() {
-	proto.RegisterFile("github.com/m3db/m3/src/dbnode/generated/proto/namespace/schema.proto", fileDescriptorSchema)
-}
-
-var fileDescriptorSchema = []byte{
-	// 286 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xc1, 0x4a, 0xec, 0x30,
-	0x14, 0x86, 0x6f, 0xee, 0xc0, 0x38, 0x4d, 0x15, 0x34, 0x0b, 0x29, 0x22, 0xa5, 0x74, 0xd5, 0x55,
-	0x03, 0xed, 0xc6, 0xb5, 0x0c, 0x62, 0x17, 0x2a, 0x74, 0x9e, 0x20, 0x6d, 0x8e, 0x6d, 0xa0, 0x69,
-	0x42, 0x92, 0x19, 0x98, 0xb7, 0xf0, 0xb1, 0x5c, 0xfa, 0x08, 0x52, 0x5f, 0x44, 0xec, 0x74, 0xca,
-	0x80, 0x2e, 0xcf, 0xff, 0x7d, 0x9c, 0xf3, 0x27, 0x78, 0xdd, 0x08, 0xd7, 0x6e, 0xab, 0xb4, 0x56,
-	0x92, 0xca, 0x9c, 0x57, 0x54, 0xe6, 0xd4, 0x9a, 0x9a, 0xf2, 0xaa, 0x57, 0x1c, 0x68, 0x03, 0x3d,
-	0x18, 0xe6, 0x80, 0x53, 0x6d, 0x94, 0x53, 0xb4, 0x67, 0x12, 0xac, 0x66, 0x35, 0x50, 0x5b, 0xb7,
-	0x20, 0x59, 0x3a, 0xc6, 0xc4, 0x9b, 0xf3, 0xd8, 0xe2, 0x8b, 0xcd, 0x88, 0x5e, 0xb4, 0x13, 0xaa,
-	0xb7, 0x24, 0xc3, 0x67, 0xad, 0xb0, 0x4e, 0x99, 0x7d, 0x80, 0x22, 0x94, 0xf8, 0x59, 0x90, 0xce,
-	0x76, 0x7a, 0x50, 0x1f, 0x0f, 0xbc, 0x3c, 0x8a, 0x24, 0xc5, 0x84, 0xc3, 0x2b, 0xdb, 0x76, 0xee,
-	0x09, 0xac, 0x65, 0x0d, 0x3c, 0x33, 0x09, 0xc1, 0xff, 0x08, 0x25, 0x5e, 0xf9, 0x07, 0x89, 0x8b,
-	0xe3, 0xd1, 0x69, 0x13, 0xb9, 0xc3, 0xab, 0x1d, 0x18, 0xfb, 0x53, 0x20, 0x40, 0xd1, 0x22, 0xf1,
-	0xb3, 0xdb, 0x93, 0xab, 0x0f, 0xa2, 0x83, 0x35, 0xd8, 0xda, 0x08, 0xed, 0x94, 0xd9, 0x80, 0x2b,
-	0x67, 0x3b, 0x16, 0xf8, 0xea, 0x17, 0x26, 0x37, 0x78, 0xc5, 0x41, 0x77, 0x6a, 0x5f, 0xf0, 0xf1,
-	0x11, 0x5e, 0x39, 0xcf, 0xe4, 0x1a, 0x2f, 0xb5, 0x81, 0x5d, 0xc1, 0xa7, 0x7e, 0xd3, 0x44, 0x22,
-	0xec, 0xf3, 0x79, 0x89, 0x0d, 0x16, 0xd1, 0x22, 0x39, 0x2f, 0x4f, 0xa3, 0xfb, 0xcb, 0xf7, 0x21,
-	0x44, 0x1f, 0x43, 0x88, 0x3e, 0x87, 0x10, 0xbd, 0x7d, 0x85, 0xff, 0xaa, 0xe5, 0xf8, 0x9d, 0xf9,
-	0x77, 0x00, 0x00, 0x00, 0xff, 0xff, 0x98, 0xeb, 0x4b, 0x47, 0x96, 0x01, 0x00, 0x00,
-}
@@ -38,6 +38,11 @@ enum ErrorFlags {
     SERVER_TIMEOUT     = 0x02
 }
 
+enum EncodingScheme {
+	M3TSZ,
+	XOR
+}
+
 exception Error {
 	1: required ErrorType type = ErrorType.INTERNAL_ERROR
 	2: required string message
@@ -91,6 +96,19 @@ service Node {
 	DebugProfileStartResult        debugProfileStart(1: DebugProfileStartRequest req) throws (1: Error err)
 	DebugProfileStopResult         debugProfileStop(1: DebugProfileStopRequest req) throws (1: Error err)
 	DebugIndexMemorySegmentsResult debugIndexMemorySegments(1: DebugIndexMemorySegmentsRequest req) throws (1: Error err)
+
+	// Exemplar endpoints
+	void                 writeExemplars(1: WriteExemplarsRequest req) throws (1: Error err)
+	FetchExemplarsResult fetchExemplars(1: FetchExemplarsRequest req) throws (1: Error err)
+
+	// Deletion endpoints
+	DeleteTaggedResult deleteTagged(1: DeleteTaggedRequest req) throws (1: Error err)
+
+	// Cardinality endpoints
+	CardinalityResult cardinality(1: CardinalityRequest req) throws (1: Error err)
+
+	// Count endpoints
+	CountResult count(1: CountRequest req) throws (1: Error err)
 }
 
 struct FetchRequest {
@@ -158,6 +176,7 @@ struct FetchBatchRawResult {
 struct FetchRawResult {
 	1: required list<Segments> segments
 	2: optional Error err
+	3: optional EncodingScheme encodingScheme = EncodingScheme.M3TSZ
 }
 
 struct Segments {
@@ -202,6 +221,7 @@ struct FetchTaggedIDResult {
 
 	// Deprecated -- do not use.
 	5: optional Error err
+	6: optional EncodingScheme encodingScheme = EncodingScheme.M3TSZ
 }
 
 struct FetchBlocksRawRequest {
@@ -559,3 +579,81 @@ struct DebugIndexMemorySegmentsRequest {
 
 struct DebugIndexMemorySegmentsResult {
 }
+
+struct Exemplar {
+	1: required list<Tag> labels
+	2: required double value
+	3: required i64 timestamp
+}
+
+struct WriteExemplarsRequest {
+	1: required binary nameSpace
+	2: required list<WriteExemplarsRequestElement> elements
+}
+
+struct WriteExemplarsRequestElement {
+	1: required binary id
+	2: required list<Exemplar> exemplars
+}
+
+struct FetchExemplarsRequest {
+	1: required binary nameSpace
+	2: required i64 rangeStart
+	3: required i64 rangeEnd
+	4: required list<binary> ids
+}
+
+struct FetchExemplarsResult {
+	1: required list<FetchExemplarsResultElement> elements
+}
+
+struct FetchExemplarsResultElement {
+	1: required binary id
+	2: required list<Exemplar> exemplars
+}
+
+struct DeleteTaggedRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+}
+
+struct DeleteTaggedResult {
+	1: required i64 numSeries
+}
+
+struct CardinalityRequest {
+	1: required binary nameSpace
+	2: required i64 rangeStart
+	3: required i64 rangeEnd
+	4: required i64 limit
+	5: required list<i32> shards
+	6: required binary metricNameTag
+}
+
+struct CardinalityResult {
+	1: required i64 numSeries
+	2: required list<CardinalityStat> seriesCountByMetricName
+	3: required list<CardinalityStat> labelValueCountByLabelName
+	4: required list<CardinalityStat> seriesCountByLabelValuePair
+}
+
+struct CardinalityStat {
+	1: required binary name
+	2: required i64 value
+}
+
+struct CountRequest {
+	1: required binary nameSpace
+	2: required binary query
+	3: required i64 rangeStart
+	4: required i64 rangeEnd
+	5: required list<i32> shards
+	6: required bool includeSketch
+}
+
+struct CountResult {
+	1: required i64 count
+	2: required binary sketch
+}
@@ -225,6 +225,64 @@ func (p *ErrorFlags) Value() (driver.Value, error) {
 	return int64(*p), nil
 }
 
+type EncodingScheme int64
+
+const (
+	EncodingScheme_M3TSZ EncodingScheme = 0
+	EncodingScheme_XOR   EncodingScheme = 1
+)
+
+func (p EncodingScheme) String() string {
+	switch p {
+	case EncodingScheme_M3TSZ:
+		return "M3TSZ"
+	case EncodingScheme_XOR:
+		return "XOR"
+	}
+	return "<UNSET>"
+}
+
+func EncodingSchemeFromString(s string) (EncodingScheme, error) {
+	switch s {
+	case "M3TSZ":
+		return EncodingScheme_M3TSZ, nil
+	case "XOR":
+		return EncodingScheme_XOR, nil
+	}
+	return EncodingScheme(0), fmt.Errorf("not a valid EncodingScheme string")
+}
+
+func EncodingSchemePtr(v EncodingScheme) *EncodingScheme { return &v }
+
+func (p EncodingScheme) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *EncodingScheme) UnmarshalText(text []byte) error {
+	q, err := EncodingSchemeFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*p = q
+	return nil
+}
+
+func (p *EncodingScheme) Scan(value interface{}) error {
+	v, ok := value.(int64)
+	if !ok {
+		return errors.New("Scan value is not int64")
+	}
+	*p = EncodingScheme(v)
+	return nil
+}
+
+func (p *EncodingScheme) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return int64(*p), nil
+}
+
 type AggregateQueryType int64
 
 const (
@@ -2672,13 +2730,17 @@ func (p *FetchBatchRawResult_) String() string {
 // Attributes:
 //  - Segments
 //  - Err
+//  - EncodingScheme
 type FetchRawResult_ struct {
-	Segments []*Segments `thrift:"segments,1,required" db:"segments" json:"segments"`
-	Err      *Error      `thrift:"err,2" db:"err" json:"err,omitempty"`
+	Segments       []*Segments    `thrift:"segments,1,required" db:"segments" json:"segments"`
+	Err            *Error         `thrift:"err,2" db:"err" json:"err,omitempty"`
+	EncodingScheme EncodingScheme `thrift:"encodingScheme,3" db:"encodingScheme" json:"encodingScheme,omitempty"`
 }
 
 func NewFetchRawResult_() *FetchRawResult_ {
-	return &FetchRawResult_{}
+	return &FetchRawResult_{
+		EncodingScheme: 0,
+	}
 }
 
 func (p *FetchRawResult_) GetSegments() []*Segments {
@@ -2693,10 +2755,20 @@ func (p *FetchRawResult_) GetErr() *Error {
 	}
 	return p.Err
 }
+
+var FetchRawResult__EncodingScheme_DEFAULT EncodingScheme = 0
+
+func (p *FetchRawResult_) GetEncodingScheme() EncodingScheme {
+	return p.EncodingScheme
+}
 func (p *FetchRawResult_) IsSetErr() bool {
 	return p.Err != nil
 }
 
+func (p *FetchRawResult_) IsSetEncodingScheme() bool {
+	return p.EncodingScheme != FetchRawResult__EncodingScheme_DEFAULT
+}
+
 func (p *FetchRawResult_) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -2722,6 +2794,10 @@ func (p *FetchRawResult_) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField2(iprot); err != nil {
 				return err
 			}
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -2770,6 +2846,16 @@ func (p *FetchRawResult_) ReadField2(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FetchRawResult_) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		temp := EncodingScheme(v)
+		p.EncodingScheme = temp
+	}
+	return nil
+}
+
 func (p *FetchRawResult_) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FetchRawResult"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -2781,6 +2867,9 @@ func (p *FetchRawResult_) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField2(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -2827,6 +2916,21 @@ func (p *FetchRawResult_) writeField2(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FetchRawResult_) writeField3(oprot thrift.TProtocol) (err error) {
+	if p.IsSetEncodingScheme() {
+		if err := oprot.WriteFieldBegin("encodingScheme", thrift.I32, 3); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:encodingScheme: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(p.EncodingScheme)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.encodingScheme (3) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 3:encodingScheme: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FetchRawResult_) String() string {
 	if p == nil {
 		return "<nil>"
@@ -4060,16 +4164,20 @@ func (p *FetchTaggedResult_) String() string {
 //  - EncodedTags
 //  - Segments
 //  - Err
+//  - EncodingScheme
 type FetchTaggedIDResult_ struct {
-	ID          []byte      `thrift:"id,1,required" db:"id" json:"id"`
-	NameSpace   []byte      `thrift:"nameSpace,2,required" db:"nameSpace" json:"nameSpace"`
-	EncodedTags []byte      `thrift:"encodedTags,3,required" db:"encodedTags" json:"encodedTags"`
-	Segments    []*Segments `thrift:"segments,4" db:"segments" json:"segments,omitempty"`
-	Err         *Error      `thrift:"err,5" db:"err" json:"err,omitempty"`
+	ID             []byte         `thrift:"id,1,required" db:"id" json:"id"`
+	NameSpace      []byte         `thrift:"nameSpace,2,required" db:"nameSpace" json:"nameSpace"`
+	EncodedTags    []byte         `thrift:"encodedTags,3,required" db:"encodedTags" json:"encodedTags"`
+	Segments       []*Segments    `thrift:"segments,4" db:"segments" json:"segments,omitempty"`
+	Err            *Error         `thrift:"err,5" db:"err" json:"err,omitempty"`
+	EncodingScheme EncodingScheme `thrift:"encodingScheme,6" db:"encodingScheme" json:"encodingScheme,omitempty"`
 }
 
 func NewFetchTaggedIDResult_() *FetchTaggedIDResult_ {
-	return &FetchTaggedIDResult_{}
+	return &FetchTaggedIDResult_{
+		EncodingScheme: 0,
+	}
 }
 
 func (p *FetchTaggedIDResult_) GetID() []byte {
@@ -4098,6 +4206,12 @@ func (p *FetchTaggedIDResult_) GetErr() *Error {
 	}
 	return p.Err
 }
+
+var FetchTaggedIDResult__EncodingScheme_DEFAULT EncodingScheme = 0
+
+func (p *FetchTaggedIDResult_) GetEncodingScheme() EncodingScheme {
+	return p.EncodingScheme
+}
 func (p *FetchTaggedIDResult_) IsSetSegments() bool {
 	return p.Segments != nil
 }
@@ -4106,6 +4220,10 @@ func (p *FetchTaggedIDResult_) IsSetErr() bool {
 	return p.Err != nil
 }
 
+func (p *FetchTaggedIDResult_) IsSetEncodingScheme() bool {
+	return p.EncodingScheme != FetchTaggedIDResult__EncodingScheme_DEFAULT
+}
+
 func (p *FetchTaggedIDResult_) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -4147,6 +4265,10 @@ func (p *FetchTaggedIDResult_) Read(iprot thrift.TProtocol) error {
 			if err := p.ReadField5(iprot); err != nil {
 				return err
 			}
+		case 6:
+			if err := p.ReadField6(iprot); err != nil {
+				return err
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -4228,6 +4350,16 @@ func (p *FetchTaggedIDResult_) ReadField5(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *FetchTaggedIDResult_) ReadField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		temp := EncodingScheme(v)
+		p.EncodingScheme = temp
+	}
+	return nil
+}
+
 func (p *FetchTaggedIDResult_) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("FetchTaggedIDResult"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -4248,6 +4380,9 @@ func (p *FetchTaggedIDResult_) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField5(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField6(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -4335,6 +4470,21 @@ func (p *FetchTaggedIDResult_) writeField5(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *FetchTaggedIDResult_) writeField6(oprot thrift.TProtocol) (err error) {
+	if p.IsSetEncodingScheme() {
+		if err := oprot.WriteFieldBegin("encodingScheme", thrift.I32, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:encodingScheme: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(p.EncodingScheme)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.encodingScheme (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:encodingScheme: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FetchTaggedIDResult_) String() string {
 	if p == nil {
 		return "<nil>"
@@ -14997,2753 +15147,6864 @@ func (p *DebugIndexMemorySegmentsResult_) String() string {
 	return fmt.Sprintf("DebugIndexMemorySegmentsResult_(%+v)", *p)
 }
 
-type Node interface {
-	// Parameters:
-	//  - Req
-	Query(req *QueryRequest) (r *QueryResult_, err error)
-	// Parameters:
-	//  - Req
-	Aggregate(req *AggregateQueryRequest) (r *AggregateQueryResult_, err error)
-	// Parameters:
-	//  - Req
-	Fetch(req *FetchRequest) (r *FetchResult_, err error)
-	// Parameters:
-	//  - Req
-	Write(req *WriteRequest) (err error)
-	// Parameters:
-	//  - Req
-	WriteTagged(req *WriteTaggedRequest) (err error)
-	// Parameters:
-	//  - Req
-	AggregateRaw(req *AggregateQueryRawRequest) (r *AggregateQueryRawResult_, err error)
-	// Parameters:
-	//  - Req
-	FetchBatchRaw(req *FetchBatchRawRequest) (r *FetchBatchRawResult_, err error)
-	// Parameters:
-	//  - Req
-	FetchBatchRawV2(req *FetchBatchRawV2Request) (r *FetchBatchRawResult_, err error)
-	// Parameters:
-	//  - Req
-	FetchBlocksRaw(req *FetchBlocksRawRequest) (r *FetchBlocksRawResult_, err error)
-	// Parameters:
-	//  - Req
-	FetchTagged(req *FetchTaggedRequest) (r *FetchTaggedResult_, err error)
-	// Parameters:
-	//  - Req
-	FetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (r *FetchBlocksMetadataRawV2Result_, err error)
-	// Parameters:
-	//  - Req
-	WriteBatchRaw(req *WriteBatchRawRequest) (err error)
-	// Parameters:
-	//  - Req
-	WriteBatchRawV2(req *WriteBatchRawV2Request) (err error)
-	// Parameters:
-	//  - Req
-	WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error)
-	// Parameters:
-	//  - Req
-	WriteTaggedBatchRawV2(req *WriteTaggedBatchRawV2Request) (err error)
-	Repair() (err error)
-	// Parameters:
-	//  - Req
-	Truncate(req *TruncateRequest) (r *TruncateResult_, err error)
-	// Parameters:
-	//  - Req
-	AggregateTiles(req *AggregateTilesRequest) (r *AggregateTilesResult_, err error)
-	Health() (r *NodeHealthResult_, err error)
-	Bootstrapped() (r *NodeBootstrappedResult_, err error)
-	BootstrappedInPlacementOrNoPlacement() (r *NodeBootstrappedInPlacementOrNoPlacementResult_, err error)
-	GetPersistRateLimit() (r *NodePersistRateLimitResult_, err error)
-	// Parameters:
-	//  - Req
-	SetPersistRateLimit(req *NodeSetPersistRateLimitRequest) (r *NodePersistRateLimitResult_, err error)
-	GetWriteNewSeriesAsync() (r *NodeWriteNewSeriesAsyncResult_, err error)
-	// Parameters:
-	//  - Req
-	SetWriteNewSeriesAsync(req *NodeSetWriteNewSeriesAsyncRequest) (r *NodeWriteNewSeriesAsyncResult_, err error)
-	GetWriteNewSeriesBackoffDuration() (r *NodeWriteNewSeriesBackoffDurationResult_, err error)
-	// Parameters:
-	//  - Req
-	SetWriteNewSeriesBackoffDuration(req *NodeSetWriteNewSeriesBackoffDurationRequest) (r *NodeWriteNewSeriesBackoffDurationResult_, err error)
-	GetWriteNewSeriesLimitPerShardPerSecond() (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error)
-	// Parameters:
-	//  - Req
-	SetWriteNewSeriesLimitPerShardPerSecond(req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error)
-	// Parameters:
-	//  - Req
-	DebugProfileStart(req *DebugProfileStartRequest) (r *DebugProfileStartResult_, err error)
-	// Parameters:
-	//  - Req
-	DebugProfileStop(req *DebugProfileStopRequest) (r *DebugProfileStopResult_, err error)
-	// Parameters:
-	//  - Req
-	DebugIndexMemorySegments(req *DebugIndexMemorySegmentsRequest) (r *DebugIndexMemorySegmentsResult_, err error)
+// Attributes:
+//  - Labels
+//  - Value
+//  - Timestamp
+type Exemplar struct {
+	Labels    []*Tag  `thrift:"labels,1,required" db:"labels" json:"labels"`
+	Value     float64 `thrift:"value,2,required" db:"value" json:"value"`
+	Timestamp int64   `thrift:"timestamp,3,required" db:"timestamp" json:"timestamp"`
 }
 
-type NodeClient struct {
-	Transport       thrift.TTransport
-	ProtocolFactory thrift.TProtocolFactory
-	InputProtocol   thrift.TProtocol
-	OutputProtocol  thrift.TProtocol
-	SeqId           int32
+func NewExemplar() *Exemplar {
+	return &Exemplar{}
 }
 
-func NewNodeClientFactory(t thrift.TTransport, f thrift.TProtocolFactory) *NodeClient {
-	return &NodeClient{Transport: t,
-		ProtocolFactory: f,
-		InputProtocol:   f.GetProtocol(t),
-		OutputProtocol:  f.GetProtocol(t),
-		SeqId:           0,
-	}
+func (p *Exemplar) GetLabels() []*Tag {
+	return p.Labels
 }
 
-func NewNodeClientProtocol(t thrift.TTransport, iprot thrift.TProtocol, oprot thrift.TProtocol) *NodeClient {
-	return &NodeClient{Transport: t,
-		ProtocolFactory: nil,
-		InputProtocol:   iprot,
-		OutputProtocol:  oprot,
-		SeqId:           0,
-	}
+func (p *Exemplar) GetValue() float64 {
+	return p.Value
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) Query(req *QueryRequest) (r *QueryResult_, err error) {
-	if err = p.sendQuery(req); err != nil {
-		return
-	}
-	return p.recvQuery()
+func (p *Exemplar) GetTimestamp() int64 {
+	return p.Timestamp
 }
-
-func (p *NodeClient) sendQuery(req *QueryRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("query", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeQueryArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+func (p *Exemplar) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	return oprot.Flush()
-}
 
-func (p *NodeClient) recvQuery() (value *QueryResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "query" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "query failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "query failed: out of sequence response")
-		return
-	}
-	if mTypeId == thrift.EXCEPTION {
-		error35 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error36 error
-		error36, err = error35.Read(iprot)
+	var issetLabels bool = false
+	var issetValue bool = false
+	var issetTimestamp bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetLabels = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetValue = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetTimestamp = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error36
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "query failed: invalid message type")
-		return
-	}
-	result := NodeQueryResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
-	}
-	if result.Err != nil {
-		err = result.Err
-		return
-	}
-	value = result.GetSuccess()
-	return
-}
-
-// Parameters:
-//  - Req
-func (p *NodeClient) Aggregate(req *AggregateQueryRequest) (r *AggregateQueryResult_, err error) {
-	if err = p.sendAggregate(req); err != nil {
-		return
-	}
-	return p.recvAggregate()
-}
-
-func (p *NodeClient) sendAggregate(req *AggregateQueryRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("aggregate", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	args := NodeAggregateArgs{
-		Req: req,
+	if !issetLabels {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Labels is not set"))
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if !issetValue {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Value is not set"))
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if !issetTimestamp {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Timestamp is not set"))
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvAggregate() (value *AggregateQueryResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+func (p *Exemplar) ReadField1(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
 	if err != nil {
-		return
-	}
-	if method != "aggregate" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "aggregate failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "aggregate failed: out of sequence response")
-		return
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error37 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error38 error
-		error38, err = error37.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	tSlice := make([]*Tag, 0, size)
+	p.Labels = tSlice
+	for i := 0; i < size; i++ {
+		_elem35 := &Tag{}
+		if err := _elem35.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem35), err)
 		}
-		err = error38
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "aggregate failed: invalid message type")
-		return
-	}
-	result := NodeAggregateResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+		p.Labels = append(p.Labels, _elem35)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) Fetch(req *FetchRequest) (r *FetchResult_, err error) {
-	if err = p.sendFetch(req); err != nil {
-		return
+func (p *Exemplar) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadDouble(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Value = v
 	}
-	return p.recvFetch()
+	return nil
 }
 
-func (p *NodeClient) sendFetch(req *FetchRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *Exemplar) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.Timestamp = v
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("fetch", thrift.CALL, p.SeqId); err != nil {
-		return
+	return nil
+}
+
+func (p *Exemplar) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("Exemplar"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	args := NodeFetchArgs{
-		Req: req,
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvFetch() (value *FetchResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "fetch" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetch failed: wrong method name")
-		return
+func (p *Exemplar) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("labels", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:labels: ", p), err)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetch failed: out of sequence response")
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.Labels)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error39 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error40 error
-		error40, err = error39.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	for _, v := range p.Labels {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
 		}
-		err = error40
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetch failed: invalid message type")
-		return
-	}
-	result := NodeFetchResult{}
-	if err = result.Read(iprot); err != nil {
-		return
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:labels: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	return err
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) Write(req *WriteRequest) (err error) {
-	if err = p.sendWrite(req); err != nil {
-		return
+func (p *Exemplar) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("value", thrift.DOUBLE, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:value: ", p), err)
 	}
-	return p.recvWrite()
+	if err := oprot.WriteDouble(float64(p.Value)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.value (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:value: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) sendWrite(req *WriteRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("write", thrift.CALL, p.SeqId); err != nil {
-		return
+func (p *Exemplar) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("timestamp", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:timestamp: ", p), err)
 	}
-	args := NodeWriteArgs{
-		Req: req,
+	if err := oprot.WriteI64(int64(p.Timestamp)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.timestamp (3) field write error: ", p), err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:timestamp: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	return err
+}
+
+func (p *Exemplar) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return oprot.Flush()
+	return fmt.Sprintf("Exemplar(%+v)", *p)
 }
 
-func (p *NodeClient) recvWrite() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "write" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "write failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "write failed: out of sequence response")
-		return
+// Attributes:
+//  - NameSpace
+//  - Elements
+type WriteExemplarsRequest struct {
+	NameSpace []byte                          `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	Elements  []*WriteExemplarsRequestElement `thrift:"elements,2,required" db:"elements" json:"elements"`
+}
+
+func NewWriteExemplarsRequest() *WriteExemplarsRequest {
+	return &WriteExemplarsRequest{}
+}
+
+func (p *WriteExemplarsRequest) GetNameSpace() []byte {
+	return p.NameSpace
+}
+
+func (p *WriteExemplarsRequest) GetElements() []*WriteExemplarsRequestElement {
+	return p.Elements
+}
+func (p *WriteExemplarsRequest) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error41 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error42 error
-		error42, err = error41.Read(iprot)
+
+	var issetNameSpace bool = false
+	var issetElements bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNameSpace = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetElements = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error42
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "write failed: invalid message type")
-		return
 	}
-	result := NodeWriteResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetElements {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Elements is not set"))
 	}
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) WriteTagged(req *WriteTaggedRequest) (err error) {
-	if err = p.sendWriteTagged(req); err != nil {
-		return
+func (p *WriteExemplarsRequest) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NameSpace = v
 	}
-	return p.recvWriteTagged()
+	return nil
 }
 
-func (p *NodeClient) sendWriteTagged(req *WriteTaggedRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("writeTagged", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeWriteTaggedArgs{
-		Req: req,
+func (p *WriteExemplarsRequest) ReadField2(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	tSlice := make([]*WriteExemplarsRequestElement, 0, size)
+	p.Elements = tSlice
+	for i := 0; i < size; i++ {
+		_elem36 := &WriteExemplarsRequestElement{}
+		if err := _elem36.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem36), err)
+		}
+		p.Elements = append(p.Elements, _elem36)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvWriteTagged() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "writeTagged" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeTagged failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeTagged failed: out of sequence response")
-		return
+func (p *WriteExemplarsRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("WriteExemplarsRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error43 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error44 error
-		error44, err = error43.Read(iprot)
-		if err != nil {
-			return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
 		}
-		err = error44
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeTagged failed: invalid message type")
-		return
-	}
-	result := NodeWriteTaggedResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	return
-}
-
-// Parameters:
-//  - Req
-func (p *NodeClient) AggregateRaw(req *AggregateQueryRawRequest) (r *AggregateQueryRawResult_, err error) {
-	if err = p.sendAggregateRaw(req); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return p.recvAggregateRaw()
+	return nil
 }
 
-func (p *NodeClient) sendAggregateRaw(req *AggregateQueryRawRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("aggregateRaw", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeAggregateRawArgs{
-		Req: req,
+func (p *WriteExemplarsRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:nameSpace: ", p), err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteBinary(p.NameSpace); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (1) field write error: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:nameSpace: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvAggregateRaw() (value *AggregateQueryRawResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "aggregateRaw" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "aggregateRaw failed: wrong method name")
-		return
+func (p *WriteExemplarsRequest) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("elements", thrift.LIST, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:elements: ", p), err)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "aggregateRaw failed: out of sequence response")
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.Elements)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error45 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error46 error
-		error46, err = error45.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	for _, v := range p.Elements {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
 		}
-		err = error46
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "aggregateRaw failed: invalid message type")
-		return
 	}
-	result := NodeAggregateRawResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:elements: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	return err
+}
+
+func (p *WriteExemplarsRequest) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	value = result.GetSuccess()
-	return
+	return fmt.Sprintf("WriteExemplarsRequest(%+v)", *p)
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) FetchBatchRaw(req *FetchBatchRawRequest) (r *FetchBatchRawResult_, err error) {
-	if err = p.sendFetchBatchRaw(req); err != nil {
-		return
-	}
-	return p.recvFetchBatchRaw()
+// Attributes:
+//  - ID
+//  - Exemplars
+type WriteExemplarsRequestElement struct {
+	ID        []byte      `thrift:"id,1,required" db:"id" json:"id"`
+	Exemplars []*Exemplar `thrift:"exemplars,2,required" db:"exemplars" json:"exemplars"`
 }
 
-func (p *NodeClient) sendFetchBatchRaw(req *FetchBatchRawRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("fetchBatchRaw", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeFetchBatchRawArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+func NewWriteExemplarsRequestElement() *WriteExemplarsRequestElement {
+	return &WriteExemplarsRequestElement{}
 }
 
-func (p *NodeClient) recvFetchBatchRaw() (value *FetchBatchRawResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "fetchBatchRaw" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBatchRaw failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBatchRaw failed: out of sequence response")
-		return
+func (p *WriteExemplarsRequestElement) GetID() []byte {
+	return p.ID
+}
+
+func (p *WriteExemplarsRequestElement) GetExemplars() []*Exemplar {
+	return p.Exemplars
+}
+func (p *WriteExemplarsRequestElement) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error47 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error48 error
-		error48, err = error47.Read(iprot)
+
+	var issetID bool = false
+	var issetExemplars bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetID = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetExemplars = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error48
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBatchRaw failed: invalid message type")
-		return
 	}
-	result := NodeFetchBatchRawResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetID {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field ID is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetExemplars {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Exemplars is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) FetchBatchRawV2(req *FetchBatchRawV2Request) (r *FetchBatchRawResult_, err error) {
-	if err = p.sendFetchBatchRawV2(req); err != nil {
-		return
+func (p *WriteExemplarsRequestElement) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.ID = v
 	}
-	return p.recvFetchBatchRawV2()
+	return nil
 }
 
-func (p *NodeClient) sendFetchBatchRawV2(req *FetchBatchRawV2Request) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("fetchBatchRawV2", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeFetchBatchRawV2Args{
-		Req: req,
+func (p *WriteExemplarsRequestElement) ReadField2(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	tSlice := make([]*Exemplar, 0, size)
+	p.Exemplars = tSlice
+	for i := 0; i < size; i++ {
+		_elem37 := &Exemplar{}
+		if err := _elem37.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem37), err)
+		}
+		p.Exemplars = append(p.Exemplars, _elem37)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvFetchBatchRawV2() (value *FetchBatchRawResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "fetchBatchRawV2" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBatchRawV2 failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBatchRawV2 failed: out of sequence response")
-		return
+func (p *WriteExemplarsRequestElement) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("WriteExemplarsRequestElement"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error49 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error50 error
-		error50, err = error49.Read(iprot)
-		if err != nil {
-			return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
 		}
-		err = error50
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBatchRawV2 failed: invalid message type")
-		return
-	}
-	result := NodeFetchBatchRawV2Result{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	value = result.GetSuccess()
-	return
-}
-
-// Parameters:
-//  - Req
-func (p *NodeClient) FetchBlocksRaw(req *FetchBlocksRawRequest) (r *FetchBlocksRawResult_, err error) {
-	if err = p.sendFetchBlocksRaw(req); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return p.recvFetchBlocksRaw()
+	return nil
 }
 
-func (p *NodeClient) sendFetchBlocksRaw(req *FetchBlocksRawRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("fetchBlocksRaw", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeFetchBlocksRawArgs{
-		Req: req,
+func (p *WriteExemplarsRequestElement) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("id", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:id: ", p), err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteBinary(p.ID); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:id: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvFetchBlocksRaw() (value *FetchBlocksRawResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "fetchBlocksRaw" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBlocksRaw failed: wrong method name")
-		return
+func (p *WriteExemplarsRequestElement) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("exemplars", thrift.LIST, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:exemplars: ", p), err)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBlocksRaw failed: out of sequence response")
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.Exemplars)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error51 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error52 error
-		error52, err = error51.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	for _, v := range p.Exemplars {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
 		}
-		err = error52
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBlocksRaw failed: invalid message type")
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	result := NodeFetchBlocksRawResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:exemplars: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
-	}
-	if result.Err != nil {
-		err = result.Err
-		return
-	}
-	value = result.GetSuccess()
-	return
+	return err
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) FetchTagged(req *FetchTaggedRequest) (r *FetchTaggedResult_, err error) {
-	if err = p.sendFetchTagged(req); err != nil {
-		return
+func (p *WriteExemplarsRequestElement) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return p.recvFetchTagged()
+	return fmt.Sprintf("WriteExemplarsRequestElement(%+v)", *p)
 }
 
-func (p *NodeClient) sendFetchTagged(req *FetchTaggedRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("fetchTagged", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeFetchTaggedArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+// Attributes:
+//  - NameSpace
+//  - RangeStart
+//  - RangeEnd
+//  - Ids
+type FetchExemplarsRequest struct {
+	NameSpace  []byte   `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	RangeStart int64    `thrift:"rangeStart,2,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd   int64    `thrift:"rangeEnd,3,required" db:"rangeEnd" json:"rangeEnd"`
+	Ids        [][]byte `thrift:"ids,4,required" db:"ids" json:"ids"`
 }
 
-func (p *NodeClient) recvFetchTagged() (value *FetchTaggedResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "fetchTagged" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchTagged failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchTagged failed: out of sequence response")
-		return
+func NewFetchExemplarsRequest() *FetchExemplarsRequest {
+	return &FetchExemplarsRequest{}
+}
+
+func (p *FetchExemplarsRequest) GetNameSpace() []byte {
+	return p.NameSpace
+}
+
+func (p *FetchExemplarsRequest) GetRangeStart() int64 {
+	return p.RangeStart
+}
+
+func (p *FetchExemplarsRequest) GetRangeEnd() int64 {
+	return p.RangeEnd
+}
+
+func (p *FetchExemplarsRequest) GetIds() [][]byte {
+	return p.Ids
+}
+func (p *FetchExemplarsRequest) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error53 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error54 error
-		error54, err = error53.Read(iprot)
+
+	var issetNameSpace bool = false
+	var issetRangeStart bool = false
+	var issetRangeEnd bool = false
+	var issetIds bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNameSpace = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetRangeStart = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetRangeEnd = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetIds = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error54
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchTagged failed: invalid message type")
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	result := NodeFetchTaggedResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetRangeStart {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeStart is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetRangeEnd {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeEnd is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	if !issetIds {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Ids is not set"))
+	}
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) FetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (r *FetchBlocksMetadataRawV2Result_, err error) {
-	if err = p.sendFetchBlocksMetadataRawV2(req); err != nil {
-		return
+func (p *FetchExemplarsRequest) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NameSpace = v
 	}
-	return p.recvFetchBlocksMetadataRawV2()
+	return nil
 }
 
-func (p *NodeClient) sendFetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeFetchBlocksMetadataRawV2Args{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+func (p *FetchExemplarsRequest) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.RangeStart = v
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvFetchBlocksMetadataRawV2() (value *FetchBlocksMetadataRawV2Result_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *FetchExemplarsRequest) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.RangeEnd = v
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	return nil
+}
+
+func (p *FetchExemplarsRequest) ReadField4(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
 	if err != nil {
-		return
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if method != "fetchBlocksMetadataRawV2" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBlocksMetadataRawV2 failed: wrong method name")
-		return
+	tSlice := make([][]byte, 0, size)
+	p.Ids = tSlice
+	for i := 0; i < size; i++ {
+		var _elem38 []byte
+		if v, err := iprot.ReadBinary(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem38 = v
+		}
+		p.Ids = append(p.Ids, _elem38)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBlocksMetadataRawV2 failed: out of sequence response")
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error55 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error56 error
-		error56, err = error55.Read(iprot)
-		if err != nil {
-			return
+	return nil
+}
+
+func (p *FetchExemplarsRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("FetchExemplarsRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
 		}
-		err = error56
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBlocksMetadataRawV2 failed: invalid message type")
-		return
-	}
-	result := NodeFetchBlocksMetadataRawV2Result{}
-	if err = result.Read(iprot); err != nil {
-		return
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) WriteBatchRaw(req *WriteBatchRawRequest) (err error) {
-	if err = p.sendWriteBatchRaw(req); err != nil {
-		return
+func (p *FetchExemplarsRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:nameSpace: ", p), err)
 	}
-	return p.recvWriteBatchRaw()
-}
-
-func (p *NodeClient) sendWriteBatchRaw(req *WriteBatchRawRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+	if err := oprot.WriteBinary(p.NameSpace); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (1) field write error: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("writeBatchRaw", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:nameSpace: ", p), err)
 	}
-	args := NodeWriteBatchRawArgs{
-		Req: req,
+	return err
+}
+
+func (p *FetchExemplarsRequest) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeStart", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:rangeStart: ", p), err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.RangeStart)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeStart (2) field write error: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:rangeStart: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvWriteBatchRaw() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
+func (p *FetchExemplarsRequest) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeEnd", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:rangeEnd: ", p), err)
 	}
-	if method != "writeBatchRaw" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeBatchRaw failed: wrong method name")
-		return
+	if err := oprot.WriteI64(int64(p.RangeEnd)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeEnd (3) field write error: ", p), err)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeBatchRaw failed: out of sequence response")
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:rangeEnd: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error57 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error58 error
-		error58, err = error57.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
-		}
-		err = error58
-		return
+	return err
+}
+
+func (p *FetchExemplarsRequest) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("ids", thrift.LIST, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:ids: ", p), err)
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeBatchRaw failed: invalid message type")
-		return
+	if err := oprot.WriteListBegin(thrift.STRING, len(p.Ids)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	result := NodeWriteBatchRawResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	for _, v := range p.Ids {
+		if err := oprot.WriteBinary(v); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+		}
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:ids: ", p), err)
 	}
-	return
+	return err
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) WriteBatchRawV2(req *WriteBatchRawV2Request) (err error) {
-	if err = p.sendWriteBatchRawV2(req); err != nil {
-		return
+func (p *FetchExemplarsRequest) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return p.recvWriteBatchRawV2()
+	return fmt.Sprintf("FetchExemplarsRequest(%+v)", *p)
 }
 
-func (p *NodeClient) sendWriteBatchRawV2(req *WriteBatchRawV2Request) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("writeBatchRawV2", thrift.CALL, p.SeqId); err != nil {
-		return
+// Attributes:
+//  - Elements
+type FetchExemplarsResult_ struct {
+	Elements []*FetchExemplarsResultElement `thrift:"elements,1,required" db:"elements" json:"elements"`
+}
+
+func NewFetchExemplarsResult_() *FetchExemplarsResult_ {
+	return &FetchExemplarsResult_{}
+}
+
+func (p *FetchExemplarsResult_) GetElements() []*FetchExemplarsResultElement {
+	return p.Elements
+}
+func (p *FetchExemplarsResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	args := NodeWriteBatchRawV2Args{
-		Req: req,
+
+	var issetElements bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetElements = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if !issetElements {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Elements is not set"))
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvWriteBatchRawV2() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+func (p *FetchExemplarsResult_) ReadField1(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
 	if err != nil {
-		return
-	}
-	if method != "writeBatchRawV2" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeBatchRawV2 failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeBatchRawV2 failed: out of sequence response")
-		return
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error59 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error60 error
-		error60, err = error59.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	tSlice := make([]*FetchExemplarsResultElement, 0, size)
+	p.Elements = tSlice
+	for i := 0; i < size; i++ {
+		_elem39 := &FetchExemplarsResultElement{}
+		if err := _elem39.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem39), err)
 		}
-		err = error60
-		return
+		p.Elements = append(p.Elements, _elem39)
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeBatchRawV2 failed: invalid message type")
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	result := NodeWriteBatchRawV2Result{}
-	if err = result.Read(iprot); err != nil {
-		return
+	return nil
+}
+
+func (p *FetchExemplarsResult_) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("FetchExemplarsResult"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	return
-}
-
-// Parameters:
-//  - Req
-func (p *NodeClient) WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error) {
-	if err = p.sendWriteTaggedBatchRaw(req); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return p.recvWriteTaggedBatchRaw()
+	return nil
 }
 
-func (p *NodeClient) sendWriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *FetchExemplarsResult_) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("elements", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:elements: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.Elements)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	args := NodeWriteTaggedBatchRawArgs{
-		Req: req,
+	for _, v := range p.Elements {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:elements: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvWriteTaggedBatchRaw() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *FetchExemplarsResult_) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "writeTaggedBatchRaw" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeTaggedBatchRaw failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeTaggedBatchRaw failed: out of sequence response")
-		return
-	}
-	if mTypeId == thrift.EXCEPTION {
-		error61 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error62 error
-		error62, err = error61.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
-		}
-		err = error62
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeTaggedBatchRaw failed: invalid message type")
-		return
-	}
-	result := NodeWriteTaggedBatchRawResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
-	}
-	if result.Err != nil {
-		err = result.Err
-		return
-	}
-	return
+	return fmt.Sprintf("FetchExemplarsResult_(%+v)", *p)
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) WriteTaggedBatchRawV2(req *WriteTaggedBatchRawV2Request) (err error) {
-	if err = p.sendWriteTaggedBatchRawV2(req); err != nil {
-		return
-	}
-	return p.recvWriteTaggedBatchRawV2()
+// Attributes:
+//  - ID
+//  - Exemplars
+type FetchExemplarsResultElement struct {
+	ID        []byte      `thrift:"id,1,required" db:"id" json:"id"`
+	Exemplars []*Exemplar `thrift:"exemplars,2,required" db:"exemplars" json:"exemplars"`
 }
 
-func (p *NodeClient) sendWriteTaggedBatchRawV2(req *WriteTaggedBatchRawV2Request) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeWriteTaggedBatchRawV2Args{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+func NewFetchExemplarsResultElement() *FetchExemplarsResultElement {
+	return &FetchExemplarsResultElement{}
 }
 
-func (p *NodeClient) recvWriteTaggedBatchRawV2() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "writeTaggedBatchRawV2" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeTaggedBatchRawV2 failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeTaggedBatchRawV2 failed: out of sequence response")
-		return
+func (p *FetchExemplarsResultElement) GetID() []byte {
+	return p.ID
+}
+
+func (p *FetchExemplarsResultElement) GetExemplars() []*Exemplar {
+	return p.Exemplars
+}
+func (p *FetchExemplarsResultElement) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error63 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error64 error
-		error64, err = error63.Read(iprot)
+
+	var issetID bool = false
+	var issetExemplars bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetID = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetExemplars = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error64
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeTaggedBatchRawV2 failed: invalid message type")
-		return
 	}
-	result := NodeWriteTaggedBatchRawV2Result{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetID {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field ID is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetExemplars {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Exemplars is not set"))
 	}
-	return
+	return nil
 }
 
-func (p *NodeClient) Repair() (err error) {
-	if err = p.sendRepair(); err != nil {
-		return
+func (p *FetchExemplarsResultElement) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.ID = v
 	}
-	return p.recvRepair()
+	return nil
 }
 
-func (p *NodeClient) sendRepair() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("repair", thrift.CALL, p.SeqId); err != nil {
-		return
+func (p *FetchExemplarsResultElement) ReadField2(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	args := NodeRepairArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
+	tSlice := make([]*Exemplar, 0, size)
+	p.Exemplars = tSlice
+	for i := 0; i < size; i++ {
+		_elem40 := &Exemplar{}
+		if err := _elem40.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem40), err)
+		}
+		p.Exemplars = append(p.Exemplars, _elem40)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvRepair() (err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "repair" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "repair failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "repair failed: out of sequence response")
-		return
+func (p *FetchExemplarsResultElement) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("FetchExemplarsResultElement"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error65 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error66 error
-		error66, err = error65.Read(iprot)
-		if err != nil {
-			return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
 		}
-		err = error66
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "repair failed: invalid message type")
-		return
 	}
-	result := NodeRepairResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) Truncate(req *TruncateRequest) (r *TruncateResult_, err error) {
-	if err = p.sendTruncate(req); err != nil {
-		return
+func (p *FetchExemplarsResultElement) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("id", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:id: ", p), err)
 	}
-	return p.recvTruncate()
+	if err := oprot.WriteBinary(p.ID); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:id: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) sendTruncate(req *TruncateRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *FetchExemplarsResultElement) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("exemplars", thrift.LIST, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:exemplars: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("truncate", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.Exemplars)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	args := NodeTruncateArgs{
-		Req: req,
+	for _, v := range p.Exemplars {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:exemplars: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvTruncate() (value *TruncateResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "truncate" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "truncate failed: wrong method name")
-		return
+func (p *FetchExemplarsResultElement) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "truncate failed: out of sequence response")
-		return
+	return fmt.Sprintf("FetchExemplarsResultElement(%+v)", *p)
+}
+
+// Attributes:
+//  - NameSpace
+//  - Query
+//  - RangeStart
+//  - RangeEnd
+type DeleteTaggedRequest struct {
+	NameSpace  []byte `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	Query      []byte `thrift:"query,2,required" db:"query" json:"query"`
+	RangeStart int64  `thrift:"rangeStart,3,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd   int64  `thrift:"rangeEnd,4,required" db:"rangeEnd" json:"rangeEnd"`
+}
+
+func NewDeleteTaggedRequest() *DeleteTaggedRequest {
+	return &DeleteTaggedRequest{}
+}
+
+func (p *DeleteTaggedRequest) GetNameSpace() []byte {
+	return p.NameSpace
+}
+
+func (p *DeleteTaggedRequest) GetQuery() []byte {
+	return p.Query
+}
+
+func (p *DeleteTaggedRequest) GetRangeStart() int64 {
+	return p.RangeStart
+}
+
+func (p *DeleteTaggedRequest) GetRangeEnd() int64 {
+	return p.RangeEnd
+}
+func (p *DeleteTaggedRequest) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error67 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error68 error
-		error68, err = error67.Read(iprot)
+
+	var issetNameSpace bool = false
+	var issetQuery bool = false
+	var issetRangeStart bool = false
+	var issetRangeEnd bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNameSpace = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetQuery = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetRangeStart = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetRangeEnd = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error68
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "truncate failed: invalid message type")
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	result := NodeTruncateResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetQuery {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Query is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetRangeStart {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeStart is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	if !issetRangeEnd {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeEnd is not set"))
+	}
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) AggregateTiles(req *AggregateTilesRequest) (r *AggregateTilesResult_, err error) {
-	if err = p.sendAggregateTiles(req); err != nil {
-		return
+func (p *DeleteTaggedRequest) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NameSpace = v
 	}
-	return p.recvAggregateTiles()
+	return nil
 }
 
-func (p *NodeClient) sendAggregateTiles(req *AggregateTilesRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("aggregateTiles", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeAggregateTilesArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+func (p *DeleteTaggedRequest) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Query = v
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvAggregateTiles() (value *AggregateTilesResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
+func (p *DeleteTaggedRequest) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.RangeStart = v
 	}
-	if method != "aggregateTiles" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "aggregateTiles failed: wrong method name")
-		return
+	return nil
+}
+
+func (p *DeleteTaggedRequest) ReadField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.RangeEnd = v
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "aggregateTiles failed: out of sequence response")
-		return
+	return nil
+}
+
+func (p *DeleteTaggedRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("DeleteTaggedRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error69 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error70 error
-		error70, err = error69.Read(iprot)
-		if err != nil {
-			return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
 		}
-		err = error70
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "aggregateTiles failed: invalid message type")
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	result := NodeAggregateTilesResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	return nil
+}
+
+func (p *DeleteTaggedRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:nameSpace: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteBinary(p.NameSpace); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (1) field write error: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:nameSpace: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) Health() (r *NodeHealthResult_, err error) {
-	if err = p.sendHealth(); err != nil {
-		return
+func (p *DeleteTaggedRequest) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("query", thrift.STRING, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:query: ", p), err)
 	}
-	return p.recvHealth()
+	if err := oprot.WriteBinary(p.Query); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.query (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:query: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) sendHealth() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("health", thrift.CALL, p.SeqId); err != nil {
-		return
+func (p *DeleteTaggedRequest) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeStart", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:rangeStart: ", p), err)
 	}
-	args := NodeHealthArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.RangeStart)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeStart (3) field write error: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:rangeStart: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvHealth() (value *NodeHealthResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "health" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "health failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "health failed: out of sequence response")
-		return
-	}
-	if mTypeId == thrift.EXCEPTION {
-		error71 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error72 error
-		error72, err = error71.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
-		}
-		err = error72
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "health failed: invalid message type")
-		return
-	}
-	result := NodeHealthResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+func (p *DeleteTaggedRequest) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeEnd", thrift.I64, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:rangeEnd: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.RangeEnd)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeEnd (4) field write error: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:rangeEnd: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	return err
 }
 
-func (p *NodeClient) Bootstrapped() (r *NodeBootstrappedResult_, err error) {
-	if err = p.sendBootstrapped(); err != nil {
-		return
+func (p *DeleteTaggedRequest) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return p.recvBootstrapped()
+	return fmt.Sprintf("DeleteTaggedRequest(%+v)", *p)
 }
 
-func (p *NodeClient) sendBootstrapped() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("bootstrapped", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeBootstrappedArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+// Attributes:
+//  - NumSeries
+type DeleteTaggedResult_ struct {
+	NumSeries int64 `thrift:"numSeries,1,required" db:"numSeries" json:"numSeries"`
 }
 
-func (p *NodeClient) recvBootstrapped() (value *NodeBootstrappedResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "bootstrapped" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "bootstrapped failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "bootstrapped failed: out of sequence response")
-		return
+func NewDeleteTaggedResult_() *DeleteTaggedResult_ {
+	return &DeleteTaggedResult_{}
+}
+
+func (p *DeleteTaggedResult_) GetNumSeries() int64 {
+	return p.NumSeries
+}
+func (p *DeleteTaggedResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error73 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error74 error
-		error74, err = error73.Read(iprot)
+
+	var issetNumSeries bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNumSeries = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error74
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "bootstrapped failed: invalid message type")
-		return
-	}
-	result := NodeBootstrappedResult{}
-	if err = result.Read(iprot); err != nil {
-		return
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetNumSeries {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NumSeries is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-func (p *NodeClient) BootstrappedInPlacementOrNoPlacement() (r *NodeBootstrappedInPlacementOrNoPlacementResult_, err error) {
-	if err = p.sendBootstrappedInPlacementOrNoPlacement(); err != nil {
-		return
+func (p *DeleteTaggedResult_) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NumSeries = v
 	}
-	return p.recvBootstrappedInPlacementOrNoPlacement()
+	return nil
 }
 
-func (p *NodeClient) sendBootstrappedInPlacementOrNoPlacement() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *DeleteTaggedResult_) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("DeleteTaggedResult"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.CALL, p.SeqId); err != nil {
-		return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
-	args := NodeBootstrappedInPlacementOrNoPlacementArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvBootstrappedInPlacementOrNoPlacement() (value *NodeBootstrappedInPlacementOrNoPlacementResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "bootstrappedInPlacementOrNoPlacement" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "bootstrappedInPlacementOrNoPlacement failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "bootstrappedInPlacementOrNoPlacement failed: out of sequence response")
-		return
-	}
-	if mTypeId == thrift.EXCEPTION {
-		error75 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error76 error
-		error76, err = error75.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
-		}
-		err = error76
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "bootstrappedInPlacementOrNoPlacement failed: invalid message type")
-		return
-	}
-	result := NodeBootstrappedInPlacementOrNoPlacementResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+func (p *DeleteTaggedResult_) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("numSeries", thrift.I64, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:numSeries: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.NumSeries)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.numSeries (1) field write error: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:numSeries: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	return err
 }
 
-func (p *NodeClient) GetPersistRateLimit() (r *NodePersistRateLimitResult_, err error) {
-	if err = p.sendGetPersistRateLimit(); err != nil {
-		return
+func (p *DeleteTaggedResult_) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return p.recvGetPersistRateLimit()
+	return fmt.Sprintf("DeleteTaggedResult_(%+v)", *p)
 }
 
-func (p *NodeClient) sendGetPersistRateLimit() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("getPersistRateLimit", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeGetPersistRateLimitArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+// Attributes:
+//  - NameSpace
+//  - RangeStart
+//  - RangeEnd
+//  - Limit
+//  - Shards
+//  - MetricNameTag
+type CardinalityRequest struct {
+	NameSpace     []byte  `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	RangeStart    int64   `thrift:"rangeStart,2,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd      int64   `thrift:"rangeEnd,3,required" db:"rangeEnd" json:"rangeEnd"`
+	Limit         int64   `thrift:"limit,4,required" db:"limit" json:"limit"`
+	Shards        []int32 `thrift:"shards,5,required" db:"shards" json:"shards"`
+	MetricNameTag []byte  `thrift:"metricNameTag,6,required" db:"metricNameTag" json:"metricNameTag"`
 }
 
-func (p *NodeClient) recvGetPersistRateLimit() (value *NodePersistRateLimitResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "getPersistRateLimit" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getPersistRateLimit failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getPersistRateLimit failed: out of sequence response")
-		return
+func NewCardinalityRequest() *CardinalityRequest {
+	return &CardinalityRequest{}
+}
+
+func (p *CardinalityRequest) GetNameSpace() []byte {
+	return p.NameSpace
+}
+
+func (p *CardinalityRequest) GetRangeStart() int64 {
+	return p.RangeStart
+}
+
+func (p *CardinalityRequest) GetRangeEnd() int64 {
+	return p.RangeEnd
+}
+
+func (p *CardinalityRequest) GetLimit() int64 {
+	return p.Limit
+}
+
+func (p *CardinalityRequest) GetShards() []int32 {
+	return p.Shards
+}
+
+func (p *CardinalityRequest) GetMetricNameTag() []byte {
+	return p.MetricNameTag
+}
+func (p *CardinalityRequest) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error77 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error78 error
-		error78, err = error77.Read(iprot)
+
+	var issetNameSpace bool = false
+	var issetRangeStart bool = false
+	var issetRangeEnd bool = false
+	var issetLimit bool = false
+	var issetShards bool = false
+	var issetMetricNameTag bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNameSpace = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetRangeStart = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetRangeEnd = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetLimit = true
+		case 5:
+			if err := p.ReadField5(iprot); err != nil {
+				return err
+			}
+			issetShards = true
+		case 6:
+			if err := p.ReadField6(iprot); err != nil {
+				return err
+			}
+			issetMetricNameTag = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error78
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getPersistRateLimit failed: invalid message type")
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	result := NodeGetPersistRateLimitResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetRangeStart {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeStart is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetRangeEnd {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeEnd is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	if !issetLimit {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Limit is not set"))
+	}
+	if !issetShards {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Shards is not set"))
+	}
+	if !issetMetricNameTag {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field MetricNameTag is not set"))
+	}
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) SetPersistRateLimit(req *NodeSetPersistRateLimitRequest) (r *NodePersistRateLimitResult_, err error) {
-	if err = p.sendSetPersistRateLimit(req); err != nil {
-		return
+func (p *CardinalityRequest) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NameSpace = v
 	}
-	return p.recvSetPersistRateLimit()
+	return nil
 }
 
-func (p *NodeClient) sendSetPersistRateLimit(req *NodeSetPersistRateLimitRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("setPersistRateLimit", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeSetPersistRateLimitArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
+func (p *CardinalityRequest) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.RangeStart = v
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	return nil
+}
+
+func (p *CardinalityRequest) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.RangeEnd = v
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvSetPersistRateLimit() (value *NodePersistRateLimitResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *CardinalityRequest) ReadField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.Limit = v
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	return nil
+}
+
+func (p *CardinalityRequest) ReadField5(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
 	if err != nil {
-		return
-	}
-	if method != "setPersistRateLimit" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setPersistRateLimit failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setPersistRateLimit failed: out of sequence response")
-		return
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error79 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error80 error
-		error80, err = error79.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	tSlice := make([]int32, 0, size)
+	p.Shards = tSlice
+	for i := 0; i < size; i++ {
+		var _elem41 int32
+		if v, err := iprot.ReadI32(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem41 = v
 		}
-		err = error80
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setPersistRateLimit failed: invalid message type")
-		return
+		p.Shards = append(p.Shards, _elem41)
 	}
-	result := NodeSetPersistRateLimitResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
-	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-func (p *NodeClient) GetWriteNewSeriesAsync() (r *NodeWriteNewSeriesAsyncResult_, err error) {
-	if err = p.sendGetWriteNewSeriesAsync(); err != nil {
-		return
+func (p *CardinalityRequest) ReadField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.MetricNameTag = v
 	}
-	return p.recvGetWriteNewSeriesAsync()
+	return nil
 }
 
-func (p *NodeClient) sendGetWriteNewSeriesAsync() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *CardinalityRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CardinalityRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.CALL, p.SeqId); err != nil {
-		return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(oprot); err != nil {
+			return err
+		}
 	}
-	args := NodeGetWriteNewSeriesAsyncArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvGetWriteNewSeriesAsync() (value *NodeWriteNewSeriesAsyncResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *CardinalityRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:nameSpace: ", p), err)
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
+	if err := oprot.WriteBinary(p.NameSpace); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (1) field write error: ", p), err)
 	}
-	if method != "getWriteNewSeriesAsync" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getWriteNewSeriesAsync failed: wrong method name")
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:nameSpace: ", p), err)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getWriteNewSeriesAsync failed: out of sequence response")
-		return
+	return err
+}
+
+func (p *CardinalityRequest) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeStart", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:rangeStart: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error81 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error82 error
-		error82, err = error81.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
-		}
-		err = error82
-		return
+	if err := oprot.WriteI64(int64(p.RangeStart)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeStart (2) field write error: ", p), err)
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getWriteNewSeriesAsync failed: invalid message type")
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:rangeStart: ", p), err)
 	}
-	result := NodeGetWriteNewSeriesAsyncResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	return err
+}
+
+func (p *CardinalityRequest) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeEnd", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:rangeEnd: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.RangeEnd)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeEnd (3) field write error: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:rangeEnd: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	return err
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) SetWriteNewSeriesAsync(req *NodeSetWriteNewSeriesAsyncRequest) (r *NodeWriteNewSeriesAsyncResult_, err error) {
-	if err = p.sendSetWriteNewSeriesAsync(req); err != nil {
-		return
+func (p *CardinalityRequest) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("limit", thrift.I64, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:limit: ", p), err)
 	}
-	return p.recvSetWriteNewSeriesAsync()
+	if err := oprot.WriteI64(int64(p.Limit)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.limit (4) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:limit: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) sendSetWriteNewSeriesAsync(req *NodeSetWriteNewSeriesAsyncRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *CardinalityRequest) writeField5(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("shards", thrift.LIST, 5); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:shards: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteListBegin(thrift.I32, len(p.Shards)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	args := NodeSetWriteNewSeriesAsyncArgs{
-		Req: req,
+	for _, v := range p.Shards {
+		if err := oprot.WriteI32(int32(v)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+		}
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 5:shards: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvSetWriteNewSeriesAsync() (value *NodeWriteNewSeriesAsyncResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *CardinalityRequest) writeField6(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("metricNameTag", thrift.STRING, 6); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:metricNameTag: ", p), err)
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
+	if err := oprot.WriteBinary(p.MetricNameTag); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.metricNameTag (6) field write error: ", p), err)
 	}
-	if method != "setWriteNewSeriesAsync" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setWriteNewSeriesAsync failed: wrong method name")
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 6:metricNameTag: ", p), err)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setWriteNewSeriesAsync failed: out of sequence response")
-		return
+	return err
+}
+
+func (p *CardinalityRequest) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error83 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error84 error
-		error84, err = error83.Read(iprot)
+	return fmt.Sprintf("CardinalityRequest(%+v)", *p)
+}
+
+// Attributes:
+//  - NumSeries
+//  - SeriesCountByMetricName
+//  - LabelValueCountByLabelName
+//  - SeriesCountByLabelValuePair
+type CardinalityResult_ struct {
+	NumSeries                   int64              `thrift:"numSeries,1,required" db:"numSeries" json:"numSeries"`
+	SeriesCountByMetricName     []*CardinalityStat `thrift:"seriesCountByMetricName,2,required" db:"seriesCountByMetricName" json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []*CardinalityStat `thrift:"labelValueCountByLabelName,3,required" db:"labelValueCountByLabelName" json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []*CardinalityStat `thrift:"seriesCountByLabelValuePair,4,required" db:"seriesCountByLabelValuePair" json:"seriesCountByLabelValuePair"`
+}
+
+func NewCardinalityResult_() *CardinalityResult_ {
+	return &CardinalityResult_{}
+}
+
+func (p *CardinalityResult_) GetNumSeries() int64 {
+	return p.NumSeries
+}
+
+func (p *CardinalityResult_) GetSeriesCountByMetricName() []*CardinalityStat {
+	return p.SeriesCountByMetricName
+}
+
+func (p *CardinalityResult_) GetLabelValueCountByLabelName() []*CardinalityStat {
+	return p.LabelValueCountByLabelName
+}
+
+func (p *CardinalityResult_) GetSeriesCountByLabelValuePair() []*CardinalityStat {
+	return p.SeriesCountByLabelValuePair
+}
+func (p *CardinalityResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetNumSeries bool = false
+	var issetSeriesCountByMetricName bool = false
+	var issetLabelValueCountByLabelName bool = false
+	var issetSeriesCountByLabelValuePair bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNumSeries = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetSeriesCountByMetricName = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetLabelValueCountByLabelName = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetSeriesCountByLabelValuePair = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error84
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setWriteNewSeriesAsync failed: invalid message type")
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	result := NodeSetWriteNewSeriesAsyncResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if !issetNumSeries {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NumSeries is not set"))
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetSeriesCountByMetricName {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field SeriesCountByMetricName is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetLabelValueCountByLabelName {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field LabelValueCountByLabelName is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	if !issetSeriesCountByLabelValuePair {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field SeriesCountByLabelValuePair is not set"))
+	}
+	return nil
 }
 
-func (p *NodeClient) GetWriteNewSeriesBackoffDuration() (r *NodeWriteNewSeriesBackoffDurationResult_, err error) {
-	if err = p.sendGetWriteNewSeriesBackoffDuration(); err != nil {
-		return
+func (p *CardinalityResult_) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NumSeries = v
 	}
-	return p.recvGetWriteNewSeriesBackoffDuration()
+	return nil
 }
 
-func (p *NodeClient) sendGetWriteNewSeriesBackoffDuration() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
-	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.CALL, p.SeqId); err != nil {
-		return
+func (p *CardinalityResult_) ReadField2(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	args := NodeGetWriteNewSeriesBackoffDurationArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
+	tSlice := make([]*CardinalityStat, 0, size)
+	p.SeriesCountByMetricName = tSlice
+	for i := 0; i < size; i++ {
+		_elem42 := &CardinalityStat{}
+		if err := _elem42.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem42), err)
+		}
+		p.SeriesCountByMetricName = append(p.SeriesCountByMetricName, _elem42)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvGetWriteNewSeriesBackoffDuration() (value *NodeWriteNewSeriesBackoffDurationResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+func (p *CardinalityResult_) ReadField3(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
 	if err != nil {
-		return
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if method != "getWriteNewSeriesBackoffDuration" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getWriteNewSeriesBackoffDuration failed: wrong method name")
-		return
+	tSlice := make([]*CardinalityStat, 0, size)
+	p.LabelValueCountByLabelName = tSlice
+	for i := 0; i < size; i++ {
+		_elem43 := &CardinalityStat{}
+		if err := _elem43.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem43), err)
+		}
+		p.LabelValueCountByLabelName = append(p.LabelValueCountByLabelName, _elem43)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getWriteNewSeriesBackoffDuration failed: out of sequence response")
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error85 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error86 error
-		error86, err = error85.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	return nil
+}
+
+func (p *CardinalityResult_) ReadField4(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*CardinalityStat, 0, size)
+	p.SeriesCountByLabelValuePair = tSlice
+	for i := 0; i < size; i++ {
+		_elem44 := &CardinalityStat{}
+		if err := _elem44.Read(iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem44), err)
 		}
-		err = error86
-		return
+		p.SeriesCountByLabelValuePair = append(p.SeriesCountByLabelValuePair, _elem44)
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getWriteNewSeriesBackoffDuration failed: invalid message type")
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	result := NodeGetWriteNewSeriesBackoffDurationResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	return nil
+}
+
+func (p *CardinalityResult_) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CardinalityResult"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
+		}
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	value = result.GetSuccess()
-	return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) SetWriteNewSeriesBackoffDuration(req *NodeSetWriteNewSeriesBackoffDurationRequest) (r *NodeWriteNewSeriesBackoffDurationResult_, err error) {
-	if err = p.sendSetWriteNewSeriesBackoffDuration(req); err != nil {
-		return
+func (p *CardinalityResult_) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("numSeries", thrift.I64, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:numSeries: ", p), err)
 	}
-	return p.recvSetWriteNewSeriesBackoffDuration()
+	if err := oprot.WriteI64(int64(p.NumSeries)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.numSeries (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:numSeries: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) sendSetWriteNewSeriesBackoffDuration(req *NodeSetWriteNewSeriesBackoffDurationRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *CardinalityResult_) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("seriesCountByMetricName", thrift.LIST, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:seriesCountByMetricName: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.SeriesCountByMetricName)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	args := NodeSetWriteNewSeriesBackoffDurationArgs{
-		Req: req,
+	for _, v := range p.SeriesCountByMetricName {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:seriesCountByMetricName: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvSetWriteNewSeriesBackoffDuration() (value *NodeWriteNewSeriesBackoffDurationResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *CardinalityResult_) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("labelValueCountByLabelName", thrift.LIST, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:labelValueCountByLabelName: ", p), err)
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "setWriteNewSeriesBackoffDuration" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setWriteNewSeriesBackoffDuration failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setWriteNewSeriesBackoffDuration failed: out of sequence response")
-		return
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.LabelValueCountByLabelName)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error87 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error88 error
-		error88, err = error87.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+	for _, v := range p.LabelValueCountByLabelName {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
 		}
-		err = error88
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setWriteNewSeriesBackoffDuration failed: invalid message type")
-		return
-	}
-	result := NodeSetWriteNewSeriesBackoffDurationResult{}
-	if err = result.Read(iprot); err != nil {
-		return
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:labelValueCountByLabelName: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	return err
 }
 
-func (p *NodeClient) GetWriteNewSeriesLimitPerShardPerSecond() (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
-	if err = p.sendGetWriteNewSeriesLimitPerShardPerSecond(); err != nil {
-		return
+func (p *CardinalityResult_) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("seriesCountByLabelValuePair", thrift.LIST, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:seriesCountByLabelValuePair: ", p), err)
 	}
-	return p.recvGetWriteNewSeriesLimitPerShardPerSecond()
-}
-
-func (p *NodeClient) sendGetWriteNewSeriesLimitPerShardPerSecond() (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(p.SeriesCountByLabelValuePair)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.CALL, p.SeqId); err != nil {
-		return
+	for _, v := range p.SeriesCountByLabelValuePair {
+		if err := v.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
 	}
-	args := NodeGetWriteNewSeriesLimitPerShardPerSecondArgs{}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:seriesCountByLabelValuePair: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvGetWriteNewSeriesLimitPerShardPerSecond() (value *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "getWriteNewSeriesLimitPerShardPerSecond" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getWriteNewSeriesLimitPerShardPerSecond failed: wrong method name")
-		return
+func (p *CardinalityResult_) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getWriteNewSeriesLimitPerShardPerSecond failed: out of sequence response")
-		return
+	return fmt.Sprintf("CardinalityResult_(%+v)", *p)
+}
+
+// Attributes:
+//  - Name
+//  - Value
+type CardinalityStat struct {
+	Name  []byte `thrift:"name,1,required" db:"name" json:"name"`
+	Value int64  `thrift:"value,2,required" db:"value" json:"value"`
+}
+
+func NewCardinalityStat() *CardinalityStat {
+	return &CardinalityStat{}
+}
+
+func (p *CardinalityStat) GetName() []byte {
+	return p.Name
+}
+
+func (p *CardinalityStat) GetValue() int64 {
+	return p.Value
+}
+func (p *CardinalityStat) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error89 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error90 error
-		error90, err = error89.Read(iprot)
+
+	var issetName bool = false
+	var issetValue bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetName = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetValue = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error90
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getWriteNewSeriesLimitPerShardPerSecond failed: invalid message type")
-		return
 	}
-	result := NodeGetWriteNewSeriesLimitPerShardPerSecondResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetName {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Name is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetValue {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Value is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) SetWriteNewSeriesLimitPerShardPerSecond(req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
-	if err = p.sendSetWriteNewSeriesLimitPerShardPerSecond(req); err != nil {
-		return
+func (p *CardinalityStat) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Name = v
 	}
-	return p.recvSetWriteNewSeriesLimitPerShardPerSecond()
+	return nil
 }
 
-func (p *NodeClient) sendSetWriteNewSeriesLimitPerShardPerSecond(req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *CardinalityStat) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Value = v
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.CALL, p.SeqId); err != nil {
-		return
-	}
-	args := NodeSetWriteNewSeriesLimitPerShardPerSecondArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvSetWriteNewSeriesLimitPerShardPerSecond() (value *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "setWriteNewSeriesLimitPerShardPerSecond" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setWriteNewSeriesLimitPerShardPerSecond failed: wrong method name")
-		return
-	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setWriteNewSeriesLimitPerShardPerSecond failed: out of sequence response")
-		return
+func (p *CardinalityStat) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CardinalityStat"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error91 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error92 error
-		error92, err = error91.Read(iprot)
-		if err != nil {
-			return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
 		}
-		err = error92
-		return
-	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setWriteNewSeriesLimitPerShardPerSecond failed: invalid message type")
-		return
 	}
-	result := NodeSetWriteNewSeriesLimitPerShardPerSecondResult{}
-	if err = result.Read(iprot); err != nil {
-		return
-	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	value = result.GetSuccess()
-	return
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) DebugProfileStart(req *DebugProfileStartRequest) (r *DebugProfileStartResult_, err error) {
-	if err = p.sendDebugProfileStart(req); err != nil {
-		return
+func (p *CardinalityStat) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("name", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:name: ", p), err)
 	}
-	return p.recvDebugProfileStart()
-}
-
-func (p *NodeClient) sendDebugProfileStart(req *DebugProfileStartRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+	if err := oprot.WriteBinary(p.Name); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.name (1) field write error: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("debugProfileStart", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:name: ", p), err)
 	}
-	args := NodeDebugProfileStartArgs{
-		Req: req,
+	return err
+}
+
+func (p *CardinalityStat) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("value", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:value: ", p), err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.Value)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.value (2) field write error: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:value: ", p), err)
 	}
-	return oprot.Flush()
+	return err
 }
 
-func (p *NodeClient) recvDebugProfileStart() (value *DebugProfileStartResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
-	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
-	}
-	if method != "debugProfileStart" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "debugProfileStart failed: wrong method name")
-		return
+func (p *CardinalityStat) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "debugProfileStart failed: out of sequence response")
-		return
+	return fmt.Sprintf("CardinalityStat(%+v)", *p)
+}
+
+// Attributes:
+//  - NameSpace
+//  - Query
+//  - RangeStart
+//  - RangeEnd
+//  - Shards
+//  - IncludeSketch
+type CountRequest struct {
+	NameSpace     []byte  `thrift:"nameSpace,1,required" db:"nameSpace" json:"nameSpace"`
+	Query         []byte  `thrift:"query,2,required" db:"query" json:"query"`
+	RangeStart    int64   `thrift:"rangeStart,3,required" db:"rangeStart" json:"rangeStart"`
+	RangeEnd      int64   `thrift:"rangeEnd,4,required" db:"rangeEnd" json:"rangeEnd"`
+	Shards        []int32 `thrift:"shards,5,required" db:"shards" json:"shards"`
+	IncludeSketch bool    `thrift:"includeSketch,6,required" db:"includeSketch" json:"includeSketch"`
+}
+
+func NewCountRequest() *CountRequest {
+	return &CountRequest{}
+}
+
+func (p *CountRequest) GetNameSpace() []byte {
+	return p.NameSpace
+}
+
+func (p *CountRequest) GetQuery() []byte {
+	return p.Query
+}
+
+func (p *CountRequest) GetRangeStart() int64 {
+	return p.RangeStart
+}
+
+func (p *CountRequest) GetRangeEnd() int64 {
+	return p.RangeEnd
+}
+
+func (p *CountRequest) GetShards() []int32 {
+	return p.Shards
+}
+
+func (p *CountRequest) GetIncludeSketch() bool {
+	return p.IncludeSketch
+}
+func (p *CountRequest) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error93 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error94 error
-		error94, err = error93.Read(iprot)
+
+	var issetNameSpace bool = false
+	var issetQuery bool = false
+	var issetRangeStart bool = false
+	var issetRangeEnd bool = false
+	var issetShards bool = false
+	var issetIncludeSketch bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
 		if err != nil {
-			return
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetNameSpace = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetQuery = true
+		case 3:
+			if err := p.ReadField3(iprot); err != nil {
+				return err
+			}
+			issetRangeStart = true
+		case 4:
+			if err := p.ReadField4(iprot); err != nil {
+				return err
+			}
+			issetRangeEnd = true
+		case 5:
+			if err := p.ReadField5(iprot); err != nil {
+				return err
+			}
+			issetShards = true
+		case 6:
+			if err := p.ReadField6(iprot); err != nil {
+				return err
+			}
+			issetIncludeSketch = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-		err = error94
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "debugProfileStart failed: invalid message type")
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	result := NodeDebugProfileStartResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if !issetNameSpace {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field NameSpace is not set"))
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if !issetQuery {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Query is not set"))
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if !issetRangeStart {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeStart is not set"))
 	}
-	value = result.GetSuccess()
-	return
+	if !issetRangeEnd {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field RangeEnd is not set"))
+	}
+	if !issetShards {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Shards is not set"))
+	}
+	if !issetIncludeSketch {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field IncludeSketch is not set"))
+	}
+	return nil
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) DebugProfileStop(req *DebugProfileStopRequest) (r *DebugProfileStopResult_, err error) {
-	if err = p.sendDebugProfileStop(req); err != nil {
-		return
+func (p *CountRequest) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.NameSpace = v
 	}
-	return p.recvDebugProfileStop()
+	return nil
 }
 
-func (p *NodeClient) sendDebugProfileStop(req *DebugProfileStopRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *CountRequest) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Query = v
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("debugProfileStop", thrift.CALL, p.SeqId); err != nil {
-		return
+	return nil
+}
+
+func (p *CountRequest) ReadField3(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.RangeStart = v
 	}
-	args := NodeDebugProfileStopArgs{
-		Req: req,
-	}
-	if err = args.Write(oprot); err != nil {
-		return
-	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
-	}
-	return oprot.Flush()
+	return nil
 }
 
-func (p *NodeClient) recvDebugProfileStop() (value *DebugProfileStopResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *CountRequest) ReadField4(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.RangeEnd = v
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	return nil
+}
+
+func (p *CountRequest) ReadField5(iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin()
 	if err != nil {
-		return
+		return thrift.PrependError("error reading list begin: ", err)
 	}
-	if method != "debugProfileStop" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "debugProfileStop failed: wrong method name")
-		return
+	tSlice := make([]int32, 0, size)
+	p.Shards = tSlice
+	for i := 0; i < size; i++ {
+		var _elem45 int32
+		if v, err := iprot.ReadI32(); err != nil {
+			return thrift.PrependError("error reading field 0: ", err)
+		} else {
+			_elem45 = v
+		}
+		p.Shards = append(p.Shards, _elem45)
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "debugProfileStop failed: out of sequence response")
-		return
+	if err := iprot.ReadListEnd(); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error95 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error96 error
-		error96, err = error95.Read(iprot)
-		if err != nil {
-			return
+	return nil
+}
+
+func (p *CountRequest) ReadField6(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.IncludeSketch = v
+	}
+	return nil
+}
+
+func (p *CountRequest) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CountRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(oprot); err != nil {
+			return err
 		}
-		err = error96
-		return
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "debugProfileStop failed: invalid message type")
-		return
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	result := NodeDebugProfileStopResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	return nil
+}
+
+func (p *CountRequest) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("nameSpace", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:nameSpace: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	if err := oprot.WriteBinary(p.NameSpace); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.nameSpace (1) field write error: ", p), err)
 	}
-	value = result.GetSuccess()
-	return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:nameSpace: ", p), err)
+	}
+	return err
 }
 
-// Parameters:
-//  - Req
-func (p *NodeClient) DebugIndexMemorySegments(req *DebugIndexMemorySegmentsRequest) (r *DebugIndexMemorySegmentsResult_, err error) {
-	if err = p.sendDebugIndexMemorySegments(req); err != nil {
-		return
+func (p *CountRequest) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("query", thrift.STRING, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:query: ", p), err)
 	}
-	return p.recvDebugIndexMemorySegments()
+	if err := oprot.WriteBinary(p.Query); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.query (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:query: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) sendDebugIndexMemorySegments(req *DebugIndexMemorySegmentsRequest) (err error) {
-	oprot := p.OutputProtocol
-	if oprot == nil {
-		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.OutputProtocol = oprot
+func (p *CountRequest) writeField3(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeStart", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:rangeStart: ", p), err)
 	}
-	p.SeqId++
-	if err = oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.CALL, p.SeqId); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.RangeStart)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeStart (3) field write error: ", p), err)
 	}
-	args := NodeDebugIndexMemorySegmentsArgs{
-		Req: req,
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:rangeStart: ", p), err)
 	}
-	if err = args.Write(oprot); err != nil {
-		return
+	return err
+}
+
+func (p *CountRequest) writeField4(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("rangeEnd", thrift.I64, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:rangeEnd: ", p), err)
 	}
-	if err = oprot.WriteMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteI64(int64(p.RangeEnd)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.rangeEnd (4) field write error: ", p), err)
 	}
-	return oprot.Flush()
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:rangeEnd: ", p), err)
+	}
+	return err
 }
 
-func (p *NodeClient) recvDebugIndexMemorySegments() (value *DebugIndexMemorySegmentsResult_, err error) {
-	iprot := p.InputProtocol
-	if iprot == nil {
-		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
-		p.InputProtocol = iprot
+func (p *CountRequest) writeField5(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("shards", thrift.LIST, 5); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:shards: ", p), err)
 	}
-	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return
+	if err := oprot.WriteListBegin(thrift.I32, len(p.Shards)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
 	}
-	if method != "debugIndexMemorySegments" {
-		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "debugIndexMemorySegments failed: wrong method name")
-		return
+	for _, v := range p.Shards {
+		if err := oprot.WriteI32(int32(v)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err)
+		}
 	}
-	if p.SeqId != seqId {
-		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "debugIndexMemorySegments failed: out of sequence response")
-		return
+	if err := oprot.WriteListEnd(); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
 	}
-	if mTypeId == thrift.EXCEPTION {
-		error97 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error98 error
-		error98, err = error97.Read(iprot)
-		if err != nil {
-			return
-		}
-		if err = iprot.ReadMessageEnd(); err != nil {
-			return
-		}
-		err = error98
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 5:shards: ", p), err)
 	}
-	if mTypeId != thrift.REPLY {
-		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "debugIndexMemorySegments failed: invalid message type")
-		return
+	return err
+}
+
+func (p *CountRequest) writeField6(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("includeSketch", thrift.BOOL, 6); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:includeSketch: ", p), err)
 	}
-	result := NodeDebugIndexMemorySegmentsResult{}
-	if err = result.Read(iprot); err != nil {
-		return
+	if err := oprot.WriteBool(bool(p.IncludeSketch)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.includeSketch (6) field write error: ", p), err)
 	}
-	if err = iprot.ReadMessageEnd(); err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 6:includeSketch: ", p), err)
 	}
-	if result.Err != nil {
-		err = result.Err
-		return
+	return err
+}
+
+func (p *CountRequest) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	value = result.GetSuccess()
-	return
+	return fmt.Sprintf("CountRequest(%+v)", *p)
 }
 
-type NodeProcessor struct {
-	processorMap map[string]thrift.TProcessorFunction
-	handler      Node
+// Attributes:
+//  - Count
+//  - Sketch
+type CountResult_ struct {
+	Count  int64  `thrift:"count,1,required" db:"count" json:"count"`
+	Sketch []byte `thrift:"sketch,2,required" db:"sketch" json:"sketch"`
 }
 
-func (p *NodeProcessor) AddToProcessorMap(key string, processor thrift.TProcessorFunction) {
-	p.processorMap[key] = processor
+func NewCountResult_() *CountResult_ {
+	return &CountResult_{}
 }
 
-func (p *NodeProcessor) GetProcessorFunction(key string) (processor thrift.TProcessorFunction, ok bool) {
-	processor, ok = p.processorMap[key]
-	return processor, ok
+func (p *CountResult_) GetCount() int64 {
+	return p.Count
 }
 
-func (p *NodeProcessor) ProcessorMap() map[string]thrift.TProcessorFunction {
-	return p.processorMap
+func (p *CountResult_) GetSketch() []byte {
+	return p.Sketch
 }
+func (p *CountResult_) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
 
-func NewNodeProcessor(handler Node) *NodeProcessor {
+	var issetCount bool = false
+	var issetSketch bool = false
 
-	self99 := &NodeProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
-	self99.processorMap["query"] = &nodeProcessorQuery{handler: handler}
-	self99.processorMap["aggregate"] = &nodeProcessorAggregate{handler: handler}
-	self99.processorMap["fetch"] = &nodeProcessorFetch{handler: handler}
-	self99.processorMap["write"] = &nodeProcessorWrite{handler: handler}
-	self99.processorMap["writeTagged"] = &nodeProcessorWriteTagged{handler: handler}
-	self99.processorMap["aggregateRaw"] = &nodeProcessorAggregateRaw{handler: handler}
-	self99.processorMap["fetchBatchRaw"] = &nodeProcessorFetchBatchRaw{handler: handler}
-	self99.processorMap["fetchBatchRawV2"] = &nodeProcessorFetchBatchRawV2{handler: handler}
-	self99.processorMap["fetchBlocksRaw"] = &nodeProcessorFetchBlocksRaw{handler: handler}
-	self99.processorMap["fetchTagged"] = &nodeProcessorFetchTagged{handler: handler}
-	self99.processorMap["fetchBlocksMetadataRawV2"] = &nodeProcessorFetchBlocksMetadataRawV2{handler: handler}
-	self99.processorMap["writeBatchRaw"] = &nodeProcessorWriteBatchRaw{handler: handler}
-	self99.processorMap["writeBatchRawV2"] = &nodeProcessorWriteBatchRawV2{handler: handler}
-	self99.processorMap["writeTaggedBatchRaw"] = &nodeProcessorWriteTaggedBatchRaw{handler: handler}
-	self99.processorMap["writeTaggedBatchRawV2"] = &nodeProcessorWriteTaggedBatchRawV2{handler: handler}
-	self99.processorMap["repair"] = &nodeProcessorRepair{handler: handler}
-	self99.processorMap["truncate"] = &nodeProcessorTruncate{handler: handler}
-	self99.processorMap["aggregateTiles"] = &nodeProcessorAggregateTiles{handler: handler}
-	self99.processorMap["health"] = &nodeProcessorHealth{handler: handler}
-	self99.processorMap["bootstrapped"] = &nodeProcessorBootstrapped{handler: handler}
-	self99.processorMap["bootstrappedInPlacementOrNoPlacement"] = &nodeProcessorBootstrappedInPlacementOrNoPlacement{handler: handler}
-	self99.processorMap["getPersistRateLimit"] = &nodeProcessorGetPersistRateLimit{handler: handler}
-	self99.processorMap["setPersistRateLimit"] = &nodeProcessorSetPersistRateLimit{handler: handler}
-	self99.processorMap["getWriteNewSeriesAsync"] = &nodeProcessorGetWriteNewSeriesAsync{handler: handler}
-	self99.processorMap["setWriteNewSeriesAsync"] = &nodeProcessorSetWriteNewSeriesAsync{handler: handler}
-	self99.processorMap["getWriteNewSeriesBackoffDuration"] = &nodeProcessorGetWriteNewSeriesBackoffDuration{handler: handler}
-	self99.processorMap["setWriteNewSeriesBackoffDuration"] = &nodeProcessorSetWriteNewSeriesBackoffDuration{handler: handler}
-	self99.processorMap["getWriteNewSeriesLimitPerShardPerSecond"] = &nodeProcessorGetWriteNewSeriesLimitPerShardPerSecond{handler: handler}
-	self99.processorMap["setWriteNewSeriesLimitPerShardPerSecond"] = &nodeProcessorSetWriteNewSeriesLimitPerShardPerSecond{handler: handler}
-	self99.processorMap["debugProfileStart"] = &nodeProcessorDebugProfileStart{handler: handler}
-	self99.processorMap["debugProfileStop"] = &nodeProcessorDebugProfileStop{handler: handler}
-	self99.processorMap["debugIndexMemorySegments"] = &nodeProcessorDebugIndexMemorySegments{handler: handler}
-	return self99
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+			issetCount = true
+		case 2:
+			if err := p.ReadField2(iprot); err != nil {
+				return err
+			}
+			issetSketch = true
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetCount {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Count is not set"))
+	}
+	if !issetSketch {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Sketch is not set"))
+	}
+	return nil
 }
 
-func (p *NodeProcessor) Process(iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	name, _, seqId, err := iprot.ReadMessageBegin()
-	if err != nil {
-		return false, err
-	}
-	if processor, ok := p.GetProcessorFunction(name); ok {
-		return processor.Process(seqId, iprot, oprot)
+func (p *CountResult_) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Count = v
+	}
+	return nil
+}
+
+func (p *CountResult_) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBinary(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Sketch = v
+	}
+	return nil
+}
+
+func (p *CountResult_) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("CountResult"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *CountResult_) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("count", thrift.I64, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:count: ", p), err)
+	}
+	if err := oprot.WriteI64(int64(p.Count)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.count (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:count: ", p), err)
+	}
+	return err
+}
+
+func (p *CountResult_) writeField2(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("sketch", thrift.STRING, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:sketch: ", p), err)
+	}
+	if err := oprot.WriteBinary(p.Sketch); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.sketch (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:sketch: ", p), err)
+	}
+	return err
+}
+
+func (p *CountResult_) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("CountResult_(%+v)", *p)
+}
+
+type Node interface {
+	// Parameters:
+	//  - Req
+	Query(req *QueryRequest) (r *QueryResult_, err error)
+	// Parameters:
+	//  - Req
+	Aggregate(req *AggregateQueryRequest) (r *AggregateQueryResult_, err error)
+	// Parameters:
+	//  - Req
+	Fetch(req *FetchRequest) (r *FetchResult_, err error)
+	// Parameters:
+	//  - Req
+	Write(req *WriteRequest) (err error)
+	// Parameters:
+	//  - Req
+	WriteTagged(req *WriteTaggedRequest) (err error)
+	// Parameters:
+	//  - Req
+	AggregateRaw(req *AggregateQueryRawRequest) (r *AggregateQueryRawResult_, err error)
+	// Parameters:
+	//  - Req
+	FetchBatchRaw(req *FetchBatchRawRequest) (r *FetchBatchRawResult_, err error)
+	// Parameters:
+	//  - Req
+	FetchBatchRawV2(req *FetchBatchRawV2Request) (r *FetchBatchRawResult_, err error)
+	// Parameters:
+	//  - Req
+	FetchBlocksRaw(req *FetchBlocksRawRequest) (r *FetchBlocksRawResult_, err error)
+	// Parameters:
+	//  - Req
+	FetchTagged(req *FetchTaggedRequest) (r *FetchTaggedResult_, err error)
+	// Parameters:
+	//  - Req
+	FetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (r *FetchBlocksMetadataRawV2Result_, err error)
+	// Parameters:
+	//  - Req
+	WriteBatchRaw(req *WriteBatchRawRequest) (err error)
+	// Parameters:
+	//  - Req
+	WriteBatchRawV2(req *WriteBatchRawV2Request) (err error)
+	// Parameters:
+	//  - Req
+	WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error)
+	// Parameters:
+	//  - Req
+	WriteTaggedBatchRawV2(req *WriteTaggedBatchRawV2Request) (err error)
+	Repair() (err error)
+	// Parameters:
+	//  - Req
+	Truncate(req *TruncateRequest) (r *TruncateResult_, err error)
+	// Parameters:
+	//  - Req
+	AggregateTiles(req *AggregateTilesRequest) (r *AggregateTilesResult_, err error)
+	Health() (r *NodeHealthResult_, err error)
+	Bootstrapped() (r *NodeBootstrappedResult_, err error)
+	BootstrappedInPlacementOrNoPlacement() (r *NodeBootstrappedInPlacementOrNoPlacementResult_, err error)
+	GetPersistRateLimit() (r *NodePersistRateLimitResult_, err error)
+	// Parameters:
+	//  - Req
+	SetPersistRateLimit(req *NodeSetPersistRateLimitRequest) (r *NodePersistRateLimitResult_, err error)
+	GetWriteNewSeriesAsync() (r *NodeWriteNewSeriesAsyncResult_, err error)
+	// Parameters:
+	//  - Req
+	SetWriteNewSeriesAsync(req *NodeSetWriteNewSeriesAsyncRequest) (r *NodeWriteNewSeriesAsyncResult_, err error)
+	GetWriteNewSeriesBackoffDuration() (r *NodeWriteNewSeriesBackoffDurationResult_, err error)
+	// Parameters:
+	//  - Req
+	SetWriteNewSeriesBackoffDuration(req *NodeSetWriteNewSeriesBackoffDurationRequest) (r *NodeWriteNewSeriesBackoffDurationResult_, err error)
+	GetWriteNewSeriesLimitPerShardPerSecond() (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error)
+	// Parameters:
+	//  - Req
+	SetWriteNewSeriesLimitPerShardPerSecond(req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error)
+	// Parameters:
+	//  - Req
+	DebugProfileStart(req *DebugProfileStartRequest) (r *DebugProfileStartResult_, err error)
+	// Parameters:
+	//  - Req
+	DebugProfileStop(req *DebugProfileStopRequest) (r *DebugProfileStopResult_, err error)
+	// Parameters:
+	//  - Req
+	DebugIndexMemorySegments(req *DebugIndexMemorySegmentsRequest) (r *DebugIndexMemorySegmentsResult_, err error)
+	// Parameters:
+	//  - Req
+	WriteExemplars(req *WriteExemplarsRequest) (err error)
+	// Parameters:
+	//  - Req
+	FetchExemplars(req *FetchExemplarsRequest) (r *FetchExemplarsResult_, err error)
+	// Parameters:
+	//  - Req
+	DeleteTagged(req *DeleteTaggedRequest) (r *DeleteTaggedResult_, err error)
+	// Parameters:
+	//  - Req
+	Cardinality(req *CardinalityRequest) (r *CardinalityResult_, err error)
+	// Parameters:
+	//  - Req
+	Count(req *CountRequest) (r *CountResult_, err error)
+}
+
+type NodeClient struct {
+	Transport       thrift.TTransport
+	ProtocolFactory thrift.TProtocolFactory
+	InputProtocol   thrift.TProtocol
+	OutputProtocol  thrift.TProtocol
+	SeqId           int32
+}
+
+func NewNodeClientFactory(t thrift.TTransport, f thrift.TProtocolFactory) *NodeClient {
+	return &NodeClient{Transport: t,
+		ProtocolFactory: f,
+		InputProtocol:   f.GetProtocol(t),
+		OutputProtocol:  f.GetProtocol(t),
+		SeqId:           0,
+	}
+}
+
+func NewNodeClientProtocol(t thrift.TTransport, iprot thrift.TProtocol, oprot thrift.TProtocol) *NodeClient {
+	return &NodeClient{Transport: t,
+		ProtocolFactory: nil,
+		InputProtocol:   iprot,
+		OutputProtocol:  oprot,
+		SeqId:           0,
+	}
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Query(req *QueryRequest) (r *QueryResult_, err error) {
+	if err = p.sendQuery(req); err != nil {
+		return
+	}
+	return p.recvQuery()
+}
+
+func (p *NodeClient) sendQuery(req *QueryRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("query", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeQueryArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvQuery() (value *QueryResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "query" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "query failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "query failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error46 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error47 error
+		error47, err = error46.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error47
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "query failed: invalid message type")
+		return
+	}
+	result := NodeQueryResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Aggregate(req *AggregateQueryRequest) (r *AggregateQueryResult_, err error) {
+	if err = p.sendAggregate(req); err != nil {
+		return
+	}
+	return p.recvAggregate()
+}
+
+func (p *NodeClient) sendAggregate(req *AggregateQueryRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("aggregate", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeAggregateArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvAggregate() (value *AggregateQueryResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "aggregate" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "aggregate failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "aggregate failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error48 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error49 error
+		error49, err = error48.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error49
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "aggregate failed: invalid message type")
+		return
+	}
+	result := NodeAggregateResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Fetch(req *FetchRequest) (r *FetchResult_, err error) {
+	if err = p.sendFetch(req); err != nil {
+		return
+	}
+	return p.recvFetch()
+}
+
+func (p *NodeClient) sendFetch(req *FetchRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetch", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetch() (value *FetchResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetch" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetch failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetch failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error50 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error51 error
+		error51, err = error50.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error51
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetch failed: invalid message type")
+		return
+	}
+	result := NodeFetchResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Write(req *WriteRequest) (err error) {
+	if err = p.sendWrite(req); err != nil {
+		return
+	}
+	return p.recvWrite()
+}
+
+func (p *NodeClient) sendWrite(req *WriteRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("write", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWrite() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "write" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "write failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "write failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error52 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error53 error
+		error53, err = error52.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error53
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "write failed: invalid message type")
+		return
+	}
+	result := NodeWriteResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) WriteTagged(req *WriteTaggedRequest) (err error) {
+	if err = p.sendWriteTagged(req); err != nil {
+		return
+	}
+	return p.recvWriteTagged()
+}
+
+func (p *NodeClient) sendWriteTagged(req *WriteTaggedRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("writeTagged", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteTaggedArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWriteTagged() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "writeTagged" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeTagged failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeTagged failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error54 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error55 error
+		error55, err = error54.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error55
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeTagged failed: invalid message type")
+		return
+	}
+	result := NodeWriteTaggedResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) AggregateRaw(req *AggregateQueryRawRequest) (r *AggregateQueryRawResult_, err error) {
+	if err = p.sendAggregateRaw(req); err != nil {
+		return
+	}
+	return p.recvAggregateRaw()
+}
+
+func (p *NodeClient) sendAggregateRaw(req *AggregateQueryRawRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("aggregateRaw", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeAggregateRawArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvAggregateRaw() (value *AggregateQueryRawResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "aggregateRaw" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "aggregateRaw failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "aggregateRaw failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error56 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error57 error
+		error57, err = error56.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error57
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "aggregateRaw failed: invalid message type")
+		return
+	}
+	result := NodeAggregateRawResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) FetchBatchRaw(req *FetchBatchRawRequest) (r *FetchBatchRawResult_, err error) {
+	if err = p.sendFetchBatchRaw(req); err != nil {
+		return
+	}
+	return p.recvFetchBatchRaw()
+}
+
+func (p *NodeClient) sendFetchBatchRaw(req *FetchBatchRawRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetchBatchRaw", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchBatchRawArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetchBatchRaw() (value *FetchBatchRawResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetchBatchRaw" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBatchRaw failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBatchRaw failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error58 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error59 error
+		error59, err = error58.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error59
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBatchRaw failed: invalid message type")
+		return
+	}
+	result := NodeFetchBatchRawResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) FetchBatchRawV2(req *FetchBatchRawV2Request) (r *FetchBatchRawResult_, err error) {
+	if err = p.sendFetchBatchRawV2(req); err != nil {
+		return
+	}
+	return p.recvFetchBatchRawV2()
+}
+
+func (p *NodeClient) sendFetchBatchRawV2(req *FetchBatchRawV2Request) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetchBatchRawV2", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchBatchRawV2Args{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetchBatchRawV2() (value *FetchBatchRawResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetchBatchRawV2" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBatchRawV2 failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBatchRawV2 failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error60 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error61 error
+		error61, err = error60.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error61
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBatchRawV2 failed: invalid message type")
+		return
+	}
+	result := NodeFetchBatchRawV2Result{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) FetchBlocksRaw(req *FetchBlocksRawRequest) (r *FetchBlocksRawResult_, err error) {
+	if err = p.sendFetchBlocksRaw(req); err != nil {
+		return
+	}
+	return p.recvFetchBlocksRaw()
+}
+
+func (p *NodeClient) sendFetchBlocksRaw(req *FetchBlocksRawRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetchBlocksRaw", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchBlocksRawArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetchBlocksRaw() (value *FetchBlocksRawResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetchBlocksRaw" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBlocksRaw failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBlocksRaw failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error62 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error63 error
+		error63, err = error62.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error63
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBlocksRaw failed: invalid message type")
+		return
+	}
+	result := NodeFetchBlocksRawResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) FetchTagged(req *FetchTaggedRequest) (r *FetchTaggedResult_, err error) {
+	if err = p.sendFetchTagged(req); err != nil {
+		return
+	}
+	return p.recvFetchTagged()
+}
+
+func (p *NodeClient) sendFetchTagged(req *FetchTaggedRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetchTagged", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchTaggedArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetchTagged() (value *FetchTaggedResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetchTagged" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchTagged failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchTagged failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error64 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error65 error
+		error65, err = error64.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error65
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchTagged failed: invalid message type")
+		return
+	}
+	result := NodeFetchTaggedResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) FetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (r *FetchBlocksMetadataRawV2Result_, err error) {
+	if err = p.sendFetchBlocksMetadataRawV2(req); err != nil {
+		return
+	}
+	return p.recvFetchBlocksMetadataRawV2()
+}
+
+func (p *NodeClient) sendFetchBlocksMetadataRawV2(req *FetchBlocksMetadataRawV2Request) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchBlocksMetadataRawV2Args{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetchBlocksMetadataRawV2() (value *FetchBlocksMetadataRawV2Result_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetchBlocksMetadataRawV2" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchBlocksMetadataRawV2 failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchBlocksMetadataRawV2 failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error66 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error67 error
+		error67, err = error66.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error67
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchBlocksMetadataRawV2 failed: invalid message type")
+		return
+	}
+	result := NodeFetchBlocksMetadataRawV2Result{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) WriteBatchRaw(req *WriteBatchRawRequest) (err error) {
+	if err = p.sendWriteBatchRaw(req); err != nil {
+		return
+	}
+	return p.recvWriteBatchRaw()
+}
+
+func (p *NodeClient) sendWriteBatchRaw(req *WriteBatchRawRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("writeBatchRaw", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteBatchRawArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWriteBatchRaw() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "writeBatchRaw" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeBatchRaw failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeBatchRaw failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error68 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error69 error
+		error69, err = error68.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error69
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeBatchRaw failed: invalid message type")
+		return
+	}
+	result := NodeWriteBatchRawResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) WriteBatchRawV2(req *WriteBatchRawV2Request) (err error) {
+	if err = p.sendWriteBatchRawV2(req); err != nil {
+		return
+	}
+	return p.recvWriteBatchRawV2()
+}
+
+func (p *NodeClient) sendWriteBatchRawV2(req *WriteBatchRawV2Request) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("writeBatchRawV2", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteBatchRawV2Args{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWriteBatchRawV2() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "writeBatchRawV2" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeBatchRawV2 failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeBatchRawV2 failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error70 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error71 error
+		error71, err = error70.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error71
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeBatchRawV2 failed: invalid message type")
+		return
+	}
+	result := NodeWriteBatchRawV2Result{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) WriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error) {
+	if err = p.sendWriteTaggedBatchRaw(req); err != nil {
+		return
+	}
+	return p.recvWriteTaggedBatchRaw()
+}
+
+func (p *NodeClient) sendWriteTaggedBatchRaw(req *WriteTaggedBatchRawRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteTaggedBatchRawArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWriteTaggedBatchRaw() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "writeTaggedBatchRaw" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeTaggedBatchRaw failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeTaggedBatchRaw failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error72 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error73 error
+		error73, err = error72.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error73
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeTaggedBatchRaw failed: invalid message type")
+		return
+	}
+	result := NodeWriteTaggedBatchRawResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) WriteTaggedBatchRawV2(req *WriteTaggedBatchRawV2Request) (err error) {
+	if err = p.sendWriteTaggedBatchRawV2(req); err != nil {
+		return
+	}
+	return p.recvWriteTaggedBatchRawV2()
+}
+
+func (p *NodeClient) sendWriteTaggedBatchRawV2(req *WriteTaggedBatchRawV2Request) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteTaggedBatchRawV2Args{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWriteTaggedBatchRawV2() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "writeTaggedBatchRawV2" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeTaggedBatchRawV2 failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeTaggedBatchRawV2 failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error74 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error75 error
+		error75, err = error74.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error75
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeTaggedBatchRawV2 failed: invalid message type")
+		return
+	}
+	result := NodeWriteTaggedBatchRawV2Result{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+func (p *NodeClient) Repair() (err error) {
+	if err = p.sendRepair(); err != nil {
+		return
+	}
+	return p.recvRepair()
+}
+
+func (p *NodeClient) sendRepair() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("repair", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeRepairArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvRepair() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "repair" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "repair failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "repair failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error76 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error77 error
+		error77, err = error76.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error77
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "repair failed: invalid message type")
+		return
+	}
+	result := NodeRepairResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Truncate(req *TruncateRequest) (r *TruncateResult_, err error) {
+	if err = p.sendTruncate(req); err != nil {
+		return
+	}
+	return p.recvTruncate()
+}
+
+func (p *NodeClient) sendTruncate(req *TruncateRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("truncate", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeTruncateArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvTruncate() (value *TruncateResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "truncate" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "truncate failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "truncate failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error78 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error79 error
+		error79, err = error78.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error79
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "truncate failed: invalid message type")
+		return
+	}
+	result := NodeTruncateResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) AggregateTiles(req *AggregateTilesRequest) (r *AggregateTilesResult_, err error) {
+	if err = p.sendAggregateTiles(req); err != nil {
+		return
+	}
+	return p.recvAggregateTiles()
+}
+
+func (p *NodeClient) sendAggregateTiles(req *AggregateTilesRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("aggregateTiles", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeAggregateTilesArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvAggregateTiles() (value *AggregateTilesResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "aggregateTiles" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "aggregateTiles failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "aggregateTiles failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error80 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error81 error
+		error81, err = error80.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error81
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "aggregateTiles failed: invalid message type")
+		return
+	}
+	result := NodeAggregateTilesResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) Health() (r *NodeHealthResult_, err error) {
+	if err = p.sendHealth(); err != nil {
+		return
+	}
+	return p.recvHealth()
+}
+
+func (p *NodeClient) sendHealth() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("health", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeHealthArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvHealth() (value *NodeHealthResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "health" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "health failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "health failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error82 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error83 error
+		error83, err = error82.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error83
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "health failed: invalid message type")
+		return
+	}
+	result := NodeHealthResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) Bootstrapped() (r *NodeBootstrappedResult_, err error) {
+	if err = p.sendBootstrapped(); err != nil {
+		return
+	}
+	return p.recvBootstrapped()
+}
+
+func (p *NodeClient) sendBootstrapped() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("bootstrapped", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeBootstrappedArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvBootstrapped() (value *NodeBootstrappedResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "bootstrapped" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "bootstrapped failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "bootstrapped failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error84 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error85 error
+		error85, err = error84.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error85
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "bootstrapped failed: invalid message type")
+		return
+	}
+	result := NodeBootstrappedResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) BootstrappedInPlacementOrNoPlacement() (r *NodeBootstrappedInPlacementOrNoPlacementResult_, err error) {
+	if err = p.sendBootstrappedInPlacementOrNoPlacement(); err != nil {
+		return
+	}
+	return p.recvBootstrappedInPlacementOrNoPlacement()
+}
+
+func (p *NodeClient) sendBootstrappedInPlacementOrNoPlacement() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeBootstrappedInPlacementOrNoPlacementArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvBootstrappedInPlacementOrNoPlacement() (value *NodeBootstrappedInPlacementOrNoPlacementResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "bootstrappedInPlacementOrNoPlacement" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "bootstrappedInPlacementOrNoPlacement failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "bootstrappedInPlacementOrNoPlacement failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error86 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error87 error
+		error87, err = error86.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error87
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "bootstrappedInPlacementOrNoPlacement failed: invalid message type")
+		return
+	}
+	result := NodeBootstrappedInPlacementOrNoPlacementResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) GetPersistRateLimit() (r *NodePersistRateLimitResult_, err error) {
+	if err = p.sendGetPersistRateLimit(); err != nil {
+		return
+	}
+	return p.recvGetPersistRateLimit()
+}
+
+func (p *NodeClient) sendGetPersistRateLimit() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("getPersistRateLimit", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeGetPersistRateLimitArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvGetPersistRateLimit() (value *NodePersistRateLimitResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "getPersistRateLimit" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getPersistRateLimit failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getPersistRateLimit failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error88 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error89 error
+		error89, err = error88.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error89
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getPersistRateLimit failed: invalid message type")
+		return
+	}
+	result := NodeGetPersistRateLimitResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) SetPersistRateLimit(req *NodeSetPersistRateLimitRequest) (r *NodePersistRateLimitResult_, err error) {
+	if err = p.sendSetPersistRateLimit(req); err != nil {
+		return
+	}
+	return p.recvSetPersistRateLimit()
+}
+
+func (p *NodeClient) sendSetPersistRateLimit(req *NodeSetPersistRateLimitRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("setPersistRateLimit", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeSetPersistRateLimitArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvSetPersistRateLimit() (value *NodePersistRateLimitResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "setPersistRateLimit" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setPersistRateLimit failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setPersistRateLimit failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error90 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error91 error
+		error91, err = error90.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error91
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setPersistRateLimit failed: invalid message type")
+		return
+	}
+	result := NodeSetPersistRateLimitResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) GetWriteNewSeriesAsync() (r *NodeWriteNewSeriesAsyncResult_, err error) {
+	if err = p.sendGetWriteNewSeriesAsync(); err != nil {
+		return
+	}
+	return p.recvGetWriteNewSeriesAsync()
+}
+
+func (p *NodeClient) sendGetWriteNewSeriesAsync() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeGetWriteNewSeriesAsyncArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvGetWriteNewSeriesAsync() (value *NodeWriteNewSeriesAsyncResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "getWriteNewSeriesAsync" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getWriteNewSeriesAsync failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getWriteNewSeriesAsync failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error92 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error93 error
+		error93, err = error92.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error93
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getWriteNewSeriesAsync failed: invalid message type")
+		return
+	}
+	result := NodeGetWriteNewSeriesAsyncResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) SetWriteNewSeriesAsync(req *NodeSetWriteNewSeriesAsyncRequest) (r *NodeWriteNewSeriesAsyncResult_, err error) {
+	if err = p.sendSetWriteNewSeriesAsync(req); err != nil {
+		return
+	}
+	return p.recvSetWriteNewSeriesAsync()
+}
+
+func (p *NodeClient) sendSetWriteNewSeriesAsync(req *NodeSetWriteNewSeriesAsyncRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeSetWriteNewSeriesAsyncArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvSetWriteNewSeriesAsync() (value *NodeWriteNewSeriesAsyncResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "setWriteNewSeriesAsync" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setWriteNewSeriesAsync failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setWriteNewSeriesAsync failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error94 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error95 error
+		error95, err = error94.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error95
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setWriteNewSeriesAsync failed: invalid message type")
+		return
+	}
+	result := NodeSetWriteNewSeriesAsyncResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) GetWriteNewSeriesBackoffDuration() (r *NodeWriteNewSeriesBackoffDurationResult_, err error) {
+	if err = p.sendGetWriteNewSeriesBackoffDuration(); err != nil {
+		return
+	}
+	return p.recvGetWriteNewSeriesBackoffDuration()
+}
+
+func (p *NodeClient) sendGetWriteNewSeriesBackoffDuration() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeGetWriteNewSeriesBackoffDurationArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvGetWriteNewSeriesBackoffDuration() (value *NodeWriteNewSeriesBackoffDurationResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "getWriteNewSeriesBackoffDuration" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getWriteNewSeriesBackoffDuration failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getWriteNewSeriesBackoffDuration failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error96 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error97 error
+		error97, err = error96.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error97
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getWriteNewSeriesBackoffDuration failed: invalid message type")
+		return
+	}
+	result := NodeGetWriteNewSeriesBackoffDurationResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) SetWriteNewSeriesBackoffDuration(req *NodeSetWriteNewSeriesBackoffDurationRequest) (r *NodeWriteNewSeriesBackoffDurationResult_, err error) {
+	if err = p.sendSetWriteNewSeriesBackoffDuration(req); err != nil {
+		return
+	}
+	return p.recvSetWriteNewSeriesBackoffDuration()
+}
+
+func (p *NodeClient) sendSetWriteNewSeriesBackoffDuration(req *NodeSetWriteNewSeriesBackoffDurationRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeSetWriteNewSeriesBackoffDurationArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvSetWriteNewSeriesBackoffDuration() (value *NodeWriteNewSeriesBackoffDurationResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "setWriteNewSeriesBackoffDuration" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setWriteNewSeriesBackoffDuration failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setWriteNewSeriesBackoffDuration failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error98 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error99 error
+		error99, err = error98.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error99
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setWriteNewSeriesBackoffDuration failed: invalid message type")
+		return
+	}
+	result := NodeSetWriteNewSeriesBackoffDurationResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+func (p *NodeClient) GetWriteNewSeriesLimitPerShardPerSecond() (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
+	if err = p.sendGetWriteNewSeriesLimitPerShardPerSecond(); err != nil {
+		return
+	}
+	return p.recvGetWriteNewSeriesLimitPerShardPerSecond()
+}
+
+func (p *NodeClient) sendGetWriteNewSeriesLimitPerShardPerSecond() (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeGetWriteNewSeriesLimitPerShardPerSecondArgs{}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvGetWriteNewSeriesLimitPerShardPerSecond() (value *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "getWriteNewSeriesLimitPerShardPerSecond" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "getWriteNewSeriesLimitPerShardPerSecond failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "getWriteNewSeriesLimitPerShardPerSecond failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error100 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error101 error
+		error101, err = error100.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error101
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "getWriteNewSeriesLimitPerShardPerSecond failed: invalid message type")
+		return
+	}
+	result := NodeGetWriteNewSeriesLimitPerShardPerSecondResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) SetWriteNewSeriesLimitPerShardPerSecond(req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (r *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
+	if err = p.sendSetWriteNewSeriesLimitPerShardPerSecond(req); err != nil {
+		return
+	}
+	return p.recvSetWriteNewSeriesLimitPerShardPerSecond()
+}
+
+func (p *NodeClient) sendSetWriteNewSeriesLimitPerShardPerSecond(req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeSetWriteNewSeriesLimitPerShardPerSecondArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvSetWriteNewSeriesLimitPerShardPerSecond() (value *NodeWriteNewSeriesLimitPerShardPerSecondResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "setWriteNewSeriesLimitPerShardPerSecond" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "setWriteNewSeriesLimitPerShardPerSecond failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "setWriteNewSeriesLimitPerShardPerSecond failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error102 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error103 error
+		error103, err = error102.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error103
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "setWriteNewSeriesLimitPerShardPerSecond failed: invalid message type")
+		return
+	}
+	result := NodeSetWriteNewSeriesLimitPerShardPerSecondResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) DebugProfileStart(req *DebugProfileStartRequest) (r *DebugProfileStartResult_, err error) {
+	if err = p.sendDebugProfileStart(req); err != nil {
+		return
+	}
+	return p.recvDebugProfileStart()
+}
+
+func (p *NodeClient) sendDebugProfileStart(req *DebugProfileStartRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("debugProfileStart", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeDebugProfileStartArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvDebugProfileStart() (value *DebugProfileStartResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "debugProfileStart" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "debugProfileStart failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "debugProfileStart failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error104 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error105 error
+		error105, err = error104.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error105
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "debugProfileStart failed: invalid message type")
+		return
+	}
+	result := NodeDebugProfileStartResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) DebugProfileStop(req *DebugProfileStopRequest) (r *DebugProfileStopResult_, err error) {
+	if err = p.sendDebugProfileStop(req); err != nil {
+		return
+	}
+	return p.recvDebugProfileStop()
+}
+
+func (p *NodeClient) sendDebugProfileStop(req *DebugProfileStopRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("debugProfileStop", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeDebugProfileStopArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvDebugProfileStop() (value *DebugProfileStopResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "debugProfileStop" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "debugProfileStop failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "debugProfileStop failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error106 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error107 error
+		error107, err = error106.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error107
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "debugProfileStop failed: invalid message type")
+		return
+	}
+	result := NodeDebugProfileStopResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) DebugIndexMemorySegments(req *DebugIndexMemorySegmentsRequest) (r *DebugIndexMemorySegmentsResult_, err error) {
+	if err = p.sendDebugIndexMemorySegments(req); err != nil {
+		return
+	}
+	return p.recvDebugIndexMemorySegments()
+}
+
+func (p *NodeClient) sendDebugIndexMemorySegments(req *DebugIndexMemorySegmentsRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeDebugIndexMemorySegmentsArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvDebugIndexMemorySegments() (value *DebugIndexMemorySegmentsResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "debugIndexMemorySegments" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "debugIndexMemorySegments failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "debugIndexMemorySegments failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error108 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error109 error
+		error109, err = error108.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error109
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "debugIndexMemorySegments failed: invalid message type")
+		return
+	}
+	result := NodeDebugIndexMemorySegmentsResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) WriteExemplars(req *WriteExemplarsRequest) (err error) {
+	if err = p.sendWriteExemplars(req); err != nil {
+		return
+	}
+	return p.recvWriteExemplars()
+}
+
+func (p *NodeClient) sendWriteExemplars(req *WriteExemplarsRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("writeExemplars", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeWriteExemplarsArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvWriteExemplars() (err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "writeExemplars" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "writeExemplars failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "writeExemplars failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error110 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error111 error
+		error111, err = error110.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error111
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "writeExemplars failed: invalid message type")
+		return
+	}
+	result := NodeWriteExemplarsResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) FetchExemplars(req *FetchExemplarsRequest) (r *FetchExemplarsResult_, err error) {
+	if err = p.sendFetchExemplars(req); err != nil {
+		return
+	}
+	return p.recvFetchExemplars()
+}
+
+func (p *NodeClient) sendFetchExemplars(req *FetchExemplarsRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("fetchExemplars", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeFetchExemplarsArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvFetchExemplars() (value *FetchExemplarsResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "fetchExemplars" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "fetchExemplars failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "fetchExemplars failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error112 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error113 error
+		error113, err = error112.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error113
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "fetchExemplars failed: invalid message type")
+		return
+	}
+	result := NodeFetchExemplarsResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) DeleteTagged(req *DeleteTaggedRequest) (r *DeleteTaggedResult_, err error) {
+	if err = p.sendDeleteTagged(req); err != nil {
+		return
+	}
+	return p.recvDeleteTagged()
+}
+
+func (p *NodeClient) sendDeleteTagged(req *DeleteTaggedRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("deleteTagged", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeDeleteTaggedArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvDeleteTagged() (value *DeleteTaggedResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "deleteTagged" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "deleteTagged failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "deleteTagged failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error114 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error115 error
+		error115, err = error114.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error115
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "deleteTagged failed: invalid message type")
+		return
+	}
+	result := NodeDeleteTaggedResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Cardinality(req *CardinalityRequest) (r *CardinalityResult_, err error) {
+	if err = p.sendCardinality(req); err != nil {
+		return
+	}
+	return p.recvCardinality()
+}
+
+func (p *NodeClient) sendCardinality(req *CardinalityRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("cardinality", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeCardinalityArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvCardinality() (value *CardinalityResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "cardinality" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "cardinality failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "cardinality failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error116 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error117 error
+		error117, err = error116.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error117
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "cardinality failed: invalid message type")
+		return
+	}
+	result := NodeCardinalityResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+// Parameters:
+//  - Req
+func (p *NodeClient) Count(req *CountRequest) (r *CountResult_, err error) {
+	if err = p.sendCount(req); err != nil {
+		return
+	}
+	return p.recvCount()
+}
+
+func (p *NodeClient) sendCount(req *CountRequest) (err error) {
+	oprot := p.OutputProtocol
+	if oprot == nil {
+		oprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.OutputProtocol = oprot
+	}
+	p.SeqId++
+	if err = oprot.WriteMessageBegin("count", thrift.CALL, p.SeqId); err != nil {
+		return
+	}
+	args := NodeCountArgs{
+		Req: req,
+	}
+	if err = args.Write(oprot); err != nil {
+		return
+	}
+	if err = oprot.WriteMessageEnd(); err != nil {
+		return
+	}
+	return oprot.Flush()
+}
+
+func (p *NodeClient) recvCount() (value *CountResult_, err error) {
+	iprot := p.InputProtocol
+	if iprot == nil {
+		iprot = p.ProtocolFactory.GetProtocol(p.Transport)
+		p.InputProtocol = iprot
+	}
+	method, mTypeId, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return
+	}
+	if method != "count" {
+		err = thrift.NewTApplicationException(thrift.WRONG_METHOD_NAME, "count failed: wrong method name")
+		return
+	}
+	if p.SeqId != seqId {
+		err = thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "count failed: out of sequence response")
+		return
+	}
+	if mTypeId == thrift.EXCEPTION {
+		error118 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error119 error
+		error119, err = error118.Read(iprot)
+		if err != nil {
+			return
+		}
+		if err = iprot.ReadMessageEnd(); err != nil {
+			return
+		}
+		err = error119
+		return
+	}
+	if mTypeId != thrift.REPLY {
+		err = thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, "count failed: invalid message type")
+		return
+	}
+	result := NodeCountResult{}
+	if err = result.Read(iprot); err != nil {
+		return
+	}
+	if err = iprot.ReadMessageEnd(); err != nil {
+		return
+	}
+	if result.Err != nil {
+		err = result.Err
+		return
+	}
+	value = result.GetSuccess()
+	return
+}
+
+type NodeProcessor struct {
+	processorMap map[string]thrift.TProcessorFunction
+	handler      Node
+}
+
+func (p *NodeProcessor) AddToProcessorMap(key string, processor thrift.TProcessorFunction) {
+	p.processorMap[key] = processor
+}
+
+func (p *NodeProcessor) GetProcessorFunction(key string) (processor thrift.TProcessorFunction, ok bool) {
+	processor, ok = p.processorMap[key]
+	return processor, ok
+}
+
+func (p *NodeProcessor) ProcessorMap() map[string]thrift.TProcessorFunction {
+	return p.processorMap
+}
+
+func NewNodeProcessor(handler Node) *NodeProcessor {
+
+	self120 := &NodeProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
+	self120.processorMap["query"] = &nodeProcessorQuery{handler: handler}
+	self120.processorMap["aggregate"] = &nodeProcessorAggregate{handler: handler}
+	self120.processorMap["fetch"] = &nodeProcessorFetch{handler: handler}
+	self120.processorMap["write"] = &nodeProcessorWrite{handler: handler}
+	self120.processorMap["writeTagged"] = &nodeProcessorWriteTagged{handler: handler}
+	self120.processorMap["aggregateRaw"] = &nodeProcessorAggregateRaw{handler: handler}
+	self120.processorMap["fetchBatchRaw"] = &nodeProcessorFetchBatchRaw{handler: handler}
+	self120.processorMap["fetchBatchRawV2"] = &nodeProcessorFetchBatchRawV2{handler: handler}
+	self120.processorMap["fetchBlocksRaw"] = &nodeProcessorFetchBlocksRaw{handler: handler}
+	self120.processorMap["fetchTagged"] = &nodeProcessorFetchTagged{handler: handler}
+	self120.processorMap["fetchBlocksMetadataRawV2"] = &nodeProcessorFetchBlocksMetadataRawV2{handler: handler}
+	self120.processorMap["writeBatchRaw"] = &nodeProcessorWriteBatchRaw{handler: handler}
+	self120.processorMap["writeBatchRawV2"] = &nodeProcessorWriteBatchRawV2{handler: handler}
+	self120.processorMap["writeTaggedBatchRaw"] = &nodeProcessorWriteTaggedBatchRaw{handler: handler}
+	self120.processorMap["writeTaggedBatchRawV2"] = &nodeProcessorWriteTaggedBatchRawV2{handler: handler}
+	self120.processorMap["repair"] = &nodeProcessorRepair{handler: handler}
+	self120.processorMap["truncate"] = &nodeProcessorTruncate{handler: handler}
+	self120.processorMap["aggregateTiles"] = &nodeProcessorAggregateTiles{handler: handler}
+	self120.processorMap["health"] = &nodeProcessorHealth{handler: handler}
+	self120.processorMap["bootstrapped"] = &nodeProcessorBootstrapped{handler: handler}
+	self120.processorMap["bootstrappedInPlacementOrNoPlacement"] = &nodeProcessorBootstrappedInPlacementOrNoPlacement{handler: handler}
+	self120.processorMap["getPersistRateLimit"] = &nodeProcessorGetPersistRateLimit{handler: handler}
+	self120.processorMap["setPersistRateLimit"] = &nodeProcessorSetPersistRateLimit{handler: handler}
+	self120.processorMap["getWriteNewSeriesAsync"] = &nodeProcessorGetWriteNewSeriesAsync{handler: handler}
+	self120.processorMap["setWriteNewSeriesAsync"] = &nodeProcessorSetWriteNewSeriesAsync{handler: handler}
+	self120.processorMap["getWriteNewSeriesBackoffDuration"] = &nodeProcessorGetWriteNewSeriesBackoffDuration{handler: handler}
+	self120.processorMap["setWriteNewSeriesBackoffDuration"] = &nodeProcessorSetWriteNewSeriesBackoffDuration{handler: handler}
+	self120.processorMap["getWriteNewSeriesLimitPerShardPerSecond"] = &nodeProcessorGetWriteNewSeriesLimitPerShardPerSecond{handler: handler}
+	self120.processorMap["setWriteNewSeriesLimitPerShardPerSecond"] = &nodeProcessorSetWriteNewSeriesLimitPerShardPerSecond{handler: handler}
+	self120.processorMap["debugProfileStart"] = &nodeProcessorDebugProfileStart{handler: handler}
+	self120.processorMap["debugProfileStop"] = &nodeProcessorDebugProfileStop{handler: handler}
+	self120.processorMap["debugIndexMemorySegments"] = &nodeProcessorDebugIndexMemorySegments{handler: handler}
+	self120.processorMap["writeExemplars"] = &nodeProcessorWriteExemplars{handler: handler}
+	self120.processorMap["fetchExemplars"] = &nodeProcessorFetchExemplars{handler: handler}
+	self120.processorMap["deleteTagged"] = &nodeProcessorDeleteTagged{handler: handler}
+	self120.processorMap["cardinality"] = &nodeProcessorCardinality{handler: handler}
+	self120.processorMap["count"] = &nodeProcessorCount{handler: handler}
+	return self120
+}
+
+func (p *NodeProcessor) Process(iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	name, _, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return false, err
+	}
+	if processor, ok := p.GetProcessorFunction(name); ok {
+		return processor.Process(seqId, iprot, oprot)
 	}
 	iprot.Skip(thrift.STRUCT)
 	iprot.ReadMessageEnd()
-	x100 := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
-	oprot.WriteMessageBegin(name, thrift.EXCEPTION, seqId)
-	x100.Write(oprot)
-	oprot.WriteMessageEnd()
-	oprot.Flush()
-	return false, x100
+	x115 := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
+	oprot.WriteMessageBegin(name, thrift.EXCEPTION, seqId)
+	x115.Write(oprot)
+	oprot.WriteMessageEnd()
+	oprot.Flush()
+	return false, x115
+
+}
+
+type nodeProcessorQuery struct {
+	handler Node
+}
+
+func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeQueryArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("query", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeQueryResult{}
+	var retval *QueryResult_
+	var err2 error
+	if retval, err2 = p.handler.Query(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing query: "+err2.Error())
+			oprot.WriteMessageBegin("query", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("query", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorAggregate struct {
+	handler Node
+}
+
+func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeAggregateArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeAggregateResult{}
+	var retval *AggregateQueryResult_
+	var err2 error
+	if retval, err2 = p.handler.Aggregate(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregate: "+err2.Error())
+			oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("aggregate", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorFetch struct {
+	handler Node
+}
+
+func (p *nodeProcessorFetch) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("fetch", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeFetchResult{}
+	var retval *FetchResult_
+	var err2 error
+	if retval, err2 = p.handler.Fetch(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetch: "+err2.Error())
+			oprot.WriteMessageBegin("fetch", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("fetch", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorWrite struct {
+	handler Node
+}
+
+func (p *nodeProcessorWrite) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("write", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeWriteResult{}
+	var err2 error
+	if err2 = p.handler.Write(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing write: "+err2.Error())
+			oprot.WriteMessageBegin("write", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("write", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorWriteTagged struct {
+	handler Node
+}
+
+func (p *nodeProcessorWriteTagged) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteTaggedArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("writeTagged", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeWriteTaggedResult{}
+	var err2 error
+	if err2 = p.handler.WriteTagged(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeTagged: "+err2.Error())
+			oprot.WriteMessageBegin("writeTagged", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("writeTagged", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorAggregateRaw struct {
+	handler Node
+}
+
+func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeAggregateRawArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeAggregateRawResult{}
+	var retval *AggregateQueryRawResult_
+	var err2 error
+	if retval, err2 = p.handler.AggregateRaw(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregateRaw: "+err2.Error())
+			oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("aggregateRaw", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorFetchBatchRaw struct {
+	handler Node
+}
+
+func (p *nodeProcessorFetchBatchRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchBatchRawArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("fetchBatchRaw", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeFetchBatchRawResult{}
+	var retval *FetchBatchRawResult_
+	var err2 error
+	if retval, err2 = p.handler.FetchBatchRaw(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBatchRaw: "+err2.Error())
+			oprot.WriteMessageBegin("fetchBatchRaw", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("fetchBatchRaw", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorFetchBatchRawV2 struct {
+	handler Node
+}
+
+func (p *nodeProcessorFetchBatchRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchBatchRawV2Args{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("fetchBatchRawV2", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeFetchBatchRawV2Result{}
+	var retval *FetchBatchRawResult_
+	var err2 error
+	if retval, err2 = p.handler.FetchBatchRawV2(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBatchRawV2: "+err2.Error())
+			oprot.WriteMessageBegin("fetchBatchRawV2", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("fetchBatchRawV2", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorFetchBlocksRaw struct {
+	handler Node
+}
+
+func (p *nodeProcessorFetchBlocksRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchBlocksRawArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("fetchBlocksRaw", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeFetchBlocksRawResult{}
+	var retval *FetchBlocksRawResult_
+	var err2 error
+	if retval, err2 = p.handler.FetchBlocksRaw(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBlocksRaw: "+err2.Error())
+			oprot.WriteMessageBegin("fetchBlocksRaw", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("fetchBlocksRaw", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorFetchTagged struct {
+	handler Node
+}
+
+func (p *nodeProcessorFetchTagged) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchTaggedArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("fetchTagged", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeFetchTaggedResult{}
+	var retval *FetchTaggedResult_
+	var err2 error
+	if retval, err2 = p.handler.FetchTagged(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchTagged: "+err2.Error())
+			oprot.WriteMessageBegin("fetchTagged", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("fetchTagged", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorFetchBlocksMetadataRawV2 struct {
+	handler Node
+}
+
+func (p *nodeProcessorFetchBlocksMetadataRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchBlocksMetadataRawV2Args{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeFetchBlocksMetadataRawV2Result{}
+	var retval *FetchBlocksMetadataRawV2Result_
+	var err2 error
+	if retval, err2 = p.handler.FetchBlocksMetadataRawV2(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBlocksMetadataRawV2: "+err2.Error())
+			oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorWriteBatchRaw struct {
+	handler Node
+}
+
+func (p *nodeProcessorWriteBatchRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteBatchRawArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("writeBatchRaw", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeWriteBatchRawResult{}
+	var err2 error
+	if err2 = p.handler.WriteBatchRaw(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *WriteBatchRawErrors:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeBatchRaw: "+err2.Error())
+			oprot.WriteMessageBegin("writeBatchRaw", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("writeBatchRaw", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorWriteBatchRawV2 struct {
+	handler Node
+}
+
+func (p *nodeProcessorWriteBatchRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteBatchRawV2Args{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("writeBatchRawV2", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeWriteBatchRawV2Result{}
+	var err2 error
+	if err2 = p.handler.WriteBatchRawV2(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *WriteBatchRawErrors:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeBatchRawV2: "+err2.Error())
+			oprot.WriteMessageBegin("writeBatchRawV2", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("writeBatchRawV2", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorWriteTaggedBatchRaw struct {
+	handler Node
+}
+
+func (p *nodeProcessorWriteTaggedBatchRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteTaggedBatchRawArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeWriteTaggedBatchRawResult{}
+	var err2 error
+	if err2 = p.handler.WriteTaggedBatchRaw(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *WriteBatchRawErrors:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeTaggedBatchRaw: "+err2.Error())
+			oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorWriteTaggedBatchRawV2 struct {
+	handler Node
+}
+
+func (p *nodeProcessorWriteTaggedBatchRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteTaggedBatchRawV2Args{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeWriteTaggedBatchRawV2Result{}
+	var err2 error
+	if err2 = p.handler.WriteTaggedBatchRawV2(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *WriteBatchRawErrors:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeTaggedBatchRawV2: "+err2.Error())
+			oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorRepair struct {
+	handler Node
+}
+
+func (p *nodeProcessorRepair) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeRepairArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("repair", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeRepairResult{}
+	var err2 error
+	if err2 = p.handler.Repair(); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing repair: "+err2.Error())
+			oprot.WriteMessageBegin("repair", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	}
+	if err2 = oprot.WriteMessageBegin("repair", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorTruncate struct {
+	handler Node
+}
+
+func (p *nodeProcessorTruncate) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeTruncateArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("truncate", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeTruncateResult{}
+	var retval *TruncateResult_
+	var err2 error
+	if retval, err2 = p.handler.Truncate(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing truncate: "+err2.Error())
+			oprot.WriteMessageBegin("truncate", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("truncate", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorAggregateTiles struct {
+	handler Node
+}
+
+func (p *nodeProcessorAggregateTiles) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeAggregateTilesArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("aggregateTiles", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeAggregateTilesResult{}
+	var retval *AggregateTilesResult_
+	var err2 error
+	if retval, err2 = p.handler.AggregateTiles(args.Req); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregateTiles: "+err2.Error())
+			oprot.WriteMessageBegin("aggregateTiles", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("aggregateTiles", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorHealth struct {
+	handler Node
+}
+
+func (p *nodeProcessorHealth) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeHealthArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("health", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeHealthResult{}
+	var retval *NodeHealthResult_
+	var err2 error
+	if retval, err2 = p.handler.Health(); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing health: "+err2.Error())
+			oprot.WriteMessageBegin("health", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("health", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorBootstrapped struct {
+	handler Node
+}
+
+func (p *nodeProcessorBootstrapped) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeBootstrappedArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("bootstrapped", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeBootstrappedResult{}
+	var retval *NodeBootstrappedResult_
+	var err2 error
+	if retval, err2 = p.handler.Bootstrapped(); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing bootstrapped: "+err2.Error())
+			oprot.WriteMessageBegin("bootstrapped", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("bootstrapped", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorBootstrappedInPlacementOrNoPlacement struct {
+	handler Node
+}
+
+func (p *nodeProcessorBootstrappedInPlacementOrNoPlacement) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeBootstrappedInPlacementOrNoPlacementArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeBootstrappedInPlacementOrNoPlacementResult{}
+	var retval *NodeBootstrappedInPlacementOrNoPlacementResult_
+	var err2 error
+	if retval, err2 = p.handler.BootstrappedInPlacementOrNoPlacement(); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing bootstrappedInPlacementOrNoPlacement: "+err2.Error())
+			oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
 
+type nodeProcessorGetPersistRateLimit struct {
+	handler Node
 }
 
-type nodeProcessorQuery struct {
+func (p *nodeProcessorGetPersistRateLimit) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeGetPersistRateLimitArgs{}
+	if err = args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		oprot.WriteMessageBegin("getPersistRateLimit", thrift.EXCEPTION, seqId)
+		x.Write(oprot)
+		oprot.WriteMessageEnd()
+		oprot.Flush()
+		return false, err
+	}
+
+	iprot.ReadMessageEnd()
+	result := NodeGetPersistRateLimitResult{}
+	var retval *NodePersistRateLimitResult_
+	var err2 error
+	if retval, err2 = p.handler.GetPersistRateLimit(); err2 != nil {
+		switch v := err2.(type) {
+		case *Error:
+			result.Err = v
+		default:
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getPersistRateLimit: "+err2.Error())
+			oprot.WriteMessageBegin("getPersistRateLimit", thrift.EXCEPTION, seqId)
+			x.Write(oprot)
+			oprot.WriteMessageEnd()
+			oprot.Flush()
+			return true, err2
+		}
+	} else {
+		result.Success = retval
+	}
+	if err2 = oprot.WriteMessageBegin("getPersistRateLimit", thrift.REPLY, seqId); err2 != nil {
+		err = err2
+	}
+	if err2 = result.Write(oprot); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err2 = oprot.Flush(); err == nil && err2 != nil {
+		err = err2
+	}
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+type nodeProcessorSetPersistRateLimit struct {
 	handler Node
 }
 
-func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeQueryArgs{}
+func (p *nodeProcessorSetPersistRateLimit) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeSetPersistRateLimitArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("query", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("setPersistRateLimit", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -17751,16 +22012,16 @@ func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeQueryResult{}
-	var retval *QueryResult_
+	result := NodeSetPersistRateLimitResult{}
+	var retval *NodePersistRateLimitResult_
 	var err2 error
-	if retval, err2 = p.handler.Query(args.Req); err2 != nil {
+	if retval, err2 = p.handler.SetPersistRateLimit(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing query: "+err2.Error())
-			oprot.WriteMessageBegin("query", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setPersistRateLimit: "+err2.Error())
+			oprot.WriteMessageBegin("setPersistRateLimit", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -17769,7 +22030,7 @@ func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("query", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("setPersistRateLimit", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -17787,16 +22048,16 @@ func (p *nodeProcessorQuery) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	return true, err
 }
 
-type nodeProcessorAggregate struct {
+type nodeProcessorGetWriteNewSeriesAsync struct {
 	handler Node
 }
 
-func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeAggregateArgs{}
+func (p *nodeProcessorGetWriteNewSeriesAsync) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeGetWriteNewSeriesAsyncArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -17804,16 +22065,16 @@ func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProto
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeAggregateResult{}
-	var retval *AggregateQueryResult_
+	result := NodeGetWriteNewSeriesAsyncResult{}
+	var retval *NodeWriteNewSeriesAsyncResult_
 	var err2 error
-	if retval, err2 = p.handler.Aggregate(args.Req); err2 != nil {
+	if retval, err2 = p.handler.GetWriteNewSeriesAsync(); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregate: "+err2.Error())
-			oprot.WriteMessageBegin("aggregate", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getWriteNewSeriesAsync: "+err2.Error())
+			oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -17822,7 +22083,7 @@ func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProto
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("aggregate", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -17840,16 +22101,16 @@ func (p *nodeProcessorAggregate) Process(seqId int32, iprot, oprot thrift.TProto
 	return true, err
 }
 
-type nodeProcessorFetch struct {
+type nodeProcessorSetWriteNewSeriesAsync struct {
 	handler Node
 }
 
-func (p *nodeProcessorFetch) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeFetchArgs{}
+func (p *nodeProcessorSetWriteNewSeriesAsync) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeSetWriteNewSeriesAsyncArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("fetch", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -17857,16 +22118,16 @@ func (p *nodeProcessorFetch) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeFetchResult{}
-	var retval *FetchResult_
+	result := NodeSetWriteNewSeriesAsyncResult{}
+	var retval *NodeWriteNewSeriesAsyncResult_
 	var err2 error
-	if retval, err2 = p.handler.Fetch(args.Req); err2 != nil {
+	if retval, err2 = p.handler.SetWriteNewSeriesAsync(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetch: "+err2.Error())
-			oprot.WriteMessageBegin("fetch", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setWriteNewSeriesAsync: "+err2.Error())
+			oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -17875,7 +22136,7 @@ func (p *nodeProcessorFetch) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("fetch", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -17893,16 +22154,16 @@ func (p *nodeProcessorFetch) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	return true, err
 }
 
-type nodeProcessorWrite struct {
+type nodeProcessorGetWriteNewSeriesBackoffDuration struct {
 	handler Node
 }
 
-func (p *nodeProcessorWrite) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeWriteArgs{}
+func (p *nodeProcessorGetWriteNewSeriesBackoffDuration) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeGetWriteNewSeriesBackoffDurationArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("write", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -17910,22 +22171,25 @@ func (p *nodeProcessorWrite) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeWriteResult{}
+	result := NodeGetWriteNewSeriesBackoffDurationResult{}
+	var retval *NodeWriteNewSeriesBackoffDurationResult_
 	var err2 error
-	if err2 = p.handler.Write(args.Req); err2 != nil {
+	if retval, err2 = p.handler.GetWriteNewSeriesBackoffDuration(); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing write: "+err2.Error())
-			oprot.WriteMessageBegin("write", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getWriteNewSeriesBackoffDuration: "+err2.Error())
+			oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("write", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -17943,16 +22207,16 @@ func (p *nodeProcessorWrite) Process(seqId int32, iprot, oprot thrift.TProtocol)
 	return true, err
 }
 
-type nodeProcessorWriteTagged struct {
+type nodeProcessorSetWriteNewSeriesBackoffDuration struct {
 	handler Node
 }
 
-func (p *nodeProcessorWriteTagged) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeWriteTaggedArgs{}
+func (p *nodeProcessorSetWriteNewSeriesBackoffDuration) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeSetWriteNewSeriesBackoffDurationArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("writeTagged", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -17960,22 +22224,25 @@ func (p *nodeProcessorWriteTagged) Process(seqId int32, iprot, oprot thrift.TPro
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeWriteTaggedResult{}
+	result := NodeSetWriteNewSeriesBackoffDurationResult{}
+	var retval *NodeWriteNewSeriesBackoffDurationResult_
 	var err2 error
-	if err2 = p.handler.WriteTagged(args.Req); err2 != nil {
+	if retval, err2 = p.handler.SetWriteNewSeriesBackoffDuration(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeTagged: "+err2.Error())
-			oprot.WriteMessageBegin("writeTagged", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setWriteNewSeriesBackoffDuration: "+err2.Error())
+			oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("writeTagged", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -17993,16 +22260,16 @@ func (p *nodeProcessorWriteTagged) Process(seqId int32, iprot, oprot thrift.TPro
 	return true, err
 }
 
-type nodeProcessorAggregateRaw struct {
+type nodeProcessorGetWriteNewSeriesLimitPerShardPerSecond struct {
 	handler Node
 }
 
-func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeAggregateRawArgs{}
+func (p *nodeProcessorGetWriteNewSeriesLimitPerShardPerSecond) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeGetWriteNewSeriesLimitPerShardPerSecondArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18010,16 +22277,16 @@ func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TPr
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeAggregateRawResult{}
-	var retval *AggregateQueryRawResult_
+	result := NodeGetWriteNewSeriesLimitPerShardPerSecondResult{}
+	var retval *NodeWriteNewSeriesLimitPerShardPerSecondResult_
 	var err2 error
-	if retval, err2 = p.handler.AggregateRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.GetWriteNewSeriesLimitPerShardPerSecond(); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregateRaw: "+err2.Error())
-			oprot.WriteMessageBegin("aggregateRaw", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getWriteNewSeriesLimitPerShardPerSecond: "+err2.Error())
+			oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -18028,7 +22295,7 @@ func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TPr
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("aggregateRaw", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18046,16 +22313,16 @@ func (p *nodeProcessorAggregateRaw) Process(seqId int32, iprot, oprot thrift.TPr
 	return true, err
 }
 
-type nodeProcessorFetchBatchRaw struct {
+type nodeProcessorSetWriteNewSeriesLimitPerShardPerSecond struct {
 	handler Node
 }
 
-func (p *nodeProcessorFetchBatchRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeFetchBatchRawArgs{}
+func (p *nodeProcessorSetWriteNewSeriesLimitPerShardPerSecond) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeSetWriteNewSeriesLimitPerShardPerSecondArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("fetchBatchRaw", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18063,16 +22330,16 @@ func (p *nodeProcessorFetchBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeFetchBatchRawResult{}
-	var retval *FetchBatchRawResult_
+	result := NodeSetWriteNewSeriesLimitPerShardPerSecondResult{}
+	var retval *NodeWriteNewSeriesLimitPerShardPerSecondResult_
 	var err2 error
-	if retval, err2 = p.handler.FetchBatchRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.SetWriteNewSeriesLimitPerShardPerSecond(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBatchRaw: "+err2.Error())
-			oprot.WriteMessageBegin("fetchBatchRaw", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setWriteNewSeriesLimitPerShardPerSecond: "+err2.Error())
+			oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -18081,7 +22348,7 @@ func (p *nodeProcessorFetchBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("fetchBatchRaw", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18099,16 +22366,16 @@ func (p *nodeProcessorFetchBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 	return true, err
 }
 
-type nodeProcessorFetchBatchRawV2 struct {
+type nodeProcessorDebugProfileStart struct {
 	handler Node
 }
 
-func (p *nodeProcessorFetchBatchRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeFetchBatchRawV2Args{}
+func (p *nodeProcessorDebugProfileStart) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeDebugProfileStartArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("fetchBatchRawV2", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("debugProfileStart", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18116,16 +22383,16 @@ func (p *nodeProcessorFetchBatchRawV2) Process(seqId int32, iprot, oprot thrift.
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeFetchBatchRawV2Result{}
-	var retval *FetchBatchRawResult_
+	result := NodeDebugProfileStartResult{}
+	var retval *DebugProfileStartResult_
 	var err2 error
-	if retval, err2 = p.handler.FetchBatchRawV2(args.Req); err2 != nil {
+	if retval, err2 = p.handler.DebugProfileStart(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBatchRawV2: "+err2.Error())
-			oprot.WriteMessageBegin("fetchBatchRawV2", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing debugProfileStart: "+err2.Error())
+			oprot.WriteMessageBegin("debugProfileStart", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -18134,7 +22401,7 @@ func (p *nodeProcessorFetchBatchRawV2) Process(seqId int32, iprot, oprot thrift.
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("fetchBatchRawV2", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("debugProfileStart", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18152,16 +22419,16 @@ func (p *nodeProcessorFetchBatchRawV2) Process(seqId int32, iprot, oprot thrift.
 	return true, err
 }
 
-type nodeProcessorFetchBlocksRaw struct {
+type nodeProcessorDebugProfileStop struct {
 	handler Node
 }
 
-func (p *nodeProcessorFetchBlocksRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeFetchBlocksRawArgs{}
+func (p *nodeProcessorDebugProfileStop) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeDebugProfileStopArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("fetchBlocksRaw", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("debugProfileStop", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18169,16 +22436,16 @@ func (p *nodeProcessorFetchBlocksRaw) Process(seqId int32, iprot, oprot thrift.T
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeFetchBlocksRawResult{}
-	var retval *FetchBlocksRawResult_
+	result := NodeDebugProfileStopResult{}
+	var retval *DebugProfileStopResult_
 	var err2 error
-	if retval, err2 = p.handler.FetchBlocksRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.DebugProfileStop(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBlocksRaw: "+err2.Error())
-			oprot.WriteMessageBegin("fetchBlocksRaw", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing debugProfileStop: "+err2.Error())
+			oprot.WriteMessageBegin("debugProfileStop", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -18187,7 +22454,7 @@ func (p *nodeProcessorFetchBlocksRaw) Process(seqId int32, iprot, oprot thrift.T
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("fetchBlocksRaw", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("debugProfileStop", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18205,16 +22472,16 @@ func (p *nodeProcessorFetchBlocksRaw) Process(seqId int32, iprot, oprot thrift.T
 	return true, err
 }
 
-type nodeProcessorFetchTagged struct {
+type nodeProcessorDebugIndexMemorySegments struct {
 	handler Node
 }
 
-func (p *nodeProcessorFetchTagged) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeFetchTaggedArgs{}
+func (p *nodeProcessorDebugIndexMemorySegments) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeDebugIndexMemorySegmentsArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("fetchTagged", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18222,16 +22489,16 @@ func (p *nodeProcessorFetchTagged) Process(seqId int32, iprot, oprot thrift.TPro
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeFetchTaggedResult{}
-	var retval *FetchTaggedResult_
+	result := NodeDebugIndexMemorySegmentsResult{}
+	var retval *DebugIndexMemorySegmentsResult_
 	var err2 error
-	if retval, err2 = p.handler.FetchTagged(args.Req); err2 != nil {
+	if retval, err2 = p.handler.DebugIndexMemorySegments(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchTagged: "+err2.Error())
-			oprot.WriteMessageBegin("fetchTagged", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing debugIndexMemorySegments: "+err2.Error())
+			oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
@@ -18240,7 +22507,7 @@ func (p *nodeProcessorFetchTagged) Process(seqId int32, iprot, oprot thrift.TPro
 	} else {
 		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("fetchTagged", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18258,16 +22525,16 @@ func (p *nodeProcessorFetchTagged) Process(seqId int32, iprot, oprot thrift.TPro
 	return true, err
 }
 
-type nodeProcessorFetchBlocksMetadataRawV2 struct {
+type nodeProcessorWriteExemplars struct {
 	handler Node
 }
 
-func (p *nodeProcessorFetchBlocksMetadataRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeFetchBlocksMetadataRawV2Args{}
+func (p *nodeProcessorWriteExemplars) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeWriteExemplarsArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("writeExemplars", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18275,25 +22542,22 @@ func (p *nodeProcessorFetchBlocksMetadataRawV2) Process(seqId int32, iprot, opro
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeFetchBlocksMetadataRawV2Result{}
-	var retval *FetchBlocksMetadataRawV2Result_
+	result := NodeWriteExemplarsResult{}
 	var err2 error
-	if retval, err2 = p.handler.FetchBlocksMetadataRawV2(args.Req); err2 != nil {
+	if err2 = p.handler.WriteExemplars(args.Req); err2 != nil {
 		switch v := err2.(type) {
 		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchBlocksMetadataRawV2: "+err2.Error())
-			oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeExemplars: "+err2.Error())
+			oprot.WriteMessageBegin("writeExemplars", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("fetchBlocksMetadataRawV2", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("writeExemplars", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18311,16 +22575,16 @@ func (p *nodeProcessorFetchBlocksMetadataRawV2) Process(seqId int32, iprot, opro
 	return true, err
 }
 
-type nodeProcessorWriteBatchRaw struct {
+type nodeProcessorFetchExemplars struct {
 	handler Node
 }
 
-func (p *nodeProcessorWriteBatchRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeWriteBatchRawArgs{}
+func (p *nodeProcessorFetchExemplars) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeFetchExemplarsArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("writeBatchRaw", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("fetchExemplars", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18328,22 +22592,25 @@ func (p *nodeProcessorWriteBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeWriteBatchRawResult{}
+	result := NodeFetchExemplarsResult{}
+	var retval *FetchExemplarsResult_
 	var err2 error
-	if err2 = p.handler.WriteBatchRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.FetchExemplars(args.Req); err2 != nil {
 		switch v := err2.(type) {
-		case *WriteBatchRawErrors:
+		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeBatchRaw: "+err2.Error())
-			oprot.WriteMessageBegin("writeBatchRaw", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing fetchExemplars: "+err2.Error())
+			oprot.WriteMessageBegin("fetchExemplars", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("writeBatchRaw", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("fetchExemplars", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18361,16 +22628,16 @@ func (p *nodeProcessorWriteBatchRaw) Process(seqId int32, iprot, oprot thrift.TP
 	return true, err
 }
 
-type nodeProcessorWriteBatchRawV2 struct {
+type nodeProcessorDeleteTagged struct {
 	handler Node
 }
 
-func (p *nodeProcessorWriteBatchRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeWriteBatchRawV2Args{}
+func (p *nodeProcessorDeleteTagged) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeDeleteTaggedArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("writeBatchRawV2", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("deleteTagged", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18378,22 +22645,25 @@ func (p *nodeProcessorWriteBatchRawV2) Process(seqId int32, iprot, oprot thrift.
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeWriteBatchRawV2Result{}
+	result := NodeDeleteTaggedResult{}
+	var retval *DeleteTaggedResult_
 	var err2 error
-	if err2 = p.handler.WriteBatchRawV2(args.Req); err2 != nil {
+	if retval, err2 = p.handler.DeleteTagged(args.Req); err2 != nil {
 		switch v := err2.(type) {
-		case *WriteBatchRawErrors:
+		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeBatchRawV2: "+err2.Error())
-			oprot.WriteMessageBegin("writeBatchRawV2", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing deleteTagged: "+err2.Error())
+			oprot.WriteMessageBegin("deleteTagged", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("writeBatchRawV2", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("deleteTagged", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18411,16 +22681,16 @@ func (p *nodeProcessorWriteBatchRawV2) Process(seqId int32, iprot, oprot thrift.
 	return true, err
 }
 
-type nodeProcessorWriteTaggedBatchRaw struct {
+type nodeProcessorCardinality struct {
 	handler Node
 }
 
-func (p *nodeProcessorWriteTaggedBatchRaw) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeWriteTaggedBatchRawArgs{}
+func (p *nodeProcessorCardinality) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeCardinalityArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("cardinality", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18428,22 +22698,25 @@ func (p *nodeProcessorWriteTaggedBatchRaw) Process(seqId int32, iprot, oprot thr
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeWriteTaggedBatchRawResult{}
+	result := NodeCardinalityResult{}
+	var retval *CardinalityResult_
 	var err2 error
-	if err2 = p.handler.WriteTaggedBatchRaw(args.Req); err2 != nil {
+	if retval, err2 = p.handler.Cardinality(args.Req); err2 != nil {
 		switch v := err2.(type) {
-		case *WriteBatchRawErrors:
+		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeTaggedBatchRaw: "+err2.Error())
-			oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing cardinality: "+err2.Error())
+			oprot.WriteMessageBegin("cardinality", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("writeTaggedBatchRaw", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("cardinality", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18461,16 +22734,16 @@ func (p *nodeProcessorWriteTaggedBatchRaw) Process(seqId int32, iprot, oprot thr
 	return true, err
 }
 
-type nodeProcessorWriteTaggedBatchRawV2 struct {
+type nodeProcessorCount struct {
 	handler Node
 }
 
-func (p *nodeProcessorWriteTaggedBatchRawV2) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeWriteTaggedBatchRawV2Args{}
+func (p *nodeProcessorCount) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	args := NodeCountArgs{}
 	if err = args.Read(iprot); err != nil {
 		iprot.ReadMessageEnd()
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin("count", thrift.EXCEPTION, seqId)
 		x.Write(oprot)
 		oprot.WriteMessageEnd()
 		oprot.Flush()
@@ -18478,22 +22751,25 @@ func (p *nodeProcessorWriteTaggedBatchRawV2) Process(seqId int32, iprot, oprot t
 	}
 
 	iprot.ReadMessageEnd()
-	result := NodeWriteTaggedBatchRawV2Result{}
+	result := NodeCountResult{}
+	var retval *CountResult_
 	var err2 error
-	if err2 = p.handler.WriteTaggedBatchRawV2(args.Req); err2 != nil {
+	if retval, err2 = p.handler.Count(args.Req); err2 != nil {
 		switch v := err2.(type) {
-		case *WriteBatchRawErrors:
+		case *Error:
 			result.Err = v
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing writeTaggedBatchRawV2: "+err2.Error())
-			oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.EXCEPTION, seqId)
+			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing count: "+err2.Error())
+			oprot.WriteMessageBegin("count", thrift.EXCEPTION, seqId)
 			x.Write(oprot)
 			oprot.WriteMessageEnd()
 			oprot.Flush()
 			return true, err2
 		}
+	} else {
+		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("writeTaggedBatchRawV2", thrift.REPLY, seqId); err2 != nil {
+	if err2 = oprot.WriteMessageBegin("count", thrift.REPLY, seqId); err2 != nil {
 		err = err2
 	}
 	if err2 = result.Write(oprot); err == nil && err2 != nil {
@@ -18505,935 +22781,1214 @@ func (p *nodeProcessorWriteTaggedBatchRawV2) Process(seqId int32, iprot, oprot t
 	if err2 = oprot.Flush(); err == nil && err2 != nil {
 		err = err2
 	}
-	if err != nil {
-		return
+	if err != nil {
+		return
+	}
+	return true, err
+}
+
+// HELPER FUNCTIONS AND STRUCTURES
+
+// Attributes:
+//  - Req
+type NodeQueryArgs struct {
+	Req *QueryRequest `thrift:"req,1" db:"req" json:"req"`
+}
+
+func NewNodeQueryArgs() *NodeQueryArgs {
+	return &NodeQueryArgs{}
+}
+
+var NodeQueryArgs_Req_DEFAULT *QueryRequest
+
+func (p *NodeQueryArgs) GetReq() *QueryRequest {
+	if !p.IsSetReq() {
+		return NodeQueryArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeQueryArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeQueryArgs) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *NodeQueryArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &QueryRequest{
+		RangeType: 0,
+
+		ResultTimeType: 0,
+	}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeQueryArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("query_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *NodeQueryArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeQueryArgs) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("NodeQueryArgs(%+v)", *p)
+}
+
+// Attributes:
+//  - Success
+//  - Err
+type NodeQueryResult struct {
+	Success *QueryResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error        `thrift:"err,1" db:"err" json:"err,omitempty"`
+}
+
+func NewNodeQueryResult() *NodeQueryResult {
+	return &NodeQueryResult{}
+}
+
+var NodeQueryResult_Success_DEFAULT *QueryResult_
+
+func (p *NodeQueryResult) GetSuccess() *QueryResult_ {
+	if !p.IsSetSuccess() {
+		return NodeQueryResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeQueryResult_Err_DEFAULT *Error
+
+func (p *NodeQueryResult) GetErr() *Error {
+	if !p.IsSetErr() {
+		return NodeQueryResult_Err_DEFAULT
+	}
+	return p.Err
+}
+func (p *NodeQueryResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeQueryResult) IsSetErr() bool {
+	return p.Err != nil
+}
+
+func (p *NodeQueryResult) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorRepair struct {
-	handler Node
+func (p *NodeQueryResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &QueryResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
 }
 
-func (p *nodeProcessorRepair) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeRepairArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("repair", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeQueryResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
+	if err := p.Err.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
+	return nil
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeRepairResult{}
-	var err2 error
-	if err2 = p.handler.Repair(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing repair: "+err2.Error())
-			oprot.WriteMessageBegin("repair", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+func (p *NodeQueryResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("query_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
 	}
-	if err2 = oprot.WriteMessageBegin("repair", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeQueryResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	return err
+}
+
+func (p *NodeQueryResult) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetErr() {
+		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
+		}
+		if err := p.Err.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Err), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:err: ", p), err)
+		}
 	}
-	if err != nil {
-		return
+	return err
+}
+
+func (p *NodeQueryResult) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return true, err
+	return fmt.Sprintf("NodeQueryResult(%+v)", *p)
 }
 
-type nodeProcessorTruncate struct {
-	handler Node
+// Attributes:
+//  - Req
+type NodeAggregateArgs struct {
+	Req *AggregateQueryRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func (p *nodeProcessorTruncate) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeTruncateArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("truncate", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func NewNodeAggregateArgs() *NodeAggregateArgs {
+	return &NodeAggregateArgs{}
+}
+
+var NodeAggregateArgs_Req_DEFAULT *AggregateQueryRequest
+
+func (p *NodeAggregateArgs) GetReq() *AggregateQueryRequest {
+	if !p.IsSetReq() {
+		return NodeAggregateArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeAggregateArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeAggregateArgs) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	iprot.ReadMessageEnd()
-	result := NodeTruncateResult{}
-	var retval *TruncateResult_
-	var err2 error
-	if retval, err2 = p.handler.Truncate(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing truncate: "+err2.Error())
-			oprot.WriteMessageBegin("truncate", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("truncate", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
 	}
-	if err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorAggregateTiles struct {
-	handler Node
-}
+func (p *NodeAggregateArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &AggregateQueryRequest{
+		AggregateQueryType: 1,
 
-func (p *nodeProcessorAggregateTiles) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeAggregateTilesArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("aggregateTiles", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+		RangeType: 0,
+	}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
+	return nil
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeAggregateTilesResult{}
-	var retval *AggregateTilesResult_
-	var err2 error
-	if retval, err2 = p.handler.AggregateTiles(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing aggregateTiles: "+err2.Error())
-			oprot.WriteMessageBegin("aggregateTiles", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+func (p *NodeAggregateArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("aggregate_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("aggregateTiles", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeAggregateArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
 	}
-	if err != nil {
-		return
+	return err
+}
+
+func (p *NodeAggregateArgs) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return true, err
+	return fmt.Sprintf("NodeAggregateArgs(%+v)", *p)
 }
 
-type nodeProcessorHealth struct {
-	handler Node
+// Attributes:
+//  - Success
+//  - Err
+type NodeAggregateResult struct {
+	Success *AggregateQueryResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func (p *nodeProcessorHealth) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeHealthArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("health", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
-	}
+func NewNodeAggregateResult() *NodeAggregateResult {
+	return &NodeAggregateResult{}
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeHealthResult{}
-	var retval *NodeHealthResult_
-	var err2 error
-	if retval, err2 = p.handler.Health(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing health: "+err2.Error())
-			oprot.WriteMessageBegin("health", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
-		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("health", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+var NodeAggregateResult_Success_DEFAULT *AggregateQueryResult_
+
+func (p *NodeAggregateResult) GetSuccess() *AggregateQueryResult_ {
+	if !p.IsSetSuccess() {
+		return NodeAggregateResult_Success_DEFAULT
 	}
-	if err != nil {
-		return
+	return p.Success
+}
+
+var NodeAggregateResult_Err_DEFAULT *Error
+
+func (p *NodeAggregateResult) GetErr() *Error {
+	if !p.IsSetErr() {
+		return NodeAggregateResult_Err_DEFAULT
 	}
-	return true, err
+	return p.Err
+}
+func (p *NodeAggregateResult) IsSetSuccess() bool {
+	return p.Success != nil
 }
 
-type nodeProcessorBootstrapped struct {
-	handler Node
+func (p *NodeAggregateResult) IsSetErr() bool {
+	return p.Err != nil
 }
 
-func (p *nodeProcessorBootstrapped) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeBootstrappedArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("bootstrapped", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeAggregateResult) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	iprot.ReadMessageEnd()
-	result := NodeBootstrappedResult{}
-	var retval *NodeBootstrappedResult_
-	var err2 error
-	if retval, err2 = p.handler.Bootstrapped(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing bootstrapped: "+err2.Error())
-			oprot.WriteMessageBegin("bootstrapped", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("bootstrapped", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
 	}
-	if err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorBootstrappedInPlacementOrNoPlacement struct {
-	handler Node
+func (p *NodeAggregateResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &AggregateQueryResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
 }
 
-func (p *nodeProcessorBootstrappedInPlacementOrNoPlacement) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeBootstrappedInPlacementOrNoPlacementArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeAggregateResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
+	if err := p.Err.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
+	return nil
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeBootstrappedInPlacementOrNoPlacementResult{}
-	var retval *NodeBootstrappedInPlacementOrNoPlacementResult_
-	var err2 error
-	if retval, err2 = p.handler.BootstrappedInPlacementOrNoPlacement(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing bootstrappedInPlacementOrNoPlacement: "+err2.Error())
-			oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+func (p *NodeAggregateResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("aggregate_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("bootstrappedInPlacementOrNoPlacement", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeAggregateResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	return err
+}
+
+func (p *NodeAggregateResult) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetErr() {
+		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
+		}
+		if err := p.Err.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Err), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:err: ", p), err)
+		}
 	}
-	if err != nil {
-		return
+	return err
+}
+
+func (p *NodeAggregateResult) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return true, err
+	return fmt.Sprintf("NodeAggregateResult(%+v)", *p)
 }
 
-type nodeProcessorGetPersistRateLimit struct {
-	handler Node
+// Attributes:
+//  - Req
+type NodeFetchArgs struct {
+	Req *FetchRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func (p *nodeProcessorGetPersistRateLimit) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeGetPersistRateLimitArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("getPersistRateLimit", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func NewNodeFetchArgs() *NodeFetchArgs {
+	return &NodeFetchArgs{}
+}
+
+var NodeFetchArgs_Req_DEFAULT *FetchRequest
+
+func (p *NodeFetchArgs) GetReq() *FetchRequest {
+	if !p.IsSetReq() {
+		return NodeFetchArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeFetchArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeFetchArgs) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	iprot.ReadMessageEnd()
-	result := NodeGetPersistRateLimitResult{}
-	var retval *NodePersistRateLimitResult_
-	var err2 error
-	if retval, err2 = p.handler.GetPersistRateLimit(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getPersistRateLimit: "+err2.Error())
-			oprot.WriteMessageBegin("getPersistRateLimit", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("getPersistRateLimit", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
 	}
-	if err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorSetPersistRateLimit struct {
-	handler Node
-}
+func (p *NodeFetchArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchRequest{
+		RangeType: 0,
 
-func (p *nodeProcessorSetPersistRateLimit) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeSetPersistRateLimitArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("setPersistRateLimit", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+		ResultTimeType: 0,
+	}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
+	return nil
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeSetPersistRateLimitResult{}
-	var retval *NodePersistRateLimitResult_
-	var err2 error
-	if retval, err2 = p.handler.SetPersistRateLimit(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setPersistRateLimit: "+err2.Error())
-			oprot.WriteMessageBegin("setPersistRateLimit", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+func (p *NodeFetchArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetch_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("setPersistRateLimit", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeFetchArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
 	}
-	if err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
 	}
-	return true, err
+	return err
 }
 
-type nodeProcessorGetWriteNewSeriesAsync struct {
-	handler Node
+func (p *NodeFetchArgs) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("NodeFetchArgs(%+v)", *p)
 }
 
-func (p *nodeProcessorGetWriteNewSeriesAsync) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeGetWriteNewSeriesAsyncArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
-	}
+// Attributes:
+//  - Success
+//  - Err
+type NodeFetchResult struct {
+	Success *FetchResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error        `thrift:"err,1" db:"err" json:"err,omitempty"`
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeGetWriteNewSeriesAsyncResult{}
-	var retval *NodeWriteNewSeriesAsyncResult_
-	var err2 error
-	if retval, err2 = p.handler.GetWriteNewSeriesAsync(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getWriteNewSeriesAsync: "+err2.Error())
-			oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
-		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("getWriteNewSeriesAsync", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+func NewNodeFetchResult() *NodeFetchResult {
+	return &NodeFetchResult{}
+}
+
+var NodeFetchResult_Success_DEFAULT *FetchResult_
+
+func (p *NodeFetchResult) GetSuccess() *FetchResult_ {
+	if !p.IsSetSuccess() {
+		return NodeFetchResult_Success_DEFAULT
 	}
-	if err != nil {
-		return
+	return p.Success
+}
+
+var NodeFetchResult_Err_DEFAULT *Error
+
+func (p *NodeFetchResult) GetErr() *Error {
+	if !p.IsSetErr() {
+		return NodeFetchResult_Err_DEFAULT
 	}
-	return true, err
+	return p.Err
+}
+func (p *NodeFetchResult) IsSetSuccess() bool {
+	return p.Success != nil
 }
 
-type nodeProcessorSetWriteNewSeriesAsync struct {
-	handler Node
+func (p *NodeFetchResult) IsSetErr() bool {
+	return p.Err != nil
 }
 
-func (p *nodeProcessorSetWriteNewSeriesAsync) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeSetWriteNewSeriesAsyncArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeFetchResult) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	iprot.ReadMessageEnd()
-	result := NodeSetWriteNewSeriesAsyncResult{}
-	var retval *NodeWriteNewSeriesAsyncResult_
-	var err2 error
-	if retval, err2 = p.handler.SetWriteNewSeriesAsync(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setWriteNewSeriesAsync: "+err2.Error())
-			oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("setWriteNewSeriesAsync", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
 	}
-	if err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorGetWriteNewSeriesBackoffDuration struct {
-	handler Node
+func (p *NodeFetchResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
 }
 
-func (p *nodeProcessorGetWriteNewSeriesBackoffDuration) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeGetWriteNewSeriesBackoffDurationArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeFetchResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
+	if err := p.Err.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
+	return nil
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeGetWriteNewSeriesBackoffDurationResult{}
-	var retval *NodeWriteNewSeriesBackoffDurationResult_
-	var err2 error
-	if retval, err2 = p.handler.GetWriteNewSeriesBackoffDuration(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getWriteNewSeriesBackoffDuration: "+err2.Error())
-			oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+func (p *NodeFetchResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetch_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("getWriteNewSeriesBackoffDuration", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeFetchResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	return err
+}
+
+func (p *NodeFetchResult) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetErr() {
+		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
+		}
+		if err := p.Err.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Err), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:err: ", p), err)
+		}
 	}
-	if err != nil {
-		return
+	return err
+}
+
+func (p *NodeFetchResult) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return true, err
+	return fmt.Sprintf("NodeFetchResult(%+v)", *p)
 }
 
-type nodeProcessorSetWriteNewSeriesBackoffDuration struct {
-	handler Node
+// Attributes:
+//  - Req
+type NodeWriteArgs struct {
+	Req *WriteRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func (p *nodeProcessorSetWriteNewSeriesBackoffDuration) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeSetWriteNewSeriesBackoffDurationArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func NewNodeWriteArgs() *NodeWriteArgs {
+	return &NodeWriteArgs{}
+}
+
+var NodeWriteArgs_Req_DEFAULT *WriteRequest
+
+func (p *NodeWriteArgs) GetReq() *WriteRequest {
+	if !p.IsSetReq() {
+		return NodeWriteArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeWriteArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeWriteArgs) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	iprot.ReadMessageEnd()
-	result := NodeSetWriteNewSeriesBackoffDurationResult{}
-	var retval *NodeWriteNewSeriesBackoffDurationResult_
-	var err2 error
-	if retval, err2 = p.handler.SetWriteNewSeriesBackoffDuration(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setWriteNewSeriesBackoffDuration: "+err2.Error())
-			oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *NodeWriteArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteRequest{}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeWriteArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("write_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("setWriteNewSeriesBackoffDuration", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeWriteArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
 	}
-	if err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
 	}
-	return true, err
+	return err
 }
 
-type nodeProcessorGetWriteNewSeriesLimitPerShardPerSecond struct {
-	handler Node
+func (p *NodeWriteArgs) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("NodeWriteArgs(%+v)", *p)
 }
 
-func (p *nodeProcessorGetWriteNewSeriesLimitPerShardPerSecond) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeGetWriteNewSeriesLimitPerShardPerSecondArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+// Attributes:
+//  - Err
+type NodeWriteResult struct {
+	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
+}
+
+func NewNodeWriteResult() *NodeWriteResult {
+	return &NodeWriteResult{}
+}
+
+var NodeWriteResult_Err_DEFAULT *Error
+
+func (p *NodeWriteResult) GetErr() *Error {
+	if !p.IsSetErr() {
+		return NodeWriteResult_Err_DEFAULT
+	}
+	return p.Err
+}
+func (p *NodeWriteResult) IsSetErr() bool {
+	return p.Err != nil
+}
+
+func (p *NodeWriteResult) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	iprot.ReadMessageEnd()
-	result := NodeGetWriteNewSeriesLimitPerShardPerSecondResult{}
-	var retval *NodeWriteNewSeriesLimitPerShardPerSecondResult_
-	var err2 error
-	if retval, err2 = p.handler.GetWriteNewSeriesLimitPerShardPerSecond(); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getWriteNewSeriesLimitPerShardPerSecond: "+err2.Error())
-			oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("getWriteNewSeriesLimitPerShardPerSecond", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeWriteResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	if err := p.Err.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeWriteResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("write_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-	if err != nil {
-		return
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
-	return true, err
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
 }
 
-type nodeProcessorSetWriteNewSeriesLimitPerShardPerSecond struct {
-	handler Node
+func (p *NodeWriteResult) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetErr() {
+		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
+		}
+		if err := p.Err.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Err), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:err: ", p), err)
+		}
+	}
+	return err
 }
 
-func (p *nodeProcessorSetWriteNewSeriesLimitPerShardPerSecond) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeSetWriteNewSeriesLimitPerShardPerSecondArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeWriteResult) String() string {
+	if p == nil {
+		return "<nil>"
 	}
+	return fmt.Sprintf("NodeWriteResult(%+v)", *p)
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeSetWriteNewSeriesLimitPerShardPerSecondResult{}
-	var retval *NodeWriteNewSeriesLimitPerShardPerSecondResult_
-	var err2 error
-	if retval, err2 = p.handler.SetWriteNewSeriesLimitPerShardPerSecond(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing setWriteNewSeriesLimitPerShardPerSecond: "+err2.Error())
-			oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
-		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("setWriteNewSeriesLimitPerShardPerSecond", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+// Attributes:
+//  - Req
+type NodeWriteTaggedArgs struct {
+	Req *WriteTaggedRequest `thrift:"req,1" db:"req" json:"req"`
+}
+
+func NewNodeWriteTaggedArgs() *NodeWriteTaggedArgs {
+	return &NodeWriteTaggedArgs{}
+}
+
+var NodeWriteTaggedArgs_Req_DEFAULT *WriteTaggedRequest
+
+func (p *NodeWriteTaggedArgs) GetReq() *WriteTaggedRequest {
+	if !p.IsSetReq() {
+		return NodeWriteTaggedArgs_Req_DEFAULT
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return p.Req
+}
+func (p *NodeWriteTaggedArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeWriteTaggedArgs) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
 	}
-	if err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorDebugProfileStart struct {
-	handler Node
+func (p *NodeWriteTaggedArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteTaggedRequest{}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
 }
 
-func (p *nodeProcessorDebugProfileStart) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeDebugProfileStartArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("debugProfileStart", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeWriteTaggedArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeTagged_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-
-	iprot.ReadMessageEnd()
-	result := NodeDebugProfileStartResult{}
-	var retval *DebugProfileStartResult_
-	var err2 error
-	if retval, err2 = p.handler.DebugProfileStart(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing debugProfileStart: "+err2.Error())
-			oprot.WriteMessageBegin("debugProfileStart", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
 	}
-	if err2 = oprot.WriteMessageBegin("debugProfileStart", thrift.REPLY, seqId); err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeWriteTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
 	}
-	if err != nil {
-		return
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
 	}
-	return true, err
+	return err
 }
 
-type nodeProcessorDebugProfileStop struct {
-	handler Node
+func (p *NodeWriteTaggedArgs) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("NodeWriteTaggedArgs(%+v)", *p)
 }
 
-func (p *nodeProcessorDebugProfileStop) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeDebugProfileStopArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("debugProfileStop", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+// Attributes:
+//  - Err
+type NodeWriteTaggedResult struct {
+	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
+}
+
+func NewNodeWriteTaggedResult() *NodeWriteTaggedResult {
+	return &NodeWriteTaggedResult{}
+}
+
+var NodeWriteTaggedResult_Err_DEFAULT *Error
+
+func (p *NodeWriteTaggedResult) GetErr() *Error {
+	if !p.IsSetErr() {
+		return NodeWriteTaggedResult_Err_DEFAULT
 	}
+	return p.Err
+}
+func (p *NodeWriteTaggedResult) IsSetErr() bool {
+	return p.Err != nil
+}
 
-	iprot.ReadMessageEnd()
-	result := NodeDebugProfileStopResult{}
-	var retval *DebugProfileStopResult_
-	var err2 error
-	if retval, err2 = p.handler.DebugProfileStop(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
+func (p *NodeWriteTaggedResult) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
 		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing debugProfileStop: "+err2.Error())
-			oprot.WriteMessageBegin("debugProfileStop", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("debugProfileStop", thrift.REPLY, seqId); err2 != nil {
-		err = err2
-	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
-	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
 	}
-	if err != nil {
-		return
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	return true, err
+	return nil
 }
 
-type nodeProcessorDebugIndexMemorySegments struct {
-	handler Node
+func (p *NodeWriteTaggedResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
+	if err := p.Err.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
+	}
+	return nil
 }
 
-func (p *nodeProcessorDebugIndexMemorySegments) Process(seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	args := NodeDebugIndexMemorySegmentsArgs{}
-	if err = args.Read(iprot); err != nil {
-		iprot.ReadMessageEnd()
-		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
-		oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.EXCEPTION, seqId)
-		x.Write(oprot)
-		oprot.WriteMessageEnd()
-		oprot.Flush()
-		return false, err
+func (p *NodeWriteTaggedResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeTagged_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
-
-	iprot.ReadMessageEnd()
-	result := NodeDebugIndexMemorySegmentsResult{}
-	var retval *DebugIndexMemorySegmentsResult_
-	var err2 error
-	if retval, err2 = p.handler.DebugIndexMemorySegments(args.Req); err2 != nil {
-		switch v := err2.(type) {
-		case *Error:
-			result.Err = v
-		default:
-			x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing debugIndexMemorySegments: "+err2.Error())
-			oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.EXCEPTION, seqId)
-			x.Write(oprot)
-			oprot.WriteMessageEnd()
-			oprot.Flush()
-			return true, err2
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
 		}
-	} else {
-		result.Success = retval
-	}
-	if err2 = oprot.WriteMessageBegin("debugIndexMemorySegments", thrift.REPLY, seqId); err2 != nil {
-		err = err2
 	}
-	if err2 = result.Write(oprot); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err2 = oprot.WriteMessageEnd(); err == nil && err2 != nil {
-		err = err2
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	if err2 = oprot.Flush(); err == nil && err2 != nil {
-		err = err2
+	return nil
+}
+
+func (p *NodeWriteTaggedResult) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetErr() {
+		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
+		}
+		if err := p.Err.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Err), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:err: ", p), err)
+		}
 	}
-	if err != nil {
-		return
+	return err
+}
+
+func (p *NodeWriteTaggedResult) String() string {
+	if p == nil {
+		return "<nil>"
 	}
-	return true, err
+	return fmt.Sprintf("NodeWriteTaggedResult(%+v)", *p)
 }
 
-// HELPER FUNCTIONS AND STRUCTURES
-
 // Attributes:
 //  - Req
-type NodeQueryArgs struct {
-	Req *QueryRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeAggregateRawArgs struct {
+	Req *AggregateQueryRawRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeQueryArgs() *NodeQueryArgs {
-	return &NodeQueryArgs{}
+func NewNodeAggregateRawArgs() *NodeAggregateRawArgs {
+	return &NodeAggregateRawArgs{}
 }
 
-var NodeQueryArgs_Req_DEFAULT *QueryRequest
+var NodeAggregateRawArgs_Req_DEFAULT *AggregateQueryRawRequest
 
-func (p *NodeQueryArgs) GetReq() *QueryRequest {
+func (p *NodeAggregateRawArgs) GetReq() *AggregateQueryRawRequest {
 	if !p.IsSetReq() {
-		return NodeQueryArgs_Req_DEFAULT
+		return NodeAggregateRawArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeQueryArgs) IsSetReq() bool {
+func (p *NodeAggregateRawArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeQueryArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeAggregateRawArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -19466,11 +24021,11 @@ func (p *NodeQueryArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeQueryArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &QueryRequest{
-		RangeType: 0,
+func (p *NodeAggregateRawArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &AggregateQueryRawRequest{
+		AggregateQueryType: 1,
 
-		ResultTimeType: 0,
+		RangeType: 0,
 	}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
@@ -19478,8 +24033,8 @@ func (p *NodeQueryArgs) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeQueryArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("query_args"); err != nil {
+func (p *NodeAggregateRawArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("aggregateRaw_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -19496,7 +24051,7 @@ func (p *NodeQueryArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeQueryArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeAggregateRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -19509,51 +24064,51 @@ func (p *NodeQueryArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeQueryArgs) String() string {
+func (p *NodeAggregateRawArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeQueryArgs(%+v)", *p)
+	return fmt.Sprintf("NodeAggregateRawArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeQueryResult struct {
-	Success *QueryResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error        `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeAggregateRawResult struct {
+	Success *AggregateQueryRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                    `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeQueryResult() *NodeQueryResult {
-	return &NodeQueryResult{}
+func NewNodeAggregateRawResult() *NodeAggregateRawResult {
+	return &NodeAggregateRawResult{}
 }
 
-var NodeQueryResult_Success_DEFAULT *QueryResult_
+var NodeAggregateRawResult_Success_DEFAULT *AggregateQueryRawResult_
 
-func (p *NodeQueryResult) GetSuccess() *QueryResult_ {
+func (p *NodeAggregateRawResult) GetSuccess() *AggregateQueryRawResult_ {
 	if !p.IsSetSuccess() {
-		return NodeQueryResult_Success_DEFAULT
+		return NodeAggregateRawResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeQueryResult_Err_DEFAULT *Error
+var NodeAggregateRawResult_Err_DEFAULT *Error
 
-func (p *NodeQueryResult) GetErr() *Error {
+func (p *NodeAggregateRawResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeQueryResult_Err_DEFAULT
+		return NodeAggregateRawResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeQueryResult) IsSetSuccess() bool {
+func (p *NodeAggregateRawResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeQueryResult) IsSetErr() bool {
+func (p *NodeAggregateRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeQueryResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeAggregateRawResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -19590,15 +24145,15 @@ func (p *NodeQueryResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeQueryResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &QueryResult_{}
+func (p *NodeAggregateRawResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &AggregateQueryRawResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeQueryResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeAggregateRawResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -19608,8 +24163,8 @@ func (p *NodeQueryResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeQueryResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("query_result"); err != nil {
+func (p *NodeAggregateRawResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("aggregateRaw_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -19629,7 +24184,7 @@ func (p *NodeQueryResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeQueryResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeAggregateRawResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -19644,7 +24199,7 @@ func (p *NodeQueryResult) writeField0(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeQueryResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeAggregateRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -19659,36 +24214,36 @@ func (p *NodeQueryResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeQueryResult) String() string {
+func (p *NodeAggregateRawResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeQueryResult(%+v)", *p)
+	return fmt.Sprintf("NodeAggregateRawResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeAggregateArgs struct {
-	Req *AggregateQueryRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeFetchBatchRawArgs struct {
+	Req *FetchBatchRawRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeAggregateArgs() *NodeAggregateArgs {
-	return &NodeAggregateArgs{}
+func NewNodeFetchBatchRawArgs() *NodeFetchBatchRawArgs {
+	return &NodeFetchBatchRawArgs{}
 }
 
-var NodeAggregateArgs_Req_DEFAULT *AggregateQueryRequest
+var NodeFetchBatchRawArgs_Req_DEFAULT *FetchBatchRawRequest
 
-func (p *NodeAggregateArgs) GetReq() *AggregateQueryRequest {
+func (p *NodeFetchBatchRawArgs) GetReq() *FetchBatchRawRequest {
 	if !p.IsSetReq() {
-		return NodeAggregateArgs_Req_DEFAULT
+		return NodeFetchBatchRawArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeAggregateArgs) IsSetReq() bool {
+func (p *NodeFetchBatchRawArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeAggregateArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBatchRawArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -19721,11 +24276,9 @@ func (p *NodeAggregateArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &AggregateQueryRequest{
-		AggregateQueryType: 1,
-
-		RangeType: 0,
+func (p *NodeFetchBatchRawArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchBatchRawRequest{
+		RangeTimeType: 0,
 	}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
@@ -19733,8 +24286,8 @@ func (p *NodeAggregateArgs) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("aggregate_args"); err != nil {
+func (p *NodeFetchBatchRawArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBatchRaw_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -19751,7 +24304,7 @@ func (p *NodeAggregateArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -19764,51 +24317,51 @@ func (p *NodeAggregateArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeAggregateArgs) String() string {
+func (p *NodeFetchBatchRawArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeAggregateArgs(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBatchRawArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeAggregateResult struct {
-	Success *AggregateQueryResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeFetchBatchRawResult struct {
+	Success *FetchBatchRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeAggregateResult() *NodeAggregateResult {
-	return &NodeAggregateResult{}
+func NewNodeFetchBatchRawResult() *NodeFetchBatchRawResult {
+	return &NodeFetchBatchRawResult{}
 }
 
-var NodeAggregateResult_Success_DEFAULT *AggregateQueryResult_
+var NodeFetchBatchRawResult_Success_DEFAULT *FetchBatchRawResult_
 
-func (p *NodeAggregateResult) GetSuccess() *AggregateQueryResult_ {
+func (p *NodeFetchBatchRawResult) GetSuccess() *FetchBatchRawResult_ {
 	if !p.IsSetSuccess() {
-		return NodeAggregateResult_Success_DEFAULT
+		return NodeFetchBatchRawResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeAggregateResult_Err_DEFAULT *Error
+var NodeFetchBatchRawResult_Err_DEFAULT *Error
 
-func (p *NodeAggregateResult) GetErr() *Error {
+func (p *NodeFetchBatchRawResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeAggregateResult_Err_DEFAULT
+		return NodeFetchBatchRawResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeAggregateResult) IsSetSuccess() bool {
+func (p *NodeFetchBatchRawResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeAggregateResult) IsSetErr() bool {
+func (p *NodeFetchBatchRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeAggregateResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBatchRawResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -19845,15 +24398,15 @@ func (p *NodeAggregateResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &AggregateQueryResult_{}
+func (p *NodeFetchBatchRawResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchBatchRawResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeAggregateResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeFetchBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -19863,8 +24416,8 @@ func (p *NodeAggregateResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("aggregate_result"); err != nil {
+func (p *NodeFetchBatchRawResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBatchRaw_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -19884,7 +24437,7 @@ func (p *NodeAggregateResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBatchRawResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -19899,7 +24452,7 @@ func (p *NodeAggregateResult) writeField0(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeAggregateResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -19914,36 +24467,36 @@ func (p *NodeAggregateResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeAggregateResult) String() string {
+func (p *NodeFetchBatchRawResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeAggregateResult(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBatchRawResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeFetchArgs struct {
-	Req *FetchRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeFetchBatchRawV2Args struct {
+	Req *FetchBatchRawV2Request `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeFetchArgs() *NodeFetchArgs {
-	return &NodeFetchArgs{}
+func NewNodeFetchBatchRawV2Args() *NodeFetchBatchRawV2Args {
+	return &NodeFetchBatchRawV2Args{}
 }
 
-var NodeFetchArgs_Req_DEFAULT *FetchRequest
+var NodeFetchBatchRawV2Args_Req_DEFAULT *FetchBatchRawV2Request
 
-func (p *NodeFetchArgs) GetReq() *FetchRequest {
+func (p *NodeFetchBatchRawV2Args) GetReq() *FetchBatchRawV2Request {
 	if !p.IsSetReq() {
-		return NodeFetchArgs_Req_DEFAULT
+		return NodeFetchBatchRawV2Args_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeFetchArgs) IsSetReq() bool {
+func (p *NodeFetchBatchRawV2Args) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeFetchArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -19976,20 +24529,16 @@ func (p *NodeFetchArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &FetchRequest{
-		RangeType: 0,
-
-		ResultTimeType: 0,
-	}
+func (p *NodeFetchBatchRawV2Args) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchBatchRawV2Request{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetch_args"); err != nil {
+func (p *NodeFetchBatchRawV2Args) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBatchRawV2_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20006,7 +24555,7 @@ func (p *NodeFetchArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -20019,51 +24568,51 @@ func (p *NodeFetchArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeFetchArgs) String() string {
+func (p *NodeFetchBatchRawV2Args) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchArgs(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBatchRawV2Args(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeFetchResult struct {
-	Success *FetchResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error        `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeFetchBatchRawV2Result struct {
+	Success *FetchBatchRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeFetchResult() *NodeFetchResult {
-	return &NodeFetchResult{}
+func NewNodeFetchBatchRawV2Result() *NodeFetchBatchRawV2Result {
+	return &NodeFetchBatchRawV2Result{}
 }
 
-var NodeFetchResult_Success_DEFAULT *FetchResult_
+var NodeFetchBatchRawV2Result_Success_DEFAULT *FetchBatchRawResult_
 
-func (p *NodeFetchResult) GetSuccess() *FetchResult_ {
+func (p *NodeFetchBatchRawV2Result) GetSuccess() *FetchBatchRawResult_ {
 	if !p.IsSetSuccess() {
-		return NodeFetchResult_Success_DEFAULT
+		return NodeFetchBatchRawV2Result_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeFetchResult_Err_DEFAULT *Error
+var NodeFetchBatchRawV2Result_Err_DEFAULT *Error
 
-func (p *NodeFetchResult) GetErr() *Error {
+func (p *NodeFetchBatchRawV2Result) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeFetchResult_Err_DEFAULT
+		return NodeFetchBatchRawV2Result_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeFetchResult) IsSetSuccess() bool {
+func (p *NodeFetchBatchRawV2Result) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeFetchResult) IsSetErr() bool {
+func (p *NodeFetchBatchRawV2Result) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeFetchResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20100,15 +24649,15 @@ func (p *NodeFetchResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &FetchResult_{}
+func (p *NodeFetchBatchRawV2Result) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchBatchRawResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeFetchBatchRawV2Result) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -20118,8 +24667,8 @@ func (p *NodeFetchResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetch_result"); err != nil {
+func (p *NodeFetchBatchRawV2Result) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBatchRawV2_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20139,7 +24688,7 @@ func (p *NodeFetchResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBatchRawV2Result) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -20154,7 +24703,7 @@ func (p *NodeFetchResult) writeField0(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeFetchResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -20169,36 +24718,36 @@ func (p *NodeFetchResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeFetchResult) String() string {
+func (p *NodeFetchBatchRawV2Result) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchResult(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBatchRawV2Result(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeWriteArgs struct {
-	Req *WriteRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeFetchBlocksRawArgs struct {
+	Req *FetchBlocksRawRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeWriteArgs() *NodeWriteArgs {
-	return &NodeWriteArgs{}
+func NewNodeFetchBlocksRawArgs() *NodeFetchBlocksRawArgs {
+	return &NodeFetchBlocksRawArgs{}
 }
 
-var NodeWriteArgs_Req_DEFAULT *WriteRequest
+var NodeFetchBlocksRawArgs_Req_DEFAULT *FetchBlocksRawRequest
 
-func (p *NodeWriteArgs) GetReq() *WriteRequest {
+func (p *NodeFetchBlocksRawArgs) GetReq() *FetchBlocksRawRequest {
 	if !p.IsSetReq() {
-		return NodeWriteArgs_Req_DEFAULT
+		return NodeFetchBlocksRawArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeWriteArgs) IsSetReq() bool {
+func (p *NodeFetchBlocksRawArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeWriteArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBlocksRawArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20231,16 +24780,16 @@ func (p *NodeWriteArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &WriteRequest{}
+func (p *NodeFetchBlocksRawArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchBlocksRawRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("write_args"); err != nil {
+func (p *NodeFetchBlocksRawArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBlocksRaw_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20257,7 +24806,7 @@ func (p *NodeWriteArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBlocksRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -20270,36 +24819,51 @@ func (p *NodeWriteArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeWriteArgs) String() string {
+func (p *NodeFetchBlocksRawArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteArgs(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBlocksRawArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeWriteResult struct {
-	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeFetchBlocksRawResult struct {
+	Success *FetchBlocksRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeWriteResult() *NodeWriteResult {
-	return &NodeWriteResult{}
+func NewNodeFetchBlocksRawResult() *NodeFetchBlocksRawResult {
+	return &NodeFetchBlocksRawResult{}
 }
 
-var NodeWriteResult_Err_DEFAULT *Error
+var NodeFetchBlocksRawResult_Success_DEFAULT *FetchBlocksRawResult_
 
-func (p *NodeWriteResult) GetErr() *Error {
+func (p *NodeFetchBlocksRawResult) GetSuccess() *FetchBlocksRawResult_ {
+	if !p.IsSetSuccess() {
+		return NodeFetchBlocksRawResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeFetchBlocksRawResult_Err_DEFAULT *Error
+
+func (p *NodeFetchBlocksRawResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeWriteResult_Err_DEFAULT
+		return NodeFetchBlocksRawResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeWriteResult) IsSetErr() bool {
+func (p *NodeFetchBlocksRawResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeFetchBlocksRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeWriteResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBlocksRawResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20313,6 +24877,10 @@ func (p *NodeWriteResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -20332,7 +24900,15 @@ func (p *NodeWriteResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeFetchBlocksRawResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchBlocksRawResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeFetchBlocksRawResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -20342,11 +24918,14 @@ func (p *NodeWriteResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("write_result"); err != nil {
+func (p *NodeFetchBlocksRawResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBlocksRaw_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -20360,7 +24939,22 @@ func (p *NodeWriteResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBlocksRawResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeFetchBlocksRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -20375,36 +24969,36 @@ func (p *NodeWriteResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeWriteResult) String() string {
+func (p *NodeFetchBlocksRawResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteResult(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBlocksRawResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeWriteTaggedArgs struct {
-	Req *WriteTaggedRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeFetchTaggedArgs struct {
+	Req *FetchTaggedRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeWriteTaggedArgs() *NodeWriteTaggedArgs {
-	return &NodeWriteTaggedArgs{}
+func NewNodeFetchTaggedArgs() *NodeFetchTaggedArgs {
+	return &NodeFetchTaggedArgs{}
 }
 
-var NodeWriteTaggedArgs_Req_DEFAULT *WriteTaggedRequest
+var NodeFetchTaggedArgs_Req_DEFAULT *FetchTaggedRequest
 
-func (p *NodeWriteTaggedArgs) GetReq() *WriteTaggedRequest {
+func (p *NodeFetchTaggedArgs) GetReq() *FetchTaggedRequest {
 	if !p.IsSetReq() {
-		return NodeWriteTaggedArgs_Req_DEFAULT
+		return NodeFetchTaggedArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeWriteTaggedArgs) IsSetReq() bool {
+func (p *NodeFetchTaggedArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeWriteTaggedArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchTaggedArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20437,16 +25031,20 @@ func (p *NodeWriteTaggedArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &WriteTaggedRequest{}
+func (p *NodeFetchTaggedArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchTaggedRequest{
+		RangeTimeType: 0,
+
+		RequireExhaustive: true,
+	}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteTaggedArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeTagged_args"); err != nil {
+func (p *NodeFetchTaggedArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchTagged_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20463,7 +25061,7 @@ func (p *NodeWriteTaggedArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -20476,36 +25074,51 @@ func (p *NodeWriteTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeWriteTaggedArgs) String() string {
+func (p *NodeFetchTaggedArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteTaggedArgs(%+v)", *p)
+	return fmt.Sprintf("NodeFetchTaggedArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeWriteTaggedResult struct {
-	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeFetchTaggedResult struct {
+	Success *FetchTaggedResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error              `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeWriteTaggedResult() *NodeWriteTaggedResult {
-	return &NodeWriteTaggedResult{}
+func NewNodeFetchTaggedResult() *NodeFetchTaggedResult {
+	return &NodeFetchTaggedResult{}
 }
 
-var NodeWriteTaggedResult_Err_DEFAULT *Error
+var NodeFetchTaggedResult_Success_DEFAULT *FetchTaggedResult_
 
-func (p *NodeWriteTaggedResult) GetErr() *Error {
+func (p *NodeFetchTaggedResult) GetSuccess() *FetchTaggedResult_ {
+	if !p.IsSetSuccess() {
+		return NodeFetchTaggedResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeFetchTaggedResult_Err_DEFAULT *Error
+
+func (p *NodeFetchTaggedResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeWriteTaggedResult_Err_DEFAULT
+		return NodeFetchTaggedResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeWriteTaggedResult) IsSetErr() bool {
+func (p *NodeFetchTaggedResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeFetchTaggedResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeWriteTaggedResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchTaggedResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20519,6 +25132,10 @@ func (p *NodeWriteTaggedResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -20538,7 +25155,15 @@ func (p *NodeWriteTaggedResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeFetchTaggedResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchTaggedResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeFetchTaggedResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -20548,11 +25173,14 @@ func (p *NodeWriteTaggedResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeTagged_result"); err != nil {
+func (p *NodeFetchTaggedResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchTagged_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -20566,7 +25194,22 @@ func (p *NodeWriteTaggedResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchTaggedResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeFetchTaggedResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -20581,36 +25224,36 @@ func (p *NodeWriteTaggedResult) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeWriteTaggedResult) String() string {
+func (p *NodeFetchTaggedResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteTaggedResult(%+v)", *p)
+	return fmt.Sprintf("NodeFetchTaggedResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeAggregateRawArgs struct {
-	Req *AggregateQueryRawRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeFetchBlocksMetadataRawV2Args struct {
+	Req *FetchBlocksMetadataRawV2Request `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeAggregateRawArgs() *NodeAggregateRawArgs {
-	return &NodeAggregateRawArgs{}
+func NewNodeFetchBlocksMetadataRawV2Args() *NodeFetchBlocksMetadataRawV2Args {
+	return &NodeFetchBlocksMetadataRawV2Args{}
 }
 
-var NodeAggregateRawArgs_Req_DEFAULT *AggregateQueryRawRequest
+var NodeFetchBlocksMetadataRawV2Args_Req_DEFAULT *FetchBlocksMetadataRawV2Request
 
-func (p *NodeAggregateRawArgs) GetReq() *AggregateQueryRawRequest {
+func (p *NodeFetchBlocksMetadataRawV2Args) GetReq() *FetchBlocksMetadataRawV2Request {
 	if !p.IsSetReq() {
-		return NodeAggregateRawArgs_Req_DEFAULT
+		return NodeFetchBlocksMetadataRawV2Args_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeAggregateRawArgs) IsSetReq() bool {
+func (p *NodeFetchBlocksMetadataRawV2Args) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeAggregateRawArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBlocksMetadataRawV2Args) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20643,20 +25286,16 @@ func (p *NodeAggregateRawArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateRawArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &AggregateQueryRawRequest{
-		AggregateQueryType: 1,
-
-		RangeType: 0,
-	}
+func (p *NodeFetchBlocksMetadataRawV2Args) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchBlocksMetadataRawV2Request{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeAggregateRawArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("aggregateRaw_args"); err != nil {
+func (p *NodeFetchBlocksMetadataRawV2Args) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBlocksMetadataRawV2_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20673,7 +25312,7 @@ func (p *NodeAggregateRawArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBlocksMetadataRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -20686,51 +25325,51 @@ func (p *NodeAggregateRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeAggregateRawArgs) String() string {
+func (p *NodeFetchBlocksMetadataRawV2Args) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeAggregateRawArgs(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBlocksMetadataRawV2Args(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeAggregateRawResult struct {
-	Success *AggregateQueryRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                    `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeFetchBlocksMetadataRawV2Result struct {
+	Success *FetchBlocksMetadataRawV2Result_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                           `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeAggregateRawResult() *NodeAggregateRawResult {
-	return &NodeAggregateRawResult{}
+func NewNodeFetchBlocksMetadataRawV2Result() *NodeFetchBlocksMetadataRawV2Result {
+	return &NodeFetchBlocksMetadataRawV2Result{}
 }
 
-var NodeAggregateRawResult_Success_DEFAULT *AggregateQueryRawResult_
+var NodeFetchBlocksMetadataRawV2Result_Success_DEFAULT *FetchBlocksMetadataRawV2Result_
 
-func (p *NodeAggregateRawResult) GetSuccess() *AggregateQueryRawResult_ {
+func (p *NodeFetchBlocksMetadataRawV2Result) GetSuccess() *FetchBlocksMetadataRawV2Result_ {
 	if !p.IsSetSuccess() {
-		return NodeAggregateRawResult_Success_DEFAULT
+		return NodeFetchBlocksMetadataRawV2Result_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeAggregateRawResult_Err_DEFAULT *Error
+var NodeFetchBlocksMetadataRawV2Result_Err_DEFAULT *Error
 
-func (p *NodeAggregateRawResult) GetErr() *Error {
+func (p *NodeFetchBlocksMetadataRawV2Result) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeAggregateRawResult_Err_DEFAULT
+		return NodeFetchBlocksMetadataRawV2Result_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeAggregateRawResult) IsSetSuccess() bool {
+func (p *NodeFetchBlocksMetadataRawV2Result) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeAggregateRawResult) IsSetErr() bool {
+func (p *NodeFetchBlocksMetadataRawV2Result) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeAggregateRawResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchBlocksMetadataRawV2Result) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20767,15 +25406,15 @@ func (p *NodeAggregateRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateRawResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &AggregateQueryRawResult_{}
+func (p *NodeFetchBlocksMetadataRawV2Result) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchBlocksMetadataRawV2Result_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeAggregateRawResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeFetchBlocksMetadataRawV2Result) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -20785,8 +25424,8 @@ func (p *NodeAggregateRawResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateRawResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("aggregateRaw_result"); err != nil {
+func (p *NodeFetchBlocksMetadataRawV2Result) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchBlocksMetadataRawV2_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20806,7 +25445,7 @@ func (p *NodeAggregateRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateRawResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBlocksMetadataRawV2Result) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -20821,7 +25460,7 @@ func (p *NodeAggregateRawResult) writeField0(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeAggregateRawResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchBlocksMetadataRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -20836,36 +25475,36 @@ func (p *NodeAggregateRawResult) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeAggregateRawResult) String() string {
+func (p *NodeFetchBlocksMetadataRawV2Result) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeAggregateRawResult(%+v)", *p)
+	return fmt.Sprintf("NodeFetchBlocksMetadataRawV2Result(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeFetchBatchRawArgs struct {
-	Req *FetchBatchRawRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeWriteBatchRawArgs struct {
+	Req *WriteBatchRawRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeFetchBatchRawArgs() *NodeFetchBatchRawArgs {
-	return &NodeFetchBatchRawArgs{}
+func NewNodeWriteBatchRawArgs() *NodeWriteBatchRawArgs {
+	return &NodeWriteBatchRawArgs{}
 }
 
-var NodeFetchBatchRawArgs_Req_DEFAULT *FetchBatchRawRequest
+var NodeWriteBatchRawArgs_Req_DEFAULT *WriteBatchRawRequest
 
-func (p *NodeFetchBatchRawArgs) GetReq() *FetchBatchRawRequest {
+func (p *NodeWriteBatchRawArgs) GetReq() *WriteBatchRawRequest {
 	if !p.IsSetReq() {
-		return NodeFetchBatchRawArgs_Req_DEFAULT
+		return NodeWriteBatchRawArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeFetchBatchRawArgs) IsSetReq() bool {
+func (p *NodeWriteBatchRawArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeFetchBatchRawArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteBatchRawArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20898,18 +25537,16 @@ func (p *NodeFetchBatchRawArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &FetchBatchRawRequest{
-		RangeTimeType: 0,
-	}
+func (p *NodeWriteBatchRawArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteBatchRawRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchBatchRawArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBatchRaw_args"); err != nil {
+func (p *NodeWriteBatchRawArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeBatchRaw_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -20926,7 +25563,7 @@ func (p *NodeFetchBatchRawArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -20939,51 +25576,36 @@ func (p *NodeFetchBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeFetchBatchRawArgs) String() string {
+func (p *NodeWriteBatchRawArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBatchRawArgs(%+v)", *p)
+	return fmt.Sprintf("NodeWriteBatchRawArgs(%+v)", *p)
 }
 
 // Attributes:
-//  - Success
 //  - Err
-type NodeFetchBatchRawResult struct {
-	Success *FetchBatchRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                `thrift:"err,1" db:"err" json:"err,omitempty"`
-}
-
-func NewNodeFetchBatchRawResult() *NodeFetchBatchRawResult {
-	return &NodeFetchBatchRawResult{}
+type NodeWriteBatchRawResult struct {
+	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-var NodeFetchBatchRawResult_Success_DEFAULT *FetchBatchRawResult_
-
-func (p *NodeFetchBatchRawResult) GetSuccess() *FetchBatchRawResult_ {
-	if !p.IsSetSuccess() {
-		return NodeFetchBatchRawResult_Success_DEFAULT
-	}
-	return p.Success
+func NewNodeWriteBatchRawResult() *NodeWriteBatchRawResult {
+	return &NodeWriteBatchRawResult{}
 }
 
-var NodeFetchBatchRawResult_Err_DEFAULT *Error
+var NodeWriteBatchRawResult_Err_DEFAULT *WriteBatchRawErrors
 
-func (p *NodeFetchBatchRawResult) GetErr() *Error {
+func (p *NodeWriteBatchRawResult) GetErr() *WriteBatchRawErrors {
 	if !p.IsSetErr() {
-		return NodeFetchBatchRawResult_Err_DEFAULT
+		return NodeWriteBatchRawResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeFetchBatchRawResult) IsSetSuccess() bool {
-	return p.Success != nil
-}
-
-func (p *NodeFetchBatchRawResult) IsSetErr() bool {
+func (p *NodeWriteBatchRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeFetchBatchRawResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteBatchRawResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -20997,10 +25619,6 @@ func (p *NodeFetchBatchRawResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
-		case 0:
-			if err := p.ReadField0(iprot); err != nil {
-				return err
-			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -21020,32 +25638,19 @@ func (p *NodeFetchBatchRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &FetchBatchRawResult_{}
-	if err := p.Success.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
-	}
-	return nil
-}
-
-func (p *NodeFetchBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &Error{
-		Type: 0,
-	}
+func (p *NodeWriteBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &WriteBatchRawErrors{}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchBatchRawResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBatchRaw_result"); err != nil {
+func (p *NodeWriteBatchRawResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeBatchRaw_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField0(oprot); err != nil {
-			return err
-		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -21059,22 +25664,7 @@ func (p *NodeFetchBatchRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawResult) writeField0(oprot thrift.TProtocol) (err error) {
-	if p.IsSetSuccess() {
-		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
-		}
-		if err := p.Success.Write(oprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
-		}
-		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
-		}
-	}
-	return err
-}
-
-func (p *NodeFetchBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -21089,36 +25679,36 @@ func (p *NodeFetchBatchRawResult) writeField1(oprot thrift.TProtocol) (err error
 	return err
 }
 
-func (p *NodeFetchBatchRawResult) String() string {
+func (p *NodeWriteBatchRawResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBatchRawResult(%+v)", *p)
+	return fmt.Sprintf("NodeWriteBatchRawResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeFetchBatchRawV2Args struct {
-	Req *FetchBatchRawV2Request `thrift:"req,1" db:"req" json:"req"`
+type NodeWriteBatchRawV2Args struct {
+	Req *WriteBatchRawV2Request `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeFetchBatchRawV2Args() *NodeFetchBatchRawV2Args {
-	return &NodeFetchBatchRawV2Args{}
+func NewNodeWriteBatchRawV2Args() *NodeWriteBatchRawV2Args {
+	return &NodeWriteBatchRawV2Args{}
 }
 
-var NodeFetchBatchRawV2Args_Req_DEFAULT *FetchBatchRawV2Request
+var NodeWriteBatchRawV2Args_Req_DEFAULT *WriteBatchRawV2Request
 
-func (p *NodeFetchBatchRawV2Args) GetReq() *FetchBatchRawV2Request {
+func (p *NodeWriteBatchRawV2Args) GetReq() *WriteBatchRawV2Request {
 	if !p.IsSetReq() {
-		return NodeFetchBatchRawV2Args_Req_DEFAULT
+		return NodeWriteBatchRawV2Args_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeFetchBatchRawV2Args) IsSetReq() bool {
+func (p *NodeWriteBatchRawV2Args) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeFetchBatchRawV2Args) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21151,16 +25741,16 @@ func (p *NodeFetchBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawV2Args) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &FetchBatchRawV2Request{}
+func (p *NodeWriteBatchRawV2Args) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteBatchRawV2Request{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchBatchRawV2Args) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBatchRawV2_args"); err != nil {
+func (p *NodeWriteBatchRawV2Args) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeBatchRawV2_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -21177,7 +25767,7 @@ func (p *NodeFetchBatchRawV2Args) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -21190,51 +25780,36 @@ func (p *NodeFetchBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error
 	return err
 }
 
-func (p *NodeFetchBatchRawV2Args) String() string {
+func (p *NodeWriteBatchRawV2Args) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBatchRawV2Args(%+v)", *p)
+	return fmt.Sprintf("NodeWriteBatchRawV2Args(%+v)", *p)
 }
 
 // Attributes:
-//  - Success
 //  - Err
-type NodeFetchBatchRawV2Result struct {
-	Success *FetchBatchRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                `thrift:"err,1" db:"err" json:"err,omitempty"`
-}
-
-func NewNodeFetchBatchRawV2Result() *NodeFetchBatchRawV2Result {
-	return &NodeFetchBatchRawV2Result{}
+type NodeWriteBatchRawV2Result struct {
+	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-var NodeFetchBatchRawV2Result_Success_DEFAULT *FetchBatchRawResult_
-
-func (p *NodeFetchBatchRawV2Result) GetSuccess() *FetchBatchRawResult_ {
-	if !p.IsSetSuccess() {
-		return NodeFetchBatchRawV2Result_Success_DEFAULT
-	}
-	return p.Success
+func NewNodeWriteBatchRawV2Result() *NodeWriteBatchRawV2Result {
+	return &NodeWriteBatchRawV2Result{}
 }
 
-var NodeFetchBatchRawV2Result_Err_DEFAULT *Error
+var NodeWriteBatchRawV2Result_Err_DEFAULT *WriteBatchRawErrors
 
-func (p *NodeFetchBatchRawV2Result) GetErr() *Error {
+func (p *NodeWriteBatchRawV2Result) GetErr() *WriteBatchRawErrors {
 	if !p.IsSetErr() {
-		return NodeFetchBatchRawV2Result_Err_DEFAULT
+		return NodeWriteBatchRawV2Result_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeFetchBatchRawV2Result) IsSetSuccess() bool {
-	return p.Success != nil
-}
-
-func (p *NodeFetchBatchRawV2Result) IsSetErr() bool {
+func (p *NodeWriteBatchRawV2Result) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeFetchBatchRawV2Result) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21248,10 +25823,6 @@ func (p *NodeFetchBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
-		case 0:
-			if err := p.ReadField0(iprot); err != nil {
-				return err
-			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -21271,32 +25842,19 @@ func (p *NodeFetchBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBatchRawV2Result) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &FetchBatchRawResult_{}
-	if err := p.Success.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
-	}
-	return nil
-}
-
-func (p *NodeFetchBatchRawV2Result) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &Error{
-		Type: 0,
-	}
+func (p *NodeWriteBatchRawV2Result) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &WriteBatchRawErrors{}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchBatchRawV2Result) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBatchRawV2_result"); err != nil {
+func (p *NodeWriteBatchRawV2Result) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeBatchRawV2_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField0(oprot); err != nil {
-			return err
-		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -21304,28 +25862,13 @@ func (p *NodeFetchBatchRawV2Result) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
 	}
-	if err := oprot.WriteStructEnd(); err != nil {
-		return thrift.PrependError("write struct stop error: ", err)
-	}
-	return nil
-}
-
-func (p *NodeFetchBatchRawV2Result) writeField0(oprot thrift.TProtocol) (err error) {
-	if p.IsSetSuccess() {
-		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
-		}
-		if err := p.Success.Write(oprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
-		}
-		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
-		}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
 	}
-	return err
+	return nil
 }
 
-func (p *NodeFetchBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -21340,36 +25883,36 @@ func (p *NodeFetchBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err err
 	return err
 }
 
-func (p *NodeFetchBatchRawV2Result) String() string {
+func (p *NodeWriteBatchRawV2Result) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBatchRawV2Result(%+v)", *p)
+	return fmt.Sprintf("NodeWriteBatchRawV2Result(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeFetchBlocksRawArgs struct {
-	Req *FetchBlocksRawRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeWriteTaggedBatchRawArgs struct {
+	Req *WriteTaggedBatchRawRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeFetchBlocksRawArgs() *NodeFetchBlocksRawArgs {
-	return &NodeFetchBlocksRawArgs{}
+func NewNodeWriteTaggedBatchRawArgs() *NodeWriteTaggedBatchRawArgs {
+	return &NodeWriteTaggedBatchRawArgs{}
 }
 
-var NodeFetchBlocksRawArgs_Req_DEFAULT *FetchBlocksRawRequest
+var NodeWriteTaggedBatchRawArgs_Req_DEFAULT *WriteTaggedBatchRawRequest
 
-func (p *NodeFetchBlocksRawArgs) GetReq() *FetchBlocksRawRequest {
+func (p *NodeWriteTaggedBatchRawArgs) GetReq() *WriteTaggedBatchRawRequest {
 	if !p.IsSetReq() {
-		return NodeFetchBlocksRawArgs_Req_DEFAULT
+		return NodeWriteTaggedBatchRawArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeFetchBlocksRawArgs) IsSetReq() bool {
+func (p *NodeWriteTaggedBatchRawArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeFetchBlocksRawArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteTaggedBatchRawArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21402,16 +25945,16 @@ func (p *NodeFetchBlocksRawArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBlocksRawArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &FetchBlocksRawRequest{}
+func (p *NodeWriteTaggedBatchRawArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteTaggedBatchRawRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchBlocksRawArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBlocksRaw_args"); err != nil {
+func (p *NodeWriteTaggedBatchRawArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeTaggedBatchRaw_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -21428,7 +25971,7 @@ func (p *NodeFetchBlocksRawArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBlocksRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteTaggedBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -21441,51 +25984,36 @@ func (p *NodeFetchBlocksRawArgs) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeFetchBlocksRawArgs) String() string {
+func (p *NodeWriteTaggedBatchRawArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBlocksRawArgs(%+v)", *p)
+	return fmt.Sprintf("NodeWriteTaggedBatchRawArgs(%+v)", *p)
 }
 
 // Attributes:
-//  - Success
 //  - Err
-type NodeFetchBlocksRawResult struct {
-	Success *FetchBlocksRawResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
-}
-
-func NewNodeFetchBlocksRawResult() *NodeFetchBlocksRawResult {
-	return &NodeFetchBlocksRawResult{}
+type NodeWriteTaggedBatchRawResult struct {
+	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-var NodeFetchBlocksRawResult_Success_DEFAULT *FetchBlocksRawResult_
-
-func (p *NodeFetchBlocksRawResult) GetSuccess() *FetchBlocksRawResult_ {
-	if !p.IsSetSuccess() {
-		return NodeFetchBlocksRawResult_Success_DEFAULT
-	}
-	return p.Success
+func NewNodeWriteTaggedBatchRawResult() *NodeWriteTaggedBatchRawResult {
+	return &NodeWriteTaggedBatchRawResult{}
 }
 
-var NodeFetchBlocksRawResult_Err_DEFAULT *Error
+var NodeWriteTaggedBatchRawResult_Err_DEFAULT *WriteBatchRawErrors
 
-func (p *NodeFetchBlocksRawResult) GetErr() *Error {
+func (p *NodeWriteTaggedBatchRawResult) GetErr() *WriteBatchRawErrors {
 	if !p.IsSetErr() {
-		return NodeFetchBlocksRawResult_Err_DEFAULT
+		return NodeWriteTaggedBatchRawResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeFetchBlocksRawResult) IsSetSuccess() bool {
-	return p.Success != nil
-}
-
-func (p *NodeFetchBlocksRawResult) IsSetErr() bool {
+func (p *NodeWriteTaggedBatchRawResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeFetchBlocksRawResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteTaggedBatchRawResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21499,10 +26027,6 @@ func (p *NodeFetchBlocksRawResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
-		case 0:
-			if err := p.ReadField0(iprot); err != nil {
-				return err
-			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -21522,32 +26046,19 @@ func (p *NodeFetchBlocksRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBlocksRawResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &FetchBlocksRawResult_{}
-	if err := p.Success.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
-	}
-	return nil
-}
-
-func (p *NodeFetchBlocksRawResult) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &Error{
-		Type: 0,
-	}
+func (p *NodeWriteTaggedBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &WriteBatchRawErrors{}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchBlocksRawResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBlocksRaw_result"); err != nil {
+func (p *NodeWriteTaggedBatchRawResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeTaggedBatchRaw_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField0(oprot); err != nil {
-			return err
-		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -21561,22 +26072,7 @@ func (p *NodeFetchBlocksRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBlocksRawResult) writeField0(oprot thrift.TProtocol) (err error) {
-	if p.IsSetSuccess() {
-		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
-		}
-		if err := p.Success.Write(oprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
-		}
-		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
-		}
-	}
-	return err
-}
-
-func (p *NodeFetchBlocksRawResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteTaggedBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -21591,36 +26087,36 @@ func (p *NodeFetchBlocksRawResult) writeField1(oprot thrift.TProtocol) (err erro
 	return err
 }
 
-func (p *NodeFetchBlocksRawResult) String() string {
+func (p *NodeWriteTaggedBatchRawResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBlocksRawResult(%+v)", *p)
+	return fmt.Sprintf("NodeWriteTaggedBatchRawResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeFetchTaggedArgs struct {
-	Req *FetchTaggedRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeWriteTaggedBatchRawV2Args struct {
+	Req *WriteTaggedBatchRawV2Request `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeFetchTaggedArgs() *NodeFetchTaggedArgs {
-	return &NodeFetchTaggedArgs{}
+func NewNodeWriteTaggedBatchRawV2Args() *NodeWriteTaggedBatchRawV2Args {
+	return &NodeWriteTaggedBatchRawV2Args{}
 }
 
-var NodeFetchTaggedArgs_Req_DEFAULT *FetchTaggedRequest
+var NodeWriteTaggedBatchRawV2Args_Req_DEFAULT *WriteTaggedBatchRawV2Request
 
-func (p *NodeFetchTaggedArgs) GetReq() *FetchTaggedRequest {
+func (p *NodeWriteTaggedBatchRawV2Args) GetReq() *WriteTaggedBatchRawV2Request {
 	if !p.IsSetReq() {
-		return NodeFetchTaggedArgs_Req_DEFAULT
+		return NodeWriteTaggedBatchRawV2Args_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeFetchTaggedArgs) IsSetReq() bool {
+func (p *NodeWriteTaggedBatchRawV2Args) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeFetchTaggedArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteTaggedBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21653,20 +26149,16 @@ func (p *NodeFetchTaggedArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchTaggedArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &FetchTaggedRequest{
-		RangeTimeType: 0,
-
-		RequireExhaustive: true,
-	}
+func (p *NodeWriteTaggedBatchRawV2Args) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteTaggedBatchRawV2Request{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchTaggedArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchTagged_args"); err != nil {
+func (p *NodeWriteTaggedBatchRawV2Args) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeTaggedBatchRawV2_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -21683,7 +26175,7 @@ func (p *NodeFetchTaggedArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteTaggedBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -21696,51 +26188,36 @@ func (p *NodeFetchTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeFetchTaggedArgs) String() string {
+func (p *NodeWriteTaggedBatchRawV2Args) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchTaggedArgs(%+v)", *p)
+	return fmt.Sprintf("NodeWriteTaggedBatchRawV2Args(%+v)", *p)
 }
 
 // Attributes:
-//  - Success
 //  - Err
-type NodeFetchTaggedResult struct {
-	Success *FetchTaggedResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error              `thrift:"err,1" db:"err" json:"err,omitempty"`
-}
-
-func NewNodeFetchTaggedResult() *NodeFetchTaggedResult {
-	return &NodeFetchTaggedResult{}
+type NodeWriteTaggedBatchRawV2Result struct {
+	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-var NodeFetchTaggedResult_Success_DEFAULT *FetchTaggedResult_
-
-func (p *NodeFetchTaggedResult) GetSuccess() *FetchTaggedResult_ {
-	if !p.IsSetSuccess() {
-		return NodeFetchTaggedResult_Success_DEFAULT
-	}
-	return p.Success
+func NewNodeWriteTaggedBatchRawV2Result() *NodeWriteTaggedBatchRawV2Result {
+	return &NodeWriteTaggedBatchRawV2Result{}
 }
 
-var NodeFetchTaggedResult_Err_DEFAULT *Error
+var NodeWriteTaggedBatchRawV2Result_Err_DEFAULT *WriteBatchRawErrors
 
-func (p *NodeFetchTaggedResult) GetErr() *Error {
+func (p *NodeWriteTaggedBatchRawV2Result) GetErr() *WriteBatchRawErrors {
 	if !p.IsSetErr() {
-		return NodeFetchTaggedResult_Err_DEFAULT
+		return NodeWriteTaggedBatchRawV2Result_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeFetchTaggedResult) IsSetSuccess() bool {
-	return p.Success != nil
-}
-
-func (p *NodeFetchTaggedResult) IsSetErr() bool {
+func (p *NodeWriteTaggedBatchRawV2Result) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeFetchTaggedResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteTaggedBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21754,10 +26231,6 @@ func (p *NodeFetchTaggedResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
-		case 0:
-			if err := p.ReadField0(iprot); err != nil {
-				return err
-			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -21777,32 +26250,19 @@ func (p *NodeFetchTaggedResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchTaggedResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &FetchTaggedResult_{}
-	if err := p.Success.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
-	}
-	return nil
-}
-
-func (p *NodeFetchTaggedResult) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &Error{
-		Type: 0,
-	}
+func (p *NodeWriteTaggedBatchRawV2Result) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &WriteBatchRawErrors{}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeFetchTaggedResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchTagged_result"); err != nil {
+func (p *NodeWriteTaggedBatchRawV2Result) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeTaggedBatchRawV2_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField0(oprot); err != nil {
-			return err
-		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -21816,22 +26276,7 @@ func (p *NodeFetchTaggedResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchTaggedResult) writeField0(oprot thrift.TProtocol) (err error) {
-	if p.IsSetSuccess() {
-		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
-		}
-		if err := p.Success.Write(oprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
-		}
-		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
-		}
-	}
-	return err
-}
-
-func (p *NodeFetchTaggedResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteTaggedBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -21846,36 +26291,21 @@ func (p *NodeFetchTaggedResult) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeFetchTaggedResult) String() string {
+func (p *NodeWriteTaggedBatchRawV2Result) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchTaggedResult(%+v)", *p)
-}
-
-// Attributes:
-//  - Req
-type NodeFetchBlocksMetadataRawV2Args struct {
-	Req *FetchBlocksMetadataRawV2Request `thrift:"req,1" db:"req" json:"req"`
-}
-
-func NewNodeFetchBlocksMetadataRawV2Args() *NodeFetchBlocksMetadataRawV2Args {
-	return &NodeFetchBlocksMetadataRawV2Args{}
+	return fmt.Sprintf("NodeWriteTaggedBatchRawV2Result(%+v)", *p)
 }
 
-var NodeFetchBlocksMetadataRawV2Args_Req_DEFAULT *FetchBlocksMetadataRawV2Request
-
-func (p *NodeFetchBlocksMetadataRawV2Args) GetReq() *FetchBlocksMetadataRawV2Request {
-	if !p.IsSetReq() {
-		return NodeFetchBlocksMetadataRawV2Args_Req_DEFAULT
-	}
-	return p.Req
+type NodeRepairArgs struct {
 }
-func (p *NodeFetchBlocksMetadataRawV2Args) IsSetReq() bool {
-	return p.Req != nil
+
+func NewNodeRepairArgs() *NodeRepairArgs {
+	return &NodeRepairArgs{}
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Args) Read(iprot thrift.TProtocol) error {
+func (p *NodeRepairArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -21888,15 +26318,8 @@ func (p *NodeFetchBlocksMetadataRawV2Args) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		switch fieldId {
-		case 1:
-			if err := p.ReadField1(iprot); err != nil {
-				return err
-			}
-		default:
-			if err := iprot.Skip(fieldTypeId); err != nil {
-				return err
-			}
+		if err := iprot.Skip(fieldTypeId); err != nil {
+			return err
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -21908,22 +26331,11 @@ func (p *NodeFetchBlocksMetadataRawV2Args) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Args) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &FetchBlocksMetadataRawV2Request{}
-	if err := p.Req.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
-	}
-	return nil
-}
-
-func (p *NodeFetchBlocksMetadataRawV2Args) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBlocksMetadataRawV2_args"); err != nil {
+func (p *NodeRepairArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("repair_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField1(oprot); err != nil {
-			return err
-		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -21934,64 +26346,36 @@ func (p *NodeFetchBlocksMetadataRawV2Args) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
-	}
-	if err := p.Req.Write(oprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
-	}
-	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
-	}
-	return err
-}
-
-func (p *NodeFetchBlocksMetadataRawV2Args) String() string {
+func (p *NodeRepairArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBlocksMetadataRawV2Args(%+v)", *p)
+	return fmt.Sprintf("NodeRepairArgs(%+v)", *p)
 }
 
 // Attributes:
-//  - Success
 //  - Err
-type NodeFetchBlocksMetadataRawV2Result struct {
-	Success *FetchBlocksMetadataRawV2Result_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                           `thrift:"err,1" db:"err" json:"err,omitempty"`
-}
-
-func NewNodeFetchBlocksMetadataRawV2Result() *NodeFetchBlocksMetadataRawV2Result {
-	return &NodeFetchBlocksMetadataRawV2Result{}
+type NodeRepairResult struct {
+	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-var NodeFetchBlocksMetadataRawV2Result_Success_DEFAULT *FetchBlocksMetadataRawV2Result_
-
-func (p *NodeFetchBlocksMetadataRawV2Result) GetSuccess() *FetchBlocksMetadataRawV2Result_ {
-	if !p.IsSetSuccess() {
-		return NodeFetchBlocksMetadataRawV2Result_Success_DEFAULT
-	}
-	return p.Success
+func NewNodeRepairResult() *NodeRepairResult {
+	return &NodeRepairResult{}
 }
 
-var NodeFetchBlocksMetadataRawV2Result_Err_DEFAULT *Error
+var NodeRepairResult_Err_DEFAULT *Error
 
-func (p *NodeFetchBlocksMetadataRawV2Result) GetErr() *Error {
+func (p *NodeRepairResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeFetchBlocksMetadataRawV2Result_Err_DEFAULT
+		return NodeRepairResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeFetchBlocksMetadataRawV2Result) IsSetSuccess() bool {
-	return p.Success != nil
-}
-
-func (p *NodeFetchBlocksMetadataRawV2Result) IsSetErr() bool {
+func (p *NodeRepairResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Result) Read(iprot thrift.TProtocol) error {
+func (p *NodeRepairResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22005,10 +26389,6 @@ func (p *NodeFetchBlocksMetadataRawV2Result) Read(iprot thrift.TProtocol) error
 			break
 		}
 		switch fieldId {
-		case 0:
-			if err := p.ReadField0(iprot); err != nil {
-				return err
-			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -22028,15 +26408,7 @@ func (p *NodeFetchBlocksMetadataRawV2Result) Read(iprot thrift.TProtocol) error
 	return nil
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Result) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &FetchBlocksMetadataRawV2Result_{}
-	if err := p.Success.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
-	}
-	return nil
-}
-
-func (p *NodeFetchBlocksMetadataRawV2Result) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeRepairResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -22046,14 +26418,11 @@ func (p *NodeFetchBlocksMetadataRawV2Result) ReadField1(iprot thrift.TProtocol)
 	return nil
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Result) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("fetchBlocksMetadataRawV2_result"); err != nil {
+func (p *NodeRepairResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("repair_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField0(oprot); err != nil {
-			return err
-		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -22067,22 +26436,7 @@ func (p *NodeFetchBlocksMetadataRawV2Result) Write(oprot thrift.TProtocol) error
 	return nil
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Result) writeField0(oprot thrift.TProtocol) (err error) {
-	if p.IsSetSuccess() {
-		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
-		}
-		if err := p.Success.Write(oprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
-		}
-		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
-		}
-	}
-	return err
-}
-
-func (p *NodeFetchBlocksMetadataRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeRepairResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -22097,36 +26451,36 @@ func (p *NodeFetchBlocksMetadataRawV2Result) writeField1(oprot thrift.TProtocol)
 	return err
 }
 
-func (p *NodeFetchBlocksMetadataRawV2Result) String() string {
+func (p *NodeRepairResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeFetchBlocksMetadataRawV2Result(%+v)", *p)
+	return fmt.Sprintf("NodeRepairResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeWriteBatchRawArgs struct {
-	Req *WriteBatchRawRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeTruncateArgs struct {
+	Req *TruncateRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeWriteBatchRawArgs() *NodeWriteBatchRawArgs {
-	return &NodeWriteBatchRawArgs{}
+func NewNodeTruncateArgs() *NodeTruncateArgs {
+	return &NodeTruncateArgs{}
 }
 
-var NodeWriteBatchRawArgs_Req_DEFAULT *WriteBatchRawRequest
+var NodeTruncateArgs_Req_DEFAULT *TruncateRequest
 
-func (p *NodeWriteBatchRawArgs) GetReq() *WriteBatchRawRequest {
+func (p *NodeTruncateArgs) GetReq() *TruncateRequest {
 	if !p.IsSetReq() {
-		return NodeWriteBatchRawArgs_Req_DEFAULT
+		return NodeTruncateArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeWriteBatchRawArgs) IsSetReq() bool {
+func (p *NodeTruncateArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeWriteBatchRawArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeTruncateArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22159,16 +26513,16 @@ func (p *NodeWriteBatchRawArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &WriteBatchRawRequest{}
+func (p *NodeTruncateArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &TruncateRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteBatchRawArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeBatchRaw_args"); err != nil {
+func (p *NodeTruncateArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("truncate_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -22185,7 +26539,7 @@ func (p *NodeWriteBatchRawArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeTruncateArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -22198,36 +26552,51 @@ func (p *NodeWriteBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeWriteBatchRawArgs) String() string {
+func (p *NodeTruncateArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteBatchRawArgs(%+v)", *p)
+	return fmt.Sprintf("NodeTruncateArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeWriteBatchRawResult struct {
-	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeTruncateResult struct {
+	Success *TruncateResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error           `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeWriteBatchRawResult() *NodeWriteBatchRawResult {
-	return &NodeWriteBatchRawResult{}
+func NewNodeTruncateResult() *NodeTruncateResult {
+	return &NodeTruncateResult{}
 }
 
-var NodeWriteBatchRawResult_Err_DEFAULT *WriteBatchRawErrors
+var NodeTruncateResult_Success_DEFAULT *TruncateResult_
 
-func (p *NodeWriteBatchRawResult) GetErr() *WriteBatchRawErrors {
+func (p *NodeTruncateResult) GetSuccess() *TruncateResult_ {
+	if !p.IsSetSuccess() {
+		return NodeTruncateResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeTruncateResult_Err_DEFAULT *Error
+
+func (p *NodeTruncateResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeWriteBatchRawResult_Err_DEFAULT
+		return NodeTruncateResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeWriteBatchRawResult) IsSetErr() bool {
+func (p *NodeTruncateResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeTruncateResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeWriteBatchRawResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeTruncateResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22241,6 +26610,10 @@ func (p *NodeWriteBatchRawResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -22260,19 +26633,32 @@ func (p *NodeWriteBatchRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &WriteBatchRawErrors{}
+func (p *NodeTruncateResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &TruncateResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeTruncateResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteBatchRawResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeBatchRaw_result"); err != nil {
+func (p *NodeTruncateResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("truncate_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -22286,7 +26672,22 @@ func (p *NodeWriteBatchRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeTruncateResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeTruncateResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -22301,36 +26702,36 @@ func (p *NodeWriteBatchRawResult) writeField1(oprot thrift.TProtocol) (err error
 	return err
 }
 
-func (p *NodeWriteBatchRawResult) String() string {
+func (p *NodeTruncateResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteBatchRawResult(%+v)", *p)
+	return fmt.Sprintf("NodeTruncateResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeWriteBatchRawV2Args struct {
-	Req *WriteBatchRawV2Request `thrift:"req,1" db:"req" json:"req"`
+type NodeAggregateTilesArgs struct {
+	Req *AggregateTilesRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeWriteBatchRawV2Args() *NodeWriteBatchRawV2Args {
-	return &NodeWriteBatchRawV2Args{}
+func NewNodeAggregateTilesArgs() *NodeAggregateTilesArgs {
+	return &NodeAggregateTilesArgs{}
 }
 
-var NodeWriteBatchRawV2Args_Req_DEFAULT *WriteBatchRawV2Request
+var NodeAggregateTilesArgs_Req_DEFAULT *AggregateTilesRequest
 
-func (p *NodeWriteBatchRawV2Args) GetReq() *WriteBatchRawV2Request {
+func (p *NodeAggregateTilesArgs) GetReq() *AggregateTilesRequest {
 	if !p.IsSetReq() {
-		return NodeWriteBatchRawV2Args_Req_DEFAULT
+		return NodeAggregateTilesArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeWriteBatchRawV2Args) IsSetReq() bool {
+func (p *NodeAggregateTilesArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeWriteBatchRawV2Args) Read(iprot thrift.TProtocol) error {
+func (p *NodeAggregateTilesArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22363,16 +26764,18 @@ func (p *NodeWriteBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawV2Args) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &WriteBatchRawV2Request{}
+func (p *NodeAggregateTilesArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &AggregateTilesRequest{
+		RangeType: 0,
+	}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteBatchRawV2Args) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeBatchRawV2_args"); err != nil {
+func (p *NodeAggregateTilesArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("aggregateTiles_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -22389,7 +26792,7 @@ func (p *NodeWriteBatchRawV2Args) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeAggregateTilesArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -22402,36 +26805,51 @@ func (p *NodeWriteBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error
 	return err
 }
 
-func (p *NodeWriteBatchRawV2Args) String() string {
+func (p *NodeAggregateTilesArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteBatchRawV2Args(%+v)", *p)
+	return fmt.Sprintf("NodeAggregateTilesArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeWriteBatchRawV2Result struct {
-	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeAggregateTilesResult struct {
+	Success *AggregateTilesResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeWriteBatchRawV2Result() *NodeWriteBatchRawV2Result {
-	return &NodeWriteBatchRawV2Result{}
+func NewNodeAggregateTilesResult() *NodeAggregateTilesResult {
+	return &NodeAggregateTilesResult{}
 }
 
-var NodeWriteBatchRawV2Result_Err_DEFAULT *WriteBatchRawErrors
+var NodeAggregateTilesResult_Success_DEFAULT *AggregateTilesResult_
 
-func (p *NodeWriteBatchRawV2Result) GetErr() *WriteBatchRawErrors {
+func (p *NodeAggregateTilesResult) GetSuccess() *AggregateTilesResult_ {
+	if !p.IsSetSuccess() {
+		return NodeAggregateTilesResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeAggregateTilesResult_Err_DEFAULT *Error
+
+func (p *NodeAggregateTilesResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeWriteBatchRawV2Result_Err_DEFAULT
+		return NodeAggregateTilesResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeWriteBatchRawV2Result) IsSetErr() bool {
+func (p *NodeAggregateTilesResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeAggregateTilesResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeWriteBatchRawV2Result) Read(iprot thrift.TProtocol) error {
+func (p *NodeAggregateTilesResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22445,6 +26863,10 @@ func (p *NodeWriteBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -22464,19 +26886,32 @@ func (p *NodeWriteBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawV2Result) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &WriteBatchRawErrors{}
+func (p *NodeAggregateTilesResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &AggregateTilesResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeAggregateTilesResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteBatchRawV2Result) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeBatchRawV2_result"); err != nil {
+func (p *NodeAggregateTilesResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("aggregateTiles_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -22490,7 +26925,22 @@ func (p *NodeWriteBatchRawV2Result) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeAggregateTilesResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeAggregateTilesResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -22505,36 +26955,21 @@ func (p *NodeWriteBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err err
 	return err
 }
 
-func (p *NodeWriteBatchRawV2Result) String() string {
+func (p *NodeAggregateTilesResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteBatchRawV2Result(%+v)", *p)
-}
-
-// Attributes:
-//  - Req
-type NodeWriteTaggedBatchRawArgs struct {
-	Req *WriteTaggedBatchRawRequest `thrift:"req,1" db:"req" json:"req"`
+	return fmt.Sprintf("NodeAggregateTilesResult(%+v)", *p)
 }
 
-func NewNodeWriteTaggedBatchRawArgs() *NodeWriteTaggedBatchRawArgs {
-	return &NodeWriteTaggedBatchRawArgs{}
+type NodeHealthArgs struct {
 }
 
-var NodeWriteTaggedBatchRawArgs_Req_DEFAULT *WriteTaggedBatchRawRequest
-
-func (p *NodeWriteTaggedBatchRawArgs) GetReq() *WriteTaggedBatchRawRequest {
-	if !p.IsSetReq() {
-		return NodeWriteTaggedBatchRawArgs_Req_DEFAULT
-	}
-	return p.Req
-}
-func (p *NodeWriteTaggedBatchRawArgs) IsSetReq() bool {
-	return p.Req != nil
+func NewNodeHealthArgs() *NodeHealthArgs {
+	return &NodeHealthArgs{}
 }
 
-func (p *NodeWriteTaggedBatchRawArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeHealthArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22547,15 +26982,8 @@ func (p *NodeWriteTaggedBatchRawArgs) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		switch fieldId {
-		case 1:
-			if err := p.ReadField1(iprot); err != nil {
-				return err
-			}
-		default:
-			if err := iprot.Skip(fieldTypeId); err != nil {
-				return err
-			}
+		if err := iprot.Skip(fieldTypeId); err != nil {
+			return err
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -22567,22 +26995,11 @@ func (p *NodeWriteTaggedBatchRawArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &WriteTaggedBatchRawRequest{}
-	if err := p.Req.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
-	}
-	return nil
-}
-
-func (p *NodeWriteTaggedBatchRawArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeTaggedBatchRaw_args"); err != nil {
+func (p *NodeHealthArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("health_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField1(oprot); err != nil {
-			return err
-		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -22593,49 +27010,51 @@ func (p *NodeWriteTaggedBatchRawArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawArgs) writeField1(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
-	}
-	if err := p.Req.Write(oprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
-	}
-	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
-	}
-	return err
-}
-
-func (p *NodeWriteTaggedBatchRawArgs) String() string {
+func (p *NodeHealthArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteTaggedBatchRawArgs(%+v)", *p)
+	return fmt.Sprintf("NodeHealthArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeWriteTaggedBatchRawResult struct {
-	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeHealthResult struct {
+	Success *NodeHealthResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error             `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeWriteTaggedBatchRawResult() *NodeWriteTaggedBatchRawResult {
-	return &NodeWriteTaggedBatchRawResult{}
+func NewNodeHealthResult() *NodeHealthResult {
+	return &NodeHealthResult{}
 }
 
-var NodeWriteTaggedBatchRawResult_Err_DEFAULT *WriteBatchRawErrors
+var NodeHealthResult_Success_DEFAULT *NodeHealthResult_
 
-func (p *NodeWriteTaggedBatchRawResult) GetErr() *WriteBatchRawErrors {
+func (p *NodeHealthResult) GetSuccess() *NodeHealthResult_ {
+	if !p.IsSetSuccess() {
+		return NodeHealthResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeHealthResult_Err_DEFAULT *Error
+
+func (p *NodeHealthResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeWriteTaggedBatchRawResult_Err_DEFAULT
+		return NodeHealthResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeWriteTaggedBatchRawResult) IsSetErr() bool {
+func (p *NodeHealthResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeHealthResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeWriteTaggedBatchRawResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeHealthResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22649,6 +27068,10 @@ func (p *NodeWriteTaggedBatchRawResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -22668,19 +27091,32 @@ func (p *NodeWriteTaggedBatchRawResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawResult) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &WriteBatchRawErrors{}
+func (p *NodeHealthResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeHealthResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeHealthResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeTaggedBatchRaw_result"); err != nil {
+func (p *NodeHealthResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("health_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -22694,7 +27130,22 @@ func (p *NodeWriteTaggedBatchRawResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeHealthResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeHealthResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -22709,36 +27160,21 @@ func (p *NodeWriteTaggedBatchRawResult) writeField1(oprot thrift.TProtocol) (err
 	return err
 }
 
-func (p *NodeWriteTaggedBatchRawResult) String() string {
+func (p *NodeHealthResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteTaggedBatchRawResult(%+v)", *p)
-}
-
-// Attributes:
-//  - Req
-type NodeWriteTaggedBatchRawV2Args struct {
-	Req *WriteTaggedBatchRawV2Request `thrift:"req,1" db:"req" json:"req"`
-}
-
-func NewNodeWriteTaggedBatchRawV2Args() *NodeWriteTaggedBatchRawV2Args {
-	return &NodeWriteTaggedBatchRawV2Args{}
+	return fmt.Sprintf("NodeHealthResult(%+v)", *p)
 }
 
-var NodeWriteTaggedBatchRawV2Args_Req_DEFAULT *WriteTaggedBatchRawV2Request
-
-func (p *NodeWriteTaggedBatchRawV2Args) GetReq() *WriteTaggedBatchRawV2Request {
-	if !p.IsSetReq() {
-		return NodeWriteTaggedBatchRawV2Args_Req_DEFAULT
-	}
-	return p.Req
+type NodeBootstrappedArgs struct {
 }
-func (p *NodeWriteTaggedBatchRawV2Args) IsSetReq() bool {
-	return p.Req != nil
+
+func NewNodeBootstrappedArgs() *NodeBootstrappedArgs {
+	return &NodeBootstrappedArgs{}
 }
 
-func (p *NodeWriteTaggedBatchRawV2Args) Read(iprot thrift.TProtocol) error {
+func (p *NodeBootstrappedArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22751,15 +27187,8 @@ func (p *NodeWriteTaggedBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		switch fieldId {
-		case 1:
-			if err := p.ReadField1(iprot); err != nil {
-				return err
-			}
-		default:
-			if err := iprot.Skip(fieldTypeId); err != nil {
-				return err
-			}
+		if err := iprot.Skip(fieldTypeId); err != nil {
+			return err
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -22771,22 +27200,11 @@ func (p *NodeWriteTaggedBatchRawV2Args) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawV2Args) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &WriteTaggedBatchRawV2Request{}
-	if err := p.Req.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
-	}
-	return nil
-}
-
-func (p *NodeWriteTaggedBatchRawV2Args) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeTaggedBatchRawV2_args"); err != nil {
+func (p *NodeBootstrappedArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("bootstrapped_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField1(oprot); err != nil {
-			return err
-		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -22797,49 +27215,51 @@ func (p *NodeWriteTaggedBatchRawV2Args) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawV2Args) writeField1(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
-	}
-	if err := p.Req.Write(oprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
-	}
-	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
-	}
-	return err
-}
-
-func (p *NodeWriteTaggedBatchRawV2Args) String() string {
+func (p *NodeBootstrappedArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteTaggedBatchRawV2Args(%+v)", *p)
+	return fmt.Sprintf("NodeBootstrappedArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeWriteTaggedBatchRawV2Result struct {
-	Err *WriteBatchRawErrors `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeBootstrappedResult struct {
+	Success *NodeBootstrappedResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                   `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeWriteTaggedBatchRawV2Result() *NodeWriteTaggedBatchRawV2Result {
-	return &NodeWriteTaggedBatchRawV2Result{}
+func NewNodeBootstrappedResult() *NodeBootstrappedResult {
+	return &NodeBootstrappedResult{}
 }
 
-var NodeWriteTaggedBatchRawV2Result_Err_DEFAULT *WriteBatchRawErrors
+var NodeBootstrappedResult_Success_DEFAULT *NodeBootstrappedResult_
 
-func (p *NodeWriteTaggedBatchRawV2Result) GetErr() *WriteBatchRawErrors {
+func (p *NodeBootstrappedResult) GetSuccess() *NodeBootstrappedResult_ {
+	if !p.IsSetSuccess() {
+		return NodeBootstrappedResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeBootstrappedResult_Err_DEFAULT *Error
+
+func (p *NodeBootstrappedResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeWriteTaggedBatchRawV2Result_Err_DEFAULT
+		return NodeBootstrappedResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeWriteTaggedBatchRawV2Result) IsSetErr() bool {
+func (p *NodeBootstrappedResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeBootstrappedResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeWriteTaggedBatchRawV2Result) Read(iprot thrift.TProtocol) error {
+func (p *NodeBootstrappedResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22853,6 +27273,10 @@ func (p *NodeWriteTaggedBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -22872,19 +27296,32 @@ func (p *NodeWriteTaggedBatchRawV2Result) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawV2Result) ReadField1(iprot thrift.TProtocol) error {
-	p.Err = &WriteBatchRawErrors{}
+func (p *NodeBootstrappedResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeBootstrappedResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeBootstrappedResult) ReadField1(iprot thrift.TProtocol) error {
+	p.Err = &Error{
+		Type: 0,
+	}
 	if err := p.Err.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Err), err)
 	}
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawV2Result) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("writeTaggedBatchRawV2_result"); err != nil {
+func (p *NodeBootstrappedResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("bootstrapped_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -22898,7 +27335,22 @@ func (p *NodeWriteTaggedBatchRawV2Result) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeWriteTaggedBatchRawV2Result) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeBootstrappedResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeBootstrappedResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -22913,21 +27365,21 @@ func (p *NodeWriteTaggedBatchRawV2Result) writeField1(oprot thrift.TProtocol) (e
 	return err
 }
 
-func (p *NodeWriteTaggedBatchRawV2Result) String() string {
+func (p *NodeBootstrappedResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeWriteTaggedBatchRawV2Result(%+v)", *p)
+	return fmt.Sprintf("NodeBootstrappedResult(%+v)", *p)
 }
 
-type NodeRepairArgs struct {
+type NodeBootstrappedInPlacementOrNoPlacementArgs struct {
 }
 
-func NewNodeRepairArgs() *NodeRepairArgs {
-	return &NodeRepairArgs{}
+func NewNodeBootstrappedInPlacementOrNoPlacementArgs() *NodeBootstrappedInPlacementOrNoPlacementArgs {
+	return &NodeBootstrappedInPlacementOrNoPlacementArgs{}
 }
 
-func (p *NodeRepairArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -22953,8 +27405,8 @@ func (p *NodeRepairArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeRepairArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("repair_args"); err != nil {
+func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("bootstrappedInPlacementOrNoPlacement_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -22968,36 +27420,51 @@ func (p *NodeRepairArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeRepairArgs) String() string {
+func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeRepairArgs(%+v)", *p)
+	return fmt.Sprintf("NodeBootstrappedInPlacementOrNoPlacementArgs(%+v)", *p)
 }
 
 // Attributes:
+//  - Success
 //  - Err
-type NodeRepairResult struct {
-	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeBootstrappedInPlacementOrNoPlacementResult struct {
+	Success *NodeBootstrappedInPlacementOrNoPlacementResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                                           `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeRepairResult() *NodeRepairResult {
-	return &NodeRepairResult{}
+func NewNodeBootstrappedInPlacementOrNoPlacementResult() *NodeBootstrappedInPlacementOrNoPlacementResult {
+	return &NodeBootstrappedInPlacementOrNoPlacementResult{}
 }
 
-var NodeRepairResult_Err_DEFAULT *Error
+var NodeBootstrappedInPlacementOrNoPlacementResult_Success_DEFAULT *NodeBootstrappedInPlacementOrNoPlacementResult_
 
-func (p *NodeRepairResult) GetErr() *Error {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) GetSuccess() *NodeBootstrappedInPlacementOrNoPlacementResult_ {
+	if !p.IsSetSuccess() {
+		return NodeBootstrappedInPlacementOrNoPlacementResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+var NodeBootstrappedInPlacementOrNoPlacementResult_Err_DEFAULT *Error
+
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeRepairResult_Err_DEFAULT
+		return NodeBootstrappedInPlacementOrNoPlacementResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeRepairResult) IsSetErr() bool {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeRepairResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23011,6 +27478,10 @@ func (p *NodeRepairResult) Read(iprot thrift.TProtocol) error {
 			break
 		}
 		switch fieldId {
+		case 0:
+			if err := p.ReadField0(iprot); err != nil {
+				return err
+			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -23030,7 +27501,15 @@ func (p *NodeRepairResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeRepairResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeBootstrappedInPlacementOrNoPlacementResult_{}
+	if err := p.Success.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -23040,11 +27519,14 @@ func (p *NodeRepairResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeRepairResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("repair_result"); err != nil {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("bootstrappedInPlacementOrNoPlacement_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField0(oprot); err != nil {
+			return err
+		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -23058,7 +27540,22 @@ func (p *NodeRepairResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeRepairResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) writeField0(oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -23073,57 +27570,35 @@ func (p *NodeRepairResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeRepairResult) String() string {
+func (p *NodeBootstrappedInPlacementOrNoPlacementResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeRepairResult(%+v)", *p)
-}
-
-// Attributes:
-//  - Req
-type NodeTruncateArgs struct {
-	Req *TruncateRequest `thrift:"req,1" db:"req" json:"req"`
+	return fmt.Sprintf("NodeBootstrappedInPlacementOrNoPlacementResult(%+v)", *p)
 }
 
-func NewNodeTruncateArgs() *NodeTruncateArgs {
-	return &NodeTruncateArgs{}
+type NodeGetPersistRateLimitArgs struct {
 }
 
-var NodeTruncateArgs_Req_DEFAULT *TruncateRequest
-
-func (p *NodeTruncateArgs) GetReq() *TruncateRequest {
-	if !p.IsSetReq() {
-		return NodeTruncateArgs_Req_DEFAULT
-	}
-	return p.Req
-}
-func (p *NodeTruncateArgs) IsSetReq() bool {
-	return p.Req != nil
+func NewNodeGetPersistRateLimitArgs() *NodeGetPersistRateLimitArgs {
+	return &NodeGetPersistRateLimitArgs{}
 }
 
-func (p *NodeTruncateArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
 	for {
 		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
-		if err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
-		}
-		if fieldTypeId == thrift.STOP {
-			break
-		}
-		switch fieldId {
-		case 1:
-			if err := p.ReadField1(iprot); err != nil {
-				return err
-			}
-		default:
-			if err := iprot.Skip(fieldTypeId); err != nil {
-				return err
-			}
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		if err := iprot.Skip(fieldTypeId); err != nil {
+			return err
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -23135,22 +27610,11 @@ func (p *NodeTruncateArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeTruncateArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &TruncateRequest{}
-	if err := p.Req.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
-	}
-	return nil
-}
-
-func (p *NodeTruncateArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("truncate_args"); err != nil {
+func (p *NodeGetPersistRateLimitArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getPersistRateLimit_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField1(oprot); err != nil {
-			return err
-		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -23161,64 +27625,51 @@ func (p *NodeTruncateArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeTruncateArgs) writeField1(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
-	}
-	if err := p.Req.Write(oprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
-	}
-	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
-	}
-	return err
-}
-
-func (p *NodeTruncateArgs) String() string {
+func (p *NodeGetPersistRateLimitArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeTruncateArgs(%+v)", *p)
+	return fmt.Sprintf("NodeGetPersistRateLimitArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeTruncateResult struct {
-	Success *TruncateResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error           `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeGetPersistRateLimitResult struct {
+	Success *NodePersistRateLimitResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                       `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeTruncateResult() *NodeTruncateResult {
-	return &NodeTruncateResult{}
+func NewNodeGetPersistRateLimitResult() *NodeGetPersistRateLimitResult {
+	return &NodeGetPersistRateLimitResult{}
 }
 
-var NodeTruncateResult_Success_DEFAULT *TruncateResult_
+var NodeGetPersistRateLimitResult_Success_DEFAULT *NodePersistRateLimitResult_
 
-func (p *NodeTruncateResult) GetSuccess() *TruncateResult_ {
+func (p *NodeGetPersistRateLimitResult) GetSuccess() *NodePersistRateLimitResult_ {
 	if !p.IsSetSuccess() {
-		return NodeTruncateResult_Success_DEFAULT
+		return NodeGetPersistRateLimitResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeTruncateResult_Err_DEFAULT *Error
+var NodeGetPersistRateLimitResult_Err_DEFAULT *Error
 
-func (p *NodeTruncateResult) GetErr() *Error {
+func (p *NodeGetPersistRateLimitResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeTruncateResult_Err_DEFAULT
+		return NodeGetPersistRateLimitResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeTruncateResult) IsSetSuccess() bool {
+func (p *NodeGetPersistRateLimitResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeTruncateResult) IsSetErr() bool {
+func (p *NodeGetPersistRateLimitResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeTruncateResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetPersistRateLimitResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23255,15 +27706,15 @@ func (p *NodeTruncateResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeTruncateResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &TruncateResult_{}
+func (p *NodeGetPersistRateLimitResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodePersistRateLimitResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeTruncateResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeGetPersistRateLimitResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -23273,8 +27724,8 @@ func (p *NodeTruncateResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeTruncateResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("truncate_result"); err != nil {
+func (p *NodeGetPersistRateLimitResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getPersistRateLimit_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -23294,7 +27745,7 @@ func (p *NodeTruncateResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeTruncateResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetPersistRateLimitResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -23309,7 +27760,7 @@ func (p *NodeTruncateResult) writeField0(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeTruncateResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetPersistRateLimitResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -23324,36 +27775,36 @@ func (p *NodeTruncateResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeTruncateResult) String() string {
+func (p *NodeGetPersistRateLimitResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeTruncateResult(%+v)", *p)
+	return fmt.Sprintf("NodeGetPersistRateLimitResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeAggregateTilesArgs struct {
-	Req *AggregateTilesRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeSetPersistRateLimitArgs struct {
+	Req *NodeSetPersistRateLimitRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeAggregateTilesArgs() *NodeAggregateTilesArgs {
-	return &NodeAggregateTilesArgs{}
+func NewNodeSetPersistRateLimitArgs() *NodeSetPersistRateLimitArgs {
+	return &NodeSetPersistRateLimitArgs{}
 }
 
-var NodeAggregateTilesArgs_Req_DEFAULT *AggregateTilesRequest
+var NodeSetPersistRateLimitArgs_Req_DEFAULT *NodeSetPersistRateLimitRequest
 
-func (p *NodeAggregateTilesArgs) GetReq() *AggregateTilesRequest {
+func (p *NodeSetPersistRateLimitArgs) GetReq() *NodeSetPersistRateLimitRequest {
 	if !p.IsSetReq() {
-		return NodeAggregateTilesArgs_Req_DEFAULT
+		return NodeSetPersistRateLimitArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeAggregateTilesArgs) IsSetReq() bool {
+func (p *NodeSetPersistRateLimitArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeAggregateTilesArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeSetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23386,18 +27837,16 @@ func (p *NodeAggregateTilesArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateTilesArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &AggregateTilesRequest{
-		RangeType: 0,
-	}
+func (p *NodeSetPersistRateLimitArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &NodeSetPersistRateLimitRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeAggregateTilesArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("aggregateTiles_args"); err != nil {
+func (p *NodeSetPersistRateLimitArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setPersistRateLimit_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -23414,7 +27863,7 @@ func (p *NodeAggregateTilesArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateTilesArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetPersistRateLimitArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -23427,51 +27876,51 @@ func (p *NodeAggregateTilesArgs) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeAggregateTilesArgs) String() string {
+func (p *NodeSetPersistRateLimitArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeAggregateTilesArgs(%+v)", *p)
+	return fmt.Sprintf("NodeSetPersistRateLimitArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeAggregateTilesResult struct {
-	Success *AggregateTilesResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeSetPersistRateLimitResult struct {
+	Success *NodePersistRateLimitResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                       `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeAggregateTilesResult() *NodeAggregateTilesResult {
-	return &NodeAggregateTilesResult{}
+func NewNodeSetPersistRateLimitResult() *NodeSetPersistRateLimitResult {
+	return &NodeSetPersistRateLimitResult{}
 }
 
-var NodeAggregateTilesResult_Success_DEFAULT *AggregateTilesResult_
+var NodeSetPersistRateLimitResult_Success_DEFAULT *NodePersistRateLimitResult_
 
-func (p *NodeAggregateTilesResult) GetSuccess() *AggregateTilesResult_ {
+func (p *NodeSetPersistRateLimitResult) GetSuccess() *NodePersistRateLimitResult_ {
 	if !p.IsSetSuccess() {
-		return NodeAggregateTilesResult_Success_DEFAULT
+		return NodeSetPersistRateLimitResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeAggregateTilesResult_Err_DEFAULT *Error
+var NodeSetPersistRateLimitResult_Err_DEFAULT *Error
 
-func (p *NodeAggregateTilesResult) GetErr() *Error {
+func (p *NodeSetPersistRateLimitResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeAggregateTilesResult_Err_DEFAULT
+		return NodeSetPersistRateLimitResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeAggregateTilesResult) IsSetSuccess() bool {
+func (p *NodeSetPersistRateLimitResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeAggregateTilesResult) IsSetErr() bool {
+func (p *NodeSetPersistRateLimitResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeAggregateTilesResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeSetPersistRateLimitResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23508,15 +27957,15 @@ func (p *NodeAggregateTilesResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateTilesResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &AggregateTilesResult_{}
+func (p *NodeSetPersistRateLimitResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodePersistRateLimitResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeAggregateTilesResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeSetPersistRateLimitResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -23526,8 +27975,8 @@ func (p *NodeAggregateTilesResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateTilesResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("aggregateTiles_result"); err != nil {
+func (p *NodeSetPersistRateLimitResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setPersistRateLimit_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -23547,7 +27996,7 @@ func (p *NodeAggregateTilesResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeAggregateTilesResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetPersistRateLimitResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -23562,7 +28011,7 @@ func (p *NodeAggregateTilesResult) writeField0(oprot thrift.TProtocol) (err erro
 	return err
 }
 
-func (p *NodeAggregateTilesResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetPersistRateLimitResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -23577,21 +28026,21 @@ func (p *NodeAggregateTilesResult) writeField1(oprot thrift.TProtocol) (err erro
 	return err
 }
 
-func (p *NodeAggregateTilesResult) String() string {
+func (p *NodeSetPersistRateLimitResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeAggregateTilesResult(%+v)", *p)
+	return fmt.Sprintf("NodeSetPersistRateLimitResult(%+v)", *p)
 }
 
-type NodeHealthArgs struct {
+type NodeGetWriteNewSeriesAsyncArgs struct {
 }
 
-func NewNodeHealthArgs() *NodeHealthArgs {
-	return &NodeHealthArgs{}
+func NewNodeGetWriteNewSeriesAsyncArgs() *NodeGetWriteNewSeriesAsyncArgs {
+	return &NodeGetWriteNewSeriesAsyncArgs{}
 }
 
-func (p *NodeHealthArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23617,8 +28066,8 @@ func (p *NodeHealthArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeHealthArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("health_args"); err != nil {
+func (p *NodeGetWriteNewSeriesAsyncArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getWriteNewSeriesAsync_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -23632,51 +28081,51 @@ func (p *NodeHealthArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeHealthArgs) String() string {
+func (p *NodeGetWriteNewSeriesAsyncArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeHealthArgs(%+v)", *p)
+	return fmt.Sprintf("NodeGetWriteNewSeriesAsyncArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeHealthResult struct {
-	Success *NodeHealthResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error             `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeGetWriteNewSeriesAsyncResult struct {
+	Success *NodeWriteNewSeriesAsyncResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                          `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeHealthResult() *NodeHealthResult {
-	return &NodeHealthResult{}
+func NewNodeGetWriteNewSeriesAsyncResult() *NodeGetWriteNewSeriesAsyncResult {
+	return &NodeGetWriteNewSeriesAsyncResult{}
 }
 
-var NodeHealthResult_Success_DEFAULT *NodeHealthResult_
+var NodeGetWriteNewSeriesAsyncResult_Success_DEFAULT *NodeWriteNewSeriesAsyncResult_
 
-func (p *NodeHealthResult) GetSuccess() *NodeHealthResult_ {
+func (p *NodeGetWriteNewSeriesAsyncResult) GetSuccess() *NodeWriteNewSeriesAsyncResult_ {
 	if !p.IsSetSuccess() {
-		return NodeHealthResult_Success_DEFAULT
+		return NodeGetWriteNewSeriesAsyncResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeHealthResult_Err_DEFAULT *Error
+var NodeGetWriteNewSeriesAsyncResult_Err_DEFAULT *Error
 
-func (p *NodeHealthResult) GetErr() *Error {
+func (p *NodeGetWriteNewSeriesAsyncResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeHealthResult_Err_DEFAULT
+		return NodeGetWriteNewSeriesAsyncResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeHealthResult) IsSetSuccess() bool {
+func (p *NodeGetWriteNewSeriesAsyncResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeHealthResult) IsSetErr() bool {
+func (p *NodeGetWriteNewSeriesAsyncResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeHealthResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesAsyncResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23713,15 +28162,15 @@ func (p *NodeHealthResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeHealthResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeHealthResult_{}
+func (p *NodeGetWriteNewSeriesAsyncResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeWriteNewSeriesAsyncResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeHealthResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesAsyncResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -23731,8 +28180,8 @@ func (p *NodeHealthResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeHealthResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("health_result"); err != nil {
+func (p *NodeGetWriteNewSeriesAsyncResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getWriteNewSeriesAsync_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -23752,7 +28201,7 @@ func (p *NodeHealthResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeHealthResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetWriteNewSeriesAsyncResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -23767,7 +28216,7 @@ func (p *NodeHealthResult) writeField0(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeHealthResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetWriteNewSeriesAsyncResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -23782,21 +28231,36 @@ func (p *NodeHealthResult) writeField1(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
-func (p *NodeHealthResult) String() string {
+func (p *NodeGetWriteNewSeriesAsyncResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeHealthResult(%+v)", *p)
+	return fmt.Sprintf("NodeGetWriteNewSeriesAsyncResult(%+v)", *p)
 }
 
-type NodeBootstrappedArgs struct {
+// Attributes:
+//  - Req
+type NodeSetWriteNewSeriesAsyncArgs struct {
+	Req *NodeSetWriteNewSeriesAsyncRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeBootstrappedArgs() *NodeBootstrappedArgs {
-	return &NodeBootstrappedArgs{}
+func NewNodeSetWriteNewSeriesAsyncArgs() *NodeSetWriteNewSeriesAsyncArgs {
+	return &NodeSetWriteNewSeriesAsyncArgs{}
 }
 
-func (p *NodeBootstrappedArgs) Read(iprot thrift.TProtocol) error {
+var NodeSetWriteNewSeriesAsyncArgs_Req_DEFAULT *NodeSetWriteNewSeriesAsyncRequest
+
+func (p *NodeSetWriteNewSeriesAsyncArgs) GetReq() *NodeSetWriteNewSeriesAsyncRequest {
+	if !p.IsSetReq() {
+		return NodeSetWriteNewSeriesAsyncArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeSetWriteNewSeriesAsyncArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeSetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23809,8 +28273,15 @@ func (p *NodeBootstrappedArgs) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		if err := iprot.Skip(fieldTypeId); err != nil {
-			return err
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -23822,11 +28293,22 @@ func (p *NodeBootstrappedArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeBootstrappedArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("bootstrapped_args"); err != nil {
+func (p *NodeSetWriteNewSeriesAsyncArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &NodeSetWriteNewSeriesAsyncRequest{}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeSetWriteNewSeriesAsyncArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setWriteNewSeriesAsync_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -23837,51 +28319,64 @@ func (p *NodeBootstrappedArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeBootstrappedArgs) String() string {
+func (p *NodeSetWriteNewSeriesAsyncArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeSetWriteNewSeriesAsyncArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeBootstrappedArgs(%+v)", *p)
+	return fmt.Sprintf("NodeSetWriteNewSeriesAsyncArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeBootstrappedResult struct {
-	Success *NodeBootstrappedResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                   `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeSetWriteNewSeriesAsyncResult struct {
+	Success *NodeWriteNewSeriesAsyncResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                          `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeBootstrappedResult() *NodeBootstrappedResult {
-	return &NodeBootstrappedResult{}
+func NewNodeSetWriteNewSeriesAsyncResult() *NodeSetWriteNewSeriesAsyncResult {
+	return &NodeSetWriteNewSeriesAsyncResult{}
 }
 
-var NodeBootstrappedResult_Success_DEFAULT *NodeBootstrappedResult_
+var NodeSetWriteNewSeriesAsyncResult_Success_DEFAULT *NodeWriteNewSeriesAsyncResult_
 
-func (p *NodeBootstrappedResult) GetSuccess() *NodeBootstrappedResult_ {
+func (p *NodeSetWriteNewSeriesAsyncResult) GetSuccess() *NodeWriteNewSeriesAsyncResult_ {
 	if !p.IsSetSuccess() {
-		return NodeBootstrappedResult_Success_DEFAULT
+		return NodeSetWriteNewSeriesAsyncResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeBootstrappedResult_Err_DEFAULT *Error
+var NodeSetWriteNewSeriesAsyncResult_Err_DEFAULT *Error
 
-func (p *NodeBootstrappedResult) GetErr() *Error {
+func (p *NodeSetWriteNewSeriesAsyncResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeBootstrappedResult_Err_DEFAULT
+		return NodeSetWriteNewSeriesAsyncResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeBootstrappedResult) IsSetSuccess() bool {
+func (p *NodeSetWriteNewSeriesAsyncResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeBootstrappedResult) IsSetErr() bool {
+func (p *NodeSetWriteNewSeriesAsyncResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeBootstrappedResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeSetWriteNewSeriesAsyncResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -23918,15 +28413,15 @@ func (p *NodeBootstrappedResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeBootstrappedResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeBootstrappedResult_{}
+func (p *NodeSetWriteNewSeriesAsyncResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeWriteNewSeriesAsyncResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeBootstrappedResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeSetWriteNewSeriesAsyncResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -23936,8 +28431,8 @@ func (p *NodeBootstrappedResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeBootstrappedResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("bootstrapped_result"); err != nil {
+func (p *NodeSetWriteNewSeriesAsyncResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setWriteNewSeriesAsync_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -23957,7 +28452,7 @@ func (p *NodeBootstrappedResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeBootstrappedResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetWriteNewSeriesAsyncResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -23972,7 +28467,7 @@ func (p *NodeBootstrappedResult) writeField0(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeBootstrappedResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetWriteNewSeriesAsyncResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -23987,21 +28482,21 @@ func (p *NodeBootstrappedResult) writeField1(oprot thrift.TProtocol) (err error)
 	return err
 }
 
-func (p *NodeBootstrappedResult) String() string {
+func (p *NodeSetWriteNewSeriesAsyncResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeBootstrappedResult(%+v)", *p)
+	return fmt.Sprintf("NodeSetWriteNewSeriesAsyncResult(%+v)", *p)
 }
 
-type NodeBootstrappedInPlacementOrNoPlacementArgs struct {
+type NodeGetWriteNewSeriesBackoffDurationArgs struct {
 }
 
-func NewNodeBootstrappedInPlacementOrNoPlacementArgs() *NodeBootstrappedInPlacementOrNoPlacementArgs {
-	return &NodeBootstrappedInPlacementOrNoPlacementArgs{}
+func NewNodeGetWriteNewSeriesBackoffDurationArgs() *NodeGetWriteNewSeriesBackoffDurationArgs {
+	return &NodeGetWriteNewSeriesBackoffDurationArgs{}
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24027,8 +28522,8 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) Read(iprot thrift.TProtoc
 	return nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("bootstrappedInPlacementOrNoPlacement_args"); err != nil {
+func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getWriteNewSeriesBackoffDuration_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -24042,51 +28537,51 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) Write(oprot thrift.TProto
 	return nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementArgs) String() string {
+func (p *NodeGetWriteNewSeriesBackoffDurationArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeBootstrappedInPlacementOrNoPlacementArgs(%+v)", *p)
+	return fmt.Sprintf("NodeGetWriteNewSeriesBackoffDurationArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeBootstrappedInPlacementOrNoPlacementResult struct {
-	Success *NodeBootstrappedInPlacementOrNoPlacementResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                                           `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeGetWriteNewSeriesBackoffDurationResult struct {
+	Success *NodeWriteNewSeriesBackoffDurationResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                                    `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeBootstrappedInPlacementOrNoPlacementResult() *NodeBootstrappedInPlacementOrNoPlacementResult {
-	return &NodeBootstrappedInPlacementOrNoPlacementResult{}
+func NewNodeGetWriteNewSeriesBackoffDurationResult() *NodeGetWriteNewSeriesBackoffDurationResult {
+	return &NodeGetWriteNewSeriesBackoffDurationResult{}
 }
 
-var NodeBootstrappedInPlacementOrNoPlacementResult_Success_DEFAULT *NodeBootstrappedInPlacementOrNoPlacementResult_
+var NodeGetWriteNewSeriesBackoffDurationResult_Success_DEFAULT *NodeWriteNewSeriesBackoffDurationResult_
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) GetSuccess() *NodeBootstrappedInPlacementOrNoPlacementResult_ {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) GetSuccess() *NodeWriteNewSeriesBackoffDurationResult_ {
 	if !p.IsSetSuccess() {
-		return NodeBootstrappedInPlacementOrNoPlacementResult_Success_DEFAULT
+		return NodeGetWriteNewSeriesBackoffDurationResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeBootstrappedInPlacementOrNoPlacementResult_Err_DEFAULT *Error
+var NodeGetWriteNewSeriesBackoffDurationResult_Err_DEFAULT *Error
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) GetErr() *Error {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeBootstrappedInPlacementOrNoPlacementResult_Err_DEFAULT
+		return NodeGetWriteNewSeriesBackoffDurationResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) IsSetSuccess() bool {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) IsSetErr() bool {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24123,15 +28618,15 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementResult) Read(iprot thrift.TProt
 	return nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeBootstrappedInPlacementOrNoPlacementResult_{}
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeWriteNewSeriesBackoffDurationResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -24141,8 +28636,8 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementResult) ReadField1(iprot thrift
 	return nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("bootstrappedInPlacementOrNoPlacement_result"); err != nil {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getWriteNewSeriesBackoffDuration_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -24162,7 +28657,7 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementResult) Write(oprot thrift.TPro
 	return nil
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -24177,7 +28672,7 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementResult) writeField0(oprot thrif
 	return err
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -24192,21 +28687,36 @@ func (p *NodeBootstrappedInPlacementOrNoPlacementResult) writeField1(oprot thrif
 	return err
 }
 
-func (p *NodeBootstrappedInPlacementOrNoPlacementResult) String() string {
+func (p *NodeGetWriteNewSeriesBackoffDurationResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeBootstrappedInPlacementOrNoPlacementResult(%+v)", *p)
+	return fmt.Sprintf("NodeGetWriteNewSeriesBackoffDurationResult(%+v)", *p)
 }
 
-type NodeGetPersistRateLimitArgs struct {
+// Attributes:
+//  - Req
+type NodeSetWriteNewSeriesBackoffDurationArgs struct {
+	Req *NodeSetWriteNewSeriesBackoffDurationRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeGetPersistRateLimitArgs() *NodeGetPersistRateLimitArgs {
-	return &NodeGetPersistRateLimitArgs{}
+func NewNodeSetWriteNewSeriesBackoffDurationArgs() *NodeSetWriteNewSeriesBackoffDurationArgs {
+	return &NodeSetWriteNewSeriesBackoffDurationArgs{}
 }
 
-func (p *NodeGetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
+var NodeSetWriteNewSeriesBackoffDurationArgs_Req_DEFAULT *NodeSetWriteNewSeriesBackoffDurationRequest
+
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) GetReq() *NodeSetWriteNewSeriesBackoffDurationRequest {
+	if !p.IsSetReq() {
+		return NodeSetWriteNewSeriesBackoffDurationArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24219,8 +28729,15 @@ func (p *NodeGetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		if err := iprot.Skip(fieldTypeId); err != nil {
-			return err
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -24232,11 +28749,24 @@ func (p *NodeGetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetPersistRateLimitArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getPersistRateLimit_args"); err != nil {
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &NodeSetWriteNewSeriesBackoffDurationRequest{
+		DurationType: 2,
+	}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setWriteNewSeriesBackoffDuration_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -24247,51 +28777,64 @@ func (p *NodeGetPersistRateLimitArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetPersistRateLimitArgs) String() string {
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeSetWriteNewSeriesBackoffDurationArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetPersistRateLimitArgs(%+v)", *p)
+	return fmt.Sprintf("NodeSetWriteNewSeriesBackoffDurationArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeGetPersistRateLimitResult struct {
-	Success *NodePersistRateLimitResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                       `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeSetWriteNewSeriesBackoffDurationResult struct {
+	Success *NodeWriteNewSeriesBackoffDurationResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                                    `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeGetPersistRateLimitResult() *NodeGetPersistRateLimitResult {
-	return &NodeGetPersistRateLimitResult{}
+func NewNodeSetWriteNewSeriesBackoffDurationResult() *NodeSetWriteNewSeriesBackoffDurationResult {
+	return &NodeSetWriteNewSeriesBackoffDurationResult{}
 }
 
-var NodeGetPersistRateLimitResult_Success_DEFAULT *NodePersistRateLimitResult_
+var NodeSetWriteNewSeriesBackoffDurationResult_Success_DEFAULT *NodeWriteNewSeriesBackoffDurationResult_
 
-func (p *NodeGetPersistRateLimitResult) GetSuccess() *NodePersistRateLimitResult_ {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) GetSuccess() *NodeWriteNewSeriesBackoffDurationResult_ {
 	if !p.IsSetSuccess() {
-		return NodeGetPersistRateLimitResult_Success_DEFAULT
+		return NodeSetWriteNewSeriesBackoffDurationResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeGetPersistRateLimitResult_Err_DEFAULT *Error
+var NodeSetWriteNewSeriesBackoffDurationResult_Err_DEFAULT *Error
 
-func (p *NodeGetPersistRateLimitResult) GetErr() *Error {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeGetPersistRateLimitResult_Err_DEFAULT
+		return NodeSetWriteNewSeriesBackoffDurationResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeGetPersistRateLimitResult) IsSetSuccess() bool {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeGetPersistRateLimitResult) IsSetErr() bool {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeGetPersistRateLimitResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24328,15 +28871,15 @@ func (p *NodeGetPersistRateLimitResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetPersistRateLimitResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodePersistRateLimitResult_{}
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeWriteNewSeriesBackoffDurationResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeGetPersistRateLimitResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -24346,8 +28889,8 @@ func (p *NodeGetPersistRateLimitResult) ReadField1(iprot thrift.TProtocol) error
 	return nil
 }
 
-func (p *NodeGetPersistRateLimitResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getPersistRateLimit_result"); err != nil {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setWriteNewSeriesBackoffDuration_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -24367,7 +28910,7 @@ func (p *NodeGetPersistRateLimitResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetPersistRateLimitResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -24382,7 +28925,7 @@ func (p *NodeGetPersistRateLimitResult) writeField0(oprot thrift.TProtocol) (err
 	return err
 }
 
-func (p *NodeGetPersistRateLimitResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -24397,36 +28940,21 @@ func (p *NodeGetPersistRateLimitResult) writeField1(oprot thrift.TProtocol) (err
 	return err
 }
 
-func (p *NodeGetPersistRateLimitResult) String() string {
+func (p *NodeSetWriteNewSeriesBackoffDurationResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetPersistRateLimitResult(%+v)", *p)
-}
-
-// Attributes:
-//  - Req
-type NodeSetPersistRateLimitArgs struct {
-	Req *NodeSetPersistRateLimitRequest `thrift:"req,1" db:"req" json:"req"`
+	return fmt.Sprintf("NodeSetWriteNewSeriesBackoffDurationResult(%+v)", *p)
 }
 
-func NewNodeSetPersistRateLimitArgs() *NodeSetPersistRateLimitArgs {
-	return &NodeSetPersistRateLimitArgs{}
+type NodeGetWriteNewSeriesLimitPerShardPerSecondArgs struct {
 }
 
-var NodeSetPersistRateLimitArgs_Req_DEFAULT *NodeSetPersistRateLimitRequest
-
-func (p *NodeSetPersistRateLimitArgs) GetReq() *NodeSetPersistRateLimitRequest {
-	if !p.IsSetReq() {
-		return NodeSetPersistRateLimitArgs_Req_DEFAULT
-	}
-	return p.Req
-}
-func (p *NodeSetPersistRateLimitArgs) IsSetReq() bool {
-	return p.Req != nil
+func NewNodeGetWriteNewSeriesLimitPerShardPerSecondArgs() *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs {
+	return &NodeGetWriteNewSeriesLimitPerShardPerSecondArgs{}
 }
 
-func (p *NodeSetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24439,15 +28967,8 @@ func (p *NodeSetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		switch fieldId {
-		case 1:
-			if err := p.ReadField1(iprot); err != nil {
-				return err
-			}
-		default:
-			if err := iprot.Skip(fieldTypeId); err != nil {
-				return err
-			}
+		if err := iprot.Skip(fieldTypeId); err != nil {
+			return err
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -24459,22 +28980,11 @@ func (p *NodeSetPersistRateLimitArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetPersistRateLimitArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &NodeSetPersistRateLimitRequest{}
-	if err := p.Req.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
-	}
-	return nil
-}
-
-func (p *NodeSetPersistRateLimitArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setPersistRateLimit_args"); err != nil {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getWriteNewSeriesLimitPerShardPerSecond_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField1(oprot); err != nil {
-			return err
-		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -24485,64 +28995,51 @@ func (p *NodeSetPersistRateLimitArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetPersistRateLimitArgs) writeField1(oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
-	}
-	if err := p.Req.Write(oprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
-	}
-	if err := oprot.WriteFieldEnd(); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
-	}
-	return err
-}
-
-func (p *NodeSetPersistRateLimitArgs) String() string {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetPersistRateLimitArgs(%+v)", *p)
+	return fmt.Sprintf("NodeGetWriteNewSeriesLimitPerShardPerSecondArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeSetPersistRateLimitResult struct {
-	Success *NodePersistRateLimitResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                       `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeGetWriteNewSeriesLimitPerShardPerSecondResult struct {
+	Success *NodeWriteNewSeriesLimitPerShardPerSecondResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                                           `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeSetPersistRateLimitResult() *NodeSetPersistRateLimitResult {
-	return &NodeSetPersistRateLimitResult{}
+func NewNodeGetWriteNewSeriesLimitPerShardPerSecondResult() *NodeGetWriteNewSeriesLimitPerShardPerSecondResult {
+	return &NodeGetWriteNewSeriesLimitPerShardPerSecondResult{}
 }
 
-var NodeSetPersistRateLimitResult_Success_DEFAULT *NodePersistRateLimitResult_
+var NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT *NodeWriteNewSeriesLimitPerShardPerSecondResult_
 
-func (p *NodeSetPersistRateLimitResult) GetSuccess() *NodePersistRateLimitResult_ {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) GetSuccess() *NodeWriteNewSeriesLimitPerShardPerSecondResult_ {
 	if !p.IsSetSuccess() {
-		return NodeSetPersistRateLimitResult_Success_DEFAULT
+		return NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeSetPersistRateLimitResult_Err_DEFAULT *Error
+var NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT *Error
 
-func (p *NodeSetPersistRateLimitResult) GetErr() *Error {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeSetPersistRateLimitResult_Err_DEFAULT
+		return NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeSetPersistRateLimitResult) IsSetSuccess() bool {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeSetPersistRateLimitResult) IsSetErr() bool {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeSetPersistRateLimitResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24579,15 +29076,15 @@ func (p *NodeSetPersistRateLimitResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetPersistRateLimitResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodePersistRateLimitResult_{}
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeWriteNewSeriesLimitPerShardPerSecondResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeSetPersistRateLimitResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -24597,8 +29094,8 @@ func (p *NodeSetPersistRateLimitResult) ReadField1(iprot thrift.TProtocol) error
 	return nil
 }
 
-func (p *NodeSetPersistRateLimitResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setPersistRateLimit_result"); err != nil {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("getWriteNewSeriesLimitPerShardPerSecond_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -24618,7 +29115,7 @@ func (p *NodeSetPersistRateLimitResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetPersistRateLimitResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -24633,7 +29130,7 @@ func (p *NodeSetPersistRateLimitResult) writeField0(oprot thrift.TProtocol) (err
 	return err
 }
 
-func (p *NodeSetPersistRateLimitResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -24648,21 +29145,36 @@ func (p *NodeSetPersistRateLimitResult) writeField1(oprot thrift.TProtocol) (err
 	return err
 }
 
-func (p *NodeSetPersistRateLimitResult) String() string {
+func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetPersistRateLimitResult(%+v)", *p)
+	return fmt.Sprintf("NodeGetWriteNewSeriesLimitPerShardPerSecondResult(%+v)", *p)
 }
 
-type NodeGetWriteNewSeriesAsyncArgs struct {
+// Attributes:
+//  - Req
+type NodeSetWriteNewSeriesLimitPerShardPerSecondArgs struct {
+	Req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeGetWriteNewSeriesAsyncArgs() *NodeGetWriteNewSeriesAsyncArgs {
-	return &NodeGetWriteNewSeriesAsyncArgs{}
+func NewNodeSetWriteNewSeriesLimitPerShardPerSecondArgs() *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs {
+	return &NodeSetWriteNewSeriesLimitPerShardPerSecondArgs{}
 }
 
-func (p *NodeGetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
+var NodeSetWriteNewSeriesLimitPerShardPerSecondArgs_Req_DEFAULT *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest
+
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) GetReq() *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest {
+	if !p.IsSetReq() {
+		return NodeSetWriteNewSeriesLimitPerShardPerSecondArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24675,8 +29187,15 @@ func (p *NodeGetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		if err := iprot.Skip(fieldTypeId); err != nil {
-			return err
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -24688,11 +29207,22 @@ func (p *NodeGetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getWriteNewSeriesAsync_args"); err != nil {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &NodeSetWriteNewSeriesLimitPerShardPerSecondRequest{}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setWriteNewSeriesLimitPerShardPerSecond_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -24703,51 +29233,64 @@ func (p *NodeGetWriteNewSeriesAsyncArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncArgs) String() string {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetWriteNewSeriesAsyncArgs(%+v)", *p)
+	return fmt.Sprintf("NodeSetWriteNewSeriesLimitPerShardPerSecondArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeGetWriteNewSeriesAsyncResult struct {
-	Success *NodeWriteNewSeriesAsyncResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                          `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeSetWriteNewSeriesLimitPerShardPerSecondResult struct {
+	Success *NodeWriteNewSeriesLimitPerShardPerSecondResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                                           `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeGetWriteNewSeriesAsyncResult() *NodeGetWriteNewSeriesAsyncResult {
-	return &NodeGetWriteNewSeriesAsyncResult{}
+func NewNodeSetWriteNewSeriesLimitPerShardPerSecondResult() *NodeSetWriteNewSeriesLimitPerShardPerSecondResult {
+	return &NodeSetWriteNewSeriesLimitPerShardPerSecondResult{}
 }
 
-var NodeGetWriteNewSeriesAsyncResult_Success_DEFAULT *NodeWriteNewSeriesAsyncResult_
+var NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT *NodeWriteNewSeriesLimitPerShardPerSecondResult_
 
-func (p *NodeGetWriteNewSeriesAsyncResult) GetSuccess() *NodeWriteNewSeriesAsyncResult_ {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) GetSuccess() *NodeWriteNewSeriesLimitPerShardPerSecondResult_ {
 	if !p.IsSetSuccess() {
-		return NodeGetWriteNewSeriesAsyncResult_Success_DEFAULT
+		return NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeGetWriteNewSeriesAsyncResult_Err_DEFAULT *Error
+var NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT *Error
 
-func (p *NodeGetWriteNewSeriesAsyncResult) GetErr() *Error {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeGetWriteNewSeriesAsyncResult_Err_DEFAULT
+		return NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeGetWriteNewSeriesAsyncResult) IsSetSuccess() bool {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) IsSetErr() bool {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24784,15 +29327,15 @@ func (p *NodeGetWriteNewSeriesAsyncResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeWriteNewSeriesAsyncResult_{}
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &NodeWriteNewSeriesLimitPerShardPerSecondResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -24802,8 +29345,8 @@ func (p *NodeGetWriteNewSeriesAsyncResult) ReadField1(iprot thrift.TProtocol) er
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getWriteNewSeriesAsync_result"); err != nil {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("setWriteNewSeriesLimitPerShardPerSecond_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -24823,7 +29366,7 @@ func (p *NodeGetWriteNewSeriesAsyncResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -24838,7 +29381,7 @@ func (p *NodeGetWriteNewSeriesAsyncResult) writeField0(oprot thrift.TProtocol) (
 	return err
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -24853,36 +29396,36 @@ func (p *NodeGetWriteNewSeriesAsyncResult) writeField1(oprot thrift.TProtocol) (
 	return err
 }
 
-func (p *NodeGetWriteNewSeriesAsyncResult) String() string {
+func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetWriteNewSeriesAsyncResult(%+v)", *p)
+	return fmt.Sprintf("NodeSetWriteNewSeriesLimitPerShardPerSecondResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeSetWriteNewSeriesAsyncArgs struct {
-	Req *NodeSetWriteNewSeriesAsyncRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeDebugProfileStartArgs struct {
+	Req *DebugProfileStartRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeSetWriteNewSeriesAsyncArgs() *NodeSetWriteNewSeriesAsyncArgs {
-	return &NodeSetWriteNewSeriesAsyncArgs{}
+func NewNodeDebugProfileStartArgs() *NodeDebugProfileStartArgs {
+	return &NodeDebugProfileStartArgs{}
 }
 
-var NodeSetWriteNewSeriesAsyncArgs_Req_DEFAULT *NodeSetWriteNewSeriesAsyncRequest
+var NodeDebugProfileStartArgs_Req_DEFAULT *DebugProfileStartRequest
 
-func (p *NodeSetWriteNewSeriesAsyncArgs) GetReq() *NodeSetWriteNewSeriesAsyncRequest {
+func (p *NodeDebugProfileStartArgs) GetReq() *DebugProfileStartRequest {
 	if !p.IsSetReq() {
-		return NodeSetWriteNewSeriesAsyncArgs_Req_DEFAULT
+		return NodeDebugProfileStartArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeSetWriteNewSeriesAsyncArgs) IsSetReq() bool {
+func (p *NodeDebugProfileStartArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeDebugProfileStartArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -24915,16 +29458,16 @@ func (p *NodeSetWriteNewSeriesAsyncArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &NodeSetWriteNewSeriesAsyncRequest{}
+func (p *NodeDebugProfileStartArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &DebugProfileStartRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setWriteNewSeriesAsync_args"); err != nil {
+func (p *NodeDebugProfileStartArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("debugProfileStart_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -24941,7 +29484,7 @@ func (p *NodeSetWriteNewSeriesAsyncArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugProfileStartArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -24954,51 +29497,51 @@ func (p *NodeSetWriteNewSeriesAsyncArgs) writeField1(oprot thrift.TProtocol) (er
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesAsyncArgs) String() string {
+func (p *NodeDebugProfileStartArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetWriteNewSeriesAsyncArgs(%+v)", *p)
+	return fmt.Sprintf("NodeDebugProfileStartArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeSetWriteNewSeriesAsyncResult struct {
-	Success *NodeWriteNewSeriesAsyncResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                          `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeDebugProfileStartResult struct {
+	Success *DebugProfileStartResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                    `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeSetWriteNewSeriesAsyncResult() *NodeSetWriteNewSeriesAsyncResult {
-	return &NodeSetWriteNewSeriesAsyncResult{}
+func NewNodeDebugProfileStartResult() *NodeDebugProfileStartResult {
+	return &NodeDebugProfileStartResult{}
 }
 
-var NodeSetWriteNewSeriesAsyncResult_Success_DEFAULT *NodeWriteNewSeriesAsyncResult_
+var NodeDebugProfileStartResult_Success_DEFAULT *DebugProfileStartResult_
 
-func (p *NodeSetWriteNewSeriesAsyncResult) GetSuccess() *NodeWriteNewSeriesAsyncResult_ {
+func (p *NodeDebugProfileStartResult) GetSuccess() *DebugProfileStartResult_ {
 	if !p.IsSetSuccess() {
-		return NodeSetWriteNewSeriesAsyncResult_Success_DEFAULT
+		return NodeDebugProfileStartResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeSetWriteNewSeriesAsyncResult_Err_DEFAULT *Error
+var NodeDebugProfileStartResult_Err_DEFAULT *Error
 
-func (p *NodeSetWriteNewSeriesAsyncResult) GetErr() *Error {
+func (p *NodeDebugProfileStartResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeSetWriteNewSeriesAsyncResult_Err_DEFAULT
+		return NodeDebugProfileStartResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeSetWriteNewSeriesAsyncResult) IsSetSuccess() bool {
+func (p *NodeDebugProfileStartResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) IsSetErr() bool {
+func (p *NodeDebugProfileStartResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeDebugProfileStartResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25035,15 +29578,15 @@ func (p *NodeSetWriteNewSeriesAsyncResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeWriteNewSeriesAsyncResult_{}
+func (p *NodeDebugProfileStartResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &DebugProfileStartResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeDebugProfileStartResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -25053,8 +29596,8 @@ func (p *NodeSetWriteNewSeriesAsyncResult) ReadField1(iprot thrift.TProtocol) er
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setWriteNewSeriesAsync_result"); err != nil {
+func (p *NodeDebugProfileStartResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("debugProfileStart_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -25074,7 +29617,7 @@ func (p *NodeSetWriteNewSeriesAsyncResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugProfileStartResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -25089,7 +29632,7 @@ func (p *NodeSetWriteNewSeriesAsyncResult) writeField0(oprot thrift.TProtocol) (
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugProfileStartResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -25104,21 +29647,36 @@ func (p *NodeSetWriteNewSeriesAsyncResult) writeField1(oprot thrift.TProtocol) (
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesAsyncResult) String() string {
+func (p *NodeDebugProfileStartResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetWriteNewSeriesAsyncResult(%+v)", *p)
+	return fmt.Sprintf("NodeDebugProfileStartResult(%+v)", *p)
 }
 
-type NodeGetWriteNewSeriesBackoffDurationArgs struct {
+// Attributes:
+//  - Req
+type NodeDebugProfileStopArgs struct {
+	Req *DebugProfileStopRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeGetWriteNewSeriesBackoffDurationArgs() *NodeGetWriteNewSeriesBackoffDurationArgs {
-	return &NodeGetWriteNewSeriesBackoffDurationArgs{}
+func NewNodeDebugProfileStopArgs() *NodeDebugProfileStopArgs {
+	return &NodeDebugProfileStopArgs{}
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol) error {
+var NodeDebugProfileStopArgs_Req_DEFAULT *DebugProfileStopRequest
+
+func (p *NodeDebugProfileStopArgs) GetReq() *DebugProfileStopRequest {
+	if !p.IsSetReq() {
+		return NodeDebugProfileStopArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeDebugProfileStopArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeDebugProfileStopArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25131,8 +29689,15 @@ func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol)
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		if err := iprot.Skip(fieldTypeId); err != nil {
-			return err
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -25144,11 +29709,22 @@ func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol)
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getWriteNewSeriesBackoffDuration_args"); err != nil {
+func (p *NodeDebugProfileStopArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &DebugProfileStopRequest{}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeDebugProfileStopArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("debugProfileStop_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -25159,51 +29735,64 @@ func (p *NodeGetWriteNewSeriesBackoffDurationArgs) Write(oprot thrift.TProtocol)
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationArgs) String() string {
+func (p *NodeDebugProfileStopArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeDebugProfileStopArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetWriteNewSeriesBackoffDurationArgs(%+v)", *p)
+	return fmt.Sprintf("NodeDebugProfileStopArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeGetWriteNewSeriesBackoffDurationResult struct {
-	Success *NodeWriteNewSeriesBackoffDurationResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                                    `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeDebugProfileStopResult struct {
+	Success *DebugProfileStopResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                   `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeGetWriteNewSeriesBackoffDurationResult() *NodeGetWriteNewSeriesBackoffDurationResult {
-	return &NodeGetWriteNewSeriesBackoffDurationResult{}
+func NewNodeDebugProfileStopResult() *NodeDebugProfileStopResult {
+	return &NodeDebugProfileStopResult{}
 }
 
-var NodeGetWriteNewSeriesBackoffDurationResult_Success_DEFAULT *NodeWriteNewSeriesBackoffDurationResult_
+var NodeDebugProfileStopResult_Success_DEFAULT *DebugProfileStopResult_
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) GetSuccess() *NodeWriteNewSeriesBackoffDurationResult_ {
+func (p *NodeDebugProfileStopResult) GetSuccess() *DebugProfileStopResult_ {
 	if !p.IsSetSuccess() {
-		return NodeGetWriteNewSeriesBackoffDurationResult_Success_DEFAULT
+		return NodeDebugProfileStopResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeGetWriteNewSeriesBackoffDurationResult_Err_DEFAULT *Error
+var NodeDebugProfileStopResult_Err_DEFAULT *Error
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) GetErr() *Error {
+func (p *NodeDebugProfileStopResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeGetWriteNewSeriesBackoffDurationResult_Err_DEFAULT
+		return NodeDebugProfileStopResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) IsSetSuccess() bool {
+func (p *NodeDebugProfileStopResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) IsSetErr() bool {
+func (p *NodeDebugProfileStopResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeDebugProfileStopResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25240,15 +29829,15 @@ func (p *NodeGetWriteNewSeriesBackoffDurationResult) Read(iprot thrift.TProtocol
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeWriteNewSeriesBackoffDurationResult_{}
+func (p *NodeDebugProfileStopResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &DebugProfileStopResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeDebugProfileStopResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -25258,8 +29847,8 @@ func (p *NodeGetWriteNewSeriesBackoffDurationResult) ReadField1(iprot thrift.TPr
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getWriteNewSeriesBackoffDuration_result"); err != nil {
+func (p *NodeDebugProfileStopResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("debugProfileStop_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -25279,7 +29868,7 @@ func (p *NodeGetWriteNewSeriesBackoffDurationResult) Write(oprot thrift.TProtoco
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugProfileStopResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -25294,7 +29883,7 @@ func (p *NodeGetWriteNewSeriesBackoffDurationResult) writeField0(oprot thrift.TP
 	return err
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugProfileStopResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -25309,36 +29898,36 @@ func (p *NodeGetWriteNewSeriesBackoffDurationResult) writeField1(oprot thrift.TP
 	return err
 }
 
-func (p *NodeGetWriteNewSeriesBackoffDurationResult) String() string {
+func (p *NodeDebugProfileStopResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetWriteNewSeriesBackoffDurationResult(%+v)", *p)
+	return fmt.Sprintf("NodeDebugProfileStopResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeSetWriteNewSeriesBackoffDurationArgs struct {
-	Req *NodeSetWriteNewSeriesBackoffDurationRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeDebugIndexMemorySegmentsArgs struct {
+	Req *DebugIndexMemorySegmentsRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeSetWriteNewSeriesBackoffDurationArgs() *NodeSetWriteNewSeriesBackoffDurationArgs {
-	return &NodeSetWriteNewSeriesBackoffDurationArgs{}
+func NewNodeDebugIndexMemorySegmentsArgs() *NodeDebugIndexMemorySegmentsArgs {
+	return &NodeDebugIndexMemorySegmentsArgs{}
 }
 
-var NodeSetWriteNewSeriesBackoffDurationArgs_Req_DEFAULT *NodeSetWriteNewSeriesBackoffDurationRequest
+var NodeDebugIndexMemorySegmentsArgs_Req_DEFAULT *DebugIndexMemorySegmentsRequest
 
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) GetReq() *NodeSetWriteNewSeriesBackoffDurationRequest {
+func (p *NodeDebugIndexMemorySegmentsArgs) GetReq() *DebugIndexMemorySegmentsRequest {
 	if !p.IsSetReq() {
-		return NodeSetWriteNewSeriesBackoffDurationArgs_Req_DEFAULT
+		return NodeDebugIndexMemorySegmentsArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) IsSetReq() bool {
+func (p *NodeDebugIndexMemorySegmentsArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeDebugIndexMemorySegmentsArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25371,18 +29960,16 @@ func (p *NodeSetWriteNewSeriesBackoffDurationArgs) Read(iprot thrift.TProtocol)
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &NodeSetWriteNewSeriesBackoffDurationRequest{
-		DurationType: 2,
-	}
+func (p *NodeDebugIndexMemorySegmentsArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &DebugIndexMemorySegmentsRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setWriteNewSeriesBackoffDuration_args"); err != nil {
+func (p *NodeDebugIndexMemorySegmentsArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("debugIndexMemorySegments_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -25399,7 +29986,7 @@ func (p *NodeSetWriteNewSeriesBackoffDurationArgs) Write(oprot thrift.TProtocol)
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugIndexMemorySegmentsArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -25412,51 +29999,51 @@ func (p *NodeSetWriteNewSeriesBackoffDurationArgs) writeField1(oprot thrift.TPro
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationArgs) String() string {
+func (p *NodeDebugIndexMemorySegmentsArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetWriteNewSeriesBackoffDurationArgs(%+v)", *p)
+	return fmt.Sprintf("NodeDebugIndexMemorySegmentsArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeSetWriteNewSeriesBackoffDurationResult struct {
-	Success *NodeWriteNewSeriesBackoffDurationResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                                    `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeDebugIndexMemorySegmentsResult struct {
+	Success *DebugIndexMemorySegmentsResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                           `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeSetWriteNewSeriesBackoffDurationResult() *NodeSetWriteNewSeriesBackoffDurationResult {
-	return &NodeSetWriteNewSeriesBackoffDurationResult{}
+func NewNodeDebugIndexMemorySegmentsResult() *NodeDebugIndexMemorySegmentsResult {
+	return &NodeDebugIndexMemorySegmentsResult{}
 }
 
-var NodeSetWriteNewSeriesBackoffDurationResult_Success_DEFAULT *NodeWriteNewSeriesBackoffDurationResult_
+var NodeDebugIndexMemorySegmentsResult_Success_DEFAULT *DebugIndexMemorySegmentsResult_
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) GetSuccess() *NodeWriteNewSeriesBackoffDurationResult_ {
+func (p *NodeDebugIndexMemorySegmentsResult) GetSuccess() *DebugIndexMemorySegmentsResult_ {
 	if !p.IsSetSuccess() {
-		return NodeSetWriteNewSeriesBackoffDurationResult_Success_DEFAULT
+		return NodeDebugIndexMemorySegmentsResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeSetWriteNewSeriesBackoffDurationResult_Err_DEFAULT *Error
+var NodeDebugIndexMemorySegmentsResult_Err_DEFAULT *Error
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) GetErr() *Error {
+func (p *NodeDebugIndexMemorySegmentsResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeSetWriteNewSeriesBackoffDurationResult_Err_DEFAULT
+		return NodeDebugIndexMemorySegmentsResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) IsSetSuccess() bool {
+func (p *NodeDebugIndexMemorySegmentsResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) IsSetErr() bool {
+func (p *NodeDebugIndexMemorySegmentsResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeDebugIndexMemorySegmentsResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25493,15 +30080,15 @@ func (p *NodeSetWriteNewSeriesBackoffDurationResult) Read(iprot thrift.TProtocol
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeWriteNewSeriesBackoffDurationResult_{}
+func (p *NodeDebugIndexMemorySegmentsResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &DebugIndexMemorySegmentsResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeDebugIndexMemorySegmentsResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -25511,8 +30098,8 @@ func (p *NodeSetWriteNewSeriesBackoffDurationResult) ReadField1(iprot thrift.TPr
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setWriteNewSeriesBackoffDuration_result"); err != nil {
+func (p *NodeDebugIndexMemorySegmentsResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("debugIndexMemorySegments_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -25532,7 +30119,7 @@ func (p *NodeSetWriteNewSeriesBackoffDurationResult) Write(oprot thrift.TProtoco
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugIndexMemorySegmentsResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -25547,7 +30134,7 @@ func (p *NodeSetWriteNewSeriesBackoffDurationResult) writeField0(oprot thrift.TP
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDebugIndexMemorySegmentsResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -25562,21 +30149,36 @@ func (p *NodeSetWriteNewSeriesBackoffDurationResult) writeField1(oprot thrift.TP
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesBackoffDurationResult) String() string {
+func (p *NodeDebugIndexMemorySegmentsResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetWriteNewSeriesBackoffDurationResult(%+v)", *p)
+	return fmt.Sprintf("NodeDebugIndexMemorySegmentsResult(%+v)", *p)
 }
 
-type NodeGetWriteNewSeriesLimitPerShardPerSecondArgs struct {
+// Attributes:
+//  - Req
+type NodeWriteExemplarsArgs struct {
+	Req *WriteExemplarsRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeGetWriteNewSeriesLimitPerShardPerSecondArgs() *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs {
-	return &NodeGetWriteNewSeriesLimitPerShardPerSecondArgs{}
+func NewNodeWriteExemplarsArgs() *NodeWriteExemplarsArgs {
+	return &NodeWriteExemplarsArgs{}
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TProtocol) error {
+var NodeWriteExemplarsArgs_Req_DEFAULT *WriteExemplarsRequest
+
+func (p *NodeWriteExemplarsArgs) GetReq() *WriteExemplarsRequest {
+	if !p.IsSetReq() {
+		return NodeWriteExemplarsArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+func (p *NodeWriteExemplarsArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *NodeWriteExemplarsArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25589,8 +30191,15 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TPro
 		if fieldTypeId == thrift.STOP {
 			break
 		}
-		if err := iprot.Skip(fieldTypeId); err != nil {
-			return err
+		switch fieldId {
+		case 1:
+			if err := p.ReadField1(iprot); err != nil {
+				return err
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
 		}
 		if err := iprot.ReadFieldEnd(); err != nil {
 			return err
@@ -25602,11 +30211,22 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TPro
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getWriteNewSeriesLimitPerShardPerSecond_args"); err != nil {
+func (p *NodeWriteExemplarsArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &WriteExemplarsRequest{}
+	if err := p.Req.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *NodeWriteExemplarsArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeExemplars_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -25617,51 +30237,49 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) Write(oprot thrift.TPr
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondArgs) String() string {
+func (p *NodeWriteExemplarsArgs) writeField1(oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *NodeWriteExemplarsArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetWriteNewSeriesLimitPerShardPerSecondArgs(%+v)", *p)
+	return fmt.Sprintf("NodeWriteExemplarsArgs(%+v)", *p)
 }
 
 // Attributes:
-//  - Success
 //  - Err
-type NodeGetWriteNewSeriesLimitPerShardPerSecondResult struct {
-	Success *NodeWriteNewSeriesLimitPerShardPerSecondResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                                           `thrift:"err,1" db:"err" json:"err,omitempty"`
-}
-
-func NewNodeGetWriteNewSeriesLimitPerShardPerSecondResult() *NodeGetWriteNewSeriesLimitPerShardPerSecondResult {
-	return &NodeGetWriteNewSeriesLimitPerShardPerSecondResult{}
+type NodeWriteExemplarsResult struct {
+	Err *Error `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-var NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT *NodeWriteNewSeriesLimitPerShardPerSecondResult_
-
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) GetSuccess() *NodeWriteNewSeriesLimitPerShardPerSecondResult_ {
-	if !p.IsSetSuccess() {
-		return NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT
-	}
-	return p.Success
+func NewNodeWriteExemplarsResult() *NodeWriteExemplarsResult {
+	return &NodeWriteExemplarsResult{}
 }
 
-var NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT *Error
+var NodeWriteExemplarsResult_Err_DEFAULT *Error
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) GetErr() *Error {
+func (p *NodeWriteExemplarsResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeGetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT
+		return NodeWriteExemplarsResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) IsSetSuccess() bool {
-	return p.Success != nil
-}
-
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) IsSetErr() bool {
+func (p *NodeWriteExemplarsResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeWriteExemplarsResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25675,10 +30293,6 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TP
 			break
 		}
 		switch fieldId {
-		case 0:
-			if err := p.ReadField0(iprot); err != nil {
-				return err
-			}
 		case 1:
 			if err := p.ReadField1(iprot); err != nil {
 				return err
@@ -25698,15 +30312,7 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TP
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeWriteNewSeriesLimitPerShardPerSecondResult_{}
-	if err := p.Success.Read(iprot); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
-	}
-	return nil
-}
-
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeWriteExemplarsResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -25716,14 +30322,11 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) ReadField1(iprot thr
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("getWriteNewSeriesLimitPerShardPerSecond_result"); err != nil {
+func (p *NodeWriteExemplarsResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("writeExemplars_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
-		if err := p.writeField0(oprot); err != nil {
-			return err
-		}
 		if err := p.writeField1(oprot); err != nil {
 			return err
 		}
@@ -25737,22 +30340,7 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) Write(oprot thrift.T
 	return nil
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) writeField0(oprot thrift.TProtocol) (err error) {
-	if p.IsSetSuccess() {
-		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
-		}
-		if err := p.Success.Write(oprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
-		}
-		if err := oprot.WriteFieldEnd(); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
-		}
-	}
-	return err
-}
-
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeWriteExemplarsResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -25767,36 +30355,36 @@ func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) writeField1(oprot th
 	return err
 }
 
-func (p *NodeGetWriteNewSeriesLimitPerShardPerSecondResult) String() string {
+func (p *NodeWriteExemplarsResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeGetWriteNewSeriesLimitPerShardPerSecondResult(%+v)", *p)
+	return fmt.Sprintf("NodeWriteExemplarsResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeSetWriteNewSeriesLimitPerShardPerSecondArgs struct {
-	Req *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeFetchExemplarsArgs struct {
+	Req *FetchExemplarsRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeSetWriteNewSeriesLimitPerShardPerSecondArgs() *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs {
-	return &NodeSetWriteNewSeriesLimitPerShardPerSecondArgs{}
+func NewNodeFetchExemplarsArgs() *NodeFetchExemplarsArgs {
+	return &NodeFetchExemplarsArgs{}
 }
 
-var NodeSetWriteNewSeriesLimitPerShardPerSecondArgs_Req_DEFAULT *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest
+var NodeFetchExemplarsArgs_Req_DEFAULT *FetchExemplarsRequest
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) GetReq() *NodeSetWriteNewSeriesLimitPerShardPerSecondRequest {
+func (p *NodeFetchExemplarsArgs) GetReq() *FetchExemplarsRequest {
 	if !p.IsSetReq() {
-		return NodeSetWriteNewSeriesLimitPerShardPerSecondArgs_Req_DEFAULT
+		return NodeFetchExemplarsArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) IsSetReq() bool {
+func (p *NodeFetchExemplarsArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchExemplarsArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25829,16 +30417,16 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) Read(iprot thrift.TPro
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &NodeSetWriteNewSeriesLimitPerShardPerSecondRequest{}
+func (p *NodeFetchExemplarsArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &FetchExemplarsRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setWriteNewSeriesLimitPerShardPerSecond_args"); err != nil {
+func (p *NodeFetchExemplarsArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchExemplars_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -25855,7 +30443,7 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) Write(oprot thrift.TPr
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchExemplarsArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -25868,51 +30456,51 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) writeField1(oprot thri
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondArgs) String() string {
+func (p *NodeFetchExemplarsArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetWriteNewSeriesLimitPerShardPerSecondArgs(%+v)", *p)
+	return fmt.Sprintf("NodeFetchExemplarsArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeSetWriteNewSeriesLimitPerShardPerSecondResult struct {
-	Success *NodeWriteNewSeriesLimitPerShardPerSecondResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                                           `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeFetchExemplarsResult struct {
+	Success *FetchExemplarsResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error                 `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeSetWriteNewSeriesLimitPerShardPerSecondResult() *NodeSetWriteNewSeriesLimitPerShardPerSecondResult {
-	return &NodeSetWriteNewSeriesLimitPerShardPerSecondResult{}
+func NewNodeFetchExemplarsResult() *NodeFetchExemplarsResult {
+	return &NodeFetchExemplarsResult{}
 }
 
-var NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT *NodeWriteNewSeriesLimitPerShardPerSecondResult_
+var NodeFetchExemplarsResult_Success_DEFAULT *FetchExemplarsResult_
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) GetSuccess() *NodeWriteNewSeriesLimitPerShardPerSecondResult_ {
+func (p *NodeFetchExemplarsResult) GetSuccess() *FetchExemplarsResult_ {
 	if !p.IsSetSuccess() {
-		return NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Success_DEFAULT
+		return NodeFetchExemplarsResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT *Error
+var NodeFetchExemplarsResult_Err_DEFAULT *Error
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) GetErr() *Error {
+func (p *NodeFetchExemplarsResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeSetWriteNewSeriesLimitPerShardPerSecondResult_Err_DEFAULT
+		return NodeFetchExemplarsResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) IsSetSuccess() bool {
+func (p *NodeFetchExemplarsResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) IsSetErr() bool {
+func (p *NodeFetchExemplarsResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeFetchExemplarsResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -25949,15 +30537,15 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) Read(iprot thrift.TP
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &NodeWriteNewSeriesLimitPerShardPerSecondResult_{}
+func (p *NodeFetchExemplarsResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &FetchExemplarsResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeFetchExemplarsResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -25967,8 +30555,8 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) ReadField1(iprot thr
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("setWriteNewSeriesLimitPerShardPerSecond_result"); err != nil {
+func (p *NodeFetchExemplarsResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("fetchExemplars_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -25988,7 +30576,7 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) Write(oprot thrift.T
 	return nil
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchExemplarsResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -26003,7 +30591,7 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) writeField0(oprot th
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeFetchExemplarsResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -26018,36 +30606,36 @@ func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) writeField1(oprot th
 	return err
 }
 
-func (p *NodeSetWriteNewSeriesLimitPerShardPerSecondResult) String() string {
+func (p *NodeFetchExemplarsResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeSetWriteNewSeriesLimitPerShardPerSecondResult(%+v)", *p)
+	return fmt.Sprintf("NodeFetchExemplarsResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeDebugProfileStartArgs struct {
-	Req *DebugProfileStartRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeDeleteTaggedArgs struct {
+	Req *DeleteTaggedRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeDebugProfileStartArgs() *NodeDebugProfileStartArgs {
-	return &NodeDebugProfileStartArgs{}
+func NewNodeDeleteTaggedArgs() *NodeDeleteTaggedArgs {
+	return &NodeDeleteTaggedArgs{}
 }
 
-var NodeDebugProfileStartArgs_Req_DEFAULT *DebugProfileStartRequest
+var NodeDeleteTaggedArgs_Req_DEFAULT *DeleteTaggedRequest
 
-func (p *NodeDebugProfileStartArgs) GetReq() *DebugProfileStartRequest {
+func (p *NodeDeleteTaggedArgs) GetReq() *DeleteTaggedRequest {
 	if !p.IsSetReq() {
-		return NodeDebugProfileStartArgs_Req_DEFAULT
+		return NodeDeleteTaggedArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeDebugProfileStartArgs) IsSetReq() bool {
+func (p *NodeDeleteTaggedArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeDebugProfileStartArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeDeleteTaggedArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -26080,16 +30668,16 @@ func (p *NodeDebugProfileStartArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStartArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &DebugProfileStartRequest{}
+func (p *NodeDeleteTaggedArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &DeleteTaggedRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeDebugProfileStartArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("debugProfileStart_args"); err != nil {
+func (p *NodeDeleteTaggedArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("deleteTagged_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -26106,7 +30694,7 @@ func (p *NodeDebugProfileStartArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStartArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDeleteTaggedArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -26119,51 +30707,51 @@ func (p *NodeDebugProfileStartArgs) writeField1(oprot thrift.TProtocol) (err err
 	return err
 }
 
-func (p *NodeDebugProfileStartArgs) String() string {
+func (p *NodeDeleteTaggedArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeDebugProfileStartArgs(%+v)", *p)
+	return fmt.Sprintf("NodeDeleteTaggedArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeDebugProfileStartResult struct {
-	Success *DebugProfileStartResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                    `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeDeleteTaggedResult struct {
+	Success *DeleteTaggedResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error               `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeDebugProfileStartResult() *NodeDebugProfileStartResult {
-	return &NodeDebugProfileStartResult{}
+func NewNodeDeleteTaggedResult() *NodeDeleteTaggedResult {
+	return &NodeDeleteTaggedResult{}
 }
 
-var NodeDebugProfileStartResult_Success_DEFAULT *DebugProfileStartResult_
+var NodeDeleteTaggedResult_Success_DEFAULT *DeleteTaggedResult_
 
-func (p *NodeDebugProfileStartResult) GetSuccess() *DebugProfileStartResult_ {
+func (p *NodeDeleteTaggedResult) GetSuccess() *DeleteTaggedResult_ {
 	if !p.IsSetSuccess() {
-		return NodeDebugProfileStartResult_Success_DEFAULT
+		return NodeDeleteTaggedResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeDebugProfileStartResult_Err_DEFAULT *Error
+var NodeDeleteTaggedResult_Err_DEFAULT *Error
 
-func (p *NodeDebugProfileStartResult) GetErr() *Error {
+func (p *NodeDeleteTaggedResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeDebugProfileStartResult_Err_DEFAULT
+		return NodeDeleteTaggedResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeDebugProfileStartResult) IsSetSuccess() bool {
+func (p *NodeDeleteTaggedResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeDebugProfileStartResult) IsSetErr() bool {
+func (p *NodeDeleteTaggedResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeDebugProfileStartResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeDeleteTaggedResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -26200,15 +30788,15 @@ func (p *NodeDebugProfileStartResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStartResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &DebugProfileStartResult_{}
+func (p *NodeDeleteTaggedResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &DeleteTaggedResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeDebugProfileStartResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeDeleteTaggedResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -26218,8 +30806,8 @@ func (p *NodeDebugProfileStartResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStartResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("debugProfileStart_result"); err != nil {
+func (p *NodeDeleteTaggedResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("deleteTagged_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -26239,7 +30827,7 @@ func (p *NodeDebugProfileStartResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStartResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeDeleteTaggedResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -26254,7 +30842,7 @@ func (p *NodeDebugProfileStartResult) writeField0(oprot thrift.TProtocol) (err e
 	return err
 }
 
-func (p *NodeDebugProfileStartResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeDeleteTaggedResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -26269,36 +30857,36 @@ func (p *NodeDebugProfileStartResult) writeField1(oprot thrift.TProtocol) (err e
 	return err
 }
 
-func (p *NodeDebugProfileStartResult) String() string {
+func (p *NodeDeleteTaggedResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeDebugProfileStartResult(%+v)", *p)
+	return fmt.Sprintf("NodeDeleteTaggedResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeDebugProfileStopArgs struct {
-	Req *DebugProfileStopRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeCardinalityArgs struct {
+	Req *CardinalityRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeDebugProfileStopArgs() *NodeDebugProfileStopArgs {
-	return &NodeDebugProfileStopArgs{}
+func NewNodeCardinalityArgs() *NodeCardinalityArgs {
+	return &NodeCardinalityArgs{}
 }
 
-var NodeDebugProfileStopArgs_Req_DEFAULT *DebugProfileStopRequest
+var NodeCardinalityArgs_Req_DEFAULT *CardinalityRequest
 
-func (p *NodeDebugProfileStopArgs) GetReq() *DebugProfileStopRequest {
+func (p *NodeCardinalityArgs) GetReq() *CardinalityRequest {
 	if !p.IsSetReq() {
-		return NodeDebugProfileStopArgs_Req_DEFAULT
+		return NodeCardinalityArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeDebugProfileStopArgs) IsSetReq() bool {
+func (p *NodeCardinalityArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeDebugProfileStopArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeCardinalityArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -26331,16 +30919,16 @@ func (p *NodeDebugProfileStopArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStopArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &DebugProfileStopRequest{}
+func (p *NodeCardinalityArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &CardinalityRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeDebugProfileStopArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("debugProfileStop_args"); err != nil {
+func (p *NodeCardinalityArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("cardinality_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -26357,7 +30945,7 @@ func (p *NodeDebugProfileStopArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStopArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeCardinalityArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -26370,51 +30958,51 @@ func (p *NodeDebugProfileStopArgs) writeField1(oprot thrift.TProtocol) (err erro
 	return err
 }
 
-func (p *NodeDebugProfileStopArgs) String() string {
+func (p *NodeCardinalityArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeDebugProfileStopArgs(%+v)", *p)
+	return fmt.Sprintf("NodeCardinalityArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeDebugProfileStopResult struct {
-	Success *DebugProfileStopResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                   `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeCardinalityResult struct {
+	Success *CardinalityResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error              `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeDebugProfileStopResult() *NodeDebugProfileStopResult {
-	return &NodeDebugProfileStopResult{}
+func NewNodeCardinalityResult() *NodeCardinalityResult {
+	return &NodeCardinalityResult{}
 }
 
-var NodeDebugProfileStopResult_Success_DEFAULT *DebugProfileStopResult_
+var NodeCardinalityResult_Success_DEFAULT *CardinalityResult_
 
-func (p *NodeDebugProfileStopResult) GetSuccess() *DebugProfileStopResult_ {
+func (p *NodeCardinalityResult) GetSuccess() *CardinalityResult_ {
 	if !p.IsSetSuccess() {
-		return NodeDebugProfileStopResult_Success_DEFAULT
+		return NodeCardinalityResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeDebugProfileStopResult_Err_DEFAULT *Error
+var NodeCardinalityResult_Err_DEFAULT *Error
 
-func (p *NodeDebugProfileStopResult) GetErr() *Error {
+func (p *NodeCardinalityResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeDebugProfileStopResult_Err_DEFAULT
+		return NodeCardinalityResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeDebugProfileStopResult) IsSetSuccess() bool {
+func (p *NodeCardinalityResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeDebugProfileStopResult) IsSetErr() bool {
+func (p *NodeCardinalityResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeDebugProfileStopResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeCardinalityResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -26451,15 +31039,15 @@ func (p *NodeDebugProfileStopResult) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStopResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &DebugProfileStopResult_{}
+func (p *NodeCardinalityResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &CardinalityResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeDebugProfileStopResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeCardinalityResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -26469,8 +31057,8 @@ func (p *NodeDebugProfileStopResult) ReadField1(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStopResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("debugProfileStop_result"); err != nil {
+func (p *NodeCardinalityResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("cardinality_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -26490,7 +31078,7 @@ func (p *NodeDebugProfileStopResult) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugProfileStopResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeCardinalityResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -26505,7 +31093,7 @@ func (p *NodeDebugProfileStopResult) writeField0(oprot thrift.TProtocol) (err er
 	return err
 }
 
-func (p *NodeDebugProfileStopResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeCardinalityResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -26520,36 +31108,36 @@ func (p *NodeDebugProfileStopResult) writeField1(oprot thrift.TProtocol) (err er
 	return err
 }
 
-func (p *NodeDebugProfileStopResult) String() string {
+func (p *NodeCardinalityResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeDebugProfileStopResult(%+v)", *p)
+	return fmt.Sprintf("NodeCardinalityResult(%+v)", *p)
 }
 
 // Attributes:
 //  - Req
-type NodeDebugIndexMemorySegmentsArgs struct {
-	Req *DebugIndexMemorySegmentsRequest `thrift:"req,1" db:"req" json:"req"`
+type NodeCountArgs struct {
+	Req *CountRequest `thrift:"req,1" db:"req" json:"req"`
 }
 
-func NewNodeDebugIndexMemorySegmentsArgs() *NodeDebugIndexMemorySegmentsArgs {
-	return &NodeDebugIndexMemorySegmentsArgs{}
+func NewNodeCountArgs() *NodeCountArgs {
+	return &NodeCountArgs{}
 }
 
-var NodeDebugIndexMemorySegmentsArgs_Req_DEFAULT *DebugIndexMemorySegmentsRequest
+var NodeCountArgs_Req_DEFAULT *CountRequest
 
-func (p *NodeDebugIndexMemorySegmentsArgs) GetReq() *DebugIndexMemorySegmentsRequest {
+func (p *NodeCountArgs) GetReq() *CountRequest {
 	if !p.IsSetReq() {
-		return NodeDebugIndexMemorySegmentsArgs_Req_DEFAULT
+		return NodeCountArgs_Req_DEFAULT
 	}
 	return p.Req
 }
-func (p *NodeDebugIndexMemorySegmentsArgs) IsSetReq() bool {
+func (p *NodeCountArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsArgs) Read(iprot thrift.TProtocol) error {
+func (p *NodeCountArgs) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -26582,16 +31170,16 @@ func (p *NodeDebugIndexMemorySegmentsArgs) Read(iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsArgs) ReadField1(iprot thrift.TProtocol) error {
-	p.Req = &DebugIndexMemorySegmentsRequest{}
+func (p *NodeCountArgs) ReadField1(iprot thrift.TProtocol) error {
+	p.Req = &CountRequest{}
 	if err := p.Req.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
 	}
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsArgs) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("debugIndexMemorySegments_args"); err != nil {
+func (p *NodeCountArgs) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("count_args"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -26608,7 +31196,7 @@ func (p *NodeDebugIndexMemorySegmentsArgs) Write(oprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsArgs) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeCountArgs) writeField1(oprot thrift.TProtocol) (err error) {
 	if err := oprot.WriteFieldBegin("req", thrift.STRUCT, 1); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
 	}
@@ -26621,51 +31209,51 @@ func (p *NodeDebugIndexMemorySegmentsArgs) writeField1(oprot thrift.TProtocol) (
 	return err
 }
 
-func (p *NodeDebugIndexMemorySegmentsArgs) String() string {
+func (p *NodeCountArgs) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeDebugIndexMemorySegmentsArgs(%+v)", *p)
+	return fmt.Sprintf("NodeCountArgs(%+v)", *p)
 }
 
 // Attributes:
 //  - Success
 //  - Err
-type NodeDebugIndexMemorySegmentsResult struct {
-	Success *DebugIndexMemorySegmentsResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
-	Err     *Error                           `thrift:"err,1" db:"err" json:"err,omitempty"`
+type NodeCountResult struct {
+	Success *CountResult_ `thrift:"success,0" db:"success" json:"success,omitempty"`
+	Err     *Error        `thrift:"err,1" db:"err" json:"err,omitempty"`
 }
 
-func NewNodeDebugIndexMemorySegmentsResult() *NodeDebugIndexMemorySegmentsResult {
-	return &NodeDebugIndexMemorySegmentsResult{}
+func NewNodeCountResult() *NodeCountResult {
+	return &NodeCountResult{}
 }
 
-var NodeDebugIndexMemorySegmentsResult_Success_DEFAULT *DebugIndexMemorySegmentsResult_
+var NodeCountResult_Success_DEFAULT *CountResult_
 
-func (p *NodeDebugIndexMemorySegmentsResult) GetSuccess() *DebugIndexMemorySegmentsResult_ {
+func (p *NodeCountResult) GetSuccess() *CountResult_ {
 	if !p.IsSetSuccess() {
-		return NodeDebugIndexMemorySegmentsResult_Success_DEFAULT
+		return NodeCountResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-var NodeDebugIndexMemorySegmentsResult_Err_DEFAULT *Error
+var NodeCountResult_Err_DEFAULT *Error
 
-func (p *NodeDebugIndexMemorySegmentsResult) GetErr() *Error {
+func (p *NodeCountResult) GetErr() *Error {
 	if !p.IsSetErr() {
-		return NodeDebugIndexMemorySegmentsResult_Err_DEFAULT
+		return NodeCountResult_Err_DEFAULT
 	}
 	return p.Err
 }
-func (p *NodeDebugIndexMemorySegmentsResult) IsSetSuccess() bool {
+func (p *NodeCountResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) IsSetErr() bool {
+func (p *NodeCountResult) IsSetErr() bool {
 	return p.Err != nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) Read(iprot thrift.TProtocol) error {
+func (p *NodeCountResult) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
@@ -26702,15 +31290,15 @@ func (p *NodeDebugIndexMemorySegmentsResult) Read(iprot thrift.TProtocol) error
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) ReadField0(iprot thrift.TProtocol) error {
-	p.Success = &DebugIndexMemorySegmentsResult_{}
+func (p *NodeCountResult) ReadField0(iprot thrift.TProtocol) error {
+	p.Success = &CountResult_{}
 	if err := p.Success.Read(iprot); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
 	}
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) ReadField1(iprot thrift.TProtocol) error {
+func (p *NodeCountResult) ReadField1(iprot thrift.TProtocol) error {
 	p.Err = &Error{
 		Type: 0,
 	}
@@ -26720,8 +31308,8 @@ func (p *NodeDebugIndexMemorySegmentsResult) ReadField1(iprot thrift.TProtocol)
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) Write(oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin("debugIndexMemorySegments_result"); err != nil {
+func (p *NodeCountResult) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("count_result"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -26741,7 +31329,7 @@ func (p *NodeDebugIndexMemorySegmentsResult) Write(oprot thrift.TProtocol) error
 	return nil
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) writeField0(oprot thrift.TProtocol) (err error) {
+func (p *NodeCountResult) writeField0(oprot thrift.TProtocol) (err error) {
 	if p.IsSetSuccess() {
 		if err := oprot.WriteFieldBegin("success", thrift.STRUCT, 0); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
@@ -26756,7 +31344,7 @@ func (p *NodeDebugIndexMemorySegmentsResult) writeField0(oprot thrift.TProtocol)
 	return err
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) writeField1(oprot thrift.TProtocol) (err error) {
+func (p *NodeCountResult) writeField1(oprot thrift.TProtocol) (err error) {
 	if p.IsSetErr() {
 		if err := oprot.WriteFieldBegin("err", thrift.STRUCT, 1); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:err: ", p), err)
@@ -26771,11 +31359,11 @@ func (p *NodeDebugIndexMemorySegmentsResult) writeField1(oprot thrift.TProtocol)
 	return err
 }
 
-func (p *NodeDebugIndexMemorySegmentsResult) String() string {
+func (p *NodeCountResult) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("NodeDebugIndexMemorySegmentsResult(%+v)", *p)
+	return fmt.Sprintf("NodeCountResult(%+v)", *p)
 }
 
 type Cluster interface {
@@ -26872,16 +31460,16 @@ func (p *ClusterClient) recvHealth() (value *HealthResult_, err error) {
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error245 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error246 error
-		error246, err = error245.Read(iprot)
+		error266 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error267 error
+		error267, err = error266.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error246
+		err = error267
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -26953,16 +31541,16 @@ func (p *ClusterClient) recvWrite() (err error) {
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error247 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error248 error
-		error248, err = error247.Read(iprot)
+		error268 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error269 error
+		error269, err = error268.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error248
+		err = error269
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -27033,16 +31621,16 @@ func (p *ClusterClient) recvWriteTagged() (err error) {
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error249 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error250 error
-		error250, err = error249.Read(iprot)
+		error270 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error271 error
+		error271, err = error270.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error250
+		err = error271
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -27113,16 +31701,16 @@ func (p *ClusterClient) recvQuery() (value *QueryResult_, err error) {
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error251 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error252 error
-		error252, err = error251.Read(iprot)
+		error272 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error273 error
+		error273, err = error272.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error252
+		err = error273
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -27194,16 +31782,16 @@ func (p *ClusterClient) recvAggregate() (value *AggregateQueryResult_, err error
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error253 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error254 error
-		error254, err = error253.Read(iprot)
+		error274 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error275 error
+		error275, err = error274.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error254
+		err = error275
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -27275,16 +31863,16 @@ func (p *ClusterClient) recvFetch() (value *FetchResult_, err error) {
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error255 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error256 error
-		error256, err = error255.Read(iprot)
+		error276 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error277 error
+		error277, err = error276.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error256
+		err = error277
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -27356,16 +31944,16 @@ func (p *ClusterClient) recvTruncate() (value *TruncateResult_, err error) {
 		return
 	}
 	if mTypeId == thrift.EXCEPTION {
-		error257 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
-		var error258 error
-		error258, err = error257.Read(iprot)
+		error278 := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "Unknown Exception")
+		var error279 error
+		error279, err = error278.Read(iprot)
 		if err != nil {
 			return
 		}
 		if err = iprot.ReadMessageEnd(); err != nil {
 			return
 		}
-		err = error258
+		err = error279
 		return
 	}
 	if mTypeId != thrift.REPLY {
@@ -27407,15 +31995,15 @@ func (p *ClusterProcessor) ProcessorMap() map[string]thrift.TProcessorFunction {
 
 func NewClusterProcessor(handler Cluster) *ClusterProcessor {
 
-	self259 := &ClusterProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
-	self259.processorMap["health"] = &clusterProcessorHealth{handler: handler}
-	self259.processorMap["write"] = &clusterProcessorWrite{handler: handler}
-	self259.processorMap["writeTagged"] = &clusterProcessorWriteTagged{handler: handler}
-	self259.processorMap["query"] = &clusterProcessorQuery{handler: handler}
-	self259.processorMap["aggregate"] = &clusterProcessorAggregate{handler: handler}
-	self259.processorMap["fetch"] = &clusterProcessorFetch{handler: handler}
-	self259.processorMap["truncate"] = &clusterProcessorTruncate{handler: handler}
-	return self259
+	self280 := &ClusterProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
+	self280.processorMap["health"] = &clusterProcessorHealth{handler: handler}
+	self280.processorMap["write"] = &clusterProcessorWrite{handler: handler}
+	self280.processorMap["writeTagged"] = &clusterProcessorWriteTagged{handler: handler}
+	self280.processorMap["query"] = &clusterProcessorQuery{handler: handler}
+	self280.processorMap["aggregate"] = &clusterProcessorAggregate{handler: handler}
+	self280.processorMap["fetch"] = &clusterProcessorFetch{handler: handler}
+	self280.processorMap["truncate"] = &clusterProcessorTruncate{handler: handler}
+	return self280
 }
 
 func (p *ClusterProcessor) Process(iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
@@ -27428,12 +32016,12 @@ func (p *ClusterProcessor) Process(iprot, oprot thrift.TProtocol) (success bool,
 	}
 	iprot.Skip(thrift.STRUCT)
 	iprot.ReadMessageEnd()
-	x260 := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
+	x279 := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
 	oprot.WriteMessageBegin(name, thrift.EXCEPTION, seqId)
-	x260.Write(oprot)
+	x279.Write(oprot)
 	oprot.WriteMessageEnd()
 	oprot.Flush()
-	return false, x260
+	return false, x279
 
 }
 
@@ -255,6 +255,36 @@ func (mr *MockTChanNodeMockRecorder) BootstrappedInPlacementOrNoPlacement(ctx in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrappedInPlacementOrNoPlacement", reflect.TypeOf((*MockTChanNode)(nil).BootstrappedInPlacementOrNoPlacement), ctx)
 }
 
+// Cardinality mocks base method.
+func (m *MockTChanNode) Cardinality(ctx thrift.Context, req *CardinalityRequest) (*CardinalityResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, req)
+	ret0, _ := ret[0].(*CardinalityResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockTChanNodeMockRecorder) Cardinality(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockTChanNode)(nil).Cardinality), ctx, req)
+}
+
+// Count mocks base method.
+func (m *MockTChanNode) Count(ctx thrift.Context, req *CountRequest) (*CountResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, req)
+	ret0, _ := ret[0].(*CountResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockTChanNodeMockRecorder) Count(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockTChanNode)(nil).Count), ctx, req)
+}
+
 // DebugIndexMemorySegments mocks base method.
 func (m *MockTChanNode) DebugIndexMemorySegments(ctx thrift.Context, req *DebugIndexMemorySegmentsRequest) (*DebugIndexMemorySegmentsResult_, error) {
 	m.ctrl.T.Helper()
@@ -300,6 +330,21 @@ func (mr *MockTChanNodeMockRecorder) DebugProfileStop(ctx, req interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugProfileStop", reflect.TypeOf((*MockTChanNode)(nil).DebugProfileStop), ctx, req)
 }
 
+// DeleteTagged mocks base method.
+func (m *MockTChanNode) DeleteTagged(ctx thrift.Context, req *DeleteTaggedRequest) (*DeleteTaggedResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, req)
+	ret0, _ := ret[0].(*DeleteTaggedResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockTChanNodeMockRecorder) DeleteTagged(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockTChanNode)(nil).DeleteTagged), ctx, req)
+}
+
 // Fetch mocks base method.
 func (m *MockTChanNode) Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error) {
 	m.ctrl.T.Helper()
@@ -375,6 +420,21 @@ func (mr *MockTChanNodeMockRecorder) FetchBlocksRaw(ctx, req interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksRaw", reflect.TypeOf((*MockTChanNode)(nil).FetchBlocksRaw), ctx, req)
 }
 
+// FetchExemplars mocks base method.
+func (m *MockTChanNode) FetchExemplars(ctx thrift.Context, req *FetchExemplarsRequest) (*FetchExemplarsResult_, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchExemplars", ctx, req)
+	ret0, _ := ret[0].(*FetchExemplarsResult_)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchExemplars indicates an expected call of FetchExemplars.
+func (mr *MockTChanNodeMockRecorder) FetchExemplars(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExemplars", reflect.TypeOf((*MockTChanNode)(nil).FetchExemplars), ctx, req)
+}
+
 // FetchTagged mocks base method.
 func (m *MockTChanNode) FetchTagged(ctx thrift.Context, req *FetchTaggedRequest) (*FetchTaggedResult_, error) {
 	m.ctrl.T.Helper()
@@ -611,6 +671,20 @@ func (mr *MockTChanNodeMockRecorder) WriteBatchRawV2(ctx, req interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBatchRawV2", reflect.TypeOf((*MockTChanNode)(nil).WriteBatchRawV2), ctx, req)
 }
 
+// WriteExemplars mocks base method.
+func (m *MockTChanNode) WriteExemplars(ctx thrift.Context, req *WriteExemplarsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteExemplars", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteExemplars indicates an expected call of WriteExemplars.
+func (mr *MockTChanNodeMockRecorder) WriteExemplars(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExemplars", reflect.TypeOf((*MockTChanNode)(nil).WriteExemplars), ctx, req)
+}
+
 // WriteTagged mocks base method.
 func (m *MockTChanNode) WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error {
 	m.ctrl.T.Helper()
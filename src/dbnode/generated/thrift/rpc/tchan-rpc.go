@@ -50,14 +50,18 @@ type TChanNode interface {
 	AggregateTiles(ctx thrift.Context, req *AggregateTilesRequest) (*AggregateTilesResult_, error)
 	Bootstrapped(ctx thrift.Context) (*NodeBootstrappedResult_, error)
 	BootstrappedInPlacementOrNoPlacement(ctx thrift.Context) (*NodeBootstrappedInPlacementOrNoPlacementResult_, error)
+	Cardinality(ctx thrift.Context, req *CardinalityRequest) (*CardinalityResult_, error)
+	Count(ctx thrift.Context, req *CountRequest) (*CountResult_, error)
 	DebugIndexMemorySegments(ctx thrift.Context, req *DebugIndexMemorySegmentsRequest) (*DebugIndexMemorySegmentsResult_, error)
 	DebugProfileStart(ctx thrift.Context, req *DebugProfileStartRequest) (*DebugProfileStartResult_, error)
 	DebugProfileStop(ctx thrift.Context, req *DebugProfileStopRequest) (*DebugProfileStopResult_, error)
+	DeleteTagged(ctx thrift.Context, req *DeleteTaggedRequest) (*DeleteTaggedResult_, error)
 	Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error)
 	FetchBatchRaw(ctx thrift.Context, req *FetchBatchRawRequest) (*FetchBatchRawResult_, error)
 	FetchBatchRawV2(ctx thrift.Context, req *FetchBatchRawV2Request) (*FetchBatchRawResult_, error)
 	FetchBlocksMetadataRawV2(ctx thrift.Context, req *FetchBlocksMetadataRawV2Request) (*FetchBlocksMetadataRawV2Result_, error)
 	FetchBlocksRaw(ctx thrift.Context, req *FetchBlocksRawRequest) (*FetchBlocksRawResult_, error)
+	FetchExemplars(ctx thrift.Context, req *FetchExemplarsRequest) (*FetchExemplarsResult_, error)
 	FetchTagged(ctx thrift.Context, req *FetchTaggedRequest) (*FetchTaggedResult_, error)
 	GetPersistRateLimit(ctx thrift.Context) (*NodePersistRateLimitResult_, error)
 	GetWriteNewSeriesAsync(ctx thrift.Context) (*NodeWriteNewSeriesAsyncResult_, error)
@@ -74,6 +78,7 @@ type TChanNode interface {
 	Write(ctx thrift.Context, req *WriteRequest) error
 	WriteBatchRaw(ctx thrift.Context, req *WriteBatchRawRequest) error
 	WriteBatchRawV2(ctx thrift.Context, req *WriteBatchRawV2Request) error
+	WriteExemplars(ctx thrift.Context, req *WriteExemplarsRequest) error
 	WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error
 	WriteTaggedBatchRaw(ctx thrift.Context, req *WriteTaggedBatchRawRequest) error
 	WriteTaggedBatchRawV2(ctx thrift.Context, req *WriteTaggedBatchRawV2Request) error
@@ -569,6 +574,42 @@ func (c *tchanNodeClient) BootstrappedInPlacementOrNoPlacement(ctx thrift.Contex
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) Cardinality(ctx thrift.Context, req *CardinalityRequest) (*CardinalityResult_, error) {
+	var resp NodeCardinalityResult
+	args := NodeCardinalityArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "cardinality", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for cardinality")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
+func (c *tchanNodeClient) Count(ctx thrift.Context, req *CountRequest) (*CountResult_, error) {
+	var resp NodeCountResult
+	args := NodeCountArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "count", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for count")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) DebugIndexMemorySegments(ctx thrift.Context, req *DebugIndexMemorySegmentsRequest) (*DebugIndexMemorySegmentsResult_, error) {
 	var resp NodeDebugIndexMemorySegmentsResult
 	args := NodeDebugIndexMemorySegmentsArgs{
@@ -623,6 +664,24 @@ func (c *tchanNodeClient) DebugProfileStop(ctx thrift.Context, req *DebugProfile
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) DeleteTagged(ctx thrift.Context, req *DeleteTaggedRequest) (*DeleteTaggedResult_, error) {
+	var resp NodeDeleteTaggedResult
+	args := NodeDeleteTaggedArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "deleteTagged", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for deleteTagged")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) Fetch(ctx thrift.Context, req *FetchRequest) (*FetchResult_, error) {
 	var resp NodeFetchResult
 	args := NodeFetchArgs{
@@ -713,6 +772,24 @@ func (c *tchanNodeClient) FetchBlocksRaw(ctx thrift.Context, req *FetchBlocksRaw
 	return resp.GetSuccess(), err
 }
 
+func (c *tchanNodeClient) FetchExemplars(ctx thrift.Context, req *FetchExemplarsRequest) (*FetchExemplarsResult_, error) {
+	var resp NodeFetchExemplarsResult
+	args := NodeFetchExemplarsArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "fetchExemplars", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for fetchExemplars")
+		}
+	}
+
+	return resp.GetSuccess(), err
+}
+
 func (c *tchanNodeClient) FetchTagged(ctx thrift.Context, req *FetchTaggedRequest) (*FetchTaggedResult_, error) {
 	var resp NodeFetchTaggedResult
 	args := NodeFetchTaggedArgs{
@@ -989,6 +1066,24 @@ func (c *tchanNodeClient) WriteBatchRawV2(ctx thrift.Context, req *WriteBatchRaw
 	return err
 }
 
+func (c *tchanNodeClient) WriteExemplars(ctx thrift.Context, req *WriteExemplarsRequest) error {
+	var resp NodeWriteExemplarsResult
+	args := NodeWriteExemplarsArgs{
+		Req: req,
+	}
+	success, err := c.client.Call(ctx, c.thriftService, "writeExemplars", &args, &resp)
+	if err == nil && !success {
+		switch {
+		case resp.Err != nil:
+			err = resp.Err
+		default:
+			err = fmt.Errorf("received no result or unknown exception for writeExemplars")
+		}
+	}
+
+	return err
+}
+
 func (c *tchanNodeClient) WriteTagged(ctx thrift.Context, req *WriteTaggedRequest) error {
 	var resp NodeWriteTaggedResult
 	args := NodeWriteTaggedArgs{
@@ -1066,14 +1161,18 @@ func (s *tchanNodeServer) Methods() []string {
 		"aggregateTiles",
 		"bootstrapped",
 		"bootstrappedInPlacementOrNoPlacement",
+		"cardinality",
+		"count",
 		"debugIndexMemorySegments",
 		"debugProfileStart",
 		"debugProfileStop",
+		"deleteTagged",
 		"fetch",
 		"fetchBatchRaw",
 		"fetchBatchRawV2",
 		"fetchBlocksMetadataRawV2",
 		"fetchBlocksRaw",
+		"fetchExemplars",
 		"fetchTagged",
 		"getPersistRateLimit",
 		"getWriteNewSeriesAsync",
@@ -1090,6 +1189,7 @@ func (s *tchanNodeServer) Methods() []string {
 		"write",
 		"writeBatchRaw",
 		"writeBatchRawV2",
+		"writeExemplars",
 		"writeTagged",
 		"writeTaggedBatchRaw",
 		"writeTaggedBatchRawV2",
@@ -1108,12 +1208,18 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleBootstrapped(ctx, protocol)
 	case "bootstrappedInPlacementOrNoPlacement":
 		return s.handleBootstrappedInPlacementOrNoPlacement(ctx, protocol)
+	case "cardinality":
+		return s.handleCardinality(ctx, protocol)
+	case "count":
+		return s.handleCount(ctx, protocol)
 	case "debugIndexMemorySegments":
 		return s.handleDebugIndexMemorySegments(ctx, protocol)
 	case "debugProfileStart":
 		return s.handleDebugProfileStart(ctx, protocol)
 	case "debugProfileStop":
 		return s.handleDebugProfileStop(ctx, protocol)
+	case "deleteTagged":
+		return s.handleDeleteTagged(ctx, protocol)
 	case "fetch":
 		return s.handleFetch(ctx, protocol)
 	case "fetchBatchRaw":
@@ -1124,6 +1230,8 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleFetchBlocksMetadataRawV2(ctx, protocol)
 	case "fetchBlocksRaw":
 		return s.handleFetchBlocksRaw(ctx, protocol)
+	case "fetchExemplars":
+		return s.handleFetchExemplars(ctx, protocol)
 	case "fetchTagged":
 		return s.handleFetchTagged(ctx, protocol)
 	case "getPersistRateLimit":
@@ -1156,6 +1264,8 @@ func (s *tchanNodeServer) Handle(ctx thrift.Context, methodName string, protocol
 		return s.handleWriteBatchRaw(ctx, protocol)
 	case "writeBatchRawV2":
 		return s.handleWriteBatchRawV2(ctx, protocol)
+	case "writeExemplars":
+		return s.handleWriteExemplars(ctx, protocol)
 	case "writeTagged":
 		return s.handleWriteTagged(ctx, protocol)
 	case "writeTaggedBatchRaw":
@@ -1308,6 +1418,62 @@ func (s *tchanNodeServer) handleBootstrappedInPlacementOrNoPlacement(ctx thrift.
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleCardinality(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeCardinalityArgs
+	var res NodeCardinalityResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.Cardinality(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
+func (s *tchanNodeServer) handleCount(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeCountArgs
+	var res NodeCountResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.Count(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleDebugIndexMemorySegments(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeDebugIndexMemorySegmentsArgs
 	var res NodeDebugIndexMemorySegmentsResult
@@ -1392,6 +1558,34 @@ func (s *tchanNodeServer) handleDebugProfileStop(ctx thrift.Context, protocol at
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleDeleteTagged(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeDeleteTaggedArgs
+	var res NodeDeleteTaggedResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.DeleteTagged(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleFetch(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeFetchArgs
 	var res NodeFetchResult
@@ -1532,6 +1726,34 @@ func (s *tchanNodeServer) handleFetchBlocksRaw(ctx thrift.Context, protocol athr
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleFetchExemplars(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeFetchExemplarsArgs
+	var res NodeFetchExemplarsResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	r, err :=
+		s.handler.FetchExemplars(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+		res.Success = r
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleFetchTagged(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeFetchTaggedArgs
 	var res NodeFetchTaggedResult
@@ -1976,6 +2198,33 @@ func (s *tchanNodeServer) handleWriteBatchRawV2(ctx thrift.Context, protocol ath
 	return err == nil, &res, nil
 }
 
+func (s *tchanNodeServer) handleWriteExemplars(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
+	var req NodeWriteExemplarsArgs
+	var res NodeWriteExemplarsResult
+
+	if err := req.Read(protocol); err != nil {
+		return false, nil, err
+	}
+
+	err :=
+		s.handler.WriteExemplars(ctx, req.Req)
+
+	if err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v == nil {
+				return false, nil, fmt.Errorf("Handler for err returned non-nil error type *Error but nil value")
+			}
+			res.Err = v
+		default:
+			return false, nil, err
+		}
+	} else {
+	}
+
+	return err == nil, &res, nil
+}
+
 func (s *tchanNodeServer) handleWriteTagged(ctx thrift.Context, protocol athrift.TProtocol) (bool, athrift.TStruct, error) {
 	var req NodeWriteTaggedArgs
 	var res NodeWriteTaggedResult
@@ -0,0 +1,125 @@
+// +build integration
+
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	xclock "github.com/m3db/m3/src/x/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetchEncodingSchemes writes to a namespace encoded with m3tsz and to a
+// namespace encoded with xor, and verifies that a single client session reads
+// the series of both namespaces.
+func TestFetchEncodingSchemes(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow() // Just skip if we're doing a short run
+	}
+
+	var (
+		numWrites    = 20
+		numTags      = 3
+		retentionOpt = retention.NewOptions().
+				SetRetentionPeriod(6 * time.Hour).
+				SetBufferPast(10 * time.Minute).
+				SetBufferFuture(5 * time.Minute).
+				SetBlockSize(time.Hour)
+		indexOpts = namespace.NewIndexOptions().
+				SetBlockSize(2 * time.Hour).SetEnabled(true)
+	)
+
+	m3tszMd, err := namespace.NewMetadata(testNamespaces[0],
+		namespace.NewOptions().
+			SetRetentionOptions(retentionOpt).
+			SetIndexOptions(indexOpts))
+	require.NoError(t, err)
+
+	attrs, err := namespace.NewAggregatedAttributes(time.Minute, namespace.NewDownsampleOptions(true))
+	require.NoError(t, err)
+	xorMd, err := namespace.NewMetadata(testNamespaces[1],
+		namespace.NewOptions().
+			SetRetentionOptions(retentionOpt).
+			SetIndexOptions(indexOpts).
+			SetEncodingScheme(namespace.XOREncodingScheme).
+			SetAggregationOptions(namespace.NewAggregationOptions().
+				SetAggregations([]namespace.Aggregation{namespace.NewAggregatedAggregation(attrs)})))
+	require.NoError(t, err)
+
+	testOpts := NewTestOptions(t).
+		SetNamespaces([]namespace.Metadata{m3tszMd, xorMd}).
+		SetWriteNewSeriesAsync(true)
+	testSetup, err := NewTestSetup(t, testOpts, nil)
+	require.NoError(t, err)
+	defer testSetup.Close()
+
+	now := testSetup.NowFn()().Truncate(time.Hour).Add(30 * time.Minute)
+	testSetup.SetNowFn(now)
+	start := now.Add(-5 * time.Minute)
+
+	require.NoError(t, testSetup.StartServer())
+	defer func() {
+		require.NoError(t, testSetup.StopServer())
+	}()
+
+	session, err := testSetup.M3DBClient().DefaultSession()
+	require.NoError(t, err)
+
+	writes := GenerateTestIndexWrite(0, numWrites, numTags, start, now)
+	query := index.Query{
+		Query: idx.NewTermQuery([]byte("shared"), []byte("shared")),
+	}
+	for _, md := range []namespace.Metadata{m3tszMd, xorMd} {
+		writes.Write(t, md.ID(), session)
+		indexed := xclock.WaitUntil(func() bool {
+			return writes.NumIndexed(t, md.ID(), session) == len(writes)
+		}, 5*time.Second)
+		require.True(t, indexed, md.ID().String())
+	}
+
+	for _, md := range []namespace.Metadata{m3tszMd, xorMd} {
+		iters, _, err := session.FetchTagged(ContextWithDefaultTimeout(),
+			md.ID(), query, index.QueryOptions{StartInclusive: start, EndExclusive: now})
+		require.NoError(t, err)
+		writes.MatchesSeriesIters(t, iters)
+		iters.Close()
+
+		for _, w := range writes {
+			iter, err := session.Fetch(md.ID(), w.ID, start, now)
+			require.NoError(t, err)
+			require.True(t, iter.Next(), md.ID().String())
+			dp, _, _ := iter.Current()
+			require.Equal(t, w.Timestamp, dp.TimestampNanos)
+			require.Equal(t, w.Value, dp.Value)
+			require.False(t, iter.Next())
+			require.NoError(t, iter.Err())
+			iter.Close()
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// +build integration
+
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package integration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/integration/generate"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/blob"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeersBootstrapOffloadedBlocks(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Test setups
+	log := xtest.NewLogger(t)
+	retentionOpts := retention.NewOptions().
+		SetRetentionPeriod(20 * time.Hour).
+		SetBlockSize(2 * time.Hour).
+		SetBufferPast(10 * time.Minute).
+		SetBufferFuture(2 * time.Minute)
+	namesp, err := namespace.NewMetadata(testNamespaces[0], namespace.NewOptions().SetRetentionOptions(retentionOpts))
+	require.NoError(t, err)
+	opts := NewTestOptions(t).
+		SetNamespaces([]namespace.Metadata{namesp}).
+		// Use TChannel clients for writing / reading because we want to target individual nodes at a time
+		// and not write/read all nodes in the cluster.
+		SetUseTChannelClientForWriting(true).
+		SetUseTChannelClientForReading(true)
+
+	setupOpts := []BootstrappableTestSetupOptions{
+		{DisablePeersBootstrapper: true},
+		{
+			DisableCommitLogBootstrapper: true,
+			DisablePeersBootstrapper:     false,
+		},
+	}
+	setups, closeFn := NewDefaultBootstrappableTestSetups(t, opts, setupOpts)
+	defer closeFn()
+
+	// Offload the filesets of the first node to a local blob store.
+	blobDir, err := ioutil.TempDir("", "peers-bootstrap-offloaded")
+	require.NoError(t, err)
+	defer os.RemoveAll(blobDir)
+
+	store, err := blob.NewLocalStore(blobDir)
+	require.NoError(t, err)
+
+	storageOpts := setups[0].StorageOpts()
+	fsOpts := storageOpts.CommitLogOptions().FilesystemOptions()
+	offloader, err := fs.NewFileSetOffloader(store, fsOpts, fs.OffloadOptions{
+		OffloadAfter:       time.Minute,
+		MaxRestoredVolumes: 4,
+	})
+	require.NoError(t, err)
+
+	newRetrieverFn := func(
+		md namespace.Metadata,
+		shardSet sharding.ShardSet,
+	) (block.DatabaseBlockRetriever, error) {
+		retrieverOpts := fs.NewBlockRetrieverOptions().
+			SetBlockLeaseManager(storageOpts.BlockLeaseManager())
+		retriever, err := fs.NewBlockRetriever(retrieverOpts, fsOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := retriever.Open(md, shardSet); err != nil {
+			return nil, err
+		}
+		return retriever, nil
+	}
+	setups[0].SetStorageOpts(storageOpts.
+		SetFileSetOffloader(offloader).
+		SetDatabaseBlockRetrieverManager(
+			block.NewTieredDatabaseBlockRetrieverManager(newRetrieverFn, offloader)))
+
+	// Write test data for first node
+	now := setups[0].NowFn()()
+	blockSize := retentionOpts.BlockSize()
+	inputData := []generate.BlockConfig{
+		{IDs: []string{"foo", "baz"}, NumPoints: 90, Start: now.Add(-4 * blockSize)},
+		{IDs: []string{"foo", "baz"}, NumPoints: 90, Start: now.Add(-3 * blockSize)},
+		{IDs: []string{"foo", "baz"}, NumPoints: 90, Start: now.Add(-2 * blockSize)},
+		{IDs: []string{"foo", "baz"}, NumPoints: 90, Start: now.Add(-blockSize)},
+		{IDs: []string{"foo", "baz"}, NumPoints: 90, Start: now},
+	}
+	seriesMaps := generate.BlocksByStart(inputData)
+	require.NoError(t, writeTestDataToDisk(namesp, setups[0], seriesMaps, 0))
+
+	// Start the first server with filesystem bootstrapper
+	require.NoError(t, setups[0].StartServer())
+
+	// Offload the flushed blocks of the first server, their data files are
+	// then only available from the blob store.
+	earliestToRetain := retention.FlushTimeStart(retentionOpts, now)
+	for _, shard := range setups[0].ShardSet().AllIDs() {
+		require.NoError(t, offloader.Offload(namesp.ID(), shard, blockSize,
+			now, earliestToRetain))
+	}
+	var numObjects int
+	require.NoError(t, filepath.Walk(blobDir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			numObjects++
+		}
+		return err
+	}))
+	require.True(t, numObjects > 0)
+
+	// Start the last server with peers and filesystem bootstrappers
+	require.NoError(t, setups[1].StartServer())
+	log.Debug("servers are now up")
+
+	// Stop the servers
+	defer func() {
+		setups.parallel(func(s TestSetup) {
+			require.NoError(t, s.StopServer())
+		})
+		log.Debug("servers are now down")
+	}()
+
+	// Verify in-memory data match what we expect
+	for _, setup := range setups {
+		verifySeriesMaps(t, setup, namesp.ID(), seriesMaps)
+	}
+}
@@ -21,12 +21,25 @@
 package namespace
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/m3db/m3/src/dbnode/retention"
+)
+
+var (
+	errRollupAfterNotPositive       = errors.New("rollup policy rollup after must be positive")
+	errRollupStepNotPositive        = errors.New("rollup policy step must be positive")
+	errRollupRawRetentionNegative   = errors.New("rollup policy raw retention must not be negative")
+	errRollupAfterLessThanBuffer    = errors.New("rollup policy rollup after must be >= namespace buffer past")
+	errRollupAfterTooLarge          = errors.New("rollup policy rollup after must be < namespace retention period")
+	errRollupRawRetentionOutOfRange = errors.New("rollup policy raw retention must be >= rollup after and <= namespace retention period")
 )
 
 type aggregationOptions struct {
 	aggregations []Aggregation
+	rollupPolicy RollupPolicy
 }
 
 // NewAggregationOptions creates new AggregationOptions.
@@ -44,11 +57,25 @@ func (a *aggregationOptions) Aggregations() []Aggregation {
 	return a.aggregations
 }
 
+func (a *aggregationOptions) SetRollupPolicy(value RollupPolicy) AggregationOptions {
+	opts := *a
+	opts.rollupPolicy = value
+	return &opts
+}
+
+func (a *aggregationOptions) RollupPolicy() RollupPolicy {
+	return a.rollupPolicy
+}
+
 func (a *aggregationOptions) Equal(rhs AggregationOptions) bool {
 	if len(a.aggregations) != len(rhs.Aggregations()) {
 		return false
 	}
 
+	if a.rollupPolicy != rhs.RollupPolicy() {
+		return false
+	}
+
 	for i, agg := range rhs.Aggregations() {
 		if a.aggregations[i] != agg {
 			return false
@@ -88,3 +115,36 @@ func NewAggregatedAttributes(resolution time.Duration, downsampleOptions Downsam
 func NewDownsampleOptions(all bool) DownsampleOptions {
 	return DownsampleOptions{All: all}
 }
+
+// Enabled returns true if the rollup policy targets a namespace.
+func (p RollupPolicy) Enabled() bool {
+	return p.TargetNamespace != ""
+}
+
+// Validate validates the rollup policy against the retention options of
+// the namespace whose blocks are rolled up.
+func (p RollupPolicy) Validate(ropts retention.Options) error {
+	if !p.Enabled() {
+		return nil
+	}
+	if p.RollupAfter <= 0 {
+		return errRollupAfterNotPositive
+	}
+	if p.Step <= 0 {
+		return errRollupStepNotPositive
+	}
+	if p.RawRetention < 0 {
+		return errRollupRawRetentionNegative
+	}
+	if p.RollupAfter < ropts.BufferPast() {
+		return errRollupAfterLessThanBuffer
+	}
+	if p.RollupAfter >= ropts.RetentionPeriod() {
+		return errRollupAfterTooLarge
+	}
+	if p.RawRetention != 0 &&
+		(p.RawRetention < p.RollupAfter || p.RawRetention > ropts.RetentionPeriod()) {
+		return errRollupRawRetentionOutOfRange
+	}
+	return nil
+}
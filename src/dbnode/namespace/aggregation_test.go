@@ -24,6 +24,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/retention"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,3 +59,83 @@ func TestAggregationAttributesValidation(t *testing.T) {
 	_, err = NewAggregatedAttributes(-5*time.Minute, NewDownsampleOptions(true))
 	require.Error(t, err)
 }
+
+func TestAggregationEqualRollupPolicy(t *testing.T) {
+	policy := RollupPolicy{
+		TargetNamespace: "agg",
+		RollupAfter:     6 * time.Hour,
+		Step:            5 * time.Minute,
+	}
+	opts1 := NewAggregationOptions().SetRollupPolicy(policy)
+	opts2 := NewAggregationOptions().SetRollupPolicy(policy)
+	opts3 := NewAggregationOptions()
+
+	require.True(t, opts1.Equal(opts2))
+	require.False(t, opts1.Equal(opts3))
+	require.True(t, opts1.RollupPolicy().Enabled())
+	require.False(t, opts3.RollupPolicy().Enabled())
+}
+
+func TestRollupPolicyValidate(t *testing.T) {
+	ropts := retention.NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour).
+		SetBufferPast(10 * time.Minute)
+	valid := RollupPolicy{
+		TargetNamespace: "agg",
+		RollupAfter:     6 * time.Hour,
+		Step:            5 * time.Minute,
+		RawRetention:    24 * time.Hour,
+	}
+	require.NoError(t, valid.Validate(ropts))
+	require.NoError(t, RollupPolicy{}.Validate(ropts))
+
+	tests := []struct {
+		name   string
+		modify func(p *RollupPolicy)
+		err    error
+	}{
+		{
+			name:   "rollup after not positive",
+			modify: func(p *RollupPolicy) { p.RollupAfter = 0 },
+			err:    errRollupAfterNotPositive,
+		},
+		{
+			name:   "step not positive",
+			modify: func(p *RollupPolicy) { p.Step = 0 },
+			err:    errRollupStepNotPositive,
+		},
+		{
+			name:   "raw retention negative",
+			modify: func(p *RollupPolicy) { p.RawRetention = -time.Hour },
+			err:    errRollupRawRetentionNegative,
+		},
+		{
+			name:   "rollup after less than buffer past",
+			modify: func(p *RollupPolicy) { p.RollupAfter = time.Minute },
+			err:    errRollupAfterLessThanBuffer,
+		},
+		{
+			name:   "rollup after beyond retention",
+			modify: func(p *RollupPolicy) { p.RollupAfter = 48 * time.Hour },
+			err:    errRollupAfterTooLarge,
+		},
+		{
+			name:   "raw retention before rollup",
+			modify: func(p *RollupPolicy) { p.RawRetention = time.Hour },
+			err:    errRollupRawRetentionOutOfRange,
+		},
+		{
+			name:   "raw retention beyond retention",
+			modify: func(p *RollupPolicy) { p.RawRetention = 72 * time.Hour },
+			err:    errRollupRawRetentionOutOfRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := valid
+			tt.modify(&policy)
+			require.Equal(t, tt.err, policy.Validate(ropts))
+		})
+	}
+}
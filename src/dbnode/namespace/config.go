@@ -55,7 +55,9 @@ type MetadataConfiguration struct {
 	CleanupEnabled        *bool                   `yaml:"cleanupEnabled"`
 	RepairEnabled         *bool                   `yaml:"repairEnabled"`
 	ColdWritesEnabled     *bool                   `yaml:"coldWritesEnabled"`
+	LateWritesEnabled     *bool                   `yaml:"lateWritesEnabled"`
 	CacheBlocksOnRetrieve *bool                   `yaml:"cacheBlocksOnRetrieve"`
+	EncodingScheme        *EncodingScheme         `yaml:"encodingScheme"`
 	Retention             retention.Configuration `yaml:"retention" validate:"nonzero"`
 	Index                 IndexConfiguration      `yaml:"index"`
 }
@@ -85,9 +87,15 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 	if v := mc.ColdWritesEnabled; v != nil {
 		opts = opts.SetColdWritesEnabled(*v)
 	}
+	if v := mc.LateWritesEnabled; v != nil {
+		opts = opts.SetLateWritesEnabled(*v)
+	}
 	if v := mc.CacheBlocksOnRetrieve; v != nil {
 		opts = opts.SetCacheBlocksOnRetrieve(*v)
 	}
+	if v := mc.EncodingScheme; v != nil {
+		opts = opts.SetEncodingScheme(*v)
+	}
 	return NewMetadata(ident.StringID(mc.ID), opts)
 }
 
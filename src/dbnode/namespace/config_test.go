@@ -147,11 +147,12 @@ metadatas:
     writesToCommitLog: true
     cleanupEnabled: true
     repairEnabled: true
+    encodingScheme: xor
     retention:
       retentionPeriod: 48h
-      blockSize: 2h
-      bufferFuture: 10m
-      bufferPast: 10m
+      blockSize: 1m
+      bufferFuture: 10s
+      bufferPast: 10s
   - id: "metrics-1m:40d"
     bootstrapEnabled: true
     flushEnabled: true
@@ -187,6 +188,7 @@ metadatas:
 	require.Equal(t, false, opts.CleanupEnabled())
 	require.Equal(t, false, opts.RepairEnabled())
 	require.Equal(t, false, opts.IndexOptions().Enabled())
+	require.Equal(t, M3TSZEncodingScheme, opts.EncodingScheme())
 	testRetentionOpts := retention.NewOptions().
 		SetRetentionPeriod(8 * time.Hour).
 		SetBlockSize(2 * time.Hour).
@@ -199,6 +201,7 @@ metadatas:
 	require.NoError(t, err)
 	require.True(t, ns.ID().Equal(metrics2d))
 	opts = ns.Options()
+	require.Equal(t, XOREncodingScheme, opts.EncodingScheme())
 	require.Equal(t, true, opts.BootstrapEnabled())
 	require.Equal(t, true, opts.FlushEnabled())
 	require.Equal(t, true, opts.WritesToCommitLog())
@@ -207,9 +210,9 @@ metadatas:
 	require.Equal(t, false, opts.IndexOptions().Enabled())
 	testRetentionOpts = retention.NewOptions().
 		SetRetentionPeriod(48 * time.Hour).
-		SetBlockSize(2 * time.Hour).
-		SetBufferFuture(10 * time.Minute).
-		SetBufferPast(10 * time.Minute)
+		SetBlockSize(time.Minute).
+		SetBufferFuture(10 * time.Second).
+		SetBufferPast(10 * time.Second)
 	require.True(t, testRetentionOpts.Equal(opts.RetentionOptions()))
 
 	metrics40d := ident.StringID("metrics-1m:40d")
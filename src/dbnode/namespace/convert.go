@@ -196,6 +196,16 @@ func ToMetadata(
 		return nil, err
 	}
 
+	resizeState, err := ToResizeState(opts.ResizeState)
+	if err != nil {
+		return nil, err
+	}
+
+	encodingScheme, err := NewEncodingScheme(opts.EncodingScheme)
+	if err != nil {
+		return nil, err
+	}
+
 	mOpts := NewOptions().
 		SetBootstrapEnabled(opts.BootstrapEnabled).
 		SetFlushEnabled(opts.FlushEnabled).
@@ -207,10 +217,13 @@ func ToMetadata(
 		SetRetentionOptions(rOpts).
 		SetIndexOptions(iOpts).
 		SetColdWritesEnabled(opts.ColdWritesEnabled).
+		SetLateWritesEnabled(opts.LateWritesEnabled).
 		SetRuntimeOptions(runtimeOpts).
 		SetExtendedOptions(extendedOpts).
 		SetAggregationOptions(aggOpts).
-		SetStagingState(stagingState)
+		SetStagingState(stagingState).
+		SetResizeState(resizeState).
+		SetEncodingScheme(encodingScheme)
 
 	if opts.CacheBlocksOnRetrieve != nil {
 		mOpts = mOpts.SetCacheBlocksOnRetrieve(opts.CacheBlocksOnRetrieve.Value)
@@ -232,10 +245,37 @@ func ToStagingState(state *nsproto.StagingState) (StagingState, error) {
 	return NewStagingState(state.Status)
 }
 
+// ToResizeState converts nsproto.ResizeState to ResizeState.
+func ToResizeState(state *nsproto.ResizeState) (ResizeState, error) {
+	if state == nil {
+		return ResizeState{}, nil
+	}
+
+	if state.BlockSizeNanos < 0 || state.IndexBlockSizeNanos < 0 {
+		return ResizeState{}, fmt.Errorf("invalid resize state: %v", state)
+	}
+
+	return ResizeState{
+		BlockSize:      time.Duration(state.BlockSizeNanos),
+		IndexBlockSize: time.Duration(state.IndexBlockSizeNanos),
+	}, nil
+}
+
 // ToAggregationOptions converts nsproto.AggregationOptions to AggregationOptions.
 func ToAggregationOptions(opts *nsproto.AggregationOptions) (AggregationOptions, error) {
 	aggOpts := NewAggregationOptions()
-	if opts == nil || len(opts.Aggregations) == 0 {
+	if opts == nil {
+		return aggOpts, nil
+	}
+	if policy := opts.RollupPolicy; policy != nil {
+		aggOpts = aggOpts.SetRollupPolicy(RollupPolicy{
+			TargetNamespace: policy.TargetNamespace,
+			RollupAfter:     time.Duration(policy.RollupAfterNanos),
+			Step:            time.Duration(policy.StepNanos),
+			RawRetention:    time.Duration(policy.RawRetentionNanos),
+		})
+	}
+	if len(opts.Aggregations) == 0 {
 		return aggOpts, nil
 	}
 	aggregations := make([]Aggregation, 0, len(opts.Aggregations))
@@ -313,6 +353,11 @@ func OptionsToProto(opts Options) (*nsproto.NamespaceOptions, error) {
 		return nil, err
 	}
 
+	encodingScheme, err := toProtoEncodingScheme(opts.EncodingScheme())
+	if err != nil {
+		return nil, err
+	}
+
 	nsOpts := &nsproto.NamespaceOptions{
 		BootstrapEnabled:  opts.BootstrapEnabled(),
 		FlushEnabled:      opts.FlushEnabled(),
@@ -335,11 +380,14 @@ func OptionsToProto(opts Options) (*nsproto.NamespaceOptions, error) {
 			BlockSizeNanos: iopts.BlockSize().Nanoseconds(),
 		},
 		ColdWritesEnabled:     opts.ColdWritesEnabled(),
+		LateWritesEnabled:     opts.LateWritesEnabled(),
 		RuntimeOptions:        toRuntimeOptions(opts.RuntimeOptions()),
 		CacheBlocksOnRetrieve: &protobuftypes.BoolValue{Value: opts.CacheBlocksOnRetrieve()},
 		ExtendedOptions:       extendedOpts,
 		AggregationOptions:    toProtoAggregationOptions(opts.AggregationOptions()),
 		StagingState:          stagingState,
+		ResizeState:           toProtoResizeState(opts.ResizeState()),
+		EncodingScheme:        encodingScheme,
 	}
 
 	return nsOpts, nil
@@ -361,8 +409,41 @@ func toProtoStagingState(state StagingState) (*nsproto.StagingState, error) {
 	return &nsproto.StagingState{Status: protoStatus}, nil
 }
 
+func toProtoEncodingScheme(scheme EncodingScheme) (nsproto.EncodingScheme, error) {
+	switch scheme {
+	case M3TSZEncodingScheme:
+		return nsproto.EncodingScheme_M3TSZ, nil
+	case XOREncodingScheme:
+		return nsproto.EncodingScheme_XOR, nil
+	}
+	return 0, fmt.Errorf("invalid encoding scheme: %v", scheme)
+}
+
+func toProtoResizeState(state ResizeState) *nsproto.ResizeState {
+	if !state.InProgress() {
+		return nil
+	}
+
+	return &nsproto.ResizeState{
+		BlockSizeNanos:      state.BlockSize.Nanoseconds(),
+		IndexBlockSizeNanos: state.IndexBlockSize.Nanoseconds(),
+	}
+}
+
 func toProtoAggregationOptions(aggOpts AggregationOptions) *nsproto.AggregationOptions {
-	if aggOpts == nil || len(aggOpts.Aggregations()) == 0 {
+	if aggOpts == nil {
+		return nil
+	}
+	var protoPolicy *nsproto.RollupPolicy
+	if policy := aggOpts.RollupPolicy(); policy.Enabled() {
+		protoPolicy = &nsproto.RollupPolicy{
+			TargetNamespace:   policy.TargetNamespace,
+			RollupAfterNanos:  policy.RollupAfter.Nanoseconds(),
+			StepNanos:         policy.Step.Nanoseconds(),
+			RawRetentionNanos: policy.RawRetention.Nanoseconds(),
+		}
+	}
+	if len(aggOpts.Aggregations()) == 0 && protoPolicy == nil {
 		return nil
 	}
 	protoAggs := make([]*nsproto.Aggregation, 0, len(aggOpts.Aggregations()))
@@ -376,7 +457,10 @@ func toProtoAggregationOptions(aggOpts AggregationOptions) *nsproto.AggregationO
 		}
 		protoAggs = append(protoAggs, &protoAgg)
 	}
-	return &nsproto.AggregationOptions{Aggregations: protoAggs}
+	return &nsproto.AggregationOptions{
+		Aggregations: protoAggs,
+		RollupPolicy: protoPolicy,
+	}
 }
 
 // toRuntimeOptions returns the corresponding RuntimeOptions proto.
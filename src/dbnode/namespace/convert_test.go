@@ -324,6 +324,57 @@ func TestFromProtoSnapshotEnabled(t *testing.T) {
 	require.Equal(t, !namespace.NewOptions().SnapshotEnabled(), md.Options().SnapshotEnabled())
 }
 
+func TestEncodingSchemeToFromProto(t *testing.T) {
+	md, err := namespace.NewMetadata(
+		ident.StringID("ns1"),
+		namespace.NewOptions().
+			SetEncodingScheme(namespace.XOREncodingScheme).
+			SetRetentionOptions(retention.NewOptions().
+				SetBlockSize(time.Minute).
+				SetBufferPast(10*time.Second).
+				SetBufferFuture(10*time.Second)),
+	)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg, err := namespace.ToProto(nsMap)
+	require.NoError(t, err)
+	require.Equal(t, nsproto.EncodingScheme_XOR, reg.Namespaces["ns1"].EncodingScheme)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err = nsMap.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.Equal(t, namespace.XOREncodingScheme, md.Options().EncodingScheme())
+
+	reg.Namespaces["ns1"].EncodingScheme = nsproto.EncodingScheme(42)
+	_, err = namespace.FromProto(*reg)
+	require.Error(t, err)
+}
+
+func TestLateWritesEnabledToFromProto(t *testing.T) {
+	md, err := namespace.NewMetadata(
+		ident.StringID("ns1"),
+		namespace.NewOptions().
+			SetColdWritesEnabled(true).
+			SetLateWritesEnabled(true),
+	)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg, err := namespace.ToProto(nsMap)
+	require.NoError(t, err)
+	require.True(t, reg.Namespaces["ns1"].LateWritesEnabled)
+
+	nsMap, err = namespace.FromProto(*reg)
+	require.NoError(t, err)
+	md, err = nsMap.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.True(t, md.Options().LateWritesEnabled())
+}
+
 func TestInvalidExtendedOptions(t *testing.T) {
 	invalidExtendedOptsNoConverterForType := &nsproto.ExtendedOptions{Type: "unknown"}
 	_, err := namespace.ToExtendedOptions(invalidExtendedOptsNoConverterForType)
@@ -385,6 +436,37 @@ func TestAggregationOptsToProto(t *testing.T) {
 	require.Equal(t, validAggregationOpts, *nsOpts.AggregationOptions)
 }
 
+func TestRollupPolicyToFromProto(t *testing.T) {
+	protoOpts := nsproto.AggregationOptions{
+		Aggregations: []*nsproto.Aggregation{},
+		RollupPolicy: &nsproto.RollupPolicy{
+			TargetNamespace:   "agg",
+			RollupAfterNanos:  int64(6 * time.Hour),
+			StepNanos:         int64(5 * time.Minute),
+			RawRetentionNanos: int64(24 * time.Hour),
+		},
+	}
+	aggOpts, err := namespace.ToAggregationOptions(&protoOpts)
+	require.NoError(t, err)
+	require.Equal(t, namespace.RollupPolicy{
+		TargetNamespace: "agg",
+		RollupAfter:     6 * time.Hour,
+		Step:            5 * time.Minute,
+		RawRetention:    24 * time.Hour,
+	}, aggOpts.RollupPolicy())
+
+	md, err := namespace.NewMetadata(ident.StringID("ns1"),
+		namespace.NewOptions().SetAggregationOptions(aggOpts))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg, err := namespace.ToProto(nsMap)
+	require.NoError(t, err)
+	require.Len(t, reg.Namespaces, 1)
+	require.Equal(t, protoOpts, *reg.Namespaces["ns1"].AggregationOptions)
+}
+
 func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOptions, observed namespace.Metadata) {
 	require.Equal(t, name, observed.ID().String())
 	opts := observed.Options()
@@ -445,3 +527,33 @@ func assertEqualStagingState(t *testing.T, expected *nsproto.StagingState, obser
 
 	require.Equal(t, state, observed)
 }
+
+func TestResizeStateToFromProto(t *testing.T) {
+	md, err := namespace.NewMetadata(ident.StringID("ns1"),
+		namespace.NewOptions().
+			SetRetentionOptions(retention.NewOptions().SetBlockSize(2*time.Hour)).
+			SetIndexOptions(namespace.NewIndexOptions().SetEnabled(true).SetBlockSize(2*time.Hour)).
+			SetResizeState(namespace.ResizeState{
+				BlockSize:      4 * time.Hour,
+				IndexBlockSize: 4 * time.Hour,
+			}))
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md})
+	require.NoError(t, err)
+
+	reg, err := namespace.ToProto(nsMap)
+	require.NoError(t, err)
+	require.Equal(t, &nsproto.ResizeState{
+		BlockSizeNanos:      int64(4 * time.Hour),
+		IndexBlockSizeNanos: int64(4 * time.Hour),
+	}, reg.Namespaces["ns1"].ResizeState)
+
+	fromProto, err := namespace.FromProto(*reg)
+	require.NoError(t, err)
+	observed, err := fromProto.Get(ident.StringID("ns1"))
+	require.NoError(t, err)
+	require.True(t, md.Equal(observed))
+
+	_, err = namespace.ToResizeState(&nsproto.ResizeState{BlockSizeNanos: -1})
+	require.Error(t, err)
+}
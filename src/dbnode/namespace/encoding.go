@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"errors"
+	"fmt"
+
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+)
+
+var errEncodingSchemeUnspecified = errors.New("encoding scheme unspecified")
+
+// EncodingScheme is the scheme used to encode the datapoints of a namespace.
+type EncodingScheme uint8
+
+const (
+	// M3TSZEncodingScheme encodes datapoints with the M3TSZ encoding.
+	M3TSZEncodingScheme EncodingScheme = iota
+	// XOREncodingScheme encodes datapoints with an encoding that is
+	// bit-compatible with the XOR chunk format of Prometheus TSDB blocks.
+	// Timestamps are truncated to milliseconds and annotations are dropped.
+	XOREncodingScheme
+)
+
+// ValidEncodingSchemes returns the valid encoding schemes.
+func ValidEncodingSchemes() []EncodingScheme {
+	return []EncodingScheme{M3TSZEncodingScheme, XOREncodingScheme}
+}
+
+// Validate validates the EncodingScheme.
+func (s EncodingScheme) Validate() error {
+	for _, valid := range ValidEncodingSchemes() {
+		if s == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("encoding scheme %d is invalid", s)
+}
+
+func (s EncodingScheme) String() string {
+	switch s {
+	case M3TSZEncodingScheme:
+		return "m3tsz"
+	case XOREncodingScheme:
+		return "xor"
+	}
+	return "unknown"
+}
+
+// ParseEncodingScheme parses an EncodingScheme from a string.
+func ParseEncodingScheme(str string) (EncodingScheme, error) {
+	var s EncodingScheme
+	if str == "" {
+		return s, errEncodingSchemeUnspecified
+	}
+	for _, valid := range ValidEncodingSchemes() {
+		if str == valid.String() {
+			return valid, nil
+		}
+	}
+	return s, fmt.Errorf("invalid encoding scheme '%s' valid types are: %v",
+		str, ValidEncodingSchemes())
+}
+
+// UnmarshalYAML unmarshals an EncodingScheme into a valid type from string.
+func (s *EncodingScheme) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParseEncodingScheme(str)
+	if err != nil {
+		return err
+	}
+	*s = r
+	return nil
+}
+
+// NewEncodingScheme creates a new EncodingScheme from its proto representation.
+func NewEncodingScheme(scheme nsproto.EncodingScheme) (EncodingScheme, error) {
+	switch scheme {
+	case nsproto.EncodingScheme_M3TSZ:
+		return M3TSZEncodingScheme, nil
+	case nsproto.EncodingScheme_XOR:
+		return XOREncodingScheme, nil
+	}
+	return 0, fmt.Errorf("invalid encoding scheme: %v", scheme)
+}
@@ -131,6 +131,20 @@ func (mr *MockOptionsMockRecorder) ColdWritesEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdWritesEnabled", reflect.TypeOf((*MockOptions)(nil).ColdWritesEnabled))
 }
 
+// EncodingScheme mocks base method.
+func (m *MockOptions) EncodingScheme() EncodingScheme {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncodingScheme")
+	ret0, _ := ret[0].(EncodingScheme)
+	return ret0
+}
+
+// EncodingScheme indicates an expected call of EncodingScheme.
+func (mr *MockOptionsMockRecorder) EncodingScheme() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncodingScheme", reflect.TypeOf((*MockOptions)(nil).EncodingScheme))
+}
+
 // Equal mocks base method.
 func (m *MockOptions) Equal(value Options) bool {
 	m.ctrl.T.Helper()
@@ -187,6 +201,20 @@ func (mr *MockOptionsMockRecorder) IndexOptions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexOptions", reflect.TypeOf((*MockOptions)(nil).IndexOptions))
 }
 
+// LateWritesEnabled mocks base method.
+func (m *MockOptions) LateWritesEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LateWritesEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// LateWritesEnabled indicates an expected call of LateWritesEnabled.
+func (mr *MockOptionsMockRecorder) LateWritesEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LateWritesEnabled", reflect.TypeOf((*MockOptions)(nil).LateWritesEnabled))
+}
+
 // RepairEnabled mocks base method.
 func (m *MockOptions) RepairEnabled() bool {
 	m.ctrl.T.Helper()
@@ -201,6 +229,20 @@ func (mr *MockOptionsMockRecorder) RepairEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairEnabled", reflect.TypeOf((*MockOptions)(nil).RepairEnabled))
 }
 
+// ResizeState mocks base method.
+func (m *MockOptions) ResizeState() ResizeState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResizeState")
+	ret0, _ := ret[0].(ResizeState)
+	return ret0
+}
+
+// ResizeState indicates an expected call of ResizeState.
+func (mr *MockOptionsMockRecorder) ResizeState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeState", reflect.TypeOf((*MockOptions)(nil).ResizeState))
+}
+
 // RetentionOptions mocks base method.
 func (m *MockOptions) RetentionOptions() retention.Options {
 	m.ctrl.T.Helper()
@@ -313,6 +355,20 @@ func (mr *MockOptionsMockRecorder) SetColdWritesEnabled(value interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetColdWritesEnabled", reflect.TypeOf((*MockOptions)(nil).SetColdWritesEnabled), value)
 }
 
+// SetEncodingScheme mocks base method.
+func (m *MockOptions) SetEncodingScheme(value EncodingScheme) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEncodingScheme", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetEncodingScheme indicates an expected call of SetEncodingScheme.
+func (mr *MockOptionsMockRecorder) SetEncodingScheme(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEncodingScheme", reflect.TypeOf((*MockOptions)(nil).SetEncodingScheme), value)
+}
+
 // SetExtendedOptions mocks base method.
 func (m *MockOptions) SetExtendedOptions(value ExtendedOptions) Options {
 	m.ctrl.T.Helper()
@@ -355,6 +411,20 @@ func (mr *MockOptionsMockRecorder) SetIndexOptions(value interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIndexOptions", reflect.TypeOf((*MockOptions)(nil).SetIndexOptions), value)
 }
 
+// SetLateWritesEnabled mocks base method.
+func (m *MockOptions) SetLateWritesEnabled(value bool) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLateWritesEnabled", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetLateWritesEnabled indicates an expected call of SetLateWritesEnabled.
+func (mr *MockOptionsMockRecorder) SetLateWritesEnabled(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLateWritesEnabled", reflect.TypeOf((*MockOptions)(nil).SetLateWritesEnabled), value)
+}
+
 // SetRepairEnabled mocks base method.
 func (m *MockOptions) SetRepairEnabled(value bool) Options {
 	m.ctrl.T.Helper()
@@ -369,6 +439,20 @@ func (mr *MockOptionsMockRecorder) SetRepairEnabled(value interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepairEnabled", reflect.TypeOf((*MockOptions)(nil).SetRepairEnabled), value)
 }
 
+// SetResizeState mocks base method.
+func (m *MockOptions) SetResizeState(value ResizeState) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResizeState", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetResizeState indicates an expected call of SetResizeState.
+func (mr *MockOptionsMockRecorder) SetResizeState(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResizeState", reflect.TypeOf((*MockOptions)(nil).SetResizeState), value)
+}
+
 // SetRetentionOptions mocks base method.
 func (m *MockOptions) SetRetentionOptions(value retention.Options) Options {
 	m.ctrl.T.Helper()
@@ -1550,6 +1634,20 @@ func (mr *MockAggregationOptionsMockRecorder) Equal(value interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Equal", reflect.TypeOf((*MockAggregationOptions)(nil).Equal), value)
 }
 
+// RollupPolicy mocks base method.
+func (m *MockAggregationOptions) RollupPolicy() RollupPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupPolicy")
+	ret0, _ := ret[0].(RollupPolicy)
+	return ret0
+}
+
+// RollupPolicy indicates an expected call of RollupPolicy.
+func (mr *MockAggregationOptionsMockRecorder) RollupPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupPolicy", reflect.TypeOf((*MockAggregationOptions)(nil).RollupPolicy))
+}
+
 // SetAggregations mocks base method.
 func (m *MockAggregationOptions) SetAggregations(value []Aggregation) AggregationOptions {
 	m.ctrl.T.Helper()
@@ -1563,3 +1661,17 @@ func (mr *MockAggregationOptionsMockRecorder) SetAggregations(value interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAggregations", reflect.TypeOf((*MockAggregationOptions)(nil).SetAggregations), value)
 }
+// SetRollupPolicy mocks base method.
+func (m *MockAggregationOptions) SetRollupPolicy(value RollupPolicy) AggregationOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRollupPolicy", value)
+	ret0, _ := ret[0].(AggregationOptions)
+	return ret0
+}
+
+// SetRollupPolicy indicates an expected call of SetRollupPolicy.
+func (mr *MockAggregationOptionsMockRecorder) SetRollupPolicy(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRollupPolicy", reflect.TypeOf((*MockAggregationOptions)(nil).SetRollupPolicy), value)
+}
+
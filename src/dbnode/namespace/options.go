@@ -22,6 +22,9 @@ package namespace
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/retention"
 )
@@ -48,9 +51,24 @@ const (
 	// Namespace with cold writes disabled by default.
 	defaultColdWritesEnabled = false
 
+	// Namespace with late writes disabled by default.
+	defaultLateWritesEnabled = false
+
+	// Namespace encoded with M3TSZ by default.
+	defaultEncodingScheme = M3TSZEncodingScheme
+
 	// Namespace does not cache retrieved blocks by default since this is only
 	// useful specifically for usage patterns tending towards heavy historical reads.
 	defaultCacheBlocksOnRetrieve = false
+
+	// xorMaxSamples is the maximum number of datapoints of a series block
+	// encoded with the xor encoding scheme, which matches the number of
+	// samples a Prometheus XOR chunk can hold.
+	xorMaxSamples = math.MaxUint16
+
+	// xorTimestampPrecision is the precision of timestamps encoded with the
+	// xor encoding scheme.
+	xorTimestampPrecision = time.Millisecond
 )
 
 var (
@@ -59,6 +77,8 @@ var (
 	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
 	errNamespaceRuntimeOptionsNotSet                = errors.New("namespace runtime options is not set")
 	errAggregationOptionsNotSet                     = errors.New("aggregation options is not set")
+	errLateWritesRequireColdWrites                  = errors.New("late writes require cold writes to be enabled")
+	errXOREncodingSchemeDoesNotSupportSchemas       = errors.New("xor encoding scheme does not support schemas")
 )
 
 type options struct {
@@ -69,7 +89,9 @@ type options struct {
 	cleanupEnabled        bool
 	repairEnabled         bool
 	coldWritesEnabled     bool
+	lateWritesEnabled     bool
 	cacheBlocksOnRetrieve bool
+	encodingScheme        EncodingScheme
 	retentionOpts         retention.Options
 	indexOpts             IndexOptions
 	schemaHis             SchemaHistory
@@ -77,6 +99,7 @@ type options struct {
 	extendedOpts          ExtendedOptions
 	aggregationOpts       AggregationOptions
 	stagingState          StagingState
+	resizeState           ResizeState
 }
 
 // NewSchemaHistory returns an empty schema history.
@@ -94,7 +117,9 @@ func NewOptions() Options {
 		cleanupEnabled:        defaultCleanupEnabled,
 		repairEnabled:         defaultRepairEnabled,
 		coldWritesEnabled:     defaultColdWritesEnabled,
+		lateWritesEnabled:     defaultLateWritesEnabled,
 		cacheBlocksOnRetrieve: defaultCacheBlocksOnRetrieve,
+		encodingScheme:        defaultEncodingScheme,
 		retentionOpts:         retention.NewOptions(),
 		indexOpts:             NewIndexOptions(),
 		schemaHis:             NewSchemaHistory(),
@@ -118,6 +143,32 @@ func (o *options) Validate() error {
 		return err
 	}
 
+	if o.aggregationOpts != nil {
+		if err := o.aggregationOpts.RollupPolicy().Validate(o.retentionOpts); err != nil {
+			return err
+		}
+	}
+
+	if err := o.resizeState.Validate(o.retentionOpts); err != nil {
+		return err
+	}
+
+	if o.lateWritesEnabled && !o.coldWritesEnabled {
+		return errLateWritesRequireColdWrites
+	}
+
+	if err := o.encodingScheme.Validate(); err != nil {
+		return err
+	}
+	if _, ok := o.schemaHis.GetLatest(); ok && o.encodingScheme == XOREncodingScheme {
+		return errXOREncodingSchemeDoesNotSupportSchemas
+	}
+	if o.encodingScheme == XOREncodingScheme {
+		if err := o.validateXORBlockSize(); err != nil {
+			return err
+		}
+	}
+
 	if !o.indexOpts.Enabled() {
 		return nil
 	}
@@ -146,6 +197,42 @@ func (o *options) Validate() error {
 	return nil
 }
 
+// validateXORBlockSize validates that a series block of the namespace cannot
+// hold more datapoints than the xor encoding scheme can encode, which is the
+// case if the block size divided by the finest resolution datapoints can be
+// written at does not exceed the maximum number of samples of a chunk.
+func (o *options) validateXORBlockSize() error {
+	var (
+		blockSize  = o.retentionOpts.BlockSize()
+		resolution = o.minResolution()
+	)
+	if blockSize/resolution > xorMaxSamples {
+		return fmt.Errorf(
+			"xor encoding scheme cannot hold more than %d datapoints per block: "+
+				"blockSize=%s, resolution=%s", xorMaxSamples, blockSize, resolution)
+	}
+	return nil
+}
+
+// minResolution returns the finest resolution datapoints of the namespace
+// can be written at, which is the finest resolution of its aggregations if
+// all datapoints are aggregated and the xor timestamp precision otherwise.
+func (o *options) minResolution() time.Duration {
+	if o.aggregationOpts == nil || len(o.aggregationOpts.Aggregations()) == 0 {
+		return xorTimestampPrecision
+	}
+	var resolution time.Duration
+	for _, agg := range o.aggregationOpts.Aggregations() {
+		if !agg.Aggregated || agg.Attributes.Resolution < xorTimestampPrecision {
+			return xorTimestampPrecision
+		}
+		if resolution == 0 || agg.Attributes.Resolution < resolution {
+			resolution = agg.Attributes.Resolution
+		}
+	}
+	return resolution
+}
+
 func (o *options) Equal(value Options) bool {
 	return o.bootstrapEnabled == value.BootstrapEnabled() &&
 		o.flushEnabled == value.FlushEnabled() &&
@@ -154,13 +241,16 @@ func (o *options) Equal(value Options) bool {
 		o.cleanupEnabled == value.CleanupEnabled() &&
 		o.repairEnabled == value.RepairEnabled() &&
 		o.coldWritesEnabled == value.ColdWritesEnabled() &&
+		o.lateWritesEnabled == value.LateWritesEnabled() &&
 		o.cacheBlocksOnRetrieve == value.CacheBlocksOnRetrieve() &&
+		o.encodingScheme == value.EncodingScheme() &&
 		o.retentionOpts.Equal(value.RetentionOptions()) &&
 		o.indexOpts.Equal(value.IndexOptions()) &&
 		o.schemaHis.Equal(value.SchemaHistory()) &&
 		o.runtimeOpts.Equal(value.RuntimeOptions()) &&
 		o.aggregationOpts.Equal(value.AggregationOptions()) &&
-		o.stagingState == value.StagingState()
+		o.stagingState == value.StagingState() &&
+		o.resizeState == value.ResizeState()
 }
 
 func (o *options) SetBootstrapEnabled(value bool) Options {
@@ -233,6 +323,26 @@ func (o *options) ColdWritesEnabled() bool {
 	return o.coldWritesEnabled
 }
 
+func (o *options) SetLateWritesEnabled(value bool) Options {
+	opts := *o
+	opts.lateWritesEnabled = value
+	return &opts
+}
+
+func (o *options) LateWritesEnabled() bool {
+	return o.lateWritesEnabled
+}
+
+func (o *options) SetEncodingScheme(value EncodingScheme) Options {
+	opts := *o
+	opts.encodingScheme = value
+	return &opts
+}
+
+func (o *options) EncodingScheme() EncodingScheme {
+	return o.encodingScheme
+}
+
 func (o *options) SetCacheBlocksOnRetrieve(value bool) Options {
 	opts := *o
 	opts.cacheBlocksOnRetrieve = value
@@ -312,3 +422,13 @@ func (o *options) SetStagingState(value StagingState) Options {
 func (o *options) StagingState() StagingState {
 	return o.stagingState
 }
+
+func (o *options) SetResizeState(value ResizeState) Options {
+	opts := *o
+	opts.resizeState = value
+	return &opts
+}
+
+func (o *options) ResizeState() ResizeState {
+	return o.resizeState
+}
@@ -217,3 +217,58 @@ func TestOptionsValidateStagingStatus(t *testing.T) {
 	o1 = o1.SetStagingState(StagingState{status: StagingStatus(12)})
 	require.Error(t, o1.Validate())
 }
+
+func TestOptionsValidateEncodingScheme(t *testing.T) {
+	attrs, err := NewAggregatedAttributes(time.Minute, NewDownsampleOptions(true))
+	require.NoError(t, err)
+	o1 := NewOptions().
+		SetEncodingScheme(XOREncodingScheme).
+		SetAggregationOptions(NewAggregationOptions().
+			SetAggregations([]Aggregation{NewAggregatedAggregation(attrs)}))
+	require.NoError(t, o1.Validate())
+	require.Equal(t, XOREncodingScheme, o1.EncodingScheme())
+	require.False(t, o1.Equal(NewOptions()))
+
+	require.Error(t, NewOptions().SetEncodingScheme(EncodingScheme(42)).Validate())
+
+	schemaHistory, err := LoadSchemaHistory(testSchemaOptions)
+	require.NoError(t, err)
+	o1 = o1.SetSchemaHistory(schemaHistory)
+	require.Equal(t, errXOREncodingSchemeDoesNotSupportSchemas, o1.Validate())
+}
+
+func TestOptionsValidateXOREncodingSchemeBlockSize(t *testing.T) {
+	// Unaggregated datapoints can be written at the xor timestamp precision.
+	o1 := NewOptions().SetEncodingScheme(XOREncodingScheme)
+	require.Error(t, o1.Validate())
+
+	o1 = o1.SetRetentionOptions(o1.RetentionOptions().
+		SetBlockSize(time.Minute).
+		SetBufferPast(10 * time.Second).
+		SetBufferFuture(10 * time.Second))
+	require.NoError(t, o1.Validate())
+
+	// Aggregated datapoints are written at the finest aggregation resolution.
+	var aggregations []Aggregation
+	for _, resolution := range []time.Duration{time.Minute, 100 * time.Millisecond} {
+		attrs, err := NewAggregatedAttributes(resolution, NewDownsampleOptions(true))
+		require.NoError(t, err)
+		aggregations = append(aggregations, NewAggregatedAggregation(attrs))
+	}
+	o1 = o1.
+		SetRetentionOptions(o1.RetentionOptions().SetBlockSize(2 * time.Hour)).
+		SetAggregationOptions(NewAggregationOptions().SetAggregations(aggregations))
+	require.Error(t, o1.Validate())
+
+	o1 = o1.SetRetentionOptions(o1.RetentionOptions().SetBlockSize(time.Hour))
+	require.NoError(t, o1.Validate())
+}
+
+func TestOptionsValidateLateWritesRequireColdWrites(t *testing.T) {
+	o1 := NewOptions().SetLateWritesEnabled(true)
+	require.Equal(t, errLateWritesRequireColdWrites, o1.Validate())
+
+	o1 = o1.SetColdWritesEnabled(true)
+	require.NoError(t, o1.Validate())
+	require.False(t, o1.Equal(NewOptions().SetColdWritesEnabled(true)))
+}
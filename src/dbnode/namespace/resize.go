@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+const resizeStatusKeyPrefix = "m3db.node.namespace-resize"
+
+var (
+	errResizeBlockSizeNotPositive      = errors.New("resize block size must be positive")
+	errResizeIndexBlockSizeNotPositive = errors.New("resize index block size must be positive")
+	errResizeIndexBlockSizeWithoutSize = errors.New("resize index block size set without a resize block size")
+	errResizeBlockSizeUnchanged        = errors.New("resize block size must differ from the current block size")
+	errResizeBlockSizeNotAligned       = errors.New("resize block size must be a multiple or a divisor of the current block size")
+	errResizeBlockSizeTooLarge         = errors.New("resize block size must not exceed the retention period")
+	errResizeIndexBlockSizeNotMultiple = errors.New("resize index block size must be a multiple of the resize block size")
+)
+
+// ResizeState is the state of an in-progress online resize of a namespace's
+// block sizes. While a resize is in progress nodes convert the sealed
+// filesets of the namespace to the target block size in the background and
+// keep serving from the current block sizes until the resize is completed.
+type ResizeState struct {
+	// BlockSize is the data block size being converted to.
+	BlockSize time.Duration
+	// IndexBlockSize is the index block size being converted to.
+	IndexBlockSize time.Duration
+}
+
+// InProgress returns whether a resize is in progress.
+func (s ResizeState) InProgress() bool {
+	return s.BlockSize != 0
+}
+
+// Validate validates the resize state against the current retention options.
+func (s ResizeState) Validate(ropts retention.Options) error {
+	if !s.InProgress() {
+		if s.IndexBlockSize != 0 {
+			return errResizeIndexBlockSizeWithoutSize
+		}
+		return nil
+	}
+
+	currBlockSize := ropts.BlockSize()
+	switch {
+	case s.BlockSize < 0:
+		return errResizeBlockSizeNotPositive
+	case s.IndexBlockSize <= 0:
+		return errResizeIndexBlockSizeNotPositive
+	case s.BlockSize == currBlockSize:
+		return errResizeBlockSizeUnchanged
+	case s.BlockSize%currBlockSize != 0 && currBlockSize%s.BlockSize != 0:
+		return errResizeBlockSizeNotAligned
+	case s.BlockSize > ropts.RetentionPeriod():
+		return errResizeBlockSizeTooLarge
+	case s.IndexBlockSize%s.BlockSize != 0:
+		return errResizeIndexBlockSizeNotMultiple
+	}
+	if err := ropts.SetBlockSize(s.BlockSize).Validate(); err != nil {
+		return fmt.Errorf("invalid resize block size: %v", err)
+	}
+	return nil
+}
+
+// ResizedOptions returns the options of a namespace once its in-progress
+// resize, if any, has been completed.
+func ResizedOptions(opts Options) Options {
+	state := opts.ResizeState()
+	if !state.InProgress() {
+		return opts
+	}
+	return opts.
+		SetRetentionOptions(opts.RetentionOptions().SetBlockSize(state.BlockSize)).
+		SetIndexOptions(opts.IndexOptions().SetBlockSize(state.IndexBlockSize)).
+		SetResizeState(ResizeState{})
+}
+
+// ResizeStatusKey returns the KV key under which a host reports whether it
+// has converted the namespace's filesets to the block size of a resize.
+func ResizeStatusKey(id ident.ID, state ResizeState, hostID string) string {
+	return fmt.Sprintf("%s/%s/%d/%s", resizeStatusKeyPrefix, id.String(),
+		state.BlockSize.Nanoseconds(), hostID)
+}
+
+// ResizeStatusReporter reports whether a host has converted the filesets of a
+// namespace to the block size of an in-progress resize.
+type ResizeStatusReporter interface {
+	// ReportConverted reports whether all sealed blocks of the namespace have
+	// been converted to the block size of the resize.
+	ReportConverted(id ident.ID, state ResizeState, converted bool) error
+}
+
+type kvResizeStatusReporter struct {
+	store  kv.Store
+	hostID string
+}
+
+// NewKVResizeStatusReporter returns a resize status reporter that reports the
+// status of the host to a KV store.
+func NewKVResizeStatusReporter(store kv.Store, hostID string) ResizeStatusReporter {
+	return &kvResizeStatusReporter{
+		store:  store,
+		hostID: hostID,
+	}
+}
+
+func (r *kvResizeStatusReporter) ReportConverted(
+	id ident.ID,
+	state ResizeState,
+	converted bool,
+) error {
+	_, err := r.store.Set(ResizeStatusKey(id, state, r.hostID),
+		&commonpb.BoolProto{Value: converted})
+	return err
+}
+
+// ResizeConverted returns whether a host has reported that it converted the
+// filesets of a namespace to the block size of an in-progress resize.
+func ResizeConverted(
+	store kv.Store,
+	id ident.ID,
+	state ResizeState,
+	hostID string,
+) (bool, error) {
+	value, err := store.Get(ResizeStatusKey(id, state, hostID))
+	if err == kv.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var converted commonpb.BoolProto
+	if err := value.Unmarshal(&converted); err != nil {
+		return false, err
+	}
+	return converted.Value, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/x/ident"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResizeStateValidate(t *testing.T) {
+	ropts := retention.NewOptions().
+		SetRetentionPeriod(48 * time.Hour).
+		SetBlockSize(2 * time.Hour)
+	valid := ResizeState{
+		BlockSize:      6 * time.Hour,
+		IndexBlockSize: 12 * time.Hour,
+	}
+	require.True(t, valid.InProgress())
+	require.NoError(t, valid.Validate(ropts))
+	require.False(t, ResizeState{}.InProgress())
+	require.NoError(t, ResizeState{}.Validate(ropts))
+
+	tests := []struct {
+		name   string
+		modify func(s *ResizeState)
+		err    error
+	}{
+		{
+			name:   "index block size without block size",
+			modify: func(s *ResizeState) { s.BlockSize = 0 },
+			err:    errResizeIndexBlockSizeWithoutSize,
+		},
+		{
+			name:   "block size not positive",
+			modify: func(s *ResizeState) { s.BlockSize = -time.Hour },
+			err:    errResizeBlockSizeNotPositive,
+		},
+		{
+			name:   "index block size not positive",
+			modify: func(s *ResizeState) { s.IndexBlockSize = 0 },
+			err:    errResizeIndexBlockSizeNotPositive,
+		},
+		{
+			name:   "block size unchanged",
+			modify: func(s *ResizeState) { s.BlockSize = 2 * time.Hour },
+			err:    errResizeBlockSizeUnchanged,
+		},
+		{
+			name:   "block size not aligned",
+			modify: func(s *ResizeState) { s.BlockSize = 3 * time.Hour },
+			err:    errResizeBlockSizeNotAligned,
+		},
+		{
+			name: "block size beyond retention",
+			modify: func(s *ResizeState) {
+				s.BlockSize = 96 * time.Hour
+				s.IndexBlockSize = 96 * time.Hour
+			},
+			err: errResizeBlockSizeTooLarge,
+		},
+		{
+			name:   "index block size not a multiple",
+			modify: func(s *ResizeState) { s.IndexBlockSize = 8 * time.Hour },
+			err:    errResizeIndexBlockSizeNotMultiple,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := valid
+			tt.modify(&state)
+			require.Equal(t, tt.err, state.Validate(ropts))
+		})
+	}
+}
+
+func TestResizedOptions(t *testing.T) {
+	opts := NewOptions()
+	require.Equal(t, opts, ResizedOptions(opts))
+
+	state := ResizeState{BlockSize: 4 * time.Hour, IndexBlockSize: 8 * time.Hour}
+	resized := ResizedOptions(opts.SetResizeState(state))
+	require.Equal(t, 4*time.Hour, resized.RetentionOptions().BlockSize())
+	require.Equal(t, 8*time.Hour, resized.IndexOptions().BlockSize())
+	require.False(t, resized.ResizeState().InProgress())
+	require.Equal(t, opts.RetentionOptions().RetentionPeriod(),
+		resized.RetentionOptions().RetentionPeriod())
+}
+
+func TestResizeStatusKey(t *testing.T) {
+	state := ResizeState{BlockSize: time.Hour, IndexBlockSize: time.Hour}
+	require.Equal(t, "m3db.node.namespace-resize/metrics/3600000000000/host1",
+		ResizeStatusKey(ident.StringID("metrics"), state, "host1"))
+}
+
+func TestKVResizeStatusReporter(t *testing.T) {
+	var (
+		store    = mem.NewStore()
+		id       = ident.StringID("metrics")
+		state    = ResizeState{BlockSize: time.Hour, IndexBlockSize: time.Hour}
+		reporter = NewKVResizeStatusReporter(store, "host1")
+	)
+
+	converted, err := ResizeConverted(store, id, state, "host1")
+	require.NoError(t, err)
+	require.False(t, converted)
+
+	require.NoError(t, reporter.ReportConverted(id, state, true))
+	converted, err = ResizeConverted(store, id, state, "host1")
+	require.NoError(t, err)
+	require.True(t, converted)
+
+	converted, err = ResizeConverted(store, id, state, "host2")
+	require.NoError(t, err)
+	require.False(t, converted)
+
+	require.NoError(t, reporter.ReportConverted(id, state, false))
+	converted, err = ResizeConverted(store, id, state, "host1")
+	require.NoError(t, err)
+	require.False(t, converted)
+}
@@ -82,6 +82,22 @@ type Options interface {
 	// ColdWritesEnabled returns whether cold writes are enabled for this namespace.
 	ColdWritesEnabled() bool
 
+	// SetLateWritesEnabled sets whether cold writes that are too far in the past
+	// for this namespace are persisted to late data sidecar volumes rather than
+	// merged into the filesets of their blocks. Requires cold writes to be enabled.
+	SetLateWritesEnabled(value bool) Options
+
+	// LateWritesEnabled returns whether cold writes that are too far in the past
+	// for this namespace are persisted to late data sidecar volumes rather than
+	// merged into the filesets of their blocks.
+	LateWritesEnabled() bool
+
+	// SetEncodingScheme sets the scheme used to encode the datapoints of this namespace.
+	SetEncodingScheme(value EncodingScheme) Options
+
+	// EncodingScheme returns the scheme used to encode the datapoints of this namespace.
+	EncodingScheme() EncodingScheme
+
 	// SetCacheBlocksOnRetrieve sets whether to cache blocks from this namespace when retrieved.
 	// If global CacheBlocksOnRetrieve option in config.BlockRetrievePolicy is set to false,
 	// then that will override any namespace-specific CacheBlocksOnRetrieve options set to true.
@@ -131,6 +147,12 @@ type Options interface {
 
 	// StagingState returns the state related to a namespace's availability for use.
 	StagingState() StagingState
+
+	// SetResizeState sets the state of an in-progress resize of the namespace's block sizes.
+	SetResizeState(value ResizeState) Options
+
+	// ResizeState returns the state of an in-progress resize of the namespace's block sizes.
+	ResizeState() ResizeState
 }
 
 // IndexOptions controls the indexing options for a namespace.
@@ -340,6 +362,12 @@ type AggregationOptions interface {
 
 	// Aggregations returns the aggregations for this namespace.
 	Aggregations() []Aggregation
+
+	// SetRollupPolicy sets the rollup policy for this namespace.
+	SetRollupPolicy(value RollupPolicy) AggregationOptions
+
+	// RollupPolicy returns the rollup policy for this namespace.
+	RollupPolicy() RollupPolicy
 }
 
 // Aggregation describes data points within the namespace.
@@ -370,6 +398,25 @@ type DownsampleOptions struct {
 	All bool
 }
 
+// RollupPolicy describes how sealed blocks of a namespace are automatically
+// rolled up into a coarser namespace once they reach a given age.
+type RollupPolicy struct {
+	// TargetNamespace is the namespace the rolled up tiles are written to.
+	// The policy is disabled when this is empty.
+	TargetNamespace string
+
+	// RollupAfter is how long after the end of a block it becomes
+	// eligible for rollup.
+	RollupAfter time.Duration
+
+	// Step is the resolution of the rolled up tiles.
+	Step time.Duration
+
+	// RawRetention, if non-zero, shortens the retention of raw data files
+	// that have already been rolled up.
+	RawRetention time.Duration
+}
+
 // StagingStatus is the status of the namespace.
 type StagingStatus uint8
 
@@ -27,7 +27,9 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	"github.com/m3db/m3/src/dbnode/namespace"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -37,6 +39,7 @@ import (
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/context"
 	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/hyperloglog"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
 )
@@ -115,6 +118,15 @@ func ToTimeType(unit xtime.Unit) (rpc.TimeType, error) {
 	return 0, errUnknownUnit
 }
 
+// ToRPCEncodingScheme converts a namespace encoding scheme to the encoding
+// scheme returned with fetched segments.
+func ToRPCEncodingScheme(scheme namespace.EncodingScheme) rpc.EncodingScheme {
+	if scheme == namespace.XOREncodingScheme {
+		return rpc.EncodingScheme_XOR
+	}
+	return rpc.EncodingScheme_M3TSZ
+}
+
 // ToSegmentsResult is the result of a convert to segments call,
 // if the segments were merged then checksum is ptr to the checksum
 // otherwise it is nil.
@@ -513,6 +525,249 @@ func ToRPCAggregateQueryRawRequest(
 	return request, nil
 }
 
+// FromRPCDeleteTaggedRequest converts the rpc request type for
+// DeleteTaggedRequest into corresponding Go API types.
+func FromRPCDeleteTaggedRequest(
+	req *rpc.DeleteTaggedRequest,
+) (ident.ID, index.Query, xtime.UnixNano, xtime.UnixNano, error) {
+	start, rangeStartErr := ToTime(req.RangeStart, fetchTaggedTimeType)
+	if rangeStartErr != nil {
+		return nil, index.Query{}, 0, 0, rangeStartErr
+	}
+
+	end, rangeEndErr := ToTime(req.RangeEnd, fetchTaggedTimeType)
+	if rangeEndErr != nil {
+		return nil, index.Query{}, 0, 0, rangeEndErr
+	}
+
+	query, err := idx.Unmarshal(req.Query)
+	if err != nil {
+		return nil, index.Query{}, 0, 0, err
+	}
+
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, index.Query{Query: query}, start, end, nil
+}
+
+// ToRPCDeleteTaggedRequest converts the Go `client/` types into rpc
+// request type for DeleteTaggedRequest.
+func ToRPCDeleteTaggedRequest(
+	ns ident.ID,
+	q index.Query,
+	start, end xtime.UnixNano,
+) (rpc.DeleteTaggedRequest, error) {
+	rangeStart, tsErr := ToValue(start, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.DeleteTaggedRequest{}, tsErr
+	}
+
+	rangeEnd, tsErr := ToValue(end, fetchTaggedTimeType)
+	if tsErr != nil {
+		return rpc.DeleteTaggedRequest{}, tsErr
+	}
+
+	query, queryErr := idx.Marshal(q.Query)
+	if queryErr != nil {
+		return rpc.DeleteTaggedRequest{}, queryErr
+	}
+
+	return rpc.DeleteTaggedRequest{
+		NameSpace:  ns.Bytes(),
+		Query:      query,
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+	}, nil
+}
+
+// ToRPCExemplars converts exemplars to their RPC representation.
+func ToRPCExemplars(exemplars []exemplar.Exemplar) []*rpc.Exemplar {
+	result := make([]*rpc.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		labels := make([]*rpc.Tag, 0, len(e.Labels))
+		for _, l := range e.Labels {
+			labels = append(labels, &rpc.Tag{Name: l.Name, Value: l.Value})
+		}
+		result = append(result, &rpc.Exemplar{
+			Labels:    labels,
+			Value:     e.Value,
+			Timestamp: int64(e.Timestamp),
+		})
+	}
+	return result
+}
+
+// FromRPCExemplars converts RPC exemplars to exemplars.
+func FromRPCExemplars(exemplars []*rpc.Exemplar) []exemplar.Exemplar {
+	result := make([]exemplar.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		labels := make([]exemplar.Label, 0, len(e.Labels))
+		for _, l := range e.Labels {
+			labels = append(labels, exemplar.Label{Name: l.Name, Value: l.Value})
+		}
+		result = append(result, exemplar.Exemplar{
+			Labels:    labels,
+			Value:     e.Value,
+			Timestamp: xtime.UnixNano(e.Timestamp),
+		})
+	}
+	return result
+}
+
+// FromRPCCardinalityRequest converts the rpc request type for
+// CardinalityRequest into corresponding Go API types.
+func FromRPCCardinalityRequest(
+	req *rpc.CardinalityRequest,
+) (ident.ID, index.CardinalityQueryOptions) {
+	shards := make([]uint32, 0, len(req.Shards))
+	for _, shard := range req.Shards {
+		shards = append(shards, uint32(shard))
+	}
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, index.CardinalityQueryOptions{
+		StartInclusive: xtime.UnixNano(req.RangeStart),
+		EndExclusive:   xtime.UnixNano(req.RangeEnd),
+		Shards:         shards,
+		MetricNameTag:  req.MetricNameTag,
+		Limit:          int(req.Limit),
+	}
+}
+
+// ToRPCCardinalityRequest converts the Go `client/` types into rpc
+// request type for CardinalityRequest.
+func ToRPCCardinalityRequest(
+	ns ident.ID,
+	opts index.CardinalityQueryOptions,
+) *rpc.CardinalityRequest {
+	shards := make([]int32, 0, len(opts.Shards))
+	for _, shard := range opts.Shards {
+		shards = append(shards, int32(shard))
+	}
+	return &rpc.CardinalityRequest{
+		NameSpace:     ns.Bytes(),
+		RangeStart:    int64(opts.StartInclusive),
+		RangeEnd:      int64(opts.EndExclusive),
+		Limit:         int64(opts.Limit),
+		Shards:        shards,
+		MetricNameTag: opts.MetricNameTag,
+	}
+}
+
+// ToRPCCardinalityResult converts cardinality stats to their RPC representation.
+func ToRPCCardinalityResult(stats index.CardinalityStats) *rpc.CardinalityResult_ {
+	return &rpc.CardinalityResult_{
+		NumSeries:                   stats.NumSeries,
+		SeriesCountByMetricName:     toRPCCardinalityStats(stats.SeriesCountByMetricName),
+		LabelValueCountByLabelName:  toRPCCardinalityStats(stats.LabelValueCountByLabelName),
+		SeriesCountByLabelValuePair: toRPCCardinalityStats(stats.SeriesCountByLabelValuePair),
+	}
+}
+
+// FromRPCCardinalityResult converts an RPC cardinality result to cardinality stats.
+func FromRPCCardinalityResult(res *rpc.CardinalityResult_) index.CardinalityStats {
+	return index.CardinalityStats{
+		NumSeries:                   res.NumSeries,
+		SeriesCountByMetricName:     fromRPCCardinalityStats(res.SeriesCountByMetricName),
+		LabelValueCountByLabelName:  fromRPCCardinalityStats(res.LabelValueCountByLabelName),
+		SeriesCountByLabelValuePair: fromRPCCardinalityStats(res.SeriesCountByLabelValuePair),
+	}
+}
+
+func toRPCCardinalityStats(stats []index.CardinalityStat) []*rpc.CardinalityStat {
+	result := make([]*rpc.CardinalityStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, &rpc.CardinalityStat{
+			Name:  []byte(stat.Name),
+			Value: stat.Value,
+		})
+	}
+	return result
+}
+
+func fromRPCCardinalityStats(stats []*rpc.CardinalityStat) []index.CardinalityStat {
+	result := make([]index.CardinalityStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, index.CardinalityStat{
+			Name:  string(stat.Name),
+			Value: stat.Value,
+		})
+	}
+	return result
+}
+
+// FromRPCCountRequest converts the rpc request type for CountRequest into
+// corresponding Go API types.
+func FromRPCCountRequest(
+	req *rpc.CountRequest,
+) (ident.ID, index.Query, index.CountQueryOptions, error) {
+	query, err := idx.Unmarshal(req.Query)
+	if err != nil {
+		return nil, index.Query{}, index.CountQueryOptions{}, err
+	}
+
+	shards := make([]uint32, 0, len(req.Shards))
+	for _, shard := range req.Shards {
+		shards = append(shards, uint32(shard))
+	}
+	ns := ident.StringID(string(req.NameSpace))
+	return ns, index.Query{Query: query}, index.CountQueryOptions{
+		StartInclusive: xtime.UnixNano(req.RangeStart),
+		EndExclusive:   xtime.UnixNano(req.RangeEnd),
+		Shards:         shards,
+		IncludeSketch:  req.IncludeSketch,
+	}, nil
+}
+
+// ToRPCCountRequest converts the Go `client/` types into rpc request type
+// for CountRequest.
+func ToRPCCountRequest(
+	ns ident.ID,
+	q index.Query,
+	opts index.CountQueryOptions,
+) (*rpc.CountRequest, error) {
+	query, err := idx.Marshal(q.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]int32, 0, len(opts.Shards))
+	for _, shard := range opts.Shards {
+		shards = append(shards, int32(shard))
+	}
+	return &rpc.CountRequest{
+		NameSpace:     ns.Bytes(),
+		Query:         query,
+		RangeStart:    int64(opts.StartInclusive),
+		RangeEnd:      int64(opts.EndExclusive),
+		Shards:        shards,
+		IncludeSketch: opts.IncludeSketch,
+	}, nil
+}
+
+// ToRPCCountResult converts a count result to its RPC representation.
+func ToRPCCountResult(result index.CountResult) (*rpc.CountResult_, error) {
+	res := &rpc.CountResult_{Count: result.Count}
+	if result.Sketch != nil {
+		sketch, err := result.Sketch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		res.Sketch = sketch
+	}
+	return res, nil
+}
+
+// FromRPCCountResult converts an RPC count result to a count result.
+func FromRPCCountResult(res *rpc.CountResult_) (index.CountResult, error) {
+	result := index.CountResult{Count: res.Count}
+	if len(res.Sketch) > 0 {
+		result.Sketch = &hyperloglog.Sketch{}
+		if err := result.Sketch.UnmarshalBinary(res.Sketch); err != nil {
+			return index.CountResult{}, err
+		}
+	}
+	return result, nil
+}
+
 // ToTagsIter returns a tag iterator over the given request.
 func ToTagsIter(r *rpc.WriteTaggedRequest) (ident.TagIterator, error) {
 	if r == nil {
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/convert"
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/x/xpool"
@@ -266,6 +267,39 @@ func TestConvertAggregateRawQueryRequest(t *testing.T) {
 	}
 }
 
+func TestConvertDeleteTaggedRequest(t *testing.T) {
+	var (
+		ns    = ident.StringID("abc")
+		start = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end   = xtime.Now().Truncate(time.Second)
+	)
+	testCases := []struct {
+		name string
+		fn   func(t *testing.T) (idx.Query, []byte)
+	}{
+		{"Term Query", termQueryTestCase},
+		{"Regexp Query", regexpQueryTestCase},
+		{"Conjunction Query A", conjunctionQueryATestCase},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, rpcQ := tc.fn(t)
+			req, err := convert.ToRPCDeleteTaggedRequest(ns, index.Query{Query: q}, start, end)
+			require.NoError(t, err)
+			require.Equal(t, rpcQ, req.Query)
+			require.Equal(t, mustToRPCTime(t, start), req.RangeStart)
+			require.Equal(t, mustToRPCTime(t, end), req.RangeEnd)
+
+			id, observedQuery, observedStart, observedEnd, err := convert.FromRPCDeleteTaggedRequest(&req)
+			require.NoError(t, err)
+			require.Equal(t, ns.String(), id.String())
+			require.True(t, index.NewQueryMatcher(index.Query{Query: q}).Matches(observedQuery))
+			require.Equal(t, start, observedStart)
+			require.Equal(t, end, observedEnd)
+		})
+	}
+}
+
 func TestToRPCError(t *testing.T) {
 	limitErr := limits.NewQueryLimitExceededError("limit")
 	invalidParamsErr := xerrors.NewInvalidParamsError(errors.New("param"))
@@ -298,6 +332,31 @@ func TestToRPCError(t *testing.T) {
 	)
 }
 
+func TestExemplarsRoundTrip(t *testing.T) {
+	exemplars := []exemplar.Exemplar{
+		{
+			Labels:    []exemplar.Label{{Name: "trace_id", Value: "abc"}},
+			Value:     1.5,
+			Timestamp: xtime.UnixNano(10),
+		},
+		{
+			Labels:    []exemplar.Label{},
+			Value:     2,
+			Timestamp: xtime.UnixNano(20),
+		},
+	}
+
+	rpcExemplars := convert.ToRPCExemplars(exemplars)
+	require.Len(t, rpcExemplars, 2)
+	assert.Equal(t, &rpc.Exemplar{
+		Labels:    []*rpc.Tag{{Name: "trace_id", Value: "abc"}},
+		Value:     1.5,
+		Timestamp: 10,
+	}, rpcExemplars[0])
+
+	assert.Equal(t, exemplars, convert.FromRPCExemplars(rpcExemplars))
+}
+
 type testPools struct {
 	id      ident.Pool
 	wrapper xpool.CheckedBytesWrapperPool
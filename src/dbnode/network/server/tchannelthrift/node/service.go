@@ -30,6 +30,8 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
@@ -37,6 +39,8 @@ import (
 	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
 	"github.com/m3db/m3/src/dbnode/storage"
 	"github.com/m3db/m3/src/dbnode/storage/block"
+	dberrors "github.com/m3db/m3/src/dbnode/storage/errors"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	idxconvert "github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
@@ -52,6 +56,7 @@ import (
 	"github.com/m3db/m3/src/x/context"
 	xdebug "github.com/m3db/m3/src/x/debug"
 	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/headers"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xopentracing "github.com/m3db/m3/src/x/opentracing"
@@ -69,8 +74,9 @@ import (
 
 var (
 	// NB(r): pool sizes are vars to help reduce stress on tests.
-	segmentArrayPoolSize        = 65536
-	writeBatchPooledReqPoolSize = 1024
+	segmentArrayPoolSize           = 65536
+	writeBatchPooledReqPoolSize    = 1024
+	xorMultiReaderIteratorPoolSize = 256
 )
 
 const (
@@ -102,6 +108,15 @@ var (
 
 	// errHealthNotSet is raised when server health data structure is not set.
 	errHealthNotSet = errors.New("server health not set")
+
+	// errDeleteTaggedInvalidRange is raised when a delete range is empty.
+	errDeleteTaggedInvalidRange = errors.New("delete range start must be before end")
+
+	// errCardinalityInvalidRange is raised when a cardinality range is empty.
+	errCardinalityInvalidRange = errors.New("cardinality range start must be before end")
+
+	// errCountInvalidRange is raised when a count range is empty.
+	errCountInvalidRange = errors.New("count range start must be before end")
 )
 
 type serviceMetrics struct {
@@ -114,12 +129,18 @@ type serviceMetrics struct {
 	fetchBlocksMetadata     instrument.MethodMetrics
 	repair                  instrument.MethodMetrics
 	truncate                instrument.MethodMetrics
+	deleteTagged            instrument.MethodMetrics
 	fetchBatchRawRPCS       tally.Counter
 	fetchBatchRaw           instrument.BatchMethodMetrics
 	writeBatchRawRPCs       tally.Counter
 	writeBatchRaw           instrument.BatchMethodMetrics
 	writeTaggedBatchRawRPCs tally.Counter
 	writeTaggedBatchRaw     instrument.BatchMethodMetrics
+	writeExemplars          instrument.MethodMetrics
+	writeExemplarsDropped   tally.Counter
+	fetchExemplars          instrument.MethodMetrics
+	cardinality             instrument.MethodMetrics
+	count                   instrument.MethodMetrics
 	overloadRejected        tally.Counter
 	rpcTotalRead            tally.Counter
 	rpcStatusCanceledRead   tally.Counter
@@ -138,12 +159,18 @@ func newServiceMetrics(scope tally.Scope, opts instrument.TimerOptions) serviceM
 		fetchBlocksMetadata:     instrument.NewMethodMetrics(scope, "fetchBlocksMetadata", opts),
 		repair:                  instrument.NewMethodMetrics(scope, "repair", opts),
 		truncate:                instrument.NewMethodMetrics(scope, "truncate", opts),
+		deleteTagged:            instrument.NewMethodMetrics(scope, "deleteTagged", opts),
 		fetchBatchRawRPCS:       scope.Counter("fetchBatchRaw-rpcs"),
 		fetchBatchRaw:           instrument.NewBatchMethodMetrics(scope, "fetchBatchRaw", opts),
 		writeBatchRawRPCs:       scope.Counter("writeBatchRaw-rpcs"),
 		writeBatchRaw:           instrument.NewBatchMethodMetrics(scope, "writeBatchRaw", opts),
 		writeTaggedBatchRawRPCs: scope.Counter("writeTaggedBatchRaw-rpcs"),
 		writeTaggedBatchRaw:     instrument.NewBatchMethodMetrics(scope, "writeTaggedBatchRaw", opts),
+		writeExemplars:          instrument.NewMethodMetrics(scope, "writeExemplars", opts),
+		writeExemplarsDropped:   scope.Counter("writeExemplars-dropped"),
+		fetchExemplars:          instrument.NewMethodMetrics(scope, "fetchExemplars", opts),
+		cardinality:             instrument.NewMethodMetrics(scope, "cardinality", opts),
+		count:                   instrument.NewMethodMetrics(scope, "count", opts),
 		overloadRejected:        scope.Counter("overload-rejected"),
 		rpcTotalRead: scope.Tagged(map[string]string{
 			"rpc_type": "read",
@@ -167,6 +194,7 @@ type service struct {
 	metrics           serviceMetrics
 	queryLimits       limits.QueryLimits
 	seriesReadPermits permits.Manager
+	exemplars         exemplar.NamespaceStores
 }
 
 type serviceState struct {
@@ -251,6 +279,7 @@ type pools struct {
 	writeBatchPooledReqPool *writeBatchPooledReqPool
 	blockMetadataV2         tchannelthrift.BlockMetadataV2Pool
 	blockMetadataV2Slice    tchannelthrift.BlockMetadataV2SlicePool
+	xorMultiReaderIterator  encoding.MultiReaderIteratorPool
 }
 
 // ensure `pools` matches a required conversion interface
@@ -319,6 +348,15 @@ func NewService(db storage.Database, opts tchannelthrift.Options) Service {
 	writeBatchPooledReqPool := newWriteBatchPooledReqPool(writeBatchPoolSize, iopts)
 	writeBatchPooledReqPool.Init()
 
+	// NB: datapoints of namespaces encoded with the xor encoding scheme
+	// are decoded with dedicated iterators.
+	xorMultiReaderIteratorPool := encoding.NewMultiReaderIteratorPool(
+		pool.NewObjectPoolOptions().
+			SetSize(xorMultiReaderIteratorPoolSize).
+			SetInstrumentOptions(iopts.SetMetricsScope(
+				scope.SubScope("xor-multi-reader-iterator-pool"))))
+	xorMultiReaderIteratorPool.Init(xor.DefaultReaderIteratorAllocFn(encoding.NewOptions()))
+
 	return &service{
 		state: serviceState{
 			db: db,
@@ -343,9 +381,11 @@ func NewService(db storage.Database, opts tchannelthrift.Options) Service {
 			writeBatchPooledReqPool: writeBatchPooledReqPool,
 			blockMetadataV2:         opts.BlockMetadataV2Pool(),
 			blockMetadataV2Slice:    opts.BlockMetadataV2SlicePool(),
+			xorMultiReaderIterator:  xorMultiReaderIteratorPool,
 		},
 		queryLimits:       opts.QueryLimits(),
 		seriesReadPermits: opts.PermitsOptions().SeriesReadPermitsManager(),
+		exemplars:         exemplar.NewNamespaceStores(opts.MaxExemplarsPerNamespace()),
 	}
 }
 
@@ -701,7 +741,11 @@ func (s *service) readDatapoints(
 	// Make datapoints an initialized empty array for JSON serialization as empty array than null
 	datapoints := make([]*rpc.Datapoint, 0)
 
-	multiIt := db.Options().MultiReaderIteratorPool().Get()
+	multiItPool := db.Options().MultiReaderIteratorPool()
+	if encodingScheme(db, nsID) == namespace.XOREncodingScheme {
+		multiItPool = s.pools.xorMultiReaderIterator
+	}
+	multiIt := multiItPool.Get()
 	nsCtx := namespace.NewContextFor(nsID, db.Options().SchemaRegistry())
 	multiIt.ResetSliceOfSlices(
 		xio.NewReaderSliceOfSlicesFromBlockReadersIterator(
@@ -731,6 +775,16 @@ func (s *service) readDatapoints(
 	return datapoints, nil
 }
 
+// encodingScheme returns the encoding scheme of the segments read from the
+// namespace, which clients need to decode them.
+func encodingScheme(db storage.Database, nsID ident.ID) namespace.EncodingScheme {
+	ns, ok := db.Namespace(nsID)
+	if !ok {
+		return namespace.M3TSZEncodingScheme
+	}
+	return ns.Options().EncodingScheme()
+}
+
 func (s *service) FetchTagged(tctx thrift.Context, req *rpc.FetchTaggedRequest) (*rpc.FetchTaggedResult_, error) {
 	ctx := tchannelthrift.Context(tctx)
 	iter, err := s.FetchTaggedIter(ctx, req)
@@ -755,6 +809,11 @@ func (s *service) fetchTaggedResult(ctx context.Context,
 		Exhaustive: iter.Exhaustive(),
 	}
 
+	scheme := rpc.EncodingScheme_M3TSZ
+	if db, ok := s.state.DB(); ok {
+		scheme = convert.ToRPCEncodingScheme(encodingScheme(db, iter.Namespace()))
+	}
+
 	for iter.Next(ctx) {
 		cur := iter.Current()
 		tagBytes, err := cur.WriteTags(nil)
@@ -766,10 +825,11 @@ func (s *service) fetchTaggedResult(ctx context.Context,
 			return nil, err
 		}
 		response.Elements = append(response.Elements, &rpc.FetchTaggedIDResult_{
-			ID:          cur.ID(),
-			NameSpace:   iter.Namespace().Bytes(),
-			EncodedTags: tagBytes,
-			Segments:    segments,
+			ID:             cur.ID(),
+			NameSpace:      iter.Namespace().Bytes(),
+			EncodedTags:    tagBytes,
+			Segments:       segments,
+			EncodingScheme: scheme,
 		})
 	}
 	if iter.Err() != nil {
@@ -1302,6 +1362,7 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 		nonRetryableErrors int
 	)
 	nsID := s.newID(ctx, req.NameSpace)
+	scheme := convert.ToRPCEncodingScheme(encodingScheme(db, nsID))
 	result := rpc.NewFetchBatchRawResult_()
 	result.Elements = make([]*rpc.FetchRawResult_, len(req.Ids))
 
@@ -1350,6 +1411,7 @@ func (s *service) FetchBatchRaw(tctx thrift.Context, req *rpc.FetchBatchRawReque
 
 		success++
 		rawResult.Segments = segments
+		rawResult.EncodingScheme = scheme
 	}
 
 	s.metrics.fetchBatchRaw.ReportSuccess(success)
@@ -1372,6 +1434,7 @@ func (s *service) FetchBatchRawV2(tctx thrift.Context, req *rpc.FetchBatchRawV2R
 		callStart          = s.nowFn()
 		ctx                = addSourceToContext(tctx, req.Source)
 		nsIDs              = make([]ident.ID, 0, len(req.Elements))
+		schemes            = make([]rpc.EncodingScheme, 0, len(req.Elements))
 		result             = rpc.NewFetchBatchRawResult_()
 		success            int
 		retryableErrors    int
@@ -1379,7 +1442,9 @@ func (s *service) FetchBatchRawV2(tctx thrift.Context, req *rpc.FetchBatchRawV2R
 	)
 
 	for _, nsBytes := range req.NameSpaces {
-		nsIDs = append(nsIDs, s.newID(ctx, nsBytes))
+		nsID := s.newID(ctx, nsBytes)
+		nsIDs = append(nsIDs, nsID)
+		schemes = append(schemes, convert.ToRPCEncodingScheme(encodingScheme(db, nsID)))
 	}
 	for _, elem := range req.Elements {
 		if elem.NameSpace >= int64(len(nsIDs)) {
@@ -1437,6 +1502,7 @@ func (s *service) FetchBatchRawV2(tctx thrift.Context, req *rpc.FetchBatchRawV2R
 
 		success++
 		rawResult.Segments = segments
+		rawResult.EncodingScheme = schemes[elem.NameSpace]
 	}
 
 	s.metrics.fetchBatchRaw.ReportSuccess(success)
@@ -1668,6 +1734,11 @@ func (s *service) Write(tctx thrift.Context, req *rpc.WriteRequest) error {
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
+	if err := s.incTenantSeriesWritten(tctx, 1, nil); err != nil {
+		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
+		return err
+	}
+
 	if req.Datapoint == nil {
 		s.metrics.write.ReportError(s.nowFn().Sub(callStart))
 		return tterrors.NewBadRequestError(errRequiresDatapoint)
@@ -1715,6 +1786,11 @@ func (s *service) WriteTagged(tctx thrift.Context, req *rpc.WriteTaggedRequest)
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
+	if err := s.incTenantSeriesWritten(tctx, 1, nil); err != nil {
+		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
+		return err
+	}
+
 	if req.Datapoint == nil {
 		s.metrics.writeTagged.ReportError(s.nowFn().Sub(callStart))
 		return tterrors.NewBadRequestError(errRequiresDatapoint)
@@ -1771,6 +1847,14 @@ func (s *service) WriteBatchRaw(tctx thrift.Context, req *rpc.WriteBatchRawReque
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
+	if err := s.incTenantSeriesWritten(tctx, len(req.Elements), func(i int) []byte {
+		return req.Elements[i].ID
+	}); err != nil {
+		s.metrics.writeBatchRaw.ReportNonRetryableErrors(len(req.Elements))
+		s.metrics.writeBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+		return err
+	}
+
 	// NB(r): Use the pooled request tracking to return thrift alloc'd bytes
 	// to the thrift bytes pool and to return ident.ID wrappers to a pool for
 	// reuse. We also reduce contention on pools by getting one per batch request
@@ -1857,6 +1941,14 @@ func (s *service) WriteBatchRawV2(tctx thrift.Context, req *rpc.WriteBatchRawV2R
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
+	if err := s.incTenantSeriesWritten(tctx, len(req.Elements), func(i int) []byte {
+		return req.Elements[i].ID
+	}); err != nil {
+		s.metrics.writeBatchRaw.ReportNonRetryableErrors(len(req.Elements))
+		s.metrics.writeBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+		return err
+	}
+
 	// Sanity check input.
 	numNamespaces := int64(len(req.NameSpaces))
 	for _, elem := range req.Elements {
@@ -1972,6 +2064,14 @@ func (s *service) WriteTaggedBatchRaw(tctx thrift.Context, req *rpc.WriteTaggedB
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
+	if err := s.incTenantSeriesWritten(tctx, len(req.Elements), func(i int) []byte {
+		return req.Elements[i].ID
+	}); err != nil {
+		s.metrics.writeTaggedBatchRaw.ReportNonRetryableErrors(len(req.Elements))
+		s.metrics.writeTaggedBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+		return err
+	}
+
 	// NB(r): Use the pooled request tracking to return thrift alloc'd bytes
 	// to the thrift bytes pool and to return ident.ID wrappers to a pool for
 	// reuse. We also reduce contention on pools by getting one per batch request
@@ -2060,6 +2160,14 @@ func (s *service) WriteTaggedBatchRawV2(tctx thrift.Context, req *rpc.WriteTagge
 	callStart := s.nowFn()
 	ctx := tchannelthrift.Context(tctx)
 
+	if err := s.incTenantSeriesWritten(tctx, len(req.Elements), func(i int) []byte {
+		return req.Elements[i].ID
+	}); err != nil {
+		s.metrics.writeBatchRaw.ReportNonRetryableErrors(len(req.Elements))
+		s.metrics.writeBatchRaw.ReportLatency(s.nowFn().Sub(callStart))
+		return err
+	}
+
 	// Sanity check input.
 	numNamespaces := int64(len(req.NameSpaces))
 	for _, elem := range req.Elements {
@@ -2167,6 +2275,77 @@ func (s *service) WriteTaggedBatchRawV2(tctx thrift.Context, req *rpc.WriteTagge
 	return nil
 }
 
+func (s *service) WriteExemplars(tctx thrift.Context, req *rpc.WriteExemplarsRequest) error {
+	db, err := s.startWriteRPCWithDB()
+	if err != nil {
+		return err
+	}
+	defer s.writeRPCCompleted()
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	if _, ok := db.Namespace(s.newID(ctx, req.NameSpace)); !ok {
+		s.metrics.writeExemplars.ReportError(s.nowFn().Sub(callStart))
+		return convert.ToRPCError(dberrors.NewUnknownNamespaceError(string(req.NameSpace)))
+	}
+
+	var (
+		store   = s.exemplars.Store(req.NameSpace)
+		dropped int
+	)
+	for _, elem := range req.Elements {
+		dropped += store.Write(elem.ID, convert.FromRPCExemplars(elem.Exemplars))
+	}
+
+	s.metrics.writeExemplarsDropped.Inc(int64(dropped))
+	s.metrics.writeExemplars.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return nil
+}
+
+func (s *service) FetchExemplars(
+	tctx thrift.Context,
+	req *rpc.FetchExemplarsRequest,
+) (*rpc.FetchExemplarsResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted(tctx)
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+
+	if _, ok := db.Namespace(s.newID(ctx, req.NameSpace)); !ok {
+		s.metrics.fetchExemplars.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(dberrors.NewUnknownNamespaceError(string(req.NameSpace)))
+	}
+
+	var (
+		start  = xtime.UnixNano(req.RangeStart)
+		end    = xtime.UnixNano(req.RangeEnd)
+		result = rpc.NewFetchExemplarsResult_()
+	)
+	result.Elements = make([]*rpc.FetchExemplarsResultElement, 0, len(req.Ids))
+	if store, ok := s.exemplars.Lookup(req.NameSpace); ok {
+		for _, id := range req.Ids {
+			exemplars := store.Fetch(id, start, end)
+			if len(exemplars) == 0 {
+				continue
+			}
+			result.Elements = append(result.Elements, &rpc.FetchExemplarsResultElement{
+				ID:        id,
+				Exemplars: convert.ToRPCExemplars(exemplars),
+			})
+		}
+	}
+
+	s.metrics.fetchExemplars.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return result, nil
+}
+
 func (s *service) Repair(tctx thrift.Context) error {
 	db, err := s.startRPCWithDB()
 	if err != nil {
@@ -2207,6 +2386,131 @@ func (s *service) Truncate(tctx thrift.Context, req *rpc.TruncateRequest) (r *rp
 	return res, nil
 }
 
+func (s *service) DeleteTagged(
+	tctx thrift.Context,
+	req *rpc.DeleteTaggedRequest,
+) (*rpc.DeleteTaggedResult_, error) {
+	db, err := s.startRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	ns, query, start, end, err := convert.FromRPCDeleteTaggedRequest(req)
+	if err != nil {
+		s.metrics.deleteTagged.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+	if !start.Before(end) {
+		s.metrics.deleteTagged.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(errDeleteTaggedInvalidRange)
+	}
+
+	deleted, err := db.DeleteTagged(ctx, ns, query, start, end)
+	if err != nil {
+		s.metrics.deleteTagged.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res := rpc.NewDeleteTaggedResult_()
+	res.NumSeries = int64(deleted)
+
+	s.metrics.deleteTagged.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
+func (s *service) Cardinality(
+	tctx thrift.Context,
+	req *rpc.CardinalityRequest,
+) (*rpc.CardinalityResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted(tctx)
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	nsID, opts := convert.FromRPCCardinalityRequest(req)
+	if !opts.StartInclusive.Before(opts.EndExclusive) {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(errCardinalityInvalidRange)
+	}
+
+	ns, ok := db.Namespace(nsID)
+	if !ok {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(dberrors.NewUnknownNamespaceError(nsID.String()))
+	}
+	idx, err := ns.Index()
+	if err != nil {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	stats, err := idx.Cardinality(ctx, opts)
+	if err != nil {
+		s.metrics.cardinality.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.cardinality.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return convert.ToRPCCardinalityResult(stats), nil
+}
+
+func (s *service) Count(
+	tctx thrift.Context,
+	req *rpc.CountRequest,
+) (*rpc.CountResult_, error) {
+	db, err := s.startReadRPCWithDB()
+	if err != nil {
+		return nil, err
+	}
+	defer s.readRPCCompleted(tctx)
+
+	callStart := s.nowFn()
+	ctx := tchannelthrift.Context(tctx)
+	nsID, query, opts, err := convert.FromRPCCountRequest(req)
+	if err != nil {
+		s.metrics.count.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(err)
+	}
+	if !opts.StartInclusive.Before(opts.EndExclusive) {
+		s.metrics.count.ReportError(s.nowFn().Sub(callStart))
+		return nil, tterrors.NewBadRequestError(errCountInvalidRange)
+	}
+
+	ns, ok := db.Namespace(nsID)
+	if !ok {
+		s.metrics.count.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(dberrors.NewUnknownNamespaceError(nsID.String()))
+	}
+	idx, err := ns.Index()
+	if err != nil {
+		s.metrics.count.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	result, err := idx.Count(ctx, query, opts)
+	if err != nil {
+		s.metrics.count.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	res, err := convert.ToRPCCountResult(result)
+	if err != nil {
+		s.metrics.count.ReportError(s.nowFn().Sub(callStart))
+		return nil, convert.ToRPCError(err)
+	}
+
+	s.metrics.count.ReportSuccess(s.nowFn().Sub(callStart))
+
+	return res, nil
+}
+
 func (s *service) GetPersistRateLimit(
 	ctx thrift.Context,
 ) (*rpc.NodePersistRateLimitResult_, error) {
@@ -2893,6 +3197,36 @@ func finalizeAnnotationFn(b []byte) {
 	apachethrift.BytesPoolPut(b)
 }
 
+// incTenantSeriesWritten charges the distinct series written by a write
+// request to the budget of the tenant set as the source header of the request,
+// if any, so that several datapoints of a series are only charged once. The
+// series ID of each element is only looked up for requests of several elements.
+func (s *service) incTenantSeriesWritten(
+	tctx thrift.Context,
+	numElements int,
+	idFn func(i int) []byte,
+) error {
+	tenant := tctx.Headers()[headers.SourceHeader]
+	if len(tenant) == 0 {
+		return nil
+	}
+
+	numSeries := numElements
+	if numElements > 1 {
+		seriesIDs := make(map[string]struct{}, numElements)
+		for i := 0; i < numElements; i++ {
+			seriesIDs[string(idFn(i))] = struct{}{}
+		}
+		numSeries = len(seriesIDs)
+	}
+
+	err := s.queryLimits.TenantLimits().Inc(limits.TenantSeriesWritten, numSeries, []byte(tenant))
+	if err != nil {
+		return convert.ToRPCError(err)
+	}
+	return nil
+}
+
 func addSourceToContext(tctx thrift.Context, source []byte) context.Context {
 	return addSourceToM3Context(tchannelthrift.Context(tctx), source)
 }
@@ -30,6 +30,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
 	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
@@ -52,6 +54,8 @@ import (
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/x/checked"
 	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/headers"
+	"github.com/m3db/m3/src/x/hyperloglog"
 	"github.com/m3db/m3/src/x/ident"
 	xtest "github.com/m3db/m3/src/x/test"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -226,6 +230,9 @@ func TestServiceQuery(t *testing.T) {
 
 	mockDB := storage.NewMockDatabase(ctrl)
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockNs.EXPECT().Options().Return(testNamespaceOptions).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(mockNs, true).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
 	service := NewService(mockDB, testTChannelThriftOptions).(*service)
@@ -552,6 +559,9 @@ func TestServiceFetch(t *testing.T) {
 
 	mockDB := storage.NewMockDatabase(ctrl)
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockNs.EXPECT().Options().Return(testNamespaceOptions).AnyTimes()
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(mockNs, true).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
 	service := NewService(mockDB, testTChannelThriftOptions).(*service)
@@ -615,6 +625,62 @@ func TestServiceFetch(t *testing.T) {
 	}
 }
 
+func TestServiceFetchXOREncodingScheme(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	nsID := "metrics"
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockNs.EXPECT().Options().
+		Return(testNamespaceOptions.SetEncodingScheme(namespace.XOREncodingScheme)).
+		AnyTimes()
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID)).Return(mockNs, true).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	start := xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	end := start.Add(2 * time.Hour)
+
+	enc := xor.NewEncoder(start, nil, encoding.NewOptions())
+	values := []ts.Datapoint{
+		{TimestampNanos: start.Add(10 * time.Second), Value: 1.0},
+		{TimestampNanos: start.Add(20 * time.Second), Value: 2.5},
+	}
+	for _, dp := range values {
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+	}
+
+	stream, _ := enc.Stream(ctx)
+	mockDB.EXPECT().
+		ReadEncoded(ctx, ident.NewIDMatcher(nsID), ident.NewIDMatcher("foo"), start, end).
+		Return(&series.FakeBlockReaderIter{
+			Readers: [][]xio.BlockReader{{{SegmentReader: stream}}},
+		}, nil)
+
+	r, err := service.Fetch(tctx, &rpc.FetchRequest{
+		RangeStart:     start.Seconds(),
+		RangeEnd:       end.Seconds(),
+		RangeType:      rpc.TimeType_UNIX_SECONDS,
+		NameSpace:      nsID,
+		ID:             "foo",
+		ResultTimeType: rpc.TimeType_UNIX_SECONDS,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, len(values), len(r.Datapoints))
+	for i, v := range values {
+		assert.Equal(t, v.TimestampNanos.Seconds(), r.Datapoints[i].Timestamp)
+		assert.Equal(t, v.Value, r.Datapoints[i].Value)
+	}
+}
+
 func TestServiceFetchIsOverloaded(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -717,6 +783,7 @@ func TestServiceFetchBatchRaw(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
@@ -815,7 +882,19 @@ func TestServiceFetchBatchRawV2MultiNS(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
 
+	nsID1 := "metrics1"
+	nsID2 := "metrics2"
+
+	// Segments of the second namespace are returned with its encoding scheme.
+	mockNs1 := storage.NewMockNamespace(ctrl)
+	mockNs1.EXPECT().Options().Return(testNamespaceOptions).AnyTimes()
+	mockNs2 := storage.NewMockNamespace(ctrl)
+	mockNs2.EXPECT().Options().
+		Return(testNamespaceOptions.SetEncodingScheme(namespace.XOREncodingScheme)).
+		AnyTimes()
 	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID1)).Return(mockNs1, true).AnyTimes()
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID2)).Return(mockNs2, true).AnyTimes()
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
@@ -830,9 +909,6 @@ func TestServiceFetchBatchRawV2MultiNS(t *testing.T) {
 
 	start, end = start.Truncate(time.Second), end.Truncate(time.Second)
 
-	nsID1 := "metrics1"
-	nsID2 := "metrics2"
-
 	streams := map[string]xio.SegmentReader{}
 	seriesData := map[string][]struct {
 		t xtime.UnixNano
@@ -878,6 +954,7 @@ func TestServiceFetchBatchRawV2MultiNS(t *testing.T) {
 	}
 
 	ids := [][]byte{[]byte("foo"), []byte("bar")}
+	schemes := []rpc.EncodingScheme{rpc.EncodingScheme_M3TSZ, rpc.EncodingScheme_XOR}
 	elements := []*rpc.FetchBatchRawV2RequestElement{
 		{
 			NameSpace:     0,
@@ -906,6 +983,7 @@ func TestServiceFetchBatchRawV2MultiNS(t *testing.T) {
 		require.NotNil(t, elem)
 
 		assert.Nil(t, elem.Err)
+		assert.Equal(t, schemes[i], elem.EncodingScheme)
 		require.Equal(t, 1, len(elem.Segments))
 
 		seg := elem.Segments[0]
@@ -935,6 +1013,7 @@ func TestServiceFetchBatchRawOverMaxOutstandingRequests(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
@@ -1034,6 +1113,7 @@ func TestServiceFetchBatchRawUnknownError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
@@ -1581,6 +1661,7 @@ func TestServiceFetchTagged(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockDB := storage.NewMockDatabase(ctrl)
+			mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 			mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 			mockDB.EXPECT().IsOverloaded().Return(false)
 			limitsOpts := limits.NewOptions().
@@ -1919,6 +2000,7 @@ func TestServiceFetchTaggedNoData(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
@@ -2059,6 +2141,7 @@ func TestServiceFetchTaggedReturnOnFirstErr(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Namespace(gomock.Any()).Return(nil, false).AnyTimes()
 	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
 	mockDB.EXPECT().IsOverloaded().Return(false)
 
@@ -2399,6 +2482,119 @@ func TestServiceWriteOverloaded(t *testing.T) {
 	require.Equal(t, tterrors.NewInternalError(errServerIsOverloaded), err)
 }
 
+func TestServiceWriteTenantLimitExceeded(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions.
+		SetQueryLimits(newTestTenantSeriesWrittenLimits(t, "foo", 2))).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+	tctx = thrift.WithHeaders(tctx, map[string]string{headers.SourceHeader: "foo"})
+
+	at := xtime.Now().Truncate(time.Second)
+	req := &rpc.WriteRequest{
+		NameSpace: "metrics",
+		ID:        "foo",
+		Datapoint: &rpc.Datapoint{
+			Timestamp:         at.Seconds(),
+			TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+			Value:             42.42,
+		},
+	}
+
+	mockDB.EXPECT().
+		Write(ctx, ident.NewIDMatcher("metrics"), ident.NewIDMatcher("foo"), at, 42.42,
+			xtime.Second, nil).
+		Return(nil)
+	require.NoError(t, service.Write(tctx, req))
+
+	err := service.Write(tctx, req)
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsResourceExhaustedErrorFlag(rpcErr))
+	require.Contains(t, rpcErr.Message, "tenant=foo")
+}
+
+func TestServiceWriteBatchRawTenantChargesDistinctSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions.
+		SetQueryLimits(newTestTenantSeriesWrittenLimits(t, "foo", 3))).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+	tctx = thrift.WithHeaders(tctx, map[string]string{headers.SourceHeader: "foo"})
+
+	nsID := "metrics"
+	newRequest := func(ids ...string) *rpc.WriteBatchRawRequest {
+		req := &rpc.WriteBatchRawRequest{NameSpace: []byte(nsID)}
+		for i, id := range ids {
+			req.Elements = append(req.Elements, &rpc.WriteBatchRawRequestElement{
+				ID: []byte(id),
+				Datapoint: &rpc.Datapoint{
+					Timestamp:         time.Now().Truncate(time.Second).Unix() - int64(i),
+					TimestampTimeType: rpc.TimeType_UNIX_SECONDS,
+					Value:             float64(i),
+				},
+			})
+		}
+		return req
+	}
+
+	// Several datapoints of the same series are only charged once, so the
+	// request is charged 2 series and stays under the limit.
+	req := newRequest("foo", "foo", "bar")
+	writeBatch := writes.NewWriteBatch(0, ident.StringID(nsID), nil)
+	mockDB.EXPECT().
+		BatchWriter(ident.NewIDMatcher(nsID), len(req.Elements)).
+		Return(writeBatch, nil)
+	mockDB.EXPECT().
+		WriteBatch(ctx, ident.NewIDMatcher(nsID), writeBatch, gomock.Any()).
+		Return(nil)
+	require.NoError(t, service.WriteBatchRaw(tctx, req))
+
+	err := service.WriteBatchRaw(tctx, newRequest("baz"))
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.True(t, tterrors.IsResourceExhaustedErrorFlag(rpcErr))
+}
+
+func newTestTenantSeriesWrittenLimits(
+	t *testing.T,
+	tenant string,
+	limit int64,
+) limits.QueryLimits {
+	queryLimits, err := limits.NewQueryLimits(
+		limits.DefaultLimitsOptions(testTChannelThriftOptions.InstrumentOptions()))
+	require.NoError(t, err)
+	tenantOpts := limits.TenantLimitOptions{
+		DocsLimitOpts:      limits.DefaultLookbackLimitOptions(),
+		BytesReadLimitOpts: limits.DefaultLookbackLimitOptions(),
+		SeriesWrittenLimitOpts: limits.LookbackLimitOptions{
+			Limit:    limit,
+			Lookback: time.Minute,
+		},
+	}
+	require.NoError(t, queryLimits.TenantLimits().Update(
+		map[string]limits.TenantLimitOptions{tenant: tenantOpts}))
+	return queryLimits
+}
+
 func TestServiceWriteDatabaseNotSet(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -3103,6 +3299,78 @@ func TestServiceWriteTaggedBatchRawUnknownError(t *testing.T) {
 	require.Equal(t, convert.ToRPCError(unknownErr), err)
 }
 
+func TestServiceWriteFetchExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	nsID := "metrics"
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID)).Return(mockNs, true).AnyTimes()
+
+	exemplars := []*rpc.Exemplar{
+		{
+			Labels:    []*rpc.Tag{{Name: "trace_id", Value: "abc"}},
+			Value:     1,
+			Timestamp: 10,
+		},
+		{
+			Labels:    []*rpc.Tag{{Name: "trace_id", Value: "def"}},
+			Value:     2,
+			Timestamp: 20,
+		},
+	}
+	err := service.WriteExemplars(tctx, &rpc.WriteExemplarsRequest{
+		NameSpace: []byte(nsID),
+		Elements: []*rpc.WriteExemplarsRequestElement{
+			{ID: []byte("foo"), Exemplars: exemplars},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := service.FetchExemplars(tctx, &rpc.FetchExemplarsRequest{
+		NameSpace:  []byte(nsID),
+		RangeStart: 15,
+		RangeEnd:   30,
+		Ids:        [][]byte{[]byte("foo"), []byte("bar")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*rpc.FetchExemplarsResultElement{
+		{ID: []byte("foo"), Exemplars: exemplars[1:]},
+	}, result.Elements)
+}
+
+func TestServiceWriteExemplarsUnknownNamespace(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false)
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher("metrics")).Return(nil, false)
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	err := service.WriteExemplars(tctx, &rpc.WriteExemplarsRequest{
+		NameSpace: []byte("metrics"),
+	})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+}
+
 func TestServiceRepair(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -3148,6 +3416,228 @@ func TestServiceTruncate(t *testing.T) {
 	assert.Equal(t, truncated, r.NumSeries)
 }
 
+func TestServiceDeleteTagged(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		start = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end   = start.Add(time.Hour)
+	)
+	req, err := idx.NewRegexpQuery([]byte("foo"), []byte("b.*"))
+	require.NoError(t, err)
+	qry := index.Query{Query: req}
+	data, err := idx.Marshal(req)
+	require.NoError(t, err)
+
+	mockDB.EXPECT().DeleteTagged(
+		gomock.Any(),
+		ident.NewIDMatcher(nsID),
+		index.NewQueryMatcher(qry),
+		start,
+		end,
+	).Return(3, nil)
+
+	r, err := service.DeleteTagged(tctx, &rpc.DeleteTaggedRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: int64(start),
+		RangeEnd:   int64(end),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), r.NumSeries)
+
+	// An empty range is rejected before reaching the database.
+	_, err = service.DeleteTagged(tctx, &rpc.DeleteTaggedRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: int64(end),
+		RangeEnd:   int64(start),
+	})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+}
+
+func TestServiceCardinality(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		start = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end   = start.Add(time.Hour)
+	)
+
+	mockIdx := storage.NewMockNamespaceIndex(ctrl)
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockNs.EXPECT().Index().Return(mockIdx, nil)
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID)).Return(mockNs, true)
+
+	mockIdx.EXPECT().Cardinality(gomock.Any(), index.CardinalityQueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		Shards:         []uint32{1, 3},
+		MetricNameTag:  []byte("__name__"),
+		Limit:          5,
+	}).Return(index.CardinalityStats{
+		NumSeries: 2,
+		SeriesCountByMetricName: []index.CardinalityStat{
+			{Name: "foo", Value: 2},
+		},
+		LabelValueCountByLabelName: []index.CardinalityStat{
+			{Name: "__name__", Value: 1},
+		},
+		SeriesCountByLabelValuePair: []index.CardinalityStat{
+			{Name: "__name__=foo", Value: 2},
+		},
+	}, nil)
+
+	r, err := service.Cardinality(tctx, &rpc.CardinalityRequest{
+		NameSpace:     []byte(nsID),
+		RangeStart:    int64(start),
+		RangeEnd:      int64(end),
+		Limit:         5,
+		Shards:        []int32{1, 3},
+		MetricNameTag: []byte("__name__"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &rpc.CardinalityResult_{
+		NumSeries: 2,
+		SeriesCountByMetricName: []*rpc.CardinalityStat{
+			{Name: []byte("foo"), Value: 2},
+		},
+		LabelValueCountByLabelName: []*rpc.CardinalityStat{
+			{Name: []byte("__name__"), Value: 1},
+		},
+		SeriesCountByLabelValuePair: []*rpc.CardinalityStat{
+			{Name: []byte("__name__=foo"), Value: 2},
+		},
+	}, r)
+
+	// An empty range is rejected before reaching the database.
+	_, err = service.Cardinality(tctx, &rpc.CardinalityRequest{
+		NameSpace:  []byte(nsID),
+		RangeStart: int64(end),
+		RangeEnd:   int64(start),
+	})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+
+	// An unknown namespace is rejected.
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher("unknown")).Return(nil, false)
+	_, err = service.Cardinality(tctx, &rpc.CardinalityRequest{
+		NameSpace:  []byte("unknown"),
+		RangeStart: int64(start),
+		RangeEnd:   int64(end),
+	})
+	require.Error(t, err)
+}
+
+func TestServiceCount(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := storage.NewMockDatabase(ctrl)
+	mockDB.EXPECT().Options().Return(testStorageOpts).AnyTimes()
+	mockDB.EXPECT().IsOverloaded().Return(false).AnyTimes()
+
+	service := NewService(mockDB, testTChannelThriftOptions).(*service)
+
+	tctx, _ := tchannelthrift.NewContext(time.Minute)
+	ctx := tchannelthrift.Context(tctx)
+	defer ctx.Close()
+
+	var (
+		nsID  = "metrics"
+		start = xtime.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		end   = start.Add(time.Hour)
+		query = idx.NewTermQuery([]byte("__name__"), []byte("foo"))
+	)
+	data, err := idx.Marshal(query)
+	require.NoError(t, err)
+
+	sketch, err := hyperloglog.New(hyperloglog.DefaultPrecision)
+	require.NoError(t, err)
+	sketch.Add([]byte("foo{a=\"b\"}"))
+	sketch.Add([]byte("foo{a=\"c\"}"))
+	encodedSketch, err := sketch.MarshalBinary()
+	require.NoError(t, err)
+
+	mockIdx := storage.NewMockNamespaceIndex(ctrl)
+	mockNs := storage.NewMockNamespace(ctrl)
+	mockNs.EXPECT().Index().Return(mockIdx, nil)
+	mockDB.EXPECT().Namespace(ident.NewIDMatcher(nsID)).Return(mockNs, true)
+
+	mockIdx.EXPECT().Count(gomock.Any(), gomock.Any(), index.CountQueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+		Shards:         []uint32{1, 3},
+		IncludeSketch:  true,
+	}).DoAndReturn(func(
+		_ context.Context,
+		q index.Query,
+		_ index.CountQueryOptions,
+	) (index.CountResult, error) {
+		require.True(t, query.Equal(q.Query))
+		return index.CountResult{Count: 2, Sketch: sketch}, nil
+	})
+
+	r, err := service.Count(tctx, &rpc.CountRequest{
+		NameSpace:     []byte(nsID),
+		Query:         data,
+		RangeStart:    int64(start),
+		RangeEnd:      int64(end),
+		Shards:        []int32{1, 3},
+		IncludeSketch: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &rpc.CountResult_{
+		Count:  2,
+		Sketch: encodedSketch,
+	}, r)
+
+	// An empty range is rejected before reaching the database.
+	_, err = service.Count(tctx, &rpc.CountRequest{
+		NameSpace:  []byte(nsID),
+		Query:      data,
+		RangeStart: int64(end),
+		RangeEnd:   int64(start),
+	})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+
+	// An invalid query is rejected before reaching the database.
+	_, err = service.Count(tctx, &rpc.CountRequest{
+		NameSpace:  []byte(nsID),
+		Query:      []byte("invalid"),
+		RangeStart: int64(start),
+		RangeEnd:   int64(end),
+	})
+	require.Error(t, err)
+	require.True(t, tterrors.IsBadRequestError(err.(*rpc.Error)))
+}
+
 func TestServiceSetPersistRateLimit(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
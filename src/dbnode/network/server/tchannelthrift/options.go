@@ -21,6 +21,7 @@
 package tchannelthrift
 
 import (
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
 	"github.com/m3db/m3/src/dbnode/topology"
@@ -47,6 +48,7 @@ type options struct {
 	queryLimits                 limits.QueryLimits
 	permitsOptions              permits.Options
 	seriesBlocksPerBatch        int
+	maxExemplarsPerNamespace    int
 }
 
 // NewOptions creates new options.
@@ -84,6 +86,7 @@ func NewOptions() Options {
 		checkedBytesWrapperPool:  bytesWrapperPool,
 		queryLimits:              limits.NoOpQueryLimits(),
 		permitsOptions:           permits.NewOptions(),
+		maxExemplarsPerNamespace: exemplar.DefaultMaxExemplars,
 	}
 }
 
@@ -216,3 +219,13 @@ func (o *options) SetFetchTaggedSeriesBlocksPerBatch(value int) Options {
 func (o *options) FetchTaggedSeriesBlocksPerBatch() int {
 	return o.seriesBlocksPerBatch
 }
+
+func (o *options) SetMaxExemplarsPerNamespace(value int) Options {
+	opts := *o
+	opts.maxExemplarsPerNamespace = value
+	return &opts
+}
+
+func (o *options) MaxExemplarsPerNamespace() int {
+	return o.maxExemplarsPerNamespace
+}
@@ -112,4 +112,12 @@ type Options interface {
 	// SetFetchTaggedSeriesBlocksPerBatch sets the series blocks allowed to be read
 	// per permit acquired.
 	SetFetchTaggedSeriesBlocksPerBatch(value int) Options
+
+	// SetMaxExemplarsPerNamespace sets the maximum number of exemplars held
+	// in memory per namespace.
+	SetMaxExemplarsPerNamespace(value int) Options
+
+	// MaxExemplarsPerNamespace returns the maximum number of exemplars held
+	// in memory per namespace.
+	MaxExemplarsPerNamespace() int
 }
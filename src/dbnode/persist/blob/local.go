@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package blob
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	localTempFilePattern = ".blob-*"
+	localTempFilePrefix  = ".blob-"
+	localNewFileMode     = os.FileMode(0666)
+	localNewDirMode      = os.FileMode(0755)
+)
+
+type localStore struct {
+	dir string
+}
+
+// NewLocalStore returns a store that keeps blobs as files under a local
+// directory, it is intended for tests and for offloading to a mounted
+// network file system.
+func NewLocalStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, localNewDirMode); err != nil {
+		return nil, err
+	}
+	return &localStore{dir: dir}, nil
+}
+
+func (s *localStore) Put(key string, r io.Reader) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, localNewDirMode); err != nil {
+		return err
+	}
+
+	// NB: write to a temporary file and rename it over the blob so that
+	// readers never observe a partially written blob.
+	f, err := ioutil.TempFile(dir, localTempFilePattern)
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath) // nolint: errcheck
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Chmod(localNewFileMode); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+func (s *localStore) Get(key string) (io.ReadCloser, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *localStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(s.dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), localTempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *localStore) Delete(key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStore) filePath(key string) (string, error) {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key ||
+		strings.HasPrefix(key, "../") || key == ".." {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
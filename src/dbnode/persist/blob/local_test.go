@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package blob
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStore(t *testing.T) (Store, func()) {
+	dir, err := ioutil.TempDir("", "blob")
+	require.NoError(t, err)
+
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+	return store, func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestLocalStorePutGetListDelete(t *testing.T) {
+	store, cleanup := newTestLocalStore(t)
+	defer cleanup()
+
+	keys := []string{
+		"data/ns/1/fileset-10-0-data.db",
+		"data/ns/1/fileset-10-0-index.db",
+		"data/ns/10/fileset-10-0-data.db",
+	}
+	for _, key := range keys {
+		require.NoError(t, store.Put(key, bytes.NewReader([]byte(key))))
+	}
+
+	// Replacing a blob overwrites its contents.
+	require.NoError(t, store.Put(keys[0], bytes.NewReader([]byte("replaced"))))
+
+	r, err := store.Get(keys[0])
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "replaced", string(data))
+
+	listed, err := store.List("data/ns/1/")
+	require.NoError(t, err)
+	require.Equal(t, keys[:2], listed)
+
+	require.NoError(t, store.Delete(keys[0]))
+	require.NoError(t, store.Delete(keys[0]))
+
+	_, err = store.Get(keys[0])
+	require.Equal(t, ErrNotFound, err)
+
+	listed, err = store.List("")
+	require.NoError(t, err)
+	require.Equal(t, []string{keys[1], keys[2]}, listed)
+}
+
+func TestLocalStoreInvalidKeys(t *testing.T) {
+	store, cleanup := newTestLocalStore(t)
+	defer cleanup()
+
+	for _, key := range []string{"", "/abs", "../escape", "a/../b", "a//b"} {
+		require.Equal(t, ErrInvalidKey, store.Put(key, bytes.NewReader(nil)), key)
+		_, err := store.Get(key)
+		require.Equal(t, ErrInvalidKey, err, key)
+		require.Equal(t, ErrInvalidKey, store.Delete(key), key)
+	}
+}
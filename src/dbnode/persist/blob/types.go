@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package blob contains object stores that sealed fileset volumes can be
+// offloaded to from local disk.
+package blob
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	// ErrNotFound is returned when a blob does not exist.
+	ErrNotFound = errors.New("blob not found")
+
+	// ErrInvalidKey is returned when a key is not a relative slash separated
+	// path.
+	ErrInvalidKey = errors.New("blob key must be a relative slash separated path")
+)
+
+// Store is an object store of blobs addressed by keys, keys are relative
+// slash separated paths such as "data/default/0/fileset-0-0-data.db".
+type Store interface {
+	// Put writes a blob, replacing any existing blob with the same key. The
+	// blob is only visible once it has been completely written.
+	Put(key string, r io.Reader) error
+
+	// Get returns a reader of a blob, or ErrNotFound if it does not exist.
+	Get(key string) (io.ReadCloser, error)
+
+	// List returns the keys of the blobs that begin with a prefix in
+	// lexical order.
+	List(prefix string) ([]string, error)
+
+	// Delete removes a blob, deleting a blob that does not exist is not an
+	// error.
+	Delete(key string) error
+}
@@ -58,6 +58,19 @@ const (
 	indexDirName      = "index"
 	snapshotDirName   = "snapshots"
 	commitLogsDirName = "commitlogs"
+	tombstonesDirName = "tombstones"
+	rollupDirName     = "rollup"
+	resizeDirName     = "resize"
+	lateDirName       = "late"
+	bootstrapDirName  = "bootstrap"
+	peersDirName      = "peers"
+
+	tombstonesFileName     = "tombstones.db"
+	rollupProgressFileName = "progress.db"
+	resizeManifestsDirName = "manifests"
+	resizePreviousDirName  = "previous"
+	resizeManifestSuffix   = ".json"
+	peersProgressSuffix    = ".json"
 
 	// The maximum number of delimeters ('-' or '.') that is expected in a
 	// (base) filename.
@@ -1520,6 +1533,65 @@ func CommitLogsDirPath(prefix string) string {
 	return path.Join(prefix, commitLogsDirName)
 }
 
+// NamespaceTombstonesFilePath returns the path to the tombstones file for a given namespace.
+func NamespaceTombstonesFilePath(prefix string, namespace ident.ID) string {
+	return path.Join(prefix, tombstonesDirName, namespace.String(), tombstonesFileName)
+}
+
+// NamespaceRollupProgressFilePath returns the path to the file tracking how far
+// the blocks of a source namespace have been rolled up into a target namespace.
+func NamespaceRollupProgressFilePath(prefix string, source, target ident.ID) string {
+	return path.Join(prefix, rollupDirName, source.String(), target.String(), rollupProgressFileName)
+}
+
+// NamespaceResizeDirPath returns the path to the directory holding the state
+// of resizes of the block sizes of a given namespace.
+func NamespaceResizeDirPath(prefix string, namespace ident.ID) string {
+	return path.Join(prefix, resizeDirName, namespace.String())
+}
+
+// NamespaceResizeFilePathPrefix returns the file path prefix that filesets of
+// a given namespace converted to a new block size are staged under until the
+// resize completes.
+func NamespaceResizeFilePathPrefix(prefix string, namespace ident.ID, blockSize time.Duration) string {
+	return path.Join(NamespaceResizeDirPath(prefix, namespace),
+		strconv.FormatInt(blockSize.Nanoseconds(), 10))
+}
+
+// NamespaceResizePreviousFilePathPrefix returns the file path prefix that the
+// filesets of a given namespace at its previous block size are moved under
+// once a resize completes, until they are no longer in use.
+func NamespaceResizePreviousFilePathPrefix(prefix string, namespace ident.ID) string {
+	return path.Join(NamespaceResizeDirPath(prefix, namespace), resizePreviousDirName)
+}
+
+// ShardResizeManifestFilePath returns the path to the file tracking which
+// volumes the staged filesets of a shard were converted from during a resize
+// of a given namespace to a new block size.
+func ShardResizeManifestFilePath(
+	prefix string,
+	namespace ident.ID,
+	blockSize time.Duration,
+	shard uint32,
+) string {
+	return path.Join(NamespaceResizeFilePathPrefix(prefix, namespace, blockSize),
+		resizeManifestsDirName, strconv.Itoa(int(shard))+resizeManifestSuffix)
+}
+
+// ShardPeersBootstrapProgressFilePath returns the path to the file tracking
+// which blocks of a shard of a given namespace have been streamed from peers
+// to disk and validated during an in-progress peers bootstrap.
+func ShardPeersBootstrapProgressFilePath(prefix string, namespace ident.ID, shard uint32) string {
+	return path.Join(prefix, bootstrapDirName, peersDirName, namespace.String(),
+		strconv.Itoa(int(shard))+peersProgressSuffix)
+}
+
+// NamespaceLateFilePathPrefix returns the file path prefix that the late data
+// sidecar volumes of a given namespace are written under.
+func NamespaceLateFilePathPrefix(prefix string, namespace ident.ID) string {
+	return path.Join(prefix, lateDirName, namespace.String())
+}
+
 // DataFileSetExists determines whether data fileset files exist for the given
 // namespace, shard, block start, and volume.
 func DataFileSetExists(
@@ -1308,6 +1308,20 @@ func (m *MockMergeWith) EXPECT() *MockMergeWithMockRecorder {
 	return m.recorder
 }
 
+// DeletedRanges mocks base method.
+func (m *MockMergeWith) DeletedRanges(arg0 ident.ID, arg1 time.UnixNano) []time.Range {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletedRanges", arg0, arg1)
+	ret0, _ := ret[0].([]time.Range)
+	return ret0
+}
+
+// DeletedRanges indicates an expected call of DeletedRanges.
+func (mr *MockMergeWithMockRecorder) DeletedRanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletedRanges", reflect.TypeOf((*MockMergeWith)(nil).DeletedRanges), arg0, arg1)
+}
+
 // ForEachRemaining mocks base method.
 func (m *MockMergeWith) ForEachRemaining(arg0 context.Context, arg1 time.UnixNano, arg2 ForEachRemainingFn, arg3 namespace.Context) error {
 	m.ctrl.T.Helper()
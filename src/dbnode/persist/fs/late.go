@@ -0,0 +1,303 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fs
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/pool"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// LateFileSetVolumes returns the indexes of the complete late data sidecar
+// volumes of a shard by block start, in ascending order.
+func LateFileSetVolumes(
+	filePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+) (map[xtime.UnixNano][]int, error) {
+	files, err := DataFiles(NamespaceLateFilePathPrefix(filePathPrefix, namespace),
+		namespace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[xtime.UnixNano][]int)
+	for i := range files {
+		if !files[i].HasCompleteCheckpointFile() {
+			continue
+		}
+		id := files[i].ID
+		volumes[id.BlockStart] = append(volumes[id.BlockStart], id.VolumeIndex)
+	}
+	for _, indexes := range volumes {
+		sort.Ints(indexes)
+	}
+	return volumes, nil
+}
+
+// NewLateMergeWith returns a MergeWith that holds the data of the given late
+// data sidecar volumes of a block in memory, so that it can be merged into
+// the fileset of the block. The reader must read from the file path prefix
+// of the sidecar volumes.
+func NewLateMergeWith(
+	reader DataFileSetReader,
+	fileIDs []FileSetFileIdentifier,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+) (MergeWith, error) {
+	m := &resizeMergeWith{
+		blockSize: blockSize,
+		series:    make(map[string]*resizeSeries),
+	}
+	for _, fileID := range fileIDs {
+		if fileID.BlockStart != blockStart {
+			return nil, errors.New("late volume does not belong to block")
+		}
+		if err := m.load(reader, fileID); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+type lateSeeker struct {
+	sync.Mutex
+
+	volumeIndex int
+	seeker      DataFileSetSeeker
+	resources   ReusableSeekerResources
+}
+
+type lateFileSetSeekers struct {
+	sync.RWMutex
+
+	filePathPrefix string
+	namespace      ident.ID
+	shard          uint32
+	blockSize      time.Duration
+	bytesPool      pool.CheckedBytesPool
+	opts           Options
+	blocks         map[xtime.UnixNano][]*lateSeeker
+}
+
+// NewLateFileSetSeekers returns a new LateFileSetSeekers for the late data
+// sidecar volumes of a shard under the given file path prefix.
+func NewLateFileSetSeekers(
+	filePathPrefix string,
+	namespace ident.ID,
+	shard uint32,
+	blockSize time.Duration,
+	bytesPool pool.CheckedBytesPool,
+	opts Options,
+) LateFileSetSeekers {
+	return &lateFileSetSeekers{
+		filePathPrefix: NamespaceLateFilePathPrefix(filePathPrefix, namespace),
+		namespace:      namespace,
+		shard:          shard,
+		blockSize:      blockSize,
+		bytesPool:      bytesPool,
+		opts:           opts,
+		blocks:         make(map[xtime.UnixNano][]*lateSeeker),
+	}
+}
+
+func (s *lateFileSetSeekers) Open(blockStart xtime.UnixNano, volumeIndex int) error {
+	resources := NewReusableSeekerResources(s.opts)
+	seeker := NewSeeker(s.filePathPrefix, s.opts.DataReaderBufferSize(),
+		s.opts.InfoReaderBufferSize(), s.bytesPool, false, s.opts)
+	err := seeker.Open(s.namespace, s.shard, blockStart, volumeIndex, resources)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	seekers := s.blocks[blockStart]
+	for _, existing := range seekers {
+		if existing.volumeIndex == volumeIndex {
+			// Already open.
+			return seeker.Close()
+		}
+	}
+	seekers = append(seekers, &lateSeeker{
+		volumeIndex: volumeIndex,
+		seeker:      seeker,
+		resources:   resources,
+	})
+	sort.Slice(seekers, func(i, j int) bool {
+		return seekers[i].volumeIndex < seekers[j].volumeIndex
+	})
+	s.blocks[blockStart] = seekers
+	return nil
+}
+
+func (s *lateFileSetSeekers) Volumes(blockStart xtime.UnixNano) []int {
+	s.RLock()
+	defer s.RUnlock()
+
+	seekers := s.blocks[blockStart]
+	if len(seekers) == 0 {
+		return nil
+	}
+	volumes := make([]int, 0, len(seekers))
+	for _, seeker := range seekers {
+		volumes = append(volumes, seeker.volumeIndex)
+	}
+	return volumes
+}
+
+func (s *lateFileSetSeekers) Stream(
+	ctx context.Context,
+	id ident.ID,
+	blockStart xtime.UnixNano,
+) ([]xio.BlockReader, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	var readers []xio.BlockReader
+	for _, seeker := range s.blocks[blockStart] {
+		segment, found, err := seeker.seek(id)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		reader := xio.NewSegmentReader(segment)
+		ctx.RegisterFinalizer(reader)
+		readers = append(readers, xio.BlockReader{
+			SegmentReader: reader,
+			Start:         blockStart,
+			BlockSize:     s.blockSize,
+		})
+	}
+	return readers, nil
+}
+
+func (s *lateSeeker) seek(id ident.ID) (ts.Segment, bool, error) {
+	if !s.seeker.ConcurrentIDBloomFilter().Test(id.Bytes()) {
+		return ts.Segment{}, false, nil
+	}
+
+	// Seekers are not safe for concurrent use, sidecar volumes are expected
+	// to be read rarely enough for a lock to be cheaper than a seeker pool.
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.seeker.SeekIndexEntry(id, s.resources)
+	if errors.Is(err, errSeekIDNotFound) {
+		return ts.Segment{}, false, nil
+	}
+	if err != nil {
+		return ts.Segment{}, false, err
+	}
+
+	data, err := s.seeker.SeekByIndexEntry(entry, s.resources)
+	if err != nil {
+		return ts.Segment{}, false, err
+	}
+	return ts.NewSegment(data, nil, entry.DataChecksum, ts.FinalizeHead), true, nil
+}
+
+func (s *lateFileSetSeekers) Remove(blockStart xtime.UnixNano, volumeIndex int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	multiErr := xerrors.NewMultiError()
+	var remaining []*lateSeeker
+	for _, seeker := range s.blocks[blockStart] {
+		if seeker.volumeIndex > volumeIndex {
+			remaining = append(remaining, seeker)
+			continue
+		}
+		multiErr = multiErr.Add(seeker.seeker.Close())
+	}
+	if len(remaining) == 0 {
+		delete(s.blocks, blockStart)
+	} else {
+		s.blocks[blockStart] = remaining
+	}
+
+	multiErr = multiErr.Add(s.deleteFiles(func(id FileSetFileIdentifier) bool {
+		return id.BlockStart == blockStart && id.VolumeIndex <= volumeIndex
+	}))
+	return multiErr.FinalError()
+}
+
+func (s *lateFileSetSeekers) RemoveBefore(blockStart xtime.UnixNano) error {
+	s.Lock()
+	defer s.Unlock()
+
+	multiErr := xerrors.NewMultiError()
+	for start, seekers := range s.blocks {
+		if !start.Before(blockStart) {
+			continue
+		}
+		for _, seeker := range seekers {
+			multiErr = multiErr.Add(seeker.seeker.Close())
+		}
+		delete(s.blocks, start)
+	}
+
+	multiErr = multiErr.Add(s.deleteFiles(func(id FileSetFileIdentifier) bool {
+		return id.BlockStart.Before(blockStart)
+	}))
+	return multiErr.FinalError()
+}
+
+func (s *lateFileSetSeekers) deleteFiles(match func(id FileSetFileIdentifier) bool) error {
+	files, err := DataFiles(s.filePathPrefix, s.namespace, s.shard)
+	if err != nil {
+		return err
+	}
+
+	var toDelete []string
+	for _, file := range files {
+		if match(file.ID) {
+			toDelete = append(toDelete, file.AbsoluteFilePaths...)
+		}
+	}
+	return DeleteFiles(toDelete)
+}
+
+func (s *lateFileSetSeekers) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	multiErr := xerrors.NewMultiError()
+	for start, seekers := range s.blocks {
+		for _, seeker := range seekers {
+			multiErr = multiErr.Add(seeker.seeker.Close())
+		}
+		delete(s.blocks, start)
+	}
+	return multiErr.FinalError()
+}
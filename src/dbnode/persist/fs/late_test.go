@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package fs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLateFileSets(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		nsID            = ident.StringID("foo")
+		shard    uint32 = 1
+		fsOpts          = NewOptions().SetFilePathPrefix(dir)
+		lateOpts        = NewOptions().SetFilePathPrefix(
+			NamespaceLateFilePathPrefix(dir, nsID))
+		dp1 = ts.Datapoint{TimestampNanos: startTime.Add(time.Minute), Value: 1}
+		dp2 = ts.Datapoint{TimestampNanos: startTime.Add(2 * time.Minute), Value: 2}
+		dp3 = ts.Datapoint{TimestampNanos: startTime.Add(3 * time.Minute), Value: 3}
+	)
+	fileID := func(volume int) FileSetFileIdentifier {
+		return FileSetFileIdentifier{
+			Namespace:   nsID,
+			Shard:       shard,
+			BlockStart:  startTime,
+			VolumeIndex: volume,
+		}
+	}
+	writeDatapointsToDisk(t, fileID(1), blockSize, map[string][]ts.Datapoint{
+		"a": {dp1},
+	}, lateOpts)
+	writeDatapointsToDisk(t, fileID(2), blockSize, map[string][]ts.Datapoint{
+		"a": {dp2},
+		"b": {dp3},
+	}, lateOpts)
+
+	volumes, err := LateFileSetVolumes(dir, nsID, shard)
+	require.NoError(t, err)
+	require.Equal(t, map[xtime.UnixNano][]int{startTime: {1, 2}}, volumes)
+
+	seekers := NewLateFileSetSeekers(dir, nsID, shard, blockSize, bytesPool, fsOpts)
+	defer seekers.Close() // nolint
+	require.NoError(t, seekers.Open(startTime, 2))
+	require.NoError(t, seekers.Open(startTime, 1))
+	require.NoError(t, seekers.Open(startTime, 1))
+	require.Equal(t, []int{1, 2}, seekers.Volumes(startTime))
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+	readers, err := seekers.Stream(ctx, ident.StringID("a"), startTime)
+	require.NoError(t, err)
+	require.Equal(t, [][]ts.Datapoint{{dp1}, {dp2}}, blockReadersDatapoints(t, readers))
+	readers, err = seekers.Stream(ctx, ident.StringID("c"), startTime)
+	require.NoError(t, err)
+	require.Len(t, readers, 0)
+
+	reader, err := NewReader(bytesPool, lateOpts)
+	require.NoError(t, err)
+	mergeWith, err := NewLateMergeWith(reader, []FileSetFileIdentifier{fileID(1), fileID(2)},
+		startTime, blockSize)
+	require.NoError(t, err)
+	readers, ok, err := mergeWith.Read(ctx, ident.StringID("a"), startTime, namespace.Context{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, [][]ts.Datapoint{{dp1}, {dp2}}, blockReadersDatapoints(t, readers))
+	var remaining []string
+	err = mergeWith.ForEachRemaining(ctx, startTime,
+		func(metadata doc.Metadata, result block.FetchBlockResult) error {
+			remaining = append(remaining, string(metadata.ID))
+			require.Equal(t, [][]ts.Datapoint{{dp3}}, blockReadersDatapoints(t, result.Blocks))
+			return nil
+		}, namespace.Context{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, remaining)
+	require.Nil(t, mergeWith.DeletedRanges(ident.StringID("a"), startTime))
+
+	require.NoError(t, seekers.Remove(startTime, 1))
+	require.Equal(t, []int{2}, seekers.Volumes(startTime))
+	volumes, err = LateFileSetVolumes(dir, nsID, shard)
+	require.NoError(t, err)
+	require.Equal(t, map[xtime.UnixNano][]int{startTime: {2}}, volumes)
+
+	require.NoError(t, seekers.RemoveBefore(startTime.Add(blockSize)))
+	require.Nil(t, seekers.Volumes(startTime))
+	volumes, err = LateFileSetVolumes(dir, nsID, shard)
+	require.NoError(t, err)
+	require.Len(t, volumes, 0)
+}
+
+func blockReadersDatapoints(t *testing.T, readers []xio.BlockReader) [][]ts.Datapoint {
+	var result [][]ts.Datapoint
+	for _, reader := range readers {
+		segment, err := reader.Segment()
+		require.NoError(t, err)
+		result = append(result, datapointsFromSegment(t, segment))
+	}
+	return result
+}
@@ -38,7 +38,10 @@ import (
 	xtime "github.com/m3db/m3/src/x/time"
 )
 
-var errMergeAndCleanupNotSupported = errors.New("function MergeAndCleanup not supported outside of bootstrapping")
+var (
+	errMergeAndCleanupNotSupported = errors.New("function MergeAndCleanup not supported outside of bootstrapping")
+	errMergeResizedNoFileSets      = errors.New("no filesets to merge into resized block")
+)
 
 type merger struct {
 	reader         DataFileSetReader
@@ -95,6 +98,45 @@ func (m *merger) Merge(
 	flushPreparer persist.FlushPreparer,
 	nsCtx namespace.Context,
 	onFlush persist.OnFlushSeries,
+) (persist.DataCloser, error) {
+	return m.merge(fileID, fileID.BlockStart, mergeWith, nextVolumeIndex,
+		flushPreparer, nsCtx, onFlush)
+}
+
+// MergeResized merges the data of filesets written at a different block size
+// into a single fileset at the given block start of the block size of the
+// namespace options of the merger. All data is re-encoded for the new block
+// and data outside of it is dropped. The first fileset is read sequentially
+// from disk while the data of the rest is held in memory.
+func (m *merger) MergeResized(
+	fileIDs []FileSetFileIdentifier,
+	blockStart xtime.UnixNano,
+	nextVolumeIndex int,
+	flushPreparer persist.FlushPreparer,
+	nsCtx namespace.Context,
+) (persist.DataCloser, error) {
+	if len(fileIDs) == 0 {
+		return nil, errMergeResizedNoFileSets
+	}
+
+	blockSize := m.nsOpts.RetentionOptions().BlockSize()
+	mergeWith, err := newResizeMergeWith(m.reader, fileIDs[1:], blockStart, blockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.merge(fileIDs[0], blockStart, mergeWith, nextVolumeIndex,
+		flushPreparer, nsCtx, &persist.NoOpColdFlushNamespace{})
+}
+
+func (m *merger) merge(
+	fileID FileSetFileIdentifier,
+	blockStart xtime.UnixNano,
+	mergeWith MergeWith,
+	nextVolumeIndex int,
+	flushPreparer persist.FlushPreparer,
+	nsCtx namespace.Context,
+	onFlush persist.OnFlushSeries,
 ) (persist.DataCloser, error) {
 	var (
 		reader         = m.reader
@@ -104,17 +146,15 @@ func (m *merger) Merge(
 		encoderPool    = m.encoderPool
 		nsOpts         = m.nsOpts
 
-		nsID       = fileID.Namespace
-		shard      = fileID.Shard
-		startTime  = fileID.BlockStart
-		volume     = fileID.VolumeIndex
-		blockSize  = nsOpts.RetentionOptions().BlockSize()
-		blockStart = startTime
-		openOpts   = DataReaderOpenOptions{
+		nsID      = fileID.Namespace
+		shard     = fileID.Shard
+		volume    = fileID.VolumeIndex
+		blockSize = nsOpts.RetentionOptions().BlockSize()
+		openOpts  = DataReaderOpenOptions{
 			Identifier: FileSetFileIdentifier{
 				Namespace:   nsID,
 				Shard:       shard,
-				BlockStart:  startTime,
+				BlockStart:  fileID.BlockStart,
 				VolumeIndex: volume,
 			},
 			FileSetType: persist.FileSetFlushType,
@@ -135,7 +175,7 @@ func (m *merger) Merge(
 	prepareOpts := persist.DataPrepareOptions{
 		NamespaceMetadata: nsMd,
 		Shard:             shard,
-		BlockStart:        startTime,
+		BlockStart:        blockStart,
 		VolumeIndex:       nextVolumeIndex,
 		FileSetType:       persist.FileSetFlushType,
 		DeleteIfExists:    false,
@@ -198,6 +238,9 @@ func (m *merger) Merge(
 			segmentReaders = appendBlockReadersToSegmentReaders(segmentReaders, mergeWithData)
 		}
 
+		// Check if any of this series' data was deleted (and thus requires dropping).
+		deleted := mergeWith.DeletedRanges(id, blockStart)
+
 		// Inform the writer to finalize the ID and tag iterator once
 		// the volume is written.
 		metadata := persist.NewMetadataFromIDAndTagIterator(id, tagsIter,
@@ -209,7 +252,7 @@ func (m *merger) Merge(
 		// In the special (but common) case that we're just copying the series data from the old file
 		// into the new one without merging or adding any additional data we can avoid recalculating
 		// the checksum.
-		if len(segmentReaders) == 1 && hasInMemoryData == false {
+		if len(segmentReaders) == 1 && hasInMemoryData == false && len(deleted) == 0 {
 			segment, err := segmentReaders[0].Segment()
 			if err != nil {
 				return closer, err
@@ -219,9 +262,17 @@ func (m *merger) Merge(
 				return closer, err
 			}
 		} else {
-			if err := persistSegmentReaders(metadata, segmentReaders, iterResources, prepared.Persist); err != nil {
+			persisted, err := persistSegmentReaders(metadata, segmentReaders, deleted,
+				iterResources, prepared.Persist)
+			if err != nil {
 				return closer, err
 			}
+			if !persisted {
+				// NB: the writer only finalizes the ID and tag iterator of
+				// series it has written, so finalize those of deleted series here.
+				id.Finalize()
+				tagsIter.Close()
+			}
 		}
 		// Closing the context will finalize the data returned from
 		// mergeWith.Read(), but is safe because it has already been persisted
@@ -239,12 +290,14 @@ func (m *merger) Merge(
 			segmentReaders = appendBlockReadersToSegmentReaders(segmentReaders, mergeWithData.Blocks)
 
 			metadata := persist.NewMetadata(seriesMetadata)
-			err := persistSegmentReaders(metadata, segmentReaders, iterResources, prepared.Persist)
+			deleted := mergeWith.DeletedRanges(ident.BytesID(seriesMetadata.ID), blockStart)
+			persisted, err := persistSegmentReaders(metadata, segmentReaders, deleted,
+				iterResources, prepared.Persist)
 
-			if err == nil {
+			if err == nil && persisted {
 				err = onFlush.OnFlushNewSeries(persist.OnFlushNewSeriesEvent{
 					Shard:      shard,
-					BlockStart: startTime,
+					BlockStart: blockStart,
 					FirstWrite: mergeWithData.FirstWrite,
 					SeriesMetadata: persist.SeriesMetadata{
 						Type:     persist.SeriesDocumentType,
@@ -313,46 +366,69 @@ func segmentReaderFromData(
 	return segReader
 }
 
+// persistSegmentReaders persists the data of the segment readers without the
+// data within the deleted ranges, returning whether any data was persisted.
 func persistSegmentReaders(
 	metadata persist.Metadata,
 	segReaders []xio.SegmentReader,
+	deleted []xtime.Range,
 	ir iterResources,
 	persistFn persist.DataFn,
-) error {
+) (bool, error) {
 	if len(segReaders) == 0 {
-		return nil
+		return false, nil
 	}
 
-	if len(segReaders) == 1 {
-		return persistSegmentReader(metadata, segReaders[0], persistFn)
+	if len(segReaders) == 1 && len(deleted) == 0 {
+		return true, persistSegmentReader(metadata, segReaders[0], persistFn)
 	}
 
-	return persistIter(metadata, segReaders, ir, persistFn)
+	return persistIter(metadata, segReaders, deleted, ir, persistFn)
 }
 
 func persistIter(
 	metadata persist.Metadata,
 	segReaders []xio.SegmentReader,
+	deleted []xtime.Range,
 	ir iterResources,
 	persistFn persist.DataFn,
-) error {
+) (bool, error) {
 	it := ir.multiIter
 	it.Reset(segReaders, ir.blockStart, ir.blockSize, ir.schema)
 	encoder := ir.encoderPool.Get()
 	encoder.Reset(ir.blockStart, ir.blockAllocSize, ir.schema)
 	for it.Next() {
-		if err := encoder.Encode(it.Current()); err != nil {
+		dp, unit, annotation := it.Current()
+		if isDeleted(deleted, dp.TimestampNanos) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
 			encoder.Close()
-			return err
+			return false, err
 		}
 	}
 	if err := it.Err(); err != nil {
 		encoder.Close()
-		return err
+		return false, err
+	}
+
+	if encoder.NumEncoded() == 0 {
+		// All of the series' data was deleted.
+		encoder.Close()
+		return false, nil
 	}
 
 	segment := encoder.Discard()
-	return persistSegment(metadata, segment, persistFn)
+	return true, persistSegment(metadata, segment, persistFn)
+}
+
+func isDeleted(deleted []xtime.Range, t xtime.UnixNano) bool {
+	for _, r := range deleted {
+		if !t.Before(r.Start) && t.Before(r.End) {
+			return true
+		}
+	}
+	return false
 }
 
 func persistSegmentReader(
@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -433,6 +434,58 @@ func TestMergeWithNoData(t *testing.T) {
 	testMergeWith(t, diskData, mergeTargetData, expected)
 }
 
+func TestMergeWithDeletedRanges(t *testing.T) {
+	// This test scenario is when series have been deleted over part or all
+	// of the block, deleted datapoints should be dropped and series with no
+	// remaining datapoints should not be persisted at all.
+	diskData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	diskData.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(0 * time.Second), Value: 0},
+		{TimestampNanos: startTime.Add(1 * time.Second), Value: 1},
+		{TimestampNanos: startTime.Add(2 * time.Second), Value: 2},
+	}))
+	diskData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(2 * time.Second), Value: 2},
+		{TimestampNanos: startTime.Add(3 * time.Second), Value: 3},
+	}))
+	diskData.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(1 * time.Second), Value: 7},
+	}))
+
+	mergeTargetData := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	mergeTargetData.Set(id1, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(4 * time.Second), Value: 4},
+	}))
+	mergeTargetData.Set(id3, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(2 * time.Second), Value: 26},
+		{TimestampNanos: startTime.Add(4 * time.Second), Value: 27},
+	}))
+	mergeTargetData.Set(id4, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(8 * time.Second), Value: 29},
+	}))
+
+	deleted := map[string][]xtime.Range{
+		id0.String(): {{Start: startTime.Add(time.Second), End: startTime.Add(2 * time.Second)}},
+		id1.String(): {{Start: startTime, End: startTime.Add(blockSize)}},
+		id3.String(): {{Start: startTime.Add(3 * time.Second), End: startTime.Add(blockSize)}},
+		id4.String(): {{Start: startTime.Add(-blockSize), End: startTime.Add(blockSize)}},
+	}
+
+	expected := newCheckedBytesByIDMap(newCheckedBytesByIDMapOptions{})
+	expected.Set(id0, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(0 * time.Second), Value: 0},
+		{TimestampNanos: startTime.Add(2 * time.Second), Value: 2},
+	}))
+	expected.Set(id2, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(1 * time.Second), Value: 7},
+	}))
+	expected.Set(id3, datapointsToCheckedBytes(t, []ts.Datapoint{
+		{TimestampNanos: startTime.Add(2 * time.Second), Value: 26},
+	}))
+
+	testMergeWithDeleted(t, diskData, mergeTargetData, deleted, expected)
+}
+
 func TestCleanup(t *testing.T) {
 	dir := createTempDir(t)
 	filePathPrefix := filepath.Join(dir, "")
@@ -502,6 +555,168 @@ func TestCleanupOnceBootstrapped(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestMergeResizedGrow(t *testing.T) {
+	// This test scenario is when two blocks are merged into a block that
+	// spans both of them, series only in the second block should be merged too.
+	start := startTime.Truncate(2 * blockSize)
+	sources := []map[string][]ts.Datapoint{
+		{
+			"id0": {
+				{TimestampNanos: start.Add(time.Second), Value: 1},
+				{TimestampNanos: start.Add(2 * time.Second), Value: 2},
+			},
+			"id1": {{TimestampNanos: start.Add(time.Second), Value: 3}},
+		},
+		{
+			"id0": {{TimestampNanos: start.Add(blockSize + time.Second), Value: 4}},
+			"id2": {{TimestampNanos: start.Add(blockSize + time.Second), Value: 5}},
+		},
+	}
+	expected := map[string][]ts.Datapoint{
+		"id0": {
+			{TimestampNanos: start.Add(time.Second), Value: 1},
+			{TimestampNanos: start.Add(2 * time.Second), Value: 2},
+			{TimestampNanos: start.Add(blockSize + time.Second), Value: 4},
+		},
+		"id1": {{TimestampNanos: start.Add(time.Second), Value: 3}},
+		"id2": {{TimestampNanos: start.Add(blockSize + time.Second), Value: 5}},
+	}
+
+	testMergeResized(t, blockSize, sources, start, 2*blockSize, expected)
+}
+
+func TestMergeResizedShrink(t *testing.T) {
+	// This test scenario is when a block is split into smaller blocks, data
+	// outside of the block should be dropped along with series that have no
+	// data within the block.
+	start := startTime.Truncate(2 * blockSize)
+	sources := []map[string][]ts.Datapoint{
+		{
+			"id0": {
+				{TimestampNanos: start.Add(time.Second), Value: 1},
+				{TimestampNanos: start.Add(blockSize + time.Second), Value: 2},
+			},
+			"id1": {{TimestampNanos: start.Add(time.Second), Value: 3}},
+		},
+	}
+	expected := map[string][]ts.Datapoint{
+		"id0": {{TimestampNanos: start.Add(blockSize + time.Second), Value: 2}},
+	}
+
+	testMergeResized(t, 2*blockSize, sources, start.Add(blockSize), blockSize, expected)
+}
+
+func TestMergeResizedNoFileSets(t *testing.T) {
+	merger := merger{}
+	_, err := merger.MergeResized(nil, startTime, 0, nil, namespace.Context{})
+	require.Equal(t, errMergeResizedNoFileSets, err)
+}
+
+func testMergeResized(
+	t *testing.T,
+	sourceBlockSize time.Duration,
+	sources []map[string][]ts.Datapoint,
+	resizedBlockStart xtime.UnixNano,
+	resizedBlockSize time.Duration,
+	expected map[string][]ts.Datapoint,
+) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		nsID          = ident.StringID("foo")
+		shard  uint32 = 1
+		fsOpts        = NewOptions().SetFilePathPrefix(dir)
+		resizedOpts   = NewOptions().SetFilePathPrefix(
+			NamespaceResizeFilePathPrefix(dir, nsID, resizedBlockSize))
+		fileIDs []FileSetFileIdentifier
+	)
+	for i, source := range sources {
+		fileID := FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      shard,
+			BlockStart: resizedBlockStart.Truncate(sourceBlockSize).Add(time.Duration(i) * sourceBlockSize),
+		}
+		writeDatapointsToDisk(t, fileID, sourceBlockSize, source, fsOpts)
+		fileIDs = append(fileIDs, fileID)
+	}
+
+	nsOpts := namespace.NewOptions().SetRetentionOptions(
+		retention.NewOptions().SetBlockSize(resizedBlockSize))
+	md, err := namespace.NewMetadata(nsID, nsOpts)
+	require.NoError(t, err)
+
+	reader, err := NewReader(bytesPool, fsOpts)
+	require.NoError(t, err)
+	merger := NewMerger(reader, 0, srPool, multiIterPool, identPool, encoderPool,
+		contextPool, dir, nsOpts)
+
+	pm, err := NewPersistManager(resizedOpts)
+	require.NoError(t, err)
+	preparer, err := pm.StartFlushPersist()
+	require.NoError(t, err)
+	close, err := merger.MergeResized(fileIDs, resizedBlockStart, 0, preparer,
+		namespace.NewContextFrom(md))
+	require.NoError(t, err)
+	require.NoError(t, close())
+	require.NoError(t, preparer.DoneFlush())
+
+	resizedReader, err := NewReader(bytesPool, resizedOpts)
+	require.NoError(t, err)
+	err = resizedReader.Open(DataReaderOpenOptions{
+		Identifier: FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      shard,
+			BlockStart: resizedBlockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	})
+	require.NoError(t, err)
+	defer resizedReader.Close()
+
+	actual := make(map[string][]ts.Datapoint)
+	for {
+		id, tagsIter, data, checksum, err := resizedReader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		actual[id.String()] = datapointsFromSegment(t,
+			ts.NewSegment(data, nil, checksum, ts.FinalizeHead))
+		id.Finalize()
+		tagsIter.Close()
+	}
+	require.Equal(t, expected, actual)
+}
+
+func writeDatapointsToDisk(
+	t *testing.T,
+	fsID FileSetFileIdentifier,
+	blockSize time.Duration,
+	data map[string][]ts.Datapoint,
+	fsOpts Options,
+) {
+	w, err := NewWriter(fsOpts)
+	require.NoError(t, err)
+
+	err = w.Open(DataWriterOpenOptions{
+		Identifier: fsID,
+		BlockSize:  blockSize,
+	})
+	require.NoError(t, err)
+
+	for id, dps := range data {
+		bytes := datapointsToCheckedBytes(t, dps)
+		bytes.IncRef()
+		metadata := persist.NewMetadataFromIDAndTags(ident.StringID(id),
+			ident.Tags{}, persist.MetadataOptions{})
+		err = w.Write(metadata, bytes, digest.Checksum(bytes.Bytes()))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+}
+
 func writeFilesetToDisk(t *testing.T, fsID FileSetFileIdentifier, fsOpts Options) {
 	w, err := NewWriter(fsOpts)
 	require.NoError(t, err)
@@ -531,6 +746,16 @@ func testMergeWith(
 	diskData *checkedBytesMap,
 	mergeTargetData *checkedBytesMap,
 	expectedData *checkedBytesMap,
+) {
+	testMergeWithDeleted(t, diskData, mergeTargetData, nil, expectedData)
+}
+
+func testMergeWithDeleted(
+	t *testing.T,
+	diskData *checkedBytesMap,
+	mergeTargetData *checkedBytesMap,
+	deleted map[string][]xtime.Range,
+	expectedData *checkedBytesMap,
 ) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -569,7 +794,7 @@ func testMergeWith(
 		Shard:      uint32(8),
 		BlockStart: startTime,
 	}
-	mergeWith := mockMergeWithFromData(t, ctrl, diskData, mergeTargetData)
+	mergeWith := mockMergeWithFromData(t, ctrl, diskData, mergeTargetData, deleted)
 	close, err := merger.Merge(fsID, mergeWith, 1, preparer, nsCtx, &persist.NoOpColdFlushNamespace{})
 	require.NoError(t, err)
 	require.False(t, deferClosed)
@@ -654,8 +879,14 @@ func mockMergeWithFromData(
 	ctrl *gomock.Controller,
 	diskData *checkedBytesMap,
 	mergeTargetData *checkedBytesMap,
+	deleted map[string][]xtime.Range,
 ) *MockMergeWith {
 	mergeWith := NewMockMergeWith(ctrl)
+	mergeWith.EXPECT().DeletedRanges(gomock.Any(), startTime).
+		DoAndReturn(func(id ident.ID, _ xtime.UnixNano) []xtime.Range {
+			return deleted[id.String()]
+		}).
+		AnyTimes()
 
 	// Get the series IDs in the merge target that does not exist in disk data.
 	// This logic is not tested here because it should be part of tests of the
@@ -53,3 +53,10 @@ func (m *noopMergeWith) ForEachRemaining(
 ) error {
 	return nil
 }
+
+func (m *noopMergeWith) DeletedRanges(
+	_ ident.ID,
+	_ xtime.UnixNano,
+) []xtime.Range {
+	return nil
+}
@@ -0,0 +1,520 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist/blob"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const offloadTempFilePattern = ".offload-*"
+
+var (
+	errOffloadAfterInvalid       = errors.New("offload after must be positive")
+	errMaxRestoredVolumesInvalid = errors.New("max restored volumes must be positive")
+
+	// offloadedFileSuffixes are the suffixes of the files of a volume that
+	// are removed from local disk when it is offloaded.
+	offloadedFileSuffixes = []string{
+		dataFileSuffix,
+		indexFileSuffix,
+		summariesFileSuffix,
+		bloomFilterFileSuffix,
+	}
+)
+
+// Validate validates the offload options.
+func (o OffloadOptions) Validate() error {
+	if o.OffloadAfter <= 0 {
+		return errOffloadAfterInvalid
+	}
+	if o.MaxRestoredVolumes <= 0 {
+		return errMaxRestoredVolumesInvalid
+	}
+	return nil
+}
+
+type offloadShardKey struct {
+	namespace string
+	shard     uint32
+}
+
+type offloadBlockKey struct {
+	offloadShardKey
+	blockStart xtime.UnixNano
+}
+
+type offloadedVolume struct {
+	checkpointFilePath string
+	restored           bool
+	// lastRead is the unix nanos of the last read of the volume while it is
+	// restored, it is updated atomically without holding the lock.
+	lastRead int64
+}
+
+func (v *offloadedVolume) filePaths() []string {
+	prefix := strings.TrimSuffix(v.checkpointFilePath, checkpointFileSuffix+fileSuffix)
+	paths := make([]string, 0, len(offloadedFileSuffixes))
+	for _, suffix := range offloadedFileSuffixes {
+		paths = append(paths, prefix+suffix+fileSuffix)
+	}
+	return paths
+}
+
+type fileSetOffloaderMetrics struct {
+	offloaded      tally.Counter
+	offloadErrors  tally.Counter
+	restored       tally.Counter
+	restoreErrors  tally.Counter
+	evicted        tally.Counter
+	deletedObjects tally.Counter
+}
+
+func newFileSetOffloaderMetrics(scope tally.Scope) fileSetOffloaderMetrics {
+	return fileSetOffloaderMetrics{
+		offloaded:      scope.Counter("offloaded"),
+		offloadErrors:  scope.Counter("offload-errors"),
+		restored:       scope.Counter("restored"),
+		restoreErrors:  scope.Counter("restore-errors"),
+		evicted:        scope.Counter("evicted"),
+		deletedObjects: scope.Counter("deleted-objects"),
+	}
+}
+
+type fileSetOffloader struct {
+	sync.RWMutex
+
+	// restoreLock serializes changes to the local files of offloaded volumes.
+	restoreLock sync.Mutex
+
+	store          blob.Store
+	opts           Options
+	offloadOpts    OffloadOptions
+	filePathPrefix string
+	nowFn          func() time.Time
+	logger         *zap.Logger
+	metrics        fileSetOffloaderMetrics
+
+	scanned   map[offloadShardKey]struct{}
+	offloaded map[offloadBlockKey]*offloadedVolume
+}
+
+// NewFileSetOffloader returns a new offloader of data fileset volumes to a
+// blob store.
+func NewFileSetOffloader(
+	store blob.Store,
+	opts Options,
+	offloadOpts OffloadOptions,
+) (FileSetOffloader, error) {
+	if err := offloadOpts.Validate(); err != nil {
+		return nil, err
+	}
+
+	iOpts := opts.InstrumentOptions()
+	return &fileSetOffloader{
+		store:          store,
+		opts:           opts,
+		offloadOpts:    offloadOpts,
+		filePathPrefix: opts.FilePathPrefix(),
+		nowFn:          opts.ClockOptions().NowFn(),
+		logger:         iOpts.Logger(),
+		metrics:        newFileSetOffloaderMetrics(iOpts.MetricsScope().SubScope("offload")),
+		scanned:        make(map[offloadShardKey]struct{}),
+		offloaded:      make(map[offloadBlockKey]*offloadedVolume),
+	}, nil
+}
+
+func (o *fileSetOffloader) Offload(
+	namespace ident.ID,
+	shard uint32,
+	blockSize time.Duration,
+	now xtime.UnixNano,
+	earliestToRetain xtime.UnixNano,
+) error {
+	o.restoreLock.Lock()
+	defer o.restoreLock.Unlock()
+
+	shardKey := offloadShardKey{namespace: namespace.String(), shard: shard}
+	latest, err := o.scanWithRestoreLock(namespace, shardKey)
+	if err != nil {
+		return err
+	}
+
+	var (
+		multiErr     = xerrors.NewMultiError()
+		offloadUntil = now.Add(-o.offloadOpts.OffloadAfter)
+	)
+	for blockStart, fileset := range latest {
+		if blockStart.Before(earliestToRetain) ||
+			blockStart.Add(blockSize).After(offloadUntil) {
+			continue
+		}
+
+		blockKey := offloadBlockKey{offloadShardKey: shardKey, blockStart: blockStart}
+		o.RLock()
+		_, ok := o.offloaded[blockKey]
+		o.RUnlock()
+		if ok {
+			continue
+		}
+
+		checkpointFilePath, _ := fileset.filepath(checkpointFileSuffix)
+		volume := &offloadedVolume{checkpointFilePath: checkpointFilePath}
+		if err := o.offloadWithRestoreLock(blockKey, volume); err != nil {
+			o.metrics.offloadErrors.Inc(1)
+			multiErr = multiErr.Add(fmt.Errorf(
+				"failed to offload volume %s: %w", checkpointFilePath, err))
+			continue
+		}
+		o.metrics.offloaded.Inc(1)
+	}
+
+	multiErr = multiErr.Add(o.deleteUnusedObjectsWithRestoreLock(namespace, shardKey))
+	return multiErr.FinalError()
+}
+
+// scanWithRestoreLock returns the latest complete volume of each block of a
+// shard, and records the volumes whose files have already been offloaded.
+// Volumes that are no longer the latest volume of a block are forgotten.
+func (o *fileSetOffloader) scanWithRestoreLock(
+	namespace ident.ID,
+	shardKey offloadShardKey,
+) (map[xtime.UnixNano]FileSetFile, error) {
+	filesets, err := DataFiles(o.filePathPrefix, namespace, shardKey.shard)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[xtime.UnixNano]FileSetFile, len(filesets))
+	for _, fileset := range filesets {
+		if !fileset.HasCompleteCheckpointFile() {
+			continue
+		}
+		existing, ok := latest[fileset.ID.BlockStart]
+		if !ok || fileset.ID.VolumeIndex > existing.ID.VolumeIndex {
+			latest[fileset.ID.BlockStart] = fileset
+		}
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	for key, volume := range o.offloaded {
+		if key.offloadShardKey != shardKey {
+			continue
+		}
+		fileset, ok := latest[key.blockStart]
+		if !ok {
+			delete(o.offloaded, key)
+			continue
+		}
+		if path, _ := fileset.filepath(checkpointFileSuffix); path != volume.checkpointFilePath {
+			delete(o.offloaded, key)
+		}
+	}
+
+	for blockStart, fileset := range latest {
+		key := offloadBlockKey{offloadShardKey: shardKey, blockStart: blockStart}
+		if _, ok := o.offloaded[key]; ok {
+			continue
+		}
+
+		checkpointFilePath, _ := fileset.filepath(checkpointFileSuffix)
+		volume := &offloadedVolume{checkpointFilePath: checkpointFilePath}
+		for _, filePath := range volume.filePaths() {
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				o.offloaded[key] = volume
+				break
+			}
+		}
+	}
+
+	o.scanned[shardKey] = struct{}{}
+	return latest, nil
+}
+
+func (o *fileSetOffloader) offloadWithRestoreLock(
+	key offloadBlockKey,
+	volume *offloadedVolume,
+) error {
+	filePaths := volume.filePaths()
+	for _, filePath := range filePaths {
+		objectKey, err := o.objectKey(filePath)
+		if err != nil {
+			return err
+		}
+		if err := o.upload(objectKey, filePath); err != nil {
+			return err
+		}
+	}
+
+	// NB: record the volume as offloaded before removing its files so that
+	// reads restore the files as soon as any of them are missing.
+	o.Lock()
+	o.offloaded[key] = volume
+	o.Unlock()
+
+	return DeleteFiles(filePaths)
+}
+
+func (o *fileSetOffloader) upload(objectKey, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := o.store.Put(objectKey, f); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	return f.Close()
+}
+
+// deleteUnusedObjectsWithRestoreLock deletes the objects of a shard that do
+// not belong to a volume that is currently offloaded, such as the objects of
+// superseded volumes, of blocks out of retention or of failed uploads.
+func (o *fileSetOffloader) deleteUnusedObjectsWithRestoreLock(
+	namespace ident.ID,
+	shardKey offloadShardKey,
+) error {
+	shardDir := ShardDataDirPath(o.filePathPrefix, namespace, shardKey.shard)
+	prefix, err := o.objectKey(shardDir)
+	if err != nil {
+		return err
+	}
+
+	objectKeys, err := o.store.List(prefix + "/")
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]struct{})
+	o.RLock()
+	for key, volume := range o.offloaded {
+		if key.offloadShardKey != shardKey {
+			continue
+		}
+		for _, filePath := range volume.filePaths() {
+			objectKey, err := o.objectKey(filePath)
+			if err != nil {
+				o.RUnlock()
+				return err
+			}
+			used[objectKey] = struct{}{}
+		}
+	}
+	o.RUnlock()
+
+	multiErr := xerrors.NewMultiError()
+	for _, objectKey := range objectKeys {
+		if _, ok := used[objectKey]; ok {
+			continue
+		}
+		if err := o.store.Delete(objectKey); err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		o.metrics.deletedObjects.Inc(1)
+	}
+	return multiErr.FinalError()
+}
+
+func (o *fileSetOffloader) Restore(
+	namespace ident.ID,
+	shard uint32,
+	blockStart xtime.UnixNano,
+) error {
+	var (
+		shardKey = offloadShardKey{namespace: namespace.String(), shard: shard}
+		blockKey = offloadBlockKey{offloadShardKey: shardKey, blockStart: blockStart}
+		now      = o.nowFn().UnixNano()
+	)
+	o.RLock()
+	_, scanned := o.scanned[shardKey]
+	volume, offloaded := o.offloaded[blockKey]
+	restored := offloaded && volume.restored
+	o.RUnlock()
+
+	if scanned && (!offloaded || restored) {
+		if restored {
+			atomic.StoreInt64(&volume.lastRead, now)
+		}
+		return nil
+	}
+
+	o.restoreLock.Lock()
+	defer o.restoreLock.Unlock()
+
+	if !scanned {
+		if _, err := o.scanWithRestoreLock(namespace, shardKey); err != nil {
+			return err
+		}
+	}
+
+	o.RLock()
+	volume, offloaded = o.offloaded[blockKey]
+	restored = offloaded && volume.restored
+	o.RUnlock()
+	if !offloaded || restored {
+		return nil
+	}
+
+	// The volume may have been superseded by a cold flush and removed since
+	// the shard was last scanned, in which case the newer volume is local.
+	if _, err := os.Stat(volume.checkpointFilePath); os.IsNotExist(err) {
+		o.Lock()
+		delete(o.offloaded, blockKey)
+		o.Unlock()
+		return nil
+	}
+
+	if err := o.download(volume); err != nil {
+		o.metrics.restoreErrors.Inc(1)
+		return fmt.Errorf("failed to restore volume %s: %w",
+			volume.checkpointFilePath, err)
+	}
+	o.metrics.restored.Inc(1)
+
+	o.Lock()
+	volume.restored = true
+	atomic.StoreInt64(&volume.lastRead, now)
+	evicted := o.evictWithLock(volume)
+	o.Unlock()
+
+	// NB: a read that found an evicted volume restored just before it was
+	// evicted will not find its files, since the least recently read volume
+	// is evicted this is unlikely and the data is restored on the next read.
+	for _, volume := range evicted {
+		if err := o.deleteLocalFiles(volume); err != nil {
+			o.logger.Warn("failed to remove restored volume",
+				zap.String("checkpointFilePath", volume.checkpointFilePath),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (o *fileSetOffloader) download(volume *offloadedVolume) error {
+	for _, filePath := range volume.filePaths() {
+		objectKey, err := o.objectKey(filePath)
+		if err != nil {
+			return err
+		}
+		if err := o.downloadFile(objectKey, filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *fileSetOffloader) downloadFile(objectKey, filePath string) error {
+	r, err := o.store.Get(objectKey)
+	if err != nil {
+		return err
+	}
+	defer r.Close() // nolint: errcheck
+
+	f, err := ioutil.TempFile(filepath.Dir(filePath), offloadTempFilePattern)
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath) // nolint: errcheck
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Chmod(o.opts.NewFileMode()); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// evictWithLock marks the least recently read restored volumes beyond the
+// maximum as offloaded again and returns them so their files can be removed,
+// the volume just restored is never evicted since it is about to be read.
+func (o *fileSetOffloader) evictWithLock(keep *offloadedVolume) []*offloadedVolume {
+	var restored []*offloadedVolume
+	for _, volume := range o.offloaded {
+		if volume.restored && volume != keep {
+			restored = append(restored, volume)
+		}
+	}
+
+	var evicted []*offloadedVolume
+	for len(restored)+1 > o.offloadOpts.MaxRestoredVolumes && len(restored) > 0 {
+		oldest := 0
+		for i := range restored {
+			if atomic.LoadInt64(&restored[i].lastRead) <
+				atomic.LoadInt64(&restored[oldest].lastRead) {
+				oldest = i
+			}
+		}
+		restored[oldest].restored = false
+		evicted = append(evicted, restored[oldest])
+		restored = append(restored[:oldest], restored[oldest+1:]...)
+		o.metrics.evicted.Inc(1)
+	}
+	return evicted
+}
+
+func (o *fileSetOffloader) deleteLocalFiles(volume *offloadedVolume) error {
+	multiErr := xerrors.NewMultiError()
+	for _, filePath := range volume.filePaths() {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			multiErr = multiErr.Add(err)
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func (o *fileSetOffloader) objectKey(filePath string) (string, error) {
+	rel, err := filepath.Rel(o.filePathPrefix, filePath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
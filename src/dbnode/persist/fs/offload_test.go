@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/blob"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testOffloadedFileExists(
+	t *testing.T,
+	filePathPrefix string,
+	blockStart xtime.UnixNano,
+	suffix string,
+) bool {
+	shardDir := ShardDataDirPath(filePathPrefix, testNs1ID, 0)
+	_, err := os.Stat(filesetPathFromTimeAndIndex(shardDir, blockStart, 0, suffix))
+	if os.IsNotExist(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestFileSetOffloaderOffloadAndRestore(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+	filePathPrefix := filepath.Join(dir, "data")
+
+	store, err := blob.NewLocalStore(filepath.Join(dir, "blob"))
+	require.NoError(t, err)
+
+	var (
+		opts    = testDefaultOpts.SetFilePathPrefix(filePathPrefix)
+		start   = testWriterStart.Truncate(testBlockSize)
+		blocks  = []xtime.UnixNano{start, start.Add(testBlockSize), start.Add(2 * testBlockSize)}
+		now     = blocks[2].Add(testBlockSize).Add(30 * time.Minute)
+		entries = []testEntry{
+			{"foo", nil, []byte{1, 2, 3}},
+			{"bar", nil, []byte{4, 5, 6}},
+		}
+	)
+	w := newTestWriter(t, filePathPrefix)
+	for _, blockStart := range blocks {
+		writeTestData(t, w, 0, blockStart, entries, persist.FileSetFlushType)
+	}
+
+	offloader, err := NewFileSetOffloader(store, opts, OffloadOptions{
+		OffloadAfter:       time.Hour,
+		MaxRestoredVolumes: 1,
+	})
+	require.NoError(t, err)
+
+	// Only the first two blocks ended more than an hour ago.
+	require.NoError(t, offloader.Offload(testNs1ID, 0, testBlockSize, now, 0))
+	for i, blockStart := range blocks {
+		for _, suffix := range offloadedFileSuffixes {
+			require.Equal(t, i == 2, testOffloadedFileExists(t, filePathPrefix, blockStart, suffix))
+		}
+		require.True(t, testOffloadedFileExists(t, filePathPrefix, blockStart, infoFileSuffix))
+		require.True(t, testOffloadedFileExists(t, filePathPrefix, blockStart, checkpointFileSuffix))
+	}
+
+	objects, err := store.List("")
+	require.NoError(t, err)
+	require.Equal(t, 2*len(offloadedFileSuffixes), len(objects))
+
+	// Restoring makes the volume readable again, restoring a block that was
+	// not offloaded is a no-op.
+	require.NoError(t, offloader.Restore(testNs1ID, 0, blocks[0]))
+	require.NoError(t, offloader.Restore(testNs1ID, 0, blocks[2]))
+	readTestData(t, newTestReader(t, filePathPrefix), 0, blocks[0], entries)
+
+	// Restoring a second volume evicts the least recently read one.
+	require.NoError(t, offloader.Restore(testNs1ID, 0, blocks[1]))
+	require.False(t, testOffloadedFileExists(t, filePathPrefix, blocks[0], dataFileSuffix))
+	readTestData(t, newTestReader(t, filePathPrefix), 0, blocks[1], entries)
+
+	// Offloading again leaves the restored volume in place.
+	require.NoError(t, offloader.Offload(testNs1ID, 0, testBlockSize, now, 0))
+	require.True(t, testOffloadedFileExists(t, filePathPrefix, blocks[1], dataFileSuffix))
+
+	// A new offloader finds offloaded volumes from the files left on disk.
+	offloader, err = NewFileSetOffloader(store, opts, OffloadOptions{
+		OffloadAfter:       time.Hour,
+		MaxRestoredVolumes: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, offloader.Restore(testNs1ID, 0, blocks[0]))
+	readTestData(t, newTestReader(t, filePathPrefix), 0, blocks[0], entries)
+
+	// Objects of volumes removed from local disk are deleted.
+	require.NoError(t, DeleteFileSetAt(filePathPrefix, testNs1ID, 0, blocks[0], 0))
+	require.NoError(t, DeleteFileSetAt(filePathPrefix, testNs1ID, 0, blocks[1], 0))
+	require.NoError(t, offloader.Offload(testNs1ID, 0, testBlockSize, now, blocks[2]))
+	objects, err = store.List("")
+	require.NoError(t, err)
+	require.Empty(t, objects)
+}
+
+func TestOffloadOptionsValidate(t *testing.T) {
+	require.NoError(t, OffloadOptions{OffloadAfter: time.Hour, MaxRestoredVolumes: 1}.Validate())
+	require.Equal(t, errOffloadAfterInvalid,
+		OffloadOptions{MaxRestoredVolumes: 1}.Validate())
+	require.Equal(t, errMaxRestoredVolumesInvalid,
+		OffloadOptions{OffloadAfter: time.Hour}.Validate())
+}
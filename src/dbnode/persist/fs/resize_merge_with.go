@@ -0,0 +1,172 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+type resizeSeries struct {
+	metadata doc.Metadata
+	segments []ts.Segment
+	read     bool
+}
+
+// resizeMergeWith holds the data of filesets written at a different block
+// size in memory so that they can be merged into a single block. Data outside
+// of the block is reported as deleted so that the merger drops it and always
+// re-encodes series for the new block. It also holds the data of late data
+// sidecar volumes, in which case nothing is reported as deleted.
+type resizeMergeWith struct {
+	blockSize time.Duration
+	outside   []xtime.Range
+	series    map[string]*resizeSeries
+	ids       []string
+}
+
+func newResizeMergeWith(
+	reader DataFileSetReader,
+	fileIDs []FileSetFileIdentifier,
+	blockStart xtime.UnixNano,
+	blockSize time.Duration,
+) (*resizeMergeWith, error) {
+	m := &resizeMergeWith{
+		blockSize: blockSize,
+		outside: []xtime.Range{
+			{Start: 0, End: blockStart},
+			{Start: blockStart.Add(blockSize), End: xtime.UnixNano(math.MaxInt64)},
+		},
+		series: make(map[string]*resizeSeries),
+	}
+	for _, fileID := range fileIDs {
+		if err := m.load(reader, fileID); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *resizeMergeWith) load(reader DataFileSetReader, fileID FileSetFileIdentifier) error {
+	err := reader.Open(DataReaderOpenOptions{
+		Identifier:  fileID,
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close() // nolint
+
+	for id, tagsIter, data, checksum, err := reader.Read(); err != io.EOF; id, tagsIter, data, checksum, err = reader.Read() {
+		if err != nil {
+			return err
+		}
+
+		series, ok := m.series[id.String()]
+		if !ok {
+			metadata, err := convert.FromSeriesIDAndTagIter(id, tagsIter)
+			if err != nil {
+				return err
+			}
+			series = &resizeSeries{metadata: metadata}
+			m.series[id.String()] = series
+			m.ids = append(m.ids, id.String())
+		}
+		id.Finalize()
+		tagsIter.Close()
+
+		// NB: the segments are read multiple times if a series is persisted
+		// to several blocks so they must not be finalized by the readers.
+		series.segments = append(series.segments,
+			ts.NewSegment(data, nil, checksum, ts.FinalizeNone))
+	}
+	return nil
+}
+
+func (m *resizeMergeWith) blockReaders(
+	series *resizeSeries,
+	blockStart xtime.UnixNano,
+) []xio.BlockReader {
+	readers := make([]xio.BlockReader, 0, len(series.segments))
+	for _, segment := range series.segments {
+		readers = append(readers, xio.BlockReader{
+			SegmentReader: xio.NewSegmentReader(segment),
+			Start:         blockStart,
+			BlockSize:     m.blockSize,
+		})
+	}
+	return readers
+}
+
+func (m *resizeMergeWith) Read(
+	_ context.Context,
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+	_ namespace.Context,
+) ([]xio.BlockReader, bool, error) {
+	series, ok := m.series[seriesID.String()]
+	if !ok {
+		return nil, false, nil
+	}
+	series.read = true
+	return m.blockReaders(series, blockStart), true, nil
+}
+
+func (m *resizeMergeWith) ForEachRemaining(
+	_ context.Context,
+	blockStart xtime.UnixNano,
+	fn ForEachRemainingFn,
+	_ namespace.Context,
+) error {
+	for _, id := range m.ids {
+		series := m.series[id]
+		if series.read {
+			continue
+		}
+		err := fn(series.metadata, block.FetchBlockResult{
+			Start:  blockStart,
+			Blocks: m.blockReaders(series, blockStart),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *resizeMergeWith) DeletedRanges(
+	_ ident.ID,
+	_ xtime.UnixNano,
+) []xtime.Range {
+	return m.outside
+}
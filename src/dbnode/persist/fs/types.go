@@ -609,6 +609,37 @@ type BlockRetrieverOptions interface {
 // series of the merge target that did not intersect with the fileset.
 type ForEachRemainingFn func(seriesMetadata doc.Metadata, data block.FetchBlockResult) error
 
+// LateFileSetSeekers reads the data of series from the late data sidecar
+// volumes of a shard, which hold late writes to blocks that have already been
+// flushed until they are merged into the filesets of their blocks.
+type LateFileSetSeekers interface {
+	// Open opens a seeker for a complete sidecar volume of a block.
+	Open(blockStart xtime.UnixNano, volumeIndex int) error
+
+	// Volumes returns the indexes of the open sidecar volumes of a block in
+	// ascending order.
+	Volumes(blockStart xtime.UnixNano) []int
+
+	// Stream returns readers of the data of a series in each of the open
+	// sidecar volumes of a block.
+	Stream(
+		ctx context.Context,
+		id ident.ID,
+		blockStart xtime.UnixNano,
+	) ([]xio.BlockReader, error)
+
+	// Remove closes and deletes the sidecar volumes of a block up to and
+	// including the given volume index.
+	Remove(blockStart xtime.UnixNano, volumeIndex int) error
+
+	// RemoveBefore closes and deletes the sidecar volumes of every block
+	// before the given block start.
+	RemoveBefore(blockStart xtime.UnixNano) error
+
+	// Close closes all the open seekers.
+	Close() error
+}
+
 // MergeWith is an interface that the fs merger uses to merge data with.
 type MergeWith interface {
 	// Read returns the data for the given block start and series ID, whether
@@ -628,6 +659,11 @@ type MergeWith interface {
 		fn ForEachRemainingFn,
 		nsCtx namespace.Context,
 	) error
+
+	// DeletedRanges returns the time ranges of a series' data within the
+	// given block start that have been deleted, data within these ranges
+	// is dropped when merging.
+	DeletedRanges(seriesID ident.ID, blockStart xtime.UnixNano) []xtime.Range
 }
 
 // Merger is in charge of merging filesets with some target MergeWith interface.
@@ -655,6 +691,17 @@ type Merger interface {
 		onFlush persist.OnFlushSeries,
 		isBootstrapped bool,
 	) error
+
+	// MergeResized merges the specified fileset files, written at a different
+	// block size, into a fileset at the given block start of the block size
+	// of the merger's namespace options.
+	MergeResized(
+		fileIDs []FileSetFileIdentifier,
+		blockStart xtime.UnixNano,
+		nextVolumeIndex int,
+		flushPreparer persist.FlushPreparer,
+		nsCtx namespace.Context,
+	) (persist.DataCloser, error)
 }
 
 // NewMergerFn is the function to call to get a new Merger.
@@ -708,3 +755,39 @@ type StreamedMetadataEntry struct {
 
 // NewReaderFn creates a new DataFileSetReader.
 type NewReaderFn func(bytesPool pool.CheckedBytesPool, opts Options) (DataFileSetReader, error)
+
+// FileSetOffloader moves the data files of sealed data fileset volumes from
+// local disk to a blob store once they reach an age, and restores them to
+// local disk on demand when they are read.
+type FileSetOffloader interface {
+	// Offload uploads the latest complete volume of each block of a shard
+	// that ended longer than the offload age before now and then removes
+	// its data, index, summaries and bloom filter files from local disk. The
+	// info, digest and checkpoint files are kept so the volume remains known
+	// to the database. Offloaded volumes superseded by a newer volume or
+	// for blocks before the earliest block to retain are removed from the
+	// blob store.
+	Offload(
+		namespace ident.ID,
+		shard uint32,
+		blockSize time.Duration,
+		now xtime.UnixNano,
+		earliestToRetain xtime.UnixNano,
+	) error
+
+	// Restore downloads the offloaded files of the latest volume of a block
+	// back to local disk, it is a no-op for blocks that are not offloaded.
+	Restore(namespace ident.ID, shard uint32, blockStart xtime.UnixNano) error
+}
+
+// OffloadOptions are the options for offloading data fileset volumes.
+type OffloadOptions struct {
+	// OffloadAfter is how long after the end of a block its latest volume
+	// is offloaded.
+	OffloadAfter time.Duration
+
+	// MaxRestoredVolumes is the number of offloaded volumes kept on local
+	// disk after being restored, the least recently read volumes are
+	// removed first.
+	MaxRestoredVolumes int
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/m3db/m3/src/cluster/placementhandler"
 	"github.com/m3db/m3/src/cluster/placementhandler/handleroptions"
 	"github.com/m3db/m3/src/cmd/services/m3dbnode/config"
+	"github.com/m3db/m3/src/dbnode/backup"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
@@ -57,6 +58,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	ttcluster "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/cluster"
 	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/dbnode/persist/blob"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/ratelimit"
@@ -111,6 +113,7 @@ const (
 	maxBgProcessLimitMonitorDuration = 5 * time.Minute
 	cpuProfileDuration               = 5 * time.Second
 	filePathPrefixLockFile           = ".lock"
+	postingsListCacheWarmupFile      = "postings_list_cache_warmup.json"
 	defaultServiceName               = "m3dbnode"
 	skipRaiseProcessLimitsEnvVar     = "SKIP_PROCESS_LIMITS_RAISE"
 	skipRaiseProcessLimitsEnvVarTrue = "true"
@@ -490,6 +493,22 @@ func Run(runOpts RunOptions) {
 		logger.Fatal("could not construct postings list cache", zap.Error(err))
 	}
 
+	var postingsListCacheWarmer *index.PostingsListCacheWarmer
+	if warmupCfg := plCacheConfig.WarmupConfiguration(); warmupCfg.Enabled {
+		postingsListCacheWarmer, err = index.NewPostingsListCacheWarmer(postingsListCache,
+			index.PostingsListCacheWarmupOptions{
+				FilePath: path.Join(cfg.Filesystem.FilePathPrefixOrDefault(),
+					postingsListCacheWarmupFile),
+				Size:              warmupCfg.SizeOrDefault(),
+				MaxDuration:       warmupCfg.MaxDurationOrDefault(),
+				PersistInterval:   warmupCfg.PersistIntervalOrDefault(),
+				InstrumentOptions: plCacheOptions.InstrumentOptions,
+			})
+		if err != nil {
+			logger.Fatal("could not construct postings list cache warmer", zap.Error(err))
+		}
+	}
+
 	// Setup index regexp compilation cache.
 	m3ninxindex.SetRegexpCacheOptions(m3ninxindex.RegexpCacheOptions{
 		Size:  cfg.Cache.RegexpConfiguration().SizeOrDefault(),
@@ -510,6 +529,10 @@ func Run(runOpts RunOptions) {
 	seriesReadPermits.Start()
 	defer seriesReadPermits.Stop()
 	defer postingsListCache.Start()()
+	stopPostingsListCacheWarmer := func() {}
+	if postingsListCacheWarmer != nil {
+		stopPostingsListCacheWarmer = postingsListCacheWarmer.Start()
+	}
 
 	// FOLLOWUP(prateek): remove this once we have the runtime options<->index wiring done
 	indexOpts := opts.IndexOptions()
@@ -638,6 +661,24 @@ func Run(runOpts RunOptions) {
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetBacklogQueueChannelSize(commitLogQueueChannelSize))
 
+	// Setup offloading of data filesets to a blob store
+	var fileSetOffloader fs.FileSetOffloader
+	if tieredCfg := cfg.TieredStorage; tieredCfg != nil {
+		if seriesCachePolicy == series.CacheAll {
+			logger.Fatal("tiered storage requires a series cache policy that retrieves series from disk",
+				zap.Stringer("policy", seriesCachePolicy))
+		}
+		store, err := blob.NewLocalStore(tieredCfg.LocalDirectory)
+		if err != nil {
+			logger.Fatal("could not create tiered storage blob store", zap.Error(err))
+		}
+		fileSetOffloader, err = fs.NewFileSetOffloader(store, fsopts, tieredCfg.OffloadOptions())
+		if err != nil {
+			logger.Fatal("could not create tiered storage offloader", zap.Error(err))
+		}
+		opts = opts.SetFileSetOffloader(fileSetOffloader)
+	}
+
 	// Setup the block retriever
 	switch seriesCachePolicy {
 	case series.CacheAll:
@@ -659,17 +700,24 @@ func Run(runOpts RunOptions) {
 				retrieverOpts = retrieverOpts.SetCacheBlocksOnRetrieve(*v)
 			}
 		}
-		blockRetrieverMgr := block.NewDatabaseBlockRetrieverManager(
-			func(md namespace.Metadata, shardSet sharding.ShardSet) (block.DatabaseBlockRetriever, error) {
-				retriever, err := fs.NewBlockRetriever(retrieverOpts, fsopts)
-				if err != nil {
-					return nil, err
-				}
-				if err := retriever.Open(md, shardSet); err != nil {
-					return nil, err
-				}
-				return retriever, nil
-			})
+		newRetrieverFn := func(
+			md namespace.Metadata,
+			shardSet sharding.ShardSet,
+		) (block.DatabaseBlockRetriever, error) {
+			retriever, err := fs.NewBlockRetriever(retrieverOpts, fsopts)
+			if err != nil {
+				return nil, err
+			}
+			if err := retriever.Open(md, shardSet); err != nil {
+				return nil, err
+			}
+			return retriever, nil
+		}
+		blockRetrieverMgr := block.NewDatabaseBlockRetrieverManager(newRetrieverFn)
+		if fileSetOffloader != nil {
+			blockRetrieverMgr = block.NewTieredDatabaseBlockRetrieverManager(
+				newRetrieverFn, fileSetOffloader)
+		}
 		opts = opts.SetDatabaseBlockRetrieverManager(blockRetrieverMgr)
 	}
 
@@ -734,7 +782,13 @@ func Run(runOpts RunOptions) {
 		runOpts.KVStoreCh <- syncCfg.KVStore
 	}
 
-	opts = opts.SetNamespaceInitializer(syncCfg.NamespaceInitializer)
+	opts = opts.SetNamespaceInitializer(syncCfg.NamespaceInitializer).
+		SetNamespaceResizeStatusReporter(namespace.NewKVResizeStatusReporter(syncCfg.KVStore, hostID))
+	if backupCfg := cfg.Backup; backupCfg != nil {
+		opts = opts.
+			SetBackupCoordinator(backup.NewKVCoordinator(syncCfg.KVStore, hostID)).
+			SetBackupDirectory(backupCfg.Directory)
+	}
 
 	// Set tchannelthrift options.
 	ttopts := tchannelthrift.NewOptions().
@@ -748,6 +802,9 @@ func Run(runOpts RunOptions) {
 		SetMaxOutstandingReadRequests(cfg.Limits.MaxOutstandingReadRequests).
 		SetQueryLimits(queryLimits).
 		SetPermitsOptions(opts.PermitsOptions())
+	if cfg.Exemplars != nil {
+		ttopts = ttopts.SetMaxExemplarsPerNamespace(cfg.Exemplars.MaxExemplarsPerNamespace)
+	}
 
 	// Start servers before constructing the DB so orchestration tools can check health endpoints
 	// before topology is set.
@@ -1035,6 +1092,10 @@ func Run(runOpts RunOptions) {
 		}
 		logger.Info("bootstrapped")
 
+		if postingsListCacheWarmer != nil {
+			go warmupPostingsListCache(db, postingsListCacheWarmer, logger)
+		}
+
 		// Only set the write new series limit after bootstrapping
 		kvWatchNewSeriesLimitPerShard(syncCfg.KVStore, logger, topo,
 			runtimeOptsMgr, cfg.Limits.WriteNewSeriesPerSecond)
@@ -1048,6 +1109,7 @@ func Run(runOpts RunOptions) {
 			// dynamic updates to this limit-based permit still be passing downstream the limit itself.
 			seriesReadPermits.Limit,
 			queryLimits.AggregateDocsLimit(),
+			queryLimits.TenantLimits(),
 			limitOpts,
 		)
 	}()
@@ -1057,6 +1119,10 @@ func Run(runOpts RunOptions) {
 		InterruptCh: runOpts.InterruptCh,
 	})
 
+	// Persist the hottest cached queries before closing the database purges
+	// the postings list cache.
+	stopPostingsListCacheWarmer()
+
 	// Attempt graceful server close.
 	closedCh := make(chan struct{})
 	go func() {
@@ -1077,6 +1143,26 @@ func Run(runOpts RunOptions) {
 	}
 }
 
+func warmupPostingsListCache(
+	db storage.Database,
+	warmer *index.PostingsListCacheWarmer,
+	logger *zap.Logger,
+) {
+	var targets []index.PostingsListCacheWarmupTarget
+	for _, ns := range db.Namespaces() {
+		nsIndex, err := ns.Index()
+		if err != nil {
+			// Namespace is not indexed.
+			continue
+		}
+		targets = append(targets, nsIndex)
+	}
+
+	if err := warmer.Warmup(targets); err != nil {
+		logger.Error("could not warmup postings list cache", zap.Error(err))
+	}
+}
+
 func bgValidateProcessLimits(logger *zap.Logger) {
 	// If unable to validate process limits on the current configuration,
 	// do not run background validator task.
@@ -1229,6 +1315,7 @@ func kvWatchQueryLimit(
 	bytesReadLimit limits.LookbackLimit,
 	diskSeriesReadLimit limits.LookbackLimit,
 	aggregateDocsLimit limits.LookbackLimit,
+	tenantLimits limits.TenantLimits,
 	defaultOpts limits.Options,
 ) {
 	value, err := store.Get(kvconfig.QueryLimits)
@@ -1238,7 +1325,7 @@ func kvWatchQueryLimit(
 		if err == nil {
 			updateQueryLimits(
 				logger, docsLimit, bytesReadLimit, diskSeriesReadLimit,
-				aggregateDocsLimit, dynamicLimits, defaultOpts)
+				aggregateDocsLimit, tenantLimits, dynamicLimits, defaultOpts)
 		}
 	} else if !errors.Is(err, kv.ErrNotFound) {
 		logger.Warn("error resolving query limit", zap.Error(err))
@@ -1260,7 +1347,7 @@ func kvWatchQueryLimit(
 				}
 				updateQueryLimits(
 					logger, docsLimit, bytesReadLimit, diskSeriesReadLimit,
-					aggregateDocsLimit, dynamicLimits, defaultOpts)
+					aggregateDocsLimit, tenantLimits, dynamicLimits, defaultOpts)
 			}
 		}
 	}()
@@ -1272,6 +1359,7 @@ func updateQueryLimits(
 	bytesReadLimit limits.LookbackLimit,
 	diskSeriesReadLimit limits.LookbackLimit,
 	aggregateDocsLimit limits.LookbackLimit,
+	tenantLimits limits.TenantLimits,
 	dynamicOpts *kvpb.QueryLimits,
 	configOpts limits.Options,
 ) {
@@ -1313,6 +1401,15 @@ func updateQueryLimits(
 	if err := updateQueryLimit(aggregateDocsLimit, aggDocsLimitOpts); err != nil {
 		logger.Error("error updating metadata read limit", zap.Error(err))
 	}
+
+	tenantLimitOpts := make(map[string]limits.TenantLimitOptions,
+		len(dynamicOpts.GetTenantLimits()))
+	for tenant, dynamicTenantLimits := range dynamicOpts.GetTenantLimits() {
+		tenantLimitOpts[tenant] = dynamicTenantLimitsToLimitOpts(dynamicTenantLimits)
+	}
+	if err := tenantLimits.Update(tenantLimitOpts); err != nil {
+		logger.Error("error updating tenant limits", zap.Error(err))
+	}
 }
 
 func updateQueryLimit(
@@ -1336,6 +1433,28 @@ func dynamicLimitToLimitOpts(dynamicLimit *kvpb.QueryLimit) limits.LookbackLimit
 	}
 }
 
+func dynamicTenantLimitsToLimitOpts(dynamicLimits *kvpb.TenantLimits) limits.TenantLimitOptions {
+	// Limits unset for a tenant are disabled.
+	opts := limits.TenantLimitOptions{
+		DocsLimitOpts:          limits.DefaultLookbackLimitOptions(),
+		BytesReadLimitOpts:     limits.DefaultLookbackLimitOptions(),
+		SeriesWrittenLimitOpts: limits.DefaultLookbackLimitOptions(),
+	}
+	if dynamicLimits == nil {
+		return opts
+	}
+	if dynamicLimits.MaxRecentlyQueriedSeriesBlocks != nil {
+		opts.DocsLimitOpts = dynamicLimitToLimitOpts(dynamicLimits.MaxRecentlyQueriedSeriesBlocks)
+	}
+	if dynamicLimits.MaxRecentlyQueriedSeriesDiskBytesRead != nil {
+		opts.BytesReadLimitOpts = dynamicLimitToLimitOpts(dynamicLimits.MaxRecentlyQueriedSeriesDiskBytesRead)
+	}
+	if dynamicLimits.MaxRecentlyWrittenSeries != nil {
+		opts.SeriesWrittenLimitOpts = dynamicLimitToLimitOpts(dynamicLimits.MaxRecentlyWrittenSeries)
+	}
+	return opts
+}
+
 func kvWatchClientConsistencyLevels(
 	store kv.Store,
 	logger *zap.Logger,
@@ -0,0 +1,247 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/backup"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+type backupManagerMetrics struct {
+	status    tally.Gauge
+	completed tally.Counter
+	errors    tally.Counter
+}
+
+func newBackupManagerMetrics(scope tally.Scope) backupManagerMetrics {
+	return backupManagerMetrics{
+		status:    scope.Gauge("backup"),
+		completed: scope.Counter("completed"),
+		errors:    scope.Counter("errors"),
+	}
+}
+
+// pausableFileOps are file operations that the backup manager pauses while
+// it cuts a backup so that no filesets are written or cleaned up meanwhile.
+type pausableFileOps interface {
+	Disable() fileOpStatus
+	Enable() fileOpStatus
+	Status() fileOpStatus
+}
+
+// backupManager backs up the filesets of the node when a cluster-wide backup
+// has been requested. It runs on the same thread as warm flushes and
+// snapshots, right after them, and pauses the file operations running on
+// other threads, so that the backup is a consistent cut of the flushed data,
+// the latest snapshot and the commit log position it covers.
+type backupManager struct {
+	sync.RWMutex
+
+	log         *zap.Logger
+	database    database
+	opts        Options
+	coordinator backup.Coordinator
+	pausable    []pausableFileOps
+	sleepFn     sleepFn
+	metrics     backupManagerMetrics
+	status      fileOpStatus
+	enabled     bool
+	reported    string
+}
+
+func newBackupManager(
+	database database,
+	pausable []pausableFileOps,
+	opts Options,
+) databaseBackupManager {
+	var (
+		instrumentOpts = opts.InstrumentOptions()
+		scope          = instrumentOpts.MetricsScope().SubScope("backup")
+	)
+	return &backupManager{
+		log:         instrumentOpts.Logger(),
+		database:    database,
+		opts:        opts,
+		coordinator: opts.BackupCoordinator(),
+		pausable:    pausable,
+		sleepFn:     time.Sleep,
+		metrics:     newBackupManagerMetrics(scope),
+		status:      fileOpNotStarted,
+		enabled:     true,
+	}
+}
+
+func (m *backupManager) Disable() fileOpStatus {
+	m.Lock()
+	status := m.status
+	m.enabled = false
+	m.Unlock()
+	return status
+}
+
+func (m *backupManager) Enable() fileOpStatus {
+	m.Lock()
+	status := m.status
+	m.enabled = true
+	m.Unlock()
+	return status
+}
+
+func (m *backupManager) Status() fileOpStatus {
+	m.RLock()
+	status := m.status
+	m.RUnlock()
+	return status
+}
+
+func (m *backupManager) Run(t xtime.UnixNano) bool {
+	m.Lock()
+	if !m.shouldRunWithLock() {
+		m.Unlock()
+		return false
+	}
+	m.status = fileOpInProgress
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.status = fileOpNotStarted
+		m.Unlock()
+	}()
+
+	if err := m.backup(t); err != nil {
+		m.metrics.errors.Inc(1)
+		m.log.Error("error backing up namespaces",
+			zap.Time("time", t.ToTime()), zap.Error(err))
+	}
+	return true
+}
+
+func (m *backupManager) Report() {
+	if m.Status() == fileOpInProgress {
+		m.metrics.status.Update(1)
+	} else {
+		m.metrics.status.Update(0)
+	}
+}
+
+func (m *backupManager) shouldRunWithLock() bool {
+	return m.enabled && m.coordinator != nil &&
+		m.status != fileOpInProgress && m.database.IsBootstrapped()
+}
+
+func (m *backupManager) backup(t xtime.UnixNano) error {
+	req, ok, err := m.coordinator.PendingRequest()
+	if err != nil || !ok || req.ID == m.reported {
+		return err
+	}
+
+	var (
+		root   = m.opts.BackupDirectory()
+		hostID = m.coordinator.HostID()
+	)
+	exists, err := backup.ManifestExists(root, req.ID, hostID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := m.backupWithFileOpsPaused(t, req); err != nil {
+			return fmt.Errorf("backup %s failed: %v", req.ID, err)
+		}
+		m.metrics.completed.Inc(1)
+		m.log.Info("backup completed", zap.String("id", req.ID),
+			zap.String("dir", backup.HostDirPath(root, req.ID, hostID)))
+	}
+
+	if err := m.coordinator.ReportCompleted(req.ID); err != nil {
+		return fmt.Errorf("failed to report backup %s: %v", req.ID, err)
+	}
+	m.reported = req.ID
+	return nil
+}
+
+func (m *backupManager) backupWithFileOpsPaused(
+	t xtime.UnixNano,
+	req backup.Request,
+) error {
+	for _, p := range m.pausable {
+		status := p.Disable()
+		for status == fileOpInProgress {
+			m.sleepFn(fileOpCheckInterval)
+			status = p.Status()
+		}
+	}
+	defer func() {
+		for _, p := range m.pausable {
+			p.Enable()
+		}
+	}()
+
+	namespaces, err := m.database.OwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	var (
+		fsOpts   = m.opts.CommitLogOptions().FilesystemOptions()
+		hostID   = m.coordinator.HostID()
+		root     = m.opts.BackupDirectory()
+		hostDir  = backup.HostDirPath(root, req.ID, hostID)
+		manifest = backup.Manifest{
+			ID:     req.ID,
+			HostID: hostID,
+		}
+	)
+	// NB: The latest snapshot determines which snapshot filesets are copied
+	// and the commit log position the backup is cut at.
+	manifest.Snapshot, err = backup.CopySnapshotMetadata(fsOpts, hostDir)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range namespaces {
+		if !req.IncludesNamespace(n.ID().String()) {
+			continue
+		}
+		owned := n.OwnedShards()
+		shards := make([]uint32, 0, len(owned))
+		for _, s := range owned {
+			shards = append(shards, s.ID())
+		}
+		nsManifest, err := backup.CopyNamespace(fsOpts, m.opts.FileSetOffloader(),
+			hostDir, n.Metadata(), shards, manifest.Snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to back up namespace %s: %v",
+				n.ID().String(), err)
+		}
+		manifest.Namespaces = append(manifest.Namespaces, nsManifest)
+	}
+
+	manifest.CreatedAt = t.ToTime()
+	return backup.WriteManifest(root, manifest, fsOpts)
+}
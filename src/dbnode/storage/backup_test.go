@@ -0,0 +1,174 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/backup"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testBackupCoordinator struct {
+	req      backup.Request
+	reported []string
+}
+
+func (c *testBackupCoordinator) PendingRequest() (backup.Request, bool, error) {
+	return c.req, c.req.ID != "", nil
+}
+
+func (c *testBackupCoordinator) ReportCompleted(id string) error {
+	c.reported = append(c.reported, id)
+	return nil
+}
+
+func (c *testBackupCoordinator) HostID() string {
+	return "host0"
+}
+
+func TestBackupManagerBacksUpLatestVolumes(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestResizeOptions(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		backupDir   = filepath.Join(dir, "backups")
+		coordinator = &testBackupCoordinator{req: backup.Request{ID: "b1"}}
+		blockSize   = 2 * time.Hour
+		blockStart  = xtime.Now().Truncate(blockSize).Add(-4 * blockSize)
+		dps         = []ts.Datapoint{{TimestampNanos: blockStart, Value: 1}}
+	)
+	opts = opts.
+		SetBackupCoordinator(coordinator).
+		SetBackupDirectory(backupDir)
+	writeTestResizeFileSet(t, opts, blockStart, 0, blockSize, dps)
+	writeTestResizeFileSet(t, opts, blockStart, 1, blockSize, dps)
+
+	md, err := namespace.NewMetadata(ident.StringID("metrics"),
+		newTestResizeNamespaceOptions(blockSize).
+			SetIndexOptions(namespace.NewIndexOptions().SetEnabled(false)))
+	require.NoError(t, err)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0))
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(md.ID()).AnyTimes()
+	ns.EXPECT().Metadata().Return(md)
+	ns.EXPECT().OwnedShards().Return([]databaseShard{shard})
+	other := NewMockdatabaseNamespace(ctrl)
+	other.EXPECT().ID().Return(ident.StringID("other")).AnyTimes()
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	db.EXPECT().OwnedNamespaces().Return([]databaseNamespace{ns, other}, nil)
+	coordinator.req.Namespaces = []string{"metrics"}
+
+	cfm := NewMockdatabaseColdFlushManager(ctrl)
+	cfm.EXPECT().Disable().Return(fileOpInProgress)
+	cfm.EXPECT().Status().Return(fileOpNotStarted)
+	cfm.EXPECT().Enable().Return(fileOpNotStarted)
+
+	m := newBackupManager(db, []pausableFileOps{cfm}, opts).(*backupManager)
+	m.sleepFn = func(time.Duration) {}
+	require.True(t, m.Run(xtime.Now()))
+	require.Equal(t, []string{"b1"}, coordinator.reported)
+
+	manifest, err := backup.ReadManifest(backup.ManifestFilePath(backupDir, "b1", "host0"))
+	require.NoError(t, err)
+	require.Equal(t, "host0", manifest.HostID)
+	require.Nil(t, manifest.Snapshot)
+	require.Len(t, manifest.Namespaces, 1)
+	nsManifest := manifest.Namespaces[0]
+	require.Equal(t, "metrics", nsManifest.ID)
+	require.Equal(t, blockSize, nsManifest.BlockSize)
+	require.Equal(t, []backup.ShardManifest{
+		{
+			ID: 0,
+			DataVolumes: []backup.VolumeManifest{
+				{BlockStart: blockStart, VolumeIndex: 1},
+			},
+		},
+	}, nsManifest.Shards)
+
+	hostDir := backup.HostDirPath(backupDir, "b1", "host0")
+	require.Equal(t, dps, readTestResizeFileSet(t, opts, hostDir, blockStart, 1))
+
+	// Completed backups are neither taken nor reported again.
+	require.True(t, m.Run(xtime.Now()))
+	require.Equal(t, []string{"b1"}, coordinator.reported)
+}
+
+func TestBackupManagerReportsExistingBackup(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestResizeOptions(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		backupDir   = filepath.Join(dir, "backups")
+		coordinator = &testBackupCoordinator{req: backup.Request{ID: "b1"}}
+		fsOpts      = opts.CommitLogOptions().FilesystemOptions()
+	)
+	opts = opts.
+		SetBackupCoordinator(coordinator).
+		SetBackupDirectory(backupDir)
+	require.NoError(t, backup.WriteManifest(backupDir,
+		backup.Manifest{ID: "b1", HostID: "host0"}, fsOpts))
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+
+	m := newBackupManager(db, nil, opts)
+	require.True(t, m.Run(xtime.Now()))
+	require.Equal(t, []string{"b1"}, coordinator.reported)
+}
+
+func TestBackupManagerDisabled(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+
+	m := newBackupManager(db, nil, DefaultTestOptions())
+	require.False(t, m.Run(xtime.Now()))
+
+	coordinator := &testBackupCoordinator{}
+	m = newBackupManager(db, nil, DefaultTestOptions().SetBackupCoordinator(coordinator))
+	m.Disable()
+	require.False(t, m.Run(xtime.Now()))
+	m.Enable()
+	require.True(t, m.Run(xtime.Now()))
+	require.Empty(t, coordinator.reported)
+}
@@ -85,6 +85,74 @@ func (m *blockRetrieverManager) Retriever(
 	return retriever, nil
 }
 
+// NewTieredDatabaseBlockRetrieverManager creates a new manager for
+// constructing and providing existing database block retrievers that
+// restore offloaded blocks to local disk before retrieving them.
+func NewTieredDatabaseBlockRetrieverManager(
+	newDatabaseBlockRetrieverFn NewDatabaseBlockRetrieverFn,
+	restorer BlockRestorer,
+) DatabaseBlockRetrieverManager {
+	return NewDatabaseBlockRetrieverManager(
+		func(md namespace.Metadata, shardSet sharding.ShardSet) (DatabaseBlockRetriever, error) {
+			retriever, err := newDatabaseBlockRetrieverFn(md, shardSet)
+			if err != nil {
+				return nil, err
+			}
+			return NewTieredDatabaseBlockRetriever(md.ID(), retriever, restorer), nil
+		})
+}
+
+type tieredBlockRetriever struct {
+	DatabaseBlockRetriever
+	namespace ident.ID
+	restorer  BlockRestorer
+}
+
+// NewTieredDatabaseBlockRetriever creates a new database block retriever
+// that restores offloaded blocks with a restorer before retrieving them
+// from an existing database block retriever.
+func NewTieredDatabaseBlockRetriever(
+	namespace ident.ID,
+	r DatabaseBlockRetriever,
+	restorer BlockRestorer,
+) DatabaseBlockRetriever {
+	return &tieredBlockRetriever{
+		DatabaseBlockRetriever: r,
+		namespace:              namespace,
+		restorer:               restorer,
+	}
+}
+
+func (r *tieredBlockRetriever) Stream(
+	ctx context.Context,
+	shard uint32,
+	id ident.ID,
+	blockStart xtime.UnixNano,
+	onRetrieve OnRetrieveBlock,
+	nsCtx namespace.Context,
+) (xio.BlockReader, error) {
+	if err := r.restorer.Restore(r.namespace, shard, blockStart); err != nil {
+		return xio.EmptyBlockReader, err
+	}
+	return r.DatabaseBlockRetriever.Stream(ctx, shard, id,
+		blockStart, onRetrieve, nsCtx)
+}
+
+func (r *tieredBlockRetriever) StreamWideEntry(
+	ctx context.Context,
+	shard uint32,
+	id ident.ID,
+	blockStart xtime.UnixNano,
+	filter schema.WideEntryFilter,
+	nsCtx namespace.Context,
+) (StreamedWideEntry, error) {
+	if err := r.restorer.Restore(r.namespace, shard, blockStart); err != nil {
+		return EmptyStreamedWideEntry, err
+	}
+	return r.DatabaseBlockRetriever.StreamWideEntry(ctx, shard, id,
+		blockStart, filter, nsCtx)
+}
+
 type shardBlockRetriever struct {
 	DatabaseBlockRetriever
 	shard uint32
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type testRestore struct {
+	namespace  string
+	shard      uint32
+	blockStart xtime.UnixNano
+}
+
+type testBlockRestorer struct {
+	restores []testRestore
+	err      error
+}
+
+func (r *testBlockRestorer) Restore(
+	namespace ident.ID,
+	shard uint32,
+	blockStart xtime.UnixNano,
+) error {
+	r.restores = append(r.restores, testRestore{
+		namespace:  namespace.String(),
+		shard:      shard,
+		blockStart: blockStart,
+	})
+	return r.err
+}
+
+func TestTieredDatabaseBlockRetrieverRestoresBeforeStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	md, err := namespace.NewMetadata(ident.StringID("ns"), namespace.NewOptions())
+	require.NoError(t, err)
+
+	var (
+		ctx        = context.NewBackground()
+		id         = ident.StringID("foo")
+		blockStart = xtime.Now().Truncate(time.Hour)
+		nsCtx      = namespace.NewContextFrom(md)
+		restorer   = &testBlockRestorer{}
+		inner      = NewMockDatabaseBlockRetriever(ctrl)
+	)
+	defer ctx.Close()
+
+	mgr := NewTieredDatabaseBlockRetrieverManager(
+		func(namespace.Metadata, sharding.ShardSet) (DatabaseBlockRetriever, error) {
+			return inner, nil
+		}, restorer)
+	retriever, err := mgr.Retriever(md, nil)
+	require.NoError(t, err)
+
+	inner.EXPECT().
+		Stream(ctx, uint32(3), id, blockStart, nil, nsCtx).
+		Return(xio.EmptyBlockReader, nil)
+	_, err = retriever.Stream(ctx, 3, id, blockStart, nil, nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, []testRestore{{namespace: "ns", shard: 3, blockStart: blockStart}},
+		restorer.restores)
+
+	// Blocks that fail to restore are not streamed.
+	restorer.err = errors.New("restore failed")
+	_, err = retriever.Stream(ctx, 3, id, blockStart, nil, nsCtx)
+	require.Equal(t, restorer.err, err)
+	_, err = retriever.StreamWideEntry(ctx, 3, id, blockStart, nil, nsCtx)
+	require.Equal(t, restorer.err, err)
+}
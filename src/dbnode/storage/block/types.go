@@ -358,6 +358,14 @@ type DatabaseBlockRetrieverManager interface {
 	) (DatabaseBlockRetriever, error)
 }
 
+// BlockRestorer restores blocks that have been offloaded from local disk so
+// that they can be retrieved.
+type BlockRestorer interface {
+	// Restore restores a block of a shard to local disk if it has been
+	// offloaded, it is a no-op for blocks that have not been offloaded.
+	Restore(namespace ident.ID, shard uint32, blockStart xtime.UnixNano) error
+}
+
 // DatabaseShardBlockRetrieverManager creates and holds shard block
 // retrievers binding shards to an existing retriever.
 type DatabaseShardBlockRetrieverManager interface {
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/pool"
+)
+
+// NewXOROptions returns a copy of the options whose database block, encoder
+// and iterator pools encode and decode datapoints in the format compatible
+// with Prometheus XOR chunks, all other pools are shared with the options.
+func NewXOROptions(opts Options, poolOpts pool.ObjectPoolOptions) Options {
+	var (
+		encoderPool             = encoding.NewEncoderPool(poolOpts)
+		readerIteratorPool      = encoding.NewReaderIteratorPool(poolOpts)
+		multiReaderIteratorPool = encoding.NewMultiReaderIteratorPool(poolOpts)
+		databaseBlockPool       = NewDatabaseBlockPool(poolOpts)
+	)
+
+	encodingOpts := encoding.NewOptions().
+		SetBytesPool(opts.BytesPool()).
+		SetEncoderPool(encoderPool).
+		SetReaderIteratorPool(readerIteratorPool).
+		SetSegmentReaderPool(opts.SegmentReaderPool()).
+		SetMetrics(encoding.NewMetrics(poolOpts.InstrumentOptions().MetricsScope()))
+
+	encoderPool.Init(func() encoding.Encoder {
+		return xor.NewEncoder(timeZero, nil, encodingOpts)
+	})
+	readerIteratorPool.Init(xor.DefaultReaderIteratorAllocFn(encodingOpts))
+	multiReaderIteratorPool.Init(xor.DefaultReaderIteratorAllocFn(encodingOpts))
+
+	xorOpts := opts.
+		SetEncoderPool(encoderPool).
+		SetReaderIteratorPool(readerIteratorPool).
+		SetMultiReaderIteratorPool(multiReaderIteratorPool).
+		SetDatabaseBlockPool(databaseBlockPool)
+	databaseBlockPool.Init(func() DatabaseBlock {
+		return NewDatabaseBlock(timeZero, 0, ts.Segment{}, xorOpts, namespace.Context{})
+	})
+	return xorOpts
+}
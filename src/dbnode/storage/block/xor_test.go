@@ -0,0 +1,78 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/pool"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestXOROptionsMergeBlocks(t *testing.T) {
+	var (
+		opts  = NewXOROptions(NewOptions(), pool.NewObjectPoolOptions().SetSize(1))
+		start = xtime.Now().Truncate(time.Hour)
+		data  = []ts.Datapoint{
+			{TimestampNanos: start, Value: 0.5},
+			{TimestampNanos: start.Add(time.Second), Value: 1.5},
+		}
+	)
+
+	newBlock := func(dp ts.Datapoint) DatabaseBlock {
+		encoder := opts.EncoderPool().Get()
+		encoder.Reset(start, 0, nil)
+		require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+		b := opts.DatabaseBlockPool().Get()
+		b.Reset(start, time.Hour, encoder.Discard(), namespace.Context{})
+		return b
+	}
+
+	// Lazily merge the two blocks, which is only possible if they are
+	// decoded and encoded with the xor encoding.
+	block1, block2 := newBlock(data[1]), newBlock(data[0])
+	require.NoError(t, block1.Merge(block2))
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	stream, err := block1.Stream(ctx)
+	require.NoError(t, err)
+	seg, err := stream.Segment()
+	require.NoError(t, err)
+
+	iter := xor.NewReaderIterator(xio.NewSegmentReader(seg), encoding.NewOptions())
+	var i int
+	for ; iter.Next(); i++ {
+		dp, _, _ := iter.Current()
+		require.True(t, data[i].Equal(dp))
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, len(data), i)
+}
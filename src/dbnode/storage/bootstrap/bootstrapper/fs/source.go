@@ -1026,8 +1026,9 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 		segmentsFulfilled := willFulfill
 		// NB(bodu): All segments read from disk are already persisted.
 		persistedSegments := make([]result.Segment, 0, len(readResult.Segments))
-		for _, segment := range readResult.Segments {
-			persistedSegments = append(persistedSegments, result.NewSegment(segment, true))
+		for i, segment := range readResult.Segments {
+			persistedSegments = append(persistedSegments,
+				result.NewPersistedSegment(segment, infoFile.ID.VolumeIndex, i))
 		}
 		volumeType := idxpersist.DefaultIndexVolumeType
 		if info.IndexVolumeType != nil {
@@ -75,6 +75,7 @@ type options struct {
 	shardPersistenceFlushConcurrency int
 	indexSegmentConcurrency          int
 	persistenceMaxQueueSize          int
+	streamingPersistEnabled          bool
 	persistManager                   persist.Manager
 	indexClaimsManager               fs.IndexClaimsManager
 	runtimeOptionsManager            m3dbruntime.OptionsManager
@@ -207,6 +208,16 @@ func (o *options) PersistenceMaxQueueSize() int {
 	return o.persistenceMaxQueueSize
 }
 
+func (o *options) SetStreamingPersistEnabled(value bool) Options {
+	opts := *o
+	opts.streamingPersistEnabled = value
+	return &opts
+}
+
+func (o *options) StreamingPersistEnabled() bool {
+	return o.streamingPersistEnabled
+}
+
 func (o *options) SetPersistManager(value persist.Manager) Options {
 	opts := *o
 	opts.persistManager = value
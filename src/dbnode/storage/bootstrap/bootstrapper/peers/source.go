@@ -51,6 +51,7 @@ import (
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
+	"github.com/m3db/m3/src/x/pool"
 	xresource "github.com/m3db/m3/src/x/resource"
 	xsync "github.com/m3db/m3/src/x/sync"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -61,10 +62,11 @@ var errNamespaceNotFound = errors.New("namespace not found")
 const readSeriesBlocksWorkerChannelSize = 512
 
 type peersSource struct {
-	opts              Options
-	newPersistManager func() (persist.Manager, error)
-	log               *zap.Logger
-	instrumentation   *instrumentation
+	opts               Options
+	newPersistManager  func() (persist.Manager, error)
+	newStreamingWriter func() (fs.StreamingWriter, error)
+	log                *zap.Logger
+	instrumentation    *instrumentation
 }
 
 type persistenceFlush struct {
@@ -85,6 +87,9 @@ func newPeersSource(opts Options) (bootstrap.Source, error) {
 		newPersistManager: func() (persist.Manager, error) {
 			return fs.NewPersistManager(opts.FilesystemOptions())
 		},
+		newStreamingWriter: func() (fs.StreamingWriter, error) {
+			return fs.NewStreamingWriter(opts.FilesystemOptions())
+		},
 		log:             instrumentation.log,
 		instrumentation: instrumentation,
 	}, nil
@@ -265,10 +270,21 @@ func (s *peersSource) readData(
 	if shouldPersist {
 		concurrency = s.opts.ShardPersistenceConcurrency()
 	}
+	if nsMetadata.Options().EncodingScheme() == namespace.XOREncodingScheme {
+		// NB: blocks fetched from peers may need to be merged and must be
+		// decoded and encoded with the encoding scheme of the namespace.
+		resultOpts = resultOpts.SetDatabaseBlockOptions(block.NewXOROptions(
+			resultOpts.DatabaseBlockOptions(),
+			pool.NewObjectPoolOptions().SetInstrumentOptions(resultOpts.InstrumentOptions())))
+	}
+
+	// When streaming, blocks are written straight to disk by the workers
+	// fetching them rather than queued up for the persistence workers.
+	streaming := shouldPersist && s.opts.StreamingPersistEnabled()
 
 	instrCtx := s.instrumentation.bootstrapShardsStarted(count, concurrency, shouldPersist)
 	defer instrCtx.bootstrapShardsCompleted()
-	if shouldPersist {
+	if shouldPersist && !streaming {
 		// Spin up persist workers.
 		for i := 0; i < s.opts.ShardPersistenceFlushConcurrency(); i++ {
 			closer, err := s.startPersistenceQueueWorkerLoop(opts,
@@ -291,6 +307,11 @@ func (s *peersSource) readData(
 		wg.Add(1)
 		workers.Go(func() {
 			defer wg.Done()
+			if streaming {
+				s.streamBootstrapBlocksFromPeers(shard, ranges, nsMetadata, session,
+					resultOpts, result, &resultLock, blockSize)
+				return
+			}
 			s.fetchBootstrapBlocksFromPeers(shard, ranges, nsMetadata, session,
 				accumulator, resultOpts, result, &resultLock, shouldPersist,
 				persistenceQueue, blockSize)
@@ -158,6 +158,9 @@ type instrumentation struct {
 	log                                *zap.Logger
 	nowFn                              clock.NowFn
 	persistedIndexBlocksOutOfRetention tally.Counter
+	streamedBlocks                     tally.Counter
+	resumedBlocks                      tally.Counter
+	blockChecksumMismatches            tally.Counter
 }
 
 func newInstrumentation(opts Options) *instrumentation {
@@ -174,6 +177,9 @@ func newInstrumentation(opts Options) *instrumentation {
 		log:                                instrumentOptions.Logger().With(zap.String("bootstrapper", "peers")),
 		nowFn:                              opts.ResultOptions().ClockOptions().NowFn(),
 		persistedIndexBlocksOutOfRetention: scope.Counter("persist-index-blocks-out-of-retention"),
+		streamedBlocks:                     scope.Counter("streamed-blocks"),
+		resumedBlocks:                      scope.Counter("resumed-blocks"),
+		blockChecksumMismatches:            scope.Counter("block-checksum-mismatches"),
 	}
 }
 
@@ -210,3 +216,18 @@ func (i *instrumentation) outOfRetentionIndexSegmentSkipped(fields []zapcore.Fie
 	i.log.Debug("skipping out of retention index segment", fields...)
 	i.persistedIndexBlocksOutOfRetention.Inc(1)
 }
+
+func (i *instrumentation) blockStreamed(fields []zapcore.Field) {
+	i.log.Debug("streamed block from peers to disk", fields...)
+	i.streamedBlocks.Inc(1)
+}
+
+func (i *instrumentation) blockResumed(fields []zapcore.Field) {
+	i.log.Info("skipping block already streamed from peers to disk", fields...)
+	i.resumedBlocks.Inc(1)
+}
+
+func (i *instrumentation) blockChecksumMismatch(fields []zapcore.Field) {
+	i.log.Error("block fetched from peers does not match peer checksums", fields...)
+	i.blockChecksumMismatches.Inc(1)
+}
@@ -0,0 +1,426 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package peers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/serialize"
+	xos "github.com/m3db/m3/src/x/os"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const peersProgressTmpSuffix = ".tmp"
+
+var errBlockChecksumMismatch = errors.New("block checksum mismatch")
+
+// shardBootstrapProgress tracks the blocks of a shard that have been streamed
+// from peers to disk and validated, so that a restarted bootstrap can resume
+// where it left off rather than fetching every block again.
+type shardBootstrapProgress struct {
+	BlockSize int64   `json:"blockSize"`
+	Completed []int64 `json:"completed"`
+}
+
+func (p *shardBootstrapProgress) completed(blockStart xtime.UnixNano) bool {
+	for _, completed := range p.Completed {
+		if completed == int64(blockStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamBootstrapBlocksFromPeers loops through all the provided ranges for a
+// given shard and streams each block fetched from peers straight to a data
+// fileset on disk. Blocks are only marked as complete in the progress of the
+// shard once their checksums have been validated against the metadata of the
+// peers and their fileset has been written, blocks already marked as complete
+// by a previous run are skipped. The progress is removed once every block of
+// the shard has been streamed.
+func (s *peersSource) streamBootstrapBlocksFromPeers(
+	shard uint32,
+	ranges xtime.Ranges,
+	nsMetadata namespace.Metadata,
+	session client.AdminSession,
+	bopts result.Options,
+	bootstrapResult result.DataBootstrapResult,
+	lock *sync.Mutex,
+	blockSize time.Duration,
+) {
+	var (
+		fsOpts         = s.opts.FilesystemOptions()
+		filePathPrefix = fsOpts.FilePathPrefix()
+		progressPath   = fs.ShardPeersBootstrapProgressFilePath(filePathPrefix,
+			nsMetadata.ID(), shard)
+		anyUnfulfilled bool
+	)
+	unfulfill := func(r xtime.Range) {
+		anyUnfulfilled = true
+		lock.Lock()
+		unfulfilled := bootstrapResult.Unfulfilled()
+		unfulfilled.AddRanges(result.NewShardTimeRanges().Set(shard, xtime.NewRanges(r)))
+		lock.Unlock()
+	}
+
+	progress, err := readShardBootstrapProgress(progressPath)
+	if err != nil {
+		s.log.Warn("could not read peers bootstrap progress, starting over",
+			zap.Stringer("namespace", nsMetadata.ID()),
+			zap.Uint32("shard", shard),
+			zap.Error(err))
+		progress = shardBootstrapProgress{}
+	}
+	if progress.BlockSize != int64(blockSize) {
+		progress = shardBootstrapProgress{BlockSize: int64(blockSize)}
+	}
+
+	it := ranges.Iter()
+	for it.Next() {
+		currRange := it.Value()
+
+		for blockStart := currRange.Start; blockStart.Before(currRange.End); blockStart = blockStart.Add(blockSize) {
+			blockRange := xtime.Range{Start: blockStart, End: blockStart.Add(blockSize)}
+			fields := []zapcore.Field{
+				zap.Stringer("namespace", nsMetadata.ID()),
+				zap.Uint32("shard", shard),
+				zap.Time("blockStart", blockStart.ToTime()),
+			}
+
+			if progress.completed(blockStart) {
+				exists, err := fs.DataFileSetExists(filePathPrefix, nsMetadata.ID(),
+					shard, blockStart, 0)
+				if err == nil && exists {
+					s.instrumentation.blockResumed(fields)
+					continue
+				}
+			}
+
+			if err := s.streamBlockFromPeers(nsMetadata, shard, blockRange, session, bopts); err != nil {
+				if errors.Is(err, errBlockChecksumMismatch) {
+					s.instrumentation.blockChecksumMismatch(append(fields, zap.Error(err)))
+				} else {
+					s.log.Error("error streaming block from peers",
+						append(fields, zap.Error(err))...)
+				}
+				unfulfill(blockRange)
+				continue
+			}
+			s.instrumentation.blockStreamed(fields)
+
+			// Failing to record progress only means the block will be fetched
+			// again if the bootstrap is restarted.
+			progress.Completed = append(progress.Completed, int64(blockStart))
+			if err := writeShardBootstrapProgress(progressPath, progress, fsOpts); err != nil {
+				s.log.Warn("could not write peers bootstrap progress",
+					append(fields, zap.Error(err))...)
+			}
+		}
+	}
+
+	if anyUnfulfilled {
+		return
+	}
+	if err := os.Remove(progressPath); err != nil && !os.IsNotExist(err) {
+		s.log.Warn("could not remove peers bootstrap progress",
+			zap.Stringer("namespace", nsMetadata.ID()),
+			zap.Uint32("shard", shard),
+			zap.Error(err))
+	}
+}
+
+// streamBlockFromPeers fetches a block of a shard from peers, validates the
+// checksum of each series against the checksums reported by peers and writes
+// the series out to a data fileset in lexicographic order of their IDs.
+func (s *peersSource) streamBlockFromPeers(
+	nsMetadata namespace.Metadata,
+	shard uint32,
+	blockRange xtime.Range,
+	session client.AdminSession,
+	bopts result.Options,
+) error {
+	checksums, err := fetchBlockChecksumsFromPeers(nsMetadata.ID(), shard,
+		blockRange, session, bopts)
+	if err != nil {
+		return fmt.Errorf("could not fetch block metadata from peers: %w", err)
+	}
+
+	shardResult, err := session.FetchBootstrapBlocksFromPeers(nsMetadata, shard,
+		blockRange.Start, blockRange.End, bopts)
+	s.logFetchBootstrapBlocksFromPeersOutcome(shard, shardResult, err)
+	if err != nil {
+		return err
+	}
+	defer shardResult.Close()
+
+	entries := make([]streamedSeries, 0, shardResult.NumSeries())
+	for _, elem := range shardResult.AllSeries().Iter() {
+		series := elem.Value()
+		bl, ok := series.Blocks.BlockAt(blockRange.Start)
+		if !ok {
+			continue
+		}
+		checksum, err := bl.Checksum()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, streamedSeries{
+			id:       series.ID,
+			tags:     series.Tags,
+			blocks:   series.Blocks,
+			block:    bl,
+			checksum: checksum,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].id.Bytes(), entries[j].id.Bytes()) < 0
+	})
+
+	if err := checksums.validate(entries); err != nil {
+		return err
+	}
+
+	return s.writeStreamedBlock(nsMetadata, shard, blockRange.Start, entries)
+}
+
+// writeStreamedBlock writes out the series of a block to a data fileset, the
+// fileset only becomes visible once all of them have been written.
+func (s *peersSource) writeStreamedBlock(
+	nsMetadata namespace.Metadata,
+	shard uint32,
+	blockStart xtime.UnixNano,
+	entries []streamedSeries,
+) error {
+	var (
+		fsOpts         = s.opts.FilesystemOptions()
+		filePathPrefix = fsOpts.FilePathPrefix()
+		nsID           = nsMetadata.ID()
+	)
+
+	// Replace any fileset left over from a shard previously owned or from an
+	// interrupted bootstrap, see persistenceFlush for the cases this covers.
+	exists, err := fs.DataFileSetExists(filePathPrefix, nsID, shard, blockStart, 0)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := fs.DeleteFileSetAt(filePathPrefix, nsID, shard, blockStart, 0); err != nil {
+			return err
+		}
+	}
+
+	writer, err := s.newStreamingWriter()
+	if err != nil {
+		return err
+	}
+
+	plannedRecordsCount := uint(len(entries))
+	if plannedRecordsCount == 0 {
+		plannedRecordsCount = 1
+	}
+	if err := writer.Open(fs.StreamingWriterOpenOptions{
+		NamespaceID:         nsID,
+		ShardID:             shard,
+		BlockStart:          blockStart,
+		BlockSize:           nsMetadata.Options().RetentionOptions().BlockSize(),
+		VolumeIndex:         0,
+		PlannedRecordsCount: plannedRecordsCount,
+	}); err != nil {
+		return err
+	}
+
+	tagEncoderPool := fsOpts.TagEncoderPool()
+	for _, entry := range entries {
+		if err := writeStreamedSeries(writer, tagEncoderPool.Get(), entry); err != nil {
+			// Abort without writing a checkpoint file so the incomplete
+			// fileset is never read.
+			_ = writer.Abort()
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+type streamedSeries struct {
+	id       ident.ID
+	tags     ident.Tags
+	blocks   block.DatabaseSeriesBlocks
+	block    block.DatabaseBlock
+	checksum uint32
+}
+
+func writeStreamedSeries(
+	writer fs.StreamingWriter,
+	tagEncoder serialize.TagEncoder,
+	entry streamedSeries,
+) error {
+	defer tagEncoder.Finalize()
+
+	if err := tagEncoder.Encode(ident.NewTagsIterator(entry.tags)); err != nil {
+		return err
+	}
+	encodedTags, ok := tagEncoder.Data()
+	if !ok {
+		return errors.New("could not encode tags")
+	}
+
+	// Discard the block and remove it from the series so that closing the
+	// shard result does not close it again.
+	blockStart := entry.block.StartTime()
+	segment := entry.block.Discard()
+	entry.blocks.RemoveBlockAt(blockStart)
+	defer segment.Finalize()
+
+	data := make([][]byte, 0, 2)
+	if segment.Head != nil {
+		data = append(data, segment.Head.Bytes())
+	}
+	if segment.Tail != nil {
+		data = append(data, segment.Tail.Bytes())
+	}
+
+	return writer.WriteAll(ident.BytesID(entry.id.Bytes()), encodedTags.Bytes(),
+		data, entry.checksum)
+}
+
+// peerBlockChecksums are the checksums that peers reported for each series
+// of a block, keyed by series ID.
+type peerBlockChecksums map[string][]uint32
+
+func fetchBlockChecksumsFromPeers(
+	nsID ident.ID,
+	shard uint32,
+	blockRange xtime.Range,
+	session client.AdminSession,
+	bopts result.Options,
+) (peerBlockChecksums, error) {
+	iter, err := session.FetchBootstrapBlocksMetadataFromPeers(nsID, shard,
+		blockRange.Start, blockRange.End, bopts)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := make(peerBlockChecksums)
+	for iter.Next() {
+		_, metadata := iter.Current()
+		if metadata.Start != blockRange.Start {
+			continue
+		}
+		id := metadata.ID.String()
+		peerChecksums := checksums[id]
+		if metadata.Checksum != nil && !containsChecksum(peerChecksums, *metadata.Checksum) {
+			peerChecksums = append(peerChecksums, *metadata.Checksum)
+		}
+		checksums[id] = peerChecksums
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+// validate checks that the series fetched for a block are exactly the series
+// peers reported metadata for, and that the checksum of each series matches
+// the checksum peers agree on. Series that peers disagree on are merged when
+// fetched so cannot be matched against a single checksum.
+func (c peerBlockChecksums) validate(entries []streamedSeries) error {
+	if len(entries) != len(c) {
+		return fmt.Errorf("%w: fetched %d series, peers reported %d",
+			errBlockChecksumMismatch, len(entries), len(c))
+	}
+	for _, entry := range entries {
+		peerChecksums, ok := c[entry.id.String()]
+		if !ok {
+			return fmt.Errorf("%w: series %s not reported by peers",
+				errBlockChecksumMismatch, entry.id.String())
+		}
+		if len(peerChecksums) == 1 && peerChecksums[0] != entry.checksum {
+			return fmt.Errorf("%w: series %s checksum %d, peers reported %d",
+				errBlockChecksumMismatch, entry.id.String(), entry.checksum,
+				peerChecksums[0])
+		}
+	}
+	return nil
+}
+
+func containsChecksum(checksums []uint32, checksum uint32) bool {
+	for _, c := range checksums {
+		if c == checksum {
+			return true
+		}
+	}
+	return false
+}
+
+func readShardBootstrapProgress(filePath string) (shardBootstrapProgress, error) {
+	var progress shardBootstrapProgress
+	data, err := ioutil.ReadFile(filePath) //nolint:gosec
+	if os.IsNotExist(err) {
+		return progress, nil
+	}
+	if err != nil {
+		return progress, err
+	}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return progress, err
+	}
+	return progress, nil
+}
+
+// writeShardBootstrapProgress atomically replaces the progress file by writing
+// to a temporary file and renaming it once synced.
+func writeShardBootstrapProgress(
+	filePath string,
+	progress shardBootstrapProgress,
+	fsOpts fs.Options,
+) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	tmpPath := filePath + peersProgressTmpSuffix
+	if err := xos.WriteFileSync(tmpPath, data, fsOpts.NewFileMode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
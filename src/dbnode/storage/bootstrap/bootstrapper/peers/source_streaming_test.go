@@ -0,0 +1,318 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package peers
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type testPeerBlockMetadataIter struct {
+	metadata []block.Metadata
+	idx      int
+}
+
+func (i *testPeerBlockMetadataIter) Next() bool {
+	i.idx++
+	return i.idx <= len(i.metadata)
+}
+
+func (i *testPeerBlockMetadataIter) Current() (topology.Host, block.Metadata) {
+	return nil, i.metadata[i.idx-1]
+}
+
+func (i *testPeerBlockMetadataIter) Err() error {
+	return nil
+}
+
+type testStreamedBlock struct {
+	id       string
+	data     []byte
+	checksum uint32
+}
+
+func newTestStreamingOpts(t *testing.T, ctrl *gomock.Controller) (Options, string) {
+	dir, err := ioutil.TempDir("", "peers-streaming")
+	require.NoError(t, err)
+
+	opts := newTestDefaultOpts(t, ctrl)
+	opts = opts.
+		SetResultOptions(testDefaultResultOpts.SetSeriesCachePolicy(series.CacheLRU)).
+		SetFilesystemOptions(opts.FilesystemOptions().SetFilePathPrefix(dir)).
+		SetStreamingPersistEnabled(true)
+	return opts, dir
+}
+
+func expectStreamedBlock(
+	session *client.MockAdminSession,
+	opts Options,
+	md namespace.Metadata,
+	blockStart xtime.UnixNano,
+	reportedChecksum uint32,
+	b testStreamedBlock,
+) {
+	blockSize := md.Options().RetentionOptions().BlockSize()
+	session.EXPECT().
+		FetchBootstrapBlocksMetadataFromPeers(md.ID(), uint32(0), blockStart,
+			blockStart.Add(blockSize), gomock.Any()).
+		Return(&testPeerBlockMetadataIter{metadata: []block.Metadata{
+			block.NewMetadata(ident.StringID(b.id), ident.Tags{}, blockStart,
+				int64(len(b.data)), &reportedChecksum, 0),
+		}}, nil)
+
+	shardResult := result.NewShardResult(opts.ResultOptions())
+	shardResult.AddBlock(ident.StringID(b.id),
+		ident.NewTags(ident.StringTag("name", b.id)),
+		block.NewDatabaseBlock(blockStart, blockSize,
+			ts.NewSegment(checked.NewBytes(b.data, nil), nil, b.checksum, ts.FinalizeNone),
+			testBlockOpts, namespace.Context{}))
+	session.EXPECT().
+		FetchBootstrapBlocksFromPeers(namespace.NewMetadataMatcher(md), uint32(0),
+			blockStart, blockStart.Add(blockSize), gomock.Any()).
+		Return(shardResult, nil)
+}
+
+func readTestStreamedBlock(
+	t *testing.T,
+	opts Options,
+	md namespace.Metadata,
+	blockStart xtime.UnixNano,
+) []testStreamedBlock {
+	bytesPool := opts.ResultOptions().DatabaseBlockOptions().BytesPool()
+	reader, err := fs.NewReader(bytesPool, opts.FilesystemOptions())
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  md.ID(),
+			Shard:      0,
+			BlockStart: blockStart,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+
+	var blocks []testStreamedBlock
+	for {
+		id, tagsIter, data, checksum, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.True(t, tagsIter.Next())
+		require.Equal(t, id.String(), tagsIter.Current().Value.String())
+		tagsIter.Close()
+
+		data.IncRef()
+		blocks = append(blocks, testStreamedBlock{
+			id:       id.String(),
+			data:     append([]byte(nil), data.Bytes()...),
+			checksum: checksum,
+		})
+		data.DecRef()
+		id.Finalize()
+	}
+	return blocks
+}
+
+func TestPeersSourceStreamingResumesAfterFailure(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestStreamingOpts(t, ctrl)
+	defer os.RemoveAll(dir)
+
+	var (
+		testNsMd     = testNamespaceMetadataNoIndex(t)
+		ropts        = testNsMd.Options().RetentionOptions()
+		blockSize    = ropts.BlockSize()
+		start        = xtime.Now().Add(-ropts.RetentionPeriod()).Truncate(blockSize)
+		end          = start.Add(2 * blockSize)
+		progressPath = fs.ShardPeersBootstrapProgressFilePath(dir, testNsMd.ID(), 0)
+		foo          = testStreamedBlock{id: "foo", data: []byte{1, 2, 3}, checksum: 1}
+		bar          = testStreamedBlock{id: "bar", data: []byte{4, 5, 6}, checksum: 2}
+		target       = result.NewShardTimeRanges().Set(0,
+			xtime.NewRanges(xtime.Range{Start: start, End: end}))
+	)
+
+	// The first bootstrap streams the first block and fails to fetch the
+	// second one.
+	session := client.NewMockAdminSession(ctrl)
+	expectStreamedBlock(session, opts, testNsMd, start, foo.checksum, foo)
+	session.EXPECT().
+		FetchBootstrapBlocksMetadataFromPeers(testNsMd.ID(), uint32(0),
+			start.Add(blockSize), end, gomock.Any()).
+		Return(nil, errors.New("an error"))
+	adminClient := client.NewMockAdminClient(ctrl)
+	adminClient.EXPECT().DefaultAdminSession().Return(session, nil).AnyTimes()
+	opts = opts.SetAdminClient(adminClient)
+
+	src, err := newPeersSource(opts)
+	require.NoError(t, err)
+
+	tester := bootstrap.BuildNamespacesTester(t, testRunOptsWithPersist, target, testNsMd)
+	tester.TestReadWith(src)
+	tester.TestUnfulfilledForNamespace(testNsMd, result.NewShardTimeRanges().Set(0,
+		xtime.NewRanges(xtime.Range{Start: start.Add(blockSize), End: end})), nil)
+	tester.EnsureNoLoadedBlocks()
+	tester.EnsureNoWrites()
+	tester.Finish()
+
+	require.Equal(t, []testStreamedBlock{foo}, readTestStreamedBlock(t, opts, testNsMd, start))
+	progress, err := readShardBootstrapProgress(progressPath)
+	require.NoError(t, err)
+	require.Equal(t, shardBootstrapProgress{
+		BlockSize: int64(blockSize),
+		Completed: []int64{int64(start)},
+	}, progress)
+
+	// The restarted bootstrap only fetches the second block and removes the
+	// progress once done.
+	session = client.NewMockAdminSession(ctrl)
+	expectStreamedBlock(session, opts, testNsMd, start.Add(blockSize), bar.checksum, bar)
+	adminClient = client.NewMockAdminClient(ctrl)
+	adminClient.EXPECT().DefaultAdminSession().Return(session, nil).AnyTimes()
+	opts = opts.SetAdminClient(adminClient)
+
+	src, err = newPeersSource(opts)
+	require.NoError(t, err)
+
+	tester = bootstrap.BuildNamespacesTester(t, testRunOptsWithPersist, target, testNsMd)
+	defer tester.Finish()
+	tester.TestReadWith(src)
+	tester.TestUnfulfilledForNamespaceIsEmpty(testNsMd)
+	tester.EnsureNoLoadedBlocks()
+	tester.EnsureNoWrites()
+
+	require.Equal(t, []testStreamedBlock{foo}, readTestStreamedBlock(t, opts, testNsMd, start))
+	require.Equal(t, []testStreamedBlock{bar},
+		readTestStreamedBlock(t, opts, testNsMd, start.Add(blockSize)))
+	_, err = os.Stat(progressPath)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestPeersSourceStreamingChecksumMismatch(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestStreamingOpts(t, ctrl)
+	defer os.RemoveAll(dir)
+
+	var (
+		testNsMd  = testNamespaceMetadataNoIndex(t)
+		ropts     = testNsMd.Options().RetentionOptions()
+		blockSize = ropts.BlockSize()
+		start     = xtime.Now().Add(-ropts.RetentionPeriod()).Truncate(blockSize)
+		end       = start.Add(blockSize)
+		target    = result.NewShardTimeRanges().Set(0,
+			xtime.NewRanges(xtime.Range{Start: start, End: end}))
+	)
+
+	session := client.NewMockAdminSession(ctrl)
+	expectStreamedBlock(session, opts, testNsMd, start, 42,
+		testStreamedBlock{id: "foo", data: []byte{1, 2, 3}, checksum: 1})
+	adminClient := client.NewMockAdminClient(ctrl)
+	adminClient.EXPECT().DefaultAdminSession().Return(session, nil).AnyTimes()
+
+	src, err := newPeersSource(opts.SetAdminClient(adminClient))
+	require.NoError(t, err)
+
+	tester := bootstrap.BuildNamespacesTester(t, testRunOptsWithPersist, target, testNsMd)
+	defer tester.Finish()
+	tester.TestReadWith(src)
+	tester.TestUnfulfilledForNamespace(testNsMd, target, nil)
+
+	exists, err := fs.DataFileSetExists(dir, testNsMd.ID(), 0, start, 0)
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = os.Stat(fs.ShardPeersBootstrapProgressFilePath(dir, testNsMd.ID(), 0))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestPeerBlockChecksumsValidate(t *testing.T) {
+	entries := []streamedSeries{
+		{id: ident.StringID("bar"), checksum: 1},
+		{id: ident.StringID("foo"), checksum: 2},
+	}
+
+	tests := []struct {
+		name      string
+		checksums peerBlockChecksums
+		valid     bool
+	}{
+		{
+			name:      "matching",
+			checksums: peerBlockChecksums{"bar": {1}, "foo": {2}},
+			valid:     true,
+		},
+		{
+			name:      "peers disagree",
+			checksums: peerBlockChecksums{"bar": {1}, "foo": {3, 4}},
+			valid:     true,
+		},
+		{
+			name:      "no checksum reported",
+			checksums: peerBlockChecksums{"bar": {1}, "foo": nil},
+			valid:     true,
+		},
+		{
+			name:      "mismatch",
+			checksums: peerBlockChecksums{"bar": {1}, "foo": {3}},
+		},
+		{
+			name:      "missing series",
+			checksums: peerBlockChecksums{"bar": {1}},
+		},
+		{
+			name:      "unexpected series",
+			checksums: peerBlockChecksums{"bar": {1}, "baz": {2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.checksums.validate(entries)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errors.Is(err, errBlockChecksumMismatch))
+		})
+	}
+}
@@ -96,6 +96,18 @@ type Options interface {
 	// the concurrent shard fetchers.
 	PersistenceMaxQueueSize() int
 
+	// SetStreamingPersistEnabled sets whether blocks fetched from peers when
+	// performing a bootstrap with persistence enabled are streamed to disk
+	// fileset-by-fileset, validated against the checksums reported by peers
+	// and checkpointed so that a restarted bootstrap resumes where it left off.
+	SetStreamingPersistEnabled(value bool) Options
+
+	// StreamingPersistEnabled returns whether blocks fetched from peers when
+	// performing a bootstrap with persistence enabled are streamed to disk
+	// fileset-by-fileset, validated against the checksums reported by peers
+	// and checkpointed so that a restarted bootstrap resumes where it left off.
+	StreamingPersistEnabled() bool
+
 	// SetPersistManager sets the persistence manager used to flush blocks
 	// when performing a bootstrap with persistence.
 	SetPersistManager(value persist.Manager) Options
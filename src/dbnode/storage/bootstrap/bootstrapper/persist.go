@@ -215,8 +215,8 @@ func persistBootstrapIndexSegment(
 		return result.IndexBlock{}, err
 	}
 	segments := make([]result.Segment, 0, len(persistedSegments))
-	for _, pSeg := range persistedSegments {
-		segments = append(segments, result.NewSegment(pSeg, true))
+	for i, pSeg := range persistedSegments {
+		segments = append(segments, result.NewPersistedSegment(pSeg, volumeIndex, i))
 	}
 
 	return result.NewIndexBlock(segments, expectedRanges), nil
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package restore
+
+import (
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/bootstrapper"
+)
+
+const (
+	// RestoreBootstrapperName is the name of the restore bootstrapper.
+	RestoreBootstrapperName = "restore"
+)
+
+type restoreBootstrapperProvider struct {
+	opts Options
+	next bootstrap.BootstrapperProvider
+}
+
+// NewRestoreBootstrapperProvider creates a new restore bootstrapper provider.
+func NewRestoreBootstrapperProvider(
+	opts Options,
+	next bootstrap.BootstrapperProvider,
+) (bootstrap.BootstrapperProvider, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return restoreBootstrapperProvider{
+		opts: opts,
+		next: next,
+	}, nil
+}
+
+func (p restoreBootstrapperProvider) Provide() (bootstrap.Bootstrapper, error) {
+	var (
+		src  = newRestoreSource(p.opts)
+		b    = &restoreBootstrapper{}
+		next bootstrap.Bootstrapper
+		err  error
+	)
+
+	if p.next != nil {
+		next, err = p.next.Provide()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bootstrapper.NewBaseBootstrapper(
+		b.String(), src, p.opts.ResultOptions(), next)
+}
+
+func (p restoreBootstrapperProvider) String() string {
+	return RestoreBootstrapperName
+}
+
+type restoreBootstrapper struct {
+	bootstrap.Bootstrapper
+}
+
+func (*restoreBootstrapper) String() string {
+	return RestoreBootstrapperName
+}
@@ -0,0 +1,548 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package restore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/backup"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+// restoreSource seeds the filesets of a node from a cluster-wide backup
+// before any other bootstrapper runs. The backed up data filesets, and index
+// volumes when the node restores the same shards it backed up, are copied to
+// the file path prefix of the node and the snapshot the backup was cut at is
+// loaded into memory. Every range is returned as unfulfilled so that the
+// following bootstrappers load the restored filesets and any data written
+// since.
+type restoreSource struct {
+	opts        Options
+	log         *zap.Logger
+	newReaderFn fs.NewReaderFn
+
+	manifests       []backup.Manifest
+	manifestsLoaded bool
+}
+
+type shardBackup struct {
+	hostDir   string
+	namespace backup.NamespaceManifest
+	shard     backup.ShardManifest
+}
+
+func newRestoreSource(opts Options) bootstrap.Source {
+	return &restoreSource{
+		opts:        opts,
+		log:         opts.InstrumentOptions().Logger(),
+		newReaderFn: fs.NewReader,
+	}
+}
+
+func (s *restoreSource) AvailableData(
+	ns namespace.Metadata,
+	shardsTimeRanges result.ShardTimeRanges,
+	_ bootstrap.Cache,
+	_ bootstrap.RunOptions,
+) (result.ShardTimeRanges, error) {
+	return s.availability(ns, shardsTimeRanges)
+}
+
+func (s *restoreSource) AvailableIndex(
+	ns namespace.Metadata,
+	shardsTimeRanges result.ShardTimeRanges,
+	_ bootstrap.Cache,
+	_ bootstrap.RunOptions,
+) (result.ShardTimeRanges, error) {
+	return s.availability(ns, shardsTimeRanges)
+}
+
+func (s *restoreSource) availability(
+	ns namespace.Metadata,
+	shardsTimeRanges result.ShardTimeRanges,
+) (result.ShardTimeRanges, error) {
+	if err := s.loadManifests(); err != nil {
+		return nil, err
+	}
+
+	var (
+		source    = s.sourceNamespace(ns.ID())
+		available = result.NewShardTimeRanges()
+	)
+	for shard, ranges := range shardsTimeRanges.Iter() {
+		if _, ok := s.shardBackup(source, shard, ""); ok {
+			available.Set(shard, ranges)
+		}
+	}
+	return available, nil
+}
+
+func (s *restoreSource) Read(
+	ctx context.Context,
+	namespaces bootstrap.Namespaces,
+	cache bootstrap.Cache,
+) (bootstrap.NamespaceResults, error) {
+	if err := s.loadManifests(); err != nil {
+		return bootstrap.NamespaceResults{}, err
+	}
+
+	var (
+		results = bootstrap.NamespaceResults{
+			Results: bootstrap.NewNamespaceResultsMap(bootstrap.NamespaceResultsMapOptions{}),
+		}
+		copied = false
+	)
+	for _, elem := range namespaces.Namespaces.Iter() {
+		ns := elem.Value()
+		nsCopied, err := s.restoreNamespace(ns)
+		if err != nil {
+			return bootstrap.NamespaceResults{}, fmt.Errorf(
+				"failed to restore namespace %s: %v", ns.Metadata.ID().String(), err)
+		}
+		copied = copied || nsCopied
+
+		// NB: The restored filesets are loaded by the bootstrappers that
+		// follow, which also fill in any data written since the backup.
+		namespaceResult := bootstrap.NamespaceResult{
+			Metadata:   ns.Metadata,
+			Shards:     ns.Shards,
+			DataResult: ns.DataRunOptions.ShardTimeRanges.ToUnfulfilledDataResult(),
+		}
+		if ns.Metadata.Options().IndexOptions().Enabled() {
+			namespaceResult.IndexResult = ns.IndexRunOptions.ShardTimeRanges.ToUnfulfilledIndexResult()
+		}
+		results.Results.Set(ns.Metadata.ID(), namespaceResult)
+	}
+
+	if copied {
+		// Filesets were copied to disk, make sure the info files are
+		// re-read by the bootstrappers that follow.
+		cache.Evict()
+	}
+	return results, nil
+}
+
+func (s *restoreSource) restoreNamespace(ns bootstrap.Namespace) (bool, error) {
+	var (
+		md        = ns.Metadata
+		source    = s.sourceNamespace(md.ID())
+		sourceID  = ident.StringID(source)
+		blockSize = md.Options().RetentionOptions().BlockSize()
+		origin    = originHostID(ns.DataRunOptions.RunOptions)
+		copied    = false
+	)
+	for shard, ranges := range ns.DataRunOptions.ShardTimeRanges.Iter() {
+		b, ok := s.shardBackup(source, shard, origin)
+		if !ok {
+			continue
+		}
+		if b.namespace.BlockSize != blockSize {
+			return false, fmt.Errorf(
+				"block size %s does not match block size %s of backed up namespace %s",
+				blockSize.String(), b.namespace.BlockSize.String(), source)
+		}
+
+		n, err := s.restoreShardData(md, sourceID, shard, ranges, b)
+		if err != nil {
+			return false, fmt.Errorf("failed to restore shard %d: %v", shard, err)
+		}
+		if err := s.loadShardSnapshots(ns, sourceID, shard, ranges, b); err != nil {
+			return false, fmt.Errorf("failed to load snapshot of shard %d: %v", shard, err)
+		}
+		copied = copied || n > 0
+	}
+
+	if !md.Options().IndexOptions().Enabled() {
+		return copied, nil
+	}
+	n, err := s.restoreIndexVolumes(ns, sourceID, origin)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore index volumes: %v", err)
+	}
+	return copied || n > 0, nil
+}
+
+// restoreShardData copies the backed up data filesets of the blocks of a
+// shard that are not on disk yet, returning the number of filesets copied.
+func (s *restoreSource) restoreShardData(
+	md namespace.Metadata,
+	sourceID ident.ID,
+	shard uint32,
+	ranges xtime.Ranges,
+	b shardBackup,
+) (int, error) {
+	var (
+		fsOpts    = s.opts.FilesystemOptions()
+		prefix    = fsOpts.FilePathPrefix()
+		blockSize = md.Options().RetentionOptions().BlockSize()
+		copied    = 0
+	)
+	local, err := fs.DataFiles(prefix, md.ID(), shard)
+	if err != nil {
+		return 0, err
+	}
+	backedUp, err := fs.DataFiles(b.hostDir, sourceID, shard)
+	if err != nil {
+		return 0, err
+	}
+	for _, vol := range b.shard.DataVolumes {
+		if !overlapsBlock(ranges, vol.BlockStart, blockSize) {
+			continue
+		}
+		if _, ok := local.LatestVolumeForBlock(vol.BlockStart); ok {
+			// Never overwrite data that was flushed since the restore.
+			continue
+		}
+		f, ok := volumeFiles(backedUp, vol)
+		if !ok {
+			return 0, fmt.Errorf("data fileset of block %s volume %d missing from backup",
+				vol.BlockStart.String(), vol.VolumeIndex)
+		}
+		if err := backup.LinkOrCopyFiles(
+			fs.ShardDataDirPath(b.hostDir, sourceID, shard),
+			fs.ShardDataDirPath(prefix, md.ID(), shard),
+			f.AbsoluteFilePaths, fsOpts); err != nil {
+			return 0, err
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// loadShardSnapshots loads the backed up snapshot filesets of a shard into
+// memory, the same way the commit log bootstrapper loads local snapshots.
+func (s *restoreSource) loadShardSnapshots(
+	ns bootstrap.Namespace,
+	sourceID ident.ID,
+	shard uint32,
+	ranges xtime.Ranges,
+	b shardBackup,
+) error {
+	var (
+		md        = ns.Metadata
+		prefix    = s.opts.FilesystemOptions().FilePathPrefix()
+		blockSize = md.Options().RetentionOptions().BlockSize()
+	)
+	local, err := fs.DataFiles(prefix, md.ID(), shard)
+	if err != nil {
+		return err
+	}
+	for _, vol := range b.shard.SnapshotVolumes {
+		if !overlapsBlock(ranges, vol.BlockStart, blockSize) {
+			continue
+		}
+		writeType := series.WarmWrite
+		if _, ok := local.LatestVolumeForBlock(vol.BlockStart); ok {
+			writeType = series.ColdWrite
+		}
+		if err := s.loadSnapshot(ns, sourceID, shard, vol, b.hostDir,
+			blockSize, writeType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *restoreSource) loadSnapshot(
+	ns bootstrap.Namespace,
+	sourceID ident.ID,
+	shard uint32,
+	vol backup.VolumeManifest,
+	hostDir string,
+	blockSize time.Duration,
+	writeType series.WriteType,
+) error {
+	var (
+		blOpts     = s.opts.ResultOptions().DatabaseBlockOptions()
+		blocksPool = blOpts.DatabaseBlockPool()
+		fsOpts     = s.opts.FilesystemOptions().SetFilePathPrefix(hostDir)
+		nsCtx      = namespace.NewContextFrom(ns.Metadata)
+	)
+	reader, err := s.newReaderFn(blOpts.BytesPool(), fsOpts)
+	if err != nil {
+		return err
+	}
+	if err := reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   sourceID,
+			Shard:       shard,
+			BlockStart:  vol.BlockStart,
+			VolumeIndex: vol.VolumeIndex,
+		},
+		FileSetType: persist.FileSetSnapshotType,
+	}); err != nil {
+		return err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			s.log.Error("error closing restore snapshot reader",
+				zap.Uint32("shard", shard),
+				zap.Time("blockStart", vol.BlockStart.ToTime()),
+				zap.Error(err))
+		}
+	}()
+
+	for {
+		id, tags, data, expectedChecksum, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dbBlock := blocksPool.Get()
+		dbBlock.Reset(vol.BlockStart, blockSize,
+			ts.NewSegment(data, nil, 0, ts.FinalizeHead), nsCtx)
+		checksum, err := dbBlock.Checksum()
+		if err != nil {
+			return err
+		}
+		if checksum != expectedChecksum {
+			return fmt.Errorf("checksum for series: %s was %d but expected %d",
+				id, checksum, expectedChecksum)
+		}
+
+		res, owned, err := ns.DataAccumulator.CheckoutSeriesWithLock(shard, id, tags)
+		id.Finalize()
+		tags.Close()
+		if err != nil {
+			dbBlock.Close()
+			if !owned {
+				// Skip restoring this series if we don't own it.
+				continue
+			}
+			return err
+		}
+
+		ref, err := res.Resolver.SeriesRef()
+		if err != nil {
+			return fmt.Errorf("unable to resolve series ref: %w", err)
+		}
+		if err := ref.LoadBlock(dbBlock, writeType); err != nil {
+			return err
+		}
+	}
+}
+
+// restoreIndexVolumes copies the backed up index volumes of a namespace that
+// are not on disk yet, returning the number of volumes copied. Index volumes
+// cover every shard of the host they were backed up on, so they are only
+// restored when the node restores the same shards it backed up, otherwise
+// the index is rebuilt from the restored data filesets.
+func (s *restoreSource) restoreIndexVolumes(
+	ns bootstrap.Namespace,
+	sourceID ident.ID,
+	origin string,
+) (int, error) {
+	var (
+		md     = ns.Metadata
+		fsOpts = s.opts.FilesystemOptions()
+		prefix = fsOpts.FilePathPrefix()
+		ranges = xtime.NewRanges()
+		copied = 0
+	)
+	hostDir, nsBackup, ok := s.hostNamespaceBackup(sourceID.String(), origin)
+	if !ok || nsBackup.IndexBlockSize != md.Options().IndexOptions().BlockSize() ||
+		!sameShards(nsBackup, ns.Shards) {
+		return 0, nil
+	}
+
+	for _, shardRanges := range ns.IndexRunOptions.ShardTimeRanges.Iter() {
+		ranges.AddRanges(shardRanges)
+	}
+	onDisk := make(map[xtime.UnixNano]struct{})
+	for _, f := range fs.ReadIndexInfoFiles(fs.ReadIndexInfoFilesOptions{
+		FilePathPrefix:   prefix,
+		Namespace:        md.ID(),
+		ReaderBufferSize: fsOpts.InfoReaderBufferSize(),
+	}) {
+		onDisk[f.ID.BlockStart] = struct{}{}
+	}
+
+	backedUp := fs.ReadIndexInfoFiles(fs.ReadIndexInfoFilesOptions{
+		FilePathPrefix:   hostDir,
+		Namespace:        sourceID,
+		ReaderBufferSize: fsOpts.InfoReaderBufferSize(),
+	})
+	for _, f := range backedUp {
+		if f.Err.Error() != nil || f.Corrupted {
+			continue
+		}
+		if !overlapsBlock(ranges, f.ID.BlockStart, nsBackup.IndexBlockSize) {
+			continue
+		}
+		if _, ok := onDisk[f.ID.BlockStart]; ok {
+			continue
+		}
+		if !hasVolume(nsBackup.IndexVolumes, f.ID) {
+			continue
+		}
+		if err := backup.LinkOrCopyFiles(
+			fs.NamespaceIndexDataDirPath(hostDir, sourceID),
+			fs.NamespaceIndexDataDirPath(prefix, md.ID()),
+			f.AbsoluteFilePaths, fsOpts); err != nil {
+			return 0, err
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+func (s *restoreSource) loadManifests() error {
+	if s.manifestsLoaded {
+		return nil
+	}
+	manifests, err := backup.ReadManifests(s.opts.BackupDirectory())
+	if err != nil {
+		return fmt.Errorf("failed to read backup manifests: %v", err)
+	}
+	if len(manifests) == 0 {
+		s.log.Warn("no completed host backups found to restore from",
+			zap.String("dir", s.opts.BackupDirectory()))
+	}
+	s.manifests = manifests
+	s.manifestsLoaded = true
+	return nil
+}
+
+func (s *restoreSource) sourceNamespace(id ident.ID) string {
+	if source, ok := s.opts.NamespaceMappings()[id.String()]; ok {
+		return source
+	}
+	return id.String()
+}
+
+// shardBackup returns the backup of a shard to restore from, preferring the
+// backup of the origin host and otherwise the most recent backup of a replica.
+func (s *restoreSource) shardBackup(
+	source string,
+	shard uint32,
+	origin string,
+) (shardBackup, bool) {
+	var (
+		result    shardBackup
+		createdAt time.Time
+		found     bool
+	)
+	for _, m := range s.manifests {
+		nsBackup, ok := m.Namespace(source)
+		if !ok {
+			continue
+		}
+		shardManifest, ok := nsBackup.Shard(shard)
+		if !ok {
+			continue
+		}
+		if found && m.HostID != origin && !m.CreatedAt.After(createdAt) {
+			continue
+		}
+		result = shardBackup{
+			hostDir:   s.hostDir(m),
+			namespace: nsBackup,
+			shard:     shardManifest,
+		}
+		createdAt = m.CreatedAt
+		found = true
+		if m.HostID == origin {
+			break
+		}
+	}
+	return result, found
+}
+
+func (s *restoreSource) hostNamespaceBackup(
+	source string,
+	hostID string,
+) (string, backup.NamespaceManifest, bool) {
+	for _, m := range s.manifests {
+		if m.HostID != hostID {
+			continue
+		}
+		nsBackup, ok := m.Namespace(source)
+		return s.hostDir(m), nsBackup, ok
+	}
+	return "", backup.NamespaceManifest{}, false
+}
+
+func (s *restoreSource) hostDir(m backup.Manifest) string {
+	return filepath.Join(s.opts.BackupDirectory(), m.HostID)
+}
+
+func originHostID(runOpts bootstrap.RunOptions) string {
+	if topoState := runOpts.InitialTopologyState(); topoState != nil && topoState.Origin != nil {
+		return topoState.Origin.ID()
+	}
+	return ""
+}
+
+func overlapsBlock(ranges xtime.Ranges, blockStart xtime.UnixNano, blockSize time.Duration) bool {
+	return ranges.Overlaps(xtime.Range{
+		Start: blockStart,
+		End:   blockStart.Add(blockSize),
+	})
+}
+
+func volumeFiles(files fs.FileSetFilesSlice, vol backup.VolumeManifest) (fs.FileSetFile, bool) {
+	for _, f := range files {
+		if f.ID.BlockStart == vol.BlockStart && f.ID.VolumeIndex == vol.VolumeIndex &&
+			f.HasCompleteCheckpointFile() {
+			return f, true
+		}
+	}
+	return fs.FileSetFile{}, false
+}
+
+func hasVolume(vols []backup.VolumeManifest, id fs.FileSetFileIdentifier) bool {
+	for _, vol := range vols {
+		if vol.BlockStart == id.BlockStart && vol.VolumeIndex == id.VolumeIndex {
+			return true
+		}
+	}
+	return false
+}
+
+func sameShards(nsBackup backup.NamespaceManifest, shards []uint32) bool {
+	if len(nsBackup.Shards) != len(shards) {
+		return false
+	}
+	for _, shard := range shards {
+		if _, ok := nsBackup.Shard(shard); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package restore
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/backup"
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/blob"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var testBlockSize = 2 * time.Hour
+
+func newTestNamespace(t *testing.T, id string) namespace.Metadata {
+	opts := namespace.NewOptions().
+		SetRetentionOptions(namespace.NewOptions().RetentionOptions().
+			SetRetentionPeriod(48 * time.Hour).
+			SetBlockSize(testBlockSize)).
+		SetIndexOptions(namespace.NewIndexOptions().SetEnabled(false))
+	md, err := namespace.NewMetadata(ident.StringID(id), opts)
+	require.NoError(t, err)
+	return md
+}
+
+func writeTestFileSet(
+	t *testing.T,
+	fsOpts fs.Options,
+	nsID ident.ID,
+	blockStart xtime.UnixNano,
+	snapshotID uuid.UUID,
+	value float64,
+) {
+	w, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+	writerOpts := fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:  nsID,
+			Shard:      0,
+			BlockStart: blockStart,
+		},
+		BlockSize: testBlockSize,
+	}
+	if snapshotID != nil {
+		writerOpts.FileSetType = persist.FileSetSnapshotType
+		writerOpts.Snapshot = fs.DataWriterSnapshotOptions{
+			SnapshotTime: blockStart.Add(time.Minute),
+			SnapshotID:   snapshotID,
+		}
+	}
+	require.NoError(t, w.Open(writerOpts))
+
+	encoder := NewOptions().ResultOptions().DatabaseBlockOptions().EncoderPool().Get()
+	encoder.Reset(blockStart, 0, nil)
+	require.NoError(t, encoder.Encode(ts.Datapoint{
+		TimestampNanos: blockStart,
+		Value:          value,
+	}, xtime.Second, nil))
+	ctx := context.NewBackground()
+	stream, ok := encoder.Stream(ctx)
+	require.True(t, ok)
+	data, err := xio.ToBytes(stream)
+	require.Equal(t, io.EOF, err)
+	bytes := checked.NewBytes(append([]byte(nil), data...), nil)
+	ctx.Close()
+	encoder.Close()
+
+	bytes.IncRef()
+	metadata := persist.NewMetadataFromIDAndTags(ident.StringID("foo"),
+		ident.Tags{}, persist.MetadataOptions{})
+	require.NoError(t, w.Write(metadata, bytes, digest.Checksum(bytes.Bytes())))
+	require.NoError(t, w.Close())
+}
+
+// writeTestBackup writes a backup of host0 with a flushed block of the
+// source namespace and a snapshot of the following block.
+func writeTestBackup(
+	t *testing.T,
+	dir string,
+	md namespace.Metadata,
+	flushed xtime.UnixNano,
+) string {
+	var (
+		hostPrefix = filepath.Join(dir, "host0")
+		fsOpts     = fs.NewOptions().SetFilePathPrefix(hostPrefix)
+		snapshotID = uuid.NewRandom()
+		backupDir  = filepath.Join(dir, "backups")
+	)
+	writeTestFileSet(t, fsOpts, md.ID(), flushed, nil, 1)
+	writeTestFileSet(t, fsOpts, md.ID(), flushed.Add(testBlockSize), snapshotID, 2)
+	require.NoError(t, fs.NewSnapshotMetadataWriter(fsOpts).Write(fs.SnapshotMetadataWriteArgs{
+		ID: fs.SnapshotMetadataIdentifier{Index: 0, UUID: snapshotID},
+		CommitlogIdentifier: persist.CommitLogFile{
+			FilePath: "commitlog-0-1.db",
+			Index:    1,
+		},
+	}))
+
+	hostDir := backup.HostDirPath(backupDir, "b1", "host0")
+	snapshot, err := backup.CopySnapshotMetadata(fsOpts, hostDir)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	require.Equal(t, snapshotID.String(), snapshot.ID)
+	nsBackup, err := backup.CopyNamespace(fsOpts, nil, hostDir, md, []uint32{0}, snapshot)
+	require.NoError(t, err)
+	require.Equal(t, []backup.ShardManifest{
+		{
+			ID:              0,
+			DataVolumes:     []backup.VolumeManifest{{BlockStart: flushed}},
+			SnapshotVolumes: []backup.VolumeManifest{{BlockStart: flushed.Add(testBlockSize)}},
+			CommitLog:       &snapshot.CommitLog,
+		},
+	}, nsBackup.Shards)
+	require.NoError(t, backup.WriteManifest(backupDir, backup.Manifest{
+		ID:         "b1",
+		HostID:     "host0",
+		CreatedAt:  time.Now(),
+		Snapshot:   snapshot,
+		Namespaces: []backup.NamespaceManifest{nsBackup},
+	}, fsOpts))
+	return filepath.Join(backupDir, "b1")
+}
+
+func TestRestoreSourceRestoresIntoMappedNamespace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		srcMd   = newTestNamespace(t, "src")
+		dstMd   = newTestNamespace(t, "dst")
+		flushed = xtime.Now().Truncate(testBlockSize).Add(-4 * testBlockSize)
+		fsOpts  = fs.NewOptions().SetFilePathPrefix(filepath.Join(dir, "local"))
+	)
+	opts := NewOptions().
+		SetFilesystemOptions(fsOpts).
+		SetBackupDirectory(writeTestBackup(t, dir, srcMd, flushed)).
+		SetNamespaceMappings(map[string]string{"dst": "src"})
+	require.NoError(t, opts.Validate())
+
+	src := newRestoreSource(opts)
+	ranges := result.NewShardTimeRangesFromRange(flushed,
+		flushed.Add(2*testBlockSize), 0, 1)
+	available, err := src.AvailableData(dstMd, ranges, nil, bootstrap.NewRunOptions())
+	require.NoError(t, err)
+	require.True(t, available.Equal(result.NewShardTimeRangesFromRange(flushed,
+		flushed.Add(2*testBlockSize), 0)))
+
+	tester := bootstrap.BuildNamespacesTesterWithFilesystemOptions(t,
+		bootstrap.NewRunOptions(), available, fsOpts, dstMd)
+	defer tester.Finish()
+	tester.TestReadWith(src)
+
+	// Every range is left for the following bootstrappers.
+	tester.TestUnfulfilledForNamespace(dstMd, available, nil)
+	tester.EnsureNoWrites()
+
+	// The flushed block was restored to disk under the mapped namespace.
+	exists, err := fs.DataFileSetExists(fsOpts.FilePathPrefix(),
+		dstMd.ID(), 0, flushed, 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// The snapshot was loaded into memory.
+	loaded := tester.EnsureDumpLoadedBlocksForNamespace(dstMd)
+	require.Len(t, loaded, 1)
+	require.Len(t, loaded["foo"], 1)
+	require.Equal(t, flushed.Add(testBlockSize), loaded["foo"][0].Timestamp)
+	require.Equal(t, 2.0, loaded["foo"][0].Value)
+}
+
+func TestRestoreSourceBlockSizeMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		srcMd   = newTestNamespace(t, "src")
+		flushed = xtime.Now().Truncate(testBlockSize).Add(-4 * testBlockSize)
+		fsOpts  = fs.NewOptions().SetFilePathPrefix(filepath.Join(dir, "local"))
+	)
+	dstMd, err := namespace.NewMetadata(ident.StringID("src"),
+		srcMd.Options().SetRetentionOptions(
+			srcMd.Options().RetentionOptions().SetBlockSize(time.Hour)))
+	require.NoError(t, err)
+
+	opts := NewOptions().
+		SetFilesystemOptions(fsOpts).
+		SetBackupDirectory(writeTestBackup(t, dir, srcMd, flushed))
+	ranges := result.NewShardTimeRangesFromRange(flushed,
+		flushed.Add(2*testBlockSize), 0)
+	tester := bootstrap.BuildNamespacesTesterWithFilesystemOptions(t,
+		bootstrap.NewRunOptions(), ranges, fsOpts, dstMd)
+	defer tester.Finish()
+
+	_, err = newRestoreSource(opts).Read(context.NewBackground(),
+		tester.Namespaces, tester.Cache)
+	require.Error(t, err)
+}
+
+func hasDataFile(f fs.FileSetFile) bool {
+	for _, p := range f.AbsoluteFilePaths {
+		if strings.HasSuffix(p, "-data.db") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBackupRestoresOffloadedVolumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := blob.NewLocalStore(filepath.Join(dir, "blob"))
+	require.NoError(t, err)
+
+	var (
+		md      = newTestNamespace(t, "src")
+		flushed = xtime.Now().Truncate(testBlockSize).Add(-4 * testBlockSize)
+		fsOpts  = fs.NewOptions().SetFilePathPrefix(filepath.Join(dir, "host0"))
+	)
+	writeTestFileSet(t, fsOpts, md.ID(), flushed, nil, 1)
+
+	offloader, err := fs.NewFileSetOffloader(store, fsOpts, fs.OffloadOptions{
+		OffloadAfter:       time.Hour,
+		MaxRestoredVolumes: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, offloader.Offload(md.ID(), 0, testBlockSize,
+		xtime.Now(), flushed.Add(-testBlockSize)))
+
+	dataFiles, err := fs.DataFiles(fsOpts.FilePathPrefix(), md.ID(), 0)
+	require.NoError(t, err)
+	require.Len(t, dataFiles, 1)
+	require.False(t, hasDataFile(dataFiles[0]))
+
+	// Offloaded volumes cannot be backed up without tiered storage.
+	hostDir := backup.HostDirPath(filepath.Join(dir, "backups"), "b1", "host0")
+	_, err = backup.CopyNamespace(fsOpts, nil, hostDir, md, []uint32{0}, nil)
+	require.Error(t, err)
+
+	nsBackup, err := backup.CopyNamespace(fsOpts, offloader, hostDir, md, []uint32{0}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []backup.ShardManifest{
+		{
+			ID:          0,
+			DataVolumes: []backup.VolumeManifest{{BlockStart: flushed}},
+		},
+	}, nsBackup.Shards)
+
+	backedUp, err := fs.DataFiles(hostDir, md.ID(), 0)
+	require.NoError(t, err)
+	require.Len(t, backedUp, 1)
+	require.True(t, hasDataFile(backedUp[0]))
+}
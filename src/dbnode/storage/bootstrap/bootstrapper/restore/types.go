@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package restore
+
+import (
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Options is the options interface for the restore source.
+type Options interface {
+	// Validate the values of the options.
+	Validate() error
+
+	// SetResultOptions sets the result options.
+	SetResultOptions(value result.Options) Options
+
+	// ResultOptions returns the result options.
+	ResultOptions() result.Options
+
+	// SetFilesystemOptions sets the filesystem options.
+	SetFilesystemOptions(value fs.Options) Options
+
+	// FilesystemOptions returns the filesystem options.
+	FilesystemOptions() fs.Options
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetBackupDirectory sets the directory of the backup to restore from,
+	// which contains the backup of each host.
+	SetBackupDirectory(value string) Options
+
+	// BackupDirectory returns the directory of the backup to restore from.
+	BackupDirectory() string
+
+	// SetNamespaceMappings sets the namespaces of the backup to restore each
+	// namespace from, keyed by the namespace restored to. Namespaces without
+	// a mapping are restored from the namespace of the same name.
+	SetNamespaceMappings(value map[string]string) Options
+
+	// NamespaceMappings returns the namespaces of the backup to restore
+	// each namespace from.
+	NamespaceMappings() map[string]string
+}
@@ -82,8 +82,11 @@ type IndexBlock struct {
 
 // Segment wraps an index segment so we can easily determine whether or not the segment is persisted to disk.
 type Segment struct {
-	segment   segment.Segment
-	persisted bool
+	segment      segment.Segment
+	persisted    bool
+	volume       bool
+	volumeIndex  int
+	segmentIndex int
 }
 
 // NewSegment returns an index segment w/ persistence metadata.
@@ -94,6 +97,22 @@ func NewSegment(segment segment.Segment, persisted bool) Segment {
 	}
 }
 
+// NewPersistedSegment returns an index segment which was persisted to disk
+// as the segment at segmentIndex of the index fileset volume volumeIndex.
+func NewPersistedSegment(
+	segment segment.Segment,
+	volumeIndex int,
+	segmentIndex int,
+) Segment {
+	return Segment{
+		segment:      segment,
+		persisted:    true,
+		volume:       true,
+		volumeIndex:  volumeIndex,
+		segmentIndex: segmentIndex,
+	}
+}
+
 // IsPersisted returns whether or not the underlying segment was persisted to disk.
 func (s Segment) IsPersisted() bool {
 	return s.persisted
@@ -104,6 +123,12 @@ func (s Segment) Segment() segment.Segment {
 	return s.segment
 }
 
+// PersistedVolume returns the index fileset volume index and the index of
+// the segment within that volume that the segment was persisted as, if known.
+func (s Segment) PersistedVolume() (volumeIndex int, segmentIndex int, ok bool) {
+	return s.volumeIndex, s.segmentIndex, s.volume
+}
+
 // DocumentsBuilderAllocator allocates a new DocumentsBuilder type when
 // creating a bootstrap result to return to the index.
 type DocumentsBuilderAllocator func() (segment.DocumentsBuilder, error)
@@ -212,6 +212,11 @@ func (m *cleanupManager) ColdFlushCleanup(t xtime.UnixNano) error {
 			"encountered errors when deleting inactive data files for %v: %v", t, err))
 	}
 
+	if err := m.offloadDataFiles(t, namespaces); err != nil {
+		multiErr = multiErr.Add(fmt.Errorf(
+			"encountered errors when offloading data files for %v: %v", t, err))
+	}
+
 	return multiErr.FinalError()
 }
 
@@ -285,6 +290,10 @@ func (m *cleanupManager) cleanupDataFiles(t xtime.UnixNano, namespaces []databas
 			continue
 		}
 		earliestToRetain := retention.FlushTimeStart(n.Options().RetentionOptions(), t)
+		if rawEarliestToRetain, ok := m.rolledUpEarliestToRetain(t, n); ok &&
+			rawEarliestToRetain.After(earliestToRetain) {
+			earliestToRetain = rawEarliestToRetain
+		}
 		shards := n.OwnedShards()
 		multiErr = multiErr.Add(m.cleanupExpiredNamespaceDataFiles(earliestToRetain, shards))
 		multiErr = multiErr.Add(m.cleanupCompactedNamespaceDataFiles(shards))
@@ -292,6 +301,72 @@ func (m *cleanupManager) cleanupDataFiles(t xtime.UnixNano, namespaces []databas
 	return multiErr.FinalError()
 }
 
+// rolledUpEarliestToRetain returns the earliest block to retain for a namespace
+// whose rollup policy shortens the retention of raw data. Blocks are only
+// expired once the whole target block they were rolled up into is complete,
+// since a partial target block is re-aggregated from its raw blocks.
+func (m *cleanupManager) rolledUpEarliestToRetain(
+	t xtime.UnixNano, n databaseNamespace,
+) (xtime.UnixNano, bool) {
+	policy := rollupPolicy(n.Options())
+	if !policy.Enabled() || policy.RawRetention <= 0 {
+		return 0, false
+	}
+
+	targetID := ident.StringID(policy.TargetNamespace)
+	target, ok := m.database.Namespace(targetID)
+	if !ok {
+		return 0, false
+	}
+
+	progressPath := fs.NamespaceRollupProgressFilePath(m.filePathPrefix, n.ID(), targetID)
+	rolledUpThrough, err := readRollupProgress(progressPath)
+	if err != nil {
+		m.logger.Error("could not read rollup progress",
+			zap.Stringer("namespace", n.ID()), zap.Error(err))
+		return 0, false
+	}
+
+	var (
+		blockSize       = n.Options().RetentionOptions().BlockSize()
+		targetBlockSize = target.Options().RetentionOptions().BlockSize()
+		rawStart        = t.Add(-policy.RawRetention).Truncate(blockSize)
+		rolledUpStart   = rolledUpThrough.Truncate(targetBlockSize)
+	)
+	if rolledUpStart.Before(rawStart) {
+		return rolledUpStart, true
+	}
+	return rawStart, true
+}
+
+// offloadDataFiles offloads the data filesets of blocks past the offload age,
+// it runs after the cold flush so that cold flushed volumes are offloaded
+// and superseded offloaded volumes are removed from the blob store.
+func (m *cleanupManager) offloadDataFiles(t xtime.UnixNano, namespaces []databaseNamespace) error {
+	offloader := m.opts.FileSetOffloader()
+	if offloader == nil {
+		return nil
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		var (
+			retentionOpts    = n.Options().RetentionOptions()
+			earliestToRetain = retention.FlushTimeStart(retentionOpts, t)
+		)
+		for _, shard := range n.OwnedShards() {
+			if !shard.IsBootstrapped() {
+				continue
+			}
+			if err := offloader.Offload(n.ID(), shard.ID(), retentionOpts.BlockSize(),
+				t, earliestToRetain); err != nil {
+				multiErr = multiErr.Add(err)
+			}
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (m *cleanupManager) cleanupExpiredIndexFiles(
 	t xtime.UnixNano, namespaces []databaseNamespace,
 ) error {
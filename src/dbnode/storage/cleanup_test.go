@@ -471,6 +471,80 @@ func TestCleanupDataAndSnapshotFileSetFiles(t *testing.T) {
 	require.NoError(t, cleanup(mgr, ts))
 }
 
+type testOffload struct {
+	namespace        string
+	shard            uint32
+	blockSize        time.Duration
+	now              xtime.UnixNano
+	earliestToRetain xtime.UnixNano
+}
+
+type testFileSetOffloader struct {
+	offloads []testOffload
+}
+
+func (o *testFileSetOffloader) Offload(
+	namespace ident.ID,
+	shard uint32,
+	blockSize time.Duration,
+	now xtime.UnixNano,
+	earliestToRetain xtime.UnixNano,
+) error {
+	o.offloads = append(o.offloads, testOffload{
+		namespace:        namespace.String(),
+		shard:            shard,
+		blockSize:        blockSize,
+		now:              now,
+		earliestToRetain: earliestToRetain,
+	})
+	return nil
+}
+
+func (o *testFileSetOffloader) Restore(ident.ID, uint32, xtime.UnixNano) error {
+	return nil
+}
+
+func TestCleanupManagerOffloadDataFiles(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	ts := timeFor()
+
+	nsOpts := namespaceOptions
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+	ns.EXPECT().ID().Return(ident.StringID("nsID")).AnyTimes()
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shardNotBootstrapped := NewMockdatabaseShard(ctrl)
+	shardNotBootstrapped.EXPECT().IsBootstrapped().Return(false).AnyTimes()
+	shardNotBootstrapped.EXPECT().ID().Return(uint32(1)).AnyTimes()
+	ns.EXPECT().OwnedShards().Return([]databaseShard{shard, shardNotBootstrapped}).AnyTimes()
+	namespaces := []databaseNamespace{ns}
+
+	db := newMockdatabase(ctrl, namespaces...)
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), tally.NoopScope).(*cleanupManager)
+
+	// Nothing is offloaded without an offloader.
+	require.NoError(t, mgr.offloadDataFiles(ts, namespaces))
+
+	offloader := &testFileSetOffloader{}
+	mgr.opts = mgr.opts.SetFileSetOffloader(offloader)
+	require.NoError(t, mgr.offloadDataFiles(ts, namespaces))
+
+	retentionOpts := nsOpts.RetentionOptions()
+	require.Equal(t, []testOffload{
+		{
+			namespace:        "nsID",
+			shard:            0,
+			blockSize:        retentionOpts.BlockSize(),
+			now:              ts,
+			earliestToRetain: retention.FlushTimeStart(retentionOpts, ts),
+		},
+	}, offloader.offloads)
+}
+
 type deleteInactiveDirectoriesCall struct {
 	parentDirPath  string
 	activeDirNames []string
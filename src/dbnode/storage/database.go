@@ -24,11 +24,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
@@ -102,6 +104,8 @@ type db struct {
 	nsWatch                namespace.NamespaceWatch
 	namespaces             *databaseNamespacesMap
 	runtimeOptionsRegistry namespace.RuntimeOptionsManagerRegistry
+	resizeStates           map[string]namespace.ResizeState
+	resizer                *namespaceResizer
 
 	commitLog commitlog.CommitLog
 
@@ -189,6 +193,8 @@ func NewDatabase(
 		lastReceivedNewShards:  nowFn(),
 		namespaces:             newDatabaseNamespacesMap(databaseNamespacesMapOptions{}),
 		runtimeOptionsRegistry: opts.NamespaceRuntimeOptionsManagerRegistry(),
+		resizeStates:           make(map[string]namespace.ResizeState),
+		resizer:                newNamespaceResizer(opts),
 		commitLog:              commitLog,
 		scope:                  scope,
 		metrics:                newDatabaseMetrics(scope),
@@ -291,6 +297,15 @@ func (d *db) UpdateOwnedNamespaces(newNamespaces namespace.Map) error {
 		}
 	}
 
+	var resizes []namespace.Metadata
+	defer func() {
+		// NB: resizes are enqueued once the lock is released since they
+		// acquire it when they run.
+		for _, md := range resizes {
+			d.enqueueResizeNamespace(md)
+		}
+	}()
+
 	d.Lock()
 	defer d.Unlock()
 
@@ -308,6 +323,9 @@ func (d *db) UpdateOwnedNamespaces(newNamespaces namespace.Map) error {
 		return err
 	}
 
+	// apply updates that resize namespaces, the rest require a restart
+	updates, resizes = d.applyResizeUpdatesWithLock(updates)
+
 	// log that updates and removals are skipped
 	if len(removes) > 0 || len(updates) > 0 {
 		d.metrics.pendingNamespaceChange.Update(1)
@@ -404,12 +422,19 @@ func (d *db) addNamespacesWithLock(namespaces []namespace.Metadata) error {
 			return fmt.Errorf("existing namespace marked for addition: %v", n.ID().String())
 		}
 
+		// complete a resize that was interrupted before switching over
+		if err := d.resizer.completeResize(n); err != nil {
+			return fmt.Errorf("unable to complete resize of namespace %s: %v",
+				n.ID().String(), err)
+		}
+
 		// create and add to the database
 		newNs, err := d.newDatabaseNamespaceWithLock(n)
 		if err != nil {
 			return err
 		}
 		d.namespaces.Set(n.ID(), newNs)
+		d.resizeStates[n.ID().String()] = n.Options().ResizeState()
 		createdNamespaces = append(createdNamespaces, newNs)
 	}
 
@@ -424,6 +449,256 @@ func (d *db) addNamespacesWithLock(namespaces []namespace.Metadata) error {
 	return nil
 }
 
+// applyResizeUpdatesWithLock records changes of the resize states of namespaces
+// and returns the updates that complete a resize, which are applied by
+// switching over the namespace, separately from the remaining updates.
+func (d *db) applyResizeUpdatesWithLock(
+	updates []namespace.Metadata,
+) ([]namespace.Metadata, []namespace.Metadata) {
+	var (
+		remaining = make([]namespace.Metadata, 0, len(updates))
+		resizes   []namespace.Metadata
+	)
+	for _, md := range updates {
+		ns, ok := d.namespaces.Get(md.ID())
+		if !ok {
+			remaining = append(remaining, md)
+			continue
+		}
+
+		var (
+			id       = md.ID().String()
+			currOpts = ns.Options().SetResizeState(d.resizeStates[id])
+			nextOpts = md.Options()
+		)
+		switch {
+		case currOpts.Equal(nextOpts):
+		case currOpts.SetResizeState(nextOpts.ResizeState()).Equal(nextOpts):
+			d.log.Info("updating namespace resize state",
+				zap.String("namespace", id),
+				zap.Duration("blockSize", nextOpts.ResizeState().BlockSize),
+				zap.Duration("indexBlockSize", nextOpts.ResizeState().IndexBlockSize))
+			d.resizeStates[id] = nextOpts.ResizeState()
+		case isOnlineResize(currOpts, nextOpts):
+			resizes = append(resizes, md)
+		default:
+			remaining = append(remaining, md)
+		}
+	}
+	return remaining, resizes
+}
+
+// isOnlineResize returns whether an update of the options of a namespace only
+// completes its in-progress resize, optionally along with a change of its
+// retention period.
+func isOnlineResize(curr, next namespace.Options) bool {
+	if !curr.ResizeState().InProgress() || next.ResizeState().InProgress() {
+		return false
+	}
+	resized := namespace.ResizedOptions(curr)
+	ropts := resized.RetentionOptions().
+		SetRetentionPeriod(next.RetentionOptions().RetentionPeriod())
+	return resized.SetRetentionOptions(ropts).Equal(next)
+}
+
+func (d *db) enqueueResizeNamespace(md namespace.Metadata) {
+	resize := func() {
+		if err := d.resizeNamespace(md); err != nil {
+			d.log.Error("failed to resize namespace, restart the process "+
+				"to complete the resize",
+				zap.Stringer("namespace", md.ID()), zap.Error(err))
+		}
+	}
+	if !d.mediator.IsOpen() {
+		go resize()
+		return
+	}
+
+	if err := d.mediator.EnqueueMutuallyExclusiveFn(resize); err != nil {
+		// should not happen.
+		instrument.EmitAndLogInvariantViolation(d.opts.InstrumentOptions(),
+			func(l *zap.Logger) {
+				l.Error("failed to enqueue resizeNamespace fn",
+					zap.Error(err),
+					zap.Stringer("namespace", md.ID()))
+			})
+	}
+}
+
+// resizeNamespace switches a namespace over to the block sizes and retention
+// of the given metadata by replacing it with a new namespace. Blocks that were
+// converted ahead of time are moved into place while the data that was not
+// converted is carried over and written to the new namespace once it has
+// bootstrapped. Writes to the namespace wait for the switch over to complete.
+func (d *db) resizeNamespace(md namespace.Metadata) error {
+	d.RLock()
+	curr, ok := d.namespaces.Get(md.ID())
+	d.RUnlock()
+	if !ok || curr.Options().Equal(md.Options()) {
+		return nil
+	}
+
+	var (
+		id            = md.ID()
+		now           = xtime.ToUnixNano(d.nowFn())
+		currOpts      = curr.Options()
+		currRetention = currOpts.RetentionOptions()
+		blockSize     = md.Options().RetentionOptions().BlockSize()
+		resized       = blockSize != currRetention.BlockSize()
+		indexResized  = md.Options().IndexOptions().BlockSize() != currOpts.IndexOptions().BlockSize()
+		shards        = make(map[uint32]databaseShard)
+		firstUnsealed = make(map[uint32]xtime.UnixNano)
+		limits        = make(map[uint32]xtime.UnixNano)
+	)
+	for _, shard := range curr.OwnedShards() {
+		if !shard.IsBootstrapped() {
+			continue
+		}
+		shards[shard.ID()] = shard
+		firstUnsealed[shard.ID()] = resizeFirstUnsealed(now, shard, currRetention)
+		limits[shard.ID()] = firstUnsealed[shard.ID()].Truncate(blockSize)
+	}
+
+	d.log.Info("resizing namespace",
+		zap.Stringer("namespace", id),
+		zap.Duration("fromBlockSize", currRetention.BlockSize()),
+		zap.Duration("toBlockSize", blockSize),
+		zap.Duration("retentionPeriod", md.Options().RetentionOptions().RetentionPeriod()))
+
+	if resized {
+		// Convert the blocks that were sealed since the last conversion.
+		if _, err := d.resizer.convert(currRetention.BlockSize(), md, limits); err != nil {
+			return err
+		}
+	}
+
+	d.Lock()
+	carries := make(map[uint32]*resizeCarry, len(shards))
+	for shardID, shard := range shards {
+		carry, err := d.resizer.carry(shard, limits[shardID], firstUnsealed[shardID], curr.Metadata())
+		if err != nil {
+			d.Unlock()
+			return fmt.Errorf("failed to carry over data of shard %d: %v", shardID, err)
+		}
+		carries[shardID] = carry
+	}
+
+	if resized {
+		if err := d.resizer.swap(id, blockSize, indexResized); err != nil {
+			d.Unlock()
+			return err
+		}
+		stagingPrefix := fs.NamespaceResizeFilePathPrefix(
+			d.resizer.filePathPrefix, id, blockSize)
+		if err := os.RemoveAll(stagingPrefix); err != nil {
+			d.Unlock()
+			return err
+		}
+	}
+
+	d.namespaces.Delete(id)
+	if err := d.addNamespacesWithLock([]namespace.Metadata{md}); err != nil {
+		d.Unlock()
+		return err
+	}
+	next, _ := d.namespaces.Get(id)
+
+	var bootstrapResult *BootstrapAsyncResult
+	if d.bootstraps > 0 {
+		bootstrapResult = d.mediator.BootstrapEnqueue()
+		bootstrapResult.WaitForStart()
+	}
+	d.Unlock()
+
+	if err := curr.Close(); err != nil {
+		d.log.Warn("failed to close namespace replaced by resize",
+			zap.Stringer("namespace", id), zap.Error(err))
+	}
+
+	go func() {
+		if bootstrapResult != nil {
+			bootstrapResult.Result()
+		}
+		if err := d.writeResizeCarries(next, carries); err != nil {
+			d.log.Error("failed to write data carried over by resize",
+				zap.Stringer("namespace", id), zap.Error(err))
+		}
+		previousPrefix := fs.NamespaceResizePreviousFilePathPrefix(d.resizer.filePathPrefix, id)
+		if err := os.RemoveAll(previousPrefix); err != nil {
+			d.log.Warn("failed to remove filesets replaced by resize",
+				zap.Stringer("namespace", id), zap.Error(err))
+		}
+		d.log.Info("resized namespace", zap.Stringer("namespace", id))
+	}()
+	return nil
+}
+
+// writeResizeCarries writes the data carried over by a resize to the shards of
+// the resized namespace.
+func (d *db) writeResizeCarries(
+	ns databaseNamespace,
+	carries map[uint32]*resizeCarry,
+) error {
+	var (
+		shards   = make(map[uint32]databaseShard)
+		nsCtx    = namespace.NewContextFrom(ns.Metadata())
+		firstErr error
+		numErrs  int
+		wOpts    = series.WriteOptions{
+			TruncateType:       d.opts.TruncateType(),
+			SchemaDesc:         nsCtx.Schema,
+			BootstrapWrite:     true,
+			SkipOutOfRetention: true,
+		}
+	)
+	for _, shard := range ns.OwnedShards() {
+		shards[shard.ID()] = shard
+	}
+
+	for shardID, carry := range carries {
+		shard, ok := shards[shardID]
+		if !ok {
+			// The shard is no longer owned.
+			continue
+		}
+		for _, key := range carry.ids {
+			var (
+				carried     = carry.series[key]
+				id          = ident.BytesID(carried.metadata.ID)
+				tags        = convert.ToSeriesTags(carried.metadata, convert.Opts{NoClone: true})
+				tagResolver = convert.NewTagsIterMetadataResolver(tags)
+			)
+			for _, dp := range carried.datapoints {
+				ctx := d.opts.ContextPool().Get()
+				seriesWrite, err := shard.WriteTagged(ctx, id, tagResolver,
+					dp.datapoint.TimestampNanos, dp.datapoint.Value, dp.unit, dp.annotation, wOpts)
+				if err == nil && ns.Options().WritesToCommitLog() && seriesWrite.WasWritten {
+					err = d.commitLog.Write(ctx, seriesWrite.Series, dp.datapoint, dp.unit, dp.annotation)
+				}
+				ctx.BlockingClose()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					numErrs++
+				}
+			}
+			tags.Close()
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("failed to write %d datapoints, first error: %v", numErrs, firstErr)
+	}
+	return nil
+}
+
+func (d *db) NamespaceResizeState(id ident.ID) namespace.ResizeState {
+	d.RLock()
+	state := d.resizeStates[id.String()]
+	d.RUnlock()
+	return state
+}
+
 func (d *db) getNamespaceWithLock(id ident.ID) (Namespace, bool) {
 	return d.namespaces.Get(id)
 }
@@ -934,6 +1209,21 @@ func (d *db) AggregateQuery(
 	return n.AggregateQuery(ctx, query, aggResultOpts)
 }
 
+func (d *db) DeleteTagged(
+	ctx context.Context,
+	namespace ident.ID,
+	query index.Query,
+	start, end xtime.UnixNano,
+) (int, error) {
+	n, err := d.namespaceFor(namespace)
+	if err != nil {
+		d.metrics.unknownNamespaceQueryIDs.Inc(1)
+		return 0, err
+	}
+
+	return n.DeleteTagged(ctx, query, start, end)
+}
+
 func (d *db) ReadEncoded(
 	ctx context.Context,
 	namespace ident.ID,
@@ -694,6 +694,51 @@ func TestDatabaseUpdateNamespace(t *testing.T) {
 	require.Nil(t, schema)
 }
 
+func TestDatabaseUpdateNamespaceResizeState(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	d, mapCh, _ := defaultTestDatabase(t, ctrl, Bootstrapped)
+	require.NoError(t, d.Open())
+	defer func() {
+		close(mapCh)
+		require.NoError(t, d.Close())
+		leaktest.CheckTimeout(t, time.Second)()
+	}()
+
+	// retrieve the update channel to track propatation
+	updateCh := d.opts.NamespaceInitializer().(*mockNsInitializer).updateCh
+
+	require.False(t, d.NamespaceResizeState(defaultTestNs1ID).InProgress())
+
+	// construct new namespace Map starting a resize of the first namespace
+	state := namespace.ResizeState{
+		BlockSize:      2 * defaultTestNs1Opts.RetentionOptions().BlockSize(),
+		IndexBlockSize: 2 * defaultTestNs1Opts.RetentionOptions().BlockSize(),
+	}
+	md1, err := namespace.NewMetadata(defaultTestNs1ID, defaultTestNs1Opts.SetResizeState(state))
+	require.NoError(t, err)
+	md2, err := namespace.NewMetadata(defaultTestNs2ID, defaultTestNs2Opts)
+	require.NoError(t, err)
+	nsMap, err := namespace.NewMap([]namespace.Metadata{md1, md2})
+	require.NoError(t, err)
+
+	// update the database watch with new Map
+	mapCh <- nsMap
+
+	// wait till the update has propagated
+	<-updateCh
+	<-updateCh
+	time.Sleep(10 * time.Millisecond)
+
+	// the resize state is tracked without replacing the namespace
+	require.Equal(t, state, d.NamespaceResizeState(defaultTestNs1ID))
+	require.False(t, d.NamespaceResizeState(defaultTestNs2ID).InProgress())
+	ns1, ok := d.Namespace(defaultTestNs1ID)
+	require.True(t, ok)
+	require.Equal(t, defaultTestNs1Opts, ns1.Options())
+}
+
 func TestDatabaseCreateSchemaNotSet(t *testing.T) {
 	protoTestDatabaseOptions := DefaultTestOptions().
 		SetSchemaRegistry(namespace.NewSchemaRegistry(true, nil))
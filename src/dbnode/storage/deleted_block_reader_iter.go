@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// deletedBlockReaderIter wraps a series.BlockReaderIter to drop the data of
+// a series within its deleted time ranges, blocks with no deleted data are
+// returned as is and blocks with all of their data deleted are skipped.
+type deletedBlockReaderIter struct {
+	iter      series.BlockReaderIter
+	deleted   []xtime.Range
+	blockSize time.Duration
+	opts      Options
+	nsCtx     namespace.Context
+
+	curr []xio.BlockReader
+	err  error
+}
+
+func newDeletedBlockReaderIter(
+	iter series.BlockReaderIter,
+	deleted []xtime.Range,
+	blockSize time.Duration,
+	opts Options,
+	nsCtx namespace.Context,
+) series.BlockReaderIter {
+	return &deletedBlockReaderIter{
+		iter:      iter,
+		deleted:   deleted,
+		blockSize: blockSize,
+		opts:      opts,
+		nsCtx:     nsCtx,
+	}
+}
+
+func (i *deletedBlockReaderIter) Next(ctx context.Context) bool {
+	if i.err != nil {
+		return false
+	}
+
+	for i.iter.Next(ctx) {
+		readers := i.iter.Current()
+		blockStart := readers[0].Start
+		blockRange := xtime.Range{Start: blockStart, End: blockStart.Add(i.blockSize)}
+		if !i.overlaps(blockRange) {
+			i.curr = readers
+			return true
+		}
+
+		reader, ok, err := i.filter(ctx, readers, blockStart)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if ok {
+			i.curr = []xio.BlockReader{reader}
+			return true
+		}
+	}
+
+	i.err = i.iter.Err()
+	return false
+}
+
+func (i *deletedBlockReaderIter) Current() []xio.BlockReader {
+	return i.curr
+}
+
+func (i *deletedBlockReaderIter) Err() error {
+	return i.err
+}
+
+func (i *deletedBlockReaderIter) ToSlices(ctx context.Context) ([][]xio.BlockReader, error) {
+	var results [][]xio.BlockReader
+	for i.Next(ctx) {
+		results = append(results, i.Current())
+	}
+	if i.Err() != nil {
+		return nil, i.Err()
+	}
+	return results, nil
+}
+
+func (i *deletedBlockReaderIter) overlaps(r xtime.Range) bool {
+	for _, deleted := range i.deleted {
+		if deleted.Overlaps(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *deletedBlockReaderIter) isDeleted(t xtime.UnixNano) bool {
+	for _, deleted := range i.deleted {
+		if !t.Before(deleted.Start) && t.Before(deleted.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter merges the block readers of a block into a single block reader
+// without the deleted data, returning false if all of the data was deleted.
+func (i *deletedBlockReaderIter) filter(
+	ctx context.Context,
+	readers []xio.BlockReader,
+	blockStart xtime.UnixNano,
+) (xio.BlockReader, bool, error) {
+	segReaders := make([]xio.SegmentReader, 0, len(readers))
+	for _, reader := range readers {
+		segReaders = append(segReaders, reader.SegmentReader)
+	}
+
+	iter := i.opts.MultiReaderIteratorPool().Get()
+	defer iter.Close()
+	iter.Reset(segReaders, blockStart, i.blockSize, i.nsCtx.Schema)
+
+	encoder := i.opts.EncoderPool().Get()
+	encoder.Reset(blockStart, i.opts.DatabaseBlockOptions().DatabaseBlockAllocSize(), i.nsCtx.Schema)
+	for iter.Next() {
+		dp, unit, annotation := iter.Current()
+		if i.isDeleted(dp.TimestampNanos) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
+			encoder.Close()
+			return xio.BlockReader{}, false, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		encoder.Close()
+		return xio.BlockReader{}, false, err
+	}
+
+	stream, ok := encoder.Stream(ctx)
+	if !ok {
+		encoder.Close()
+		return xio.BlockReader{}, false, nil
+	}
+
+	// NB: the stream references the encoder's data so only close the
+	// encoder once the caller is done with the stream.
+	ctx.RegisterFinalizer(stream)
+	ctx.RegisterCloser(encoder)
+	return xio.BlockReader{
+		SegmentReader: stream,
+		Start:         blockStart,
+		BlockSize:     i.blockSize,
+	}, true, nil
+}
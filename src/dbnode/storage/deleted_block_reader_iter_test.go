@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/context"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletedBlockReaderIter(t *testing.T) {
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	var (
+		opts      = DefaultTestOptions()
+		blockSize = time.Hour
+		start     = xtime.Now().Truncate(blockSize)
+	)
+	newBlockReader := func(blockStart xtime.UnixNano, offsets ...time.Duration) xio.BlockReader {
+		encoder := opts.EncoderPool().Get()
+		encoder.Reset(blockStart, 0, nil)
+		for i, offset := range offsets {
+			require.NoError(t, encoder.Encode(ts.Datapoint{
+				TimestampNanos: blockStart.Add(offset),
+				Value:          float64(i),
+			}, xtime.Second, nil))
+		}
+		return xio.BlockReader{
+			SegmentReader: xio.NewSegmentReader(encoder.Discard()),
+			Start:         blockStart,
+			BlockSize:     blockSize,
+		}
+	}
+
+	iter := newDeletedBlockReaderIter(&series.FakeBlockReaderIter{
+		Readers: [][]xio.BlockReader{
+			// Partially deleted block merged from multiple readers.
+			{
+				newBlockReader(start, time.Minute, 20*time.Minute),
+				newBlockReader(start, 10*time.Minute, 30*time.Minute),
+			},
+			// Fully deleted block.
+			{newBlockReader(start.Add(blockSize), time.Minute)},
+			// Block with no deleted data.
+			{newBlockReader(start.Add(2*blockSize), time.Minute)},
+		},
+	}, []xtime.Range{
+		{Start: start.Add(5 * time.Minute), End: start.Add(20 * time.Minute)},
+		{Start: start.Add(30 * time.Minute), End: start.Add(2 * blockSize)},
+	}, blockSize, opts, namespace.Context{})
+
+	var results [][]ts.Datapoint
+	for iter.Next(ctx) {
+		var (
+			readers = iter.Current()
+			dpIter  = opts.MultiReaderIteratorPool().Get()
+			dps     []ts.Datapoint
+		)
+		segReaders := make([]xio.SegmentReader, 0, len(readers))
+		for _, reader := range readers {
+			segReaders = append(segReaders, reader.SegmentReader)
+		}
+		dpIter.Reset(segReaders, readers[0].Start, blockSize, nil)
+		for dpIter.Next() {
+			dp, _, _ := dpIter.Current()
+			dps = append(dps, ts.Datapoint{TimestampNanos: dp.TimestampNanos, Value: dp.Value})
+		}
+		require.NoError(t, dpIter.Err())
+		dpIter.Close()
+		results = append(results, dps)
+	}
+	require.NoError(t, iter.Err())
+
+	require.Equal(t, [][]ts.Datapoint{
+		{
+			{TimestampNanos: start.Add(time.Minute), Value: 0},
+			{TimestampNanos: start.Add(20 * time.Minute), Value: 1},
+		},
+		{
+			{TimestampNanos: start.Add(2*blockSize + time.Minute), Value: 0},
+		},
+	}, results)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exemplar
+
+import (
+	"sync"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const noEntry = -1
+
+type seriesEntries struct {
+	id     string
+	oldest int
+	newest int
+}
+
+type entry struct {
+	exemplar Exemplar
+	series   *seriesEntries
+	next     int
+}
+
+// store is a circular buffer of exemplars with each series linking its
+// exemplars from oldest to newest, evicting the oldest entry in the buffer
+// also always evicts the oldest exemplar of the series that owns it.
+type store struct {
+	sync.RWMutex
+
+	entries   []entry
+	series    map[string]*seriesEntries
+	nextEntry int
+	size      int
+}
+
+// NewStore returns a new exemplar store holding up to maxExemplars, a
+// non-positive value drops all exemplars written.
+func NewStore(maxExemplars int) Store {
+	if maxExemplars < 0 {
+		maxExemplars = 0
+	}
+	return &store{
+		entries: make([]entry, maxExemplars),
+		series:  make(map[string]*seriesEntries),
+	}
+}
+
+func (s *store) Write(id []byte, exemplars []Exemplar) int {
+	if len(s.entries) == 0 {
+		return len(exemplars)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	dropped := 0
+	for _, e := range exemplars {
+		series, ok := s.series[string(id)]
+		if ok && !e.Timestamp.After(s.entries[series.newest].exemplar.Timestamp) {
+			dropped++
+			continue
+		}
+
+		s.evict(s.nextEntry)
+
+		// NB: re-lookup the series since evicting may have removed its last
+		// exemplar and so the series itself.
+		series, ok = s.series[string(id)]
+		if !ok {
+			series = &seriesEntries{id: string(id), oldest: s.nextEntry}
+			s.series[series.id] = series
+		} else {
+			s.entries[series.newest].next = s.nextEntry
+		}
+		series.newest = s.nextEntry
+		s.entries[s.nextEntry] = entry{
+			exemplar: Exemplar{
+				Labels:    append([]Label(nil), e.Labels...),
+				Value:     e.Value,
+				Timestamp: e.Timestamp,
+			},
+			series: series,
+			next:   noEntry,
+		}
+		s.nextEntry = (s.nextEntry + 1) % len(s.entries)
+		s.size++
+	}
+
+	return dropped
+}
+
+func (s *store) evict(i int) {
+	series := s.entries[i].series
+	if series == nil {
+		return
+	}
+
+	if next := s.entries[i].next; next == noEntry {
+		delete(s.series, series.id)
+	} else {
+		series.oldest = next
+	}
+	s.entries[i] = entry{}
+	s.size--
+}
+
+func (s *store) Fetch(id []byte, start, end xtime.UnixNano) []Exemplar {
+	s.RLock()
+	defer s.RUnlock()
+
+	series, ok := s.series[string(id)]
+	if !ok {
+		return nil
+	}
+
+	var result []Exemplar
+	for i := series.oldest; i != noEntry; i = s.entries[i].next {
+		e := s.entries[i].exemplar
+		if e.Timestamp.Before(start) {
+			continue
+		}
+		if e.Timestamp.After(end) {
+			break
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func (s *store) Len() int {
+	s.RLock()
+	n := s.size
+	s.RUnlock()
+	return n
+}
+
+type namespaceStores struct {
+	sync.RWMutex
+
+	maxExemplars int
+	stores       map[string]Store
+}
+
+// NewNamespaceStores returns a new set of namespace stores that each hold
+// up to maxExemplars.
+func NewNamespaceStores(maxExemplars int) NamespaceStores {
+	return &namespaceStores{
+		maxExemplars: maxExemplars,
+		stores:       make(map[string]Store),
+	}
+}
+
+func (n *namespaceStores) Store(namespace []byte) Store {
+	if store, ok := n.Lookup(namespace); ok {
+		return store
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	store, ok := n.stores[string(namespace)]
+	if !ok {
+		store = NewStore(n.maxExemplars)
+		n.stores[string(namespace)] = store
+	}
+	return store
+}
+
+func (n *namespaceStores) Lookup(namespace []byte) (Store, bool) {
+	n.RLock()
+	store, ok := n.stores[string(namespace)]
+	n.RUnlock()
+	return store, ok
+}
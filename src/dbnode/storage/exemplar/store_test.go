@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package exemplar
+
+import (
+	"testing"
+
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testExemplar(ts int64, traceID string) Exemplar {
+	return Exemplar{
+		Labels:    []Label{{Name: "trace_id", Value: traceID}},
+		Value:     float64(ts),
+		Timestamp: xtime.UnixNano(ts),
+	}
+}
+
+func TestStoreWriteFetch(t *testing.T) {
+	s := NewStore(10)
+
+	dropped := s.Write([]byte("foo"), []Exemplar{
+		testExemplar(1, "a"),
+		testExemplar(2, "b"),
+		testExemplar(3, "c"),
+	})
+	assert.Equal(t, 0, dropped)
+	dropped = s.Write([]byte("bar"), []Exemplar{testExemplar(2, "d")})
+	assert.Equal(t, 0, dropped)
+
+	assert.Equal(t, 4, s.Len())
+	assert.Equal(t, []Exemplar{
+		testExemplar(2, "b"),
+		testExemplar(3, "c"),
+	}, s.Fetch([]byte("foo"), 2, 5))
+	assert.Equal(t, []Exemplar{testExemplar(2, "d")},
+		s.Fetch([]byte("bar"), 0, 10))
+	assert.Nil(t, s.Fetch([]byte("foo"), 4, 10))
+	assert.Nil(t, s.Fetch([]byte("baz"), 0, 10))
+}
+
+func TestStoreDropsOutOfOrderAndDuplicates(t *testing.T) {
+	s := NewStore(10)
+
+	dropped := s.Write([]byte("foo"), []Exemplar{
+		testExemplar(2, "a"),
+		testExemplar(2, "a"),
+		testExemplar(1, "b"),
+		testExemplar(3, "c"),
+	})
+	assert.Equal(t, 2, dropped)
+	assert.Equal(t, 2, s.Len())
+	assert.Equal(t, []Exemplar{
+		testExemplar(2, "a"),
+		testExemplar(3, "c"),
+	}, s.Fetch([]byte("foo"), 0, 10))
+}
+
+func TestStoreEvictsOldest(t *testing.T) {
+	s := NewStore(3)
+
+	s.Write([]byte("foo"), []Exemplar{testExemplar(1, "a")})
+	s.Write([]byte("bar"), []Exemplar{testExemplar(1, "b")})
+	s.Write([]byte("foo"), []Exemplar{testExemplar(2, "c")})
+	s.Write([]byte("baz"), []Exemplar{testExemplar(1, "d")})
+	require.Equal(t, 3, s.Len())
+	assert.Equal(t, []Exemplar{testExemplar(2, "c")},
+		s.Fetch([]byte("foo"), 0, 10))
+
+	// Evicting the only exemplar of a series removes the series.
+	s.Write([]byte("foo"), []Exemplar{testExemplar(3, "e")})
+	require.Equal(t, 3, s.Len())
+	assert.Nil(t, s.Fetch([]byte("bar"), 0, 10))
+	assert.Equal(t, []Exemplar{
+		testExemplar(2, "c"),
+		testExemplar(3, "e"),
+	}, s.Fetch([]byte("foo"), 0, 10))
+
+	// A series can evict its own exemplars once it fills the store.
+	s.Write([]byte("foo"), []Exemplar{
+		testExemplar(4, "f"),
+		testExemplar(5, "g"),
+		testExemplar(6, "h"),
+	})
+	require.Equal(t, 3, s.Len())
+	assert.Nil(t, s.Fetch([]byte("baz"), 0, 10))
+	assert.Equal(t, []Exemplar{
+		testExemplar(4, "f"),
+		testExemplar(5, "g"),
+		testExemplar(6, "h"),
+	}, s.Fetch([]byte("foo"), 0, 10))
+}
+
+func TestStoreZeroSizeDropsAll(t *testing.T) {
+	s := NewStore(0)
+
+	dropped := s.Write([]byte("foo"), []Exemplar{testExemplar(1, "a")})
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, 0, s.Len())
+	assert.Nil(t, s.Fetch([]byte("foo"), 0, 10))
+}
+
+func TestNamespaceStores(t *testing.T) {
+	stores := NewNamespaceStores(10)
+
+	_, ok := stores.Lookup([]byte("ns"))
+	require.False(t, ok)
+
+	store := stores.Store([]byte("ns"))
+	store.Write([]byte("foo"), []Exemplar{testExemplar(1, "a")})
+
+	lookup, ok := stores.Lookup([]byte("ns"))
+	require.True(t, ok)
+	assert.Equal(t, 1, lookup.Len())
+	assert.Equal(t, store, stores.Store([]byte("ns")))
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package exemplar contains a bounded in-memory store of exemplars.
+package exemplar
+
+import (
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// DefaultMaxExemplars is the default maximum number of exemplars held
+// per namespace.
+const DefaultMaxExemplars = 100000
+
+// Label is a name value pair attached to an exemplar, such as a trace ID.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Exemplar is a sample of a series annotated with labels.
+type Exemplar struct {
+	Labels    []Label
+	Value     float64
+	Timestamp xtime.UnixNano
+}
+
+// Store is a fixed size store of exemplars keyed by series ID, once full the
+// oldest exemplars written are evicted first.
+type Store interface {
+	// Write adds exemplars to a series and returns the number of exemplars
+	// dropped for being out of order or duplicates of the newest exemplar.
+	Write(id []byte, exemplars []Exemplar) int
+
+	// Fetch returns the exemplars of a series with timestamps within the
+	// inclusive range [start, end] in timestamp order.
+	Fetch(id []byte, start, end xtime.UnixNano) []Exemplar
+
+	// Len returns the number of exemplars held.
+	Len() int
+}
+
+// NamespaceStores holds a store per namespace.
+type NamespaceStores interface {
+	// Store returns the store for a namespace, creating it if required.
+	Store(namespace []byte) Store
+
+	// Lookup returns the store for a namespace if one has been created.
+	Lookup(namespace []byte) (Store, bool)
+}
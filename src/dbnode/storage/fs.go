@@ -66,7 +66,12 @@ type fileOpState struct {
 	// cold version that has been flushed and to validate lease requests from the SeekerManager when it
 	// receives a signal to open a new lease.
 	ColdVersionFlushed int
-	NumFailures        int
+	// LateVersion keeps track of data persistence for LateWrites only. Each
+	// block can have late writes flushed to multiple late data sidecar
+	// volumes, this tracks the latest volume that has been flushed and can
+	// be queried via the shard's late fileset seekers.
+	LateVersion int
+	NumFailures int
 }
 
 type forceType int
@@ -21,11 +21,15 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/context"
 	"github.com/m3db/m3/src/x/ident"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -42,6 +46,8 @@ type fsMergeWithMem struct {
 	retriever          series.QueryableBlockRetriever
 	dirtySeries        *dirtySeriesMap
 	dirtySeriesToWrite map[xtime.UnixNano]*idList
+	tombstones         tombstone.Store
+	blockSize          time.Duration
 	reusableID         *ident.ReusableBytesID
 }
 
@@ -50,12 +56,16 @@ func newFSMergeWithMem(
 	retriever series.QueryableBlockRetriever,
 	dirtySeries *dirtySeriesMap,
 	dirtySeriesToWrite map[xtime.UnixNano]*idList,
+	tombstones tombstone.Store,
+	blockSize time.Duration,
 ) fs.MergeWith {
 	return &fsMergeWithMem{
 		shard:              shard,
 		retriever:          retriever,
 		dirtySeries:        dirtySeries,
 		dirtySeriesToWrite: dirtySeriesToWrite,
+		tombstones:         tombstones,
+		blockSize:          blockSize,
 		reusableID:         ident.NewReusableBytesID(),
 	}
 }
@@ -143,3 +153,111 @@ func (m *fsMergeWithMem) ForEachRemaining(
 
 	return nil
 }
+
+func (m *fsMergeWithMem) DeletedRanges(
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+) []xtime.Range {
+	if m.tombstones == nil || m.tombstones.Len() == 0 {
+		return nil
+	}
+
+	var (
+		block   = xtime.Range{Start: blockStart, End: blockStart.Add(m.blockSize)}
+		deleted []xtime.Range
+	)
+	for _, r := range m.tombstones.Ranges(seriesID.Bytes()) {
+		if r.Overlaps(block) {
+			deleted = append(deleted, r)
+		}
+	}
+	return deleted
+}
+
+// fsMergeWithLate implements fs.MergeWith, where the merge target is data in
+// memory as well as the data of the late data sidecar volumes of a block. It
+// keeps track of the series that had late data merged so that blocks of them
+// cached from the fileset that is being replaced can be evicted.
+type fsMergeWithLate struct {
+	mem     fs.MergeWith
+	late    fs.MergeWith
+	lateIDs []ident.ID
+}
+
+func newFSMergeWithLate(mem fs.MergeWith, late fs.MergeWith) *fsMergeWithLate {
+	return &fsMergeWithLate{
+		mem:  mem,
+		late: late,
+	}
+}
+
+func (m *fsMergeWithLate) Read(
+	ctx context.Context,
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, bool, error) {
+	memReaders, memOk, err := m.mem.Read(ctx, seriesID, blockStart, nsCtx)
+	if err != nil {
+		return nil, false, err
+	}
+	lateReaders, lateOk, err := m.readLate(ctx, seriesID.Bytes(), blockStart, nsCtx)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(memReaders, lateReaders...), memOk || lateOk, nil
+}
+
+func (m *fsMergeWithLate) readLate(
+	ctx context.Context,
+	id []byte,
+	blockStart xtime.UnixNano,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, bool, error) {
+	readers, ok, err := m.late.Read(ctx, ident.BytesID(id), blockStart, nsCtx)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	m.lateIDs = append(m.lateIDs, ident.BytesID(append([]byte(nil), id...)))
+	return readers, true, nil
+}
+
+func (m *fsMergeWithLate) ForEachRemaining(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	fn fs.ForEachRemainingFn,
+	nsCtx namespace.Context,
+) error {
+	err := m.mem.ForEachRemaining(ctx, blockStart, func(
+		seriesMetadata doc.Metadata,
+		data block.FetchBlockResult,
+	) error {
+		lateReaders, ok, err := m.readLate(ctx, seriesMetadata.ID, blockStart, nsCtx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			data.Blocks = append(data.Blocks, lateReaders...)
+		}
+		return fn(seriesMetadata, data)
+	}, nsCtx)
+	if err != nil {
+		return err
+	}
+
+	// The remaining series only have late data.
+	return m.late.ForEachRemaining(ctx, blockStart, func(
+		seriesMetadata doc.Metadata,
+		data block.FetchBlockResult,
+	) error {
+		m.lateIDs = append(m.lateIDs, ident.BytesID(append([]byte(nil), seriesMetadata.ID...)))
+		return fn(seriesMetadata, data)
+	}, nsCtx)
+}
+
+func (m *fsMergeWithLate) DeletedRanges(
+	seriesID ident.ID,
+	blockStart xtime.UnixNano,
+) []xtime.Range {
+	return m.mem.DeletedRanges(seriesID, blockStart)
+}
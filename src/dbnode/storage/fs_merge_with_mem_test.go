@@ -23,10 +23,13 @@ package storage
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/context"
@@ -81,7 +84,7 @@ func TestRead(t *testing.T) {
 			Return(result, nil)
 	}
 
-	mergeWith := newFSMergeWithMem(shard, retriever, dirtySeries, dirtySeriesToWrite)
+	mergeWith := newFSMergeWithMem(shard, retriever, dirtySeries, dirtySeriesToWrite, nil, time.Hour)
 
 	for _, d := range data {
 		require.True(t, dirtySeries.Contains(idAndBlockStart{
@@ -170,7 +173,7 @@ func TestForEachRemaining(t *testing.T) {
 		addDirtySeries(dirtySeries, dirtySeriesToWrite, d.id, d.start)
 	}
 
-	mergeWith := newFSMergeWithMem(shard, retriever, dirtySeries, dirtySeriesToWrite)
+	mergeWith := newFSMergeWithMem(shard, retriever, dirtySeries, dirtySeriesToWrite, nil, time.Hour)
 
 	var forEachCalls []doc.Metadata
 	shard.EXPECT().
@@ -232,6 +235,116 @@ func TestForEachRemaining(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDeletedRanges(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	shard := NewMockdatabaseShard(ctrl)
+	retriever := series.NewMockQueryableBlockRetriever(ctrl)
+	tombstones, err := tombstone.NewStore(tombstone.Options{BlockSize: time.Hour})
+	require.NoError(t, err)
+
+	var (
+		start   = xtime.Now().Truncate(time.Hour)
+		deleted = []xtime.Range{
+			{Start: start.Add(-time.Hour), End: start.Add(10 * time.Minute)},
+			{Start: start.Add(20 * time.Minute), End: start.Add(30 * time.Minute)},
+			{Start: start.Add(90 * time.Minute), End: start.Add(2 * time.Hour)},
+		}
+	)
+	for _, r := range deleted {
+		require.NoError(t, tombstones.Add([]tombstone.Tombstone{{ID: []byte("id0"), Range: r}}))
+	}
+
+	mergeWith := newFSMergeWithMem(shard, retriever, newDirtySeriesMap(),
+		make(map[xtime.UnixNano]*idList), tombstones, time.Hour)
+
+	assert.Equal(t, deleted[:2], mergeWith.DeletedRanges(ident.StringID("id0"), start))
+	assert.Equal(t, deleted[2:], mergeWith.DeletedRanges(ident.StringID("id0"), start.Add(time.Hour)))
+	assert.Empty(t, mergeWith.DeletedRanges(ident.StringID("id0"), start.Add(2*time.Hour)))
+	assert.Empty(t, mergeWith.DeletedRanges(ident.StringID("id1"), start))
+}
+
+func TestMergeWithLate(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		ctx           = context.NewBackground()
+		nsCtx         = namespace.Context{}
+		start         = xtime.Now().Truncate(time.Hour)
+		mem           = fs.NewMockMergeWith(ctrl)
+		late          = fs.NewMockMergeWith(ctrl)
+		memReader     = xio.BlockReader{Start: start}
+		lateReader    = xio.BlockReader{Start: start, BlockSize: time.Hour}
+		memAndLate    = ident.StringID("mem-and-late")
+		memOnly       = ident.StringID("mem-only")
+		lateOnly      = ident.StringID("late-only")
+		memRemaining  = doc.Metadata{ID: []byte("remaining")}
+		lateRemaining = doc.Metadata{ID: []byte("remaining-late")}
+	)
+	mergeWith := newFSMergeWithLate(mem, late)
+
+	mem.EXPECT().Read(ctx, memAndLate, start, nsCtx).
+		Return([]xio.BlockReader{memReader}, true, nil)
+	late.EXPECT().Read(ctx, ident.BytesID(memAndLate.Bytes()), start, nsCtx).
+		Return([]xio.BlockReader{lateReader}, true, nil)
+	readers, ok, err := mergeWith.Read(ctx, memAndLate, start, nsCtx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []xio.BlockReader{memReader, lateReader}, readers)
+
+	mem.EXPECT().Read(ctx, memOnly, start, nsCtx).
+		Return([]xio.BlockReader{memReader}, true, nil)
+	late.EXPECT().Read(ctx, ident.BytesID(memOnly.Bytes()), start, nsCtx).
+		Return(nil, false, nil)
+	readers, ok, err = mergeWith.Read(ctx, memOnly, start, nsCtx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []xio.BlockReader{memReader}, readers)
+
+	mem.EXPECT().Read(ctx, lateOnly, start, nsCtx).Return(nil, false, nil)
+	late.EXPECT().Read(ctx, ident.BytesID(lateOnly.Bytes()), start, nsCtx).
+		Return([]xio.BlockReader{lateReader}, true, nil)
+	readers, ok, err = mergeWith.Read(ctx, lateOnly, start, nsCtx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []xio.BlockReader{lateReader}, readers)
+
+	// Series remaining in memory have their late data merged, the series
+	// only remaining in the late data sidecar volumes are looped through last.
+	mem.EXPECT().ForEachRemaining(ctx, start, gomock.Any(), nsCtx).DoAndReturn(
+		func(_ context.Context, _ xtime.UnixNano, fn fs.ForEachRemainingFn, _ namespace.Context) error {
+			return fn(memRemaining, block.FetchBlockResult{Start: start, Blocks: []xio.BlockReader{memReader}})
+		})
+	late.EXPECT().Read(ctx, ident.BytesID(memRemaining.ID), start, nsCtx).
+		Return([]xio.BlockReader{lateReader}, true, nil)
+	late.EXPECT().ForEachRemaining(ctx, start, gomock.Any(), nsCtx).DoAndReturn(
+		func(_ context.Context, _ xtime.UnixNano, fn fs.ForEachRemainingFn, _ namespace.Context) error {
+			return fn(lateRemaining, block.FetchBlockResult{Start: start, Blocks: []xio.BlockReader{lateReader}})
+		})
+	var results []block.FetchBlockResult
+	err = mergeWith.ForEachRemaining(ctx, start, func(_ doc.Metadata, data block.FetchBlockResult) error {
+		results = append(results, data)
+		return nil
+	}, nsCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []block.FetchBlockResult{
+		{Start: start, Blocks: []xio.BlockReader{memReader, lateReader}},
+		{Start: start, Blocks: []xio.BlockReader{lateReader}},
+	}, results)
+
+	var lateIDs []string
+	for _, id := range mergeWith.lateIDs {
+		lateIDs = append(lateIDs, id.String())
+	}
+	assert.Equal(t, []string{"mem-and-late", "late-only", "remaining", "remaining-late"}, lateIDs)
+
+	deleted := []xtime.Range{{Start: start, End: start.Add(time.Minute)}}
+	mem.EXPECT().DeletedRanges(memOnly, start).Return(deleted)
+	assert.Equal(t, deleted, mergeWith.DeletedRanges(memOnly, start))
+}
+
 func addDirtySeries(
 	dirtySeries *dirtySeriesMap,
 	dirtySeriesToWrite map[xtime.UnixNano]*idList,
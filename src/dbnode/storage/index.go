@@ -47,6 +47,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/limits"
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -137,6 +138,7 @@ type nsIndex struct {
 
 	doNotIndexWithFields []doc.Field
 	shardSet             sharding.ShardSet
+	tombstones           tombstone.Store
 }
 
 type nsIndexState struct {
@@ -196,6 +198,7 @@ type newNamespaceIndexOpts struct {
 	md                      namespace.Metadata
 	namespaceRuntimeOptsMgr namespace.RuntimeOptionsManager
 	shardSet                sharding.ShardSet
+	tombstones              tombstone.Store
 	opts                    Options
 	newIndexQueueFn         newNamespaceIndexInsertQueueFn
 	newBlockFn              index.NewBlockFn
@@ -253,12 +256,14 @@ func newNamespaceIndex(
 	nsMD namespace.Metadata,
 	namespaceRuntimeOptsMgr namespace.RuntimeOptionsManager,
 	shardSet sharding.ShardSet,
+	tombstones tombstone.Store,
 	opts Options,
 ) (NamespaceIndex, error) {
 	return newNamespaceIndexWithOptions(newNamespaceIndexOpts{
 		md:                      nsMD,
 		namespaceRuntimeOptsMgr: namespaceRuntimeOptsMgr,
 		shardSet:                shardSet,
+		tombstones:              tombstones,
 		opts:                    opts,
 		newIndexQueueFn:         newNamespaceIndexInsertQueue,
 		newBlockFn:              index.NewBlock,
@@ -375,6 +380,7 @@ func newNamespaceIndexWithOptions(
 
 		doNotIndexWithFields: doNotIndexWithFields,
 		shardSet:             shardSet,
+		tombstones:           newIndexOpts.tombstones,
 	}
 
 	// Assign shard set upfront.
@@ -968,7 +974,7 @@ func (i *nsIndex) WarmFlush(
 
 	var evicted int
 	for _, block := range flushable {
-		immutableSegments, err := i.flushBlock(flush, block, shards, builder)
+		persistedSegments, err := i.flushBlock(flush, block, shards, builder)
 		if err != nil {
 			return err
 		}
@@ -978,11 +984,6 @@ func (i *nsIndex) WarmFlush(
 			dbShards(shards).IDs()...)
 
 		// Add the results to the block.
-		persistedSegments := make([]result.Segment, 0, len(immutableSegments))
-		for _, elem := range immutableSegments {
-			persistedSegment := result.NewSegment(elem, true)
-			persistedSegments = append(persistedSegments, persistedSegment)
-		}
 		blockResult := result.NewIndexBlock(persistedSegments, fulfilled)
 		results := result.NewIndexBlockByVolumeType(block.StartTime())
 		results.SetBlock(idxpersist.DefaultIndexVolumeType, blockResult)
@@ -1147,7 +1148,7 @@ func (i *nsIndex) flushBlock(
 	indexBlock index.Block,
 	shards []databaseShard,
 	builder segment.DocumentsBuilder,
-) ([]segment.Segment, error) {
+) ([]result.Segment, error) {
 	allShards := make(map[uint32]struct{})
 	for _, shard := range shards {
 		// Populate all shards
@@ -1194,7 +1195,17 @@ func (i *nsIndex) flushBlock(
 	closed = true
 
 	// Now return the immutable segments
-	return preparedPersist.Close()
+	segments, err := preparedPersist.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	persistedSegments := make([]result.Segment, 0, len(segments))
+	for i, segment := range segments {
+		persistedSegments = append(persistedSegments,
+			result.NewPersistedSegment(segment, volumeIndex, i))
+	}
+	return persistedSegments, nil
 }
 
 func (i *nsIndex) flushBlockSegment(
@@ -1213,6 +1224,7 @@ func (i *nsIndex) flushBlockSegment(
 	ctx := i.opts.ContextPool().Get()
 	defer ctx.Close()
 
+	blockRange := xtime.Range{Start: indexBlock.StartTime(), End: indexBlock.EndTime()}
 	for _, shard := range shards {
 		var (
 			first     = true
@@ -1245,6 +1257,12 @@ func (i *nsIndex) flushBlockSegment(
 			// Reset docs batch before use.
 			batch.Docs = batch.Docs[:0]
 			for _, result := range results.Results() {
+				if i.isDeleted(result.ID, blockRange) {
+					// Skip series deleted for the whole block which may not
+					// have been dropped from the data filesets yet.
+					continue
+				}
+
 				doc, exists, err := shard.DocRef(result.ID)
 				if err != nil {
 					return err
@@ -1340,6 +1358,41 @@ func (i *nsIndex) shardsFilterID() func(id ident.ID) bool {
 	return v
 }
 
+// queryFilterID returns the filter of query results for a time range, which
+// excludes series not owned by the assigned shards and series deleted over
+// the whole time range.
+func (i *nsIndex) queryFilterID(start, end xtime.UnixNano) func(id ident.ID) bool {
+	shardsFilterID := i.shardsFilterID()
+	if i.tombstones == nil || i.tombstones.Len() == 0 {
+		return shardsFilterID
+	}
+
+	queryRange := xtime.Range{Start: start, End: end}
+	return func(id ident.ID) bool {
+		if shardsFilterID != nil && !shardsFilterID(id) {
+			return false
+		}
+		return !i.tombstones.Covers(id.Bytes(), queryRange)
+	}
+}
+
+func (i *nsIndex) isDeleted(id ident.ID, r xtime.Range) bool {
+	return i.tombstones != nil && i.tombstones.Covers(id.Bytes(), r)
+}
+
+func (i *nsIndex) blockOptions(blockStart xtime.UnixNano) index.BlockOptions {
+	if i.tombstones == nil {
+		return index.BlockOptions{}
+	}
+
+	blockRange := xtime.Range{Start: blockStart, End: blockStart.Add(i.blockSize)}
+	return index.BlockOptions{
+		DeletedIDsFn: func() [][]byte {
+			return i.tombstones.IDs(blockRange)
+		},
+	}
+}
+
 func (i *nsIndex) shardForID() func(id ident.ID) (uint32, bool) {
 	i.state.RLock()
 	v := i.state.shardFilteredForID
@@ -1369,7 +1422,7 @@ func (i *nsIndex) Query(
 	results := i.resultsPool.Get()
 	results.Reset(i.nsMetadata.ID(), index.QueryResultsOptions{
 		SizeLimit: opts.SeriesLimit,
-		FilterID:  i.shardsFilterID(),
+		FilterID:  i.queryFilterID(opts.StartInclusive, opts.EndExclusive),
 	})
 	ctx.RegisterFinalizer(results)
 	queryRes, err := i.query(ctx, query, results, opts, i.execBlockQueryFn,
@@ -2006,7 +2059,7 @@ func (i *nsIndex) ensureBlockPresentWithRLock(blockStart xtime.UnixNano) (index.
 
 	// ok now we know for sure we have to alloc
 	block, err := i.newBlockFn(blockStart, i.nsMetadata,
-		index.BlockOptions{}, i.namespaceRuntimeOptsMgr, i.opts.IndexOptions())
+		i.blockOptions(blockStart), i.namespaceRuntimeOptsMgr, i.opts.IndexOptions())
 	if err != nil { // unable to allocate the block, should never happen.
 		return nil, i.unableToAllocBlockInvariantError(err)
 	}
@@ -2266,6 +2319,169 @@ func (i *nsIndex) CleanupDuplicateFileSets(activeShards []uint32) error {
 	return multiErr.FinalError()
 }
 
+func (i *nsIndex) Cardinality(
+	ctx context.Context,
+	opts index.CardinalityQueryOptions,
+) (index.CardinalityStats, error) {
+	_, sp := ctx.StartTraceSpan(tracepoint.NSIdxCardinality)
+	defer sp.Finish()
+
+	var (
+		queryFilterID = i.queryFilterID(opts.StartInclusive, opts.EndExclusive)
+		shardForID    = i.shardForID()
+		shards        = make(map[uint32]struct{}, len(opts.Shards))
+	)
+	for _, shard := range opts.Shards {
+		shards[shard] = struct{}{}
+	}
+	filterID := func(id ident.ID) bool {
+		if queryFilterID != nil && !queryFilterID(id) {
+			return false
+		}
+		if len(shards) == 0 || shardForID == nil {
+			return true
+		}
+		shard, _ := shardForID(id)
+		_, ok := shards[shard]
+		return ok
+	}
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return index.CardinalityStats{}, errDbIndexUnableToQueryClosed
+	}
+	blocks, err := i.blocksForQueryWithRLock(xtime.NewRanges(xtime.Range{
+		Start: opts.StartInclusive,
+		End:   opts.EndExclusive,
+	}))
+	i.state.RUnlock()
+	if err != nil {
+		return index.CardinalityStats{}, err
+	}
+
+	// NB: series are typically indexed by successive blocks, so each series
+	// is only counted by the first block that indexes it.
+	var (
+		result  = index.NewCardinalityResult()
+		counted = make(map[string]struct{})
+	)
+	for _, block := range blocks {
+		blockResult, err := block.Cardinality(index.CardinalityOptions{
+			FilterID: filterID,
+			Counted:  counted,
+		})
+		if err != nil {
+			sp.LogFields(opentracinglog.Error(err))
+			return index.CardinalityStats{}, err
+		}
+		result.Merge(blockResult)
+	}
+	return result.Stats(opts.MetricNameTag, opts.Limit), nil
+}
+
+func (i *nsIndex) WarmupPostingsListCache(
+	queries []index.PostingsListCacheQuery,
+	deadline time.Time,
+) (int, error) {
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return 0, errDbIndexUnableToQueryClosed
+	}
+	blocks := make([]index.Block, 0, len(i.state.blockStartsDescOrder))
+	for _, blockStart := range i.state.blockStartsDescOrder {
+		if block, ok := i.state.blocksByTime[blockStart]; ok {
+			blocks = append(blocks, block)
+		}
+	}
+	i.state.RUnlock()
+
+	warmed := 0
+	for _, block := range blocks {
+		n, err := block.WarmupPostingsListCache(queries, deadline)
+		warmed += n
+		if err == index.ErrUnableToQueryBlockClosed {
+			// Block was closed since, e.g. expired.
+			continue
+		}
+		if err != nil {
+			return warmed, err
+		}
+	}
+	return warmed, nil
+}
+
+func (i *nsIndex) Count(
+	ctx context.Context,
+	query index.Query,
+	opts index.CountQueryOptions,
+) (index.CountResult, error) {
+	_, sp := ctx.StartTraceSpan(tracepoint.NSIdxCount)
+	defer sp.Finish()
+
+	var (
+		queryFilterID = i.queryFilterID(opts.StartInclusive, opts.EndExclusive)
+		shardForID    = i.shardForID()
+		shards        = make(map[uint32]struct{}, len(opts.Shards))
+		// Series are typically indexed in successive blocks so they are
+		// counted by the first block that matches them only.
+		seen = make(map[string]struct{})
+	)
+	for _, shard := range opts.Shards {
+		shards[shard] = struct{}{}
+	}
+	filterID := func(id ident.ID) bool {
+		if _, ok := seen[string(id.Bytes())]; ok {
+			return false
+		}
+		if queryFilterID != nil && !queryFilterID(id) {
+			return false
+		}
+		if len(shards) > 0 && shardForID != nil {
+			shard, _ := shardForID(id)
+			if _, ok := shards[shard]; !ok {
+				return false
+			}
+		}
+		seen[string(id.Bytes())] = struct{}{}
+		return true
+	}
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return index.CountResult{}, errDbIndexUnableToQueryClosed
+	}
+	blocks, err := i.blocksForQueryWithRLock(xtime.NewRanges(xtime.Range{
+		Start: opts.StartInclusive,
+		End:   opts.EndExclusive,
+	}))
+	i.state.RUnlock()
+	if err != nil {
+		return index.CountResult{}, err
+	}
+
+	result, err := index.NewCountResult(opts.IncludeSketch)
+	if err != nil {
+		return index.CountResult{}, err
+	}
+	for _, block := range blocks {
+		blockResult, err := block.Count(query, index.CountOptions{
+			FilterID:      filterID,
+			IncludeSketch: opts.IncludeSketch,
+		})
+		if err != nil {
+			sp.LogFields(opentracinglog.Error(err))
+			return index.CountResult{}, err
+		}
+		if err := result.Merge(blockResult); err != nil {
+			return index.CountResult{}, err
+		}
+	}
+	return result, nil
+}
+
 func (i *nsIndex) DebugMemorySegments(opts DebugMemorySegmentsOptions) error {
 	i.state.RLock()
 	defer i.state.RLock()
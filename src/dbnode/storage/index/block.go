@@ -47,6 +47,7 @@ import (
 	xtime "github.com/m3db/m3/src/x/time"
 
 	opentracinglog "github.com/opentracing/opentracing-go/log"
+	"github.com/pborman/uuid"
 	"github.com/uber-go/tally"
 	"go.uber.org/zap"
 )
@@ -206,6 +207,17 @@ type blockShardRangesSegments struct {
 type BlockOptions struct {
 	ForegroundCompactorMmapDocsData bool
 	BackgroundCompactorMmapDocsData bool
+	// DeletedIDsFn returns the IDs of the series with all of their data
+	// within the block deleted, their documents are excluded from aggregate
+	// queries and dropped when compacting background segments.
+	DeletedIDsFn func() [][]byte
+}
+
+func (o BlockOptions) deletedIDs() [][]byte {
+	if o.DeletedIDsFn == nil {
+		return nil
+	}
+	return o.DeletedIDsFn()
 }
 
 // NewBlockFn is a new block constructor.
@@ -588,6 +600,7 @@ func (b *block) AggregateIter(ctx context.Context, aggOpts AggregateResultsOptio
 
 	iterateOpts := fieldsAndTermsIteratorOpts{
 		restrictByQuery: aggOpts.RestrictByQuery,
+		excludeIDs:      b.blockOpts.deletedIDs(),
 		iterateTerms:    aggOpts.Type == AggregateTagNamesAndValues,
 		allowFn: func(field []byte) bool {
 			// skip any field names that we shouldn't allow.
@@ -951,7 +964,13 @@ func (b *block) addResults(
 		elem := seg.Segment()
 		if immSeg, ok := elem.(segment.ImmutableSegment); ok {
 			// only wrap the immutable segments with a read through cache.
-			elem = NewReadThroughSegment(immSeg, plCache, readThroughOpts)
+			segmentUUID := uuid.NewUUID()
+			if volumeIndex, segmentIndex, ok := seg.PersistedVolume(); ok {
+				// NB: persisted segments are loaded again after a restart, so
+				// their UUID must be stable for postings list cache warmup.
+				segmentUUID = b.persistedSegmentUUID(volumeType, volumeIndex, segmentIndex)
+			}
+			elem = newReadThroughSegment(immSeg, segmentUUID, plCache, readThroughOpts)
 		}
 		readThroughSegments = append(readThroughSegments, elem)
 	}
@@ -1083,6 +1102,119 @@ func (b *block) Stats(reporter BlockStatsReporter) error {
 	return nil
 }
 
+func (b *block) Cardinality(opts CardinalityOptions) (CardinalityResult, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.state == blockStateClosed {
+		return CardinalityResult{}, ErrUnableToQueryBlockClosed
+	}
+
+	readers, err := b.segmentReadersWithRLock()
+	if err != nil {
+		return CardinalityResult{}, err
+	}
+	defer func() {
+		for _, reader := range readers {
+			b.closeAsync(reader)
+		}
+	}()
+
+	// Series deleted from the block are marked as seen so they are not counted.
+	deletedIDs := b.blockOpts.deletedIDs()
+	seen := make(map[string]struct{}, len(deletedIDs))
+	for _, id := range deletedIDs {
+		seen[string(id)] = struct{}{}
+	}
+
+	result := NewCardinalityResult()
+	for _, reader := range readers {
+		if err := addSegmentCardinality(reader, opts, seen, &result); err != nil {
+			return CardinalityResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// persistedSegmentUUID returns a UUID for a persisted segment of the block
+// which is stable across restarts, it is unique to the segment on disk since
+// index fileset volumes are never rewritten.
+func (b *block) persistedSegmentUUID(
+	volumeType persist.IndexVolumeType,
+	volumeIndex int,
+	segmentIndex int,
+) uuid.UUID {
+	name := fmt.Sprintf("%s/%d/%s/%d/%d", b.nsMD.ID().String(),
+		b.blockStart, volumeType, volumeIndex, segmentIndex)
+	return uuid.NewSHA1(uuid.NameSpace_OID, []byte(name))
+}
+
+func (b *block) WarmupPostingsListCache(
+	queries []PostingsListCacheQuery,
+	deadline time.Time,
+) (int, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.state == blockStateClosed {
+		return 0, ErrUnableToQueryBlockClosed
+	}
+
+	readers, err := b.segmentReadersWithRLock()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		for _, reader := range readers {
+			b.closeAsync(reader)
+		}
+	}()
+
+	return warmupPostingsListCache(readers, queries, deadline)
+}
+
+func (b *block) Count(query Query, opts CountOptions) (CountResult, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.state == blockStateClosed {
+		return CountResult{}, ErrUnableToQueryBlockClosed
+	}
+
+	searcher, err := query.SearchQuery().Searcher()
+	if err != nil {
+		return CountResult{}, err
+	}
+
+	readers, err := b.segmentReadersWithRLock()
+	if err != nil {
+		return CountResult{}, err
+	}
+	defer func() {
+		for _, reader := range readers {
+			b.closeAsync(reader)
+		}
+	}()
+
+	// Series deleted from the block are marked as seen so they are not counted.
+	deletedIDs := b.blockOpts.deletedIDs()
+	seen := make(map[string]struct{}, len(deletedIDs))
+	for _, id := range deletedIDs {
+		seen[string(id)] = struct{}{}
+	}
+
+	result, err := NewCountResult(opts.IncludeSketch)
+	if err != nil {
+		return CountResult{}, err
+	}
+	for _, reader := range readers {
+		if err := addSegmentCount(reader, searcher, opts, seen, &result); err != nil {
+			return CountResult{}, err
+		}
+	}
+	return result, nil
+}
+
 func (b *block) IsSealedWithRLock() bool {
 	return b.state == blockStateSealed
 }
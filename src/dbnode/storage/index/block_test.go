@@ -38,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/fst"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	idxpersist "github.com/m3db/m3/src/m3ninx/persist"
 	"github.com/m3db/m3/src/m3ninx/search"
@@ -987,6 +988,118 @@ func TestBlockAddResultsAddsSegment(t *testing.T) {
 	require.Equal(t, seg1, shardRangesSegments[0].segments[0])
 }
 
+func TestBlockAddResultsPersistedSegmentUUIDIsStable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := xtime.Now().Truncate(time.Hour)
+	segmentUUIDs := func() []string {
+		blk, err := NewBlock(start, testMD, BlockOptions{},
+			namespace.NewRuntimeOptionsManager("foo"), testOpts)
+		require.NoError(t, err)
+
+		b, ok := blk.(*block)
+		require.True(t, ok)
+
+		segments := []result.Segment{
+			result.NewPersistedSegment(segment.NewMockImmutableSegment(ctrl), 0, 0),
+			result.NewPersistedSegment(segment.NewMockImmutableSegment(ctrl), 0, 1),
+			result.NewSegment(segment.NewMockImmutableSegment(ctrl), false),
+		}
+
+		results := result.NewIndexBlockByVolumeType(start)
+		results.SetBlock(idxpersist.DefaultIndexVolumeType,
+			result.NewIndexBlock(segments,
+				result.NewShardTimeRangesFromRange(start, start.Add(time.Hour), 1, 2, 3)))
+		require.NoError(t, b.AddResults(results))
+
+		var uuids []string
+		shardRangesSegments := b.shardRangesSegmentsByVolumeType[idxpersist.DefaultIndexVolumeType]
+		for _, seg := range shardRangesSegments[0].segments {
+			readThrough, ok := seg.(*ReadThroughSegment)
+			require.True(t, ok)
+			uuids = append(uuids, readThrough.uuid.String())
+		}
+		return uuids
+	}
+
+	// NB: only persisted segments, which are loaded again after a restart,
+	// have the same UUIDs when added again.
+	first, second := segmentUUIDs(), segmentUUIDs()
+	require.Equal(t, first[:2], second[:2])
+	require.NotEqual(t, first[0], first[1])
+	require.NotEqual(t, first[2], second[2])
+}
+
+func TestBlockAddResultsAppendedPersistedSegmentsDoNotShareCache(t *testing.T) {
+	plCache, err := NewPostingsListCache(1000, PostingsListCacheOptions{
+		InstrumentOptions: instrument.NewOptions(),
+	})
+	require.NoError(t, err)
+	defer plCache.Start()()
+
+	opts := testOpts.
+		SetPostingsListCache(plCache).
+		SetReadThroughSegmentOptions(ReadThroughSegmentOptions{
+			CacheTerms: true,
+		})
+
+	testMD := newTestNSMetadata(t)
+	start := xtime.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, BlockOptions{},
+		namespace.NewRuntimeOptionsManager("foo"), opts)
+	require.NoError(t, err)
+
+	// NB: both segments are the first segment of their volume and the first
+	// document of each has the same postings ID, the second results do not
+	// cover the shards of the first so are appended rather than replacing them.
+	for i, shardRanges := range []result.ShardTimeRanges{
+		result.NewShardTimeRangesFromRange(start, start.Add(time.Hour), 1, 2, 3),
+		result.NewShardTimeRangesFromRange(start, start.Add(time.Hour), 4),
+	} {
+		docs := []doc.Metadata{testDoc1(), testDoc2()}
+		seg := fst.ToTestSegment(t, testSegment(t, docs[i]).(segment.MutableSegment),
+			testFstOptions)
+		results := result.NewIndexBlockByVolumeType(start)
+		results.SetBlock(idxpersist.DefaultIndexVolumeType,
+			result.NewIndexBlock([]result.Segment{result.NewPersistedSegment(seg, i, 0)},
+				shardRanges))
+		require.NoError(t, blk.AddResults(results))
+	}
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+	require.Len(t, b.shardRangesSegmentsByVolumeType[idxpersist.DefaultIndexVolumeType], 2)
+
+	query := func(field, value string) []string {
+		ctx := context.NewBackground()
+		defer ctx.Close()
+
+		q := Query{idx.NewTermQuery([]byte(field), []byte(value))}
+		results := NewQueryResults(nil, QueryResultsOptions{}, opts)
+		queryIter, err := blk.QueryIter(ctx, q)
+		require.NoError(t, err)
+		for !queryIter.Done() {
+			require.NoError(t, blk.QueryWithIter(ctx, QueryOptions{}, queryIter,
+				results, time.Now().Add(time.Minute), emptyLogFields))
+		}
+
+		var ids []string
+		for _, entry := range results.Map().Iter() {
+			ids = append(ids, string(entry.Key()))
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	// Query twice so that the second query is served from the cache.
+	for i := 0; i < 2; i++ {
+		require.Equal(t, []string{"something"}, query("some", "more"))
+		require.Equal(t, []string{"foo", "something"}, query("bar", "baz"))
+	}
+}
+
 func TestBlockAddResultsAfterCloseFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
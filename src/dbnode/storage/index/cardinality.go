@@ -0,0 +1,323 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"sort"
+
+	pilosaroaring "github.com/m3dbx/pilosa/roaring"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// CardinalityQueryOptions are the options for computing the cardinality of
+// the series of a namespace index.
+type CardinalityQueryOptions struct {
+	// StartInclusive is the start of the time range of the index blocks.
+	StartInclusive xtime.UnixNano
+	// EndExclusive is the end of the time range of the index blocks.
+	EndExclusive xtime.UnixNano
+	// Shards restricts the series counted to those of the shards, the series
+	// of all owned shards are counted if empty.
+	Shards []uint32
+	// MetricNameTag is the name of the tag holding the metric name.
+	MetricNameTag []byte
+	// Limit is the number of entries of each statistic to return.
+	Limit int
+}
+
+// CardinalityOptions are the options for computing the cardinality of the
+// series of an index block.
+type CardinalityOptions struct {
+	// FilterID returns whether to count a series, all series are counted if nil.
+	FilterID func(id ident.ID) bool
+	// Counted holds the IDs of the series already counted by other blocks,
+	// series counted by the block are added to it so that series indexed by
+	// several blocks are only counted once. Series are not tracked across
+	// blocks if nil.
+	Counted map[string]struct{}
+}
+
+// LabelValuePair is a label name and value.
+type LabelValuePair struct {
+	Name  string
+	Value string
+}
+
+// CardinalityResult is the cardinality of the series of an index block.
+type CardinalityResult struct {
+	// NumSeries is the number of series.
+	NumSeries int64
+	// SeriesCountByLabelValuePair is the number of series with each label
+	// name and value.
+	SeriesCountByLabelValuePair map[LabelValuePair]int64
+}
+
+// NewCardinalityResult returns a new empty cardinality result.
+func NewCardinalityResult() CardinalityResult {
+	return CardinalityResult{
+		SeriesCountByLabelValuePair: make(map[LabelValuePair]int64),
+	}
+}
+
+// Merge adds the result of another index block, the blocks must count
+// disjoint sets of series, see CardinalityOptions.Counted.
+func (r *CardinalityResult) Merge(other CardinalityResult) {
+	r.NumSeries += other.NumSeries
+	for pair, count := range other.SeriesCountByLabelValuePair {
+		r.SeriesCountByLabelValuePair[pair] += count
+	}
+}
+
+// Stats returns the top statistics of the result, the metric names being the
+// values of the metric name tag.
+func (r CardinalityResult) Stats(metricNameTag []byte, limit int) CardinalityStats {
+	var (
+		metricName         = string(metricNameTag)
+		seriesByMetricName = make(map[string]int64)
+		valuesByLabelName  = make(map[string]int64)
+		seriesByPair       = make(map[string]int64, len(r.SeriesCountByLabelValuePair))
+	)
+	for pair, count := range r.SeriesCountByLabelValuePair {
+		if pair.Name == metricName {
+			seriesByMetricName[pair.Value] = count
+		}
+		valuesByLabelName[pair.Name]++
+		seriesByPair[pair.Name+"="+pair.Value] = count
+	}
+
+	return CardinalityStats{
+		NumSeries:                   r.NumSeries,
+		SeriesCountByMetricName:     topCardinalityStats(seriesByMetricName, limit),
+		LabelValueCountByLabelName:  topCardinalityStats(valuesByLabelName, limit),
+		SeriesCountByLabelValuePair: topCardinalityStats(seriesByPair, limit),
+	}
+}
+
+// CardinalityStat is a count for a name.
+type CardinalityStat struct {
+	Name  string
+	Value int64
+}
+
+// CardinalityStats are the top cardinality statistics of a set of series,
+// each in descending order of value.
+type CardinalityStats struct {
+	// NumSeries is the number of series.
+	NumSeries int64
+	// SeriesCountByMetricName is the number of series by metric name.
+	SeriesCountByMetricName []CardinalityStat
+	// LabelValueCountByLabelName is the number of label values by label name.
+	LabelValueCountByLabelName []CardinalityStat
+	// SeriesCountByLabelValuePair is the number of series by label pair,
+	// formatted as name=value.
+	SeriesCountByLabelValuePair []CardinalityStat
+}
+
+// MergeCardinalityStats merges the statistics of disjoint sets of series,
+// such as those of different shards, into the top statistics of their union.
+// Series counts are summed while label value counts, which may count the
+// same values several times, keep the largest count.
+// NB: each set of statistics is already truncated so counts of names not in
+// the top statistics of every set are lower bounds, and so are label value
+// counts. Sets should hold more entries than the limit so that the merged
+// top statistics are accurate.
+func MergeCardinalityStats(stats []CardinalityStats, limit int) CardinalityStats {
+	var (
+		numSeries          int64
+		seriesByMetricName = make(map[string]int64)
+		valuesByLabelName  = make(map[string]int64)
+		seriesByPair       = make(map[string]int64)
+	)
+	for _, s := range stats {
+		numSeries += s.NumSeries
+		for _, stat := range s.SeriesCountByMetricName {
+			seriesByMetricName[stat.Name] += stat.Value
+		}
+		for _, stat := range s.LabelValueCountByLabelName {
+			if stat.Value > valuesByLabelName[stat.Name] {
+				valuesByLabelName[stat.Name] = stat.Value
+			}
+		}
+		for _, stat := range s.SeriesCountByLabelValuePair {
+			seriesByPair[stat.Name] += stat.Value
+		}
+	}
+
+	return CardinalityStats{
+		NumSeries:                   numSeries,
+		SeriesCountByMetricName:     topCardinalityStats(seriesByMetricName, limit),
+		LabelValueCountByLabelName:  topCardinalityStats(valuesByLabelName, limit),
+		SeriesCountByLabelValuePair: topCardinalityStats(seriesByPair, limit),
+	}
+}
+
+func topCardinalityStats(counts map[string]int64, limit int) []CardinalityStat {
+	stats := make([]CardinalityStat, 0, len(counts))
+	for name, value := range counts {
+		stats = append(stats, CardinalityStat{Name: name, Value: value})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Value != stats[j].Value {
+			return stats[i].Value > stats[j].Value
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
+
+// addSegmentCardinality adds the cardinality of the series of a segment not
+// yet seen, since the same series may be indexed by several segments of a
+// block, to the result.
+func addSegmentCardinality(
+	reader segment.Reader,
+	opts CardinalityOptions,
+	seen map[string]struct{},
+	result *CardinalityResult,
+) error {
+	// Restrict counts to the documents of the series counted by this segment.
+	counted := pilosaroaring.NewBitmap()
+	idsIter, err := reader.Terms(doc.IDReservedFieldName)
+	if err != nil {
+		return err
+	}
+	for idsIter.Next() {
+		id, pl := idsIter.Current()
+		if _, ok := seen[string(id)]; ok {
+			continue
+		}
+		if _, ok := opts.Counted[string(id)]; ok {
+			continue
+		}
+		if opts.FilterID != nil && !opts.FilterID(ident.BytesID(id)) {
+			continue
+		}
+		seen[string(id)] = struct{}{}
+		if opts.Counted != nil {
+			opts.Counted[string(id)] = struct{}{}
+		}
+		if err := addPostings(counted, pl); err != nil {
+			idsIter.Close()
+			return err
+		}
+	}
+	if err := idsIter.Err(); err != nil {
+		idsIter.Close()
+		return err
+	}
+	if err := idsIter.Close(); err != nil {
+		return err
+	}
+
+	numSeries := counted.Count()
+	if numSeries == 0 {
+		return nil
+	}
+	result.NumSeries += int64(numSeries)
+
+	fieldsIter, err := reader.FieldsPostingsList()
+	if err != nil {
+		return err
+	}
+	defer fieldsIter.Close()
+
+	for fieldsIter.Next() {
+		field, _ := fieldsIter.Current()
+		if bytes.Equal(field, doc.IDReservedFieldName) {
+			continue
+		}
+		if err := addFieldCardinality(reader, field, counted, result); err != nil {
+			return err
+		}
+	}
+	return fieldsIter.Err()
+}
+
+func addFieldCardinality(
+	reader segment.Reader,
+	field []byte,
+	counted *pilosaroaring.Bitmap,
+	result *CardinalityResult,
+) error {
+	termsIter, err := reader.Terms(field)
+	if err != nil {
+		return err
+	}
+	defer termsIter.Close()
+
+	name := string(field)
+	for termsIter.Next() {
+		term, pl := termsIter.Current()
+		n, err := intersectionCount(counted, pl)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+		result.SeriesCountByLabelValuePair[LabelValuePair{
+			Name:  name,
+			Value: string(term),
+		}] += int64(n)
+	}
+	return termsIter.Err()
+}
+
+func addPostings(bitmap *pilosaroaring.Bitmap, pl postings.List) error {
+	iter := pl.Iterator()
+	for iter.Next() {
+		bitmap.DirectAdd(uint64(iter.Current()))
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return err
+	}
+	return iter.Close()
+}
+
+func intersectionCount(bitmap *pilosaroaring.Bitmap, pl postings.List) (uint64, error) {
+	if other, ok := roaring.BitmapFromPostingsList(pl); ok {
+		// NB: IntersectionCount does not allocate.
+		return bitmap.IntersectionCount(other), nil
+	}
+
+	var n uint64
+	iter := pl.Iterator()
+	for iter.Next() {
+		if bitmap.Contains(uint64(iter.Current())) {
+			n++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return 0, err
+	}
+	return n, iter.Close()
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func newTestCardinalityBlock(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	blockOpts BlockOptions,
+) Block {
+	blockSize := time.Hour
+	now := xtime.Now()
+	blockStart := now.Truncate(blockSize)
+
+	blk, err := NewBlock(blockStart, newTestNSMetadata(t), blockOpts,
+		namespace.NewRuntimeOptionsManager("foo"), testOpts)
+	require.NoError(t, err)
+
+	batch := NewWriteBatch(WriteBatchOptions{
+		IndexBlockSize: blockSize,
+	})
+	for _, d := range []func() doc.Metadata{testDoc1, testDoc2, testDoc3} {
+		h := NewMockOnIndexSeries(ctrl)
+		h.EXPECT().OnIndexFinalize(blockStart)
+		h.EXPECT().OnIndexSuccess(blockStart)
+		batch.Append(WriteBatchEntry{
+			Timestamp:     blockStart.Add(time.Minute),
+			OnIndexSeries: h,
+		}, d())
+	}
+
+	res, err := blk.WriteBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), res.NumSuccess)
+	return blk
+}
+
+func TestBlockCardinality(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blk := newTestCardinalityBlock(t, ctrl, BlockOptions{})
+	defer func() {
+		require.NoError(t, blk.Close())
+	}()
+
+	result, err := blk.Cardinality(CardinalityOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CardinalityResult{
+		NumSeries: 3,
+		SeriesCountByLabelValuePair: map[LabelValuePair]int64{
+			{Name: "bar", Value: "baz"}:    2,
+			{Name: "bar", Value: "qux"}:    1,
+			{Name: "some", Value: "more"}:  1,
+			{Name: "some", Value: "other"}: 1,
+		},
+	}, result)
+}
+
+func TestBlockCardinalityFilteredAndDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blk := newTestCardinalityBlock(t, ctrl, BlockOptions{
+		DeletedIDsFn: func() [][]byte {
+			return [][]byte{testDoc3().ID}
+		},
+	})
+	defer func() {
+		require.NoError(t, blk.Close())
+	}()
+
+	result, err := blk.Cardinality(CardinalityOptions{
+		FilterID: func(id ident.ID) bool {
+			return !id.Equal(ident.BytesID(testDoc1().ID))
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, CardinalityResult{
+		NumSeries: 1,
+		SeriesCountByLabelValuePair: map[LabelValuePair]int64{
+			{Name: "bar", Value: "baz"}:   1,
+			{Name: "some", Value: "more"}: 1,
+		},
+	}, result)
+}
+
+func TestBlockCardinalityCounted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blk := newTestCardinalityBlock(t, ctrl, BlockOptions{})
+	defer func() {
+		require.NoError(t, blk.Close())
+	}()
+
+	// Series already counted by another block are not counted again.
+	counted := map[string]struct{}{
+		string(testDoc1().ID): {},
+	}
+	result, err := blk.Cardinality(CardinalityOptions{Counted: counted})
+	require.NoError(t, err)
+	require.Equal(t, CardinalityResult{
+		NumSeries: 2,
+		SeriesCountByLabelValuePair: map[LabelValuePair]int64{
+			{Name: "bar", Value: "baz"}:    1,
+			{Name: "bar", Value: "qux"}:    1,
+			{Name: "some", Value: "more"}:  1,
+			{Name: "some", Value: "other"}: 1,
+		},
+	}, result)
+	require.Equal(t, map[string]struct{}{
+		string(testDoc1().ID): {},
+		string(testDoc2().ID): {},
+		string(testDoc3().ID): {},
+	}, counted)
+
+	result, err = blk.Cardinality(CardinalityOptions{Counted: counted})
+	require.NoError(t, err)
+	require.Equal(t, NewCardinalityResult(), result)
+}
+
+func TestCardinalityResultStats(t *testing.T) {
+	result := NewCardinalityResult()
+	result.Merge(CardinalityResult{
+		NumSeries: 3,
+		SeriesCountByLabelValuePair: map[LabelValuePair]int64{
+			{Name: "__name__", Value: "foo"}: 2,
+			{Name: "__name__", Value: "bar"}: 1,
+			{Name: "job", Value: "a"}:        3,
+		},
+	})
+	result.Merge(CardinalityResult{
+		NumSeries: 2,
+		SeriesCountByLabelValuePair: map[LabelValuePair]int64{
+			{Name: "__name__", Value: "bar"}: 2,
+			{Name: "job", Value: "b"}:        1,
+		},
+	})
+
+	stats := result.Stats([]byte("__name__"), 2)
+	require.Equal(t, CardinalityStats{
+		NumSeries: 5,
+		SeriesCountByMetricName: []CardinalityStat{
+			{Name: "bar", Value: 3},
+			{Name: "foo", Value: 2},
+		},
+		LabelValueCountByLabelName: []CardinalityStat{
+			{Name: "__name__", Value: 2},
+			{Name: "job", Value: 2},
+		},
+		SeriesCountByLabelValuePair: []CardinalityStat{
+			{Name: "__name__=bar", Value: 3},
+			{Name: "job=a", Value: 3},
+		},
+	}, stats)
+}
+
+func TestMergeCardinalityStats(t *testing.T) {
+	stats := MergeCardinalityStats([]CardinalityStats{
+		{
+			NumSeries: 3,
+			SeriesCountByMetricName: []CardinalityStat{
+				{Name: "foo", Value: 2},
+				{Name: "bar", Value: 1},
+			},
+			LabelValueCountByLabelName: []CardinalityStat{
+				{Name: "job", Value: 2},
+			},
+			SeriesCountByLabelValuePair: []CardinalityStat{
+				{Name: "job=a", Value: 2},
+			},
+		},
+		{
+			NumSeries: 2,
+			SeriesCountByMetricName: []CardinalityStat{
+				{Name: "bar", Value: 2},
+			},
+			LabelValueCountByLabelName: []CardinalityStat{
+				{Name: "job", Value: 1},
+			},
+			SeriesCountByLabelValuePair: []CardinalityStat{
+				{Name: "job=a", Value: 1},
+				{Name: "job=b", Value: 1},
+			},
+		},
+	}, 1)
+	require.Equal(t, CardinalityStats{
+		NumSeries: 5,
+		SeriesCountByMetricName: []CardinalityStat{
+			{Name: "bar", Value: 3},
+		},
+		LabelValueCountByLabelName: []CardinalityStat{
+			{Name: "job", Value: 2},
+		},
+		SeriesCountByLabelValuePair: []CardinalityStat{
+			{Name: "job=a", Value: 3},
+		},
+	}, stats)
+}
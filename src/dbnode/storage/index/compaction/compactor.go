@@ -101,10 +101,13 @@ func NewCompactor(
 // converted into an FST segment, otherwise an intermediary mutable segment
 // (reused by the compactor between runs) is used to combine all the segments
 // together first before compacting into an FST segment.
+// Only documents the filter keeps are compacted, unless the filter is nil,
+// and a nil segment is returned if the filter drops all documents.
 // Note: This is not thread safe and only a single compaction may happen at a
 // time.
 func (c *Compactor) Compact(
 	segs []segment.Segment,
+	keep segment.DocumentsFilter,
 	reporterOptions mmap.ReporterOptions,
 ) (segment.Segment, error) {
 	c.Lock()
@@ -115,10 +118,17 @@ func (c *Compactor) Compact(
 	}
 
 	c.builder.Reset()
+	c.builder.SetFilter(keep)
 	if err := c.builder.AddSegments(segs); err != nil {
 		return nil, err
 	}
 
+	if keep != nil && len(c.builder.Docs()) == 0 {
+		// All documents were filtered.
+		c.builder.Reset()
+		return nil, nil
+	}
+
 	return c.compactFromBuilderWithLock(c.builder, reporterOptions)
 }
 
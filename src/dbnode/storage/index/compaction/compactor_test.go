@@ -21,6 +21,7 @@
 package compaction
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -96,7 +97,7 @@ func TestCompactorSingleMutableSegment(t *testing.T) {
 
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -122,7 +123,7 @@ func TestCompactorSingleMutableSegmentWithMmapDocsData(t *testing.T) {
 
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -150,7 +151,7 @@ func TestCompactorManySegments(t *testing.T) {
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg1),
 		mustSeal(t, seg2),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -181,7 +182,7 @@ func TestCompactorCompactDuplicateIDsNoError(t *testing.T) {
 	compacted, err := compactor.Compact([]segment.Segment{
 		mustSeal(t, seg1),
 		mustSeal(t, seg2),
-	}, mmap.ReporterOptions{})
+	}, nil, mmap.ReporterOptions{})
 	require.NoError(t, err)
 
 	assertContents(t, compacted, testDocuments)
@@ -189,6 +190,53 @@ func TestCompactorCompactDuplicateIDsNoError(t *testing.T) {
 	require.NoError(t, compactor.Close())
 }
 
+type testDocumentsFilter func(d doc.Metadata) bool
+
+func (f testDocumentsFilter) ContainsDoc(d doc.Metadata) bool {
+	return f(d)
+}
+
+func TestCompactorManySegmentsFiltered(t *testing.T) {
+	seg1, err := mem.NewSegment(testMemSegmentOptions)
+	require.NoError(t, err)
+
+	_, err = seg1.Insert(testDocuments[0])
+	require.NoError(t, err)
+
+	seg2, err := mem.NewSegment(testMemSegmentOptions)
+	require.NoError(t, err)
+
+	_, err = seg2.Insert(testDocuments[1])
+	require.NoError(t, err)
+
+	compactor, err := NewCompactor(testMetadataPool, testMetadataMaxBatch,
+		testBuilderSegmentOptions, testFSTSegmentOptions, CompactorOptions{})
+	require.NoError(t, err)
+
+	keep := testDocumentsFilter(func(d doc.Metadata) bool {
+		return bytes.Equal(d.ID, testDocuments[1].ID)
+	})
+	compacted, err := compactor.Compact([]segment.Segment{
+		mustSeal(t, seg1),
+		mustSeal(t, seg2),
+	}, keep, mmap.ReporterOptions{})
+	require.NoError(t, err)
+
+	assertContents(t, compacted, testDocuments[1:])
+
+	// Filtering all documents returns no segment.
+	dropAll := testDocumentsFilter(func(d doc.Metadata) bool {
+		return false
+	})
+	compacted, err = compactor.Compact([]segment.Segment{
+		seg1,
+	}, dropAll, mmap.ReporterOptions{})
+	require.NoError(t, err)
+	require.Nil(t, compacted)
+
+	require.NoError(t, compactor.Close())
+}
+
 func assertContents(t *testing.T, seg segment.Segment, docs []doc.Metadata) {
 	// Ensure has contents
 	require.Equal(t, int64(len(docs)), seg.Size())
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/x/hyperloglog"
+	"github.com/m3db/m3/src/x/ident"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// CountQueryOptions are the options for counting the series of a namespace
+// index matching a query.
+type CountQueryOptions struct {
+	// StartInclusive is the start of the time range of the index blocks.
+	StartInclusive xtime.UnixNano
+	// EndExclusive is the end of the time range of the index blocks.
+	EndExclusive xtime.UnixNano
+	// Shards restricts the series counted to those of the shards, the series
+	// of all owned shards are counted if empty.
+	Shards []uint32
+	// IncludeSketch includes a HyperLogLog sketch of the IDs of the series
+	// counted in the result.
+	IncludeSketch bool
+}
+
+// CountOptions are the options for counting the series of an index block
+// matching a query.
+type CountOptions struct {
+	// FilterID returns whether to count a series, all series are counted if nil.
+	FilterID func(id ident.ID) bool
+	// IncludeSketch includes a HyperLogLog sketch of the IDs of the series
+	// counted in the result.
+	IncludeSketch bool
+}
+
+// CountResult is the number of series matching a query.
+type CountResult struct {
+	// Count is the number of series.
+	Count int64
+	// Sketch is a HyperLogLog sketch of the IDs of the series, it is nil
+	// unless requested.
+	Sketch *hyperloglog.Sketch
+}
+
+// NewCountResult returns a new empty count result.
+func NewCountResult(includeSketch bool) (CountResult, error) {
+	if !includeSketch {
+		return CountResult{}, nil
+	}
+	sketch, err := hyperloglog.New(hyperloglog.DefaultPrecision)
+	if err != nil {
+		return CountResult{}, err
+	}
+	return CountResult{Sketch: sketch}, nil
+}
+
+// Merge merges the result of a disjoint set of series, such as those of a
+// different shard, into the result.
+func (r *CountResult) Merge(other CountResult) error {
+	r.Count += other.Count
+	if other.Sketch == nil {
+		return nil
+	}
+	if r.Sketch == nil {
+		sketch, err := hyperloglog.New(other.Sketch.Precision())
+		if err != nil {
+			return err
+		}
+		r.Sketch = sketch
+	}
+	return r.Sketch.Merge(other.Sketch)
+}
+
+// addSegmentCount counts the series of a segment matching a query not yet
+// seen, since the same series may be indexed by several segments of a block.
+func addSegmentCount(
+	reader segment.Reader,
+	searcher search.Searcher,
+	opts CountOptions,
+	seen map[string]struct{},
+	result *CountResult,
+) error {
+	pl, err := searcher.Search(reader)
+	if err != nil {
+		return err
+	}
+
+	iter, err := reader.MetadataIterator(pl)
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		id := iter.Current().ID
+		if _, ok := seen[string(id)]; ok {
+			continue
+		}
+		if opts.FilterID != nil && !opts.FilterID(ident.BytesID(id)) {
+			continue
+		}
+		seen[string(id)] = struct{}{}
+		result.Count++
+		if result.Sketch != nil {
+			result.Sketch.Add(id)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return err
+	}
+	return iter.Close()
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/x/hyperloglog"
+	"github.com/m3db/m3/src/x/ident"
+)
+
+func TestBlockCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blk := newTestCardinalityBlock(t, ctrl, BlockOptions{})
+	defer func() {
+		require.NoError(t, blk.Close())
+	}()
+
+	query := Query{Query: idx.NewTermQuery([]byte("bar"), []byte("baz"))}
+	result, err := blk.Count(query, CountOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CountResult{Count: 2}, result)
+
+	result, err = blk.Count(query, CountOptions{IncludeSketch: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Count)
+	require.NotNil(t, result.Sketch)
+	require.Equal(t, uint64(2), result.Sketch.Estimate())
+}
+
+func TestBlockCountFilteredAndDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blk := newTestCardinalityBlock(t, ctrl, BlockOptions{
+		DeletedIDsFn: func() [][]byte {
+			return [][]byte{testDoc3().ID}
+		},
+	})
+	defer func() {
+		require.NoError(t, blk.Close())
+	}()
+
+	result, err := blk.Count(Query{Query: idx.NewFieldQuery([]byte("bar"))}, CountOptions{
+		FilterID: func(id ident.ID) bool {
+			return !id.Equal(ident.BytesID(testDoc1().ID))
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, CountResult{Count: 1}, result)
+}
+
+func TestCountResultMerge(t *testing.T) {
+	result, err := NewCountResult(false)
+	require.NoError(t, err)
+	require.Nil(t, result.Sketch)
+
+	sketch, err := hyperloglog.New(hyperloglog.DefaultPrecision)
+	require.NoError(t, err)
+	sketch.Add([]byte("foo"))
+	sketch.Add([]byte("bar"))
+
+	require.NoError(t, result.Merge(CountResult{Count: 2, Sketch: sketch}))
+	require.NoError(t, result.Merge(CountResult{Count: 3}))
+	require.Equal(t, int64(5), result.Count)
+	require.Equal(t, uint64(2), result.Sketch.Estimate())
+
+	// The sketch of the result is a copy of the merged sketch.
+	sketch.Add([]byte("baz"))
+	require.Equal(t, uint64(2), result.Sketch.Estimate())
+}
@@ -26,6 +26,7 @@ import (
 	pilosaroaring "github.com/m3dbx/pilosa/roaring"
 
 	"github.com/m3db/m3/src/dbnode/tracepoint"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3/src/m3ninx/postings/roaring"
@@ -38,9 +39,11 @@ var errUnpackBitmapFromPostingsList = errors.New("unable to unpack bitmap from p
 // fieldsAndTermsIteratorOpts configures the fieldsAndTermsIterator.
 type fieldsAndTermsIteratorOpts struct {
 	restrictByQuery *Query
-	iterateTerms    bool
-	allowFn         allowFn
-	fieldIterFn     newFieldIterFn
+	// excludeIDs are the IDs of documents to exclude from results.
+	excludeIDs   [][]byte
+	iterateTerms bool
+	allowFn      allowFn
+	fieldIterFn  newFieldIterFn
 }
 
 func (o fieldsAndTermsIteratorOpts) allow(f []byte) bool {
@@ -103,34 +106,92 @@ func newFieldsAndTermsIterator(
 	}
 	iter.fieldIter = fiter
 
-	if opts.restrictByQuery == nil {
-		// No need to restrict results by query.
+	if opts.restrictByQuery == nil && len(opts.excludeIDs) == 0 {
+		// No need to restrict results.
 		return iter, nil
 	}
 
-	// If need to restrict by query, run the query on the segment first.
-	searcher, err := opts.restrictByQuery.SearchQuery().Searcher()
-	if err != nil {
-		return nil, err
-	}
+	var bitmap *pilosaroaring.Bitmap
+	if opts.restrictByQuery != nil {
+		// If need to restrict by query, run the query on the segment first.
+		searcher, err := opts.restrictByQuery.SearchQuery().Searcher()
+		if err != nil {
+			return nil, err
+		}
 
-	_, sp := ctx.StartTraceSpan(tracepoint.FieldTermsIteratorIndexSearch)
-	pl, err := searcher.Search(iter.reader)
-	sp.Finish()
-	if err != nil {
-		return nil, err
+		_, sp := ctx.StartTraceSpan(tracepoint.FieldTermsIteratorIndexSearch)
+		pl, err := searcher.Search(iter.reader)
+		sp.Finish()
+		if err != nil {
+			return nil, err
+		}
+
+		// Hold onto the postings bitmap to intersect against on a per term basis.
+		var ok bool
+		bitmap, ok = roaring.BitmapFromPostingsList(pl)
+		if !ok {
+			return nil, errUnpackBitmapFromPostingsList
+		}
 	}
 
-	// Hold onto the postings bitmap to intersect against on a per term basis.
-	bitmap, ok := roaring.BitmapFromPostingsList(pl)
-	if !ok {
-		return nil, errUnpackBitmapFromPostingsList
+	if len(opts.excludeIDs) > 0 {
+		bitmap, err = excludeDocs(reader, bitmap, opts.excludeIDs)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	iter.restrictByPostings = bitmap
 	return iter, nil
 }
 
+// excludeDocs returns a copy of the bitmap to restrict results by, or of all
+// documents of the segment if nil, without the documents of the IDs given.
+func excludeDocs(
+	reader segment.Reader,
+	restrictBy *pilosaroaring.Bitmap,
+	excludeIDs [][]byte,
+) (*pilosaroaring.Bitmap, error) {
+	var bitmap *pilosaroaring.Bitmap
+	if restrictBy != nil {
+		// NB: the bitmap may be held by the postings list cache so must
+		// not be modified in place.
+		bitmap = restrictBy.Clone()
+	} else {
+		all, err := reader.MatchAll()
+		if err != nil {
+			return nil, err
+		}
+		pl := roaring.NewPostingsList()
+		if err := pl.AddIterator(all.Iterator()); err != nil {
+			return nil, err
+		}
+		var ok bool
+		bitmap, ok = roaring.BitmapFromPostingsList(pl)
+		if !ok {
+			return nil, errUnpackBitmapFromPostingsList
+		}
+	}
+
+	for _, id := range excludeIDs {
+		pl, err := reader.MatchTerm(doc.IDReservedFieldName, id)
+		if err != nil {
+			return nil, err
+		}
+		iter := pl.Iterator()
+		for iter.Next() {
+			if _, err := bitmap.Remove(uint64(iter.Current())); err != nil {
+				iter.Close()
+				return nil, err
+			}
+		}
+		if err := xerrors.FirstError(iter.Err(), iter.Close()); err != nil {
+			return nil, err
+		}
+	}
+	return bitmap, nil
+}
+
 func (fti *fieldsAndTermsIter) setNextField() bool {
 	fieldIter := fti.fieldIter
 	if fieldIter == nil {
@@ -929,6 +929,21 @@ func (mr *MockBlockMockRecorder) AggregateWithIter(ctx, iter, opts, results, dea
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateWithIter", reflect.TypeOf((*MockBlock)(nil).AggregateWithIter), ctx, iter, opts, results, deadline, logFields)
 }
 
+// Cardinality mocks base method.
+func (m *MockBlock) Cardinality(opts CardinalityOptions) (CardinalityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", opts)
+	ret0, _ := ret[0].(CardinalityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockBlockMockRecorder) Cardinality(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockBlock)(nil).Cardinality), opts)
+}
+
 // Close mocks base method.
 func (m *MockBlock) Close() error {
 	m.ctrl.T.Helper()
@@ -943,6 +958,21 @@ func (mr *MockBlockMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBlock)(nil).Close))
 }
 
+// Count mocks base method.
+func (m *MockBlock) Count(query Query, opts CountOptions) (CountResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", query, opts)
+	ret0, _ := ret[0].(CountResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockBlockMockRecorder) Count(query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockBlock)(nil).Count), query, opts)
+}
+
 // EndTime mocks base method.
 func (m *MockBlock) EndTime() time0.UnixNano {
 	m.ctrl.T.Helper()
@@ -1140,6 +1170,21 @@ func (mr *MockBlockMockRecorder) Tick(c interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockBlock)(nil).Tick), c)
 }
 
+// WarmupPostingsListCache mocks base method.
+func (m *MockBlock) WarmupPostingsListCache(queries []PostingsListCacheQuery, deadline time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmupPostingsListCache", queries, deadline)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WarmupPostingsListCache indicates an expected call of WarmupPostingsListCache.
+func (mr *MockBlockMockRecorder) WarmupPostingsListCache(queries, deadline interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmupPostingsListCache", reflect.TypeOf((*MockBlock)(nil).WarmupPostingsListCache), queries, deadline)
+}
+
 // WriteBatch mocks base method.
 func (m *MockBlock) WriteBatch(inserts *WriteBatch) (WriteBatchResult, error) {
 	m.ctrl.T.Helper()
@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/storage/index/compaction"
 	"github.com/m3db/m3/src/dbnode/storage/index/segments"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	m3ninxindex "github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/builder"
@@ -461,8 +462,14 @@ func (m *mutableSegments) backgroundCompactWithTask(
 		segments = append(segments, seg.Segment)
 	}
 
+	// Drop the documents of deleted series while compacting.
+	var keep segment.DocumentsFilter
+	if ids := m.blockOpts.deletedIDs(); len(ids) > 0 {
+		keep = newDeletedIDsFilter(ids)
+	}
+
 	start := time.Now()
-	compacted, err := m.compact.backgroundCompactor.Compact(segments, mmap.ReporterOptions{
+	compacted, err := m.compact.backgroundCompactor.Compact(segments, keep, mmap.ReporterOptions{
 		Context: mmap.Context{
 			Name: mmapIndexBlockName,
 		},
@@ -531,10 +538,31 @@ func (m *mutableSegments) addCompactedSegmentFromSegmentsWithLock(
 		}
 	}
 
+	if compacted == nil {
+		// All documents of the compacted segments were dropped.
+		return result
+	}
+
 	// Return all the ones we kept plus the new compacted segment
 	return append(result, newReadableSeg(compacted, m.opts))
 }
 
+// deletedIDsFilter keeps the documents of series that have not been deleted.
+type deletedIDsFilter map[string]struct{}
+
+func newDeletedIDsFilter(ids [][]byte) deletedIDsFilter {
+	f := make(deletedIDsFilter, len(ids))
+	for _, id := range ids {
+		f[string(id)] = struct{}{}
+	}
+	return f
+}
+
+func (f deletedIDsFilter) ContainsDoc(d doc.Metadata) bool {
+	_, deleted := f[string(d.ID)]
+	return !deleted
+}
+
 func (m *mutableSegments) foregroundCompactWithBuilder(builder segment.DocumentsBuilder) error {
 	// We inserted some documents, need to compact immediately into a
 	// foreground segment.
@@ -21,6 +21,7 @@
 package index
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -45,11 +46,54 @@ const (
 	PatternTypeTerm
 	// PatternTypeField indicates that the pattern is of type field.
 	PatternTypeField
+	// PatternTypePrefix indicates that the pattern is of type prefix.
+	PatternTypePrefix
+	// PatternTypeRange indicates that the pattern is of type range.
+	PatternTypeRange
+	// PatternTypeCaseInsensitiveTerm indicates that the pattern is of type
+	// case insensitive term.
+	PatternTypeCaseInsensitiveTerm
 
 	reportLoopInterval = 10 * time.Second
 	emptyPattern       = ""
 )
 
+var patternTypeNames = map[PatternType]string{
+	PatternTypeRegexp:              "regexp",
+	PatternTypeTerm:                "term",
+	PatternTypeField:               "field",
+	PatternTypePrefix:              "prefix",
+	PatternTypeRange:               "range",
+	PatternTypeCaseInsensitiveTerm: "case_insensitive_term",
+}
+
+func (p PatternType) String() string {
+	if name, ok := patternTypeNames[p]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText marshals the pattern type as its name.
+func (p PatternType) MarshalText() ([]byte, error) {
+	name, ok := patternTypeNames[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown pattern type: %d", int(p))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the pattern type from its name.
+func (p *PatternType) UnmarshalText(text []byte) error {
+	for patternType, name := range patternTypeNames {
+		if name == string(text) {
+			*p = patternType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown pattern type: %s", text)
+}
+
 // PostingsListCacheOptions is the options struct for the query cache.
 type PostingsListCacheOptions struct {
 	InstrumentOptions instrument.Options
@@ -114,6 +158,34 @@ func (q *PostingsListCache) GetField(
 	return q.get(segmentUUID, field, emptyPattern, PatternTypeField)
 }
 
+// GetPrefix returns the cached results for the provided prefix query, if any.
+func (q *PostingsListCache) GetPrefix(
+	segmentUUID uuid.UUID,
+	field string,
+	pattern string,
+) (postings.List, bool) {
+	return q.get(segmentUUID, field, pattern, PatternTypePrefix)
+}
+
+// GetRange returns the cached results for the provided range query, if any.
+func (q *PostingsListCache) GetRange(
+	segmentUUID uuid.UUID,
+	field string,
+	pattern string,
+) (postings.List, bool) {
+	return q.get(segmentUUID, field, pattern, PatternTypeRange)
+}
+
+// GetCaseInsensitiveTerm returns the cached results for the provided case
+// insensitive term query, if any.
+func (q *PostingsListCache) GetCaseInsensitiveTerm(
+	segmentUUID uuid.UUID,
+	field string,
+	pattern string,
+) (postings.List, bool) {
+	return q.get(segmentUUID, field, pattern, PatternTypeCaseInsensitiveTerm)
+}
+
 func (q *PostingsListCache) get(
 	segmentUUID uuid.UUID,
 	field string,
@@ -163,6 +235,37 @@ func (q *PostingsListCache) PutField(
 	q.put(segmentUUID, field, emptyPattern, PatternTypeField, pl)
 }
 
+// PutPrefix updates the LRU with the result of the prefix query.
+func (q *PostingsListCache) PutPrefix(
+	segmentUUID uuid.UUID,
+	field string,
+	pattern string,
+	pl postings.List,
+) {
+	q.put(segmentUUID, field, pattern, PatternTypePrefix, pl)
+}
+
+// PutRange updates the LRU with the result of the range query.
+func (q *PostingsListCache) PutRange(
+	segmentUUID uuid.UUID,
+	field string,
+	pattern string,
+	pl postings.List,
+) {
+	q.put(segmentUUID, field, pattern, PatternTypeRange, pl)
+}
+
+// PutCaseInsensitiveTerm updates the LRU with the result of the case
+// insensitive term query.
+func (q *PostingsListCache) PutCaseInsensitiveTerm(
+	segmentUUID uuid.UUID,
+	field string,
+	pattern string,
+	pl postings.List,
+) {
+	q.put(segmentUUID, field, pattern, PatternTypeCaseInsensitiveTerm, pl)
+}
+
 func (q *PostingsListCache) put(
 	segmentUUID uuid.UUID,
 	field string,
@@ -237,6 +340,12 @@ func (q *PostingsListCache) emitCacheGetMetrics(patternType PatternType, hit boo
 		method = q.metrics.term
 	case PatternTypeField:
 		method = q.metrics.field
+	case PatternTypePrefix:
+		method = q.metrics.prefix
+	case PatternTypeRange:
+		method = q.metrics.termRange
+	case PatternTypeCaseInsensitiveTerm:
+		method = q.metrics.caseInsensitiveTerm
 	default:
 		method = q.metrics.unknown // should never happen
 	}
@@ -255,16 +364,25 @@ func (q *PostingsListCache) emitCachePutMetrics(patternType PatternType) {
 		q.metrics.term.puts.Inc(1)
 	case PatternTypeField:
 		q.metrics.field.puts.Inc(1)
+	case PatternTypePrefix:
+		q.metrics.prefix.puts.Inc(1)
+	case PatternTypeRange:
+		q.metrics.termRange.puts.Inc(1)
+	case PatternTypeCaseInsensitiveTerm:
+		q.metrics.caseInsensitiveTerm.puts.Inc(1)
 	default:
 		q.metrics.unknown.puts.Inc(1) // should never happen
 	}
 }
 
 type postingsListCacheMetrics struct {
-	regexp  *postingsListCacheMethodMetrics
-	term    *postingsListCacheMethodMetrics
-	field   *postingsListCacheMethodMetrics
-	unknown *postingsListCacheMethodMetrics
+	regexp              *postingsListCacheMethodMetrics
+	term                *postingsListCacheMethodMetrics
+	field               *postingsListCacheMethodMetrics
+	prefix              *postingsListCacheMethodMetrics
+	termRange           *postingsListCacheMethodMetrics
+	caseInsensitiveTerm *postingsListCacheMethodMetrics
+	unknown             *postingsListCacheMethodMetrics
 
 	size     tally.Gauge
 	capacity tally.Gauge
@@ -281,6 +399,15 @@ func newPostingsListCacheMetrics(scope tally.Scope) *postingsListCacheMetrics {
 		field: newPostingsListCacheMethodMetrics(scope.Tagged(map[string]string{
 			"query_type": "field",
 		})),
+		prefix: newPostingsListCacheMethodMetrics(scope.Tagged(map[string]string{
+			"query_type": "prefix",
+		})),
+		termRange: newPostingsListCacheMethodMetrics(scope.Tagged(map[string]string{
+			"query_type": "range",
+		})),
+		caseInsensitiveTerm: newPostingsListCacheMethodMetrics(scope.Tagged(map[string]string{
+			"query_type": "case_insensitive_term",
+		})),
 		unknown: newPostingsListCacheMethodMetrics(scope.Tagged(map[string]string{
 			"query_type": "unknown",
 		})),
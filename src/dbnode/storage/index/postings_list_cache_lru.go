@@ -69,6 +69,8 @@ type entry struct {
 	uuid         uuid.UUID
 	key          key
 	postingsList postings.List
+	// hits is the number of times the entry was retrieved from the cache.
+	hits int64
 }
 
 type key struct {
@@ -150,7 +152,9 @@ func (c *postingsListLRU) Get(
 	if uuidEntries, ok := c.items[uuidArray]; ok {
 		if ent, ok := uuidEntries[newKey]; ok {
 			c.evictList.MoveToFront(ent)
-			return ent.Value.(*entry).postingsList, true
+			value := ent.Value.(*entry)
+			value.hits++
+			return value.postingsList, true
 		}
 	}
 
@@ -185,6 +189,15 @@ func (c *postingsListLRU) PurgeSegment(segmentUUID uuid.UUID) {
 	}
 }
 
+// ForEach calls fn for each entry of the cache, from the most to the least
+// recently used.
+func (c *postingsListLRU) ForEach(fn func(segmentUUID uuid.UUID, key key, hits int64)) {
+	for e := c.evictList.Front(); e != nil; e = e.Next() {
+		ent := e.Value.(*entry)
+		fn(ent.uuid, ent.key, ent.hits)
+	}
+}
+
 // Len returns the number of items in the cache.
 func (c *postingsListLRU) Len() int {
 	return c.evictList.Len()
@@ -61,12 +61,18 @@ func init() {
 		pl.Insert(postings.ID(i))
 
 		patternType := PatternTypeRegexp
-		switch i % 3 {
+		switch i % 6 {
 		case 0:
 			patternType = PatternTypeTerm
 		case 1:
 			patternType = PatternTypeField
 			pattern = "" // field queries don't have patterns
+		case 3:
+			patternType = PatternTypePrefix
+		case 4:
+			patternType = PatternTypeRange
+		case 5:
+			patternType = PatternTypeCaseInsensitiveTerm
 		}
 
 		testPlEntries = append(testPlEntries, testEntry{
@@ -314,6 +320,45 @@ func putEntry(t *testing.T, cache *PostingsListCache, i int) {
 			testPlEntries[i].key.field,
 			testPlEntries[i].postingsList,
 		)
+	case PatternTypePrefix:
+		cache.PutPrefix(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+			testPlEntries[i].postingsList,
+		)
+		cache.PutPrefix(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+			testPlEntries[i].postingsList,
+		)
+	case PatternTypeRange:
+		cache.PutRange(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+			testPlEntries[i].postingsList,
+		)
+		cache.PutRange(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+			testPlEntries[i].postingsList,
+		)
+	case PatternTypeCaseInsensitiveTerm:
+		cache.PutCaseInsensitiveTerm(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+			testPlEntries[i].postingsList,
+		)
+		cache.PutCaseInsensitiveTerm(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+			testPlEntries[i].postingsList,
+		)
 	default:
 		require.FailNow(t, "unknown pattern type", testPlEntries[i].key.patternType)
 	}
@@ -338,6 +383,24 @@ func getEntry(t *testing.T, cache *PostingsListCache, i int) (postings.List, boo
 			testPlEntries[i].segmentUUID,
 			testPlEntries[i].key.field,
 		)
+	case PatternTypePrefix:
+		return cache.GetPrefix(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+		)
+	case PatternTypeRange:
+		return cache.GetRange(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+		)
+	case PatternTypeCaseInsensitiveTerm:
+		return cache.GetCaseInsensitiveTerm(
+			testPlEntries[i].segmentUUID,
+			testPlEntries[i].key.field,
+			testPlEntries[i].key.pattern,
+		)
 	default:
 		require.FailNow(t, "unknown pattern type", testPlEntries[i].key.patternType)
 	}
@@ -0,0 +1,396 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/pborman/uuid"
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPostingsListCacheWarmupPersistInterval = time.Minute
+
+	postingsListCacheWarmupFileMode = 0644
+	postingsListCacheWarmupDirMode  = 0755
+)
+
+var (
+	errPostingsListCacheWarmupTimeout         = errors.New("postings list cache warmup timed out")
+	errPostingsListCacheWarmupNoFilePath      = errors.New("postings list cache warmup file path is not set")
+	errPostingsListCacheWarmupNonPositiveSize = errors.New("postings list cache warmup size must be positive")
+)
+
+// PostingsListCacheQuery is a query whose resulting postings list is cached
+// for a segment.
+type PostingsListCacheQuery struct {
+	// Segment is the UUID of the segment the postings list is cached for.
+	Segment     string      `json:"segment"`
+	Field       string      `json:"field"`
+	Pattern     string      `json:"pattern,omitempty"`
+	PatternType PatternType `json:"patternType"`
+	// Hits is the number of cache hits of the query against the segment.
+	Hits int64 `json:"hits"`
+}
+
+// HottestQueries returns at most n of the queries whose postings lists are
+// cached, the most hit first. Range queries are excluded since their cache
+// patterns cannot be executed again.
+func (q *PostingsListCache) HottestQueries(n int) []PostingsListCacheQuery {
+	var queries []PostingsListCacheQuery
+	q.Lock()
+	q.lru.ForEach(func(segmentUUID uuid.UUID, k key, hits int64) {
+		if k.patternType == PatternTypeRange {
+			return
+		}
+		queries = append(queries, PostingsListCacheQuery{
+			Segment:     segmentUUID.String(),
+			Field:       k.field,
+			Pattern:     k.pattern,
+			PatternType: k.patternType,
+			Hits:        hits,
+		})
+	})
+	q.Unlock()
+
+	// NB: queries are in most recently used order which breaks ties.
+	sort.SliceStable(queries, func(i, j int) bool {
+		return queries[i].Hits > queries[j].Hits
+	})
+	if len(queries) > n {
+		queries = queries[:n]
+	}
+	return queries
+}
+
+// PostingsListCacheWarmupOptions is the options struct for the postings list
+// cache warmer.
+type PostingsListCacheWarmupOptions struct {
+	// FilePath is the path of the file the hottest queries are persisted to.
+	FilePath string
+	// Size is the maximum number of queries persisted and executed on warmup.
+	Size int
+	// MaxDuration is the maximum duration of a warmup, unlimited if zero.
+	MaxDuration time.Duration
+	// PersistInterval is the interval at which the hottest queries are persisted.
+	PersistInterval time.Duration
+	// InstrumentOptions is the instrument options.
+	InstrumentOptions instrument.Options
+}
+
+// PostingsListCacheWarmupTarget is an index whose cached postings lists can
+// be warmed.
+type PostingsListCacheWarmupTarget interface {
+	// WarmupPostingsListCache executes the queries against the cached segments
+	// of the index and returns the number of postings lists warmed.
+	WarmupPostingsListCache(
+		queries []PostingsListCacheQuery,
+		deadline time.Time,
+	) (int, error)
+}
+
+// PostingsListCacheWarmer persists the hottest queries of a postings list
+// cache so that the cache can be warmed by executing them again after a
+// restart.
+type PostingsListCacheWarmer struct {
+	cache   *PostingsListCache
+	opts    PostingsListCacheWarmupOptions
+	logger  *zap.Logger
+	metrics postingsListCacheWarmupMetrics
+
+	// warmedUp is whether the warmup finished, before which the queries
+	// persisted by the previous process must not be overwritten.
+	warmedUp atomic.Bool
+}
+
+// NewPostingsListCacheWarmer creates a new postings list cache warmer.
+func NewPostingsListCacheWarmer(
+	cache *PostingsListCache,
+	opts PostingsListCacheWarmupOptions,
+) (*PostingsListCacheWarmer, error) {
+	if opts.FilePath == "" {
+		return nil, errPostingsListCacheWarmupNoFilePath
+	}
+	if opts.Size <= 0 {
+		return nil, errPostingsListCacheWarmupNonPositiveSize
+	}
+	if opts.PersistInterval <= 0 {
+		opts.PersistInterval = defaultPostingsListCacheWarmupPersistInterval
+	}
+	if opts.InstrumentOptions == nil {
+		opts.InstrumentOptions = instrument.NewOptions()
+	}
+
+	return &PostingsListCacheWarmer{
+		cache:  cache,
+		opts:   opts,
+		logger: opts.InstrumentOptions.Logger(),
+		metrics: newPostingsListCacheWarmupMetrics(
+			opts.InstrumentOptions.MetricsScope().SubScope("warmup")),
+	}, nil
+}
+
+// Start starts a background process that persists the hottest queries on a
+// regular basis once the cache was warmed and returns a function that will end
+// the background process after persisting them one last time.
+func (w *PostingsListCacheWarmer) Start() Closer {
+	doneCh := make(chan struct{})
+	stoppedCh := make(chan struct{})
+
+	go func() {
+		defer close(stoppedCh)
+
+		ticker := time.NewTicker(w.opts.PersistInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-doneCh:
+				return
+			case <-ticker.C:
+			}
+
+			w.persistAndLog()
+		}
+	}()
+
+	return func() {
+		close(doneCh)
+		<-stoppedCh
+		w.persistAndLog()
+	}
+}
+
+func (w *PostingsListCacheWarmer) persistAndLog() {
+	if !w.warmedUp.Load() {
+		return
+	}
+	if err := w.Persist(); err != nil {
+		w.logger.Error("could not persist postings list cache warmup queries",
+			zap.String("path", w.opts.FilePath), zap.Error(err))
+	}
+}
+
+// Persist persists the hottest queries of the cache.
+func (w *PostingsListCacheWarmer) Persist() error {
+	queries := w.cache.HottestQueries(w.opts.Size)
+	// NB: never overwrite the persisted queries with nothing, e.g. when
+	// stopping before the cache could be warmed.
+	if len(queries) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(queries)
+	if err != nil {
+		w.metrics.persistErrors.Inc(1)
+		return err
+	}
+
+	// Write to a temporary file renamed over the previous one so that the
+	// persisted queries are never partially written.
+	if err := os.MkdirAll(filepath.Dir(w.opts.FilePath), postingsListCacheWarmupDirMode); err != nil {
+		w.metrics.persistErrors.Inc(1)
+		return err
+	}
+	tmpPath := w.opts.FilePath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, postingsListCacheWarmupFileMode); err != nil {
+		w.metrics.persistErrors.Inc(1)
+		return err
+	}
+	if err := os.Rename(tmpPath, w.opts.FilePath); err != nil {
+		w.metrics.persistErrors.Inc(1)
+		return err
+	}
+
+	w.metrics.persistedQueries.Update(float64(len(queries)))
+	return nil
+}
+
+// Load returns the persisted queries, if any.
+func (w *PostingsListCacheWarmer) Load() ([]PostingsListCacheQuery, error) {
+	data, err := ioutil.ReadFile(w.opts.FilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []PostingsListCacheQuery
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("invalid postings list cache warmup file %s: %v",
+			w.opts.FilePath, err)
+	}
+	if len(queries) > w.opts.Size {
+		queries = queries[:w.opts.Size]
+	}
+	return queries, nil
+}
+
+// Warmup executes the persisted queries against the targets to warm the
+// cache, stopping once the maximum warmup duration has elapsed.
+func (w *PostingsListCacheWarmer) Warmup(targets []PostingsListCacheWarmupTarget) error {
+	defer w.warmedUp.Store(true)
+
+	start := time.Now()
+	queries, err := w.Load()
+	if err != nil {
+		w.metrics.warmupErrors.Inc(1)
+		return err
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	var deadline time.Time
+	if w.opts.MaxDuration > 0 {
+		deadline = start.Add(w.opts.MaxDuration)
+	}
+
+	var (
+		warmed   int
+		timedOut bool
+		multiErr xerrors.MultiError
+	)
+	for _, target := range targets {
+		n, err := target.WarmupPostingsListCache(queries, deadline)
+		warmed += n
+		if err == errPostingsListCacheWarmupTimeout {
+			timedOut = true
+			break
+		}
+		if err != nil {
+			multiErr = multiErr.Add(err)
+		}
+	}
+
+	took := time.Since(start)
+	w.metrics.warmupQueries.Update(float64(len(queries)))
+	w.metrics.warmupPostingsLists.Inc(int64(warmed))
+	w.metrics.warmupDuration.Record(took)
+	if timedOut {
+		w.metrics.warmupTimeouts.Inc(1)
+	}
+	if !multiErr.Empty() {
+		w.metrics.warmupErrors.Inc(1)
+	}
+	w.logger.Info("postings list cache warmup finished",
+		zap.Int("queries", len(queries)),
+		zap.Int("postingsLists", warmed),
+		zap.Duration("took", took),
+		zap.Bool("timedOut", timedOut))
+
+	return multiErr.FinalError()
+}
+
+// warmupPostingsListCache executes the queries against the cached segment
+// readers of the segments they were cached for and returns the number of
+// postings lists warmed. Queries of segments which no longer exist, or belong
+// to other indexes, are skipped.
+func warmupPostingsListCache(
+	readers []segment.Reader,
+	queries []PostingsListCacheQuery,
+	deadline time.Time,
+) (int, error) {
+	// Only readers of cached segments can be warmed.
+	cachedBySegment := make(map[string]*readThroughSegmentReader, len(readers))
+	for _, reader := range readers {
+		if cached, ok := reader.(*readThroughSegmentReader); ok {
+			cachedBySegment[cached.uuid.String()] = cached
+		}
+	}
+
+	warmed := 0
+	for _, query := range queries {
+		cached, ok := cachedBySegment[query.Segment]
+		if !ok {
+			continue
+		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return warmed, errPostingsListCacheWarmupTimeout
+		}
+
+		if err := query.execute(cached, []byte(query.Field)); err != nil {
+			return warmed, err
+		}
+		warmed++
+	}
+	return warmed, nil
+}
+
+func (q PostingsListCacheQuery) execute(reader segment.Reader, field []byte) error {
+	var err error
+	switch q.PatternType {
+	case PatternTypeRegexp:
+		compiled, compileErr := index.CompileRegex([]byte(q.Pattern))
+		if compileErr != nil {
+			return compileErr
+		}
+		_, err = reader.MatchRegexp(field, compiled)
+	case PatternTypeTerm:
+		_, err = reader.MatchTerm(field, []byte(q.Pattern))
+	case PatternTypeField:
+		_, err = reader.MatchField(field)
+	case PatternTypePrefix:
+		_, err = reader.MatchPrefix(field, []byte(q.Pattern))
+	case PatternTypeCaseInsensitiveTerm:
+		_, err = reader.MatchTermCaseInsensitive(field, []byte(q.Pattern))
+	default:
+		err = fmt.Errorf("unsupported postings list cache warmup pattern type: %v", q.PatternType)
+	}
+	return err
+}
+
+type postingsListCacheWarmupMetrics struct {
+	persistedQueries    tally.Gauge
+	persistErrors       tally.Counter
+	warmupQueries       tally.Gauge
+	warmupPostingsLists tally.Counter
+	warmupDuration      tally.Timer
+	warmupTimeouts      tally.Counter
+	warmupErrors        tally.Counter
+}
+
+func newPostingsListCacheWarmupMetrics(scope tally.Scope) postingsListCacheWarmupMetrics {
+	return postingsListCacheWarmupMetrics{
+		persistedQueries:    scope.Gauge("persisted-queries"),
+		persistErrors:       scope.Counter("persist-errors"),
+		warmupQueries:       scope.Gauge("queries"),
+		warmupPostingsLists: scope.Counter("postings-lists"),
+		warmupDuration:      scope.Timer("duration"),
+		warmupTimeouts:      scope.Counter("timeouts"),
+		warmupErrors:        scope.Counter("errors"),
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/postings/roaring"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestPatternTypeMarshalText(t *testing.T) {
+	for patternType := range patternTypeNames {
+		data, err := json.Marshal(patternType)
+		require.NoError(t, err)
+
+		var unmarshalled PatternType
+		require.NoError(t, json.Unmarshal(data, &unmarshalled))
+		require.Equal(t, patternType, unmarshalled)
+	}
+
+	var unmarshalled PatternType
+	require.Error(t, json.Unmarshal([]byte(`"unknown"`), &unmarshalled))
+}
+
+func TestPostingsListCacheHottestQueries(t *testing.T) {
+	cache, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+
+	var (
+		segmentA = uuid.NewUUID()
+		segmentB = uuid.NewUUID()
+		pl       = roaring.NewPostingsList()
+	)
+	cache.PutRegexp(segmentA, "city", "new.*", pl)
+	cache.PutRegexp(segmentB, "city", "new.*", pl)
+	cache.PutTerm(segmentA, "city", "paris", pl)
+	cache.PutField(segmentA, "city", pl)
+	cache.PutRange(segmentA, "population", `numeric[1, *)`, pl)
+
+	hit := func(n int, get func() bool) {
+		for i := 0; i < n; i++ {
+			require.True(t, get())
+		}
+	}
+	hit(2, func() bool {
+		_, ok := cache.GetRegexp(segmentA, "city", "new.*")
+		return ok
+	})
+	hit(2, func() bool {
+		_, ok := cache.GetRegexp(segmentB, "city", "new.*")
+		return ok
+	})
+	hit(3, func() bool {
+		_, ok := cache.GetTerm(segmentA, "city", "paris")
+		return ok
+	})
+	hit(5, func() bool {
+		_, ok := cache.GetRange(segmentA, "population", `numeric[1, *)`)
+		return ok
+	})
+
+	// Queries are kept per segment and range queries are excluded.
+	var (
+		a = segmentA.String()
+		b = segmentB.String()
+	)
+	require.Equal(t, []PostingsListCacheQuery{
+		{Segment: a, Field: "city", Pattern: "paris", PatternType: PatternTypeTerm, Hits: 3},
+		{Segment: b, Field: "city", Pattern: "new.*", PatternType: PatternTypeRegexp, Hits: 2},
+		{Segment: a, Field: "city", Pattern: "new.*", PatternType: PatternTypeRegexp, Hits: 2},
+		{Segment: a, Field: "city", PatternType: PatternTypeField, Hits: 0},
+	}, cache.HottestQueries(10))
+
+	require.Equal(t, []PostingsListCacheQuery{
+		{Segment: a, Field: "city", Pattern: "paris", PatternType: PatternTypeTerm, Hits: 3},
+	}, cache.HottestQueries(1))
+}
+
+func newTestPostingsListCacheWarmer(
+	t *testing.T,
+	cache *PostingsListCache,
+	scope tally.Scope,
+) (*PostingsListCacheWarmer, func()) {
+	dir, err := ioutil.TempDir("", "postings-list-cache-warmup")
+	require.NoError(t, err)
+
+	warmer, err := NewPostingsListCacheWarmer(cache, PostingsListCacheWarmupOptions{
+		FilePath:          filepath.Join(dir, "cache", "warmup.json"),
+		Size:              2,
+		MaxDuration:       time.Minute,
+		InstrumentOptions: instrument.NewOptions().SetMetricsScope(scope),
+	})
+	require.NoError(t, err)
+
+	return warmer, func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestNewPostingsListCacheWarmerInvalidOptions(t *testing.T) {
+	cache, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+
+	_, err = NewPostingsListCacheWarmer(cache, PostingsListCacheWarmupOptions{
+		Size: 1,
+	})
+	require.Error(t, err)
+
+	_, err = NewPostingsListCacheWarmer(cache, PostingsListCacheWarmupOptions{
+		FilePath: "warmup.json",
+	})
+	require.Error(t, err)
+}
+
+func TestPostingsListCacheWarmerPersistAndLoad(t *testing.T) {
+	cache, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+
+	warmer, cleanup := newTestPostingsListCacheWarmer(t, cache, tally.NoopScope)
+	defer cleanup()
+
+	// Nothing was persisted yet.
+	queries, err := warmer.Load()
+	require.NoError(t, err)
+	require.Empty(t, queries)
+
+	// An empty cache is not persisted.
+	require.NoError(t, warmer.Persist())
+	_, err = os.Stat(warmer.opts.FilePath)
+	require.True(t, os.IsNotExist(err))
+
+	var (
+		segmentUUID = uuid.NewUUID()
+		pl          = roaring.NewPostingsList()
+	)
+	cache.PutTerm(segmentUUID, "city", "paris", pl)
+	cache.PutPrefix(segmentUUID, "city", "new", pl)
+	cache.PutCaseInsensitiveTerm(segmentUUID, "city", "Rome", pl)
+	_, ok := cache.GetCaseInsensitiveTerm(segmentUUID, "city", "Rome")
+	require.True(t, ok)
+
+	require.NoError(t, warmer.Persist())
+	queries, err = warmer.Load()
+	require.NoError(t, err)
+	require.Equal(t, []PostingsListCacheQuery{
+		{
+			Segment:     segmentUUID.String(),
+			Field:       "city",
+			Pattern:     "Rome",
+			PatternType: PatternTypeCaseInsensitiveTerm,
+			Hits:        1,
+		},
+		{
+			Segment:     segmentUUID.String(),
+			Field:       "city",
+			Pattern:     "new",
+			PatternType: PatternTypePrefix,
+		},
+	}, queries)
+}
+
+func TestPostingsListCacheWarmerStartPersistsOnlyOnceWarmedUp(t *testing.T) {
+	cache, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+
+	warmer, cleanup := newTestPostingsListCacheWarmer(t, cache, tally.NoopScope)
+	defer cleanup()
+
+	cache.PutTerm(uuid.NewUUID(), "city", "paris", roaring.NewPostingsList())
+
+	// Not warmed up so the queries of the previous process are kept.
+	warmer.Start()()
+	_, err = os.Stat(warmer.opts.FilePath)
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, warmer.Warmup(nil))
+	warmer.Start()()
+	queries, err := warmer.Load()
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+}
+
+type testWarmupTarget struct {
+	queries  []PostingsListCacheQuery
+	deadline time.Time
+	warmed   int
+	err      error
+}
+
+func (w *testWarmupTarget) WarmupPostingsListCache(
+	queries []PostingsListCacheQuery,
+	deadline time.Time,
+) (int, error) {
+	w.queries = queries
+	w.deadline = deadline
+	return w.warmed, w.err
+}
+
+func TestPostingsListCacheWarmerWarmup(t *testing.T) {
+	cache, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+
+	scope := tally.NewTestScope("", nil)
+	warmer, cleanup := newTestPostingsListCacheWarmer(t, cache, scope)
+	defer cleanup()
+
+	segmentID := uuid.NewUUID().String()
+	persisted := []PostingsListCacheQuery{
+		{Segment: segmentID, Field: "city", Pattern: "paris", PatternType: PatternTypeTerm, Hits: 3},
+		{Segment: segmentID, Field: "city", Pattern: "new.*", PatternType: PatternTypeRegexp, Hits: 2},
+		{Segment: segmentID, Field: "city", PatternType: PatternTypeField, Hits: 1},
+	}
+	data, err := json.Marshal(persisted)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(warmer.opts.FilePath), 0755))
+	require.NoError(t, ioutil.WriteFile(warmer.opts.FilePath, data, 0644))
+
+	var (
+		first    = &testWarmupTarget{warmed: 3}
+		second   = &testWarmupTarget{warmed: 1, err: errors.New("an error")}
+		timedOut = &testWarmupTarget{warmed: 2, err: errPostingsListCacheWarmupTimeout}
+		skipped  = &testWarmupTarget{}
+		start    = time.Now()
+	)
+	err = warmer.Warmup([]PostingsListCacheWarmupTarget{first, second, timedOut, skipped})
+	require.Error(t, err)
+
+	// Only the hottest queries within the warmup size are executed.
+	require.Equal(t, persisted[:2], first.queries)
+	require.True(t, first.deadline.After(start))
+	require.False(t, first.deadline.After(start.Add(time.Minute+time.Second)))
+	require.Equal(t, persisted[:2], second.queries)
+	require.Equal(t, persisted[:2], timedOut.queries)
+	require.Nil(t, skipped.queries)
+
+	snapshot := scope.Snapshot()
+	require.Equal(t, int64(6), snapshot.Counters()["warmup.postings-lists+"].Value())
+	require.Equal(t, int64(1), snapshot.Counters()["warmup.timeouts+"].Value())
+	require.Equal(t, int64(1), snapshot.Counters()["warmup.errors+"].Value())
+	require.Equal(t, float64(2), snapshot.Gauges()["warmup.queries+"].Value())
+}
+
+func TestWarmupPostingsListCache(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	cache, err := NewPostingsListCache(10, testPostingListCacheOptions)
+	require.NoError(t, err)
+
+	// NB: regexps are cached by their compiled pattern which is the pattern
+	// persisted and must compile to itself for warmed regexps to be hit.
+	compiled, err := index.CompileRegex([]byte("new.*"))
+	require.NoError(t, err)
+
+	var (
+		segmentUUID = uuid.NewUUID()
+		segmentID   = segmentUUID.String()
+		reader      = segment.NewMockReader(ctrl)
+		cached      = newReadThroughSegmentReader(reader, segmentUUID, cache,
+			defaultReadThroughSegmentOptions)
+		uncached = segment.NewMockReader(ctrl)
+		pl       = roaring.NewPostingsList()
+		queries  = []PostingsListCacheQuery{
+			{Segment: segmentID, Field: "city", Pattern: compiled.FSTSyntax.String(), PatternType: PatternTypeRegexp},
+			{Segment: segmentID, Field: "city", Pattern: "paris", PatternType: PatternTypeTerm},
+			{Segment: segmentID, Field: "city", PatternType: PatternTypeField},
+			{Segment: segmentID, Field: "city", Pattern: "new", PatternType: PatternTypePrefix},
+			{Segment: segmentID, Field: "city", Pattern: "Rome", PatternType: PatternTypeCaseInsensitiveTerm},
+			// NB: queries of segments which no longer exist are skipped.
+			{Segment: uuid.NewUUID().String(), Field: "country", Pattern: "france", PatternType: PatternTypeTerm},
+		}
+	)
+	require.NoError(t, pl.Insert(1))
+
+	reader.EXPECT().MatchRegexp([]byte("city"), gomock.Any()).Return(pl, nil)
+	reader.EXPECT().MatchTerm([]byte("city"), []byte("paris")).Return(pl, nil)
+	reader.EXPECT().MatchField([]byte("city")).Return(pl, nil)
+	reader.EXPECT().MatchPrefix([]byte("city"), []byte("new")).Return(pl, nil)
+	reader.EXPECT().MatchTermCaseInsensitive([]byte("city"), []byte("Rome")).Return(pl, nil)
+
+	warmed, err := warmupPostingsListCache([]segment.Reader{uncached, cached}, queries, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 5, warmed)
+
+	// Queries executed again hit the cache.
+	for _, query := range queries[:5] {
+		require.NoError(t, query.execute(cached, []byte(query.Field)))
+	}
+	hottest := cache.HottestQueries(10)
+	require.Len(t, hottest, 5)
+	for i := range hottest {
+		require.Equal(t, int64(1), hottest[i].Hits, hottest[i])
+		hottest[i].Hits = 0
+	}
+	sort.Slice(hottest, func(i, j int) bool {
+		return hottest[i].PatternType < hottest[j].PatternType
+	})
+	require.Equal(t, []PostingsListCacheQuery{
+		queries[0], queries[1], queries[2], queries[3], queries[4],
+	}, hottest)
+
+	// No queries are executed past the deadline.
+	warmed, err = warmupPostingsListCache([]segment.Reader{cached}, queries,
+		time.Now().Add(-time.Second))
+	require.Equal(t, errPostingsListCacheWarmupTimeout, err)
+	require.Equal(t, 0, warmed)
+}
@@ -64,7 +64,8 @@ type ReadThroughSegment struct {
 // ReadThroughSegmentOptions is the options struct for the
 // ReadThroughSegment.
 type ReadThroughSegmentOptions struct {
-	// Whether the postings list for regexp queries should be cached.
+	// Whether the postings list for regexp, prefix, range and case insensitive
+	// term queries should be cached.
 	CacheRegexp bool
 	// Whether the postings list for term queries should be cached.
 	CacheTerms bool
@@ -75,11 +76,20 @@ func NewReadThroughSegment(
 	seg segment.ImmutableSegment,
 	cache *PostingsListCache,
 	opts ReadThroughSegmentOptions,
+) segment.Segment {
+	return newReadThroughSegment(seg, uuid.NewUUID(), cache, opts)
+}
+
+func newReadThroughSegment(
+	seg segment.ImmutableSegment,
+	segmentUUID uuid.UUID,
+	cache *PostingsListCache,
+	opts ReadThroughSegmentOptions,
 ) segment.Segment {
 	return &ReadThroughSegment{
 		segment:           seg,
 		opts:              opts,
-		uuid:              uuid.NewUUID(),
+		uuid:              segmentUUID,
 		postingsListCache: cache,
 	}
 }
@@ -228,6 +238,75 @@ func (s *readThroughSegmentReader) MatchTerm(
 	return pl, err
 }
 
+// MatchPrefix returns a cached posting list or queries the underlying
+// segment if their is a cache miss.
+func (s *readThroughSegmentReader) MatchPrefix(
+	field []byte, prefix []byte,
+) (postings.List, error) {
+	if s.postingsListCache == nil || !s.opts.CacheRegexp {
+		return s.reader.MatchPrefix(field, prefix)
+	}
+
+	fieldStr := string(field)
+	patternStr := string(prefix)
+	pl, ok := s.postingsListCache.GetPrefix(s.uuid, fieldStr, patternStr)
+	if ok {
+		return pl, nil
+	}
+
+	pl, err := s.reader.MatchPrefix(field, prefix)
+	if err == nil {
+		s.postingsListCache.PutPrefix(s.uuid, fieldStr, patternStr, pl)
+	}
+	return pl, err
+}
+
+// MatchRange returns a cached posting list or queries the underlying
+// segment if their is a cache miss.
+func (s *readThroughSegmentReader) MatchRange(
+	field []byte, termRange index.TermRange,
+) (postings.List, error) {
+	if s.postingsListCache == nil || !s.opts.CacheRegexp {
+		return s.reader.MatchRange(field, termRange)
+	}
+
+	fieldStr := string(field)
+	patternStr := termRange.String()
+	pl, ok := s.postingsListCache.GetRange(s.uuid, fieldStr, patternStr)
+	if ok {
+		return pl, nil
+	}
+
+	pl, err := s.reader.MatchRange(field, termRange)
+	if err == nil {
+		s.postingsListCache.PutRange(s.uuid, fieldStr, patternStr, pl)
+	}
+	return pl, err
+}
+
+// MatchTermCaseInsensitive returns a cached posting list or queries the
+// underlying segment if their is a cache miss.
+func (s *readThroughSegmentReader) MatchTermCaseInsensitive(
+	field []byte, term []byte,
+) (postings.List, error) {
+	if s.postingsListCache == nil || !s.opts.CacheRegexp {
+		return s.reader.MatchTermCaseInsensitive(field, term)
+	}
+
+	fieldStr := string(field)
+	patternStr := string(term)
+	pl, ok := s.postingsListCache.GetCaseInsensitiveTerm(s.uuid, fieldStr, patternStr)
+	if ok {
+		return pl, nil
+	}
+
+	pl, err := s.reader.MatchTermCaseInsensitive(field, term)
+	if err == nil {
+		s.postingsListCache.PutCaseInsensitiveTerm(s.uuid, fieldStr, patternStr, pl)
+	}
+	return pl, err
+}
+
 // MatchField returns a cached posting list or queries the underlying
 // segment if their is a cache miss.
 func (s *readThroughSegmentReader) MatchField(field []byte) (postings.List, error) {
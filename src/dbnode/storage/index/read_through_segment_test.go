@@ -263,6 +263,167 @@ func TestReadThroughSegmentMatchTermNoCache(t *testing.T) {
 	require.True(t, pl.Equal(originalPL))
 }
 
+func TestReadThroughSegmentMatchPrefix(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	seg := fst.NewMockSegment(ctrl)
+	reader := segment.NewMockReader(ctrl)
+	seg.EXPECT().Reader().Return(reader, nil)
+
+	cache, err := NewPostingsListCache(1, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer cache.Start()()
+
+	var (
+		field  = []byte("some-field")
+		prefix = []byte("some-")
+	)
+
+	readThrough, err := NewReadThroughSegment(
+		seg, cache, defaultReadThroughSegmentOptions).Reader()
+	require.NoError(t, err)
+
+	originalPL := roaring.NewPostingsList()
+	require.NoError(t, originalPL.Insert(1))
+	reader.EXPECT().MatchPrefix(field, prefix).Return(originalPL, nil)
+
+	// Make sure it goes to the segment when the cache misses.
+	pl, err := readThrough.MatchPrefix(field, prefix)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+
+	// Make sure it relies on the cache if its present (mock only expects
+	// one call.)
+	pl, err = readThrough.MatchPrefix(field, prefix)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+}
+
+func TestReadThroughSegmentMatchPrefixCacheDisabled(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	seg := fst.NewMockSegment(ctrl)
+	reader := segment.NewMockReader(ctrl)
+	seg.EXPECT().Reader().Return(reader, nil)
+
+	cache, err := NewPostingsListCache(1, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer cache.Start()()
+
+	var (
+		field  = []byte("some-field")
+		prefix = []byte("some-")
+	)
+
+	readThrough, err := NewReadThroughSegment(seg, cache, ReadThroughSegmentOptions{
+		CacheRegexp: false,
+	}).Reader()
+	require.NoError(t, err)
+
+	originalPL := roaring.NewPostingsList()
+	require.NoError(t, originalPL.Insert(1))
+	reader.EXPECT().
+		MatchPrefix(field, prefix).
+		Return(originalPL, nil).
+		Times(2)
+
+	// Make sure it goes to the segment.
+	pl, err := readThrough.MatchPrefix(field, prefix)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+
+	// Make sure it goes to the segment the second time - meaning the cache was
+	// disabled.
+	pl, err = readThrough.MatchPrefix(field, prefix)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+}
+
+func TestReadThroughSegmentMatchRange(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	seg := fst.NewMockSegment(ctrl)
+	reader := segment.NewMockReader(ctrl)
+	seg.EXPECT().Reader().Return(reader, nil)
+
+	cache, err := NewPostingsListCache(2, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer cache.Start()()
+
+	field := []byte("some-field")
+	termRange, err := index.NewTermRange([]byte("1"), []byte("10"), true, false, true)
+	require.NoError(t, err)
+	otherTermRange, err := index.NewTermRange([]byte("1"), []byte("10"), true, true, true)
+	require.NoError(t, err)
+
+	readThrough, err := NewReadThroughSegment(
+		seg, cache, defaultReadThroughSegmentOptions).Reader()
+	require.NoError(t, err)
+
+	originalPL := roaring.NewPostingsList()
+	require.NoError(t, originalPL.Insert(1))
+	otherPL := roaring.NewPostingsList()
+	require.NoError(t, otherPL.Insert(2))
+	reader.EXPECT().MatchRange(field, termRange).Return(originalPL, nil)
+	reader.EXPECT().MatchRange(field, otherTermRange).Return(otherPL, nil)
+
+	// Make sure it goes to the segment when the cache misses.
+	pl, err := readThrough.MatchRange(field, termRange)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+
+	// Make sure ranges with different bounds are cached separately.
+	pl, err = readThrough.MatchRange(field, otherTermRange)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(otherPL))
+
+	// Make sure it relies on the cache if its present (mock only expects
+	// one call.)
+	pl, err = readThrough.MatchRange(field, termRange)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+}
+
+func TestReadThroughSegmentMatchTermCaseInsensitive(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	seg := fst.NewMockSegment(ctrl)
+	reader := segment.NewMockReader(ctrl)
+	seg.EXPECT().Reader().Return(reader, nil)
+
+	cache, err := NewPostingsListCache(1, testPostingListCacheOptions)
+	require.NoError(t, err)
+	defer cache.Start()()
+
+	var (
+		field = []byte("some-field")
+		term  = []byte("Some-Term")
+	)
+
+	readThrough, err := NewReadThroughSegment(
+		seg, cache, defaultReadThroughSegmentOptions).Reader()
+	require.NoError(t, err)
+
+	originalPL := roaring.NewPostingsList()
+	require.NoError(t, originalPL.Insert(1))
+	reader.EXPECT().MatchTermCaseInsensitive(field, term).Return(originalPL, nil)
+
+	// Make sure it goes to the segment when the cache misses.
+	pl, err := readThrough.MatchTermCaseInsensitive(field, term)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+
+	// Make sure it relies on the cache if its present (mock only expects
+	// one call.)
+	pl, err = readThrough.MatchTermCaseInsensitive(field, term)
+	require.NoError(t, err)
+	require.True(t, pl.Equal(originalPL))
+}
+
 func TestClose(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -444,6 +444,19 @@ type Block interface {
 	// Stats returns block stats.
 	Stats(reporter BlockStatsReporter) error
 
+	// Cardinality returns the cardinality of the series of the block.
+	Cardinality(opts CardinalityOptions) (CardinalityResult, error)
+
+	// Count returns the number of series of the block matching a query.
+	Count(query Query, opts CountOptions) (CountResult, error)
+
+	// WarmupPostingsListCache executes the queries against the cached segments
+	// of the block and returns the number of postings lists warmed.
+	WarmupPostingsListCache(
+		queries []PostingsListCacheQuery,
+		deadline time.Time,
+	) (int, error)
+
 	// Seal prevents the block from taking any more writes, but, it still permits
 	// addition of segments via Bootstrap().
 	Seal() error
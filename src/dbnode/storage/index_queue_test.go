@@ -198,7 +198,7 @@ func TestNamespaceIndexInsertOlderThanRetentionPeriod(t *testing.T) {
 	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
 	dbIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions().SetIndexOptions(opts))
+		testShardSet, nil, DefaultTestOptions().SetIndexOptions(opts))
 	assert.NoError(t, err)
 
 	idx, ok := dbIdx.(*nsIndex)
@@ -357,6 +357,83 @@ func TestNamespaceIndexInsertQuery(t *testing.T) {
 		tags))
 }
 
+func TestNamespaceIndexInsertCardinality(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	defer leaktest.CheckTimeout(t, 2*time.Second)()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	now := xtime.Now()
+	idx := setupIndex(t, ctrl, now)
+	defer idx.Close()
+
+	opts := index.CardinalityQueryOptions{
+		StartInclusive: now.Add(-1 * time.Minute),
+		EndExclusive:   now.Add(1 * time.Minute),
+		MetricNameTag:  []byte("name"),
+		Limit:          10,
+	}
+	stats, err := idx.Cardinality(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, index.CardinalityStats{
+		NumSeries: 1,
+		SeriesCountByMetricName: []index.CardinalityStat{
+			{Name: "value", Value: 1},
+		},
+		LabelValueCountByLabelName: []index.CardinalityStat{
+			{Name: "name", Value: 1},
+		},
+		SeriesCountByLabelValuePair: []index.CardinalityStat{
+			{Name: "name=value", Value: 1},
+		},
+	}, stats)
+
+	// Series of other shards are not counted.
+	shard := testShardSet.Lookup(ident.StringID("foo"))
+	opts.Shards = []uint32{(shard + 1) % uint32(len(testShardSet.AllIDs()))}
+	stats, err = idx.Cardinality(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.NumSeries)
+	assert.Empty(t, stats.SeriesCountByLabelValuePair)
+}
+
+func TestNamespaceIndexInsertCount(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	defer leaktest.CheckTimeout(t, 2*time.Second)()
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	now := xtime.Now()
+	idx := setupIndex(t, ctrl, now)
+	defer idx.Close()
+
+	reQuery, err := m3ninxidx.NewRegexpQuery([]byte("name"), []byte("val.*"))
+	assert.NoError(t, err)
+	query := index.Query{Query: reQuery}
+	opts := index.CountQueryOptions{
+		StartInclusive: now.Add(-1 * time.Minute),
+		EndExclusive:   now.Add(1 * time.Minute),
+		IncludeSketch:  true,
+	}
+	result, err := idx.Count(ctx, query, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Count)
+	require.NotNil(t, result.Sketch)
+	assert.Equal(t, uint64(1), result.Sketch.Estimate())
+
+	// Series of other shards are not counted.
+	shard := testShardSet.Lookup(ident.StringID("foo"))
+	opts.Shards = []uint32{(shard + 1) % uint32(len(testShardSet.AllIDs()))}
+	opts.IncludeSketch = false
+	result, err = idx.Count(ctx, query, opts)
+	require.NoError(t, err)
+	assert.Equal(t, index.CountResult{}, result)
+}
+
 func TestNamespaceIndexInsertAggregateQuery(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -37,6 +37,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
@@ -54,11 +55,47 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestNamespaceIndexQueryFilterIDExcludesDeleted(t *testing.T) {
+	md := testNamespaceMetadata(time.Hour, time.Hour*8)
+	tombstones, err := tombstone.NewStore(tombstone.Options{BlockSize: time.Hour})
+	require.NoError(t, err)
+	nsIdx, err := newNamespaceIndex(md,
+		namespace.NewRuntimeOptionsManager(md.ID().String()),
+		testShardSet, tombstones, DefaultTestOptions())
+	require.NoError(t, err)
+	idx := nsIdx.(*nsIndex)
+	defer func() {
+		require.NoError(t, idx.Close())
+	}()
+
+	var (
+		now     = xtime.Now().Truncate(time.Hour)
+		deleted = xtime.Range{Start: now.Add(-2 * time.Hour), End: now}
+	)
+	require.NoError(t, tombstones.Add([]tombstone.Tombstone{
+		{ID: []byte("foo"), Range: deleted},
+	}))
+
+	filter := idx.queryFilterID(deleted.Start, deleted.End)
+	require.False(t, filter(ident.StringID("foo")))
+	require.True(t, filter(ident.StringID("bar")))
+
+	// Series deleted over only part of the query range are still returned.
+	filter = idx.queryFilterID(deleted.Start, deleted.End.Add(time.Hour))
+	require.True(t, filter(ident.StringID("foo")))
+
+	// Only series deleted over a whole block are excluded from the block.
+	blockOpts := idx.blockOptions(now.Add(-time.Hour))
+	require.Equal(t, [][]byte{[]byte("foo")}, blockOpts.DeletedIDsFn())
+	blockOpts = idx.blockOptions(now)
+	require.Empty(t, blockOpts.DeletedIDsFn())
+}
+
 func TestNamespaceIndexCleanupExpiredFilesets(t *testing.T) {
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 
 	now := xtime.Now().Truncate(time.Hour)
@@ -83,7 +120,7 @@ func TestNamespaceIndexCleanupDuplicateFilesets(t *testing.T) {
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 
 	idx := nsIdx.(*nsIndex)
@@ -217,7 +254,7 @@ func TestNamespaceIndexCleanupDuplicateFilesets_SortingByBlockStartAndVolumeType
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 	idx := nsIdx.(*nsIndex)
 	idx.readIndexInfoFilesFn = func(_ fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult {
@@ -282,7 +319,7 @@ func TestNamespaceIndexCleanupDuplicateFilesets_ChangingShardList(t *testing.T)
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 	idx := nsIdx.(*nsIndex)
 	idx.readIndexInfoFilesFn = func(_ fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult {
@@ -333,7 +370,7 @@ func TestNamespaceIndexCleanupDuplicateFilesets_IgnoreNonActiveShards(t *testing
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 	idx := nsIdx.(*nsIndex)
 	idx.readIndexInfoFilesFn = func(_ fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult {
@@ -384,7 +421,7 @@ func TestNamespaceIndexCleanupDuplicateFilesets_NoActiveShards(t *testing.T) {
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 	idx := nsIdx.(*nsIndex)
 	idx.readIndexInfoFilesFn = func(_ fs.ReadIndexInfoFilesOptions) []fs.ReadIndexInfoFileResult {
@@ -405,7 +442,7 @@ func TestNamespaceIndexCleanupDuplicateFilesetsNoop(t *testing.T) {
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 
 	idx := nsIdx.(*nsIndex)
@@ -467,7 +504,7 @@ func TestNamespaceIndexCleanupExpiredFilesetsWithBlocks(t *testing.T) {
 	md := testNamespaceMetadata(time.Hour, time.Hour*8)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 
 	defer func() {
@@ -495,7 +532,7 @@ func TestNamespaceIndexCleanupCorruptedFilesets(t *testing.T) {
 	md := testNamespaceMetadata(time.Hour, time.Hour*24)
 	nsIdx, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, DefaultTestOptions())
+		testShardSet, nil, DefaultTestOptions())
 	require.NoError(t, err)
 
 	idx := nsIdx.(*nsIndex)
@@ -1010,7 +1047,7 @@ func newTestIndex(t *testing.T, ctrl *gomock.Controller) testIndex {
 	opts := DefaultTestOptions()
 	index, err := newNamespaceIndex(md,
 		namespace.NewRuntimeOptionsManager(md.ID().String()),
-		testShardSet, opts)
+		testShardSet, nil, opts)
 	require.NoError(t, err)
 
 	return testIndex{
@@ -26,9 +26,13 @@ type noOpQueryLimits struct {
 type noOpLookbackLimit struct {
 }
 
+type noOpTenantLimits struct {
+}
+
 var (
 	_ QueryLimits   = (*noOpQueryLimits)(nil)
 	_ LookbackLimit = (*noOpLookbackLimit)(nil)
+	_ TenantLimits  = (*noOpTenantLimits)(nil)
 )
 
 // NoOpQueryLimits returns inactive query limits.
@@ -48,6 +52,10 @@ func (q *noOpQueryLimits) AggregateDocsLimit() LookbackLimit {
 	return &noOpLookbackLimit{}
 }
 
+func (q *noOpQueryLimits) TenantLimits() TenantLimits {
+	return &noOpTenantLimits{}
+}
+
 func (q *noOpQueryLimits) AnyFetchExceeded() error {
 	return nil
 }
@@ -75,3 +83,21 @@ func (q *noOpLookbackLimit) Start() {
 
 func (q *noOpLookbackLimit) Stop() {
 }
+
+func (q *noOpTenantLimits) Options() map[string]TenantLimitOptions {
+	return nil
+}
+
+func (q *noOpTenantLimits) Inc(TenantLimitKind, int, []byte) error {
+	return nil
+}
+
+func (q *noOpTenantLimits) Update(map[string]TenantLimitOptions) error {
+	return nil
+}
+
+func (q *noOpTenantLimits) Start() {
+}
+
+func (q *noOpTenantLimits) Stop() {
+}
@@ -42,10 +42,16 @@ type queryLimits struct {
 	docsLimit           *lookbackLimit
 	bytesReadLimit      *lookbackLimit
 	aggregatedDocsLimit *lookbackLimit
+	tenantLimits        *tenantLimits
+
+	fetchDocsLimit      LookbackLimit
+	fetchBytesReadLimit LookbackLimit
+	aggregateDocsLimit  LookbackLimit
 }
 
 type lookbackLimit struct {
 	name      string
+	tenant    string
 	started   bool
 	options   LookbackLimitOptions
 	metrics   lookbackLimitMetrics
@@ -123,12 +129,18 @@ func NewQueryLimits(options Options) (QueryLimits, error) {
 			metricName: docsMatched,
 			metricType: "aggregate",
 		}, aggDocsLimitOpts, iOpts, sourceLoggerBuilder)
+		tenantLimits = newTenantLimits(iOpts, sourceLoggerBuilder)
 	)
 
 	return &queryLimits{
 		docsLimit:           docsLimit,
 		bytesReadLimit:      bytesReadLimit,
 		aggregatedDocsLimit: aggregatedDocsLimit,
+		tenantLimits:        tenantLimits,
+
+		fetchDocsLimit:      newTenantChargedLimit(docsLimit, TenantDocsMatched, tenantLimits),
+		fetchBytesReadLimit: newTenantChargedLimit(bytesReadLimit, TenantBytesRead, tenantLimits),
+		aggregateDocsLimit:  newTenantChargedLimit(aggregatedDocsLimit, TenantDocsMatched, tenantLimits),
 	}, nil
 }
 
@@ -150,6 +162,7 @@ type limitNames struct {
 	limitName  string
 	metricName string
 	metricType string
+	tenant     string
 }
 
 func newLookbackLimit(
@@ -166,6 +179,7 @@ func newLookbackLimit(
 
 	return &lookbackLimit{
 		name:      limitNames.limitName,
+		tenant:    limitNames.tenant,
 		options:   opts,
 		metrics:   metrics,
 		logger:    instrumentOpts.Logger(),
@@ -182,9 +196,13 @@ func newLookbackLimitMetrics(
 	sourceLoggerBuilder SourceLoggerBuilder,
 ) lookbackLimitMetrics {
 	metricName := limitNames.metricName
-	loggerScope := instrumentOpts.MetricsScope().Tagged(map[string]string{
+	tags := map[string]string{
 		"type": limitNames.metricType,
-	})
+	}
+	if limitNames.tenant != "" {
+		tags["tenant"] = limitNames.tenant
+	}
+	loggerScope := instrumentOpts.MetricsScope().Tagged(tags)
 
 	var (
 		loggerOpts  = instrumentOpts.SetMetricsScope(loggerScope)
@@ -204,27 +222,33 @@ func newLookbackLimitMetrics(
 }
 
 func (q *queryLimits) FetchDocsLimit() LookbackLimit {
-	return q.docsLimit
+	return q.fetchDocsLimit
 }
 
 func (q *queryLimits) BytesReadLimit() LookbackLimit {
-	return q.bytesReadLimit
+	return q.fetchBytesReadLimit
 }
 
 func (q *queryLimits) AggregateDocsLimit() LookbackLimit {
-	return q.aggregatedDocsLimit
+	return q.aggregateDocsLimit
+}
+
+func (q *queryLimits) TenantLimits() TenantLimits {
+	return q.tenantLimits
 }
 
 func (q *queryLimits) Start() {
 	q.docsLimit.Start()
 	q.bytesReadLimit.Start()
 	q.aggregatedDocsLimit.Start()
+	q.tenantLimits.Start()
 }
 
 func (q *queryLimits) Stop() {
 	q.docsLimit.Stop()
 	q.bytesReadLimit.Stop()
 	q.aggregatedDocsLimit.Stop()
+	q.tenantLimits.Stop()
 }
 
 func (q *queryLimits) AnyFetchExceeded() error {
@@ -262,6 +286,7 @@ func (q *lookbackLimit) Update(opts LookbackLimitOptions) error {
 
 	q.logger.Info("query limit options updated",
 		zap.String("name", q.name),
+		zap.String("tenant", q.tenant),
 		zap.Any("new", opts),
 		zap.Any("old", old))
 
@@ -302,6 +327,11 @@ func (q *lookbackLimit) checkLimit(recent int64) error {
 	if currentOpts.ForceExceeded {
 		q.metrics.exceeded.Inc(1)
 
+		if q.tenant != "" {
+			return xerrors.NewInvalidParamsError(NewQueryLimitExceededError(fmt.Sprintf(
+				"request aborted due to forced tenant limit: tenant=%s, name=%s", q.tenant, q.name)))
+		}
+
 		return xerrors.NewInvalidParamsError(NewQueryLimitExceededError(fmt.Sprintf(
 			"query aborted due to forced limit: name=%s", q.name)))
 	}
@@ -313,6 +343,12 @@ func (q *lookbackLimit) checkLimit(recent int64) error {
 	if recent >= currentOpts.Limit {
 		q.metrics.exceeded.Inc(1)
 
+		if q.tenant != "" {
+			return xerrors.NewInvalidParamsError(NewQueryLimitExceededError(fmt.Sprintf(
+				"request aborted due to tenant limit: tenant=%s, name=%s, limit=%d, current=%d, within=%s",
+				q.tenant, q.name, currentOpts.Limit, recent, currentOpts.Lookback)))
+		}
+
 		return xerrors.NewInvalidParamsError(NewQueryLimitExceededError(fmt.Sprintf(
 			"query aborted due to limit: name=%s, limit=%d, current=%d, within=%s",
 			q.name, q.options.Limit, recent, q.options.Lookback)))
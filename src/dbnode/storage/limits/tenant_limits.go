@@ -0,0 +1,269 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+type tenantLimits struct {
+	sync.RWMutex
+
+	started             bool
+	limits              map[string]*tenantLimit
+	iOpts               instrument.Options
+	sourceLoggerBuilder SourceLoggerBuilder
+}
+
+type tenantLimit struct {
+	docsLimit          *lookbackLimit
+	bytesReadLimit     *lookbackLimit
+	seriesWrittenLimit *lookbackLimit
+}
+
+// tenantChargedLimit is a lookback limit that also charges each increment to
+// the budget of the tenant that is the source of the increment.
+type tenantChargedLimit struct {
+	*lookbackLimit
+
+	kind    TenantLimitKind
+	tenants TenantLimits
+}
+
+var (
+	_ TenantLimits  = (*tenantLimits)(nil)
+	_ LookbackLimit = (*tenantChargedLimit)(nil)
+)
+
+func newTenantLimits(
+	iOpts instrument.Options,
+	sourceLoggerBuilder SourceLoggerBuilder,
+) *tenantLimits {
+	return &tenantLimits{
+		limits:              make(map[string]*tenantLimit),
+		iOpts:               iOpts,
+		sourceLoggerBuilder: sourceLoggerBuilder,
+	}
+}
+
+func newTenantChargedLimit(
+	limit *lookbackLimit,
+	kind TenantLimitKind,
+	tenants TenantLimits,
+) *tenantChargedLimit {
+	return &tenantChargedLimit{
+		lookbackLimit: limit,
+		kind:          kind,
+		tenants:       tenants,
+	}
+}
+
+func (l *tenantChargedLimit) Inc(val int, source []byte) error {
+	if err := l.lookbackLimit.Inc(val, source); err != nil {
+		return err
+	}
+	return l.tenants.Inc(l.kind, val, source)
+}
+
+func (t *tenantLimits) Options() map[string]TenantLimitOptions {
+	t.RLock()
+	defer t.RUnlock()
+
+	opts := make(map[string]TenantLimitOptions, len(t.limits))
+	for tenant, limit := range t.limits {
+		opts[tenant] = limit.options()
+	}
+	return opts
+}
+
+func (t *tenantLimits) Inc(kind TenantLimitKind, val int, tenant []byte) error {
+	if len(tenant) == 0 {
+		return nil
+	}
+
+	t.RLock()
+	limit, ok := t.limits[string(tenant)]
+	t.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	switch kind {
+	case TenantDocsMatched:
+		return limit.docsLimit.Inc(val, tenant)
+	case TenantBytesRead:
+		return limit.bytesReadLimit.Inc(val, tenant)
+	case TenantSeriesWritten:
+		return limit.seriesWrittenLimit.Inc(val, tenant)
+	default:
+		return fmt.Errorf("unknown tenant limit kind: %d", kind)
+	}
+}
+
+func (t *tenantLimits) Update(opts map[string]TenantLimitOptions) error {
+	for tenant, tenantOpts := range opts {
+		if err := tenantOpts.validate(); err != nil {
+			return fmt.Errorf("tenant %s limit options invalid: %w", tenant, err)
+		}
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	for tenant, limit := range t.limits {
+		if _, ok := opts[tenant]; ok {
+			continue
+		}
+		if t.started {
+			limit.stop()
+		}
+		delete(t.limits, tenant)
+	}
+
+	for tenant, tenantOpts := range opts {
+		limit, ok := t.limits[tenant]
+		if ok && t.started {
+			if err := limit.update(tenantOpts); err != nil {
+				return fmt.Errorf("could not update tenant %s limits: %w", tenant, err)
+			}
+			continue
+		}
+
+		// NB: limits which have not yet been started are recreated rather than
+		// updated since updating the lookback requires a running limit.
+		limit = t.newTenantLimit(tenant, tenantOpts)
+		if t.started {
+			limit.start()
+		}
+		t.limits[tenant] = limit
+	}
+
+	return nil
+}
+
+func (t *tenantLimits) Start() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.started = true
+	for _, limit := range t.limits {
+		limit.start()
+	}
+}
+
+func (t *tenantLimits) Stop() {
+	t.Lock()
+	defer t.Unlock()
+
+	if !t.started {
+		return
+	}
+	t.started = false
+	for _, limit := range t.limits {
+		limit.stop()
+	}
+}
+
+func (t *tenantLimits) newTenantLimit(
+	tenant string,
+	opts TenantLimitOptions,
+) *tenantLimit {
+	var (
+		docsMatched   = "docs-matched"
+		bytesRead     = "disk-bytes-read"
+		seriesWritten = "series-written"
+	)
+	return &tenantLimit{
+		docsLimit: newLookbackLimit(limitNames{
+			limitName:  docsMatched,
+			metricName: docsMatched,
+			metricType: "fetch",
+			tenant:     tenant,
+		}, opts.DocsLimitOpts, t.iOpts, t.sourceLoggerBuilder),
+		bytesReadLimit: newLookbackLimit(limitNames{
+			limitName:  bytesRead,
+			metricName: bytesRead,
+			metricType: "read",
+			tenant:     tenant,
+		}, opts.BytesReadLimitOpts, t.iOpts, t.sourceLoggerBuilder),
+		seriesWrittenLimit: newLookbackLimit(limitNames{
+			limitName:  seriesWritten,
+			metricName: seriesWritten,
+			metricType: "write",
+			tenant:     tenant,
+		}, opts.SeriesWrittenLimitOpts, t.iOpts, t.sourceLoggerBuilder),
+	}
+}
+
+func (l *tenantLimit) options() TenantLimitOptions {
+	return TenantLimitOptions{
+		DocsLimitOpts:          l.docsLimit.Options(),
+		BytesReadLimitOpts:     l.bytesReadLimit.Options(),
+		SeriesWrittenLimitOpts: l.seriesWrittenLimit.Options(),
+	}
+}
+
+func (l *tenantLimit) update(opts TenantLimitOptions) error {
+	for _, u := range []struct {
+		limit *lookbackLimit
+		opts  LookbackLimitOptions
+	}{
+		{limit: l.docsLimit, opts: opts.DocsLimitOpts},
+		{limit: l.bytesReadLimit, opts: opts.BytesReadLimitOpts},
+		{limit: l.seriesWrittenLimit, opts: opts.SeriesWrittenLimitOpts},
+	} {
+		if u.limit.Options().Equals(u.opts) {
+			continue
+		}
+		if err := u.limit.Update(u.opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *tenantLimit) start() {
+	l.docsLimit.Start()
+	l.bytesReadLimit.Start()
+	l.seriesWrittenLimit.Start()
+}
+
+func (l *tenantLimit) stop() {
+	l.docsLimit.Stop()
+	l.bytesReadLimit.Stop()
+	l.seriesWrittenLimit.Stop()
+}
+
+func (opts TenantLimitOptions) validate() error {
+	if err := opts.DocsLimitOpts.validate(); err != nil {
+		return fmt.Errorf("doc limit options invalid: %w", err)
+	}
+	if err := opts.BytesReadLimitOpts.validate(); err != nil {
+		return fmt.Errorf("bytes limit options invalid: %w", err)
+	}
+	if err := opts.SeriesWrittenLimitOpts.validate(); err != nil {
+		return fmt.Errorf("series written limit options invalid: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package limits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+func testTenantLimitOptions(docs, bytes, series int64) TenantLimitOptions {
+	return TenantLimitOptions{
+		DocsLimitOpts:          LookbackLimitOptions{Limit: docs, Lookback: time.Minute},
+		BytesReadLimitOpts:     LookbackLimitOptions{Limit: bytes, Lookback: time.Minute},
+		SeriesWrittenLimitOpts: LookbackLimitOptions{Limit: series, Lookback: time.Minute},
+	}
+}
+
+func TestTenantLimits(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	iOpts := instrument.NewOptions().SetMetricsScope(scope)
+	limits := newTenantLimits(iOpts, &sourceLoggerBuilder{})
+	limits.Start()
+	defer limits.Stop()
+
+	require.NoError(t, limits.Update(map[string]TenantLimitOptions{
+		"foo": testTenantLimitOptions(2, 10, 3),
+	}))
+
+	// Unknown and empty tenants are not limited.
+	require.NoError(t, limits.Inc(TenantDocsMatched, 100, []byte("bar")))
+	require.NoError(t, limits.Inc(TenantDocsMatched, 100, nil))
+
+	require.NoError(t, limits.Inc(TenantDocsMatched, 1, []byte("foo")))
+	require.NoError(t, limits.Inc(TenantBytesRead, 5, []byte("foo")))
+	require.NoError(t, limits.Inc(TenantSeriesWritten, 2, []byte("foo")))
+
+	err := limits.Inc(TenantDocsMatched, 1, []byte("foo"))
+	require.Error(t, err)
+	require.True(t, xerrors.IsInvalidParams(err))
+	require.True(t, IsQueryLimitExceededError(err))
+	assert.Contains(t, err.Error(), "tenant=foo")
+	assert.Contains(t, err.Error(), "name=docs-matched")
+
+	err = limits.Inc(TenantSeriesWritten, 1, []byte("foo"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name=series-written")
+
+	require.NoError(t, limits.Inc(TenantBytesRead, 4, []byte("foo")))
+	require.Error(t, limits.Inc(TenantBytesRead, 1, []byte("foo")))
+
+	require.Error(t, limits.Inc(TenantLimitKind(100), 1, []byte("foo")))
+
+	snapshot := scope.Snapshot()
+	counters := snapshot.Counters()
+	exceeded, ok := counters["query-limit.exceeded+limit=docs-matched,tenant=foo,type=fetch"]
+	require.True(t, ok)
+	assert.Equal(t, int64(1), exceeded.Value())
+	written, ok := counters["query-limit.total-series-written+tenant=foo,type=write"]
+	require.True(t, ok)
+	assert.Equal(t, int64(3), written.Value())
+}
+
+func TestTenantLimitsUpdate(t *testing.T) {
+	limits := newTenantLimits(instrument.NewOptions(), &sourceLoggerBuilder{})
+
+	// Updating before starting recreates the limits.
+	opts := map[string]TenantLimitOptions{
+		"foo": testTenantLimitOptions(1, 1, 1),
+	}
+	require.NoError(t, limits.Update(opts))
+	opts["foo"] = testTenantLimitOptions(5, 5, 5)
+	require.NoError(t, limits.Update(opts))
+	assert.Equal(t, opts, limits.Options())
+
+	limits.Start()
+	defer limits.Stop()
+
+	// Updating started limits, including the lookback.
+	updated := testTenantLimitOptions(2, 2, 2)
+	updated.SeriesWrittenLimitOpts.Lookback = time.Hour
+	opts = map[string]TenantLimitOptions{
+		"foo": updated,
+		"bar": testTenantLimitOptions(1, 1, 1),
+	}
+	require.NoError(t, limits.Update(opts))
+	assert.Equal(t, opts, limits.Options())
+	require.NoError(t, limits.Inc(TenantSeriesWritten, 1, []byte("foo")))
+	require.Error(t, limits.Inc(TenantSeriesWritten, 1, []byte("foo")))
+	require.Error(t, limits.Inc(TenantSeriesWritten, 1, []byte("bar")))
+
+	// Removed tenants are no longer limited.
+	delete(opts, "bar")
+	require.NoError(t, limits.Update(opts))
+	assert.Equal(t, opts, limits.Options())
+	require.NoError(t, limits.Inc(TenantSeriesWritten, 10, []byte("bar")))
+
+	// Invalid options are rejected without applying any update.
+	invalid := testTenantLimitOptions(1, 1, 1)
+	invalid.BytesReadLimitOpts.Lookback = 0
+	require.Error(t, limits.Update(map[string]TenantLimitOptions{"baz": invalid}))
+	assert.Equal(t, opts, limits.Options())
+}
+
+func TestQueryLimitsChargeTenants(t *testing.T) {
+	opts := DefaultLimitsOptions(instrument.NewOptions())
+	queryLimits, err := NewQueryLimits(opts)
+	require.NoError(t, err)
+	queryLimits.Start()
+	defer queryLimits.Stop()
+
+	require.NoError(t, queryLimits.TenantLimits().Update(map[string]TenantLimitOptions{
+		"foo": testTenantLimitOptions(3, 2, 0),
+	}))
+
+	source := []byte("foo")
+	require.NoError(t, queryLimits.FetchDocsLimit().Inc(1, source))
+	require.NoError(t, queryLimits.AggregateDocsLimit().Inc(1, source))
+	err = queryLimits.FetchDocsLimit().Inc(1, source)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenant=foo")
+
+	require.NoError(t, queryLimits.BytesReadLimit().Inc(1, source))
+	require.Error(t, queryLimits.BytesReadLimit().Inc(1, source))
+
+	// Other sources are only bound by the global limits.
+	require.NoError(t, queryLimits.FetchDocsLimit().Inc(100, []byte("bar")))
+	require.NoError(t, queryLimits.BytesReadLimit().Inc(100, nil))
+}
@@ -47,6 +47,10 @@ type QueryLimits interface {
 	// concurrent count of index docs matched.
 	AggregateDocsLimit() LookbackLimit
 
+	// TenantLimits limits queries and writes by per-tenant budgets, where the
+	// tenant is the source of the request.
+	TenantLimits() TenantLimits
+
 	// Start begins background resetting of the query limits.
 	Start()
 	// Stop end background resetting of the query limits.
@@ -81,6 +85,46 @@ type LookbackLimitOptions struct {
 	ForceWaited bool
 }
 
+// TenantLimits provides an interface for limits enforced per tenant.
+type TenantLimits interface {
+	// Options returns the current per-tenant limit options, keyed by tenant.
+	Options() map[string]TenantLimitOptions
+	// Inc increments the recent value of the given kind for the tenant and
+	// returns an error naming the tenant if its budget is exceeded.
+	// Tenants without configured limits are not limited.
+	Inc(kind TenantLimitKind, new int, tenant []byte) error
+	// Update replaces the per-tenant limit options, tenants absent from
+	// the given options are no longer limited.
+	Update(opts map[string]TenantLimitOptions) error
+
+	// Start begins background resetting of the tenant limits.
+	Start()
+	// Stop end background resetting of the tenant limits.
+	Stop()
+}
+
+// TenantLimitKind is a kind of budget enforced per tenant.
+type TenantLimitKind uint
+
+const (
+	// TenantDocsMatched limits the index docs matched by a tenant's queries.
+	TenantDocsMatched TenantLimitKind = iota
+	// TenantBytesRead limits the bytes read from disk by a tenant's queries.
+	TenantBytesRead
+	// TenantSeriesWritten limits the series written by a tenant's writes.
+	TenantSeriesWritten
+)
+
+// TenantLimitOptions holds the options for the limits of a single tenant.
+type TenantLimitOptions struct {
+	// DocsLimitOpts limits the index docs matched.
+	DocsLimitOpts LookbackLimitOptions
+	// BytesReadLimitOpts limits the bytes read from disk.
+	BytesReadLimitOpts LookbackLimitOptions
+	// SeriesWrittenLimitOpts limits the series written.
+	SeriesWrittenLimitOpts LookbackLimitOptions
+}
+
 // SourceLoggerBuilder builds a SourceLogger given instrument options.
 type SourceLoggerBuilder interface {
 	// NewSourceLogger builds a source logger.
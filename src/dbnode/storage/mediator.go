@@ -79,6 +79,9 @@ type mediator struct {
 	databaseColdFlushManager
 	databaseTickManager
 
+	rollupManager       databaseRollupManager
+	resizeManager       databaseResizeManager
+	backupManager       databaseBackupManager
 	opts                Options
 	nowFn               clock.NowFn
 	sleepFn             sleepFn
@@ -127,6 +130,15 @@ func newMediator(database database, commitlog commitlog.CommitLog, opts Options)
 	cfm := newColdFlushManager(database, pm, opts)
 	d.databaseColdFlushManager = cfm
 
+	d.rollupManager = newRollupManager(database, opts)
+	d.resizeManager = newResizeManager(database, opts)
+	// NB: Backups run on the same thread as warm flushes and snapshots and
+	// pause the file operations that run on the cold flush thread.
+	d.backupManager = newBackupManager(database, []pausableFileOps{
+		d.databaseColdFlushManager,
+		d.rollupManager,
+		d.resizeManager,
+	}, opts)
 	d.databaseTickManager = newTickManager(database, opts)
 	d.databaseBootstrapManager = newBootstrapManager(database, d, opts)
 	return d, nil
@@ -183,6 +195,14 @@ func (m *mediator) DisableFileOpsAndWait() {
 		m.sleepFn(fileOpCheckInterval)
 		status = m.databaseFileSystemManager.Status()
 	}
+	// Backups run after the fs manager on the same thread and re-enable the
+	// file operations they pause once done, so wait for them before
+	// disabling those.
+	status = m.backupManager.Disable()
+	for status == fileOpInProgress {
+		m.sleepFn(fileOpCheckInterval)
+		status = m.backupManager.Status()
+	}
 	// Even though the cold flush runs separately, its still
 	// considered a fs process.
 	status = m.databaseColdFlushManager.Disable()
@@ -190,6 +210,17 @@ func (m *mediator) DisableFileOpsAndWait() {
 		m.sleepFn(fileOpCheckInterval)
 		status = m.databaseColdFlushManager.Status()
 	}
+	// Rollups write to the target namespace and run alongside cold flushes.
+	status = m.rollupManager.Disable()
+	for status == fileOpInProgress {
+		m.sleepFn(fileOpCheckInterval)
+		status = m.rollupManager.Status()
+	}
+	status = m.resizeManager.Disable()
+	for status == fileOpInProgress {
+		m.sleepFn(fileOpCheckInterval)
+		status = m.resizeManager.Status()
+	}
 }
 
 func (m *mediator) EnableFileOps() {
@@ -197,12 +228,18 @@ func (m *mediator) EnableFileOps() {
 	// Even though the cold flush runs separately, its still
 	// considered a fs process.
 	m.databaseColdFlushManager.Enable()
+	m.rollupManager.Enable()
+	m.resizeManager.Enable()
+	m.backupManager.Enable()
 }
 
 func (m *mediator) Report() {
 	m.databaseBootstrapManager.Report()
 	m.databaseFileSystemManager.Report()
 	m.databaseColdFlushManager.Report()
+	m.rollupManager.Report()
+	m.resizeManager.Report()
+	m.backupManager.Report()
 
 	for _, process := range m.backgroundProcesses {
 		process.Report()
@@ -319,12 +356,19 @@ func (m *mediator) runFileSystemProcesses() {
 	// See comment over mediatorTimeBarrier for an explanation of this logic.
 	mediatorTime := m.mediatorTimeBarrier.fsProcessesWait()
 	m.databaseFileSystemManager.Run(mediatorTime)
+	m.backupManager.Run(mediatorTime)
 }
 
 func (m *mediator) runColdFlushProcesses() {
 	// See comment over mediatorTimeBarrier for an explanation of this logic.
 	mediatorTime := m.mediatorTimeBarrier.fsProcessesWait()
 	m.databaseColdFlushManager.Run(mediatorTime)
+	// NB: Rollups run after cold flushes on the same thread since tiling
+	// cold flushes the target namespace.
+	m.rollupManager.Run(mediatorTime)
+	// NB: Resizes convert the sealed blocks of namespaces being resized once
+	// any cold writes to them have been flushed.
+	m.resizeManager.Run(mediatorTime)
 }
 
 func (m *mediator) reportLoop() {
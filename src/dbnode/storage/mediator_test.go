@@ -148,6 +148,18 @@ func TestDatabaseMediatorEnqueueMutuallyExclusiveFnAndExecute(t *testing.T) {
 	cfm.EXPECT().Run(gomock.Any()).Return(true).AnyTimes()
 	cfm.EXPECT().Report().AnyTimes()
 	m.databaseColdFlushManager = cfm
+	rm := NewMockdatabaseRollupManager(ctrl)
+	rm.EXPECT().Run(gomock.Any()).Return(true).AnyTimes()
+	rm.EXPECT().Report().AnyTimes()
+	m.rollupManager = rm
+	rsm := NewMockdatabaseResizeManager(ctrl)
+	rsm.EXPECT().Run(gomock.Any()).Return(true).AnyTimes()
+	rsm.EXPECT().Report().AnyTimes()
+	m.resizeManager = rsm
+	bm := NewMockdatabaseBackupManager(ctrl)
+	bm.EXPECT().Run(gomock.Any()).Return(true).AnyTimes()
+	bm.EXPECT().Report().AnyTimes()
+	m.backupManager = bm
 
 	require.NoError(t, med.Open())
 	defer func() {
@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/persist"
 	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/schema"
+	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/sharding"
 	"github.com/m3db/m3/src/dbnode/storage/block"
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap"
@@ -44,6 +45,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
@@ -54,6 +56,7 @@ import (
 	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xopentracing "github.com/m3db/m3/src/x/opentracing"
+	"github.com/m3db/m3/src/x/pool"
 	xresource "github.com/m3db/m3/src/x/resource"
 	xsync "github.com/m3db/m3/src/x/sync"
 	xtime "github.com/m3db/m3/src/x/time"
@@ -135,6 +138,7 @@ type dbNamespace struct {
 	increasingIndex increasingIndex
 	commitLogWriter commitLogWriter
 	reverseIndex    NamespaceIndex
+	tombstones      tombstone.Store
 
 	tickWorkers            xsync.WorkerPool
 	tickWorkersConcurrency int
@@ -171,6 +175,7 @@ type databaseNamespaceMetrics struct {
 	queryIDs            instrument.MethodMetrics
 	wideQuery           instrument.MethodMetrics
 	aggregateQuery      instrument.MethodMetrics
+	deleteTagged        instrument.MethodMetrics
 
 	unfulfilled             tally.Counter
 	bootstrapStart          tally.Counter
@@ -269,6 +274,7 @@ func newDatabaseNamespaceMetrics(
 		queryIDs:            instrument.NewMethodMetrics(scope, "queryIDs", opts),
 		wideQuery:           instrument.NewMethodMetrics(scope, "wideQuery", opts),
 		aggregateQuery:      instrument.NewMethodMetrics(scope, "aggregateQuery", opts),
+		deleteTagged:        instrument.NewMethodMetrics(scope, "deleteTagged", opts),
 
 		unfulfilled:             bootstrapScope.Counter("unfulfilled"),
 		bootstrapStart:          bootstrapScope.Counter("start"),
@@ -344,6 +350,9 @@ func newDatabaseNamespace(
 			"namespace": id.String(),
 		}))
 	opts = opts.SetInstrumentOptions(iops)
+	if nopts.EncodingScheme() == namespace.XOREncodingScheme {
+		opts = withXOREncoding(opts)
+	}
 
 	scope := iops.MetricsScope().SubScope("database")
 
@@ -353,20 +362,31 @@ func newDatabaseNamespace(
 
 	seriesOpts := NewSeriesOptionsFromOptions(opts, nopts.RetentionOptions()).
 		SetStats(series.NewStats(scope)).
-		SetColdWritesEnabled(nopts.ColdWritesEnabled())
+		SetColdWritesEnabled(nopts.ColdWritesEnabled()).
+		SetLateWritesEnabled(nopts.LateWritesEnabled())
 	if err := seriesOpts.Validate(); err != nil {
 		return nil, fmt.Errorf(
 			"unable to create namespace %v, invalid series options: %v",
 			metadata.ID().String(), err)
 	}
 
-	var (
-		index NamespaceIndex
-		err   error
-	)
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	tombstones, err := tombstone.NewStore(tombstone.Options{
+		FilePath:         fs.NamespaceTombstonesFilePath(fsOpts.FilePathPrefix(), id),
+		BlockSize:        nopts.RetentionOptions().BlockSize(),
+		NewFileMode:      fsOpts.NewFileMode(),
+		NewDirectoryMode: fsOpts.NewDirectoryMode(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to create namespace %v, could not load tombstones: %v",
+			metadata.ID().String(), err)
+	}
+
+	var index NamespaceIndex
 	if metadata.Options().IndexOptions().Enabled() {
 		index, err = newNamespaceIndex(metadata, namespaceRuntimeOptsMgr,
-			shardSet, opts)
+			shardSet, tombstones, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -388,6 +408,7 @@ func newDatabaseNamespace(
 		increasingIndex:        increasingIndex,
 		commitLogWriter:        commitLogWriter,
 		reverseIndex:           index,
+		tombstones:             tombstones,
 		tickWorkers:            tickWorkers,
 		tickWorkersConcurrency: tickWorkersConcurrency,
 		metrics:                newDatabaseNamespaceMetrics(scope, iops.TimerOptions()),
@@ -411,6 +432,22 @@ func newDatabaseNamespace(
 	return n, nil
 }
 
+// withXOREncoding returns a copy of the options whose encoders and iterators
+// use the encoding compatible with Prometheus XOR chunks.
+func withXOREncoding(opts Options) Options {
+	iopts := opts.InstrumentOptions()
+	poolOpts := pool.NewObjectPoolOptions().SetInstrumentOptions(
+		iopts.SetMetricsScope(iopts.MetricsScope().SubScope("xor-encoding-pool")))
+	blockOpts := block.NewXOROptions(opts.DatabaseBlockOptions(), poolOpts)
+	// NB: the database block options are set last as the series options
+	// are derived from them and the pools.
+	return opts.
+		SetEncoderPool(blockOpts.EncoderPool()).
+		SetReaderIteratorPool(blockOpts.ReaderIteratorPool()).
+		SetMultiReaderIteratorPool(blockOpts.MultiReaderIteratorPool()).
+		SetDatabaseBlockOptions(blockOpts)
+}
+
 // SetSchemaHistory implements namespace.SchemaListener.
 func (n *dbNamespace) SetSchemaHistory(value namespace.SchemaHistory) {
 	n.Lock()
@@ -547,7 +584,7 @@ func (n *dbNamespace) assignShardSet(
 		// Otherwise it's the initial assignment or there isn't an existing
 		// shard created for this shard ID.
 		n.shards[shard] = newDatabaseShard(metadata, shard, n.blockRetriever,
-			n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex,
+			n.namespaceReaderMgr, n.increasingIndex, n.reverseIndex, n.tombstones,
 			opts.needsBootstrap, n.opts, n.seriesOpts)
 		createdShardIds = append(createdShardIds, shard)
 		// NB(bodu): We only record shard add metrics for shards created in non
@@ -668,6 +705,12 @@ func (n *dbNamespace) Tick(c context.Cancellable, startTime xtime.UnixNano) erro
 		}
 	}
 
+	// Drop tombstones of data that is no longer retained.
+	earliest := retention.FlushTimeStart(n.nopts.RetentionOptions(), startTime)
+	if err := n.tombstones.Prune(earliest); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+
 	// NB: we early terminate here to ensure we are not reporting metrics
 	// based on in-accurate/partial tick results.
 	if err := multiErr.FinalError(); err != nil || c.IsCancelled() {
@@ -910,6 +953,42 @@ func (n *dbNamespace) AggregateQuery(
 	return res, err
 }
 
+func (n *dbNamespace) DeleteTagged(
+	ctx context.Context,
+	query index.Query,
+	start, end xtime.UnixNano,
+) (int, error) {
+	callStart := n.nowFn()
+	res, err := n.QueryIDs(ctx, query, index.QueryOptions{
+		StartInclusive: start,
+		EndExclusive:   end,
+	})
+	if err != nil {
+		n.metrics.deleteTagged.ReportError(n.nowFn().Sub(callStart))
+		return 0, err
+	}
+
+	var (
+		deleted    = xtime.Range{Start: start, End: end}
+		tombstones = make([]tombstone.Tombstone, 0, res.Results.Size())
+	)
+	for _, entry := range res.Results.Map().Iter() {
+		id := entry.Key()
+		tombstones = append(tombstones, tombstone.Tombstone{
+			ID:    append([]byte(nil), id...),
+			Shard: n.shardSet.Lookup(ident.BytesID(id)),
+			Range: deleted,
+		})
+	}
+
+	err = n.tombstones.Add(tombstones)
+	n.metrics.deleteTagged.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
+	if err != nil {
+		return 0, err
+	}
+	return len(tombstones), nil
+}
+
 func (n *dbNamespace) PrepareBootstrap(ctx context.Context) ([]databaseShard, error) {
 	ctx, span, sampled := ctx.StartSampledTraceSpan(tracepoint.NSPrepareBootstrap)
 	defer span.Finish()
@@ -961,7 +1040,17 @@ func (n *dbNamespace) ReadEncoded(
 	}
 	res, err := shard.ReadEncoded(ctx, id, start, end, nsCtx)
 	n.metrics.read.ReportSuccessOrError(err, n.nowFn().Sub(callStart))
-	return res, err
+	if err != nil || res == nil || n.tombstones.Len() == 0 {
+		return res, err
+	}
+
+	// Drop any of the series' data that has been deleted but may not yet
+	// have been dropped from memory or disk.
+	if deleted := n.tombstones.Ranges(id.Bytes()); len(deleted) > 0 {
+		res = newDeletedBlockReaderIter(res, deleted,
+			n.nopts.RetentionOptions().BlockSize(), n.opts, nsCtx)
+	}
+	return res, nil
 }
 
 func (n *dbNamespace) FetchWideEntry(
@@ -1295,6 +1384,20 @@ func (r *coldFlushReusableResources) reset() {
 	r.dirtySeries.Reset()
 }
 
+// hasPendingTombstones returns whether any of the shards have deleted series
+// pending removal from their filesets.
+func (n *dbNamespace) hasPendingTombstones(shards []databaseShard) bool {
+	if n.tombstones == nil {
+		return false
+	}
+	for _, shard := range shards {
+		if len(n.tombstones.PendingBlocks(shard.ID())) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 	// NB(rartoul): This value can be used for emitting metrics, but should not be used
 	// for business logic.
@@ -1311,14 +1414,16 @@ func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 	n.RUnlock()
 
 	// If repair has run we still need cold flush regardless of whether cold writes is
-	// enabled since repairs are dependent on the cold flushing logic.
-	enabled := n.nopts.ColdWritesEnabled() || repairsAny
+	// enabled since repairs are dependent on the cold flushing logic, the same
+	// goes for removing deleted series from disk.
+	shards := n.OwnedShards()
+	enabled := n.nopts.ColdWritesEnabled() || repairsAny ||
+		n.hasPendingTombstones(shards)
 	if n.ReadOnly() || !enabled {
 		n.metrics.flushColdData.ReportSuccess(n.nowFn().Sub(callStart))
 		return nil
 	}
 
-	shards := n.OwnedShards()
 	resources := newColdFlushReusableResources(n.opts)
 
 	// NB(bodu): The in-mem index will lag behind the TSDB in terms of new series writes. For a period of
@@ -1379,6 +1484,11 @@ func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 		for _, shardColdFlush := range shardColdFlushes {
 			multiErr = multiErr.Add(shardColdFlush.Done())
 		}
+		if n.nopts.LateWritesEnabled() {
+			// Late writes are persisted to sidecar volumes rather than merged
+			// into the filesets of their blocks, see shard.LateFlush.
+			multiErr = multiErr.Add(n.lateFlush(shards, nsCtx))
+		}
 	}
 	multiErr = multiErr.Add(indexColdFlushError)
 
@@ -1387,6 +1497,20 @@ func (n *dbNamespace) ColdFlush(flushPersist persist.FlushPreparer) error {
 	return res
 }
 
+func (n *dbNamespace) lateFlush(shards []databaseShard, nsCtx namespace.Context) error {
+	multiErr := xerrors.NewMultiError()
+	for _, shard := range shards {
+		if !shard.IsBootstrapped() {
+			continue
+		}
+		if err := shard.LateFlush(nsCtx); err != nil {
+			detailedErr := fmt.Errorf("shard %d failed to flush late writes: %v", shard.ID(), err)
+			multiErr = multiErr.Add(detailedErr)
+		}
+	}
+	return multiErr.FinalError()
+}
+
 func (n *dbNamespace) FlushIndex(flush persist.IndexFlush) error {
 	callStart := n.nowFn()
 	n.RLock()
@@ -102,6 +102,7 @@ type namespaceReaderManager struct {
 
 	namespace         namespace.Metadata
 	fsOpts            fs.Options
+	offloader         fs.FileSetOffloader
 	blockLeaseManager block.LeaseManager
 	bytesPool         pool.CheckedBytesPool
 
@@ -163,6 +164,7 @@ func newNamespaceReaderManager(
 		newReaderFn:       fs.NewReader,
 		namespace:         namespace,
 		fsOpts:            opts.CommitLogOptions().FilesystemOptions(),
+		offloader:         opts.FileSetOffloader(),
 		blockLeaseManager: blm,
 		bytesPool:         opts.BytesPool(),
 		logger:            opts.InstrumentOptions().Logger(),
@@ -303,6 +305,14 @@ func (m *namespaceReaderManager) get(
 		position.metadataIdx = 0
 	}
 
+	// The data files of the volume may have been offloaded to the blob store,
+	// in which case they need to be restored before they can be read.
+	if m.offloader != nil {
+		if err := m.offloader.Restore(m.namespace.ID(), shard, blockStart); err != nil {
+			return nil, err
+		}
+	}
+
 	key := cachedOpenReaderKey{
 		shard:      shard,
 		blockStart: blockStart,
@@ -29,6 +29,8 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/xor"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
@@ -39,8 +41,11 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
+	"github.com/m3db/m3/src/dbnode/ts"
 	xmetrics "github.com/m3db/m3/src/dbnode/x/metrics"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	xidx "github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/x/context"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -223,6 +228,40 @@ func TestNamespaceReadOnlyRejectWrites(t *testing.T) {
 	require.False(t, seriesWrite.WasWritten)
 }
 
+func TestNamespaceXOREncodingScheme(t *testing.T) {
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	attrs, err := namespace.NewAggregatedAttributes(time.Minute, namespace.NewDownsampleOptions(true))
+	require.NoError(t, err)
+	ns, closer := newTestNamespaceWithIDOpts(t, defaultTestNs1ID,
+		defaultTestNs1Opts.
+			SetEncodingScheme(namespace.XOREncodingScheme).
+			SetAggregationOptions(namespace.NewAggregationOptions().
+				SetAggregations([]namespace.Aggregation{namespace.NewAggregatedAggregation(attrs)})))
+	defer closer()
+
+	var (
+		start    = xtime.Now().Truncate(time.Hour)
+		dp       = ts.Datapoint{TimestampNanos: start, Value: 42}
+		expected = xor.NewEncoder(start, nil, nil)
+	)
+	require.NoError(t, expected.Encode(dp, xtime.Second, nil))
+	expectedSegment := expected.Discard()
+
+	// Series and blocks of the namespace encode datapoints with the xor encoding.
+	for _, pool := range []encoding.EncoderPool{
+		ns.opts.EncoderPool(),
+		ns.seriesOpts.EncoderPool(),
+		ns.seriesOpts.DatabaseBlockOptions().EncoderPool(),
+	} {
+		enc := pool.Get()
+		enc.Reset(start, 0, nil)
+		require.NoError(t, enc.Encode(dp, xtime.Second, nil))
+		require.Equal(t, expectedSegment.Head.Bytes(), enc.Discard().Head.Bytes())
+	}
+}
+
 func TestNamespaceWriteShardOwned(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -683,6 +722,43 @@ func TestNamespaceFlushSkipShardNotBootstrapped(t *testing.T) {
 	require.NoError(t, ns.ColdFlush(nil))
 }
 
+func TestNamespaceColdFlushPendingTombstonesColdWritesDisabled(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	ns, closer := newTestNamespace(t)
+	defer closer()
+
+	require.False(t, ns.nopts.ColdWritesEnabled())
+	ns.bootstrapState = Bootstrapped
+
+	shardColdFlush := NewMockShardColdFlush(ctrl)
+	shardColdFlush.EXPECT().Done().Return(nil)
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(testShardIDs[0].ID()).AnyTimes()
+	shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	ns.shards[testShardIDs[0].ID()] = shard
+
+	// Without pending tombstones the cold flush is skipped.
+	require.NoError(t, ns.ColdFlush(nil))
+
+	blockSize := ns.Options().RetentionOptions().BlockSize()
+	blockStart := xtime.Now().Truncate(blockSize).Add(-2 * blockSize)
+	require.NoError(t, ns.tombstones.Add([]tombstone.Tombstone{
+		{
+			ID:    []byte("foo"),
+			Shard: testShardIDs[0].ID(),
+			Range: xtime.Range{Start: blockStart, End: blockStart.Add(blockSize)},
+		},
+	}))
+
+	shard.EXPECT().
+		ColdFlush(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(shardColdFlush, nil)
+	require.NoError(t, ns.ColdFlush(nil))
+}
+
 type snapshotTestCase struct {
 	isSnapshotting                bool
 	expectSnapshot                bool
@@ -1389,6 +1465,56 @@ func TestNamespaceIndexQuery(t *testing.T) {
 	assert.Equal(t, "root", spans[1].OperationName)
 }
 
+func TestNamespaceDeleteTagged(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	idx := NewMockNamespaceIndex(ctrl)
+	idx.EXPECT().Bootstrapped().Return(true)
+
+	ns, closer := newTestNamespaceWithIndex(t, idx)
+	defer closer()
+
+	tombstones, err := tombstone.NewStore(tombstone.Options{
+		BlockSize: ns.Options().RetentionOptions().BlockSize(),
+	})
+	require.NoError(t, err)
+	ns.tombstones = tombstones
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	var (
+		query = index.Query{
+			Query: xidx.NewTermQuery([]byte("foo"), []byte("bar")),
+		}
+		end   = xtime.Now()
+		start = end.Add(-time.Hour)
+	)
+	results := index.NewQueryResults(ns.ID(), index.QueryResultsOptions{},
+		DefaultTestOptions().IndexOptions())
+	_, _, err = results.AddDocuments([]doc.Document{
+		doc.NewDocumentFromMetadata(doc.Metadata{ID: []byte("a")}),
+		doc.NewDocumentFromMetadata(doc.Metadata{ID: []byte("b")}),
+	})
+	require.NoError(t, err)
+	idx.EXPECT().
+		Query(gomock.Any(), query, index.QueryOptions{
+			StartInclusive: start,
+			EndExclusive:   end,
+		}).
+		Return(index.QueryResult{Results: results}, nil)
+
+	deleted, err := ns.DeleteTagged(ctx, query, start, end)
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	expected := []xtime.Range{{Start: start, End: end}}
+	require.Equal(t, expected, tombstones.Ranges([]byte("a")))
+	require.Equal(t, expected, tombstones.Ranges([]byte("b")))
+	require.Empty(t, tombstones.Ranges([]byte("c")))
+}
+
 func TestNamespaceIndexWideQuery(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/backup"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
@@ -141,6 +142,10 @@ type options struct {
 	bootstrapProcessProvider        bootstrap.ProcessProvider
 	persistManager                  persist.Manager
 	indexClaimsManager              fs.IndexClaimsManager
+	fileSetOffloader                fs.FileSetOffloader
+	namespaceResizeStatusReporter   namespace.ResizeStatusReporter
+	backupCoordinator               backup.Coordinator
+	backupDirectory                 string
 	blockRetrieverManager           block.DatabaseBlockRetrieverManager
 	poolOpts                        pool.ObjectPoolOptions
 	contextPool                     context.Pool
@@ -571,6 +576,46 @@ func (o *options) IndexClaimsManager() fs.IndexClaimsManager {
 	return o.indexClaimsManager
 }
 
+func (o *options) SetFileSetOffloader(value fs.FileSetOffloader) Options {
+	opts := *o
+	opts.fileSetOffloader = value
+	return &opts
+}
+
+func (o *options) FileSetOffloader() fs.FileSetOffloader {
+	return o.fileSetOffloader
+}
+
+func (o *options) SetNamespaceResizeStatusReporter(value namespace.ResizeStatusReporter) Options {
+	opts := *o
+	opts.namespaceResizeStatusReporter = value
+	return &opts
+}
+
+func (o *options) NamespaceResizeStatusReporter() namespace.ResizeStatusReporter {
+	return o.namespaceResizeStatusReporter
+}
+
+func (o *options) SetBackupCoordinator(value backup.Coordinator) Options {
+	opts := *o
+	opts.backupCoordinator = value
+	return &opts
+}
+
+func (o *options) BackupCoordinator() backup.Coordinator {
+	return o.backupCoordinator
+}
+
+func (o *options) SetBackupDirectory(value string) Options {
+	opts := *o
+	opts.backupDirectory = value
+	return &opts
+}
+
+func (o *options) BackupDirectory() string {
+	return o.backupDirectory
+}
+
 func (o *options) SetDatabaseBlockRetrieverManager(value block.DatabaseBlockRetrieverManager) Options {
 	opts := *o
 	opts.blockRetrieverManager = value
@@ -0,0 +1,823 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/dbnode/storage/index/convert"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/m3ninx/doc"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xos "github.com/m3db/m3/src/x/os"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const resizeManifestTmpSuffix = ".tmp"
+
+type resizeManagerMetrics struct {
+	status          tally.Gauge
+	convertedBlocks tally.Counter
+	errors          tally.Counter
+}
+
+func newResizeManagerMetrics(scope tally.Scope) resizeManagerMetrics {
+	return resizeManagerMetrics{
+		status:          scope.Gauge("resize"),
+		convertedBlocks: scope.Counter("converted-blocks"),
+		errors:          scope.Counter("errors"),
+	}
+}
+
+type resizeReport struct {
+	state     namespace.ResizeState
+	converted bool
+}
+
+// resizeManager converts the sealed blocks of namespaces with an in-progress
+// resize to the new block size ahead of the switch over, and reports whether
+// all sealed blocks of each namespace have been converted.
+type resizeManager struct {
+	sync.RWMutex
+
+	log      *zap.Logger
+	database database
+	opts     Options
+	resizer  *namespaceResizer
+	metrics  resizeManagerMetrics
+	status   fileOpStatus
+	enabled  bool
+	reported map[string]resizeReport
+}
+
+func newResizeManager(database database, opts Options) databaseResizeManager {
+	var (
+		instrumentOpts = opts.InstrumentOptions()
+		scope          = instrumentOpts.MetricsScope().SubScope("resize")
+	)
+	return &resizeManager{
+		log:      instrumentOpts.Logger(),
+		database: database,
+		opts:     opts,
+		resizer:  newNamespaceResizer(opts),
+		metrics:  newResizeManagerMetrics(scope),
+		status:   fileOpNotStarted,
+		enabled:  true,
+		reported: make(map[string]resizeReport),
+	}
+}
+
+func (m *resizeManager) Disable() fileOpStatus {
+	m.Lock()
+	status := m.status
+	m.enabled = false
+	m.Unlock()
+	return status
+}
+
+func (m *resizeManager) Enable() fileOpStatus {
+	m.Lock()
+	status := m.status
+	m.enabled = true
+	m.Unlock()
+	return status
+}
+
+func (m *resizeManager) Status() fileOpStatus {
+	m.RLock()
+	status := m.status
+	m.RUnlock()
+	return status
+}
+
+func (m *resizeManager) Run(t xtime.UnixNano) bool {
+	m.Lock()
+	if !m.shouldRunWithLock() {
+		m.Unlock()
+		return false
+	}
+	m.status = fileOpInProgress
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.status = fileOpNotStarted
+		m.Unlock()
+	}()
+
+	if err := m.resize(t); err != nil {
+		m.metrics.errors.Inc(1)
+		m.log.Error("error resizing namespaces",
+			zap.Time("time", t.ToTime()), zap.Error(err))
+	}
+	return true
+}
+
+func (m *resizeManager) Report() {
+	if m.Status() == fileOpInProgress {
+		m.metrics.status.Update(1)
+	} else {
+		m.metrics.status.Update(0)
+	}
+}
+
+func (m *resizeManager) shouldRunWithLock() bool {
+	return m.enabled && m.status != fileOpInProgress && m.database.IsBootstrapped()
+}
+
+func (m *resizeManager) resize(t xtime.UnixNano) error {
+	namespaces, err := m.database.OwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		state := m.database.NamespaceResizeState(n.ID())
+		if !state.InProgress() {
+			continue
+		}
+		converted, err := m.resizeNamespace(t, n, state)
+		if err != nil {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"resize of namespace %s failed: %v", n.ID().String(), err))
+		}
+		if err := m.report(n.ID(), state, converted); err != nil {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"failed to report resize of namespace %s: %v", n.ID().String(), err))
+		}
+	}
+	return multiErr.FinalError()
+}
+
+// resizeNamespace converts the sealed blocks of a namespace, returning whether
+// the blocks of every shard have been converted up to the first block that
+// has not been sealed yet.
+func (m *resizeManager) resizeNamespace(
+	t xtime.UnixNano,
+	n databaseNamespace,
+	state namespace.ResizeState,
+) (bool, error) {
+	var (
+		srcRetention = n.Options().RetentionOptions()
+		limits       = make(map[uint32]xtime.UnixNano)
+		complete     = true
+	)
+	dst, err := namespace.NewMetadata(n.ID(),
+		namespace.ResizedOptions(n.Options().SetResizeState(state)))
+	if err != nil {
+		return false, err
+	}
+	for _, shard := range n.OwnedShards() {
+		if !shard.IsBootstrapped() {
+			complete = false
+			continue
+		}
+		limits[shard.ID()] = resizeFirstUnsealed(t, shard, srcRetention).Truncate(state.BlockSize)
+	}
+
+	converted, err := m.resizer.convert(srcRetention.BlockSize(), dst, limits)
+	m.metrics.convertedBlocks.Inc(int64(converted))
+	return complete && err == nil, err
+}
+
+// report reports the resize status of a namespace if it changed since it was
+// last reported.
+func (m *resizeManager) report(
+	id ident.ID,
+	state namespace.ResizeState,
+	converted bool,
+) error {
+	reporter := m.opts.NamespaceResizeStatusReporter()
+	if reporter == nil {
+		return nil
+	}
+
+	report := resizeReport{state: state, converted: converted}
+	if prev, ok := m.reported[id.String()]; ok && prev == report {
+		return nil
+	}
+	if err := reporter.ReportConverted(id, state, converted); err != nil {
+		return err
+	}
+	m.reported[id.String()] = report
+	return nil
+}
+
+// resizeFirstUnsealed returns the start of the first block of a shard from the
+// start of retention that has not been warm flushed, data before it is sealed.
+func resizeFirstUnsealed(
+	t xtime.UnixNano,
+	shard databaseShard,
+	ropts retention.Options,
+) xtime.UnixNano {
+	blockSize := ropts.BlockSize()
+	for blockStart := retention.FlushTimeStart(ropts, t); ; blockStart = blockStart.Add(blockSize) {
+		state, err := shard.FlushState(blockStart)
+		if err != nil || !statusIsRetrievable(state.WarmStatus) {
+			return blockStart
+		}
+	}
+}
+
+type resizeManifestSource struct {
+	BlockStart  xtime.UnixNano `json:"blockStart"`
+	VolumeIndex int            `json:"volumeIndex"`
+}
+
+type resizeManifestBlock struct {
+	VolumeIndex int                    `json:"volumeIndex"`
+	Sources     []resizeManifestSource `json:"sources"`
+}
+
+// resizeManifest tracks, for each staged block of the new block size, the
+// volume it was staged at and the source volumes it was converted from so
+// that it is only converted again once a source has a newer volume.
+type resizeManifest map[xtime.UnixNano]resizeManifestBlock
+
+// namespaceResizer converts the filesets of a namespace to a new block size by
+// merging the volumes that overlap each block of the new block size. Converted
+// filesets are staged outside of the data directory until the resize
+// completes.
+type namespaceResizer struct {
+	log            *zap.Logger
+	opts           Options
+	fsOpts         fs.Options
+	filePathPrefix string
+}
+
+func newNamespaceResizer(opts Options) *namespaceResizer {
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	return &namespaceResizer{
+		log:            opts.InstrumentOptions().Logger(),
+		opts:           opts,
+		fsOpts:         fsOpts,
+		filePathPrefix: fsOpts.FilePathPrefix(),
+	}
+}
+
+// convert stages the blocks of the destination block size that start before
+// the limit of each shard, returning the number of blocks converted. Blocks
+// already staged from the latest volumes of their sources are skipped.
+func (r *namespaceResizer) convert(
+	srcBlockSize time.Duration,
+	dst namespace.Metadata,
+	limits map[uint32]xtime.UnixNano,
+) (int, error) {
+	if len(limits) == 0 {
+		return 0, nil
+	}
+
+	var (
+		dstOpts       = dst.Options()
+		stagingPrefix = fs.NamespaceResizeFilePathPrefix(r.filePathPrefix, dst.ID(),
+			dstOpts.RetentionOptions().BlockSize())
+	)
+	reader, err := fs.NewReader(r.opts.BytesPool(), r.fsOpts)
+	if err != nil {
+		return 0, err
+	}
+	pm, err := fs.NewPersistManager(r.fsOpts.SetFilePathPrefix(stagingPrefix))
+	if err != nil {
+		return 0, err
+	}
+	defer pm.Close()
+
+	preparer, err := pm.StartFlushPersist()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		merger = fs.NewMerger(reader, r.opts.DatabaseBlockOptions().DatabaseBlockAllocSize(),
+			r.opts.SegmentReaderPool(), r.opts.MultiReaderIteratorPool(),
+			r.opts.IdentifierPool(), r.opts.EncoderPool(), r.opts.ContextPool(),
+			stagingPrefix, dstOpts)
+		nsCtx     = namespace.NewContextFrom(dst)
+		converted int
+		multiErr  = xerrors.NewMultiError()
+	)
+	for shard, limit := range limits {
+		n, err := r.convertShard(shard, limit, srcBlockSize, dst, stagingPrefix,
+			merger, preparer, nsCtx)
+		converted += n
+		if err != nil {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"failed to convert shard %d: %v", shard, err))
+		}
+	}
+	multiErr = multiErr.Add(preparer.DoneFlush())
+	return converted, multiErr.FinalError()
+}
+
+func (r *namespaceResizer) convertShard(
+	shard uint32,
+	limit xtime.UnixNano,
+	srcBlockSize time.Duration,
+	dst namespace.Metadata,
+	stagingPrefix string,
+	merger fs.Merger,
+	preparer persist.FlushPreparer,
+	nsCtx namespace.Context,
+) (int, error) {
+	var (
+		id           = dst.ID()
+		dstBlockSize = dst.Options().RetentionOptions().BlockSize()
+		manifestPath = fs.ShardResizeManifestFilePath(r.filePathPrefix, id, dstBlockSize, shard)
+	)
+	volumes, err := latestCompleteVolumes(r.filePathPrefix, id, shard)
+	if err != nil {
+		return 0, err
+	}
+	manifest, err := readResizeManifest(manifestPath)
+	if err != nil {
+		return 0, err
+	}
+
+	targets := make(map[xtime.UnixNano]struct{})
+	for blockStart := range volumes {
+		blockEnd := blockStart.Add(srcBlockSize)
+		for t := blockStart.Truncate(dstBlockSize); t.Before(blockEnd) && t.Before(limit); t = t.Add(dstBlockSize) {
+			targets[t] = struct{}{}
+		}
+	}
+	sortedTargets := make([]xtime.UnixNano, 0, len(targets))
+	for t := range targets {
+		sortedTargets = append(sortedTargets, t)
+	}
+	sort.Slice(sortedTargets, func(i, j int) bool {
+		return sortedTargets[i].Before(sortedTargets[j])
+	})
+
+	converted := 0
+	for _, blockStart := range sortedTargets {
+		var sources []resizeManifestSource
+		blockEnd := blockStart.Add(dstBlockSize)
+		for t := blockStart.Truncate(srcBlockSize); t.Before(blockEnd); t = t.Add(srcBlockSize) {
+			if volume, ok := volumes[t]; ok {
+				sources = append(sources, resizeManifestSource{BlockStart: t, VolumeIndex: volume})
+			}
+		}
+
+		prev, staged := manifest[blockStart]
+		if staged && resizeSourcesEqual(prev.Sources, sources) {
+			continue
+		}
+
+		fileIDs := make([]fs.FileSetFileIdentifier, 0, len(sources))
+		for _, source := range sources {
+			if offloader := r.opts.FileSetOffloader(); offloader != nil {
+				if err := offloader.Restore(id, shard, source.BlockStart); err != nil {
+					return converted, err
+				}
+			}
+			fileIDs = append(fileIDs, fs.FileSetFileIdentifier{
+				FileSetContentType: persist.FileSetDataContentType,
+				Namespace:          id,
+				Shard:              shard,
+				BlockStart:         source.BlockStart,
+				VolumeIndex:        source.VolumeIndex,
+			})
+		}
+
+		volume := 0
+		if staged {
+			volume = prev.VolumeIndex + 1
+		}
+		closer, err := merger.MergeResized(fileIDs, blockStart, volume, preparer, nsCtx)
+		if err != nil {
+			return converted, err
+		}
+		if err := closer(); err != nil {
+			return converted, err
+		}
+
+		manifest[blockStart] = resizeManifestBlock{VolumeIndex: volume, Sources: sources}
+		if err := r.writeResizeManifest(manifestPath, manifest); err != nil {
+			return converted, err
+		}
+		converted++
+
+		// NB: the previous volume is only removed once the manifest no longer
+		// references it, failing to remove it is harmless since the latest
+		// volume of a block is the one that is read.
+		if staged {
+			err := fs.DeleteFileSetAt(stagingPrefix, id, shard, blockStart, prev.VolumeIndex)
+			if err != nil {
+				r.log.Warn("failed to remove superseded resize volume",
+					zap.Stringer("namespace", id),
+					zap.Uint32("shard", shard),
+					zap.Time("blockStart", blockStart.ToTime()),
+					zap.Int("volume", prev.VolumeIndex),
+					zap.Error(err))
+			}
+		}
+	}
+	return converted, nil
+}
+
+// swap moves the staged filesets of a namespace resized to the given block
+// size into place. The filesets and snapshots at the previous block size, and
+// the index filesets if the index block size changed, are moved out of the
+// way until they are no longer in use.
+func (r *namespaceResizer) swap(id ident.ID, blockSize time.Duration, indexResized bool) error {
+	var (
+		stagingPrefix  = fs.NamespaceResizeFilePathPrefix(r.filePathPrefix, id, blockSize)
+		previousPrefix = fs.NamespaceResizePreviousFilePathPrefix(r.filePathPrefix, id)
+		newDirMode     = r.fsOpts.NewDirectoryMode()
+		dirs           = []func(string, ident.ID) string{
+			fs.NamespaceDataDirPath,
+			fs.NamespaceSnapshotsDirPath,
+		}
+	)
+	if indexResized {
+		dirs = append(dirs, fs.NamespaceIndexDataDirPath, fs.NamespaceIndexSnapshotDirPath)
+	}
+
+	if err := os.RemoveAll(previousPrefix); err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := moveDir(dir(r.filePathPrefix, id), dir(previousPrefix, id), newDirMode); err != nil {
+			return err
+		}
+	}
+	return moveDir(fs.NamespaceDataDirPath(stagingPrefix, id),
+		fs.NamespaceDataDirPath(r.filePathPrefix, id), newDirMode)
+}
+
+// completeResize completes a resize of a namespace to its current block size
+// that was interrupted by a restart before the node switched over to it, by
+// converting every fileset on disk and moving the converted filesets into
+// place. Data that was only persisted by snapshots at the previous block size
+// is recovered from the commit log as long as it has not been cleaned up.
+func (r *namespaceResizer) completeResize(md namespace.Metadata) error {
+	var (
+		id            = md.ID()
+		blockSize     = md.Options().RetentionOptions().BlockSize()
+		stagingPrefix = fs.NamespaceResizeFilePathPrefix(r.filePathPrefix, id, blockSize)
+	)
+	if _, err := os.Stat(stagingPrefix); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	shards, err := r.shardsOnDisk(id)
+	if err != nil {
+		return err
+	}
+	srcBlockSize, ok := r.blockSizeOnDisk(id, shards)
+	switch {
+	case !ok:
+		err := moveDir(fs.NamespaceDataDirPath(stagingPrefix, id),
+			fs.NamespaceDataDirPath(r.filePathPrefix, id), r.fsOpts.NewDirectoryMode())
+		if err != nil {
+			return err
+		}
+	case srcBlockSize != blockSize:
+		r.log.Info("completing interrupted namespace resize",
+			zap.Stringer("namespace", id),
+			zap.Duration("fromBlockSize", srcBlockSize),
+			zap.Duration("toBlockSize", blockSize))
+
+		limits := make(map[uint32]xtime.UnixNano, len(shards))
+		for _, shard := range shards {
+			limits[shard] = xtime.UnixNano(math.MaxInt64)
+		}
+		if _, err := r.convert(srcBlockSize, md, limits); err != nil {
+			return err
+		}
+		// NB: the index block size on disk is not known so always rebuild the
+		// index from the converted data.
+		if err := r.swap(id, blockSize, true); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(fs.NamespaceResizePreviousFilePathPrefix(r.filePathPrefix, id)); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(stagingPrefix)
+}
+
+func (r *namespaceResizer) shardsOnDisk(id ident.ID) ([]uint32, error) {
+	entries, err := ioutil.ReadDir(fs.NamespaceDataDirPath(r.filePathPrefix, id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]uint32, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		shard, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		shards = append(shards, uint32(shard))
+	}
+	return shards, nil
+}
+
+// blockSizeOnDisk returns the block size of the filesets of a namespace on
+// disk, or false if there are none.
+func (r *namespaceResizer) blockSizeOnDisk(id ident.ID, shards []uint32) (time.Duration, bool) {
+	for _, shard := range shards {
+		results := fs.ReadInfoFiles(r.filePathPrefix, id, shard,
+			r.fsOpts.InfoReaderBufferSize(), r.fsOpts.DecodingOptions(),
+			persist.FileSetFlushType)
+		for _, result := range results {
+			if result.Err.Error() == nil {
+				return time.Duration(result.Info.BlockSize), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// carry collects the data of a shard from the given start, reading the blocks
+// of the previous block size between the start and the first unsealed block
+// from disk and the rest from the shard.
+func (r *namespaceResizer) carry(
+	shard databaseShard,
+	start xtime.UnixNano,
+	firstUnsealed xtime.UnixNano,
+	src namespace.Metadata,
+) (*resizeCarry, error) {
+	var (
+		id           = src.ID()
+		srcBlockSize = src.Options().RetentionOptions().BlockSize()
+		nsCtx        = namespace.NewContextFrom(src)
+		carry        = newResizeCarry(r.opts.MultiReaderIteratorPool(), nsCtx.Schema)
+	)
+	defer carry.iter.Close()
+
+	if start.Before(firstUnsealed) {
+		volumes, err := latestCompleteVolumes(r.filePathPrefix, id, shard.ID())
+		if err != nil {
+			return nil, err
+		}
+		reader, err := fs.NewReader(r.opts.BytesPool(), r.fsOpts)
+		if err != nil {
+			return nil, err
+		}
+		for blockStart := start; blockStart.Before(firstUnsealed); blockStart = blockStart.Add(srcBlockSize) {
+			volume, ok := volumes[blockStart]
+			if !ok {
+				continue
+			}
+			if offloader := r.opts.FileSetOffloader(); offloader != nil {
+				if err := offloader.Restore(id, shard.ID(), blockStart); err != nil {
+					return nil, err
+				}
+			}
+			err := carry.addFileSet(reader, fs.FileSetFileIdentifier{
+				FileSetContentType: persist.FileSetDataContentType,
+				Namespace:          id,
+				Shard:              shard.ID(),
+				BlockStart:         blockStart,
+				VolumeIndex:        volume,
+			}, srcBlockSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := shard.ForEachUnflushed(firstUnsealed, nsCtx,
+		func(metadata doc.Metadata, blocks [][]xio.BlockReader) error {
+			return carry.add(cloneResizeMetadata(metadata), blocks)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return carry, nil
+}
+
+func (r *namespaceResizer) writeResizeManifest(filePath string, manifest resizeManifest) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), r.fsOpts.NewDirectoryMode()); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	tmpPath := filePath + resizeManifestTmpSuffix
+	if err := xos.WriteFileSync(tmpPath, data, r.fsOpts.NewFileMode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+func readResizeManifest(filePath string) (resizeManifest, error) {
+	manifest := make(resizeManifest)
+	data, err := ioutil.ReadFile(filePath) //nolint:gosec
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func resizeSourcesEqual(a, b []resizeManifestSource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// latestCompleteVolumes returns the latest complete volume of each block of a
+// shard.
+func latestCompleteVolumes(
+	filePathPrefix string,
+	id ident.ID,
+	shard uint32,
+) (map[xtime.UnixNano]int, error) {
+	files, err := fs.DataFiles(filePathPrefix, id, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[xtime.UnixNano]int, len(files))
+	for i := range files {
+		file := &files[i]
+		if !file.HasCompleteCheckpointFile() {
+			continue
+		}
+		volume, ok := volumes[file.ID.BlockStart]
+		if !ok || file.ID.VolumeIndex > volume {
+			volumes[file.ID.BlockStart] = file.ID.VolumeIndex
+		}
+	}
+	return volumes, nil
+}
+
+// moveDir renames a directory, creating the parent directories of the
+// destination, it is a no-op if the directory does not exist.
+func moveDir(from, to string, newDirMode os.FileMode) error {
+	if _, err := os.Stat(from); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(to), newDirMode); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+type resizeCarriedDatapoint struct {
+	datapoint  ts.Datapoint
+	unit       xtime.Unit
+	annotation ts.Annotation
+}
+
+type resizeCarriedSeries struct {
+	metadata   doc.Metadata
+	datapoints []resizeCarriedDatapoint
+}
+
+// resizeCarry holds the data of a shard that was not converted ahead of a
+// resize so that it can be written to the namespace once it is resized.
+type resizeCarry struct {
+	iter   encoding.MultiReaderIterator
+	schema namespace.SchemaDescr
+	series map[string]*resizeCarriedSeries
+	ids    []string
+}
+
+func newResizeCarry(
+	iterPool encoding.MultiReaderIteratorPool,
+	schema namespace.SchemaDescr,
+) *resizeCarry {
+	return &resizeCarry{
+		iter:   iterPool.Get(),
+		schema: schema,
+		series: make(map[string]*resizeCarriedSeries),
+	}
+}
+
+// add decodes the blocks of a series, the metadata must not be shared.
+func (c *resizeCarry) add(metadata doc.Metadata, blocks [][]xio.BlockReader) error {
+	series, ok := c.series[string(metadata.ID)]
+	if !ok {
+		series = &resizeCarriedSeries{metadata: metadata}
+		c.series[string(metadata.ID)] = series
+		c.ids = append(c.ids, string(metadata.ID))
+	}
+
+	c.iter.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(blocks), c.schema)
+	for c.iter.Next() {
+		dp, unit, annotation := c.iter.Current()
+		series.datapoints = append(series.datapoints, resizeCarriedDatapoint{
+			datapoint:  dp,
+			unit:       unit,
+			annotation: append(ts.Annotation(nil), annotation...),
+		})
+	}
+	return c.iter.Err()
+}
+
+func (c *resizeCarry) addFileSet(
+	reader fs.DataFileSetReader,
+	fileID fs.FileSetFileIdentifier,
+	blockSize time.Duration,
+) error {
+	err := reader.Open(fs.DataReaderOpenOptions{
+		Identifier:  fileID,
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close() // nolint
+
+	for id, tagsIter, data, checksum, err := reader.Read(); err != io.EOF; id, tagsIter, data, checksum, err = reader.Read() {
+		if err != nil {
+			return err
+		}
+
+		metadata, err := convert.FromSeriesIDAndTagIter(id, tagsIter)
+		id.Finalize()
+		tagsIter.Close()
+		if err != nil {
+			return err
+		}
+
+		err = c.add(metadata, [][]xio.BlockReader{{{
+			SegmentReader: xio.NewSegmentReader(ts.NewSegment(data, nil, checksum, ts.FinalizeNone)),
+			Start:         fileID.BlockStart,
+			BlockSize:     blockSize,
+		}}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cloneResizeMetadata(metadata doc.Metadata) doc.Metadata {
+	fields := make([]doc.Field, 0, len(metadata.Fields))
+	for _, field := range metadata.Fields {
+		fields = append(fields, doc.Field{
+			Name:  append([]byte(nil), field.Name...),
+			Value: append([]byte(nil), field.Value...),
+		})
+	}
+	return doc.Metadata{
+		ID:     append([]byte(nil), metadata.ID...),
+		Fields: fields,
+	}
+}
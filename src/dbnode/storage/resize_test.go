@@ -0,0 +1,356 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/digest"
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/runtime"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/x/checked"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+type testResizeStatusReporter struct {
+	reports []resizeReport
+}
+
+func (r *testResizeStatusReporter) ReportConverted(
+	_ ident.ID,
+	state namespace.ResizeState,
+	converted bool,
+) error {
+	r.reports = append(r.reports, resizeReport{state: state, converted: converted})
+	return nil
+}
+
+func newTestResizeOptions(t *testing.T) (Options, string) {
+	dir, err := ioutil.TempDir("", "resize")
+	require.NoError(t, err)
+
+	opts := DefaultTestOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir).
+		SetRuntimeOptionsManager(runtime.NewOptionsManager())
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+	return opts, dir
+}
+
+func newTestResizeNamespaceOptions(blockSize time.Duration) namespace.Options {
+	return namespace.NewOptions().
+		SetRetentionOptions(namespace.NewOptions().RetentionOptions().
+			SetRetentionPeriod(48 * time.Hour).
+			SetBlockSize(blockSize)).
+		SetIndexOptions(namespace.NewIndexOptions().SetBlockSize(4 * time.Hour))
+}
+
+func writeTestResizeFileSet(
+	t *testing.T,
+	opts Options,
+	blockStart xtime.UnixNano,
+	volume int,
+	blockSize time.Duration,
+	dps []ts.Datapoint,
+) {
+	fsOpts := opts.CommitLogOptions().FilesystemOptions()
+	w, err := fs.NewWriter(fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, w.Open(fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   ident.StringID("metrics"),
+			Shard:       0,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+		BlockSize: blockSize,
+	}))
+
+	encoder := opts.EncoderPool().Get()
+	encoder.Reset(blockStart, 0, nil)
+	for _, dp := range dps {
+		require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+	}
+	ctx := context.NewBackground()
+	stream, ok := encoder.Stream(ctx)
+	require.True(t, ok)
+	data, err := xio.ToBytes(stream)
+	require.Equal(t, io.EOF, err)
+	bytes := checked.NewBytes(append([]byte(nil), data...), nil)
+	ctx.Close()
+	encoder.Close()
+
+	bytes.IncRef()
+	metadata := persist.NewMetadataFromIDAndTags(ident.StringID("foo"),
+		ident.Tags{}, persist.MetadataOptions{})
+	require.NoError(t, w.Write(metadata, bytes, digest.Checksum(bytes.Bytes())))
+	require.NoError(t, w.Close())
+}
+
+func readTestResizeFileSet(
+	t *testing.T,
+	opts Options,
+	filePathPrefix string,
+	blockStart xtime.UnixNano,
+	volume int,
+) []ts.Datapoint {
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().SetFilePathPrefix(filePathPrefix)
+	reader, err := fs.NewReader(opts.BytesPool(), fsOpts)
+	require.NoError(t, err)
+	require.NoError(t, reader.Open(fs.DataReaderOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   ident.StringID("metrics"),
+			Shard:       0,
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		},
+		FileSetType: persist.FileSetFlushType,
+	}))
+	defer reader.Close()
+
+	var result []ts.Datapoint
+	for {
+		id, tagsIter, data, checksum, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.Equal(t, "foo", id.String())
+
+		iter := opts.ReaderIteratorPool().Get()
+		iter.Reset(xio.NewSegmentReader(ts.NewSegment(data, nil, checksum, ts.FinalizeHead)), nil)
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			result = append(result, ts.Datapoint{TimestampNanos: dp.TimestampNanos, Value: dp.Value})
+		}
+		require.NoError(t, iter.Err())
+		iter.Close()
+		id.Finalize()
+		tagsIter.Close()
+	}
+	return result
+}
+
+func TestResizeManagerConvertsSealedBlocksAndReports(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestResizeOptions(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		dayStart  = xtime.FromSeconds(0).Add(24 * time.Hour)
+		now       = dayStart.Add(12 * time.Hour)
+		unflushed = dayStart.Add(6 * time.Hour)
+		state     = namespace.ResizeState{BlockSize: 4 * time.Hour, IndexBlockSize: 4 * time.Hour}
+		reporter  = &testResizeStatusReporter{}
+		scope     = tally.NewTestScope("", nil)
+		dps       = func(blockStart xtime.UnixNano, value float64) []ts.Datapoint {
+			return []ts.Datapoint{{TimestampNanos: blockStart.Add(time.Minute), Value: value}}
+		}
+	)
+	for i := 0; i < 4; i++ {
+		blockStart := dayStart.Add(time.Duration(i) * 2 * time.Hour)
+		writeTestResizeFileSet(t, opts, blockStart, 0, 2*time.Hour, dps(blockStart, float64(i)))
+	}
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	shard.EXPECT().FlushState(gomock.Any()).DoAndReturn(
+		func(blockStart xtime.UnixNano) (fileOpState, error) {
+			if !blockStart.Before(unflushed) {
+				return fileOpState{WarmStatus: fileOpNotStarted}, nil
+			}
+			return fileOpState{WarmStatus: fileOpSuccess}, nil
+		}).AnyTimes()
+
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(ident.StringID("metrics")).AnyTimes()
+	ns.EXPECT().Options().Return(newTestResizeNamespaceOptions(2 * time.Hour)).AnyTimes()
+	ns.EXPECT().OwnedShards().Return([]databaseShard{shard}).AnyTimes()
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	db.EXPECT().OwnedNamespaces().Return([]databaseNamespace{ns}, nil).AnyTimes()
+	db.EXPECT().NamespaceResizeState(ident.NewIDMatcher("metrics")).Return(state).AnyTimes()
+
+	mgr := newResizeManager(db, opts.
+		SetNamespaceResizeStatusReporter(reporter).
+		SetInstrumentOptions(opts.InstrumentOptions().SetMetricsScope(scope))).(*resizeManager)
+
+	// Block 06:00 has not been warm flushed yet, so only the first block of
+	// the new block size is converted.
+	stagingPrefix := fs.NamespaceResizeFilePathPrefix(dir, ident.StringID("metrics"), 4*time.Hour)
+	require.True(t, mgr.Run(now))
+	require.Equal(t, append(dps(dayStart, 0), dps(dayStart.Add(2*time.Hour), 1)...),
+		readTestResizeFileSet(t, opts, stagingPrefix, dayStart, 0))
+	exists, err := fs.DataFileSetExists(stagingPrefix, ident.StringID("metrics"), 0,
+		dayStart.Add(4*time.Hour), 0)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Equal(t, []resizeReport{{state: state, converted: true}}, reporter.reports)
+	require.Equal(t, int64(1), scope.Snapshot().Counters()["resize.converted-blocks+"].Value())
+
+	// Blocks whose sources did not change are not converted again.
+	require.True(t, mgr.Run(now))
+	require.Equal(t, int64(1), scope.Snapshot().Counters()["resize.converted-blocks+"].Value())
+	require.Len(t, reporter.reports, 1)
+
+	// A new volume of a source block replaces the converted block.
+	writeTestResizeFileSet(t, opts, dayStart.Add(2*time.Hour), 1, 2*time.Hour,
+		dps(dayStart.Add(2*time.Hour), 5))
+	require.True(t, mgr.Run(now))
+	require.Equal(t, append(dps(dayStart, 0), dps(dayStart.Add(2*time.Hour), 5)...),
+		readTestResizeFileSet(t, opts, stagingPrefix, dayStart, 1))
+	exists, err = fs.DataFileSetExists(stagingPrefix, ident.StringID("metrics"), 0, dayStart, 0)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// Once the remaining blocks are sealed they are converted too.
+	unflushed = dayStart.Add(8 * time.Hour)
+	require.True(t, mgr.Run(now))
+	require.Equal(t, append(dps(dayStart.Add(4*time.Hour), 2), dps(dayStart.Add(6*time.Hour), 3)...),
+		readTestResizeFileSet(t, opts, stagingPrefix, dayStart.Add(4*time.Hour), 0))
+}
+
+func TestNamespaceResizerCompleteResize(t *testing.T) {
+	opts, dir := newTestResizeOptions(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		id       = ident.StringID("metrics")
+		dayStart = xtime.FromSeconds(0).Add(24 * time.Hour)
+		resizer  = newNamespaceResizer(opts)
+	)
+	for i := 0; i < 4; i++ {
+		blockStart := dayStart.Add(time.Duration(i) * time.Hour)
+		writeTestResizeFileSet(t, opts, blockStart, 0, time.Hour, []ts.Datapoint{
+			{TimestampNanos: blockStart.Add(time.Minute), Value: float64(i)},
+		})
+	}
+
+	md, err := namespace.NewMetadata(id, newTestResizeNamespaceOptions(2*time.Hour))
+	require.NoError(t, err)
+
+	// Nothing to do without a staged resize.
+	require.NoError(t, resizer.completeResize(md))
+	exists, err := fs.DataFileSetExists(dir, id, 0, dayStart.Add(time.Hour), 0)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	stagingPrefix := fs.NamespaceResizeFilePathPrefix(dir, id, 2*time.Hour)
+	require.NoError(t, os.MkdirAll(stagingPrefix, opts.CommitLogOptions().
+		FilesystemOptions().NewDirectoryMode()))
+	require.NoError(t, resizer.completeResize(md))
+
+	for i := 0; i < 2; i++ {
+		blockStart := dayStart.Add(time.Duration(i) * 2 * time.Hour)
+		require.Equal(t, []ts.Datapoint{
+			{TimestampNanos: blockStart.Add(time.Minute), Value: float64(2 * i)},
+			{TimestampNanos: blockStart.Add(time.Hour + time.Minute), Value: float64(2*i + 1)},
+		}, readTestResizeFileSet(t, opts, dir, blockStart, 0))
+	}
+	exists, err = fs.DataFileSetExists(dir, id, 0, dayStart.Add(time.Hour), 0)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	for _, path := range []string{
+		stagingPrefix,
+		fs.NamespaceResizePreviousFilePathPrefix(dir, id),
+	} {
+		_, err := os.Stat(path)
+		require.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestIsOnlineResize(t *testing.T) {
+	var (
+		curr  = newTestResizeNamespaceOptions(2 * time.Hour)
+		state = namespace.ResizeState{BlockSize: 4 * time.Hour, IndexBlockSize: 8 * time.Hour}
+	)
+	resized := namespace.ResizedOptions(curr.SetResizeState(state))
+
+	tests := []struct {
+		name     string
+		curr     namespace.Options
+		next     namespace.Options
+		expected bool
+	}{
+		{
+			name:     "completes resize",
+			curr:     curr.SetResizeState(state),
+			next:     resized,
+			expected: true,
+		},
+		{
+			name: "completes resize and changes retention period",
+			curr: curr.SetResizeState(state),
+			next: resized.SetRetentionOptions(resized.RetentionOptions().
+				SetRetentionPeriod(72 * time.Hour)),
+			expected: true,
+		},
+		{
+			name: "changes retention period without resize",
+			curr: curr,
+			next: curr.SetRetentionOptions(curr.RetentionOptions().
+				SetRetentionPeriod(72 * time.Hour)),
+			expected: false,
+		},
+		{
+			name:     "changes block size without resize",
+			curr:     curr,
+			next:     resized,
+			expected: false,
+		},
+		{
+			name:     "starts resize",
+			curr:     curr,
+			next:     curr.SetResizeState(state),
+			expected: false,
+		},
+		{
+			name:     "changes other options",
+			curr:     curr.SetResizeState(state),
+			next:     resized.SetWritesToCommitLog(!resized.WritesToCommitLog()),
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, isOnlineResize(tt.curr, tt.next))
+		})
+	}
+}
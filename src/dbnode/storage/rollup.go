@@ -0,0 +1,324 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/dbnode/retention"
+	"github.com/m3db/m3/src/x/context"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	xos "github.com/m3db/m3/src/x/os"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	rollupProgressTmpSuffix = ".tmp"
+	rollupProgressSize      = 8
+)
+
+var errRollupProgressCorrupt = errors.New("rollup progress file is corrupt")
+
+type rollupManagerMetrics struct {
+	status         tally.Gauge
+	rolledUpBlocks tally.Counter
+	processedTiles tally.Counter
+	errors         tally.Counter
+}
+
+func newRollupManagerMetrics(scope tally.Scope) rollupManagerMetrics {
+	return rollupManagerMetrics{
+		status:         scope.Gauge("rollup"),
+		rolledUpBlocks: scope.Counter("rolled-up-blocks"),
+		processedTiles: scope.Counter("processed-tiles"),
+		errors:         scope.Counter("errors"),
+	}
+}
+
+// rollupManager rolls up sealed blocks of namespaces that have a rollup
+// policy into their target namespace. Progress is persisted per source and
+// target namespace so that a restarted node resumes where it left off.
+type rollupManager struct {
+	sync.RWMutex
+
+	log            *zap.Logger
+	database       database
+	opts           Options
+	scope          tally.Scope
+	filePathPrefix string
+	newFileMode    os.FileMode
+	newDirMode     os.FileMode
+	metrics        rollupManagerMetrics
+	status         fileOpStatus
+	enabled        bool
+}
+
+func newRollupManager(database database, opts Options) databaseRollupManager {
+	var (
+		instrumentOpts = opts.InstrumentOptions()
+		scope          = instrumentOpts.MetricsScope().SubScope("rollup")
+		fsOpts         = opts.CommitLogOptions().FilesystemOptions()
+	)
+	return &rollupManager{
+		log:            instrumentOpts.Logger(),
+		database:       database,
+		opts:           opts,
+		scope:          scope,
+		filePathPrefix: fsOpts.FilePathPrefix(),
+		newFileMode:    fsOpts.NewFileMode(),
+		newDirMode:     fsOpts.NewDirectoryMode(),
+		metrics:        newRollupManagerMetrics(scope),
+		status:         fileOpNotStarted,
+		enabled:        true,
+	}
+}
+
+func (m *rollupManager) Disable() fileOpStatus {
+	m.Lock()
+	status := m.status
+	m.enabled = false
+	m.Unlock()
+	return status
+}
+
+func (m *rollupManager) Enable() fileOpStatus {
+	m.Lock()
+	status := m.status
+	m.enabled = true
+	m.Unlock()
+	return status
+}
+
+func (m *rollupManager) Status() fileOpStatus {
+	m.RLock()
+	status := m.status
+	m.RUnlock()
+	return status
+}
+
+func (m *rollupManager) Run(t xtime.UnixNano) bool {
+	m.Lock()
+	if !m.shouldRunWithLock() {
+		m.Unlock()
+		return false
+	}
+	m.status = fileOpInProgress
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.status = fileOpNotStarted
+		m.Unlock()
+	}()
+
+	if err := m.rollup(t); err != nil {
+		m.metrics.errors.Inc(1)
+		m.log.Error("error rolling up namespaces",
+			zap.Time("time", t.ToTime()), zap.Error(err))
+	}
+	return true
+}
+
+func (m *rollupManager) Report() {
+	if m.Status() == fileOpInProgress {
+		m.metrics.status.Update(1)
+	} else {
+		m.metrics.status.Update(0)
+	}
+}
+
+func (m *rollupManager) shouldRunWithLock() bool {
+	return m.enabled && m.status != fileOpInProgress && m.database.IsBootstrapped()
+}
+
+func (m *rollupManager) rollup(t xtime.UnixNano) error {
+	namespaces, err := m.database.OwnedNamespaces()
+	if err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, n := range namespaces {
+		policy := rollupPolicy(n.Options())
+		if !policy.Enabled() {
+			continue
+		}
+		if err := m.rollupNamespace(t, n, policy); err != nil {
+			multiErr = multiErr.Add(fmt.Errorf(
+				"rollup of namespace %s failed: %v", n.ID().String(), err))
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func (m *rollupManager) rollupNamespace(
+	t xtime.UnixNano,
+	source databaseNamespace,
+	policy namespace.RollupPolicy,
+) error {
+	targetID := ident.StringID(policy.TargetNamespace)
+	target, ok := m.database.Namespace(targetID)
+	if !ok {
+		return fmt.Errorf("rollup target namespace %s does not exist", policy.TargetNamespace)
+	}
+
+	var (
+		sourceRetention = source.Options().RetentionOptions()
+		sourceBlockSize = sourceRetention.BlockSize()
+		targetBlockSize = target.Options().RetentionOptions().BlockSize()
+		progressPath    = fs.NamespaceRollupProgressFilePath(m.filePathPrefix, source.ID(), targetID)
+		lag             = m.scope.Tagged(map[string]string{
+			"source-namespace": source.ID().String(),
+			"target-namespace": policy.TargetNamespace,
+		}).Gauge("lag-seconds")
+	)
+	if targetBlockSize%sourceBlockSize != 0 {
+		return fmt.Errorf("target block size %s must be a multiple of source block size %s",
+			targetBlockSize.String(), sourceBlockSize.String())
+	}
+
+	rolledUpThrough, err := readRollupProgress(progressPath)
+	if err != nil {
+		return err
+	}
+	if earliest := retention.FlushTimeStart(sourceRetention, t); rolledUpThrough.Before(earliest) {
+		rolledUpThrough = earliest
+	}
+
+	sealedEnd := sealedBlocksEnd(t, source, policy, rolledUpThrough)
+	for rolledUpThrough.Before(sealedEnd) {
+		// Each aggregation writes a new volume replacing the whole target block,
+		// so always aggregate from the start of the target block.
+		tileStart := rolledUpThrough.Truncate(targetBlockSize)
+		tileEnd := tileStart.Add(targetBlockSize)
+		if tileEnd.After(sealedEnd) {
+			tileEnd = sealedEnd
+		}
+
+		opts, err := NewAggregateTilesOptions(tileStart, tileEnd, policy.Step,
+			targetID, m.opts.InstrumentOptions())
+		if err != nil {
+			return err
+		}
+
+		ctx := context.NewBackground()
+		processedTiles, err := m.database.AggregateTiles(ctx, source.ID(), targetID, opts)
+		ctx.Close()
+		if err != nil {
+			return err
+		}
+
+		if err := m.writeRollupProgress(progressPath, tileEnd); err != nil {
+			return err
+		}
+
+		m.metrics.rolledUpBlocks.Inc(int64(tileEnd.Sub(rolledUpThrough) / sourceBlockSize))
+		m.metrics.processedTiles.Inc(processedTiles)
+		rolledUpThrough = tileEnd
+	}
+
+	lag.Update(t.Sub(rolledUpThrough).Seconds())
+	return nil
+}
+
+// sealedBlocksEnd returns the end of the last contiguous block from the given
+// start that is old enough to be rolled up and has been warm flushed by every
+// bootstrapped shard.
+func sealedBlocksEnd(
+	t xtime.UnixNano,
+	source databaseNamespace,
+	policy namespace.RollupPolicy,
+	start xtime.UnixNano,
+) xtime.UnixNano {
+	var (
+		blockSize = source.Options().RetentionOptions().BlockSize()
+		shards    = source.OwnedShards()
+		end       = start
+	)
+	for blockStart := start; ; blockStart = blockStart.Add(blockSize) {
+		blockEnd := blockStart.Add(blockSize)
+		if blockEnd.Add(policy.RollupAfter).After(t) {
+			return end
+		}
+		for _, shard := range shards {
+			if !shard.IsBootstrapped() {
+				continue
+			}
+			state, err := shard.FlushState(blockStart)
+			if err != nil || !statusIsRetrievable(state.WarmStatus) {
+				return end
+			}
+		}
+		end = blockEnd
+	}
+}
+
+func rollupPolicy(opts namespace.Options) namespace.RollupPolicy {
+	aggOpts := opts.AggregationOptions()
+	if aggOpts == nil {
+		return namespace.RollupPolicy{}
+	}
+	return aggOpts.RollupPolicy()
+}
+
+// readRollupProgress returns the end of the last block that was rolled up,
+// or zero if nothing has been rolled up yet.
+func readRollupProgress(filePath string) (xtime.UnixNano, error) {
+	data, err := ioutil.ReadFile(filePath) //nolint:gosec
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != rollupProgressSize {
+		return 0, errRollupProgressCorrupt
+	}
+	return xtime.UnixNano(binary.BigEndian.Uint64(data)), nil
+}
+
+// writeRollupProgress atomically replaces the progress file by writing to a
+// temporary file and renaming it once synced.
+func (m *rollupManager) writeRollupProgress(filePath string, value xtime.UnixNano) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), m.newDirMode); err != nil {
+		return err
+	}
+
+	var data [rollupProgressSize]byte
+	binary.BigEndian.PutUint64(data[:], uint64(value))
+	tmpPath := filePath + rollupProgressTmpSuffix
+	if err := xos.WriteFileSync(tmpPath, data[:], m.newFileMode); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
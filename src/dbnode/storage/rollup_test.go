@@ -0,0 +1,247 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/namespace"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
+	"github.com/m3db/m3/src/x/context"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+type testRollupWindow struct {
+	start, end xtime.UnixNano
+}
+
+func newTestRollupOptions(t *testing.T) (Options, string) {
+	dir, err := ioutil.TempDir("", "rollup")
+	require.NoError(t, err)
+
+	opts := DefaultTestOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().SetFilePathPrefix(dir)
+	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().SetFilesystemOptions(fsOpts))
+	return opts, dir
+}
+
+func newTestRollupNamespaces(
+	ctrl *gomock.Controller,
+	policy namespace.RollupPolicy,
+	unflushed *xtime.UnixNano,
+) (*MockdatabaseNamespace, *MockNamespace) {
+	sourceOpts := namespace.NewOptions().
+		SetRetentionOptions(namespace.NewOptions().RetentionOptions().
+			SetRetentionPeriod(12 * time.Hour).
+			SetBlockSize(2 * time.Hour)).
+		SetAggregationOptions(namespace.NewAggregationOptions().SetRollupPolicy(policy))
+	source := NewMockdatabaseNamespace(ctrl)
+	source.EXPECT().ID().Return(ident.StringID("raw")).AnyTimes()
+	source.EXPECT().Options().Return(sourceOpts).AnyTimes()
+
+	shard := NewMockdatabaseShard(ctrl)
+	shard.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	shard.EXPECT().FlushState(gomock.Any()).DoAndReturn(
+		func(blockStart xtime.UnixNano) (fileOpState, error) {
+			if blockStart == *unflushed {
+				return fileOpState{WarmStatus: fileOpNotStarted}, nil
+			}
+			return fileOpState{WarmStatus: fileOpSuccess}, nil
+		}).AnyTimes()
+	source.EXPECT().OwnedShards().Return([]databaseShard{shard}).AnyTimes()
+
+	targetOpts := namespace.NewOptions().
+		SetRetentionOptions(namespace.NewOptions().RetentionOptions().
+			SetRetentionPeriod(48 * time.Hour).
+			SetBlockSize(4 * time.Hour))
+	target := NewMockNamespace(ctrl)
+	target.EXPECT().Options().Return(targetOpts).AnyTimes()
+
+	return source, target
+}
+
+func TestRollupManagerRollsUpSealedBlocksAndResumes(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestRollupOptions(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		policy = namespace.RollupPolicy{
+			TargetNamespace: "agg",
+			RollupAfter:     2 * time.Hour,
+			Step:            5 * time.Minute,
+		}
+		dayStart  = xtime.FromSeconds(0).Add(24 * time.Hour)
+		now       = dayStart.Add(12 * time.Hour)
+		unflushed = dayStart.Add(8 * time.Hour)
+		windows   []testRollupWindow
+	)
+	source, target := newTestRollupNamespaces(ctrl, policy, &unflushed)
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+	db.EXPECT().IsBootstrapped().Return(true).AnyTimes()
+	db.EXPECT().OwnedNamespaces().Return([]databaseNamespace{source}, nil).AnyTimes()
+	db.EXPECT().Namespace(ident.NewIDMatcher("agg")).Return(target, true).AnyTimes()
+	db.EXPECT().
+		AggregateTiles(gomock.Any(), ident.NewIDMatcher("raw"), ident.NewIDMatcher("agg"), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context, _, _ ident.ID, opts AggregateTilesOptions,
+		) (int64, error) {
+			require.Equal(t, policy.Step, opts.Step)
+			windows = append(windows, testRollupWindow{start: opts.Start, end: opts.End})
+			return 10, nil
+		}).AnyTimes()
+
+	scope := tally.NewTestScope("", nil)
+	mgr := newRollupManager(db, opts.SetInstrumentOptions(
+		opts.InstrumentOptions().SetMetricsScope(scope))).(*rollupManager)
+
+	// Block 08:00 has not been warm flushed yet, so only the earlier blocks
+	// are rolled up, one target block at a time.
+	require.True(t, mgr.Run(now))
+	require.Equal(t, []testRollupWindow{
+		{start: dayStart, end: dayStart.Add(4 * time.Hour)},
+		{start: dayStart.Add(4 * time.Hour), end: dayStart.Add(8 * time.Hour)},
+	}, windows)
+
+	progressPath := fs.NamespaceRollupProgressFilePath(dir,
+		ident.StringID("raw"), ident.StringID("agg"))
+	rolledUpThrough, err := readRollupProgress(progressPath)
+	require.NoError(t, err)
+	require.Equal(t, dayStart.Add(8*time.Hour), rolledUpThrough)
+	require.Equal(t, int64(4), scope.Snapshot().Counters()["rollup.rolled-up-blocks+"].Value())
+
+	// A new manager resumes from the persisted progress once the block has
+	// been flushed, re-aggregating the partially complete target block.
+	windows = nil
+	unflushed = 0
+	mgr = newRollupManager(db, opts).(*rollupManager)
+	require.True(t, mgr.Run(now))
+	require.Equal(t, []testRollupWindow{
+		{start: dayStart.Add(8 * time.Hour), end: dayStart.Add(10 * time.Hour)},
+	}, windows)
+
+	rolledUpThrough, err = readRollupProgress(progressPath)
+	require.NoError(t, err)
+	require.Equal(t, dayStart.Add(10*time.Hour), rolledUpThrough)
+
+	// Nothing else is old enough to roll up.
+	windows = nil
+	require.True(t, mgr.Run(now))
+	require.Empty(t, windows)
+}
+
+func TestRollupManagerDisabled(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestRollupOptions(t)
+	defer os.RemoveAll(dir)
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+
+	mgr := newRollupManager(db, opts)
+	require.Equal(t, fileOpNotStarted, mgr.Disable())
+	require.False(t, mgr.Run(xtime.Now()))
+	require.Equal(t, fileOpNotStarted, mgr.Enable())
+}
+
+func TestRollupProgressCorrupt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rollup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	progressPath := fs.NamespaceRollupProgressFilePath(dir,
+		ident.StringID("raw"), ident.StringID("agg"))
+	value, err := readRollupProgress(progressPath)
+	require.NoError(t, err)
+	require.Equal(t, xtime.UnixNano(0), value)
+
+	mgr := &rollupManager{newFileMode: 0666, newDirMode: 0755}
+	require.NoError(t, mgr.writeRollupProgress(progressPath, xtime.UnixNano(42)))
+	value, err = readRollupProgress(progressPath)
+	require.NoError(t, err)
+	require.Equal(t, xtime.UnixNano(42), value)
+
+	require.NoError(t, ioutil.WriteFile(progressPath, []byte{1, 2, 3}, 0666))
+	_, err = readRollupProgress(progressPath)
+	require.Equal(t, errRollupProgressCorrupt, err)
+}
+
+func TestCleanupManagerRolledUpEarliestToRetain(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts, dir := newTestRollupOptions(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		policy = namespace.RollupPolicy{
+			TargetNamespace: "agg",
+			RollupAfter:     2 * time.Hour,
+			Step:            5 * time.Minute,
+			RawRetention:    4 * time.Hour,
+		}
+		dayStart  = xtime.FromSeconds(0).Add(24 * time.Hour)
+		now       = dayStart.Add(12 * time.Hour)
+		unflushed xtime.UnixNano
+	)
+	source, target := newTestRollupNamespaces(ctrl, policy, &unflushed)
+
+	db := NewMockdatabase(ctrl)
+	db.EXPECT().Options().Return(opts).AnyTimes()
+	db.EXPECT().Namespace(ident.NewIDMatcher("agg")).Return(target, true).AnyTimes()
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), tally.NoopScope).(*cleanupManager)
+
+	// Nothing has been rolled up yet so raw data is kept.
+	earliest, ok := mgr.rolledUpEarliestToRetain(now, source)
+	require.True(t, ok)
+	require.Equal(t, xtime.UnixNano(0), earliest)
+
+	// Raw blocks are retained from the start of the partially rolled up
+	// target block.
+	progressPath := fs.NamespaceRollupProgressFilePath(dir,
+		ident.StringID("raw"), ident.StringID("agg"))
+	rollupMgr := newRollupManager(db, opts).(*rollupManager)
+	require.NoError(t, rollupMgr.writeRollupProgress(progressPath, dayStart.Add(6*time.Hour)))
+	earliest, ok = mgr.rolledUpEarliestToRetain(now, source)
+	require.True(t, ok)
+	require.Equal(t, dayStart.Add(4*time.Hour), earliest)
+
+	// Once fully rolled up the raw retention applies.
+	require.NoError(t, rollupMgr.writeRollupProgress(progressPath, dayStart.Add(10*time.Hour)))
+	earliest, ok = mgr.rolledUpEarliestToRetain(now, source)
+	require.True(t, ok)
+	require.Equal(t, dayStart.Add(8*time.Hour), earliest)
+}
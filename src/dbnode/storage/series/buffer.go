@@ -137,6 +137,17 @@ type databaseBuffer interface {
 
 	ColdFlushBlockStarts(blockStates map[xtime.UnixNano]BlockState) OptimizedTimes
 
+	LateFlushBlockStarts(blockStates map[xtime.UnixNano]BlockState) OptimizedTimes
+
+	LateFlush(
+		ctx context.Context,
+		blockStart xtime.UnixNano,
+		version int,
+		metadata persist.Metadata,
+		persistFn persist.DataFn,
+		nsCtx namespace.Context,
+	) (FlushOutcome, error)
+
 	Stats() bufferStats
 
 	Tick(versions ShardBlockStateSnapshot, nsCtx namespace.Context) bufferTickResult
@@ -317,9 +328,12 @@ func (b *dbBuffer) Write(
 		// Bootstrap writes are allowed to be outside of time boundaries
 		// and determined as cold or warm writes depending on whether
 		// the block is retrievable or not.
-		if !exists {
+		switch {
+		case !exists:
 			writeType = WarmWrite
-		} else {
+		case b.opts.LateWritesEnabled() && timestamp.Before(pastLimit):
+			writeType = LateWrite
+		default:
 			writeType = ColdWrite
 		}
 
@@ -335,6 +349,11 @@ func (b *dbBuffer) Write(
 					pastLimit.Format(errTimestampFormat),
 					timestamp, pastLimit))
 		}
+		if b.opts.LateWritesEnabled() {
+			// Late writes are persisted to sidecar volumes of their block
+			// rather than merged into the block by a cold flush.
+			writeType = LateWrite
+		}
 
 	case !futureLimit.After(timestamp):
 		writeType = ColdWrite
@@ -354,7 +373,7 @@ func (b *dbBuffer) Write(
 
 	}
 
-	if writeType == ColdWrite {
+	if writeType != WarmWrite {
 		retentionLimit := now.Add(-ropts.RetentionPeriod())
 		if wOpts.BootstrapWrite {
 			// NB(r): Allow bootstrapping to write to blocks that are
@@ -395,6 +414,9 @@ func (b *dbBuffer) Write(
 		}
 
 		b.opts.Stats().IncColdWrites()
+		if writeType == LateWrite {
+			b.opts.Stats().IncLateWrites()
+		}
 	}
 
 	buckets := b.bucketVersionsAtCreate(blockStart)
@@ -452,6 +474,25 @@ func (b *dbBuffer) ColdFlushBlockStarts(blockStates map[xtime.UnixNano]BlockStat
 	return times
 }
 
+func (b *dbBuffer) LateFlushBlockStarts(blockStates map[xtime.UnixNano]BlockState) OptimizedTimes {
+	var times OptimizedTimes
+
+	for t, bucketVersions := range b.bucketsMap {
+		for _, bucket := range bucketVersions.buckets {
+			// Same as for cold flushes, buckets with new late writes and
+			// buckets whose late flush did not complete need to be flushed.
+			if bucket.writeType == LateWrite &&
+				(bucket.version == writableBucketVersion ||
+					blockStates[bucket.start].LateVersion < bucket.version) {
+				times.Add(t)
+				break
+			}
+		}
+	}
+
+	return times
+}
+
 func (b *dbBuffer) Stats() bufferStats {
 	return bufferStats{
 		wiredBlocks: len(b.bucketsMap),
@@ -475,7 +516,8 @@ func (b *dbBuffer) Tick(blockStates ShardBlockStateSnapshot, nsCtx namespace.Con
 		// has been properly bootstrapped already.
 		if bootstrapped {
 			blockState := blockStateSnapshot.Snapshot[tNano]
-			if coldVersion := blockState.ColdVersion; blockState.WarmRetrievable || coldVersion > 0 {
+			coldVersion, lateVersion := blockState.ColdVersion, blockState.LateVersion
+			if blockState.WarmRetrievable || coldVersion > 0 || lateVersion > 0 {
 				if blockState.WarmRetrievable {
 					// Buckets for WarmWrites that are retrievable will only be version 1, since
 					// they only get successfully persisted once.
@@ -484,6 +526,9 @@ func (b *dbBuffer) Tick(blockStates ShardBlockStateSnapshot, nsCtx namespace.Con
 				if coldVersion > 0 {
 					buckets.removeBucketsUpToVersion(ColdWrite, coldVersion)
 				}
+				if lateVersion > 0 {
+					buckets.removeBucketsUpToVersion(LateWrite, lateVersion)
+				}
 
 				if buckets.streamsLen() == 0 {
 					// All underlying buckets have been flushed successfully, so we can
@@ -645,7 +690,70 @@ func (b *dbBuffer) WarmFlush(
 
 	// Flush only deals with WarmWrites. ColdWrites get persisted to disk via
 	// the compaction cycle.
-	streams, err := buckets.mergeToStreams(ctx, streamsOptions{filterWriteType: true, writeType: WarmWrite, nsCtx: nsCtx})
+	outcome, err := b.flushBuckets(ctx, blockStart, buckets, streamsOptions{
+		filterWriteType: true,
+		writeType:       WarmWrite,
+		nsCtx:           nsCtx,
+	}, metadata, persistFn)
+	if outcome != FlushOutcomeFlushedToDisk {
+		return outcome, err
+	}
+
+	if bucket, exists := buckets.writableBucket(WarmWrite); exists {
+		// WarmFlushes only happen once per block, so it makes sense to always
+		// set this to 1.
+		bucket.version = 1
+	}
+
+	return FlushOutcomeFlushedToDisk, nil
+}
+
+func (b *dbBuffer) LateFlush(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	version int,
+	metadata persist.Metadata,
+	persistFn persist.DataFn,
+	nsCtx namespace.Context,
+) (FlushOutcome, error) {
+	buckets, exists := b.bucketVersionsAt(blockStart)
+	if !exists {
+		return FlushOutcomeBlockDoesNotExist, nil
+	}
+
+	// Buckets already persisted to a previous sidecar volume are left to be
+	// evicted by the next tick rather than persisted again.
+	outcome, err := b.flushBuckets(ctx, blockStart, buckets, streamsOptions{
+		filterWriteType: true,
+		writeType:       LateWrite,
+		filterFlushed:   true,
+		flushedVersion:  version - 1,
+		nsCtx:           nsCtx,
+	}, metadata, persistFn)
+	if err != nil {
+		return outcome, err
+	}
+
+	if bucket, exists := buckets.writableBucket(LateWrite); exists {
+		// Same as for cold flushes, this marks the bucket as attempted to be
+		// flushed to the sidecar volume with the given version. The tick
+		// following the shard persisting the volume removes it from memory.
+		bucket.version = version
+	}
+
+	return outcome, nil
+}
+
+func (b *dbBuffer) flushBuckets(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	buckets *BufferBucketVersions,
+	opts streamsOptions,
+	metadata persist.Metadata,
+	persistFn persist.DataFn,
+) (FlushOutcome, error) {
+	nsCtx := opts.nsCtx
+	streams, err := buckets.mergeToStreams(ctx, opts)
 	if err != nil {
 		return FlushOutcomeErr, err
 	}
@@ -693,12 +801,6 @@ func (b *dbBuffer) WarmFlush(
 		return FlushOutcomeErr, err
 	}
 
-	if bucket, exists := buckets.writableBucket(WarmWrite); exists {
-		// WarmFlushes only happen once per block, so it makes sense to always
-		// set this to 1.
-		bucket.version = 1
-	}
-
 	return FlushOutcomeFlushedToDisk, nil
 }
 
@@ -1173,6 +1275,10 @@ func (b *BufferBucketVersions) mergeToStreams(ctx context.Context, opts streamsO
 		if opts.filterWriteType && bucket.writeType != opts.writeType {
 			continue
 		}
+		if opts.filterFlushed && bucket.version != writableBucketVersion &&
+			bucket.version <= opts.flushedVersion {
+			continue
+		}
 		stream, ok, err := bucket.mergeToStream(ctx, opts.nsCtx)
 		if err != nil {
 			return nil, err
@@ -1199,7 +1305,11 @@ func (b *BufferBucketVersions) recordActiveEncoders() {
 type streamsOptions struct {
 	filterWriteType bool
 	writeType       WriteType
-	nsCtx           namespace.Context
+	// filterFlushed excludes the buckets already persisted at a version up
+	// to and including flushedVersion.
+	filterFlushed  bool
+	flushedVersion int
+	nsCtx          namespace.Context
 }
 
 // BufferBucket is a specific version of a bucket of encoders, which is where
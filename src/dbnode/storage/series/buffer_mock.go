@@ -147,6 +147,35 @@ func (mr *MockdatabaseBufferMockRecorder) IsEmptyAtBlockStart(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEmptyAtBlockStart", reflect.TypeOf((*MockdatabaseBuffer)(nil).IsEmptyAtBlockStart), arg0)
 }
 
+// LateFlush mocks base method.
+func (m *MockdatabaseBuffer) LateFlush(ctx context.Context, blockStart time.UnixNano, version int, metadata persist.Metadata, persistFn persist.DataFn, nsCtx namespace.Context) (FlushOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LateFlush", ctx, blockStart, version, metadata, persistFn, nsCtx)
+	ret0, _ := ret[0].(FlushOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LateFlush indicates an expected call of LateFlush.
+func (mr *MockdatabaseBufferMockRecorder) LateFlush(ctx, blockStart, version, metadata, persistFn, nsCtx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LateFlush", reflect.TypeOf((*MockdatabaseBuffer)(nil).LateFlush), ctx, blockStart, version, metadata, persistFn, nsCtx)
+}
+
+// LateFlushBlockStarts mocks base method.
+func (m *MockdatabaseBuffer) LateFlushBlockStarts(blockStates map[time.UnixNano]BlockState) OptimizedTimes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LateFlushBlockStarts", blockStates)
+	ret0, _ := ret[0].(OptimizedTimes)
+	return ret0
+}
+
+// LateFlushBlockStarts indicates an expected call of LateFlushBlockStarts.
+func (mr *MockdatabaseBufferMockRecorder) LateFlushBlockStarts(blockStates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LateFlushBlockStarts", reflect.TypeOf((*MockdatabaseBuffer)(nil).LateFlushBlockStarts), blockStates)
+}
+
 // Load mocks base method.
 func (m *MockdatabaseBuffer) Load(bl block.DatabaseBlock, writeType WriteType) {
 	m.ctrl.T.Helper()
@@ -1557,6 +1557,92 @@ func assertEqualUnixSlices(t *testing.T, expected, actual []xtime.UnixNano) {
 	}
 }
 
+func TestBufferLateWrites(t *testing.T) {
+	opts := newBufferTestOptions().
+		SetColdWritesEnabled(true).
+		SetLateWritesEnabled(true)
+	rops := opts.RetentionOptions()
+	blockSize := rops.BlockSize()
+	curr := xtime.Now().Truncate(blockSize)
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+		return curr.ToTime()
+	}))
+	buffer := newDatabaseBuffer().(*dbBuffer)
+	buffer.Reset(databaseBufferResetOptions{
+		Options: opts,
+	})
+	ctx := context.NewBackground()
+	defer ctx.Close()
+	nsCtx := namespace.Context{}
+
+	lateStart := curr.Add(-2 * blockSize)
+	wasWritten, writeType, err := buffer.Write(ctx, testID, lateStart.Add(secs(1)), 1,
+		xtime.Second, nil, WriteOptions{})
+	require.NoError(t, err)
+	require.True(t, wasWritten)
+	require.Equal(t, LateWrite, writeType)
+
+	wasWritten, writeType, err = buffer.Write(ctx, testID, curr.Add(secs(1)), 2,
+		xtime.Second, nil, WriteOptions{})
+	require.NoError(t, err)
+	require.True(t, wasWritten)
+	require.Equal(t, WarmWrite, writeType)
+
+	// Late writes are not cold flushed.
+	coldStarts := buffer.ColdFlushBlockStarts(nil)
+	require.Equal(t, 0, coldStarts.Len())
+	lateStarts := buffer.LateFlushBlockStarts(nil)
+	require.Equal(t, 1, lateStarts.Len())
+	require.True(t, lateStarts.Contains(lateStart))
+
+	var persisted []DecodedTestValue
+	persistFn := func(_ persist.Metadata, segment ts.Segment, _ uint32) error {
+		reader := xio.BlockReader{
+			SegmentReader: xio.NewSegmentReader(segment),
+			Start:         lateStart,
+			BlockSize:     blockSize,
+		}
+		values, err := decodedReaderValues([][]xio.BlockReader{{reader}}, opts, nsCtx)
+		persisted = values
+		return err
+	}
+	metadata := persist.NewMetadata(doc.Metadata{ID: testID.Bytes()})
+	outcome, err := buffer.LateFlush(ctx, lateStart, 1, metadata, persistFn, nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, FlushOutcomeFlushedToDisk, outcome)
+	requireValuesEqual(t, []DecodedTestValue{
+		{lateStart.Add(secs(1)), 1, xtime.Second, nil},
+	}, persisted, nsCtx)
+
+	// The bucket must be flushed again until the volume is persisted.
+	lateStarts = buffer.LateFlushBlockStarts(map[xtime.UnixNano]BlockState{
+		lateStart: {WarmRetrievable: true},
+	})
+	require.True(t, lateStarts.Contains(lateStart))
+	lateStarts = buffer.LateFlushBlockStarts(map[xtime.UnixNano]BlockState{
+		lateStart: {WarmRetrievable: true, LateVersion: 1},
+	})
+	require.Equal(t, 0, lateStarts.Len())
+
+	// Late writes already persisted to a previous volume are not persisted
+	// to the next one.
+	persisted = nil
+	outcome, err = buffer.LateFlush(ctx, lateStart, 2, metadata, persistFn, nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, FlushOutcomeBlockDoesNotExist, outcome)
+	require.Empty(t, persisted)
+
+	// Once persisted the late bucket is evicted.
+	result := buffer.Tick(NewShardBlockStateSnapshot(true, BootstrappedBlockStateSnapshot{
+		Snapshot: map[xtime.UnixNano]BlockState{
+			lateStart: {WarmRetrievable: true, LateVersion: 1},
+		},
+	}), nsCtx)
+	require.True(t, result.evictedBucketTimes.Contains(lateStart))
+	require.True(t, buffer.IsEmptyAtBlockStart(lateStart))
+	require.False(t, buffer.IsEmptyAtBlockStart(curr))
+}
+
 func TestColdFlushBlockStarts(t *testing.T) {
 	opts := newBufferTestOptions()
 	rops := opts.RetentionOptions()
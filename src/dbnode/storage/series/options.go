@@ -45,6 +45,7 @@ type options struct {
 	identifierPool                ident.Pool
 	stats                         Stats
 	coldWritesEnabled             bool
+	lateWritesEnabled             bool
 	bufferBucketPool              *BufferBucketPool
 	bufferBucketVersionsPool      *BufferBucketVersionsPool
 	runtimeOptsMgr                m3dbruntime.OptionsManager
@@ -201,6 +202,16 @@ func (o *options) ColdWritesEnabled() bool {
 	return o.coldWritesEnabled
 }
 
+func (o *options) SetLateWritesEnabled(value bool) Options {
+	opts := *o
+	opts.lateWritesEnabled = value
+	return &opts
+}
+
+func (o *options) LateWritesEnabled() bool {
+	return o.lateWritesEnabled
+}
+
 func (o *options) SetBufferBucketVersionsPool(value *BufferBucketVersionsPool) Options {
 	opts := *o
 	opts.bufferBucketVersionsPool = value
@@ -208,6 +208,14 @@ func (i *blockReaderIter) Next(ctx context.Context) bool {
 				i.curr = append(i.curr, blockReader)
 			}
 		}
+
+		// finally check for late data persisted next to the disk block.
+		lateReaders, err := i.reader.streamLate(ctx, i.blockAt, i.nsCtx)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		i.curr = append(i.curr, lateReaders...)
 		i.blockAt = i.blockAt.Add(i.blockSize)
 	}
 	return len(i.curr) != 0
@@ -449,6 +457,17 @@ func (r *Reader) resolveBlockResults(
 			blockReaders = append(blockReaders, blockReader)
 		}
 
+		lateReaders, err := r.streamLate(ctx, start, nsCtx)
+		if err != nil {
+			// Short-circuit this entire blockstart if an error was encountered.
+			r := block.NewFetchBlockResult(start, nil,
+				fmt.Errorf("unable to retrieve late data stream for series %s time %v: %w",
+					r.id.String(), start, err))
+			res = append(res, r)
+			continue
+		}
+		blockReaders = append(blockReaders, lateReaders...)
+
 		if len(blockReaders) > 0 {
 			res = append(res, block.NewFetchBlockResult(start, blockReaders, nil))
 		}
@@ -510,3 +529,17 @@ func (r *Reader) streamBlock(
 
 	return xio.BlockReader{}, false, nil
 }
+
+// streamLate returns readers of the data of the series persisted to late
+// data sidecar volumes of the block, these are merged with the rest of the
+// data of the block at read time.
+func (r *Reader) streamLate(
+	ctx context.Context,
+	start xtime.UnixNano,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, error) {
+	if !r.opts.LateWritesEnabled() || r.retriever == nil {
+		return nil, nil
+	}
+	return r.retriever.StreamLate(ctx, r.id, start, nsCtx)
+}
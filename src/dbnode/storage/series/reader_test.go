@@ -94,6 +94,52 @@ func TestReaderUsingRetrieverReadEncoded(t *testing.T) {
 	require.Equal(t, 2, count)
 }
 
+func TestReaderUsingRetrieverReadEncodedLateData(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSeriesTestOptions().SetLateWritesEnabled(true)
+	ropts := opts.RetentionOptions()
+
+	end := xtime.ToUnixNano(opts.ClockOptions().NowFn()().Truncate(ropts.BlockSize()))
+	start := end.Add(-ropts.BlockSize())
+
+	onRetrieveBlock := block.NewMockOnRetrieveBlock(ctrl)
+
+	ctx := opts.ContextPool().Get()
+	defer ctx.Close()
+
+	diskReader := xio.BlockReader{
+		SegmentReader: xio.NewMockSegmentReader(ctrl),
+		Start:         start,
+	}
+	lateReaders := []xio.BlockReader{
+		{SegmentReader: xio.NewMockSegmentReader(ctrl), Start: start},
+		{SegmentReader: xio.NewMockSegmentReader(ctrl), Start: start},
+	}
+
+	retriever := NewMockQueryableBlockRetriever(ctrl)
+	retriever.EXPECT().IsBlockRetrievable(start).Return(true, nil)
+	retriever.EXPECT().
+		Stream(ctx, ident.NewIDMatcher("foo"), start, onRetrieveBlock, gomock.Any()).
+		Return(diskReader, nil)
+	retriever.EXPECT().
+		StreamLate(ctx, ident.NewIDMatcher("foo"), start, gomock.Any()).
+		Return(lateReaders, nil)
+
+	reader := NewReaderUsingRetriever(
+		ident.StringID("foo"), retriever, onRetrieveBlock, nil, opts)
+
+	iter, err := reader.ReadEncoded(ctx, start, end, namespace.Context{})
+	require.NoError(t, err)
+
+	require.True(t, iter.Next(ctx))
+	require.Equal(t, []xio.BlockReader{diskReader, lateReaders[0], lateReaders[1]},
+		iter.Current())
+	require.False(t, iter.Next(ctx))
+	require.NoError(t, iter.Err())
+}
+
 func TestReaderUsingRetrieverWideEntrysBlockInvalid(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
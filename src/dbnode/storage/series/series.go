@@ -651,6 +651,29 @@ func (s *dbSeries) ColdFlushBlockStarts(blockStates BootstrappedBlockStateSnapsh
 	return s.buffer.ColdFlushBlockStarts(blockStates.Snapshot)
 }
 
+func (s *dbSeries) LateFlushBlockStarts(blockStates BootstrappedBlockStateSnapshot) OptimizedTimes {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.buffer.LateFlushBlockStarts(blockStates.Snapshot)
+}
+
+func (s *dbSeries) LateFlush(
+	ctx context.Context,
+	blockStart xtime.UnixNano,
+	version int,
+	persistFn persist.DataFn,
+	nsCtx namespace.Context,
+) (FlushOutcome, error) {
+	// Need a write lock because the buffer LateFlush method mutates
+	// state (by updating the version of the flushed buckets).
+	s.Lock()
+	outcome, err := s.buffer.LateFlush(ctx, blockStart, version,
+		persist.NewMetadata(s.metadata), persistFn, nsCtx)
+	s.Unlock()
+	return outcome, err
+}
+
 func (s *dbSeries) Bootstrap(nsCtx namespace.Context) error {
 	// NB(r): Need to hold the lock the whole time since
 	// this needs to be consistent view for a tick to see.
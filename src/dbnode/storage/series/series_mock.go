@@ -206,6 +206,35 @@ func (mr *MockDatabaseSeriesMockRecorder) IsEmpty() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEmpty", reflect.TypeOf((*MockDatabaseSeries)(nil).IsEmpty))
 }
 
+// LateFlush mocks base method.
+func (m *MockDatabaseSeries) LateFlush(arg0 context.Context, arg1 time.UnixNano, arg2 int, arg3 persist.DataFn, arg4 namespace.Context) (FlushOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LateFlush", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(FlushOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LateFlush indicates an expected call of LateFlush.
+func (mr *MockDatabaseSeriesMockRecorder) LateFlush(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LateFlush", reflect.TypeOf((*MockDatabaseSeries)(nil).LateFlush), arg0, arg1, arg2, arg3, arg4)
+}
+
+// LateFlushBlockStarts mocks base method.
+func (m *MockDatabaseSeries) LateFlushBlockStarts(arg0 BootstrappedBlockStateSnapshot) OptimizedTimes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LateFlushBlockStarts", arg0)
+	ret0, _ := ret[0].(OptimizedTimes)
+	return ret0
+}
+
+// LateFlushBlockStarts indicates an expected call of LateFlushBlockStarts.
+func (mr *MockDatabaseSeriesMockRecorder) LateFlushBlockStarts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LateFlushBlockStarts", reflect.TypeOf((*MockDatabaseSeries)(nil).LateFlushBlockStarts), arg0)
+}
+
 // LoadBlock mocks base method.
 func (m *MockDatabaseSeries) LoadBlock(arg0 block.DatabaseBlock, arg1 WriteType) error {
 	m.ctrl.T.Helper()
@@ -456,6 +485,21 @@ func (mr *MockQueryableBlockRetrieverMockRecorder) Stream(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockQueryableBlockRetriever)(nil).Stream), arg0, arg1, arg2, arg3, arg4)
 }
 
+// StreamLate mocks base method.
+func (m *MockQueryableBlockRetriever) StreamLate(arg0 context.Context, arg1 ident.ID, arg2 time.UnixNano, arg3 namespace.Context) ([]xio.BlockReader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamLate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]xio.BlockReader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamLate indicates an expected call of StreamLate.
+func (mr *MockQueryableBlockRetrieverMockRecorder) StreamLate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamLate", reflect.TypeOf((*MockQueryableBlockRetriever)(nil).StreamLate), arg0, arg1, arg2, arg3)
+}
+
 // StreamWideEntry mocks base method.
 func (m *MockQueryableBlockRetriever) StreamWideEntry(arg0 context.Context, arg1 ident.ID, arg2 time.UnixNano, arg3 schema.WideEntryFilter, arg4 namespace.Context) (block.StreamedWideEntry, error) {
 	m.ctrl.T.Helper()
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/x/xio"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/x/clock"
 	"github.com/m3db/m3/src/x/context"
@@ -154,6 +155,21 @@ type DatabaseSeries interface {
 	// ColdFlushBlockStarts returns the block starts that need cold flushes.
 	ColdFlushBlockStarts(blockStates BootstrappedBlockStateSnapshot) OptimizedTimes
 
+	// LateFlushBlockStarts returns the block starts that have late writes
+	// that need to be flushed to late data sidecar volumes.
+	LateFlushBlockStarts(blockStates BootstrappedBlockStateSnapshot) OptimizedTimes
+
+	// LateFlush flushes the LateWrites of this series for a given start time
+	// to a late data sidecar volume, marking them with the volume's version so
+	// they are evicted once the volume has been persisted.
+	LateFlush(
+		ctx context.Context,
+		blockStart xtime.UnixNano,
+		version int,
+		persistFn persist.DataFn,
+		nsCtx namespace.Context,
+	) (FlushOutcome, error)
+
 	// Bootstrap will moved any bootstrapped data to buffer so series
 	// is ready for reading.
 	Bootstrap(nsCtx namespace.Context) error
@@ -206,6 +222,15 @@ type QueryableBlockRetriever interface {
 	// successfully persisted.
 	RetrievableBlockColdVersion(blockStart xtime.UnixNano) (int, error)
 
+	// StreamLate streams the data of a series persisted to the late data
+	// sidecar volumes of a given block start.
+	StreamLate(
+		ctx context.Context,
+		id ident.ID,
+		blockStart xtime.UnixNano,
+		nsCtx namespace.Context,
+	) ([]xio.BlockReader, error)
+
 	// BlockStatesSnapshot returns a snapshot of the whether blocks are
 	// retrievable and their flush versions for each block start. This is used
 	// to reduce lock contention of acquiring flush state.
@@ -249,6 +274,7 @@ type BootstrappedBlockStateSnapshot struct {
 type BlockState struct {
 	WarmRetrievable bool
 	ColdVersion     int
+	LateVersion     int
 }
 
 // TickStatus is the status of a series for a given tick.
@@ -381,6 +407,14 @@ type Options interface {
 	// ColdWritesEnabled returns whether cold writes are enabled.
 	ColdWritesEnabled() bool
 
+	// SetLateWritesEnabled sets whether cold writes too far in the past are
+	// staged as late writes.
+	SetLateWritesEnabled(value bool) Options
+
+	// LateWritesEnabled returns whether cold writes too far in the past are
+	// staged as late writes.
+	LateWritesEnabled() bool
+
 	// SetBufferBucketVersionsPool sets the BufferBucketVersionsPool.
 	SetBufferBucketVersionsPool(value *BufferBucketVersionsPool) Options
 
@@ -404,6 +438,7 @@ type Options interface {
 type Stats struct {
 	encoderCreated            tally.Counter
 	coldWrites                tally.Counter
+	lateWrites                tally.Counter
 	encodersPerBlock          tally.Histogram
 	encoderLimitWriteRejected tally.Counter
 	snapshotMergesEachBucket  tally.Counter
@@ -418,6 +453,7 @@ func NewStats(scope tally.Scope) Stats {
 	return Stats{
 		encoderCreated:            subScope.Counter("encoder-created"),
 		coldWrites:                subScope.Counter("cold-writes"),
+		lateWrites:                subScope.Counter("late-writes"),
 		encodersPerBlock:          subScope.Histogram("encoders-per-block", buckets),
 		encoderLimitWriteRejected: subScope.Counter("encoder-limit-write-rejected"),
 		snapshotMergesEachBucket:  subScope.Counter("snapshot-merges-each-bucket"),
@@ -434,6 +470,11 @@ func (s Stats) IncColdWrites() {
 	s.coldWrites.Inc(1)
 }
 
+// IncLateWrites incs the LateWrites stat.
+func (s Stats) IncLateWrites() {
+	s.lateWrites.Inc(1)
+}
+
 // RecordEncodersPerBlock records the number of encoders histogram.
 func (s Stats) RecordEncodersPerBlock(num int) {
 	s.encodersPerBlock.RecordValue(float64(num))
@@ -453,6 +494,11 @@ const (
 
 	// ColdWrite represents cold writes (outside the buffer past/future window).
 	ColdWrite
+
+	// LateWrite represents cold writes before the buffer past window that are
+	// persisted to late data sidecar volumes instead of being merged into the
+	// fileset of their block.
+	LateWrite
 )
 
 // WriteTransformOptions describes transforms to run on incoming writes.
@@ -44,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/storage/series/lookup"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/tracepoint"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
@@ -67,6 +68,10 @@ import (
 const (
 	shardIterateBatchPercent = 0.01
 	shardIterateBatchMinSize = 16
+
+	// lateVolumesCompactionThreshold is the number of late data sidecar
+	// volumes of a block at which they are merged into its fileset.
+	lateVolumesCompactionThreshold = 8
 )
 
 var (
@@ -128,6 +133,8 @@ type dbShard struct {
 	increasingIndex          increasingIndex
 	seriesPool               series.DatabaseSeriesPool
 	reverseIndex             NamespaceIndex
+	tombstones               tombstone.Store
+	lateSeekers              fs.LateFileSetSeekers
 	insertQueue              *dbShardInsertQueue
 	lookup                   *shardMap
 	list                     *list.List
@@ -248,6 +255,7 @@ func newDatabaseShard(
 	namespaceReaderMgr databaseNamespaceReaderManager,
 	increasingIndex increasingIndex,
 	reverseIndex NamespaceIndex,
+	tombstones tombstone.Store,
 	needsBootstrap bool,
 	opts Options,
 	seriesOpts series.Options,
@@ -266,6 +274,7 @@ func newDatabaseShard(
 		increasingIndex:      increasingIndex,
 		seriesPool:           opts.DatabaseSeriesPool(),
 		reverseIndex:         reverseIndex,
+		tombstones:           tombstones,
 		lookup:               newShardMap(shardMapOptions{}),
 		list:                 list.New(),
 		newMergerFn:          fs.NewMerger,
@@ -303,6 +312,13 @@ func newDatabaseShard(
 		s.bootstrapState = Bootstrapped
 	}
 
+	if nsOpts := namespaceMetadata.Options(); nsOpts.LateWritesEnabled() {
+		fsOpts := opts.CommitLogOptions().FilesystemOptions()
+		s.lateSeekers = fs.NewLateFileSetSeekers(fsOpts.FilePathPrefix(),
+			namespaceMetadata.ID(), shard, nsOpts.RetentionOptions().BlockSize(),
+			opts.BytesPool(), fsOpts)
+	}
+
 	if blockRetriever != nil {
 		s.setBlockRetriever(blockRetriever)
 	}
@@ -354,6 +370,19 @@ func (s *dbShard) Stream(
 		blockStart, onRetrieve, nsCtx)
 }
 
+// StreamLate implements series.QueryableBlockRetriever
+func (s *dbShard) StreamLate(
+	ctx context.Context,
+	id ident.ID,
+	blockStart xtime.UnixNano,
+	nsCtx namespace.Context,
+) ([]xio.BlockReader, error) {
+	if s.lateSeekers == nil {
+		return nil, nil
+	}
+	return s.lateSeekers.Stream(ctx, id, blockStart)
+}
+
 // StreamWideEntry implements series.QueryableBlockRetriever
 func (s *dbShard) StreamWideEntry(
 	ctx context.Context,
@@ -430,6 +459,7 @@ func (s *dbShard) blockStatesSnapshotWithRLock() series.ShardBlockStateSnapshot
 			// will be used to make eviction decisions and we don't want to evict data before
 			// it is retrievable.
 			ColdVersion: state.ColdVersionRetrievable,
+			LateVersion: state.LateVersion,
 		}
 	}
 
@@ -648,6 +678,11 @@ func (s *dbShard) Close() error {
 	// should be increased.
 	cancellable := context.NewNoOpCanncellable()
 	_, err := s.tickAndExpire(cancellable, tickPolicyCloseShard, namespace.Context{})
+	if s.lateSeekers != nil {
+		if closeErr := s.lateSeekers.Close(); err == nil {
+			err = closeErr
+		}
+	}
 	return err
 }
 
@@ -1092,6 +1127,73 @@ func (s *dbShard) ReadEncoded(
 	return reader.ReadEncoded(ctx, start, end, nsCtx)
 }
 
+// ForEachUnflushed calls the function with the data of each series from the
+// start onwards along with the data of blocks with cold writes that are yet
+// to be cold flushed, the data includes what has been persisted for those
+// blocks.
+func (s *dbShard) ForEachUnflushed(
+	start xtime.UnixNano,
+	nsCtx namespace.Context,
+	fn forEachUnflushedFn,
+) error {
+	blockStates, bootstrapped := s.BlockStatesSnapshot().UnwrapValue()
+	if !bootstrapped {
+		return errFlushStateIsNotInitialized
+	}
+
+	var (
+		ropts     = s.namespace.Options().RetentionOptions()
+		blockSize = ropts.BlockSize()
+		end       = xtime.ToUnixNano(s.nowFn()).Add(ropts.BufferFuture()).
+				Truncate(blockSize).Add(blockSize)
+		ctx     = s.contextPool.Get()
+		loopErr error
+	)
+	defer ctx.Close()
+
+	s.forEachShardEntry(func(entry *lookup.Entry) bool {
+		var (
+			curr   = entry.Series
+			blocks [][]xio.BlockReader
+			err    error
+		)
+		read := func(start, end xtime.UnixNano) {
+			if err != nil {
+				return
+			}
+			var iter series.BlockReaderIter
+			iter, err = curr.ReadEncoded(ctx, start, end, nsCtx)
+			if err != nil {
+				return
+			}
+			var readers [][]xio.BlockReader
+			readers, err = iter.ToSlices(ctx)
+			blocks = append(blocks, readers...)
+		}
+
+		coldBlockStarts := curr.ColdFlushBlockStarts(blockStates)
+		coldBlockStarts.ForEach(func(t xtime.UnixNano) {
+			if t.Before(start) {
+				read(t, t.Add(blockSize))
+			}
+		})
+		read(start, end)
+		if err == nil && len(blocks) > 0 {
+			err = fn(curr.Metadata(), blocks)
+		}
+
+		// NB: use BlockingCloseReset so the context can be reused for the
+		// next series once the data of this one has been consumed.
+		ctx.BlockingCloseReset()
+		if err != nil {
+			loopErr = err
+			return false
+		}
+		return true
+	})
+	return loopErr
+}
+
 func (s *dbShard) FetchWideEntry(
 	ctx context.Context,
 	id ident.ID,
@@ -1960,6 +2062,43 @@ func (s *dbShard) UpdateFlushStates() {
 			s.setFlushStateColdVersionFlushed(at, info.VolumeIndex)
 		}
 	}
+
+	if s.lateSeekers != nil {
+		s.updateLateFlushStates()
+	}
+}
+
+// updateLateFlushStates opens the late data sidecar volumes on disk so that
+// they can be queried and bootstraps the late version of their blocks so
+// that the volume numbers remain monotonically increasing.
+func (s *dbShard) updateLateFlushStates() {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	volumesByBlock, err := fs.LateFileSetVolumes(fsOpts.FilePathPrefix(),
+		s.namespace.ID(), s.shard)
+	if err != nil {
+		s.logger.Error("unable to read late data volumes in shard bootstrap",
+			zap.Uint32("shard", s.ID()),
+			zap.Stringer("namespace", s.namespace.ID()),
+			zap.Error(err))
+		return
+	}
+
+	for at, volumes := range volumesByBlock {
+		for _, volume := range volumes {
+			if err := s.lateSeekers.Open(at, volume); err != nil {
+				s.logger.Error("unable to open late data volume in shard bootstrap",
+					zap.Uint32("shard", s.ID()),
+					zap.Stringer("namespace", s.namespace.ID()),
+					zap.Time("blockStart", at.ToTime()),
+					zap.Int("volume", volume),
+					zap.Error(err))
+				continue
+			}
+			if s.flushStateNoBootstrapCheck(at).LateVersion < volume {
+				s.setFlushStateLateVersion(at, volume)
+			}
+		}
+	}
 }
 
 func (s *dbShard) Bootstrap(
@@ -2301,7 +2440,30 @@ func (s *dbShard) ColdFlush(
 		return shardColdFlush{}, loopErr
 	}
 
-	if dirtySeries.Len() == 0 {
+	// Blocks with series deleted since they were last merged need to be
+	// merged regardless of whether they have any dirty series so that the
+	// deleted data is dropped from disk.
+	tombstoneBlocks, err := s.tombstoneBlocksToColdFlush()
+	if err != nil {
+		return shardColdFlush{}, err
+	}
+	for blockStart := range tombstoneBlocks {
+		if dirtySeriesToWrite[blockStart] == nil {
+			dirtySeriesToWrite[blockStart] = newIDList(idElementPool)
+		}
+	}
+
+	// Blocks with enough late data sidecar volumes are compacted by merging
+	// the volumes into the fileset of the block so that reads don't have to
+	// seek into too many volumes.
+	lateBlocks := s.lateBlocksToColdFlush(blockStatesSnapshot)
+	for _, blockStart := range lateBlocks {
+		if dirtySeriesToWrite[blockStart] == nil {
+			dirtySeriesToWrite[blockStart] = newIDList(idElementPool)
+		}
+	}
+
+	if dirtySeries.Len() == 0 && len(tombstoneBlocks) == 0 && len(lateBlocks) == 0 {
 		// Early exit if there is nothing dirty to merge. dirtySeriesToWrite
 		// may be non-empty when dirtySeries is empty because we purposely
 		// leave empty seriesLists in the dirtySeriesToWrite map to avoid having
@@ -2317,7 +2479,8 @@ func (s *dbShard) ColdFlush(
 		s.opts.SegmentReaderPool(), s.opts.MultiReaderIteratorPool(),
 		s.opts.IdentifierPool(), s.opts.EncoderPool(), s.opts.ContextPool(),
 		s.opts.CommitLogOptions().FilesystemOptions().FilePathPrefix(), s.namespace.Options())
-	mergeWithMem := s.newFSMergeWithMemFn(s, s, dirtySeries, dirtySeriesToWrite)
+	mergeWithMem := s.newFSMergeWithMemFn(s, s, dirtySeries, dirtySeriesToWrite,
+		s.tombstones, s.namespace.Options().RetentionOptions().BlockSize())
 	// Loop through each block that we know has ColdWrites. Since each block
 	// has its own fileset, if we encounter an error while trying to persist
 	// a block, we continue to try persisting other blocks.
@@ -2335,22 +2498,253 @@ func (s *dbShard) ColdFlush(
 			VolumeIndex: coldVersion,
 		}
 
+		// The merge reads the existing fileset of the block so it must be on
+		// local disk if it has been offloaded.
+		if offloader := s.opts.FileSetOffloader(); offloader != nil {
+			if err := offloader.Restore(s.namespace.ID(), s.ID(), startTime); err != nil {
+				multiErr = multiErr.Add(err)
+				continue
+			}
+		}
+
+		// Any late data sidecar volumes of a block that is merged are merged
+		// too since the fileset of the block is rewritten regardless.
+		var (
+			mergeWith  = mergeWithMem
+			mergeLate  *fsMergeWithLate
+			lateVolume int
+		)
+		if s.lateSeekers != nil {
+			if volumes := s.lateSeekers.Volumes(startTime); len(volumes) > 0 {
+				late, err := s.lateMergeWith(startTime, volumes)
+				if err != nil {
+					multiErr = multiErr.Add(err)
+					continue
+				}
+				mergeLate = newFSMergeWithLate(mergeWithMem, late)
+				mergeWith = mergeLate
+				lateVolume = volumes[len(volumes)-1]
+			}
+		}
+
 		nextVersion := coldVersion + 1
-		close, err := merger.Merge(fsID, mergeWithMem, nextVersion, flushPreparer, nsCtx,
+		close, err := merger.Merge(fsID, mergeWith, nextVersion, flushPreparer, nsCtx,
 			onFlushSeries)
 		if err != nil {
 			multiErr = multiErr.Add(err)
 			continue
 		}
-		flush.doneFns = append(flush.doneFns, shardColdFlushDone{
-			startTime:   startTime,
-			nextVersion: nextVersion,
-			close:       close,
-		})
+		tombstoneBlock, hasTombstoneBlock := tombstoneBlocks[startTime]
+		done := shardColdFlushDone{
+			startTime:         startTime,
+			nextVersion:       nextVersion,
+			close:             close,
+			tombstoneBlock:    tombstoneBlock,
+			hasTombstoneBlock: hasTombstoneBlock,
+		}
+		if mergeLate != nil {
+			done.lateVolume = lateVolume
+			done.lateIDs = mergeLate.lateIDs
+		}
+		flush.doneFns = append(flush.doneFns, done)
 	}
 	return flush, multiErr.FinalError()
 }
 
+// lateBlocksToColdFlush returns the blocks with enough late data sidecar
+// volumes to be compacted, only blocks that have been warm flushed can be
+// merged and the rest remain as sidecar volumes until they are.
+func (s *dbShard) lateBlocksToColdFlush(
+	blockStates series.BootstrappedBlockStateSnapshot,
+) []xtime.UnixNano {
+	if s.lateSeekers == nil {
+		return nil
+	}
+
+	var blocks []xtime.UnixNano
+	for blockStart, state := range blockStates.Snapshot {
+		if !state.WarmRetrievable ||
+			len(s.lateSeekers.Volumes(blockStart)) < lateVolumesCompactionThreshold {
+			continue
+		}
+		blocks = append(blocks, blockStart)
+	}
+	return blocks
+}
+
+// lateMergeWith returns a merge target holding the data of the given late
+// data sidecar volumes of a block.
+func (s *dbShard) lateMergeWith(
+	blockStart xtime.UnixNano,
+	volumes []int,
+) (fs.MergeWith, error) {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	reader, err := s.newReaderFn(s.opts.BytesPool(), fsOpts.SetFilePathPrefix(
+		fs.NamespaceLateFilePathPrefix(fsOpts.FilePathPrefix(), s.namespace.ID())))
+	if err != nil {
+		return nil, err
+	}
+
+	fileIDs := make([]fs.FileSetFileIdentifier, 0, len(volumes))
+	for _, volume := range volumes {
+		fileIDs = append(fileIDs, fs.FileSetFileIdentifier{
+			Namespace:   s.namespace.ID(),
+			Shard:       s.ID(),
+			BlockStart:  blockStart,
+			VolumeIndex: volume,
+		})
+	}
+	return fs.NewLateMergeWith(reader, fileIDs, blockStart,
+		s.namespace.Options().RetentionOptions().BlockSize())
+}
+
+// tombstoneBlocksToColdFlush returns the blocks with deleted series pending
+// removal from disk, only blocks that have been warm flushed can be merged
+// and the rest remain pending until they are.
+func (s *dbShard) tombstoneBlocksToColdFlush() (map[xtime.UnixNano]tombstone.PendingBlock, error) {
+	if s.tombstones == nil {
+		return nil, nil
+	}
+
+	var blocks map[xtime.UnixNano]tombstone.PendingBlock
+	for _, block := range s.tombstones.PendingBlocks(s.ID()) {
+		hasWarmFlushed, err := s.hasWarmFlushed(block.BlockStart)
+		if err != nil {
+			return nil, err
+		}
+		if !hasWarmFlushed {
+			continue
+		}
+		if blocks == nil {
+			blocks = make(map[xtime.UnixNano]tombstone.PendingBlock)
+		}
+		blocks[block.BlockStart] = block
+	}
+	return blocks, nil
+}
+
+func (s *dbShard) LateFlush(nsCtx namespace.Context) error {
+	// We don't flush data when the shard is still bootstrapping.
+	s.RLock()
+	if s.bootstrapState != Bootstrapped {
+		s.RUnlock()
+		return errShardNotBootstrappedToFlush
+	}
+	blockStates := s.blockStatesSnapshotWithRLock()
+	s.RUnlock()
+
+	if s.lateSeekers == nil {
+		return nil
+	}
+
+	blockStatesSnapshot, bootstrapped := blockStates.UnwrapValue()
+	if !bootstrapped {
+		return errFlushStateIsNotInitialized
+	}
+
+	// Capture which blocks have late writes to flush, late writes do not
+	// need their blocks to have been warm flushed since the sidecar volumes
+	// are queried independently of the filesets of the blocks.
+	dirty := make(map[xtime.UnixNano][]*lookup.Entry)
+	s.forEachShardEntry(func(entry *lookup.Entry) bool {
+		blockStarts := entry.Series.LateFlushBlockStarts(blockStatesSnapshot)
+		blockStarts.ForEach(func(t xtime.UnixNano) {
+			entry.IncrementReaderWriterCount()
+			dirty[t] = append(dirty[t], entry)
+		})
+		return true
+	})
+
+	var multiErr xerrors.MultiError
+	for blockStart, entries := range dirty {
+		if err := s.lateFlushBlock(blockStart, entries, nsCtx); err != nil {
+			multiErr = multiErr.Add(err)
+		}
+		for _, entry := range entries {
+			entry.DecrementReaderWriterCount()
+		}
+	}
+	return multiErr.FinalError()
+}
+
+// lateFlushBlock persists the late writes of the given series for a block
+// to a new late data sidecar volume.
+func (s *dbShard) lateFlushBlock(
+	blockStart xtime.UnixNano,
+	entries []*lookup.Entry,
+	nsCtx namespace.Context,
+) error {
+	fsOpts := s.opts.CommitLogOptions().FilesystemOptions()
+	writer, err := fs.NewWriter(fsOpts.SetFilePathPrefix(
+		fs.NamespaceLateFilePathPrefix(fsOpts.FilePathPrefix(), s.namespace.ID())))
+	if err != nil {
+		return err
+	}
+
+	flushState, err := s.FlushState(blockStart)
+	if err != nil {
+		return err
+	}
+	nextVersion := flushState.LateVersion + 1
+	err = writer.Open(fs.DataWriterOpenOptions{
+		Identifier: fs.FileSetFileIdentifier{
+			Namespace:   s.namespace.ID(),
+			Shard:       s.ID(),
+			BlockStart:  blockStart,
+			VolumeIndex: nextVersion,
+		},
+		BlockSize:   s.namespace.Options().RetentionOptions().BlockSize(),
+		FileSetType: persist.FileSetFlushType,
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		multiErr      xerrors.MultiError
+		segmentHolder = make([]checked.Bytes, 2)
+		flushCtx      = s.contextPool.Get()
+		flushResult   = dbShardFlushResult{}
+	)
+	persistFn := func(metadata persist.Metadata, segment ts.Segment, checksum uint32) error {
+		segmentHolder[0] = segment.Head
+		segmentHolder[1] = segment.Tail
+		return writer.WriteAll(metadata, segmentHolder, checksum)
+	}
+	for _, entry := range entries {
+		// Use a temporary context here so the stream readers can be returned to
+		// the pool after we finish flushing the series.
+		flushCtx.Reset()
+		flushOutcome, err := entry.Series.LateFlush(flushCtx, blockStart, nextVersion,
+			persistFn, nsCtx)
+		// Use BlockingCloseReset so context doesn't get returned to the pool.
+		flushCtx.BlockingCloseReset()
+		if err != nil {
+			// If we encounter an error when persisting a series, don't continue as
+			// the file on disk could be in a corrupt state.
+			multiErr = multiErr.Add(err)
+			break
+		}
+		flushResult.update(flushOutcome)
+	}
+	s.logFlushResult(flushResult)
+
+	if err := writer.Close(); err != nil {
+		multiErr = multiErr.Add(err)
+	}
+	if err := multiErr.FinalError(); err != nil {
+		return err
+	}
+
+	// Only update the late version once the volume can be queried so that a
+	// concurrent tick does not evict the late writes from memory before.
+	if err := s.lateSeekers.Open(blockStart, nextVersion); err != nil {
+		return err
+	}
+	s.setFlushStateLateVersion(blockStart, nextVersion)
+	return nil
+}
+
 func (s *dbShard) Snapshot(
 	blockStart xtime.UnixNano,
 	snapshotTime xtime.UnixNano,
@@ -2501,6 +2895,9 @@ func (s *dbShard) markWarmFlushStateSuccess(blockStart xtime.UnixNano) {
 	s.flushState.statesByTime[blockStart] =
 		fileOpState{
 			WarmStatus: fileOpSuccess,
+			// Late writes are persisted independently of warm flushes so the
+			// version of their sidecar volumes is retained.
+			LateVersion: s.flushState.statesByTime[blockStart].LateVersion,
 		}
 	s.flushState.Unlock()
 }
@@ -2530,6 +2927,14 @@ func (s *dbShard) setFlushStateColdVersionFlushed(blockStart xtime.UnixNano, ver
 	s.flushState.Unlock()
 }
 
+func (s *dbShard) setFlushStateLateVersion(blockStart xtime.UnixNano, version int) {
+	s.flushState.Lock()
+	state := s.flushState.statesByTime[blockStart]
+	state.LateVersion = version
+	s.flushState.statesByTime[blockStart] = state
+	s.flushState.Unlock()
+}
+
 func (s *dbShard) removeAnyFlushStatesTooEarly(startTime xtime.UnixNano) {
 	s.flushState.Lock()
 	earliestFlush := retention.FlushTimeStart(s.namespace.Options().RetentionOptions(), startTime)
@@ -2549,6 +2954,13 @@ func (s *dbShard) CleanupExpiredFileSets(earliestToRetain xtime.UnixNano) error
 			filePathPrefix, s.namespace.ID(), s.ID(), err)
 	}
 
+	if s.lateSeekers != nil {
+		if err := s.lateSeekers.RemoveBefore(earliestToRetain); err != nil {
+			return fmt.Errorf("encountered errors when removing late data volumes for namespace %s shard %d: %v",
+				s.namespace.ID(), s.ID(), err)
+		}
+	}
+
 	return s.deleteFilesFn(expired)
 }
 
@@ -2740,9 +3152,13 @@ func (s *dbShard) finishWriting(
 }
 
 type shardColdFlushDone struct {
-	startTime   xtime.UnixNano
-	nextVersion int
-	close       persist.DataCloser
+	startTime         xtime.UnixNano
+	nextVersion       int
+	close             persist.DataCloser
+	tombstoneBlock    tombstone.PendingBlock
+	hasTombstoneBlock bool
+	lateVolume        int
+	lateIDs           []ident.ID
 }
 
 type shardColdFlush struct {
@@ -2764,11 +3180,46 @@ func (s shardColdFlush) Done() error {
 		err := s.shard.finishWriting(startTime, nextVersion, false)
 		if err != nil {
 			multiErr = multiErr.Add(err)
+			continue
+		}
+
+		if done.hasTombstoneBlock {
+			// The deleted series have now been dropped from this block on disk.
+			err := s.shard.tombstones.MarkCompacted(s.shard.ID(), done.tombstoneBlock)
+			if err != nil {
+				multiErr = multiErr.Add(err)
+			}
+		}
+
+		if done.lateVolume > 0 {
+			// The late data sidecar volumes have now been merged into this block
+			// on disk, blocks cached from the previous fileset are missing the
+			// late data so they are evicted before the volumes are removed.
+			s.shard.evictCachedBlocks(done.lateIDs, startTime)
+			if err := s.shard.lateSeekers.Remove(startTime, done.lateVolume); err != nil {
+				multiErr = multiErr.Add(err)
+			}
 		}
 	}
 	return multiErr.FinalError()
 }
 
+func (s *dbShard) evictCachedBlocks(ids []ident.ID, blockStart xtime.UnixNano) {
+	for _, id := range ids {
+		s.RLock()
+		entry, _, err := s.lookupEntryWithLock(id)
+		if entry != nil {
+			entry.IncrementReaderWriterCount()
+		}
+		s.RUnlock()
+		if err != nil || entry == nil {
+			continue
+		}
+		entry.Series.OnEvictedFromWiredList(id, blockStart)
+		entry.DecrementReaderWriterCount()
+	}
+}
+
 // dbShardFlushResult is a helper struct for keeping track of the result of flushing all the
 // series in the shard.
 type dbShardFlushResult struct {
@@ -43,6 +43,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/index/convert"
 	"github.com/m3db/m3/src/dbnode/storage/series"
 	"github.com/m3db/m3/src/dbnode/storage/series/lookup"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/ts"
 	xmetrics "github.com/m3db/m3/src/dbnode/x/metrics"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -92,7 +93,7 @@ func testDatabaseShardWithIndexFn(
 		SetColdWritesEnabled(coldWritesEnabled)
 
 	return newDatabaseShard(metadata, 0, nil, nsReaderMgr,
-		&testIncreasingIndex{}, idx, true, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, idx, nil, true, opts, seriesOpts).(*dbShard)
 }
 
 func addMockSeries(ctrl *gomock.Controller, shard *dbShard, id ident.ID, tags ident.Tags, index uint64) *series.MockDatabaseSeries {
@@ -114,7 +115,7 @@ func TestShardDontNeedBootstrap(t *testing.T) {
 	defer closer()
 	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
 	shard := newDatabaseShard(testNs.metadata, 0, nil, nil,
-		&testIncreasingIndex{}, nil, false, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, nil, nil, false, opts, seriesOpts).(*dbShard)
 	defer shard.Close()
 
 	require.Equal(t, Bootstrapped, shard.bootstrapState)
@@ -127,7 +128,7 @@ func TestShardErrorIfDoubleBootstrap(t *testing.T) {
 	defer closer()
 	seriesOpts := NewSeriesOptionsFromOptions(opts, testNs.Options().RetentionOptions())
 	shard := newDatabaseShard(testNs.metadata, 0, nil, nil,
-		&testIncreasingIndex{}, nil, false, opts, seriesOpts).(*dbShard)
+		&testIncreasingIndex{}, nil, nil, false, opts, seriesOpts).(*dbShard)
 	defer shard.Close()
 
 	require.Equal(t, Bootstrapped, shard.bootstrapState)
@@ -677,6 +678,256 @@ func TestShardColdFlush(t *testing.T) {
 	require.Equal(t, 0, coldVersion)
 }
 
+type testLateWritesShards struct {
+	t      *testing.T
+	opts   Options
+	nsOpts namespace.Options
+	dir    string
+}
+
+func newTestLateWritesShards(t *testing.T) *testLateWritesShards {
+	dir, err := ioutil.TempDir("", "testdir")
+	require.NoError(t, err)
+
+	now := xtime.Now()
+	opts := DefaultTestOptions()
+	fsOpts := opts.CommitLogOptions().FilesystemOptions().
+		SetFilePathPrefix(dir)
+	opts = opts.
+		SetClockOptions(opts.ClockOptions().SetNowFn(func() time.Time {
+			return now.ToTime()
+		})).
+		SetCommitLogOptions(opts.CommitLogOptions().
+			SetFilesystemOptions(fsOpts))
+
+	return &testLateWritesShards{
+		t:      t,
+		opts:   opts,
+		nsOpts: defaultTestNs1Opts.SetColdWritesEnabled(true).SetLateWritesEnabled(true),
+		dir:    dir,
+	}
+}
+
+func (s *testLateWritesShards) newShard() *dbShard {
+	metadata, err := namespace.NewMetadata(defaultTestNs1ID, s.nsOpts)
+	require.NoError(s.t, err)
+	seriesOpts := NewSeriesOptionsFromOptions(s.opts, s.nsOpts.RetentionOptions()).
+		SetBufferBucketVersionsPool(series.NewBufferBucketVersionsPool(nil)).
+		SetBufferBucketPool(series.NewBufferBucketPool(nil)).
+		SetColdWritesEnabled(true).
+		SetLateWritesEnabled(true)
+	nsReaderMgr := newNamespaceReaderManager(metadata, tally.NoopScope, s.opts)
+	return newDatabaseShard(metadata, 0, nil, nsReaderMgr,
+		&testIncreasingIndex{}, nil, nil, true, s.opts, seriesOpts).(*dbShard)
+}
+
+func (s *testLateWritesShards) blockStart() xtime.UnixNano {
+	blockSize := s.nsOpts.RetentionOptions().BlockSize()
+	return xtime.ToUnixNano(s.opts.ClockOptions().NowFn()()).
+		Truncate(blockSize).Add(-10 * blockSize)
+}
+
+func (s *testLateWritesShards) writeAndLateFlush(
+	ctx context.Context,
+	shard *dbShard,
+	id ident.ID,
+	timestamp xtime.UnixNano,
+	value float64,
+) {
+	seriesWrite, err := shard.Write(ctx, id, timestamp, value, xtime.Second,
+		nil, series.WriteOptions{})
+	require.NoError(s.t, err)
+	require.True(s.t, seriesWrite.WasWritten)
+	require.NoError(s.t, shard.LateFlush(namespace.Context{ID: defaultTestNs1ID}))
+}
+
+func (s *testLateWritesShards) datapoints(readers []xio.BlockReader) []ts.Datapoint {
+	var result []ts.Datapoint
+	for _, reader := range readers {
+		iter := s.opts.ReaderIteratorPool().Get()
+		iter.Reset(reader, nil)
+		for iter.Next() {
+			dp, _, _ := iter.Current()
+			result = append(result, ts.Datapoint{TimestampNanos: dp.TimestampNanos, Value: dp.Value})
+		}
+		require.NoError(s.t, iter.Err())
+		iter.Close()
+	}
+	return result
+}
+
+func TestShardLateFlush(t *testing.T) {
+	shards := newTestLateWritesShards(t)
+	defer os.RemoveAll(shards.dir)
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	nsCtx := namespace.Context{ID: defaultTestNs1ID}
+	shard := shards.newShard()
+	require.NoError(t, shard.Bootstrap(ctx, nsCtx))
+
+	var (
+		blockSize  = shards.nsOpts.RetentionOptions().BlockSize()
+		blockStart = shards.blockStart()
+		id         = ident.StringID("foo")
+	)
+	lateData := func(shard *dbShard) []ts.Datapoint {
+		readers, err := shard.StreamLate(ctx, id, blockStart, nsCtx)
+		require.NoError(t, err)
+		return shards.datapoints(readers)
+	}
+
+	// Each late flush persists the late writes since the last one to a new
+	// sidecar volume of the block.
+	for i := 1; i <= 2; i++ {
+		shards.writeAndLateFlush(ctx, shard, id, blockStart.Add(time.Duration(i)*time.Minute), float64(i))
+		flushState, err := shard.FlushState(blockStart)
+		require.NoError(t, err)
+		require.Equal(t, i, flushState.LateVersion)
+		require.Equal(t, 0, flushState.ColdVersionFlushed)
+	}
+	expected := []ts.Datapoint{
+		{TimestampNanos: blockStart.Add(time.Minute), Value: 1},
+		{TimestampNanos: blockStart.Add(2 * time.Minute), Value: 2},
+	}
+	require.Equal(t, expected, lateData(shard))
+
+	// Nothing new to flush.
+	require.NoError(t, shard.LateFlush(nsCtx))
+	flushState, err := shard.FlushState(blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 2, flushState.LateVersion)
+
+	// A warm flush of the block retains the late version.
+	shard.markWarmFlushStateSuccess(blockStart)
+	flushState, err = shard.FlushState(blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 2, flushState.LateVersion)
+	require.NoError(t, shard.Close())
+
+	// The sidecar volumes are opened and their versions bootstrapped.
+	shard = shards.newShard()
+	require.NoError(t, shard.Bootstrap(ctx, nsCtx))
+	flushState, err = shard.FlushState(blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 2, flushState.LateVersion)
+	require.Equal(t, expected, lateData(shard))
+
+	// Expired sidecar volumes are removed.
+	require.NoError(t, shard.CleanupExpiredFileSets(blockStart.Add(blockSize)))
+	require.Empty(t, lateData(shard))
+	volumes, err := fs.LateFileSetVolumes(shards.dir, defaultTestNs1ID, 0)
+	require.NoError(t, err)
+	require.Empty(t, volumes)
+	require.NoError(t, shard.Close())
+}
+
+type captureMerger struct {
+	noopMerger
+
+	mergeWith fs.MergeWith
+}
+
+func (m *captureMerger) Merge(
+	_ fs.FileSetFileIdentifier,
+	mergeWith fs.MergeWith,
+	_ int,
+	_ persist.FlushPreparer,
+	_ namespace.Context,
+	_ persist.OnFlushSeries,
+) (persist.DataCloser, error) {
+	m.mergeWith = mergeWith
+	closer := func() error { return nil }
+	return closer, nil
+}
+
+func TestShardColdFlushCompactsLateVolumes(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	shards := newTestLateWritesShards(t)
+	defer os.RemoveAll(shards.dir)
+
+	ctx := context.NewBackground()
+	defer ctx.Close()
+
+	nsCtx := namespace.Context{ID: defaultTestNs1ID}
+	shard := shards.newShard()
+	defer shard.Close()
+	require.NoError(t, shard.Bootstrap(ctx, nsCtx))
+
+	merger := &captureMerger{}
+	shard.newMergerFn = func(
+		_ fs.DataFileSetReader,
+		_ int,
+		_ xio.SegmentReaderPool,
+		_ encoding.MultiReaderIteratorPool,
+		_ ident.Pool,
+		_ encoding.EncoderPool,
+		_ context.Pool,
+		_ string,
+		_ namespace.Options,
+	) fs.Merger {
+		return merger
+	}
+
+	var (
+		blockStart = shards.blockStart()
+		id         = ident.StringID("foo")
+		expected   []ts.Datapoint
+	)
+	shard.markWarmFlushStateSuccess(blockStart)
+	resources := coldFlushReusableResources{
+		dirtySeries:        newDirtySeriesMap(),
+		dirtySeriesToWrite: make(map[xtime.UnixNano]*idList),
+		idElementPool:      newIDElementPool(nil),
+		fsReader:           fs.NewMockDataFileSetReader(ctrl),
+	}
+	for i := 1; i <= lateVolumesCompactionThreshold; i++ {
+		// Blocks are only compacted once they have enough sidecar volumes.
+		shardColdFlush, err := shard.ColdFlush(persist.NewMockFlushPreparer(ctrl), resources,
+			nsCtx, &persist.NoOpColdFlushNamespace{})
+		require.NoError(t, err)
+		require.NoError(t, shardColdFlush.Done())
+		require.Nil(t, merger.mergeWith)
+
+		timestamp := blockStart.Add(time.Duration(i) * time.Minute)
+		shards.writeAndLateFlush(ctx, shard, id, timestamp, float64(i))
+		expected = append(expected, ts.Datapoint{TimestampNanos: timestamp, Value: float64(i)})
+	}
+
+	shardColdFlush, err := shard.ColdFlush(persist.NewMockFlushPreparer(ctrl), resources,
+		nsCtx, &persist.NoOpColdFlushNamespace{})
+	require.NoError(t, err)
+	require.NotNil(t, merger.mergeWith)
+
+	// The late data is merged into the fileset of the block.
+	var merged []ts.Datapoint
+	err = merger.mergeWith.ForEachRemaining(ctx, blockStart, func(
+		seriesMetadata doc.Metadata,
+		data block.FetchBlockResult,
+	) error {
+		require.Equal(t, id.String(), string(seriesMetadata.ID))
+		merged = append(merged, shards.datapoints(data.Blocks)...)
+		return nil
+	}, nsCtx)
+	require.NoError(t, err)
+	require.Equal(t, expected, merged)
+
+	// Once the merge is done the sidecar volumes are removed.
+	require.NoError(t, shardColdFlush.Done())
+	coldVersion, err := shard.RetrievableBlockColdVersion(blockStart)
+	require.NoError(t, err)
+	require.Equal(t, 1, coldVersion)
+	readers, err := shard.StreamLate(ctx, id, blockStart, nsCtx)
+	require.NoError(t, err)
+	require.Empty(t, readers)
+	volumes, err := fs.LateFileSetVolumes(shards.dir, defaultTestNs1ID, 0)
+	require.NoError(t, err)
+	require.Empty(t, volumes)
+}
+
 func TestShardColdFlushNoMergeIfNothingDirty(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -780,11 +1031,24 @@ func (m *noopMerger) MergeAndCleanup(
 	return nil
 }
 
+func (m *noopMerger) MergeResized(
+	_ []fs.FileSetFileIdentifier,
+	_ xtime.UnixNano,
+	_ int,
+	_ persist.FlushPreparer,
+	_ namespace.Context,
+) (persist.DataCloser, error) {
+	closer := func() error { return nil }
+	return closer, nil
+}
+
 func newFSMergeWithMemTestFn(
 	_ databaseShard,
 	_ series.QueryableBlockRetriever,
 	_ *dirtySeriesMap,
 	_ map[xtime.UnixNano]*idList,
+	_ tombstone.Store,
+	_ time.Duration,
 ) fs.MergeWith {
 	return fs.NewNoopMergeWith()
 }
@@ -29,6 +29,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/backup"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
@@ -233,6 +234,21 @@ func (mr *MockDatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDatabase)(nil).Close))
 }
 
+// DeleteTagged mocks base method.
+func (m *MockDatabase) DeleteTagged(ctx context.Context, namespace ident.ID, query index.Query, start, end time0.UnixNano) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, namespace, query, start, end)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockDatabaseMockRecorder) DeleteTagged(ctx, namespace, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockDatabase)(nil).DeleteTagged), ctx, namespace, query, start, end)
+}
+
 // FetchBlocks mocks base method.
 func (m *MockDatabase) FetchBlocks(ctx context.Context, namespace ident.ID, shard uint32, id ident.ID, starts []time0.UnixNano) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -672,6 +688,21 @@ func (mr *MockdatabaseMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*Mockdatabase)(nil).Close))
 }
 
+// DeleteTagged mocks base method.
+func (m *Mockdatabase) DeleteTagged(ctx context.Context, namespace ident.ID, query index.Query, start, end time0.UnixNano) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, namespace, query, start, end)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockdatabaseMockRecorder) DeleteTagged(ctx, namespace, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*Mockdatabase)(nil).DeleteTagged), ctx, namespace, query, start, end)
+}
+
 // FetchBlocks mocks base method.
 func (m *Mockdatabase) FetchBlocks(ctx context.Context, namespace ident.ID, shard uint32, id ident.ID, starts []time0.UnixNano) ([]block.FetchBlockResult, error) {
 	m.ctrl.T.Helper()
@@ -775,6 +806,20 @@ func (mr *MockdatabaseMockRecorder) Namespace(ns interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Namespace", reflect.TypeOf((*Mockdatabase)(nil).Namespace), ns)
 }
 
+// NamespaceResizeState mocks base method.
+func (m *Mockdatabase) NamespaceResizeState(id ident.ID) namespace.ResizeState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NamespaceResizeState", id)
+	ret0, _ := ret[0].(namespace.ResizeState)
+	return ret0
+}
+
+// NamespaceResizeState indicates an expected call of NamespaceResizeState.
+func (mr *MockdatabaseMockRecorder) NamespaceResizeState(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamespaceResizeState", reflect.TypeOf((*Mockdatabase)(nil).NamespaceResizeState), id)
+}
+
 // Namespaces mocks base method.
 func (m *Mockdatabase) Namespaces() []Namespace {
 	m.ctrl.T.Helper()
@@ -1362,6 +1407,21 @@ func (mr *MockdatabaseNamespaceMockRecorder) ColdFlush(flush interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockdatabaseNamespace)(nil).ColdFlush), flush)
 }
 
+// DeleteTagged mocks base method.
+func (m *MockdatabaseNamespace) DeleteTagged(ctx context.Context, query index.Query, start, end time0.UnixNano) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagged", ctx, query, start, end)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTagged indicates an expected call of DeleteTagged.
+func (mr *MockdatabaseNamespaceMockRecorder) DeleteTagged(ctx, query, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagged", reflect.TypeOf((*MockdatabaseNamespace)(nil).DeleteTagged), ctx, query, start, end)
+}
+
 // DocRef mocks base method.
 func (m *MockdatabaseNamespace) DocRef(id ident.ID) (doc.Metadata, bool, error) {
 	m.ctrl.T.Helper()
@@ -2164,6 +2224,20 @@ func (mr *MockdatabaseShardMockRecorder) FlushState(blockStart interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushState", reflect.TypeOf((*MockdatabaseShard)(nil).FlushState), blockStart)
 }
 
+// ForEachUnflushed mocks base method.
+func (m *MockdatabaseShard) ForEachUnflushed(start time0.UnixNano, nsCtx namespace.Context, fn forEachUnflushedFn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachUnflushed", start, nsCtx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachUnflushed indicates an expected call of ForEachUnflushed.
+func (mr *MockdatabaseShardMockRecorder) ForEachUnflushed(start, nsCtx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachUnflushed", reflect.TypeOf((*MockdatabaseShard)(nil).ForEachUnflushed), start, nsCtx, fn)
+}
+
 // ID mocks base method.
 func (m *MockdatabaseShard) ID() uint32 {
 	m.ctrl.T.Helper()
@@ -2192,6 +2266,20 @@ func (mr *MockdatabaseShardMockRecorder) IsBootstrapped() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBootstrapped", reflect.TypeOf((*MockdatabaseShard)(nil).IsBootstrapped))
 }
 
+// LateFlush mocks base method.
+func (m *MockdatabaseShard) LateFlush(arg0 namespace.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LateFlush", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LateFlush indicates an expected call of LateFlush.
+func (mr *MockdatabaseShardMockRecorder) LateFlush(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LateFlush", reflect.TypeOf((*MockdatabaseShard)(nil).LateFlush), arg0)
+}
+
 // LatestVolume mocks base method.
 func (m *MockdatabaseShard) LatestVolume(blockStart time0.UnixNano) (int, error) {
 	m.ctrl.T.Helper()
@@ -2551,6 +2639,21 @@ func (mr *MockNamespaceIndexMockRecorder) Bootstrapped() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bootstrapped", reflect.TypeOf((*MockNamespaceIndex)(nil).Bootstrapped))
 }
 
+// Cardinality mocks base method.
+func (m *MockNamespaceIndex) Cardinality(ctx context.Context, opts index.CardinalityQueryOptions) (index.CardinalityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cardinality", ctx, opts)
+	ret0, _ := ret[0].(index.CardinalityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cardinality indicates an expected call of Cardinality.
+func (mr *MockNamespaceIndexMockRecorder) Cardinality(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cardinality", reflect.TypeOf((*MockNamespaceIndex)(nil).Cardinality), ctx, opts)
+}
+
 // CleanupCorruptedFileSets mocks base method.
 func (m *MockNamespaceIndex) CleanupCorruptedFileSets() error {
 	m.ctrl.T.Helper()
@@ -2622,6 +2725,21 @@ func (mr *MockNamespaceIndexMockRecorder) ColdFlush(shards interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColdFlush", reflect.TypeOf((*MockNamespaceIndex)(nil).ColdFlush), shards)
 }
 
+// Count mocks base method.
+func (m *MockNamespaceIndex) Count(ctx context.Context, query index.Query, opts index.CountQueryOptions) (index.CountResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, query, opts)
+	ret0, _ := ret[0].(index.CountResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockNamespaceIndexMockRecorder) Count(ctx, query, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockNamespaceIndex)(nil).Count), ctx, query, opts)
+}
+
 // DebugMemorySegments mocks base method.
 func (m *MockNamespaceIndex) DebugMemorySegments(opts DebugMemorySegmentsOptions) error {
 	m.ctrl.T.Helper()
@@ -2680,6 +2798,21 @@ func (mr *MockNamespaceIndexMockRecorder) WarmFlush(flush, shards interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmFlush", reflect.TypeOf((*MockNamespaceIndex)(nil).WarmFlush), flush, shards)
 }
 
+// WarmupPostingsListCache mocks base method.
+func (m *MockNamespaceIndex) WarmupPostingsListCache(queries []index.PostingsListCacheQuery, deadline time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmupPostingsListCache", queries, deadline)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WarmupPostingsListCache indicates an expected call of WarmupPostingsListCache.
+func (mr *MockNamespaceIndexMockRecorder) WarmupPostingsListCache(queries, deadline interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmupPostingsListCache", reflect.TypeOf((*MockNamespaceIndex)(nil).WarmupPostingsListCache), queries, deadline)
+}
+
 // WideQuery mocks base method.
 func (m *MockNamespaceIndex) WideQuery(ctx context.Context, query index.Query, collector chan *ident.IDBatch, opts index.WideQueryOptions) error {
 	m.ctrl.T.Helper()
@@ -3262,6 +3395,279 @@ func (mr *MockdatabaseColdFlushManagerMockRecorder) WarmFlushCleanup(t interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmFlushCleanup", reflect.TypeOf((*MockdatabaseColdFlushManager)(nil).WarmFlushCleanup), t)
 }
 
+// MockdatabaseRollupManager is a mock of databaseRollupManager interface.
+type MockdatabaseRollupManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockdatabaseRollupManagerMockRecorder
+}
+
+// MockdatabaseRollupManagerMockRecorder is the mock recorder for MockdatabaseRollupManager.
+type MockdatabaseRollupManagerMockRecorder struct {
+	mock *MockdatabaseRollupManager
+}
+
+// NewMockdatabaseRollupManager creates a new mock instance.
+func NewMockdatabaseRollupManager(ctrl *gomock.Controller) *MockdatabaseRollupManager {
+	mock := &MockdatabaseRollupManager{ctrl: ctrl}
+	mock.recorder = &MockdatabaseRollupManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockdatabaseRollupManager) EXPECT() *MockdatabaseRollupManagerMockRecorder {
+	return m.recorder
+}
+
+// Disable mocks base method.
+func (m *MockdatabaseRollupManager) Disable() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockdatabaseRollupManagerMockRecorder) Disable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockdatabaseRollupManager)(nil).Disable))
+}
+
+// Enable mocks base method.
+func (m *MockdatabaseRollupManager) Enable() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockdatabaseRollupManagerMockRecorder) Enable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockdatabaseRollupManager)(nil).Enable))
+}
+
+// Report mocks base method.
+func (m *MockdatabaseRollupManager) Report() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Report")
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockdatabaseRollupManagerMockRecorder) Report() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseRollupManager)(nil).Report))
+}
+
+// Run mocks base method.
+func (m *MockdatabaseRollupManager) Run(t time0.UnixNano) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", t)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockdatabaseRollupManagerMockRecorder) Run(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockdatabaseRollupManager)(nil).Run), t)
+}
+
+// Status mocks base method.
+func (m *MockdatabaseRollupManager) Status() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockdatabaseRollupManagerMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockdatabaseRollupManager)(nil).Status))
+}
+
+// MockdatabaseResizeManager is a mock of databaseResizeManager interface.
+type MockdatabaseResizeManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockdatabaseResizeManagerMockRecorder
+}
+
+// MockdatabaseResizeManagerMockRecorder is the mock recorder for MockdatabaseResizeManager.
+type MockdatabaseResizeManagerMockRecorder struct {
+	mock *MockdatabaseResizeManager
+}
+
+// NewMockdatabaseResizeManager creates a new mock instance.
+func NewMockdatabaseResizeManager(ctrl *gomock.Controller) *MockdatabaseResizeManager {
+	mock := &MockdatabaseResizeManager{ctrl: ctrl}
+	mock.recorder = &MockdatabaseResizeManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockdatabaseResizeManager) EXPECT() *MockdatabaseResizeManagerMockRecorder {
+	return m.recorder
+}
+
+// Disable mocks base method.
+func (m *MockdatabaseResizeManager) Disable() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockdatabaseResizeManagerMockRecorder) Disable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockdatabaseResizeManager)(nil).Disable))
+}
+
+// Enable mocks base method.
+func (m *MockdatabaseResizeManager) Enable() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockdatabaseResizeManagerMockRecorder) Enable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockdatabaseResizeManager)(nil).Enable))
+}
+
+// Report mocks base method.
+func (m *MockdatabaseResizeManager) Report() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Report")
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockdatabaseResizeManagerMockRecorder) Report() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseResizeManager)(nil).Report))
+}
+
+// Run mocks base method.
+func (m *MockdatabaseResizeManager) Run(t time0.UnixNano) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", t)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockdatabaseResizeManagerMockRecorder) Run(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockdatabaseResizeManager)(nil).Run), t)
+}
+
+// Status mocks base method.
+func (m *MockdatabaseResizeManager) Status() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockdatabaseResizeManagerMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockdatabaseResizeManager)(nil).Status))
+}
+
+// MockdatabaseBackupManager is a mock of databaseBackupManager interface.
+type MockdatabaseBackupManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockdatabaseBackupManagerMockRecorder
+}
+
+// MockdatabaseBackupManagerMockRecorder is the mock recorder for MockdatabaseBackupManager.
+type MockdatabaseBackupManagerMockRecorder struct {
+	mock *MockdatabaseBackupManager
+}
+
+// NewMockdatabaseBackupManager creates a new mock instance.
+func NewMockdatabaseBackupManager(ctrl *gomock.Controller) *MockdatabaseBackupManager {
+	mock := &MockdatabaseBackupManager{ctrl: ctrl}
+	mock.recorder = &MockdatabaseBackupManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockdatabaseBackupManager) EXPECT() *MockdatabaseBackupManagerMockRecorder {
+	return m.recorder
+}
+
+// Disable mocks base method.
+func (m *MockdatabaseBackupManager) Disable() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockdatabaseBackupManagerMockRecorder) Disable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockdatabaseBackupManager)(nil).Disable))
+}
+
+// Enable mocks base method.
+func (m *MockdatabaseBackupManager) Enable() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockdatabaseBackupManagerMockRecorder) Enable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockdatabaseBackupManager)(nil).Enable))
+}
+
+// Report mocks base method.
+func (m *MockdatabaseBackupManager) Report() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Report")
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockdatabaseBackupManagerMockRecorder) Report() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockdatabaseBackupManager)(nil).Report))
+}
+
+// Run mocks base method.
+func (m *MockdatabaseBackupManager) Run(t time0.UnixNano) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", t)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockdatabaseBackupManagerMockRecorder) Run(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockdatabaseBackupManager)(nil).Run), t)
+}
+
+// Status mocks base method.
+func (m *MockdatabaseBackupManager) Status() fileOpStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(fileOpStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockdatabaseBackupManagerMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockdatabaseBackupManager)(nil).Status))
+}
+
 // MockdatabaseShardRepairer is a mock of databaseShardRepairer interface.
 type MockdatabaseShardRepairer struct {
 	ctrl     *gomock.Controller
@@ -3939,6 +4345,34 @@ func (mr *MockOptionsMockRecorder) BackgroundProcessFns() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackgroundProcessFns", reflect.TypeOf((*MockOptions)(nil).BackgroundProcessFns))
 }
 
+// BackupCoordinator mocks base method.
+func (m *MockOptions) BackupCoordinator() backup.Coordinator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackupCoordinator")
+	ret0, _ := ret[0].(backup.Coordinator)
+	return ret0
+}
+
+// BackupCoordinator indicates an expected call of BackupCoordinator.
+func (mr *MockOptionsMockRecorder) BackupCoordinator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupCoordinator", reflect.TypeOf((*MockOptions)(nil).BackupCoordinator))
+}
+
+// BackupDirectory mocks base method.
+func (m *MockOptions) BackupDirectory() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackupDirectory")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BackupDirectory indicates an expected call of BackupDirectory.
+func (mr *MockOptionsMockRecorder) BackupDirectory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupDirectory", reflect.TypeOf((*MockOptions)(nil).BackupDirectory))
+}
+
 // BlockLeaseManager mocks base method.
 func (m *MockOptions) BlockLeaseManager() block.LeaseManager {
 	m.ctrl.T.Helper()
@@ -4191,6 +4625,20 @@ func (mr *MockOptionsMockRecorder) FetchBlocksMetadataResultsPool() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchBlocksMetadataResultsPool", reflect.TypeOf((*MockOptions)(nil).FetchBlocksMetadataResultsPool))
 }
 
+// FileSetOffloader mocks base method.
+func (m *MockOptions) FileSetOffloader() fs.FileSetOffloader {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FileSetOffloader")
+	ret0, _ := ret[0].(fs.FileSetOffloader)
+	return ret0
+}
+
+// FileSetOffloader indicates an expected call of FileSetOffloader.
+func (mr *MockOptionsMockRecorder) FileSetOffloader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileSetOffloader", reflect.TypeOf((*MockOptions)(nil).FileSetOffloader))
+}
+
 // ForceColdWritesEnabled mocks base method.
 func (m *MockOptions) ForceColdWritesEnabled() bool {
 	m.ctrl.T.Helper()
@@ -4373,6 +4821,20 @@ func (mr *MockOptionsMockRecorder) NamespaceInitializer() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamespaceInitializer", reflect.TypeOf((*MockOptions)(nil).NamespaceInitializer))
 }
 
+// NamespaceResizeStatusReporter mocks base method.
+func (m *MockOptions) NamespaceResizeStatusReporter() namespace.ResizeStatusReporter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NamespaceResizeStatusReporter")
+	ret0, _ := ret[0].(namespace.ResizeStatusReporter)
+	return ret0
+}
+
+// NamespaceResizeStatusReporter indicates an expected call of NamespaceResizeStatusReporter.
+func (mr *MockOptionsMockRecorder) NamespaceResizeStatusReporter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamespaceResizeStatusReporter", reflect.TypeOf((*MockOptions)(nil).NamespaceResizeStatusReporter))
+}
+
 // NamespaceRuntimeOptionsManagerRegistry mocks base method.
 func (m *MockOptions) NamespaceRuntimeOptionsManagerRegistry() namespace.RuntimeOptionsManagerRegistry {
 	m.ctrl.T.Helper()
@@ -4583,6 +5045,34 @@ func (mr *MockOptionsMockRecorder) SetBackgroundProcessFns(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackgroundProcessFns", reflect.TypeOf((*MockOptions)(nil).SetBackgroundProcessFns), arg0)
 }
 
+// SetBackupCoordinator mocks base method.
+func (m *MockOptions) SetBackupCoordinator(value backup.Coordinator) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackupCoordinator", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackupCoordinator indicates an expected call of SetBackupCoordinator.
+func (mr *MockOptionsMockRecorder) SetBackupCoordinator(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackupCoordinator", reflect.TypeOf((*MockOptions)(nil).SetBackupCoordinator), value)
+}
+
+// SetBackupDirectory mocks base method.
+func (m *MockOptions) SetBackupDirectory(value string) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackupDirectory", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetBackupDirectory indicates an expected call of SetBackupDirectory.
+func (mr *MockOptionsMockRecorder) SetBackupDirectory(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackupDirectory", reflect.TypeOf((*MockOptions)(nil).SetBackupDirectory), value)
+}
+
 // SetBlockLeaseManager mocks base method.
 func (m *MockOptions) SetBlockLeaseManager(leaseMgr block.LeaseManager) Options {
 	m.ctrl.T.Helper()
@@ -4849,6 +5339,20 @@ func (mr *MockOptionsMockRecorder) SetFetchBlocksMetadataResultsPool(value inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFetchBlocksMetadataResultsPool", reflect.TypeOf((*MockOptions)(nil).SetFetchBlocksMetadataResultsPool), value)
 }
 
+// SetFileSetOffloader mocks base method.
+func (m *MockOptions) SetFileSetOffloader(value fs.FileSetOffloader) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFileSetOffloader", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetFileSetOffloader indicates an expected call of SetFileSetOffloader.
+func (mr *MockOptionsMockRecorder) SetFileSetOffloader(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFileSetOffloader", reflect.TypeOf((*MockOptions)(nil).SetFileSetOffloader), value)
+}
+
 // SetForceColdWritesEnabled mocks base method.
 func (m *MockOptions) SetForceColdWritesEnabled(value bool) Options {
 	m.ctrl.T.Helper()
@@ -5031,6 +5535,20 @@ func (mr *MockOptionsMockRecorder) SetNamespaceInitializer(value interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNamespaceInitializer", reflect.TypeOf((*MockOptions)(nil).SetNamespaceInitializer), value)
 }
 
+// SetNamespaceResizeStatusReporter mocks base method.
+func (m *MockOptions) SetNamespaceResizeStatusReporter(value namespace.ResizeStatusReporter) Options {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNamespaceResizeStatusReporter", value)
+	ret0, _ := ret[0].(Options)
+	return ret0
+}
+
+// SetNamespaceResizeStatusReporter indicates an expected call of SetNamespaceResizeStatusReporter.
+func (mr *MockOptionsMockRecorder) SetNamespaceResizeStatusReporter(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNamespaceResizeStatusReporter", reflect.TypeOf((*MockOptions)(nil).SetNamespaceResizeStatusReporter), value)
+}
+
 // SetNamespaceRuntimeOptionsManagerRegistry mocks base method.
 func (m *MockOptions) SetNamespaceRuntimeOptionsManagerRegistry(value namespace.RuntimeOptionsManagerRegistry) Options {
 	m.ctrl.T.Helper()
@@ -0,0 +1,379 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tombstone
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/tombstone"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+const tmpFileSuffix = ".tmp"
+
+var errBlockSizeNotPositive = errors.New("tombstone block size must be positive")
+
+type store struct {
+	sync.RWMutex
+
+	// persistLock serializes persisting so that the store itself is only
+	// locked while it is mutated and marshalled.
+	persistLock sync.Mutex
+
+	opts      Options
+	numSeries int64
+	series    map[string][]xtime.Range
+	pending   map[uint32]map[xtime.UnixNano]uint64
+	version   uint64
+}
+
+// NewStore returns a new tombstone store, loading any tombstones previously
+// persisted to the file path of the options.
+func NewStore(opts Options) (Store, error) {
+	if opts.BlockSize <= 0 {
+		return nil, errBlockSizeNotPositive
+	}
+
+	s := &store{
+		opts:    opts,
+		series:  make(map[string][]xtime.Range),
+		pending: make(map[uint32]map[xtime.UnixNano]uint64),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *store) Add(tombstones []Tombstone) error {
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+
+	s.Lock()
+	s.version++
+	for _, t := range tombstones {
+		if t.Range.IsEmpty() {
+			continue
+		}
+
+		s.series[string(t.ID)] = addRange(s.series[string(t.ID)], t.Range)
+
+		blocks, ok := s.pending[t.Shard]
+		if !ok {
+			blocks = make(map[xtime.UnixNano]uint64)
+			s.pending[t.Shard] = blocks
+		}
+		for bs := t.Range.Start.Truncate(s.opts.BlockSize); bs.Before(t.Range.End); bs = bs.Add(s.opts.BlockSize) {
+			blocks[bs] = s.version
+		}
+	}
+	atomic.StoreInt64(&s.numSeries, int64(len(s.series)))
+	data, err := s.marshalWithLock()
+	s.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return s.persist(data)
+}
+
+func (s *store) Ranges(id []byte) []xtime.Range {
+	if atomic.LoadInt64(&s.numSeries) == 0 {
+		return nil
+	}
+
+	s.RLock()
+	ranges := s.series[string(id)]
+	s.RUnlock()
+	return ranges
+}
+
+func (s *store) Covers(id []byte, r xtime.Range) bool {
+	// NB: ranges are coalesced so a single range must contain all of r.
+	for _, deleted := range s.Ranges(id) {
+		if deleted.Contains(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *store) IDs(r xtime.Range) [][]byte {
+	if atomic.LoadInt64(&s.numSeries) == 0 {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	var ids [][]byte
+	for id, ranges := range s.series {
+		for _, deleted := range ranges {
+			if deleted.Contains(r) {
+				ids = append(ids, []byte(id))
+				break
+			}
+		}
+	}
+	return ids
+}
+
+func (s *store) PendingBlocks(shard uint32) []PendingBlock {
+	s.RLock()
+	defer s.RUnlock()
+
+	blocks := s.pending[shard]
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	result := make([]PendingBlock, 0, len(blocks))
+	for bs, version := range blocks {
+		result = append(result, PendingBlock{BlockStart: bs, Version: version})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].BlockStart.Before(result[j].BlockStart)
+	})
+	return result
+}
+
+func (s *store) MarkCompacted(shard uint32, block PendingBlock) error {
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+
+	s.Lock()
+	blocks := s.pending[shard]
+	if version, ok := blocks[block.BlockStart]; !ok || version != block.Version {
+		s.Unlock()
+		return nil
+	}
+	delete(blocks, block.BlockStart)
+	if len(blocks) == 0 {
+		delete(s.pending, shard)
+	}
+	data, err := s.marshalWithLock()
+	s.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return s.persist(data)
+}
+
+func (s *store) Prune(before xtime.UnixNano) error {
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+
+	s.Lock()
+	pruned := false
+	for id, ranges := range s.series {
+		i := 0
+		for i < len(ranges) && !ranges[i].End.After(before) {
+			i++
+		}
+		switch {
+		case i == len(ranges):
+			delete(s.series, id)
+		case i > 0:
+			s.series[id] = ranges[i:]
+		default:
+			continue
+		}
+		pruned = true
+	}
+	for shard, blocks := range s.pending {
+		for bs := range blocks {
+			if !bs.Add(s.opts.BlockSize).After(before) {
+				delete(blocks, bs)
+				pruned = true
+			}
+		}
+		if len(blocks) == 0 {
+			delete(s.pending, shard)
+		}
+	}
+	if !pruned {
+		s.Unlock()
+		return nil
+	}
+	atomic.StoreInt64(&s.numSeries, int64(len(s.series)))
+	data, err := s.marshalWithLock()
+	s.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return s.persist(data)
+}
+
+func (s *store) Len() int {
+	return int(atomic.LoadInt64(&s.numSeries))
+}
+
+// addRange adds a range to sorted and non-overlapping ranges, coalescing it
+// with the ranges it overlaps or abuts. The ranges are copied rather than
+// modified in place since readers may hold a reference to them.
+func addRange(ranges []xtime.Range, r xtime.Range) []xtime.Range {
+	result := make([]xtime.Range, 0, len(ranges)+1)
+	inserted := false
+	for _, existing := range ranges {
+		switch {
+		case existing.End.Before(r.Start):
+			result = append(result, existing)
+		case r.End.Before(existing.Start):
+			if !inserted {
+				result = append(result, r)
+				inserted = true
+			}
+			result = append(result, existing)
+		default:
+			r = r.Merge(existing)
+		}
+	}
+	if !inserted {
+		result = append(result, r)
+	}
+	return result
+}
+
+func (s *store) marshalWithLock() ([]byte, error) {
+	if s.opts.FilePath == "" {
+		return nil, nil
+	}
+
+	pb := &tombstone.Tombstones{
+		Series: make([]*tombstone.Series, 0, len(s.series)),
+	}
+	for id, ranges := range s.series {
+		series := &tombstone.Series{
+			Id:     []byte(id),
+			Ranges: make([]*tombstone.Range, 0, len(ranges)),
+		}
+		for _, r := range ranges {
+			series.Ranges = append(series.Ranges, &tombstone.Range{
+				StartNanos: int64(r.Start),
+				EndNanos:   int64(r.End),
+			})
+		}
+		pb.Series = append(pb.Series, series)
+	}
+	for shard, blocks := range s.pending {
+		for bs := range blocks {
+			pb.PendingBlocks = append(pb.PendingBlocks, &tombstone.PendingBlock{
+				Shard:           shard,
+				BlockStartNanos: int64(bs),
+			})
+		}
+	}
+	return pb.Marshal()
+}
+
+// persist atomically replaces the tombstones file by writing to a temporary
+// file and renaming it once synced.
+func (s *store) persist(data []byte) error {
+	if s.opts.FilePath == "" {
+		return nil
+	}
+
+	dir := filepath.Dir(s.opts.FilePath)
+	if err := os.MkdirAll(dir, s.opts.NewDirectoryMode); err != nil {
+		return err
+	}
+
+	tmpPath := s.opts.FilePath + tmpFileSuffix
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, s.opts.NewFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.opts.FilePath); err != nil {
+		return err
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
+
+func (s *store) load() error {
+	if s.opts.FilePath == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(s.opts.FilePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var pb tombstone.Tombstones
+	if err := pb.Unmarshal(data); err != nil {
+		return err
+	}
+	for _, series := range pb.Series {
+		ranges := make([]xtime.Range, 0, len(series.Ranges))
+		for _, r := range series.Ranges {
+			ranges = append(ranges, xtime.Range{
+				Start: xtime.UnixNano(r.StartNanos),
+				End:   xtime.UnixNano(r.EndNanos),
+			})
+		}
+		s.series[string(series.Id)] = ranges
+	}
+	for _, block := range pb.PendingBlocks {
+		blocks, ok := s.pending[block.Shard]
+		if !ok {
+			blocks = make(map[xtime.UnixNano]uint64)
+			s.pending[block.Shard] = blocks
+		}
+		blocks[xtime.UnixNano(block.BlockStartNanos)] = 0
+	}
+	s.numSeries = int64(len(s.series))
+	return nil
+}
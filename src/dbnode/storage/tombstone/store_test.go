@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tombstone
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBlockSize = 10 * time.Nanosecond
+
+func newTestStore(t *testing.T, filePath string) Store {
+	s, err := NewStore(Options{
+		FilePath:         filePath,
+		BlockSize:        testBlockSize,
+		NewFileMode:      0666,
+		NewDirectoryMode: 0755,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func testRange(start, end int64) xtime.Range {
+	return xtime.Range{Start: xtime.UnixNano(start), End: xtime.UnixNano(end)}
+}
+
+func TestStoreAddCoalescesRanges(t *testing.T) {
+	s := newTestStore(t, "")
+
+	require.NoError(t, s.Add([]Tombstone{
+		{ID: []byte("foo"), Range: testRange(30, 40)},
+		{ID: []byte("foo"), Range: testRange(0, 10)},
+		{ID: []byte("foo"), Range: testRange(50, 60)},
+		{ID: []byte("bar"), Range: testRange(0, 5)},
+	}))
+	require.NoError(t, s.Add([]Tombstone{
+		{ID: []byte("foo"), Range: testRange(5, 30)},
+	}))
+
+	assert.Equal(t, 2, s.Len())
+	assert.Equal(t, []xtime.Range{testRange(0, 40), testRange(50, 60)},
+		s.Ranges([]byte("foo")))
+	assert.Nil(t, s.Ranges([]byte("baz")))
+
+	assert.True(t, s.Covers([]byte("foo"), testRange(10, 40)))
+	assert.False(t, s.Covers([]byte("foo"), testRange(35, 55)))
+	assert.False(t, s.Covers([]byte("baz"), testRange(0, 1)))
+
+	assert.Equal(t, [][]byte{[]byte("foo")}, s.IDs(testRange(20, 30)))
+}
+
+func TestStorePendingBlocks(t *testing.T) {
+	s := newTestStore(t, "")
+
+	require.NoError(t, s.Add([]Tombstone{
+		{ID: []byte("foo"), Shard: 1, Range: testRange(5, 25)},
+		{ID: []byte("bar"), Shard: 2, Range: testRange(40, 41)},
+	}))
+
+	blocks := s.PendingBlocks(1)
+	require.Len(t, blocks, 3)
+	assert.Equal(t, xtime.UnixNano(0), blocks[0].BlockStart)
+	assert.Equal(t, xtime.UnixNano(10), blocks[1].BlockStart)
+	assert.Equal(t, xtime.UnixNano(20), blocks[2].BlockStart)
+	assert.Len(t, s.PendingBlocks(2), 1)
+	assert.Nil(t, s.PendingBlocks(3))
+
+	// Adding tombstones while a block is compacted must keep it pending.
+	stale := blocks[0]
+	require.NoError(t, s.Add([]Tombstone{
+		{ID: []byte("baz"), Shard: 1, Range: testRange(0, 1)},
+	}))
+	require.NoError(t, s.MarkCompacted(1, stale))
+	require.NoError(t, s.MarkCompacted(1, blocks[1]))
+
+	blocks = s.PendingBlocks(1)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, xtime.UnixNano(0), blocks[0].BlockStart)
+	assert.Equal(t, xtime.UnixNano(20), blocks[1].BlockStart)
+}
+
+func TestStorePrune(t *testing.T) {
+	s := newTestStore(t, "")
+
+	require.NoError(t, s.Add([]Tombstone{
+		{ID: []byte("foo"), Shard: 1, Range: testRange(0, 10)},
+		{ID: []byte("foo"), Shard: 1, Range: testRange(20, 30)},
+		{ID: []byte("bar"), Shard: 1, Range: testRange(0, 5)},
+	}))
+
+	require.NoError(t, s.Prune(10))
+	assert.Equal(t, 1, s.Len())
+	assert.Equal(t, []xtime.Range{testRange(20, 30)}, s.Ranges([]byte("foo")))
+	assert.Nil(t, s.Ranges([]byte("bar")))
+
+	blocks := s.PendingBlocks(1)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, xtime.UnixNano(20), blocks[0].BlockStart)
+}
+
+func TestStorePersistsAndLoads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tombstones")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "ns", "tombstones.db")
+	s := newTestStore(t, filePath)
+	require.NoError(t, s.Add([]Tombstone{
+		{ID: []byte("foo"), Shard: 3, Range: testRange(0, 15)},
+	}))
+
+	loaded := newTestStore(t, filePath)
+	assert.Equal(t, 1, loaded.Len())
+	assert.Equal(t, []xtime.Range{testRange(0, 15)}, loaded.Ranges([]byte("foo")))
+	assert.Len(t, loaded.PendingBlocks(3), 2)
+
+	for _, block := range loaded.PendingBlocks(3) {
+		require.NoError(t, loaded.MarkCompacted(3, block))
+	}
+	assert.Nil(t, newTestStore(t, filePath).PendingBlocks(3))
+}
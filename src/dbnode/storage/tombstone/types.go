@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package tombstone contains a persisted store of tombstones recording the
+// series data deleted from a namespace.
+package tombstone
+
+import (
+	"os"
+	"time"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// Tombstone records that the data of a series within a time range has been
+// deleted.
+type Tombstone struct {
+	ID    []byte
+	Shard uint32
+	Range xtime.Range
+}
+
+// PendingBlock is a block of a shard whose data fileset may still hold data
+// deleted by a tombstone.
+type PendingBlock struct {
+	BlockStart xtime.UnixNano
+	// Version changes each time the block receives new tombstones, so a block
+	// is only marked compacted if no tombstones were added while compacting it.
+	Version uint64
+}
+
+// Store holds the tombstones of a namespace, the deleted data is hidden from
+// reads until it is dropped from the filesets of each pending block by the
+// next cold flush, after which the tombstones are kept until the data they
+// cover falls out of retention so that index segments flushed before the
+// deletion continue to hide the series.
+type Store interface {
+	// Add records tombstones, persisting them before returning.
+	Add(tombstones []Tombstone) error
+
+	// Ranges returns the sorted and non-overlapping deleted time ranges of a
+	// series, or nil if none of its data has been deleted.
+	Ranges(id []byte) []xtime.Range
+
+	// Covers returns whether the deleted time ranges of a series cover all
+	// of a time range.
+	Covers(id []byte, r xtime.Range) bool
+
+	// IDs returns the IDs of the series with deleted time ranges covering
+	// all of a time range.
+	IDs(r xtime.Range) [][]byte
+
+	// PendingBlocks returns the blocks of a shard with data filesets that
+	// may still hold deleted data.
+	PendingBlocks(shard uint32) []PendingBlock
+
+	// MarkCompacted records that the data fileset of a pending block has
+	// been rewritten without the deleted data.
+	MarkCompacted(shard uint32, block PendingBlock) error
+
+	// Prune removes tombstones and pending blocks for data before a time,
+	// which is no longer retained.
+	Prune(before xtime.UnixNano) error
+
+	// Len returns the number of series with deleted time ranges.
+	Len() int
+}
+
+// Options are the options for a tombstone store.
+type Options struct {
+	// FilePath is the file tombstones are persisted to, if empty tombstones
+	// are only held in memory.
+	FilePath string
+
+	// BlockSize is the block size of the namespace data filesets.
+	BlockSize time.Duration
+
+	// NewFileMode is the file mode of the persisted tombstones file.
+	NewFileMode os.FileMode
+
+	// NewDirectoryMode is the mode of the directories created to hold the
+	// persisted tombstones file.
+	NewDirectoryMode os.FileMode
+}
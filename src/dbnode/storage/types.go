@@ -25,6 +25,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/backup"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
 	"github.com/m3db/m3/src/dbnode/namespace"
@@ -43,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/limits/permits"
 	"github.com/m3db/m3/src/dbnode/storage/repair"
 	"github.com/m3db/m3/src/dbnode/storage/series"
+	"github.com/m3db/m3/src/dbnode/storage/tombstone"
 	"github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/dbnode/ts/writes"
 	"github.com/m3db/m3/src/dbnode/x/xio"
@@ -168,6 +170,15 @@ type Database interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// DeleteTagged deletes the data of the series matching the given query
+	// within [start, end), returning the number of series deleted.
+	DeleteTagged(
+		ctx context.Context,
+		namespace ident.ID,
+		query index.Query,
+		start, end xtime.UnixNano,
+	) (int, error)
+
 	// ReadEncoded retrieves encoded segments for an ID.
 	ReadEncoded(
 		ctx context.Context,
@@ -261,6 +272,9 @@ type database interface {
 
 	// UpdateOwnedNamespaces updates the namespaces this database owns.
 	UpdateOwnedNamespaces(namespaces namespace.Map) error
+
+	// NamespaceResizeState returns the latest resize state of a namespace.
+	NamespaceResizeState(id ident.ID) namespace.ResizeState
 }
 
 // Namespace is a time series database namespace.
@@ -390,6 +404,16 @@ type databaseNamespace interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// DeleteTagged deletes the data of the series matching the given query
+	// within [start, end), returning the number of series deleted. The data
+	// is hidden from reads immediately and dropped from disk by the next
+	// cold flush.
+	DeleteTagged(
+		ctx context.Context,
+		query index.Query,
+		start, end xtime.UnixNano,
+	) (int, error)
+
 	// ReadEncoded reads data for given id within [start, end).
 	ReadEncoded(
 		ctx context.Context,
@@ -504,6 +528,10 @@ type Shard interface {
 	OpenStreamingReader(blockStart xtime.UnixNano) (fs.DataFileSetReader, error)
 }
 
+// forEachUnflushedFn is called with the metadata of a series and its data,
+// grouped by block start.
+type forEachUnflushedFn func(metadata doc.Metadata, blocks [][]xio.BlockReader) error
+
 type databaseShard interface {
 	Shard
 
@@ -548,6 +576,15 @@ type databaseShard interface {
 		nsCtx namespace.Context,
 	) (series.BlockReaderIter, error)
 
+	// ForEachUnflushed calls the function with the data of each series from
+	// the start onwards along with the data of blocks with cold writes that
+	// are yet to be cold flushed.
+	ForEachUnflushed(
+		start xtime.UnixNano,
+		nsCtx namespace.Context,
+		fn forEachUnflushedFn,
+	) error
+
 	// FetchWideEntry retrieves wide entry for an ID for the
 	// block at time start.
 	FetchWideEntry(
@@ -619,6 +656,10 @@ type databaseShard interface {
 		onFlush persist.OnFlushSeries,
 	) (ShardColdFlush, error)
 
+	// LateFlush flushes the unflushed LateWrites in this shard to late data
+	// sidecar volumes.
+	LateFlush(nsCtx namespace.Context) error
+
 	// Snapshot snapshot's the unflushed WarmWrites in this shard.
 	Snapshot(
 		blockStart xtime.UnixNano,
@@ -731,6 +772,29 @@ type NamespaceIndex interface {
 		opts index.AggregationOptions,
 	) (index.AggregateQueryResult, error)
 
+	// Cardinality returns the top cardinality statistics of the series of
+	// the index blocks within a time range.
+	Cardinality(
+		ctx context.Context,
+		opts index.CardinalityQueryOptions,
+	) (index.CardinalityStats, error)
+
+	// Count returns the number of series of the index blocks within a time
+	// range matching a query.
+	Count(
+		ctx context.Context,
+		query index.Query,
+		opts index.CountQueryOptions,
+	) (index.CountResult, error)
+
+	// WarmupPostingsListCache executes the queries against the cached
+	// segments of the index blocks, most recent first, and returns the number
+	// of postings lists warmed.
+	WarmupPostingsListCache(
+		queries []index.PostingsListCacheQuery,
+		deadline time.Time,
+	) (int, error)
+
 	// Bootstrap bootstraps the index with the provided segments.
 	Bootstrap(
 		bootstrapResults result.IndexResults,
@@ -948,6 +1012,69 @@ type databaseColdFlushManager interface {
 	Run(t xtime.UnixNano) bool
 }
 
+// databaseRollupManager rolls up sealed blocks of namespaces with a rollup
+// policy into their coarser target namespace.
+type databaseRollupManager interface {
+	// Disable disables the rollup manager and prevents it from
+	// performing file operations, returns the current file operation status.
+	Disable() fileOpStatus
+
+	// Enable enables the rollup manager to perform file operations.
+	Enable() fileOpStatus
+
+	// Status returns the file operation status.
+	Status() fileOpStatus
+
+	// Run attempts to roll up all eligible blocks, returning true if
+	// the rollup was performed, and false otherwise.
+	Run(t xtime.UnixNano) bool
+
+	// Report reports runtime information.
+	Report()
+}
+
+// databaseResizeManager converts the sealed blocks of namespaces with an
+// in-progress resize to their new block size.
+type databaseResizeManager interface {
+	// Disable disables the resize manager and prevents it from
+	// performing file operations, returns the current file operation status.
+	Disable() fileOpStatus
+
+	// Enable enables the resize manager to perform file operations.
+	Enable() fileOpStatus
+
+	// Status returns the file operation status.
+	Status() fileOpStatus
+
+	// Run attempts to convert all sealed blocks, returning true if
+	// the conversion was performed, and false otherwise.
+	Run(t xtime.UnixNano) bool
+
+	// Report reports runtime information.
+	Report()
+}
+
+// databaseBackupManager backs up the filesets of the node when a cluster-wide
+// backup has been requested.
+type databaseBackupManager interface {
+	// Disable disables the backup manager and prevents it from
+	// performing file operations, returns the current file operation status.
+	Disable() fileOpStatus
+
+	// Enable enables the backup manager to perform file operations.
+	Enable() fileOpStatus
+
+	// Status returns the file operation status.
+	Status() fileOpStatus
+
+	// Run attempts to perform a pending backup, returning true if
+	// a backup was attempted, and false otherwise.
+	Run(t xtime.UnixNano) bool
+
+	// Report reports runtime information.
+	Report()
+}
+
 // databaseShardRepairer repairs in-memory data for a shard.
 type databaseShardRepairer interface {
 	// Options returns the repair options.
@@ -1181,6 +1308,35 @@ type Options interface {
 	// IndexClaimsManager returns the index claims manager.
 	IndexClaimsManager() fs.IndexClaimsManager
 
+	// SetFileSetOffloader sets the offloader of data fileset volumes, if
+	// nil data filesets are only kept on local disk.
+	SetFileSetOffloader(value fs.FileSetOffloader) Options
+
+	// FileSetOffloader returns the offloader of data fileset volumes.
+	FileSetOffloader() fs.FileSetOffloader
+
+	// SetNamespaceResizeStatusReporter sets the reporter of whether the
+	// filesets of namespaces being resized have been converted, if nil the
+	// status is not reported.
+	SetNamespaceResizeStatusReporter(value namespace.ResizeStatusReporter) Options
+
+	// NamespaceResizeStatusReporter returns the reporter of whether the
+	// filesets of namespaces being resized have been converted.
+	NamespaceResizeStatusReporter() namespace.ResizeStatusReporter
+
+	// SetBackupCoordinator sets the coordinator of cluster-wide backups, if
+	// nil the node does not take part in backups.
+	SetBackupCoordinator(value backup.Coordinator) Options
+
+	// BackupCoordinator returns the coordinator of cluster-wide backups.
+	BackupCoordinator() backup.Coordinator
+
+	// SetBackupDirectory sets the directory backups are written to.
+	SetBackupDirectory(value string) Options
+
+	// BackupDirectory returns the directory backups are written to.
+	BackupDirectory() string
+
 	// SetDatabaseBlockRetrieverManager sets the block retriever manager to
 	// use when bootstrapping retrievable blocks instead of blocks
 	// containing data.
@@ -1466,6 +1622,8 @@ type newFSMergeWithMemFn func(
 	retriever series.QueryableBlockRetriever,
 	dirtySeries *dirtySeriesMap,
 	dirtySeriesToWrite map[xtime.UnixNano]*idList,
+	tombstones tombstone.Store,
+	blockSize time.Duration,
 ) fs.MergeWith
 
 // NewBackgroundProcessFn is a function that creates and returns a new BackgroundProcess.
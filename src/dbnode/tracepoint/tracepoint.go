@@ -100,6 +100,12 @@ const (
 	// NSIdxAggregateQuery is the operation name for the nsIndex AggregateQuery path.
 	NSIdxAggregateQuery = "storage.nsIndex.AggregateQuery"
 
+	// NSIdxCardinality is the operation name for the nsIndex Cardinality path.
+	NSIdxCardinality = "storage.nsIndex.Cardinality"
+
+	// NSIdxCount is the operation name for the nsIndex Count path.
+	NSIdxCount = "storage.nsIndex.Count"
+
 	// NSIdxQueryHelper is the operation name for the nsIndex query path.
 	NSIdxQueryHelper = "storage.nsIndex.query"
 
@@ -21,29 +21,15 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-/*
-	Package querypb is a generated protocol buffer package.
-
-	It is generated from these files:
-		github.com/m3db/m3/src/m3ninx/generated/proto/querypb/query.proto
-
-	It has these top-level messages:
-		FieldQuery
-		TermQuery
-		RegexpQuery
-		NegationQuery
-		ConjunctionQuery
-		DisjunctionQuery
-		AllQuery
-		Query
-*/
 package querypb
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
-import io "io"
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -54,16 +40,44 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type FieldQuery struct {
 	Field []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
 }
 
-func (m *FieldQuery) Reset()                    { *m = FieldQuery{} }
-func (m *FieldQuery) String() string            { return proto.CompactTextString(m) }
-func (*FieldQuery) ProtoMessage()               {}
-func (*FieldQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{0} }
+func (m *FieldQuery) Reset()         { *m = FieldQuery{} }
+func (m *FieldQuery) String() string { return proto.CompactTextString(m) }
+func (*FieldQuery) ProtoMessage()    {}
+func (*FieldQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{0}
+}
+func (m *FieldQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FieldQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FieldQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FieldQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldQuery.Merge(m, src)
+}
+func (m *FieldQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *FieldQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldQuery proto.InternalMessageInfo
 
 func (m *FieldQuery) GetField() []byte {
 	if m != nil {
@@ -77,10 +91,38 @@ type TermQuery struct {
 	Term  []byte `protobuf:"bytes,2,opt,name=term,proto3" json:"term,omitempty"`
 }
 
-func (m *TermQuery) Reset()                    { *m = TermQuery{} }
-func (m *TermQuery) String() string            { return proto.CompactTextString(m) }
-func (*TermQuery) ProtoMessage()               {}
-func (*TermQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{1} }
+func (m *TermQuery) Reset()         { *m = TermQuery{} }
+func (m *TermQuery) String() string { return proto.CompactTextString(m) }
+func (*TermQuery) ProtoMessage()    {}
+func (*TermQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{1}
+}
+func (m *TermQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TermQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TermQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TermQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TermQuery.Merge(m, src)
+}
+func (m *TermQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *TermQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_TermQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TermQuery proto.InternalMessageInfo
 
 func (m *TermQuery) GetField() []byte {
 	if m != nil {
@@ -101,10 +143,38 @@ type RegexpQuery struct {
 	Regexp []byte `protobuf:"bytes,2,opt,name=regexp,proto3" json:"regexp,omitempty"`
 }
 
-func (m *RegexpQuery) Reset()                    { *m = RegexpQuery{} }
-func (m *RegexpQuery) String() string            { return proto.CompactTextString(m) }
-func (*RegexpQuery) ProtoMessage()               {}
-func (*RegexpQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{2} }
+func (m *RegexpQuery) Reset()         { *m = RegexpQuery{} }
+func (m *RegexpQuery) String() string { return proto.CompactTextString(m) }
+func (*RegexpQuery) ProtoMessage()    {}
+func (*RegexpQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{2}
+}
+func (m *RegexpQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RegexpQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RegexpQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RegexpQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegexpQuery.Merge(m, src)
+}
+func (m *RegexpQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *RegexpQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegexpQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegexpQuery proto.InternalMessageInfo
 
 func (m *RegexpQuery) GetField() []byte {
 	if m != nil {
@@ -120,14 +190,230 @@ func (m *RegexpQuery) GetRegexp() []byte {
 	return nil
 }
 
+type PrefixQuery struct {
+	Field  []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Prefix []byte `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (m *PrefixQuery) Reset()         { *m = PrefixQuery{} }
+func (m *PrefixQuery) String() string { return proto.CompactTextString(m) }
+func (*PrefixQuery) ProtoMessage()    {}
+func (*PrefixQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{3}
+}
+func (m *PrefixQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrefixQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrefixQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrefixQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrefixQuery.Merge(m, src)
+}
+func (m *PrefixQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrefixQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrefixQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrefixQuery proto.InternalMessageInfo
+
+func (m *PrefixQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *PrefixQuery) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+type RangeQuery struct {
+	Field        []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Min          []byte `protobuf:"bytes,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max          []byte `protobuf:"bytes,3,opt,name=max,proto3" json:"max,omitempty"`
+	MinInclusive bool   `protobuf:"varint,4,opt,name=min_inclusive,json=minInclusive,proto3" json:"min_inclusive,omitempty"`
+	MaxInclusive bool   `protobuf:"varint,5,opt,name=max_inclusive,json=maxInclusive,proto3" json:"max_inclusive,omitempty"`
+	Numeric      bool   `protobuf:"varint,6,opt,name=numeric,proto3" json:"numeric,omitempty"`
+}
+
+func (m *RangeQuery) Reset()         { *m = RangeQuery{} }
+func (m *RangeQuery) String() string { return proto.CompactTextString(m) }
+func (*RangeQuery) ProtoMessage()    {}
+func (*RangeQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{4}
+}
+func (m *RangeQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RangeQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RangeQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RangeQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RangeQuery.Merge(m, src)
+}
+func (m *RangeQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *RangeQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RangeQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RangeQuery proto.InternalMessageInfo
+
+func (m *RangeQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *RangeQuery) GetMin() []byte {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *RangeQuery) GetMax() []byte {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
+func (m *RangeQuery) GetMinInclusive() bool {
+	if m != nil {
+		return m.MinInclusive
+	}
+	return false
+}
+
+func (m *RangeQuery) GetMaxInclusive() bool {
+	if m != nil {
+		return m.MaxInclusive
+	}
+	return false
+}
+
+func (m *RangeQuery) GetNumeric() bool {
+	if m != nil {
+		return m.Numeric
+	}
+	return false
+}
+
+type CaseInsensitiveTermQuery struct {
+	Field []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Term  []byte `protobuf:"bytes,2,opt,name=term,proto3" json:"term,omitempty"`
+}
+
+func (m *CaseInsensitiveTermQuery) Reset()         { *m = CaseInsensitiveTermQuery{} }
+func (m *CaseInsensitiveTermQuery) String() string { return proto.CompactTextString(m) }
+func (*CaseInsensitiveTermQuery) ProtoMessage()    {}
+func (*CaseInsensitiveTermQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{5}
+}
+func (m *CaseInsensitiveTermQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CaseInsensitiveTermQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CaseInsensitiveTermQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CaseInsensitiveTermQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CaseInsensitiveTermQuery.Merge(m, src)
+}
+func (m *CaseInsensitiveTermQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *CaseInsensitiveTermQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_CaseInsensitiveTermQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CaseInsensitiveTermQuery proto.InternalMessageInfo
+
+func (m *CaseInsensitiveTermQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *CaseInsensitiveTermQuery) GetTerm() []byte {
+	if m != nil {
+		return m.Term
+	}
+	return nil
+}
+
 type NegationQuery struct {
-	Query *Query `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
+	Query *Query `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (m *NegationQuery) Reset()         { *m = NegationQuery{} }
+func (m *NegationQuery) String() string { return proto.CompactTextString(m) }
+func (*NegationQuery) ProtoMessage()    {}
+func (*NegationQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{6}
+}
+func (m *NegationQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NegationQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NegationQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NegationQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegationQuery.Merge(m, src)
+}
+func (m *NegationQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *NegationQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegationQuery.DiscardUnknown(m)
 }
 
-func (m *NegationQuery) Reset()                    { *m = NegationQuery{} }
-func (m *NegationQuery) String() string            { return proto.CompactTextString(m) }
-func (*NegationQuery) ProtoMessage()               {}
-func (*NegationQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{3} }
+var xxx_messageInfo_NegationQuery proto.InternalMessageInfo
 
 func (m *NegationQuery) GetQuery() *Query {
 	if m != nil {
@@ -137,13 +423,41 @@ func (m *NegationQuery) GetQuery() *Query {
 }
 
 type ConjunctionQuery struct {
-	Queries []*Query `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+	Queries []*Query `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+}
+
+func (m *ConjunctionQuery) Reset()         { *m = ConjunctionQuery{} }
+func (m *ConjunctionQuery) String() string { return proto.CompactTextString(m) }
+func (*ConjunctionQuery) ProtoMessage()    {}
+func (*ConjunctionQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{7}
+}
+func (m *ConjunctionQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConjunctionQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConjunctionQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConjunctionQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConjunctionQuery.Merge(m, src)
+}
+func (m *ConjunctionQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConjunctionQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConjunctionQuery.DiscardUnknown(m)
 }
 
-func (m *ConjunctionQuery) Reset()                    { *m = ConjunctionQuery{} }
-func (m *ConjunctionQuery) String() string            { return proto.CompactTextString(m) }
-func (*ConjunctionQuery) ProtoMessage()               {}
-func (*ConjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{4} }
+var xxx_messageInfo_ConjunctionQuery proto.InternalMessageInfo
 
 func (m *ConjunctionQuery) GetQueries() []*Query {
 	if m != nil {
@@ -153,13 +467,41 @@ func (m *ConjunctionQuery) GetQueries() []*Query {
 }
 
 type DisjunctionQuery struct {
-	Queries []*Query `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+	Queries []*Query `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+}
+
+func (m *DisjunctionQuery) Reset()         { *m = DisjunctionQuery{} }
+func (m *DisjunctionQuery) String() string { return proto.CompactTextString(m) }
+func (*DisjunctionQuery) ProtoMessage()    {}
+func (*DisjunctionQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{8}
+}
+func (m *DisjunctionQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DisjunctionQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DisjunctionQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DisjunctionQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisjunctionQuery.Merge(m, src)
+}
+func (m *DisjunctionQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *DisjunctionQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisjunctionQuery.DiscardUnknown(m)
 }
 
-func (m *DisjunctionQuery) Reset()                    { *m = DisjunctionQuery{} }
-func (m *DisjunctionQuery) String() string            { return proto.CompactTextString(m) }
-func (*DisjunctionQuery) ProtoMessage()               {}
-func (*DisjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
+var xxx_messageInfo_DisjunctionQuery proto.InternalMessageInfo
 
 func (m *DisjunctionQuery) GetQueries() []*Query {
 	if m != nil {
@@ -171,10 +513,38 @@ func (m *DisjunctionQuery) GetQueries() []*Query {
 type AllQuery struct {
 }
 
-func (m *AllQuery) Reset()                    { *m = AllQuery{} }
-func (m *AllQuery) String() string            { return proto.CompactTextString(m) }
-func (*AllQuery) ProtoMessage()               {}
-func (*AllQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{6} }
+func (m *AllQuery) Reset()         { *m = AllQuery{} }
+func (m *AllQuery) String() string { return proto.CompactTextString(m) }
+func (*AllQuery) ProtoMessage()    {}
+func (*AllQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{9}
+}
+func (m *AllQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AllQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AllQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AllQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllQuery.Merge(m, src)
+}
+func (m *AllQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *AllQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllQuery proto.InternalMessageInfo
 
 type Query struct {
 	// Types that are valid to be assigned to Query:
@@ -185,13 +555,44 @@ type Query struct {
 	//	*Query_Disjunction
 	//	*Query_All
 	//	*Query_Field
+	//	*Query_Prefix
+	//	*Query_Range
+	//	*Query_CaseInsensitiveTerm
 	Query isQuery_Query `protobuf_oneof:"query"`
 }
 
-func (m *Query) Reset()                    { *m = Query{} }
-func (m *Query) String() string            { return proto.CompactTextString(m) }
-func (*Query) ProtoMessage()               {}
-func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{7} }
+func (m *Query) Reset()         { *m = Query{} }
+func (m *Query) String() string { return proto.CompactTextString(m) }
+func (*Query) ProtoMessage()    {}
+func (*Query) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0057d6187b27ff7, []int{10}
+}
+func (m *Query) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Query) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Query.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Query) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Query.Merge(m, src)
+}
+func (m *Query) XXX_Size() int {
+	return m.Size()
+}
+func (m *Query) XXX_DiscardUnknown() {
+	xxx_messageInfo_Query.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Query proto.InternalMessageInfo
 
 type isQuery_Query interface {
 	isQuery_Query()
@@ -200,34 +601,46 @@ type isQuery_Query interface {
 }
 
 type Query_Term struct {
-	Term *TermQuery `protobuf:"bytes,1,opt,name=term,oneof"`
+	Term *TermQuery `protobuf:"bytes,1,opt,name=term,proto3,oneof" json:"term,omitempty"`
 }
 type Query_Regexp struct {
-	Regexp *RegexpQuery `protobuf:"bytes,2,opt,name=regexp,oneof"`
+	Regexp *RegexpQuery `protobuf:"bytes,2,opt,name=regexp,proto3,oneof" json:"regexp,omitempty"`
 }
 type Query_Negation struct {
-	Negation *NegationQuery `protobuf:"bytes,3,opt,name=negation,oneof"`
+	Negation *NegationQuery `protobuf:"bytes,3,opt,name=negation,proto3,oneof" json:"negation,omitempty"`
 }
 type Query_Conjunction struct {
-	Conjunction *ConjunctionQuery `protobuf:"bytes,4,opt,name=conjunction,oneof"`
+	Conjunction *ConjunctionQuery `protobuf:"bytes,4,opt,name=conjunction,proto3,oneof" json:"conjunction,omitempty"`
 }
 type Query_Disjunction struct {
-	Disjunction *DisjunctionQuery `protobuf:"bytes,5,opt,name=disjunction,oneof"`
+	Disjunction *DisjunctionQuery `protobuf:"bytes,5,opt,name=disjunction,proto3,oneof" json:"disjunction,omitempty"`
 }
 type Query_All struct {
-	All *AllQuery `protobuf:"bytes,6,opt,name=all,oneof"`
+	All *AllQuery `protobuf:"bytes,6,opt,name=all,proto3,oneof" json:"all,omitempty"`
 }
 type Query_Field struct {
-	Field *FieldQuery `protobuf:"bytes,7,opt,name=field,oneof"`
+	Field *FieldQuery `protobuf:"bytes,7,opt,name=field,proto3,oneof" json:"field,omitempty"`
+}
+type Query_Prefix struct {
+	Prefix *PrefixQuery `protobuf:"bytes,8,opt,name=prefix,proto3,oneof" json:"prefix,omitempty"`
+}
+type Query_Range struct {
+	Range *RangeQuery `protobuf:"bytes,9,opt,name=range,proto3,oneof" json:"range,omitempty"`
+}
+type Query_CaseInsensitiveTerm struct {
+	CaseInsensitiveTerm *CaseInsensitiveTermQuery `protobuf:"bytes,10,opt,name=case_insensitive_term,json=caseInsensitiveTerm,proto3,oneof" json:"case_insensitive_term,omitempty"`
 }
 
-func (*Query_Term) isQuery_Query()        {}
-func (*Query_Regexp) isQuery_Query()      {}
-func (*Query_Negation) isQuery_Query()    {}
-func (*Query_Conjunction) isQuery_Query() {}
-func (*Query_Disjunction) isQuery_Query() {}
-func (*Query_All) isQuery_Query()         {}
-func (*Query_Field) isQuery_Query()       {}
+func (*Query_Term) isQuery_Query()                {}
+func (*Query_Regexp) isQuery_Query()              {}
+func (*Query_Negation) isQuery_Query()            {}
+func (*Query_Conjunction) isQuery_Query()         {}
+func (*Query_Disjunction) isQuery_Query()         {}
+func (*Query_All) isQuery_Query()                 {}
+func (*Query_Field) isQuery_Query()               {}
+func (*Query_Prefix) isQuery_Query()              {}
+func (*Query_Range) isQuery_Query()               {}
+func (*Query_CaseInsensitiveTerm) isQuery_Query() {}
 
 func (m *Query) GetQuery() isQuery_Query {
 	if m != nil {
@@ -285,189 +698,105 @@ func (m *Query) GetField() *FieldQuery {
 	return nil
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _Query_OneofMarshaler, _Query_OneofUnmarshaler, _Query_OneofSizer, []interface{}{
-		(*Query_Term)(nil),
-		(*Query_Regexp)(nil),
-		(*Query_Negation)(nil),
-		(*Query_Conjunction)(nil),
-		(*Query_Disjunction)(nil),
-		(*Query_All)(nil),
-		(*Query_Field)(nil),
+func (m *Query) GetPrefix() *PrefixQuery {
+	if x, ok := m.GetQuery().(*Query_Prefix); ok {
+		return x.Prefix
 	}
+	return nil
 }
 
-func _Query_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*Query)
-	// query
-	switch x := m.Query.(type) {
-	case *Query_Term:
-		_ = b.EncodeVarint(1<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Term); err != nil {
-			return err
-		}
-	case *Query_Regexp:
-		_ = b.EncodeVarint(2<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Regexp); err != nil {
-			return err
-		}
-	case *Query_Negation:
-		_ = b.EncodeVarint(3<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Negation); err != nil {
-			return err
-		}
-	case *Query_Conjunction:
-		_ = b.EncodeVarint(4<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Conjunction); err != nil {
-			return err
-		}
-	case *Query_Disjunction:
-		_ = b.EncodeVarint(5<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Disjunction); err != nil {
-			return err
-		}
-	case *Query_All:
-		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.All); err != nil {
-			return err
-		}
-	case *Query_Field:
-		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Field); err != nil {
-			return err
-		}
-	case nil:
-	default:
-		return fmt.Errorf("Query.Query has unexpected type %T", x)
+func (m *Query) GetRange() *RangeQuery {
+	if x, ok := m.GetQuery().(*Query_Range); ok {
+		return x.Range
 	}
 	return nil
 }
 
-func _Query_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*Query)
-	switch tag {
-	case 1: // query.term
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(TermQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_Term{msg}
-		return true, err
-	case 2: // query.regexp
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(RegexpQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_Regexp{msg}
-		return true, err
-	case 3: // query.negation
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(NegationQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_Negation{msg}
-		return true, err
-	case 4: // query.conjunction
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(ConjunctionQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_Conjunction{msg}
-		return true, err
-	case 5: // query.disjunction
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(DisjunctionQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_Disjunction{msg}
-		return true, err
-	case 6: // query.all
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(AllQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_All{msg}
-		return true, err
-	case 7: // query.field
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(FieldQuery)
-		err := b.DecodeMessage(msg)
-		m.Query = &Query_Field{msg}
-		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _Query_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*Query)
-	// query
-	switch x := m.Query.(type) {
-	case *Query_Term:
-		s := proto.Size(x.Term)
-		n += proto.SizeVarint(1<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Query_Regexp:
-		s := proto.Size(x.Regexp)
-		n += proto.SizeVarint(2<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Query_Negation:
-		s := proto.Size(x.Negation)
-		n += proto.SizeVarint(3<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Query_Conjunction:
-		s := proto.Size(x.Conjunction)
-		n += proto.SizeVarint(4<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Query_Disjunction:
-		s := proto.Size(x.Disjunction)
-		n += proto.SizeVarint(5<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Query_All:
-		s := proto.Size(x.All)
-		n += proto.SizeVarint(6<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Query_Field:
-		s := proto.Size(x.Field)
-		n += proto.SizeVarint(7<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+func (m *Query) GetCaseInsensitiveTerm() *CaseInsensitiveTermQuery {
+	if x, ok := m.GetQuery().(*Query_CaseInsensitiveTerm); ok {
+		return x.CaseInsensitiveTerm
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Query) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Query_Term)(nil),
+		(*Query_Regexp)(nil),
+		(*Query_Negation)(nil),
+		(*Query_Conjunction)(nil),
+		(*Query_Disjunction)(nil),
+		(*Query_All)(nil),
+		(*Query_Field)(nil),
+		(*Query_Prefix)(nil),
+		(*Query_Range)(nil),
+		(*Query_CaseInsensitiveTerm)(nil),
 	}
-	return n
 }
 
 func init() {
 	proto.RegisterType((*FieldQuery)(nil), "query.FieldQuery")
 	proto.RegisterType((*TermQuery)(nil), "query.TermQuery")
 	proto.RegisterType((*RegexpQuery)(nil), "query.RegexpQuery")
+	proto.RegisterType((*PrefixQuery)(nil), "query.PrefixQuery")
+	proto.RegisterType((*RangeQuery)(nil), "query.RangeQuery")
+	proto.RegisterType((*CaseInsensitiveTermQuery)(nil), "query.CaseInsensitiveTermQuery")
 	proto.RegisterType((*NegationQuery)(nil), "query.NegationQuery")
 	proto.RegisterType((*ConjunctionQuery)(nil), "query.ConjunctionQuery")
 	proto.RegisterType((*DisjunctionQuery)(nil), "query.DisjunctionQuery")
 	proto.RegisterType((*AllQuery)(nil), "query.AllQuery")
 	proto.RegisterType((*Query)(nil), "query.Query")
 }
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/m3ninx/generated/proto/querypb/query.proto", fileDescriptor_a0057d6187b27ff7)
+}
+
+var fileDescriptor_a0057d6187b27ff7 = []byte{
+	// 563 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xcd, 0x6e, 0xd3, 0x4e,
+	0x14, 0xc5, 0xed, 0x7f, 0x3e, 0x7b, 0x9d, 0xea, 0x1f, 0x86, 0x02, 0x5e, 0x99, 0xe0, 0x4a, 0xa8,
+	0x48, 0x55, 0x2c, 0x39, 0x62, 0x43, 0x57, 0xfd, 0x10, 0x72, 0x37, 0x08, 0x2c, 0xd8, 0xb0, 0x89,
+	0x1c, 0x67, 0x1a, 0x06, 0xd9, 0x93, 0x60, 0x3b, 0x95, 0x79, 0x0b, 0x16, 0x3c, 0x05, 0x4f, 0xc2,
+	0xb2, 0x4b, 0x96, 0x28, 0x79, 0x11, 0x34, 0x77, 0x66, 0xe2, 0xb8, 0x15, 0x41, 0xea, 0x2a, 0xbe,
+	0xf7, 0x9e, 0xdf, 0x28, 0x3e, 0xf7, 0x8c, 0xe1, 0x74, 0xc6, 0x8a, 0x4f, 0xcb, 0xc9, 0x30, 0x9e,
+	0xa7, 0x5e, 0x3a, 0x9a, 0x4e, 0xbc, 0x74, 0xe4, 0xe5, 0x59, 0xec, 0xa5, 0x23, 0xce, 0x78, 0xe9,
+	0xcd, 0x28, 0xa7, 0x59, 0x54, 0xd0, 0xa9, 0xb7, 0xc8, 0xe6, 0xc5, 0xdc, 0xfb, 0xb2, 0xa4, 0xd9,
+	0xd7, 0xc5, 0x44, 0xfe, 0x0e, 0xb1, 0x47, 0x5a, 0x58, 0xb8, 0x2e, 0xc0, 0x6b, 0x46, 0x93, 0xe9,
+	0x3b, 0x51, 0x91, 0x03, 0x68, 0x5d, 0x89, 0xca, 0x36, 0x07, 0xe6, 0x51, 0x2f, 0x94, 0x85, 0xfb,
+	0x12, 0xf6, 0xde, 0xd3, 0x2c, 0xdd, 0x21, 0x21, 0x04, 0x9a, 0x05, 0xcd, 0x52, 0xfb, 0x3f, 0x6c,
+	0xe2, 0xb3, 0x7b, 0x02, 0x56, 0x48, 0x67, 0xb4, 0x5c, 0xec, 0x02, 0x1f, 0x43, 0x3b, 0x43, 0x91,
+	0x42, 0x55, 0x25, 0xe0, 0xb7, 0x19, 0xbd, 0x62, 0xe5, 0x3f, 0xe0, 0x05, 0x8a, 0x34, 0x2c, 0x2b,
+	0xf7, 0x87, 0x09, 0x10, 0x46, 0x7c, 0x46, 0x77, 0xc1, 0x7d, 0x68, 0xa4, 0x8c, 0x2b, 0x52, 0x3c,
+	0x62, 0x27, 0x2a, 0xed, 0x86, 0xea, 0x44, 0x25, 0x39, 0x84, 0xfd, 0x94, 0xf1, 0x31, 0xe3, 0x71,
+	0xb2, 0xcc, 0xd9, 0x35, 0xb5, 0x9b, 0x03, 0xf3, 0xa8, 0x1b, 0xf6, 0x52, 0xc6, 0x2f, 0x75, 0x0f,
+	0x45, 0x51, 0xb9, 0x25, 0x6a, 0x29, 0x51, 0x54, 0x56, 0x22, 0x1b, 0x3a, 0x7c, 0x99, 0xd2, 0x8c,
+	0xc5, 0x76, 0x1b, 0xc7, 0xba, 0x74, 0x2f, 0xc0, 0x3e, 0x8f, 0x72, 0x7a, 0xc9, 0x73, 0xca, 0x73,
+	0x56, 0xb0, 0x6b, 0x7a, 0x1f, 0xb3, 0x47, 0xb0, 0xff, 0x86, 0xce, 0xa2, 0x82, 0xcd, 0xb9, 0x44,
+	0x5d, 0x90, 0x1b, 0x46, 0xd4, 0xf2, 0x7b, 0x43, 0xb9, 0x7c, 0x1c, 0x86, 0x6a, 0xf9, 0xaf, 0xa0,
+	0x7f, 0x3e, 0xe7, 0x9f, 0x97, 0x3c, 0xae, 0xb8, 0xe7, 0xd0, 0x11, 0x43, 0x46, 0x73, 0xdb, 0x1c,
+	0x34, 0xee, 0x90, 0x7a, 0x28, 0xd8, 0x0b, 0x96, 0xdf, 0x8f, 0x05, 0xe8, 0x9e, 0x26, 0x09, 0x36,
+	0xdd, 0xef, 0x4d, 0x68, 0x69, 0x5a, 0xbe, 0x96, 0xfc, 0xc3, 0x7d, 0x85, 0x6e, 0xcc, 0x08, 0x0c,
+	0xf9, 0xaa, 0xe4, 0xb8, 0x16, 0x19, 0xcb, 0x27, 0x4a, 0xb9, 0x15, 0xb6, 0xc0, 0xd0, 0x41, 0x22,
+	0x3e, 0x74, 0xb9, 0x32, 0x06, 0x37, 0x6b, 0xf9, 0x07, 0x4a, 0x5f, 0xf3, 0x2b, 0x30, 0xc2, 0x8d,
+	0x8e, 0x9c, 0x80, 0x15, 0x57, 0xbe, 0xe0, 0xd2, 0x2d, 0xff, 0x89, 0xc2, 0x6e, 0x3b, 0x16, 0x18,
+	0xe1, 0xb6, 0x5a, 0xc0, 0xd3, 0xca, 0x18, 0xbb, 0x55, 0x83, 0x6f, 0x5b, 0x26, 0xe0, 0x2d, 0x35,
+	0x39, 0x84, 0x46, 0x94, 0x24, 0x18, 0x11, 0xcb, 0xff, 0x5f, 0x41, 0xda, 0xab, 0xc0, 0x08, 0xc5,
+	0x94, 0xbc, 0xd0, 0xa9, 0xe8, 0xa0, 0xec, 0x81, 0x92, 0x55, 0xf7, 0x38, 0x30, 0x74, 0x54, 0x8e,
+	0x37, 0x37, 0xa4, 0x5b, 0xf3, 0x6a, 0xeb, 0x6e, 0x09, 0xaf, 0xa4, 0x46, 0x1c, 0x9c, 0x89, 0x6b,
+	0x63, 0xef, 0xd5, 0x0e, 0xae, 0xae, 0x92, 0x38, 0x18, 0x15, 0xe4, 0x03, 0x3c, 0x8a, 0xa3, 0x9c,
+	0x8e, 0x59, 0x15, 0xdb, 0x31, 0x6e, 0x0f, 0x10, 0x7d, 0xaa, 0xcd, 0xfa, 0x4b, 0xb2, 0x03, 0x23,
+	0x7c, 0x18, 0xdf, 0x9d, 0x9d, 0x75, 0x54, 0x6a, 0xcf, 0x9e, 0xfd, 0x5c, 0x39, 0xe6, 0xcd, 0xca,
+	0x31, 0x7f, 0xaf, 0x1c, 0xf3, 0xdb, 0xda, 0x31, 0x6e, 0xd6, 0x8e, 0xf1, 0x6b, 0xed, 0x18, 0x1f,
+	0x3b, 0xea, 0x6b, 0x36, 0x69, 0xe3, 0x87, 0x6c, 0xf4, 0x67, 0x00, 0x66, 0xc3, 0xcf, 0x14, 0x0d,
+	0x05, 0x00, 0x00,
+}
+
 func (m *FieldQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -475,23 +804,29 @@ func (m *FieldQuery) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *FieldQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FieldQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Field) > 0 {
-		dAtA[i] = 0xa
-		i++
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
 		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
-		i += copy(dAtA[i:], m.Field)
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *TermQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -499,29 +834,36 @@ func (m *TermQuery) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *TermQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TermQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Field) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
-		i += copy(dAtA[i:], m.Field)
-	}
 	if len(m.Term) > 0 {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.Term)
+		copy(dAtA[i:], m.Term)
 		i = encodeVarintQuery(dAtA, i, uint64(len(m.Term)))
-		i += copy(dAtA[i:], m.Term)
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *RegexpQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -529,87 +871,256 @@ func (m *RegexpQuery) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *RegexpQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RegexpQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Field) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
-		i += copy(dAtA[i:], m.Field)
-	}
 	if len(m.Regexp) > 0 {
-		dAtA[i] = 0x12
-		i++
+		i -= len(m.Regexp)
+		copy(dAtA[i:], m.Regexp)
 		i = encodeVarintQuery(dAtA, i, uint64(len(m.Regexp)))
-		i += copy(dAtA[i:], m.Regexp)
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
-func (m *NegationQuery) Marshal() (dAtA []byte, err error) {
+func (m *PrefixQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
 	return dAtA[:n], nil
 }
 
-func (m *NegationQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+func (m *PrefixQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrefixQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Query != nil {
+	if len(m.Prefix) > 0 {
+		i -= len(m.Prefix)
+		copy(dAtA[i:], m.Prefix)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Prefix)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i--
 		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Query.Size()))
-		n1, err := m.Query.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
-func (m *ConjunctionQuery) Marshal() (dAtA []byte, err error) {
+func (m *RangeQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
 	return dAtA[:n], nil
 }
 
-func (m *ConjunctionQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
+func (m *RangeQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RangeQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
 	_ = l
-	if len(m.Queries) > 0 {
-		for _, msg := range m.Queries {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
+	if m.Numeric {
+		i--
+		if m.Numeric {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.MaxInclusive {
+		i--
+		if m.MaxInclusive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.MinInclusive {
+		i--
+		if m.MinInclusive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Max) > 0 {
+		i -= len(m.Max)
+		copy(dAtA[i:], m.Max)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Max)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Min) > 0 {
+		i -= len(m.Min)
+		copy(dAtA[i:], m.Min)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Min)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CaseInsensitiveTermQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CaseInsensitiveTermQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CaseInsensitiveTermQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Term) > 0 {
+		i -= len(m.Term)
+		copy(dAtA[i:], m.Term)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Term)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *NegationQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NegationQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NegationQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
-			i += n
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ConjunctionQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConjunctionQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConjunctionQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Queries) > 0 {
+		for iNdEx := len(m.Queries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Queries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQuery(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *DisjunctionQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -617,29 +1128,36 @@ func (m *DisjunctionQuery) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *DisjunctionQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DisjunctionQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Queries) > 0 {
-		for _, msg := range m.Queries {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+		for iNdEx := len(m.Queries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Queries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQuery(dAtA, i, uint64(size))
 			}
-			i += n
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *AllQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -647,17 +1165,22 @@ func (m *AllQuery) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *AllQuery) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AllQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *Query) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -665,128 +1188,252 @@ func (m *Query) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *Query) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if m.Query != nil {
-		nn2, err := m.Query.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size := m.Query.Size()
+			i -= size
+			if _, err := m.Query.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
-		i += nn2
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *Query_Term) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Term) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Term != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Term.Size()))
-		n3, err := m.Term.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Term.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n3
+		i--
+		dAtA[i] = 0xa
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *Query_Regexp) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Regexp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Regexp != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Regexp.Size()))
-		n4, err := m.Regexp.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Regexp.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n4
+		i--
+		dAtA[i] = 0x12
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *Query_Negation) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Negation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Negation != nil {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Negation.Size()))
-		n5, err := m.Negation.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Negation.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n5
+		i--
+		dAtA[i] = 0x1a
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *Query_Conjunction) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Conjunction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Conjunction != nil {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Conjunction.Size()))
-		n6, err := m.Conjunction.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Conjunction.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n6
+		i--
+		dAtA[i] = 0x22
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *Query_Disjunction) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Disjunction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Disjunction != nil {
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Disjunction.Size()))
-		n7, err := m.Disjunction.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.Disjunction.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n7
+		i--
+		dAtA[i] = 0x2a
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *Query_All) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_All) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.All != nil {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.All.Size()))
-		n8, err := m.All.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		{
+			size, err := m.All.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n8
+		i--
+		dAtA[i] = 0x32
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func (m *Query_Field) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Field) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Field != nil {
+		{
+			size, err := m.Field.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
 		dAtA[i] = 0x3a
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Field.Size()))
-		n9, err := m.Field.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Query_Prefix) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Prefix) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Prefix != nil {
+		{
+			size, err := m.Prefix.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Query_Range) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_Range) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Range != nil {
+		{
+			size, err := m.Range.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Query_CaseInsensitiveTerm) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query_CaseInsensitiveTerm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CaseInsensitiveTerm != nil {
+		{
+			size, err := m.CaseInsensitiveTerm.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQuery(dAtA, i, uint64(size))
 		}
-		i += n9
+		i--
+		dAtA[i] = 0x52
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	offset -= sovQuery(v)
+	base := offset
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
 		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
-	return offset + 1
+	return base
 }
 func (m *FieldQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Field)
@@ -797,6 +1444,9 @@ func (m *FieldQuery) Size() (n int) {
 }
 
 func (m *TermQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Field)
@@ -811,6 +1461,9 @@ func (m *TermQuery) Size() (n int) {
 }
 
 func (m *RegexpQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	l = len(m.Field)
@@ -824,7 +1477,74 @@ func (m *RegexpQuery) Size() (n int) {
 	return n
 }
 
+func (m *PrefixQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *RangeQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Min)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Max)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.MinInclusive {
+		n += 2
+	}
+	if m.MaxInclusive {
+		n += 2
+	}
+	if m.Numeric {
+		n += 2
+	}
+	return n
+}
+
+func (m *CaseInsensitiveTermQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Term)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
 func (m *NegationQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Query != nil {
@@ -835,6 +1555,9 @@ func (m *NegationQuery) Size() (n int) {
 }
 
 func (m *ConjunctionQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Queries) > 0 {
@@ -847,6 +1570,9 @@ func (m *ConjunctionQuery) Size() (n int) {
 }
 
 func (m *DisjunctionQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Queries) > 0 {
@@ -859,12 +1585,18 @@ func (m *DisjunctionQuery) Size() (n int) {
 }
 
 func (m *AllQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	return n
 }
 
 func (m *Query) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Query != nil {
@@ -874,6 +1606,9 @@ func (m *Query) Size() (n int) {
 }
 
 func (m *Query_Term) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Term != nil {
@@ -883,6 +1618,9 @@ func (m *Query_Term) Size() (n int) {
 	return n
 }
 func (m *Query_Regexp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Regexp != nil {
@@ -892,6 +1630,9 @@ func (m *Query_Regexp) Size() (n int) {
 	return n
 }
 func (m *Query_Negation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Negation != nil {
@@ -901,6 +1642,9 @@ func (m *Query_Negation) Size() (n int) {
 	return n
 }
 func (m *Query_Conjunction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Conjunction != nil {
@@ -910,6 +1654,9 @@ func (m *Query_Conjunction) Size() (n int) {
 	return n
 }
 func (m *Query_Disjunction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Disjunction != nil {
@@ -919,6 +1666,9 @@ func (m *Query_Disjunction) Size() (n int) {
 	return n
 }
 func (m *Query_All) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.All != nil {
@@ -928,6 +1678,9 @@ func (m *Query_All) Size() (n int) {
 	return n
 }
 func (m *Query_Field) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.Field != nil {
@@ -936,17 +1689,46 @@ func (m *Query_Field) Size() (n int) {
 	}
 	return n
 }
-
-func sovQuery(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+func (m *Query_Prefix) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Prefix != nil {
+		l = m.Prefix.Size()
+		n += 1 + l + sovQuery(uint64(l))
 	}
 	return n
 }
+func (m *Query_Range) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Range != nil {
+		l = m.Range.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_CaseInsensitiveTerm) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CaseInsensitiveTerm != nil {
+		l = m.CaseInsensitiveTerm.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
 func sozQuery(x uint64) (n int) {
 	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
@@ -965,23 +1747,377 @@ func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FieldQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FieldQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TermQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TermQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
+			if m.Term == nil {
+				m.Term = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegexpQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegexpQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Regexp = append(m.Regexp[:0], dAtA[iNdEx:postIndex]...)
+			if m.Regexp == nil {
+				m.Regexp = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrefixQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: FieldQuery: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FieldQuery: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrefixQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrefixQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -993,7 +2129,7 @@ func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1002,12 +2138,15 @@ func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
-			if m.Field == nil {
-				m.Field = []byte{}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1016,7 +2155,7 @@ func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1031,7 +2170,7 @@ func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TermQuery) Unmarshal(dAtA []byte) error {
+func (m *RangeQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1046,7 +2185,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1054,10 +2193,10 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TermQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: RangeQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RangeQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1074,7 +2213,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1083,6 +2222,9 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1093,7 +2235,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1105,7 +2247,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1114,21 +2256,118 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
-			if m.Term == nil {
-				m.Term = []byte{}
+			m.Min = append(m.Min[:0], dAtA[iNdEx:postIndex]...)
+			if m.Min == nil {
+				m.Min = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Max = append(m.Max[:0], dAtA[iNdEx:postIndex]...)
+			if m.Max == nil {
+				m.Max = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinInclusive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MinInclusive = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxInclusive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MaxInclusive = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Numeric", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Numeric = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1143,7 +2382,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
+func (m *CaseInsensitiveTermQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1158,7 +2397,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1166,10 +2405,10 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RegexpQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: CaseInsensitiveTermQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RegexpQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CaseInsensitiveTermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1186,7 +2425,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1195,6 +2434,9 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1205,7 +2447,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1217,7 +2459,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1226,12 +2468,15 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Regexp = append(m.Regexp[:0], dAtA[iNdEx:postIndex]...)
-			if m.Regexp == nil {
-				m.Regexp = []byte{}
+			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
+			if m.Term == nil {
+				m.Term = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1240,7 +2485,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1270,7 +2515,7 @@ func (m *NegationQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1298,7 +2543,7 @@ func (m *NegationQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1307,6 +2552,9 @@ func (m *NegationQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1323,7 +2571,7 @@ func (m *NegationQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1353,7 +2601,7 @@ func (m *ConjunctionQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1381,7 +2629,7 @@ func (m *ConjunctionQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1390,6 +2638,9 @@ func (m *ConjunctionQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1404,7 +2655,7 @@ func (m *ConjunctionQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1434,7 +2685,7 @@ func (m *DisjunctionQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1462,7 +2713,7 @@ func (m *DisjunctionQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1471,6 +2722,9 @@ func (m *DisjunctionQuery) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1485,7 +2739,7 @@ func (m *DisjunctionQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1515,7 +2769,7 @@ func (m *AllQuery) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1535,7 +2789,7 @@ func (m *AllQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1565,7 +2819,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1593,7 +2847,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1602,6 +2856,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1625,7 +2882,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1634,6 +2891,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1657,7 +2917,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1666,6 +2926,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1689,7 +2952,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1698,6 +2961,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1721,7 +2987,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1730,6 +2996,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1753,7 +3022,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1762,6 +3031,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1785,7 +3057,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1794,6 +3066,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthQuery
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -1803,13 +3078,118 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			m.Query = &Query_Field{v}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &PrefixQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Prefix{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &RangeQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Range{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CaseInsensitiveTerm", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CaseInsensitiveTermQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_CaseInsensitiveTerm{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if (iNdEx + skippy) > l {
@@ -1827,6 +3207,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 func skipQuery(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -1858,10 +3239,8 @@ func skipQuery(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -1878,86 +3257,34 @@ func skipQuery(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthQuery
 			}
-			return iNdEx, nil
+			iNdEx += length
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowQuery
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipQuery(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupQuery
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthQuery
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthQuery = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowQuery   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthQuery        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowQuery          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupQuery = fmt.Errorf("proto: unexpected end of group")
 )
-
-func init() {
-	proto.RegisterFile("github.com/m3db/m3/src/m3ninx/generated/proto/querypb/query.proto", fileDescriptorQuery)
-}
-
-var fileDescriptorQuery = []byte{
-	// 389 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xcd, 0x4a, 0xf3, 0x40,
-	0x14, 0x86, 0x93, 0xb6, 0x69, 0xfa, 0x9d, 0xf4, 0xc3, 0x3a, 0x14, 0x8d, 0x9b, 0x50, 0x22, 0x88,
-	0x82, 0x34, 0xd0, 0xe0, 0xc6, 0xae, 0x5a, 0x45, 0xb2, 0x12, 0x0c, 0xae, 0xdc, 0x35, 0xc9, 0x18,
-	0x23, 0xc9, 0xa4, 0x4e, 0x53, 0xa8, 0x77, 0xe1, 0x65, 0xb9, 0xf4, 0x12, 0xa4, 0x7a, 0x21, 0x32,
-	0x3f, 0x69, 0xda, 0x0a, 0x5d, 0xb8, 0x4a, 0xce, 0x9c, 0xf7, 0x81, 0x99, 0x87, 0x17, 0x46, 0x71,
-	0x52, 0x3c, 0xcd, 0x83, 0x7e, 0x98, 0x67, 0x4e, 0xe6, 0x46, 0x81, 0x93, 0xb9, 0xce, 0x8c, 0x86,
-	0x4e, 0xe6, 0x92, 0x84, 0x2c, 0x9c, 0x18, 0x13, 0x4c, 0x27, 0x05, 0x8e, 0x9c, 0x29, 0xcd, 0x8b,
-	0xdc, 0x79, 0x99, 0x63, 0xfa, 0x3a, 0x0d, 0xc4, 0xb7, 0xcf, 0xcf, 0x90, 0xc6, 0x07, 0xdb, 0x06,
-	0xb8, 0x49, 0x70, 0x1a, 0xdd, 0xb1, 0x09, 0x75, 0x41, 0x7b, 0x64, 0x93, 0xa9, 0xf6, 0xd4, 0xd3,
-	0xb6, 0x2f, 0x06, 0xfb, 0x02, 0xfe, 0xdd, 0x63, 0x9a, 0xed, 0x88, 0x20, 0x04, 0x8d, 0x02, 0xd3,
-	0xcc, 0xac, 0xf1, 0x43, 0xfe, 0x6f, 0x0f, 0xc1, 0xf0, 0x71, 0x8c, 0x17, 0xd3, 0x5d, 0xe0, 0x01,
-	0x34, 0x29, 0x0f, 0x49, 0x54, 0x4e, 0xb6, 0x0b, 0xff, 0x6f, 0x71, 0x3c, 0x29, 0x92, 0x9c, 0x08,
-	0xdc, 0x06, 0x71, 0x63, 0x8e, 0x1b, 0x83, 0x76, 0x5f, 0x3c, 0x86, 0x2f, 0x7d, 0xf9, 0x98, 0x4b,
-	0xe8, 0x5c, 0xe5, 0xe4, 0x79, 0x4e, 0xc2, 0x8a, 0x3b, 0x01, 0x9d, 0x2d, 0x13, 0x3c, 0x33, 0xd5,
-	0x5e, 0xfd, 0x17, 0x59, 0x2e, 0x19, 0x7b, 0x9d, 0xcc, 0xfe, 0xc6, 0x02, 0xb4, 0x46, 0x69, 0xca,
-	0x0f, 0xed, 0xef, 0x1a, 0x68, 0x25, 0x2d, 0x9c, 0x88, 0x0b, 0x77, 0x24, 0xba, 0x32, 0xe9, 0x29,
-	0xc2, 0x13, 0x3a, 0xdf, 0x50, 0x60, 0x0c, 0x90, 0x4c, 0xae, 0xc9, 0xf3, 0x94, 0x52, 0x0c, 0x1a,
-	0x40, 0x8b, 0x48, 0x31, 0x66, 0x9d, 0xe7, 0xbb, 0x32, 0xbf, 0xe1, 0xcb, 0x53, 0xfc, 0x55, 0x0e,
-	0x0d, 0xc1, 0x08, 0x2b, 0x2f, 0x66, 0x83, 0x63, 0x87, 0x12, 0xdb, 0x36, 0xe6, 0x29, 0xfe, 0x7a,
-	0x9a, 0xc1, 0x51, 0x25, 0xc6, 0xd4, 0x36, 0xe0, 0x6d, 0x65, 0x0c, 0x5e, 0x4b, 0xa3, 0x63, 0xa8,
-	0x4f, 0xd2, 0xd4, 0x6c, 0x72, 0x68, 0x4f, 0x42, 0xa5, 0x2b, 0x4f, 0xf1, 0xd9, 0x16, 0x9d, 0x95,
-	0xcd, 0xd0, 0x79, 0x6c, 0x5f, 0xc6, 0xaa, 0x5e, 0x7a, 0x8a, 0xac, 0xcb, 0x58, 0x97, 0x2d, 0x18,
-	0x1f, 0xbd, 0x2f, 0x2d, 0xf5, 0x63, 0x69, 0xa9, 0x9f, 0x4b, 0x4b, 0x7d, 0xfb, 0xb2, 0x94, 0x07,
-	0x5d, 0xb6, 0x3c, 0x68, 0xf2, 0x82, 0xbb, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x7d, 0x4a, 0xb7,
-	0x71, 0x25, 0x03, 0x00, 0x00,
-}
@@ -21,6 +21,7 @@
 package idx
 
 import (
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/search"
 	"github.com/m3db/m3/src/m3ninx/search/query"
 )
@@ -94,6 +95,39 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewPrefixQuery returns a new query for finding documents which have a term starting
+// with a prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewRangeQuery returns a new query for finding documents which have a term within a
+// range, a nil bound is unbounded. Terms are compared as numbers if numeric is set and
+// lexicographically otherwise.
+func NewRangeQuery(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+	numeric bool,
+) (Query, error) {
+	termRange, err := index.NewTermRange(min, max, minInclusive, maxInclusive, numeric)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{
+		query: query.NewRangeQuery(field, termRange),
+	}, nil
+}
+
+// NewCaseInsensitiveTermQuery returns a new query for finding documents which match a
+// term ignoring case.
+func NewCaseInsensitiveTermQuery(field, term []byte) Query {
+	return Query{
+		query: query.NewCaseInsensitiveTermQuery(field, term),
+	}
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
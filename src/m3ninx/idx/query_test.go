@@ -62,3 +62,15 @@ func TestFieldQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRangeQuery(t *testing.T) {
+	q, err := NewRangeQuery([]byte("fruit"), []byte("apple"), nil, true, false, false)
+	require.NoError(t, err)
+	require.Equal(t, `range(fruit, ["apple", *))`, q.String())
+
+	_, err = NewRangeQuery([]byte("weight"), []byte("heavy"), nil, true, false, true)
+	require.Error(t, err)
+
+	_, err = NewRangeQuery([]byte("fruit"), []byte("banana"), []byte("apple"), true, false, false)
+	require.Error(t, err)
+}
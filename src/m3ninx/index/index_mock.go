@@ -145,6 +145,36 @@ func (mr *MockReaderMockRecorder) MatchField(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchField", reflect.TypeOf((*MockReader)(nil).MatchField), arg0)
 }
 
+// MatchPrefix mocks base method.
+func (m *MockReader) MatchPrefix(arg0, arg1 []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchPrefix", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPrefix indicates an expected call of MatchPrefix.
+func (mr *MockReaderMockRecorder) MatchPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPrefix", reflect.TypeOf((*MockReader)(nil).MatchPrefix), arg0, arg1)
+}
+
+// MatchRange mocks base method.
+func (m *MockReader) MatchRange(arg0 []byte, arg1 TermRange) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchRange", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchRange indicates an expected call of MatchRange.
+func (mr *MockReaderMockRecorder) MatchRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchRange", reflect.TypeOf((*MockReader)(nil).MatchRange), arg0, arg1)
+}
+
 // MatchRegexp mocks base method.
 func (m *MockReader) MatchRegexp(arg0 []byte, arg1 CompiledRegex) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +205,21 @@ func (mr *MockReaderMockRecorder) MatchTerm(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerm", reflect.TypeOf((*MockReader)(nil).MatchTerm), arg0, arg1)
 }
 
+// MatchTermCaseInsensitive mocks base method.
+func (m *MockReader) MatchTermCaseInsensitive(arg0, arg1 []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchTermCaseInsensitive", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTermCaseInsensitive indicates an expected call of MatchTermCaseInsensitive.
+func (mr *MockReaderMockRecorder) MatchTermCaseInsensitive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTermCaseInsensitive", reflect.TypeOf((*MockReader)(nil).MatchTermCaseInsensitive), arg0, arg1)
+}
+
 // Metadata mocks base method.
 func (m *MockReader) Metadata(arg0 postings.ID) (doc.Metadata, error) {
 	m.ctrl.T.Helper()
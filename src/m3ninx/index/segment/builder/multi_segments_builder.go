@@ -37,6 +37,7 @@ type builderFromSegments struct {
 	segments       []segmentMetadata
 	termsIter      *termsIterFromSegments
 	segmentsOffset postings.ID
+	filter         segment.DocumentsFilter
 }
 
 type segmentMetadata struct {
@@ -44,8 +45,8 @@ type segmentMetadata struct {
 	offset  postings.ID
 	// duplicatesAsc is a lookup of document IDs are duplicates
 	// in this segment, that is documents that are already
-	// contained by other segments and hence should not be
-	// returned when looking up documents.
+	// contained by other segments or are not kept by the filter
+	// and hence should not be returned when looking up documents.
 	duplicatesAsc []postings.ID
 }
 
@@ -79,6 +80,11 @@ func (b *builderFromSegments) Reset() {
 	b.segments = b.segments[:0]
 
 	b.termsIter.clear()
+	b.filter = nil
+}
+
+func (b *builderFromSegments) SetFilter(keep segment.DocumentsFilter) {
+	b.filter = keep
 }
 
 func (b *builderFromSegments) AddSegments(segments []segment.Segment) error {
@@ -119,7 +125,10 @@ func (b *builderFromSegments) AddSegments(segments []segment.Segment) error {
 		)
 		for iter.Next() {
 			d := iter.Current()
-			if b.idSet.Contains(d.ID) {
+			if b.idSet.Contains(d.ID) ||
+				(b.filter != nil && !b.filter.ContainsDoc(d)) {
+				// NB: filtered documents are dropped the same as duplicates
+				// so their postings IDs are removed from postings lists.
 				duplicates = append(duplicates, iter.PostingsID())
 				continue
 			}
@@ -93,6 +93,21 @@ func (i *multiKeyPostingsListIterator) Next() bool {
 		return true
 	}
 
+	for i.next() {
+		if err := i.buildPostingsList(); err != nil {
+			i.err = err
+			return false
+		}
+		// NB: skip fields with all of their documents filtered.
+		if !i.currFieldPostingsList.IsEmpty() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *multiKeyPostingsListIterator) next() bool {
 	for _, currIter := range i.currIters {
 		currNext := currIter.Next()
 		if currNext {
@@ -119,12 +134,15 @@ func (i *multiKeyPostingsListIterator) Next() bool {
 
 	// Re-evaluate current value
 	i.currEvaluate()
+	return true
+}
 
+func (i *multiKeyPostingsListIterator) buildPostingsList() (err error) {
 	// NB(bodu): Build the postings list for this field if the field has changed.
 	defer func() {
 		for idx, reader := range i.currReaders {
-			if err := reader.Close(); err != nil {
-				i.err = err
+			if closeErr := reader.Close(); closeErr != nil && err == nil {
+				err = closeErr
 			}
 			i.currReaders[idx] = nil
 		}
@@ -138,20 +156,19 @@ func (i *multiKeyPostingsListIterator) Next() bool {
 		fieldsKeyIter := iter.(*fieldsKeyIter)
 		reader, err := fieldsKeyIter.segment.segment.Reader()
 		if err != nil {
-			i.err = err
-			return false
+			return err
 		}
 		i.currReaders = append(i.currReaders, reader)
 
 		pl, err := reader.MatchField(currField)
 		if err != nil {
-			i.err = err
-			return false
+			return err
 		}
 
-		if fieldsKeyIter.segment.offset == 0 {
-			// No offset, which means is first segment we are combining from
-			// so can just direct union
+		if fieldsKeyIter.segment.offset == 0 && len(fieldsKeyIter.segment.duplicatesAsc) == 0 {
+			// No offset or duplicates, which means is first segment we are
+			// combining from and none of its documents were filtered so can
+			// just direct union
 			i.currFieldPostingsList.Union(pl)
 			continue
 		}
@@ -164,8 +181,7 @@ func (i *multiKeyPostingsListIterator) Next() bool {
 		)
 		bitmap, ok := roaring.BitmapFromPostingsList(pl)
 		if !ok {
-			i.err = errPostingsListNotRoaring
-			return false
+			return errPostingsListNotRoaring
 		}
 
 		iter.Reset(bitmap)
@@ -183,12 +199,11 @@ func (i *multiKeyPostingsListIterator) Next() bool {
 			}
 			value := curr + fieldsKeyIter.segment.offset - negativeOffset
 			if err := i.currFieldPostingsList.Insert(value); err != nil {
-				i.err = err
-				return false
+				return err
 			}
 		}
 	}
-	return true
+	return nil
 }
 
 func (i *multiKeyPostingsListIterator) currEvaluate() {
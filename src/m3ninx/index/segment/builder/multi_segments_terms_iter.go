@@ -131,19 +131,31 @@ func (i *termsIterFromSegments) Next() bool {
 		return false
 	}
 
-	if !i.keyIter.Next() {
-		return false
+	for i.keyIter.Next() {
+		if err := i.buildPostingsList(); err != nil {
+			i.err = err
+			return false
+		}
+		// NB: skip terms with all of their documents filtered.
+		if !i.currPostingsList.IsEmpty() {
+			return true
+		}
 	}
 
+	return false
+}
+
+func (i *termsIterFromSegments) buildPostingsList() error {
 	// Create the overlayed postings list for this term
 	i.currPostingsList.Reset()
 	for _, iter := range i.keyIter.CurrentIters() {
 		termsKeyIter := iter.(*termsKeyIter)
 		_, list := termsKeyIter.iter.Current()
 
-		if termsKeyIter.segment.offset == 0 {
-			// No offset, which means is first segment we are combining from
-			// so can just direct union
+		if termsKeyIter.segment.offset == 0 && len(termsKeyIter.segment.duplicatesAsc) == 0 {
+			// No offset or duplicates, which means is first segment we are
+			// combining from and none of its documents were filtered so can
+			// just direct union
 			i.currPostingsList.Union(list)
 			continue
 		}
@@ -156,8 +168,7 @@ func (i *termsIterFromSegments) Next() bool {
 		)
 		bitmap, ok := roaring.BitmapFromPostingsList(list)
 		if !ok {
-			i.err = errPostingsListNotRoaring
-			return false
+			return errPostingsListNotRoaring
 		}
 
 		iter.Reset(bitmap)
@@ -175,13 +186,12 @@ func (i *termsIterFromSegments) Next() bool {
 			}
 			value := curr + termsKeyIter.segment.offset - negativeOffset
 			if err := i.currPostingsList.Insert(value); err != nil {
-				i.err = err
-				return false
+				return err
 			}
 		}
 	}
 
-	return true
+	return nil
 }
 
 func (i *termsIterFromSegments) Current() ([]byte, postings.List) {
@@ -91,6 +91,99 @@ func TestTermsIterFromSegmentsDeduplicates(t *testing.T) {
 	})
 }
 
+type testDocumentsFilter func(d doc.Metadata) bool
+
+func (f testDocumentsFilter) ContainsDoc(d doc.Metadata) bool {
+	return f(d)
+}
+
+func TestTermsIterFromSegmentsFiltered(t *testing.T) {
+	segments := []segment.Segment{
+		newTestSegmentWithDocs(t, []doc.Metadata{
+			{
+				ID: []byte("foo"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("apple")},
+					{Name: []byte("vegetable"), Value: []byte("carrot")},
+				},
+			},
+		}),
+		newTestSegmentWithDocs(t, []doc.Metadata{
+			{
+				ID: []byte("bar"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("apple")},
+					{Name: []byte("color"), Value: []byte("blue")},
+				},
+			},
+			{
+				ID: []byte("foo"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("apple")},
+					{Name: []byte("vegetable"), Value: []byte("carrot")},
+				},
+			},
+			{
+				ID: []byte("baz"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("watermelon")},
+					{Name: []byte("color"), Value: []byte("green")},
+				},
+			},
+			{
+				ID: []byte("bux"),
+				Fields: []doc.Field{
+					{Name: []byte("fruit"), Value: []byte("watermelon")},
+					{Name: []byte("color"), Value: []byte("red")},
+					{Name: []byte("shape"), Value: []byte("round")},
+				},
+			},
+		}),
+	}
+
+	builder := NewBuilderFromSegments(testOptions)
+	builder.Reset()
+	builder.SetFilter(testDocumentsFilter(func(d doc.Metadata) bool {
+		return string(d.ID) != "bar" && string(d.ID) != "bux"
+	}))
+	require.NoError(t, builder.AddSegments(segments))
+
+	docs := builder.Docs()
+	require.Len(t, docs, 2)
+	postingsIDs := make(map[string]int, len(docs))
+	for i, d := range docs {
+		postingsIDs[string(d.ID)] = i
+	}
+	require.Contains(t, postingsIDs, "foo")
+	require.Contains(t, postingsIDs, "baz")
+
+	iter, err := builder.Terms([]byte("fruit"))
+	require.NoError(t, err)
+	assertTermsPostings(t, docs, iter, termPostings{
+		"apple":      []int{postingsIDs["foo"]},
+		"watermelon": []int{postingsIDs["baz"]},
+	})
+
+	iter, err = builder.Terms([]byte("color"))
+	require.NoError(t, err)
+	assertTermsPostings(t, docs, iter, termPostings{
+		"green": []int{postingsIDs["baz"]},
+	})
+
+	// Fields with all of their documents filtered are skipped.
+	fields, err := builder.FieldsPostingsList()
+	require.NoError(t, err)
+	var names []string
+	for fields.Next() {
+		field, _ := fields.Current()
+		names = append(names, string(field))
+	}
+	require.NoError(t, fields.Err())
+	require.NoError(t, fields.Close())
+	require.NotContains(t, names, "shape")
+	require.Contains(t, names, "vegetable")
+}
+
 func assertTermsPostings(
 	t *testing.T,
 	docs []doc.Metadata,
@@ -21,6 +21,7 @@
 package fst
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -605,6 +606,108 @@ func (r *fsSegment) matchRegexpNotClosedMaybeFinalizedWithRLock(
 	return pl, nil
 }
 
+func (r *fsSegment) matchPrefixNotClosedMaybeFinalizedWithRLock(
+	field, prefix []byte,
+) (postings.List, error) {
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field,
+		prefix, index.PrefixEnd(prefix), func(term []byte) bool {
+			return bytes.HasPrefix(term, prefix)
+		})
+}
+
+func (r *fsSegment) matchRangeNotClosedMaybeFinalizedWithRLock(
+	field []byte,
+	termRange index.TermRange,
+) (postings.List, error) {
+	start, end := termRange.Bounds()
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field,
+		start, end, termRange.Contains)
+}
+
+func (r *fsSegment) matchTermCaseInsensitiveNotClosedMaybeFinalizedWithRLock(
+	field, term []byte,
+) (postings.List, error) {
+	// NB: when the term is not ASCII the bounds are nil and the whole
+	// terms FST of the field is scanned.
+	start, end, _ := index.CaseInsensitiveBounds(term)
+	return r.matchTermsNotClosedMaybeFinalizedWithRLock(field,
+		start, end, func(t []byte) bool {
+			return bytes.EqualFold(t, term)
+		})
+}
+
+// matchTermsNotClosedMaybeFinalizedWithRLock returns the union of the
+// postings lists of the terms of the field between start (inclusive) and
+// end (exclusive) which are matched by the predicate, nil bounds meaning
+// unbounded.
+func (r *fsSegment) matchTermsNotClosedMaybeFinalizedWithRLock(
+	field []byte,
+	start, end []byte,
+	matchFn func(term []byte) bool,
+) (postings.List, error) {
+	// NB(r): Not closed, but could be finalized (i.e. closed segment reader)
+	// calling match field after this segment is finalized.
+	if r.finalized {
+		return nil, errReaderFinalized
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool().Get(), nil
+	}
+
+	var (
+		fstCloser     = x.NewSafeCloser(termsFST)
+		iter, iterErr = termsFST.Iterator(start, end)
+		iterCloser    = x.NewSafeCloser(iter)
+		pls           []postings.List
+	)
+	defer func() {
+		iterCloser.Close()
+		fstCloser.Close()
+	}()
+
+	for {
+		if iterErr == vellum.ErrIteratorDone {
+			break
+		}
+
+		if iterErr != nil {
+			return nil, iterErr
+		}
+
+		term, postingsOffset := iter.Current()
+		if matchFn(term) {
+			nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
+			if err != nil {
+				return nil, err
+			}
+			pls = append(pls, nextPl)
+		}
+		iterErr = iter.Next()
+	}
+
+	pl, err := roaring.Union(pls)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := iterCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
 func (r *fsSegment) matchAllNotClosedMaybeFinalizedWithRLock() (postings.MutableList, error) {
 	// NB(r): Not closed, but could be finalized (i.e. closed segment reader)
 	// calling match field after this segment is finalized.
@@ -986,6 +1089,45 @@ func (sr *fsSegmentReader) MatchRegexp(
 	return pl, err
 }
 
+func (sr *fsSegmentReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	// NB(r): We are allowed to call match field after Close called on
+	// the segment but not after it is finalized.
+	sr.fsSegment.RLock()
+	pl, err := sr.fsSegment.matchPrefixNotClosedMaybeFinalizedWithRLock(field, prefix)
+	sr.fsSegment.RUnlock()
+	return pl, err
+}
+
+func (sr *fsSegmentReader) MatchRange(
+	field []byte,
+	termRange index.TermRange,
+) (postings.List, error) {
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	// NB(r): We are allowed to call match field after Close called on
+	// the segment but not after it is finalized.
+	sr.fsSegment.RLock()
+	pl, err := sr.fsSegment.matchRangeNotClosedMaybeFinalizedWithRLock(field, termRange)
+	sr.fsSegment.RUnlock()
+	return pl, err
+}
+
+func (sr *fsSegmentReader) MatchTermCaseInsensitive(field, term []byte) (postings.List, error) {
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+	// NB(r): We are allowed to call match field after Close called on
+	// the segment but not after it is finalized.
+	sr.fsSegment.RLock()
+	pl, err := sr.fsSegment.matchTermCaseInsensitiveNotClosedMaybeFinalizedWithRLock(field, term)
+	sr.fsSegment.RUnlock()
+	return pl, err
+}
+
 func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
 	if sr.closed {
 		return nil, errReaderClosed
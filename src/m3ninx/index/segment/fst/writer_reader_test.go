@@ -404,6 +404,86 @@ func TestPostingsListEqualForMatchTermWithFieldsPostingsList(t *testing.T) {
 	}
 }
 
+func TestPostingsListEqualForMatchTerms(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			memFieldsIter, err := memSeg.Fields()
+			require.NoError(t, err)
+			memFields := toSlice(t, memFieldsIter)
+
+			numericRange, err := index.NewTermRange([]byte("0"), []byte("100"), false, true, true)
+			require.NoError(t, err)
+
+			for _, f := range memFields {
+				memTermsIter, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				memTerms := toTermPostings(t, memTermsIter)
+
+				assertMatch := func(
+					desc string,
+					matchFn func(term []byte) bool,
+					match func(r sgmt.Reader) (postings.List, error),
+				) {
+					expected := []int{}
+					for term, ids := range memTerms {
+						if matchFn([]byte(term)) {
+							expected = append(expected, ids...)
+						}
+					}
+					sort.Sort(sort.IntSlice(expected))
+
+					memPl, err := match(memReader)
+					require.NoError(t, err)
+					fstPl, err := match(fstReader)
+					require.NoError(t, err)
+					require.Equal(t, expected, toSortedIDs(memPl),
+						fmt.Sprintf("%s:%s", string(f), desc))
+					require.True(t, memPl.Equal(fstPl),
+						fmt.Sprintf("%s:%s - [%v] != [%v]", string(f), desc, pprintIter(memPl), pprintIter(fstPl)))
+				}
+
+				assertMatch(numericRange.String(), numericRange.Contains,
+					func(r sgmt.Reader) (postings.List, error) {
+						return r.MatchRange(f, numericRange)
+					})
+
+				for term := range memTerms {
+					prefix := []byte(term[:len(term)/2])
+					assertMatch("prefix "+string(prefix),
+						func(t []byte) bool {
+							return bytes.HasPrefix(t, prefix)
+						},
+						func(r sgmt.Reader) (postings.List, error) {
+							return r.MatchPrefix(f, prefix)
+						})
+
+					termRange, err := index.NewTermRange(prefix, []byte(term), true, true, false)
+					require.NoError(t, err)
+					assertMatch(termRange.String(), termRange.Contains,
+						func(r sgmt.Reader) (postings.List, error) {
+							return r.MatchRange(f, termRange)
+						})
+
+					upper := bytes.ToUpper([]byte(term))
+					assertMatch("case insensitive "+string(upper),
+						func(t []byte) bool {
+							return bytes.EqualFold(t, upper)
+						},
+						func(r sgmt.Reader) (postings.List, error) {
+							return r.MatchTermCaseInsensitive(f, upper)
+						})
+				}
+			}
+		})
+	}
+}
+
 func TestPostingsListContainsID(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -758,6 +838,16 @@ func toSlice(t *testing.T, iter sgmt.OrderedBytesIterator) [][]byte {
 
 type termPostings map[string][]int
 
+func toSortedIDs(pl postings.List) []int {
+	values := []int{}
+	it := pl.Iterator()
+	for it.Next() {
+		values = append(values, int(it.Current()))
+	}
+	sort.Sort(sort.IntSlice(values))
+	return values
+}
+
 func toTermPostings(t *testing.T, iter sgmt.TermsIterator) termPostings {
 	elems := make(termPostings)
 	for iter.Next() {
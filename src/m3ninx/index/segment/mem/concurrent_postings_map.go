@@ -100,6 +100,12 @@ func (m *concurrentPostingsMap) Get(key []byte) (postings.List, bool) {
 // GetRegex returns the union of the postings lists whose keys match the
 // provided regexp.
 func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool) {
+	return m.GetMatching(re.Match)
+}
+
+// GetMatching returns the union of the postings lists whose keys match the
+// provided predicate.
+func (m *concurrentPostingsMap) GetMatching(matchFn func(key []byte) bool) (postings.List, bool) {
 	var pl postings.MutableList
 
 	m.RLock()
@@ -107,7 +113,7 @@ func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool
 		// TODO: Evaluate lock contention caused by holding on to the read lock while
 		// evaluating this predicate.
 		// TODO: Evaluate if performing a prefix match would speed up the common case.
-		if re.Match(mapEntry.Key()) {
+		if matchFn(mapEntry.Key()) {
 			if pl == nil {
 				pl = mapEntry.Value().Clone()
 			} else {
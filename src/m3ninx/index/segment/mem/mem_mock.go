@@ -162,3 +162,18 @@ func (mr *MockReadableSegmentMockRecorder) matchTerm(arg0, arg1 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTerm", reflect.TypeOf((*MockReadableSegment)(nil).matchTerm), arg0, arg1)
 }
+
+// matchTerms mocks base method.
+func (m *MockReadableSegment) matchTerms(arg0 []byte, arg1 func([]byte) bool) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "matchTerms", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// matchTerms indicates an expected call of matchTerms.
+func (mr *MockReadableSegmentMockRecorder) matchTerms(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTerms", reflect.TypeOf((*MockReadableSegment)(nil).matchTerms), arg0, arg1)
+}
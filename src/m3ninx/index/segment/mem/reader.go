@@ -21,6 +21,7 @@
 package mem
 
 import (
+	"bytes"
 	"errors"
 	"sync"
 
@@ -113,6 +114,34 @@ func (r *reader) MatchRegexp(field []byte, compiled index.CompiledRegex) (postin
 	return r.segment.matchRegexp(field, compileRE)
 }
 
+func (r *reader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	return r.matchTerms(field, func(term []byte) bool {
+		return bytes.HasPrefix(term, prefix)
+	})
+}
+
+func (r *reader) MatchRange(field []byte, termRange index.TermRange) (postings.List, error) {
+	return r.matchTerms(field, termRange.Contains)
+}
+
+func (r *reader) MatchTermCaseInsensitive(field, term []byte) (postings.List, error) {
+	return r.matchTerms(field, func(t []byte) bool {
+		return bytes.EqualFold(t, term)
+	})
+}
+
+func (r *reader) matchTerms(field []byte, matchFn func(term []byte) bool) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	// As with regexps, the postings list can include IDs greater than the
+	// maximum permitted ID of the reader.
+	return r.segment.matchTerms(field, matchFn)
+}
+
 func (r *reader) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
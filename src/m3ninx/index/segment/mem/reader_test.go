@@ -85,6 +85,64 @@ func TestReaderMatchRegex(t *testing.T) {
 	require.NoError(t, reader.Close())
 }
 
+func TestReaderMatchPrefix(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	maxID := postings.ID(55)
+
+	name, prefix := []byte("apple"), []byte("re")
+	postingsList := roaring.NewPostingsList()
+	require.NoError(t, postingsList.Insert(postings.ID(42)))
+
+	segment := NewMockReadableSegment(mockCtrl)
+	segment.EXPECT().matchTerms(name, gomock.Any()).DoAndReturn(
+		func(_ []byte, matchFn func([]byte) bool) (postings.List, error) {
+			require.True(t, matchFn([]byte("red")))
+			require.True(t, matchFn([]byte("re")))
+			require.False(t, matchFn([]byte("r")))
+			require.False(t, matchFn([]byte("green")))
+			return postingsList, nil
+		})
+
+	reader := newReader(segment, readerDocRange{0, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	actual, err := reader.MatchPrefix(name, prefix)
+	require.NoError(t, err)
+	require.True(t, postingsList.Equal(actual))
+
+	require.NoError(t, reader.Close())
+}
+
+func TestReaderMatchTermCaseInsensitive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	maxID := postings.ID(55)
+
+	name, term := []byte("apple"), []byte("Red")
+	postingsList := roaring.NewPostingsList()
+	require.NoError(t, postingsList.Insert(postings.ID(42)))
+
+	segment := NewMockReadableSegment(mockCtrl)
+	segment.EXPECT().matchTerms(name, gomock.Any()).DoAndReturn(
+		func(_ []byte, matchFn func([]byte) bool) (postings.List, error) {
+			require.True(t, matchFn([]byte("red")))
+			require.True(t, matchFn([]byte("RED")))
+			require.False(t, matchFn([]byte("reds")))
+			return postingsList, nil
+		})
+
+	reader := newReader(segment, readerDocRange{0, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	actual, err := reader.MatchTermCaseInsensitive(name, term)
+	require.NoError(t, err)
+	require.True(t, postingsList.Equal(actual))
+
+	require.NoError(t, reader.Close())
+
+	_, err = reader.MatchTermCaseInsensitive(name, term)
+	require.Error(t, err)
+}
+
 func TestReaderMatchAll(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
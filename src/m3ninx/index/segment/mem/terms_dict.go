@@ -151,6 +151,23 @@ func (d *termsDict) MatchRegexp(
 	return pl
 }
 
+func (d *termsDict) MatchTerms(
+	field []byte,
+	matchFn func(term []byte) bool,
+) postings.List {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	pl, ok := postingsMap.GetMatching(matchFn)
+	if !ok {
+		return d.opts.PostingsListPool().Get()
+	}
+	return pl
+}
+
 func (d *termsDict) Reset() {
 	d.fields.Lock()
 	defer d.fields.Unlock()
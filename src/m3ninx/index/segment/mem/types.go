@@ -49,6 +49,10 @@ type termsDictionary interface {
 	// given egular expression.
 	MatchRegexp(field []byte, compiled *re.Regexp) postings.List
 
+	// MatchTerms returns the postings list corresponding to documents which match
+	// a term of the given field matched by the predicate.
+	MatchTerms(field []byte, matchFn func(term []byte) bool) postings.List
+
 	// Fields returns the known fields.
 	Fields() sgmt.FieldsIterator
 
@@ -75,5 +79,6 @@ type ReadableSegment interface {
 	FieldsPostingsList() (sgmt.FieldsPostingsListIterator, error)
 	matchTerm(field, term []byte) (postings.List, error)
 	matchRegexp(field []byte, compiled *re.Regexp) (postings.List, error)
+	matchTerms(field []byte, matchFn func(term []byte) bool) (postings.List, error)
 	getDoc(id postings.ID) (doc.Metadata, error)
 }
@@ -315,6 +315,36 @@ func (mr *MockReaderMockRecorder) MatchField(field interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchField", reflect.TypeOf((*MockReader)(nil).MatchField), field)
 }
 
+// MatchPrefix mocks base method.
+func (m *MockReader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchPrefix", field, prefix)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPrefix indicates an expected call of MatchPrefix.
+func (mr *MockReaderMockRecorder) MatchPrefix(field, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPrefix", reflect.TypeOf((*MockReader)(nil).MatchPrefix), field, prefix)
+}
+
+// MatchRange mocks base method.
+func (m *MockReader) MatchRange(field []byte, r index.TermRange) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchRange", field, r)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchRange indicates an expected call of MatchRange.
+func (mr *MockReaderMockRecorder) MatchRange(field, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchRange", reflect.TypeOf((*MockReader)(nil).MatchRange), field, r)
+}
+
 // MatchRegexp mocks base method.
 func (m *MockReader) MatchRegexp(field []byte, c index.CompiledRegex) (postings.List, error) {
 	m.ctrl.T.Helper()
@@ -345,6 +375,21 @@ func (mr *MockReaderMockRecorder) MatchTerm(field, term interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerm", reflect.TypeOf((*MockReader)(nil).MatchTerm), field, term)
 }
 
+// MatchTermCaseInsensitive mocks base method.
+func (m *MockReader) MatchTermCaseInsensitive(field, term []byte) (postings.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchTermCaseInsensitive", field, term)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTermCaseInsensitive indicates an expected call of MatchTermCaseInsensitive.
+func (mr *MockReaderMockRecorder) MatchTermCaseInsensitive(field, term interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTermCaseInsensitive", reflect.TypeOf((*MockReader)(nil).MatchTermCaseInsensitive), field, term)
+}
+
 // Metadata mocks base method.
 func (m *MockReader) Metadata(id postings.ID) (doc.Metadata, error) {
 	m.ctrl.T.Helper()
@@ -1817,6 +1862,18 @@ func (mr *MockSegmentsBuilderMockRecorder) Reset() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockSegmentsBuilder)(nil).Reset))
 }
 
+// SetFilter mocks base method.
+func (m *MockSegmentsBuilder) SetFilter(keep DocumentsFilter) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFilter", keep)
+}
+
+// SetFilter indicates an expected call of SetFilter.
+func (mr *MockSegmentsBuilderMockRecorder) SetFilter(keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFilter", reflect.TypeOf((*MockSegmentsBuilder)(nil).SetFilter), keep)
+}
+
 // Terms mocks base method.
 func (m *MockSegmentsBuilder) Terms(field []byte) (TermsIterator, error) {
 	m.ctrl.T.Helper()
@@ -1831,3 +1888,40 @@ func (mr *MockSegmentsBuilderMockRecorder) Terms(field interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terms", reflect.TypeOf((*MockSegmentsBuilder)(nil).Terms), field)
 }
+
+// MockDocumentsFilter is a mock of DocumentsFilter interface.
+type MockDocumentsFilter struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentsFilterMockRecorder
+}
+
+// MockDocumentsFilterMockRecorder is the mock recorder for MockDocumentsFilter.
+type MockDocumentsFilterMockRecorder struct {
+	mock *MockDocumentsFilter
+}
+
+// NewMockDocumentsFilter creates a new mock instance.
+func NewMockDocumentsFilter(ctrl *gomock.Controller) *MockDocumentsFilter {
+	mock := &MockDocumentsFilter{ctrl: ctrl}
+	mock.recorder = &MockDocumentsFilterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentsFilter) EXPECT() *MockDocumentsFilterMockRecorder {
+	return m.recorder
+}
+
+// ContainsDoc mocks base method.
+func (m *MockDocumentsFilter) ContainsDoc(d doc.Metadata) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainsDoc", d)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ContainsDoc indicates an expected call of ContainsDoc.
+func (mr *MockDocumentsFilterMockRecorder) ContainsDoc(d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainsDoc", reflect.TypeOf((*MockDocumentsFilter)(nil).ContainsDoc), d)
+}
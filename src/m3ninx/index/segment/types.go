@@ -220,6 +220,17 @@ type CloseableDocumentsBuilder interface {
 type SegmentsBuilder interface {
 	Builder
 
+	// SetFilter sets a filter for the documents of the segments added, only
+	// documents the filter contains are kept. A nil filter keeps all
+	// documents, the filter is cleared on reset.
+	SetFilter(keep DocumentsFilter)
+
 	// AddSegments adds segments to build from.
 	AddSegments(segments []Segment) error
 }
+
+// DocumentsFilter is a filter for documents.
+type DocumentsFilter interface {
+	// ContainsDoc returns whether the filter contains a document.
+	ContainsDoc(d doc.Metadata) bool
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+var errTermRangeEmpty = errors.New("term range min must not be greater than max")
+
+// TermRange is a range of the terms of a field. Terms are compared
+// lexicographically unless the range is numeric, in which case terms are
+// compared as floating point numbers and terms which are not numbers are never
+// within the range.
+type TermRange struct {
+	// Min is the lower bound of the range, the range has no lower bound if nil.
+	Min []byte
+	// Max is the upper bound of the range, the range has no upper bound if nil.
+	Max []byte
+	// MinInclusive is whether terms equal to the lower bound are within the range.
+	MinInclusive bool
+	// MaxInclusive is whether terms equal to the upper bound are within the range.
+	MaxInclusive bool
+	// Numeric is whether terms are compared as numbers.
+	Numeric bool
+
+	minValue float64
+	maxValue float64
+}
+
+// NewTermRange returns a new term range, numeric bounds must be numbers.
+func NewTermRange(
+	min, max []byte,
+	minInclusive, maxInclusive bool,
+	numeric bool,
+) (TermRange, error) {
+	r := TermRange{
+		Min:          min,
+		Max:          max,
+		MinInclusive: minInclusive,
+		MaxInclusive: maxInclusive,
+		Numeric:      numeric,
+	}
+	if !numeric {
+		if min != nil && max != nil && bytes.Compare(min, max) > 0 {
+			return TermRange{}, errTermRangeEmpty
+		}
+		return r, nil
+	}
+
+	var err error
+	if min != nil {
+		if r.minValue, err = strconv.ParseFloat(string(min), 64); err != nil {
+			return TermRange{}, fmt.Errorf("invalid numeric term range min: %v", err)
+		}
+	}
+	if max != nil {
+		if r.maxValue, err = strconv.ParseFloat(string(max), 64); err != nil {
+			return TermRange{}, fmt.Errorf("invalid numeric term range max: %v", err)
+		}
+	}
+	if min != nil && max != nil && r.minValue > r.maxValue {
+		return TermRange{}, errTermRangeEmpty
+	}
+	return r, nil
+}
+
+// Contains returns whether a term is within the range.
+func (r TermRange) Contains(term []byte) bool {
+	if r.Numeric {
+		v, err := strconv.ParseFloat(string(term), 64)
+		if err != nil {
+			return false
+		}
+		if r.Min != nil && (v < r.minValue || (v == r.minValue && !r.MinInclusive)) {
+			return false
+		}
+		return r.Max == nil || v < r.maxValue || (v == r.maxValue && r.MaxInclusive)
+	}
+
+	if r.Min != nil {
+		if c := bytes.Compare(term, r.Min); c < 0 || (c == 0 && !r.MinInclusive) {
+			return false
+		}
+	}
+	if r.Max != nil {
+		if c := bytes.Compare(term, r.Max); c > 0 || (c == 0 && !r.MaxInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the lexicographic bounds of the terms within the range, a nil
+// bound is unbounded. Terms within the bounds must still be checked with
+// Contains since the bounds of an exclusive range or of a numeric range,
+// which is unbounded, include terms not within the range.
+func (r TermRange) Bounds() (startInclusive, endExclusive []byte) {
+	if r.Numeric {
+		return nil, nil
+	}
+	if r.Max != nil {
+		endExclusive = r.Max
+		if r.MaxInclusive {
+			// The smallest term greater than the upper bound.
+			endExclusive = append(append(make([]byte, 0, len(r.Max)+1), r.Max...), 0)
+		}
+	}
+	return r.Min, endExclusive
+}
+
+// Equal returns whether the range is equal to another range.
+func (r TermRange) Equal(o TermRange) bool {
+	return bytes.Equal(r.Min, o.Min) && bytes.Equal(r.Max, o.Max) &&
+		r.MinInclusive == o.MinInclusive && r.MaxInclusive == o.MaxInclusive &&
+		r.Numeric == o.Numeric
+}
+
+func (r TermRange) String() string {
+	var b bytes.Buffer
+	if r.Numeric {
+		b.WriteString("numeric")
+	}
+	if r.MinInclusive {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	writeTermRangeBound(&b, r.Min)
+	b.WriteString(", ")
+	writeTermRangeBound(&b, r.Max)
+	if r.MaxInclusive {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+func writeTermRangeBound(b *bytes.Buffer, bound []byte) {
+	if bound == nil {
+		b.WriteByte('*')
+		return
+	}
+	b.WriteString(strconv.Quote(string(bound)))
+}
+
+// PrefixEnd returns the smallest term greater than every term starting with
+// the prefix, or nil if there is no such term.
+func PrefixEnd(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			end := append(make([]byte, 0, i+1), prefix[:i+1]...)
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
+
+// CaseInsensitiveBounds returns the lexicographic bounds of the terms equal to
+// a term ignoring case. Only ASCII terms can be bounded since the case
+// variants of other characters may not be ordered between their upper and
+// lower case, false is returned if the term cannot be bounded.
+func CaseInsensitiveBounds(term []byte) (startInclusive, endExclusive []byte, ok bool) {
+	for _, c := range term {
+		if c >= utf8.RuneSelf {
+			return nil, nil, false
+		}
+	}
+	// Upper case ASCII letters sort before lower case ones so the upper case
+	// term is the smallest case variant and the lower case term the largest.
+	startInclusive = bytes.ToUpper(term)
+	endExclusive = append(bytes.ToLower(term), 0)
+	return startInclusive, endExclusive, true
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTermRangeErrors(t *testing.T) {
+	_, err := NewTermRange([]byte("b"), []byte("a"), true, true, false)
+	require.Error(t, err)
+
+	_, err = NewTermRange([]byte("10"), []byte("9"), true, true, true)
+	require.Error(t, err)
+
+	_, err = NewTermRange([]byte("abc"), nil, true, true, true)
+	require.Error(t, err)
+
+	_, err = NewTermRange(nil, []byte("abc"), true, true, true)
+	require.Error(t, err)
+
+	// Lexicographically "10" < "9" but numerically it is not.
+	_, err = NewTermRange([]byte("10"), []byte("9"), true, true, false)
+	require.NoError(t, err)
+}
+
+func TestTermRangeContains(t *testing.T) {
+	tests := []struct {
+		name       string
+		min, max   []byte
+		minIncl    bool
+		maxIncl    bool
+		numeric    bool
+		contains   []string
+		notContain []string
+	}{
+		{
+			name:       "lexicographic inclusive",
+			min:        []byte("b"),
+			max:        []byte("d"),
+			minIncl:    true,
+			maxIncl:    true,
+			contains:   []string{"b", "c", "czz", "d"},
+			notContain: []string{"a", "azz", "da", "e"},
+		},
+		{
+			name:       "lexicographic exclusive",
+			min:        []byte("b"),
+			max:        []byte("d"),
+			contains:   []string{"ba", "c", "czz"},
+			notContain: []string{"a", "b", "d", "da"},
+		},
+		{
+			name:       "lexicographic unbounded min",
+			max:        []byte("b"),
+			contains:   []string{"", "a", "azz"},
+			notContain: []string{"b", "c"},
+		},
+		{
+			name:       "lexicographic unbounded max",
+			min:        []byte("b"),
+			minIncl:    true,
+			contains:   []string{"b", "c", "zzz"},
+			notContain: []string{"", "a"},
+		},
+		{
+			name:       "numeric inclusive",
+			min:        []byte("2"),
+			max:        []byte("10"),
+			minIncl:    true,
+			maxIncl:    true,
+			numeric:    true,
+			contains:   []string{"2", "2.0", "3", "9.99", "10", "1e1"},
+			notContain: []string{"1", "10.5", "100", "abc", ""},
+		},
+		{
+			name:       "numeric exclusive",
+			min:        []byte("-1.5"),
+			max:        []byte("10"),
+			numeric:    true,
+			contains:   []string{"-1", "0", "9"},
+			notContain: []string{"-1.5", "-2", "10", "abc"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := NewTermRange(test.min, test.max, test.minIncl, test.maxIncl, test.numeric)
+			require.NoError(t, err)
+
+			start, end := r.Bounds()
+			for _, term := range test.contains {
+				require.True(t, r.Contains([]byte(term)), term)
+				if !test.numeric {
+					// Terms within the range must be within its bounds.
+					require.True(t, start == nil || string(start) <= term, term)
+					require.True(t, end == nil || term < string(end), term)
+				}
+			}
+			for _, term := range test.notContain {
+				require.False(t, r.Contains([]byte(term)), term)
+			}
+		})
+	}
+}
+
+func TestTermRangeString(t *testing.T) {
+	r, err := NewTermRange([]byte("a"), nil, true, false, false)
+	require.NoError(t, err)
+	require.Equal(t, `["a", *)`, r.String())
+
+	r, err = NewTermRange([]byte("1"), []byte("2"), false, true, true)
+	require.NoError(t, err)
+	require.Equal(t, `numeric("1", "2"]`, r.String())
+}
+
+func TestPrefixEnd(t *testing.T) {
+	require.Equal(t, []byte("ab"), PrefixEnd([]byte("aa")))
+	require.Equal(t, []byte("b"), PrefixEnd([]byte("a\xff")))
+	require.Nil(t, PrefixEnd([]byte("\xff\xff")))
+	require.Nil(t, PrefixEnd(nil))
+}
+
+func TestCaseInsensitiveBounds(t *testing.T) {
+	start, end, ok := CaseInsensitiveBounds([]byte("aBc-1"))
+	require.True(t, ok)
+	for _, term := range []string{"abc-1", "ABC-1", "AbC-1", "abC-1"} {
+		require.True(t, string(start) <= term, term)
+		require.True(t, term < string(end), term)
+	}
+
+	_, _, ok = CaseInsensitiveBounds([]byte("straße"))
+	require.False(t, ok)
+}
@@ -74,6 +74,18 @@ type Readable interface {
 	// regular expression.
 	MatchRegexp(field []byte, c CompiledRegex) (postings.List, error)
 
+	// MatchPrefix returns a postings list over all documents which match a term
+	// starting with the given prefix.
+	MatchPrefix(field, prefix []byte) (postings.List, error)
+
+	// MatchRange returns a postings list over all documents which match a term
+	// within the given range.
+	MatchRange(field []byte, r TermRange) (postings.List, error)
+
+	// MatchTermCaseInsensitive returns a postings list over all documents which
+	// match the given term ignoring case.
+	MatchTermCaseInsensitive(field, term []byte) (postings.List, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.MutableList, error)
 
@@ -25,6 +25,7 @@ import (
 	"fmt"
 
 	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/search"
 )
 
@@ -63,6 +64,21 @@ func unmarshal(q *querypb.Query) (search.Query, error) {
 	case *querypb.Query_Regexp:
 		return NewRegexpQuery(q.Regexp.Field, q.Regexp.Regexp)
 
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_Range:
+		termRange, err := index.NewTermRange(q.Range.Min, q.Range.Max,
+			q.Range.MinInclusive, q.Range.MaxInclusive, q.Range.Numeric)
+		if err != nil {
+			return nil, err
+		}
+		return NewRangeQuery(q.Range.Field, termRange), nil
+
+	case *querypb.Query_CaseInsensitiveTerm:
+		return NewCaseInsensitiveTermQuery(q.CaseInsensitiveTerm.Field,
+			q.CaseInsensitiveTerm.Term), nil
+
 	case *querypb.Query_Negation:
 		inner, err := unmarshal(q.Negation.Query)
 		if err != nil {
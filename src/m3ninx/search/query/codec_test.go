@@ -23,6 +23,7 @@ package query
 import (
 	"testing"
 
+	"github.com/m3db/m3/src/m3ninx/index"
 	"github.com/m3db/m3/src/m3ninx/search"
 
 	"github.com/stretchr/testify/require"
@@ -49,6 +50,22 @@ func TestMarshal(t *testing.T) {
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "range query",
+			query: NewRangeQuery([]byte("fruit"), mustNewTermRange([]byte("apple"), nil, true, false, false)),
+		},
+		{
+			name:  "numeric range query",
+			query: NewRangeQuery([]byte("weight"), mustNewTermRange([]byte("1"), []byte("2.5"), false, true, true)),
+		},
+		{
+			name:  "case insensitive term query",
+			query: NewCaseInsensitiveTermQuery([]byte("fruit"), []byte("Apple")),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
@@ -114,3 +131,11 @@ func TestMarshalError(t *testing.T) {
 		})
 	}
 }
+
+func mustNewTermRange(min, max []byte, minInclusive, maxInclusive, numeric bool) index.TermRange {
+	r, err := index.NewTermRange(min, max, minInclusive, maxInclusive, numeric)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/m3db/m3/src/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3/src/m3ninx/index"
+	"github.com/m3db/m3/src/m3ninx/search"
+	"github.com/m3db/m3/src/m3ninx/search/searcher"
+)
+
+// RangeQuery finds documents which have a term within the given range.
+type RangeQuery struct {
+	field     []byte
+	termRange index.TermRange
+}
+
+// NewRangeQuery constructs a new RangeQuery for the given field and range.
+func NewRangeQuery(field []byte, termRange index.TermRange) search.Query {
+	return &RangeQuery{
+		field:     field,
+		termRange: termRange,
+	}
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *RangeQuery) Searcher() (search.Searcher, error) {
+	return searcher.NewRangeSearcher(q.field, q.termRange), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *RangeQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*RangeQuery)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(q.field, inner.field) && q.termRange.Equal(inner.termRange)
+}
+
+// ToProto returns the Protobuf query struct corresponding to the range query.
+func (q *RangeQuery) ToProto() *querypb.Query {
+	termRange := querypb.RangeQuery{
+		Field:        q.field,
+		Min:          q.termRange.Min,
+		Max:          q.termRange.Max,
+		MinInclusive: q.termRange.MinInclusive,
+		MaxInclusive: q.termRange.MaxInclusive,
+		Numeric:      q.termRange.Numeric,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Range{Range: &termRange},
+	}
+}
+
+func (q *RangeQuery) String() string {
+	return fmt.Sprintf("range(%s, %s)", q.field, q.termRange)
+}
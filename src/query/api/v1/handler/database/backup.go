@@ -0,0 +1,212 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placementhandler/handleroptions"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/dbnode/backup"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// BackupURL is the url to request and inspect cluster-wide backups.
+	BackupURL = route.Prefix + "/database/backup"
+)
+
+var errNoBackupRequested = xhttp.NewError(
+	errors.New("no backup has been requested"), http.StatusNotFound)
+
+// BackupRequest is a request to back up the database.
+type BackupRequest struct {
+	// ID identifies the backup and names its directory on every node.
+	ID string `json:"id"`
+	// Namespaces are the namespaces to back up, all if empty.
+	Namespaces []string `json:"namespaces"`
+}
+
+// BackupStatus is the status of the latest requested backup.
+type BackupStatus struct {
+	// ID identifies the backup.
+	ID string `json:"id"`
+	// Namespaces are the namespaces being backed up, all if empty.
+	Namespaces []string `json:"namespaces"`
+	// RequestedAt is the time the backup was requested at.
+	RequestedAt time.Time `json:"requestedAt"`
+	// Hosts is the status of the backup of each host of the placement.
+	Hosts []BackupHostStatus `json:"hosts"`
+	// Completed is whether every host has completed the backup.
+	Completed bool `json:"completed"`
+}
+
+// BackupHostStatus is the status of the backup of a host.
+type BackupHostStatus struct {
+	// ID is the ID of the host.
+	ID string `json:"id"`
+	// Completed is whether the host has completed the backup.
+	Completed bool `json:"completed"`
+}
+
+// BackupHandler requests cluster-wide backups and reports their status.
+type BackupHandler struct {
+	client         clusterclient.Client
+	defaults       []handleroptions.ServiceOptionsDefault
+	instrumentOpts instrument.Options
+	nowFn          func() time.Time
+}
+
+// NewBackupHandler returns a new instance of a backup handler.
+func NewBackupHandler(
+	client clusterclient.Client,
+	defaults []handleroptions.ServiceOptionsDefault,
+	instrumentOpts instrument.Options,
+) http.Handler {
+	return &BackupHandler{
+		client:         client,
+		defaults:       defaults,
+		instrumentOpts: instrumentOpts,
+		nowFn:          time.Now,
+	}
+}
+
+func (h *BackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	store, err := h.client.KV()
+	if err != nil {
+		logger.Error("unable to get kv store", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		req, err := h.parseBody(r)
+		if err != nil {
+			logger.Error("unable to parse request", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+		if _, err := backup.SetRequest(store, req); err != nil {
+			logger.Error("unable to request backup", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+		logger.Info("backup requested", zap.String("id", req.ID),
+			zap.Strings("namespaces", req.Namespaces))
+	}
+
+	opts := handleroptions.NewServiceOptions(handleroptions.ServiceNameAndDefaults{
+		ServiceName: handleroptions.M3DBServiceName,
+		Defaults:    h.defaults,
+	}, r.Header, nil)
+	status, err := h.status(store, opts)
+	if err != nil {
+		logger.Error("unable to get backup status", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, status, logger)
+}
+
+func (h *BackupHandler) parseBody(r *http.Request) (backup.Request, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return backup.Request{}, xerrors.NewInvalidParamsError(err)
+	}
+	defer r.Body.Close()
+
+	var parsed BackupRequest
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return backup.Request{}, xerrors.NewInvalidParamsError(err)
+	}
+
+	req := backup.Request{
+		ID:          parsed.ID,
+		Namespaces:  parsed.Namespaces,
+		RequestedAt: h.nowFn(),
+	}
+	if err := req.Validate(); err != nil {
+		return backup.Request{}, xerrors.NewInvalidParamsError(err)
+	}
+	return req, nil
+}
+
+func (h *BackupHandler) status(
+	store kv.Store,
+	opts handleroptions.ServiceOptions,
+) (BackupStatus, error) {
+	req, ok, err := backup.PendingRequest(store)
+	if err != nil {
+		return BackupStatus{}, err
+	}
+	if !ok {
+		return BackupStatus{}, errNoBackupRequested
+	}
+
+	svcs, err := h.client.Services(services.NewOverrideOptions())
+	if err != nil {
+		return BackupStatus{}, err
+	}
+	ps, err := svcs.PlacementService(opts.ServiceID(), placement.NewOptions())
+	if err != nil {
+		return BackupStatus{}, err
+	}
+	p, err := ps.Placement()
+	if err != nil {
+		return BackupStatus{}, fmt.Errorf("unable to get placement: %w", err)
+	}
+
+	status := BackupStatus{
+		ID:          req.ID,
+		Namespaces:  req.Namespaces,
+		RequestedAt: req.RequestedAt,
+		Completed:   true,
+	}
+	for _, instance := range p.Instances() {
+		completed, err := backup.Completed(store, req.ID, instance.ID())
+		if err != nil {
+			return BackupStatus{}, err
+		}
+		status.Hosts = append(status.Hosts, BackupHostStatus{
+			ID:        instance.ID(),
+			Completed: completed,
+		})
+		status.Completed = status.Completed && completed
+	}
+	return status, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package database
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/dbnode/backup"
+	"github.com/m3db/m3/src/x/instrument"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mem.NewStore()
+	p := placement.NewPlacement().SetInstances([]placement.Instance{
+		placement.NewInstance().SetID("host0"),
+		placement.NewInstance().SetID("host1"),
+	})
+	mockPlacementService := placement.NewMockService(ctrl)
+	mockPlacementService.EXPECT().Placement().Return(p, nil).AnyTimes()
+	mockServices := services.NewMockServices(ctrl)
+	mockServices.EXPECT().PlacementService(gomock.Any(), gomock.Any()).
+		Return(mockPlacementService, nil).AnyTimes()
+	mockClient := client.NewMockClient(ctrl)
+	mockClient.EXPECT().KV().Return(store, nil).AnyTimes()
+	mockClient.EXPECT().Services(gomock.Any()).Return(mockServices, nil).AnyTimes()
+
+	handler := NewBackupHandler(mockClient, nil, instrument.NewOptions()).(*BackupHandler)
+	now := time.Unix(1000, 0)
+	handler.nowFn = func() time.Time { return now }
+
+	serve := func(method, body string) (int, BackupStatus) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, BackupURL, strings.NewReader(body))
+		handler.ServeHTTP(w, req)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		var status BackupStatus
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		}
+		return resp.StatusCode, status
+	}
+
+	code, _ := serve(http.MethodGet, "")
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, _ = serve(http.MethodPost, `{"id": "../b1"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, status := serve(http.MethodPost, `{"id": "b1", "namespaces": ["metrics"]}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "b1", status.ID)
+	require.Equal(t, []string{"metrics"}, status.Namespaces)
+	require.True(t, now.Equal(status.RequestedAt))
+	require.False(t, status.Completed)
+	require.Equal(t, []BackupHostStatus{
+		{ID: "host0"},
+		{ID: "host1"},
+	}, status.Hosts)
+
+	require.NoError(t, backup.NewKVCoordinator(store, "host0").ReportCompleted("b1"))
+	code, status = serve(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, status.Completed)
+	require.True(t, status.Hosts[0].Completed)
+
+	require.NoError(t, backup.NewKVCoordinator(store, "host1").ReportCompleted("b1"))
+	code, status = serve(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, status.Completed)
+}
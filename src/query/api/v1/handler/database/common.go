@@ -22,6 +22,8 @@
 package database
 
 import (
+	"net/http"
+
 	clusterclient "github.com/m3db/m3/src/cluster/client"
 	"github.com/m3db/m3/src/cluster/placementhandler/handleroptions"
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
@@ -57,6 +59,7 @@ func RegisterRoutes(
 	}
 
 	kvStoreHandler := NewKeyValueStoreHandler(client, instrumentOpts, kvStoreProtoParser)
+	backupHandler := NewBackupHandler(client, defaults, instrumentOpts)
 
 	// Register the same handler under two different endpoints. This just makes explaining things in
 	// our documentation easier so we can separate out concepts, but share the underlying code.
@@ -81,6 +84,13 @@ func RegisterRoutes(
 	}); err != nil {
 		return err
 	}
+	if err := r.Register(queryhttp.RegisterOptions{
+		Path:    BackupURL,
+		Handler: backupHandler,
+		Methods: []string{http.MethodGet, http.MethodPost},
+	}); err != nil {
+		return err
+	}
 
 	return nil
 }
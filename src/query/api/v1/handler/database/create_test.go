@@ -160,7 +160,8 @@ func testLocalType(t *testing.T, providedType string, placementExists bool) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -185,6 +186,9 @@ func testLocalType(t *testing.T, providedType string, placementExists bool) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -336,7 +340,8 @@ func TestLocalTypeWithNumShards(t *testing.T) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -361,6 +366,9 @@ func TestLocalTypeWithNumShards(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -465,7 +473,8 @@ func TestLocalWithBlockSizeNanos(t *testing.T) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -490,6 +499,9 @@ func TestLocalWithBlockSizeNanos(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -599,7 +611,8 @@ func TestLocalWithBlockSizeExpectedSeriesDatapointsPerHour(t *testing.T) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -624,6 +637,9 @@ func TestLocalWithBlockSizeExpectedSeriesDatapointsPerHour(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -863,7 +879,8 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -888,6 +905,9 @@ func testClusterTypeHosts(t *testing.T, placementExists bool) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -1020,7 +1040,8 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -1045,6 +1066,9 @@ func TestClusterTypeHostsWithIsolationGroup(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -1239,7 +1263,8 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 									"aggregated": false,
 									"attributes": null
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -1264,6 +1289,9 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -1281,7 +1309,8 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 										}
 									}
 								}
-							]
+							],
+							"rollupPolicy": null
 						},
 						"bootstrapEnabled": true,
 						"cacheBlocksOnRetrieve": false,
@@ -1306,6 +1335,9 @@ func TestLocalTypeWithAggregatedNamespace(t *testing.T) {
 						"runtimeOptions": null,
 						"schemaOptions": null,
 						"coldWritesEnabled": false,
+						"encodingScheme": "M3TSZ",
+						"resizeState": null,
+						"lateWritesEnabled": false,
 						"extendedOptions": null,
 						"stagingState": {
 							"status": "UNKNOWN"
@@ -0,0 +1,339 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	graphitestorage "github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	"github.com/m3db/m3/src/query/util/json"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TagsURL is the url for listing graphite tags.
+	TagsURL = route.Prefix + "/graphite/tags"
+	// TagsAutoCompleteTagsURL is the url for auto completing graphite tags.
+	TagsAutoCompleteTagsURL = TagsURL + "/autoComplete/tags"
+	// TagsAutoCompleteValuesURL is the url for auto completing graphite tag
+	// values.
+	TagsAutoCompleteValuesURL = TagsURL + "/autoComplete/values"
+
+	// NB: this matches the default auto complete limit of graphite-web.
+	defaultAutoCompleteLimit = 100
+)
+
+// TagsHTTPMethods are the HTTP methods for the tags handlers.
+var TagsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+var errAutoCompleteNameValues = xerrors.NewInvalidParamsError(
+	errors.New("auto completing values of the name tag is not supported"))
+
+type tagsRequestType uint
+
+const (
+	tagsRequest tagsRequestType = iota
+	autoCompleteTagsRequest
+	autoCompleteValuesRequest
+)
+
+type graphiteTagsHandler struct {
+	requestType         tagsRequestType
+	storage             graphitestorage.Storage
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	instrumentOpts      instrument.Options
+}
+
+// NewTagsHandler returns a new instance of the handler listing tags.
+func NewTagsHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(opts, tagsRequest)
+}
+
+// NewTagsAutoCompleteTagsHandler returns a new instance of the handler auto
+// completing tags.
+func NewTagsAutoCompleteTagsHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(opts, autoCompleteTagsRequest)
+}
+
+// NewTagsAutoCompleteValuesHandler returns a new instance of the handler auto
+// completing tag values.
+func NewTagsAutoCompleteValuesHandler(opts options.HandlerOptions) http.Handler {
+	return newTagsHandler(opts, autoCompleteValuesRequest)
+}
+
+func newTagsHandler(
+	opts options.HandlerOptions,
+	requestType tagsRequestType,
+) http.Handler {
+	wrappedStore := graphitestorage.NewM3WrappedStorage(opts.Storage(),
+		opts.M3DBOptions(), opts.InstrumentOpts(), opts.GraphiteStorageOptions())
+	return &graphiteTagsHandler{
+		requestType:         requestType,
+		storage:             wrappedStore,
+		fetchOptionsBuilder: opts.GraphiteFindFetchOptionsBuilder(),
+		instrumentOpts:      opts.InstrumentOpts(),
+	}
+}
+
+func (h *graphiteTagsHandler) ServeHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	ctx, opts, err := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	query, params, err := parseTagsParamsToQuery(r, h.requestType)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	result, err := h.storage.CompleteTags(ctx, query, opts)
+	if err != nil {
+		logger.Error("unable to complete tags", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	err = handleroptions.AddDBResultResponseHeaders(w, result.Metadata, opts)
+	if err != nil {
+		logger.Error("unable to render tags header", zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	if h.requestType == autoCompleteValuesRequest {
+		err = tagsResultsJSON(w, params.filterValues(result), false)
+	} else {
+		err = tagsResultsJSON(w, params.filterNames(result),
+			h.requestType == tagsRequest)
+	}
+
+	if err != nil {
+		logger.Error("unable to render tags results", zap.Error(err))
+	}
+}
+
+// tagsParams are the parameters used to filter the results of a tags query.
+type tagsParams struct {
+	filter      *regexp.Regexp
+	prefix      string
+	excludeTags map[string]struct{}
+	limit       int
+}
+
+// parseTagsParamsToQuery parses an incoming tags request to a complete tags
+// query, along with the parameters used to filter its results. Tag
+// expressions given by `expr` restrict the query to the matching series,
+// otherwise all graphite series are considered.
+func parseTagsParamsToQuery(
+	r *http.Request,
+	requestType tagsRequestType,
+) (*storage.CompleteTagsQuery, tagsParams, error) {
+	var params tagsParams
+	if err := r.ParseForm(); err != nil {
+		return nil, params, xerrors.NewInvalidParamsError(err)
+	}
+
+	from, until, err := parseFromUntil(r)
+	if err != nil {
+		return nil, params, err
+	}
+
+	if requestType != tagsRequest {
+		params.limit = defaultAutoCompleteLimit
+	}
+
+	if limit := r.FormValue("limit"); limit != "" {
+		params.limit, err = strconv.Atoi(limit)
+		if err != nil || params.limit < 0 {
+			return nil, params, xerrors.NewInvalidParamsError(
+				fmt.Errorf("invalid 'limit': %s", limit))
+		}
+	}
+
+	query := &storage.CompleteTagsQuery{
+		CompleteNameOnly: requestType != autoCompleteValuesRequest,
+		Start:            xtime.ToUnixNano(from),
+		End:              xtime.ToUnixNano(until),
+	}
+
+	switch requestType {
+	case tagsRequest:
+		if filter := r.FormValue("filter"); filter != "" {
+			// NB: graphite tag filters are only anchored at the start.
+			params.filter, err = regexp.Compile("^(?:" + filter + ")")
+			if err != nil {
+				return nil, params, xerrors.NewInvalidParamsError(
+					fmt.Errorf("invalid 'filter': %s", filter))
+			}
+		}
+	case autoCompleteTagsRequest:
+		params.prefix = r.FormValue("tagPrefix")
+	case autoCompleteValuesRequest:
+		tag := r.FormValue("tag")
+		if tag == "" {
+			return nil, params, xerrors.NewInvalidParamsError(
+				errors.New("missing 'tag'"))
+		}
+
+		if tag == graphite.NameTag {
+			return nil, params, errAutoCompleteNameValues
+		}
+
+		params.prefix = r.FormValue("valuePrefix")
+		query.FilterNameTags = [][]byte{[]byte(tag)}
+	}
+
+	exprs := r.Form["expr"]
+	if len(exprs) == 0 {
+		query.TagMatchers = models.Matchers{
+			{Type: models.MatchField, Name: graphite.TagName(0)},
+		}
+
+		return query, params, nil
+	}
+
+	query.TagMatchers, err = graphitestorage.TranslateTagExpressionsToMatchers(exprs)
+	if err != nil {
+		return nil, params, err
+	}
+
+	// NB: tags which are already part of the expressions are not suggested.
+	if requestType == autoCompleteTagsRequest {
+		params.excludeTags = make(map[string]struct{}, len(exprs))
+		for _, expr := range exprs {
+			if idx := strings.IndexAny(expr, "!="); idx > 0 {
+				params.excludeTags[expr[:idx]] = struct{}{}
+			}
+		}
+	}
+
+	return query, params, nil
+}
+
+// filterNames returns the sorted tag names of the result, where any graphite
+// path tags are reported as the name tag.
+func (p tagsParams) filterNames(result *consolidators.CompleteTagsResult) []string {
+	names := make([]string, 0, len(result.CompletedTags))
+	seenName := false
+	for _, tag := range result.CompletedTags {
+		name := string(tag.Name)
+		if _, ok := graphite.TagIndex(tag.Name); ok {
+			if seenName {
+				continue
+			}
+
+			seenName = true
+			name = graphite.NameTag
+		}
+
+		names = append(names, name)
+	}
+
+	return p.filterAndLimit(names)
+}
+
+// filterValues returns the sorted tag values of the result.
+func (p tagsParams) filterValues(result *consolidators.CompleteTagsResult) []string {
+	var values []string
+	for _, tag := range result.CompletedTags {
+		for _, value := range tag.Values {
+			values = append(values, string(value))
+		}
+	}
+
+	return p.filterAndLimit(values)
+}
+
+func (p tagsParams) filterAndLimit(values []string) []string {
+	filtered := values[:0]
+	for _, value := range values {
+		if !strings.HasPrefix(value, p.prefix) {
+			continue
+		}
+
+		if p.filter != nil && !p.filter.MatchString(value) {
+			continue
+		}
+
+		if _, ok := p.excludeTags[value]; ok {
+			continue
+		}
+
+		filtered = append(filtered, value)
+	}
+
+	sort.Strings(filtered)
+	if p.limit > 0 && len(filtered) > p.limit {
+		filtered = filtered[:p.limit]
+	}
+
+	return filtered
+}
+
+// tagsResultsJSON writes the results either as a list of strings, as used by
+// auto completion, or as a list of tag objects.
+func tagsResultsJSON(w io.Writer, values []string, asTagObjects bool) error {
+	jw := json.NewWriter(w)
+	jw.BeginArray()
+
+	for _, value := range values {
+		if !asTagObjects {
+			jw.WriteString(value)
+			continue
+		}
+
+		jw.BeginObject()
+		jw.BeginObjectField("tag")
+		jw.WriteString(value)
+		jw.EndObject()
+	}
+
+	jw.EndArray()
+	return jw.Close()
+}
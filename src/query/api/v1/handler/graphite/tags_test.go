@@ -0,0 +1,203 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3/consolidators"
+	xtest "github.com/m3db/m3/src/x/test"
+)
+
+type testTagsQuery struct {
+	params         url.Values
+	expectedQuery  storage.CompleteTagsQuery
+	mockResult     consolidators.CompleteTagsResult
+	expectedResult string
+}
+
+func testTags(t *testing.T, handler func(options.HandlerOptions) http.Handler, test testTagsQuery) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		CompleteTags(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			query *storage.CompleteTagsQuery,
+			_ *storage.FetchOptions,
+		) (*consolidators.CompleteTagsResult, error) {
+			assert.Equal(t, test.expectedQuery, *query)
+			return &test.mockResult, nil
+		})
+
+	builder, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{
+			Timeout: 15 * time.Second,
+		})
+	require.NoError(t, err)
+
+	h := handler(options.EmptyHandlerOptions().
+		SetGraphiteFindFetchOptionsBuilder(builder).
+		SetStorage(store))
+
+	test.params.Set("from", from.s)
+	test.params.Set("until", until.s)
+
+	w := &writer{}
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{RawQuery: test.params.Encode()},
+	}
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, 1, len(w.results))
+	assert.Equal(t, test.expectedResult, strings.TrimSpace(w.results[0]))
+}
+
+func TestTags(t *testing.T) {
+	testTags(t, NewTagsHandler, testTagsQuery{
+		params: url.Values{"filter": []string{"d|n"}},
+		expectedQuery: storage.CompleteTagsQuery{
+			CompleteNameOnly: true,
+			TagMatchers: models.Matchers{
+				{Type: models.MatchField, Name: graphite.TagName(0)},
+			},
+			Start: from.t,
+			End:   until.t,
+		},
+		mockResult: consolidators.CompleteTagsResult{
+			CompleteNameOnly: true,
+			CompletedTags: []consolidators.CompletedTag{
+				{Name: b("__g0__")},
+				{Name: b("__g1__")},
+				{Name: b("host")},
+				{Name: b("dc")},
+			},
+			Metadata: block.NewResultMetadata(),
+		},
+		expectedResult: `[{"tag":"dc"},{"tag":"name"}]`,
+	})
+}
+
+func TestTagsAutoCompleteTags(t *testing.T) {
+	testTags(t, NewTagsAutoCompleteTagsHandler, testTagsQuery{
+		params: url.Values{
+			"expr":      []string{"name=cpu", "dc!=us-west"},
+			"tagPrefix": []string{"d"},
+		},
+		expectedQuery: storage.CompleteTagsQuery{
+			CompleteNameOnly: true,
+			TagMatchers: models.Matchers{
+				{Type: models.MatchEqual, Name: graphite.TagName(0), Value: b("cpu")},
+				{Type: models.MatchNotField, Name: graphite.TagName(1)},
+				{Type: models.MatchNotEqual, Name: b("dc"), Value: b("us-west")},
+			},
+			Start: from.t,
+			End:   until.t,
+		},
+		mockResult: consolidators.CompleteTagsResult{
+			CompleteNameOnly: true,
+			CompletedTags: []consolidators.CompletedTag{
+				{Name: b("__g0__")},
+				{Name: b("disk")},
+				{Name: b("host")},
+				{Name: b("dc")},
+				{Name: b("device")},
+			},
+			Metadata: block.NewResultMetadata(),
+		},
+		expectedResult: `["device","disk"]`,
+	})
+}
+
+func TestTagsAutoCompleteValues(t *testing.T) {
+	testTags(t, NewTagsAutoCompleteValuesHandler, testTagsQuery{
+		params: url.Values{
+			"tag":         []string{"host"},
+			"valuePrefix": []string{"web"},
+			"limit":       []string{"2"},
+		},
+		expectedQuery: storage.CompleteTagsQuery{
+			FilterNameTags: bs("host"),
+			TagMatchers: models.Matchers{
+				{Type: models.MatchField, Name: graphite.TagName(0)},
+			},
+			Start: from.t,
+			End:   until.t,
+		},
+		mockResult: consolidators.CompleteTagsResult{
+			CompletedTags: []consolidators.CompletedTag{
+				{Name: b("host"), Values: bs("web03", "db01", "web01", "web02")},
+			},
+			Metadata: block.NewResultMetadata(),
+		},
+		expectedResult: `["web01","web02"]`,
+	})
+}
+
+func TestTagsInvalidParams(t *testing.T) {
+	for _, test := range []struct {
+		handler func(options.HandlerOptions) http.Handler
+		params  url.Values
+	}{
+		{NewTagsHandler, url.Values{"filter": []string{"("}}},
+		{NewTagsHandler, url.Values{"limit": []string{"-1"}}},
+		{NewTagsAutoCompleteTagsHandler, url.Values{"expr": []string{"dc!=a"}}},
+		{NewTagsAutoCompleteValuesHandler, url.Values{}},
+		{NewTagsAutoCompleteValuesHandler, url.Values{"tag": []string{"name"}}},
+	} {
+		builder, err := handleroptions.NewFetchOptionsBuilder(
+			handleroptions.FetchOptionsBuilderOptions{
+				Timeout: 15 * time.Second,
+			})
+		require.NoError(t, err)
+
+		h := test.handler(options.EmptyHandlerOptions().
+			SetGraphiteFindFetchOptionsBuilder(builder))
+
+		w := &writer{}
+		req := &http.Request{
+			Method: http.MethodGet,
+			URL:    &url.URL{RawQuery: test.params.Encode()},
+		}
+		h.ServeHTTP(w, req)
+
+		require.Equal(t, 1, len(w.results), test.params.Encode())
+		assert.Contains(t, w.results[0], "error", test.params.Encode())
+	}
+}
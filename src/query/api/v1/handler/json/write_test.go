@@ -73,7 +73,7 @@ func TestJSONWrite(t *testing.T) {
 
 	storage, session := m3.NewStorageAndSession(t, ctrl)
 	session.EXPECT().
-		WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(),
+		WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		AnyTimes()
 	session.EXPECT().IteratorPools().
@@ -103,7 +103,7 @@ func TestJSONWriteError(t *testing.T) {
 
 	storage, session := m3.NewStorageAndSession(t, ctrl)
 	session.EXPECT().
-		WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(),
+		WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		AnyTimes().
 		Return(expectedErr)
@@ -151,6 +151,9 @@ func TestNamespaceAddHandler(t *testing.T) {
 						"runtimeOptions":    nil,
 						"schemaOptions":     nil,
 						"coldWritesEnabled": false,
+						"encodingScheme":    "M3TSZ",
+						"resizeState":       nil,
+						"lateWritesEnabled": false,
 						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
 					},
 				},
@@ -127,6 +127,9 @@ func TestNamespaceGetHandler(t *testing.T) {
 						"cacheBlocksOnRetrieve": nil,
 						"cleanupEnabled":        false,
 						"coldWritesEnabled":     false,
+						"encodingScheme":        "M3TSZ",
+						"resizeState":           nil,
+						"lateWritesEnabled":     false,
 						"flushEnabled":          true,
 						"indexOptions":          nil,
 						"repairEnabled":         false,
@@ -212,6 +215,9 @@ func TestNamespaceGetHandlerWithDebug(t *testing.T) {
 						"cacheBlocksOnRetrieve": nil,
 						"cleanupEnabled":        false,
 						"coldWritesEnabled":     false,
+						"encodingScheme":        "M3TSZ",
+						"resizeState":           nil,
+						"lateWritesEnabled":     false,
 						"flushEnabled":          true,
 						"indexOptions":          nil,
 						"repairEnabled":         false,
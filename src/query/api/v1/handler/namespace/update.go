@@ -27,9 +27,13 @@ import (
 	"net/http"
 	"path"
 	"reflect"
+	"time"
 
 	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/placementhandler/handleroptions"
+	"github.com/m3db/m3/src/cluster/services"
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
 	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/query/api/v1/route"
@@ -52,16 +56,20 @@ var (
 
 	fieldNameRetentionOptions   = "RetentionOptions"
 	fieldNameRetentionPeriod    = "RetentionPeriodNanos"
+	fieldNameBlockSize          = "BlockSizeNanos"
+	fieldNameIndexOptions       = "IndexOptions"
 	fieldNameRuntimeOptions     = "RuntimeOptions"
 	fieldNameAggregationOptions = "AggregationOptions"
 	fieldNameExtendedOptions    = "ExtendedOptions"
 
-	errEmptyNamespaceName      = errors.New("must specify namespace name")
-	errEmptyNamespaceOptions   = errors.New("update options cannot be empty")
-	errNamespaceFieldImmutable = errors.New("namespace option field is immutable")
+	errEmptyNamespaceName             = errors.New("must specify namespace name")
+	errEmptyNamespaceOptions          = errors.New("update options cannot be empty")
+	errNamespaceFieldImmutable        = errors.New("namespace option field is immutable")
+	errIndexBlockSizeWithoutBlockSize = errors.New("index block size can only be updated along with the block size")
 
 	allowedUpdateOptionsFields = map[string]struct{}{
 		fieldNameRetentionOptions:   {},
+		fieldNameIndexOptions:       {},
 		fieldNameRuntimeOptions:     {},
 		fieldNameAggregationOptions: {},
 		fieldNameExtendedOptions:    {},
@@ -170,12 +178,27 @@ func validateUpdateRequest(req *admin.NamespaceUpdateRequest) error {
 		for i := 0; i < optsVal.NumField(); i++ {
 			field := optsVal.Field(i)
 			fieldName := optsVal.Type().Field(i).Name
-			if !field.IsZero() && fieldName != fieldNameRetentionPeriod {
+			if !field.IsZero() && fieldName != fieldNameRetentionPeriod && fieldName != fieldNameBlockSize {
 				return fmt.Errorf("%s.%s: %w", fieldNameRetentionOptions, fieldName, errNamespaceFieldImmutable)
 			}
 		}
 	}
 
+	if opts := req.Options.IndexOptions; opts != nil {
+		optsVal := reflect.ValueOf(*opts)
+		for i := 0; i < optsVal.NumField(); i++ {
+			field := optsVal.Field(i)
+			fieldName := optsVal.Type().Field(i).Name
+			if !field.IsZero() && fieldName != fieldNameBlockSize {
+				return fmt.Errorf("%s.%s: %w", fieldNameIndexOptions, fieldName, errNamespaceFieldImmutable)
+			}
+		}
+		if opts.BlockSizeNanos != 0 &&
+			(req.Options.RetentionOptions == nil || req.Options.RetentionOptions.BlockSizeNanos == 0) {
+			return errIndexBlockSizeWithoutBlockSize
+		}
+	}
+
 	return nil
 }
 
@@ -224,6 +247,20 @@ func (h *UpdateHandler) Update(
 		}
 	}
 
+	// Resize the block sizes.
+	if newRetentionOpts := updateReq.Options.RetentionOptions; newRetentionOpts != nil &&
+		newRetentionOpts.BlockSizeNanos != 0 {
+		var indexBlockSizeNanos int64
+		if newIndexOpts := updateReq.Options.IndexOptions; newIndexOpts != nil {
+			indexBlockSizeNanos = newIndexOpts.BlockSizeNanos
+		}
+		ns, err = h.resize(ns, namespace.FromNanos(newRetentionOpts.BlockSizeNanos),
+			namespace.FromNanos(indexBlockSizeNanos), store, opts)
+		if err != nil {
+			return emptyReg, err
+		}
+	}
+
 	// Update runtime options.
 	if newRuntimeOpts := updateReq.Options.RuntimeOptions; newRuntimeOpts != nil {
 		runtimeOpts := ns.Options().RuntimeOptions()
@@ -301,3 +338,92 @@ func (h *UpdateHandler) Update(
 
 	return *protoRegistry, nil
 }
+
+// resize starts a resize of the block sizes of a namespace, or completes the
+// resize once every host in the placement has converted the sealed blocks of
+// the namespace to the new block size. Repeating an update with the block
+// size of an in-progress resize completes it.
+func (h *UpdateHandler) resize(
+	ns namespace.Metadata,
+	blockSize time.Duration,
+	indexBlockSize time.Duration,
+	store kv.Store,
+	opts handleroptions.ServiceOptions,
+) (namespace.Metadata, error) {
+	nsOpts := ns.Options()
+	if blockSize == nsOpts.RetentionOptions().BlockSize() &&
+		(indexBlockSize == 0 || indexBlockSize == nsOpts.IndexOptions().BlockSize()) {
+		return ns, nil
+	}
+
+	if indexBlockSize == 0 {
+		indexBlockSize = nsOpts.IndexOptions().BlockSize()
+		if indexBlockSize%blockSize != 0 {
+			indexBlockSize = blockSize
+		}
+	}
+	state := namespace.ResizeState{
+		BlockSize:      blockSize,
+		IndexBlockSize: indexBlockSize,
+	}
+	if nsOpts.ResizeState() != state {
+		resized, err := namespace.NewMetadata(ns.ID(), nsOpts.SetResizeState(state))
+		if err != nil {
+			return nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+				"error constructing new metadata: %w", err))
+		}
+		return resized, nil
+	}
+
+	converted, total, err := h.resizeConvertedHosts(ns, state, store, opts)
+	if err != nil {
+		return nil, err
+	}
+	if converted < total {
+		return nil, xhttp.NewError(fmt.Errorf(
+			"resize in progress: %d of %d hosts converted", converted, total),
+			http.StatusConflict)
+	}
+
+	resized, err := namespace.NewMetadata(ns.ID(), namespace.ResizedOptions(nsOpts))
+	if err != nil {
+		return nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+			"error constructing new metadata: %w", err))
+	}
+	return resized, nil
+}
+
+// resizeConvertedHosts returns the number of hosts in the placement that have
+// converted the sealed blocks of a namespace to the block size of a resize,
+// along with the number of hosts in the placement.
+func (h *UpdateHandler) resizeConvertedHosts(
+	ns namespace.Metadata,
+	state namespace.ResizeState,
+	store kv.Store,
+	opts handleroptions.ServiceOptions,
+) (int, int, error) {
+	svcs, err := h.client.Services(services.NewOverrideOptions())
+	if err != nil {
+		return 0, 0, err
+	}
+	ps, err := svcs.PlacementService(opts.ServiceID(), placement.NewOptions())
+	if err != nil {
+		return 0, 0, err
+	}
+	p, err := ps.Placement()
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to get placement: %w", err)
+	}
+
+	converted := 0
+	for _, instance := range p.Instances() {
+		ok, err := namespace.ResizeConverted(store, ns.ID(), state, instance.ID())
+		if err != nil {
+			return 0, 0, err
+		}
+		if ok {
+			converted++
+		}
+	}
+	return converted, p.NumInstances(), nil
+}
@@ -28,14 +28,21 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
 	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
 	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	"github.com/m3db/m3/src/dbnode/namespace"
 	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/x/ident"
 	"github.com/m3db/m3/src/x/instrument"
 	xjson "github.com/m3db/m3/src/x/json"
 	xtest "github.com/m3db/m3/src/x/test"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -174,6 +181,7 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 									},
 								},
 							},
+							"rollupPolicy": nil,
 						},
 						"bootstrapEnabled":      true,
 						"cacheBlocksOnRetrieve": true,
@@ -202,6 +210,9 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 						"schemaOptions":     nil,
 						"stagingState":      xjson.Map{"status": "UNKNOWN"},
 						"coldWritesEnabled": false,
+						"encodingScheme":    "M3TSZ",
+						"resizeState":       nil,
+						"lateWritesEnabled": false,
 						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("bar"),
 					},
 				},
@@ -260,6 +271,9 @@ func TestNamespaceUpdateHandler(t *testing.T) {
 						"schemaOptions":     nil,
 						"stagingState":      xjson.Map{"status": "UNKNOWN"},
 						"coldWritesEnabled": false,
+						"encodingScheme":    "M3TSZ",
+						"resizeState":       nil,
+						"lateWritesEnabled": false,
 						"extendedOptions":   xtest.NewTestExtendedOptionsJSON("foo"),
 					},
 				},
@@ -308,6 +322,24 @@ func TestValidateUpdateRequest(t *testing.T) {
 			},
 		}
 
+		reqIndexBlockSizeOnly = &admin.NamespaceUpdateRequest{
+			Name: "foo",
+			Options: &nsproto.NamespaceOptions{
+				IndexOptions: &nsproto.IndexOptions{
+					BlockSizeNanos: 1,
+				},
+			},
+		}
+
+		reqNonZeroIndexEnabled = &admin.NamespaceUpdateRequest{
+			Name: "foo",
+			Options: &nsproto.NamespaceOptions{
+				IndexOptions: &nsproto.IndexOptions{
+					Enabled: true,
+				},
+			},
+		}
+
 		reqValid = &admin.NamespaceUpdateRequest{
 			Name: "foo",
 			Options: &nsproto.NamespaceOptions{
@@ -316,6 +348,18 @@ func TestValidateUpdateRequest(t *testing.T) {
 				},
 			},
 		}
+
+		reqValidResize = &admin.NamespaceUpdateRequest{
+			Name: "foo",
+			Options: &nsproto.NamespaceOptions{
+				RetentionOptions: &nsproto.RetentionOptions{
+					BlockSizeNanos: 1,
+				},
+				IndexOptions: &nsproto.IndexOptions{
+					BlockSizeNanos: 1,
+				},
+			},
+		}
 	)
 
 	for _, test := range []struct {
@@ -346,6 +390,16 @@ func TestValidateUpdateRequest(t *testing.T) {
 		{
 			name:    "nonZeroBlockSize",
 			request: reqNonZeroBlockSize,
+			expErr:  nil,
+		},
+		{
+			name:    "indexBlockSizeOnly",
+			request: reqIndexBlockSizeOnly,
+			expErr:  errIndexBlockSizeWithoutBlockSize,
+		},
+		{
+			name:    "nonZeroIndexEnabled",
+			request: reqNonZeroIndexEnabled,
 			expErr:  errNamespaceFieldImmutable,
 		},
 		{
@@ -353,6 +407,11 @@ func TestValidateUpdateRequest(t *testing.T) {
 			request: reqValid,
 			expErr:  nil,
 		},
+		{
+			name:    "validResize",
+			request: reqValidResize,
+			expErr:  nil,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			err := validateUpdateRequest(test.request)
@@ -366,3 +425,111 @@ func TestValidateUpdateRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceUpdateHandlerResize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockKV := setupNamespaceTest(t, ctrl)
+	updateHandler := NewUpdateHandler(mockClient, instrument.NewOptions())
+	mockClient.EXPECT().Store(gomock.Any()).Return(mockKV, nil).AnyTimes()
+
+	p := placement.NewPlacement().SetInstances([]placement.Instance{
+		placement.NewInstance().SetID("host0"),
+		placement.NewInstance().SetID("host1"),
+	})
+	mockPlacementService := placement.NewMockService(ctrl)
+	mockPlacementService.EXPECT().Placement().Return(p, nil).AnyTimes()
+	mockServices := services.NewMockServices(ctrl)
+	mockServices.EXPECT().PlacementService(gomock.Any(), gomock.Any()).
+		Return(mockPlacementService, nil).AnyTimes()
+	mockClient.EXPECT().Services(gomock.Any()).Return(mockServices, nil).AnyTimes()
+
+	const resizeJSON = `
+{
+		"name": "testNamespace",
+		"options": {
+			"retentionOptions": {
+				"blockSizeDuration": "4h"
+			}
+		}
+}
+`
+	registry := nsproto.Registry{
+		Namespaces: map[string]*nsproto.NamespaceOptions{
+			"testNamespace": {
+				BootstrapEnabled:  true,
+				FlushEnabled:      true,
+				SnapshotEnabled:   true,
+				WritesToCommitLog: true,
+				RetentionOptions: &nsproto.RetentionOptions{
+					RetentionPeriodNanos: int64(48 * time.Hour),
+					BlockSizeNanos:       int64(2 * time.Hour),
+					BufferFutureNanos:    int64(10 * time.Minute),
+					BufferPastNanos:      int64(10 * time.Minute),
+				},
+				IndexOptions: &nsproto.IndexOptions{
+					Enabled:        true,
+					BlockSizeNanos: int64(2 * time.Hour),
+				},
+			},
+		},
+	}
+	id := ident.StringID("testNamespace")
+	state := namespace.ResizeState{
+		BlockSize:      4 * time.Hour,
+		IndexBlockSize: 4 * time.Hour,
+	}
+
+	update := func(expectedStatus int) *nsproto.NamespaceOptions {
+		mockValue := kv.NewMockValue(ctrl)
+		mockValue.EXPECT().Unmarshal(gomock.Any()).Return(nil).SetArg(0, registry)
+		mockValue.EXPECT().Version().Return(0)
+		mockKV.EXPECT().Get(M3DBNodeNamespacesKey).Return(mockValue, nil)
+
+		var updated *nsproto.NamespaceOptions
+		if expectedStatus == http.StatusOK {
+			mockKV.EXPECT().CheckAndSet(M3DBNodeNamespacesKey, gomock.Any(), gomock.Not(nil)).
+				DoAndReturn(func(_ string, _ int, v proto.Message) (int, error) {
+					updated = v.(*nsproto.Registry).Namespaces["testNamespace"]
+					return 1, nil
+				})
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("PUT", "/namespace", strings.NewReader(resizeJSON))
+		updateHandler.ServeHTTP(svcDefaults, w, req)
+		require.Equal(t, expectedStatus, w.Result().StatusCode)
+		return updated
+	}
+
+	expectConverted := func(hostID string, converted bool) {
+		mockValue := kv.NewMockValue(ctrl)
+		mockValue.EXPECT().Unmarshal(gomock.Any()).Return(nil).
+			SetArg(0, commonpb.BoolProto{Value: converted})
+		mockKV.EXPECT().Get(namespace.ResizeStatusKey(id, state, hostID)).Return(mockValue, nil)
+	}
+
+	// Start the resize, the index block size defaults to the new block size
+	// since the current one is not a multiple of it.
+	updated := update(http.StatusOK)
+	require.Equal(t, int64(2*time.Hour), updated.RetentionOptions.BlockSizeNanos)
+	require.Equal(t, &nsproto.ResizeState{
+		BlockSizeNanos:      int64(4 * time.Hour),
+		IndexBlockSizeNanos: int64(4 * time.Hour),
+	}, updated.ResizeState)
+
+	// Completing the resize is rejected until every host has converted.
+	registry.Namespaces["testNamespace"].ResizeState = updated.ResizeState
+	expectConverted("host0", true)
+	mockKV.EXPECT().Get(namespace.ResizeStatusKey(id, state, "host1")).Return(nil, kv.ErrNotFound)
+	require.Nil(t, update(http.StatusConflict))
+
+	// Complete the resize.
+	expectConverted("host0", true)
+	expectConverted("host1", true)
+	updated = update(http.StatusOK)
+	require.Equal(t, int64(4*time.Hour), updated.RetentionOptions.BlockSizeNanos)
+	require.Equal(t, int64(4*time.Hour), updated.IndexOptions.BlockSizeNanos)
+	require.Nil(t, updated.ResizeState)
+}
@@ -0,0 +1,601 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// The resource attributes that identify the producer of a set of
+	// metrics, mapped to the job and instance labels as Prometheus does.
+	serviceNameAttribute       = "service.name"
+	serviceNamespaceAttribute  = "service.namespace"
+	serviceInstanceIDAttribute = "service.instance.id"
+
+	quantileLabel = "quantile"
+
+	// Exponential histograms are expanded using the native histogram
+	// conversion, which supports scales in the range [-4, 8]. Higher scales
+	// are downscaled to the maximum.
+	minExponentialHistogramScale = -4
+	maxExponentialHistogramScale = 8
+)
+
+var (
+	errUnspecifiedTemporality = errors.New("aggregation temporality unspecified")
+	errMissingMetricName      = errors.New("metric missing name")
+)
+
+// convertResult is the result of converting an OTLP export request.
+type convertResult struct {
+	series   []prompb.TimeSeries
+	metadata []prompb.MetricMetadata
+	rejected int64
+	lastErr  error
+}
+
+func (r *convertResult) reject(n int, err error) {
+	r.rejected += int64(n)
+	r.lastErr = err
+}
+
+// converter converts OTLP metrics into the equivalent Prometheus series.
+type converter struct {
+	promoteResourceAttributes []string
+	deltas                    *deltaAccumulator
+}
+
+func newConverter(
+	promoteResourceAttributes []string,
+	deltas *deltaAccumulator,
+) *converter {
+	return &converter{
+		promoteResourceAttributes: promoteResourceAttributes,
+		deltas:                    deltas,
+	}
+}
+
+func (c *converter) convert(req *otlppb.ExportMetricsServiceRequest) convertResult {
+	var (
+		result   convertResult
+		metadata = make(map[string]prompb.MetricMetadata)
+	)
+	for _, resourceMetrics := range req.ResourceMetrics {
+		resourceLabels := c.resourceLabels(resourceMetrics.Resource)
+		for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+			for _, metric := range scopeMetrics.Metrics {
+				c.convertMetric(resourceLabels, metric, metadata, &result)
+			}
+		}
+	}
+
+	result.metadata = make([]prompb.MetricMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		result.metadata = append(result.metadata, m)
+	}
+	sort.Slice(result.metadata, func(i, j int) bool {
+		return result.metadata[i].MetricFamilyName < result.metadata[j].MetricFamilyName
+	})
+	return result
+}
+
+func (c *converter) resourceLabels(resource otlppb.Resource) map[string]string {
+	var (
+		labels     = make(map[string]string, 2+len(c.promoteResourceAttributes))
+		attributes = make(map[string]otlppb.AnyValue, len(resource.Attributes))
+	)
+	for _, attribute := range resource.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+
+	if name, ok := attributes[serviceNameAttribute]; ok {
+		job := anyValueString(name)
+		if namespace, ok := attributes[serviceNamespaceAttribute]; ok {
+			job = anyValueString(namespace) + "/" + job
+		}
+		labels[model.JobLabel] = job
+	}
+	if instance, ok := attributes[serviceInstanceIDAttribute]; ok {
+		labels[model.InstanceLabel] = anyValueString(instance)
+	}
+	for _, key := range c.promoteResourceAttributes {
+		if value, ok := attributes[key]; ok {
+			labels[sanitizeLabelName(key)] = anyValueString(value)
+		}
+	}
+	return labels
+}
+
+func (c *converter) convertMetric(
+	resourceLabels map[string]string,
+	metric otlppb.Metric,
+	metadata map[string]prompb.MetricMetadata,
+	result *convertResult,
+) {
+	name := sanitizeMetricName(metric.Name)
+	if name == "" {
+		result.reject(numDataPoints(metric), errMissingMetricName)
+		return
+	}
+
+	var metricType prompb.MetricType
+	switch data := metric.Data.(type) {
+	case *otlppb.Metric_Gauge:
+		metricType = prompb.MetricType_GAUGE
+		c.convertNumberDataPoints(resourceLabels, name, metricType,
+			otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			data.Gauge.DataPoints, result)
+	case *otlppb.Metric_Sum:
+		metricType = prompb.MetricType_GAUGE
+		if data.Sum.IsMonotonic {
+			metricType = prompb.MetricType_COUNTER
+		}
+		c.convertNumberDataPoints(resourceLabels, name, metricType,
+			data.Sum.AggregationTemporality, data.Sum.DataPoints, result)
+	case *otlppb.Metric_Histogram:
+		metricType = prompb.MetricType_HISTOGRAM
+		c.convertHistogramDataPoints(resourceLabels, name,
+			data.Histogram.AggregationTemporality, data.Histogram.DataPoints, result)
+	case *otlppb.Metric_ExponentialHistogram:
+		metricType = prompb.MetricType_HISTOGRAM
+		c.convertExponentialHistogramDataPoints(resourceLabels, name,
+			data.ExponentialHistogram.AggregationTemporality,
+			data.ExponentialHistogram.DataPoints, result)
+	case *otlppb.Metric_Summary:
+		metricType = prompb.MetricType_SUMMARY
+		c.convertSummaryDataPoints(resourceLabels, name,
+			data.Summary.DataPoints, result)
+	default:
+		// NB: metrics without data carry no data points to reject.
+		return
+	}
+
+	if _, ok := metadata[name]; !ok {
+		metadata[name] = prompb.MetricMetadata{
+			Type:             metricType,
+			MetricFamilyName: name,
+			Help:             metric.Description,
+			Unit:             metric.Unit,
+		}
+	}
+}
+
+func (c *converter) convertNumberDataPoints(
+	resourceLabels map[string]string,
+	name string,
+	metricType prompb.MetricType,
+	temporality otlppb.AggregationTemporality,
+	dataPoints []otlppb.NumberDataPoint,
+	result *convertResult,
+) {
+	if temporality == otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED {
+		result.reject(len(dataPoints), fmt.Errorf("sum %s: %v", name, errUnspecifiedTemporality))
+		return
+	}
+
+	for _, dp := range dataPoints {
+		if noRecordedValue(dp.Flags) {
+			continue
+		}
+
+		var value float64
+		switch v := dp.Value.(type) {
+		case *otlppb.NumberDataPoint_AsDouble:
+			value = v.AsDouble
+		case *otlppb.NumberDataPoint_AsInt:
+			value = float64(v.AsInt)
+		default:
+			result.reject(1, fmt.Errorf("metric %s: data point missing value", name))
+			continue
+		}
+
+		labels := newLabels(resourceLabels, dp.Attributes)
+		c.add(newSeries(labels, name, "", metricType), temporality,
+			dp.TimeUnixNano, value, result)
+	}
+}
+
+func (c *converter) convertHistogramDataPoints(
+	resourceLabels map[string]string,
+	name string,
+	temporality otlppb.AggregationTemporality,
+	dataPoints []otlppb.HistogramDataPoint,
+	result *convertResult,
+) {
+	if temporality == otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED {
+		result.reject(len(dataPoints), fmt.Errorf("histogram %s: %v", name, errUnspecifiedTemporality))
+		return
+	}
+
+	metricType := prompb.MetricType_HISTOGRAM
+	for _, dp := range dataPoints {
+		if noRecordedValue(dp.Flags) {
+			continue
+		}
+
+		if n := len(dp.BucketCounts); n != 0 && n != len(dp.ExplicitBounds)+1 {
+			result.reject(1, fmt.Errorf("histogram %s: %d bucket counts for %d bounds",
+				name, n, len(dp.ExplicitBounds)))
+			continue
+		}
+
+		labels := newLabels(resourceLabels, dp.Attributes)
+
+		// NB: the bucket counted by the last bucket count has no upper bound,
+		// it is covered by the +Inf bucket which is always the total count.
+		// Data points without bucket counts only have a count and sum.
+		var cumulative uint64
+		for i := 0; i < len(dp.BucketCounts)-1; i++ {
+			bound := dp.ExplicitBounds[i]
+			cumulative += dp.BucketCounts[i]
+			series := newSeries(labels, name, "_bucket", metricType)
+			series.Labels = appendLabel(series.Labels, model.BucketLabel,
+				strconv.FormatFloat(bound, 'g', -1, 64))
+			c.add(series, temporality, dp.TimeUnixNano, float64(cumulative), result)
+		}
+
+		series := newSeries(labels, name, "_bucket", metricType)
+		series.Labels = appendLabel(series.Labels, model.BucketLabel, "+Inf")
+		c.add(series, temporality, dp.TimeUnixNano, float64(dp.Count), result)
+		c.add(newSeries(labels, name, "_count", metricType), temporality,
+			dp.TimeUnixNano, float64(dp.Count), result)
+		c.add(newSeries(labels, name, "_sum", metricType), temporality,
+			dp.TimeUnixNano, dp.Sum, result)
+	}
+}
+
+func (c *converter) convertExponentialHistogramDataPoints(
+	resourceLabels map[string]string,
+	name string,
+	temporality otlppb.AggregationTemporality,
+	dataPoints []otlppb.ExponentialHistogramDataPoint,
+	result *convertResult,
+) {
+	if temporality == otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED {
+		result.reject(len(dataPoints), fmt.Errorf("exponential histogram %s: %v",
+			name, errUnspecifiedTemporality))
+		return
+	}
+
+	for _, dp := range dataPoints {
+		if noRecordedValue(dp.Flags) {
+			continue
+		}
+
+		if dp.Scale < minExponentialHistogramScale {
+			result.reject(1, fmt.Errorf("exponential histogram %s: scale %d less than minimum %d",
+				name, dp.Scale, minExponentialHistogramScale))
+			continue
+		}
+
+		series := newSeries(newLabels(resourceLabels, dp.Attributes), name, "",
+			prompb.MetricType_HISTOGRAM)
+		series.Histograms = []prompb.Histogram{exponentialHistogramToProm(dp)}
+
+		// NB: exponential histograms share the representation of native
+		// histograms so are expanded into classic histogram series the same
+		// way native histograms are on remote write.
+		classic, err := storage.PromHistogramsToClassicTimeSeries(series)
+		if err != nil {
+			result.reject(1, fmt.Errorf("exponential histogram %s: %v", name, err))
+			continue
+		}
+
+		for _, s := range classic {
+			c.add(s, temporality, dp.TimeUnixNano, s.Samples[0].Value, result)
+		}
+	}
+}
+
+func (c *converter) convertSummaryDataPoints(
+	resourceLabels map[string]string,
+	name string,
+	dataPoints []otlppb.SummaryDataPoint,
+	result *convertResult,
+) {
+	// NB: summaries are always cumulative.
+	var (
+		metricType  = prompb.MetricType_SUMMARY
+		temporality = otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	)
+	for _, dp := range dataPoints {
+		if noRecordedValue(dp.Flags) {
+			continue
+		}
+
+		labels := newLabels(resourceLabels, dp.Attributes)
+		for _, q := range dp.QuantileValues {
+			series := newSeries(labels, name, "", metricType)
+			series.Labels = appendLabel(series.Labels, quantileLabel,
+				strconv.FormatFloat(q.Quantile, 'g', -1, 64))
+			c.add(series, temporality, dp.TimeUnixNano, q.Value, result)
+		}
+
+		c.add(newSeries(labels, name, "_count", metricType), temporality,
+			dp.TimeUnixNano, float64(dp.Count), result)
+		c.add(newSeries(labels, name, "_sum", metricType), temporality,
+			dp.TimeUnixNano, dp.Sum, result)
+	}
+}
+
+// add adds a sample to a series, delta samples are converted to the running
+// total of the series so that all series are stored as cumulative values.
+func (c *converter) add(
+	series prompb.TimeSeries,
+	temporality otlppb.AggregationTemporality,
+	timeUnixNano uint64,
+	value float64,
+	result *convertResult,
+) {
+	timestamp := int64(timeUnixNano / uint64(1e6))
+	if temporality == otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA {
+		var ok bool
+		value, ok = c.deltas.accumulate(seriesKey(series.Labels), timestamp, value)
+		if !ok {
+			result.reject(1, fmt.Errorf("series %s: delta sample out of order",
+				seriesKey(series.Labels)))
+			return
+		}
+	}
+
+	series.Samples = []prompb.Sample{{Value: value, Timestamp: timestamp}}
+	series.Histograms = nil
+	result.series = append(result.series, series)
+}
+
+// exponentialHistogramToProm converts an exponential histogram data point to
+// the equivalent native histogram. The index of an OTLP bucket is one less
+// than the index of the native histogram bucket with the same bounds.
+func exponentialHistogramToProm(dp otlppb.ExponentialHistogramDataPoint) prompb.Histogram {
+	var (
+		scale  = dp.Scale
+		shift  = int32(0)
+		result = prompb.Histogram{
+			Count:         &prompb.Histogram_CountFloat{CountFloat: float64(dp.Count)},
+			Sum:           dp.Sum,
+			ZeroThreshold: dp.ZeroThreshold,
+			ZeroCount:     &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: float64(dp.ZeroCount)},
+			Timestamp:     int64(dp.TimeUnixNano / uint64(1e6)),
+		}
+	)
+	if scale > maxExponentialHistogramScale {
+		shift = scale - maxExponentialHistogramScale
+		scale = maxExponentialHistogramScale
+	}
+	result.Schema = scale
+
+	result.PositiveSpans, result.PositiveCounts = exponentialHistogramBuckets(dp.Positive, shift)
+	result.NegativeSpans, result.NegativeCounts = exponentialHistogramBuckets(dp.Negative, shift)
+	return result
+}
+
+// exponentialHistogramBuckets converts a set of exponential histogram buckets
+// to a native histogram span, merging buckets by the given downscale shift.
+func exponentialHistogramBuckets(
+	buckets otlppb.ExponentialHistogramDataPoint_Buckets,
+	shift int32,
+) ([]prompb.BucketSpan, []float64) {
+	if len(buckets.BucketCounts) == 0 {
+		return nil, nil
+	}
+
+	var (
+		first  = buckets.Offset >> uint(shift)
+		last   = (buckets.Offset + int32(len(buckets.BucketCounts)) - 1) >> uint(shift)
+		counts = make([]float64, last-first+1)
+	)
+	for i, count := range buckets.BucketCounts {
+		index := (buckets.Offset + int32(i)) >> uint(shift)
+		counts[index-first] += float64(count)
+	}
+
+	spans := []prompb.BucketSpan{{Offset: first + 1, Length: uint32(len(counts))}}
+	return spans, counts
+}
+
+func numDataPoints(metric otlppb.Metric) int {
+	switch data := metric.Data.(type) {
+	case *otlppb.Metric_Gauge:
+		return len(data.Gauge.DataPoints)
+	case *otlppb.Metric_Sum:
+		return len(data.Sum.DataPoints)
+	case *otlppb.Metric_Histogram:
+		return len(data.Histogram.DataPoints)
+	case *otlppb.Metric_ExponentialHistogram:
+		return len(data.ExponentialHistogram.DataPoints)
+	case *otlppb.Metric_Summary:
+		return len(data.Summary.DataPoints)
+	}
+	return 0
+}
+
+func noRecordedValue(flags uint32) bool {
+	mask := uint32(otlppb.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK)
+	return flags&mask != 0
+}
+
+// newLabels returns the labels of a data point, data point attributes take
+// precedence over resource labels with the same name.
+func newLabels(
+	resourceLabels map[string]string,
+	attributes []otlppb.KeyValue,
+) []prompb.Label {
+	labels := make(map[string]string, len(resourceLabels)+len(attributes))
+	for name, value := range resourceLabels {
+		labels[name] = value
+	}
+	for _, attribute := range attributes {
+		labels[sanitizeLabelName(attribute.Key)] = anyValueString(attribute.Value)
+	}
+
+	result := make([]prompb.Label, 0, len(labels)+2)
+	for name, value := range labels {
+		if name == model.MetricNameLabel || value == "" {
+			continue
+		}
+		result = append(result, prompb.Label{Name: []byte(name), Value: []byte(value)})
+	}
+	return result
+}
+
+// newSeries returns a series with a copy of the given labels along with
+// the metric name label.
+func newSeries(
+	labels []prompb.Label,
+	name string,
+	suffix string,
+	metricType prompb.MetricType,
+) prompb.TimeSeries {
+	result := make([]prompb.Label, 0, len(labels)+2)
+	result = append(result, labels...)
+	return prompb.TimeSeries{
+		Labels: appendLabel(result, model.MetricNameLabel, name+suffix),
+		Type:   metricType,
+		Source: prompb.Source_PROMETHEUS,
+		M3Type: prompb.M3Type_M3_GAUGE,
+	}
+}
+
+func appendLabel(labels []prompb.Label, name, value string) []prompb.Label {
+	labels = append(labels, prompb.Label{Name: []byte(name), Value: []byte(value)})
+	sort.Slice(labels, func(i, j int) bool {
+		return string(labels[i].Name) < string(labels[j].Name)
+	})
+	return labels
+}
+
+// seriesKey returns a key identifying a series by its sorted labels.
+func seriesKey(labels []prompb.Label) string {
+	var b strings.Builder
+	for _, label := range labels {
+		b.Write(label.Name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(string(label.Value)))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// sanitizeMetricName replaces characters that are invalid in Prometheus metric
+// names, such as the dots OpenTelemetry uses as separators, with underscores.
+func sanitizeMetricName(name string) string {
+	return sanitize(name, func(r rune) bool {
+		return r == ':' || r == '_' || isAlphanumeric(r)
+	})
+}
+
+// sanitizeLabelName replaces characters that are invalid in Prometheus label
+// names with underscores.
+func sanitizeLabelName(name string) string {
+	return sanitize(name, func(r rune) bool {
+		return r == '_' || isAlphanumeric(r)
+	})
+}
+
+func sanitize(name string, valid func(r rune) bool) string {
+	if name == "" {
+		return ""
+	}
+
+	result := strings.Map(func(r rune) rune {
+		if valid(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if result[0] >= '0' && result[0] <= '9' {
+		result = "_" + result
+	}
+	return result
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// anyValueString returns the string representation of an attribute value,
+// arrays and key value lists are represented as JSON.
+func anyValueString(value otlppb.AnyValue) string {
+	switch v := value.Value.(type) {
+	case *otlppb.AnyValue_StringValue:
+		return v.StringValue
+	case *otlppb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *otlppb.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *otlppb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	case *otlppb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(v.BytesValue)
+	case *otlppb.AnyValue_ArrayValue, *otlppb.AnyValue_KvlistValue:
+		b, err := json.Marshal(anyValueJSON(value))
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	return ""
+}
+
+func anyValueJSON(value otlppb.AnyValue) interface{} {
+	switch v := value.Value.(type) {
+	case *otlppb.AnyValue_StringValue:
+		return v.StringValue
+	case *otlppb.AnyValue_BoolValue:
+		return v.BoolValue
+	case *otlppb.AnyValue_IntValue:
+		return v.IntValue
+	case *otlppb.AnyValue_DoubleValue:
+		if math.IsNaN(v.DoubleValue) || math.IsInf(v.DoubleValue, 0) {
+			return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+		}
+		return v.DoubleValue
+	case *otlppb.AnyValue_BytesValue:
+		return v.BytesValue
+	case *otlppb.AnyValue_ArrayValue:
+		result := make([]interface{}, 0, len(v.ArrayValue.Values))
+		for _, elem := range v.ArrayValue.Values {
+			result = append(result, anyValueJSON(elem))
+		}
+		return result
+	case *otlppb.AnyValue_KvlistValue:
+		result := make(map[string]interface{}, len(v.KvlistValue.Values))
+		for _, kv := range v.KvlistValue.Values {
+			result[kv.Key] = anyValueJSON(kv.Value)
+		}
+		return result
+	}
+	return nil
+}
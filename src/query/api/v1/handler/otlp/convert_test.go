@@ -0,0 +1,384 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Unix(1600000000, 0)
+
+func newTestConverter() *converter {
+	return newConverter([]string{"host.name"},
+		newDeltaAccumulator(time.Minute, time.Now))
+}
+
+func stringAttribute(key, value string) otlppb.KeyValue {
+	return otlppb.KeyValue{
+		Key:   key,
+		Value: otlppb.AnyValue{Value: &otlppb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func testRequest(metrics ...otlppb.Metric) *otlppb.ExportMetricsServiceRequest {
+	return &otlppb.ExportMetricsServiceRequest{
+		ResourceMetrics: []otlppb.ResourceMetrics{
+			{
+				Resource: otlppb.Resource{
+					Attributes: []otlppb.KeyValue{
+						stringAttribute("service.name", "api"),
+						stringAttribute("service.namespace", "shop"),
+						stringAttribute("service.instance.id", "pod-1"),
+						stringAttribute("host.name", "host-1"),
+						stringAttribute("process.pid", "123"),
+					},
+				},
+				ScopeMetrics: []otlppb.ScopeMetrics{{Metrics: metrics}},
+			},
+		},
+	}
+}
+
+func timeUnixNano(offset time.Duration) uint64 {
+	return uint64(testTime.Add(offset).UnixNano())
+}
+
+// seriesStrings renders converted series in the Prometheus text format.
+func seriesStrings(series []prompb.TimeSeries) []string {
+	result := make([]string, 0, len(series))
+	for _, s := range series {
+		var (
+			name   string
+			labels []string
+		)
+		for _, l := range s.Labels {
+			if string(l.Name) == "__name__" {
+				name = string(l.Value)
+				continue
+			}
+			labels = append(labels, fmt.Sprintf("%s=%q", l.Name, l.Value))
+		}
+		for _, sample := range s.Samples {
+			result = append(result, fmt.Sprintf("%s{%s} %v @%d %s",
+				name, strings.Join(labels, ","), sample.Value, sample.Timestamp, s.Type))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestConvertGaugeAndSum(t *testing.T) {
+	req := testRequest(
+		otlppb.Metric{
+			Name:        "system.memory.usage",
+			Description: "Memory in use.",
+			Unit:        "By",
+			Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+				DataPoints: []otlppb.NumberDataPoint{
+					{
+						Attributes:   []otlppb.KeyValue{stringAttribute("state", "used")},
+						TimeUnixNano: timeUnixNano(0),
+						Value:        &otlppb.NumberDataPoint_AsInt{AsInt: 1024},
+					},
+					{
+						// NB: data points without a recorded value are skipped.
+						TimeUnixNano: timeUnixNano(0),
+						Flags:        1,
+					},
+				},
+			}},
+		},
+		otlppb.Metric{
+			Name: "http.requests",
+			Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+				AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+				DataPoints: []otlppb.NumberDataPoint{
+					{
+						Attributes: []otlppb.KeyValue{
+							stringAttribute("http.method", "GET"),
+							// NB: data point attributes take precedence.
+							stringAttribute("host.name", "host-2"),
+						},
+						TimeUnixNano: timeUnixNano(time.Second),
+						Value:        &otlppb.NumberDataPoint_AsDouble{AsDouble: 42.5},
+					},
+				},
+			}},
+		},
+		otlppb.Metric{
+			Name: "queue.size",
+			Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+				AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				DataPoints: []otlppb.NumberDataPoint{
+					{
+						TimeUnixNano: timeUnixNano(time.Second),
+						Value:        &otlppb.NumberDataPoint_AsInt{AsInt: -3},
+					},
+				},
+			}},
+		},
+	)
+
+	result := newTestConverter().convert(req)
+	require.Equal(t, int64(0), result.rejected)
+	assert.Equal(t, []string{
+		`http_requests{host_name="host-2",http_method="GET",instance="pod-1",job="shop/api"} 42.5 @1600000001000 COUNTER`,
+		`queue_size{host_name="host-1",instance="pod-1",job="shop/api"} -3 @1600000001000 GAUGE`,
+		`system_memory_usage{host_name="host-1",instance="pod-1",job="shop/api",state="used"} 1024 @1600000000000 GAUGE`,
+	}, seriesStrings(result.series))
+
+	assert.Equal(t, []prompb.MetricMetadata{
+		{Type: prompb.MetricType_COUNTER, MetricFamilyName: "http_requests"},
+		{Type: prompb.MetricType_GAUGE, MetricFamilyName: "queue_size"},
+		{
+			Type:             prompb.MetricType_GAUGE,
+			MetricFamilyName: "system_memory_usage",
+			Help:             "Memory in use.",
+			Unit:             "By",
+		},
+	}, result.metadata)
+}
+
+func TestConvertDeltaSum(t *testing.T) {
+	deltaSum := func(offset time.Duration, value int64) otlppb.Metric {
+		return otlppb.Metric{
+			Name: "requests",
+			Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+				AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				IsMonotonic:            true,
+				DataPoints: []otlppb.NumberDataPoint{
+					{
+						TimeUnixNano: timeUnixNano(offset),
+						Value:        &otlppb.NumberDataPoint_AsInt{AsInt: value},
+					},
+				},
+			}},
+		}
+	}
+
+	c := newTestConverter()
+	result := c.convert(testRequest(deltaSum(0, 5), deltaSum(time.Second, 3)))
+	require.Equal(t, int64(0), result.rejected)
+	assert.Equal(t, []string{
+		`requests{host_name="host-1",instance="pod-1",job="shop/api"} 5 @1600000000000 COUNTER`,
+		`requests{host_name="host-1",instance="pod-1",job="shop/api"} 8 @1600000001000 COUNTER`,
+	}, seriesStrings(result.series))
+
+	// Totals carry over across requests while out of order samples are rejected.
+	result = c.convert(testRequest(deltaSum(2*time.Second, 2), deltaSum(time.Second, 1)))
+	assert.Equal(t, int64(1), result.rejected)
+	assert.Error(t, result.lastErr)
+	assert.Equal(t, []string{
+		`requests{host_name="host-1",instance="pod-1",job="shop/api"} 10 @1600000002000 COUNTER`,
+	}, seriesStrings(result.series))
+}
+
+func TestConvertUnspecifiedTemporality(t *testing.T) {
+	result := newTestConverter().convert(testRequest(otlppb.Metric{
+		Name: "requests",
+		Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+			DataPoints: []otlppb.NumberDataPoint{
+				{TimeUnixNano: timeUnixNano(0), Value: &otlppb.NumberDataPoint_AsInt{AsInt: 1}},
+				{TimeUnixNano: timeUnixNano(time.Second), Value: &otlppb.NumberDataPoint_AsInt{AsInt: 2}},
+			},
+		}},
+	}))
+	assert.Equal(t, int64(2), result.rejected)
+	assert.Error(t, result.lastErr)
+	assert.Empty(t, result.series)
+}
+
+func TestConvertHistogram(t *testing.T) {
+	result := newTestConverter().convert(testRequest(otlppb.Metric{
+		Name: "http.server.duration",
+		Data: &otlppb.Metric_Histogram{Histogram: &otlppb.Histogram{
+			AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			DataPoints: []otlppb.HistogramDataPoint{
+				{
+					TimeUnixNano:   timeUnixNano(0),
+					Count:          6,
+					Sum:            10,
+					BucketCounts:   []uint64{1, 2, 3},
+					ExplicitBounds: []float64{0.5, 5},
+				},
+				{
+					TimeUnixNano:   timeUnixNano(time.Second),
+					BucketCounts:   []uint64{1, 2},
+					ExplicitBounds: []float64{0.5, 5},
+				},
+			},
+		}},
+	}))
+
+	assert.Equal(t, int64(1), result.rejected)
+	assert.Equal(t, []string{
+		`http_server_duration_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="+Inf"} 6 @1600000000000 HISTOGRAM`,
+		`http_server_duration_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="0.5"} 1 @1600000000000 HISTOGRAM`,
+		`http_server_duration_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="5"} 3 @1600000000000 HISTOGRAM`,
+		`http_server_duration_count{host_name="host-1",instance="pod-1",job="shop/api"} 6 @1600000000000 HISTOGRAM`,
+		`http_server_duration_sum{host_name="host-1",instance="pod-1",job="shop/api"} 10 @1600000000000 HISTOGRAM`,
+	}, seriesStrings(result.series))
+}
+
+func TestConvertExponentialHistogram(t *testing.T) {
+	result := newTestConverter().convert(testRequest(otlppb.Metric{
+		Name: "latency",
+		Data: &otlppb.Metric_ExponentialHistogram{ExponentialHistogram: &otlppb.ExponentialHistogram{
+			AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+			DataPoints: []otlppb.ExponentialHistogramDataPoint{
+				{
+					TimeUnixNano: timeUnixNano(0),
+					Count:        5,
+					Sum:          7,
+					Scale:        0,
+					ZeroCount:    1,
+					Positive: otlppb.ExponentialHistogramDataPoint_Buckets{
+						// Buckets (1, 2] and (2, 4].
+						Offset:       0,
+						BucketCounts: []uint64{1, 2},
+					},
+					Negative: otlppb.ExponentialHistogramDataPoint_Buckets{
+						// Bucket [-2, -1).
+						Offset:       0,
+						BucketCounts: []uint64{1},
+					},
+				},
+				{
+					TimeUnixNano: timeUnixNano(time.Second),
+					Scale:        -5,
+				},
+			},
+		}},
+	}))
+
+	assert.Equal(t, int64(1), result.rejected)
+	assert.Equal(t, []string{
+		`latency_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="+Inf"} 5 @1600000000000 HISTOGRAM`,
+		`latency_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="-1"} 1 @1600000000000 HISTOGRAM`,
+		`latency_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="0"} 2 @1600000000000 HISTOGRAM`,
+		`latency_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="2"} 3 @1600000000000 HISTOGRAM`,
+		`latency_bucket{host_name="host-1",instance="pod-1",job="shop/api",le="4"} 5 @1600000000000 HISTOGRAM`,
+		`latency_count{host_name="host-1",instance="pod-1",job="shop/api"} 5 @1600000000000 HISTOGRAM`,
+		`latency_sum{host_name="host-1",instance="pod-1",job="shop/api"} 7 @1600000000000 HISTOGRAM`,
+	}, seriesStrings(result.series))
+}
+
+func TestConvertSummary(t *testing.T) {
+	result := newTestConverter().convert(testRequest(otlppb.Metric{
+		Name: "rpc.latency",
+		Data: &otlppb.Metric_Summary{Summary: &otlppb.Summary{
+			DataPoints: []otlppb.SummaryDataPoint{
+				{
+					TimeUnixNano: timeUnixNano(0),
+					Count:        3,
+					Sum:          1.5,
+					QuantileValues: []otlppb.SummaryDataPoint_ValueAtQuantile{
+						{Quantile: 0.5, Value: 0.25},
+						{Quantile: 0.99, Value: 1},
+					},
+				},
+			},
+		}},
+	}))
+
+	assert.Equal(t, int64(0), result.rejected)
+	assert.Equal(t, []string{
+		`rpc_latency_count{host_name="host-1",instance="pod-1",job="shop/api"} 3 @1600000000000 SUMMARY`,
+		`rpc_latency_sum{host_name="host-1",instance="pod-1",job="shop/api"} 1.5 @1600000000000 SUMMARY`,
+		`rpc_latency{host_name="host-1",instance="pod-1",job="shop/api",quantile="0.5"} 0.25 @1600000000000 SUMMARY`,
+		`rpc_latency{host_name="host-1",instance="pod-1",job="shop/api",quantile="0.99"} 1 @1600000000000 SUMMARY`,
+	}, seriesStrings(result.series))
+}
+
+func TestExponentialHistogramToPromDownscale(t *testing.T) {
+	histogram := exponentialHistogramToProm(otlppb.ExponentialHistogramDataPoint{
+		Scale: 10,
+		Positive: otlppb.ExponentialHistogramDataPoint_Buckets{
+			Offset:       -5,
+			BucketCounts: []uint64{1, 2, 3, 4, 5, 6},
+		},
+	})
+
+	// NB: downscaling by 2 merges every 4 buckets, the buckets at indexes
+	// [-5, 0] fall into the merged buckets at indexes -2, -1 and 0.
+	assert.Equal(t, int32(8), histogram.Schema)
+	assert.Equal(t, []prompb.BucketSpan{{Offset: -1, Length: 3}}, histogram.PositiveSpans)
+	assert.Equal(t, []float64{1, 14, 6}, histogram.PositiveCounts)
+	assert.Empty(t, histogram.NegativeSpans)
+}
+
+func TestSanitizeNames(t *testing.T) {
+	assert.Equal(t, "http_server_duration", sanitizeMetricName("http.server.duration"))
+	assert.Equal(t, "ns:metric_name", sanitizeMetricName("ns:metric-name"))
+	assert.Equal(t, "_2xx_responses", sanitizeMetricName("2xx.responses"))
+	assert.Equal(t, "k8s_pod_name", sanitizeLabelName("k8s.pod.name"))
+	assert.Equal(t, "a_b", sanitizeLabelName("a:b"))
+	assert.Equal(t, "", sanitizeLabelName(""))
+}
+
+func TestAnyValueString(t *testing.T) {
+	for _, test := range []struct {
+		value    otlppb.AnyValue
+		expected string
+	}{
+		{
+			value:    otlppb.AnyValue{Value: &otlppb.AnyValue_BoolValue{BoolValue: true}},
+			expected: "true",
+		},
+		{
+			value:    otlppb.AnyValue{Value: &otlppb.AnyValue_IntValue{IntValue: -7}},
+			expected: "-7",
+		},
+		{
+			value:    otlppb.AnyValue{Value: &otlppb.AnyValue_DoubleValue{DoubleValue: math.Inf(1)}},
+			expected: "+Inf",
+		},
+		{
+			value: otlppb.AnyValue{Value: &otlppb.AnyValue_ArrayValue{ArrayValue: &otlppb.ArrayValue{
+				Values: []otlppb.AnyValue{
+					{Value: &otlppb.AnyValue_StringValue{StringValue: "a"}},
+					{Value: &otlppb.AnyValue_IntValue{IntValue: 1}},
+				},
+			}}},
+			expected: `["a",1]`,
+		},
+		{
+			value: otlppb.AnyValue{Value: &otlppb.AnyValue_KvlistValue{KvlistValue: &otlppb.KeyValueList{
+				Values: []otlppb.KeyValue{stringAttribute("k", "v")},
+			}}},
+			expected: `{"k":"v"}`,
+		},
+	} {
+		assert.Equal(t, test.expected, anyValueString(test.value))
+	}
+}
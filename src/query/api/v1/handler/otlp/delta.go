@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/x/clock"
+)
+
+// deltaAccumulator converts the samples of series with delta temporality to
+// the running total of each series so they can be stored like any other
+// cumulative Prometheus series. Series that are not written to within the
+// expiry are forgotten, their totals restart from zero which queries
+// handle as a counter reset.
+type deltaAccumulator struct {
+	sync.Mutex
+
+	expiry    time.Duration
+	nowFn     clock.NowFn
+	series    map[string]*deltaSeries
+	lastSweep time.Time
+}
+
+type deltaSeries struct {
+	total         float64
+	lastTimestamp int64
+	lastWrite     time.Time
+}
+
+func newDeltaAccumulator(expiry time.Duration, nowFn clock.NowFn) *deltaAccumulator {
+	return &deltaAccumulator{
+		expiry:    expiry,
+		nowFn:     nowFn,
+		series:    make(map[string]*deltaSeries),
+		lastSweep: nowFn(),
+	}
+}
+
+// accumulate adds a delta sample to the running total of a series and returns
+// the new total, it returns false if the sample is not newer than the last
+// sample of the series since it can no longer be added in order.
+func (a *deltaAccumulator) accumulate(
+	key string,
+	timestamp int64,
+	delta float64,
+) (float64, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	now := a.nowFn()
+	if now.Sub(a.lastSweep) >= a.expiry {
+		a.sweepWithLock(now)
+	}
+
+	series, ok := a.series[key]
+	if !ok {
+		series = &deltaSeries{}
+		a.series[key] = series
+	} else if timestamp <= series.lastTimestamp {
+		return 0, false
+	}
+
+	series.total += delta
+	series.lastTimestamp = timestamp
+	series.lastWrite = now
+	return series.total, true
+}
+
+func (a *deltaAccumulator) sweepWithLock(now time.Time) {
+	for key, series := range a.series {
+		if now.Sub(series.lastWrite) >= a.expiry {
+			delete(a.series, key)
+		}
+	}
+	a.lastSweep = now
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeltaAccumulator(t *testing.T) {
+	now := time.Unix(0, 0)
+	nowFn := func() time.Time { return now }
+	a := newDeltaAccumulator(time.Minute, nowFn)
+
+	total, ok := a.accumulate("a", 1000, 1.5)
+	require.True(t, ok)
+	assert.Equal(t, 1.5, total)
+
+	total, ok = a.accumulate("a", 2000, 2)
+	require.True(t, ok)
+	assert.Equal(t, 3.5, total)
+
+	// Samples not newer than the last sample are rejected.
+	_, ok = a.accumulate("a", 2000, 1)
+	assert.False(t, ok)
+
+	total, ok = a.accumulate("b", 1000, 1)
+	require.True(t, ok)
+	assert.Equal(t, 1.0, total)
+
+	// Series not written to within the expiry are forgotten.
+	now = now.Add(30 * time.Second)
+	_, ok = a.accumulate("b", 3000, 1)
+	require.True(t, ok)
+
+	now = now.Add(40 * time.Second)
+	total, ok = a.accumulate("a", 1000, 4)
+	require.True(t, ok)
+	assert.Equal(t, 4.0, total)
+	assert.Len(t, a.series, 2)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = a.accumulate("c", 1000, 1)
+	require.True(t, ok)
+	assert.Len(t, a.series, 1)
+}
@@ -0,0 +1,476 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package otlp contains the OpenTelemetry protocol (OTLP) metrics receiver.
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// WriteURL is the OTLP/HTTP metrics write URL. OTLP exporters append the
+	// "/v1/metrics" signal path to their endpoint, so should be configured
+	// with the endpoint route.Prefix + "/otlp".
+	WriteURL = route.Prefix + "/otlp/v1/metrics"
+
+	// WriteHTTPMethod is the HTTP method used with this resource.
+	WriteHTTPMethod = http.MethodPost
+
+	// DefaultDeltaExpiry is the default time the running total of a series
+	// with delta temporality is kept after it was last written.
+	DefaultDeltaExpiry = 5 * time.Minute
+
+	contentEncodingGzip = "gzip"
+)
+
+var (
+	defaultValue = ingest.IterValue{
+		Tags:       models.EmptyTags(),
+		Attributes: ts.DefaultSeriesAttributes(),
+		Metadata:   ts.Metadata{},
+	}
+
+	jsonUnmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
+	jsonMarshaler   = &jsonpb.Marshaler{}
+)
+
+// Receiver converts OTLP metrics to M3 series and writes them using the
+// downsampler and writer, it serves both OTLP/HTTP and OTLP/gRPC requests.
+//
+// Gauges and non-monotonic sums are written as Prometheus gauges, monotonic
+// sums as Prometheus counters, and explicit bucket and exponential histograms
+// as classic Prometheus histograms. Series with delta temporality are
+// converted to cumulative series so they can be queried like series
+// written with Prometheus remote write.
+type Receiver struct {
+	downsamplerAndWriter ingest.DownsamplerAndWriter
+	metricMetadata       metricmetadata.Store
+	tagOpts              models.TagOptions
+	storeMetricsType     bool
+	converter            *converter
+	instrumentOpts       instrument.Options
+	metrics              receiverMetrics
+}
+
+var _ otlppb.MetricsServiceServer = (*Receiver)(nil)
+
+type receiverMetrics struct {
+	writeSuccess       tally.Counter
+	writeErrorsServer  tally.Counter
+	writeErrorsClient  tally.Counter
+	datapointsRejected tally.Counter
+	metadataErrors     tally.Counter
+}
+
+func newReceiverMetrics(scope tally.Scope) receiverMetrics {
+	return receiverMetrics{
+		writeSuccess:       scope.SubScope("write").Counter("success"),
+		writeErrorsServer:  scope.SubScope("write").Tagged(map[string]string{"code": "5XX"}).Counter("errors"),
+		writeErrorsClient:  scope.SubScope("write").Tagged(map[string]string{"code": "4XX"}).Counter("errors"),
+		datapointsRejected: scope.SubScope("datapoints").Counter("rejected"),
+		metadataErrors:     scope.SubScope("metadata").Counter("errors"),
+	}
+}
+
+// NewReceiver returns a new OTLP metrics receiver.
+func NewReceiver(opts options.HandlerOptions) *Receiver {
+	var (
+		cfg         = opts.Config().OTLP
+		deltaExpiry = DefaultDeltaExpiry
+		iOpts       = opts.InstrumentOpts()
+	)
+	if cfg.DeltaExpiry != nil {
+		deltaExpiry = *cfg.DeltaExpiry
+	}
+
+	return &Receiver{
+		downsamplerAndWriter: opts.DownsamplerAndWriter(),
+		metricMetadata:       opts.MetricMetadataStore(),
+		tagOpts:              opts.TagOptions(),
+		storeMetricsType:     opts.StoreMetricsType(),
+		converter: newConverter(cfg.PromoteResourceAttributes,
+			newDeltaAccumulator(deltaExpiry, opts.NowFn())),
+		instrumentOpts: iOpts,
+		metrics:        newReceiverMetrics(iOpts.MetricsScope().SubScope("otlp")),
+	}
+}
+
+// Export implements the OTLP/gRPC metrics service.
+func (r *Receiver) Export(
+	ctx context.Context,
+	req *otlppb.ExportMetricsServiceRequest,
+) (*otlppb.ExportMetricsServiceResponse, error) {
+	resp, err := r.write(ctx, req)
+	if err != nil {
+		// NB: OTLP exporters retry unavailable errors but not invalid
+		// argument errors.
+		code := codes.Unavailable
+		if xerrors.IsInvalidParams(err) {
+			code = codes.InvalidArgument
+		}
+		return nil, status.Error(code, err.Error())
+	}
+	return resp, nil
+}
+
+func (r *Receiver) write(
+	ctx context.Context,
+	req *otlppb.ExportMetricsServiceRequest,
+) (*otlppb.ExportMetricsServiceResponse, error) {
+	result := r.converter.convert(req)
+
+	resp := &otlppb.ExportMetricsServiceResponse{}
+	if result.rejected > 0 {
+		r.metrics.datapointsRejected.Inc(result.rejected)
+		resp.PartialSuccess = otlppb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: result.rejected,
+			ErrorMessage:       result.lastErr.Error(),
+		}
+	}
+
+	if len(result.metadata) > 0 && r.metricMetadata != nil {
+		// NB: metadata is best effort, failing to store it does not fail
+		// the write of the series themselves.
+		if err := r.metricMetadata.Write(result.metadata); err != nil {
+			r.metrics.metadataErrors.Inc(1)
+			logging.WithContext(ctx, r.instrumentOpts).
+				Warn("unable to store otlp metric metadata", zap.Error(err))
+		}
+	}
+
+	if len(result.series) == 0 {
+		r.metrics.writeSuccess.Inc(1)
+		return resp, nil
+	}
+
+	iter, err := newSeriesIter(result.series, r.tagOpts, r.storeMetricsType)
+	if err != nil {
+		r.metrics.writeErrorsClient.Inc(1)
+		return nil, xerrors.NewInvalidParamsError(err)
+	}
+
+	batchErr := r.downsamplerAndWriter.WriteBatch(ctx, iter, ingest.WriteOptions{})
+	if batchErr != nil {
+		err := r.batchError(ctx, batchErr)
+		if xerrors.IsInvalidParams(err) {
+			r.metrics.writeErrorsClient.Inc(1)
+		} else {
+			r.metrics.writeErrorsServer.Inc(1)
+		}
+		return nil, err
+	}
+
+	r.metrics.writeSuccess.Inc(1)
+	return resp, nil
+}
+
+// batchError returns the error to respond with for a failed batch write, the
+// error is an invalid params error only if all errors were bad requests.
+func (r *Receiver) batchError(ctx context.Context, batchErr ingest.BatchError) error {
+	var (
+		errs              = batchErr.Errors()
+		lastRegularErr    error
+		lastBadRequestErr error
+		numRegular        int
+		numBadRequest     int
+	)
+	for _, err := range errs {
+		switch {
+		case client.IsBadRequestError(err), xerrors.IsInvalidParams(err):
+			numBadRequest++
+			lastBadRequestErr = err
+		default:
+			numRegular++
+			lastRegularErr = err
+		}
+	}
+
+	logger := logging.WithContext(ctx, r.instrumentOpts)
+	logger.Error("otlp write error",
+		zap.Int("numRegularErrors", numRegular),
+		zap.Int("numBadRequestErrors", numBadRequest),
+		zap.NamedError("lastRegularError", lastRegularErr),
+		zap.NamedError("lastBadRequestErr", lastBadRequestErr))
+
+	if numBadRequest == len(errs) {
+		return xerrors.NewInvalidParamsError(fmt.Errorf(
+			"bad_request_errors: count=%d, last=%v", numBadRequest, lastBadRequestErr))
+	}
+	return fmt.Errorf("retryable_errors: count=%d, last=%v", numRegular, lastRegularErr)
+}
+
+type writeHandler struct {
+	receiver *Receiver
+}
+
+// NewWriteHandler returns a new OTLP/HTTP metrics write handler, requests are
+// accepted in both the binary protobuf and JSON encodings. The receiver should
+// be the same one that serves OTLP/gRPC requests so that series with delta
+// temporality written over either transport share the same running totals.
+func NewWriteHandler(receiver *Receiver) http.Handler {
+	return &writeHandler{receiver: receiver}
+}
+
+func (h *writeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType, err := parseContentType(r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	var req otlppb.ExportMetricsServiceRequest
+	if contentType == xhttp.ContentTypeJSON {
+		err = jsonUnmarshaler.Unmarshal(bytes.NewReader(body), &req)
+	} else {
+		err = req.Unmarshal(body)
+	}
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(
+			fmt.Errorf("unable to decode otlp request: %v", err)))
+		return
+	}
+
+	resp, err := h.receiver.write(r.Context(), &req)
+	if err != nil {
+		if !xerrors.IsInvalidParams(err) {
+			// NB: OTLP exporters retry service unavailable responses.
+			err = xhttp.NewError(err, http.StatusServiceUnavailable)
+		}
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	var data []byte
+	if contentType == xhttp.ContentTypeJSON {
+		var buf bytes.Buffer
+		err = jsonMarshaler.Marshal(&buf, resp)
+		data = buf.Bytes()
+	} else {
+		data, err = resp.Marshal()
+	}
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set(xhttp.HeaderContentType, contentType)
+	w.Write(data) //nolint:errcheck
+}
+
+func parseContentType(r *http.Request) (string, error) {
+	contentType := r.Header.Get(xhttp.HeaderContentType)
+	if contentType == "" {
+		return xhttp.ContentTypeProtobuf, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", xerrors.NewInvalidParamsError(err)
+	}
+
+	switch mediaType {
+	case xhttp.ContentTypeProtobuf, xhttp.ContentTypeJSON:
+		return mediaType, nil
+	}
+	return "", xhttp.NewError(fmt.Errorf("unsupported content type: %s", mediaType),
+		http.StatusUnsupportedMediaType)
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	switch encoding := r.Header.Get("Content-Encoding"); encoding {
+	case "", "identity":
+	case contentEncodingGzip:
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, xerrors.NewInvalidParamsError(err)
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	default:
+		return nil, xhttp.NewError(fmt.Errorf("unsupported content encoding: %s", encoding),
+			http.StatusUnsupportedMediaType)
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		if errors.Is(err, gzip.ErrChecksum) || errors.Is(err, gzip.ErrHeader) {
+			return nil, xerrors.NewInvalidParamsError(err)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// seriesIter iterates over the converted series of an OTLP request.
+type seriesIter struct {
+	idx        int
+	err        error
+	attributes []ts.SeriesAttributes
+	tags       []models.Tags
+	datapoints []ts.Datapoints
+	metadatas  []ts.Metadata
+	annotation []byte
+
+	storeMetricsType bool
+}
+
+func newSeriesIter(
+	series []prompb.TimeSeries,
+	tagOpts models.TagOptions,
+	storeMetricsType bool,
+) (*seriesIter, error) {
+	var (
+		tags       = make([]models.Tags, 0, len(series))
+		datapoints = make([]ts.Datapoints, 0, len(series))
+		attributes = make([]ts.SeriesAttributes, 0, len(series))
+	)
+	for _, s := range series {
+		attrs, err := storage.PromTimeSeriesToSeriesAttributes(s)
+		if err != nil {
+			return nil, err
+		}
+
+		attributes = append(attributes, attrs)
+		tags = append(tags, storage.PromLabelsToM3Tags(s.Labels, tagOpts))
+		datapoints = append(datapoints, storage.PromSamplesToM3Datapoints(s.Samples))
+	}
+
+	return &seriesIter{
+		idx:              -1,
+		attributes:       attributes,
+		tags:             tags,
+		datapoints:       datapoints,
+		storeMetricsType: storeMetricsType,
+	}, nil
+}
+
+func (i *seriesIter) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	i.idx++
+	if i.idx >= len(i.tags) {
+		return false
+	}
+
+	if !i.storeMetricsType {
+		return true
+	}
+
+	payload, err := storage.SeriesAttributesToAnnotationPayload(i.attributes[i.idx])
+	if err != nil {
+		i.err = err
+		return false
+	}
+
+	i.annotation, err = payload.Marshal()
+	if err != nil {
+		i.err = err
+		return false
+	}
+
+	return true
+}
+
+func (i *seriesIter) Current() ingest.IterValue {
+	if i.idx < 0 || i.idx >= len(i.tags) {
+		return defaultValue
+	}
+
+	value := ingest.IterValue{
+		Tags:       i.tags[i.idx],
+		Datapoints: i.datapoints[i.idx],
+		Attributes: i.attributes[i.idx],
+		Unit:       xtime.Millisecond,
+		Annotation: i.annotation,
+	}
+	if i.idx < len(i.metadatas) {
+		value.Metadata = i.metadatas[i.idx]
+	}
+	return value
+}
+
+func (i *seriesIter) Reset() error {
+	i.idx = -1
+	i.err = nil
+	i.annotation = nil
+	return nil
+}
+
+func (i *seriesIter) Error() error {
+	return i.err
+}
+
+func (i *seriesIter) SetCurrentMetadata(metadata ts.Metadata) {
+	if len(i.metadatas) == 0 {
+		i.metadatas = make([]ts.Metadata, len(i.tags))
+	}
+	if i.idx < 0 || i.idx >= len(i.metadatas) {
+		return
+	}
+	i.metadatas[i.idx] = metadata
+}
+
+func (i *seriesIter) CurrentMetadata() ts.Metadata {
+	if i.idx < 0 || i.idx >= len(i.metadatas) {
+		return ts.Metadata{}
+	}
+	return i.metadatas[i.idx]
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	"github.com/m3db/m3/src/query/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func makeOptions(ds ingest.DownsamplerAndWriter) options.HandlerOptions {
+	return options.EmptyHandlerOptions().
+		SetNowFn(time.Now).
+		SetDownsamplerAndWriter(ds).
+		SetTagOptions(models.NewTagOptions()).
+		SetConfig(config.Configuration{
+			OTLP: config.OTLPConfiguration{
+				PromoteResourceAttributes: []string{"host.name"},
+			},
+		}).
+		SetMetricMetadataStore(metricmetadata.NewStore(metricmetadata.NewOptions())).
+		SetStoreMetricsType(true)
+}
+
+func gaugeRequest() *otlppb.ExportMetricsServiceRequest {
+	return testRequest(otlppb.Metric{
+		Name: "system.cpu.utilization",
+		Unit: "1",
+		Data: &otlppb.Metric_Gauge{Gauge: &otlppb.Gauge{
+			DataPoints: []otlppb.NumberDataPoint{
+				{
+					TimeUnixNano: timeUnixNano(0),
+					Value:        &otlppb.NumberDataPoint_AsDouble{AsDouble: 0.25},
+				},
+			},
+		}},
+	})
+}
+
+func expectGaugeWrite(t *testing.T, ds *ingest.MockDownsamplerAndWriter) {
+	ds.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			_ ingest.WriteOptions,
+		) ingest.BatchError {
+			require.True(t, iter.Next())
+			value := iter.Current()
+
+			name, ok := value.Tags.Name()
+			require.True(t, ok)
+			assert.Equal(t, "system_cpu_utilization", string(name))
+			job, ok := value.Tags.Get([]byte("job"))
+			require.True(t, ok)
+			assert.Equal(t, "shop/api", string(job))
+			assert.Equal(t, ts.PromMetricTypeGauge, value.Attributes.PromType)
+			assert.NotEmpty(t, value.Annotation)
+			require.Len(t, value.Datapoints, 1)
+			assert.Equal(t, 0.25, value.Datapoints[0].Value)
+			assert.True(t, testTime.Equal(value.Datapoints[0].Timestamp.ToTime()))
+
+			require.False(t, iter.Next())
+			return nil
+		})
+}
+
+func TestWriteHandlerProtobuf(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	ds := ingest.NewMockDownsamplerAndWriter(ctrl)
+	expectGaugeWrite(t, ds)
+
+	opts := makeOptions(ds)
+	handler := NewWriteHandler(NewReceiver(opts))
+
+	data, err := gaugeRequest().Marshal()
+	require.NoError(t, err)
+
+	var body bytes.Buffer
+	gzipWriter := gzip.NewWriter(&body)
+	_, err = gzipWriter.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL, &body)
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeProtobuf)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, xhttp.ContentTypeProtobuf, resp.Header.Get(xhttp.HeaderContentType))
+
+	var exportResp otlppb.ExportMetricsServiceResponse
+	require.NoError(t, exportResp.Unmarshal(writer.Body.Bytes()))
+	assert.Equal(t, int64(0), exportResp.PartialSuccess.RejectedDataPoints)
+
+	metadata, err := opts.MetricMetadataStore().Metadata("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]metricmetadata.Metadata{
+		"system_cpu_utilization": {Type: prompb.MetricType_GAUGE, Unit: "1"},
+	}, metadata)
+}
+
+func TestWriteHandlerJSON(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	ds := ingest.NewMockDownsamplerAndWriter(ctrl)
+	expectGaugeWrite(t, ds)
+
+	handler := NewWriteHandler(NewReceiver(makeOptions(ds)))
+
+	// NB: OTLP/JSON encodes 64 bit integers as strings and may contain
+	// fields unknown to the receiver such as exemplars.
+	body := `{
+		"resourceMetrics": [{
+			"resource": {"attributes": [
+				{"key": "service.name", "value": {"stringValue": "api"}},
+				{"key": "service.namespace", "value": {"stringValue": "shop"}}
+			]},
+			"scopeMetrics": [{
+				"scope": {"name": "test"},
+				"metrics": [{
+					"name": "system.cpu.utilization",
+					"unit": "1",
+					"gauge": {"dataPoints": [{
+						"timeUnixNano": "1600000000000000000",
+						"asDouble": 0.25,
+						"exemplars": []
+					}]}
+				}]
+			}]
+		}],
+		"unknownField": true
+	}`
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL, strings.NewReader(body))
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, writer.Body.String())
+	assert.Equal(t, xhttp.ContentTypeJSON, resp.Header.Get(xhttp.HeaderContentType))
+}
+
+func TestWriteHandlerPartialSuccess(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	// NB: no series are written since all data points are rejected.
+	ds := ingest.NewMockDownsamplerAndWriter(ctrl)
+	handler := NewWriteHandler(NewReceiver(makeOptions(ds)))
+
+	data, err := testRequest(otlppb.Metric{
+		Name: "requests",
+		Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+			DataPoints: []otlppb.NumberDataPoint{
+				{TimeUnixNano: timeUnixNano(0), Value: &otlppb.NumberDataPoint_AsInt{AsInt: 1}},
+			},
+		}},
+	}).Marshal()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL, bytes.NewReader(data))
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusOK, writer.Result().StatusCode)
+
+	var exportResp otlppb.ExportMetricsServiceResponse
+	require.NoError(t, exportResp.Unmarshal(writer.Body.Bytes()))
+	assert.Equal(t, int64(1), exportResp.PartialSuccess.RejectedDataPoints)
+	assert.Contains(t, exportResp.PartialSuccess.ErrorMessage, "temporality")
+}
+
+func TestWriteHandlerErrors(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	data, err := gaugeRequest().Marshal()
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name            string
+		contentType     string
+		contentEncoding string
+		body            []byte
+		writeErr        error
+		expectedStatus  int
+	}{
+		{
+			name:           "unsupported content type",
+			contentType:    "text/plain",
+			body:           data,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:            "unsupported content encoding",
+			contentEncoding: "snappy",
+			body:            data,
+			expectedStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "invalid body",
+			body:           []byte("invalid"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bad request write error",
+			body:           data,
+			writeErr:       xerrors.NewInvalidParamsError(errors.New("bad")),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "write error",
+			body:           data,
+			writeErr:       errors.New("unavailable"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ds := ingest.NewMockDownsamplerAndWriter(ctrl)
+			if test.writeErr != nil {
+				ds.EXPECT().
+					WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(ingest.BatchError(xerrors.NewMultiError().Add(test.writeErr)))
+			}
+
+			handler := NewWriteHandler(NewReceiver(makeOptions(ds)))
+			req := httptest.NewRequest(WriteHTTPMethod, WriteURL, bytes.NewReader(test.body))
+			if test.contentType != "" {
+				req.Header.Set(xhttp.HeaderContentType, test.contentType)
+			}
+			if test.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", test.contentEncoding)
+			}
+
+			writer := httptest.NewRecorder()
+			handler.ServeHTTP(writer, req)
+			assert.Equal(t, test.expectedStatus, writer.Result().StatusCode)
+		})
+	}
+}
+
+func TestReceiverExport(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	ds := ingest.NewMockDownsamplerAndWriter(ctrl)
+	expectGaugeWrite(t, ds)
+	ds.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(ingest.BatchError(xerrors.NewMultiError().
+			Add(xerrors.NewInvalidParamsError(errors.New("bad")))))
+	ds.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(ingest.BatchError(xerrors.NewMultiError().Add(errors.New("unavailable"))))
+
+	receiver := NewReceiver(makeOptions(ds))
+
+	resp, err := receiver.Export(context.Background(), gaugeRequest())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), resp.PartialSuccess.RejectedDataPoints)
+
+	_, err = receiver.Export(context.Background(), gaugeRequest())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = receiver.Export(context.Background(), gaugeRequest())
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestWriteHandlerAndReceiverShareDeltaTotals(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	deltaRequest := func(offset time.Duration, value int64) *otlppb.ExportMetricsServiceRequest {
+		return testRequest(otlppb.Metric{
+			Name: "requests",
+			Data: &otlppb.Metric_Sum{Sum: &otlppb.Sum{
+				AggregationTemporality: otlppb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				IsMonotonic:            true,
+				DataPoints: []otlppb.NumberDataPoint{
+					{
+						TimeUnixNano: timeUnixNano(offset),
+						Value:        &otlppb.NumberDataPoint_AsInt{AsInt: value},
+					},
+				},
+			}},
+		})
+	}
+
+	var written []float64
+	ds := ingest.NewMockDownsamplerAndWriter(ctrl)
+	ds.EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			iter ingest.DownsampleAndWriteIter,
+			_ ingest.WriteOptions,
+		) ingest.BatchError {
+			for iter.Next() {
+				for _, dp := range iter.Current().Datapoints {
+					written = append(written, dp.Value)
+				}
+			}
+			return nil
+		}).
+		Times(2)
+
+	receiver := NewReceiver(makeOptions(ds))
+	handler := NewWriteHandler(receiver)
+
+	data, err := deltaRequest(0, 5).Marshal()
+	require.NoError(t, err)
+	req := httptest.NewRequest(WriteHTTPMethod, WriteURL, bytes.NewReader(data))
+	req.Header.Set(xhttp.HeaderContentType, xhttp.ContentTypeProtobuf)
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusOK, writer.Result().StatusCode)
+
+	_, err = receiver.Export(context.Background(), deltaRequest(time.Second, 3))
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{5, 8}, written)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DeleteSeriesURL is the url for deleting series.
+	DeleteSeriesURL = route.Prefix + "/admin/tsdb/delete_series"
+)
+
+var (
+	// DeleteSeriesHTTPMethods are the HTTP methods for this handler.
+	DeleteSeriesHTTPMethods = []string{http.MethodPost, http.MethodPut}
+
+	errNoDeleteSeriesMatchers = errors.New("no match[] parameter provided")
+	errNoDeleteSeriesClusters = errors.New(
+		"deleting series requires local M3DB namespaces")
+)
+
+// DeleteSeriesHandler is a handler for the Prometheus delete series admin
+// endpoint, it deletes the data of all series selected by the matchers
+// within a time range from every local namespace.
+type DeleteSeriesHandler struct {
+	clusters       m3.Clusters
+	parseOpts      promql.ParseOptions
+	instrumentOpts instrument.Options
+	tagOpts        models.TagOptions
+}
+
+// NewDeleteSeriesHandler returns a new delete series handler.
+func NewDeleteSeriesHandler(opts options.HandlerOptions) http.Handler {
+	return &DeleteSeriesHandler{
+		clusters: opts.Clusters(),
+		parseOpts: promql.NewParseOptions().
+			SetNowFn(opts.NowFn()),
+		instrumentOpts: opts.InstrumentOpts(),
+		tagOpts:        opts.TagOptions(),
+	}
+}
+
+func (h *DeleteSeriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	start, end, err := prometheus.ParseStartAndEnd(r, h.parseOpts)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	matchers, ok, err := prometheus.ParseMatch(r, h.parseOpts, h.tagOpts)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+	if !ok {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(errNoDeleteSeriesMatchers))
+		return
+	}
+
+	if h.clusters == nil {
+		xhttp.WriteError(w, errNoDeleteSeriesClusters)
+		return
+	}
+	namespaces := h.clusters.ClusterNamespaces()
+	if len(namespaces) == 0 {
+		xhttp.WriteError(w, errNoDeleteSeriesClusters)
+		return
+	}
+
+	var (
+		logger = logging.WithContext(r.Context(), h.instrumentOpts)
+		// NB: the Prometheus API treats end as inclusive whereas deletions
+		// exclude their end.
+		rangeStart = xtime.ToUnixNano(start)
+		rangeEnd   = xtime.ToUnixNano(end).Add(time.Nanosecond)
+	)
+	for _, m := range matchers {
+		query, err := storage.FetchQueryToM3Query(&storage.FetchQuery{
+			TagMatchers: m.Matchers,
+			Start:       start,
+			End:         end,
+		}, storage.NewFetchOptions())
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+
+		for _, namespace := range namespaces {
+			deleted, err := namespace.Session().DeleteTagged(
+				namespace.NamespaceID(), query, rangeStart, rangeEnd)
+			if err != nil {
+				logger.Error("unable to delete series",
+					zap.String("match", m.Match),
+					zap.Stringer("namespace", namespace.NamespaceID()),
+					zap.Error(err))
+				xhttp.WriteError(w, err)
+				return
+			}
+
+			logger.Info("deleted series",
+				zap.String("match", m.Match),
+				zap.Stringer("namespace", namespace.NamespaceID()),
+				zap.Int64("series", deleted),
+				zap.Time("start", start),
+				zap.Time("end", end))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
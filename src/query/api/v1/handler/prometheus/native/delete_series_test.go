@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func newTestDeleteSeriesHandler(
+	t *testing.T,
+	ctrl *gomock.Controller,
+) (http.Handler, *client.MockSession) {
+	session := client.NewMockSession(ctrl)
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("default"),
+		Session:     session,
+		Retention:   48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	opts := options.EmptyHandlerOptions().
+		SetClusters(clusters).
+		SetTagOptions(models.NewTagOptions())
+	return NewDeleteSeriesHandler(opts), session
+}
+
+func TestDeleteSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, session := newTestDeleteSeriesHandler(t, ctrl)
+
+	expected := index.Query{
+		Query: idx.NewConjunctionQuery(
+			idx.NewTermQuery([]byte("job"), []byte("bar")),
+			idx.NewTermQuery([]byte("__name__"), []byte("foo")),
+		),
+	}
+	session.EXPECT().
+		DeleteTagged(
+			ident.NewIDMatcher("default"),
+			index.NewQueryMatcher(expected),
+			xtime.FromSeconds(100),
+			xtime.FromSeconds(200).Add(time.Nanosecond),
+		).
+		Return(int64(2), nil)
+
+	form := url.Values{}
+	form.Set("match[]", `foo{job="bar"}`)
+	form.Set("start", "100")
+	form.Set("end", "200")
+	req := httptest.NewRequest(http.MethodPost, DeleteSeriesURL,
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	resp := w.Result()
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestDeleteSeriesInvalidRequest(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, _ := newTestDeleteSeriesHandler(t, ctrl)
+
+	for _, target := range []string{
+		DeleteSeriesURL,
+		DeleteSeriesURL + "?match[]=foo{",
+		DeleteSeriesURL + "?match[]=foo&start=200&end=100",
+	} {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		resp := w.Result()
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+	}
+}
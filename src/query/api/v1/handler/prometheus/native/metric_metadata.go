@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	promparser "github.com/prometheus/prometheus/promql/parser"
+	"go.uber.org/zap"
+)
+
+const (
+	// MetricMetadataURL is the url for querying metric metadata.
+	MetricMetadataURL = route.Prefix + "/metadata"
+
+	// TargetsMetadataURL is the url for querying metric metadata by target.
+	TargetsMetadataURL = route.Prefix + "/targets/metadata"
+
+	metricParam         = "metric"
+	limitParam          = "limit"
+	limitPerMetricParam = "limit_per_metric"
+	matchTargetParam    = "match_target"
+)
+
+var (
+	// MetricMetadataHTTPMethods are the HTTP methods for the metric
+	// metadata handlers.
+	MetricMetadataHTTPMethods = []string{http.MethodGet}
+
+	metricTypeNames = map[prompb.MetricType]string{
+		prompb.MetricType_UNKNOWN:         "unknown",
+		prompb.MetricType_COUNTER:         "counter",
+		prompb.MetricType_GAUGE:           "gauge",
+		prompb.MetricType_HISTOGRAM:       "histogram",
+		prompb.MetricType_GAUGE_HISTOGRAM: "gaugehistogram",
+		prompb.MetricType_SUMMARY:         "summary",
+		prompb.MetricType_INFO:            "info",
+		prompb.MetricType_STATESET:        "stateset",
+	}
+)
+
+type metricMetadataValue struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+type targetMetadataValue struct {
+	Target map[string]string `json:"target"`
+	Metric string            `json:"metric,omitempty"`
+	Type   string            `json:"type"`
+	Help   string            `json:"help"`
+	Unit   string            `json:"unit"`
+}
+
+type metadataResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
+
+// MetricMetadataHandler is a handler for the Prometheus metric metadata
+// endpoints, it returns the metadata of metric families written with remote
+// write.
+type MetricMetadataHandler struct {
+	store          metricmetadata.Store
+	targets        bool
+	instrumentOpts instrument.Options
+}
+
+// NewMetricMetadataHandler returns a new handler for the metric metadata
+// endpoint.
+func NewMetricMetadataHandler(opts options.HandlerOptions) http.Handler {
+	return &MetricMetadataHandler{
+		store:          opts.MetricMetadataStore(),
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+// NewTargetsMetadataHandler returns a new handler for the targets metadata
+// endpoint. Remote write does not include the target that metadata was
+// scraped from so all metadata is returned with an empty target.
+func NewTargetsMetadataHandler(opts options.HandlerOptions) http.Handler {
+	return &MetricMetadataHandler{
+		store:          opts.MetricMetadataStore(),
+		targets:        true,
+		instrumentOpts: opts.InstrumentOpts(),
+	}
+}
+
+func (h *MetricMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	if err := r.ParseForm(); err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	limit, err := parseMetadataLimit(r, limitParam)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	var matchTarget []*labels.Matcher
+	if v := r.FormValue(matchTargetParam); h.targets && v != "" {
+		matchTarget, err = promparser.ParseMetricSelector(v)
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+	}
+
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+
+	metric := r.FormValue(metricParam)
+	families := make(map[string]metricmetadata.Metadata)
+	if h.store != nil {
+		families, err = h.store.Metadata(metric)
+		if err != nil {
+			logger.Error("unable to get metric metadata", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if limit >= 0 && len(names) > limit {
+		names = names[:limit]
+	}
+
+	if !h.targets {
+		// NB: only the latest metadata of each metric family is stored so
+		// limit_per_metric only needs to be validated.
+		if _, err := parseMetadataLimit(r, limitPerMetricParam); err != nil {
+			xhttp.WriteError(w, err)
+			return
+		}
+
+		data := make(map[string][]metricMetadataValue, len(names))
+		for _, name := range names {
+			m := families[name]
+			data[name] = []metricMetadataValue{{
+				Type: metricTypeNames[m.Type],
+				Help: m.Help,
+				Unit: m.Unit,
+			}}
+		}
+		xhttp.WriteJSONResponse(w, metadataResponse{Status: "success", Data: data}, logger)
+		return
+	}
+
+	// NB: metadata has no target so only matchers that match an empty
+	// label value can match.
+	for _, m := range matchTarget {
+		if !m.Matches("") {
+			names = nil
+			break
+		}
+	}
+
+	data := make([]targetMetadataValue, 0, len(names))
+	for _, name := range names {
+		m := families[name]
+		value := targetMetadataValue{
+			Target: map[string]string{},
+			Type:   metricTypeNames[m.Type],
+			Help:   m.Help,
+			Unit:   m.Unit,
+		}
+		if metric == "" {
+			value.Metric = name
+		}
+		data = append(data, value)
+	}
+	xhttp.WriteJSONResponse(w, metadataResponse{Status: "success", Data: data}, logger)
+}
+
+func parseMetadataLimit(r *http.Request, param string) (int, error) {
+	v := r.FormValue(param)
+	if v == "" {
+		return -1, nil
+	}
+
+	limit, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid %s: %v", param, err))
+	}
+	return limit, nil
+}
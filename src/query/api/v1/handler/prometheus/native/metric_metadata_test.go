@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
+)
+
+func newTestMetricMetadataOptions(t *testing.T) options.HandlerOptions {
+	store := metricmetadata.NewStore(metricmetadata.NewOptions())
+	require.NoError(t, store.Write([]prompb.MetricMetadata{
+		{
+			Type:             prompb.MetricType_COUNTER,
+			MetricFamilyName: "http_requests_total",
+			Help:             "Total HTTP requests.",
+		},
+		{
+			Type:             prompb.MetricType_HISTOGRAM,
+			MetricFamilyName: "http_request_duration_seconds",
+			Help:             "HTTP request latency.",
+			Unit:             "seconds",
+		},
+	}))
+	return options.EmptyHandlerOptions().SetMetricMetadataStore(store)
+}
+
+func serveMetricMetadata(
+	t *testing.T,
+	h http.Handler,
+	target string,
+) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close() // nolint:errcheck
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestMetricMetadata(t *testing.T) {
+	h := NewMetricMetadataHandler(newTestMetricMetadataOptions(t))
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{
+			target: "/metadata",
+			expected: `{"status":"success","data":{` +
+				`"http_request_duration_seconds":[{"type":"histogram","help":"HTTP request latency.","unit":"seconds"}],` +
+				`"http_requests_total":[{"type":"counter","help":"Total HTTP requests.","unit":""}]}}`,
+		},
+		{
+			target: "/metadata?metric=http_requests_total",
+			expected: `{"status":"success","data":{` +
+				`"http_requests_total":[{"type":"counter","help":"Total HTTP requests.","unit":""}]}}`,
+		},
+		{
+			target: "/metadata?limit=1",
+			expected: `{"status":"success","data":{` +
+				`"http_request_duration_seconds":[{"type":"histogram","help":"HTTP request latency.","unit":"seconds"}]}}`,
+		},
+		{
+			target:   "/metadata?metric=foo",
+			expected: `{"status":"success","data":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			code, body := serveMetricMetadata(t, h, tt.target)
+			require.Equal(t, http.StatusOK, code)
+			assert.Equal(t, tt.expected, body)
+		})
+	}
+
+	code, _ := serveMetricMetadata(t, h, "/metadata?limit=foo")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestTargetsMetadata(t *testing.T) {
+	h := NewTargetsMetadataHandler(newTestMetricMetadataOptions(t))
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{
+			target: "/targets/metadata?metric=http_requests_total",
+			expected: `{"status":"success","data":[` +
+				`{"target":{},"type":"counter","help":"Total HTTP requests.","unit":""}]}`,
+		},
+		{
+			target: "/targets/metadata?limit=1",
+			expected: `{"status":"success","data":[` +
+				`{"target":{},"metric":"http_request_duration_seconds","type":"histogram",` +
+				`"help":"HTTP request latency.","unit":"seconds"}]}`,
+		},
+		{
+			target:   `/targets/metadata?match_target={job="foo"}`,
+			expected: `{"status":"success","data":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			code, body := serveMetricMetadata(t, h, tt.target)
+			require.Equal(t, http.StatusOK, code)
+			assert.Equal(t, tt.expected, body)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/ident"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	promparser "github.com/prometheus/prometheus/promql/parser"
+	"go.uber.org/zap"
+)
+
+const (
+	// QueryExemplarsURL is the url for querying exemplars.
+	QueryExemplarsURL = route.Prefix + "/query_exemplars"
+
+	queryParam = "query"
+)
+
+var (
+	// QueryExemplarsHTTPMethods are the HTTP methods for this handler.
+	QueryExemplarsHTTPMethods = []string{http.MethodGet, http.MethodPost}
+
+	errNoExemplarsQuery   = errors.New("no query specified")
+	errNoExemplarsCluster = errors.New(
+		"exemplars require a local unaggregated M3DB namespace")
+)
+
+// QueryExemplarsHandler is a handler for the Prometheus query exemplars
+// endpoint, it returns the exemplars of all series selected by a query.
+type QueryExemplarsHandler struct {
+	storage             storage.Storage
+	clusters            m3.Clusters
+	fetchOptionsBuilder handleroptions.FetchOptionsBuilder
+	parseOpts           promql.ParseOptions
+	instrumentOpts      instrument.Options
+	tagOpts             models.TagOptions
+}
+
+// NewQueryExemplarsHandler returns a new query exemplars handler.
+func NewQueryExemplarsHandler(opts options.HandlerOptions) http.Handler {
+	return &QueryExemplarsHandler{
+		storage:             opts.Storage(),
+		clusters:            opts.Clusters(),
+		fetchOptionsBuilder: opts.FetchOptionsBuilder(),
+		parseOpts: promql.NewParseOptions().
+			SetNowFn(opts.NowFn()),
+		instrumentOpts: opts.InstrumentOpts(),
+		tagOpts:        opts.TagOptions(),
+	}
+}
+
+type exemplarsResponse struct {
+	Status string                 `json:"status"`
+	Data   []seriesExemplarsValue `json:"data"`
+}
+
+type seriesExemplarsValue struct {
+	SeriesLabels map[string]string `json:"seriesLabels"`
+	Exemplars    []exemplarValue   `json:"exemplars"`
+}
+
+type exemplarValue struct {
+	Labels    map[string]string `json:"labels"`
+	Value     string            `json:"value"`
+	Timestamp float64           `json:"timestamp"`
+}
+
+func (h *QueryExemplarsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	ctx, opts, rErr := h.fetchOptionsBuilder.NewFetchOptions(r.Context(), r)
+	if rErr != nil {
+		xhttp.WriteError(w, rErr)
+		return
+	}
+
+	start, end, err := prometheus.ParseStartAndEnd(r, h.parseOpts)
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	query := r.FormValue(queryParam)
+	if query == "" {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(errNoExemplarsQuery))
+		return
+	}
+
+	expr, err := promparser.ParseExpr(query)
+	if err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	if h.clusters == nil {
+		xhttp.WriteError(w, errNoExemplarsCluster)
+		return
+	}
+	namespace, ok := h.clusters.UnaggregatedClusterNamespace()
+	if !ok {
+		xhttp.WriteError(w, errNoExemplarsCluster)
+		return
+	}
+
+	logger := logging.WithContext(ctx, h.instrumentOpts)
+
+	var (
+		ids  []ident.ID
+		tags = make(map[string]models.Tags)
+	)
+	for _, selector := range promparser.ExtractSelectors(expr) {
+		matchers, err := promql.LabelMatchersToModelMatcher(selector, h.tagOpts)
+		if err != nil {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+
+		result, err := h.storage.SearchSeries(ctx, &storage.FetchQuery{
+			Raw:         query,
+			TagMatchers: matchers,
+			Start:       start,
+			End:         end,
+		}, opts)
+		if err != nil {
+			logger.Error("unable to search series for exemplars", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+
+		for _, metric := range result.Metrics {
+			id := string(metric.ID)
+			if _, ok := tags[id]; ok {
+				continue
+			}
+			tags[id] = metric.Tags
+			ids = append(ids, ident.StringID(id))
+		}
+	}
+
+	var series []client.SeriesExemplars
+	if len(ids) > 0 {
+		series, err = namespace.Session().FetchExemplars(namespace.NamespaceID(),
+			ids, xtime.ToUnixNano(start), xtime.ToUnixNano(end))
+		if err != nil {
+			logger.Error("unable to fetch exemplars", zap.Error(err))
+			xhttp.WriteError(w, err)
+			return
+		}
+	}
+
+	response := exemplarsResponse{
+		Status: "success",
+		Data:   make([]seriesExemplarsValue, 0, len(series)),
+	}
+	for _, s := range series {
+		response.Data = append(response.Data, seriesExemplarsValue{
+			SeriesLabels: tagsToLabels(tags[s.ID.String()]),
+			Exemplars:    renderExemplars(s.Exemplars),
+		})
+	}
+
+	xhttp.WriteJSONResponse(w, response, logger)
+}
+
+func tagsToLabels(tags models.Tags) map[string]string {
+	labels := make(map[string]string, tags.Len())
+	for _, tag := range tags.Tags {
+		labels[string(tag.Name)] = string(tag.Value)
+	}
+	return labels
+}
+
+func renderExemplars(exemplars []exemplar.Exemplar) []exemplarValue {
+	result := make([]exemplarValue, 0, len(exemplars))
+	for _, e := range exemplars {
+		labels := make(map[string]string, len(e.Labels))
+		for _, label := range e.Labels {
+			labels[label.Name] = label.Value
+		}
+		result = append(result, exemplarValue{
+			Labels:    labels,
+			Value:     strconv.FormatFloat(e.Value, 'f', -1, 64),
+			Timestamp: float64(e.Timestamp) / float64(time.Second),
+		})
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func newTestQueryExemplarsHandler(
+	t *testing.T,
+	ctrl *gomock.Controller,
+) (http.Handler, *storage.MockStorage, *client.MockSession) {
+	store := storage.NewMockStorage(ctrl)
+	session := client.NewMockSession(ctrl)
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("default"),
+		Session:     session,
+		Retention:   48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	fb, err := handleroptions.NewFetchOptionsBuilder(
+		handleroptions.FetchOptionsBuilderOptions{Timeout: 15 * time.Second})
+	require.NoError(t, err)
+	opts := options.EmptyHandlerOptions().
+		SetStorage(store).
+		SetClusters(clusters).
+		SetFetchOptionsBuilder(fb).
+		SetTagOptions(models.NewTagOptions())
+	return NewQueryExemplarsHandler(opts), store, session
+}
+
+func TestQueryExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, store, session := newTestQueryExemplarsHandler(t, ctrl)
+
+	tags := models.NewTags(2, models.NewTagOptions()).AddTags([]models.Tag{
+		{Name: []byte("__name__"), Value: []byte("foo")},
+		{Name: []byte("job"), Value: []byte("bar")},
+	})
+	store.EXPECT().SearchSeries(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ interface{},
+			query *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (*storage.SearchResults, error) {
+			require.Equal(t, 1, len(query.TagMatchers))
+			assert.Equal(t, "__name__", string(query.TagMatchers[0].Name))
+			assert.Equal(t, "foo", string(query.TagMatchers[0].Value))
+			return &storage.SearchResults{
+				Metrics: models.Metrics{{ID: []byte("foo_id"), Tags: tags}},
+			}, nil
+		})
+
+	start := xtime.FromSeconds(100)
+	end := xtime.FromSeconds(200)
+	session.EXPECT().
+		FetchExemplars(gomock.Any(), gomock.Any(), start, end).
+		DoAndReturn(func(
+			_ ident.ID,
+			ids []ident.ID,
+			_, _ xtime.UnixNano,
+		) ([]client.SeriesExemplars, error) {
+			require.Equal(t, 1, len(ids))
+			assert.Equal(t, "foo_id", ids[0].String())
+			return []client.SeriesExemplars{
+				{
+					ID: ident.StringID("foo_id"),
+					Exemplars: []exemplar.Exemplar{
+						{
+							Labels:    []exemplar.Label{{Name: "trace_id", Value: "abc"}},
+							Value:     1.5,
+							Timestamp: xtime.FromSeconds(150),
+						},
+					},
+				},
+			}, nil
+		})
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/query_exemplars?query=rate(foo[1m])&start=100&end=200", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close() // nolint:errcheck
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	expected := `{"status":"success","data":[{` +
+		`"seriesLabels":{"__name__":"foo","job":"bar"},` +
+		`"exemplars":[{"labels":{"trace_id":"abc"},"value":"1.5","timestamp":150}]` +
+		`}]}`
+	assert.Equal(t, expected, string(body))
+}
+
+func TestQueryExemplarsInvalidQuery(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, _, _ := newTestQueryExemplarsHandler(t, ctrl)
+
+	for _, target := range []string{
+		"/query_exemplars",
+		"/query_exemplars?query=foo{",
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		resp := w.Result()
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+	}
+}
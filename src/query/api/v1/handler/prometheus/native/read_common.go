@@ -200,7 +200,8 @@ func read(
 	}
 
 	// TODO: Capture timing
-	parseOpts := engine.Options().ParseOptions()
+	parseOpts := engine.Options().ParseOptions().
+		SetQueryBounds(params.Start, params.End)
 	parser, err := promql.Parse(params.Query, params.Step, tagOpts, parseOpts)
 	if err != nil {
 		return emptyResult, xerrors.NewInvalidParamsError(err)
@@ -0,0 +1,216 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/api/v1/route"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util"
+	"github.com/m3db/m3/src/query/util/logging"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	"github.com/m3db/m3/src/x/instrument"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TSDBStatusURL is the url for the TSDB status cardinality statistics.
+	TSDBStatusURL = route.Prefix + "/status/tsdb"
+
+	namespaceParam = "namespace"
+
+	// defaultTSDBStatusLimit is the default number of entries of each
+	// statistic, which matches Prometheus.
+	defaultTSDBStatusLimit = 10
+	// defaultTSDBStatusRange is the default time range of the statistics
+	// when no start is given, which matches the Prometheus head block.
+	defaultTSDBStatusRange = 2 * time.Hour
+)
+
+var (
+	// TSDBStatusHTTPMethods are the HTTP methods for this handler.
+	TSDBStatusHTTPMethods = []string{http.MethodGet}
+
+	errNoTSDBStatusCluster = errors.New(
+		"TSDB status requires a local M3DB namespace")
+)
+
+// TSDBStatusHandler is a handler for the Prometheus TSDB status endpoint, it
+// returns the top cardinality statistics of the series of a namespace indexed
+// within a time range.
+type TSDBStatusHandler struct {
+	clusters       m3.Clusters
+	nowFn          func() time.Time
+	instrumentOpts instrument.Options
+	tagOpts        models.TagOptions
+}
+
+// NewTSDBStatusHandler returns a new TSDB status handler.
+func NewTSDBStatusHandler(opts options.HandlerOptions) http.Handler {
+	return &TSDBStatusHandler{
+		clusters:       opts.Clusters(),
+		nowFn:          opts.NowFn(),
+		instrumentOpts: opts.InstrumentOpts(),
+		tagOpts:        opts.TagOptions(),
+	}
+}
+
+type tsdbStatusResponse struct {
+	Status string         `json:"status"`
+	Data   tsdbStatusData `json:"data"`
+}
+
+type tsdbStatusData struct {
+	HeadStats                   tsdbHeadStats `json:"headStats"`
+	SeriesCountByMetricName     []tsdbStat    `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []tsdbStat    `json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []tsdbStat    `json:"seriesCountByLabelValuePair"`
+}
+
+type tsdbHeadStats struct {
+	NumSeries int64 `json:"numSeries"`
+	MinTime   int64 `json:"minTime"`
+	MaxTime   int64 `json:"maxTime"`
+}
+
+type tsdbStat struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
+func (h *TSDBStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(xhttp.HeaderContentType, xhttp.ContentTypeJSON)
+
+	if err := r.ParseForm(); err != nil {
+		xhttp.WriteError(w, xerrors.NewInvalidParamsError(err))
+		return
+	}
+
+	start, end, err := parseTSDBStatusRange(r, h.nowFn())
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	limit := defaultTSDBStatusLimit
+	if v := r.FormValue(limitParam); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			xhttp.WriteError(w, xerrors.NewInvalidParamsError(
+				fmt.Errorf("invalid %s: %s", limitParam, v)))
+			return
+		}
+	}
+
+	namespace, err := h.namespace(r.FormValue(namespaceParam))
+	if err != nil {
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	logger := logging.WithContext(r.Context(), h.instrumentOpts)
+	stats, err := namespace.Session().Cardinality(namespace.NamespaceID(),
+		index.CardinalityQueryOptions{
+			StartInclusive: xtime.ToUnixNano(start),
+			EndExclusive:   xtime.ToUnixNano(end),
+			MetricNameTag:  h.tagOpts.MetricName(),
+			Limit:          limit,
+		})
+	if err != nil {
+		logger.Error("unable to compute cardinality",
+			zap.Stringer("namespace", namespace.NamespaceID()),
+			zap.Error(err))
+		xhttp.WriteError(w, err)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, tsdbStatusResponse{
+		Status: "success",
+		Data: tsdbStatusData{
+			HeadStats: tsdbHeadStats{
+				NumSeries: stats.NumSeries,
+				MinTime:   start.UnixNano() / int64(time.Millisecond),
+				MaxTime:   end.UnixNano() / int64(time.Millisecond),
+			},
+			SeriesCountByMetricName:     renderTSDBStats(stats.SeriesCountByMetricName),
+			LabelValueCountByLabelName:  renderTSDBStats(stats.LabelValueCountByLabelName),
+			SeriesCountByLabelValuePair: renderTSDBStats(stats.SeriesCountByLabelValuePair),
+		},
+	}, logger)
+}
+
+// namespace returns the namespace with the given name, or the unaggregated
+// namespace if no name is given.
+func (h *TSDBStatusHandler) namespace(name string) (m3.ClusterNamespace, error) {
+	if h.clusters == nil {
+		return nil, errNoTSDBStatusCluster
+	}
+	if name == "" {
+		namespace, ok := h.clusters.UnaggregatedClusterNamespace()
+		if !ok {
+			return nil, errNoTSDBStatusCluster
+		}
+		return namespace, nil
+	}
+	for _, namespace := range h.clusters.ClusterNamespaces() {
+		if namespace.NamespaceID().String() == name {
+			return namespace, nil
+		}
+	}
+	return nil, xerrors.NewInvalidParamsError(
+		fmt.Errorf("unknown %s: %s", namespaceParam, name))
+}
+
+func parseTSDBStatusRange(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	end, err := util.ParseTimeStringWithDefault(r.FormValue("end"), now)
+	if err != nil {
+		return time.Time{}, time.Time{}, xerrors.NewInvalidParamsError(err)
+	}
+	start, err := util.ParseTimeStringWithDefault(r.FormValue("start"),
+		end.Add(-defaultTSDBStatusRange))
+	if err != nil {
+		return time.Time{}, time.Time{}, xerrors.NewInvalidParamsError(err)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, xerrors.NewInvalidParamsError(
+			fmt.Errorf("start %v must be before end %v", start, end))
+	}
+	return start, end, nil
+}
+
+func renderTSDBStats(stats []index.CardinalityStat) []tsdbStat {
+	result := make([]tsdbStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, tsdbStat{Name: stat.Name, Value: stat.Value})
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	"github.com/m3db/m3/src/query/api/v1/options"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/ident"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+func newTestTSDBStatusHandler(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	now time.Time,
+) (http.Handler, *client.MockSession) {
+	session := client.NewMockSession(ctrl)
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("default"),
+		Session:     session,
+		Retention:   48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	opts := options.EmptyHandlerOptions().
+		SetClusters(clusters).
+		SetNowFn(func() time.Time { return now }).
+		SetTagOptions(models.NewTagOptions())
+	return NewTSDBStatusHandler(opts), session
+}
+
+func TestTSDBStatus(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, session := newTestTSDBStatusHandler(t, ctrl, time.Unix(7200, 0))
+
+	session.EXPECT().
+		Cardinality(ident.NewIDMatcher("default"), index.CardinalityQueryOptions{
+			StartInclusive: xtime.FromSeconds(100),
+			EndExclusive:   xtime.FromSeconds(200),
+			MetricNameTag:  []byte("__name__"),
+			Limit:          2,
+		}).
+		Return(index.CardinalityStats{
+			NumSeries: 3,
+			SeriesCountByMetricName: []index.CardinalityStat{
+				{Name: "foo", Value: 2},
+				{Name: "bar", Value: 1},
+			},
+			LabelValueCountByLabelName: []index.CardinalityStat{
+				{Name: "__name__", Value: 2},
+			},
+			SeriesCountByLabelValuePair: []index.CardinalityStat{
+				{Name: "job=baz", Value: 3},
+			},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet,
+		TSDBStatusURL+"?start=100&end=200&limit=2", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	expected := `{"status":"success","data":{` +
+		`"headStats":{"numSeries":3,"minTime":100000,"maxTime":200000},` +
+		`"seriesCountByMetricName":[{"name":"foo","value":2},{"name":"bar","value":1}],` +
+		`"labelValueCountByLabelName":[{"name":"__name__","value":2}],` +
+		`"seriesCountByLabelValuePair":[{"name":"job=baz","value":3}]}}`
+	assert.JSONEq(t, expected, string(body))
+}
+
+func TestTSDBStatusDefaults(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, session := newTestTSDBStatusHandler(t, ctrl, time.Unix(7200, 0))
+
+	session.EXPECT().
+		Cardinality(ident.NewIDMatcher("default"), index.CardinalityQueryOptions{
+			StartInclusive: xtime.FromSeconds(0),
+			EndExclusive:   xtime.FromSeconds(7200),
+			MetricNameTag:  []byte("__name__"),
+			Limit:          defaultTSDBStatusLimit,
+		}).
+		Return(index.CardinalityStats{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, TSDBStatusURL, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	resp := w.Result()
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestTSDBStatusInvalidRequest(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	h, _ := newTestTSDBStatusHandler(t, ctrl, time.Unix(7200, 0))
+
+	for _, target := range []string{
+		TSDBStatusURL + "?start=200&end=100",
+		TSDBStatusURL + "?limit=0",
+		TSDBStatusURL + "?limit=foo",
+		TSDBStatusURL + "?namespace=unknown",
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		resp := w.Result()
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompbv2"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/snappy"
@@ -84,3 +85,15 @@ func GeneratePromWriteRequestBodyBytes(
 	compressed := snappy.Encode(nil, data)
 	return compressed
 }
+
+// GeneratePromWriteV2RequestBody generates a Prometheus remote
+// write 2.0 request body.
+func GeneratePromWriteV2RequestBody(
+	t require.TestingT,
+	req *prompbv2.Request,
+) io.Reader {
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	return bytes.NewReader(snappy.Encode(nil, data))
+}
@@ -33,15 +33,18 @@ import (
 
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	"github.com/m3db/m3/src/query/api/v1/route"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompbv2"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/clock"
@@ -110,6 +113,7 @@ type PromWriteHandler struct {
 	downsamplerAndWriter   ingest.DownsamplerAndWriter
 	tagOptions             models.TagOptions
 	storeMetricsType       bool
+	metricMetadata         metricmetadata.Store
 	forwarding             handleroptions.PromWriteHandlerForwardingOptions
 	forwardTimeout         time.Duration
 	forwardHTTPClient      *http.Client
@@ -180,6 +184,7 @@ func NewPromWriteHandler(options options.HandlerOptions) (http.Handler, error) {
 		downsamplerAndWriter:   downsamplerAndWriter,
 		tagOptions:             tagOptions,
 		storeMetricsType:       options.StoreMetricsType(),
+		metricMetadata:         options.MetricMetadataStore(),
 		forwarding:             forwarding,
 		forwardTimeout:         forwardTimeout,
 		forwardHTTPClient:      xhttp.NewHTTPClient(forwardHTTPOpts),
@@ -204,6 +209,7 @@ type promWriteMetrics struct {
 	forwardErrors            tally.Counter
 	forwardDropped           tally.Counter
 	forwardLatency           tally.Histogram
+	metadataErrors           tally.Counter
 }
 
 func (m *promWriteMetrics) incError(err error) {
@@ -231,6 +237,7 @@ func newPromWriteMetrics(scope tally.Scope) (promWriteMetrics, error) {
 		forwardErrors:            scope.SubScope("forward").Counter("errors"),
 		forwardDropped:           scope.SubScope("forward").Counter("dropped"),
 		forwardLatency:           scope.SubScope("forward").Histogram("latency", buckets.WriteLatencyBuckets),
+		metadataErrors:           scope.SubScope("metadata").Counter("errors"),
 	}, nil
 }
 
@@ -274,6 +281,10 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						age := now.Sub(storage.PromTimestampToTime(sample.Timestamp))
 						h.metrics.forwardLatency.RecordDuration(age)
 					}
+					for _, histogram := range series.Histograms {
+						age := now.Sub(storage.PromTimestampToTime(histogram.Timestamp))
+						h.metrics.forwardLatency.RecordDuration(age)
+					}
 				}
 
 				if err != nil {
@@ -301,6 +312,16 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	batchErr := h.write(r.Context(), req, opts)
 
+	// NB: metric metadata is written on a best effort basis, it is resent
+	// periodically by Prometheus so failing the request is not required.
+	if h.metricMetadata != nil && len(req.Metadata) > 0 {
+		if err := h.metricMetadata.Write(req.Metadata); err != nil {
+			h.metrics.metadataErrors.Inc(1)
+			logger := logging.WithContext(r.Context(), h.instrumentOpts)
+			logger.Error("metric metadata write error", zap.Error(err))
+		}
+	}
+
 	// Record ingestion delay latency
 	now := h.nowFn()
 	for _, series := range req.Timeseries {
@@ -308,6 +329,10 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			age := now.Sub(storage.PromTimestampToTime(sample.Timestamp))
 			h.metrics.ingestLatency.RecordDuration(age)
 		}
+		for _, histogram := range series.Histograms {
+			age := now.Sub(storage.PromTimestampToTime(histogram.Timestamp))
+			h.metrics.ingestLatency.RecordDuration(age)
+		}
 	}
 
 	if batchErr != nil {
@@ -369,6 +394,10 @@ func (h *PromWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if checkedReq.ProtoVersion == promWriteProtoV2 {
+		checkedReq.V2Stats.setHeaders(w)
+	}
+
 	// NB(schallert): this is frustrating but if we don't explicitly write an HTTP
 	// status code (or via Write()), OpenTracing middleware reports code=0 and
 	// shows up as error.
@@ -380,6 +409,8 @@ type parseRequestResult struct {
 	Request        *prompb.WriteRequest
 	Options        ingest.WriteOptions
 	CompressResult prometheus.ParsePromCompressedRequestResult
+	ProtoVersion   promWriteProtoVersion
+	V2Stats        promWriteV2Stats
 }
 
 func (h *PromWriteHandler) checkedParseRequest(
@@ -387,6 +418,11 @@ func (h *PromWriteHandler) checkedParseRequest(
 ) (parseRequestResult, error) {
 	result, err := h.parseRequest(r)
 	if err != nil {
+		// NB: errors that explicitly set a status code, such as an
+		// unsupported content type, are returned as is.
+		if _, ok := err.(xhttp.Error); ok {
+			return parseRequestResult{}, err
+		}
 		// Always invalid request if parsing fails params.
 		return parseRequestResult{}, xerrors.NewInvalidParamsError(err)
 	}
@@ -403,6 +439,11 @@ func (h *PromWriteHandler) checkedParseRequest(
 func (h *PromWriteHandler) parseRequest(
 	r *http.Request,
 ) (parseRequestResult, error) {
+	protoVersion, err := parsePromWriteProtoVersion(r)
+	if err != nil {
+		return parseRequestResult{}, err
+	}
+
 	var opts ingest.WriteOptions
 	if v := strings.TrimSpace(r.Header.Get(headers.MetricsTypeHeader)); v != "" {
 		// Allow the metrics type and storage policies to override
@@ -455,9 +496,26 @@ func (h *PromWriteHandler) parseRequest(
 		return parseRequestResult{}, err
 	}
 
-	var req prompb.WriteRequest
-	if err := proto.Unmarshal(result.UncompressedBody, &req); err != nil {
-		return parseRequestResult{}, err
+	var (
+		req     *prompb.WriteRequest
+		v2Stats promWriteV2Stats
+	)
+	switch protoVersion {
+	case promWriteProtoV2:
+		var reqV2 prompbv2.Request
+		if err := reqV2.Unmarshal(result.UncompressedBody); err != nil {
+			return parseRequestResult{}, err
+		}
+
+		req, v2Stats, err = promWriteV2RequestToV1(&reqV2)
+		if err != nil {
+			return parseRequestResult{}, err
+		}
+	default:
+		req = &prompb.WriteRequest{}
+		if err := proto.Unmarshal(result.UncompressedBody, req); err != nil {
+			return parseRequestResult{}, err
+		}
 	}
 
 	if mapStr := r.Header.Get(headers.MapTagsByJSONHeader); mapStr != "" {
@@ -466,7 +524,7 @@ func (h *PromWriteHandler) parseRequest(
 			return parseRequestResult{}, err
 		}
 
-		if err := mapTags(&req, opts); err != nil {
+		if err := mapTags(req, opts); err != nil {
 			return parseRequestResult{}, err
 		}
 	}
@@ -482,9 +540,11 @@ func (h *PromWriteHandler) parseRequest(
 	}
 
 	return parseRequestResult{
-		Request:        &req,
+		Request:        req,
 		Options:        opts,
 		CompressResult: result,
+		ProtoVersion:   protoVersion,
+		V2Stats:        v2Stats,
 	}, nil
 }
 
@@ -527,6 +587,12 @@ func (h *PromWriteHandler) forward(
 				req.Header.Add(h, header.Get(h))
 			}
 		}
+
+		// Forward the content type so the target negotiates the same
+		// remote write protocol version.
+		if contentType := header.Get(xhttp.HeaderContentType); contentType != "" {
+			req.Header.Set(xhttp.HeaderContentType, contentType)
+		}
 	}
 
 	if targetHeaders := target.Headers; targetHeaders != nil {
@@ -565,14 +631,16 @@ func newPromTSIter(
 	var (
 		tags             = make([]models.Tags, 0, len(timeseries))
 		datapoints       = make([]ts.Datapoints, 0, len(timeseries))
+		exemplars        = make([][]exemplar.Exemplar, 0, len(timeseries))
 		seriesAttributes = make([]ts.SeriesAttributes, 0, len(timeseries))
+		annotations      = make([][]byte, 0, len(timeseries))
 	)
 
 	graphiteTagOpts := tagOpts.SetIDSchemeType(models.TypeGraphite)
-	for _, promTS := range timeseries {
+	add := func(promTS prompb.TimeSeries, annotation []byte) error {
 		attributes, err := storage.PromTimeSeriesToSeriesAttributes(promTS)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		// Set the tag options based on the incoming source.
@@ -584,6 +652,48 @@ func newPromTSIter(
 		seriesAttributes = append(seriesAttributes, attributes)
 		tags = append(tags, storage.PromLabelsToM3Tags(promTS.Labels, opts))
 		datapoints = append(datapoints, storage.PromSamplesToM3Datapoints(promTS.Samples))
+		exemplars = append(exemplars, storage.PromExemplarsToM3(promTS.Exemplars))
+		annotations = append(annotations, annotation)
+		return nil
+	}
+
+	for _, promTS := range timeseries {
+		if len(promTS.Histograms) > 0 {
+			// NB: native histograms are stored as their classic histogram
+			// series so that they can be queried like any other histogram.
+			histogramSeries, err := storage.PromHistogramsToClassicTimeSeries(promTS)
+			if err != nil {
+				return nil, xerrors.NewInvalidParamsError(err)
+			}
+
+			for _, series := range histogramSeries {
+				if err := add(series, nil); err != nil {
+					return nil, err
+				}
+			}
+
+			// NB: the native histograms themselves are also stored, in the
+			// annotations of the histogram count under the original series,
+			// so that they can be returned as is by remote read.
+			nativeSeries, nativeAnnotations, err := storage.PromHistogramsToNativeTimeSeries(promTS)
+			if err != nil {
+				return nil, xerrors.NewInvalidParamsError(err)
+			}
+
+			for j, series := range nativeSeries {
+				if err := add(series, nativeAnnotations[j]); err != nil {
+					return nil, err
+				}
+			}
+
+			if len(promTS.Samples) == 0 {
+				continue
+			}
+		}
+
+		if err := add(promTS, nil); err != nil {
+			return nil, err
+		}
 	}
 
 	return &promTSIter{
@@ -591,6 +701,8 @@ func newPromTSIter(
 		idx:              -1,
 		tags:             tags,
 		datapoints:       datapoints,
+		exemplars:        exemplars,
+		annotations:      annotations,
 		storeMetricsType: storeMetricsType,
 	}, nil
 }
@@ -601,8 +713,12 @@ type promTSIter struct {
 	attributes []ts.SeriesAttributes
 	tags       []models.Tags
 	datapoints []ts.Datapoints
+	exemplars  [][]exemplar.Exemplar
 	metadatas  []ts.Metadata
 	annotation []byte
+	// annotations holds the precomputed annotations of the series that
+	// require one regardless of whether the metrics type is stored.
+	annotations [][]byte
 
 	storeMetricsType bool
 }
@@ -617,6 +733,12 @@ func (i *promTSIter) Next() bool {
 		return false
 	}
 
+	if i.idx < len(i.annotations) && i.annotations[i.idx] != nil {
+		i.annotation = i.annotations[i.idx]
+		return true
+	}
+
+	i.annotation = nil
 	if !i.storeMetricsType {
 		return true
 	}
@@ -655,6 +777,9 @@ func (i *promTSIter) Current() ingest.IterValue {
 	if i.idx < len(i.metadatas) {
 		value.Metadata = i.metadatas[i.idx]
 	}
+	if i.idx < len(i.exemplars) {
+		value.Exemplars = i.exemplars[i.idx]
+	}
 	return value
 }
 
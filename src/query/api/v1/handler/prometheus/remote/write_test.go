@@ -42,6 +42,7 @@ import (
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	xclock "github.com/m3db/m3/src/x/clock"
 	xerrors "github.com/m3db/m3/src/x/errors"
 	"github.com/m3db/m3/src/x/headers"
@@ -109,6 +110,49 @@ func TestPromWrite(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestPromWriteMetricMetadata(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any())
+
+	store := metricmetadata.NewStore(metricmetadata.NewOptions())
+	opts := makeOptions(mockDownsamplerAndWriter).
+		SetMetricMetadataStore(store)
+	handler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	// NB: Prometheus sends metadata in requests without any series.
+	promReq := &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{
+			{
+				Type:             prompb.MetricType_COUNTER,
+				MetricFamilyName: "http_requests_total",
+				Help:             "Total HTTP requests.",
+			},
+		},
+	}
+	promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	metadata, err := store.Metadata("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]metricmetadata.Metadata{
+		"http_requests_total": {
+			Type: prompb.MetricType_COUNTER,
+			Help: "Total HTTP requests.",
+		},
+	}, metadata)
+}
+
 func TestPromWriteError(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -418,6 +462,113 @@ func TestPromWriteDisabledMetricsTypes(t *testing.T) {
 	require.NoError(t, capturedIter.Error())
 }
 
+func TestPromWriteNativeHistograms(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var capturedIter ingest.DownsampleAndWriteIter
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, iter ingest.DownsampleAndWriteIter, _ ingest.WriteOptions) ingest.BatchError {
+			capturedIter = iter
+			return nil
+		})
+
+	opts := makeOptions(mockDownsamplerAndWriter)
+
+	promReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{{Name: []byte("__name__"), Value: []byte("foo")}},
+				Histograms: []prompb.Histogram{{
+					Count:          &prompb.Histogram_CountInt{CountInt: 3},
+					Sum:            2.5,
+					ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 1}},
+					PositiveDeltas: []int64{2},
+					Timestamp:      1000,
+				}},
+			},
+		},
+	}
+
+	executeWriteRequest(t, opts, promReq)
+
+	expected := []struct {
+		name   string
+		bucket string
+		value  float64
+	}{
+		{name: "foo_bucket", bucket: "0", value: 1},
+		{name: "foo_bucket", bucket: "1", value: 3},
+		{name: "foo_bucket", bucket: "+Inf", value: 3},
+		{name: "foo_count", value: 3},
+		{name: "foo_sum", value: 2.5},
+	}
+	for _, e := range expected {
+		value := verifyIterValueAnnotation(t, capturedIter, annotation.MetricType_HISTOGRAM, true)
+
+		name, ok := value.Tags.Name()
+		require.True(t, ok)
+		assert.Equal(t, e.name, string(name))
+
+		bucket, ok := value.Tags.Bucket()
+		assert.Equal(t, e.bucket != "", ok)
+		assert.Equal(t, e.bucket, string(bucket))
+
+		require.Equal(t, 1, len(value.Datapoints))
+		assert.Equal(t, e.value, value.Datapoints[0].Value)
+	}
+
+	// The native histogram is stored in the annotation of its count.
+	require.True(t, capturedIter.Next())
+	value := capturedIter.Current()
+	name, ok := value.Tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "foo", string(name))
+	require.Equal(t, 1, len(value.Datapoints))
+	assert.Equal(t, float64(3), value.Datapoints[0].Value)
+
+	payload := unmarshalAnnotation(t, value.Annotation)
+	assert.Equal(t, annotation.MetricType_HISTOGRAM, payload.MetricType)
+	var histogram prompb.Histogram
+	require.NoError(t, histogram.Unmarshal(payload.NativeHistogram))
+	assert.Equal(t, promReq.Timeseries[0].Histograms[0], histogram)
+
+	require.False(t, capturedIter.Next())
+	require.NoError(t, capturedIter.Error())
+}
+
+func TestPromWriteInvalidNativeHistogram(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	opts := makeOptions(mockDownsamplerAndWriter)
+	handler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	promReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:     []prompb.Label{{Name: []byte("__name__"), Value: []byte("foo")}},
+				Histograms: []prompb.Histogram{{Schema: 100}},
+			},
+		},
+	}
+
+	promReqBody := test.GeneratePromWriteRequestBody(t, promReq)
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, promReqBody)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
 func BenchmarkWriteDatapoints(b *testing.B) {
 	ctrl := xtest.NewController(b)
 	defer ctrl.Finish()
@@ -0,0 +1,343 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompbv2"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// PromWriteV1ProtoMessage is the fully qualified name of the remote write
+	// 1.0 protobuf message, it is used if no proto is specified in the
+	// request content type.
+	PromWriteV1ProtoMessage = "prometheus.WriteRequest"
+
+	// PromWriteV2ProtoMessage is the fully qualified name of the remote write
+	// 2.0 protobuf message.
+	PromWriteV2ProtoMessage = "io.prometheus.write.v2.Request"
+
+	// PromWriteSamplesWrittenHeader is the response header reporting the
+	// number of samples written by a remote write 2.0 request.
+	PromWriteSamplesWrittenHeader = "X-Prometheus-Remote-Write-Samples-Written"
+
+	// PromWriteHistogramsWrittenHeader is the response header reporting the
+	// number of native histogram samples written by a remote write 2.0 request.
+	PromWriteHistogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+
+	// PromWriteExemplarsWrittenHeader is the response header reporting the
+	// number of exemplars written by a remote write 2.0 request.
+	PromWriteExemplarsWrittenHeader = "X-Prometheus-Remote-Write-Exemplars-Written"
+
+	promWriteProtoParam = "proto"
+)
+
+var errOddLabelsRefs = errors.New("series has an odd number of label refs")
+
+type promWriteProtoVersion uint
+
+const (
+	promWriteProtoV1 promWriteProtoVersion = iota
+	promWriteProtoV2
+)
+
+// parsePromWriteProtoVersion negotiates the remote write protocol version
+// using the proto parameter of the request content type. Requests that do not
+// specify a protobuf content type are assumed to be remote write 1.0 requests
+// for backwards compatibility.
+func parsePromWriteProtoVersion(r *http.Request) (promWriteProtoVersion, error) {
+	contentType := r.Header.Get(xhttp.HeaderContentType)
+	if contentType == "" {
+		return promWriteProtoV1, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0, xhttp.NewError(fmt.Errorf("invalid content type %s: %v",
+			contentType, err), http.StatusUnsupportedMediaType)
+	}
+
+	if mediaType != xhttp.ContentTypeProtobuf {
+		return promWriteProtoV1, nil
+	}
+
+	switch params[promWriteProtoParam] {
+	case "", PromWriteV1ProtoMessage:
+		return promWriteProtoV1, nil
+	case PromWriteV2ProtoMessage:
+		return promWriteProtoV2, nil
+	default:
+		return 0, xhttp.NewError(fmt.Errorf("unsupported remote write proto message: %s",
+			params[promWriteProtoParam]), http.StatusUnsupportedMediaType)
+	}
+}
+
+// promWriteV2Stats are the number of samples a remote write 2.0 request
+// contained, reported back to the client in the response headers.
+type promWriteV2Stats struct {
+	samples    int
+	histograms int
+	exemplars  int
+}
+
+func (s promWriteV2Stats) setHeaders(w http.ResponseWriter) {
+	w.Header().Set(PromWriteSamplesWrittenHeader, strconv.Itoa(s.samples))
+	w.Header().Set(PromWriteHistogramsWrittenHeader, strconv.Itoa(s.histograms))
+	w.Header().Set(PromWriteExemplarsWrittenHeader, strconv.Itoa(s.exemplars))
+}
+
+// promWriteV2RequestToV1 converts a remote write 2.0 request into the
+// equivalent remote write 1.0 request, resolving the interned label and
+// metadata symbols, so that it can be written using the same write path.
+func promWriteV2RequestToV1(
+	req *prompbv2.Request,
+) (*prompb.WriteRequest, promWriteV2Stats, error) {
+	var (
+		stats    promWriteV2Stats
+		symbols  = req.Symbols
+		families = make(map[string]struct{})
+		result   = &prompb.WriteRequest{
+			Timeseries: make([]prompb.TimeSeries, 0, len(req.Timeseries)),
+		}
+	)
+	symbol := func(ref uint32) ([]byte, error) {
+		if int(ref) >= len(symbols) {
+			return nil, fmt.Errorf("symbol ref %d out of range, num symbols: %d",
+				ref, len(symbols))
+		}
+		return []byte(symbols[ref]), nil
+	}
+
+	labels := func(refs []uint32) ([]prompb.Label, error) {
+		if len(refs)%2 != 0 {
+			return nil, errOddLabelsRefs
+		}
+
+		labels := make([]prompb.Label, 0, len(refs)/2)
+		for i := 0; i < len(refs); i += 2 {
+			name, err := symbol(refs[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := symbol(refs[i+1])
+			if err != nil {
+				return nil, err
+			}
+			labels = append(labels, prompb.Label{Name: name, Value: value})
+		}
+		return labels, nil
+	}
+
+	for _, series := range req.Timeseries {
+		seriesLabels, err := labels(series.LabelsRefs)
+		if err != nil {
+			return nil, stats, err
+		}
+
+		help, err := symbol(series.Metadata.HelpRef)
+		if err != nil {
+			return nil, stats, err
+		}
+		unit, err := symbol(series.Metadata.UnitRef)
+		if err != nil {
+			return nil, stats, err
+		}
+
+		var histograms []prompb.Histogram
+		if len(series.Histograms) > 0 {
+			histograms = make([]prompb.Histogram, 0, len(series.Histograms))
+			for _, histogram := range series.Histograms {
+				histograms = append(histograms, promWriteV2HistogramToV1(histogram))
+			}
+		}
+
+		var exemplars []prompb.Exemplar
+		if len(series.Exemplars) > 0 {
+			exemplars = make([]prompb.Exemplar, 0, len(series.Exemplars))
+			for _, exemplar := range series.Exemplars {
+				exemplarLabels, err := labels(exemplar.LabelsRefs)
+				if err != nil {
+					return nil, stats, err
+				}
+				exemplars = append(exemplars, prompb.Exemplar{
+					Labels:    exemplarLabels,
+					Value:     exemplar.Value,
+					Timestamp: exemplar.Timestamp,
+				})
+			}
+		}
+
+		metricType := promWriteV2MetricTypeToV1(series.Metadata.Type)
+		if metricType != prompb.MetricType_UNKNOWN || len(help) > 0 || len(unit) > 0 {
+			family := promWriteV2MetricFamilyName(seriesLabels, metricType)
+			if _, ok := families[family]; !ok && family != "" {
+				families[family] = struct{}{}
+				result.Metadata = append(result.Metadata, prompb.MetricMetadata{
+					Type:             metricType,
+					MetricFamilyName: family,
+					Help:             string(help),
+					Unit:             string(unit),
+				})
+			}
+		}
+
+		result.Timeseries = append(result.Timeseries, prompb.TimeSeries{
+			Labels:     seriesLabels,
+			Samples:    promWriteV2SamplesToV1(series),
+			Exemplars:  exemplars,
+			Histograms: histograms,
+			Type:       metricType,
+			Unit:       string(unit),
+			Help:       string(help),
+		})
+
+		stats.samples += len(series.Samples)
+		stats.histograms += len(series.Histograms)
+		stats.exemplars += len(series.Exemplars)
+	}
+
+	return result, stats, nil
+}
+
+// promWriteV2SamplesToV1 converts the float samples of a series.
+//
+// NB: the created timestamp of counters is ignored rather than written as a
+// zero sample, the created timestamp of a long lived counter can be older
+// than the buffer past or retention of the namespaces written to which would
+// fail the write of the whole request.
+func promWriteV2SamplesToV1(series prompbv2.TimeSeries) []prompb.Sample {
+	samples := make([]prompb.Sample, 0, len(series.Samples))
+	for _, sample := range series.Samples {
+		samples = append(samples, prompb.Sample{
+			Value:     sample.Value,
+			Timestamp: sample.Timestamp,
+		})
+	}
+
+	return samples
+}
+
+// promWriteV2MetricFamilyName returns the metric family name of a series,
+// which for histograms and summaries excludes the suffix of their bucket,
+// count and sum series.
+func promWriteV2MetricFamilyName(
+	labels []prompb.Label,
+	metricType prompb.MetricType,
+) string {
+	var name string
+	for _, label := range labels {
+		if string(label.Name) == model.MetricNameLabel {
+			name = string(label.Value)
+			break
+		}
+	}
+
+	switch metricType {
+	case prompb.MetricType_HISTOGRAM,
+		prompb.MetricType_GAUGE_HISTOGRAM,
+		prompb.MetricType_SUMMARY:
+		for _, suffix := range []string{"_bucket", "_count", "_sum"} {
+			if strings.HasSuffix(name, suffix) {
+				return strings.TrimSuffix(name, suffix)
+			}
+		}
+	}
+
+	return name
+}
+
+func promWriteV2MetricTypeToV1(metricType prompbv2.Metadata_MetricType) prompb.MetricType {
+	switch metricType {
+	case prompbv2.Metadata_METRIC_TYPE_COUNTER:
+		return prompb.MetricType_COUNTER
+	case prompbv2.Metadata_METRIC_TYPE_GAUGE:
+		return prompb.MetricType_GAUGE
+	case prompbv2.Metadata_METRIC_TYPE_HISTOGRAM:
+		return prompb.MetricType_HISTOGRAM
+	case prompbv2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM:
+		return prompb.MetricType_GAUGE_HISTOGRAM
+	case prompbv2.Metadata_METRIC_TYPE_SUMMARY:
+		return prompb.MetricType_SUMMARY
+	case prompbv2.Metadata_METRIC_TYPE_INFO:
+		return prompb.MetricType_INFO
+	case prompbv2.Metadata_METRIC_TYPE_STATESET:
+		return prompb.MetricType_STATESET
+	default:
+		return prompb.MetricType_UNKNOWN
+	}
+}
+
+func promWriteV2HistogramToV1(histogram prompbv2.Histogram) prompb.Histogram {
+	result := prompb.Histogram{
+		Sum:            histogram.Sum,
+		Schema:         histogram.Schema,
+		ZeroThreshold:  histogram.ZeroThreshold,
+		NegativeSpans:  promWriteV2SpansToV1(histogram.NegativeSpans),
+		NegativeDeltas: histogram.NegativeDeltas,
+		NegativeCounts: histogram.NegativeCounts,
+		PositiveSpans:  promWriteV2SpansToV1(histogram.PositiveSpans),
+		PositiveDeltas: histogram.PositiveDeltas,
+		PositiveCounts: histogram.PositiveCounts,
+		ResetHint:      prompb.Histogram_ResetHint(histogram.ResetHint),
+		Timestamp:      histogram.Timestamp,
+	}
+
+	switch count := histogram.Count.(type) {
+	case *prompbv2.Histogram_CountInt:
+		result.Count = &prompb.Histogram_CountInt{CountInt: count.CountInt}
+	case *prompbv2.Histogram_CountFloat:
+		result.Count = &prompb.Histogram_CountFloat{CountFloat: count.CountFloat}
+	}
+
+	switch count := histogram.ZeroCount.(type) {
+	case *prompbv2.Histogram_ZeroCountInt:
+		result.ZeroCount = &prompb.Histogram_ZeroCountInt{ZeroCountInt: count.ZeroCountInt}
+	case *prompbv2.Histogram_ZeroCountFloat:
+		result.ZeroCount = &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: count.ZeroCountFloat}
+	}
+
+	return result
+}
+
+func promWriteV2SpansToV1(spans []prompbv2.BucketSpan) []prompb.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	result := make([]prompb.BucketSpan, 0, len(spans))
+	for _, span := range spans {
+		result = append(result, prompb.BucketSpan{
+			Offset: span.Offset,
+			Length: span.Length,
+		})
+	}
+	return result
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote/test"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/query/generated/proto/prompbv2"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const promWriteV2ContentType = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+
+func TestParsePromWriteProtoVersion(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    promWriteProtoVersion
+		expectedErr bool
+	}{
+		{contentType: "", expected: promWriteProtoV1},
+		{contentType: "application/x-protobuf", expected: promWriteProtoV1},
+		{contentType: "application/octet-stream", expected: promWriteProtoV1},
+		{contentType: "application/x-protobuf;proto=prometheus.WriteRequest", expected: promWriteProtoV1},
+		{contentType: promWriteV2ContentType, expected: promWriteProtoV2},
+		{contentType: "application/x-protobuf; proto=io.prometheus.write.v2.Request", expected: promWriteProtoV2},
+		{contentType: "application/x-protobuf;proto=io.prometheus.write.v3.Request", expectedErr: true},
+		{contentType: "application/x-protobuf;;", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL, nil)
+			req.Header.Set(xhttp.HeaderContentType, tt.contentType)
+
+			version, err := parsePromWriteProtoVersion(req)
+			if tt.expectedErr {
+				require.Error(t, err)
+				xhttpErr, ok := err.(xhttp.Error)
+				require.True(t, ok)
+				assert.Equal(t, http.StatusUnsupportedMediaType, xhttpErr.Code())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
+
+func TestPromWriteV2RequestToV1(t *testing.T) {
+	req := &prompbv2.Request{
+		Symbols: []string{"", "__name__", "foo_total", "job", "bar", "help text", "seconds", "baz",
+			"trace_id", "abc"},
+		Timeseries: []prompbv2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples: []prompbv2.Sample{
+					{Value: 1, Timestamp: 2000},
+					{Value: 2, Timestamp: 3000},
+				},
+				Exemplars: []prompbv2.Exemplar{
+					{LabelsRefs: []uint32{8, 9}, Value: 1, Timestamp: 2000},
+				},
+				Metadata: prompbv2.Metadata{
+					Type:    prompbv2.Metadata_METRIC_TYPE_COUNTER,
+					HelpRef: 5,
+					UnitRef: 6,
+				},
+				CreatedTimestamp: 1000,
+			},
+			{
+				LabelsRefs: []uint32{1, 7},
+				Histograms: []prompbv2.Histogram{
+					{
+						Count:          &prompbv2.Histogram_CountFloat{CountFloat: 2},
+						Sum:            3,
+						ZeroCount:      &prompbv2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+						PositiveSpans:  []prompbv2.BucketSpan{{Offset: 1, Length: 1}},
+						PositiveCounts: []float64{1},
+						ResetHint:      prompbv2.Histogram_RESET_HINT_GAUGE,
+						Timestamp:      1000,
+					},
+				},
+				Metadata: prompbv2.Metadata{
+					Type: prompbv2.Metadata_METRIC_TYPE_GAUGEHISTOGRAM,
+				},
+				CreatedTimestamp: 500,
+			},
+		},
+	}
+
+	result, stats, err := promWriteV2RequestToV1(req)
+	require.NoError(t, err)
+	assert.Equal(t, promWriteV2Stats{samples: 2, histograms: 1, exemplars: 1}, stats)
+
+	expected := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: []byte("__name__"), Value: []byte("foo_total")},
+					{Name: []byte("job"), Value: []byte("bar")},
+				},
+				Samples: []prompb.Sample{
+					{Value: 1, Timestamp: 2000},
+					{Value: 2, Timestamp: 3000},
+				},
+				Exemplars: []prompb.Exemplar{
+					{
+						Labels: []prompb.Label{
+							{Name: []byte("trace_id"), Value: []byte("abc")},
+						},
+						Value:     1,
+						Timestamp: 2000,
+					},
+				},
+				Type: prompb.MetricType_COUNTER,
+				Unit: "seconds",
+				Help: "help text",
+			},
+			{
+				Labels: []prompb.Label{
+					{Name: []byte("__name__"), Value: []byte("baz")},
+				},
+				Samples: []prompb.Sample{},
+				Histograms: []prompb.Histogram{
+					{
+						Count:          &prompb.Histogram_CountFloat{CountFloat: 2},
+						Sum:            3,
+						ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+						PositiveSpans:  []prompb.BucketSpan{{Offset: 1, Length: 1}},
+						PositiveCounts: []float64{1},
+						ResetHint:      prompb.Histogram_GAUGE,
+						Timestamp:      1000,
+					},
+				},
+				Type: prompb.MetricType_GAUGE_HISTOGRAM,
+			},
+		},
+		Metadata: []prompb.MetricMetadata{
+			{
+				Type:             prompb.MetricType_COUNTER,
+				MetricFamilyName: "foo_total",
+				Help:             "help text",
+				Unit:             "seconds",
+			},
+			{
+				Type:             prompb.MetricType_GAUGE_HISTOGRAM,
+				MetricFamilyName: "baz",
+			},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestPromWriteV2MetricFamilyName(t *testing.T) {
+	labels := func(name string) []prompb.Label {
+		return []prompb.Label{
+			{Name: []byte("__name__"), Value: []byte(name)},
+			{Name: []byte("job"), Value: []byte("bar")},
+		}
+	}
+
+	assert.Equal(t, "foo_total",
+		promWriteV2MetricFamilyName(labels("foo_total"), prompb.MetricType_COUNTER))
+	assert.Equal(t, "foo_count",
+		promWriteV2MetricFamilyName(labels("foo_count"), prompb.MetricType_GAUGE))
+	assert.Equal(t, "foo",
+		promWriteV2MetricFamilyName(labels("foo_bucket"), prompb.MetricType_HISTOGRAM))
+	assert.Equal(t, "foo",
+		promWriteV2MetricFamilyName(labels("foo_sum"), prompb.MetricType_SUMMARY))
+	assert.Equal(t, "",
+		promWriteV2MetricFamilyName(nil, prompb.MetricType_GAUGE))
+}
+
+func TestPromWriteV2RequestToV1Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		series prompbv2.TimeSeries
+	}{
+		{
+			name:   "odd label refs",
+			series: prompbv2.TimeSeries{LabelsRefs: []uint32{1}},
+		},
+		{
+			name:   "label ref out of range",
+			series: prompbv2.TimeSeries{LabelsRefs: []uint32{1, 5}},
+		},
+		{
+			name:   "help ref out of range",
+			series: prompbv2.TimeSeries{Metadata: prompbv2.Metadata{HelpRef: 5}},
+		},
+		{
+			name:   "unit ref out of range",
+			series: prompbv2.TimeSeries{Metadata: prompbv2.Metadata{UnitRef: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := promWriteV2RequestToV1(&prompbv2.Request{
+				Symbols:    []string{"", "__name__"},
+				Timeseries: []prompbv2.TimeSeries{tt.series},
+			})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPromWriteV2(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var capturedIter ingest.DownsampleAndWriteIter
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	mockDownsamplerAndWriter.
+		EXPECT().
+		WriteBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, iter ingest.DownsampleAndWriteIter, _ ingest.WriteOptions) ingest.BatchError {
+			capturedIter = iter
+			return nil
+		})
+
+	opts := makeOptions(mockDownsamplerAndWriter)
+	handler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	promReq := &prompbv2.Request{
+		Symbols: []string{"", "__name__", "foo", "job", "bar"},
+		Timeseries: []prompbv2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []prompbv2.Sample{{Value: 42, Timestamp: 1000}},
+				Metadata:   prompbv2.Metadata{Type: prompbv2.Metadata_METRIC_TYPE_GAUGE},
+			},
+		},
+	}
+
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL,
+		test.GeneratePromWriteV2RequestBody(t, promReq))
+	req.Header.Set(xhttp.HeaderContentType, promWriteV2ContentType)
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "1", resp.Header.Get(PromWriteSamplesWrittenHeader))
+	assert.Equal(t, "0", resp.Header.Get(PromWriteHistogramsWrittenHeader))
+	assert.Equal(t, "0", resp.Header.Get(PromWriteExemplarsWrittenHeader))
+
+	value := verifyIterValueAnnotation(t, capturedIter, annotation.MetricType_GAUGE, false)
+	name, ok := value.Tags.Name()
+	require.True(t, ok)
+	assert.Equal(t, "foo", string(name))
+	tagValue, ok := value.Tags.Get([]byte("job"))
+	require.True(t, ok)
+	assert.Equal(t, "bar", string(tagValue))
+	require.Equal(t, 1, len(value.Datapoints))
+	assert.Equal(t, 42.0, value.Datapoints[0].Value)
+
+	require.False(t, capturedIter.Next())
+	require.NoError(t, capturedIter.Error())
+}
+
+func TestPromWriteUnsupportedProtoMessage(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	mockDownsamplerAndWriter := ingest.NewMockDownsamplerAndWriter(ctrl)
+	opts := makeOptions(mockDownsamplerAndWriter)
+	handler, err := NewPromWriteHandler(opts)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(PromWriteHTTPMethod, PromWriteURL,
+		test.GeneratePromWriteRequestBody(t, test.GeneratePromWriteRequest()))
+	req.Header.Set(xhttp.HeaderContentType, "application/x-protobuf;proto=unknown.Request")
+
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+	resp := writer.Result()
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
@@ -38,6 +38,7 @@ import (
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
 	"github.com/m3db/m3/src/query/api/v1/handler/namespace"
 	"github.com/m3db/m3/src/query/api/v1/handler/openapi"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/handler/prom"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
@@ -79,6 +80,7 @@ type Handler struct {
 	customHandlers   []options.CustomHandler
 	logger           *zap.Logger
 	middlewareConfig config.MiddlewareConfiguration
+	otlpReceiver     *otlp.Receiver
 }
 
 // Router returns the http handler registered with all relevant routes for query.
@@ -86,6 +88,12 @@ func (h *Handler) Router() http.Handler {
 	return h.handler
 }
 
+// OTLPReceiver returns the OTLP metrics receiver that serves OTLP/HTTP
+// requests, it should also be used to serve OTLP/gRPC requests.
+func (h *Handler) OTLPReceiver() *otlp.Receiver {
+	return h.otlpReceiver
+}
+
 // NewHandler returns a new instance of handler with routes.
 func NewHandler(
 	handlerOptions options.HandlerOptions,
@@ -104,6 +112,7 @@ func NewHandler(
 		customHandlers:   customHandlers,
 		logger:           logger,
 		middlewareConfig: middlewareConfig,
+		otlpReceiver:     otlp.NewReceiver(handlerOptions),
 	}
 }
 
@@ -253,6 +262,17 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// OTLP/HTTP metrics write endpoint.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    otlp.WriteURL,
+		Handler: otlp.NewWriteHandler(h.otlpReceiver),
+		Methods: methods(otlp.WriteHTTPMethod),
+		// Register with no response logging for write calls since so frequent.
+		MiddlewareOverride: middleware.WithNoResponseLogging,
+	}); err != nil {
+		return err
+	}
+
 	// Native M3 search and write endpoints.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:    handler.SearchURL,
@@ -306,6 +326,50 @@ func (h *Handler) RegisterRoutes() error {
 		return err
 	}
 
+	// Exemplar endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:               native.QueryExemplarsURL,
+		Handler:            native.NewQueryExemplarsHandler(h.options),
+		Methods:            native.QueryExemplarsHTTPMethods,
+		MiddlewareOverride: native.WithQueryParams,
+	}); err != nil {
+		return err
+	}
+
+	// Metric metadata endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.MetricMetadataURL,
+		Handler: native.NewMetricMetadataHandler(h.options),
+		Methods: native.MetricMetadataHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.TargetsMetadataURL,
+		Handler: native.NewTargetsMetadataHandler(h.options),
+		Methods: native.MetricMetadataHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
+	// Series deletion endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.DeleteSeriesURL,
+		Handler: native.NewDeleteSeriesHandler(h.options),
+		Methods: native.DeleteSeriesHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
+	// TSDB status endpoints.
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    native.TSDBStatusURL,
+		Handler: native.NewTSDBStatusHandler(h.options),
+		Methods: native.TSDBStatusHTTPMethods,
+	}); err != nil {
+		return err
+	}
+
 	// Query parse endpoints.
 	if err := h.registry.Register(queryhttp.RegisterOptions{
 		Path:    native.PromParseURL,
@@ -347,6 +411,27 @@ func (h *Handler) RegisterRoutes() error {
 	}); err != nil {
 		return err
 	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    graphite.TagsURL,
+		Handler: graphite.NewTagsHandler(h.options),
+		Methods: graphite.TagsHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    graphite.TagsAutoCompleteTagsURL,
+		Handler: graphite.NewTagsAutoCompleteTagsHandler(h.options),
+		Methods: graphite.TagsHTTPMethods,
+	}); err != nil {
+		return err
+	}
+	if err := h.registry.Register(queryhttp.RegisterOptions{
+		Path:    graphite.TagsAutoCompleteValuesURL,
+		Handler: graphite.NewTagsAutoCompleteValuesHandler(h.options),
+		Methods: graphite.TagsHTTPMethods,
+	}); err != nil {
+		return err
+	}
 
 	placementOpts, err := h.placementOpts()
 	if err != nil {
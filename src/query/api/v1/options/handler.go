@@ -44,6 +44,7 @@ import (
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/storage/metricmetadata"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/ts/m3db"
 	"github.com/m3db/m3/src/x/clock"
@@ -243,6 +244,11 @@ type HandlerOptions interface {
 	SetRegisterMiddleware(value middleware.Register) HandlerOptions
 	// RegisterMiddleware returns the function to construct the set of Middleware functions to run.
 	RegisterMiddleware() middleware.Register
+
+	// SetMetricMetadataStore sets the store of Prometheus metric metadata.
+	SetMetricMetadataStore(value metricmetadata.Store) HandlerOptions
+	// MetricMetadataStore returns the store of Prometheus metric metadata.
+	MetricMetadataStore() metricmetadata.Store
 }
 
 // HandlerOptions represents handler options.
@@ -275,6 +281,7 @@ type handlerOptions struct {
 	storeMetricsType                  bool
 	kvStoreProtoParser                KVStoreProtoParser
 	registerMiddleware                middleware.Register
+	metricMetadataStore               metricmetadata.Store
 }
 
 // EmptyHandlerOptions returns  default handler options.
@@ -313,6 +320,11 @@ func NewHandlerOptions(
 	if cfg.StoreMetricsType != nil {
 		storeMetricsType = *cfg.StoreMetricsType
 	}
+	metricMetadataOpts := metricmetadata.NewOptions().
+		SetInstrumentOptions(instrumentOpts)
+	if clusterClient != nil {
+		metricMetadataOpts = metricMetadataOpts.SetKVStoreFn(clusterClient.KV)
+	}
 	return &handlerOptions{
 		storage:                           downsamplerAndWriter.Storage(),
 		downsamplerAndWriter:              downsamplerAndWriter,
@@ -341,6 +353,7 @@ func NewHandlerOptions(
 		storeMetricsType:                  storeMetricsType,
 		namespaceValidator:                validators.NamespaceValidator,
 		registerMiddleware:                middleware.Default,
+		metricMetadataStore:               metricmetadata.NewStore(metricMetadataOpts),
 	}, nil
 }
 
@@ -649,5 +662,15 @@ func (o *handlerOptions) SetRegisterMiddleware(value middleware.Register) Handle
 	return &opts
 }
 
+func (o *handlerOptions) SetMetricMetadataStore(value metricmetadata.Store) HandlerOptions {
+	opts := *o
+	opts.metricMetadataStore = value
+	return &opts
+}
+
+func (o *handlerOptions) MetricMetadataStore() metricmetadata.Store {
+	return o.metricMetadataStore
+}
+
 // KVStoreProtoParser parses protobuf messages based off specific keys.
 type KVStoreProtoParser func(key string) (protoiface.MessageV1, error)
@@ -47,6 +47,9 @@ const (
 	BlockContainer
 	// BlockEmpty is a block with metadata but no series or values.
 	BlockEmpty
+	// BlockUnconsolidated is a block of raw, in-memory datapoints that may only
+	// be iterated series-wise.
+	BlockUnconsolidated
 	// BlockTest is a block used for testing only.
 	BlockTest
 )
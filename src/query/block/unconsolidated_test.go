@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package block
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/ts"
+)
+
+func TestUnconsolidatedBlock(t *testing.T) {
+	meta := Metadata{
+		Tags:   models.MustMakeTags("a", "b"),
+		Bounds: testBound,
+	}
+
+	series := make([]UnconsolidatedSeries, 0, 5)
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprint(i)
+		series = append(series, NewUnconsolidatedSeries(
+			ts.Datapoints{{Timestamp: start, Value: float64(i)}},
+			SeriesMeta{Name: []byte(name), Tags: models.MustMakeTags("i", name)},
+			UnconsolidatedSeriesStats{},
+		))
+	}
+
+	bl := NewUnconsolidatedBlock(series, meta)
+	assert.True(t, meta.Equals(bl.Meta()))
+	assert.Equal(t, BlockUnconsolidated, bl.Info().Type())
+
+	_, err := bl.StepIter()
+	assert.Error(t, err)
+
+	it, err := bl.SeriesIter()
+	require.NoError(t, err)
+	assert.Equal(t, 5, it.SeriesCount())
+	for i := 0; it.Next(); i++ {
+		current := it.Current()
+		assert.Equal(t, float64(i), current.Datapoints()[0].Value)
+		assert.Equal(t, []byte(fmt.Sprint(i)), current.Meta.Name)
+	}
+
+	require.NoError(t, it.Err())
+
+	batches, err := bl.MultiSeriesIter(2)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 3, batches[0].Size)
+	assert.Equal(t, 2, batches[1].Size)
+
+	var values []float64
+	for _, batch := range batches {
+		assert.Equal(t, batch.Size, batch.Iter.SeriesCount())
+		for batch.Iter.Next() {
+			values = append(values, batch.Iter.Current().Datapoints()[0].Value)
+		}
+	}
+
+	assert.Equal(t, []float64{0, 1, 2, 3, 4}, values)
+
+	_, err = bl.MultiSeriesIter(0)
+	assert.Error(t, err)
+	assert.NoError(t, bl.Close())
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeWithTimeValues(
+	t *testing.T,
+	q string,
+	params models.RequestParams,
+) (*storage.FetchQuery, [][]float64) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var query *storage.FetchQuery
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			q *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			query = q
+			bounds := models.Bounds{
+				Start:    xtime.ToUnixNano(q.Start),
+				Duration: q.End.Sub(q.Start),
+				StepSize: q.Interval,
+			}
+
+			// NB: each value is the time of its step in minutes.
+			values := make([]float64, bounds.Steps())
+			for i := range values {
+				values[i] = float64(bounds.Start.Add(
+					time.Duration(i)*bounds.StepSize).Seconds()) / 60
+			}
+
+			return block.Result{
+				Blocks: []block.Block{
+					test.NewBlockFromValues(bounds, [][]float64{values}),
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+
+	parser, err := promql.Parse(q, params.Step, models.NewTagOptions(),
+		promql.NewParseOptions().SetQueryBounds(params.Start, params.End))
+	require.NoError(t, err)
+
+	engine := newEngine(store, params.LookbackDuration, instrument.NewOptions())
+	bl, err := engine.ExecuteExpr(context.TODO(), parser,
+		&QueryOptions{}, storage.NewFetchOptions(), params)
+	require.NoError(t, err)
+
+	it, err := bl.StepIter()
+	require.NoError(t, err)
+
+	var steps [][]float64
+	for it.Next() {
+		steps = append(steps, append([]float64{}, it.Current().Values()...))
+	}
+
+	require.NoError(t, it.Err())
+	require.NotNil(t, query)
+	return query, steps
+}
+
+func TestExecuteAtModifier(t *testing.T) {
+	start := xtime.UnixNano(0).Add(time.Hour)
+	query, steps := executeWithTimeValues(t, "foo @ 300",
+		models.RequestParams{
+			Start:            start,
+			End:              start.Add(3 * time.Minute),
+			Step:             time.Minute,
+			LookbackDuration: time.Minute,
+		})
+
+	// NB: the fetch is offset so that its final step is at 300s.
+	assert.Equal(t, xtime.UnixNano(0).Add(2*time.Minute).ToTime(), query.Start)
+	assert.Equal(t, xtime.UnixNano(0).Add(6*time.Minute).ToTime(), query.End)
+
+	// NB: the value at 300s is repeated at every step.
+	assert.Equal(t, [][]float64{{5}, {5}, {5}, {5}}, steps)
+}
+
+func TestExecuteAtModifierStart(t *testing.T) {
+	start := xtime.UnixNano(0).Add(time.Hour)
+	query, steps := executeWithTimeValues(t, "sum(foo @ start())",
+		models.RequestParams{
+			Start:            start,
+			End:              start.Add(3 * time.Minute),
+			Step:             time.Minute,
+			LookbackDuration: time.Minute,
+		})
+
+	assert.Equal(t, start.Add(-3*time.Minute).ToTime(), query.Start)
+	assert.Equal(t, start.Add(time.Minute).ToTime(), query.End)
+
+	// NB: start() resolves to the unshifted start of the query.
+	assert.Equal(t, [][]float64{{60}, {60}, {60}, {60}}, steps)
+}
+
+func TestExecuteAtModifierWithNegativeOffsetInSubquery(t *testing.T) {
+	var (
+		start  = xtime.UnixNano(0).Add(time.Hour)
+		params = models.RequestParams{
+			Start:            start,
+			End:              start.Add(3 * time.Minute),
+			Step:             time.Minute,
+			LookbackDuration: time.Minute,
+		}
+	)
+
+	_, steps := executeWithTimeValues(t,
+		"max_over_time((foo @ 300 offset -1m)[3m:1m])", params)
+
+	// NB: the negative offset moves the evaluation time to 360s, and the
+	// steps include the range the query is shifted by.
+	assert.Equal(t, [][]float64{{6}, {6}, {6}, {6}, {6}, {6}}, steps)
+
+	for _, q := range []string{
+		"max_over_time((foo @ 360)[3m:1m])",
+		"max_over_time((foo)[3m:1m] @ 300 offset -1m)",
+	} {
+		_, expected := executeWithTimeValues(t, q, params)
+		assert.Equal(t, expected, steps, q)
+	}
+}
+
+func TestExecuteNegativeOffset(t *testing.T) {
+	start := xtime.UnixNano(0).Add(time.Hour)
+	query, _ := executeWithTimeValues(t, "foo offset -5m",
+		models.RequestParams{
+			Start:            start,
+			End:              start.Add(3 * time.Minute),
+			Step:             time.Minute,
+			LookbackDuration: time.Minute,
+		})
+
+	assert.Equal(t, start.Add(4*time.Minute).ToTime(), query.Start)
+	assert.Equal(t, start.Add(8*time.Minute).ToTime(), query.End)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type seriesCountingStorage struct {
+	storage.Storage
+
+	count  int64
+	err    error
+	counts int
+}
+
+func (s *seriesCountingStorage) CountSeries(
+	_ context.Context,
+	_ *storage.FetchQuery,
+	_ *storage.FetchOptions,
+) (int64, error) {
+	s.counts++
+	return s.count, s.err
+}
+
+// newStaleSeriesStorage returns a storage with a single series that is
+// indexed, but which has no datapoints within the query range.
+func newStaleSeriesStorage(ctrl *gomock.Controller) *seriesCountingStorage {
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			q *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			bounds := models.Bounds{
+				Start:    xtime.ToUnixNano(q.Start),
+				Duration: q.End.Sub(q.Start),
+				StepSize: q.Interval,
+			}
+
+			values := make([]float64, bounds.Steps())
+			for i := range values {
+				values[i] = math.NaN()
+			}
+
+			return block.Result{
+				Blocks: []block.Block{
+					test.NewBlockFromValues(bounds, [][]float64{values}),
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		}).AnyTimes()
+
+	return &seriesCountingStorage{Storage: store, count: 1}
+}
+
+func executeInstant(
+	t *testing.T,
+	store storage.Storage,
+	countSeriesFromIndex bool,
+	q string,
+) []float64 {
+	now := xtime.UnixNano(0).Add(time.Hour)
+	params := models.RequestParams{
+		Start:            now,
+		End:              now,
+		Step:             time.Minute,
+		LookbackDuration: time.Minute,
+	}
+
+	parser, err := promql.Parse(q, params.Step, models.NewTagOptions(),
+		promql.NewParseOptions().SetQueryBounds(params.Start, params.End))
+	require.NoError(t, err)
+
+	engine := NewEngine(NewEngineOptions().
+		SetStore(store).
+		SetLookbackDuration(params.LookbackDuration).
+		SetCountSeriesFromIndex(countSeriesFromIndex).
+		SetInstrumentOptions(instrument.NewOptions()))
+	bl, err := engine.ExecuteExpr(context.TODO(), parser,
+		&QueryOptions{}, storage.NewFetchOptions(), params)
+	require.NoError(t, err)
+
+	it, err := bl.StepIter()
+	require.NoError(t, err)
+
+	var values []float64
+	for it.Next() {
+		values = append(values, it.Current().Values()...)
+	}
+
+	require.NoError(t, it.Err())
+	return values
+}
+
+func TestExecuteCountStaleSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := newStaleSeriesStorage(ctrl)
+
+	// NB: by default the stale series is fetched and has no datapoints.
+	assert.Equal(t, []float64{0}, executeInstant(t, store, false, "count(foo)"))
+	assert.Equal(t, []float64{1}, executeInstant(t, store, false, "absent(foo)"))
+	assert.Equal(t, 0, store.counts)
+
+	// NB: when counting from the index the stale series is counted.
+	assert.Equal(t, []float64{1}, executeInstant(t, store, true, "count(foo)"))
+	assert.Equal(t, 1, store.counts)
+}
+
+func TestExecuteCountSeriesUnsupported(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store := newStaleSeriesStorage(ctrl)
+	store.err = storage.ErrSeriesCountUnsupported
+
+	assert.Equal(t, []float64{0}, executeInstant(t, store, true, "count(foo)"))
+	assert.Equal(t, []float64{1}, executeInstant(t, store, true, "absent(foo)"))
+	assert.Equal(t, 2, store.counts)
+}
@@ -29,10 +29,11 @@ import (
 )
 
 type engineOptions struct {
-	instrumentOpts   instrument.Options
-	store            storage.Storage
-	parseOptions     promql.ParseOptions
-	lookbackDuration time.Duration
+	instrumentOpts       instrument.Options
+	store                storage.Storage
+	parseOptions         promql.ParseOptions
+	lookbackDuration     time.Duration
+	countSeriesFromIndex bool
 }
 
 // NewEngineOptions returns a new instance of options used to create an engine.
@@ -72,6 +73,16 @@ func (o *engineOptions) SetLookbackDuration(v time.Duration) EngineOptions {
 	return &opts
 }
 
+func (o *engineOptions) CountSeriesFromIndex() bool {
+	return o.countSeriesFromIndex
+}
+
+func (o *engineOptions) SetCountSeriesFromIndex(v bool) EngineOptions {
+	opts := *o
+	opts.countSeriesFromIndex = v
+	return &opts
+}
+
 func (o *engineOptions) ParseOptions() promql.ParseOptions {
 	return o.parseOptions
 }
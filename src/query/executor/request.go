@@ -117,7 +117,9 @@ func (r *Request) plan(ctx context.Context, nodes parser.Nodes, edges parser.Edg
 			Info("logical plan", zap.String("plan", lp.String()))
 	}
 
-	pp, err := plan.NewPhysicalPlan(lp, r.params)
+	pp, err := plan.NewPhysicalPlan(lp, r.params, plan.PhysicalPlanOptions{
+		CountSeriesFromIndex: r.engine.opts.CountSeriesFromIndex(),
+	})
 	if err != nil {
 		return plan.PhysicalPlan{}, err
 	}
@@ -23,14 +23,17 @@ package executor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	"github.com/m3db/m3/src/query/plan"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/util/execution"
 	"github.com/m3db/m3/src/x/instrument"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"github.com/pkg/errors"
 )
@@ -147,6 +150,18 @@ func (s *ExecutionState) createNode(
 		return controller, nil
 	}
 
+	subqueryOp, ok := step.Transform.Op.(functions.SubqueryOp)
+	if ok {
+		source, controller, err := s.createSubquerySource(step.ID(), subqueryOp,
+			options)
+		if err != nil {
+			return nil, err
+		}
+
+		s.sources = append(s.sources, source)
+		return controller, nil
+	}
+
 	scalarParams, ok := step.Transform.Op.(ScalarParams)
 	if ok {
 		source, controller := CreateScalarSource(step.ID(), scalarParams, options)
@@ -179,6 +194,38 @@ func (s *ExecutionState) createNode(
 	return controller, nil
 }
 
+// createNestedState creates the execution state for an inner expression
+// which is evaluated with different time bounds to the enclosing query.
+func (s *ExecutionState) createNestedState(
+	nodes parser.Nodes,
+	edges parser.Edges,
+	start xtime.UnixNano,
+	end xtime.UnixNano,
+	step time.Duration,
+	options transform.Options,
+) (*ExecutionState, error) {
+	lp, err := plan.NewLogicalPlan(nodes, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	pp, err := plan.NewPhysicalPlan(lp, models.RequestParams{
+		Start:            start,
+		End:              end,
+		Now:              s.plan.TimeSpec.Now,
+		Step:             step,
+		Debug:            s.plan.Debug,
+		BlockType:        s.plan.BlockType,
+		LookbackDuration: s.plan.LookbackDuration,
+	}, plan.PhysicalPlanOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return GenerateExecutionState(pp, s.storage, options.FetchOptions(),
+		options.InstrumentOptions())
+}
+
 // Execute the sources in parallel and return the first error.
 func (s *ExecutionState) Execute(queryCtx *models.QueryContext) error {
 	requests := make([]execution.Request, 0, len(s.sources))
@@ -65,7 +65,7 @@ func TestValidState(t *testing.T) {
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
 	store := mock.NewMockStorage()
-	p, err := plan.NewPhysicalPlan(lp, testRequestParams())
+	p, err := plan.NewPhysicalPlan(lp, testRequestParams(), plan.PhysicalPlanOptions{})
 	require.NoError(t, err)
 	state, err := GenerateExecutionState(p, store, storage.NewFetchOptions(),
 		instrument.NewOptions())
@@ -83,7 +83,7 @@ func TestWithoutSources(t *testing.T) {
 	edges := parser.Edges{}
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := plan.NewPhysicalPlan(lp, testRequestParams())
+	p, err := plan.NewPhysicalPlan(lp, testRequestParams(), plan.PhysicalPlanOptions{})
 	require.NoError(t, err)
 	_, err = GenerateExecutionState(p, nil, storage.NewFetchOptions(), instrument.NewOptions())
 	assert.Error(t, err)
@@ -95,7 +95,7 @@ func TestOnlySources(t *testing.T) {
 	edges := parser.Edges{}
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := plan.NewPhysicalPlan(lp, testRequestParams())
+	p, err := plan.NewPhysicalPlan(lp, testRequestParams(), plan.PhysicalPlanOptions{})
 	require.NoError(t, err)
 	state, err := GenerateExecutionState(p, nil, storage.NewFetchOptions(),
 		instrument.NewOptions())
@@ -123,7 +123,7 @@ func TestMultipleSources(t *testing.T) {
 
 	lp, err := plan.NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := plan.NewPhysicalPlan(lp, testRequestParams())
+	p, err := plan.NewPhysicalPlan(lp, testRequestParams(), plan.PhysicalPlanOptions{})
 	require.NoError(t, err)
 	state, err := GenerateExecutionState(p, nil, storage.NewFetchOptions(),
 		instrument.NewOptions())
@@ -0,0 +1,160 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/ts"
+	"github.com/m3db/m3/src/x/opentracing"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// subqueryNode is a source which evaluates the inner expression of a
+// subquery with its own execution state, and emits the result as a range
+// vector aligned to the bounds of the enclosing query.
+type subqueryNode struct {
+	op         functions.SubqueryOp
+	offset     time.Duration
+	controller *transform.Controller
+	state      *ExecutionState
+	timeSpec   transform.TimeSpec
+}
+
+// createSubquerySource creates a source node for a subquery.
+func (s *ExecutionState) createSubquerySource(
+	id parser.NodeID,
+	op functions.SubqueryOp,
+	options transform.Options,
+) (parser.Source, *transform.Controller, error) {
+	if op.Step <= 0 {
+		return nil, nil, fmt.Errorf("expected positive subquery step, got %v",
+			op.Step)
+	}
+
+	var (
+		timeSpec = options.TimeSpec()
+		step     = xtime.UnixNano(op.Step)
+		offset   = op.EvaluationOffset(timeSpec)
+		// NB: the subquery must cover a full range before the first step of
+		// the enclosing query.
+		start = timeSpec.Start.Add(-1 * (op.Range + offset))
+	)
+
+	// NB: Prometheus aligns subquery steps to absolute multiples of the
+	// subquery step, rather than to the start of the enclosing query.
+	if rem := start % step; rem != 0 {
+		start = start + step - rem
+	}
+
+	state, err := s.createNestedState(op.Nodes, op.Edges, start,
+		timeSpec.End.Add(-1*offset), op.Step, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	controller := &transform.Controller{ID: id}
+	return &subqueryNode{
+		op:         op,
+		offset:     offset,
+		controller: controller,
+		state:      state,
+		timeSpec:   timeSpec,
+	}, controller, nil
+}
+
+// Execute runs the inner expression and processes its result.
+func (n *subqueryNode) Execute(queryCtx *models.QueryContext) error {
+	sp, ctx := opentracing.StartSpanFromContext(queryCtx.Ctx, functions.SubqueryType)
+	defer sp.Finish()
+
+	queryCtx = queryCtx.WithContext(ctx)
+	if err := n.state.Execute(queryCtx); err != nil {
+		n.state.sink.closeWithError(err)
+		return err
+	}
+
+	bl, err := n.state.sink.getValue()
+	if err != nil {
+		return err
+	}
+
+	rangeBlock, err := n.rangeVector(bl)
+	if closeErr := bl.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return n.controller.Process(queryCtx, rangeBlock)
+}
+
+// rangeVector converts the consolidated result of the inner expression into
+// raw datapoints, shifting them by the subquery offset, so that they can be
+// consumed by temporal functions in the same way as fetched series.
+func (n *subqueryNode) rangeVector(bl block.Block) (block.Block, error) {
+	it, err := bl.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	defer it.Close()
+
+	metas := it.SeriesMeta()
+	datapoints := make([]ts.Datapoints, len(metas))
+	for it.Next() {
+		step := it.Current()
+		t := step.Time().Add(n.offset)
+		for i, v := range step.Values() {
+			if math.IsNaN(v) {
+				continue
+			}
+
+			datapoints[i] = append(datapoints[i], ts.Datapoint{
+				Timestamp: t,
+				Value:     v,
+			})
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	series := make([]block.UnconsolidatedSeries, 0, len(metas))
+	for i, meta := range metas {
+		series = append(series, block.NewUnconsolidatedSeries(datapoints[i],
+			meta, block.UnconsolidatedSeriesStats{}))
+	}
+
+	meta := bl.Meta()
+	meta.Bounds = n.timeSpec.Bounds()
+	return block.NewUnconsolidatedBlock(series, meta), nil
+}
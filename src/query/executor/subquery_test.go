@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser/promql"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/x/instrument"
+	xtest "github.com/m3db/m3/src/x/test"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteSubquery(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		start = xtime.UnixNano(0).Add(time.Hour)
+		end   = start.Add(3 * time.Minute)
+		query *storage.FetchQuery
+	)
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			q *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			query = q
+			bounds := models.Bounds{
+				Start:    xtime.ToUnixNano(q.Start),
+				Duration: q.End.Sub(q.Start),
+				StepSize: q.Interval,
+			}
+
+			values := make([]float64, bounds.Steps())
+			for i := range values {
+				values[i] = float64(i)
+			}
+
+			return block.Result{
+				Blocks: []block.Block{
+					test.NewBlockFromValues(bounds, [][]float64{values}),
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+
+	parser, err := promql.Parse("max_over_time(foo[5m:1m])", time.Minute,
+		models.NewTagOptions(), promql.NewParseOptions())
+	require.NoError(t, err)
+
+	engine := newEngine(store, time.Minute, instrument.NewOptions())
+	bl, err := engine.ExecuteExpr(context.TODO(), parser,
+		&QueryOptions{}, storage.NewFetchOptions(), models.RequestParams{
+			Start:            start,
+			End:              end,
+			Step:             time.Minute,
+			LookbackDuration: time.Minute,
+		})
+	require.NoError(t, err)
+
+	// NB: the subquery is evaluated from a full range before the (already
+	// shifted) start of the outer query, and the inner fetch is shifted by
+	// the lookback duration.
+	require.NotNil(t, query)
+	assert.Equal(t, start.Add(-11*time.Minute).ToTime(), query.Start)
+	assert.Equal(t, end.ToTime(), query.End)
+	assert.Equal(t, time.Minute, query.Interval)
+
+	it, err := bl.StepIter()
+	require.NoError(t, err)
+
+	var actual []float64
+	for it.Next() {
+		values := it.Current().Values()
+		require.Len(t, values, 1)
+		actual = append(actual, values[0])
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []float64{6, 7, 8, 9, 10, 11, 12, 13}, actual)
+}
+
+func TestExecuteSubqueryWithOffset(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		start = xtime.UnixNano(0).Add(time.Hour)
+		end   = start.Add(3 * time.Minute)
+		query *storage.FetchQuery
+	)
+
+	store := storage.NewMockStorage(ctrl)
+	store.EXPECT().FetchBlocks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			q *storage.FetchQuery,
+			_ *storage.FetchOptions,
+		) (block.Result, error) {
+			query = q
+			return block.Result{
+				Blocks: []block.Block{
+					block.NewEmptyBlock(block.Metadata{
+						Bounds: models.Bounds{
+							Start:    xtime.ToUnixNano(q.Start),
+							Duration: q.End.Sub(q.Start),
+							StepSize: q.Interval,
+						},
+					}),
+				},
+				Metadata: block.NewResultMetadata(),
+			}, nil
+		})
+
+	parser, err := promql.Parse("sum_over_time(foo[2m:30s] offset 10m)",
+		time.Minute, models.NewTagOptions(), promql.NewParseOptions())
+	require.NoError(t, err)
+
+	engine := newEngine(store, time.Minute, instrument.NewOptions())
+	_, err = engine.ExecuteExpr(context.TODO(), parser,
+		&QueryOptions{}, storage.NewFetchOptions(), models.RequestParams{
+			Start:            start,
+			End:              end,
+			Step:             time.Minute,
+			LookbackDuration: time.Minute,
+		})
+	require.NoError(t, err)
+
+	require.NotNil(t, query)
+	assert.Equal(t, start.Add(-15*time.Minute).ToTime(), query.Start)
+	assert.Equal(t, end.Add(-10*time.Minute).ToTime(), query.End)
+	assert.Equal(t, 30*time.Second, query.Interval)
+}
@@ -128,6 +128,16 @@ func (ts TimeSpec) Bounds() models.Bounds {
 	}
 }
 
+// LastStep returns the time of the final step of the timespec.
+func (ts TimeSpec) LastStep() xtime.UnixNano {
+	bounds := ts.Bounds()
+	if steps := bounds.Steps(); steps > 1 {
+		return bounds.Start.Add(time.Duration(steps-1) * bounds.StepSize)
+	}
+
+	return bounds.Start
+}
+
 // Params are defined by transforms.
 type Params interface {
 	parser.Params
@@ -78,6 +78,13 @@ type EngineOptions interface {
 	// SetLookbackDuration sets the query lookback duration.
 	SetLookbackDuration(time.Duration) EngineOptions
 
+	// CountSeriesFromIndex returns whether instant counts and absents of a
+	// selector count the matching series from the index.
+	CountSeriesFromIndex() bool
+	// SetCountSeriesFromIndex sets whether instant counts and absents of a
+	// selector count the matching series from the index.
+	SetCountSeriesFromIndex(bool) EngineOptions
+
 	// ParseOptions returns the parse options.
 	ParseOptions() promql.ParseOptions
 	// SetParseOptions sets the parse options.
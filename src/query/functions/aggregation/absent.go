@@ -41,8 +41,17 @@ func NewAbsentOp() parser.Params {
 	return newAbsentOp()
 }
 
+// NewAbsentOpWithTags creates a new absent operation which always returns a
+// single series with the given tags, as absent_over_time does.
+func NewAbsentOpWithTags(tags models.Tags) parser.Params {
+	return absentOp{tags: tags, withTags: true}
+}
+
 // absentOp stores required properties for absent ops.
-type absentOp struct{}
+type absentOp struct {
+	tags     models.Tags
+	withTags bool
+}
 
 // OpType for the operator.
 func (o absentOp) OpType() string {
@@ -72,7 +81,7 @@ func newAbsentOp() absentOp {
 // absentNode is different from base node as it uses no grouping and has
 // special handling for the 0-series case.
 type absentNode struct {
-	op         parser.Params
+	op         absentOp
 	controller *transform.Controller
 }
 
@@ -101,14 +110,6 @@ func (n *absentNode) ProcessBlock(
 		tagOpts     = meta.Tags.Opts
 	)
 
-	// If no series in the input, return a scalar block with value 1.
-	if len(seriesMetas) == 0 {
-		return block.NewScalar(1, meta), nil
-	}
-
-	// NB: pull any common tags out into the created series.
-	dupeTags, _ := utils.DedupeMetadata(seriesMetas, tagOpts)
-	meta.Tags = meta.Tags.Add(dupeTags).Normalize()
 	emptySeriesMeta := []block.SeriesMeta{
 		block.SeriesMeta{
 			Tags: models.NewTags(0, tagOpts),
@@ -116,6 +117,32 @@ func (n *absentNode) ProcessBlock(
 		},
 	}
 
+	if n.op.withTags {
+		// NB: the result series has exactly the given tags, rather than the
+		// tags common to the input series.
+		meta.Tags = models.NewTags(0, tagOpts)
+		emptySeriesMeta[0] = block.SeriesMeta{
+			Tags: n.op.tags,
+			Name: n.op.tags.ID(),
+		}
+	}
+
+	// If no series in the input, return a scalar block with value 1.
+	if len(seriesMetas) == 0 {
+		if n.op.withTags {
+			return n.absentBlock(queryCtx, meta, emptySeriesMeta,
+				stepIter.StepCount())
+		}
+
+		return block.NewScalar(1, meta), nil
+	}
+
+	if !n.op.withTags {
+		// NB: pull any common tags out into the created series.
+		dupeTags, _ := utils.DedupeMetadata(seriesMetas, tagOpts)
+		meta.Tags = meta.Tags.Add(dupeTags).Normalize()
+	}
+
 	setupBuilderWithValuesToIndex := func(idx int) (block.Builder, error) {
 		builder, err := n.controller.BlockBuilder(queryCtx, meta, emptySeriesMeta)
 		if err != nil {
@@ -177,3 +204,27 @@ func (n *absentNode) ProcessBlock(
 
 	return builder.Build(), nil
 }
+
+func (n *absentNode) absentBlock(
+	queryCtx *models.QueryContext,
+	meta block.Metadata,
+	seriesMetas []block.SeriesMeta,
+	steps int,
+) (block.Block, error) {
+	builder, err := n.controller.BlockBuilder(queryCtx, meta, seriesMetas)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = builder.AddCols(steps); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < steps; i++ {
+		if err := builder.AppendValue(i, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Build(), nil
+}
@@ -146,3 +146,37 @@ func TestAbsent(t *testing.T) {
 		})
 	}
 }
+
+func TestAbsentWithTags(t *testing.T) {
+	tags := models.MustMakeTags("instance", "127.0.0.1")
+	for _, tt := range absentTests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := test.NewBlockFromValuesWithMetaAndSeriesMeta(
+				tt.meta,
+				tt.seriesMetas,
+				tt.vals,
+			)
+
+			c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+			op, ok := NewAbsentOpWithTags(tags).(transform.Params)
+			require.True(t, ok)
+
+			node := op.Node(c, transform.Options{})
+			err := node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), block)
+			require.NoError(t, err)
+
+			if tt.expectedVals == nil {
+				require.Equal(t, 0, len(sink.Values))
+				return
+			}
+
+			// NB: the result is always a single series with exactly the given
+			// tags, even if there are no input series.
+			require.Equal(t, 1, len(sink.Values))
+			test.EqualsWithNans(t, tt.expectedVals, sink.Values[0])
+			assert.Equal(t, 0, sink.Meta.Tags.Len())
+			require.Equal(t, 1, len(sink.Metas))
+			assert.Equal(t, tags, sink.Metas[0].Tags)
+		})
+	}
+}
@@ -41,6 +41,7 @@ var aggregationFunctions = map[string]aggregationFn{
 	StandardDeviationType: stddevFn,
 	StandardVarianceType:  varianceFn,
 	CountType:             countFn,
+	GroupType:             groupFn,
 }
 
 // NodeParams contains additional parameters required for aggregation ops.
@@ -174,3 +175,16 @@ func (n *baseNode) ProcessBlock(
 
 	return builder.Build(), nil
 }
+
+// IsUngroupedCount returns true if the operation counts all of its input
+// series into a single output series, as with `count(foo)`.
+func IsUngroupedCount(op parser.Params) bool {
+	base, ok := op.(baseOp)
+	if !ok {
+		return false
+	}
+
+	return base.opType == CountType &&
+		!base.params.Without &&
+		len(base.params.MatchingTags) == 0
+}
@@ -109,6 +109,7 @@ var fnTest = []struct {
 			{StandardDeviationType, stddevFn, []float64{2, 36.73403}},
 			{StandardVarianceType, varianceFn, []float64{4, 1349.38889}},
 			{CountType, countFn, []float64{6, 6}},
+			{GroupType, groupFn, []float64{1, 1}},
 		},
 	},
 	{
@@ -123,6 +124,7 @@ var fnTest = []struct {
 			{StandardVarianceType, varianceFn, []float64{6}},
 			{CountType, countFn, []float64{4}},
 			{AbsentType, absentFn, []float64{nan}},
+			{GroupType, groupFn, []float64{1}},
 		},
 	},
 	{
@@ -137,6 +139,7 @@ var fnTest = []struct {
 			{StandardVarianceType, varianceFn, []float64{nan}},
 			{CountType, countFn, []float64{0}},
 			{AbsentType, absentFn, []float64{1}},
+			{GroupType, groupFn, []float64{nan}},
 		},
 	},
 	{
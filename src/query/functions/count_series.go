@@ -0,0 +1,188 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/x/opentracing"
+)
+
+// CountSeriesType counts the series matching a selector from the index
+// without fetching their data.
+const CountSeriesType = "count_series"
+
+// CountSeriesOp replaces a fetch followed by an ungrouped count or absent
+// aggregation. When the storage cannot count series from the index, it
+// falls back to running the fetch and the aggregation.
+//
+// NB: series are counted if they were indexed within the query range, even
+// if they have no datapoints within the lookback of the evaluated step, so
+// stale series are counted; for this reason the physical plan only uses this
+// op when explicitly enabled.
+type CountSeriesOp struct {
+	// Fetch is the selector whose matching series are counted.
+	Fetch FetchOp
+	// Aggregation is the aggregation the count replaces, used for fallback.
+	Aggregation transform.Params
+	// Absent indicates if the op returns 1 when no series match rather
+	// than the number of matching series.
+	Absent bool
+}
+
+// OpType for the operator.
+func (o CountSeriesOp) OpType() string {
+	return CountSeriesType
+}
+
+// Bounds returns the bounds for this operation.
+func (o CountSeriesOp) Bounds() transform.BoundSpec {
+	return o.Fetch.Bounds()
+}
+
+// String is the string representation for this operation.
+func (o CountSeriesOp) String() string {
+	return fmt.Sprintf("type: %s. absent: %v, fetch: (%s)",
+		o.OpType(), o.Absent, o.Fetch.String())
+}
+
+// Node creates the count series execution node for this operation.
+func (o CountSeriesOp) Node(
+	controller *transform.Controller,
+	storage storage.Storage,
+	options transform.Options,
+) parser.Source {
+	fetchController := &transform.Controller{ID: controller.ID}
+	fetchController.AddTransform(o.Aggregation.Node(controller, options))
+	return &countSeriesNode{
+		op:         o,
+		controller: controller,
+		storage:    storage,
+		fetch:      o.Fetch.Node(fetchController, storage, options),
+		fetchOpts:  options.FetchOptions(),
+		timespec:   options.TimeSpec(),
+		blockType:  options.BlockType(),
+	}
+}
+
+type countSeriesNode struct {
+	op         CountSeriesOp
+	controller *transform.Controller
+	storage    storage.Storage
+	fetch      parser.Source
+	fetchOpts  *storage.FetchOptions
+	timespec   transform.TimeSpec
+	blockType  models.FetchedBlockType
+}
+
+// Execute runs the count series node operation.
+func (n *countSeriesNode) Execute(queryCtx *models.QueryContext) error {
+	counter, ok := n.storage.(storage.SeriesCounter)
+	if !ok {
+		return n.fetch.Execute(queryCtx)
+	}
+
+	count, err := n.count(queryCtx, counter)
+	if errors.Is(err, storage.ErrSeriesCountUnsupported) {
+		return n.fetch.Execute(queryCtx)
+	}
+	if err != nil {
+		return err
+	}
+
+	bl, err := n.block(queryCtx, count)
+	if err != nil {
+		return err
+	}
+
+	defer bl.Close()
+	return n.controller.Process(queryCtx, bl)
+}
+
+func (n *countSeriesNode) count(
+	queryCtx *models.QueryContext,
+	counter storage.SeriesCounter,
+) (int64, error) {
+	sp, ctx := opentracing.StartSpanFromContext(queryCtx.Ctx, "count_series")
+	defer sp.Finish()
+
+	opts, err := n.fetchOpts.QueryFetchOptions(queryCtx, n.blockType)
+	if err != nil {
+		return 0, err
+	}
+
+	// NB: as with fetch, the physical plan already considers the range.
+	offset := n.op.Fetch.EvaluationOffset(n.timespec)
+	return counter.CountSeries(ctx, &storage.FetchQuery{
+		Start:       n.timespec.Start.Add(-1 * offset).ToTime(),
+		End:         n.timespec.End.Add(-1 * offset).ToTime(),
+		TagMatchers: n.op.Fetch.Matchers,
+		Interval:    n.timespec.Step,
+	}, opts)
+}
+
+func (n *countSeriesNode) block(
+	queryCtx *models.QueryContext,
+	count int64,
+) (block.Block, error) {
+	meta := block.Metadata{
+		Bounds:         n.timespec.Bounds(),
+		Tags:           models.NewTags(0, models.NewTagOptions()),
+		ResultMetadata: block.NewResultMetadata(),
+	}
+
+	if n.op.Absent {
+		if count > 0 {
+			return block.NewEmptyBlock(meta), nil
+		}
+
+		return block.NewScalar(1, meta), nil
+	}
+
+	if count == 0 {
+		return block.NewEmptyBlock(meta), nil
+	}
+
+	builder, err := n.controller.BlockBuilder(queryCtx, meta,
+		[]block.SeriesMeta{{Tags: models.NewTags(0, meta.Tags.Opts), Name: []byte{}}})
+	if err != nil {
+		return nil, err
+	}
+
+	steps := meta.Bounds.Steps()
+	if err := builder.AddCols(steps); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < steps; i++ {
+		if err := builder.AppendValue(i, float64(count)); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Build(), nil
+}
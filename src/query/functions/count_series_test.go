@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions/aggregation"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/test/executor"
+	"github.com/m3db/m3/src/query/test/transformtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSeriesCounter struct {
+	mock.Storage
+
+	count int64
+	err   error
+	query *storage.FetchQuery
+}
+
+func (s *testSeriesCounter) CountSeries(
+	_ context.Context,
+	query *storage.FetchQuery,
+	_ *storage.FetchOptions,
+) (int64, error) {
+	s.query = query
+	return s.count, s.err
+}
+
+func newTestCountSeriesOp(t *testing.T, absent bool) CountSeriesOp {
+	var (
+		agg parser.Params = aggregation.NewAbsentOp()
+		err error
+	)
+	if !absent {
+		agg, err = aggregation.NewAggregationOp(aggregation.CountType,
+			aggregation.NodeParams{})
+		require.NoError(t, err)
+	}
+
+	return CountSeriesOp{
+		Fetch: FetchOp{Name: "foo", Matchers: models.Matchers{{
+			Type:  models.MatchEqual,
+			Name:  []byte("__name__"),
+			Value: []byte("foo"),
+		}}},
+		Aggregation: agg.(transform.Params),
+		Absent:      absent,
+	}
+}
+
+func executeTestCountSeries(
+	t *testing.T,
+	op CountSeriesOp,
+	store storage.Storage,
+) *executor.SinkNode {
+	_, bounds := test.GenerateValuesAndBounds(nil, nil)
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	source := op.Node(c, store, transformtest.Options(t, transform.OptionsParams{
+		TimeSpec: transform.TimeSpec{
+			Start: bounds.Start,
+			End:   bounds.End(),
+			Step:  bounds.StepSize,
+		},
+	}))
+
+	require.NoError(t, source.Execute(models.NoopQueryContext()))
+	return sink
+}
+
+func TestCountSeries(t *testing.T) {
+	store := &testSeriesCounter{Storage: mock.NewMockStorage(), count: 3}
+	sink := executeTestCountSeries(t, newTestCountSeriesOp(t, false), store)
+
+	require.NotNil(t, store.query)
+	assert.Equal(t, "foo", string(store.query.TagMatchers[0].Value))
+	assert.Equal(t, [][]float64{{3, 3, 3, 3, 3}}, sink.Values)
+	require.Equal(t, 1, len(sink.Metas))
+	assert.Equal(t, 0, sink.Metas[0].Tags.Len())
+}
+
+func TestCountSeriesNoSeries(t *testing.T) {
+	store := &testSeriesCounter{Storage: mock.NewMockStorage()}
+	sink := executeTestCountSeries(t, newTestCountSeriesOp(t, false), store)
+	assert.Equal(t, 0, len(sink.Values))
+}
+
+func TestCountSeriesAbsent(t *testing.T) {
+	store := &testSeriesCounter{Storage: mock.NewMockStorage()}
+	sink := executeTestCountSeries(t, newTestCountSeriesOp(t, true), store)
+	assert.Equal(t, [][]float64{{1, 1, 1, 1, 1}}, sink.Values)
+
+	store.count = 2
+	sink = executeTestCountSeries(t, newTestCountSeriesOp(t, true), store)
+	assert.Equal(t, 0, len(sink.Values))
+}
+
+func TestCountSeriesFallsBackToFetch(t *testing.T) {
+	values, bounds := test.GenerateValuesAndBounds(nil, nil)
+	fetchStore := mock.NewMockStorage()
+	fetchStore.SetFetchBlocksResult(block.Result{
+		Blocks: []block.Block{test.NewBlockFromValues(bounds, values)},
+	}, nil)
+
+	store := &testSeriesCounter{
+		Storage: fetchStore,
+		err:     storage.ErrSeriesCountUnsupported,
+	}
+	sink := executeTestCountSeries(t, newTestCountSeriesOp(t, false), store)
+	assert.Equal(t, [][]float64{{2, 2, 2, 2, 2}}, sink.Values)
+
+	// NB: storage which cannot count series also falls back to fetching.
+	sink = executeTestCountSeries(t, newTestCountSeriesOp(t, false), fetchStore)
+	assert.Equal(t, [][]float64{{2, 2, 2, 2, 2}}, sink.Values)
+}
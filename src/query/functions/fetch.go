@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/instrument"
 	"github.com/m3db/m3/src/x/opentracing"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	"go.uber.org/zap"
 )
@@ -46,6 +47,9 @@ type FetchOp struct {
 	Range    time.Duration
 	Offset   time.Duration
 	Matchers models.Matchers
+	// At is the evaluation time given by an `@` modifier, if any, which the
+	// final step of the query is offset to.
+	At *xtime.UnixNano
 }
 
 // FetchNode is a fetch execution node.
@@ -74,6 +78,12 @@ func (o FetchOp) Bounds() transform.BoundSpec {
 	}
 }
 
+// EvaluationOffset returns the offset the series are fetched at for the given
+// time spec, which includes the `@` modifier if there is one.
+func (o FetchOp) EvaluationOffset(timeSpec transform.TimeSpec) time.Duration {
+	return evaluationOffset(o.Offset, o.At, timeSpec)
+}
+
 // String is the string representation for this operation.
 func (o FetchOp) String() string {
 	return fmt.Sprintf("type: %s. name: %s, range: %v, offset: %v, matchers: %v",
@@ -98,6 +108,20 @@ func (o FetchOp) Node(
 	}
 }
 
+// evaluationOffset offsets the final step of the time spec to the time given
+// by an `@` modifier, in addition to the offset itself.
+func evaluationOffset(
+	offset time.Duration,
+	at *xtime.UnixNano,
+	timeSpec transform.TimeSpec,
+) time.Duration {
+	if at == nil {
+		return offset
+	}
+
+	return offset + timeSpec.LastStep().Sub(*at)
+}
+
 func (n *FetchNode) fetch(queryCtx *models.QueryContext) (block.Result, error) {
 	ctx := queryCtx.Ctx
 	sp, ctx := opentracing.StartSpanFromContext(ctx, "fetch")
@@ -114,7 +138,7 @@ func (n *FetchNode) fetch(queryCtx *models.QueryContext) (block.Result, error) {
 		return block.Result{}, err
 	}
 
-	offset := n.op.Offset
+	offset := n.op.EvaluationOffset(timeSpec)
 	return n.storage.FetchBlocks(ctx, &storage.FetchQuery{
 		Start:       startTime.Add(-1 * offset).ToTime(),
 		End:         endTime.Add(-1 * offset).ToTime(),
@@ -35,6 +35,10 @@ const (
 
 	// UnaryType offsets incoming data point values by the given operator.
 	UnaryType = "unary"
+
+	// AtType offsets incoming data point timestamps and metadata so that the
+	// final step of the query is evaluated at the time given by an `@` modifier.
+	AtType = "at"
 )
 
 // NewLazyOp creates a new lazy operation
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lazy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/parser"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// NewOffsetOptions creates lazy options which offset data point timestamps
+// and metadata by the given offset.
+func NewOffsetOptions(offset time.Duration) block.LazyOptions {
+	var (
+		tt = func(t xtime.UnixNano) xtime.UnixNano { return t.Add(offset) }
+		mt = func(meta block.Metadata) block.Metadata {
+			meta.Bounds.Start = meta.Bounds.Start.Add(offset)
+			return meta
+		}
+	)
+
+	return block.NewLazyOptions().
+		SetTimeTransform(tt).
+		SetMetaTransform(mt)
+}
+
+// NewAtOp creates a new operation for a selector with an `@` modifier, which
+// offsets data points by the given offset, plus the distance between the
+// modifier time and the final step of the query.
+func NewAtOp(at xtime.UnixNano, offset time.Duration) parser.Params {
+	return atOp{
+		at:     at,
+		offset: offset,
+	}
+}
+
+type atOp struct {
+	at     xtime.UnixNano
+	offset time.Duration
+}
+
+func (o atOp) OpType() string {
+	return AtType
+}
+
+func (o atOp) String() string {
+	return fmt.Sprintf("type: %s, at: %v, offset: %v", o.OpType(), o.at, o.offset)
+}
+
+func (o atOp) Node(
+	controller *transform.Controller,
+	opts transform.Options,
+) transform.OpNode {
+	offset := o.offset + opts.TimeSpec().LastStep().Sub(o.at)
+	return &baseNode{
+		op: baseOp{
+			opType:   AtType,
+			lazyOpts: NewOffsetOptions(offset),
+		},
+		controller: controller,
+	}
+}
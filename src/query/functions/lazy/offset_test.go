@@ -0,0 +1,73 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lazy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/test/transformtest"
+	xtime "github.com/m3db/m3/src/x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtOp(t *testing.T) {
+	var (
+		at    = xtime.UnixNano(0).Add(5 * time.Minute)
+		start = xtime.UnixNano(0).Add(time.Hour)
+		op    = NewAtOp(at, -time.Minute)
+	)
+
+	assert.Equal(t, AtType, op.OpType())
+
+	atOp, ok := op.(atOp)
+	require.True(t, ok)
+
+	opts := transformtest.Options(t, transform.OptionsParams{
+		TimeSpec: transform.TimeSpec{
+			Start: start,
+			End:   start.Add(4 * time.Minute),
+			Step:  time.Minute,
+		},
+	})
+
+	n, ok := atOp.Node(nil, opts).(*baseNode)
+	require.True(t, ok)
+	assert.Equal(t, AtType, n.Params().OpType())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := block.NewMockBlock(ctrl)
+	b.EXPECT().Meta().Return(block.Metadata{
+		Bounds: models.Bounds{Start: at.Add(time.Minute)},
+	})
+
+	// NB: the final step at 1h3m is offset to 5m, less the negative offset.
+	bl := n.processBlock(b)
+	assert.Equal(t, start.Add(3*time.Minute), bl.Meta().Bounds.Start)
+}
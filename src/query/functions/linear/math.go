@@ -60,6 +60,55 @@ const (
 
 	// Log10Type calculates the decimal logarithm for values.
 	Log10Type = "log10"
+
+	// SgnType returns the sign of all values, i.e. 1 for positive values, -1
+	// for negative values, and 0 for values equal to zero.
+	SgnType = "sgn"
+
+	// Trigonometric functions are applied to all values, with angles given
+	// in radians.
+
+	// AcosType calculates the arccosine for all values.
+	AcosType = "acos"
+
+	// AcoshType calculates the inverse hyperbolic cosine for all values.
+	AcoshType = "acosh"
+
+	// AsinType calculates the arcsine for all values.
+	AsinType = "asin"
+
+	// AsinhType calculates the inverse hyperbolic sine for all values.
+	AsinhType = "asinh"
+
+	// AtanType calculates the arctangent for all values.
+	AtanType = "atan"
+
+	// AtanhType calculates the inverse hyperbolic tangent for all values.
+	AtanhType = "atanh"
+
+	// CosType calculates the cosine for all values.
+	CosType = "cos"
+
+	// CoshType calculates the hyperbolic cosine for all values.
+	CoshType = "cosh"
+
+	// SinType calculates the sine for all values.
+	SinType = "sin"
+
+	// SinhType calculates the hyperbolic sine for all values.
+	SinhType = "sinh"
+
+	// TanType calculates the tangent for all values.
+	TanType = "tan"
+
+	// TanhType calculates the hyperbolic tangent for all values.
+	TanhType = "tanh"
+
+	// DegType converts all values from radians to degrees.
+	DegType = "deg"
+
+	// RadType converts all values from degrees to radians.
+	RadType = "rad"
 )
 
 var (
@@ -72,15 +121,57 @@ var (
 		LnType:    math.Log,
 		Log2Type:  math.Log2,
 		Log10Type: math.Log10,
+		SgnType:   sgn,
+		AcosType:  math.Acos,
+		AcoshType: math.Acosh,
+		AsinType:  math.Asin,
+		AsinhType: math.Asinh,
+		AtanType:  math.Atan,
+		AtanhType: math.Atanh,
+		CosType:   math.Cos,
+		CoshType:  math.Cosh,
+		SinType:   math.Sin,
+		SinhType:  math.Sinh,
+		TanType:   math.Tan,
+		TanhType:  math.Tanh,
+		DegType:   deg,
+		RadType:   rad,
 	}
 )
 
+// MathFunc returns the value transform applied by the given math type.
+func MathFunc(opType string) (block.ValueTransform, bool) {
+	fn, ok := mathFuncs[opType]
+	return fn, ok
+}
+
 // NewMathOp creates a new math op based on the type.
 func NewMathOp(opType string) (parser.Params, error) {
-	if fn, ok := mathFuncs[opType]; ok {
+	if fn, ok := MathFunc(opType); ok {
 		lazyOpts := block.NewLazyOptions().SetValueTransform(fn)
 		return lazy.NewLazyOp(opType, lazyOpts)
 	}
 
 	return nil, fmt.Errorf("unknown math type: %s", opType)
 }
+
+func sgn(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+
+	if v > 0 {
+		return 1
+	}
+
+	// NB: returns zero and NaN values unchanged.
+	return v
+}
+
+func deg(v float64) float64 {
+	return v * 180 / math.Pi
+}
+
+func rad(v float64) float64 {
+	return v * math.Pi / 180
+}
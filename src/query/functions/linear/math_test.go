@@ -250,3 +250,78 @@ func TestNonExistentFunc(t *testing.T) {
 	_, err := NewMathOp("nonexistent_func")
 	require.Error(t, err)
 }
+
+func TestSgn(t *testing.T) {
+	v := [][]float64{
+		{0, math.NaN(), -2.2, 3.3, 4},
+		{math.NaN(), -6, 0, 8, math.Inf(-1)},
+	}
+
+	values, bounds := test.GenerateValuesAndBounds(v, nil)
+	block := test.NewBlockFromValues(bounds, values)
+	c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+	mathOp, err := NewMathOp(SgnType)
+	require.NoError(t, err)
+
+	op, ok := mathOp.(transform.Params)
+	require.True(t, ok)
+
+	node := op.Node(c, transform.Options{})
+	err = node.Process(models.NoopQueryContext(), parser.NodeID(rune(0)), block)
+	require.NoError(t, err)
+	expected := [][]float64{
+		{0, math.NaN(), -1, 1, 1},
+		{math.NaN(), -1, 0, 1, -1},
+	}
+
+	assert.Len(t, sink.Values, 2)
+	test.EqualsWithNans(t, expected, sink.Values)
+}
+
+func TestTrigonometricFunctions(t *testing.T) {
+	tests := []struct {
+		opType string
+		fn     func(float64) float64
+	}{
+		{AcosType, math.Acos},
+		{AcoshType, math.Acosh},
+		{AsinType, math.Asin},
+		{AsinhType, math.Asinh},
+		{AtanType, math.Atan},
+		{AtanhType, math.Atanh},
+		{CosType, math.Cos},
+		{CoshType, math.Cosh},
+		{SinType, math.Sin},
+		{SinhType, math.Sinh},
+		{TanType, math.Tan},
+		{TanhType, math.Tanh},
+		{DegType, func(v float64) float64 { return v * 180 / math.Pi }},
+		{RadType, func(v float64) float64 { return v * math.Pi / 180 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.opType, func(t *testing.T) {
+			v := [][]float64{
+				{0, math.NaN(), 0.5, -0.5, 1},
+				{math.NaN(), 2, -1, 180, math.Pi},
+			}
+
+			values, bounds := test.GenerateValuesAndBounds(v, nil)
+			block := test.NewBlockFromValues(bounds, values)
+			c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+			mathOp, err := NewMathOp(tt.opType)
+			require.NoError(t, err)
+
+			op, ok := mathOp.(transform.Params)
+			require.True(t, ok)
+
+			node := op.Node(c, transform.Options{})
+			err = node.Process(models.NoopQueryContext(),
+				parser.NodeID(rune(0)), block)
+			require.NoError(t, err)
+			expected := expectedMathVals(values, tt.fn)
+			assert.Len(t, sink.Values, 2)
+			test.EqualsWithNans(t, expected, sink.Values)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"math"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+)
+
+// StepInvariantType repeats the value of an expression at its final step
+// across every step of the query.
+const StepInvariantType = "step_invariant"
+
+// StepInvariantOp repeats the final step of an expression, which is used for
+// expressions whose selectors have an `@` modifier. The selectors are offset
+// so that the final step is evaluated at the time given by the modifier.
+type StepInvariantOp struct{}
+
+// OpType for the operator.
+func (o StepInvariantOp) OpType() string {
+	return StepInvariantType
+}
+
+// String is the string representation for this operation.
+func (o StepInvariantOp) String() string {
+	return "type: " + o.OpType()
+}
+
+// Node creates the step invariant execution node for this operation.
+func (o StepInvariantOp) Node(
+	controller *transform.Controller,
+	_ transform.Options,
+) transform.OpNode {
+	return &stepInvariantNode{
+		op:         o,
+		controller: controller,
+	}
+}
+
+type stepInvariantNode struct {
+	op         StepInvariantOp
+	controller *transform.Controller
+}
+
+func (n *stepInvariantNode) Params() parser.Params {
+	return n.op
+}
+
+func (n *stepInvariantNode) Process(
+	queryCtx *models.QueryContext,
+	ID parser.NodeID,
+	b block.Block,
+) error {
+	return transform.ProcessSimpleBlock(n, n.controller, queryCtx, ID, b)
+}
+
+func (n *stepInvariantNode) ProcessBlock(
+	queryCtx *models.QueryContext,
+	_ parser.NodeID,
+	b block.Block,
+) (block.Block, error) {
+	it, err := b.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	defer it.Close()
+
+	var (
+		metas  = it.SeriesMeta()
+		values = make([]float64, len(metas))
+	)
+
+	for i := range values {
+		values[i] = math.NaN()
+	}
+
+	for it.Next() {
+		copy(values, it.Current().Values())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	meta := b.Meta()
+	builder, err := n.controller.BlockBuilder(queryCtx, meta, metas)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := meta.Bounds.Steps()
+	if err := builder.AddCols(steps); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < steps; i++ {
+		if err := builder.AppendValues(i, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Build(), nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package functions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/parser"
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// SubqueryType evaluates an inner expression at a fixed step over a range.
+const SubqueryType = "subquery"
+
+// SubqueryOp stores required properties for a subquery. The inner expression
+// is kept as its own DAG since it is evaluated with a different time spec to
+// the enclosing query; the executor runs it and feeds the resulting range
+// vector into the subquery's children.
+type SubqueryOp struct {
+	Range  time.Duration
+	Step   time.Duration
+	Offset time.Duration
+	Nodes  parser.Nodes
+	Edges  parser.Edges
+	// At is the evaluation time given by an `@` modifier, if any, which the
+	// final step of the enclosing query is offset to.
+	At *xtime.UnixNano
+}
+
+// OpType for the operator.
+func (o SubqueryOp) OpType() string {
+	return SubqueryType
+}
+
+// Bounds returns the bounds for this operation.
+func (o SubqueryOp) Bounds() transform.BoundSpec {
+	return transform.BoundSpec{
+		Range:  o.Range,
+		Offset: o.Offset,
+	}
+}
+
+// EvaluationOffset returns the offset the inner expression is evaluated at for
+// the given time spec, which includes the `@` modifier if there is one.
+func (o SubqueryOp) EvaluationOffset(timeSpec transform.TimeSpec) time.Duration {
+	return evaluationOffset(o.Offset, o.At, timeSpec)
+}
+
+// String is the string representation for this operation.
+func (o SubqueryOp) String() string {
+	return fmt.Sprintf("type: %s, range: %v, step: %v, offset: %v, nodes: %v",
+		o.OpType(), o.Range, o.Step, o.Offset, o.Nodes)
+}
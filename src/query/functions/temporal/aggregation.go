@@ -52,6 +52,17 @@ const (
 	// StdVarType calculates the standard variance of all values in the specified interval.
 	StdVarType = "stdvar_over_time"
 
+	// LastType returns the most recent value in the specified interval.
+	LastType = "last_over_time"
+
+	// PresentType returns 1 for any series with values in the specified interval.
+	PresentType = "present_over_time"
+
+	// AbsentType returns 1 if there are no values in the specified interval.
+	// NB: this is evaluated as present_over_time for each series; the parser
+	// follows it with an absent aggregation across all series.
+	AbsentType = "absent_over_time"
+
 	// QuantileType calculates the φ-quantile (0 ≤ φ ≤ 1) of the values in the specified interval.
 	QuantileType = "quantile_over_time"
 )
@@ -60,13 +71,16 @@ type aggFunc func([]float64) float64
 
 var (
 	aggFuncs = map[string]aggFunc{
-		AvgType:    avgOverTime,
-		CountType:  countOverTime,
-		MinType:    minOverTime,
-		MaxType:    maxOverTime,
-		SumType:    sumOverTime,
-		StdDevType: stddevOverTime,
-		StdVarType: stdvarOverTime,
+		AvgType:     avgOverTime,
+		CountType:   countOverTime,
+		MinType:     minOverTime,
+		MaxType:     maxOverTime,
+		SumType:     sumOverTime,
+		StdDevType:  stddevOverTime,
+		StdVarType:  stdvarOverTime,
+		LastType:    lastOverTime,
+		PresentType: presentOverTime,
+		AbsentType:  presentOverTime,
 	}
 )
 
@@ -220,6 +234,24 @@ func stdvarOverTime(values []float64) float64 {
 	return aux / count
 }
 
+// NB: last_over_time returns the last raw value, including NaN values.
+func lastOverTime(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	return values[len(values)-1]
+}
+
+// NB: NaN values are present datapoints, as in Prometheus.
+func presentOverTime(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	return 1
+}
+
 func sumAndCount(values []float64) (float64, float64) {
 	sum := 0.0
 	count := 0.0
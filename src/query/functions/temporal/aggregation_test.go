@@ -25,7 +25,14 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/parser"
+	"github.com/m3db/m3/src/query/test"
+	"github.com/m3db/m3/src/query/test/executor"
+	"github.com/m3db/m3/src/query/test/transformtest"
+	xtime "github.com/m3db/m3/src/x/time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -213,6 +220,56 @@ var aggregationTestCases = []testCase{
 			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
 		},
 	},
+	{
+		name:     "last_over_time",
+		opType:   LastType,
+		keepName: true,
+		vals: [][]float64{
+			{nan, 1, 2, nan, nan, nan, nan, nan, nan, nan},
+			{5, 6, 7, 8, 9, 5, 6, 7, 8, 9},
+		},
+		expected: [][]float64{
+			{nan, 1, 2, nan, nan, nan, nan, nan, nan, nan},
+			{5, 6, 7, 8, 9, 5, 6, 7, 8, 9},
+		},
+	},
+	{
+		name:     "last_over_time all NaNs",
+		opType:   LastType,
+		keepName: true,
+		vals: [][]float64{
+			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
+			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
+		},
+		expected: [][]float64{
+			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
+			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
+		},
+	},
+	{
+		name:   "present_over_time",
+		opType: PresentType,
+		vals: [][]float64{
+			{nan, 1, 2, nan, nan, nan, nan, nan, nan, nan},
+			{5, 6, 7, 8, 9, 5, 6, 7, 8, 9},
+		},
+		expected: [][]float64{
+			{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		},
+	},
+	{
+		name:   "present_over_time all NaNs",
+		opType: PresentType,
+		vals: [][]float64{
+			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
+			{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan},
+		},
+		expected: [][]float64{
+			{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		},
+	},
 	{
 		name:   "quantile_over_time",
 		opType: QuantileType,
@@ -262,3 +319,33 @@ func TestUnknownAggregation(t *testing.T) {
 	_, err := NewAggOp([]interface{}{5 * time.Minute}, "unknown_agg_func")
 	require.Error(t, err)
 }
+
+func TestLastOverTimeInstantRemovesEmptySeries(t *testing.T) {
+	for _, runBatched := range []bool{true, false} {
+		bounds := models.Bounds{
+			Start:    xtime.Now().Truncate(time.Minute),
+			Duration: time.Minute,
+			StepSize: time.Minute,
+		}
+
+		seriesMetas := test.NewSeriesMeta("series", 3)
+		bl := test.NewUnconsolidatedBlockFromDatapointsWithMeta(bounds,
+			seriesMetas, buildMetadata(), [][]float64{{nan}, {}, {1}}, runBatched)
+
+		op, err := NewAggOp([]interface{}{time.Minute}, LastType)
+		require.NoError(t, err)
+
+		c, sink := executor.NewControllerWithSink(parser.NodeID(rune(1)))
+		node := op.Node(c, transformtest.Options(t, transform.OptionsParams{}))
+		queryCtx := models.NoopQueryContext()
+		queryCtx.Options.Instantaneous = true
+		require.NoError(t, node.Process(queryCtx, parser.NodeID(rune(0)), bl))
+
+		// NB: the series without datapoints is removed, and the NaN is kept.
+		assert.True(t, sink.Meta.ResultMetadata.KeepNaNs)
+		test.EqualsWithNans(t, [][]float64{{nan}, {1}}, sink.Values)
+		require.Equal(t, 2, len(sink.Metas))
+		assert.Equal(t, seriesMetas[0].Tags, sink.Metas[0].Tags)
+		assert.Equal(t, seriesMetas[2].Tags, sink.Metas[1].Tags)
+	}
+}
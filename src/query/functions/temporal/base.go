@@ -133,6 +133,16 @@ func (c *baseNode) Process(
 		stepSize:    xtime.UnixNano(bounds.StepSize),
 		steps:       bounds.Steps(),
 		resultMeta:  resultMeta,
+		// NB: last_over_time returns raw values, so retains the series name.
+		keepName: c.op.operatorType == LastType,
+	}
+
+	// NB: last_over_time returns raw NaN values for instant queries, so series
+	// without any datapoints in their window must be removed rather than being
+	// rendered as NaN.
+	if queryCtx.Options.Instantaneous && c.op.operatorType == LastType {
+		m.resultMeta.KeepNaNs = true
+		m.trackEmpty = true
 	}
 
 	concurrency := runtime.NumCPU()
@@ -141,9 +151,9 @@ func (c *baseNode) Process(
 	if err != nil {
 		// NB: If the unconsolidated block does not support multi series iteration,
 		// fallback to processing series one by one.
-		builder, err = c.singleProcess(ctx, b, m)
+		builder, err = c.singleProcess(ctx, b, &m)
 	} else {
-		builder, err = c.batchProcess(ctx, b, batches, m)
+		builder, err = c.batchProcess(ctx, b, batches, &m)
 	}
 
 	if err != nil {
@@ -156,10 +166,84 @@ func (c *baseNode) Process(
 	}
 
 	bl := builder.Build()
+	if m.trackEmpty {
+		bl, err = c.removeEmptySeries(queryCtx, bl, m.empty)
+		if err != nil {
+			return err
+		}
+	}
+
 	defer bl.Close()
 	return c.controller.Process(queryCtx, bl)
 }
 
+// removeEmptySeries removes series which had no datapoints in their final
+// window from the block.
+func (c *baseNode) removeEmptySeries(
+	queryCtx *models.QueryContext,
+	bl block.Block,
+	empty []bool,
+) (block.Block, error) {
+	numEmpty := 0
+	for _, e := range empty {
+		if e {
+			numEmpty++
+		}
+	}
+
+	if numEmpty == 0 {
+		return bl, nil
+	}
+
+	defer bl.Close()
+	stepIter, err := bl.StepIter()
+	if err != nil {
+		return nil, err
+	}
+
+	seriesMetas := stepIter.SeriesMeta()
+	if len(seriesMetas) != len(empty) {
+		return nil, fmt.Errorf("mismatched series count: %d, expected %d",
+			len(seriesMetas), len(empty))
+	}
+
+	filteredMetas := make([]block.SeriesMeta, 0, len(seriesMetas)-numEmpty)
+	for i, meta := range seriesMetas {
+		if !empty[i] {
+			filteredMetas = append(filteredMetas, meta)
+		}
+	}
+
+	builder, err := c.controller.BlockBuilder(queryCtx, bl.Meta(), filteredMetas)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := builder.AddCols(stepIter.StepCount()); err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(filteredMetas))
+	for index := 0; stepIter.Next(); index++ {
+		values = values[:0]
+		for i, v := range stepIter.Current().Values() {
+			if !empty[i] {
+				values = append(values, v)
+			}
+		}
+
+		if err := builder.AppendValues(index, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := stepIter.Err(); err != nil {
+		return nil, err
+	}
+
+	return builder.Build(), nil
+}
+
 type blockMeta struct {
 	end         xtime.UnixNano
 	aggDuration xtime.UnixNano
@@ -167,13 +251,18 @@ type blockMeta struct {
 	queryCtx    *models.QueryContext
 	steps       int
 	resultMeta  block.ResultMetadata
+	keepName    bool
+	// trackEmpty indicates that empty tracks the series which had no
+	// datapoints in their final window.
+	trackEmpty bool
+	empty      []bool
 }
 
 func (c *baseNode) batchProcess(
 	ctx context.Context,
 	b block.Block,
 	iterBatches []block.SeriesIterBatch,
-	m blockMeta,
+	m *blockMeta,
 ) (block.Builder, error) {
 	var (
 		mu       sync.Mutex
@@ -199,6 +288,10 @@ func (c *baseNode) batchProcess(
 		numSeries += b.Size
 	}
 
+	if m.trackEmpty {
+		m.empty = make([]bool, numSeries)
+	}
+
 	builder.PopulateColumns(numSeries)
 	for _, batch := range iterBatches {
 		wg.Add(1)
@@ -208,7 +301,7 @@ func (c *baseNode) batchProcess(
 		idx = idx + batch.Size
 		p := c.makeProcessor.initialize(c.op.duration, c.transformOpts)
 		go func() {
-			err := parallelProcess(ctx, loopIndex, batch.Iter, builder, m, p, &mu)
+			err := parallelProcess(ctx, loopIndex, batch.Iter, builder, *m, p, &mu)
 			if err != nil {
 				mu.Lock()
 				// NB: this no-ops if the error is nil.
@@ -277,9 +370,12 @@ func parallelProcess(
 
 		// rename series to exclude their __name__ tag as
 		// part of function processing.
-		seriesMeta.Tags = seriesMeta.Tags.WithoutName()
-		seriesMeta.Name = seriesMeta.Tags.ID()
+		if !blockMeta.keepName {
+			seriesMeta.Tags = seriesMeta.Tags.WithoutName()
+			seriesMeta.Name = seriesMeta.Tags.ID()
+		}
 		values = values[:0]
+		empty := true
 		for i := 0; i < blockMeta.steps; i++ {
 			iterBounds := iterationBounds{
 				start: start,
@@ -294,11 +390,17 @@ func parallelProcess(
 				newVal = processor.process(datapoints[l:r], iterBounds)
 			}
 
+			empty = !b || l == r
 			values = append(values, newVal)
 			start += step
 			end += step
 		}
 
+		if blockMeta.empty != nil {
+			// NB: each batch writes to a distinct range of indices.
+			blockMeta.empty[idx] = empty
+		}
+
 		mu.Lock()
 
 		// NB: this sets the values internally, so no need to worry about keeping
@@ -317,7 +419,7 @@ func parallelProcess(
 func (c *baseNode) singleProcess(
 	ctx context.Context,
 	b block.Block,
-	m blockMeta,
+	m *blockMeta,
 ) (block.Builder, error) {
 	var (
 		start          = time.Now()
@@ -344,8 +446,13 @@ func (c *baseNode) singleProcess(
 
 	// rename series to exclude their __name__ tag as part of function processing.
 	resultSeriesMeta := make([]block.SeriesMeta, 0, len(seriesIter.SeriesMeta()))
-	for _, m := range seriesIter.SeriesMeta() {
-		tags := m.Tags.WithoutName()
+	for _, meta := range seriesIter.SeriesMeta() {
+		if m.keepName {
+			resultSeriesMeta = append(resultSeriesMeta, meta)
+			continue
+		}
+
+		tags := meta.Tags.WithoutName()
 		resultSeriesMeta = append(resultSeriesMeta, block.SeriesMeta{
 			Name: tags.ID(),
 			Tags: tags,
@@ -364,10 +471,15 @@ func (c *baseNode) singleProcess(
 		return nil, err
 	}
 
+	if m.trackEmpty {
+		m.empty = make([]bool, 0, len(resultSeriesMeta))
+	}
+
 	p := c.makeProcessor.initialize(c.op.duration, c.transformOpts)
 	for seriesIter.Next() {
 		var (
 			newVal float64
+			empty  = true
 			init   = 0
 			end    = m.end
 			start  = end - m.aggDuration
@@ -400,9 +512,14 @@ func (c *baseNode) singleProcess(
 				return nil, err
 			}
 
+			empty = !b || l == r
 			start += step
 			end += step
 		}
+
+		if m.trackEmpty {
+			m.empty = append(m.empty, empty)
+		}
 	}
 
 	return builder, seriesIter.Err()
@@ -50,6 +50,7 @@ type testCase struct {
 	vals        [][]float64
 	expected    [][]float64
 	withWarning bool
+	keepName    bool
 }
 
 type opGenerator func(t *testing.T, tc testCase) transform.Params
@@ -153,6 +154,10 @@ func testTemporalFunc(t *testing.T, opGen opGenerator, tests []testCase) {
 				// NB: name should be dropped from series tags, and the name
 				// should be the updated ID.
 				expectedSeriesMetas := []block.SeriesMeta{metaOne, metaTwo}
+				if tt.keepName {
+					expectedSeriesMetas = seriesMetas
+				}
+
 				require.Equal(t, expectedSeriesMetas, sink.Metas)
 			})
 		}
@@ -23,26 +23,63 @@
 
 package prompb
 
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import _ "github.com/gogo/protobuf/gogoproto"
-
-import io "io"
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
 type WriteRequest struct {
-	Timeseries []TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries"`
+	Timeseries []TimeSeries     `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries"`
+	Metadata   []MetricMetadata `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata"`
 }
 
-func (m *WriteRequest) Reset()                    { *m = WriteRequest{} }
-func (m *WriteRequest) String() string            { return proto.CompactTextString(m) }
-func (*WriteRequest) ProtoMessage()               {}
-func (*WriteRequest) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{0} }
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+func (*WriteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_47910ee569e085ef, []int{0}
+}
+func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WriteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WriteRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WriteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteRequest.Merge(m, src)
+}
+func (m *WriteRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *WriteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteRequest proto.InternalMessageInfo
 
 func (m *WriteRequest) GetTimeseries() []TimeSeries {
 	if m != nil {
@@ -51,14 +88,49 @@ func (m *WriteRequest) GetTimeseries() []TimeSeries {
 	return nil
 }
 
+func (m *WriteRequest) GetMetadata() []MetricMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type ReadRequest struct {
-	Queries []*Query `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+	Queries []*Query `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_47910ee569e085ef, []int{1}
+}
+func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReadRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadRequest.Merge(m, src)
+}
+func (m *ReadRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadRequest.DiscardUnknown(m)
 }
 
-func (m *ReadRequest) Reset()                    { *m = ReadRequest{} }
-func (m *ReadRequest) String() string            { return proto.CompactTextString(m) }
-func (*ReadRequest) ProtoMessage()               {}
-func (*ReadRequest) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{1} }
+var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
 
 func (m *ReadRequest) GetQueries() []*Query {
 	if m != nil {
@@ -69,13 +141,41 @@ func (m *ReadRequest) GetQueries() []*Query {
 
 type ReadResponse struct {
 	// In same order as the request's queries.
-	Results []*QueryResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	Results []*QueryResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (m *ReadResponse) Reset()                    { *m = ReadResponse{} }
-func (m *ReadResponse) String() string            { return proto.CompactTextString(m) }
-func (*ReadResponse) ProtoMessage()               {}
-func (*ReadResponse) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{2} }
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_47910ee569e085ef, []int{2}
+}
+func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReadResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadResponse.Merge(m, src)
+}
+func (m *ReadResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadResponse proto.InternalMessageInfo
 
 func (m *ReadResponse) GetResults() []*QueryResult {
 	if m != nil {
@@ -87,13 +187,41 @@ func (m *ReadResponse) GetResults() []*QueryResult {
 type Query struct {
 	StartTimestampMs int64           `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
 	EndTimestampMs   int64           `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
-	Matchers         []*LabelMatcher `protobuf:"bytes,3,rep,name=matchers" json:"matchers,omitempty"`
+	Matchers         []*LabelMatcher `protobuf:"bytes,3,rep,name=matchers,proto3" json:"matchers,omitempty"`
 }
 
-func (m *Query) Reset()                    { *m = Query{} }
-func (m *Query) String() string            { return proto.CompactTextString(m) }
-func (*Query) ProtoMessage()               {}
-func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{3} }
+func (m *Query) Reset()         { *m = Query{} }
+func (m *Query) String() string { return proto.CompactTextString(m) }
+func (*Query) ProtoMessage()    {}
+func (*Query) Descriptor() ([]byte, []int) {
+	return fileDescriptor_47910ee569e085ef, []int{3}
+}
+func (m *Query) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Query) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Query.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Query) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Query.Merge(m, src)
+}
+func (m *Query) XXX_Size() int {
+	return m.Size()
+}
+func (m *Query) XXX_DiscardUnknown() {
+	xxx_messageInfo_Query.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Query proto.InternalMessageInfo
 
 func (m *Query) GetStartTimestampMs() int64 {
 	if m != nil {
@@ -117,13 +245,41 @@ func (m *Query) GetMatchers() []*LabelMatcher {
 }
 
 type QueryResult struct {
-	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries,omitempty"`
 }
 
-func (m *QueryResult) Reset()                    { *m = QueryResult{} }
-func (m *QueryResult) String() string            { return proto.CompactTextString(m) }
-func (*QueryResult) ProtoMessage()               {}
-func (*QueryResult) Descriptor() ([]byte, []int) { return fileDescriptorRemote, []int{4} }
+func (m *QueryResult) Reset()         { *m = QueryResult{} }
+func (m *QueryResult) String() string { return proto.CompactTextString(m) }
+func (*QueryResult) ProtoMessage()    {}
+func (*QueryResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_47910ee569e085ef, []int{4}
+}
+func (m *QueryResult) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryResult.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResult.Merge(m, src)
+}
+func (m *QueryResult) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResult proto.InternalMessageInfo
 
 func (m *QueryResult) GetTimeseries() []*TimeSeries {
 	if m != nil {
@@ -139,10 +295,44 @@ func init() {
 	proto.RegisterType((*Query)(nil), "m3prometheus.Query")
 	proto.RegisterType((*QueryResult)(nil), "m3prometheus.QueryResult")
 }
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/query/generated/proto/prompb/remote.proto", fileDescriptor_47910ee569e085ef)
+}
+
+var fileDescriptor_47910ee569e085ef = []byte{
+	// 395 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x31, 0x6b, 0xdb, 0x40,
+	0x18, 0x86, 0xa5, 0xba, 0xb5, 0xcd, 0xd9, 0x14, 0x73, 0x5d, 0x5c, 0x53, 0x54, 0xa3, 0xc9, 0x43,
+	0x2d, 0x41, 0x05, 0xa5, 0x43, 0x71, 0x8b, 0x3b, 0x74, 0xa9, 0x86, 0x28, 0x86, 0x40, 0x16, 0x73,
+	0x92, 0xbe, 0xc8, 0x02, 0x9f, 0x24, 0xdf, 0x7d, 0x1a, 0xfc, 0x27, 0x42, 0xb6, 0xfc, 0x25, 0x8f,
+	0x1e, 0x33, 0x85, 0x60, 0xff, 0x91, 0xa0, 0x93, 0x65, 0x24, 0xc8, 0x92, 0x2c, 0x42, 0xba, 0xef,
+	0x79, 0x5e, 0x5e, 0xdd, 0x1d, 0xf9, 0x13, 0xc5, 0xb8, 0xca, 0x7d, 0x2b, 0x48, 0xb9, 0xcd, 0x9d,
+	0xd0, 0xb7, 0xb9, 0x63, 0x4b, 0x11, 0xd8, 0x9b, 0x1c, 0xc4, 0xd6, 0x8e, 0x20, 0x01, 0xc1, 0x10,
+	0x42, 0x3b, 0x13, 0x29, 0xa6, 0xc5, 0x93, 0x67, 0xbe, 0x2d, 0x80, 0xa7, 0x08, 0x96, 0x5a, 0xa3,
+	0x7d, 0xee, 0x14, 0xcb, 0x80, 0x2b, 0xc8, 0xe5, 0xe8, 0xf7, 0x5b, 0xf2, 0x70, 0x9b, 0x81, 0x2c,
+	0xe3, 0x46, 0xd3, 0x5a, 0x40, 0x94, 0x46, 0x69, 0x49, 0xfa, 0xf9, 0x8d, 0xfa, 0x2a, 0xb5, 0xe2,
+	0xad, 0xc4, 0xcd, 0x5b, 0x9d, 0xf4, 0xaf, 0x44, 0x8c, 0xe0, 0xc1, 0x26, 0x07, 0x89, 0x74, 0x46,
+	0x08, 0xc6, 0x1c, 0x24, 0x88, 0x18, 0xe4, 0x50, 0x1f, 0xb7, 0x26, 0xbd, 0xef, 0x43, 0xab, 0xde,
+	0xd1, 0x5a, 0xc4, 0x1c, 0x2e, 0xd5, 0x7c, 0xfe, 0x7e, 0xf7, 0xf8, 0x55, 0xf3, 0x6a, 0x06, 0x9d,
+	0x91, 0x2e, 0x07, 0x64, 0x21, 0x43, 0x36, 0x6c, 0x29, 0xfb, 0x4b, 0xd3, 0x76, 0x01, 0x45, 0x1c,
+	0xb8, 0x27, 0xe6, 0x94, 0x70, 0x76, 0xcc, 0x5f, 0xa4, 0xe7, 0x01, 0x0b, 0xab, 0x3a, 0x53, 0xd2,
+	0xd9, 0xe4, 0xf5, 0x2e, 0x9f, 0x9a, 0x69, 0x17, 0xc5, 0xbe, 0x78, 0x15, 0x63, 0xfe, 0x25, 0xfd,
+	0xd2, 0x96, 0x59, 0x9a, 0x48, 0xa0, 0x0e, 0xe9, 0x08, 0x90, 0xf9, 0x1a, 0x2b, 0xfd, 0xf3, 0x4b,
+	0xba, 0x22, 0xbc, 0x8a, 0x34, 0xef, 0x75, 0xf2, 0x41, 0x0d, 0xe8, 0x37, 0x42, 0x25, 0x32, 0x81,
+	0x4b, 0xf5, 0x83, 0xc8, 0x78, 0xb6, 0xe4, 0x45, 0x92, 0x3e, 0x69, 0x79, 0x03, 0x35, 0x59, 0x54,
+	0x03, 0x57, 0xd2, 0x09, 0x19, 0x40, 0x12, 0x36, 0xd9, 0x77, 0x8a, 0xfd, 0x08, 0x49, 0x58, 0x27,
+	0x7f, 0x90, 0x2e, 0x67, 0x18, 0xac, 0x40, 0xc8, 0xd3, 0x26, 0x8d, 0x9a, 0xbd, 0xfe, 0x33, 0x1f,
+	0xd6, 0x6e, 0x89, 0x78, 0x67, 0xd6, 0xfc, 0x47, 0x7a, 0xb5, 0xc6, 0xf4, 0xe7, 0x6b, 0xce, 0xaa,
+	0x7e, 0x4a, 0xf3, 0xf1, 0xee, 0x60, 0xe8, 0xfb, 0x83, 0xa1, 0x3f, 0x1d, 0x0c, 0xfd, 0xee, 0x68,
+	0x68, 0xfb, 0xa3, 0xa1, 0x3d, 0x1c, 0x0d, 0xed, 0xba, 0x5d, 0xde, 0x29, 0xbf, 0xad, 0xee, 0x87,
+	0xf3, 0x3c, 0x00, 0xa5, 0x00, 0x31, 0x4f, 0xe1, 0x02, 0x00, 0x00,
+}
+
 func (m *WriteRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -150,29 +340,50 @@ func (m *WriteRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *WriteRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WriteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if len(m.Metadata) > 0 {
+		for iNdEx := len(m.Metadata) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metadata[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRemote(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Timeseries) > 0 {
-		for _, msg := range m.Timeseries {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+		for iNdEx := len(m.Timeseries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Timeseries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRemote(dAtA, i, uint64(size))
 			}
-			i += n
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *ReadRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -180,29 +391,36 @@ func (m *ReadRequest) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *ReadRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReadRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Queries) > 0 {
-		for _, msg := range m.Queries {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+		for iNdEx := len(m.Queries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Queries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRemote(dAtA, i, uint64(size))
 			}
-			i += n
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *ReadResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -210,29 +428,36 @@ func (m *ReadResponse) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *ReadResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReadResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Results) > 0 {
-		for _, msg := range m.Results {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+		for iNdEx := len(m.Results) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Results[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRemote(dAtA, i, uint64(size))
 			}
-			i += n
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *Query) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -240,39 +465,46 @@ func (m *Query) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *Query) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Query) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.StartTimestampMs != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintRemote(dAtA, i, uint64(m.StartTimestampMs))
+	if len(m.Matchers) > 0 {
+		for iNdEx := len(m.Matchers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Matchers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRemote(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
 	if m.EndTimestampMs != 0 {
-		dAtA[i] = 0x10
-		i++
 		i = encodeVarintRemote(dAtA, i, uint64(m.EndTimestampMs))
+		i--
+		dAtA[i] = 0x10
 	}
-	if len(m.Matchers) > 0 {
-		for _, msg := range m.Matchers {
-			dAtA[i] = 0x1a
-			i++
-			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+	if m.StartTimestampMs != 0 {
+		i = encodeVarintRemote(dAtA, i, uint64(m.StartTimestampMs))
+		i--
+		dAtA[i] = 0x8
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func (m *QueryResult) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
 	if err != nil {
 		return nil, err
 	}
@@ -280,35 +512,47 @@ func (m *QueryResult) Marshal() (dAtA []byte, err error) {
 }
 
 func (m *QueryResult) MarshalTo(dAtA []byte) (int, error) {
-	var i int
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResult) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
 	if len(m.Timeseries) > 0 {
-		for _, msg := range m.Timeseries {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRemote(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+		for iNdEx := len(m.Timeseries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Timeseries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRemote(dAtA, i, uint64(size))
 			}
-			i += n
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return i, nil
+	return len(dAtA) - i, nil
 }
 
 func encodeVarintRemote(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRemote(v)
+	base := offset
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
 		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
-	return offset + 1
+	return base
 }
 func (m *WriteRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Timeseries) > 0 {
@@ -317,10 +561,19 @@ func (m *WriteRequest) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if len(m.Metadata) > 0 {
+		for _, e := range m.Metadata {
+			l = e.Size()
+			n += 1 + l + sovRemote(uint64(l))
+		}
+	}
 	return n
 }
 
 func (m *ReadRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Queries) > 0 {
@@ -333,6 +586,9 @@ func (m *ReadRequest) Size() (n int) {
 }
 
 func (m *ReadResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Results) > 0 {
@@ -345,6 +601,9 @@ func (m *ReadResponse) Size() (n int) {
 }
 
 func (m *Query) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if m.StartTimestampMs != 0 {
@@ -363,6 +622,9 @@ func (m *Query) Size() (n int) {
 }
 
 func (m *QueryResult) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	if len(m.Timeseries) > 0 {
@@ -375,14 +637,7 @@ func (m *QueryResult) Size() (n int) {
 }
 
 func sovRemote(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
-	}
-	return n
+	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozRemote(x uint64) (n int) {
 	return sovRemote(uint64((x << 1) ^ uint64((int64(x) >> 63))))
@@ -402,7 +657,7 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -430,7 +685,7 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -439,6 +694,9 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRemote
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRemote
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -447,13 +705,47 @@ func (m *WriteRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemote
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRemote
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metadata = append(m.Metadata, MetricMetadata{})
+			if err := m.Metadata[len(m.Metadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRemote
 			}
 			if (iNdEx + skippy) > l {
@@ -483,7 +775,7 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -511,7 +803,7 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -520,6 +812,9 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRemote
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRemote
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -534,7 +829,7 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRemote
 			}
 			if (iNdEx + skippy) > l {
@@ -564,7 +859,7 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -592,7 +887,7 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -601,6 +896,9 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRemote
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRemote
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -615,7 +913,7 @@ func (m *ReadResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRemote
 			}
 			if (iNdEx + skippy) > l {
@@ -645,7 +943,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -673,7 +971,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartTimestampMs |= (int64(b) & 0x7F) << shift
+				m.StartTimestampMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -692,7 +990,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.EndTimestampMs |= (int64(b) & 0x7F) << shift
+				m.EndTimestampMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -711,7 +1009,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -720,6 +1018,9 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRemote
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRemote
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -734,7 +1035,7 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRemote
 			}
 			if (iNdEx + skippy) > l {
@@ -764,7 +1065,7 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -792,7 +1093,7 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -801,6 +1102,9 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthRemote
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRemote
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
@@ -815,7 +1119,7 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthRemote
 			}
 			if (iNdEx + skippy) > l {
@@ -833,6 +1137,7 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 func skipRemote(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -864,10 +1169,8 @@ func skipRemote(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -884,84 +1187,34 @@ func skipRemote(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthRemote
 			}
-			return iNdEx, nil
+			iNdEx += length
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowRemote
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipRemote(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupRemote
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthRemote
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthRemote = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowRemote   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthRemote        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowRemote          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupRemote = fmt.Errorf("proto: unexpected end of group")
 )
-
-func init() {
-	proto.RegisterFile("github.com/m3db/m3/src/query/generated/proto/prompb/remote.proto", fileDescriptorRemote)
-}
-
-var fileDescriptorRemote = []byte{
-	// 363 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xc1, 0x4a, 0xeb, 0x40,
-	0x14, 0x86, 0x9b, 0xdb, 0x7b, 0xdb, 0xcb, 0xb4, 0x48, 0x89, 0x9b, 0xd8, 0x45, 0x94, 0xac, 0xba,
-	0xb0, 0x19, 0x30, 0x20, 0x2e, 0x44, 0xa5, 0x2e, 0xdc, 0x58, 0xc1, 0xb1, 0x20, 0xb8, 0x29, 0x49,
-	0x73, 0x4c, 0x03, 0x9d, 0x4c, 0x3a, 0x73, 0xb2, 0xe8, 0x5b, 0xb8, 0xf3, 0x95, 0xba, 0xf4, 0x09,
-	0x44, 0xea, 0x8b, 0x48, 0x66, 0x88, 0xa6, 0xe0, 0x46, 0x37, 0x21, 0x39, 0xff, 0xf7, 0xff, 0xfc,
-	0x39, 0x33, 0xe4, 0x22, 0x49, 0x71, 0x5e, 0x44, 0xfe, 0x4c, 0x70, 0xca, 0x83, 0x38, 0xa2, 0x3c,
-	0xa0, 0x4a, 0xce, 0xe8, 0xb2, 0x00, 0xb9, 0xa2, 0x09, 0x64, 0x20, 0x43, 0x84, 0x98, 0xe6, 0x52,
-	0xa0, 0x28, 0x9f, 0x3c, 0x8f, 0xa8, 0x04, 0x2e, 0x10, 0x7c, 0x3d, 0xb3, 0xbb, 0x3c, 0x28, 0xc7,
-	0x80, 0x73, 0x28, 0x54, 0xff, 0xfc, 0x37, 0x79, 0xb8, 0xca, 0x41, 0x99, 0xb8, 0xfe, 0xb0, 0x16,
-	0x90, 0x88, 0x44, 0x18, 0x32, 0x2a, 0x1e, 0xf5, 0x97, 0xb1, 0x95, 0x6f, 0x06, 0xf7, 0x6e, 0x48,
-	0xf7, 0x5e, 0xa6, 0x08, 0x0c, 0x96, 0x05, 0x28, 0xb4, 0xcf, 0x08, 0xc1, 0x94, 0x83, 0x02, 0x99,
-	0x82, 0x72, 0xac, 0x83, 0xe6, 0xa0, 0x73, 0xe4, 0xf8, 0xf5, 0x8a, 0xfe, 0x24, 0xe5, 0x70, 0xa7,
-	0xf5, 0xd1, 0xdf, 0xf5, 0xeb, 0x7e, 0x83, 0xd5, 0x1c, 0xde, 0x29, 0xe9, 0x30, 0x08, 0xe3, 0x2a,
-	0x6e, 0x48, 0xda, 0x65, 0xf3, 0xaf, 0xac, 0xdd, 0xed, 0xac, 0xdb, 0xf2, 0xb7, 0x58, 0xc5, 0x78,
-	0x97, 0xa4, 0x6b, 0xdc, 0x2a, 0x17, 0x99, 0x02, 0x3b, 0x20, 0x6d, 0x09, 0xaa, 0x58, 0x60, 0x65,
-	0xdf, 0xfb, 0xce, 0xae, 0x09, 0x56, 0x91, 0xde, 0xb3, 0x45, 0xfe, 0x69, 0xc1, 0x3e, 0x24, 0xb6,
-	0xc2, 0x50, 0xe2, 0x54, 0x17, 0xc4, 0x90, 0xe7, 0x53, 0x5e, 0x26, 0x59, 0x83, 0x26, 0xeb, 0x69,
-	0x65, 0x52, 0x09, 0x63, 0x65, 0x0f, 0x48, 0x0f, 0xb2, 0x78, 0x9b, 0xfd, 0xa3, 0xd9, 0x1d, 0xc8,
-	0xe2, 0x3a, 0x79, 0x4c, 0xfe, 0xf3, 0x10, 0x67, 0x73, 0x90, 0xca, 0x69, 0xea, 0x5e, 0xfd, 0xed,
-	0x5e, 0xd7, 0x61, 0x04, 0x8b, 0xb1, 0x41, 0xd8, 0x27, 0xeb, 0x5d, 0x91, 0x4e, 0xad, 0xb1, 0x7d,
-	0xf2, 0x93, 0x5d, 0xd7, 0xb7, 0x3c, 0x72, 0xd6, 0x1b, 0xd7, 0x7a, 0xd9, 0xb8, 0xd6, 0xdb, 0xc6,
-	0xb5, 0x9e, 0xde, 0xdd, 0xc6, 0x43, 0xcb, 0x5c, 0x85, 0xa8, 0xa5, 0x8f, 0x35, 0xf8, 0x08, 0x00,
-	0x00, 0xff, 0xff, 0x24, 0xf8, 0x63, 0xea, 0x98, 0x02, 0x00, 0x00,
-}
@@ -0,0 +1,98 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package graphite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+const (
+	// NameTag is the reserved tag name used to refer to the metric path of a
+	// tagged series, e.g. `name=cpu.load` matches `cpu.load;host=a`.
+	NameTag = "name"
+
+	taggedPathSeparator = ';'
+	tagValueSeparator   = '='
+)
+
+var errEmptyTaggedPath = errors.New("tagged path has an empty metric name")
+
+// TaggedPathTag is a tag parsed from a Graphite tagged path.
+type TaggedPathTag struct {
+	Name  []byte
+	Value []byte
+}
+
+// IsTaggedPath returns true if the path is a Graphite tagged path, i.e. of
+// the form `cpu.load;host=a;dc=b`.
+func IsTaggedPath(path []byte) bool {
+	return bytes.IndexByte(path, taggedPathSeparator) >= 0
+}
+
+// ParseTaggedPath splits a Graphite tagged path into the metric path and its
+// tags, which are appended to the given slice. Untagged paths are returned
+// as is without any tags.
+func ParseTaggedPath(
+	path []byte,
+	tags []TaggedPathTag,
+) ([]byte, []TaggedPathTag, error) {
+	idx := bytes.IndexByte(path, taggedPathSeparator)
+	if idx < 0 {
+		return path, tags, nil
+	}
+
+	if idx == 0 {
+		return nil, nil, errEmptyTaggedPath
+	}
+
+	name, remaining := path[:idx], path[idx+1:]
+	for len(remaining) > 0 {
+		var tag []byte
+		if idx = bytes.IndexByte(remaining, taggedPathSeparator); idx < 0 {
+			tag, remaining = remaining, nil
+		} else {
+			tag, remaining = remaining[:idx], remaining[idx+1:]
+		}
+
+		sep := bytes.IndexByte(tag, tagValueSeparator)
+		if sep <= 0 || sep == len(tag)-1 {
+			return nil, nil, fmt.Errorf("invalid tag in tagged path %s: %s", path, tag)
+		}
+
+		tagName := tag[:sep]
+		if string(tagName) == NameTag {
+			return nil, nil, fmt.Errorf("reserved tag %s in tagged path %s",
+				NameTag, path)
+		}
+
+		if _, ok := TagIndex(tagName); ok {
+			return nil, nil, fmt.Errorf("reserved tag %s in tagged path %s",
+				tagName, path)
+		}
+
+		tags = append(tags, TaggedPathTag{Name: tagName, Value: tag[sep+1:]})
+	}
+
+	return name, tags, nil
+}
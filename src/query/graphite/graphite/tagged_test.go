@@ -0,0 +1,81 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaggedPath(t *testing.T) {
+	for _, tt := range []struct {
+		path         string
+		expectedName string
+		expectedTags []TaggedPathTag
+	}{
+		{
+			path:         "cpu.load",
+			expectedName: "cpu.load",
+		},
+		{
+			path:         "cpu.load;host=web01",
+			expectedName: "cpu.load",
+			expectedTags: []TaggedPathTag{
+				{Name: []byte("host"), Value: []byte("web01")},
+			},
+		},
+		{
+			path:         "cpu;host=web01;dc=us-east=1;",
+			expectedName: "cpu",
+			expectedTags: []TaggedPathTag{
+				{Name: []byte("host"), Value: []byte("web01")},
+				{Name: []byte("dc"), Value: []byte("us-east=1")},
+			},
+		},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, len(tt.expectedTags) > 0, IsTaggedPath([]byte(tt.path)))
+			name, tags, err := ParseTaggedPath([]byte(tt.path), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, string(name))
+			assert.Equal(t, tt.expectedTags, tags)
+		})
+	}
+}
+
+func TestParseTaggedPathErrors(t *testing.T) {
+	for _, path := range []string{
+		";host=web01",
+		"cpu;host",
+		"cpu;=web01",
+		"cpu;host=",
+		"cpu;name=foo",
+		"cpu;__g0__=foo",
+	} {
+		t.Run(path, func(t *testing.T) {
+			_, _, err := ParseTaggedPath([]byte(path), nil)
+			require.Error(t, err)
+		})
+	}
+}
@@ -277,6 +277,88 @@ func divideSeriesLists(ctx *common.Context, dividendSeriesList, divisorSeriesLis
 	return r, nil
 }
 
+// aggregateSeriesLists applies the aggregation function to each pair of series
+// at the same position in the two series lists, which must have the same
+// length.
+func aggregateSeriesLists(
+	ctx *common.Context,
+	seriesListFirstPos singlePathSpec,
+	seriesListSecondPos singlePathSpec,
+	fname string,
+) (ts.SeriesList, error) {
+	if len(seriesListFirstPos.Values) != len(seriesListSecondPos.Values) {
+		err := xerrors.NewInvalidParamsError(fmt.Errorf(
+			"aggregateSeriesLists both SeriesLists must have exactly the same length"))
+		return ts.NewSeriesList(), err
+	}
+
+	var (
+		metadata = seriesListFirstPos.Metadata.CombineMetadata(seriesListSecondPos.Metadata)
+		results  = make([]*ts.Series, 0, len(seriesListFirstPos.Values))
+	)
+	for idx, firstSeries := range seriesListFirstPos.Values {
+		secondSeries := seriesListSecondPos.Values[idx]
+		aggregated, err := aggregate(ctx, singlePathSpec{
+			Values:   []*ts.Series{firstSeries, secondSeries},
+			Metadata: metadata,
+		}, fname)
+		if err != nil {
+			return ts.NewSeriesList(), err
+		}
+
+		if len(aggregated.Values) == 0 {
+			continue
+		}
+
+		// NB: name the result after the aggregation, e.g. sumSeries(a,b).
+		result := aggregated.Values[0]
+		prefix := result.Name()
+		if sep := strings.Index(prefix, "Series("); sep >= 0 {
+			prefix = prefix[:sep]
+		} else {
+			prefix = fname
+		}
+
+		name := fmt.Sprintf("%sSeries(%s,%s)", prefix, firstSeries.Name(), secondSeries.Name())
+		results = append(results, result.RenamedTo(name))
+	}
+
+	r := ts.SeriesList(seriesListFirstPos)
+	r.Values = results
+	r.Metadata = metadata
+	return r, nil
+}
+
+// sumSeriesLists adds each pair of series at the same position in the two
+// series lists.
+func sumSeriesLists(
+	ctx *common.Context,
+	seriesListFirstPos singlePathSpec,
+	seriesListSecondPos singlePathSpec,
+) (ts.SeriesList, error) {
+	return aggregateSeriesLists(ctx, seriesListFirstPos, seriesListSecondPos, sumFnName)
+}
+
+// diffSeriesLists subtracts each series in the second series list from the
+// series at the same position in the first series list.
+func diffSeriesLists(
+	ctx *common.Context,
+	seriesListFirstPos singlePathSpec,
+	seriesListSecondPos singlePathSpec,
+) (ts.SeriesList, error) {
+	return aggregateSeriesLists(ctx, seriesListFirstPos, seriesListSecondPos, diffFnName)
+}
+
+// multiplySeriesLists multiplies each pair of series at the same position in
+// the two series lists.
+func multiplySeriesLists(
+	ctx *common.Context,
+	seriesListFirstPos singlePathSpec,
+	seriesListSecondPos singlePathSpec,
+) (ts.SeriesList, error) {
+	return aggregateSeriesLists(ctx, seriesListFirstPos, seriesListSecondPos, multiplyFnName)
+}
+
 // aggregate takes a list of series and returns a new series containing the
 // value aggregated across the series at each datapoint using the specified function.
 // This function can be used with aggregation functions average (or avg), avg_zero,
@@ -443,6 +443,82 @@ func TestDivideSeriesLists(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestAggregateSeriesLists(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	var (
+		stepSize = 10000
+		start    = ctx.StartTime
+		nan      = math.NaN()
+		newList  = func(series ...common.TestSeries) singlePathSpec {
+			values := make([]*ts.Series, 0, len(series))
+			for _, s := range series {
+				values = append(values, ts.NewSeries(ctx, s.Name, start,
+					common.NewTestSeriesValues(ctx, stepSize, s.Data)))
+			}
+			return singlePathSpec{Values: values}
+		}
+		first = newList(
+			common.TestSeries{Name: "a", Data: []float64{1, 2, nan}},
+			common.TestSeries{Name: "b", Data: []float64{4, 5, 6}},
+		)
+		second = newList(
+			common.TestSeries{Name: "c", Data: []float64{10, 20, 30}},
+			common.TestSeries{Name: "d", Data: []float64{40, nan, 60}},
+		)
+	)
+
+	// NB: each pair is aggregated as by the corresponding series function,
+	// e.g. sumSeries(a,c), which ignores null values.
+	tests := []struct {
+		fn       func(*common.Context, singlePathSpec, singlePathSpec) (ts.SeriesList, error)
+		expected []common.TestSeries
+	}{
+		{
+			fn: func(ctx *common.Context, a, b singlePathSpec) (ts.SeriesList, error) {
+				return aggregateSeriesLists(ctx, a, b, "max")
+			},
+			expected: []common.TestSeries{
+				{Name: "maxSeries(a,c)", Data: []float64{10, 20, 30}},
+				{Name: "maxSeries(b,d)", Data: []float64{40, 5, 60}},
+			},
+		},
+		{
+			fn: sumSeriesLists,
+			expected: []common.TestSeries{
+				{Name: "sumSeries(a,c)", Data: []float64{11, 22, 30}},
+				{Name: "sumSeries(b,d)", Data: []float64{44, 5, 66}},
+			},
+		},
+		{
+			fn: diffSeriesLists,
+			expected: []common.TestSeries{
+				{Name: "diffSeries(a,c)", Data: []float64{-9, -18, -30}},
+				{Name: "diffSeries(b,d)", Data: []float64{-36, 5, -54}},
+			},
+		},
+		{
+			fn: multiplySeriesLists,
+			expected: []common.TestSeries{
+				{Name: "multiplySeries(a,c)", Data: []float64{10, 40, 30}},
+				{Name: "multiplySeries(b,d)", Data: []float64{160, 5, 360}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		res, err := test.fn(ctx, first, second)
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, stepSize, start, test.expected, res.Values)
+	}
+
+	_, err := sumSeriesLists(ctx, first, singlePathSpec{Values: second.Values[:1]})
+	require.Error(t, err)
+	_, err = aggregateSeriesLists(ctx, first, second, "unknown")
+	require.Error(t, err)
+}
+
 //nolint:govet
 func TestAverageSeriesWithWildcards(t *testing.T) {
 	ctx, _ := newConsolidationTestSeries()
@@ -345,7 +345,8 @@ func grep(_ *common.Context, seriesList singlePathSpec, regex string) (ts.Series
 
 // timeShift draws the selected metrics shifted in time. If no sign is given, a minus sign ( - ) is
 // implied which will shift the metric back in time. If a plus sign ( + ) is given, the metric will
-// be shifted forward in time
+// be shifted forward in time. Multiple shifts may be given separated by commas, e.g. '1d,7d', in
+// which case the metrics are drawn once for each of the shifts.
 func timeShift(
 	_ *common.Context,
 	_ singlePathSpec,
@@ -354,29 +355,217 @@ func timeShift(
 	_ bool,
 ) (*unaryContextShifter, error) {
 	// TODO: implement resetEnd
-	if !(strings.HasPrefix(timeShiftS, "+") || strings.HasPrefix(timeShiftS, "-")) {
-		timeShiftS = "-" + timeShiftS
+	timeShifts := strings.Split(timeShiftS, ",")
+	shifts := make([]time.Duration, 0, len(timeShifts))
+	for i, timeShiftS := range timeShifts {
+		timeShiftS = strings.TrimSpace(timeShiftS)
+		if !(strings.HasPrefix(timeShiftS, "+") || strings.HasPrefix(timeShiftS, "-")) {
+			timeShiftS = "-" + timeShiftS
+		}
+
+		shift, err := common.ParseInterval(timeShiftS)
+		if err != nil {
+			return nil, xerrors.NewInvalidParamsError(
+				fmt.Errorf("invalid timeShift parameter %s: %w", timeShiftS, err))
+		}
+
+		timeShifts[i] = timeShiftS
+		shifts = append(shifts, shift)
+	}
+
+	if len(shifts) == 1 {
+		shift, timeShiftS := shifts[0], timeShifts[0]
+		transformerFn := func(input ts.SeriesList) (ts.SeriesList, error) {
+			input.Values = shiftSeries(input.Values, shift, func(name string) string {
+				return fmt.Sprintf("timeShift(%s, %s)", name, timeShiftS)
+			})
+			return input, nil
+		}
+
+		return &unaryContextShifter{
+			ContextShiftFunc: shiftedContextFn(shift),
+			UnaryTransformer: transformerFn,
+		}, nil
 	}
 
-	shift, err := common.ParseInterval(timeShiftS)
+	contextShiftingFns := make([]contextShiftFunc, 0, len(shifts))
+	for _, shift := range shifts {
+		contextShiftingFns = append(contextShiftingFns, shiftedContextFn(shift))
+	}
+
+	transformerFn := func(inputs []ts.SeriesList) (ts.SeriesList, error) {
+		return combineShiftedSeries(inputs, shifts, func(i int, name string) string {
+			return fmt.Sprintf("timeShift(%s, %s)", name, timeShifts[i])
+		}), nil
+	}
+
+	return &unaryContextShifter{
+		ContextShiftFuncs: contextShiftingFns,
+		MultiTransformer:  transformerFn,
+	}, nil
+}
+
+// timeStack draws the selected metrics shifted in time by each multiple of the
+// time shift unit from timeShiftStart up to (but excluding) timeShiftEnd,
+// stacking for example the same hour of each of the previous days.
+func timeStack(
+	_ *common.Context,
+	_ singlePathSpec,
+	timeShiftUnit string,
+	timeShiftStart int,
+	timeShiftEnd int,
+) (*unaryContextShifter, error) {
+	if !(strings.HasPrefix(timeShiftUnit, "+") || strings.HasPrefix(timeShiftUnit, "-")) {
+		timeShiftUnit = "-" + timeShiftUnit
+	}
+
+	delta, err := common.ParseInterval(timeShiftUnit)
 	if err != nil {
 		return nil, xerrors.NewInvalidParamsError(
-			fmt.Errorf("invalid timeShift parameter %s: %w", timeShiftS, err))
+			fmt.Errorf("invalid timeStack parameter %s: %w", timeShiftUnit, err))
 	}
 
-	contextShiftingFn := func(c *common.Context) *common.Context {
+	if timeShiftEnd <= timeShiftStart {
+		// NB: an empty range of shifts draws nothing.
+		return nil, nil
+	}
+
+	var (
+		numShifts          = timeShiftEnd - timeShiftStart
+		shifts             = make([]time.Duration, 0, numShifts)
+		contextShiftingFns = make([]contextShiftFunc, 0, numShifts)
+	)
+	for i := timeShiftStart; i < timeShiftEnd; i++ {
+		shift := delta * time.Duration(i)
+		shifts = append(shifts, shift)
+		contextShiftingFns = append(contextShiftingFns, shiftedContextFn(shift))
+	}
+
+	transformerFn := func(inputs []ts.SeriesList) (ts.SeriesList, error) {
+		return combineShiftedSeries(inputs, shifts, func(i int, name string) string {
+			return fmt.Sprintf("timeShift(%s, %s, %d)", name, timeShiftUnit, timeShiftStart+i)
+		}), nil
+	}
+
+	return &unaryContextShifter{
+		ContextShiftFuncs: contextShiftingFns,
+		MultiTransformer:  transformerFn,
+	}, nil
+}
+
+// shiftedContextFn returns a context shift func which shifts the time range
+// of the context by the given shift.
+func shiftedContextFn(shift time.Duration) contextShiftFunc {
+	return func(c *common.Context) *common.Context {
 		opts := common.NewChildContextOptions()
 		opts.AdjustTimeRange(shift, shift, 0, 0)
-		childCtx := c.NewChildContext(opts)
-		return childCtx
+		return c.NewChildContext(opts)
 	}
+}
 
+// shiftSeries shifts the series fetched for a shifted time range back onto
+// the original time range and renames them.
+func shiftSeries(
+	series []*ts.Series,
+	shift time.Duration,
+	namer func(name string) string,
+) []*ts.Series {
+	output := make([]*ts.Series, len(series))
+	for i, in := range series {
+		// NB(jayp): opposite direction
+		output[i] = in.Shift(-1 * shift).RenamedTo(namer(in.Name()))
+	}
+	return output
+}
+
+// combineShiftedSeries shifts and combines the series fetched for each of
+// the shifted time ranges, in the order of the shifts.
+func combineShiftedSeries(
+	inputs []ts.SeriesList,
+	shifts []time.Duration,
+	namer func(i int, name string) string,
+) ts.SeriesList {
+	output := ts.NewSeriesList()
+	for i, input := range inputs {
+		i := i
+		output.Values = append(output.Values, shiftSeries(input.Values, shifts[i],
+			func(name string) string {
+				return namer(i, name)
+			})...)
+		output.Metadata = output.Metadata.CombineMetadata(input.Metadata)
+	}
+	return output
+}
+
+// linearRegression draws the linear regression of each series, computed from
+// the data between startSourceAt and endSourceAt which default to the time
+// range of the query.
+func linearRegression(
+	ctx *common.Context,
+	_ singlePathSpec,
+	startSourceAt string,
+	endSourceAt string,
+) (*unaryContextShifter, error) {
+	var (
+		now                     = time.Now()
+		tzOffsetForAbsoluteTime time.Duration
+		start, end              = ctx.StartTime, ctx.EndTime
+		sourceStart, sourceEnd  = start, end
+		err                     error
+	)
+	if startSourceAt != "" {
+		sourceStart, err = graphite.ParseTime(startSourceAt, now, tzOffsetForAbsoluteTime)
+		if err != nil {
+			return nil, xerrors.NewInvalidParamsError(err)
+		}
+	}
+	if endSourceAt != "" {
+		sourceEnd, err = graphite.ParseTime(endSourceAt, now, tzOffsetForAbsoluteTime)
+		if err != nil {
+			return nil, xerrors.NewInvalidParamsError(err)
+		}
+	}
+	if !sourceStart.Before(sourceEnd) {
+		return nil, xerrors.NewInvalidParamsError(fmt.Errorf(
+			"linearRegression source start %s must be before source end %s",
+			sourceStart, sourceEnd))
+	}
+
+	contextShiftingFn := func(c *common.Context) *common.Context {
+		opts := common.NewChildContextOptions()
+		opts.AdjustTimeRange(sourceStart.Sub(c.StartTime), sourceEnd.Sub(c.EndTime), 0, 0)
+		return c.NewChildContext(opts)
+	}
+
+	sourceIsQueryRange := startSourceAt == "" && endSourceAt == ""
 	transformerFn := func(input ts.SeriesList) (ts.SeriesList, error) {
-		output := make([]*ts.Series, input.Len())
-		for i, in := range input.Values {
-			// NB(jayp): opposite direction
-			output[i] = in.Shift(-1 * shift).RenamedTo(fmt.Sprintf("timeShift(%s, %s)", in.Name(), timeShiftS))
+		output := make([]*ts.Series, 0, input.Len())
+		for _, source := range input.Values {
+			factor, offset, ok := linearRegressionAnalysis(source)
+			if !ok {
+				continue
+			}
+
+			// NB: unless the regression is computed from the series itself,
+			// its values are drawn over the steps of the query time range.
+			seriesStart, numSteps := source.StartTime(), source.Len()
+			if !sourceIsQueryRange {
+				seriesStart = start.Truncate(source.Resolution())
+				numSteps = int(math.Ceil(float64(end.Sub(seriesStart)) /
+					float64(source.Resolution())))
+			}
+
+			vals := ts.NewValues(ctx, source.MillisPerStep(), numSteps)
+			for i := 0; i < numSteps; i++ {
+				t := seriesStart.Add(time.Duration(i) * source.Resolution())
+				vals.SetValueAt(i, offset+factor*float64(t.UnixNano())/float64(time.Second))
+			}
+
+			name := fmt.Sprintf("linearRegression(%s, %d, %d)",
+				source.Name(), sourceStart.Unix(), sourceEnd.Unix())
+			output = append(output, ts.NewSeries(ctx, name, seriesStart, vals))
 		}
+
 		input.Values = output
 		return input, nil
 	}
@@ -387,6 +576,37 @@ func timeShift(
 	}, nil
 }
 
+// linearRegressionAnalysis returns the factor and offset of the least squares
+// fit of the series against its timestamps in seconds, or false if no fit
+// exists.
+func linearRegressionAnalysis(series *ts.Series) (float64, float64, bool) {
+	var n, sumI, sumV, sumII, sumIV float64
+	for i := 0; i < series.Len(); i++ {
+		v := series.ValueAt(i)
+		if math.IsNaN(v) {
+			continue
+		}
+
+		idx := float64(i)
+		n++
+		sumI += idx
+		sumV += v
+		sumII += idx * idx
+		sumIV += idx * v
+	}
+
+	denominator := n*sumII - sumI*sumI
+	if denominator == 0 {
+		return 0, 0, false
+	}
+
+	step := series.Resolution().Seconds()
+	factor := (n*sumIV - sumI*sumV) / denominator / step
+	offset := (sumII*sumV-sumIV*sumI)/denominator -
+		factor*float64(series.StartTime().UnixNano())/float64(time.Second)
+	return factor, offset, true
+}
+
 // delay shifts all samples later by an integer number of steps. This can be used
 // for custom derivative calculations, among other things. Note: this will pad
 // the early end of the data with NaN for every step shifted. delay complements
@@ -2151,6 +2371,68 @@ func rangeOfSeries(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesLis
 	}, nil
 }
 
+// removeBetweenPercentile removes the series which do not have a value lying
+// outside of the n-th and (100 - n)-th percentiles of all the values at some
+// point in time.
+func removeBetweenPercentile(
+	_ *common.Context,
+	seriesList singlePathSpec,
+	percentile float64,
+) (ts.SeriesList, error) {
+	if percentile < 0.0 || percentile > 100.0 {
+		return ts.NewSeriesList(), common.ErrInvalidPercentile(percentile)
+	}
+
+	if percentile < 50 {
+		percentile = 100 - percentile
+	}
+
+	maxSteps := 0
+	for _, series := range seriesList.Values {
+		if series.Len() > maxSteps {
+			maxSteps = series.Len()
+		}
+	}
+
+	var (
+		lowPercentiles  = make([]float64, maxSteps)
+		highPercentiles = make([]float64, maxSteps)
+		column          = make([]float64, 0, len(seriesList.Values))
+	)
+	for i := 0; i < maxSteps; i++ {
+		column = column[:0]
+		for _, series := range seriesList.Values {
+			if i < series.Len() {
+				column = append(column, series.ValueAt(i))
+			}
+		}
+
+		// NB: GetPercentile sorts the values so they must be copied.
+		lowPercentiles[i] = common.GetPercentile(
+			append([]float64(nil), column...), 100-percentile, false)
+		highPercentiles[i] = common.GetPercentile(column, percentile, false)
+	}
+
+	results := make([]*ts.Series, 0, len(seriesList.Values))
+	for _, series := range seriesList.Values {
+		for i := 0; i < series.Len(); i++ {
+			v := series.ValueAt(i)
+			if math.IsNaN(v) {
+				continue
+			}
+
+			if !(lowPercentiles[i] < v && v < highPercentiles[i]) {
+				results = append(results, series)
+				break
+			}
+		}
+	}
+
+	r := ts.SeriesList(seriesList)
+	r.Values = results
+	return r, nil
+}
+
 // removeAbovePercentile removes data above the specified percentile from the series
 // or list of series provided. Values above this percentile are assigned a value
 // of None.
@@ -2680,6 +2962,60 @@ func cumulative(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList,
 	return consolidateBy(ctx, seriesList, "sum")
 }
 
+// minMax normalizes each series to values between 0 and 1, where 0 is its
+// minimum and 1 is its maximum.
+func minMax(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList, error) {
+	results := make([]*ts.Series, len(seriesList.Values))
+	for idx, series := range seriesList.Values {
+		var (
+			minimum  = series.SafeMin()
+			maximum  = series.SafeMax()
+			numSteps = series.Len()
+			vals     = ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+		)
+		for i := 0; i < numSteps; i++ {
+			v := series.ValueAt(i)
+			if math.IsNaN(v) {
+				continue
+			}
+
+			if maximum == minimum {
+				vals.SetValueAt(i, 0)
+			} else {
+				vals.SetValueAt(i, (v-minimum)/(maximum-minimum))
+			}
+		}
+		name := fmt.Sprintf("minMax(%s)", series.Name())
+		results[idx] = ts.NewSeries(ctx, name, series.StartTime(), vals)
+	}
+
+	r := ts.SeriesList(seriesList)
+	r.Values = results
+	return r, nil
+}
+
+// invert takes a seriesList and inverts each value, i.e. 1/x. Zero values
+// become null.
+func invert(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList, error) {
+	results := make([]*ts.Series, len(seriesList.Values))
+	for idx, series := range seriesList.Values {
+		numSteps := series.Len()
+		vals := ts.NewValues(ctx, series.MillisPerStep(), numSteps)
+		for i := 0; i < numSteps; i++ {
+			v := series.ValueAt(i)
+			if !math.IsNaN(v) && v != 0 {
+				vals.SetValueAt(i, 1/v)
+			}
+		}
+		name := fmt.Sprintf("invert(%s)", series.Name())
+		results[idx] = ts.NewSeries(ctx, name, series.StartTime(), vals)
+	}
+
+	r := ts.SeriesList(seriesList)
+	r.Values = results
+	return r, nil
+}
+
 // offsetToZero offsets a metric or wildcard seriesList by subtracting the minimum
 // value in the series from each data point.
 func offsetToZero(ctx *common.Context, seriesList singlePathSpec) (ts.SeriesList, error) {
@@ -2746,6 +3082,64 @@ func dashed(_ *common.Context, seriesList singlePathSpec, dashLength float64) (t
 	return r, nil
 }
 
+// verticalLine draws a vertical line at the given timestamp, which must be
+// within the time range of the query.
+func verticalLine(
+	ctx *common.Context,
+	timestamp string,
+	label string,
+	_ string,
+) (ts.SeriesList, error) {
+	var (
+		now                     = time.Now()
+		tzOffsetForAbsoluteTime time.Duration
+	)
+	t, err := graphite.ParseTime(timestamp, now, tzOffsetForAbsoluteTime)
+	if err != nil {
+		return ts.NewSeriesList(), xerrors.NewInvalidParamsError(err)
+	}
+
+	t = t.Truncate(time.Second)
+	if t.Before(ctx.StartTime.Truncate(time.Second)) {
+		return ts.NewSeriesList(), xerrors.NewInvalidParamsError(fmt.Errorf(
+			"verticalLine timestamp %s is before the start of the range", timestamp))
+	}
+	if t.After(ctx.EndTime) {
+		return ts.NewSeriesList(), xerrors.NewInvalidParamsError(fmt.Errorf(
+			"verticalLine timestamp %s is after the end of the range", timestamp))
+	}
+
+	name := label
+	if name == "" {
+		name = fmt.Sprintf("verticalLine(%s)", timestamp)
+	}
+
+	// NB: graphite draws the line as two points one second apart.
+	vals := ts.NewConstantValues(ctx, 1, 2, int(time.Second/time.Millisecond))
+	return ts.NewSeriesListWithSeries(ts.NewSeries(ctx, name, t, vals)), nil
+}
+
+// unique takes one or more series lists and removes the series with duplicate
+// names, keeping the first of each.
+func unique(_ *common.Context, seriesLists multiplePathSpecs) (ts.SeriesList, error) {
+	var (
+		seen    = make(map[string]struct{}, len(seriesLists.Values))
+		results = make([]*ts.Series, 0, len(seriesLists.Values))
+	)
+	for _, series := range seriesLists.Values {
+		if _, ok := seen[series.Name()]; ok {
+			continue
+		}
+
+		seen[series.Name()] = struct{}{}
+		results = append(results, series)
+	}
+
+	r := ts.SeriesList(seriesLists)
+	r.Values = results
+	return r, nil
+}
+
 // threshold draws a horizontal line at value f across the graph.
 func threshold(ctx *common.Context, value float64, label string, color string) (ts.SeriesList, error) {
 	seriesList, err := constantLine(ctx, value)
@@ -2773,9 +3167,11 @@ func init() {
 	MustRegisterFunction(aggregateWithWildcards).WithDefaultParams(map[uint8]interface{}{
 		3: -1, // positions
 	})
+	MustRegisterFunction(aggregateSeriesLists)
 	MustRegisterFunction(alias)
 	MustRegisterFunction(aliasByMetric)
 	MustRegisterFunction(aliasByNode)
+	MustRegisterFunction(aliasByTags)
 	MustRegisterFunction(aliasSub)
 	MustRegisterFunction(applyByNode).WithDefaultParams(map[uint8]interface{}{
 		4: "", // newName
@@ -2802,6 +3198,7 @@ func init() {
 	MustRegisterFunction(delay)
 	MustRegisterFunction(derivative)
 	MustRegisterFunction(diffSeries)
+	MustRegisterFunction(diffSeriesLists)
 	MustRegisterFunction(divideSeries)
 	MustRegisterFunction(divideSeriesLists)
 	MustRegisterFunction(exclude)
@@ -2815,6 +3212,7 @@ func init() {
 		3: "average", // fname
 	})
 	MustRegisterFunction(groupByNodes)
+	MustRegisterFunction(groupByTags)
 	MustRegisterFunction(highest).WithDefaultParams(map[uint8]interface{}{
 		2: 1,         // n,
 		3: "average", // f
@@ -2832,12 +3230,17 @@ func init() {
 	MustRegisterFunction(interpolate).WithDefaultParams(map[uint8]interface{}{
 		2: -1, // limit
 	})
+	MustRegisterFunction(invert)
 	MustRegisterFunction(isNonNull)
 	MustRegisterFunction(keepLastValue).WithDefaultParams(map[uint8]interface{}{
 		2: -1, // limit
 	})
 	MustRegisterFunction(legendValue)
 	MustRegisterFunction(limit)
+	MustRegisterFunction(linearRegression).WithDefaultParams(map[uint8]interface{}{
+		2: "", // startSourceAt
+		3: "", // endSourceAt
+	})
 	MustRegisterFunction(logarithm).WithDefaultParams(map[uint8]interface{}{
 		2: 10.0, // base
 	})
@@ -2849,6 +3252,7 @@ func init() {
 	MustRegisterFunction(lowestCurrent)
 	MustRegisterFunction(maxSeries)
 	MustRegisterFunction(maximumAbove)
+	MustRegisterFunction(minMax)
 	MustRegisterFunction(minSeries)
 	MustRegisterFunction(minimumAbove)
 	MustRegisterFunction(mostDeviant)
@@ -2884,6 +3288,7 @@ func init() {
 		}).
 		WithoutUnaryContextShifterSkipFetchOptimization()
 	MustRegisterFunction(multiplySeries)
+	MustRegisterFunction(multiplySeriesLists)
 	MustRegisterFunction(nonNegativeDerivative).WithDefaultParams(map[uint8]interface{}{
 		2: math.NaN(), // maxValue
 	})
@@ -2906,11 +3311,13 @@ func init() {
 	MustRegisterFunction(removeAboveValue)
 	MustRegisterFunction(removeBelowPercentile)
 	MustRegisterFunction(removeBelowValue)
+	MustRegisterFunction(removeBetweenPercentile)
 	MustRegisterFunction(removeEmptySeries).WithDefaultParams(map[uint8]interface{}{
 		2: 0.0, // xFilesFactor
 	})
 	MustRegisterFunction(scale)
 	MustRegisterFunction(scaleToSeconds)
+	MustRegisterFunction(seriesByTag)
 	MustRegisterFunction(sortBy).WithDefaultParams(map[uint8]interface{}{
 		2: "average", // fn
 		3: false,     // reverse
@@ -2942,6 +3349,7 @@ func init() {
 	MustRegisterFunction(sumSeriesWithWildcards).WithDefaultParams(map[uint8]interface{}{
 		2: -1, // positions
 	})
+	MustRegisterFunction(sumSeriesLists)
 	MustRegisterFunction(sustainedAbove)
 	MustRegisterFunction(sustainedBelow)
 	MustRegisterFunction(threshold).WithDefaultParams(map[uint8]interface{}{
@@ -2958,15 +3366,24 @@ func init() {
 	MustRegisterFunction(timeSlice).WithDefaultParams(map[uint8]interface{}{
 		3: "now", // endTime
 	})
+	MustRegisterFunction(timeStack).WithDefaultParams(map[uint8]interface{}{
+		2: "1d", // timeShiftUnit
+		3: 0,    // timeShiftStart
+		4: 7,    // timeShiftEnd
+	})
 	MustRegisterFunction(transformNull).WithDefaultParams(map[uint8]interface{}{
 		2: 0.0, // defaultValue
 	})
+	MustRegisterFunction(unique)
 	MustRegisterFunction(useSeriesAbove)
+	MustRegisterFunction(verticalLine).WithDefaultParams(map[uint8]interface{}{
+		2: "", // label
+		3: "", // color
+	})
 	MustRegisterFunction(weightedAverage)
 
 	// alias functions - in alpha ordering
 	MustRegisterAliasedFunction("abs", absolute)
-	MustRegisterAliasedFunction("aliasByTags", aliasByNode)
 	MustRegisterAliasedFunction("avg", averageSeries)
 	MustRegisterAliasedFunction("log", logarithm)
 	MustRegisterAliasedFunction("max", maxSeries)
@@ -4574,3 +4574,459 @@ func TestFunctionsRegistered(t *testing.T) {
 		assert.NotNil(t, findFunction(fname), "could not find function: %s", fname)
 	}
 }
+
+// shiftedTestSeriesFn returns series whose values are the number of minutes
+// the fetched time range is shifted back from the given start.
+func shiftedTestSeriesFn(
+	stepSize int,
+	start time.Time,
+	name string,
+) func(xctx.Context, string, storage.FetchOptions) (*storage.FetchResult, error) {
+	return func(_ xctx.Context, _ string, opts storage.FetchOptions) (*storage.FetchResult, error) {
+		val := float64(start.Sub(opts.StartTime) / time.Minute)
+		return &storage.FetchResult{
+			SeriesList: []*ts.Series{testSeries(name, stepSize, val, opts)},
+		}, nil
+	}
+}
+
+func TestTimeShiftMultipleShifts(t *testing.T) {
+	ctrl := xgomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	now := time.Now().Truncate(time.Hour)
+	engine := NewEngine(store, CompileOptions{})
+	startTime := now.Add(-3 * time.Minute)
+	endTime := now.Add(-time.Minute)
+	ctx := common.NewContext(common.ContextOptions{
+		Start:  startTime,
+		End:    endTime,
+		Engine: engine,
+	})
+	defer func() { _ = ctx.Close() }()
+
+	stepSize := 60000
+	store.EXPECT().FetchByQuery(gomock.Any(), "foo.bar.q.zed", gomock.Any()).DoAndReturn(
+		shiftedTestSeriesFn(stepSize, startTime, "foo.bar.q.zed")).Times(2)
+
+	expr, err := engine.Compile("timeShift(foo.bar.q.zed, '1min, +2min')")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, startTime, []common.TestSeries{
+		{Name: "timeShift(foo.bar.q.zed, -1min)", Data: []float64{1, 1}},
+		{Name: "timeShift(foo.bar.q.zed, +2min)", Data: []float64{-2, -2}},
+	}, res.Values)
+}
+
+func TestTimeStack(t *testing.T) {
+	ctrl := xgomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	now := time.Now().Truncate(time.Hour)
+	engine := NewEngine(store, CompileOptions{})
+	startTime := now.Add(-3 * time.Minute)
+	endTime := now.Add(-time.Minute)
+	ctx := common.NewContext(common.ContextOptions{
+		Start:  startTime,
+		End:    endTime,
+		Engine: engine,
+	})
+	defer func() { _ = ctx.Close() }()
+
+	stepSize := 60000
+	store.EXPECT().FetchByQuery(gomock.Any(), "foo.bar.q.zed", gomock.Any()).DoAndReturn(
+		shiftedTestSeriesFn(stepSize, startTime, "foo.bar.q.zed")).Times(3)
+
+	expr, err := engine.Compile("timeStack(foo.bar.q.zed, '1min', 1, 4)")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, startTime, []common.TestSeries{
+		{Name: "timeShift(foo.bar.q.zed, -1min, 1)", Data: []float64{1, 1}},
+		{Name: "timeShift(foo.bar.q.zed, -1min, 2)", Data: []float64{2, 2}},
+		{Name: "timeShift(foo.bar.q.zed, -1min, 3)", Data: []float64{3, 3}},
+	}, res.Values)
+
+	expr, err = engine.Compile("timeStack(foo.bar.q.zed, '1min', 3, 3)")
+	require.NoError(t, err)
+	res, err = expr.Execute(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Len())
+
+	_, err = timeStack(ctx, singlePathSpec{}, "foo", 0, 7)
+	require.Error(t, err)
+}
+
+// TestTimeStackGraphiteWebFixture checks the output of graphite-web's timeStack
+// for its default arguments, where each shifted series is named after its
+// shift and drawn from the start of the query time range.
+func TestTimeStackGraphiteWebFixture(t *testing.T) {
+	ctrl := xgomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	now := time.Now().Truncate(time.Hour)
+	engine := NewEngine(store, CompileOptions{})
+	startTime := now.Add(-3 * time.Minute)
+	endTime := now
+	ctx := common.NewContext(common.ContextOptions{
+		Start:  startTime,
+		End:    endTime,
+		Engine: engine,
+	})
+	defer func() { _ = ctx.Close() }()
+
+	stepSize := 60000
+	day := 24 * time.Hour
+	store.EXPECT().FetchByQuery(gomock.Any(), "collectd.test-db0.load.value", gomock.Any()).
+		DoAndReturn(func(_ xctx.Context, _ string, opts storage.FetchOptions) (*storage.FetchResult, error) {
+			val := float64(startTime.Sub(opts.StartTime) / day)
+			return &storage.FetchResult{SeriesList: []*ts.Series{
+				testSeries("collectd.test-db0.load.value", stepSize, val, opts),
+			}}, nil
+		}).Times(7)
+
+	expr, err := engine.Compile("timeStack(collectd.test-db0.load.value, '1d', 0, 7)")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+
+	expected := make([]common.TestSeries, 0, 7)
+	for i := 0; i < 7; i++ {
+		expected = append(expected, common.TestSeries{
+			Name: fmt.Sprintf("timeShift(collectd.test-db0.load.value, -1d, %d)", i),
+			Data: []float64{float64(i), float64(i), float64(i)},
+		})
+	}
+
+	common.CompareOutputsAndExpected(t, stepSize, startTime, expected, res.Values)
+}
+
+func TestLinearRegression(t *testing.T) {
+	ctrl := xgomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	now := time.Now().Truncate(time.Hour)
+	engine := NewEngine(store, CompileOptions{})
+	startTime := now.Add(-4 * time.Minute)
+	endTime := now
+	sourceStart := startTime.Add(-10 * time.Minute)
+	sourceEnd := startTime.Add(-5 * time.Minute)
+	ctx := common.NewContext(common.ContextOptions{
+		Start:  startTime,
+		End:    endTime,
+		Engine: engine,
+	})
+	defer func() { _ = ctx.Close() }()
+
+	nan := math.NaN()
+	stepSize := 60000
+	store.EXPECT().FetchByQuery(gomock.Any(), "foo.bar", gomock.Any()).DoAndReturn(
+		func(_ xctx.Context, _ string, opts storage.FetchOptions) (*storage.FetchResult, error) {
+			values := []float64{1, nan, 5, 7}
+			if opts.StartTime.Equal(sourceStart) {
+				assert.Equal(t, sourceEnd, opts.EndTime)
+				values = []float64{1, 3, nan, 7, 9}
+			}
+
+			return &storage.FetchResult{SeriesList: []*ts.Series{
+				ts.NewSeries(ctx, "foo.bar", opts.StartTime,
+					common.NewTestSeriesValues(ctx, stepSize, values)),
+			}}, nil
+		}).Times(2)
+
+	// NB: expected values are those of graphite-web, which fits the
+	// non-null values [1, 5, 7] at steps [0, 2, 3] to 1 + 2x.
+	expr, err := engine.Compile("linearRegression(foo.bar)")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, startTime, []common.TestSeries{
+		{
+			Name: fmt.Sprintf("linearRegression(foo.bar, %d, %d)",
+				startTime.Unix(), endTime.Unix()),
+			Data: []float64{1, 3, 5, 7},
+		},
+	}, res.Values)
+
+	// NB: the fit from the source range is extrapolated over the query range,
+	// which starts 10 steps after the source range.
+	expr, err = engine.Compile(fmt.Sprintf("linearRegression(foo.bar, '%d', '%d')",
+		sourceStart.Unix(), sourceEnd.Unix()))
+	require.NoError(t, err)
+	res, err = expr.Execute(ctx)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, startTime, []common.TestSeries{
+		{
+			Name: fmt.Sprintf("linearRegression(foo.bar, %d, %d)",
+				sourceStart.Unix(), sourceEnd.Unix()),
+			Data: []float64{21, 23, 25, 27},
+		},
+	}, res.Values)
+
+	_, err = linearRegression(ctx, singlePathSpec{}, "now", "-1h")
+	require.Error(t, err)
+}
+
+// TestLinearRegressionGraphiteWebFixture uses the fixture of graphite-web's
+// test_linearRegression, which fits minutes 3 to 8 past the epoch and draws
+// the fit over minutes 20 to 26.
+func TestLinearRegressionGraphiteWebFixture(t *testing.T) {
+	ctrl := xgomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := storage.NewMockStorage(ctrl)
+	engine := NewEngine(store, CompileOptions{})
+	startTime := time.Unix(1200, 0)
+	endTime := time.Unix(1620, 0)
+	ctx := common.NewContext(common.ContextOptions{
+		Start:  startTime,
+		End:    endTime,
+		Engine: engine,
+	})
+	defer func() { _ = ctx.Close() }()
+
+	nan := math.NaN()
+	stepSize := 60000
+	store.EXPECT().FetchByQuery(gomock.Any(), "test.value", gomock.Any()).DoAndReturn(
+		func(_ xctx.Context, _ string, opts storage.FetchOptions) (*storage.FetchResult, error) {
+			assert.Equal(t, time.Unix(180, 0), opts.StartTime)
+			assert.Equal(t, time.Unix(480, 0), opts.EndTime)
+			return &storage.FetchResult{SeriesList: []*ts.Series{
+				ts.NewSeries(ctx, "test.value", opts.StartTime,
+					common.NewTestSeriesValues(ctx, stepSize, []float64{3, nan, 5, 6, nan, 8})),
+			}}, nil
+		})
+
+	expr, err := engine.Compile("linearRegression(test.value, '180', '480')")
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, startTime, []common.TestSeries{
+		{
+			Name: "linearRegression(test.value, 180, 480)",
+			Data: []float64{20, 21, 22, 23, 24, 25, 26},
+		},
+	}, res.Values)
+}
+
+func TestLinearRegressionAnalysisNoFit(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	nan := math.NaN()
+	for _, values := range [][]float64{{nan, nan}, {nan, 3, nan}} {
+		series := ts.NewSeries(ctx, "foo", ctx.StartTime,
+			common.NewTestSeriesValues(ctx, 10000, values))
+		_, _, ok := linearRegressionAnalysis(series)
+		assert.False(t, ok)
+	}
+}
+
+func TestRemoveBetweenPercentile(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	nan := math.NaN()
+	var series []*ts.Series
+	for i, values := range [][]float64{
+		{1, nan}, {2, nan}, {3, nan}, {4, nan}, {5, nan}, {nan, nan},
+	} {
+		series = append(series, ts.NewSeries(ctx, fmt.Sprintf("s%d", i),
+			ctx.StartTime, common.NewTestSeriesValues(ctx, 10000, values)))
+	}
+
+	// NB: matches graphite-web, where the 25th and 75th percentiles of the
+	// first step are 2 and 5, values equal to either are kept and null values
+	// are ignored.
+	for _, percentile := range []float64{25, 75} {
+		res, err := removeBetweenPercentile(ctx, singlePathSpec{Values: series}, percentile)
+		require.NoError(t, err)
+
+		names := make([]string, 0, res.Len())
+		for _, s := range res.Values {
+			names = append(names, s.Name())
+		}
+		assert.Equal(t, []string{"s0", "s1", "s4"}, names)
+	}
+
+	_, err := removeBetweenPercentile(ctx, singlePathSpec{Values: series}, 101)
+	require.Error(t, err)
+}
+
+// TestRemoveBetweenPercentileGraphiteWebFixture uses expected values computed
+// with graphite-web's removeBetweenPercentile, where series are kept if any of
+// their values is at or outside of the percentiles of its step.
+func TestRemoveBetweenPercentileGraphiteWebFixture(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	var series []*ts.Series
+	for i, values := range [][]float64{
+		{1, 9, 1, 9, 1},
+		{5, 5, 5, 5, 5},
+		{4, 6, 3, 4, 6},
+		{9, 1, 9, 1, 9},
+		{3, 4, 4, 5, 2},
+		{6, 3, 6, 3, 4},
+	} {
+		series = append(series, ts.NewSeries(ctx,
+			fmt.Sprintf("collectd.test-db%d.load.value", i+1), ctx.StartTime,
+			common.NewTestSeriesValues(ctx, 10000, values)))
+	}
+
+	tests := []struct {
+		percentile float64
+		expected   []string
+	}{
+		{
+			percentile: 30,
+			expected: []string{
+				"collectd.test-db1.load.value",
+				"collectd.test-db2.load.value",
+				"collectd.test-db3.load.value",
+				"collectd.test-db4.load.value",
+				"collectd.test-db5.load.value",
+				"collectd.test-db6.load.value",
+			},
+		},
+		{
+			percentile: 10,
+			expected: []string{
+				"collectd.test-db1.load.value",
+				"collectd.test-db4.load.value",
+			},
+		},
+		{
+			percentile: 90,
+			expected: []string{
+				"collectd.test-db1.load.value",
+				"collectd.test-db4.load.value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		res, err := removeBetweenPercentile(ctx, singlePathSpec{Values: series}, tt.percentile)
+		require.NoError(t, err)
+
+		names := make([]string, 0, res.Len())
+		for _, s := range res.Values {
+			names = append(names, s.Name())
+		}
+		assert.Equal(t, tt.expected, names, "percentile %v", tt.percentile)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	nan := math.NaN()
+	stepSize := 10000
+	inputs := []struct {
+		values   []float64
+		expected []float64
+	}{
+		{[]float64{1, nan, 3, 5}, []float64{0, nan, 0.5, 1}},
+		{[]float64{2, 2, nan}, []float64{0, 0, nan}},
+		{[]float64{nan, nan}, []float64{nan, nan}},
+	}
+
+	for _, input := range inputs {
+		series := ts.NewSeries(ctx, "foo", ctx.StartTime,
+			common.NewTestSeriesValues(ctx, stepSize, input.values))
+		results, err := minMax(ctx, singlePathSpec{Values: []*ts.Series{series}})
+		require.NoError(t, err)
+		common.CompareOutputsAndExpected(t, stepSize, ctx.StartTime,
+			[]common.TestSeries{{Name: "minMax(foo)", Data: input.expected}},
+			results.Values)
+	}
+}
+
+// TestMinMaxGraphiteWebFixture uses expected values computed with graphite-web's
+// minMax, which normalizes the values 1 to 10.
+func TestMinMaxGraphiteWebFixture(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	stepSize := 10000
+	series := ts.NewSeries(ctx, "collectd.test-db1.load.value", ctx.StartTime,
+		common.NewTestSeriesValues(ctx, stepSize,
+			[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}))
+	results, err := minMax(ctx, singlePathSpec{Values: []*ts.Series{series}})
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, ctx.StartTime,
+		[]common.TestSeries{{
+			Name: "minMax(collectd.test-db1.load.value)",
+			Data: []float64{
+				0.0, 0.1111111111111111, 0.2222222222222222, 0.3333333333333333,
+				0.4444444444444444, 0.5555555555555556, 0.6666666666666666,
+				0.7777777777777778, 0.8888888888888888, 1.0,
+			},
+		}},
+		results.Values)
+}
+
+func TestInvert(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	nan := math.NaN()
+	stepSize := 10000
+	series := ts.NewSeries(ctx, "foo", ctx.StartTime,
+		common.NewTestSeriesValues(ctx, stepSize, []float64{2, 0, nan, -4}))
+	results, err := invert(ctx, singlePathSpec{Values: []*ts.Series{series}})
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, stepSize, ctx.StartTime,
+		[]common.TestSeries{{Name: "invert(foo)", Data: []float64{0.5, nan, nan, -0.25}}},
+		results.Values)
+}
+
+func TestVerticalLine(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	ctx := common.NewContext(common.ContextOptions{
+		Start: now.Add(-time.Hour),
+		End:   now,
+	})
+	defer func() { _ = ctx.Close() }()
+
+	at := now.Add(-30 * time.Minute)
+	timestamp := fmt.Sprint(at.Unix())
+	results, err := verticalLine(ctx, timestamp, "", "")
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 1000, at, []common.TestSeries{
+		{Name: fmt.Sprintf("verticalLine(%s)", timestamp), Data: []float64{1, 1}},
+	}, results.Values)
+
+	results, err = verticalLine(ctx, timestamp, "deploy", "red")
+	require.NoError(t, err)
+	require.Equal(t, 1, results.Len())
+	assert.Equal(t, "deploy", results.Values[0].Name())
+
+	for _, outside := range []time.Time{now.Add(-2 * time.Hour), now.Add(time.Hour)} {
+		_, err = verticalLine(ctx, fmt.Sprint(outside.Unix()), "", "")
+		require.Error(t, err)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	values := ts.NewConstantValues(ctx, 1, 3, 10000)
+	first := ts.NewSeries(ctx, "foo", ctx.StartTime, values)
+	results, err := unique(ctx, multiplePathSpecs{Values: []*ts.Series{
+		first,
+		ts.NewSeries(ctx, "bar", ctx.StartTime, values),
+		ts.NewSeries(ctx, "foo", ctx.StartTime, values),
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 2, results.Len())
+	assert.True(t, first == results.Values[0])
+	assert.Equal(t, "bar", results.Values[1].Name())
+}
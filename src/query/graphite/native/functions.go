@@ -171,6 +171,10 @@ type contextShiftFunc func(*common.Context) *common.Context
 // unaryTransformer takes in one series and returns a transformed series.
 type unaryTransformer func(ts.SeriesList) (ts.SeriesList, error)
 
+// multiTransformer takes in the series of each shifted context, in the order
+// of the shifts, and returns a transformed series.
+type multiTransformer func([]ts.SeriesList) (ts.SeriesList, error)
+
 // contextShiftAdjustFunc determines after an initial context shift whether
 // an adjustment is necessary or not
 type contextShiftAdjustFunc func(
@@ -184,6 +188,12 @@ type unaryContextShifter struct {
 	ContextShiftFunc       contextShiftFunc
 	UnaryTransformer       unaryTransformer
 	ContextShiftAdjustFunc contextShiftAdjustFunc
+
+	// NB: if set, the series are evaluated once for each of the
+	// ContextShiftFuncs and transformed together by the MultiTransformer,
+	// instead of using the single ContextShiftFunc.
+	ContextShiftFuncs []contextShiftFunc
+	MultiTransformer  multiTransformer
 }
 
 var (
@@ -195,6 +205,7 @@ var (
 	singlePathSpecType         = reflect.TypeOf(singlePathSpec{})
 	multiplePathSpecsType      = reflect.TypeOf(multiplePathSpecs{})
 	interfaceType              = reflect.TypeOf([]genericInterface{}).Elem()
+	interfaceSliceType         = reflect.SliceOf(interfaceType)
 	float64Type                = reflect.TypeOf(float64(100))
 	float64SliceType           = reflect.SliceOf(float64Type)
 	intType                    = reflect.TypeOf(int(0))
@@ -214,7 +225,8 @@ var allowableTypes = reflectTypeSet{
 	seriesListType,
 	singlePathSpecType,
 	multiplePathSpecsType,
-	interfaceType, // only for function parameters
+	interfaceType,      // only for function parameters
+	interfaceSliceType, // only for function parameters
 	float64Type,
 	float64SliceType,
 	intType,
@@ -589,6 +601,10 @@ func (call *functionCall) Evaluate(ctx *common.Context) (reflect.Value, error) {
 		return reflect.ValueOf(ts.NewSeriesList()), nil
 	}
 
+	if shifter := result.Interface().(*unaryContextShifter); len(shifter.ContextShiftFuncs) > 0 {
+		return call.evaluateMultipleContextShifts(ctx, shifter)
+	}
+
 	contextShifter := result.Elem()
 	ctxShiftingFn := contextShifter.Field(0)
 	reflected := ctxShiftingFn.Call([]reflect.Value{reflect.ValueOf(ctx)})
@@ -650,6 +666,26 @@ MaybeAdjustShiftLoop:
 	return ret[0], err
 }
 
+// evaluateMultipleContextShifts evaluates the series in each of the shifted
+// contexts and transforms the results together.
+func (call *functionCall) evaluateMultipleContextShifts(
+	ctx *common.Context,
+	shifter *unaryContextShifter,
+) (reflect.Value, error) {
+	shiftedSeries := make([]ts.SeriesList, 0, len(shifter.ContextShiftFuncs))
+	for _, shiftFn := range shifter.ContextShiftFuncs {
+		series, err := call.in[0].Evaluate(shiftFn(ctx))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		shiftedSeries = append(shiftedSeries, series.Interface().(ts.SeriesList))
+	}
+
+	result, err := shifter.MultiTransformer(shiftedSeries)
+	return reflect.ValueOf(result), err
+}
+
 // CompatibleWith checks whether the function call's return is compatible with the given reflection type
 func (call *functionCall) CompatibleWith(reflectType reflect.Type) bool {
 	if reflectType == interfaceType {
@@ -0,0 +1,221 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/query/graphite/common"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/graphite/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+var (
+	errNoTagExpressions = xerrors.NewInvalidParamsError(
+		errors.New("seriesByTag requires at least one tag expression"))
+	errNoGroupByTags = xerrors.NewInvalidParamsError(
+		errors.New("groupByTags requires at least one tag"))
+)
+
+// seriesByTag returns the series matching all of the given tag expressions,
+// e.g. seriesByTag('name=cpu.load', 'dc=~us-.*', 'host!=web01').
+func seriesByTag(ctx *common.Context, tagExpressions ...string) (ts.SeriesList, error) {
+	if len(tagExpressions) == 0 {
+		return ts.NewSeriesList(), errNoTagExpressions
+	}
+
+	var (
+		begin = time.Now()
+		query = storage.SeriesByTagQuery(tagExpressions)
+		opts  = storage.FetchOptions{
+			StartTime: ctx.StartTime,
+			EndTime:   ctx.EndTime,
+			DataOptions: storage.DataOptions{
+				Timeout: ctx.Timeout,
+				Limit:   ctx.Limit,
+			},
+			Source: ctx.Source,
+		}
+	)
+
+	result, err := ctx.Engine.FetchByQuery(ctx, query, opts)
+	if err != nil {
+		return ts.NewSeriesList(), err
+	}
+
+	if ctx.TracingEnabled() {
+		ctx.Trace(common.Trace{
+			ActivityName: fmt.Sprintf("fetch %s", query),
+			Duration:     time.Since(begin),
+			Outputs:      common.TraceStats{NumSeries: len(result.SeriesList)},
+		})
+	}
+
+	for _, r := range result.SeriesList {
+		r.Specification = query
+	}
+
+	return ts.SeriesList{
+		Values:   result.SeriesList,
+		Metadata: result.Metadata,
+	}, nil
+}
+
+// groupByTags groups the series by the values of the given tags and applies
+// the callback aggregation to each group, e.g.
+//
+//	groupByTags(seriesByTag('name=cpu.load'), 'sum', 'dc')
+//
+// returns series named like `sum;dc=us-east`. If `name` is one of the tags
+// the metric path is used in place of the callback name.
+func groupByTags(
+	ctx *common.Context,
+	seriesList singlePathSpec,
+	callback string,
+	tags ...string,
+) (ts.SeriesList, error) {
+	if len(tags) == 0 {
+		return ts.NewSeriesList(), errNoGroupByTags
+	}
+
+	tags = append([]string(nil), tags...)
+	sort.Strings(tags)
+
+	metaSeries := make(map[string][]*ts.Series)
+	for _, series := range seriesList.Values {
+		var (
+			path, seriesTags = parseSeriesTags(series)
+			key              strings.Builder
+		)
+
+		key.WriteString(callback)
+		for _, tag := range tags {
+			if tag == graphite.NameTag {
+				key.Reset()
+				key.WriteString(path)
+				break
+			}
+		}
+
+		for _, tag := range tags {
+			if tag == graphite.NameTag {
+				continue
+			}
+
+			if value, ok := seriesTags[tag]; ok {
+				key.WriteByte(';')
+				key.WriteString(tag)
+				key.WriteByte('=')
+				key.WriteString(value)
+			}
+		}
+
+		groupKey := key.String()
+		metaSeries[groupKey] = append(metaSeries[groupKey], series)
+	}
+
+	return applyFnToMetaSeries(ctx, seriesList, metaSeries, callback)
+}
+
+// aliasByTags renames each series using the given tags and path nodes, e.g.
+//
+//	aliasByTags(seriesByTag('name=cpu.load'), 1, 'dc', 'host')
+//
+// returns series named like `load.us-east.web01`. Numeric arguments refer to
+// nodes of the metric path while `name` refers to the whole path.
+func aliasByTags(
+	ctx *common.Context,
+	seriesList singlePathSpec,
+	tags ...genericInterface,
+) (ts.SeriesList, error) {
+	renamed := make([]*ts.Series, 0, ts.SeriesList(seriesList).Len())
+	for _, series := range seriesList.Values {
+		var (
+			path, seriesTags = parseSeriesTags(series)
+			nameParts        = strings.Split(path, ".")
+			newNameParts     = make([]string, 0, len(tags))
+		)
+
+		for _, tag := range tags {
+			var node int
+			switch v := tag.(type) {
+			case string:
+				if v == graphite.NameTag {
+					newNameParts = append(newNameParts, path)
+				} else if value, ok := seriesTags[v]; ok {
+					newNameParts = append(newNameParts, value)
+				}
+				continue
+			case float64:
+				node = int(v)
+			case int:
+				node = v
+			default:
+				return ts.NewSeriesList(), xerrors.NewInvalidParamsError(
+					fmt.Errorf("aliasByTags expects tags or nodes, received %v", tag))
+			}
+
+			// NB: graphite supports negative indexing of nodes.
+			if node < 0 {
+				node += len(nameParts)
+			}
+			if node < 0 || node >= len(nameParts) {
+				continue
+			}
+			newNameParts = append(newNameParts, nameParts[node])
+		}
+
+		renamed = append(renamed, series.RenamedTo(strings.Join(newNameParts, ".")))
+	}
+
+	seriesList.Values = renamed
+	return ts.SeriesList(seriesList), nil
+}
+
+// parseSeriesTags returns the metric path and tags of a series from its
+// tagged name, looking through any function calls wrapping the name.
+func parseSeriesTags(series *ts.Series) (string, map[string]string) {
+	name := series.Name()
+	if metricExpr, ok := findFirstMetricExpression(name); ok {
+		name = metricExpr
+		if idx := strings.IndexByte(name, ','); idx >= 0 {
+			name = name[:idx]
+		}
+	}
+
+	path, tags, err := graphite.ParseTaggedPath([]byte(name), nil)
+	if err != nil {
+		return name, nil
+	}
+
+	seriesTags := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		seriesTags[string(tag.Name)] = string(tag.Value)
+	}
+
+	return string(path), seriesTags
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package native
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/graphite/common"
+	"github.com/m3db/m3/src/query/graphite/storage"
+	"github.com/m3db/m3/src/query/graphite/ts"
+	xerrors "github.com/m3db/m3/src/x/errors"
+	xgomock "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTaggedSeries(ctx *common.Context, now time.Time) []*ts.Series {
+	return []*ts.Series{
+		ts.NewSeries(ctx, "cpu.load;dc=us-east;host=web01", now,
+			common.NewTestSeriesValues(ctx, 60000, []float64{1, 2, 3})),
+		ts.NewSeries(ctx, "cpu.load;dc=us-east;host=web02", now,
+			common.NewTestSeriesValues(ctx, 60000, []float64{10, 20, 30})),
+		ts.NewSeries(ctx, "cpu.load;dc=us-west;host=web03", now,
+			common.NewTestSeriesValues(ctx, 60000, []float64{100, 200, 300})),
+	}
+}
+
+func TestSeriesByTag(t *testing.T) {
+	var (
+		ctrl      = xgomock.NewController(t)
+		store     = storage.NewMockStorage(ctrl)
+		now       = time.Now().Truncate(time.Hour)
+		engine    = NewEngine(store, CompileOptions{})
+		startTime = now.Add(-3 * time.Minute)
+		endTime   = now
+		ctx       = common.NewContext(common.ContextOptions{Start: startTime, End: endTime, Engine: engine})
+		query     = "seriesByTag('name=cpu.load','dc=~us-.*')"
+	)
+
+	defer ctrl.Finish()
+	defer func() { _ = ctx.Close() }()
+
+	store.EXPECT().FetchByQuery(gomock.Any(), query, gomock.Any()).Return(
+		&storage.FetchResult{SeriesList: testTaggedSeries(ctx, startTime)}, nil).Times(2)
+
+	expr, err := engine.Compile(`seriesByTag('name=cpu.load', "dc=~us-.*")`)
+	require.NoError(t, err)
+	res, err := expr.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Len())
+	assert.Equal(t, "cpu.load;dc=us-east;host=web01", res.Values[0].Name())
+	assert.Equal(t, query, res.Values[0].Specification)
+
+	expr, err = engine.Compile(
+		`aliasByTags(groupByTags(seriesByTag('name=cpu.load', 'dc=~us-.*'), 'sum', 'dc'), 'dc')`)
+	require.NoError(t, err)
+	res, err = expr.Execute(ctx)
+	require.NoError(t, err)
+	res, err = sortByName(ctx, singlePathSpec(res), false, false)
+	require.NoError(t, err)
+	common.CompareOutputsAndExpected(t, 60000, startTime, []common.TestSeries{
+		{Name: "us-east", Data: []float64{11, 22, 33}},
+		{Name: "us-west", Data: []float64{100, 200, 300}},
+	}, res.Values)
+
+	_, err = seriesByTag(ctx)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
+
+func TestGroupByTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	series := singlePathSpec{Values: testTaggedSeries(ctx, time.Now())}
+	tests := []struct {
+		callback string
+		tags     []string
+		expected []string
+	}{
+		{"sum", []string{"dc"}, []string{"sum;dc=us-east", "sum;dc=us-west"}},
+		{"max", []string{"name", "dc"}, []string{"cpu.load;dc=us-east", "cpu.load;dc=us-west"}},
+		{"avg", []string{"name"}, []string{"cpu.load"}},
+		{"sum", []string{"host", "missing"}, []string{
+			"sum;host=web01", "sum;host=web02", "sum;host=web03",
+		}},
+	}
+
+	for _, test := range tests {
+		res, err := groupByTags(ctx, series, test.callback, test.tags...)
+		require.NoError(t, err)
+		res, err = sortByName(ctx, singlePathSpec(res), false, false)
+		require.NoError(t, err)
+
+		names := make([]string, 0, res.Len())
+		for _, s := range res.Values {
+			names = append(names, s.Name())
+		}
+		assert.Equal(t, test.expected, names)
+	}
+
+	_, err := groupByTags(ctx, series, "sum")
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
+
+func TestAliasByTags(t *testing.T) {
+	ctx := common.NewTestContext()
+	defer func() { _ = ctx.Close() }()
+
+	now := time.Now()
+	values := ts.NewConstantValues(ctx, 10.0, 1000, 10)
+	series := singlePathSpec{Values: []*ts.Series{
+		ts.NewSeries(ctx, "cpu.load;dc=us-east;host=web01", now, values),
+		ts.NewSeries(ctx, "sumSeries(cpu.idle;host=web02)", now, values),
+		ts.NewSeries(ctx, "servers.web03.cpu", now, values),
+	}}
+
+	res, err := aliasByTags(ctx, series, float64(1), "host", "dc")
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Len())
+	assert.Equal(t, "load.web01.us-east", res.Values[0].Name())
+	assert.Equal(t, "idle.web02", res.Values[1].Name())
+	assert.Equal(t, "web03", res.Values[2].Name())
+
+	res, err = aliasByTags(ctx, series, "name", -1)
+	require.NoError(t, err)
+	assert.Equal(t, "cpu.load.load", res.Values[0].Name())
+	assert.Equal(t, "cpu.idle.idle", res.Values[1].Name())
+	assert.Equal(t, "servers.web03.cpu.cpu", res.Values[2].Name())
+
+	_, err = aliasByTags(ctx, series, true)
+	require.Error(t, err)
+	assert.True(t, xerrors.IsInvalidParams(err))
+}
@@ -172,7 +172,21 @@ func translateQuery(
 	fetchOpts FetchOptions,
 	opts M3WrappedStorageOptions,
 ) (*storage.FetchQuery, error) {
-	matchers, _, err := TranslateQueryToMatchersWithTerminator(query)
+	var (
+		matchers models.Matchers
+		err      error
+	)
+
+	if IsSeriesByTagQuery(query) {
+		var tagExpressions []string
+		tagExpressions, err = parseSeriesByTagQuery(query)
+		if err == nil {
+			matchers, err = TranslateTagExpressionsToMatchers(tagExpressions)
+		}
+	} else {
+		matchers, _, err = TranslateQueryToMatchersWithTerminator(query)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,253 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/models"
+	xerrors "github.com/m3db/m3/src/x/errors"
+)
+
+const (
+	// SeriesByTagFunction is the name of the function which fetches series
+	// by tag expressions rather than by path.
+	SeriesByTagFunction = "seriesByTag"
+
+	seriesByTagPrefix = SeriesByTagFunction + "("
+
+	// NB: tagged series IDs are the metric path followed by any tags, e.g.
+	// `cpu.load;host=a`, so the path is matched by excluding the tags.
+	taggedIDSuffixPattern = "(;.*)?"
+)
+
+var errNoPositiveTagExpression = xerrors.NewInvalidParamsError(errors.New(
+	"seriesByTag requires at least one tag expression matching a non-empty value"))
+
+// SeriesByTagQuery returns the query used to fetch series matching the
+// given tag expressions, e.g. `seriesByTag('name=cpu','host=~web.*')`.
+func SeriesByTagQuery(tagExpressions []string) string {
+	var b strings.Builder
+	b.WriteString(seriesByTagPrefix)
+	for i, expr := range tagExpressions {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		quote := byte('\'')
+		if strings.IndexByte(expr, quote) >= 0 {
+			quote = '"'
+		}
+
+		b.WriteByte(quote)
+		b.WriteString(expr)
+		b.WriteByte(quote)
+	}
+
+	b.WriteByte(')')
+	return b.String()
+}
+
+// IsSeriesByTagQuery returns true if the query is a seriesByTag query.
+func IsSeriesByTagQuery(query string) bool {
+	return strings.HasPrefix(query, seriesByTagPrefix)
+}
+
+// parseSeriesByTagQuery parses the tag expressions of a seriesByTag query.
+func parseSeriesByTagQuery(query string) ([]string, error) {
+	if !IsSeriesByTagQuery(query) || !strings.HasSuffix(query, ")") {
+		return nil, fmt.Errorf("invalid seriesByTag query: %s", query)
+	}
+
+	var (
+		args  = query[len(seriesByTagPrefix) : len(query)-1]
+		exprs []string
+	)
+
+	for {
+		args = strings.TrimLeft(args, " ")
+		if len(args) == 0 {
+			return exprs, nil
+		}
+
+		quote := args[0]
+		if quote != '\'' && quote != '"' {
+			return nil, fmt.Errorf("invalid seriesByTag query, "+
+				"expected quoted tag expression: %s", query)
+		}
+
+		end := strings.IndexByte(args[1:], quote)
+		if end < 0 {
+			return nil, fmt.Errorf("invalid seriesByTag query, "+
+				"unterminated tag expression: %s", query)
+		}
+
+		exprs = append(exprs, args[1:end+1])
+		args = strings.TrimLeft(args[end+2:], " ")
+		if len(args) > 0 {
+			if args[0] != ',' {
+				return nil, fmt.Errorf("invalid seriesByTag query, "+
+					"expected comma between tag expressions: %s", query)
+			}
+
+			args = args[1:]
+		}
+	}
+}
+
+// TranslateTagExpressionsToMatchers converts Graphite tag expressions, as
+// given to seriesByTag, to tag matchers. The supported expressions are:
+//
+//	tag=value    tag is equal to value, or does not exist if value is empty
+//	tag!=value   tag is not equal to value, or exists if value is empty
+//	tag=~regex   tag matches the regex, which is anchored at the start only
+//	tag!=~regex  tag does not match the regex
+//
+// The `name` tag refers to the metric path of the series.
+func TranslateTagExpressionsToMatchers(
+	tagExpressions []string,
+) (models.Matchers, error) {
+	var (
+		matchers = make(models.Matchers, 0, len(tagExpressions))
+		positive bool
+	)
+
+	for _, expr := range tagExpressions {
+		tag, matchType, value, err := parseTagExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(value) > 0 &&
+			(matchType == models.MatchEqual || matchType == models.MatchRegexp) {
+			positive = true
+		}
+
+		if tag == graphite.NameTag {
+			matchers, err = appendNameMatchers(matchers, matchType, value)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		matchers = append(matchers, tagMatcher(tag, matchType, value))
+	}
+
+	if !positive {
+		return nil, errNoPositiveTagExpression
+	}
+
+	return matchers, nil
+}
+
+func parseTagExpression(expr string) (string, models.MatchType, string, error) {
+	idx := strings.IndexAny(expr, "!=")
+	if idx <= 0 {
+		return "", 0, "", xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression: %s", expr))
+	}
+
+	tag, op := expr[:idx], expr[idx:]
+	switch {
+	case strings.HasPrefix(op, "!=~"):
+		return tag, models.MatchNotRegexp, op[3:], nil
+	case strings.HasPrefix(op, "=~"):
+		return tag, models.MatchRegexp, op[2:], nil
+	case strings.HasPrefix(op, "!="):
+		return tag, models.MatchNotEqual, op[2:], nil
+	case strings.HasPrefix(op, "="):
+		return tag, models.MatchEqual, op[1:], nil
+	default:
+		return "", 0, "", xerrors.NewInvalidParamsError(
+			fmt.Errorf("invalid tag expression: %s", expr))
+	}
+}
+
+func tagMatcher(tag string, matchType models.MatchType, value string) models.Matcher {
+	switch {
+	case matchType == models.MatchEqual && len(value) == 0:
+		return models.Matcher{Type: models.MatchNotField, Name: []byte(tag)}
+	case matchType == models.MatchNotEqual && len(value) == 0:
+		return models.Matcher{Type: models.MatchField, Name: []byte(tag)}
+	case matchType == models.MatchRegexp || matchType == models.MatchNotRegexp:
+		// NB: Graphite regexes are only anchored at the start, whereas
+		// matchers are anchored at both ends.
+		value = "(?:" + value + ").*"
+	}
+
+	return models.Matcher{Type: matchType, Name: []byte(tag), Value: []byte(value)}
+}
+
+func appendNameMatchers(
+	matchers models.Matchers,
+	matchType models.MatchType,
+	value string,
+) (models.Matchers, error) {
+	if matchType == models.MatchEqual && len(value) > 0 {
+		// NB: exact paths are matched on each path part which is cheaper than
+		// matching the entire ID.
+		parts := strings.Split(value, ".")
+		for i, part := range parts {
+			if len(part) == 0 {
+				return nil, xerrors.NewInvalidParamsError(
+					fmt.Errorf("invalid name in tag expression: %s", value))
+			}
+
+			matchers = append(matchers, models.Matcher{
+				Type:  models.MatchEqual,
+				Name:  graphite.TagName(i),
+				Value: []byte(part),
+			})
+		}
+
+		return append(matchers, matcherTerminator(len(parts))), nil
+	}
+
+	var pattern string
+	switch matchType {
+	case models.MatchEqual, models.MatchNotEqual:
+		pattern = regexp.QuoteMeta(value) + taggedIDSuffixPattern
+		if matchType == models.MatchEqual {
+			matchType = models.MatchRegexp
+		} else {
+			matchType = models.MatchNotRegexp
+		}
+	default:
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, xerrors.NewInvalidParamsError(err)
+		}
+
+		pattern = "(?:" + value + ")[^;]*" + taggedIDSuffixPattern
+	}
+
+	return append(matchers, models.Matcher{
+		Type:  matchType,
+		Name:  doc.IDReservedFieldName,
+		Value: []byte(pattern),
+	}), nil
+}
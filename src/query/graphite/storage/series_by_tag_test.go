@@ -0,0 +1,157 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/m3ninx/doc"
+	"github.com/m3db/m3/src/query/graphite/graphite"
+	"github.com/m3db/m3/src/query/models"
+	xerrors "github.com/m3db/m3/src/x/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesByTagQueryRoundTrip(t *testing.T) {
+	exprs := []string{"name=cpu.load", "host=~web,db", `dc!='a'`}
+	query := SeriesByTagQuery(exprs)
+	assert.Equal(t, `seriesByTag('name=cpu.load','host=~web,db',"dc!='a'")`, query)
+	assert.True(t, IsSeriesByTagQuery(query))
+
+	parsed, err := parseSeriesByTagQuery(query)
+	require.NoError(t, err)
+	assert.Equal(t, exprs, parsed)
+
+	parsed, err = parseSeriesByTagQuery(`seriesByTag( 'a=b' , "c=d" )`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a=b", "c=d"}, parsed)
+}
+
+func TestParseSeriesByTagQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`seriesByTag(a=b)`,
+		`seriesByTag('a=b`,
+		`seriesByTag('a=b')x`,
+		`seriesByTag('a=b' 'c=d')`,
+	} {
+		_, err := parseSeriesByTagQuery(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestTranslateTagExpressionsToMatchers(t *testing.T) {
+	matchers, err := TranslateTagExpressionsToMatchers([]string{
+		"name=cpu.load",
+		"host=~web",
+		"dc!=us-east",
+		"az=",
+		"rack!=",
+		"env!=~prod",
+	})
+	require.NoError(t, err)
+
+	expected := models.Matchers{
+		{Type: models.MatchEqual, Name: graphite.TagName(0), Value: []byte("cpu")},
+		{Type: models.MatchEqual, Name: graphite.TagName(1), Value: []byte("load")},
+		{Type: models.MatchNotField, Name: graphite.TagName(2)},
+		{Type: models.MatchRegexp, Name: []byte("host"), Value: []byte("(?:web).*")},
+		{Type: models.MatchNotEqual, Name: []byte("dc"), Value: []byte("us-east")},
+		{Type: models.MatchNotField, Name: []byte("az")},
+		{Type: models.MatchField, Name: []byte("rack")},
+		{Type: models.MatchNotRegexp, Name: []byte("env"), Value: []byte("(?:prod).*")},
+	}
+
+	assert.Equal(t, expected, matchers)
+}
+
+func TestTranslateTagExpressionsToMatchersName(t *testing.T) {
+	matchers, err := TranslateTagExpressionsToMatchers([]string{
+		"host=a",
+		"name!=cpu.load",
+		"name=~cpu",
+		"name!=~mem",
+	})
+	require.NoError(t, err)
+
+	expected := models.Matchers{
+		{Type: models.MatchEqual, Name: []byte("host"), Value: []byte("a")},
+		{
+			Type:  models.MatchNotRegexp,
+			Name:  doc.IDReservedFieldName,
+			Value: []byte(`cpu\.load(;.*)?`),
+		},
+		{
+			Type:  models.MatchRegexp,
+			Name:  doc.IDReservedFieldName,
+			Value: []byte(`(?:cpu)[^;]*(;.*)?`),
+		},
+		{
+			Type:  models.MatchNotRegexp,
+			Name:  doc.IDReservedFieldName,
+			Value: []byte(`(?:mem)[^;]*(;.*)?`),
+		},
+	}
+
+	assert.Equal(t, expected, matchers)
+}
+
+func TestTranslateTagExpressionsToMatchersErrors(t *testing.T) {
+	for _, exprs := range [][]string{
+		{"host!=a"},
+		{"host="},
+		{"host"},
+		{"=a"},
+		{"name=cpu..load"},
+		{"name=~cpu[", "host=a"},
+	} {
+		_, err := TranslateTagExpressionsToMatchers(exprs)
+		require.Error(t, err, fmt.Sprint(exprs))
+		assert.True(t, xerrors.IsInvalidParams(err), fmt.Sprint(exprs))
+	}
+}
+
+func TestTranslateQuerySeriesByTag(t *testing.T) {
+	query := SeriesByTagQuery([]string{"name=cpu", "host=a"})
+	end := time.Now()
+	start := end.Add(time.Hour * -2)
+	opts := FetchOptions{
+		StartTime: start,
+		EndTime:   end,
+		DataOptions: DataOptions{
+			Timeout: time.Minute,
+		},
+	}
+
+	translated, err := translateQuery(query, opts, M3WrappedStorageOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, query, translated.Raw)
+	expected := models.Matchers{
+		{Type: models.MatchEqual, Name: graphite.TagName(0), Value: []byte("cpu")},
+		{Type: models.MatchNotField, Name: graphite.TagName(1)},
+		{Type: models.MatchEqual, Name: []byte("host"), Value: []byte("a")},
+	}
+
+	assert.Equal(t, expected, translated.TagMatchers)
+}
@@ -21,6 +21,10 @@
 package models
 
 import (
+	"bytes"
+	"sort"
+
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models/strconv"
 	"github.com/m3db/m3/src/query/util/writer"
 )
@@ -212,22 +216,60 @@ func idLenGraphite(t Tags) int {
 	idLen := t.Len() - 1 // account for separators
 	for _, tag := range t.Tags {
 		idLen += len(tag.Value)
+		if _, ok := graphite.TagIndex(tag.Name); !ok {
+			// NB: tagged series also include the tag name and value separator.
+			idLen += len(tag.Name) + 1
+		}
 	}
 
 	return idLen
 }
 
+// graphiteID generates the ID for graphite tags, which is the dot separated
+// metric path for plain metrics, e.g. `cpu.load`. Any tags other than the
+// path tags are appended sorted by name as with Graphite tagged series, e.g.
+// `cpu.load;dc=us;host=a`.
 func graphiteID(t Tags) []byte {
 	// TODO: pool these bytes.
-	id := make([]byte, idLenGraphite(t))
-	idx := 0
-	lastIndex := len(t.Tags) - 1
-	for _, tag := range t.Tags[:lastIndex] {
+	var (
+		id     = make([]byte, idLenGraphite(t))
+		idx    = 0
+		tagged []Tag
+	)
+
+	for i, tag := range t.Tags {
+		if _, ok := graphite.TagIndex(tag.Name); !ok {
+			tagged = append(tagged, tag)
+			continue
+		}
+
+		if i > len(tagged) {
+			id[idx] = graphiteSep
+			idx++
+		}
+
 		idx += copy(id[idx:], tag.Value)
-		id[idx] = graphiteSep
+	}
+
+	if len(tagged) == 0 {
+		return id
+	}
+
+	sort.Slice(tagged, func(i, j int) bool {
+		return bytes.Compare(tagged[i].Name, tagged[j].Name) < 0
+	})
+
+	for _, tag := range tagged {
+		if idx > 0 {
+			id[idx] = graphiteTaggedSep
+			idx++
+		}
+
+		idx += copy(id[idx:], tag.Name)
+		id[idx] = graphiteTagValueSep
 		idx++
+		idx += copy(id[idx:], tag.Value)
 	}
 
-	copy(id[idx:], t.Tags[lastIndex].Value)
 	return id
 }
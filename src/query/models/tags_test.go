@@ -76,6 +76,21 @@ func TestLongTagNewIDOutOfOrderGraphite(t *testing.T) {
 	assert.Equal(t, []byte("v0.v1.v2.v3.v4.v5.v6.v7.v8.v9.v10.v11.v12"), actual)
 }
 
+func TestTaggedIDGraphite(t *testing.T) {
+	opts := NewTagOptions().SetIDSchemeType(TypeGraphite)
+	tags := NewTags(5, opts).AddTags([]Tag{
+		{Name: []byte("host"), Value: []byte("web01")},
+		{Name: []byte("__g1__"), Value: []byte("load")},
+		{Name: []byte("dc"), Value: []byte("us-east")},
+		{Name: []byte("__g0__"), Value: []byte("cpu")},
+		{Name: []byte("availability_zone"), Value: []byte("a")},
+	})
+
+	require.NoError(t, tags.Validate())
+	assert.Equal(t,
+		[]byte("cpu.load;availability_zone=a;dc=us-east;host=web01"), tags.ID())
+}
+
 func TestLongTagNewIDOutOfOrderQuotedWithEscape(t *testing.T) {
 	tags := testLongTagIDOutOfOrder(t, TypeQuoted)
 	tags = tags.AddTag(Tag{Name: []byte(`t5""`), Value: []byte(`v"5`)})
@@ -34,6 +34,9 @@ const (
 	eq           = byte('=')
 	leftBracket  = byte('{')
 	rightBracket = byte('}')
+
+	graphiteTaggedSep   = byte(';')
+	graphiteTagValueSep = byte('=')
 )
 
 // IDSchemeType determines the scheme for generating
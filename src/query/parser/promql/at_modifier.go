@@ -0,0 +1,183 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"time"
+
+	pql "github.com/prometheus/prometheus/promql/parser"
+
+	xtime "github.com/m3db/m3/src/x/time"
+)
+
+// atState describes whether an expression is evaluated at a fixed time.
+type atState uint8
+
+const (
+	// atNone is an expression without any selectors, e.g. a literal.
+	atNone atState = iota
+	// atFixed is an expression where every selector has the same `@` modifier.
+	atFixed
+	// atVarying is an expression that must be evaluated at every step.
+	atVarying
+)
+
+// atModifierUnsafeFunctions are functions whose results depend on the
+// evaluation time even if their arguments are step invariant.
+var atModifierUnsafeFunctions = map[string]struct{}{
+	"days_in_month":  {},
+	"day_of_month":   {},
+	"day_of_week":    {},
+	"hour":           {},
+	"minute":         {},
+	"month":          {},
+	"year":           {},
+	"predict_linear": {},
+	"time":           {},
+	"timestamp":      {},
+}
+
+// resolveStartEnd resolves the `@ start()` and `@ end()` modifiers of an
+// expression to the bounds of the top level query, as Prometheus does.
+func resolveStartEnd(expr pql.Expr, start, end xtime.UnixNano) {
+	resolve := func(timestamp **int64, startOrEnd *pql.ItemType) {
+		var at xtime.UnixNano
+		switch *startOrEnd {
+		case pql.START:
+			at = start
+		case pql.END:
+			at = end
+		default:
+			return
+		}
+
+		ts := at.ToNormalizedTime(time.Millisecond)
+		*timestamp = &ts
+		*startOrEnd = 0
+	}
+
+	pql.Inspect(expr, func(node pql.Node, _ []pql.Node) error {
+		switch n := node.(type) {
+		case *pql.VectorSelector:
+			resolve(&n.Timestamp, &n.StartOrEnd)
+		case *pql.SubqueryExpr:
+			resolve(&n.Timestamp, &n.StartOrEnd)
+		}
+
+		return nil
+	})
+}
+
+// atTime returns the time given by an `@` modifier, if there is one.
+func atTime(timestamp *int64) *xtime.UnixNano {
+	if timestamp == nil {
+		return nil
+	}
+
+	at := xtime.FromNormalizedTime(*timestamp, time.Millisecond)
+	return &at
+}
+
+func selectorAt(timestamp *int64) (xtime.UnixNano, atState) {
+	if timestamp == nil {
+		return 0, atVarying
+	}
+
+	return *atTime(timestamp), atFixed
+}
+
+func combineAt(
+	a xtime.UnixNano,
+	aState atState,
+	b xtime.UnixNano,
+	bState atState,
+) (xtime.UnixNano, atState) {
+	switch {
+	case aState == atVarying || bState == atVarying:
+		return 0, atVarying
+	case aState == atNone:
+		return b, bState
+	case bState == atNone:
+		return a, aState
+	case a == b:
+		return a, atFixed
+	default:
+		return 0, atVarying
+	}
+}
+
+// exprAt returns the time an expression is evaluated at if it is fixed by
+// `@` modifiers on all of its selectors.
+func exprAt(expr pql.Expr) (xtime.UnixNano, atState) {
+	switch n := expr.(type) {
+	case *pql.VectorSelector:
+		return selectorAt(n.Timestamp)
+
+	case *pql.MatrixSelector:
+		return exprAt(n.VectorSelector)
+
+	case *pql.SubqueryExpr:
+		return selectorAt(n.Timestamp)
+
+	case *pql.Call:
+		if _, unsafe := atModifierUnsafeFunctions[n.Func.Name]; unsafe {
+			return 0, atVarying
+		}
+
+		var (
+			at    xtime.UnixNano
+			state = atNone
+		)
+
+		for _, arg := range n.Args {
+			argAt, argState := exprAt(arg)
+			at, state = combineAt(at, state, argAt, argState)
+		}
+
+		return at, state
+
+	case *pql.AggregateExpr:
+		at, state := exprAt(n.Expr)
+		if n.Param == nil {
+			return at, state
+		}
+
+		paramAt, paramState := exprAt(n.Param)
+		return combineAt(at, state, paramAt, paramState)
+
+	case *pql.BinaryExpr:
+		lhsAt, lhsState := exprAt(n.LHS)
+		rhsAt, rhsState := exprAt(n.RHS)
+		return combineAt(lhsAt, lhsState, rhsAt, rhsState)
+
+	case *pql.ParenExpr:
+		return exprAt(n.Expr)
+
+	case *pql.UnaryExpr:
+		return exprAt(n.Expr)
+
+	case *pql.NumberLiteral, *pql.StringLiteral:
+		return 0, atNone
+
+	default:
+		return 0, atVarying
+	}
+}
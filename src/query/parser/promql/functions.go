@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package promql
+
+import (
+	"github.com/prometheus/prometheus/promql"
+	pql "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions/linear"
+	"github.com/m3db/m3/src/query/functions/temporal"
+)
+
+// mathFunctions are functions supported by the native engine that are not
+// available in the vendored Prometheus parser; they are registered with both
+// the Prometheus parser and engine so queries parse identically regardless
+// of which engine is used.
+var mathFunctions = []string{
+	linear.AcosType, linear.AcoshType, linear.AsinType, linear.AsinhType,
+	linear.AtanType, linear.AtanhType, linear.CosType, linear.CoshType,
+	linear.SinType, linear.SinhType, linear.TanType, linear.TanhType,
+	linear.DegType, linear.RadType,
+}
+
+func init() {
+	for _, name := range mathFunctions {
+		fn, ok := linear.MathFunc(name)
+		if !ok {
+			panic("unknown math function: " + name)
+		}
+
+		registerFunction(&pql.Function{
+			Name:       name,
+			ArgTypes:   []pql.ValueType{pql.ValueTypeVector},
+			ReturnType: pql.ValueTypeVector,
+		}, simpleFunction(fn))
+	}
+
+	registerFunction(&pql.Function{
+		Name:       temporal.PresentType,
+		ArgTypes:   []pql.ValueType{pql.ValueTypeMatrix},
+		ReturnType: pql.ValueTypeVector,
+	}, presentOverTime)
+}
+
+func registerFunction(fn *pql.Function, call promql.FunctionCall) {
+	// NB: do not override functions if they are supported upstream.
+	if _, ok := pql.Functions[fn.Name]; ok {
+		return
+	}
+
+	pql.Functions[fn.Name] = fn
+	promql.FunctionCalls[fn.Name] = call
+}
+
+func simpleFunction(fn block.ValueTransform) promql.FunctionCall {
+	return func(
+		vals []pql.Value,
+		_ pql.Expressions,
+		enh *promql.EvalNodeHelper,
+	) promql.Vector {
+		for _, el := range vals[0].(promql.Vector) {
+			enh.Out = append(enh.Out, promql.Sample{
+				Metric: enh.DropMetricName(el.Metric),
+				Point:  promql.Point{V: fn(el.V)},
+			})
+		}
+
+		return enh.Out
+	}
+}
+
+// presentOverTime is only called by the Prometheus engine for series with
+// at least one value in range.
+func presentOverTime(
+	vals []pql.Value,
+	_ pql.Expressions,
+	enh *promql.EvalNodeHelper,
+) promql.Vector {
+	el := vals[0].(promql.Matrix)[0]
+	return append(enh.Out, promql.Sample{
+		Metric: enh.DropMetricName(el.Metric),
+		Point:  promql.Point{V: 1},
+	})
+}
@@ -54,6 +54,7 @@ func NewSelectorFromVector(
 	return functions.FetchOp{
 		Name:     n.Name,
 		Offset:   n.Offset,
+		At:       atTime(n.Timestamp),
 		Matchers: matchers,
 	}, nil
 }
@@ -72,6 +73,7 @@ func NewSelectorFromMatrix(
 	return functions.FetchOp{
 		Name:     vectorSelector.Name,
 		Offset:   vectorSelector.Offset,
+		At:       atTime(vectorSelector.Timestamp),
 		Matchers: matchers,
 		Range:    n.Range,
 	}, nil
@@ -162,6 +164,8 @@ func getAggOpType(opType promql.ItemType) string {
 		return aggregation.StandardVarianceType
 	case promql.COUNT:
 		return aggregation.CountType
+	case promql.GROUP:
+		return aggregation.GroupType
 
 	case promql.TOPK:
 		return aggregation.TopKType
@@ -235,7 +239,12 @@ func NewFunctionExpr(
 	switch name {
 	case linear.AbsType, linear.CeilType, linear.ExpType,
 		linear.FloorType, linear.LnType, linear.Log10Type,
-		linear.Log2Type, linear.SqrtType:
+		linear.Log2Type, linear.SqrtType, linear.SgnType,
+		linear.AcosType, linear.AcoshType, linear.AsinType,
+		linear.AsinhType, linear.AtanType, linear.AtanhType,
+		linear.CosType, linear.CoshType, linear.SinType,
+		linear.SinhType, linear.TanType, linear.TanhType,
+		linear.DegType, linear.RadType:
 		p, err = linear.NewMathOp(name)
 		return p, true, err
 
@@ -261,7 +270,8 @@ func NewFunctionExpr(
 
 	case temporal.AvgType, temporal.CountType, temporal.MinType,
 		temporal.MaxType, temporal.SumType, temporal.StdDevType,
-		temporal.StdVarType:
+		temporal.StdVarType, temporal.LastType, temporal.PresentType,
+		temporal.AbsentType:
 		p, err = temporal.NewAggOp(argValues, name)
 		return p, true, err
 
@@ -462,6 +472,55 @@ func LabelMatchersToModelMatcher(
 	return matchers, nil
 }
 
+// absentTags returns the tags of the series returned by absent_over_time;
+// as in Prometheus, these are taken from the equality matchers of a matrix
+// selector argument, excluding any label which is matched more than once.
+func absentTags(expr promql.Expr, tagOpts models.TagOptions) models.Tags {
+	tags := models.NewTags(0, tagOpts)
+	selector, ok := expr.(*promql.MatrixSelector)
+	if !ok {
+		return tags
+	}
+
+	vectorSelector, ok := selector.VectorSelector.(*promql.VectorSelector)
+	if !ok {
+		return tags
+	}
+
+	var (
+		names   = make([]string, 0, len(vectorSelector.LabelMatchers))
+		values  = make(map[string]string, len(vectorSelector.LabelMatchers))
+		removed = make(map[string]struct{})
+	)
+
+	for _, m := range vectorSelector.LabelMatchers {
+		if m.Name == model.MetricNameLabel {
+			continue
+		}
+
+		if _, ok := values[m.Name]; ok || m.Type != labels.MatchEqual {
+			removed[m.Name] = struct{}{}
+			continue
+		}
+
+		names = append(names, m.Name)
+		values[m.Name] = m.Value
+	}
+
+	for _, name := range names {
+		if _, ok := removed[name]; ok {
+			continue
+		}
+
+		tags = tags.AddTag(models.Tag{
+			Name:  []byte(name),
+			Value: []byte(values[name]),
+		})
+	}
+
+	return tags.Normalize()
+}
+
 // promTypeToM3 converts a prometheus label type to m3 matcher type.
 // TODO(nikunj): Consider merging with prompb code.
 func promTypeToM3(labelType labels.MatchType) (models.MatchType, error) {
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	xclock "github.com/m3db/m3/src/x/clock"
+	xtime "github.com/m3db/m3/src/x/time"
 )
 
 // ParseFunctionExpr parses arguments to a function expression, returning
@@ -87,6 +88,12 @@ type ParseOptions interface {
 	RequireStartEndTime() bool
 	// SetRequireStartEndTime sets whether requests require a start and end time.
 	SetRequireStartEndTime(bool) ParseOptions
+
+	// QueryBounds returns the start and end of the query, which the
+	// `@ start()` and `@ end()` modifiers resolve to.
+	QueryBounds() (start xtime.UnixNano, end xtime.UnixNano)
+	// SetQueryBounds sets the start and end of the query.
+	SetQueryBounds(start xtime.UnixNano, end xtime.UnixNano) ParseOptions
 }
 
 type parseOptions struct {
@@ -95,6 +102,8 @@ type parseOptions struct {
 	fnParseExpr         ParseFunctionExpr
 	nowFn               xclock.NowFn
 	requireStartEndTime bool
+	queryStart          xtime.UnixNano
+	queryEnd            xtime.UnixNano
 }
 
 // NewParseOptions creates a new parse options.
@@ -156,3 +165,14 @@ func (o *parseOptions) SetRequireStartEndTime(r bool) ParseOptions {
 	opts.requireStartEndTime = r
 	return &opts
 }
+
+func (o *parseOptions) QueryBounds() (xtime.UnixNano, xtime.UnixNano) {
+	return o.queryStart, o.queryEnd
+}
+
+func (o *parseOptions) SetQueryBounds(start, end xtime.UnixNano) ParseOptions {
+	opts := *o
+	opts.queryStart = start
+	opts.queryEnd = end
+	return &opts
+}
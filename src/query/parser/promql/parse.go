@@ -27,14 +27,22 @@ import (
 	pql "github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/functions"
+	"github.com/m3db/m3/src/query/functions/aggregation"
 	"github.com/m3db/m3/src/query/functions/binary"
 	"github.com/m3db/m3/src/query/functions/lazy"
 	"github.com/m3db/m3/src/query/functions/scalar"
+	"github.com/m3db/m3/src/query/functions/temporal"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 	xtime "github.com/m3db/m3/src/x/time"
 )
 
+// defaultSubqueryStep is the step used to evaluate subqueries which do not
+// specify an explicit resolution, e.g. `rate(foo[5m])[1h:]`, matching the
+// default Prometheus global evaluation interval.
+const defaultSubqueryStep = time.Minute
+
 type promParser struct {
 	stepSize          time.Duration
 	expr              pql.Expr
@@ -55,6 +63,8 @@ func Parse(
 		return nil, err
 	}
 
+	queryStart, queryEnd := parseOptions.QueryBounds()
+	resolveStartEnd(expr, queryStart, queryEnd)
 	return &promParser{
 		expr:              expr,
 		stepSize:          stepSize,
@@ -83,6 +93,9 @@ func (p *promParser) String() string {
 }
 
 type parseState struct {
+	// stepInvariant is set while parsing an expression whose selectors all
+	// share the same `@` modifier.
+	stepInvariant     bool
 	stepSize          time.Duration
 	edges             parser.Edges
 	transforms        parser.Nodes
@@ -126,29 +139,23 @@ func (p *parseState) addLazyUnaryTransform(unaryOp string) error {
 	return nil
 }
 
-func (p *parseState) addLazyOffsetTransform(offset time.Duration) error {
-	// NB: if offset is <= 0, we do not apply any offsets.
-	if offset == 0 {
-		return nil
-	} else if offset < 0 {
-		return fmt.Errorf("offset must be positive, received: %v", offset)
-	}
-
-	var (
-		tt = func(t xtime.UnixNano) xtime.UnixNano { return t.Add(offset) }
-		mt = func(meta block.Metadata) block.Metadata {
-			meta.Bounds.Start = meta.Bounds.Start.Add(offset)
-			return meta
+func (p *parseState) addLazyOffsetTransform(
+	offset time.Duration,
+	at *xtime.UnixNano,
+) error {
+	var op parser.Params
+	switch {
+	case at != nil:
+		op = lazy.NewAtOp(*at, offset)
+	case offset != 0:
+		var err error
+		op, err = lazy.NewLazyOp(lazy.OffsetType, lazy.NewOffsetOptions(offset))
+		if err != nil {
+			return err
 		}
-	)
-
-	lazyOpts := block.NewLazyOptions().
-		SetTimeTransform(tt).
-		SetMetaTransform(mt)
-
-	op, err := lazy.NewLazyOp(lazy.OffsetType, lazyOpts)
-	if err != nil {
-		return err
+	default:
+		// NB: if offset is 0, we do not apply any offsets.
+		return nil
 	}
 
 	opTransform := parser.NewTransformFromOperation(op, p.transformLen())
@@ -162,6 +169,12 @@ func (p *parseState) addLazyOffsetTransform(offset time.Duration) error {
 }
 
 func adjustOffset(offset time.Duration, step time.Duration) time.Duration {
+	// NB: negative offsets are rounded away from zero, in the same way as
+	// positive offsets.
+	if offset < 0 {
+		return -1 * adjustOffset(-1*offset, step)
+	}
+
 	// handles case where offset is 0 too.
 	align := offset % step
 	if align == 0 {
@@ -173,11 +186,83 @@ func adjustOffset(offset time.Duration, step time.Duration) time.Duration {
 	return offset + step - align
 }
 
+// addSubquery compiles the inner expression of a subquery into its own DAG,
+// evaluated at the subquery step, and adds it to the current DAG as a source.
+func (p *parseState) addSubquery(n *pql.SubqueryExpr) error {
+	step := n.Step
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+
+	inner := &parseState{
+		stepSize:          step,
+		tagOpts:           p.tagOpts,
+		parseFunctionExpr: p.parseFunctionExpr,
+	}
+
+	if err := inner.walk(n.Expr); err != nil {
+		return err
+	}
+
+	if len(inner.transforms) == 0 {
+		return fmt.Errorf("subquery has no inner expression: %v", n)
+	}
+
+	op := functions.SubqueryOp{
+		Range:  n.Range,
+		Step:   step,
+		Offset: adjustOffset(n.OriginalOffset, p.stepSize),
+		At:     atTime(n.Timestamp),
+		Nodes:  inner.transforms,
+		Edges:  inner.edges,
+	}
+
+	p.transforms = append(
+		p.transforms,
+		parser.NewTransformFromOperation(op, p.transformLen()),
+	)
+
+	return nil
+}
+
+// addStepInvariant adds an expression which has the same result at every
+// step, given by the `@` modifier of its selectors. The selectors offset the
+// final step to the modifier time, and its result is repeated at each step.
+func (p *parseState) addStepInvariant(expr pql.Expr) error {
+	if expr.Type() == pql.ValueTypeMatrix {
+		return fmt.Errorf("@ modifier on a range vector is only supported "+
+			"as an argument to a function, received: %v", expr)
+	}
+
+	p.stepInvariant = true
+	err := p.walk(expr)
+	p.stepInvariant = false
+	if err != nil {
+		return err
+	}
+
+	opTransform := parser.NewTransformFromOperation(functions.StepInvariantOp{},
+		p.transformLen())
+	p.edges = append(p.edges, parser.Edge{
+		ParentID: p.lastTransformID(),
+		ChildID:  opTransform.ID,
+	})
+	p.transforms = append(p.transforms, opTransform)
+
+	return nil
+}
+
 func (p *parseState) walk(node pql.Node) error {
 	if node == nil {
 		return nil
 	}
 
+	if expr, ok := node.(pql.Expr); ok && !p.stepInvariant {
+		if _, state := exprAt(expr); state == atFixed {
+			return p.addStepInvariant(expr)
+		}
+	}
+
 	switch n := node.(type) {
 	case *pql.AggregateExpr:
 		err := p.walk(n.Expr)
@@ -212,7 +297,8 @@ func (p *parseState) walk(node pql.Node) error {
 			p.transforms,
 			parser.NewTransformFromOperation(operation, p.transformLen()),
 		)
-		return p.addLazyOffsetTransform(vectorSelector.OriginalOffset)
+		return p.addLazyOffsetTransform(vectorSelector.OriginalOffset,
+			atTime(vectorSelector.Timestamp))
 
 	case *pql.VectorSelector:
 		// Align offset to stepSize.
@@ -227,7 +313,11 @@ func (p *parseState) walk(node pql.Node) error {
 			parser.NewTransformFromOperation(operation, p.transformLen()),
 		)
 
-		return p.addLazyOffsetTransform(n.OriginalOffset)
+		return p.addLazyOffsetTransform(n.OriginalOffset,
+			atTime(n.Timestamp))
+
+	case *pql.SubqueryExpr:
+		return p.addSubquery(n)
 
 	case *pql.Call:
 		if n.Func.Name == scalar.VectorType {
@@ -301,7 +391,10 @@ func (p *parseState) walk(node pql.Node) error {
 			} else if argType == pql.ValueTypeString {
 				stringValues = append(stringValues, expr.(*pql.StringLiteral).Val)
 			} else {
-				if e, ok := expr.(*pql.MatrixSelector); ok {
+				switch e := expr.(type) {
+				case *pql.MatrixSelector:
+					argValues = append(argValues, e.Range)
+				case *pql.SubqueryExpr:
 					argValues = append(argValues, e.Range)
 				}
 
@@ -347,6 +440,21 @@ func (p *parseState) walk(node pql.Node) error {
 		}
 
 		p.transforms = append(p.transforms, opTransform)
+
+		// NB: absent_over_time is computed per series as present_over_time, then
+		// absent is applied across every series, matching Prometheus semantics
+		// where the result is 1 only if no series has any values in range.
+		if op.OpType() == temporal.AbsentType {
+			absentTransform := parser.NewTransformFromOperation(
+				aggregation.NewAbsentOpWithTags(absentTags(n.Args[0], p.tagOpts)),
+				p.transformLen())
+			p.edges = append(p.edges, parser.Edge{
+				ParentID: opTransform.ID,
+				ChildID:  absentTransform.ID,
+			})
+			p.transforms = append(p.transforms, absentTransform)
+		}
+
 		return nil
 
 	case *pql.BinaryExpr:
@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/query/functions/temporal"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
+	xtime "github.com/m3db/m3/src/x/time"
 
 	pql "github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/assert"
@@ -77,10 +78,166 @@ func TestDAGWithOffset(t *testing.T) {
 		"offset should be the child")
 }
 
-func TestInvalidOffset(t *testing.T) {
+func TestNegativeOffset(t *testing.T) {
 	q := "up offset -2m"
-	_, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
 	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+	assert.Equal(t, functions.FetchType, transforms[0].Op.OpType())
+	assert.Equal(t, lazy.OffsetType, transforms[1].Op.OpType())
+	require.Len(t, edges, 1)
+
+	fetch, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, -2*time.Minute, fetch.Offset)
+}
+
+func TestAdjustOffset(t *testing.T) {
+	tests := []struct {
+		offset   time.Duration
+		expected time.Duration
+	}{
+		{0, 0},
+		{time.Minute, time.Minute},
+		{61 * time.Second, 2 * time.Minute},
+		{-1 * time.Minute, -1 * time.Minute},
+		{-61 * time.Second, -2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.offset.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, adjustOffset(tt.offset, time.Minute))
+		})
+	}
+}
+
+func TestAtModifierParses(t *testing.T) {
+	var (
+		at100 = xtime.UnixNano(100 * time.Second)
+		start = xtime.UnixNano(50 * time.Second)
+		end   = xtime.UnixNano(150 * time.Second)
+	)
+
+	tests := []struct {
+		q      string
+		at     xtime.UnixNano
+		offset time.Duration
+		types  []string
+	}{
+		{
+			q:  "up @ 100",
+			at: at100,
+			types: []string{functions.FetchType, lazy.AtType,
+				functions.StepInvariantType},
+		},
+		{
+			q:      "up @ 100 offset 1m",
+			at:     at100,
+			offset: time.Minute,
+			types: []string{functions.FetchType, lazy.AtType,
+				functions.StepInvariantType},
+		},
+		{
+			q:  "rate(up[5m] @ start())",
+			at: start,
+			types: []string{functions.FetchType, lazy.AtType,
+				temporal.RateType, functions.StepInvariantType},
+		},
+		{
+			q:  "sum(up @ end()) by (foo) * 2",
+			at: end,
+			types: []string{functions.FetchType, lazy.AtType,
+				aggregation.SumType, scalar.ScalarType, binary.MultiplyType,
+				functions.StepInvariantType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			p, err := Parse(tt.q, time.Second, models.NewTagOptions(),
+				NewParseOptions().SetQueryBounds(start, end))
+			require.NoError(t, err)
+			transforms, _, err := p.DAG()
+			require.NoError(t, err)
+
+			types := make([]string, 0, len(transforms))
+			for _, transform := range transforms {
+				types = append(types, transform.Op.OpType())
+			}
+
+			assert.Equal(t, tt.types, types)
+
+			fetch, ok := transforms[0].Op.(functions.FetchOp)
+			require.True(t, ok)
+			require.NotNil(t, fetch.At)
+			assert.Equal(t, tt.at, *fetch.At)
+			assert.Equal(t, tt.offset, fetch.Offset)
+		})
+	}
+}
+
+func TestAtModifierSubqueryParses(t *testing.T) {
+	q := "max_over_time(rate(up[5m])[1h:1m] @ 100)"
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+	assert.Len(t, edges, 2)
+	assert.Equal(t, temporal.MaxType, transforms[1].Op.OpType())
+	assert.Equal(t, functions.StepInvariantType, transforms[2].Op.OpType())
+
+	subquery, ok := transforms[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	require.NotNil(t, subquery.At)
+	assert.Equal(t, xtime.UnixNano(100*time.Second), *subquery.At)
+}
+
+func TestAtModifierMixedParses(t *testing.T) {
+	q := "up @ 100 - up @ 200 + up"
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+
+	types := make([]string, 0, len(transforms))
+	for _, transform := range transforms {
+		types = append(types, transform.Op.OpType())
+	}
+
+	assert.Equal(t, []string{
+		functions.FetchType, lazy.AtType, functions.StepInvariantType,
+		functions.FetchType, lazy.AtType, functions.StepInvariantType,
+		binary.MinusType, functions.FetchType, binary.PlusType,
+	}, types)
+	assert.Len(t, edges, 8)
+
+	first, ok := transforms[0].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, xtime.UnixNano(100*time.Second), *first.At)
+	second, ok := transforms[3].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Equal(t, xtime.UnixNano(200*time.Second), *second.At)
+	last, ok := transforms[7].Op.(functions.FetchOp)
+	require.True(t, ok)
+	assert.Nil(t, last.At)
+}
+
+func TestAtModifierOnRangeVectorErrors(t *testing.T) {
+	for _, q := range []string{
+		"up[5m] @ 100",
+		"predict_linear(up[5m] @ 100, 60)",
+	} {
+		t.Run(q, func(t *testing.T) {
+			p, err := Parse(q, time.Second, models.NewTagOptions(),
+				NewParseOptions())
+			require.NoError(t, err)
+			_, _, err = p.DAG()
+			require.Error(t, err)
+		})
+	}
 }
 
 func TestNegativeUnary(t *testing.T) {
@@ -149,6 +306,7 @@ var aggregateParseTests = []struct {
 	{"stddev(up)", aggregation.StandardDeviationType},
 	{"stdvar(up)", aggregation.StandardVarianceType},
 	{"count(up)", aggregation.CountType},
+	{"group(up)", aggregation.GroupType},
 
 	{"topk(3, up)", aggregation.TopKType},
 	{"bottomk(3, up)", aggregation.BottomKType},
@@ -221,6 +379,21 @@ var linearParseTests = []struct {
 	{"log2(up)", linear.Log2Type},
 	{"log10(up)", linear.Log10Type},
 	{"sqrt(up)", linear.SqrtType},
+	{"sgn(up)", linear.SgnType},
+	{"acos(up)", linear.AcosType},
+	{"acosh(up)", linear.AcoshType},
+	{"asin(up)", linear.AsinType},
+	{"asinh(up)", linear.AsinhType},
+	{"atan(up)", linear.AtanType},
+	{"atanh(up)", linear.AtanhType},
+	{"cos(up)", linear.CosType},
+	{"cosh(up)", linear.CoshType},
+	{"sin(up)", linear.SinType},
+	{"sinh(up)", linear.SinhType},
+	{"tan(up)", linear.TanType},
+	{"tanh(up)", linear.TanhType},
+	{"deg(up)", linear.DegType},
+	{"rad(up)", linear.RadType},
 	{"round(up)", linear.RoundType},
 	{"round(up, 10)", linear.RoundType},
 
@@ -470,6 +643,8 @@ var temporalParseTests = []struct {
 	{"stddev_over_time(up[5m])", temporal.StdDevType},
 	{"stdvar_over_time(up[5m])", temporal.StdVarType},
 	{"quantile_over_time(0.2, up[5m])", temporal.QuantileType},
+	{"last_over_time(up[5m])", temporal.LastType},
+	{"present_over_time(up[5m])", temporal.PresentType},
 	{"irate(up[5m])", temporal.IRateType},
 	{"idelta(up[5m])", temporal.IDeltaType},
 	{"rate(up[5m])", temporal.RateType},
@@ -502,6 +677,53 @@ func TestTemporalParses(t *testing.T) {
 	}
 }
 
+func TestAbsentOverTimeParses(t *testing.T) {
+	p, err := Parse("absent_over_time(up[5m])", time.Second,
+		models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+	assert.Equal(t, functions.FetchType, transforms[0].Op.OpType())
+	assert.Equal(t, temporal.AbsentType, transforms[1].Op.OpType())
+	assert.Equal(t, aggregation.AbsentType, transforms[2].Op.OpType())
+	require.Len(t, edges, 2)
+	assert.Equal(t, parser.NodeID("0"), edges[0].ParentID)
+	assert.Equal(t, parser.NodeID("1"), edges[0].ChildID)
+	assert.Equal(t, parser.NodeID("1"), edges[1].ParentID)
+	assert.Equal(t, parser.NodeID("2"), edges[1].ChildID)
+}
+
+func TestAbsentOverTimeTags(t *testing.T) {
+	tests := []struct {
+		q        string
+		expected string
+	}{
+		{`absent_over_time(up[5m])`, ``},
+		{`absent_over_time(up{handler="/foo"}[5m])`, `handler: /foo`},
+		{`absent_over_time(up{handler!="/foo"}[5m])`, ``},
+		{
+			`absent_over_time(up{handler="/foo", handler="/bar", instance="1"}[5m])`,
+			`instance: 1`,
+		},
+		{`absent_over_time(rate(up[5m])[5m:])`, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			expr, err := pql.ParseExpr(tt.q)
+			require.NoError(t, err)
+
+			call, ok := expr.(*pql.Call)
+			require.True(t, ok)
+
+			tags := absentTags(call.Args[0], models.NewTagOptions())
+			assert.Equal(t, tt.expected, tags.String())
+		})
+	}
+}
+
 var tagParseTests = []struct {
 	q            string
 	expectedType string
@@ -530,6 +752,68 @@ func TestTagParses(t *testing.T) {
 	}
 }
 
+func TestSubqueryParses(t *testing.T) {
+	q := "max_over_time(rate(http_requests_total[5m])[1h:1m] offset 2m)"
+	p, err := Parse(q, time.Minute, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, edges, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+	assert.Equal(t, functions.SubqueryType, transforms[0].Op.OpType())
+	assert.Equal(t, parser.NodeID("0"), transforms[0].ID)
+	assert.Equal(t, temporal.MaxType, transforms[1].Op.OpType())
+	assert.Equal(t, parser.NodeID("1"), transforms[1].ID)
+	require.Len(t, edges, 1)
+	assert.Equal(t, parser.NodeID("0"), edges[0].ParentID)
+	assert.Equal(t, parser.NodeID("1"), edges[0].ChildID)
+
+	op, ok := transforms[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	assert.Equal(t, time.Hour, op.Range)
+	assert.Equal(t, time.Minute, op.Step)
+	assert.Equal(t, 2*time.Minute, op.Offset)
+	assert.Equal(t, time.Hour, op.Bounds().Range)
+
+	require.Len(t, op.Nodes, 2)
+	assert.Equal(t, functions.FetchType, op.Nodes[0].Op.OpType())
+	assert.Equal(t, temporal.RateType, op.Nodes[1].Op.OpType())
+	require.Len(t, op.Edges, 1)
+	assert.Equal(t, op.Nodes[0].ID, op.Edges[0].ParentID)
+	assert.Equal(t, op.Nodes[1].ID, op.Edges[0].ChildID)
+}
+
+func TestSubqueryDefaultStep(t *testing.T) {
+	q := "avg_over_time(up[10m:])"
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+
+	op, ok := transforms[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	assert.Equal(t, defaultSubqueryStep, op.Step)
+	require.Len(t, op.Nodes, 1)
+	assert.Equal(t, functions.FetchType, op.Nodes[0].Op.OpType())
+}
+
+func TestNestedSubqueryParses(t *testing.T) {
+	q := "max_over_time(deriv(rate(up[1m])[5m:1m])[10m:])"
+	p, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
+	require.NoError(t, err)
+	transforms, _, err := p.DAG()
+	require.NoError(t, err)
+	require.Len(t, transforms, 2)
+
+	outer, ok := transforms[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	require.Len(t, outer.Nodes, 2)
+	inner, ok := outer.Nodes[0].Op.(functions.SubqueryOp)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Minute, inner.Range)
+	assert.Equal(t, temporal.DerivType, outer.Nodes[1].Op.OpType())
+}
+
 func TestFailedTemporalParse(t *testing.T) {
 	q := "unknown_over_time(http_requests_total[5m])"
 	_, err := Parse(q, time.Second, models.NewTagOptions(), NewParseOptions())
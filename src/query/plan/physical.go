@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/query/executor/transform"
+	"github.com/m3db/m3/src/query/functions"
+	"github.com/m3db/m3/src/query/functions/aggregation"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser"
 )
@@ -40,6 +42,15 @@ type PhysicalPlan struct {
 	LookbackDuration time.Duration
 }
 
+// PhysicalPlanOptions are the options used to generate a physical plan.
+type PhysicalPlanOptions struct {
+	// CountSeriesFromIndex rewrites instant ungrouped counts and absents of a
+	// selector to count the matching series from the index. Series without
+	// datapoints within the lookback window are still counted, so this is
+	// disabled by default.
+	CountSeriesFromIndex bool
+}
+
 // ResultOp is responsible for delivering results to the clients.
 type ResultOp struct {
 	Parent parser.NodeID
@@ -52,6 +63,7 @@ type ResultOp struct {
 func NewPhysicalPlan(
 	lp LogicalPlan,
 	params models.RequestParams,
+	opts PhysicalPlanOptions,
 ) (PhysicalPlan, error) {
 	if params.Step <= 0 {
 		return PhysicalPlan{}, fmt.Errorf("expected non-zero step size, got %d",
@@ -75,6 +87,12 @@ func NewPhysicalPlan(
 		LookbackDuration: params.LookbackDuration,
 	}
 
+	if opts.CountSeriesFromIndex && params.Start == params.End {
+		// NB: only instant queries are rewritten since the count is taken over
+		// the whole query range rather than at each step.
+		p = p.countSeriesFromIndex()
+	}
+
 	pl, err := p.createResultNode()
 	if err != nil {
 		return PhysicalPlan{}, err
@@ -121,6 +139,66 @@ func (p PhysicalPlan) shiftTime() PhysicalPlan {
 	return p
 }
 
+// countSeriesFromIndex replaces an ungrouped count or absent of a selector
+// with a single step which counts the matching series from the index,
+// avoiding fetching the data of every matching series.
+func (p PhysicalPlan) countSeriesFromIndex() PhysicalPlan {
+	for _, transformID := range p.pipeline {
+		step, ok := p.steps[transformID]
+		if !ok || len(step.Parents) != 1 {
+			continue
+		}
+
+		aggOp, ok := step.Transform.Op.(transform.Params)
+		if !ok {
+			continue
+		}
+
+		absent := aggOp.OpType() == aggregation.AbsentType
+		if !absent && !aggregation.IsUngroupedCount(aggOp) {
+			continue
+		}
+
+		parent, ok := p.steps[step.Parents[0]]
+		if !ok || len(parent.Children) != 1 {
+			continue
+		}
+
+		fetchOp, ok := parent.Transform.Op.(functions.FetchOp)
+		if !ok || fetchOp.Range != 0 || fetchOp.At != nil {
+			continue
+		}
+
+		step.Parents = nil
+		step.Transform = parser.Node{
+			ID: step.ID(),
+			Op: functions.CountSeriesOp{
+				Fetch:       fetchOp,
+				Aggregation: aggOp,
+				Absent:      absent,
+			},
+		}
+
+		p.steps[step.ID()] = step
+		delete(p.steps, parent.ID())
+		p.pipeline = removeStep(p.pipeline, parent.ID())
+		return p.countSeriesFromIndex()
+	}
+
+	return p
+}
+
+func removeStep(pipeline []parser.NodeID, ID parser.NodeID) []parser.NodeID {
+	updated := make([]parser.NodeID, 0, len(pipeline))
+	for _, transformID := range pipeline {
+		if transformID != ID {
+			updated = append(updated, transformID)
+		}
+	}
+
+	return updated
+}
+
 func (p PhysicalPlan) createResultNode() (PhysicalPlan, error) {
 	leaf, err := p.leafNode()
 	if err != nil {
@@ -56,7 +56,7 @@ func TestResultNode(t *testing.T) {
 
 	lp, err := NewLogicalPlan(transforms, edges)
 	require.NoError(t, err)
-	p, err := NewPhysicalPlan(lp, testRequestParams())
+	p, err := NewPhysicalPlan(lp, testRequestParams(), PhysicalPlanOptions{})
 	require.NoError(t, err)
 	node, err := p.leafNode()
 	require.NoError(t, err)
@@ -133,9 +133,122 @@ func TestShiftTime(t *testing.T) {
 				Step:             tt.step,
 			}
 
-			p, err := NewPhysicalPlan(lp, params)
+			p, err := NewPhysicalPlan(lp, params, PhysicalPlanOptions{})
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantShiftBy.String(), params.Start.Sub(p.TimeSpec.Start).String(), "start time shifted by")
 		})
 	}
 }
+
+func TestCountSeriesFromIndex(t *testing.T) {
+	count, err := aggregation.NewAggregationOp(aggregation.CountType,
+		aggregation.NodeParams{})
+	require.NoError(t, err)
+	countBy, err := aggregation.NewAggregationOp(aggregation.CountType,
+		aggregation.NodeParams{MatchingTags: [][]byte{[]byte("a")}})
+	require.NoError(t, err)
+	sum, err := aggregation.NewAggregationOp(aggregation.SumType,
+		aggregation.NodeParams{})
+	require.NoError(t, err)
+
+	rangeParams := testRequestParams()
+	rangeParams.End = rangeParams.Start.Add(time.Hour)
+	at := rangeParams.Start
+
+	tests := []struct {
+		name        string
+		fetchOp     functions.FetchOp
+		op          parser.Params
+		params      models.RequestParams
+		disabled    bool
+		wantRewrite bool
+		wantAbsent  bool
+	}{
+		{
+			name:        "count",
+			op:          count,
+			params:      testRequestParams(),
+			wantRewrite: true,
+		},
+		{
+			name:        "absent",
+			op:          aggregation.NewAbsentOp(),
+			params:      testRequestParams(),
+			wantRewrite: true,
+			wantAbsent:  true,
+		},
+		{
+			name:   "count by",
+			op:     countBy,
+			params: testRequestParams(),
+		},
+		{
+			name:   "sum",
+			op:     sum,
+			params: testRequestParams(),
+		},
+		{
+			name:    "count of range selector",
+			fetchOp: functions.FetchOp{Range: time.Minute},
+			op:      count,
+			params:  testRequestParams(),
+		},
+		{
+			name:   "range query",
+			op:     count,
+			params: rangeParams,
+		},
+		{
+			name:    "count with @ modifier",
+			fetchOp: functions.FetchOp{At: &at},
+			op:      count,
+			params:  testRequestParams(),
+		},
+		{
+			name:     "count when disabled",
+			op:       count,
+			params:   testRequestParams(),
+			disabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetchTransform := parser.NewTransformFromOperation(tt.fetchOp, 1)
+			opTransform := parser.NewTransformFromOperation(tt.op, 2)
+			transforms := parser.Nodes{fetchTransform, opTransform}
+			edges := parser.Edges{
+				parser.Edge{
+					ParentID: fetchTransform.ID,
+					ChildID:  opTransform.ID,
+				},
+			}
+
+			lp, err := NewLogicalPlan(transforms, edges)
+			require.NoError(t, err)
+			p, err := NewPhysicalPlan(lp, tt.params, PhysicalPlanOptions{
+				CountSeriesFromIndex: !tt.disabled,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, opTransform.ID, p.ResultStep.Parent)
+
+			step, ok := p.Step(opTransform.ID)
+			require.True(t, ok)
+			_, hasFetch := p.Step(fetchTransform.ID)
+			if !tt.wantRewrite {
+				assert.True(t, hasFetch)
+				assert.Equal(t, tt.op.OpType(), step.Transform.Op.OpType())
+				return
+			}
+
+			assert.False(t, hasFetch)
+			assert.Equal(t, []parser.NodeID{opTransform.ID}, p.pipeline)
+			assert.Empty(t, step.Parents)
+			op, ok := step.Transform.Op.(functions.CountSeriesOp)
+			require.True(t, ok)
+			assert.Equal(t, tt.fetchOp, op.Fetch)
+			assert.Equal(t, tt.op.OpType(), op.Aggregation.OpType())
+			assert.Equal(t, tt.wantAbsent, op.Absent)
+		})
+	}
+}
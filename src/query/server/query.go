@@ -42,11 +42,13 @@ import (
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/api/v1/handler/otlp"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/handleroptions"
 	"github.com/m3db/m3/src/query/api/v1/httpd"
 	"github.com/m3db/m3/src/query/api/v1/options"
 	m3dbcluster "github.com/m3db/m3/src/query/cluster/m3db"
 	"github.com/m3db/m3/src/query/executor"
+	"github.com/m3db/m3/src/query/generated/proto/otlppb"
 	graphite "github.com/m3db/m3/src/query/graphite/storage"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/parser/promql"
@@ -460,6 +462,7 @@ func Run(runOpts RunOptions) RunResult {
 	engineOpts := executor.NewEngineOptions().
 		SetStore(backendStorage).
 		SetLookbackDuration(*cfg.LookbackDuration).
+		SetCountSeriesFromIndex(cfg.Query.CountSeriesFromIndex).
 		SetInstrumentOptions(instrumentOptions.
 			SetMetricsScope(instrumentOptions.MetricsScope().SubScope("engine")))
 	if fn := runOpts.CustomPromQLParseFunction; fn != nil {
@@ -652,6 +655,14 @@ func Run(runOpts RunOptions) RunResult {
 		defer server.Close()
 	}
 
+	if cfg.OTLP.GRPC != nil {
+		server, err := startOTLPGRPCServer(*cfg.OTLP.GRPC, handler.OTLPReceiver(), logger)
+		if err != nil {
+			logger.Fatal("unable to start otlp grpc server", zap.Error(err))
+		}
+		defer server.GracefulStop()
+	}
+
 	// Wait for process interrupt.
 	xos.WaitForInterrupt(logger, xos.InterruptOptions{
 		InterruptCh: runOpts.InterruptCh,
@@ -1151,6 +1162,28 @@ func startGRPCServer(
 	return server, nil
 }
 
+func startOTLPGRPCServer(
+	cfg config.OTLPGRPCConfiguration,
+	receiver *otlp.Receiver,
+	logger *zap.Logger,
+) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", cfg.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	server := grpc.NewServer()
+	otlppb.RegisterMetricsServiceServer(server, receiver)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			logger.Error("error from serving otlp grpc server", zap.Error(err))
+		}
+	}()
+
+	logger.Info("started otlp grpc server", zap.Stringer("addr", listener.Addr()))
+	return server, nil
+}
+
 func startCarbonIngestion(
 	ingesterCfg config.CarbonIngesterConfiguration,
 	listenerOpts xnet.ListenerOptions,
@@ -1264,6 +1297,10 @@ func newPromQLEngine(
 			MaxSamples:    cfg.Query.Prometheus.MaxSamplesPerQueryOrDefault(),
 			Timeout:       cfg.Query.TimeoutOrDefault(),
 			LookbackDelta: lookbackDelta,
+			// NB: the native engine supports both of these, so the
+			// Prometheus engine is kept consistent with it.
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
 			NoStepSubqueryIntervalFn: func(rangeMillis int64) int64 {
 				return durationMilliseconds(1 * time.Minute)
 			},
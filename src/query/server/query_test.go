@@ -295,7 +295,7 @@ func testWrite(t *testing.T, cfg config.Configuration, ctrl *gomock.Controller)
 
 	session := client.NewMockSession(ctrl)
 	for _, value := range []float64{1, 2} {
-		session.EXPECT().WriteTagged(ident.NewIDMatcher("prometheus_metrics"),
+		session.EXPECT().WriteTaggedWithContext(gomock.Any(), ident.NewIDMatcher("prometheus_metrics"),
 			ident.NewIDMatcher(`{_new="first",biz="baz",foo="bar"}`),
 			gomock.Any(),
 			gomock.Any(),
@@ -304,7 +304,7 @@ func testWrite(t *testing.T, cfg config.Configuration, ctrl *gomock.Controller)
 			nil)
 	}
 	for _, value := range []float64{3, 4} {
-		session.EXPECT().WriteTagged(ident.NewIDMatcher("prometheus_metrics"),
+		session.EXPECT().WriteTaggedWithContext(gomock.Any(), ident.NewIDMatcher("prometheus_metrics"),
 			ident.NewIDMatcher(`{_new="second",bar="baz",foo="qux"}`),
 			gomock.Any(),
 			gomock.Any(),
@@ -398,14 +398,14 @@ func testIngest(t *testing.T, cfg config.Configuration, ctrl *gomock.Controller)
 
 	session := client.NewMockSession(ctrl)
 	session.EXPECT().
-		WriteTagged(ident.NewIDMatcher("prometheus_metrics_1m_aggregated"),
+		WriteTaggedWithContext(gomock.Any(), ident.NewIDMatcher("prometheus_metrics_1m_aggregated"),
 			ident.NewIDMatcher(`{_new="first",biz="baz",foo="bar"}`),
 			gomock.Any(),
 			gomock.Any(),
 			42.0,
 			gomock.Any(),
 			nil).
-		Do(func(_, _, _, _, _, _, _ interface{}) {
+		Do(func(_, _, _, _, _, _, _, _ interface{}) {
 			numWrites.Add(1)
 		})
 	session.EXPECT().Close().AnyTimes()
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
@@ -200,6 +201,31 @@ func PromSamplesToM3Datapoints(samples []prompb.Sample) ts.Datapoints {
 	return datapoints
 }
 
+// PromExemplarsToM3 converts Prometheus exemplars to M3 exemplars.
+func PromExemplarsToM3(exemplars []prompb.Exemplar) []exemplar.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+
+	result := make([]exemplar.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		labels := make([]exemplar.Label, 0, len(e.Labels))
+		for _, label := range e.Labels {
+			labels = append(labels, exemplar.Label{
+				Name:  string(label.Name),
+				Value: string(label.Value),
+			})
+		}
+		result = append(result, exemplar.Exemplar{
+			Labels:    labels,
+			Value:     e.Value,
+			Timestamp: promTimestampToUnixNanos(e.Timestamp),
+		})
+	}
+
+	return result
+}
+
 // PromReadQueryToM3 converts a prometheus read query to m3 read query
 func PromReadQueryToM3(query *prompb.Query) (*FetchQuery, error) {
 	tagMatchers, err := PromMatchersToM3(query.Matchers)
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/ts"
@@ -68,6 +69,33 @@ func TestLabelConversion(t *testing.T) {
 	assert.Equal(t, labels, reverted)
 }
 
+func TestPromExemplarsToM3(t *testing.T) {
+	assert.Nil(t, PromExemplarsToM3(nil))
+
+	exemplars := PromExemplarsToM3([]prompb.Exemplar{
+		{
+			Labels: []prompb.Label{
+				{Name: []byte("trace_id"), Value: []byte("abc")},
+			},
+			Value:     1.5,
+			Timestamp: 1000,
+		},
+		{Value: 2, Timestamp: 2000},
+	})
+	assert.Equal(t, []exemplar.Exemplar{
+		{
+			Labels:    []exemplar.Label{{Name: "trace_id", Value: "abc"}},
+			Value:     1.5,
+			Timestamp: xtime.UnixNano(time.Second),
+		},
+		{
+			Labels:    []exemplar.Label{},
+			Value:     2,
+			Timestamp: xtime.UnixNano(2 * time.Second),
+		},
+	}, exemplars)
+}
+
 var (
 	name  = []byte("foo")
 	value = []byte("bar")
@@ -363,6 +363,26 @@ func (s *fanoutStorage) SearchSeries(
 	return result, nil
 }
 
+func (s *fanoutStorage) CountSeries(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (int64, error) {
+	// NB: the series of different stores may overlap, so they can only be
+	// counted from the index of a single store.
+	stores := filterStores(s.stores, s.fetchFilter, query)
+	if len(stores) != 1 {
+		return 0, storage.ErrSeriesCountUnsupported
+	}
+
+	counter, ok := stores[0].(storage.SeriesCounter)
+	if !ok {
+		return 0, storage.ErrSeriesCountUnsupported
+	}
+
+	return counter.CountSeries(ctx, query, options)
+}
+
 func (s *fanoutStorage) CompleteTags(
 	ctx context.Context,
 	query *storage.CompleteTagsQuery,
@@ -30,6 +30,7 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/block"
 	errs "github.com/m3db/m3/src/query/errors"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
@@ -135,7 +136,7 @@ func setupFanoutWrite(t *testing.T, output bool, errs ...error) storage.Storage
 	store1, session1 := m3.NewStorageAndSession(t, ctrl)
 	store2, session2 := m3.NewStorageAndSession(t, ctrl)
 	session1.EXPECT().
-		WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any()).Return(errs[0])
 	session1.EXPECT().IteratorPools().
 		Return(nil, nil).AnyTimes()
@@ -145,7 +146,7 @@ func setupFanoutWrite(t *testing.T, output bool, errs ...error) storage.Storage
 		Return(nil, client.FetchResponseMetadata{Exhaustive: true}, errs[0]).AnyTimes()
 
 	session2.EXPECT().
-		WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any()).Return(errs[len(errs)-1])
 	session2.EXPECT().IteratorPools().
 		Return(nil, nil).AnyTimes()
@@ -334,6 +335,40 @@ func TestCompleteTagsError(t *testing.T) {
 }
 
 // Error continuation tests below.
+func TestFanoutCountSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	store1, session1 := m3.NewStorageAndSession(t, ctrl)
+	session1.EXPECT().Count(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(index.CountResult{Count: 2}, nil)
+
+	store := NewStorage([]storage.Storage{store1}, filterFunc(true),
+		filterFunc(true), filterCompleteTagsFunc(true), models.NewTagOptions(),
+		instrument.NewOptions())
+	counter, ok := store.(storage.SeriesCounter)
+	require.True(t, ok)
+
+	count, err := counter.CountSeries(context.TODO(), &storage.FetchQuery{
+		Start: time.Now().Add(-time.Hour),
+		End:   time.Now(),
+	}, storage.NewFetchOptions())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// The series of several stores cannot be counted from their indexes.
+	store2, _ := m3.NewStorageAndSession(t, ctrl)
+	store = NewStorage([]storage.Storage{store1, store2}, filterFunc(true),
+		filterFunc(true), filterCompleteTagsFunc(true), models.NewTagOptions(),
+		instrument.NewOptions())
+	_, err = store.(storage.SeriesCounter).CountSeries(context.TODO(),
+		&storage.FetchQuery{
+			Start: time.Now().Add(-time.Hour),
+			End:   time.Now(),
+		}, storage.NewFetchOptions())
+	assert.Equal(t, storage.ErrSeriesCountUnsupported, err)
+}
+
 func TestFanoutSearchErrorContinues(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -22,10 +22,13 @@ package storage
 
 import (
 	"fmt"
+	"regexp/syntax"
 	"time"
+	"unicode"
 
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/query/graphite/graphite"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
 	xerrors "github.com/m3db/m3/src/x/errors"
@@ -290,7 +293,7 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 			err   error
 		)
 
-		query, err = idx.NewRegexpQuery(matcher.Name, matcher.Value)
+		query, err = regexpToQuery(matcher.Name, matcher.Value)
 		if err != nil {
 			return idx.Query{}, err
 		}
@@ -333,3 +336,64 @@ func matcherToQuery(matcher models.Matcher) (idx.Query, error) {
 		return idx.Query{}, fmt.Errorf("unsupported query type: %v", matcher)
 	}
 }
+
+// regexpToQuery returns the query for a regexp matcher, rewriting regexps
+// which are literals, case insensitive literals or literal prefixes as term,
+// case insensitive term and prefix queries which are much cheaper to evaluate.
+func regexpToQuery(name, value []byte) (idx.Query, error) {
+	parsed, err := syntax.Parse(string(value), syntax.Perl)
+	if err != nil {
+		// NB: return the error of the regexp query for consistency.
+		return idx.NewRegexpQuery(name, value)
+	}
+
+	switch parsed.Op {
+	case syntax.OpLiteral:
+		literal := []byte(string(parsed.Rune))
+		if parsed.Flags&syntax.FoldCase != 0 {
+			return idx.NewCaseInsensitiveTermQuery(name, literal), nil
+		}
+		return idx.NewTermQuery(name, literal), nil
+
+	case syntax.OpConcat:
+		// NB: a literal followed by `.*` is matched as a prefix, label values
+		// containing newlines, which `.` does not match, are practically
+		// nonexistent.
+		if len(parsed.Sub) != 2 {
+			break
+		}
+		literal, rest := parsed.Sub[0], parsed.Sub[1]
+		if literal.Op != syntax.OpLiteral || literal.Flags&syntax.FoldCase != 0 {
+			break
+		}
+		if rest.Op != syntax.OpStar || len(rest.Sub) != 1 {
+			break
+		}
+		if !matchesAnyChar(name, rest.Sub[0]) {
+			break
+		}
+		return idx.NewPrefixQuery(name, []byte(string(literal.Rune))), nil
+	}
+
+	return idx.NewRegexpQuery(name, value)
+}
+
+// matchesAnyChar returns true if the regexp matches any character which can
+// appear in values of the given tag. Graphite globs match any character but
+// the hierarchy separator with `[^\.]`, which never appears in values of
+// graphite tags.
+func matchesAnyChar(name []byte, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpCharClass:
+		if _, ok := graphite.TagIndex(name); !ok {
+			return false
+		}
+		return len(re.Rune) == 4 &&
+			re.Rune[0] == 0 && re.Rune[1] == '.'-1 &&
+			re.Rune[2] == '.'+1 && re.Rune[3] == unicode.MaxRune
+	}
+
+	return false
+}
@@ -103,7 +103,7 @@ func TestFetchQueryToM3Query(t *testing.T) {
 		},
 		{
 			name:     "regexp match",
-			expected: "regexp(t1, v1)",
+			expected: "term(t1, v1)",
 			matchers: models.Matchers{
 				{
 					Type:  models.MatchRegexp,
@@ -112,6 +112,83 @@ func TestFetchQueryToM3Query(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "regexp match dot plus",
+			expected: "regexp(t1, v1.+)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.+"),
+				},
+			},
+		},
+		{
+			name:     "regexp match literal dot star -> prefix",
+			expected: "prefix(t1, v1)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match escaped literal dot star -> prefix",
+			expected: "prefix(t1, v1.)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1\\..*"),
+				},
+			},
+		},
+		{
+			name:     "regexp match graphite glob -> prefix",
+			expected: "prefix(__g1__, v1)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("__g1__"),
+					Value: []byte(`v1[^\.]*`),
+				},
+			},
+		},
+		{
+			name:     "regexp match not dot star",
+			expected: `regexp(t1, v1[^\.]*)`,
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte(`v1[^\.]*`),
+				},
+			},
+		},
+		{
+			name:     "regexp match case insensitive literal -> case insensitive term",
+			expected: "case_insensitive_term(t1, V1)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("(?i)v1"),
+				},
+			},
+		},
+		{
+			name:     "regexp match case insensitive literal dot star",
+			expected: "regexp(t1, (?i)v1.*)",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("(?i)v1.*"),
+				},
+			},
+		},
 		{
 			name:     "regexp match dot star -> all",
 			expected: "all()",
@@ -136,7 +213,7 @@ func TestFetchQueryToM3Query(t *testing.T) {
 		},
 		{
 			name:     "regexp match negated",
-			expected: "negation(regexp(t1, v1))",
+			expected: "negation(term(t1, v1))",
 			matchers: models.Matchers{
 				{
 					Type:  models.MatchNotRegexp,
@@ -145,6 +222,28 @@ func TestFetchQueryToM3Query(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "regexp match negated dot plus",
+			expected: "negation(regexp(t1, v1.+))",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchNotRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.+"),
+				},
+			},
+		},
+		{
+			name:     "regexp match negated literal dot star -> prefix",
+			expected: "negation(prefix(t1, v1))",
+			matchers: models.Matchers{
+				{
+					Type:  models.MatchNotRegexp,
+					Name:  []byte("t1"),
+					Value: []byte("v1.*"),
+				},
+			},
+		},
 		{
 			name:     "regexp match negated",
 			expected: "negation(all())",
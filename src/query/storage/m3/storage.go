@@ -427,6 +427,81 @@ func (s *m3storage) SearchSeries(
 	}, nil
 }
 
+func (s *m3storage) CountSeries(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (int64, error) {
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	m3query, err := storage.FetchQueryToM3Query(query, options)
+	if err != nil {
+		return 0, err
+	}
+
+	m3opts, err := storage.FetchOptionsToM3Options(options, query)
+	if err != nil {
+		return 0, err
+	}
+
+	_, namespaces, err := resolveClusterNamespacesForQuery(
+		xtime.ToUnixNano(s.nowFn()),
+		m3opts.StartInclusive,
+		m3opts.EndExclusive,
+		s.clusters,
+		options.FanoutOptions,
+		options.RestrictQueryOptions,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if len(namespaces) == 0 {
+		return 0, errNoNamespacesConfigured
+	}
+	if len(namespaces) > 1 {
+		// NB: the same series are typically written to several namespaces and
+		// cannot be deduplicated exactly from their counts, so fall back to
+		// fetching them.
+		return 0, storage.ErrSeriesCountUnsupported
+	}
+
+	_, span, sampled := xcontext.StartSampledTraceSpan(ctx,
+		tracepoint.CountSeriesCount)
+	defer span.Finish()
+
+	var (
+		namespace   = namespaces[0]
+		namespaceID = namespace.NamespaceID()
+	)
+	result, err := namespace.Session().Count(namespaceID, m3query,
+		index.CountQueryOptions{
+			StartInclusive: m3opts.StartInclusive,
+			EndExclusive:   m3opts.EndExclusive,
+		})
+	if goerrors.Is(err, client.ErrNoAvailableReplicaForShard) {
+		// NB: series can only be counted from a single available replica of
+		// each shard, otherwise fall back to fetching them at the read
+		// consistency level.
+		return 0, storage.ErrSeriesCountUnsupported
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if sampled {
+		span.LogFields(
+			log.String("namespace", namespaceID.String()),
+			log.Int64("series", result.Count),
+		)
+	}
+	return result.Count, nil
+}
+
 // CompleteTagsCompressed has the same behavior as CompleteTags.
 func (s *m3storage) CompleteTagsCompressed(
 	ctx context.Context,
@@ -724,7 +799,11 @@ func (s *m3storage) Write(
 		// Special case single datapoint because it is common and we
 		// can avoid the overhead of a waitgroup, goroutine, multierr,
 		// iterator duplication etc.
-		return s.writeSingle(ctx, query, datapoints[0], id, tagIterator)
+		if err := s.writeSingle(ctx, query, datapoints[0], id, tagIterator); err != nil {
+			return err
+		}
+		s.writeExemplars(query, id)
+		return nil
 	}
 
 	var (
@@ -762,7 +841,34 @@ func (s *m3storage) Write(
 	}
 
 	wg.Wait()
-	return multiErr.lastError()
+	if err := multiErr.lastError(); err != nil {
+		return err
+	}
+	s.writeExemplars(query, id)
+	return nil
+}
+
+// writeExemplars writes the exemplars of a write query on a best effort
+// basis, failures are logged rather than failing the write since the
+// datapoints the exemplars annotate have already been written.
+func (s *m3storage) writeExemplars(query *storage.WriteQuery, id ident.ID) {
+	exemplars := query.Exemplars()
+	if len(exemplars) == 0 ||
+		query.Attributes().MetricsType != storagemetadata.UnaggregatedMetricsType {
+		return
+	}
+
+	namespace, exists := s.clusters.UnaggregatedClusterNamespace()
+	if !exists {
+		return
+	}
+
+	err := namespace.Session().WriteExemplars(namespace.NamespaceID(),
+		[]client.SeriesExemplars{{ID: id, Exemplars: exemplars}})
+	if err != nil {
+		s.logger.Warn("error writing exemplars",
+			zap.Stringer("id", id), zap.Error(err))
+	}
 }
 
 func (s *m3storage) Type() storage.Type {
@@ -814,6 +920,6 @@ func (s *m3storage) writeSingle(
 
 	namespaceID := namespace.NamespaceID()
 	session := namespace.Session()
-	return session.WriteTagged(namespaceID, identID, iterator,
+	return session.WriteTaggedWithContext(ctx, namespaceID, identID, iterator,
 		datapoint.Timestamp, datapoint.Value, query.Unit(), query.Annotation())
 }
@@ -22,6 +22,7 @@ package m3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -30,7 +31,10 @@ import (
 
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
+	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/source"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3/consolidators"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
@@ -190,7 +194,7 @@ func newWriteQuery(t *testing.T) *storage.WriteQuery {
 func setupLocalWrite(t *testing.T, ctrl *gomock.Controller) storage.Storage {
 	store, sessions := setup(t, ctrl)
 	session := sessions.unaggregated1MonthRetention
-	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(),
+	session.EXPECT().WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	return store
 }
@@ -253,6 +257,54 @@ func TestLocalWriteSuccess(t *testing.T) {
 	assert.NoError(t, store.Close())
 }
 
+func TestLocalWriteWithSource(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	ctx, err := source.NewContext(context.TODO(), []byte("foo"), nil)
+	require.NoError(t, err)
+
+	// The write is attributed to the source of the request by the session.
+	sessions.unaggregated1MonthRetention.EXPECT().
+		WriteTaggedWithContext(ctx, gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(len(newWriteQuery(t).Datapoints()))
+
+	require.NoError(t, store.Write(ctx, newWriteQuery(t)))
+}
+
+func TestLocalWriteExemplars(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+	session := sessions.unaggregated1MonthRetention
+	session.EXPECT().WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	opts := newWriteQuery(t).Options()
+	opts.Exemplars = []exemplar.Exemplar{
+		{
+			Labels:    []exemplar.Label{{Name: "trace_id", Value: "abc"}},
+			Value:     1,
+			Timestamp: xtime.Now(),
+		},
+	}
+	writeQuery, err := storage.NewWriteQuery(opts)
+	require.NoError(t, err)
+
+	session.EXPECT().WriteExemplars(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ ident.ID, series []client.SeriesExemplars) error {
+			require.Equal(t, 1, len(series))
+			assert.Equal(t, string(opts.Tags.ID()), series[0].ID.String())
+			assert.Equal(t, opts.Exemplars, series[0].Exemplars)
+			return fmt.Errorf("an error")
+		})
+
+	// NB: failing to write exemplars does not fail the write.
+	require.NoError(t, store.Write(context.TODO(), writeQuery))
+}
+
 func TestLocalWriteAggregatedNoClusterNamespaceError(t *testing.T) {
 	ctrl := xtest.NewController(t)
 	defer ctrl.Finish()
@@ -336,7 +388,7 @@ func TestLocalWriteAggregatedSuccess(t *testing.T) {
 	require.NoError(t, err)
 
 	session := sessions.aggregated1MonthRetention1MinuteResolution
-	session.EXPECT().WriteTagged(gomock.Any(), gomock.Any(), gomock.Any(),
+	session.EXPECT().WriteTaggedWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(len(writeQuery.Datapoints()))
 
 	err = store.Write(context.TODO(), writeQuery)
@@ -700,6 +752,60 @@ func TestLocalSearchSuccess(t *testing.T) {
 	}
 }
 
+func TestLocalCountSeries(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	sessions.unaggregated1MonthRetention.EXPECT().
+		Count(ident.NewIDMatcher("metrics_unaggregated"), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ ident.ID,
+			q index.Query,
+			opts index.CountQueryOptions,
+		) (index.CountResult, error) {
+			require.Equal(t, "conjunction(term(foo, bar), term(biz, baz))", q.String())
+			require.False(t, opts.IncludeSketch)
+			return index.CountResult{Count: 3}, nil
+		})
+
+	counter, ok := store.(storage.SeriesCounter)
+	require.True(t, ok)
+	count, err := counter.CountSeries(context.TODO(), newFetchReq(), buildFetchOpts())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+}
+
+func TestLocalCountSeriesNoAvailableReplica(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, sessions := setup(t, ctrl)
+
+	sessions.unaggregated1MonthRetention.EXPECT().
+		Count(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(index.CountResult{}, fmt.Errorf("%w: shard=0",
+			client.ErrNoAvailableReplicaForShard))
+
+	_, err := store.(storage.SeriesCounter).CountSeries(context.TODO(),
+		newFetchReq(), buildFetchOpts())
+	require.True(t, errors.Is(err, storage.ErrSeriesCountUnsupported))
+}
+
+func TestLocalCountSeriesMultipleNamespaces(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+	store, _ := setup(t, ctrl)
+
+	// Count between 1 month and 3 months ago to hit multiple aggregated
+	// namespaces, the series of which cannot be deduplicated from counts.
+	countReq := newFetchReq()
+	countReq.Start = time.Now().Add(-2 * test1MonthRetention)
+	countReq.End = time.Now()
+	_, err := store.(storage.SeriesCounter).CountSeries(context.TODO(),
+		countReq, buildFetchOpts())
+	require.True(t, errors.Is(err, storage.ErrSeriesCountUnsupported))
+}
+
 func newTestIteratorPools(ctrl *gomock.Controller) encoding.IteratorPools {
 	pools := encoding.NewMockIteratorPools(ctrl)
 
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metricmetadata
+
+import (
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+const (
+	// DefaultMaxMetricFamilies is the default max number of metric families
+	// stored, it bounds the size of the values persisted to KV.
+	DefaultMaxMetricFamilies = 10000
+)
+
+type options struct {
+	kvStoreFn         KVStoreFn
+	maxMetricFamilies int
+	instrumentOpts    instrument.Options
+}
+
+// NewOptions returns new store options.
+func NewOptions() Options {
+	return &options{
+		maxMetricFamilies: DefaultMaxMetricFamilies,
+		instrumentOpts:    instrument.NewOptions(),
+	}
+}
+
+func (o *options) SetKVStoreFn(value KVStoreFn) Options {
+	opts := *o
+	opts.kvStoreFn = value
+	return &opts
+}
+
+func (o *options) KVStoreFn() KVStoreFn {
+	return o.kvStoreFn
+}
+
+func (o *options) SetMaxMetricFamilies(value int) Options {
+	opts := *o
+	opts.maxMetricFamilies = value
+	return &opts
+}
+
+func (o *options) MaxMetricFamilies() int {
+	return o.maxMetricFamilies
+}
+
+func (o *options) SetInstrumentOptions(value instrument.Options) Options {
+	opts := *o
+	opts.instrumentOpts = value
+	return &opts
+}
+
+func (o *options) InstrumentOptions() instrument.Options {
+	return o.instrumentOpts
+}
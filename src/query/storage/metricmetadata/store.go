@@ -0,0 +1,408 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metricmetadata
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/gogo/protobuf/proto"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+)
+
+const (
+	// KVKeyPrefix is the prefix of the KV keys that metric metadata is
+	// persisted under, the metadata of a metric family is persisted under
+	// the key of the shard its name hashes to.
+	KVKeyPrefix = "m3query.prometheus.metadata"
+
+	// NumKVShards is the number of KV keys metric metadata is sharded
+	// across, which keeps each value well below the KV request size limit.
+	NumKVShards = 64
+
+	// MaxKVValueBytes is the max size of the value persisted to each KV
+	// key, metadata that would grow a value past it is dropped.
+	MaxKVValueBytes = 256 << 10
+
+	maxCheckAndSetAttempts = 10
+)
+
+// KVKey returns the KV key of the given shard.
+func KVKey(shard int) string {
+	return fmt.Sprintf("%s.%d", KVKeyPrefix, shard)
+}
+
+func kvShard(metricFamily string) int {
+	return int(xxhash.Sum64String(metricFamily) % NumKVShards)
+}
+
+type store struct {
+	sync.RWMutex
+
+	kvLock    sync.Mutex
+	kvStore   kv.Store
+	kvStoreFn KVStoreFn
+
+	// shards holds the metadata of each KV shard, metadata is held in a
+	// single shard when there is no KV store.
+	shards            []map[string]Metadata
+	maxMetricFamilies int
+	logger            *zap.Logger
+	metrics           storeMetrics
+}
+
+type storeMetrics struct {
+	written tally.Counter
+	dropped tally.Counter
+	updates tally.Counter
+}
+
+func newStoreMetrics(scope tally.Scope) storeMetrics {
+	return storeMetrics{
+		written: scope.Counter("written"),
+		dropped: scope.Counter("dropped"),
+		updates: scope.Counter("kv-updates"),
+	}
+}
+
+// NewStore returns a new store, if a KV store is set metadata is persisted
+// to it and updates written by other stores are watched.
+func NewStore(opts Options) Store {
+	var (
+		iOpts     = opts.InstrumentOptions()
+		numShards = 1
+	)
+	if opts.KVStoreFn() != nil {
+		numShards = NumKVShards
+	}
+
+	shards := make([]map[string]Metadata, 0, numShards)
+	for i := 0; i < numShards; i++ {
+		shards = append(shards, make(map[string]Metadata))
+	}
+
+	return &store{
+		kvStoreFn:         opts.KVStoreFn(),
+		shards:            shards,
+		maxMetricFamilies: opts.MaxMetricFamilies(),
+		logger:            iOpts.Logger(),
+		metrics:           newStoreMetrics(iOpts.MetricsScope().SubScope("metric-metadata")),
+	}
+}
+
+func (s *store) Write(metadata []prompb.MetricMetadata) error {
+	updated := s.updated(metadata)
+	if len(updated) == 0 {
+		return nil
+	}
+
+	kvStore, err := s.kv()
+	if err != nil {
+		return err
+	}
+
+	if kvStore == nil {
+		s.Lock()
+		for name, m := range updated {
+			s.shards[0][name] = m
+		}
+		s.Unlock()
+		s.metrics.written.Inc(int64(len(updated)))
+		return nil
+	}
+
+	byShard := make(map[int]map[string]Metadata)
+	for name, m := range updated {
+		shard := kvShard(name)
+		if byShard[shard] == nil {
+			byShard[shard] = make(map[string]Metadata)
+		}
+		byShard[shard][name] = m
+	}
+
+	for shard, shardUpdated := range byShard {
+		if err := s.persist(kvStore, shard, shardUpdated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shardFor returns the shard that holds the metadata of a metric family,
+// must be called with the lock held.
+func (s *store) shardFor(metricFamily string) map[string]Metadata {
+	if len(s.shards) == 1 {
+		return s.shards[0]
+	}
+	return s.shards[kvShard(metricFamily)]
+}
+
+// numMetricFamilies returns the number of metric families held, must be
+// called with the lock held.
+func (s *store) numMetricFamilies() int {
+	var n int
+	for _, shard := range s.shards {
+		n += len(shard)
+	}
+	return n
+}
+
+// updated returns the metadata that is not already stored, metadata of new
+// metric families is dropped once the max number of metric families is held.
+func (s *store) updated(metadata []prompb.MetricMetadata) map[string]Metadata {
+	s.RLock()
+	defer s.RUnlock()
+
+	var (
+		updated     map[string]Metadata
+		dropped     int64
+		numFamilies = s.numMetricFamilies()
+	)
+	for _, m := range metadata {
+		if m.MetricFamilyName == "" {
+			continue
+		}
+
+		value := Metadata{Type: m.Type, Help: m.Help, Unit: m.Unit}
+		existing, ok := s.shardFor(m.MetricFamilyName)[m.MetricFamilyName]
+		if ok && existing == value {
+			continue
+		}
+		if !ok && numFamilies+len(updated) >= s.maxMetricFamilies {
+			dropped++
+			continue
+		}
+
+		if updated == nil {
+			updated = make(map[string]Metadata)
+		}
+		updated[m.MetricFamilyName] = value
+	}
+
+	s.metrics.dropped.Inc(dropped)
+	return updated
+}
+
+func (s *store) persist(
+	kvStore kv.Store,
+	shard int,
+	updated map[string]Metadata,
+) error {
+	key := KVKey(shard)
+	for attempt := 1; ; attempt++ {
+		families, version, err := get(kvStore, key)
+		if err != nil {
+			return err
+		}
+
+		var (
+			size    = toProto(families).Size()
+			written int64
+			dropped int64
+		)
+		for name, m := range updated {
+			delta := metadataSize(name, m)
+			if existing, ok := families[name]; ok {
+				delta -= metadataSize(name, existing)
+			}
+			if size+delta > MaxKVValueBytes {
+				dropped++
+				continue
+			}
+			size += delta
+			families[name] = m
+			written++
+		}
+
+		_, err = kvStore.CheckAndSet(key, version, toProto(families))
+		if err == kv.ErrVersionMismatch && attempt < maxCheckAndSetAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not persist metric metadata: %v", err)
+		}
+
+		s.metrics.written.Inc(written)
+		s.metrics.dropped.Inc(dropped)
+		s.update(shard, families)
+		return nil
+	}
+}
+
+func (s *store) Metadata(metric string) (map[string]Metadata, error) {
+	if _, err := s.kv(); err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	if metric != "" {
+		result := make(map[string]Metadata, 1)
+		if m, ok := s.shardFor(metric)[metric]; ok {
+			result[metric] = m
+		}
+		return result, nil
+	}
+
+	result := make(map[string]Metadata, s.numMetricFamilies())
+	for _, shard := range s.shards {
+		for name, m := range shard {
+			result[name] = m
+		}
+	}
+	return result, nil
+}
+
+// kv returns the KV store if set, the first successful call loads the
+// persisted metadata and starts watching for updates from other stores.
+func (s *store) kv() (kv.Store, error) {
+	if s.kvStoreFn == nil {
+		return nil, nil
+	}
+
+	s.kvLock.Lock()
+	defer s.kvLock.Unlock()
+
+	if s.kvStore != nil {
+		return s.kvStore, nil
+	}
+
+	kvStore, err := s.kvStoreFn()
+	if err != nil {
+		return nil, fmt.Errorf("could not get metric metadata KV store: %v", err)
+	}
+
+	watches := make([]kv.ValueWatch, 0, NumKVShards)
+	for shard := 0; shard < NumKVShards; shard++ {
+		families, _, err := get(kvStore, KVKey(shard))
+		if err != nil {
+			return nil, err
+		}
+
+		watch, err := kvStore.Watch(KVKey(shard))
+		if err != nil {
+			return nil, fmt.Errorf("could not watch metric metadata: %v", err)
+		}
+
+		s.update(shard, families)
+		watches = append(watches, watch)
+	}
+
+	s.kvStore = kvStore
+	for shard, watch := range watches {
+		go s.watch(shard, watch)
+	}
+	return kvStore, nil
+}
+
+func (s *store) watch(shard int, watch kv.ValueWatch) {
+	for range watch.C() {
+		value := watch.Get()
+		if value == nil {
+			continue
+		}
+
+		var req prompb.WriteRequest
+		if err := value.Unmarshal(&req); err != nil {
+			s.logger.Error("could not unmarshal metric metadata", zap.Error(err))
+			continue
+		}
+
+		s.metrics.updates.Inc(1)
+		s.update(shard, fromProto(&req))
+	}
+}
+
+// update replaces the metadata of a shard with the persisted metadata, which
+// includes the metadata written by all stores.
+func (s *store) update(shard int, families map[string]Metadata) {
+	s.Lock()
+	s.shards[shard] = families
+	s.Unlock()
+}
+
+func get(kvStore kv.Store, key string) (map[string]Metadata, int, error) {
+	value, err := kvStore.Get(key)
+	if err == kv.ErrNotFound {
+		return make(map[string]Metadata), 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not get metric metadata: %v", err)
+	}
+
+	var req prompb.WriteRequest
+	if err := value.Unmarshal(&req); err != nil {
+		return nil, 0, fmt.Errorf("could not unmarshal metric metadata: %v", err)
+	}
+
+	return fromProto(&req), value.Version(), nil
+}
+
+// metadataSize returns the number of bytes the metadata of a metric family
+// adds to the persisted value.
+func metadataSize(name string, m Metadata) int {
+	metadata := toProtoMetadata(name, m)
+	size := metadata.Size()
+	return 1 + proto.SizeVarint(uint64(size)) + size
+}
+
+func toProtoMetadata(name string, m Metadata) prompb.MetricMetadata {
+	return prompb.MetricMetadata{
+		MetricFamilyName: name,
+		Type:             m.Type,
+		Help:             m.Help,
+		Unit:             m.Unit,
+	}
+}
+
+// NB: metadata is persisted as a remote write request with only metadata set
+// so that it reuses the remote write protobuf messages.
+func toProto(families map[string]Metadata) *prompb.WriteRequest {
+	req := &prompb.WriteRequest{
+		Metadata: make([]prompb.MetricMetadata, 0, len(families)),
+	}
+	for name, m := range families {
+		req.Metadata = append(req.Metadata, toProtoMetadata(name, m))
+	}
+	sort.Slice(req.Metadata, func(i, j int) bool {
+		return req.Metadata[i].MetricFamilyName < req.Metadata[j].MetricFamilyName
+	})
+	return req
+}
+
+func fromProto(req *prompb.WriteRequest) map[string]Metadata {
+	families := make(map[string]Metadata, len(req.Metadata))
+	for _, m := range req.Metadata {
+		families[m.MetricFamilyName] = Metadata{
+			Type: m.Type,
+			Help: m.Help,
+			Unit: m.Unit,
+		}
+	}
+	return families
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package metricmetadata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/clock"
+)
+
+var (
+	testFooMetadata = prompb.MetricMetadata{
+		Type:             prompb.MetricType_COUNTER,
+		MetricFamilyName: "foo_total",
+		Help:             "foo help",
+	}
+	testBarMetadata = prompb.MetricMetadata{
+		Type:             prompb.MetricType_GAUGE,
+		MetricFamilyName: "bar",
+		Help:             "bar help",
+		Unit:             "seconds",
+	}
+)
+
+func TestStoreWriteInMemory(t *testing.T) {
+	s := NewStore(NewOptions())
+
+	require.NoError(t, s.Write([]prompb.MetricMetadata{
+		testFooMetadata,
+		testBarMetadata,
+		{Type: prompb.MetricType_GAUGE},
+	}))
+
+	result, err := s.Metadata("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Metadata{
+		"foo_total": {Type: prompb.MetricType_COUNTER, Help: "foo help"},
+		"bar":       {Type: prompb.MetricType_GAUGE, Help: "bar help", Unit: "seconds"},
+	}, result)
+
+	// NB: the latest metadata of a metric family replaces the existing.
+	updated := testBarMetadata
+	updated.Help = "new bar help"
+	require.NoError(t, s.Write([]prompb.MetricMetadata{updated}))
+
+	result, err = s.Metadata("bar")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Metadata{
+		"bar": {Type: prompb.MetricType_GAUGE, Help: "new bar help", Unit: "seconds"},
+	}, result)
+
+	result, err = s.Metadata("baz")
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(result))
+}
+
+func TestStoreWriteMaxMetricFamilies(t *testing.T) {
+	s := NewStore(NewOptions().SetMaxMetricFamilies(1))
+
+	require.NoError(t, s.Write([]prompb.MetricMetadata{testFooMetadata}))
+	require.NoError(t, s.Write([]prompb.MetricMetadata{testBarMetadata}))
+
+	result, err := s.Metadata("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo_total"}, keys(result))
+}
+
+func TestStoreWriteKV(t *testing.T) {
+	kvStore := mem.NewStore()
+	kvStoreFn := func() (kv.Store, error) { return kvStore, nil }
+	first := NewStore(NewOptions().SetKVStoreFn(kvStoreFn))
+	second := NewStore(NewOptions().SetKVStoreFn(kvStoreFn))
+
+	require.NoError(t, first.Write([]prompb.MetricMetadata{testFooMetadata}))
+	require.NoError(t, second.Write([]prompb.MetricMetadata{testBarMetadata}))
+
+	// Each metric family is persisted under the key of its shard.
+	for _, m := range []prompb.MetricMetadata{testFooMetadata, testBarMetadata} {
+		value, err := kvStore.Get(KVKey(kvShard(m.MetricFamilyName)))
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, value.Unmarshal(&req))
+		assert.Contains(t, req.Metadata, m)
+	}
+
+	// The first store picks up the metadata written by the second store.
+	require.True(t, clock.WaitUntil(func() bool {
+		result, err := first.Metadata("")
+		require.NoError(t, err)
+		return len(result) == 2
+	}, 5*time.Second))
+
+	// A new store loads the persisted metadata.
+	third := NewStore(NewOptions().SetKVStoreFn(kvStoreFn))
+	result, err := third.Metadata("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar", "foo_total"}, keys(result))
+}
+
+func TestStoreWriteKVMaxValueBytes(t *testing.T) {
+	kvStore := mem.NewStore()
+	kvStoreFn := func() (kv.Store, error) { return kvStore, nil }
+	s := NewStore(NewOptions().SetKVStoreFn(kvStoreFn))
+
+	// NB: metric families of the same shard share a value, so large help
+	// strings of a shard are dropped once the value is full.
+	var (
+		help     = strings.Repeat("a", MaxKVValueBytes/4)
+		shard    = kvShard(testFooMetadata.MetricFamilyName)
+		metadata []prompb.MetricMetadata
+	)
+	for i := 0; len(metadata) < 5; i++ {
+		name := fmt.Sprintf("foo_%d", i)
+		if kvShard(name) != shard {
+			continue
+		}
+		metadata = append(metadata, prompb.MetricMetadata{
+			Type:             prompb.MetricType_GAUGE,
+			MetricFamilyName: name,
+			Help:             help,
+		})
+	}
+	require.NoError(t, s.Write(metadata))
+
+	value, err := kvStore.Get(KVKey(shard))
+	require.NoError(t, err)
+	var req prompb.WriteRequest
+	require.NoError(t, value.Unmarshal(&req))
+	assert.Equal(t, 3, len(req.Metadata))
+	assert.True(t, req.Size() <= MaxKVValueBytes)
+
+	result, err := s.Metadata("")
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(result))
+
+	// Metric families of other shards are still written.
+	require.NoError(t, s.Write([]prompb.MetricMetadata{testBarMetadata}))
+	result, err = s.Metadata("bar")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(result))
+}
+
+func keys(m map[string]Metadata) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
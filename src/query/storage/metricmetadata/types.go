@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package metricmetadata stores the metadata of Prometheus metric families
+// written with remote write.
+package metricmetadata
+
+import (
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+	"github.com/m3db/m3/src/x/instrument"
+)
+
+// Metadata is the metadata of a metric family.
+type Metadata struct {
+	Type prompb.MetricType
+	Help string
+	Unit string
+}
+
+// Store stores the latest metadata of each metric family.
+type Store interface {
+	// Write stores the metadata of metric families, metadata that is
+	// already stored is not written again.
+	Write(metadata []prompb.MetricMetadata) error
+
+	// Metadata returns the metadata of all metric families, or of a single
+	// metric family if metric is not empty.
+	Metadata(metric string) (map[string]Metadata, error)
+}
+
+// KVStoreFn returns the KV store that metadata is persisted to.
+type KVStoreFn func() (kv.Store, error)
+
+// Options are the options for a store.
+type Options interface {
+	// SetKVStoreFn sets the function that returns the KV store, if not set
+	// metadata is only held in memory.
+	SetKVStoreFn(value KVStoreFn) Options
+
+	// KVStoreFn returns the function that returns the KV store.
+	KVStoreFn() KVStoreFn
+
+	// SetMaxMetricFamilies sets the max number of metric families stored.
+	SetMaxMetricFamilies(value int) Options
+
+	// MaxMetricFamilies returns the max number of metric families stored.
+	MaxMetricFamilies() int
+
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+}
@@ -39,11 +39,25 @@ func iteratorToPromResult(
 	tags models.Tags,
 	tagOptions models.TagOptions,
 ) (*prompb.TimeSeries, error) {
-	samples := make([]prompb.Sample, 0, initRawFetchAllocSize)
+	var (
+		samples    = make([]prompb.Sample, 0, initRawFetchAllocSize)
+		histograms []prompb.Histogram
+	)
 	for iter.Next() {
-		dp, _, _ := iter.Current()
+		dp, _, ant := iter.Current()
+		timestamp := TimeToPromTimestamp(dp.TimestampNanos)
+		histogram, ok, err := annotationToPromHistogram(ant, timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			histograms = append(histograms, histogram)
+			continue
+		}
+
 		samples = append(samples, prompb.Sample{
-			Timestamp: TimeToPromTimestamp(dp.TimestampNanos),
+			Timestamp: timestamp,
 			Value:     dp.Value,
 		})
 	}
@@ -53,8 +67,9 @@ func iteratorToPromResult(
 	}
 
 	return &prompb.TimeSeries{
-		Labels:  TagsToPromLabels(tags),
-		Samples: samples,
+		Labels:     TagsToPromLabels(tags),
+		Samples:    samples,
+		Histograms: histograms,
 	}, nil
 }
 
@@ -76,7 +91,7 @@ func toPromSequentially(
 			return PromResult{}, err
 		}
 
-		if len(series.GetSamples()) > 0 {
+		if len(series.GetSamples()) > 0 || len(series.GetHistograms()) > 0 {
 			seriesList = append(seriesList, series)
 		}
 	}
@@ -140,7 +155,7 @@ func toPromConcurrently(
 	// Filter out empty series inplace.
 	filteredList := seriesList[:0]
 	for _, s := range seriesList {
-		if len(s.GetSamples()) > 0 {
+		if len(s.GetSamples()) > 0 || len(s.GetHistograms()) > 0 {
 			filteredList = append(filteredList, s)
 		}
 	}
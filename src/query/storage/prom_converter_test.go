@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
 	dts "github.com/m3db/m3/src/dbnode/ts"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
@@ -333,3 +334,52 @@ func TestDecodeIteratorsWithEmptySeries(t *testing.T) {
 	require.NoError(t, err)
 	verifyResult(t, res)
 }
+
+func TestIteratorToPromResultNativeHistograms(t *testing.T) {
+	ctrl := xtest.NewController(t)
+	defer ctrl.Finish()
+
+	histogram := prompb.Histogram{
+		Count:          &prompb.Histogram_CountInt{CountInt: 3},
+		Sum:            2.5,
+		ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+		PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 1}},
+		PositiveDeltas: []int64{2},
+		Timestamp:      1000,
+	}
+	series, annotations, err := PromHistogramsToNativeTimeSeries(prompb.TimeSeries{
+		Labels:     []prompb.Label{{Name: promDefaultName, Value: []byte("foo")}},
+		Histograms: []prompb.Histogram{histogram},
+	})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	require.Len(t, annotations, 1)
+
+	metricType, err := (&annotation.Payload{MetricType: annotation.MetricType_GAUGE}).Marshal()
+	require.NoError(t, err)
+
+	var (
+		now  = xtime.Now().Truncate(time.Millisecond)
+		iter = encoding.NewMockSeriesIterator(ctrl)
+	)
+	gomock.InOrder(
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(dts.Datapoint{TimestampNanos: now, Value: 3},
+			xtime.Millisecond, annotations[0]),
+		iter.EXPECT().Next().Return(true),
+		iter.EXPECT().Current().Return(dts.Datapoint{TimestampNanos: now.Add(time.Second), Value: 5},
+			xtime.Millisecond, metricType),
+		iter.EXPECT().Next().Return(false),
+		iter.EXPECT().Err().Return(nil),
+	)
+
+	result, err := iteratorToPromResult(iter, models.EmptyTags(), models.NewTagOptions())
+	require.NoError(t, err)
+
+	histogram.Timestamp = TimeToPromTimestamp(now)
+	assert.Equal(t, []prompb.Histogram{histogram}, result.Histograms)
+	assert.Equal(t, []prompb.Sample{{
+		Value:     5,
+		Timestamp: TimeToPromTimestamp(now.Add(time.Second)),
+	}}, result.Samples)
+}
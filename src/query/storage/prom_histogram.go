@@ -0,0 +1,410 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/annotation"
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+)
+
+const (
+	// Native histograms only support base-2 exponential schemas in the
+	// range [-4, 8].
+	promHistogramMinSchema = -4
+	promHistogramMaxSchema = 8
+)
+
+var (
+	// The suffixes of the classic histogram series a native histogram is
+	// expanded into.
+	promDefaultBucketSuffix = []byte("_bucket")
+	promDefaultSumSuffix    = []byte("_sum")
+
+	errHistogramMissingName = errors.New("native histogram series missing metric name")
+)
+
+type promHistogramBucket struct {
+	upperBound float64
+	count      float64
+}
+
+// PromHistogramsToClassicTimeSeries expands the native histograms of a
+// Prometheus timeseries into the equivalent classic histogram timeseries, i.e.
+// a cumulative "_bucket" series per bucket upper bound (set as the "le" label)
+// along with the "_count" and "_sum" series. This allows native histograms
+// to be stored using the existing float encodings and queried using functions
+// such as histogram_quantile and rate.
+func PromHistogramsToClassicTimeSeries(
+	series prompb.TimeSeries,
+) ([]prompb.TimeSeries, error) {
+	if len(series.Histograms) == 0 {
+		return nil, nil
+	}
+
+	name := metricNameFromLabels(series.Labels)
+	if len(name) == 0 {
+		return nil, errHistogramMissingName
+	}
+
+	var (
+		metricType  = prompb.MetricType_HISTOGRAM
+		cumulatives = make([][]promHistogramBucket, 0, len(series.Histograms))
+		boundsSet   = make(map[float64]struct{})
+		counts      = make([]prompb.Sample, 0, len(series.Histograms))
+		sums        = make([]prompb.Sample, 0, len(series.Histograms))
+	)
+	for _, histogram := range series.Histograms {
+		if histogram.ResetHint == prompb.Histogram_GAUGE {
+			metricType = prompb.MetricType_GAUGE_HISTOGRAM
+		}
+
+		cumulative, err := promHistogramCumulativeBuckets(histogram)
+		if err != nil {
+			return nil, err
+		}
+
+		count := promHistogramCount(histogram)
+		cumulative = append(cumulative, promHistogramBucket{
+			upperBound: math.Inf(1),
+			count:      count,
+		})
+		for _, bucket := range cumulative {
+			boundsSet[bucket.upperBound] = struct{}{}
+		}
+
+		cumulatives = append(cumulatives, cumulative)
+		counts = append(counts, prompb.Sample{Value: count, Timestamp: histogram.Timestamp})
+		sums = append(sums, prompb.Sample{Value: histogram.Sum, Timestamp: histogram.Timestamp})
+	}
+
+	upperBounds := make([]float64, 0, len(boundsSet))
+	for upperBound := range boundsSet {
+		upperBounds = append(upperBounds, upperBound)
+	}
+	sort.Float64s(upperBounds)
+
+	// NB: native histograms only carry the buckets that are populated, and
+	// the populated buckets may change from sample to sample. Every bucket
+	// series gets a sample per histogram so that the set of buckets stays
+	// stable across timestamps, with buckets absent from a histogram taking
+	// the cumulative count of the closest bucket below them.
+	buckets := make([][]prompb.Sample, len(upperBounds))
+	for i, cumulative := range cumulatives {
+		var (
+			timestamp = series.Histograms[i].Timestamp
+			count     float64
+			next      int
+		)
+		for j, upperBound := range upperBounds {
+			for next < len(cumulative) && cumulative[next].upperBound <= upperBound {
+				count = cumulative[next].count
+				next++
+			}
+			buckets[j] = append(buckets[j], prompb.Sample{
+				Value:     count,
+				Timestamp: timestamp,
+			})
+		}
+	}
+
+	newSeries := func(suffix []byte, samples []prompb.Sample) prompb.TimeSeries {
+		return prompb.TimeSeries{
+			Labels:  promHistogramLabels(series.Labels, name, suffix),
+			Samples: samples,
+			M3Type:  series.M3Type,
+			Source:  series.Source,
+			Type:    metricType,
+		}
+	}
+
+	result := make([]prompb.TimeSeries, 0, len(upperBounds)+2)
+	for i, upperBound := range upperBounds {
+		bucketSeries := newSeries(promDefaultBucketSuffix, buckets[i])
+		bucketSeries.Labels = append(bucketSeries.Labels, prompb.Label{
+			Name:  promDefaultBucketName,
+			Value: []byte(strconv.FormatFloat(upperBound, 'g', -1, 64)),
+		})
+		result = append(result, bucketSeries)
+	}
+
+	result = append(result,
+		newSeries(promDefaultCountSuffix, counts),
+		newSeries(promDefaultSumSuffix, sums))
+	return result, nil
+}
+
+// PromHistogramsToNativeTimeSeries returns a timeseries per native histogram
+// of a Prometheus timeseries, each with a single sample of the histogram count
+// and the annotation that stores the full native histogram. The annotation
+// is decoded on read so that remote read returns the native histograms as
+// they were written.
+func PromHistogramsToNativeTimeSeries(
+	series prompb.TimeSeries,
+) ([]prompb.TimeSeries, [][]byte, error) {
+	var (
+		result      = make([]prompb.TimeSeries, 0, len(series.Histograms))
+		annotations = make([][]byte, 0, len(series.Histograms))
+	)
+	for _, histogram := range series.Histograms {
+		metricType := prompb.MetricType_HISTOGRAM
+		if histogram.ResetHint == prompb.Histogram_GAUGE {
+			metricType = prompb.MetricType_GAUGE_HISTOGRAM
+		}
+
+		nativeSeries := prompb.TimeSeries{
+			Labels: series.Labels,
+			Samples: []prompb.Sample{{
+				Value:     promHistogramCount(histogram),
+				Timestamp: histogram.Timestamp,
+			}},
+			M3Type: series.M3Type,
+			Source: series.Source,
+			Type:   metricType,
+		}
+
+		attributes, err := PromTimeSeriesToSeriesAttributes(nativeSeries)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payload, err := SeriesAttributesToAnnotationPayload(attributes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payload.NativeHistogram, err = histogram.Marshal()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		annotation, err := payload.Marshal()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result = append(result, nativeSeries)
+		annotations = append(annotations, annotation)
+	}
+
+	return result, annotations, nil
+}
+
+// annotationToPromHistogram decodes the native histogram stored in the
+// annotation of a datapoint, returning false if there is none.
+func annotationToPromHistogram(
+	ant []byte,
+	timestamp int64,
+) (prompb.Histogram, bool, error) {
+	if len(ant) == 0 {
+		return prompb.Histogram{}, false, nil
+	}
+
+	var payload annotation.Payload
+	if err := payload.Unmarshal(ant); err != nil {
+		// NB: annotations are not required to be payloads, so anything that
+		// does not decode as one is not a native histogram.
+		return prompb.Histogram{}, false, nil
+	}
+
+	if len(payload.NativeHistogram) == 0 {
+		return prompb.Histogram{}, false, nil
+	}
+
+	var histogram prompb.Histogram
+	if err := histogram.Unmarshal(payload.NativeHistogram); err != nil {
+		return prompb.Histogram{}, false, fmt.Errorf("invalid native histogram annotation: %v", err)
+	}
+
+	histogram.Timestamp = timestamp
+	return histogram, true, nil
+}
+
+// promHistogramLabels copies the labels of a native histogram series, suffixing
+// the metric name and dropping any existing bucket label.
+func promHistogramLabels(
+	labels []prompb.Label,
+	name []byte,
+	suffix []byte,
+) []prompb.Label {
+	result := make([]prompb.Label, 0, len(labels)+1)
+	for _, label := range labels {
+		switch {
+		case bytes.Equal(promDefaultName, label.Name):
+			value := make([]byte, 0, len(name)+len(suffix))
+			value = append(append(value, name...), suffix...)
+			result = append(result, prompb.Label{Name: label.Name, Value: value})
+		case bytes.Equal(promDefaultBucketName, label.Name):
+			continue
+		default:
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// promHistogramCumulativeBuckets returns the cumulative counts of the buckets
+// of a native histogram, ordered by upper bound. The zero bucket is included
+// with an upper bound of the zero threshold, the implicit +Inf bucket is not.
+func promHistogramCumulativeBuckets(
+	histogram prompb.Histogram,
+) ([]promHistogramBucket, error) {
+	if histogram.Schema < promHistogramMinSchema || histogram.Schema > promHistogramMaxSchema {
+		return nil, fmt.Errorf("invalid native histogram schema %d: must be between %d and %d",
+			histogram.Schema, promHistogramMinSchema, promHistogramMaxSchema)
+	}
+
+	negative, err := promHistogramBucketCounts(histogram.NegativeSpans,
+		histogram.NegativeDeltas, histogram.NegativeCounts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid native histogram negative buckets: %v", err)
+	}
+
+	positive, err := promHistogramBucketCounts(histogram.PositiveSpans,
+		histogram.PositiveDeltas, histogram.PositiveCounts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid native histogram positive buckets: %v", err)
+	}
+
+	var (
+		cumulative float64
+		result     = make([]promHistogramBucket, 0, len(negative)+len(positive)+1)
+	)
+	// NB: negative bucket i covers [-base^i, -base^(i-1)), so the most
+	// negative bucket is the one with the highest index.
+	for i := len(negative) - 1; i >= 0; i-- {
+		cumulative += negative[i].count
+		result = append(result, promHistogramBucket{
+			upperBound: -promHistogramBucketBound(negative[i].index-1, histogram.Schema),
+			count:      cumulative,
+		})
+	}
+
+	cumulative += promHistogramZeroCount(histogram)
+	result = append(result, promHistogramBucket{
+		upperBound: histogram.ZeroThreshold,
+		count:      cumulative,
+	})
+
+	for _, bucket := range positive {
+		cumulative += bucket.count
+		result = append(result, promHistogramBucket{
+			upperBound: promHistogramBucketBound(bucket.index, histogram.Schema),
+			count:      cumulative,
+		})
+	}
+
+	return result, nil
+}
+
+type promHistogramIndexedCount struct {
+	index int32
+	count float64
+}
+
+// promHistogramBucketCounts resolves the absolute count of each bucket
+// described by the given spans, using either the delta encoded integer counts
+// or the absolute float counts.
+func promHistogramBucketCounts(
+	spans []prompb.BucketSpan,
+	deltas []int64,
+	counts []float64,
+) ([]promHistogramIndexedCount, error) {
+	numBuckets := len(counts)
+	if len(deltas) > 0 {
+		if len(counts) > 0 {
+			return nil, errors.New("both integer deltas and float counts set")
+		}
+		numBuckets = len(deltas)
+	}
+
+	var (
+		result = make([]promHistogramIndexedCount, 0, numBuckets)
+		next   int32
+		curr   int64
+	)
+	for _, span := range spans {
+		index := next + span.Offset
+		for i := int32(0); i < int32(span.Length); i++ {
+			pos := len(result)
+			if pos >= numBuckets {
+				return nil, fmt.Errorf("spans describe more than %d buckets", numBuckets)
+			}
+
+			var count float64
+			if len(deltas) > 0 {
+				curr += deltas[pos]
+				count = float64(curr)
+			} else {
+				count = counts[pos]
+			}
+
+			result = append(result, promHistogramIndexedCount{
+				index: index + i,
+				count: count,
+			})
+		}
+		next = index + int32(span.Length)
+	}
+
+	if len(result) != numBuckets {
+		return nil, fmt.Errorf("spans describe %d buckets, expected %d",
+			len(result), numBuckets)
+	}
+
+	return result, nil
+}
+
+// promHistogramBucketBound returns the upper bound of the positive bucket with
+// the given index, which is base^index where base is 2^(2^-schema).
+func promHistogramBucketBound(index int32, schema int32) float64 {
+	if schema <= 0 {
+		return math.Ldexp(1, int(index)<<uint(-schema))
+	}
+
+	// NB: split the index into the power of two and the fraction within
+	// it so that each power of two is an exact bucket boundary.
+	var (
+		exp  = index >> uint(schema)
+		frac = index & (int32(1)<<uint(schema) - 1)
+	)
+	return math.Ldexp(math.Pow(2, float64(frac)/float64(int32(1)<<uint(schema))), int(exp))
+}
+
+func promHistogramCount(histogram prompb.Histogram) float64 {
+	if c, ok := histogram.Count.(*prompb.Histogram_CountFloat); ok {
+		return c.CountFloat
+	}
+	return float64(histogram.GetCountInt())
+}
+
+func promHistogramZeroCount(histogram prompb.Histogram) float64 {
+	if c, ok := histogram.ZeroCount.(*prompb.Histogram_ZeroCountFloat); ok {
+		return c.ZeroCountFloat
+	}
+	return float64(histogram.GetZeroCountInt())
+}
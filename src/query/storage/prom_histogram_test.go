@@ -0,0 +1,262 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package storage
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/query/generated/proto/prompb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromHistogramsToClassicTimeSeries(t *testing.T) {
+	series := prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: promDefaultName, Value: []byte("foo")},
+			{Name: []byte("job"), Value: []byte("bar")},
+		},
+		Histograms: []prompb.Histogram{
+			{
+				Count:          &prompb.Histogram_CountInt{CountInt: 9},
+				Sum:            12.5,
+				Schema:         0,
+				ZeroThreshold:  0.001,
+				ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 2},
+				NegativeSpans:  []prompb.BucketSpan{{Offset: 0, Length: 1}},
+				NegativeDeltas: []int64{3},
+				PositiveSpans: []prompb.BucketSpan{
+					{Offset: 0, Length: 2},
+					{Offset: 1, Length: 1},
+				},
+				PositiveDeltas: []int64{1, 1, -1},
+				Timestamp:      1000,
+			},
+		},
+	}
+
+	result, err := PromHistogramsToClassicTimeSeries(series)
+	require.NoError(t, err)
+
+	bucket := func(le string, value float64) prompb.TimeSeries {
+		return prompb.TimeSeries{
+			Labels: []prompb.Label{
+				{Name: promDefaultName, Value: []byte("foo_bucket")},
+				{Name: []byte("job"), Value: []byte("bar")},
+				{Name: promDefaultBucketName, Value: []byte(le)},
+			},
+			Samples: []prompb.Sample{{Value: value, Timestamp: 1000}},
+			Type:    prompb.MetricType_HISTOGRAM,
+		}
+	}
+
+	expected := []prompb.TimeSeries{
+		bucket("-0.5", 3),
+		bucket("0.001", 5),
+		bucket("1", 6),
+		bucket("2", 8),
+		bucket("8", 9),
+		bucket("+Inf", 9),
+		{
+			Labels: []prompb.Label{
+				{Name: promDefaultName, Value: []byte("foo_count")},
+				{Name: []byte("job"), Value: []byte("bar")},
+			},
+			Samples: []prompb.Sample{{Value: 9, Timestamp: 1000}},
+			Type:    prompb.MetricType_HISTOGRAM,
+		},
+		{
+			Labels: []prompb.Label{
+				{Name: promDefaultName, Value: []byte("foo_sum")},
+				{Name: []byte("job"), Value: []byte("bar")},
+			},
+			Samples: []prompb.Sample{{Value: 12.5, Timestamp: 1000}},
+			Type:    prompb.MetricType_HISTOGRAM,
+		},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestPromHistogramsToClassicTimeSeriesFloatGaugeHistogram(t *testing.T) {
+	series := prompb.TimeSeries{
+		Labels: []prompb.Label{{Name: promDefaultName, Value: []byte("foo")}},
+		Histograms: []prompb.Histogram{
+			{
+				Count:          &prompb.Histogram_CountFloat{CountFloat: 3.5},
+				Sum:            4,
+				Schema:         1,
+				ZeroCount:      &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: 0.5},
+				PositiveSpans:  []prompb.BucketSpan{{Offset: 1, Length: 2}},
+				PositiveCounts: []float64{1, 2},
+				ResetHint:      prompb.Histogram_GAUGE,
+				Timestamp:      1000,
+			},
+			{
+				Count:          &prompb.Histogram_CountFloat{CountFloat: 1},
+				Schema:         1,
+				PositiveSpans:  []prompb.BucketSpan{{Offset: 2, Length: 1}},
+				PositiveCounts: []float64{1},
+				ResetHint:      prompb.Histogram_GAUGE,
+				Timestamp:      2000,
+			},
+		},
+	}
+
+	result, err := PromHistogramsToClassicTimeSeries(series)
+	require.NoError(t, err)
+	require.Len(t, result, 6)
+
+	expected := []struct {
+		le      string
+		samples []prompb.Sample
+	}{
+		{le: "0", samples: []prompb.Sample{{Value: 0.5, Timestamp: 1000}, {Value: 0, Timestamp: 2000}}},
+		{le: "1.4142135623730951", samples: []prompb.Sample{{Value: 1.5, Timestamp: 1000}, {Value: 0, Timestamp: 2000}}},
+		{le: "2", samples: []prompb.Sample{{Value: 3.5, Timestamp: 1000}, {Value: 1, Timestamp: 2000}}},
+		{le: "+Inf", samples: []prompb.Sample{{Value: 3.5, Timestamp: 1000}, {Value: 1, Timestamp: 2000}}},
+	}
+	for i, e := range expected {
+		require.Equal(t, prompb.MetricType_GAUGE_HISTOGRAM, result[i].Type)
+		require.Equal(t, "foo_bucket", string(metricNameFromLabels(result[i].Labels)))
+		require.Equal(t, e.le, string(result[i].Labels[1].Value))
+		assert.Equal(t, e.samples, result[i].Samples)
+	}
+
+	assert.Equal(t, "foo_count", string(metricNameFromLabels(result[4].Labels)))
+	assert.Equal(t, "foo_sum", string(metricNameFromLabels(result[5].Labels)))
+}
+
+func TestPromHistogramsToClassicTimeSeriesStableBuckets(t *testing.T) {
+	series := prompb.TimeSeries{
+		Labels: []prompb.Label{{Name: promDefaultName, Value: []byte("foo")}},
+		Histograms: []prompb.Histogram{
+			{
+				Count:          &prompb.Histogram_CountInt{CountInt: 3},
+				Schema:         0,
+				PositiveSpans:  []prompb.BucketSpan{{Offset: 1, Length: 1}},
+				PositiveDeltas: []int64{3},
+				Timestamp:      1000,
+			},
+			{
+				Count:          &prompb.Histogram_CountInt{CountInt: 6},
+				Schema:         0,
+				PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 1}, {Offset: 1, Length: 1}},
+				PositiveDeltas: []int64{2, 2},
+				Timestamp:      2000,
+			},
+		},
+	}
+
+	result, err := PromHistogramsToClassicTimeSeries(series)
+	require.NoError(t, err)
+	require.Len(t, result, 7)
+
+	// NB: each histogram only populates some of the buckets, the absent
+	// buckets carry forward the cumulative count of the bucket below them.
+	expected := []struct {
+		le      string
+		samples []prompb.Sample
+	}{
+		{le: "0", samples: []prompb.Sample{{Value: 0, Timestamp: 1000}, {Value: 0, Timestamp: 2000}}},
+		{le: "1", samples: []prompb.Sample{{Value: 0, Timestamp: 1000}, {Value: 2, Timestamp: 2000}}},
+		{le: "2", samples: []prompb.Sample{{Value: 3, Timestamp: 1000}, {Value: 2, Timestamp: 2000}}},
+		{le: "4", samples: []prompb.Sample{{Value: 3, Timestamp: 1000}, {Value: 6, Timestamp: 2000}}},
+		{le: "+Inf", samples: []prompb.Sample{{Value: 3, Timestamp: 1000}, {Value: 6, Timestamp: 2000}}},
+	}
+	for i, e := range expected {
+		require.Equal(t, "foo_bucket", string(metricNameFromLabels(result[i].Labels)))
+		require.Equal(t, e.le, string(result[i].Labels[1].Value))
+		assert.Equal(t, e.samples, result[i].Samples)
+	}
+}
+
+func TestPromHistogramsToClassicTimeSeriesErrors(t *testing.T) {
+	name := []prompb.Label{{Name: promDefaultName, Value: []byte("foo")}}
+	tests := []struct {
+		name   string
+		series prompb.TimeSeries
+	}{
+		{
+			name: "missing name",
+			series: prompb.TimeSeries{
+				Histograms: []prompb.Histogram{{}},
+			},
+		},
+		{
+			name: "invalid schema",
+			series: prompb.TimeSeries{
+				Labels:     name,
+				Histograms: []prompb.Histogram{{Schema: 9}},
+			},
+		},
+		{
+			name: "too few buckets",
+			series: prompb.TimeSeries{
+				Labels: name,
+				Histograms: []prompb.Histogram{{
+					PositiveSpans:  []prompb.BucketSpan{{Length: 2}},
+					PositiveDeltas: []int64{1},
+				}},
+			},
+		},
+		{
+			name: "too many buckets",
+			series: prompb.TimeSeries{
+				Labels: name,
+				Histograms: []prompb.Histogram{{
+					NegativeSpans:  []prompb.BucketSpan{{Length: 1}},
+					NegativeCounts: []float64{1, 2},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PromHistogramsToClassicTimeSeries(tt.series)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPromHistogramBucketBound(t *testing.T) {
+	tests := []struct {
+		index    int32
+		schema   int32
+		expected float64
+	}{
+		{index: 0, schema: 0, expected: 1},
+		{index: 3, schema: 0, expected: 8},
+		{index: -2, schema: 0, expected: 0.25},
+		{index: 1, schema: -1, expected: 4},
+		{index: -1, schema: -2, expected: 1.0 / 16},
+		{index: 1, schema: 1, expected: math.Sqrt2},
+		{index: -1, schema: 1, expected: 1 / math.Sqrt2},
+		{index: 8, schema: 3, expected: 2},
+		{index: -256, schema: 8, expected: 0.5},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, promHistogramBucketBound(tt.index, tt.schema), 1e-12)
+	}
+}
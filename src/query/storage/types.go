@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/metrics/policy"
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
@@ -40,6 +41,10 @@ import (
 
 var (
 	errWriteQueryNoDatapoints = errors.New("write query with no datapoints")
+
+	// ErrSeriesCountUnsupported is returned by a SeriesCounter which cannot
+	// count the series of a query from its index.
+	ErrSeriesCountUnsupported = errors.New("series count from index unsupported")
 )
 
 // Type describes the type of storage.
@@ -207,6 +212,18 @@ type RestrictQueryOptions struct {
 	RestrictByTag *RestrictByTag
 }
 
+// SeriesCounter counts the series matching a query from the index of a
+// storage, without fetching their datapoints.
+type SeriesCounter interface {
+	// CountSeries returns the number of series matching a query, it returns
+	// ErrSeriesCountUnsupported if the series cannot be counted.
+	CountSeries(
+		ctx context.Context,
+		query *FetchQuery,
+		options *FetchOptions,
+	) (int64, error)
+}
+
 // Querier handles queries against a storage.
 type Querier interface {
 	// FetchProm fetches decompressed timeseries data based on a query in a
@@ -266,6 +283,9 @@ type WriteQueryOptions struct {
 	Unit       xtime.Unit
 	Annotation []byte
 	Attributes storagemetadata.Attributes
+	// Exemplars are optional exemplars of the datapoints, they are written
+	// on a best effort basis.
+	Exemplars []exemplar.Exemplar
 }
 
 // CompleteTagsQuery represents a query that returns an autocompleted
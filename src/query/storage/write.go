@@ -21,6 +21,7 @@
 package storage
 
 import (
+	"github.com/m3db/m3/src/dbnode/storage/exemplar"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3/storagemetadata"
 	"github.com/m3db/m3/src/query/ts"
@@ -95,6 +96,11 @@ func (q WriteQuery) Attributes() storagemetadata.Attributes {
 	return q.opts.Attributes
 }
 
+// Exemplars returns the exemplars.
+func (q WriteQuery) Exemplars() []exemplar.Exemplar {
+	return q.opts.Exemplars
+}
+
 // Validate validates the write query.
 func (q *WriteQuery) Validate() error {
 	return q.opts.Validate()
@@ -139,6 +139,21 @@ func (s *AsyncSession) WriteTagged(namespace, id ident.ID, tags ident.TagIterato
 	return s.session.WriteTagged(namespace, id, tags, t, value, unit, annotation)
 }
 
+// WriteTaggedWithContext writes a value to the database for an ID and given
+// tags, attributing the write to the source of the context.
+func (s *AsyncSession) WriteTaggedWithContext(ctx context.Context, namespace, id ident.ID,
+	tags ident.TagIterator, t xtime.UnixNano, value float64, unit xtime.Unit,
+	annotation []byte) error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.session.WriteTaggedWithContext(ctx, namespace, id, tags, t, value,
+		unit, annotation)
+}
+
 // Fetch fetches values from the database for an ID.
 func (s *AsyncSession) Fetch(namespace, id ident.ID, startInclusive,
 	endExclusive xtime.UnixNano) (encoding.SeriesIterator, error) {
@@ -212,6 +227,79 @@ func (s *AsyncSession) Aggregate(
 	return s.session.Aggregate(ctx, namespace, q, opts)
 }
 
+// WriteExemplars writes exemplars to the database for a set of series.
+func (s *AsyncSession) WriteExemplars(
+	namespace ident.ID,
+	series []client.SeriesExemplars,
+) error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.session.WriteExemplars(namespace, series)
+}
+
+// FetchExemplars fetches exemplars from the database for a set of IDs.
+func (s *AsyncSession) FetchExemplars(
+	namespace ident.ID,
+	ids []ident.ID,
+	start, end xtime.UnixNano,
+) ([]client.SeriesExemplars, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.session.FetchExemplars(namespace, ids, start, end)
+}
+
+// DeleteTagged deletes the data of the series matching the query.
+func (s *AsyncSession) DeleteTagged(
+	namespace ident.ID,
+	q index.Query,
+	start, end xtime.UnixNano,
+) (int64, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	return s.session.DeleteTagged(namespace, q, start, end)
+}
+
+// Cardinality returns the top cardinality statistics of the series of a namespace.
+func (s *AsyncSession) Cardinality(
+	namespace ident.ID,
+	opts index.CardinalityQueryOptions,
+) (index.CardinalityStats, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return index.CardinalityStats{}, s.err
+	}
+
+	return s.session.Cardinality(namespace, opts)
+}
+
+// Count returns the number of series of a namespace matching a query.
+func (s *AsyncSession) Count(
+	namespace ident.ID,
+	q index.Query,
+	opts index.CountQueryOptions,
+) (index.CountResult, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.err != nil {
+		return index.CountResult{}, s.err
+	}
+
+	return s.session.Count(namespace, q, opts)
+}
+
 // ShardID returns the given shard for an ID for callers
 // to easily discern what shard is failing when operations
 // for given IDs begin failing.
@@ -37,6 +37,9 @@ const (
 	// SearchCompressedFetchTaggedIDs is for the call to FetchTaggedIDs in SearchCompressed.
 	SearchCompressedFetchTaggedIDs = "m3.m3storage.SearchCompressed.FetchTaggedIDs"
 
+	// CountSeriesCount is for the call to Count in CountSeries.
+	CountSeriesCount = "m3.m3storage.CountSeries.Count"
+
 	// CompleteTagsAggregate is for the call to Aggregate in CompleteTags.
 	CompleteTagsAggregate = "m3.m3storage.CompleteTags.Aggregate"
 
@@ -0,0 +1,160 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package hyperloglog implements HyperLogLog sketches which estimate the
+// number of distinct values of a set and can be merged to estimate the number
+// of distinct values of the union of their sets.
+package hyperloglog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// DefaultPrecision is the default precision of a sketch, the 2^14
+	// registers of which have a standard error of ~0.8%.
+	DefaultPrecision = 14
+	// MinPrecision is the minimum precision of a sketch.
+	MinPrecision = 4
+	// MaxPrecision is the maximum precision of a sketch.
+	MaxPrecision = 18
+)
+
+var errEmptyEncodedSketch = errors.New("encoded sketch is empty")
+
+// Sketch is a HyperLogLog sketch, it is not safe for concurrent use.
+type Sketch struct {
+	precision uint8
+	registers []uint8
+}
+
+// New returns a new empty sketch with 2^precision registers.
+func New(precision uint8) (*Sketch, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, fmt.Errorf("invalid sketch precision: actual=%d, valid=[%d,%d]",
+			precision, MinPrecision, MaxPrecision)
+	}
+	return &Sketch{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Precision returns the precision of the sketch.
+func (s *Sketch) Precision() uint8 {
+	return s.precision
+}
+
+// Add adds a value to the sketch.
+func (s *Sketch) Add(value []byte) {
+	s.AddHash(xxhash.Sum64(value))
+}
+
+// AddHash adds the 64 bit hash of a value to the sketch.
+func (s *Sketch) AddHash(hash uint64) {
+	// The first bits of the hash select the register which keeps the largest
+	// position of the first set bit of the remaining bits, the guard bit
+	// bounds the position when the remaining bits are all unset.
+	idx := hash >> (64 - s.precision)
+	rank := uint8(bits.LeadingZeros64(hash<<s.precision|1<<(s.precision-1))) + 1
+	if rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+// Merge merges another sketch of the same precision into the sketch.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.precision != other.precision {
+		return fmt.Errorf("cannot merge sketches of different precisions: %d and %d",
+			s.precision, other.precision)
+	}
+	for i, rank := range other.registers {
+		if rank > s.registers[i] {
+			s.registers[i] = rank
+		}
+	}
+	return nil
+}
+
+// Estimate returns the estimated number of distinct values added to the sketch.
+func (s *Sketch) Estimate() uint64 {
+	var (
+		m     = float64(len(s.registers))
+		sum   float64
+		zeros int
+	)
+	for _, rank := range s.registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(s.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Use linear counting for small cardinalities which the raw estimate
+		// overestimates.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// MarshalBinary encodes the sketch as its precision followed by its registers.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 1+len(s.registers))
+	data = append(data, s.precision)
+	return append(data, s.registers...), nil
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errEmptyEncodedSketch
+	}
+	decoded, err := New(data[0])
+	if err != nil {
+		return err
+	}
+	if len(data)-1 != len(decoded.registers) {
+		return fmt.Errorf("invalid encoded sketch size: actual=%d, expected=%d",
+			len(data)-1, len(decoded.registers))
+	}
+	copy(decoded.registers, data[1:])
+	*s = *decoded
+	return nil
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
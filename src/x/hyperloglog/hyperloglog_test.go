@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package hyperloglog
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInvalidPrecision(t *testing.T) {
+	_, err := New(MinPrecision - 1)
+	require.Error(t, err)
+
+	_, err = New(MaxPrecision + 1)
+	require.Error(t, err)
+}
+
+func TestSketchEstimate(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, 100000} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			s, err := New(DefaultPrecision)
+			require.NoError(t, err)
+
+			for i := 0; i < n; i++ {
+				value := []byte(fmt.Sprintf("series-%d", i))
+				// Values added several times are only counted once.
+				s.Add(value)
+				s.Add(value)
+			}
+
+			requireEstimate(t, n, s.Estimate())
+		})
+	}
+}
+
+func TestSketchMerge(t *testing.T) {
+	a, err := New(DefaultPrecision)
+	require.NoError(t, err)
+	b, err := New(DefaultPrecision)
+	require.NoError(t, err)
+
+	// The sets overlap by half of their values.
+	for i := 0; i < 20000; i++ {
+		a.Add([]byte(fmt.Sprintf("series-%d", i)))
+		b.Add([]byte(fmt.Sprintf("series-%d", i+10000)))
+	}
+
+	require.NoError(t, a.Merge(b))
+	requireEstimate(t, 30000, a.Estimate())
+
+	c, err := New(DefaultPrecision - 1)
+	require.NoError(t, err)
+	require.Error(t, a.Merge(c))
+}
+
+func TestSketchMarshalBinary(t *testing.T) {
+	s, err := New(MinPrecision)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		s.Add([]byte(fmt.Sprintf("series-%d", i)))
+	}
+
+	data, err := s.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, 1+1<<MinPrecision, len(data))
+
+	var decoded Sketch
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, s, &decoded)
+	require.Equal(t, s.Estimate(), decoded.Estimate())
+
+	require.Error(t, decoded.UnmarshalBinary(nil))
+	require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	require.Error(t, decoded.UnmarshalBinary([]byte{MaxPrecision + 1}))
+}
+
+func requireEstimate(t *testing.T, expected int, actual uint64) {
+	// Allow for four times the standard error of the default precision.
+	tolerance := math.Max(1, 4*1.04/math.Sqrt(1<<DefaultPrecision)*float64(expected))
+	require.InDelta(t, float64(expected), float64(actual), tolerance)
+}